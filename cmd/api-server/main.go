@@ -6,38 +6,70 @@
 //   - API endpoints cho manga search, library management
 //   - Tích hợp với tất cả 5 protocols thông qua Protocol Bridge
 //   - WebSocket chat server endpoint
+//   - SSE activity stream (GET /activities/stream) cho web/curl client
 //   - Phase 2: Rating, Comment, Leaderboard APIs
+//   - SIGHUP: reload log level/format và rate limits không cần restart
 //
 // Port: 8080
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net/http"
-
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"mangahub/internal/achievements"
 	"mangahub/internal/activity"
 	"mangahub/internal/auth"
+	"mangahub/internal/chat"
 	"mangahub/internal/comment"
+	"mangahub/internal/customlist"
+	"mangahub/internal/goals"
+	"mangahub/internal/health"
 	"mangahub/internal/leaderboard"
 	"mangahub/internal/manga"
+	"mangahub/internal/middleware"
+	"mangahub/internal/notify"
+	"mangahub/internal/preferences"
+	"mangahub/internal/profile"
 	"mangahub/internal/progress"
 	"mangahub/internal/protocols"
 	"mangahub/internal/rating"
+	"mangahub/internal/savedsearch"
+	"mangahub/internal/statistics"
 	"mangahub/internal/udp"
 	"mangahub/internal/websocket"
+	"mangahub/pkg/cache"
 	"mangahub/pkg/config"
 	"mangahub/pkg/database"
+	"mangahub/pkg/external"
+	"mangahub/pkg/importer"
 	"mangahub/pkg/logger"
+	"mangahub/pkg/models"
+	"mangahub/pkg/moderation"
 
 	"github.com/gin-gonic/gin"
 )
 
+// shutdownTimeout bounds how long in-flight requests may run after a signal
+const shutdownTimeout = 15 * time.Second
+
 func main() {
 	cfg, err := config.Load("./configs/development.yaml")
 	if err != nil {
 		log.Fatal("failed to load config:", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
 
 	logger.Init(logger.Config{
 		Level:  cfg.Logging.Level,
@@ -50,11 +82,24 @@ func main() {
 		MaxOpenConns:    cfg.Database.MaxOpenConns,
 		MaxIdleConns:    cfg.Database.MaxIdleConns,
 		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+		BusyTimeout:     cfg.Database.BusyTimeout,
 	})
 	if err != nil {
 		logger.Fatal("failed to init database:", err)
 	}
-	defer db.Close()
+
+	// Background jobs (backups, email digests, trending) stop on shutdown
+	bgCtx, stopBackground := context.WithCancel(context.Background())
+	defer stopBackground()
+	if cfg.Database.Backup.Interval > 0 {
+		logger.Infof("Auto backup every %s into %s (keep %d)",
+			cfg.Database.Backup.Interval, cfg.Database.Backup.Dir, cfg.Database.Backup.Keep)
+		go db.AutoBackup(bgCtx, database.BackupConfig{
+			Dir:      cfg.Database.Backup.Dir,
+			Interval: cfg.Database.Backup.Interval,
+			Keep:     cfg.Database.Backup.Keep,
+		}, logger.Warnf)
+	}
 
 	// UDP server runs separately as cmd/udp-server on port 9091
 	// We connect to it via protocol bridge, not start it here
@@ -68,28 +113,97 @@ func main() {
 		cfg.TCP.Host, cfg.TCP.Port,
 		udpClient,
 		cfg.GRPC.Host, cfg.GRPC.Port,
+		protocols.RetryPolicy{
+			InitialBackoff: cfg.Bridge.InitialBackoff,
+			MaxBackoff:     cfg.Bridge.MaxBackoff,
+		},
 	)
 	if err != nil {
 		logger.Warnf("Protocol bridge initialization error: %v (will continue without bridge)", err)
 	}
-	if protocolBridge != nil {
-		defer protocolBridge.Close()
+
+	// Redis backs rate limiting and the leaderboard cache; both fail open without it
+	// An empty redis.host means Redis is intentionally not used
+	var redisCache *cache.RedisCache
+	var redisErr error
+	if !cfg.Redis.Enabled() {
+		logger.Info("Redis not configured, rate limiting and leaderboard caching disabled")
+	} else if rc, err := cache.NewRedisCache(&cfg.Redis); err != nil {
+		redisErr = err
+		logger.Warnf("Redis unavailable, rate limiting and leaderboard caching disabled: %v", err)
+	} else {
+		redisCache = rc
+		defer redisCache.Close()
 	}
 
-	authSvc := auth.NewService(db.DB, cfg.JWT.Secret, cfg.JWT.Issuer, cfg.JWT.Expiration)
+	// Tokens are signed with jwt.secret and accepted from it or any jwt.previous_keys entry
+	jwtKeys := auth.KeySet{Algorithm: cfg.JWT.Algorithm, Current: auth.SigningKey{ID: cfg.JWT.KeyID, Secret: cfg.JWT.Secret}}
+	for _, k := range cfg.JWT.PreviousKeys {
+		jwtKeys.Previous = append(jwtKeys.Previous, auth.SigningKey(k))
+	}
+	authSvc, err := auth.NewServiceWithKeys(db.DB, jwtKeys, cfg.JWT.Issuer, cfg.JWT.Expiration, cfg.JWT.RefreshExpiration)
+	if err != nil {
+		logger.Fatal("failed to init auth:", err)
+	}
+	authSvc.SetVerificationTTL(cfg.Auth.VerificationTTL)
 	authHandler := auth.NewHandler(authSvc)
+	authHandler.SetExposeVerificationToken(cfg.Auth.ExposeVerificationToken)
 
 	mangaRepo := manga.NewRepository(db.DB)
 	mangaSvc := manga.NewService(mangaRepo)
 	mangaHandler := manga.NewHandler(mangaSvc)
 
+	// Admin resync refetches from MangaDex/Jikan; new chapters are announced through the UDP server
+	resyncer := importer.NewResyncer(db.DB)
+	resyncer.SetFetcher(models.SourceMangaDex, importer.MangaDexFetcher(external.NewMangaDexClient(&cfg.MangaDex)))
+	resyncer.SetFetcher(models.SourceJikan, importer.JikanFetcher(external.NewJikanClient(&cfg.Jikan)))
+	udpAddr := fmt.Sprintf("%s:%d", cfg.UDP.Host, cfg.UDP.Port)
+	mangaHandler.SetResync(resyncer, func(n udp.Notification) error {
+		return udp.SendBroadcast(udpAddr, cfg.UDP.Secret, n)
+	})
+	mangaHandler.SetMerger(importer.NewMerger(db.DB))
+
+	// Initialize Reading Statistics (chapter history); progress catch-ups record into it
+	statistics.StreakGraceDays = cfg.Stats.StreakGraceDays
+	statsRepo := statistics.NewRepository(db.DB)
+	// Reading stats are cached in Redis when available, otherwise in this process
+	var statsCache cache.Cache = cache.NewMemoryCache()
+	if redisCache != nil {
+		statsCache = redisCache
+	}
+	statsSvc := statistics.NewServiceWithCache(statsRepo, statsCache)
+	statsHandler := statistics.NewHandler(statsSvc)
+
+	// New-chapter email digests; without email.sender=smtp they are only logged
+	digestSvc := notify.NewService(notify.NewRepository(db.DB), notify.NewSender(cfg.Email.Sender, notify.SMTPConfig{
+		Host:     cfg.Email.SMTPHost,
+		Port:     cfg.Email.SMTPPort,
+		Username: cfg.Email.Username,
+		Password: cfg.Email.Password,
+		From:     cfg.Email.From,
+	}))
+	if cfg.Email.DigestInterval > 0 {
+		logger.Infof("Email digests every %s via %s sender", cfg.Email.DigestInterval, cfg.Email.Sender)
+		go digestSvc.Run(bgCtx, cfg.Email.DigestInterval)
+	}
+
 	progressRepo := progress.NewRepository(db.DB)
-	progressSvc := progress.NewService(progressRepo)
+	progressSvc := progress.NewServiceWithHistory(progressRepo, statsSvc)
 
 	// Initialize Activity Feed system (before handlers need it)
 	activityRepo := activity.NewRepository(db.DB)
 	activitySvc := activity.NewService(activityRepo)
 	activityHandler := activity.NewHandler(activitySvc)
+	// Tails activity_feed for GET /activities/stream; stopped before HTTP
+	// shutdown so open streams end instead of holding it up
+	feedCtx, stopFeed := context.WithCancel(context.Background())
+	activityFeed := activity.NewFeed(activityRepo, activity.DefaultFeedInterval)
+	go func() {
+		if err := activityFeed.Run(feedCtx); err != nil {
+			logger.Errorf("activity feed stopped: %v", err)
+		}
+	}()
+	activityHandler.SetFeed(activityFeed)
 
 	// Use bridge-enabled handler with activity recording
 	var progressHandler *progress.Handler
@@ -101,8 +215,31 @@ func main() {
 		logger.Warnf("Progress handler initialized without protocol bridge but with activity recording")
 	}
 
+	// Wordlist filter shared by comments and chat; validated with the config
+	wordFilter, err := moderation.New(cfg.Moderation.Mask, cfg.Moderation.Block)
+	if err != nil {
+		logger.Fatal("failed to build moderation filter:", err)
+	}
+
 	// Initialize WebSocket hub
 	wsHub := websocket.NewHub()
+	wsHub.SetWordFilter(wordFilter)
+	wsHub.SetChatRepository(chat.NewRepository(db.DB))
+	wsHub.SetLimits(websocket.Limits{
+		MaxFrameSize:     cfg.WebSocket.MaxMessageSize,
+		MaxContentLength: cfg.WebSocket.MaxContentLength,
+		RateMessages:     cfg.WebSocket.RateLimitMessages,
+		RateWindow:       cfg.WebSocket.RateLimitWindow,
+		MaxViolations:    cfg.WebSocket.MaxViolations,
+	})
+	// Multi-instance deployments share chat rooms through Redis pub/sub
+	if cfg.WebSocket.RedisPubSub {
+		if redisCache != nil {
+			wsHub.SetRelay(redisCache)
+		} else {
+			logger.Warn("websocket.redis_pubsub is set but Redis is unavailable; chat stays local to this instance")
+		}
+	}
 	go wsHub.Run()
 	wsHandler := websocket.NewHandler(wsHub)
 
@@ -117,61 +254,221 @@ func main() {
 
 	// Initialize Comment system
 	commentRepo := comment.NewRepository(db.DB)
-	commentSvc := comment.NewService(commentRepo)
+	commentSvc := comment.NewServiceWithFilter(commentRepo, wordFilter)
 	commentHandler := comment.NewHandler(commentSvc)
 
 	// Initialize Leaderboard system
-	leaderboardSvc := leaderboard.NewService(db.DB)
+	// Trending reads precomputed scores until two intervals pass without a refresh
+	var leaderboardCache cache.Cache
+	if redisCache != nil {
+		leaderboardCache = redisCache
+	}
+	trendingInterval := cfg.Leaderboard.TrendingInterval
+	leaderboardSvc := leaderboard.NewServiceWithScores(db.DB, leaderboardCache, leaderboard.Prior{
+		MinVotes: cfg.Leaderboard.MinVotes,
+		Mean:     cfg.Leaderboard.PriorMean,
+	}, 2*trendingInterval)
+	if trendingInterval > 0 {
+		trendingWorker := leaderboard.NewWorker(db.DB)
+		if cfg.Leaderboard.LeaderElection {
+			host, _ := os.Hostname()
+			trendingWorker.SetLeaderElection(fmt.Sprintf("%s-%d", host, os.Getpid()))
+		}
+		logger.Infof("Trending scores every %s (leader election: %v)", trendingInterval, cfg.Leaderboard.LeaderElection)
+		go trendingWorker.Run(bgCtx, trendingInterval)
+	}
 	leaderboardHandler := leaderboard.NewHandler(leaderboardSvc)
 
+	// Initialize Reading Goals
+	goalRepo := goals.NewRepository(db.DB)
+	goalSvc := goals.NewService(goalRepo)
+	goalHandler := goals.NewHandler(goalSvc)
+
+	// Initialize Achievements (badges are evaluated when the list is loaded)
+	achievementSvc := achievements.NewService(achievements.NewRepository(db.DB), statsSvc)
+	achievementHandler := achievements.NewHandler(achievementSvc)
+
+	// Initialize Public Profiles
+	profileSvc := profile.NewService(profile.NewRepository(db.DB))
+	profileHandler := profile.NewHandler(profileSvc)
+
+	// Initialize Custom Lists
+	listRepo := customlist.NewRepository(db.DB)
+	listSvc := customlist.NewService(listRepo)
+	listHandler := customlist.NewHandler(listSvc)
+
+	// Initialize Search History & Saved Searches
+	searchSvc := savedsearch.NewService(savedsearch.NewRepository(db.DB))
+	searchHandler := savedsearch.NewHandler(searchSvc)
+
+	// Initialize Preferences (data export)
+	prefsRepo := preferences.NewRepository(db.DB)
+	prefsSvc := preferences.NewService(prefsRepo)
+	prefsHandler := preferences.NewHandler(prefsSvc)
+	// Spoiler comments and reviews are masked server-side for viewers with show_spoilers off
+	commentHandler.SetSpoilerPreferences(prefsSvc)
+	ratingHandler.SetSpoilerPreferences(prefsSvc)
+
 	if cfg.Server.Mode == "release" {
 		gin.SetMode(gin.ReleaseMode)
 	}
 
 	router := gin.New()
 	router.Use(logger.GinLogger(), logger.Recovery())
+	// Only listed proxies may set X-Forwarded-For; otherwise any client could
+	// pick its own ClientIP and dodge the per-IP rate limit
+	if err := router.SetTrustedProxies(cfg.Server.TrustedProxies); err != nil {
+		logger.Fatal("invalid server.trusted_proxies:", err)
+	}
+
+	// Rate limiter (Redis sliding window); fails open when Redis is unavailable.
+	// Budgets are read per request so a config reload applies them at once.
+	var limiter middleware.Limiter
+	if redisCache != nil {
+		limiter = redisCache
+	}
+	var rateLimits atomic.Pointer[config.RateLimitConfig]
+	rateLimits.Store(&cfg.Server.RateLimit)
+	ipBudget := func() middleware.Budget {
+		if rl := rateLimits.Load(); rl.Enabled {
+			return middleware.Budget{Requests: rl.Requests, Window: rl.Window}
+		}
+		return middleware.Budget{}
+	}
+	loginBudget := func() middleware.Budget {
+		if rl := rateLimits.Load(); rl.Enabled {
+			return middleware.Budget{Requests: rl.LoginRequests, Window: rl.LoginWindow}
+		}
+		return middleware.Budget{}
+	}
 
 	api := router.Group("/")
+	api.Use(middleware.RateLimitFunc(limiter, ipBudget))
+	// Cancels slow queries; set below write_timeout so the error still reaches the client
+	api.Use(middleware.RequestTimeout(cfg.Server.RequestTimeout))
 
 	// Public auth routes
 	api.POST("/auth/register", authHandler.Register)
-	api.POST("/auth/login", authHandler.Login)
+	api.POST("/auth/login", middleware.LoginRateLimitFunc(limiter, loginBudget), authHandler.Login)
+	api.POST("/auth/refresh", authHandler.RefreshToken)
+	api.GET("/auth/verify", authHandler.VerifyEmail)
 
 	// Public manga routes
 	api.GET("/manga", mangaHandler.ListManga)
+	api.GET("/manga/suggest", mangaHandler.SuggestManga)
+	api.GET("/manga/random", mangaHandler.GetRandomManga)
+	api.GET("/genres", mangaHandler.ListGenres)
 	api.GET("/manga/:id", mangaHandler.GetManga)
-
-	// Health check endpoint
-	api.GET("/health", func(c *gin.Context) {
-		dbHealth, err := db.HealthCheck()
-		if err != nil {
-			c.JSON(http.StatusServiceUnavailable, gin.H{
-				"status":   "unhealthy",
-				"database": fmt.Sprintf("error: %v", err),
-				"server":   "running",
-			})
-			return
+	api.GET("/manga/:id/chapters", mangaHandler.GetChapters)
+	api.POST("/manga/batch", mangaHandler.BatchGetManga)
+	api.GET("/manga/:id/similar", auth.OptionalJWTMiddleware(authSvc), mangaHandler.GetSimilarManga)
+
+	// Liveness and readiness probes
+	healthChecker := health.NewChecker(2 * time.Second)
+	healthChecker.Require("database", func(ctx context.Context) (map[string]interface{}, error) {
+		return db.HealthCheck()
+	})
+	healthChecker.Require("redis", func(ctx context.Context) (map[string]interface{}, error) {
+		switch {
+		case !cfg.Redis.Enabled():
+			return nil, health.ErrNotConfigured
+		case redisCache == nil:
+			return nil, fmt.Errorf("unavailable since startup: %w", redisErr)
+		}
+		// cache_disabled: the circuit breaker is skipping Redis until a probe succeeds
+		return map[string]interface{}{"cache_disabled": redisCache.Disabled()}, redisCache.Ping(ctx)
+	})
+	// The bridge reconnects on its own and progress sync degrades without it,
+	// so it is reported but does not gate traffic
+	healthChecker.Observe("protocol_bridge", func(ctx context.Context) (map[string]interface{}, error) {
+		if protocolBridge == nil {
+			return nil, fmt.Errorf("bridge failed to initialize")
+		}
+		details := map[string]interface{}{}
+		var down []string
+		for name, status := range protocolBridge.Health() {
+			details[name] = status
+			if status != protocols.StatusUp {
+				down = append(down, name)
+			}
+		}
+		if len(down) > 0 {
+			sort.Strings(down)
+			return details, fmt.Errorf("disconnected: %v", down)
 		}
-		c.JSON(http.StatusOK, gin.H{
-			"status":   "ok",
-			"database": dbHealth,
-			"server":   "running",
-		})
+		return details, nil
 	})
+	healthHandler := health.NewHandler(healthChecker)
+	api.GET("/health", healthHandler.Liveness)
+	api.GET("/ready", healthHandler.Readiness)
 
 	protected := api.Group("/")
 	protected.Use(auth.JWTMiddleware(authSvc))
 
 	// Protected auth routes
 	protected.GET("/auth/me", authHandler.GetMe)
+	protected.DELETE("/auth/me", authHandler.DeleteMe)
 	protected.POST("/auth/logout", authHandler.Logout)
-	protected.POST("/auth/refresh", authHandler.RefreshToken)
+	protected.PUT("/auth/password", authHandler.ChangePassword)
+	protected.POST("/auth/verify/resend", authHandler.ResendVerification)
+
+	// Commenting and rating need a verified email address
+	verified := auth.RequireVerifiedEmail(authSvc)
+
+	// Admin: refetch manga metadata from external sources
+	protected.POST("/manga/:id/resync", mangaHandler.ResyncManga)
+	// Admin: fold a duplicate manga into another one
+	protected.POST("/manga/:id/merge", mangaHandler.MergeManga)
 
 	// Library endpoints
 	protected.POST("/users/library", progressHandler.AddToLibrary)
 	protected.GET("/users/library", progressHandler.GetLibrary)
+	protected.GET("/users/continue", progressHandler.GetContinueReading)
+	protected.POST("/users/library/bulk", progressHandler.BulkImportLibrary)
+	protected.PUT("/users/library/bulk-status", progressHandler.BulkUpdateStatus)
 	protected.DELETE("/users/library/:manga_id", progressHandler.RemoveFromLibrary)
 	protected.PUT("/users/progress", progressHandler.UpdateProgress)
+	protected.PUT("/users/progress/catchup", progressHandler.CatchUpProgress)
+
+	// Chapter reading history endpoints
+	protected.POST("/users/chapter-history", statsHandler.RecordChapterRead)
+	protected.GET("/users/chapter-history", statsHandler.GetHistory)
+	protected.DELETE("/users/chapter-history/:id", statsHandler.DeleteChapterRead)
+	protected.GET("/users/stats", statsHandler.GetReadingStats)
+	protected.GET("/users/stats/overview", statsHandler.GetStatsOverview)
+	protected.GET("/users/stats/heatmap", statsHandler.GetReadingHeatmap)
+	protected.GET("/users/stats/genres", statsHandler.GetGenreDistribution)
+	protected.GET("/users/goals", goalHandler.GetGoals)
+	protected.POST("/users/goals", goalHandler.SetGoal)
+	protected.DELETE("/users/goals/:id", goalHandler.DeleteGoal)
+	protected.GET("/users/achievements", achievementHandler.GetAchievements)
+	protected.GET("/users/preferences", prefsHandler.GetPreferences)
+	protected.PUT("/users/preferences", prefsHandler.UpdatePreferences)
+	protected.GET("/users/export", prefsHandler.ExportData)
+	protected.GET("/manga/:id/mute", prefsHandler.GetMangaMute)
+	protected.PUT("/manga/:id/mute", prefsHandler.MuteManga)
+	protected.DELETE("/manga/:id/mute", prefsHandler.UnmuteManga)
+
+	// Custom list endpoints
+	protected.GET("/users/lists", listHandler.GetUserLists)
+	protected.POST("/users/lists", listHandler.CreateList)
+	protected.GET("/users/lists/:id", listHandler.GetList)
+	protected.PUT("/users/lists/:id", listHandler.UpdateList)
+	protected.DELETE("/users/lists/:id", listHandler.DeleteList)
+	protected.POST("/users/lists/:id/items", listHandler.AddItem)
+	protected.PUT("/users/lists/:id/items", listHandler.ReorderItems)
+	protected.DELETE("/users/lists/:id/items/:manga_id", listHandler.RemoveItem)
+	api.GET("/lists/:id", listHandler.GetPublicList)
+
+	// Search history & saved search endpoints
+	protected.GET("/users/searches", searchHandler.GetSearches)
+	protected.POST("/users/searches", searchHandler.SaveSearch)
+	protected.DELETE("/users/searches/:id", searchHandler.DeleteSavedSearch)
+	protected.POST("/users/search-history", searchHandler.RecordSearch)
+	protected.DELETE("/users/search-history", searchHandler.ClearHistory)
+
+	// Public profile: GET /users/:username (static /users/* routes above take precedence)
+	api.GET("/users/:username", profileHandler.GetProfile)
 
 	// ================================================
 	// Phase 2: Social Features Routes
@@ -180,16 +477,22 @@ func main() {
 	// Activity Feed routes
 	api.GET("/activities", activityHandler.GetRecentActivities)
 	protected.GET("/activities/user/:userID", activityHandler.GetUserActivities)
+	// SSE live feed: GET /activities/stream?type=&user_id=&manga_id=
+	// Rate limited but outside RequestTimeout, which would cut the stream
+	router.GET("/activities/stream", middleware.RateLimitFunc(limiter, ipBudget), activityHandler.StreamActivities)
 
 	// Rating routes (authenticated)
 	// POST /manga/:id/ratings - Submit or update rating
 	// DELETE /manga/:id/ratings - Delete user's rating
-	protected.POST("/manga/:id/ratings", ratingHandler.SubmitRating)
+	protected.POST("/manga/:id/ratings", verified, ratingHandler.SubmitRating)
 	protected.DELETE("/manga/:id/ratings", ratingHandler.DeleteRating)
 
 	// Rating routes (public - view only)
 	// GET /manga/:id/ratings - Get ratings summary
-	api.GET("/manga/:id/ratings", ratingHandler.GetRatings)
+	// GET /manga/:id/reviews - Written reviews (?sort=recent|helpful&page=N&spoilers=show)
+	// The optional token identifies the viewer, whose show_spoilers preference decides masking
+	api.GET("/manga/:id/ratings", auth.OptionalJWTMiddleware(authSvc), ratingHandler.GetRatings)
+	api.GET("/manga/:id/reviews", auth.OptionalJWTMiddleware(authSvc), ratingHandler.GetReviews)
 
 	// Comment routes (authenticated)
 	// POST /manga/:id/comments - Create new comment
@@ -197,28 +500,41 @@ func main() {
 	// DELETE /comments/:id - Delete comment
 	// POST /comments/:id/like - Like comment
 	// DELETE /comments/:id/like - Unlike comment
-	protected.POST("/manga/:id/comments", commentHandler.CreateComment)
-	protected.PUT("/comments/:id", commentHandler.UpdateComment)
+	protected.POST("/manga/:id/comments", verified, commentHandler.CreateComment)
+	protected.PUT("/comments/:id", verified, commentHandler.UpdateComment)
 	protected.DELETE("/comments/:id", commentHandler.DeleteComment)
-	protected.POST("/comments/:id/like", commentHandler.LikeComment)
+	protected.POST("/comments/:id/like", verified, commentHandler.LikeComment)
 	protected.DELETE("/comments/:id/like", commentHandler.UnlikeComment)
 
 	// Comment routes (public - view only)
-	api.GET("/manga/:id/comments", commentHandler.GetComments)
+	// GET /manga/:id/comments - spoilers masked unless ?spoilers=show or the viewer's show_spoilers
+	api.GET("/manga/:id/comments", auth.OptionalJWTMiddleware(authSvc), commentHandler.GetComments)
 
 	// Leaderboard routes (public)
-	// GET /leaderboards/manga - Top rated manga
+	// GET /leaderboards/manga - Top rated manga (?genre=&type=)
+	// GET /leaderboards/manga/hidden-gems - Manga below the vote threshold
 	// GET /leaderboards/users - Most active users
 	// GET /leaderboards/trending - Trending manga
 	api.GET("/leaderboards/manga", leaderboardHandler.GetTopRatedManga)
+	api.GET("/leaderboards/manga/hidden-gems", leaderboardHandler.GetHiddenGems)
 	api.GET("/leaderboards/users", leaderboardHandler.GetMostActiveUsers)
 	api.GET("/leaderboards/trending", leaderboardHandler.GetTrendingManga)
 
 	// WebSocket chat endpoint (requires JWT)
 	protected.GET("/ws/chat", wsHandler.ServeWS)
 
-	// Room info endpoint
+	// Rooms directory and room info (members paginated): GET /rooms?type=manga&page=2
+	api.GET("/rooms", wsHandler.ListRooms)
 	api.GET("/rooms/:room_id", wsHandler.GetRoomInfo)
+	// A manga's room, created the first time someone opens it
+	protected.POST("/rooms/manga/:manga_id", wsHandler.OpenMangaRoom)
+
+	// Chat history (requires JWT): GET /rooms/:room_id/messages?before=<id>&limit=50 (or ?after=<id>)
+	protected.GET("/rooms/:room_id/messages", wsHandler.GetRoomMessages)
+
+	// Read receipts: POST /rooms/:room_id/read {message_id?}, GET /rooms/unread
+	protected.POST("/rooms/:room_id/read", wsHandler.MarkRoomRead)
+	protected.GET("/rooms/unread", wsHandler.GetUnread)
 
 	srv := &http.Server{
 		Addr:         fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port),
@@ -235,7 +551,91 @@ func main() {
 	}
 	logger.Infof("✨ Social features enabled (Rating, Comment, Leaderboard, Chat persistence)")
 
-	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-		logger.Fatalf("server error: %v", err)
+	go func() {
+		if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			logger.Fatalf("server error: %v", err)
+		}
+	}()
+
+	// SIGHUP re-reads the config file and applies what can change while running
+	hupCh := make(chan os.Signal, 1)
+	signal.Notify(hupCh, syscall.SIGHUP)
+	go func() {
+		applied := cfg
+		for range hupCh {
+			applied = reloadConfig(cfg, applied, func(next *config.Config) {
+				logger.Reconfigure(next.Logging.Level, next.Logging.Format)
+				rateLimits.Store(&next.Server.RateLimit)
+			})
+		}
+	}()
+
+	// Wait for interrupt signal
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, os.Interrupt, syscall.SIGTERM)
+	<-sigCh
+
+	logger.Info("Shutting down HTTP API server...")
+
+	// Let in-flight requests finish before tearing down dependencies
+	stopFeed()
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	if err := srv.Shutdown(ctx); err != nil {
+		logger.Warnf("HTTP shutdown did not complete cleanly: %v", err)
+	}
+
+	// Hijacked WebSocket connections are not tracked by srv.Shutdown
+	wsHub.Stop()
+
+	if protocolBridge != nil {
+		if err := protocolBridge.Close(); err != nil {
+			logger.Warnf("failed to close protocol bridge: %v", err)
+		}
+	}
+	stopBackground()
+	if err := db.Close(); err != nil {
+		logger.Warnf("failed to close database: %v", err)
+	}
+
+	logger.Info("HTTP API server stopped.")
+}
+
+// hotReloadable reports whether a config key takes effect on reload;
+// everything else is read once at startup
+func hotReloadable(key string) bool {
+	return key == "logging.level" || key == "logging.format" || strings.HasPrefix(key, "server.rate_limit.")
+}
+
+// reloadConfig re-reads the config file. A file that fails to load or
+// validate is rejected as a whole and applied stays in force. Otherwise
+// apply gets the new config if a hot-reloadable key changed, and keys that
+// differ from startup but need a restart are logged. Returns the config now
+// in force.
+func reloadConfig(startup, applied *config.Config, apply func(*config.Config)) *config.Config {
+	logger.Info("SIGHUP received, reloading configuration")
+	next, err := config.Reload(startup)
+	if err != nil {
+		logger.Errorf("Config reload rejected, keeping the current configuration: %v", err)
+		return applied
+	}
+
+	for _, key := range config.Changed(startup, next) {
+		if !hotReloadable(key) {
+			logger.Warnf("Config %s changed; change ignored until restart", key)
+		}
+	}
+	var hot []string
+	for _, key := range config.Changed(applied, next) {
+		if hotReloadable(key) {
+			hot = append(hot, key)
+		}
+	}
+	if len(hot) == 0 {
+		logger.Info("Config reloaded, nothing to apply")
+		return applied
 	}
+	apply(next)
+	logger.Infof("Config reloaded, applied: %s", strings.Join(hot, ", "))
+	return next
 }
@@ -3,7 +3,7 @@
 //
 // Features:
 //   - Search MangaDex and Jikan APIs
-//   - Preview data before import
+//   - Preview data before import, with validation badges per result
 //   - Import selected manga to local database
 //   - Redis caching to save API calls
 //   - Full pipeline testing
@@ -14,24 +14,34 @@
 package main
 
 import (
+	"bufio"
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
+	"io"
+	"maps"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"slices"
 	"strconv"
 	"strings"
 	"time"
 
+	"mangahub/internal/udp"
 	"mangahub/pkg/cache"
 	"mangahub/pkg/config"
+	"mangahub/pkg/database"
 	"mangahub/pkg/external"
 	"mangahub/pkg/importer"
 	"mangahub/pkg/models"
 
 	_ "github.com/glebarez/go-sqlite"
 
+	"github.com/charmbracelet/bubbles/progress"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 )
@@ -64,6 +74,9 @@ var (
 	errorStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#FF0000"))
 
+	warnStyle = lipgloss.NewStyle().
+			Foreground(lipgloss.Color("#FFD700"))
+
 	infoStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#00BFFF"))
 
@@ -103,9 +116,16 @@ type model struct {
 	searchResults   []models.ExternalMangaData
 	topMangaList    []models.ExternalMangaData
 	importPreviews  []importer.MangaPreview
+	validation      map[int][]importer.ValidationIssue // issues per searchResults index
 	lastImportStats importer.ImportStats
 	dbStats         dbStatistics
 
+	// Import in progress (stateImporting)
+	importCh       <-chan tea.Msg
+	cancelImport   context.CancelFunc
+	importProgress importProgressMsg
+	progressBar    progress.Model
+
 	// Services
 	cfg            *config.Config
 	db             *sql.DB
@@ -146,11 +166,12 @@ var menuItems = []string{
 
 func initialModel() model {
 	return model{
-		state:    stateMenu,
-		cursor:   0,
-		selected: make(map[int]bool),
-		width:    80,
-		height:   24,
+		state:       stateMenu,
+		cursor:      0,
+		selected:    make(map[int]bool),
+		width:       80,
+		height:      24,
+		progressBar: progress.New(progress.WithDefaultGradient(), progress.WithWidth(40)),
 	}
 }
 
@@ -183,6 +204,14 @@ type importDoneMsg struct {
 	err   error
 }
 
+// importProgressMsg is sent before each item of a running import and once at the end
+type importProgressMsg struct {
+	done    int
+	total   int
+	current string
+	stats   importer.ImportStats
+}
+
 type dbStatsMsg struct {
 	stats dbStatistics
 	err   error
@@ -223,9 +252,13 @@ func initializeApp() tea.Msg {
 	// Initialize cache (optional)
 	var redisCache *cache.RedisCache
 	redisCache, _ = cache.NewRedisCache(&cfg.Redis)
+	if redisCache != nil {
+		mangadex.SetCoverCache(redisCache)
+	}
 
 	// Initialize importer
 	imp := importer.NewImporter(db, redisCache)
+	imp.SetChapterFetcher(models.SourceMangaDex, importer.MangaDexChapterFetcher(mangadex, "en"))
 
 	return initMsg{
 		cfg:      cfg,
@@ -247,6 +280,8 @@ func setDefaults(cfg *config.Config) {
 	cfg.Redis.Host = "localhost"
 	cfg.Redis.Port = 6379
 	cfg.Redis.PoolSize = 10
+	cfg.UDP.Host = "localhost"
+	cfg.UDP.Port = 9091
 }
 
 func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -280,6 +315,7 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 		}
 		m.searchResults = msg.results
+		m.validation = m.validate(msg.results)
 		m.selected = make(map[int]bool)
 		m.cursor = 0
 		m.state = stateResults
@@ -294,14 +330,31 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		m.topMangaList = msg.results
 		m.searchResults = msg.results
+		m.validation = m.validate(msg.results)
 		m.selected = make(map[int]bool)
 		m.cursor = 0
 		m.state = stateResults
 		m.statusMsg = fmt.Sprintf("Loaded top %d manga", len(msg.results))
 		return m, nil
 
+	case importProgressMsg:
+		m.importProgress = msg
+		return m, waitForImport(m.importCh)
+
 	case importDoneMsg:
 		m.isLoading = false
+		if m.cancelImport != nil {
+			m.cancelImport()
+			m.cancelImport = nil
+			m.importCh = nil
+		}
+		if errors.Is(msg.err, context.Canceled) {
+			m.lastImportStats = msg.stats
+			m.statusMsg = fmt.Sprintf("⚠️  Import cancelled after %d of %d: %d new, %d updated, %d failed",
+				msg.stats.Total, m.importProgress.total, msg.stats.Inserted, msg.stats.Updated, msg.stats.Failed)
+			m.state = stateMenu
+			return m, nil
+		}
 		if msg.err != nil {
 			m.errorMsg = msg.err.Error()
 			return m, nil
@@ -336,6 +389,18 @@ func (m model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m model) handleKeyPress(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
+	// A running import only listens for cancellation; it stops between items
+	if m.state == stateImporting {
+		switch msg.String() {
+		case "esc", "ctrl+c":
+			if m.cancelImport != nil {
+				m.cancelImport()
+			}
+			m.statusMsg = "Cancelling import..."
+		}
+		return m, nil
+	}
+
 	// Global keys
 	switch msg.String() {
 	case "ctrl+c":
@@ -474,17 +539,13 @@ func (m model) handleResultsKeys(msg tea.KeyMsg) (tea.Model, tea.Cmd) {
 			m.errorMsg = "No items selected. Press SPACE to select."
 			return m, nil
 		}
-		m.isLoading = true
-		m.statusMsg = "Importing selected manga..."
-		return m, m.importSelected()
+		return m.startImport()
 	case "I":
 		// Import all
 		for i := range m.searchResults {
 			m.selected[i] = true
 		}
-		m.isLoading = true
-		m.statusMsg = "Importing all results..."
-		return m, m.importSelected()
+		return m.startImport()
 	}
 	return m, nil
 }
@@ -559,7 +620,7 @@ func (m model) fetchTopManga() tea.Cmd {
 		}
 
 		// Fetch from Jikan
-		resp, err := m.jikanClient.GetTopManga(ctx, 1, 25, "")
+		resp, err := m.jikanClient.GetTopManga(ctx, 1, 25, "", "")
 		if err != nil {
 			return topMangaMsg{err: err}
 		}
@@ -578,33 +639,70 @@ func (m model) fetchTopManga() tea.Cmd {
 	}
 }
 
-func (m model) importSelected() tea.Cmd {
-	return func() tea.Msg {
-		ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
-		defer cancel()
+// validate checks results for bad records so the list can show badges;
+// nothing is written. Nil until the importer is initialized.
+func (m model) validate(results []models.ExternalMangaData) map[int][]importer.ValidationIssue {
+	if m.dataImporter == nil {
+		return nil
+	}
+	return importer.IssuesByItem(m.dataImporter.Validate(context.Background(), results))
+}
 
-		// Collect selected items
-		toImport := make([]models.ExternalMangaData, 0)
-		for i, selected := range m.selected {
-			if selected && i < len(m.searchResults) {
-				toImport = append(toImport, m.searchResults[i])
-			}
+// startImport imports the selected results in the background, streaming
+// importProgressMsg updates until the final importDoneMsg.
+// Results with blocking validation errors are left out.
+func (m model) startImport() (tea.Model, tea.Cmd) {
+	// Collect selected items in list order
+	toImport := make([]models.ExternalMangaData, 0)
+	blocked := 0
+	for i, result := range m.searchResults {
+		if !m.selected[i] {
+			continue
 		}
-
-		if len(toImport) == 0 {
-			return importDoneMsg{err: fmt.Errorf("no items to import")}
+		if importer.ItemSeverity(m.validation[i]) == importer.SeverityError {
+			blocked++
+			continue
 		}
+		toImport = append(toImport, result)
+	}
 
-		// Reset importer stats
-		m.dataImporter.ResetStats()
-
-		// Import batch
-		_, err := m.dataImporter.ImportBatch(ctx, toImport)
-		if err != nil {
-			return importDoneMsg{err: err}
+	if len(toImport) == 0 {
+		m.errorMsg = "no items to import"
+		if blocked > 0 {
+			m.errorMsg = fmt.Sprintf("all %d selected items have blocking validation errors", blocked)
 		}
+		return m, nil
+	}
 
-		return importDoneMsg{stats: m.dataImporter.GetStats()}
+	ctx, cancel := context.WithCancel(context.Background())
+	// Room for every progress update plus the result, so the import never blocks on the UI
+	ch := make(chan tea.Msg, len(toImport)+2)
+	imp := m.dataImporter
+	go func() {
+		defer close(ch)
+		imp.ResetStats()
+		_, err := imp.ImportBatchWithProgress(ctx, toImport, func(done, total int, current string) {
+			ch <- importProgressMsg{done: done, total: total, current: current, stats: imp.GetStats()}
+		})
+		ch <- importDoneMsg{stats: imp.GetStats(), err: err}
+	}()
+
+	m.state = stateImporting
+	m.importCh = ch
+	m.cancelImport = cancel
+	m.importProgress = importProgressMsg{total: len(toImport)}
+	m.errorMsg = ""
+	m.statusMsg = ""
+	if blocked > 0 {
+		m.statusMsg = fmt.Sprintf("Skipped %d items with blocking validation errors", blocked)
+	}
+	return m, waitForImport(ch)
+}
+
+// waitForImport delivers the next message from a running import
+func waitForImport(ch <-chan tea.Msg) tea.Cmd {
+	return func() tea.Msg {
+		return <-ch
 	}
 }
 
@@ -686,6 +784,8 @@ func (m model) View() string {
 		s.WriteString(m.viewDBStats())
 	case stateCacheMenu:
 		s.WriteString(m.viewCacheStatus())
+	case stateImporting:
+		s.WriteString(m.viewImporting())
 	}
 
 	// Status bar
@@ -800,13 +900,62 @@ func (m model) viewResults() string {
 
 		line := fmt.Sprintf("%s %s %-40s │ %s │ %s",
 			cursor, checkbox, title, rating, result.Source)
-		s.WriteString(style.Render(line) + "\n")
+		s.WriteString(style.Render(line) + " " + m.renderValidationBadge(i) + "\n")
 	}
 
 	if len(m.searchResults) > visibleCount {
 		s.WriteString(dimStyle.Render(fmt.Sprintf("\n... showing %d-%d of %d", start+1, end, len(m.searchResults))))
 	}
 
+	// Issues of the highlighted result
+	if issues := m.validation[m.cursor]; len(issues) > 0 {
+		s.WriteString("\n")
+		for _, issue := range issues {
+			style := warnStyle
+			if issue.Blocking() {
+				style = errorStyle
+			}
+			s.WriteString("\n" + style.Render(fmt.Sprintf("  %s %s: %s", validationBadge(issue.Severity), issue.Field, issue.Message)))
+		}
+	}
+
+	return s.String()
+}
+
+// renderValidationBadge marks a result by its worst validation issue: ✖ blocks import, ⚠ is a warning
+func (m model) renderValidationBadge(i int) string {
+	issues := m.validation[i]
+	switch importer.ItemSeverity(issues) {
+	case importer.SeverityError:
+		return errorStyle.Render(fmt.Sprintf("✖ %d", len(issues)))
+	case importer.SeverityWarning:
+		return warnStyle.Render(fmt.Sprintf("⚠ %d", len(issues)))
+	}
+	if m.validation == nil {
+		return ""
+	}
+	return successStyle.Render("✓")
+}
+
+func (m model) viewImporting() string {
+	p := m.importProgress
+	var s strings.Builder
+	s.WriteString(menuStyle.Render(fmt.Sprintf("📥 Importing %d manga", p.total)))
+	s.WriteString("\n\n")
+
+	percent := 0.0
+	if p.total > 0 {
+		percent = float64(p.done) / float64(p.total)
+	}
+	s.WriteString(m.progressBar.ViewAs(percent))
+	s.WriteString(fmt.Sprintf("  %d/%d\n\n", p.done, p.total))
+
+	if p.current != "" {
+		s.WriteString(infoStyle.Render("Current: "+p.current) + "\n")
+	}
+	s.WriteString(dimStyle.Render(fmt.Sprintf("New: %d • Updated: %d • Failed: %d",
+		p.stats.Inserted, p.stats.Updated, p.stats.Failed)))
+
 	return s.String()
 }
 
@@ -822,7 +971,9 @@ func (m model) viewDBStats() string {
 		fmt.Sprintf("  ⭐ Ratings:           %d", m.dbStats.RatingsCount),
 	}
 
-	if m.redisCache != nil {
+	if m.redisCache != nil && m.redisCache.Disabled() {
+		stats = append(stats, "  🗄️  Redis:            Unreachable (cache skipped, retrying)")
+	} else if m.redisCache != nil {
 		stats = append(stats, "  🗄️  Redis:            Connected")
 	} else {
 		stats = append(stats, "  🗄️  Redis:            Not connected")
@@ -842,6 +993,9 @@ func (m model) viewCacheStatus() string {
 		s.WriteString(errorStyle.Render("Redis is not connected.\n\n"))
 		s.WriteString(dimStyle.Render("To start Redis:\n"))
 		s.WriteString(dimStyle.Render("  docker run -d --name mangahub-redis -p 6379:6379 redis:7-alpine"))
+	} else if m.redisCache.Disabled() {
+		s.WriteString(warnStyle.Render("⚠ Redis stopped answering; the cache is skipped until it comes back\n"))
+		s.WriteString(dimStyle.Render(fmt.Sprintf("Host: %s:%d", m.cfg.Redis.Host, m.cfg.Redis.Port)))
 	} else {
 		s.WriteString(successStyle.Render("✅ Redis connected\n"))
 		s.WriteString(dimStyle.Render(fmt.Sprintf("Host: %s:%d", m.cfg.Redis.Host, m.cfg.Redis.Port)))
@@ -858,6 +1012,8 @@ func (m model) getHelpText() string {
 		return "↑/↓: Navigate • SPACE: Toggle • a: All • n: None • i: Import selected • I: Import all • ESC: Back"
 	case stateDBStats:
 		return "r: Refresh • ESC: Back"
+	case stateImporting:
+		return "ESC/Ctrl+C: Cancel import"
 	default:
 		return "ESC: Back • q: Quit"
 	}
@@ -870,7 +1026,9 @@ func (m model) getHelpText() string {
 func main() {
 	// Check for CLI mode
 	if len(os.Args) > 1 {
-		runCLIMode(os.Args)
+		if !runCLIMode(os.Args) {
+			os.Exit(1)
+		}
 		return
 	}
 
@@ -886,10 +1044,28 @@ func main() {
 // CLI MODE (for non-interactive usage)
 // ============================================================
 
-func runCLIMode(args []string) {
+// dbStats is the output of the stats command
+type dbStats struct {
+	Manga    int  `json:"manga"`
+	Users    int  `json:"users"`
+	Progress int  `json:"progress"`
+	Ratings  int  `json:"ratings"`
+	Redis    bool `json:"redis"`
+}
+
+// queueResult is the output of the imports command
+type queueResult struct {
+	Imported int      `json:"imported"`
+	Failed   int      `json:"failed"`
+	Errors   []string `json:"errors,omitempty"`
+}
+
+// runCLIMode runs one command and reports whether it succeeded
+func runCLIMode(args []string) bool {
+	args, out := parseGlobalFlags(args)
 	if len(args) < 2 {
-		printCLIHelp()
-		return
+		printCLIHelp(os.Stdout)
+		return true
 	}
 
 	// Load config
@@ -901,10 +1077,17 @@ func runCLIMode(args []string) {
 
 	// Initialize database
 	dbPath := filepath.Join(".", "data", "mangahub.db")
+
+	// Restore swaps the database file, so it runs before anything opens it
+	if args[1] == "restore" {
+		runRestore(args[2:], dbPath, out)
+		return !out.failed
+	}
+
 	db, err := sql.Open("sqlite", dbPath+"?_pragma=foreign_keys(1)")
 	if err != nil {
-		fmt.Printf("❌ Database error: %v\n", err)
-		return
+		out.errorf("Database error: %v", err)
+		return false
 	}
 	defer db.Close()
 
@@ -912,36 +1095,82 @@ func runCLIMode(args []string) {
 	mangadex := external.NewMangaDexClient(&cfg.MangaDex)
 	jikan := external.NewJikanClient(&cfg.Jikan)
 	redisCache, _ := cache.NewRedisCache(&cfg.Redis)
+	if redisCache != nil {
+		mangadex.SetCoverCache(redisCache)
+	}
 	imp := importer.NewImporter(db, redisCache)
+	imp.SetChapterFetcher(models.SourceMangaDex, importer.MangaDexChapterFetcher(mangadex, "en"))
 
-	ctx := context.Background()
+	// Ctrl+C cancels long imports between items instead of killing the process
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 	cmd := args[1]
 
 	switch cmd {
 	case "search", "searchj", "sj":
 		// Use Jikan (more reliable) for searchj/sj, MangaDex for search
 		useJikan := cmd == "searchj" || cmd == "sj"
-		if len(args) < 3 {
-			fmt.Println("Usage: data-cli search <query>")
-			fmt.Println("       data-cli searchj <query>  (use Jikan/MAL)")
-			return
+		fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+		lang := fs.String("lang", "", `MangaDex translated languages, e.g. en,pt-br; "all" lifts mangadex.languages`)
+		count := fs.Int("count", 10, "number of Jikan results, fetched over several pages")
+		words, ok := parseInterspersed(fs, args[2:])
+		if !ok {
+			out.failed = true
+			break
+		}
+		if len(words) == 0 {
+			out.usage("Usage: data-cli search [--lang en,pt-br] <query>",
+				"       data-cli searchj [--count N] <query>  (use Jikan/MAL)")
+			break
+		}
+		query := strings.Join(words, " ")
+
+		search := mangadex
+		if *lang != "" {
+			if useJikan {
+				out.errorf("--lang applies to MangaDex searches only")
+				break
+			}
+			languages, err := parseLanguages(*lang)
+			if err != nil {
+				out.errorf("%v", err)
+				break
+			}
+			search = mangadex.WithLanguages(languages)
+		}
+		if *count != 10 && !useJikan {
+			out.errorf("--count applies to Jikan searches only")
+			break
+		}
+		if *count <= 0 {
+			out.errorf("--count must be positive")
+			break
 		}
-		query := strings.Join(args[2:], " ")
 
 		var results []models.ExternalMangaData
 		var err error
 
 		if useJikan {
-			fmt.Printf("🔍 Searching Jikan/MAL for: %s\n", query)
-			results, err = jikan.SearchMangaFiltered(ctx, query, 1, 10)
+			out.infof("🔍 Searching Jikan/MAL for: %s\n", query)
+			results, err = jikan.SearchMangaAll(ctx, query, *count)
 		} else {
-			fmt.Printf("🔍 Searching MangaDex for: %s\n", query)
-			results, err = mangadex.SearchMangaFiltered(ctx, query, 10, 0)
+			out.infof("🔍 Searching MangaDex for: %s\n", query)
+			results, err = search.SearchMangaFiltered(ctx, query, 10, 0)
 		}
 
 		if err != nil {
-			fmt.Printf("❌ Error: %v\n", err)
-			return
+			if len(results) == 0 {
+				out.errorf("Error: %v", err)
+				break
+			}
+			// Keep what the earlier pages returned
+			out.warnf("Stopped after %d results: %v", len(results), err)
+		}
+		if results == nil {
+			results = []models.ExternalMangaData{}
+		}
+		if out.emitJSON(results) {
+			break
 		}
 
 		fmt.Printf("\n📚 Found %d results:\n", len(results))
@@ -950,128 +1179,658 @@ func runCLIMode(args []string) {
 			if r.Rating > 0 {
 				rating = fmt.Sprintf("%.2f", r.Rating)
 			}
-			fmt.Printf("%d. %s (Rating: %s, %s)\n", i+1, r.Title, rating, r.Source)
+			source := r.Source
+			if len(r.Languages) > 0 {
+				source += ", " + strings.Join(r.Languages, "/")
+			}
+			fmt.Printf("%d. %s (Rating: %s, %s)\n", i+1, r.Title, rating, source)
 		}
 
 	case "import", "importj", "ij":
 		// Use Jikan for importj/ij, MangaDex for import
 		useJikan := cmd == "importj" || cmd == "ij"
-		if len(args) < 3 {
-			fmt.Println("Usage: data-cli import <query>")
-			fmt.Println("       data-cli importj <query>  (use Jikan/MAL)")
-			return
+		words, ok := parseImportFlags(flag.NewFlagSet(cmd, flag.ContinueOnError), args[2:], imp)
+		if !ok {
+			out.failed = true
+			break
 		}
-		query := strings.Join(args[2:], " ")
+		if len(words) == 0 {
+			out.usage("Usage: data-cli import [--dedupe] [--threshold 0.8] [--dry-run] <query>",
+				"       data-cli importj <query>  (use Jikan/MAL)")
+			break
+		}
+		query := strings.Join(words, " ")
 
 		var results []models.ExternalMangaData
 		var err error
 
 		if useJikan {
-			fmt.Printf("🔍 Searching Jikan/MAL for: %s\n", query)
+			out.infof("🔍 Searching Jikan/MAL for: %s\n", query)
 			results, err = jikan.SearchMangaFiltered(ctx, query, 1, 10)
 		} else {
-			fmt.Printf("🔍 Searching MangaDex for: %s\n", query)
+			out.infof("🔍 Searching MangaDex for: %s\n", query)
 			results, err = mangadex.SearchMangaFiltered(ctx, query, 10, 0)
 		}
 
 		if err != nil {
-			fmt.Printf("❌ Search error: %v\n", err)
-			return
+			out.errorf("Search error: %v", err)
+			break
 		}
 
 		if len(results) == 0 {
-			fmt.Println("No results found.")
-			return
+			out.infof("No results found.\n")
+			out.emitJSON(importResult{DryRun: imp.IsDryRun()})
+			break
+		}
+
+		importWithProgress(ctx, imp, results, out)
+
+	case "top":
+		fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+		var query external.TopMangaQuery
+		fs.StringVar(&query.Type, "type", "", "MAL type: "+strings.Join(external.JikanMangaTypes, ", "))
+		fs.StringVar(&query.Genre, "genre", "", "only manga with this genre, theme or demographic")
+		fs.IntVar(&query.Count, "count", 25, "number of manga to import")
+		words, ok := parseImportFlags(fs, args[2:], imp)
+		if !ok {
+			out.failed = true
+			break
+		}
+		// The count may also be given positionally: data-cli top 50
+		if len(words) >= 1 {
+			if n, err := strconv.Atoi(words[0]); err == nil {
+				query.Count = n
+			}
 		}
+		out.infof("🏆 Fetching top %d %s from MAL...\n", query.Count, describeTopQuery(query))
 
-		fmt.Printf("📥 Importing %d manga...\n", len(results))
-		_, err = imp.ImportBatch(ctx, results)
+		items, err := jikan.FetchTopManga(ctx, query)
 		if err != nil {
-			fmt.Printf("❌ Import error: %v\n", err)
-			return
+			if len(items) == 0 {
+				out.errorf("Error: %v", err)
+				break
+			}
+			// Keep what the earlier pages returned
+			out.warnf("Stopped after %d manga: %v", len(items), err)
+		}
+		if len(items) < query.Count {
+			out.infof("Found %d matching manga\n", len(items))
 		}
 
-		stats := imp.GetStats()
-		fmt.Printf("✅ Done! Inserted: %d, Updated: %d, Failed: %d\n",
-			stats.Inserted, stats.Updated, stats.Failed)
+		results := make([]models.ExternalMangaData, 0, len(items))
+		for _, item := range items {
+			results = append(results, item.ToExternalMangaData())
+		}
 
-	case "top":
-		count := 25
+		importWithProgress(ctx, imp, results, out)
+
+	case "validate":
+		fs := flag.NewFlagSet(cmd, flag.ContinueOnError)
+		var query external.TopMangaQuery
+		fs.StringVar(&query.Type, "type", "", "MAL type for validate top: "+strings.Join(external.JikanMangaTypes, ", "))
+		fs.StringVar(&query.Genre, "genre", "", "only top manga with this genre, theme or demographic")
+		fs.IntVar(&query.Count, "count", 25, "number of top manga to validate")
+		useMangaDex := fs.Bool("mangadex", false, "search MangaDex instead of Jikan/MAL")
+		words, ok := parseInterspersed(fs, args[2:])
+		if !ok {
+			out.failed = true
+			break
+		}
+		if len(words) == 0 {
+			out.usage("Usage: data-cli validate [--mangadex] <query>",
+				"       data-cli validate top [--type T] [--genre G] [count]")
+			break
+		}
+
+		var results []models.ExternalMangaData
+		if words[0] == "top" {
+			if len(words) >= 2 {
+				if n, err := strconv.Atoi(words[1]); err == nil {
+					query.Count = n
+				}
+			}
+			out.infof("🏆 Fetching top %d %s from MAL...\n", query.Count, describeTopQuery(query))
+			items, err := jikan.FetchTopManga(ctx, query)
+			if err != nil {
+				if len(items) == 0 {
+					out.errorf("Error: %v", err)
+					break
+				}
+				out.warnf("Stopped after %d manga: %v", len(items), err)
+			}
+			for _, item := range items {
+				results = append(results, item.ToExternalMangaData())
+			}
+		} else {
+			q := strings.Join(words, " ")
+			var err error
+			if *useMangaDex {
+				out.infof("🔍 Searching MangaDex for: %s\n", q)
+				results, err = mangadex.SearchMangaFiltered(ctx, q, 10, 0)
+			} else {
+				out.infof("🔍 Searching Jikan/MAL for: %s\n", q)
+				results, err = jikan.SearchMangaFiltered(ctx, q, 1, 10)
+			}
+			if err != nil {
+				out.errorf("Search error: %v", err)
+				break
+			}
+		}
+
+		printValidation(results, imp.Validate(ctx, results), out)
+
+	case "imports":
+		limit := 50
 		if len(args) >= 3 {
 			if n, err := strconv.Atoi(args[2]); err == nil {
-				count = n
+				limit = n
 			}
 		}
-		fmt.Printf("🏆 Fetching top %d manga from MAL...\n", count)
 
-		resp, err := jikan.GetTopManga(ctx, 1, count, "")
+		queue, err := imp.PendingLibraryImports(ctx, limit)
 		if err != nil {
-			fmt.Printf("❌ Error: %v\n", err)
-			return
+			out.errorf("Error: %v", err)
+			break
+		}
+		out.infof("📥 Processing %d queued library imports...\n", len(queue))
+
+		var result queueResult
+		for _, q := range queue {
+			ext, err := fetchQueuedManga(ctx, jikan, mangadex, q)
+			if err == nil {
+				var manga *models.Manga
+				if manga, err = imp.ImportOne(ctx, ext); err == nil {
+					err = imp.CompleteLibraryImport(ctx, q, manga.ID)
+				}
+			}
+			if err != nil {
+				result.Failed++
+				result.Errors = append(result.Errors, fmt.Sprintf("%s %s: %v", q.Source, q.ExternalID, err))
+				out.infof("  ✗ %s %s: %v\n", q.Source, q.ExternalID, err)
+				_ = imp.FailLibraryImport(ctx, q.ID, err)
+				continue
+			}
+			result.Imported++
+			out.infof("  ✓ %s %s → %s\n", q.Source, q.ExternalID, ext.Title)
+		}
+		if !out.emitJSON(result) {
+			fmt.Printf("✅ Done! Imported: %d, Failed: %d\n", result.Imported, result.Failed)
 		}
 
-		results := make([]models.ExternalMangaData, 0, len(resp.Data))
-		for _, item := range resp.Data {
-			results = append(results, item.ToExternalMangaData())
+	case "resync":
+		if len(args) < 3 {
+			out.usage("Usage: data-cli resync <manga-id>")
+			break
 		}
+		mangaID := args[2]
 
-		fmt.Printf("📥 Importing %d manga...\n", len(results))
-		_, err = imp.ImportBatch(ctx, results)
+		resyncer := importer.NewResyncer(db)
+		resyncer.SetFetcher(models.SourceMangaDex, importer.MangaDexFetcher(mangadex))
+		resyncer.SetFetcher(models.SourceJikan, importer.JikanFetcher(jikan))
+
+		out.infof("🔄 Resyncing %s...\n", mangaID)
+		result, err := resyncer.Resync(ctx, mangaID)
 		if err != nil {
-			fmt.Printf("❌ Import error: %v\n", err)
-			return
+			out.errorf("Resync error: %v", err)
+			break
+		}
+		for _, failure := range result.Failures {
+			out.infof("  ⚠️  %s (fell back)\n", failure)
+		}
+		if result.NewChapters() {
+			notification := udp.NewChapterNotification(mangaID,
+				fmt.Sprintf("Chapter %d is out!", result.TotalChapters))
+			udpAddr := fmt.Sprintf("%s:%d", cfg.UDP.Host, cfg.UDP.Port)
+			if err := udp.SendBroadcast(udpAddr, cfg.UDP.Secret, notification); err != nil {
+				out.warnf("chapter_release broadcast failed: %v", err)
+			} else {
+				out.infof("  📣 chapter_release sent to %s\n", udpAddr)
+			}
+		}
+		if out.emitJSON(result) {
+			break
 		}
 
-		stats := imp.GetStats()
-		fmt.Printf("✅ Done! Inserted: %d, Updated: %d, Failed: %d\n",
-			stats.Inserted, stats.Updated, stats.Failed)
+		fmt.Printf("✅ Synced from %s %s\n", result.Source, result.ExternalID)
+		if len(result.UpdatedFields) == 0 {
+			fmt.Println("  No changes")
+		} else {
+			fmt.Printf("  Updated: %s\n", strings.Join(result.UpdatedFields, ", "))
+		}
+		if len(result.SkippedFields) > 0 {
+			fmt.Printf("  Kept manual edits: %s\n", strings.Join(result.SkippedFields, ", "))
+		}
+		if result.NewChapters() {
+			fmt.Printf("  📖 Chapters: %d → %d\n", result.PreviousChapters, result.TotalChapters)
+		}
 
-	case "stats":
-		fmt.Println("📊 Database Statistics")
-		fmt.Println("─────────────────────")
+	case "provenance":
+		if len(args) < 3 {
+			out.usage("Usage: data-cli provenance <manga-id>")
+			break
+		}
+		fields, err := importer.Provenance(ctx, db, args[2])
+		if err != nil {
+			out.errorf("Provenance error: %v", err)
+			break
+		}
+		if out.emitJSON(fields) {
+			break
+		}
+		if len(fields) == 0 {
+			fmt.Println("No provenance recorded (never imported or edited)")
+			break
+		}
+		for _, f := range fields {
+			source := f.Source
+			if f.ExternalID != "" {
+				source += " " + f.ExternalID
+			}
+			fmt.Printf("  %-15s %-20s %s\n", f.Field, source, f.UpdatedAt.Format("2006-01-02 15:04"))
+		}
 
-		var count int
-		db.QueryRow("SELECT COUNT(*) FROM manga").Scan(&count)
-		fmt.Printf("  📚 Manga:    %d\n", count)
+	case "merge":
+		if len(args) < 4 {
+			out.usage("Usage: data-cli merge <source-id> <target-id>",
+				"  Moves everything attached to the source onto the target, then deletes the source")
+			break
+		}
+		sourceID, targetID := args[2], args[3]
 
-		db.QueryRow("SELECT COUNT(*) FROM users").Scan(&count)
-		fmt.Printf("  👤 Users:    %d\n", count)
+		out.infof("🔀 Merging %s into %s...\n", sourceID, targetID)
+		result, err := importer.NewMerger(db).Merge(ctx, "data-cli", sourceID, targetID)
+		if err != nil {
+			out.errorf("Merge error: %v", err)
+			break
+		}
+		if out.emitJSON(result) {
+			break
+		}
 
-		db.QueryRow("SELECT COUNT(*) FROM reading_progress").Scan(&count)
-		fmt.Printf("  📖 Progress: %d\n", count)
+		fmt.Printf("✅ Merged %q into %q (%d rows moved)\n", result.SourceTitle, result.TargetTitle, result.Moved())
+		for _, table := range slices.Sorted(maps.Keys(result.Reparented)) {
+			fmt.Printf("  → %-20s %d\n", table, result.Reparented[table])
+		}
+		for _, table := range slices.Sorted(maps.Keys(result.Dropped)) {
+			fmt.Printf("  ✗ %-20s %d older duplicate(s) dropped\n", table, result.Dropped[table])
+		}
+		fmt.Printf("  ⭐ Rating: %.2f (%d ratings)\n", result.AverageRating, result.RatingCount)
 
-		db.QueryRow("SELECT COUNT(*) FROM manga_ratings").Scan(&count)
-		fmt.Printf("  ⭐ Ratings:  %d\n", count)
+	case "stats":
+		stats := dbStats{Redis: redisCache != nil}
+		for _, c := range []struct {
+			table string
+			dest  *int
+		}{
+			{"manga", &stats.Manga},
+			{"users", &stats.Users},
+			{"reading_progress", &stats.Progress},
+			{"manga_ratings", &stats.Ratings},
+		} {
+			if err := db.QueryRowContext(ctx, "SELECT COUNT(*) FROM "+c.table).Scan(c.dest); err != nil {
+				out.errorf("Database error: %v", err)
+				return false
+			}
+		}
+		if out.emitJSON(stats) {
+			break
+		}
 
-		if redisCache != nil {
+		fmt.Println("📊 Database Statistics")
+		fmt.Println("─────────────────────")
+		fmt.Printf("  📚 Manga:    %d\n", stats.Manga)
+		fmt.Printf("  👤 Users:    %d\n", stats.Users)
+		fmt.Printf("  📖 Progress: %d\n", stats.Progress)
+		fmt.Printf("  ⭐ Ratings:  %d\n", stats.Ratings)
+		if stats.Redis {
 			fmt.Printf("  🗄️  Redis:   Connected\n")
 		} else {
 			fmt.Printf("  🗄️  Redis:   Not connected\n")
 		}
 
+	case "backup":
+		if len(args) < 3 {
+			out.usage("Usage: data-cli backup <path>")
+			break
+		}
+		path := args[2]
+		out.infof("💾 Backing up %s → %s\n", dbPath, path)
+		if err := (&database.DB{DB: db}).Backup(ctx, path); err != nil {
+			out.errorf("Backup error: %v", err)
+			break
+		}
+		version, _ := database.FileSchemaVersion(path)
+		if !out.emitJSON(map[string]interface{}{"path": path, "schema_version": version}) {
+			fmt.Printf("✅ Backup written (schema v%d)\n", version)
+		}
+
 	default:
-		fmt.Printf("Unknown command: %s\n", cmd)
-		printCLIHelp()
+		out.errorf("Unknown command: %s", cmd)
+		printCLIHelp(out.stderr)
+	}
+	return !out.failed
+}
+
+// runRestore replaces the database with a backup after the user confirms.
+// --yes skips the prompt; --force allows restoring over a newer schema.
+func runRestore(args []string, dbPath string, out *cliOutput) {
+	fs := flag.NewFlagSet("restore", flag.ContinueOnError)
+	yes := fs.Bool("yes", false, "skip the confirmation prompt")
+	force := fs.Bool("force", false, "restore even if the database schema is newer")
+	if err := fs.Parse(args); err != nil {
+		out.failed = true
+		return
+	}
+	if fs.NArg() < 1 {
+		out.usage("Usage: data-cli restore [--yes] [--force] <path>")
+		return
+	}
+	backupPath := fs.Arg(0)
+
+	if _, err := os.Stat(backupPath); err != nil {
+		out.errorf("Backup error: %v", err)
+		return
+	}
+	backupVersion, err := database.FileSchemaVersion(backupPath)
+	if err != nil {
+		out.errorf("Backup error: %v", err)
+		return
+	}
+	currentVersion, err := database.FileSchemaVersion(dbPath)
+	if err != nil {
+		out.errorf("Database error: %v", err)
+		return
+	}
+	out.infof("♻️  Restore %s (schema v%d) over %s (schema v%d)\n", backupPath, backupVersion, dbPath, currentVersion)
+	out.infof("   Stop the API, TCP and gRPC servers first; they hold the database open.\n")
+
+	if !*yes {
+		// The prompt is never quiet: without --yes the user has to see it
+		fmt.Fprint(out.stderr, "   Type 'restore' to continue: ")
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		if strings.TrimSpace(answer) != "restore" {
+			out.errorf("Aborted.")
+			return
+		}
+	}
+
+	if err := database.Restore(backupPath, dbPath, *force); err != nil {
+		out.errorf("Restore error: %v", err)
+		return
+	}
+	if !out.emitJSON(map[string]interface{}{"restored": backupPath, "schema_version": backupVersion}) {
+		fmt.Println("✅ Database restored; pending migrations run on next server start")
+	}
+}
+
+// parseImportFlags applies --dedupe, --threshold, --dry-run, --idempotency-key
+// and --chunk-size to the importer. Flags may appear before, between or after the query words,
+// which are returned. Commands with extra flags define them on fs first.
+func parseImportFlags(fs *flag.FlagSet, args []string, imp *importer.Importer) ([]string, bool) {
+	dedupe := fs.Bool("dedupe", false, "merge titles similar to existing manga instead of inserting duplicates")
+	threshold := fs.Float64("threshold", importer.DefaultDedupeThreshold, "trigram similarity (0-1] required to merge with --dedupe")
+	dryRun := fs.Bool("dry-run", false, "report what would be imported and merged without writing")
+	key := fs.String("idempotency-key", "", "skip records a previous run with this key imported unchanged (default: the command line)")
+	chunkSize := fs.Int("chunk-size", importer.DefaultChunkSize, "items committed per transaction; a failed chunk rolls back alone")
+
+	words, ok := parseInterspersed(fs, args)
+	if !ok {
+		return nil, false
+	}
+
+	imp.SetDedupe(*dedupe, *threshold)
+	imp.SetDryRun(*dryRun)
+	imp.SetChunkSize(*chunkSize)
+	if *key == "" {
+		*key = defaultIdempotencyKey(fs, words)
+	}
+	imp.SetIdempotencyKey(*key)
+	return words, true
+}
+
+// parseInterspersed parses fs's flags wherever they appear among args and
+// returns the remaining words in order
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, bool) {
+	var words []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, false
+		}
+		if fs.NArg() == 0 {
+			return words, true
+		}
+		words = append(words, fs.Arg(0))
+		args = fs.Args()[1:]
+	}
+}
+
+// parseLanguages splits a --lang value into MangaDex language codes; "all"
+// returns none, which lifts the configured filter
+func parseLanguages(value string) ([]string, error) {
+	if strings.EqualFold(value, "all") {
+		return nil, nil
+	}
+	var languages []string
+	for _, lang := range strings.Split(value, ",") {
+		lang = strings.ToLower(strings.TrimSpace(lang))
+		if lang == "" {
+			continue
+		}
+		if !config.ValidLanguageCode(lang) {
+			return nil, fmt.Errorf("--lang: %q is not a language code like en or pt-br", lang)
+		}
+		languages = append(languages, lang)
+	}
+	return languages, nil
+}
+
+// defaultIdempotencyKey derives the key from the command, the flags that pick
+// what is fetched and the query, so re-running the same command line after a
+// failure skips what was already imported, e.g. "top --genre=action 50"
+func defaultIdempotencyKey(fs *flag.FlagSet, words []string) string {
+	parts := []string{fs.Name()}
+	fs.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "dry-run", "idempotency-key", "chunk-size":
+		default:
+			parts = append(parts, "--"+f.Name+"="+f.Value.String())
+		}
+	})
+	parts = append(parts, strings.ToLower(strings.Join(words, " ")))
+	return strings.Join(parts, " ")
+}
+
+// describeTopQuery names the slice of the top list being fetched, e.g. "action manhwa"
+func describeTopQuery(q external.TopMangaQuery) string {
+	kind := "manga"
+	if q.Type != "" {
+		kind = q.Type
+	}
+	if q.Genre != "" {
+		return q.Genre + " " + kind
+	}
+	return kind
+}
+
+// importResult is the output of the import commands
+type importResult struct {
+	Stats     importer.ImportStats      `json:"stats"`
+	Merges    []importer.MergeCandidate `json:"merges,omitempty"`
+	DryRun    bool                      `json:"dry_run"`
+	Cancelled bool                      `json:"cancelled"`
+}
+
+// importWithProgress imports results with a live counter; Ctrl+C stops the
+// import between items and the partial summary is still printed
+func importWithProgress(ctx context.Context, imp *importer.Importer, results []models.ExternalMangaData, out *cliOutput) {
+	out.infof("📥 Importing %d manga...\n", len(results))
+	_, err := imp.ImportBatchWithProgress(ctx, results, func(done, total int, current string) {
+		out.infof("\r  [%d/%d] %-50.50s", done, total, current)
+	})
+	out.infof("\n")
+	cancelled := errors.Is(err, context.Canceled)
+	if cancelled {
+		out.warnf("Import cancelled")
+	} else if err != nil {
+		out.errorf("Import error: %v", err)
+		return
+	}
+	printImportSummary(imp, cancelled, out)
+}
+
+// printImportSummary prints the import stats and any fuzzy merges
+func printImportSummary(imp *importer.Importer, cancelled bool, out *cliOutput) {
+	result := importResult{Stats: imp.GetStats(), Merges: imp.GetMerges(), DryRun: imp.IsDryRun(), Cancelled: cancelled}
+	if out.emitJSON(result) {
+		return
+	}
+
+	for _, m := range result.Merges {
+		fmt.Printf("  ⇄ %q → %q (similarity %.2f)\n", m.Title, m.ExistingTitle, m.Similarity)
+	}
+
+	stats := result.Stats
+	if result.DryRun {
+		fmt.Printf("🔎 Dry run: %d manga, %d would merge into existing titles, nothing written\n",
+			stats.Total, stats.Merged)
+		return
+	}
+	fmt.Printf("✅ Done! Inserted: %d, Updated: %d (merged: %d), Skipped: %d, Failed: %d\n",
+		stats.Inserted, stats.Updated, stats.Merged, stats.Skipped, stats.Failed)
+}
+
+// validationResult is the output of the validate command
+type validationResult struct {
+	Summary importer.ValidationSummary `json:"summary"`
+	Issues  []importer.ValidationIssue `json:"issues"`
+}
+
+// printValidation lists each item with its issues and a summary.
+// Blocking issues make the command exit nonzero so scripts can gate an import on it.
+func printValidation(items []models.ExternalMangaData, issues []importer.ValidationIssue, out *cliOutput) {
+	result := validationResult{Summary: importer.SummarizeIssues(len(items), issues), Issues: issues}
+	if result.Issues == nil {
+		result.Issues = []importer.ValidationIssue{}
+	}
+	if result.Summary.Blocked > 0 {
+		out.failed = true
+	}
+	if out.emitJSON(result) {
+		return
+	}
+
+	byItem := importer.IssuesByItem(issues)
+	for n, item := range items {
+		itemIssues := byItem[n]
+		fmt.Printf("%s %d. %s\n", validationBadge(importer.ItemSeverity(itemIssues)), n+1, item.Title)
+		for _, issue := range itemIssues {
+			fmt.Printf("      %-7s %s: %s\n", issue.Severity, issue.Field, issue.Message)
+		}
+	}
+
+	sum := result.Summary
+	fmt.Printf("\n🔎 %d manga: %d ok, %d with warnings, %d blocked (%d errors, %d warnings)\n",
+		sum.Items, sum.Valid, sum.Warned, sum.Blocked, sum.Errors, sum.Warnings)
+}
+
+// validationBadge marks an item by its worst validation issue
+func validationBadge(severity importer.Severity) string {
+	switch severity {
+	case importer.SeverityError:
+		return "✖"
+	case importer.SeverityWarning:
+		return "⚠"
+	}
+	return "✓"
+}
+
+// fetchQueuedManga loads a queued import's manga from its source.
+// Sources without a client fall back to a Jikan title search.
+func fetchQueuedManga(ctx context.Context, jikan *external.JikanClient, mangadex *external.MangaDexClient, q models.QueuedLibraryImport) (models.ExternalMangaData, error) {
+	switch q.Source {
+	case models.ImportSourceMAL:
+		malID, err := strconv.Atoi(q.ExternalID)
+		if err != nil {
+			return models.ExternalMangaData{}, fmt.Errorf("invalid MAL id %q", q.ExternalID)
+		}
+		data, err := jikan.GetManga(ctx, malID)
+		if err != nil {
+			return models.ExternalMangaData{}, err
+		}
+		return data.ToExternalMangaData(), nil
+
+	case models.SourceMangaDex:
+		data, err := mangadex.GetManga(ctx, q.ExternalID)
+		if err != nil {
+			return models.ExternalMangaData{}, err
+		}
+		return data.ToExternalMangaData(), nil
+	}
+
+	if q.Title == "" {
+		return models.ExternalMangaData{}, fmt.Errorf("no fetcher for source %s and no title to search", q.Source)
+	}
+	results, err := jikan.SearchMangaFiltered(ctx, q.Title, 1, 1)
+	if err != nil {
+		return models.ExternalMangaData{}, err
+	}
+	if len(results) == 0 {
+		return models.ExternalMangaData{}, fmt.Errorf("no match for %q", q.Title)
 	}
+	return results[0], nil
 }
 
-func printCLIHelp() {
-	fmt.Println("MangaHub Data Pipeline CLI")
-	fmt.Println()
-	fmt.Println("Usage: data-cli [command] [args]")
-	fmt.Println()
-	fmt.Println("Commands:")
-	fmt.Println("  (no args)        Launch interactive TUI")
-	fmt.Println("  search <query>   Search MangaDex")
-	fmt.Println("  searchj <query>  Search Jikan/MAL (recommended)")
-	fmt.Println("  import <query>   Search MangaDex and import to database")
-	fmt.Println("  importj <query>  Search Jikan/MAL and import (recommended)")
-	fmt.Println("  top [count]      Import top manga from MAL (default: 25)")
-	fmt.Println("  stats            Show database statistics")
-	fmt.Println()
-	fmt.Println("Examples:")
-	fmt.Println("  data-cli                     # Launch TUI")
-	fmt.Println("  data-cli searchj \"one piece\" # Search Jikan")
-	fmt.Println("  data-cli importj naruto      # Import from Jikan")
-	fmt.Println("  data-cli top 50              # Import top 50")
+// printCLIHelp writes the CLI usage to w
+func printCLIHelp(w io.Writer) {
+	fmt.Fprintln(w, "MangaHub Data Pipeline CLI")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Usage: data-cli [command] [args]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Commands:")
+	fmt.Fprintln(w, "  (no args)        Launch interactive TUI")
+	fmt.Fprintln(w, "  search <query>   Search MangaDex (--lang en,pt-br: only manga translated into these)")
+	fmt.Fprintln(w, "  searchj <query>  Search Jikan/MAL (recommended; --count N for more than 10)")
+	fmt.Fprintln(w, "  import <query>   Search MangaDex and import to database")
+	fmt.Fprintln(w, "  importj <query>  Search Jikan/MAL and import (recommended)")
+	fmt.Fprintln(w, "  top [count]      Import top manga from MAL (default: 25)")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Top flags:")
+	fmt.Fprintln(w, "  --type T         MAL type: manga, manhwa, manhua, novel, lightnovel, oneshot, doujin")
+	fmt.Fprintln(w, "  --genre G        Only manga with this genre/theme (\"action\", \"slice-of-life\")")
+	fmt.Fprintln(w, "  --count N        Number of manga, fetched over several pages (default: 25)")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Import flags (import, importj, top):")
+	fmt.Fprintln(w, "  --dedupe         Merge near-duplicate titles (\"Re:Zero\" = \"ReZero\")")
+	fmt.Fprintln(w, "  --threshold N    Similarity needed to merge, 0-1 (default: 0.8)")
+	fmt.Fprintln(w, "  --dry-run        Report would-be imports and merges without writing")
+	fmt.Fprintln(w, "  --idempotency-key K  Skip records already imported unchanged under K")
+	fmt.Fprintln(w, "                   (default: the command line, so a re-run resumes)")
+	fmt.Fprintln(w, "  --chunk-size N   Items committed per transaction (default: 25);")
+	fmt.Fprintln(w, "                   a failed chunk rolls back without undoing earlier ones")
+	fmt.Fprintln(w, "  validate <query> Check search results for bad records without importing")
+	fmt.Fprintln(w, "  validate top [count]  Check the MAL top list (--type, --genre as for top)")
+	fmt.Fprintln(w, "                   (--mangadex searches MangaDex; exits nonzero on blocking errors)")
+	fmt.Fprintln(w, "  imports [limit]  Fetch manga for queued library imports (default: 50)")
+	fmt.Fprintln(w, "  resync <id>      Refetch a manga from its external sources")
+	fmt.Fprintln(w, "  provenance <id>  Show which source supplied each field (manual = edited by hand)")
+	fmt.Fprintln(w, "  merge <src> <dst>  Fold duplicate manga src into dst and delete src")
+	fmt.Fprintln(w, "  stats            Show database statistics")
+	fmt.Fprintln(w, "  backup <path>    Copy the database to path (safe while servers run)")
+	fmt.Fprintln(w, "  restore <path>   Replace the database with a backup (--yes, --force)")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Global flags (any command):")
+	fmt.Fprintln(w, "  --json           Print results as JSON; errors go to stderr as {\"error\": ...}")
+	fmt.Fprintln(w, "  --quiet, -q      Suppress progress messages")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "Examples:")
+	fmt.Fprintln(w, "  data-cli                     # Launch TUI")
+	fmt.Fprintln(w, "  data-cli searchj \"one piece\" # Search Jikan")
+	fmt.Fprintln(w, "  data-cli searchj --count 60 isekai  # Several pages of results")
+	fmt.Fprintln(w, "  data-cli importj naruto      # Import from Jikan")
+	fmt.Fprintln(w, "  data-cli top 50              # Import top 50")
+	fmt.Fprintln(w, "  data-cli top --type manhwa --genre action --count 100")
+	fmt.Fprintln(w, "  data-cli importj --dedupe --dry-run \"re zero\"  # Preview merges")
+	fmt.Fprintln(w, "  data-cli validate top 100    # Vet a top 100 pull before importing it")
+	fmt.Fprintln(w, "  data-cli --json searchj naruto | jq '.[].title'")
+	fmt.Fprintln(w, "  data-cli backup data/backups/before-upgrade.db")
 }
@@ -0,0 +1,96 @@
+// Package main - CLI Output Modes
+// Điều hướng output của CLI mode (không ảnh hưởng TUI)
+//
+//   - --json: kết quả là JSON trên stdout, lỗi là {"error": ...} trên stderr
+//   - --quiet: bỏ progress chatter, chỉ in kết quả và lỗi
+//   - Lỗi luôn ra stderr và exit code khác 0
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// cliOutput routes one CLI command's output. Results go to stdout; progress
+// chatter goes to stdout, or stderr in JSON mode so stdout stays parseable.
+type cliOutput struct {
+	json   bool
+	quiet  bool
+	stdout io.Writer
+	stderr io.Writer
+	failed bool
+}
+
+// parseGlobalFlags removes --json and --quiet (-q) from args wherever they
+// appear, so they work with every command and do not reach command flag sets
+func parseGlobalFlags(args []string) ([]string, *cliOutput) {
+	out := &cliOutput{stdout: os.Stdout, stderr: os.Stderr}
+	rest := make([]string, 0, len(args))
+	for _, arg := range args {
+		switch arg {
+		case "--json", "-json":
+			out.json = true
+		case "--quiet", "-quiet", "-q":
+			out.quiet = true
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return rest, out
+}
+
+// chatter is where progress messages go; io.Discard when quiet
+func (o *cliOutput) chatter() io.Writer {
+	switch {
+	case o.quiet:
+		return io.Discard
+	case o.json:
+		return o.stderr
+	}
+	return o.stdout
+}
+
+// infof prints progress chatter
+func (o *cliOutput) infof(format string, args ...interface{}) {
+	fmt.Fprintf(o.chatter(), format, args...)
+}
+
+// warnf prints a problem that did not stop the command; shown even when quiet
+func (o *cliOutput) warnf(format string, args ...interface{}) {
+	fmt.Fprintf(o.stderr, "⚠️  "+format+"\n", args...)
+}
+
+// errorf reports a failed command on stderr and makes the CLI exit nonzero
+func (o *cliOutput) errorf(format string, args ...interface{}) {
+	o.failed = true
+	msg := fmt.Sprintf(format, args...)
+	if o.json {
+		_ = json.NewEncoder(o.stderr).Encode(map[string]string{"error": msg})
+		return
+	}
+	fmt.Fprintln(o.stderr, "❌ "+msg)
+}
+
+// usage prints command usage on stderr and fails the command
+func (o *cliOutput) usage(lines ...string) {
+	o.failed = true
+	for _, line := range lines {
+		fmt.Fprintln(o.stderr, line)
+	}
+}
+
+// emitJSON writes v to stdout in JSON mode and reports whether it did, so
+// callers print their human output only when it returns false
+func (o *cliOutput) emitJSON(v interface{}) bool {
+	if !o.json {
+		return false
+	}
+	enc := json.NewEncoder(o.stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		o.errorf("encode output: %v", err)
+	}
+	return true
+}
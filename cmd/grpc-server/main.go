@@ -3,6 +3,8 @@
 // Chức năng:
 //   - High-performance RPC calls với Protocol Buffers
 //   - GetManga, SearchManga, UpdateProgress RPCs
+//   - StreamActivity: server-streaming activity feed
+//   - CreateManga, UpdateManga, DeleteManga: catalog mutations (x-api-key)
 //   - Reflection API support cho debugging
 //   - Audit logging và internal service calls
 //
@@ -10,6 +12,7 @@
 package main
 
 import (
+	"context"
 	"fmt"
 	"log"
 	"net"
@@ -20,8 +23,10 @@ import (
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/reflection"
 
+	"mangahub/internal/activity"
 	grpcpkg "mangahub/internal/grpc"
 	pb "mangahub/internal/grpc/pb"
+	"mangahub/internal/udp"
 	"mangahub/pkg/config"
 	"mangahub/pkg/database"
 	"mangahub/pkg/logger"
@@ -32,6 +37,9 @@ func main() {
 	if err != nil {
 		log.Fatal("failed to load config:", err)
 	}
+	if err := cfg.Validate(); err != nil {
+		log.Fatal(err)
+	}
 
 	logger.Init(logger.Config{
 		Level:  cfg.Logging.Level,
@@ -44,6 +52,7 @@ func main() {
 		MaxOpenConns:    cfg.Database.MaxOpenConns,
 		MaxIdleConns:    cfg.Database.MaxIdleConns,
 		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+		BusyTimeout:     cfg.Database.BusyTimeout,
 	})
 	if err != nil {
 		logger.Fatal("failed to init database:", err)
@@ -61,6 +70,25 @@ func main() {
 		grpc.MaxSendMsgSize(100*1024*1024), // 100MB
 	)
 	mangaService := grpcpkg.NewMangaServiceServer(db.DB)
+
+	// Activity rows are written by the API server, so the feed tails the table
+	feedCtx, stopFeed := context.WithCancel(context.Background())
+	activityFeed := activity.NewFeed(activity.NewRepository(db.DB), activity.DefaultFeedInterval)
+	go func() {
+		if err := activityFeed.Run(feedCtx); err != nil {
+			logger.Errorf("activity feed stopped: %v", err)
+		}
+	}()
+	mangaService.SetActivityFeed(activityFeed)
+	mangaService.SetAPIKey(cfg.GRPC.APIKey)
+	if cfg.GRPC.APIKey == "" {
+		logger.Warn("grpc.api_key is empty: catalog mutation RPCs are disabled")
+	}
+	udpAddr := fmt.Sprintf("%s:%d", cfg.UDP.Host, cfg.UDP.Port)
+	mangaService.SetNotifier(func(n udp.Notification) error {
+		return udp.SendBroadcast(udpAddr, cfg.UDP.Secret, n)
+	})
+
 	pb.RegisterMangaServiceServer(grpcServer, mangaService)
 
 	// Register reflection service for grpcurl
@@ -79,6 +107,8 @@ func main() {
 	<-sigCh
 
 	logger.Info("Shutting down gRPC server...")
+	// Closing the feed ends open activity streams so GracefulStop can finish
+	stopFeed()
 	grpcServer.GracefulStop()
 	logger.Info("gRPC server stopped.")
 }
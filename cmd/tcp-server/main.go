@@ -5,6 +5,7 @@
 //   - Broadcast progress updates đến tất cả clients đã kết nối
 //   - Xử lý concurrent connections với goroutines
 //   - JSON message protocol cho communication
+//   - AUTH / SYNC_SINCE cho delta sync reading progress
 //
 // Port: 9090
 package main
@@ -14,8 +15,11 @@ import (
 	"os/signal"
 	"syscall"
 
+	"mangahub/internal/auth"
+	"mangahub/internal/progress"
 	"mangahub/internal/tcp"
 	"mangahub/pkg/config"
+	"mangahub/pkg/database"
 	"mangahub/pkg/logger"
 )
 
@@ -24,6 +28,9 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
 
 	logger.Init(logger.Config{
 		Level:  cfg.Logging.Level,
@@ -31,8 +38,30 @@ func main() {
 		Output: cfg.Logging.Output,
 	})
 
+	db, err := database.NewDB(database.Config{
+		Path:            cfg.Database.Path,
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+		BusyTimeout:     cfg.Database.BusyTimeout,
+	})
+	if err != nil {
+		logger.Fatal("failed to init database:", err)
+	}
+
 	server := tcp.NewProgressSyncServer(cfg.TCP.Host, cfg.TCP.Port)
 
+	// Delta sync needs the progress table and JWT validation
+	jwtKeys := auth.KeySet{Algorithm: cfg.JWT.Algorithm, Current: auth.SigningKey{ID: cfg.JWT.KeyID, Secret: cfg.JWT.Secret}}
+	for _, k := range cfg.JWT.PreviousKeys {
+		jwtKeys.Previous = append(jwtKeys.Previous, auth.SigningKey(k))
+	}
+	authSvc, err := auth.NewServiceWithKeys(db.DB, jwtKeys, cfg.JWT.Issuer, cfg.JWT.Expiration, cfg.JWT.RefreshExpiration)
+	if err != nil {
+		logger.Fatal("failed to init auth:", err)
+	}
+	server.EnableSync(progress.NewRepository(db.DB), authSvc)
+
 	go func() {
 		if err := server.Start(); err != nil {
 			logger.Fatalf("TCP server error: %v", err)
@@ -50,5 +79,8 @@ func main() {
 	if err := server.Stop(); err != nil {
 		logger.Errorf("error stopping TCP server: %v", err)
 	}
+	if err := db.Close(); err != nil {
+		logger.Errorf("error closing database: %v", err)
+	}
 	logger.Info("TCP server stopped.")
 }
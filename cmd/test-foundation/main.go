@@ -31,6 +31,7 @@ func main() {
 		MaxOpenConns:    cfg.Database.MaxOpenConns,
 		MaxIdleConns:    cfg.Database.MaxIdleConns,
 		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+		BusyTimeout:     cfg.Database.BusyTimeout,
 	})
 	if err != nil {
 		logger.Fatal("Failed to initialize database:", err)
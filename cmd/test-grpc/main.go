@@ -4,12 +4,19 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
+	"io"
+	"os"
+	"strings"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	pb "mangahub/internal/grpc/pb"
 )
@@ -17,12 +24,21 @@ import (
 func main() {
 	host := flag.String("host", "localhost", "gRPC server host")
 	port := flag.Int("port", 9092, "gRPC server port")
-	method := flag.String("method", "get-manga", "Method to call: get-manga, search-manga, update-progress")
+	method := flag.String("method", "get-manga", "Method to call: get-manga, search-manga, update-progress, stream-activity, create-manga, update-manga, delete-manga")
 	mangaID := flag.String("manga", "5463cf5e-ec80-48ba-a3e2-04a8d825e555", "Manga ID (One Piece)")
 	query := flag.String("query", "kimetsu", "Search query")
 	userID := flag.String("user", "test-user", "User ID (for update-progress)")
 	chapter := flag.Int("chapter", 100, "Chapter number (for update-progress)")
 	statusFlag := flag.String("status", "reading", "Status (for update-progress)")
+	filterUser := flag.String("filter-user", "", "Only stream this user's activity (for stream-activity)")
+	filterManga := flag.String("filter-manga", "", "Only stream activity on this manga (for stream-activity)")
+	duration := flag.Duration("duration", 60*time.Second, "How long to listen (for stream-activity)")
+	apiKey := flag.String("api-key", os.Getenv("GRPC_API_KEY"), "x-api-key metadata (for create/update/delete-manga; defaults to $GRPC_API_KEY)")
+	title := flag.String("title", "gRPC Test Manga", "Title (for create/update-manga)")
+	author := flag.String("author", "", "Author (for create/update-manga)")
+	mangaStatus := flag.String("manga-status", "ongoing", "Manga status (for create/update-manga)")
+	genres := flag.String("genres", "", "Comma-separated genre names (for create/update-manga)")
+	fields := flag.String("fields", "title", "Comma-separated fields to write (for update-manga)")
 	flag.Parse()
 
 	addr := fmt.Sprintf("%s:%d", *host, *port)
@@ -48,9 +64,29 @@ func main() {
 		searchMangas(ctx, client, *query)
 	case "update-progress":
 		updateProgress(ctx, client, *userID, *mangaID, *chapter, *statusFlag)
+	case "stream-activity":
+		streamCtx, streamCancel := context.WithTimeout(context.Background(), *duration)
+		defer streamCancel()
+		streamActivity(streamCtx, client, *filterUser, *filterManga)
+	case "create-manga", "update-manga", "delete-manga":
+		ctx = metadata.AppendToOutgoingContext(ctx, "x-api-key", *apiKey)
+		input := &pb.MangaInput{
+			Title:  *title,
+			Author: *author,
+			Status: *mangaStatus,
+			Genres: splitList(*genres),
+		}
+		switch *method {
+		case "create-manga":
+			createManga(ctx, client, input)
+		case "update-manga":
+			updateManga(ctx, client, *mangaID, input, splitList(*fields))
+		default:
+			deleteManga(ctx, client, *mangaID)
+		}
 	default:
 		fmt.Printf("❌ Unknown method: %s\n", *method)
-		fmt.Println("Available methods: get-manga, search-manga, update-progress")
+		fmt.Println("Available methods: get-manga, search-manga, update-progress, stream-activity, create-manga, update-manga, delete-manga")
 	}
 }
 
@@ -131,3 +167,107 @@ func updateProgress(ctx context.Context, client pb.MangaServiceClient, userID, m
 	fmt.Printf("   Status: %s\n", resp.Status)
 	fmt.Printf("   Last Updated: %v\n", time.Unix(resp.Timestamp, 0))
 }
+
+func streamActivity(ctx context.Context, client pb.MangaServiceClient, userID, mangaID string) {
+	fmt.Printf("\n📤 Calling StreamActivity(user=%q, manga=%q)...\n", userID, mangaID)
+
+	stream, err := client.StreamActivity(ctx, &pb.StreamActivityRequest{
+		UserId:  userID,
+		MangaId: mangaID,
+	})
+	if err != nil {
+		fmt.Printf("❌ RPC failed: %v\n", err)
+		return
+	}
+
+	fmt.Println("✅ Listening for activity (Ctrl+C to stop)...")
+	fmt.Println()
+
+	count := 0
+	for {
+		event, err := stream.Recv()
+		if err != nil {
+			if errors.Is(err, io.EOF) || status.Code(err) == codes.DeadlineExceeded {
+				fmt.Printf("\n⏹ Stream ended after %d events\n", count)
+				return
+			}
+			fmt.Printf("❌ Stream error: %v\n", err)
+			return
+		}
+		count++
+
+		detail := ""
+		switch {
+		case event.ChapterNumber > 0:
+			detail = fmt.Sprintf(" (chapter %d)", event.ChapterNumber)
+		case event.Rating > 0:
+			detail = fmt.Sprintf(" (%.1f/10)", event.Rating)
+		}
+		fmt.Printf("[%s] %s %s %s%s\n",
+			time.Unix(event.Timestamp, 0).Format("15:04:05"),
+			event.Username, event.ActivityType, event.MangaTitle, detail)
+	}
+}
+
+func createManga(ctx context.Context, client pb.MangaServiceClient, input *pb.MangaInput) {
+	fmt.Printf("\n📤 Calling CreateManga(title=%q, genres=%v)...\n", input.Title, input.Genres)
+
+	resp, err := client.CreateManga(ctx, &pb.CreateMangaRequest{Manga: input})
+	if err != nil {
+		fmt.Printf("❌ RPC failed: %v\n", err)
+		return
+	}
+	fmt.Println("✅ Manga created!")
+	printManga(resp)
+}
+
+func updateManga(ctx context.Context, client pb.MangaServiceClient, mangaID string, input *pb.MangaInput, fields []string) {
+	fmt.Printf("\n📤 Calling UpdateManga(id=%s, fields=%v)...\n", mangaID, fields)
+
+	resp, err := client.UpdateManga(ctx, &pb.UpdateMangaRequest{
+		MangaId:      mangaID,
+		Manga:        input,
+		UpdateFields: fields,
+	})
+	if err != nil {
+		fmt.Printf("❌ RPC failed: %v\n", err)
+		return
+	}
+	fmt.Println("✅ Manga updated!")
+	printManga(resp)
+}
+
+func deleteManga(ctx context.Context, client pb.MangaServiceClient, mangaID string) {
+	fmt.Printf("\n📤 Calling DeleteManga(id=%s)...\n", mangaID)
+
+	resp, err := client.DeleteManga(ctx, &pb.DeleteMangaRequest{MangaId: mangaID})
+	if err != nil {
+		fmt.Printf("❌ RPC failed: %v\n", err)
+		return
+	}
+	fmt.Printf("✅ Deleted manga %s\n", resp.MangaId)
+}
+
+func printManga(m *pb.MangaResponse) {
+	fmt.Printf("   ID: %s\n", m.Id)
+	fmt.Printf("   Title: %s\n", m.Title)
+	fmt.Printf("   Author: %s\n", m.Author)
+	fmt.Printf("   Status: %s\n", m.Status)
+	if len(m.Genres) > 0 {
+		fmt.Println("   Genres:")
+	}
+	for _, g := range m.Genres {
+		fmt.Printf("     - %s\n", g.Name)
+	}
+}
+
+// splitList splits a comma-separated flag value, dropping blanks
+func splitList(s string) []string {
+	var out []string
+	for _, part := range strings.Split(s, ",") {
+		if part = strings.TrimSpace(part); part != "" {
+			out = append(out, part)
+		}
+	}
+	return out
+}
@@ -1,5 +1,6 @@
 // Package main - UDP Protocol Manual Test
 // Gửi/nhận UDP notifications để test push notification functionality
+// Dùng -secret (= udp.secret của server) để sign BROADCAST và verify notifications nhận được
 package main
 
 import (
@@ -8,14 +9,9 @@ import (
 	"fmt"
 	"net"
 	"time"
-)
 
-type Notification struct {
-	Type      string `json:"type"`
-	MangaID   string `json:"manga_id"`
-	Message   string `json:"message"`
-	Timestamp int64  `json:"timestamp"`
-}
+	"mangahub/internal/udp"
+)
 
 func main() {
 	host := flag.String("host", "localhost", "UDP server host")
@@ -23,8 +19,11 @@ func main() {
 	mangaID := flag.String("manga", "one-piece", "Manga ID")
 	message := flag.String("msg", "New chapter released!", "Notification message")
 	notifType := flag.String("type", "chapter_release", "Notification type (chapter_release, system)")
+	secret := flag.String("secret", "", "Shared notification secret (udp.secret); empty sends unsigned")
 	flag.Parse()
 
+	verifier := udp.NewVerifier(*secret, udp.DefaultReplayWindow)
+
 	serverAddr := fmt.Sprintf("%s:%d", *host, *port)
 	fmt.Printf("📡 UDP Server: %s\n", serverAddr)
 
@@ -62,18 +61,19 @@ func main() {
 	}
 
 	// Send test notification
-	notification := Notification{
+	notification := udp.Notification{
 		Type:      *notifType,
 		MangaID:   *mangaID,
 		Message:   *message,
 		Timestamp: time.Now().Unix(),
 	}
+	udp.Sign(&notification, *secret)
 
 	data, _ := json.Marshal(notification)
 	fmt.Printf("📤 Sending notification:\n%s\n\n", string(data))
 
-	// Send notification to server
-	_, err = conn.WriteToUDP(data, serverUDP)
+	// Ask the server to broadcast it to every subscriber (including us)
+	_, err = conn.WriteToUDP(append([]byte("BROADCAST "), data...), serverUDP)
 	if err != nil {
 		fmt.Printf("❌ Send failed: %v\n", err)
 		return
@@ -93,15 +93,14 @@ func main() {
 			break
 		}
 
-		// Ignore our own message
-		if string(buffer[:n]) == string(data) {
-			continue
-		}
-
 		fmt.Printf("\n📥 Notification from %s:\n%s\n", remoteAddr.String(), string(buffer[:n]))
 
-		var notif Notification
+		var notif udp.Notification
 		if err := json.Unmarshal(buffer[:n], &notif); err == nil {
+			if err := verifier.Verify(notif); err != nil {
+				fmt.Printf("   ⚠️  Rejected: %v\n", err)
+				continue
+			}
 			fmt.Printf("   Type: %s\n", notif.Type)
 			fmt.Printf("   Manga: %s\n", notif.MangaID)
 			fmt.Printf("   Message: %s\n", notif.Message)
@@ -11,6 +11,8 @@ import (
 
 	"mangahub/internal/tui"
 	"mangahub/internal/tui/api"
+	"mangahub/internal/tui/network"
+	"mangahub/internal/tui/views"
 	"mangahub/pkg/config"
 )
 
@@ -26,6 +28,19 @@ func main() {
 	baseURL := fmt.Sprintf("http://%s:%d", cfg.Server.Host, cfg.Server.Port)
 	api.InitClient(baseURL)
 
+	// Reading time estimation for the chapter reader
+	views.ReaderMaxChapterTime = cfg.Reader.MaxChapterTime
+	views.ReaderDefaultChapterTime = cfg.Reader.DefaultChapterTime
+
+	// Notification signature verification
+	// An unexpanded "${UDP_SECRET}" means unsigned, as on the servers
+	if !config.SecretUnset(cfg.UDP.Secret) {
+		network.NotificationSecret = cfg.UDP.Secret
+	}
+	if cfg.UDP.ReplayWindow > 0 {
+		network.NotificationReplayWindow = cfg.UDP.ReplayWindow
+	}
+
 	// Create the TUI application
 	app := tui.NewApp()
 
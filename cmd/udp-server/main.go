@@ -5,18 +5,22 @@
 //   - Gửi chapter release notifications đến subscribers
 //   - Connectionless protocol - không cần maintain connections
 //   - Broadcast notifications đến nhiều clients
+//   - Bỏ qua user đã tắt notifications hoặc mute manga (đọc từ database)
 //
 // Port: 9091
 package main
 
 import (
+	"context"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
+	"mangahub/internal/preferences"
 	"mangahub/internal/udp"
 	"mangahub/pkg/config"
+	"mangahub/pkg/database"
 	"mangahub/pkg/logger"
 )
 
@@ -25,6 +29,9 @@ func main() {
 	if err != nil {
 		panic(err)
 	}
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
 
 	logger.Init(logger.Config{
 		Level:  cfg.Logging.Level,
@@ -33,6 +40,29 @@ func main() {
 	})
 
 	server := udp.NewNotificationServer(cfg.UDP.Host, cfg.UDP.Port)
+	if cfg.UDP.Secret == "" {
+		logger.Warn("udp.secret is empty: notifications are unsigned and BROADCAST requests are not verified")
+	}
+	server.SetSecret(cfg.UDP.Secret, cfg.UDP.ReplayWindow)
+
+	// Notification preferences live in the shared database; without it every
+	// subscriber gets every notification
+	db, err := database.NewDB(database.Config{
+		Path:            cfg.Database.Path,
+		MaxOpenConns:    cfg.Database.MaxOpenConns,
+		MaxIdleConns:    cfg.Database.MaxIdleConns,
+		ConnMaxLifetime: cfg.Database.ConnMaxLifetime,
+		BusyTimeout:     cfg.Database.BusyTimeout,
+	})
+	if err != nil {
+		logger.Warnf("Database unavailable, notification preferences not applied: %v", err)
+	} else {
+		defer db.Close()
+		prefsSvc := preferences.NewService(preferences.NewRepository(db.DB))
+		server.SetFilter(func(ctx context.Context, n udp.Notification, userIDs []string) (map[string]bool, error) {
+			return prefsSvc.BlockedRecipients(ctx, n.MangaID, userIDs)
+		})
+	}
 
 	// Start server in background
 	go func() {
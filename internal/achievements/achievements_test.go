@@ -0,0 +1,107 @@
+// Package achievements - Achievements Tests
+// Unit tests cho việc trao badge: đúng ngưỡng, không trao trùng khi đánh giá lại
+package achievements
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"mangahub/internal/statistics"
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+// setupTestDB opens the shared test database and seeds the rows these tests use
+func setupTestDB(t *testing.T) *sql.DB {
+	db := testutil.OpenDB(t)
+
+	for _, q := range []string{
+		`INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`,
+		`INSERT INTO manga (id, title) VALUES ('m1', 'Berserk')`,
+		`INSERT INTO chapter_history (id, user_id, manga_id, chapter_number) VALUES ('h1', 'u1', 'm1', 1)`,
+		`INSERT INTO manga_ratings (id, manga_id, user_id, rating, review_text) VALUES ('r1', 'm1', 'u1', 9, '   ')`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("seed failed: %v", err)
+		}
+	}
+
+	return db
+}
+
+func TestEvaluateAwardsEachBadgeOnce(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+	svc := NewService(NewRepository(db), statistics.NewService(statistics.NewRepository(db)))
+
+	fresh, err := svc.Evaluate(ctx, "u1")
+	if err != nil {
+		t.Fatalf("Evaluate: %v", err)
+	}
+	// A blank review is not a review
+	if fmt.Sprint(badgeIDs(fresh)) != "[first_chapter]" {
+		t.Errorf("fresh = %v, want [first_chapter]", badgeIDs(fresh))
+	}
+
+	if _, err := db.Exec(`UPDATE manga_ratings SET review_text = 'Peak fiction' WHERE id = 'r1'`); err != nil {
+		t.Fatal(err)
+	}
+	fresh, err = svc.Evaluate(ctx, "u1")
+	if err != nil {
+		t.Fatalf("second Evaluate: %v", err)
+	}
+	if fmt.Sprint(badgeIDs(fresh)) != "[first_review]" {
+		t.Errorf("fresh = %v, want only the new [first_review]", badgeIDs(fresh))
+	}
+
+	if fresh, _ = svc.Evaluate(ctx, "u1"); len(fresh) != 0 {
+		t.Errorf("re-evaluating awarded %v again", badgeIDs(fresh))
+	}
+	var rows int
+	db.QueryRow(`SELECT COUNT(*) FROM achievements WHERE user_id = 'u1'`).Scan(&rows)
+	if rows != 2 {
+		t.Errorf("achievements rows = %d, want 2", rows)
+	}
+}
+
+func TestGetAchievementsListsLockedBadgesWithProgress(t *testing.T) {
+	db := setupTestDB(t)
+	now := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	svc := &service{
+		repo:   NewRepository(db),
+		stats:  statistics.NewService(statistics.NewRepository(db)),
+		badges: models.Badges,
+		now:    func() time.Time { return now },
+	}
+
+	list, err := svc.GetAchievements(context.Background(), "u1")
+	if err != nil {
+		t.Fatalf("GetAchievements: %v", err)
+	}
+	if len(list) != len(models.Badges) {
+		t.Fatalf("got %d achievements, want every badge (%d)", len(list), len(models.Badges))
+	}
+	for _, a := range list {
+		switch a.ID {
+		case "first_chapter":
+			if !a.Earned || a.EarnedAt == nil || !a.EarnedAt.Equal(now) {
+				t.Errorf("first_chapter = %+v, want earned at %v", a, now)
+			}
+		case "chapters_100":
+			if a.Earned || a.Progress != 1 {
+				t.Errorf("chapters_100 = %+v, want locked with progress 1", a)
+			}
+		}
+	}
+}
+
+func badgeIDs(badges []models.Badge) []string {
+	var ids []string
+	for _, b := range badges {
+		ids = append(ids, b.ID)
+	}
+	return ids
+}
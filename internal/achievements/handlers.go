@@ -0,0 +1,45 @@
+// Package achievements - Achievements HTTP Handlers
+// HTTP handlers cho achievements API endpoints
+// Endpoints:
+//   - GET /users/achievements - Every badge, earned or locked, with progress
+package achievements
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"mangahub/internal/auth"
+	"mangahub/pkg/apperrors"
+	"mangahub/pkg/models"
+)
+
+// Handler handles HTTP requests for achievements
+type Handler struct {
+	svc Service
+}
+
+// NewHandler creates a new achievements handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GetAchievements handles GET /users/achievements.
+// Badges are evaluated on each request, so newly reached milestones show up
+// without waiting for a background job.
+func (h *Handler) GetAchievements(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	achievements, err := h.svc.GetAchievements(c.Request.Context(), user.ID)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to get achievements")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(achievements, "achievements"))
+}
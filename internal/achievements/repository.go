@@ -0,0 +1,104 @@
+// Package achievements - Achievements Repository
+// Data access layer cho achievements
+// Chức năng:
+//   - Lưu badge đã đạt (INSERT OR IGNORE: đánh giá lại không tạo bản trùng)
+//   - Lấy danh sách badge đã đạt
+//   - Đếm review và series đã hoàn thành cho các metric
+package achievements
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"mangahub/pkg/database"
+)
+
+// Repository defines data access operations for achievements
+type Repository interface {
+	// Earned returns when the user earned each of their badges, by badge ID
+	Earned(ctx context.Context, userID string) (map[string]time.Time, error)
+
+	// Award records badges as earned at the given time; badges the user
+	// already has keep their original time
+	Award(ctx context.Context, userID string, badgeIDs []string, at time.Time) error
+
+	// CountReviews counts the user's ratings that have review text
+	CountReviews(ctx context.Context, userID string) (int, error)
+
+	// CountCompleted counts the user's library entries marked completed
+	CountCompleted(ctx context.Context, userID string) (int, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new achievements repository
+func NewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+// Earned returns when the user earned each of their badges, by badge ID
+func (r *repository) Earned(ctx context.Context, userID string) (map[string]time.Time, error) {
+	rows, err := r.db.QueryContext(ctx,
+		"SELECT badge_id, earned_at FROM achievements WHERE user_id = ?", userID)
+	if err != nil {
+		return nil, fmt.Errorf("list achievements: %w", err)
+	}
+	defer rows.Close()
+
+	earned := map[string]time.Time{}
+	for rows.Next() {
+		var id string
+		var at time.Time
+		if err := rows.Scan(&id, &at); err != nil {
+			return nil, fmt.Errorf("scan achievement: %w", err)
+		}
+		earned[id] = at
+	}
+	return earned, rows.Err()
+}
+
+// Award records badges as earned at the given time; badges the user
+// already has keep their original time
+func (r *repository) Award(ctx context.Context, userID string, badgeIDs []string, at time.Time) error {
+	return database.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		for _, id := range badgeIDs {
+			if _, err := tx.ExecContext(ctx,
+				"INSERT OR IGNORE INTO achievements (user_id, badge_id, earned_at) VALUES (?, ?, ?)",
+				userID, id, at,
+			); err != nil {
+				return fmt.Errorf("award achievement %s: %w", id, err)
+			}
+		}
+		return nil
+	})
+}
+
+// CountReviews counts the user's ratings that have review text
+func (r *repository) CountReviews(ctx context.Context, userID string) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM manga_ratings
+		WHERE user_id = ? AND TRIM(COALESCE(review_text, '')) != ''`,
+		userID,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count reviews: %w", err)
+	}
+	return n, nil
+}
+
+// CountCompleted counts the user's library entries marked completed
+func (r *repository) CountCompleted(ctx context.Context, userID string) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM reading_progress WHERE user_id = ? AND status = 'completed'", userID,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count completed series: %w", err)
+	}
+	return n, nil
+}
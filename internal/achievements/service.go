@@ -0,0 +1,135 @@
+// Package achievements - Achievements Service
+// Business logic layer cho badges
+// Chức năng:
+//   - Tính metric của user từ reading stats, history và library
+//   - Đánh giá models.Badges và trao badge mới (idempotent)
+//   - Trả về mọi badge kèm trạng thái đạt/chưa đạt và tiến độ
+package achievements
+
+import (
+	"context"
+	"time"
+
+	"mangahub/pkg/apperrors"
+	"mangahub/pkg/models"
+)
+
+// StatsSource provides the reading stats badges are evaluated on.
+// Implemented by statistics.Service.
+type StatsSource interface {
+	GetStatsOverview(ctx context.Context, userID string) (*models.StatsOverview, error)
+	GetGenreDistribution(ctx context.Context, userID string) ([]models.GenreStat, error)
+}
+
+// Service defines business operations for achievements
+type Service interface {
+	// Evaluate awards every badge the user now qualifies for and returns the
+	// newly earned ones. Running it again awards nothing twice.
+	Evaluate(ctx context.Context, userID string) ([]models.Badge, error)
+
+	// GetAchievements evaluates the user's badges and returns all of them,
+	// earned and locked, in display order
+	GetAchievements(ctx context.Context, userID string) ([]models.Achievement, error)
+}
+
+type service struct {
+	repo   Repository
+	stats  StatsSource
+	badges []models.Badge
+	now    func() time.Time
+}
+
+// NewService creates a new achievements service for models.Badges
+func NewService(repo Repository, stats StatsSource) Service {
+	return &service{repo: repo, stats: stats, badges: models.Badges, now: time.Now}
+}
+
+// Evaluate awards every badge the user now qualifies for and returns the newly earned ones
+func (s *service) Evaluate(ctx context.Context, userID string) ([]models.Badge, error) {
+	_, _, fresh, err := s.evaluate(ctx, userID)
+	return fresh, err
+}
+
+// GetAchievements evaluates the user's badges and returns all of them in display order
+func (s *service) GetAchievements(ctx context.Context, userID string) ([]models.Achievement, error) {
+	metrics, earned, _, err := s.evaluate(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := make([]models.Achievement, 0, len(s.badges))
+	for _, b := range s.badges {
+		a := models.Achievement{Badge: b, Progress: min(metrics[b.Metric], b.Threshold)}
+		if at, ok := earned[b.ID]; ok {
+			a.Earned = true
+			a.EarnedAt = &at
+			// Earned badges stay earned, e.g. after a completed series is dropped
+			a.Progress = b.Threshold
+		}
+		result = append(result, a)
+	}
+	return result, nil
+}
+
+// evaluate computes the user's metrics and awards the badges they reach
+// that were not earned yet, returning the metrics, every earned badge and
+// the fresh ones. Awarding is INSERT OR IGNORE, so concurrent evaluations
+// cannot earn a badge twice.
+func (s *service) evaluate(ctx context.Context, userID string) (map[string]int, map[string]time.Time, []models.Badge, error) {
+	metrics, err := s.metrics(ctx, userID)
+	if err != nil {
+		return nil, nil, nil, apperrors.Internal("failed to evaluate achievements", err)
+	}
+	earned, err := s.repo.Earned(ctx, userID)
+	if err != nil {
+		return nil, nil, nil, apperrors.Internal("failed to evaluate achievements", err)
+	}
+
+	var fresh []models.Badge
+	var ids []string
+	for _, b := range s.badges {
+		if _, ok := earned[b.ID]; ok || metrics[b.Metric] < b.Threshold {
+			continue
+		}
+		fresh = append(fresh, b)
+		ids = append(ids, b.ID)
+	}
+	if len(ids) > 0 {
+		at := s.now().UTC()
+		if err := s.repo.Award(ctx, userID, ids, at); err != nil {
+			return nil, nil, nil, apperrors.Internal("failed to award achievements", err)
+		}
+		for _, id := range ids {
+			earned[id] = at
+		}
+	}
+	return metrics, earned, fresh, nil
+}
+
+// metrics computes every models.Metric* value for the user
+func (s *service) metrics(ctx context.Context, userID string) (map[string]int, error) {
+	overview, err := s.stats.GetStatsOverview(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	genres, err := s.stats.GetGenreDistribution(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	reviews, err := s.repo.CountReviews(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	completed, err := s.repo.CountCompleted(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return map[string]int{
+		models.MetricChaptersRead:    overview.TotalChapters,
+		models.MetricLongestStreak:   overview.LongestStreak,
+		models.MetricCompletedSeries: completed,
+		models.MetricReviews:         reviews,
+		models.MetricGenresRead:      len(genres),
+	}, nil
+}
@@ -0,0 +1,149 @@
+// Package activity - Live Activity Feed
+// Tail bảng activity_feed và fan-out activity mới tới các subscriber
+// Rows được ghi bởi process khác (api-server) hoặc bởi triggers nên feed
+// poll theo rowid thay vì được Service báo trực tiếp
+package activity
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"mangahub/pkg/logger"
+	"mangahub/pkg/models"
+)
+
+const (
+	// DefaultFeedInterval is how often the feed checks for new rows
+	DefaultFeedInterval = 2 * time.Second
+
+	feedBatchSize    = 100
+	subscriberBuffer = 64
+)
+
+// Feed tails activity_feed and broadcasts new rows to in-process subscribers
+type Feed struct {
+	repo     Repository
+	interval time.Duration
+
+	mu      sync.Mutex
+	subs    map[chan models.Activity]struct{}
+	stopped bool
+}
+
+// NewFeed creates a feed that polls the repository every interval
+func NewFeed(repo Repository, interval time.Duration) *Feed {
+	if interval <= 0 {
+		interval = DefaultFeedInterval
+	}
+	return &Feed{
+		repo:     repo,
+		interval: interval,
+		subs:     make(map[chan models.Activity]struct{}),
+	}
+}
+
+// Subscribe registers a new subscriber. The channel is closed when cancel is
+// called or the feed stops.
+func (f *Feed) Subscribe() (<-chan models.Activity, func()) {
+	ch := make(chan models.Activity, subscriberBuffer)
+
+	f.mu.Lock()
+	if f.stopped {
+		close(ch)
+	} else {
+		f.subs[ch] = struct{}{}
+	}
+	f.mu.Unlock()
+
+	cancel := func() {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		if _, ok := f.subs[ch]; ok {
+			delete(f.subs, ch)
+			close(ch)
+		}
+	}
+	return ch, cancel
+}
+
+// Run tails the feed until ctx is cancelled, then closes every subscriber.
+// Only rows inserted after Run starts are broadcast.
+func (f *Feed) Run(ctx context.Context) error {
+	defer f.closeAll()
+
+	cursor, err := f.repo.LatestSeq(ctx)
+	if err != nil {
+		return err
+	}
+
+	ticker := time.NewTicker(f.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+
+		cursor = f.poll(ctx, cursor)
+	}
+}
+
+// poll broadcasts every row after cursor and returns the new cursor
+func (f *Feed) poll(ctx context.Context, cursor int64) int64 {
+	// Nobody listening: skip ahead so new subscribers only see fresh rows
+	if f.subscriberCount() == 0 {
+		latest, err := f.repo.LatestSeq(ctx)
+		if err != nil {
+			logger.Warnf("activity feed: %v", err)
+			return cursor
+		}
+		return latest
+	}
+
+	for {
+		activities, next, err := f.repo.GetAfter(ctx, cursor, feedBatchSize)
+		if err != nil {
+			logger.Warnf("activity feed: %v", err)
+			return cursor
+		}
+		for _, a := range activities {
+			f.publish(a)
+		}
+		cursor = next
+		if len(activities) < feedBatchSize {
+			return cursor
+		}
+	}
+}
+
+// publish delivers an activity without blocking on slow subscribers
+func (f *Feed) publish(a models.Activity) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	for ch := range f.subs {
+		select {
+		case ch <- a:
+		default:
+			logger.Warnf("activity feed: subscriber buffer full, dropping activity %s", a.ID)
+		}
+	}
+}
+
+func (f *Feed) subscriberCount() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.subs)
+}
+
+func (f *Feed) closeAll() {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.stopped = true
+	for ch := range f.subs {
+		delete(f.subs, ch)
+		close(ch)
+	}
+}
@@ -0,0 +1,82 @@
+// Package activity - Live Feed Tests
+// Unit tests cho tailing activity_feed và fan-out tới subscribers
+package activity
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+// setupTestDB opens the shared test database and seeds the rows these tests use
+func setupTestDB(t *testing.T) *sql.DB {
+	db := testutil.OpenDB(t)
+
+	for _, q := range []string{
+		`INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'alice', 'a@example.com', 'x', 'Alice')`,
+		`INSERT INTO manga (id, title) VALUES ('m1', 'Berserk')`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("seed failed: %v", err)
+		}
+	}
+
+	return db
+}
+
+func TestFeedBroadcastsNewRows(t *testing.T) {
+	repo := NewRepository(setupTestDB(t))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	chapter := 1
+	record := func(id string) {
+		t.Helper()
+		err := repo.Create(ctx, &models.Activity{
+			ID: id, UserID: "u1", Username: "alice", ActivityType: models.ActivityProgress,
+			MangaID: "m1", MangaTitle: "Berserk", ChapterNumber: &chapter,
+		})
+		if err != nil {
+			t.Fatalf("create activity failed: %v", err)
+		}
+	}
+
+	// Rows from before the feed started are not replayed
+	record("old")
+
+	feed := NewFeed(repo, 10*time.Millisecond)
+	events, unsubscribe := feed.Subscribe()
+	defer unsubscribe()
+
+	done := make(chan struct{})
+	go func() {
+		feed.Run(ctx)
+		close(done)
+	}()
+	time.Sleep(50 * time.Millisecond)
+
+	record("new-1")
+	record("new-2")
+
+	for _, want := range []string{"new-1", "new-2"} {
+		select {
+		case a := <-events:
+			if a.ID != want {
+				t.Fatalf("got activity %q, want %q", a.ID, want)
+			}
+		case <-time.After(2 * time.Second):
+			t.Fatalf("timed out waiting for %s", want)
+		}
+	}
+
+	// Stopping the feed closes subscriber channels
+	cancel()
+	<-done
+	if _, ok := <-events; ok {
+		t.Error("expected subscriber channel to be closed after the feed stops")
+	}
+}
@@ -3,15 +3,22 @@
 package activity
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
+
+	"mangahub/pkg/apperrors"
+	"mangahub/pkg/models"
 )
 
 // Handler handles HTTP requests for activities
 type Handler struct {
-	service *Service
+	service   *Service
+	feed      *Feed         // nil disables GET /activities/stream
+	heartbeat time.Duration // stream keep-alive interval, DefaultHeartbeatInterval when zero
 }
 
 // NewHandler creates a new activity handler
@@ -20,23 +27,32 @@ func NewHandler(service *Service) *Handler {
 }
 
 // GetRecentActivities handles GET /activities
-// Returns recent activities across all users
+// Returns recent activities across all users, newest first
+// Query params: ?limit=20&offset=0&type=comment&before=<next_before>
 func (h *Handler) GetRecentActivities(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
 
-	activities, total, err := h.service.GetRecentActivities(c.Request.Context(), limit, offset)
+	page, err := h.service.GetRecentActivities(c.Request.Context(), models.ActivityQuery{
+		Type:   c.Query("type"),
+		Before: c.Query("before"),
+		Limit:  limit,
+		Offset: offset,
+	})
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		switch {
+		case errors.Is(err, ErrInvalidType):
+			apperrors.Respond(c, apperrors.Validation("type", err.Error()), "")
+		case errors.Is(err, ErrInvalidCursor):
+			apperrors.Respond(c, apperrors.Validation("before", err.Error()), "")
+		default:
+			apperrors.Respond(c, err, "failed to get activities")
+		}
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{
-		"activities": activities,
-		"total":      total,
-		"limit":      limit,
-		"offset":     offset,
-	})
+	// Not wrapped in data: clients read {activities, total, has_more, next_before}
+	c.JSON(http.StatusOK, page)
 }
 
 // GetUserActivities handles GET /activities/user/:userID
@@ -48,7 +64,7 @@ func (h *Handler) GetUserActivities(c *gin.Context) {
 
 	activities, total, err := h.service.GetUserActivities(c.Request.Context(), userID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		apperrors.Respond(c, err, "failed to get activities")
 		return
 	}
 
@@ -0,0 +1,78 @@
+// Package activity - Feed Paging Tests
+// Unit tests cho cursor paging và lọc theo activity type
+package activity
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"mangahub/pkg/models"
+)
+
+func TestGetRecentActivitiesPaging(t *testing.T) {
+	repo := NewRepository(setupTestDB(t))
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	// Rows sharing a timestamp must still page without gaps or repeats
+	at := time.Date(2026, time.March, 1, 12, 0, 0, 0, time.UTC)
+	for i := 0; i < 5; i++ {
+		activityType := models.ActivityProgress
+		if i%2 == 1 {
+			activityType = models.ActivityRating
+		}
+		err := repo.Create(ctx, &models.Activity{
+			ID: fmt.Sprintf("a%d", i), UserID: "u1", Username: "alice", ActivityType: activityType,
+			MangaID: "m1", MangaTitle: "Berserk", CreatedAt: at.Add(time.Duration(i/2) * time.Minute),
+		})
+		if err != nil {
+			t.Fatalf("create activity failed: %v", err)
+		}
+	}
+
+	var seen []string
+	query := models.ActivityQuery{Limit: 2}
+	for page := 0; ; page++ {
+		result, err := svc.GetRecentActivities(ctx, query)
+		if err != nil {
+			t.Fatalf("page %d failed: %v", page, err)
+		}
+		for _, a := range result.Activities {
+			seen = append(seen, a.ID)
+		}
+		if !result.HasMore {
+			break
+		}
+
+		// A new row arriving between pages must not shift the next one
+		if page == 0 {
+			if err := repo.Create(ctx, &models.Activity{
+				ID: "late", UserID: "u1", Username: "alice", ActivityType: models.ActivityProgress,
+				MangaID: "m1", MangaTitle: "Berserk", CreatedAt: at.Add(time.Hour),
+			}); err != nil {
+				t.Fatalf("create activity failed: %v", err)
+			}
+		}
+		query.Before = result.NextBefore
+	}
+	if got := fmt.Sprint(seen); got != "[a4 a3 a2 a1 a0]" {
+		t.Errorf("expected a4..a0 exactly once, got %s", got)
+	}
+
+	ratings, err := svc.GetRecentActivities(ctx, models.ActivityQuery{Type: models.ActivityRating})
+	if err != nil {
+		t.Fatalf("type filter failed: %v", err)
+	}
+	if ratings.Total != 2 || len(ratings.Activities) != 2 || ratings.HasMore {
+		t.Errorf("expected the 2 ratings, got total %d, %d rows", ratings.Total, len(ratings.Activities))
+	}
+
+	if _, err := svc.GetRecentActivities(ctx, models.ActivityQuery{Type: "follow"}); err != ErrInvalidType {
+		t.Errorf("expected ErrInvalidType, got %v", err)
+	}
+	if _, err := svc.GetRecentActivities(ctx, models.ActivityQuery{Before: "not-a-cursor"}); err != ErrInvalidCursor {
+		t.Errorf("expected ErrInvalidCursor, got %v", err)
+	}
+}
@@ -5,7 +5,10 @@ package activity
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -13,11 +16,16 @@ import (
 	"mangahub/pkg/models"
 )
 
+// ErrInvalidCursor is returned for a before cursor that was not issued by the feed
+var ErrInvalidCursor = errors.New("invalid activity cursor")
+
 // Repository defines activity data operations
 type Repository interface {
 	Create(ctx context.Context, activity *models.Activity) error
-	GetRecent(ctx context.Context, limit, offset int) ([]models.Activity, int, error)
+	GetRecent(ctx context.Context, q models.ActivityQuery) (*models.ActivityPage, error)
 	GetByUser(ctx context.Context, userID string, limit, offset int) ([]models.Activity, int, error)
+	GetAfter(ctx context.Context, afterSeq int64, limit int) ([]models.Activity, int64, error)
+	LatestSeq(ctx context.Context) (int64, error)
 }
 
 type repository struct {
@@ -48,40 +56,99 @@ func (r *repository) Create(ctx context.Context, activity *models.Activity) erro
 	return err
 }
 
-// GetRecent retrieves recent activities across all users
-func (r *repository) GetRecent(ctx context.Context, limit, offset int) ([]models.Activity, int, error) {
-	// Get total count
+// GetRecent retrieves a page of activities across all users, newest first.
+// A cursor from a previous page continues strictly after its last row, so rows
+// inserted in the meantime do not shift the page the way offset alone would.
+func (r *repository) GetRecent(ctx context.Context, q models.ActivityQuery) (*models.ActivityPage, error) {
+	where := "1 = 1"
+	var filterArgs []interface{}
+	if q.Type != "" {
+		where += " AND activity_type = ?"
+		filterArgs = append(filterArgs, q.Type)
+	}
+
+	// Total matches the filter, not the cursor
 	var total int
-	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM activity_feed").Scan(&total)
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM activity_feed WHERE "+where, filterArgs...).Scan(&total)
 	if err != nil {
-		return nil, 0, fmt.Errorf("count activities: %w", err)
+		return nil, fmt.Errorf("count activities: %w", err)
+	}
+
+	args := append([]interface{}{}, filterArgs...)
+	if q.Before != "" {
+		createdAt, id, err := decodeCursor(q.Before)
+		if err != nil {
+			return nil, err
+		}
+		// created_at <= ? keeps the range scan on idx_activity_created; id breaks ties
+		where += " AND created_at <= ? AND (created_at < ? OR id < ?)"
+		args = append(args, createdAt, createdAt, id)
 	}
+	// One extra row tells whether another page follows
+	args = append(args, q.Limit+1, q.Offset)
 
-	// Get activities
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, username, activity_type, manga_id, manga_title, 
-		       chapter_number, rating, comment_text, created_at
+		SELECT id, COALESCE(user_id, ''), username, activity_type, manga_id, manga_title,
+		       chapter_number, rating, COALESCE(comment_text, ''), created_at,
+		       CAST(created_at AS TEXT)
 		FROM activity_feed
-		ORDER BY created_at DESC
-		LIMIT ? OFFSET ?`, limit, offset)
+		WHERE `+where+`
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?`, args...)
 	if err != nil {
-		return nil, 0, fmt.Errorf("query activities: %w", err)
+		return nil, fmt.Errorf("query activities: %w", err)
 	}
 	defer rows.Close()
 
-	var activities []models.Activity
+	page := &models.ActivityPage{
+		Activities: []models.Activity{},
+		Total:      total,
+		Limit:      q.Limit,
+		Offset:     q.Offset,
+	}
+	var lastCreatedAt string
 	for rows.Next() {
+		if len(page.Activities) == q.Limit {
+			page.HasMore = true
+			break
+		}
 		var a models.Activity
 		err := rows.Scan(&a.ID, &a.UserID, &a.Username, &a.ActivityType,
 			&a.MangaID, &a.MangaTitle, &a.ChapterNumber, &a.Rating,
-			&a.CommentText, &a.CreatedAt)
+			&a.CommentText, &a.CreatedAt, &lastCreatedAt)
 		if err != nil {
-			return nil, 0, fmt.Errorf("scan activity: %w", err)
+			return nil, fmt.Errorf("scan activity: %w", err)
 		}
-		activities = append(activities, a)
+		page.Activities = append(page.Activities, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate activities: %w", err)
 	}
 
-	return activities, total, nil
+	if page.HasMore {
+		last := page.Activities[len(page.Activities)-1]
+		page.NextBefore = encodeCursor(lastCreatedAt, last.ID)
+	}
+	return page, nil
+}
+
+// encodeCursor packs a row's stored created_at text and id into an opaque cursor.
+// The raw text is kept so the cursor compares equal to what SQLite holds.
+func encodeCursor(createdAt, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt + "|" + id))
+}
+
+// decodeCursor unpacks a cursor made by encodeCursor
+func decodeCursor(cursor string) (string, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", ErrInvalidCursor
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || createdAt == "" || id == "" {
+		return "", "", ErrInvalidCursor
+	}
+	return createdAt, id, nil
 }
 
 // GetByUser retrieves activities for a specific user
@@ -94,8 +161,8 @@ func (r *repository) GetByUser(ctx context.Context, userID string, limit, offset
 	}
 
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT id, user_id, username, activity_type, manga_id, manga_title,
-		       chapter_number, rating, comment_text, created_at
+		SELECT id, COALESCE(user_id, ''), username, activity_type, manga_id, manga_title,
+		       chapter_number, rating, COALESCE(comment_text, ''), created_at
 		FROM activity_feed
 		WHERE user_id = ?
 		ORDER BY created_at DESC
@@ -119,3 +186,47 @@ func (r *repository) GetByUser(ctx context.Context, userID string, limit, offset
 
 	return activities, total, nil
 }
+
+// GetAfter retrieves activities inserted after the given sequence, oldest first.
+// The sequence is the SQLite rowid, which grows with every insert (including
+// rows written by triggers), so the last returned value works as a tail cursor.
+func (r *repository) GetAfter(ctx context.Context, afterSeq int64, limit int) ([]models.Activity, int64, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT rowid, id, COALESCE(user_id, ''), username, activity_type, manga_id, manga_title,
+		       chapter_number, rating, COALESCE(comment_text, ''), created_at
+		FROM activity_feed
+		WHERE rowid > ?
+		ORDER BY rowid ASC
+		LIMIT ?`, afterSeq, limit)
+	if err != nil {
+		return nil, afterSeq, fmt.Errorf("query new activities: %w", err)
+	}
+	defer rows.Close()
+
+	lastSeq := afterSeq
+	var activities []models.Activity
+	for rows.Next() {
+		var a models.Activity
+		err := rows.Scan(&lastSeq, &a.ID, &a.UserID, &a.Username, &a.ActivityType,
+			&a.MangaID, &a.MangaTitle, &a.ChapterNumber, &a.Rating,
+			&a.CommentText, &a.CreatedAt)
+		if err != nil {
+			return nil, afterSeq, fmt.Errorf("scan activity: %w", err)
+		}
+		activities = append(activities, a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, afterSeq, fmt.Errorf("iterate new activities: %w", err)
+	}
+
+	return activities, lastSeq, nil
+}
+
+// LatestSeq returns the sequence of the newest activity, 0 for an empty feed
+func (r *repository) LatestSeq(ctx context.Context) (int64, error) {
+	var seq int64
+	if err := r.db.QueryRowContext(ctx, "SELECT COALESCE(MAX(rowid), 0) FROM activity_feed").Scan(&seq); err != nil {
+		return 0, fmt.Errorf("query latest activity: %w", err)
+	}
+	return seq, nil
+}
@@ -4,11 +4,15 @@ package activity
 
 import (
 	"context"
+	"errors"
 	"fmt"
 
 	"mangahub/pkg/models"
 )
 
+// ErrInvalidType is returned when filtering by an unknown activity type
+var ErrInvalidType = errors.New("type must be one of comment, rating, progress, list_add, status_change")
+
 // Service provides activity business logic
 type Service struct {
 	repo Repository
@@ -70,15 +74,21 @@ func (s *Service) RecordCommentAdded(ctx context.Context, userID, username, mang
 	return s.repo.Create(ctx, activity)
 }
 
-// GetRecentActivities retrieves recent activities
-func (s *Service) GetRecentActivities(ctx context.Context, limit, offset int) ([]models.Activity, int, error) {
-	if limit <= 0 {
-		limit = 20
+// GetRecentActivities retrieves a page of recent activities
+func (s *Service) GetRecentActivities(ctx context.Context, q models.ActivityQuery) (*models.ActivityPage, error) {
+	if q.Limit <= 0 {
+		q.Limit = 20
 	}
-	if limit > 100 {
-		limit = 100
+	if q.Limit > 100 {
+		q.Limit = 100
+	}
+	if q.Offset < 0 {
+		q.Offset = 0
+	}
+	if q.Type != "" && !models.IsActivityType(q.Type) {
+		return nil, ErrInvalidType
 	}
-	return s.repo.GetRecent(ctx, limit, offset)
+	return s.repo.GetRecent(ctx, q)
 }
 
 // GetUserActivities retrieves activities for a specific user
@@ -0,0 +1,121 @@
+// Package activity - Activity Feed SSE Stream
+// GET /activities/stream: đẩy activity mới qua Server-Sent Events
+// Chức năng:
+//   - Dùng chung Feed broadcaster với gRPC StreamActivity
+//   - Frame chuẩn SSE: id:/event:/data: và comment heartbeat cho proxy
+//   - Kết thúc khi client ngắt kết nối hoặc feed dừng (shutdown)
+package activity
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mangahub/pkg/apperrors"
+	"mangahub/pkg/logger"
+	"mangahub/pkg/models"
+)
+
+const (
+	// DefaultHeartbeatInterval keeps idle streams below common proxy read
+	// timeouts (nginx defaults to 60s)
+	DefaultHeartbeatInterval = 15 * time.Second
+
+	// sseRetry is the reconnect delay suggested to EventSource clients
+	sseRetry = 5 * time.Second
+)
+
+// SetFeed enables GET /activities/stream; without a feed it answers 503
+func (h *Handler) SetFeed(feed *Feed) {
+	h.feed = feed
+}
+
+// StreamActivities handles GET /activities/stream
+// Pushes new activities as "activity" events until the client disconnects.
+// Query params: ?type=comment&user_id=<id>&manga_id=<id> (all optional)
+func (h *Handler) StreamActivities(c *gin.Context) {
+	if h.feed == nil {
+		c.JSON(http.StatusServiceUnavailable,
+			models.NewErrorResponse(models.ErrCodeServiceUnavailable, "activity stream is not enabled", nil))
+		return
+	}
+	activityType := c.Query("type")
+	if activityType != "" && !models.IsActivityType(activityType) {
+		apperrors.Respond(c, apperrors.Validation("type", ErrInvalidType.Error()), "")
+		return
+	}
+	userID, mangaID := c.Query("user_id"), c.Query("manga_id")
+
+	events, cancel := h.feed.Subscribe()
+	defer cancel()
+
+	// The stream outlives the server's write timeout
+	if err := http.NewResponseController(c.Writer).SetWriteDeadline(time.Time{}); err != nil {
+		logger.Warnf("activity stream: cannot clear write deadline: %v", err)
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// Stops nginx from buffering the response
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	if err := writeSSE(c.Writer, "retry: %d\n\n", sseRetry.Milliseconds()); err != nil {
+		return
+	}
+
+	heartbeat := time.NewTicker(h.heartbeatInterval())
+	defer heartbeat.Stop()
+
+	ctx := c.Request.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-heartbeat.C:
+			if err := writeSSE(c.Writer, ": heartbeat\n\n"); err != nil {
+				return
+			}
+		case a, ok := <-events:
+			if !ok {
+				// Feed stopped: the server is shutting down
+				return
+			}
+			if (activityType != "" && a.ActivityType != activityType) ||
+				(userID != "" && a.UserID != userID) ||
+				(mangaID != "" && a.MangaID != mangaID) {
+				continue
+			}
+			data, err := json.Marshal(a)
+			if err != nil {
+				logger.Warnf("activity stream: encode activity %s: %v", a.ID, err)
+				continue
+			}
+			// JSON has no raw newlines, so one data: line holds the event
+			if err := writeSSE(c.Writer, "id: %s\nevent: activity\ndata: %s\n\n", a.ID, data); err != nil {
+				return
+			}
+		}
+	}
+}
+
+func (h *Handler) heartbeatInterval() time.Duration {
+	if h.heartbeat > 0 {
+		return h.heartbeat
+	}
+	return DefaultHeartbeatInterval
+}
+
+// writeSSE writes one frame and flushes it to the client
+func writeSSE(w gin.ResponseWriter, format string, args ...any) error {
+	if _, err := fmt.Fprintf(w, format, args...); err != nil {
+		return err
+	}
+	w.Flush()
+	return nil
+}
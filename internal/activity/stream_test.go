@@ -0,0 +1,122 @@
+// Package activity - SSE Stream Tests
+// Kiểm tra framing SSE, filter, heartbeat và teardown khi client ngắt kết nối
+package activity
+
+import (
+	"bufio"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mangahub/pkg/models"
+)
+
+func TestStreamActivitiesSendsFramedEvents(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := NewRepository(setupTestDB(t))
+
+	feedCtx, stopFeed := context.WithCancel(context.Background())
+	defer stopFeed()
+	feed := NewFeed(repo, 10*time.Millisecond)
+	go feed.Run(feedCtx)
+
+	h := NewHandler(NewService(repo))
+	h.SetFeed(feed)
+	h.heartbeat = 20 * time.Millisecond
+	router := gin.New()
+	router.GET("/activities/stream", h.StreamActivities)
+	srv := httptest.NewServer(router)
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL+"/activities/stream?type=comment", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("stream request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); ct != "text/event-stream" {
+		t.Fatalf("Content-Type = %q, want text/event-stream", ct)
+	}
+
+	lines := make(chan string)
+	go func() {
+		defer close(lines)
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			lines <- scanner.Text()
+		}
+	}()
+	next := func() string {
+		t.Helper()
+		select {
+		case l, ok := <-lines:
+			if !ok {
+				t.Fatal("stream ended early")
+			}
+			return l
+		case <-time.After(2 * time.Second):
+			t.Fatal("timed out waiting for stream data")
+		}
+		return ""
+	}
+
+	// Wait for a heartbeat so the subscription is live before recording
+	for next() != ": heartbeat" {
+	}
+
+	chapter := 3
+	for _, a := range []*models.Activity{
+		{ID: "skip", UserID: "u1", Username: "alice", ActivityType: models.ActivityProgress, MangaID: "m1", MangaTitle: "Berserk", ChapterNumber: &chapter},
+		{ID: "c1", UserID: "u1", Username: "alice", ActivityType: models.ActivityComment, MangaID: "m1", MangaTitle: "Berserk", CommentText: "wow"},
+	} {
+		if err := repo.Create(context.Background(), a); err != nil {
+			t.Fatalf("create activity failed: %v", err)
+		}
+	}
+
+	line := next()
+	for line == ": heartbeat" || line == "" {
+		line = next()
+	}
+	if line != "id: c1" {
+		t.Fatalf("first event line = %q, want id: c1 (progress filtered out)", line)
+	}
+	if l := next(); l != "event: activity" {
+		t.Fatalf("event line = %q", l)
+	}
+	if l := next(); !strings.HasPrefix(l, "data: {") || !strings.Contains(l, `"id":"c1"`) {
+		t.Fatalf("data line = %q", l)
+	}
+	if l := next(); l != "" {
+		t.Fatalf("frame not terminated by a blank line: %q", l)
+	}
+
+	// Disconnecting ends the handler and drops its subscription
+	cancel()
+	deadline := time.Now().Add(2 * time.Second)
+	for feed.subscriberCount() != 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("subscription not released after client disconnect")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+func TestStreamActivitiesWithoutFeed(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/activities/stream", NewHandler(nil).StreamActivities)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/activities/stream", nil))
+	if w.Code != http.StatusServiceUnavailable {
+		t.Fatalf("status = %d, want 503", w.Code)
+	}
+}
@@ -4,17 +4,29 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"mangahub/pkg/apperrors"
+	"mangahub/pkg/logger"
 	"mangahub/pkg/models"
 )
 
 type Handler struct {
 	svc Service
+
+	// exposeVerificationToken returns verification tokens in responses
+	// (development, where no mail is sent)
+	exposeVerificationToken bool
 }
 
 func NewHandler(svc Service) *Handler {
 	return &Handler{svc: svc}
 }
 
+// SetExposeVerificationToken makes register and resend return the
+// verification token in the response body
+func (h *Handler) SetExposeVerificationToken(expose bool) {
+	h.exposeVerificationToken = expose
+}
+
 func (h *Handler) Register(c *gin.Context) {
 	var req models.RegisterRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
@@ -25,18 +37,69 @@ func (h *Handler) Register(c *gin.Context) {
 
 	user, err := h.svc.Register(c.Request.Context(), req)
 	if err != nil {
-		if appErr, ok := err.(*models.AppError); ok {
-			c.JSON(appErr.StatusCode,
-				models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
-			return
-		}
-		c.JSON(http.StatusInternalServerError,
-			models.NewErrorResponse(models.ErrCodeInternal, "unexpected error", nil))
+		apperrors.Respond(c, err, "unexpected error")
 		return
 	}
 
+	resp := models.RegisterResponse{UserProfile: *user}
+	// The account exists either way; a failed token can be reissued with /auth/verify/resend
+	if verification, err := h.svc.IssueEmailVerification(c.Request.Context(), user.ID); err != nil {
+		logger.Warnf("auth: failed to issue verification token for %s: %v", user.ID, err)
+	} else {
+		h.deliverVerification(user.Username, verification)
+		if h.exposeVerificationToken {
+			resp.VerificationToken = verification.Token
+			resp.VerificationExpiresAt = &verification.ExpiresAt
+		}
+	}
+
 	c.JSON(http.StatusCreated,
-		models.NewSuccessResponse(user, "user registered successfully"))
+		models.NewSuccessResponse(resp, "user registered successfully; verify your email to comment and rate"))
+}
+
+// VerifyEmail marks the token owner's email as verified
+// Query: token (from the verification link)
+func (h *Handler) VerifyEmail(c *gin.Context) {
+	user, err := h.svc.VerifyEmail(c.Request.Context(), c.Query("token"))
+	if err != nil {
+		apperrors.Respond(c, err, "failed to verify email")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(user, "email verified"))
+}
+
+// ResendVerification issues a new verification token for the current user;
+// earlier tokens stop working
+func (h *Handler) ResendVerification(c *gin.Context) {
+	user := GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "not authenticated", nil))
+		return
+	}
+
+	verification, err := h.svc.IssueEmailVerification(c.Request.Context(), user.ID)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to issue verification token")
+		return
+	}
+	h.deliverVerification(user.Username, verification)
+
+	data := map[string]interface{}{"expires_at": verification.ExpiresAt}
+	if h.exposeVerificationToken {
+		data["verification_token"] = verification.Token
+	}
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(data, "verification token issued"))
+}
+
+// deliverVerification hands the token to the user. There is no mailer yet, so
+// the verification link is written to the debug log.
+func (h *Handler) deliverVerification(username string, v *models.EmailVerification) {
+	logger.Debugf("auth: verification link for %s (expires %s): /auth/verify?token=%s",
+		username, v.ExpiresAt.Format("2006-01-02 15:04"), v.Token)
 }
 
 func (h *Handler) Login(c *gin.Context) {
@@ -49,13 +112,7 @@ func (h *Handler) Login(c *gin.Context) {
 
 	resp, err := h.svc.Login(c.Request.Context(), req)
 	if err != nil {
-		if appErr, ok := err.(*models.AppError); ok {
-			c.JSON(appErr.StatusCode,
-				models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
-			return
-		}
-		c.JSON(http.StatusInternalServerError,
-			models.NewErrorResponse(models.ErrCodeInternal, "unexpected error", nil))
+		apperrors.Respond(c, err, "unexpected error")
 		return
 	}
 
@@ -63,22 +120,29 @@ func (h *Handler) Login(c *gin.Context) {
 		models.NewSuccessResponse(resp, "login successful"))
 }
 
-// GetMe returns the currently authenticated user's profile
+// GetMe returns the currently authenticated user's profile, read from the
+// database so email_verified is current
 func (h *Handler) GetMe(c *gin.Context) {
-	user := GetCurrentUser(c)
-	if user == nil {
+	current := GetCurrentUser(c)
+	if current == nil {
 		c.JSON(http.StatusUnauthorized,
 			models.NewErrorResponse(models.ErrCodeUnauthorized, "not authenticated", nil))
 		return
 	}
 
+	user, err := h.svc.GetUserByID(c.Request.Context(), current.ID)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to load user")
+		return
+	}
+
 	c.JSON(http.StatusOK,
 		models.NewSuccessResponse(user, "user profile retrieved"))
 }
 
 // Logout handles user logout
-// Note: With stateless JWT, we just return success. 
-// Token blacklisting will be implemented with Redis in Phase 2.
+// Revokes the refresh token in the body (or all of the user's refresh tokens
+// when none is given). The short-lived access token simply expires.
 func (h *Handler) Logout(c *gin.Context) {
 	user := GetCurrentUser(c)
 	if user == nil {
@@ -87,8 +151,14 @@ func (h *Handler) Logout(c *gin.Context) {
 		return
 	}
 
-	// TODO Phase 2: Add token to blacklist in Redis
-	// For now, client should discard the token
+	// Body is optional
+	var req models.RefreshTokenRequest
+	_ = c.ShouldBindJSON(&req)
+
+	if err := h.svc.RevokeRefreshToken(c.Request.Context(), user.ID, req.RefreshToken); err != nil {
+		apperrors.Respond(c, err, "failed to logout")
+		return
+	}
 
 	c.JSON(http.StatusOK,
 		models.NewSuccessResponse(map[string]interface{}{
@@ -97,9 +167,9 @@ func (h *Handler) Logout(c *gin.Context) {
 		}, "logout successful"))
 }
 
-// RefreshToken generates a new token for the current user
-// Useful for extending session without re-login
-func (h *Handler) RefreshToken(c *gin.Context) {
+// DeleteMe deletes the current user's account and all of its data
+// Request body: { password }
+func (h *Handler) DeleteMe(c *gin.Context) {
 	user := GetCurrentUser(c)
 	if user == nil {
 		c.JSON(http.StatusUnauthorized,
@@ -107,22 +177,74 @@ func (h *Handler) RefreshToken(c *gin.Context) {
 		return
 	}
 
-	// Get new token from service
-	token, err := h.svc.RefreshToken(c.Request.Context(), user.ID)
-	if err != nil {
-		if appErr, ok := err.(*models.AppError); ok {
-			c.JSON(appErr.StatusCode,
-				models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
-			return
-		}
-		c.JSON(http.StatusInternalServerError,
-			models.NewErrorResponse(models.ErrCodeInternal, "failed to refresh token", nil))
+	var req models.DeleteAccountRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeBadRequest, "invalid JSON body", map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	if err := h.svc.DeleteAccount(c.Request.Context(), user.ID, req); err != nil {
+		apperrors.Respond(c, err, "failed to delete account")
 		return
 	}
 
 	c.JSON(http.StatusOK,
 		models.NewSuccessResponse(map[string]interface{}{
-			"token":   token,
 			"user_id": user.ID,
+		}, "account deleted"))
+}
+
+// RefreshToken exchanges a refresh token for a new access/refresh token pair
+// Request body: { refresh_token }
+// The presented refresh token is revoked (rotation).
+func (h *Handler) RefreshToken(c *gin.Context) {
+	var req models.RefreshTokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil || req.RefreshToken == "" {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "refresh token required", nil))
+		return
+	}
+
+	resp, err := h.svc.RefreshToken(c.Request.Context(), req.RefreshToken)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to refresh token")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(map[string]interface{}{
+			"token":              resp.Token,
+			"expires_at":         resp.ExpiresAt,
+			"refresh_token":      resp.RefreshToken,
+			"refresh_expires_at": resp.RefreshExpiresAt,
+			"user_id":            resp.User.ID,
+			"user":               resp.User,
 		}, "token refreshed"))
 }
+
+// ChangePassword replaces the current user's password and returns a new token pair
+func (h *Handler) ChangePassword(c *gin.Context) {
+	user := GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "not authenticated", nil))
+		return
+	}
+
+	var req models.ChangePasswordRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeBadRequest, "invalid JSON body", map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	resp, err := h.svc.ChangePassword(c.Request.Context(), user.ID, req)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to change password")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(resp, "password changed successfully"))
+}
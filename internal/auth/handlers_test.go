@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"mangahub/pkg/models"
 
@@ -180,7 +181,8 @@ func TestLoginFail(t *testing.T) {
 type mockAuthService struct {
 	registerFunc     func(ctx context.Context, req models.RegisterRequest) (*models.UserProfile, error)
 	loginFunc        func(ctx context.Context, req models.LoginRequest) (*models.LoginResponse, error)
-	refreshTokenFunc func(ctx context.Context, userID string) (string, error)
+	refreshTokenFunc func(ctx context.Context, refreshToken string) (*models.LoginResponse, error)
+	revokeFunc       func(ctx context.Context, userID, refreshToken string) error
 	getUserByIDFunc  func(ctx context.Context, userID string) (*models.UserProfile, error)
 }
 
@@ -202,11 +204,26 @@ func (m *mockAuthService) ParseToken(token string) (*models.UserProfile, error)
 	return nil, nil
 }
 
-func (m *mockAuthService) RefreshToken(ctx context.Context, userID string) (string, error) {
+func (m *mockAuthService) RefreshToken(ctx context.Context, refreshToken string) (*models.LoginResponse, error) {
 	if m.refreshTokenFunc != nil {
-		return m.refreshTokenFunc(ctx, userID)
+		return m.refreshTokenFunc(ctx, refreshToken)
 	}
-	return "new-mock-token", nil
+	return &models.LoginResponse{Token: "new-mock-token", RefreshToken: "new-mock-refresh"}, nil
+}
+
+func (m *mockAuthService) RevokeRefreshToken(ctx context.Context, userID, refreshToken string) error {
+	if m.revokeFunc != nil {
+		return m.revokeFunc(ctx, userID, refreshToken)
+	}
+	return nil
+}
+
+func (m *mockAuthService) ChangePassword(ctx context.Context, userID string, req models.ChangePasswordRequest) (*models.LoginResponse, error) {
+	return &models.LoginResponse{Token: "new-mock-token", RefreshToken: "new-mock-refresh"}, nil
+}
+
+func (m *mockAuthService) DeleteAccount(ctx context.Context, userID string, req models.DeleteAccountRequest) error {
+	return nil
 }
 
 func (m *mockAuthService) GetUserByID(ctx context.Context, userID string) (*models.UserProfile, error) {
@@ -216,6 +233,20 @@ func (m *mockAuthService) GetUserByID(ctx context.Context, userID string) (*mode
 	return &models.UserProfile{ID: userID, Username: "testuser"}, nil
 }
 
+func (m *mockAuthService) IssueEmailVerification(ctx context.Context, userID string) (*models.EmailVerification, error) {
+	return &models.EmailVerification{Token: "mock-verification-token", ExpiresAt: time.Now().Add(time.Hour)}, nil
+}
+
+func (m *mockAuthService) VerifyEmail(ctx context.Context, token string) (*models.UserProfile, error) {
+	return &models.UserProfile{ID: "user-123", Username: "testuser", EmailVerified: true}, nil
+}
+
+func (m *mockAuthService) RequireVerifiedEmail(ctx context.Context, userID string) error {
+	return nil
+}
+
+func (m *mockAuthService) SetVerificationTTL(ttl time.Duration) {}
+
 // Helper to set up authenticated context
 func setupAuthenticatedRouter(handler *Handler) *gin.Engine {
 	router := gin.Default()
@@ -296,15 +327,22 @@ func TestRefreshToken(t *testing.T) {
 	gin.SetMode(gin.TestMode)
 
 	svc := &mockAuthService{
-		refreshTokenFunc: func(ctx context.Context, userID string) (string, error) {
-			return "refreshed-token-abc", nil
+		refreshTokenFunc: func(ctx context.Context, refreshToken string) (*models.LoginResponse, error) {
+			assert.Equal(t, "old-refresh-token", refreshToken)
+			return &models.LoginResponse{
+				Token:        "refreshed-token-abc",
+				RefreshToken: "rotated-refresh-token",
+				User:         models.UserProfile{ID: "user-123", Username: "testuser"},
+			}, nil
 		},
 	}
 	handler := NewHandler(svc)
-	router := setupAuthenticatedRouter(handler)
+	router := gin.Default()
 	router.POST("/auth/refresh", handler.RefreshToken)
 
-	req := httptest.NewRequest("POST", "/auth/refresh", nil)
+	jsonBody, _ := json.Marshal(map[string]string{"refresh_token": "old-refresh-token"})
+	req := httptest.NewRequest("POST", "/auth/refresh", bytes.NewReader(jsonBody))
+	req.Header.Set("Content-Type", "application/json")
 	w := httptest.NewRecorder()
 
 	router.ServeHTTP(w, req)
@@ -317,6 +355,7 @@ func TestRefreshToken(t *testing.T) {
 
 	data := resp["data"].(map[string]interface{})
 	assert.Equal(t, "refreshed-token-abc", data["token"])
+	assert.Equal(t, "rotated-refresh-token", data["refresh_token"])
 	assert.Equal(t, "user-123", data["user_id"])
 }
 
@@ -0,0 +1,89 @@
+// Package auth - JWT Signing Keys
+// Key rotation cho access token: ký bằng key hiện tại, chấp nhận cả key cũ
+// Chức năng:
+//   - Mỗi key có ID, ghi vào header "kid" của token
+//   - Token không có kid hoặc kid lạ bị từ chối
+//   - Thuật toán ký cấu hình được (HS256/HS384/HS512)
+package auth
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/golang-jwt/jwt/v4"
+)
+
+// DefaultKeyID names the configured secret when no key ID is set
+const DefaultKeyID = "primary"
+
+// DefaultAlgorithm signs tokens when no algorithm is set
+const DefaultAlgorithm = "HS256"
+
+// SigningKey is an HMAC secret, named in the kid header of the tokens it signs
+type SigningKey struct {
+	ID     string
+	Secret string
+}
+
+// KeySet signs new tokens with Current and accepts tokens signed by Current
+// or any Previous key. To rotate, add a new Current and move the old one to
+// Previous until the tokens it signed have expired.
+type KeySet struct {
+	Algorithm string // HS256, HS384 or HS512; empty means DefaultAlgorithm
+	Current   SigningKey
+	Previous  []SigningKey
+}
+
+var errUnknownKey = errors.New("token signed by an unknown key")
+
+// keyring is a validated KeySet
+type keyring struct {
+	method  jwt.SigningMethod
+	current SigningKey
+	byID    map[string][]byte
+}
+
+func newKeyring(ks KeySet) (*keyring, error) {
+	alg := ks.Algorithm
+	if alg == "" {
+		alg = DefaultAlgorithm
+	}
+	method, ok := jwt.GetSigningMethod(alg).(*jwt.SigningMethodHMAC)
+	if !ok {
+		return nil, fmt.Errorf("unsupported JWT algorithm %q (use HS256, HS384 or HS512)", alg)
+	}
+
+	kr := &keyring{method: method, current: ks.Current, byID: map[string][]byte{}}
+	for _, k := range append([]SigningKey{ks.Current}, ks.Previous...) {
+		if k.ID == "" {
+			return nil, errors.New("JWT key without an ID")
+		}
+		if _, dup := kr.byID[k.ID]; dup {
+			return nil, fmt.Errorf("duplicate JWT key ID %q", k.ID)
+		}
+		kr.byID[k.ID] = []byte(k.Secret)
+	}
+	return kr, nil
+}
+
+// sign signs claims with the current key and names it in the kid header
+func (kr *keyring) sign(claims jwt.Claims) (string, error) {
+	token := jwt.NewWithClaims(kr.method, claims)
+	token.Header["kid"] = kr.current.ID
+	return token.SignedString([]byte(kr.current.Secret))
+}
+
+// verificationKey is the jwt.Keyfunc: the secret named by the token's kid.
+// Any HMAC algorithm is accepted so that changing the algorithm does not
+// invalidate tokens that are still live.
+func (kr *keyring) verificationKey(t *jwt.Token) (interface{}, error) {
+	if _, ok := t.Method.(*jwt.SigningMethodHMAC); !ok {
+		return nil, fmt.Errorf("unexpected signing method %v", t.Header["alg"])
+	}
+	kid, _ := t.Header["kid"].(string)
+	secret, ok := kr.byID[kid]
+	if !ok {
+		return nil, errUnknownKey
+	}
+	return secret, nil
+}
@@ -5,6 +5,7 @@ import (
 	"strings"
 
 	"github.com/gin-gonic/gin"
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/models"
 )
 
@@ -45,6 +46,41 @@ func JWTMiddleware(authService Service) gin.HandlerFunc {
 	}
 }
 
+// OptionalJWTMiddleware sets the current user when a valid Bearer token is sent
+// and lets the request through anonymously otherwise (public routes that
+// personalise their response)
+func OptionalJWTMiddleware(authService Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		parts := strings.SplitN(c.GetHeader("Authorization"), " ", 2)
+		if len(parts) == 2 && strings.EqualFold(parts[0], "Bearer") {
+			if userProfile, err := authService.ParseToken(parts[1]); err == nil {
+				c.Set(ContextUserKey, userProfile)
+			}
+		}
+		c.Next()
+	}
+}
+
+// RequireVerifiedEmail rejects users whose email is not verified with 403
+// EMAIL_NOT_VERIFIED. Runs after JWTMiddleware; the flag is read from the
+// database so verifying takes effect without a new token.
+func RequireVerifiedEmail(authService Service) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user := GetCurrentUser(c)
+		if user == nil {
+			c.AbortWithStatusJSON(http.StatusUnauthorized,
+				models.NewErrorResponse(models.ErrCodeUnauthorized, "not authenticated", nil))
+			return
+		}
+		if err := authService.RequireVerifiedEmail(c.Request.Context(), user.ID); err != nil {
+			apperrors.Respond(c, err, "failed to check email verification")
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
+
 func GetCurrentUser(c *gin.Context) *models.UserProfile {
 	val, exists := c.Get(ContextUserKey)
 	if !exists {
@@ -3,18 +3,25 @@
 // Chức năng:
 //   - User registration với password hashing (bcrypt)
 //   - User login với JWT token generation
-//   - Token validation và parsing
+//   - Token validation và parsing (key rotation qua header kid)
+//   - Refresh token rotation với reuse detection
+//   - Email verification token (hết hạn sau verificationTTL)
+//   - Xóa tài khoản cùng toàn bộ dữ liệu (cần xác nhận mật khẩu)
 //   - Session management
 package auth
 
 import (
 	"context"
+	"crypto/rand"
+	"crypto/sha256"
 	"database/sql"
+	"encoding/hex"
 	"errors"
 	"time"
 
 	"github.com/golang-jwt/jwt/v4"
 	"github.com/google/uuid"
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/models"
 	"mangahub/pkg/utils"
 )
@@ -23,15 +30,27 @@ type Service interface {
 	Register(ctx context.Context, req models.RegisterRequest) (*models.UserProfile, error)
 	Login(ctx context.Context, req models.LoginRequest) (*models.LoginResponse, error)
 	ParseToken(tokenStr string) (*models.UserProfile, error)
-	RefreshToken(ctx context.Context, userID string) (string, error)
+	RefreshToken(ctx context.Context, refreshToken string) (*models.LoginResponse, error)
+	RevokeRefreshToken(ctx context.Context, userID, refreshToken string) error
+	ChangePassword(ctx context.Context, userID string, req models.ChangePasswordRequest) (*models.LoginResponse, error)
+	DeleteAccount(ctx context.Context, userID string, req models.DeleteAccountRequest) error
 	GetUserByID(ctx context.Context, userID string) (*models.UserProfile, error)
+	IssueEmailVerification(ctx context.Context, userID string) (*models.EmailVerification, error)
+	VerifyEmail(ctx context.Context, token string) (*models.UserProfile, error)
+	RequireVerifiedEmail(ctx context.Context, userID string) error
+	SetVerificationTTL(ttl time.Duration)
 }
 
+// DefaultVerificationTTL is how long an email verification token stays valid
+const DefaultVerificationTTL = 24 * time.Hour
+
 type service struct {
-	db        *sql.DB
-	jwtSecret []byte
-	issuer    string
-	exp       time.Duration
+	db         *sql.DB
+	keys       *keyring
+	issuer     string
+	exp        time.Duration
+	refreshExp time.Duration
+	verifyTTL  time.Duration
 }
 
 type jwtClaims struct {
@@ -41,18 +60,40 @@ type jwtClaims struct {
 	jwt.RegisteredClaims
 }
 
-func NewService(db *sql.DB, secret, issuer string, exp time.Duration) Service {
+// NewService creates an auth service that signs HS256 tokens with a single
+// secret, named DefaultKeyID
+func NewService(db *sql.DB, secret, issuer string, exp, refreshExp time.Duration) Service {
+	svc, _ := NewServiceWithKeys(db, KeySet{Current: SigningKey{ID: DefaultKeyID, Secret: secret}}, issuer, exp, refreshExp)
+	return svc
+}
+
+// NewServiceWithKeys creates an auth service that signs with keys.Current and
+// accepts tokens from any key in the set
+func NewServiceWithKeys(db *sql.DB, keys KeySet, issuer string, exp, refreshExp time.Duration) (Service, error) {
+	kr, err := newKeyring(keys)
+	if err != nil {
+		return nil, err
+	}
 	return &service{
-		db:        db,
-		jwtSecret: []byte(secret),
-		issuer:    issuer,
-		exp:       exp,
+		db:         db,
+		keys:       kr,
+		issuer:     issuer,
+		exp:        exp,
+		refreshExp: refreshExp,
+		verifyTTL:  DefaultVerificationTTL,
+	}, nil
+}
+
+// SetVerificationTTL sets how long new email verification tokens stay valid
+func (s *service) SetVerificationTTL(ttl time.Duration) {
+	if ttl > 0 {
+		s.verifyTTL = ttl
 	}
 }
 
 func (s *service) Register(ctx context.Context, req models.RegisterRequest) (*models.UserProfile, error) {
 	if err := utils.ValidateStruct(req); err != nil {
-		return nil, models.NewAppError(models.ErrCodeValidation, "invalid registration data", 400, err)
+		return nil, apperrors.Invalid("invalid registration data", err)
 	}
 
 	var exists int
@@ -61,27 +102,27 @@ func (s *service) Register(ctx context.Context, req models.RegisterRequest) (*mo
 		req.Username, req.Email,
 	).Scan(&exists)
 	if err != nil {
-		return nil, models.NewAppError(models.ErrCodeInternal, "failed checking user uniqueness", 500, err)
+		return nil, apperrors.Internal("failed checking user uniqueness", err)
 	}
 	if exists > 0 {
-		return nil, models.NewAppError(models.ErrCodeConflict, "username or email already exists", 409, models.ErrUsernameExists)
+		return nil, apperrors.Conflict("username or email already exists", models.ErrUsernameExists)
 	}
 
 	hash, err := utils.HashPassword(req.Password)
 	if err != nil {
-		return nil, models.NewAppError(models.ErrCodeInternal, "failed to hash password", 500, err)
+		return nil, apperrors.Internal("failed to hash password", err)
 	}
 
 	now := time.Now()
 	userID := uuid.New().String()
 
 	_, err = s.db.ExecContext(ctx, `
-		INSERT INTO users (id, username, email, password_hash, display_name, role, is_active, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, 'user', 1, ?, ?)`,
+		INSERT INTO users (id, username, email, password_hash, display_name, role, is_active, email_verified, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, 'user', 1, 0, ?, ?)`,
 		userID, req.Username, req.Email, hash, req.Username, now, now,
 	)
 	if err != nil {
-		return nil, models.NewAppError(models.ErrCodeInternal, "failed to create user", 500, err)
+		return nil, apperrors.Internal("failed to create user", err)
 	}
 
 	profile := &models.UserProfile{
@@ -89,6 +130,7 @@ func (s *service) Register(ctx context.Context, req models.RegisterRequest) (*mo
 		Username:    req.Username,
 		DisplayName: req.Username,
 		AvatarURL:   "",
+		Role:        models.UserRoleUser,
 		CreatedAt:   now,
 	}
 
@@ -97,7 +139,7 @@ func (s *service) Register(ctx context.Context, req models.RegisterRequest) (*mo
 
 func (s *service) Login(ctx context.Context, req models.LoginRequest) (*models.LoginResponse, error) {
 	if err := utils.ValidateStruct(req); err != nil {
-		return nil, models.NewAppError(models.ErrCodeValidation, "invalid login data", 400, err)
+		return nil, apperrors.Invalid("invalid login data", err)
 	}
 
 	var (
@@ -107,117 +149,496 @@ func (s *service) Login(ctx context.Context, req models.LoginRequest) (*models.L
 		hash         string
 		displayName  string
 		role         string
+		verified     bool
 		createdAt    time.Time
 		lastLoginPtr *time.Time
 	)
 
+	// Unverified accounts can log in, so they are able to verify
 	err := s.db.QueryRowContext(ctx, `
-		SELECT id, username, email, password_hash, display_name, role, created_at, last_login_at
+		SELECT id, username, email, password_hash, display_name, role, email_verified, created_at, last_login_at
 		FROM users
 		WHERE username = ? OR email = ?`,
 		req.Username, req.Username,
-	).Scan(&id, &username, &email, &hash, &displayName, &role, &createdAt, &lastLoginPtr)
+	).Scan(&id, &username, &email, &hash, &displayName, &role, &verified, &createdAt, &lastLoginPtr)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, models.NewAppError(models.ErrCodeUnauthorized, "invalid credentials", 401, models.ErrInvalidCredentials)
+			return nil, apperrors.Unauthorized("invalid credentials", models.ErrInvalidCredentials)
 		}
-		return nil, models.NewAppError(models.ErrCodeInternal, "failed to query user", 500, err)
+		return nil, apperrors.Internal("failed to query user", err)
 	}
 
 	if !utils.CheckPassword(req.Password, hash) {
-		return nil, models.NewAppError(models.ErrCodeUnauthorized, "invalid credentials", 401, models.ErrInvalidCredentials)
+		return nil, apperrors.Unauthorized("invalid credentials", models.ErrInvalidCredentials)
 	}
 
 	now := time.Now()
-	expiresAt := now.Add(s.exp)
-
-	claims := jwtClaims{
-		UserID:   id,
-		Username: username,
-		Role:     role,
-		RegisteredClaims: jwt.RegisteredClaims{
-			Subject:   id,
-			Issuer:    s.issuer,
-			ExpiresAt: jwt.NewNumericDate(expiresAt),
-			IssuedAt:  jwt.NewNumericDate(now),
-		},
+	tokenStr, expiresAt, err := s.signAccessToken(id, username, role, now)
+	if err != nil {
+		return nil, err
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenStr, err := token.SignedString(s.jwtSecret)
+	refreshStr, refreshExpiresAt, err := s.issueRefreshToken(ctx, s.db, id, now)
 	if err != nil {
-		return nil, models.NewAppError(models.ErrCodeInternal, "failed to sign token", 500, err)
+		return nil, err
 	}
 
 	_, _ = s.db.ExecContext(ctx, "UPDATE users SET last_login_at = ?, updated_at = ? WHERE id = ?", now, now, id)
 
 	profile := models.UserProfile{
-		ID:          id,
-		Username:    username,
-		DisplayName: displayName,
-		AvatarURL:   "",
-		CreatedAt:   createdAt,
-		LastLoginAt: lastLoginPtr,
+		ID:            id,
+		Username:      username,
+		DisplayName:   displayName,
+		AvatarURL:     "",
+		Role:          role,
+		CreatedAt:     createdAt,
+		LastLoginAt:   lastLoginPtr,
+		EmailVerified: verified,
 	}
 
 	return &models.LoginResponse{
-		Token:     tokenStr,
-		ExpiresAt: expiresAt,
-		User:      profile,
+		Token:            tokenStr,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshStr,
+		RefreshExpiresAt: refreshExpiresAt,
+		User:             profile,
 	}, nil
 }
 
 func (s *service) ParseToken(tokenStr string) (*models.UserProfile, error) {
-	token, err := jwt.ParseWithClaims(tokenStr, &jwtClaims{}, func(t *jwt.Token) (interface{}, error) {
-		return s.jwtSecret, nil
-	})
+	token, err := jwt.ParseWithClaims(tokenStr, &jwtClaims{}, s.keys.verificationKey)
 	if err != nil || !token.Valid {
-		return nil, models.NewAppError(models.ErrCodeUnauthorized, "invalid token", 401, models.ErrInvalidToken)
+		return nil, apperrors.Unauthorized("invalid token", models.ErrInvalidToken)
 	}
 
 	claims, ok := token.Claims.(*jwtClaims)
 	if !ok {
-		return nil, models.NewAppError(models.ErrCodeUnauthorized, "invalid token claims", 401, models.ErrInvalidToken)
+		return nil, apperrors.Unauthorized("invalid token claims", models.ErrInvalidToken)
 	}
 
 	return &models.UserProfile{
 		ID:       claims.UserID,
 		Username: claims.Username,
-		// role can be added if you include it in UserProfile later
+		Role:     claims.Role,
 	}, nil
 }
 
-// RefreshToken generates a new JWT token for an existing user
-func (s *service) RefreshToken(ctx context.Context, userID string) (string, error) {
-	// Get user from DB to ensure they still exist and are active
+// RefreshToken rotates a refresh token: the presented token is revoked and a
+// new access/refresh pair is issued. Presenting an already revoked token is
+// treated as theft, so every active refresh token of that user is revoked.
+func (s *service) RefreshToken(ctx context.Context, refreshToken string) (*models.LoginResponse, error) {
+	if refreshToken == "" {
+		return nil, apperrors.Unauthorized("refresh token required", models.ErrInvalidToken)
+	}
+
+	var (
+		tokenID   string
+		userID    string
+		expiresAt time.Time
+		revokedAt *time.Time
+	)
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, user_id, expires_at, revoked_at
+		FROM refresh_tokens
+		WHERE token_hash = ?`,
+		hashToken(refreshToken),
+	).Scan(&tokenID, &userID, &expiresAt, &revokedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.Unauthorized("invalid refresh token", models.ErrInvalidToken)
+		}
+		return nil, apperrors.Internal("failed to query refresh token", err)
+	}
+
+	now := time.Now()
+
+	// Reuse detection: a rotated token should never be presented again
+	if revokedAt != nil {
+		return nil, s.revokeOnReuse(ctx, userID, now)
+	}
+
+	if now.After(expiresAt) {
+		return nil, apperrors.Unauthorized("refresh token expired", models.ErrInvalidToken)
+	}
+
+	// Ensure the user still exists and is active
 	user, err := s.GetUserByID(ctx, userID)
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, apperrors.Internal("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	newRefresh, refreshExpiresAt, err := s.issueRefreshToken(ctx, tx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	// Revoke the old token only if nobody rotated it concurrently
+	res, err := tx.ExecContext(ctx, `
+		UPDATE refresh_tokens
+		SET revoked_at = ?, replaced_by = (SELECT id FROM refresh_tokens WHERE token_hash = ?)
+		WHERE id = ? AND revoked_at IS NULL`,
+		now, hashToken(newRefresh), tokenID,
+	)
+	if err != nil {
+		return nil, apperrors.Internal("failed to revoke refresh token", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		// The same token was presented twice at once and the other request
+		// rotated it first: that is reuse too
+		tx.Rollback()
+		return nil, s.revokeOnReuse(ctx, userID, now)
+	}
+
+	tokenStr, expiresAt, err := s.signAccessToken(user.ID, user.Username, user.Role, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, apperrors.Internal("failed to commit token rotation", err)
+	}
+
+	return &models.LoginResponse{
+		Token:            tokenStr,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     newRefresh,
+		RefreshExpiresAt: refreshExpiresAt,
+		User:             *user,
+	}, nil
+}
+
+// revokeOnReuse revokes every active refresh token of the user after a
+// rotated token was presented again, and returns the reuse error
+func (s *service) revokeOnReuse(ctx context.Context, userID string, now time.Time) error {
+	if _, err := s.db.ExecContext(ctx,
+		"UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL",
+		now, userID,
+	); err != nil {
+		return apperrors.Internal("failed to revoke refresh tokens", err)
+	}
+	return apperrors.Unauthorized("refresh token reuse detected", models.ErrTokenReused)
+}
+
+// RevokeRefreshToken revokes the given refresh token of a user.
+// An empty token revokes every active refresh token of the user.
+func (s *service) RevokeRefreshToken(ctx context.Context, userID, refreshToken string) error {
+	now := time.Now()
+
+	var err error
+	if refreshToken == "" {
+		_, err = s.db.ExecContext(ctx,
+			"UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL",
+			now, userID,
+		)
+	} else {
+		_, err = s.db.ExecContext(ctx,
+			"UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND token_hash = ? AND revoked_at IS NULL",
+			now, userID, hashToken(refreshToken),
+		)
+	}
+	if err != nil {
+		return apperrors.Internal("failed to revoke refresh token", err)
+	}
+	return nil
+}
+
+// ChangePassword verifies the current password and replaces it with a new one.
+// Every refresh token of the user is revoked, and a fresh pair is returned so
+// the calling session keeps working while other sessions are logged out.
+func (s *service) ChangePassword(ctx context.Context, userID string, req models.ChangePasswordRequest) (*models.LoginResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, apperrors.Invalid("invalid password data", err)
+	}
+
+	var (
+		username    string
+		displayName string
+		role        string
+		hash        string
+		verified    bool
+		createdAt   time.Time
+		lastLogin   *time.Time
+	)
+
+	err := s.db.QueryRowContext(ctx, `
+		SELECT username, display_name, role, password_hash, email_verified, created_at, last_login_at
+		FROM users
+		WHERE id = ? AND is_active = 1`,
+		userID,
+	).Scan(&username, &displayName, &role, &hash, &verified, &createdAt, &lastLogin)
+	if err != nil {
+		// Same answer as a wrong password, so the response says nothing about the account
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.Unauthorized("invalid credentials", models.ErrInvalidCredentials)
+		}
+		return nil, apperrors.Internal("failed to query user", err)
+	}
+
+	if !utils.CheckPassword(req.CurrentPassword, hash) {
+		return nil, apperrors.Unauthorized("invalid credentials", models.ErrInvalidCredentials)
+	}
+	if req.NewPassword == req.CurrentPassword {
+		return nil, apperrors.Validation("new_password", "new password must differ from the current password")
+	}
+	if err := utils.CheckPasswordStrength(req.NewPassword); err != nil {
+		return nil, apperrors.Validation("new_password", err.Error())
+	}
+
+	newHash, err := utils.HashPassword(req.NewPassword)
+	if err != nil {
+		return nil, apperrors.Internal("failed to hash password", err)
+	}
+
+	now := time.Now()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, apperrors.Internal("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE users SET password_hash = ?, updated_at = ? WHERE id = ?",
+		newHash, now, userID,
+	); err != nil {
+		return nil, apperrors.Internal("failed to update password", err)
+	}
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE refresh_tokens SET revoked_at = ? WHERE user_id = ? AND revoked_at IS NULL",
+		now, userID,
+	); err != nil {
+		return nil, apperrors.Internal("failed to revoke refresh tokens", err)
+	}
+
+	refreshStr, refreshExpiresAt, err := s.issueRefreshToken(ctx, tx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	tokenStr, expiresAt, err := s.signAccessToken(userID, username, role, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, apperrors.Internal("failed to commit password change", err)
+	}
+
+	return &models.LoginResponse{
+		Token:            tokenStr,
+		ExpiresAt:        expiresAt,
+		RefreshToken:     refreshStr,
+		RefreshExpiresAt: refreshExpiresAt,
+		User: models.UserProfile{
+			ID:            userID,
+			Username:      username,
+			DisplayName:   displayName,
+			Role:          role,
+			CreatedAt:     createdAt,
+			LastLoginAt:   lastLogin,
+			EmailVerified: verified,
+		},
+	}, nil
+}
+
+// accountPurge deletes what a user wrote that must not outlive the account.
+// The foreign keys cascade most of it from users as well, but only while
+// foreign_keys is on for the connection; these make the purge independent of
+// it. Comments are removed, not anonymized: live and soft-deleted ones alike,
+// since a soft-deleted placeholder still ties the thread to the user. Replies
+// from other users stay and become top-level (parent_id ON DELETE SET NULL).
+var accountPurge = []struct{ table, query string }{
+	{"chat messages", "DELETE FROM chat_messages WHERE user_id = ?"},
+	{"comments", "DELETE FROM comments WHERE user_id = ?"},
+	{"activity", "DELETE FROM activity_feed WHERE user_id = ?"},
+	{"chapter history", "DELETE FROM chapter_history WHERE user_id = ?"},
+	{"daily stats", "DELETE FROM daily_stats WHERE user_id = ?"},
+	{"preferences", "DELETE FROM user_preferences WHERE user_id = ?"},
+}
+
+// DeleteAccount verifies the password and removes the user with everything
+// they own in one transaction; nothing is deleted unless all of it is.
+// Chat rooms the user owns pass to their longest-standing moderator, else
+// member, and are deleted only when nobody else is in them. Audit log rows
+// are kept: the account.delete entry added here and earlier moderation
+// entries name the user by ID only. Access tokens already issued stay valid
+// until they expire; every refresh token goes with the user.
+func (s *service) DeleteAccount(ctx context.Context, userID string, req models.DeleteAccountRequest) error {
+	if err := utils.ValidateStruct(req); err != nil {
+		return apperrors.Invalid("invalid account deletion data", err)
+	}
+
+	var hash string
+	err := s.db.QueryRowContext(ctx,
+		"SELECT password_hash FROM users WHERE id = ? AND is_active = 1", userID,
+	).Scan(&hash)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.Unauthorized("invalid credentials", models.ErrInvalidCredentials)
+		}
+		return apperrors.Internal("failed to query user", err)
+	}
+	if !utils.CheckPassword(req.Password, hash) {
+		return apperrors.Unauthorized("invalid credentials", models.ErrInvalidCredentials)
 	}
 
 	now := time.Now()
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return apperrors.Internal("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	if err := handOverChatRooms(ctx, tx, userID, now); err != nil {
+		return apperrors.Internal("failed to hand over chat rooms", err)
+	}
+	for _, purge := range accountPurge {
+		if _, err := tx.ExecContext(ctx, purge.query, userID); err != nil {
+			return apperrors.Internal("failed to delete "+purge.table, err)
+		}
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO audit_log (id, actor_id, action, target_type, target_id, created_at)
+		VALUES (?, ?, 'account.delete', 'user', ?, ?)`,
+		uuid.New().String(), userID, userID, now,
+	); err != nil {
+		return apperrors.Internal("failed to write audit log", err)
+	}
+
+	result, err := tx.ExecContext(ctx, "DELETE FROM users WHERE id = ?", userID)
+	if err != nil {
+		return apperrors.Internal("failed to delete user", err)
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return apperrors.Unauthorized("invalid credentials", models.ErrInvalidCredentials)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return apperrors.Internal("failed to commit account deletion", err)
+	}
+	return nil
+}
+
+// handOverChatRooms gives each room the user owns to its longest-standing
+// moderator, else member, and deletes the rooms nobody else is in; the owner_id
+// cascade would otherwise take every member's messages with the room
+func handOverChatRooms(ctx context.Context, tx *sql.Tx, userID string, now time.Time) error {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT r.id, (
+			SELECT m.user_id FROM chat_room_members m
+			WHERE m.room_id = r.id AND m.user_id != r.owner_id
+			ORDER BY CASE m.role WHEN 'moderator' THEN 0 ELSE 1 END, m.joined_at
+			LIMIT 1)
+		FROM chat_rooms r
+		WHERE r.owner_id = ?`, userID)
+	if err != nil {
+		return err
+	}
+	successors := make(map[string]string)
+	for rows.Next() {
+		var roomID string
+		var successor sql.NullString
+		if err := rows.Scan(&roomID, &successor); err != nil {
+			rows.Close()
+			return err
+		}
+		if successor.Valid {
+			successors[roomID] = successor.String
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for roomID, successor := range successors {
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE chat_rooms SET owner_id = ?, updated_at = ? WHERE id = ?",
+			successor, now, roomID,
+		); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE chat_room_members SET role = 'owner' WHERE room_id = ? AND user_id = ?",
+			roomID, successor,
+		); err != nil {
+			return err
+		}
+	}
+
+	_, err = tx.ExecContext(ctx, "DELETE FROM chat_rooms WHERE owner_id = ?", userID)
+	return err
+}
+
+// execer is satisfied by both *sql.DB and *sql.Tx
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// signAccessToken creates a short-lived JWT access token signed with the current key
+func (s *service) signAccessToken(userID, username, role string, now time.Time) (string, time.Time, error) {
 	expiresAt := now.Add(s.exp)
 
 	claims := jwtClaims{
-		UserID:   user.ID,
-		Username: user.Username,
-		Role:     "user", // Default role
+		UserID:   userID,
+		Username: username,
+		Role:     role,
 		RegisteredClaims: jwt.RegisteredClaims{
-			Subject:   user.ID,
+			Subject:   userID,
 			Issuer:    s.issuer,
 			ExpiresAt: jwt.NewNumericDate(expiresAt),
 			IssuedAt:  jwt.NewNumericDate(now),
 		},
 	}
 
-	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
-	tokenStr, err := token.SignedString(s.jwtSecret)
+	tokenStr, err := s.keys.sign(claims)
+	if err != nil {
+		return "", time.Time{}, apperrors.Internal("failed to sign token", err)
+	}
+	return tokenStr, expiresAt, nil
+}
+
+// issueRefreshToken generates an opaque refresh token and stores its hash
+func (s *service) issueRefreshToken(ctx context.Context, db execer, userID string, now time.Time) (string, time.Time, error) {
+	token, err := randomToken()
 	if err != nil {
-		return "", models.NewAppError(models.ErrCodeInternal, "failed to sign token", 500, err)
+		return "", time.Time{}, apperrors.Internal("failed to generate refresh token", err)
 	}
+	expiresAt := now.Add(s.refreshExp)
 
-	return tokenStr, nil
+	_, err = db.ExecContext(ctx, `
+		INSERT INTO refresh_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		uuid.New().String(), userID, hashToken(token), expiresAt, now,
+	)
+	if err != nil {
+		return "", time.Time{}, apperrors.Internal("failed to store refresh token", err)
+	}
+	return token, expiresAt, nil
+}
+
+// randomToken returns 32 random bytes, hex encoded
+func randomToken() (string, error) {
+	raw := make([]byte, 32)
+	if _, err := rand.Read(raw); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(raw), nil
+}
+
+// hashToken returns the SHA-256 hex digest stored instead of the raw token
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
 }
 
 // GetUserByID retrieves a user profile by their ID
@@ -226,30 +647,161 @@ func (s *service) GetUserByID(ctx context.Context, userID string) (*models.UserP
 		id          string
 		username    string
 		displayName string
+		role        string
+		verified    bool
 		createdAt   time.Time
 		lastLogin   *time.Time
 	)
 
 	err := s.db.QueryRowContext(ctx, `
-		SELECT id, username, display_name, created_at, last_login_at
+		SELECT id, username, display_name, role, email_verified, created_at, last_login_at
 		FROM users
 		WHERE id = ? AND is_active = 1`,
 		userID,
-	).Scan(&id, &username, &displayName, &createdAt, &lastLogin)
+	).Scan(&id, &username, &displayName, &role, &verified, &createdAt, &lastLogin)
 
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, models.NewAppError(models.ErrCodeNotFound, "user not found", 404, nil)
+			return nil, apperrors.NotFound("user not found", nil)
 		}
-		return nil, models.NewAppError(models.ErrCodeInternal, "failed to query user", 500, err)
+		return nil, apperrors.Internal("failed to query user", err)
 	}
 
 	return &models.UserProfile{
-		ID:          id,
-		Username:    username,
-		DisplayName: displayName,
-		AvatarURL:   "", // Avatar URL can be generated from external service (Gravatar, etc.)
-		CreatedAt:   createdAt,
-		LastLoginAt: lastLogin,
+		ID:            id,
+		Username:      username,
+		DisplayName:   displayName,
+		AvatarURL:     "", // Avatar URL can be generated from external service (Gravatar, etc.)
+		Role:          role,
+		CreatedAt:     createdAt,
+		LastLoginAt:   lastLogin,
+		EmailVerified: verified,
 	}, nil
 }
+
+// IssueEmailVerification creates a verification token for an unverified user.
+// Older unused tokens of the user stop working.
+func (s *service) IssueEmailVerification(ctx context.Context, userID string) (*models.EmailVerification, error) {
+	user, err := s.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.EmailVerified {
+		return nil, apperrors.Conflict("email address is already verified", nil)
+	}
+
+	token, err := randomToken()
+	if err != nil {
+		return nil, apperrors.Internal("failed to generate verification token", err)
+	}
+	now := time.Now()
+	expiresAt := now.Add(s.verifyTTL)
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, apperrors.Internal("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE email_verification_tokens SET used_at = ? WHERE user_id = ? AND used_at IS NULL",
+		now, userID,
+	); err != nil {
+		return nil, apperrors.Internal("failed to revoke verification tokens", err)
+	}
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO email_verification_tokens (id, user_id, token_hash, expires_at, created_at)
+		VALUES (?, ?, ?, ?, ?)`,
+		uuid.New().String(), userID, hashToken(token), expiresAt, now,
+	); err != nil {
+		return nil, apperrors.Internal("failed to store verification token", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, apperrors.Internal("failed to commit verification token", err)
+	}
+
+	return &models.EmailVerification{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// VerifyEmail marks the token's user as verified. Each token works once and
+// only until it expires.
+func (s *service) VerifyEmail(ctx context.Context, token string) (*models.UserProfile, error) {
+	if token == "" {
+		return nil, apperrors.Validation("token", "verification token is required")
+	}
+
+	var (
+		tokenID   string
+		userID    string
+		expiresAt time.Time
+		usedAt    *time.Time
+	)
+	err := s.db.QueryRowContext(ctx, `
+		SELECT id, user_id, expires_at, used_at
+		FROM email_verification_tokens
+		WHERE token_hash = ?`,
+		hashToken(token),
+	).Scan(&tokenID, &userID, &expiresAt, &usedAt)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, apperrors.Validation("token", "invalid verification token")
+		}
+		return nil, apperrors.Internal("failed to query verification token", err)
+	}
+
+	now := time.Now()
+	if usedAt != nil {
+		return nil, apperrors.Validation("token", "verification token has already been used or replaced")
+	}
+	if now.After(expiresAt) {
+		return nil, apperrors.Validation("token", "verification token has expired; request a new one")
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, apperrors.Internal("failed to begin transaction", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx,
+		"UPDATE email_verification_tokens SET used_at = ? WHERE id = ? AND used_at IS NULL",
+		now, tokenID,
+	)
+	if err != nil {
+		return nil, apperrors.Internal("failed to use verification token", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return nil, apperrors.Validation("token", "verification token has already been used or replaced")
+	}
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE users SET email_verified = 1, updated_at = ? WHERE id = ?",
+		now, userID,
+	); err != nil {
+		return nil, apperrors.Internal("failed to verify email", err)
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, apperrors.Internal("failed to commit email verification", err)
+	}
+
+	return s.GetUserByID(ctx, userID)
+}
+
+// RequireVerifiedEmail returns an EMAIL_NOT_VERIFIED error unless the user
+// has verified their email address
+func (s *service) RequireVerifiedEmail(ctx context.Context, userID string) error {
+	var verified bool
+	err := s.db.QueryRowContext(ctx,
+		"SELECT email_verified FROM users WHERE id = ? AND is_active = 1", userID,
+	).Scan(&verified)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return apperrors.NotFound("user not found", nil)
+		}
+		return apperrors.Internal("failed to query user", err)
+	}
+	if !verified {
+		return apperrors.EmailNotVerified(
+			"verify your email address before commenting or rating; request a new link with POST /auth/verify/resend")
+	}
+	return nil
+}
@@ -0,0 +1,416 @@
+// Package auth - Authentication Service Tests
+// Unit tests cho refresh token rotation, reuse detection, đổi mật khẩu và xóa tài khoản
+package auth
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v4"
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+func loginTestUser(t *testing.T, svc Service) *models.LoginResponse {
+	ctx := context.Background()
+	if _, err := svc.Register(ctx, models.RegisterRequest{
+		Username: "reader",
+		Email:    "reader@example.com",
+		Password: "password123",
+	}); err != nil {
+		t.Fatalf("register failed: %v", err)
+	}
+
+	resp, err := svc.Login(ctx, models.LoginRequest{Username: "reader", Password: "password123"})
+	if err != nil {
+		t.Fatalf("login failed: %v", err)
+	}
+	if resp.RefreshToken == "" {
+		t.Fatal("expected login to issue a refresh token")
+	}
+	return resp
+}
+
+func TestRefreshTokenRotation(t *testing.T) {
+	db := testutil.OpenDB(t)
+	svc := NewService(db, "test-secret", "test", time.Hour, 24*time.Hour)
+	ctx := context.Background()
+
+	login := loginTestUser(t, svc)
+
+	rotated, err := svc.RefreshToken(ctx, login.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+	if rotated.RefreshToken == login.RefreshToken {
+		t.Error("expected a new refresh token after rotation")
+	}
+	if _, err := svc.ParseToken(rotated.Token); err != nil {
+		t.Errorf("rotated access token should be valid: %v", err)
+	}
+
+	// The rotated token keeps working
+	if _, err := svc.RefreshToken(ctx, rotated.RefreshToken); err != nil {
+		t.Errorf("expected rotated refresh token to be usable: %v", err)
+	}
+}
+
+func TestRefreshTokenReuseRevokesChain(t *testing.T) {
+	db := testutil.OpenDB(t)
+	svc := NewService(db, "test-secret", "test", time.Hour, 24*time.Hour)
+	ctx := context.Background()
+
+	login := loginTestUser(t, svc)
+
+	rotated, err := svc.RefreshToken(ctx, login.RefreshToken)
+	if err != nil {
+		t.Fatalf("refresh failed: %v", err)
+	}
+
+	// Presenting the already-rotated token again is a reuse
+	_, err = svc.RefreshToken(ctx, login.RefreshToken)
+	appErr, ok := err.(*models.AppError)
+	if !ok || appErr.StatusCode != 401 {
+		t.Fatalf("expected 401 on reuse, got %v", err)
+	}
+
+	// The legitimate successor must be revoked too
+	if _, err := svc.RefreshToken(ctx, rotated.RefreshToken); err == nil {
+		t.Error("expected the whole token chain to be revoked after reuse")
+	}
+
+	var active int
+	if err := db.QueryRow("SELECT COUNT(*) FROM refresh_tokens WHERE revoked_at IS NULL").Scan(&active); err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	if active != 0 {
+		t.Errorf("expected no active refresh tokens, got %d", active)
+	}
+}
+
+func TestRefreshTokenConcurrentReuseRevokesChain(t *testing.T) {
+	db := testutil.OpenDB(t)
+	svc := NewService(db, "test-secret", "test", time.Hour, 24*time.Hour)
+	ctx := context.Background()
+
+	login := loginTestUser(t, svc)
+
+	// Another request rotates the same token between our read and our
+	// conditional revoke: revoke it as soon as the successor is inserted
+	if _, err := db.Exec(`CREATE TRIGGER concurrent_rotation AFTER INSERT ON refresh_tokens
+		BEGIN
+			UPDATE refresh_tokens SET revoked_at = CURRENT_TIMESTAMP WHERE id != NEW.id AND revoked_at IS NULL;
+		END`); err != nil {
+		t.Fatalf("create trigger failed: %v", err)
+	}
+
+	_, err := svc.RefreshToken(ctx, login.RefreshToken)
+	if !errors.Is(err, models.ErrTokenReused) {
+		t.Fatalf("expected reuse error for a concurrent rotation, got %v", err)
+	}
+
+	var active int
+	if err := db.QueryRow("SELECT COUNT(*) FROM refresh_tokens WHERE revoked_at IS NULL").Scan(&active); err != nil {
+		t.Fatalf("count failed: %v", err)
+	}
+	if active != 0 {
+		t.Errorf("expected the token chain to be revoked, got %d active tokens", active)
+	}
+}
+
+func TestRevokeRefreshTokenOnLogout(t *testing.T) {
+	db := testutil.OpenDB(t)
+	svc := NewService(db, "test-secret", "test", time.Hour, 24*time.Hour)
+	ctx := context.Background()
+
+	login := loginTestUser(t, svc)
+
+	if err := svc.RevokeRefreshToken(ctx, login.User.ID, login.RefreshToken); err != nil {
+		t.Fatalf("revoke failed: %v", err)
+	}
+	if _, err := svc.RefreshToken(ctx, login.RefreshToken); err == nil {
+		t.Error("expected revoked refresh token to be rejected")
+	}
+}
+
+func TestAccessTokenKeyRotation(t *testing.T) {
+	newSvc := func(keys KeySet) *service {
+		t.Helper()
+		svc, err := NewServiceWithKeys(nil, keys, "test", time.Hour, 24*time.Hour)
+		if err != nil {
+			t.Fatalf("NewServiceWithKeys failed: %v", err)
+		}
+		return svc.(*service)
+	}
+	sign := func(svc *service) string {
+		t.Helper()
+		token, _, err := svc.signAccessToken("u1", "reader", "user", time.Now())
+		if err != nil {
+			t.Fatalf("sign failed: %v", err)
+		}
+		return token
+	}
+	oldKey := SigningKey{ID: "k1", Secret: "old-secret"}
+	before := newSvc(KeySet{Current: oldKey})
+	oldToken := sign(before)
+
+	// Rotated: signs with k2 (and a new algorithm), still accepts k1
+	rotated := newSvc(KeySet{Algorithm: "HS512", Current: SigningKey{ID: "k2", Secret: "new-secret"}, Previous: []SigningKey{oldKey}})
+	if _, err := rotated.ParseToken(oldToken); err != nil {
+		t.Errorf("token from the previous key should still be accepted: %v", err)
+	}
+	newToken := sign(rotated)
+	parsed, _ := jwt.Parse(newToken, rotated.keys.verificationKey)
+	if parsed == nil || parsed.Header["kid"] != "k2" || parsed.Header["alg"] != "HS512" {
+		t.Fatalf("expected a k2/HS512 token, got %v", parsed)
+	}
+	if _, err := before.ParseToken(newToken); err == nil {
+		t.Error("a service without k2 should reject its tokens")
+	}
+
+	// Without a recognized kid a token is rejected, even with a valid secret
+	claims := jwtClaims{UserID: "u1", RegisteredClaims: jwt.RegisteredClaims{ExpiresAt: jwt.NewNumericDate(time.Now().Add(time.Hour))}}
+	noKid, _ := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte("old-secret"))
+	if _, err := rotated.ParseToken(noKid); err == nil {
+		t.Error("expected a token without kid to be rejected")
+	}
+	forged := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
+	forged.Header["kid"] = "k1"
+	forgedStr, _ := forged.SignedString([]byte("not-the-secret"))
+	if _, err := rotated.ParseToken(forgedStr); err == nil {
+		t.Error("expected a token with k1 but the wrong secret to be rejected")
+	}
+
+	// Once k1 is retired its tokens stop working
+	retired := newSvc(KeySet{Current: SigningKey{ID: "k2", Secret: "new-secret"}})
+	if _, err := retired.ParseToken(oldToken); err == nil {
+		t.Error("expected a retired key's token to be rejected")
+	}
+
+	// The single-secret constructor signs as DefaultKeyID
+	legacy := NewService(nil, "old-secret", "test", time.Hour, 24*time.Hour).(*service)
+	fromLegacy := sign(legacy)
+	migrated := newSvc(KeySet{Current: SigningKey{ID: "k2", Secret: "new-secret"}, Previous: []SigningKey{{ID: DefaultKeyID, Secret: "old-secret"}}})
+	if _, err := migrated.ParseToken(fromLegacy); err != nil {
+		t.Errorf("token from the old single secret should be accepted as %q: %v", DefaultKeyID, err)
+	}
+
+	for _, bad := range []KeySet{
+		{Algorithm: "RS256", Current: oldKey},
+		{Current: SigningKey{Secret: "no-id"}},
+		{Current: oldKey, Previous: []SigningKey{oldKey}},
+	} {
+		if _, err := NewServiceWithKeys(nil, bad, "test", time.Hour, time.Hour); err == nil {
+			t.Errorf("expected %+v to be rejected", bad)
+		}
+	}
+}
+
+func TestChangePassword(t *testing.T) {
+	db := testutil.OpenDB(t)
+	svc := NewService(db, "test-secret", "test", time.Hour, 24*time.Hour)
+	ctx := context.Background()
+
+	login := loginTestUser(t, svc)
+	userID := login.User.ID
+
+	cases := []struct {
+		name   string
+		req    models.ChangePasswordRequest
+		status int
+	}{
+		{"wrong current", models.ChangePasswordRequest{CurrentPassword: "wrong-pass1", NewPassword: "newpassword1"}, 401},
+		{"same password", models.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "password123"}, 400},
+		{"no digit", models.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "onlyletters"}, 400},
+		{"too short", models.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "ab1"}, 400},
+	}
+	for _, tc := range cases {
+		_, err := svc.ChangePassword(ctx, userID, tc.req)
+		appErr, ok := err.(*models.AppError)
+		if !ok || appErr.StatusCode != tc.status {
+			t.Errorf("%s: expected status %d, got %v", tc.name, tc.status, err)
+		}
+	}
+
+	// Unknown users get the same 401 as a wrong password
+	_, err := svc.ChangePassword(ctx, "missing", models.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "newpassword1"})
+	if appErr, ok := err.(*models.AppError); !ok || appErr.StatusCode != 401 {
+		t.Errorf("expected 401 for unknown user, got %v", err)
+	}
+
+	resp, err := svc.ChangePassword(ctx, userID, models.ChangePasswordRequest{CurrentPassword: "password123", NewPassword: "newpassword1"})
+	if err != nil {
+		t.Fatalf("change password failed: %v", err)
+	}
+
+	// The returned pair keeps working, sessions from before the change are logged out
+	if _, err := svc.RefreshToken(ctx, resp.RefreshToken); err != nil {
+		t.Errorf("expected new refresh token to be valid: %v", err)
+	}
+	if _, err := svc.RefreshToken(ctx, login.RefreshToken); err == nil {
+		t.Error("expected refresh tokens issued before the change to be revoked")
+	}
+
+	if _, err := svc.Login(ctx, models.LoginRequest{Username: "reader", Password: "password123"}); err == nil {
+		t.Error("expected old password to be rejected")
+	}
+	if _, err := svc.Login(ctx, models.LoginRequest{Username: "reader", Password: "newpassword1"}); err != nil {
+		t.Errorf("expected login with new password: %v", err)
+	}
+}
+
+func TestDeleteAccountPurgesData(t *testing.T) {
+	// Foreign keys on, as the server's DSN has them
+	db := testutil.OpenDB(t)
+	if _, err := db.Exec("PRAGMA foreign_keys = ON"); err != nil {
+		t.Fatalf("failed to enable foreign keys: %v", err)
+	}
+
+	svc := NewService(db, "test-secret", "test", time.Hour, 24*time.Hour)
+	ctx := context.Background()
+	userID := loginTestUser(t, svc).User.ID
+	friend, err := svc.Register(ctx, models.RegisterRequest{Username: "friend", Email: "friend@example.com", Password: "password123"})
+	if err != nil {
+		t.Fatalf("register friend failed: %v", err)
+	}
+
+	seed := []string{
+		`INSERT INTO manga (id, title) VALUES ('m1', 'Berserk')`,
+		`INSERT INTO reading_progress (id, user_id, manga_id) VALUES ('p1', '` + userID + `', 'm1')`,
+		`INSERT INTO manga_ratings (id, manga_id, user_id, rating) VALUES ('r1', 'm1', '` + userID + `', 9)`,
+		`INSERT INTO chapter_history (id, user_id, manga_id, chapter_number) VALUES ('h1', '` + userID + `', 'm1', 1)`,
+		`INSERT INTO daily_stats (user_id, stat_date, chapters_read) VALUES ('` + userID + `', '2026-10-01', 1)`,
+		`INSERT INTO user_preferences (user_id) VALUES ('` + userID + `')`,
+		`INSERT INTO comments (id, manga_id, user_id, content) VALUES ('c1', 'm1', '` + userID + `', 'first')`,
+		`INSERT INTO comments (id, manga_id, user_id, content, is_deleted) VALUES ('c2', 'm1', '` + userID + `', '[deleted]', 1)`,
+		`INSERT INTO comments (id, manga_id, user_id, content, parent_id) VALUES ('c3', 'm1', '` + friend.ID + `', 'reply', 'c1')`,
+		`INSERT INTO chat_rooms (id, name, owner_id) VALUES ('shared', 'Shared', '` + userID + `')`,
+		`INSERT INTO chat_rooms (id, name, owner_id) VALUES ('solo', 'Solo', '` + userID + `')`,
+		`INSERT INTO chat_room_members (id, room_id, user_id, role) VALUES ('rm1', 'shared', '` + userID + `', 'owner')`,
+		`INSERT INTO chat_room_members (id, room_id, user_id) VALUES ('rm2', 'shared', '` + friend.ID + `')`,
+		`INSERT INTO chat_messages (id, room_id, user_id, content) VALUES ('msg1', 'shared', '` + userID + `', 'hi')`,
+		`INSERT INTO chat_messages (id, room_id, user_id, content) VALUES ('msg2', 'shared', '` + friend.ID + `', 'hello')`,
+	}
+	for _, q := range seed {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("seed %q: %v", q, err)
+		}
+	}
+
+	count := func(query string, args ...interface{}) int {
+		t.Helper()
+		var n int
+		if err := db.QueryRow(query, args...).Scan(&n); err != nil {
+			t.Fatalf("%s: %v", query, err)
+		}
+		return n
+	}
+
+	// A wrong password deletes nothing
+	err = svc.DeleteAccount(ctx, userID, models.DeleteAccountRequest{Password: "wrong-pass1"})
+	if appErr, ok := err.(*models.AppError); !ok || appErr.StatusCode != 401 {
+		t.Fatalf("expected 401 for a wrong password, got %v", err)
+	}
+	if count("SELECT COUNT(*) FROM comments WHERE user_id = ?", userID) != 2 {
+		t.Fatal("expected a rejected deletion to leave the data alone")
+	}
+
+	if err := svc.DeleteAccount(ctx, userID, models.DeleteAccountRequest{Password: "password123"}); err != nil {
+		t.Fatalf("delete account failed: %v", err)
+	}
+
+	for _, table := range []string{"users WHERE id", "refresh_tokens WHERE user_id", "reading_progress WHERE user_id",
+		"manga_ratings WHERE user_id", "chapter_history WHERE user_id", "daily_stats WHERE user_id",
+		"user_preferences WHERE user_id", "comments WHERE user_id", "chat_messages WHERE user_id",
+		"chat_room_members WHERE user_id", "activity_feed WHERE user_id"} {
+		if n := count("SELECT COUNT(*) FROM "+table+" = ?", userID); n != 0 {
+			t.Errorf("expected no rows left in %s, got %d", table, n)
+		}
+	}
+
+	// Other people's content stays: the reply loses its parent, the shared
+	// room passes to the remaining member with their messages
+	if count("SELECT COUNT(*) FROM comments WHERE id = 'c3' AND parent_id IS NULL") != 1 {
+		t.Error("expected the friend's reply to survive as a top-level comment")
+	}
+	if count("SELECT COUNT(*) FROM chat_rooms WHERE id = 'shared' AND owner_id = ?", friend.ID) != 1 {
+		t.Error("expected the shared room to pass to the friend")
+	}
+	if count("SELECT COUNT(*) FROM chat_room_members WHERE room_id = 'shared' AND user_id = ? AND role = 'owner'", friend.ID) != 1 {
+		t.Error("expected the friend to become the room's owner member")
+	}
+	if count("SELECT COUNT(*) FROM chat_messages WHERE id = 'msg2'") != 1 {
+		t.Error("expected the friend's message to survive")
+	}
+	if count("SELECT COUNT(*) FROM chat_rooms WHERE id = 'solo'") != 0 {
+		t.Error("expected the room nobody else was in to be deleted")
+	}
+	if count("SELECT rating_count FROM manga WHERE id = 'm1'") != 0 {
+		t.Error("expected the deleted rating to drop out of the manga's count")
+	}
+	if count("SELECT COUNT(*) FROM audit_log WHERE action = 'account.delete' AND target_id = ?", userID) != 1 {
+		t.Error("expected the deletion to be audited")
+	}
+
+	if _, err := svc.Login(ctx, models.LoginRequest{Username: "reader", Password: "password123"}); err == nil {
+		t.Error("expected login to fail after deletion")
+	}
+}
+
+func TestEmailVerification(t *testing.T) {
+	db := testutil.OpenDB(t)
+	svc := NewService(db, "test-secret", "test", time.Hour, 24*time.Hour)
+	ctx := context.Background()
+
+	// Unverified users can still log in
+	login := loginTestUser(t, svc)
+	userID := login.User.ID
+	if login.User.EmailVerified {
+		t.Fatal("expected a new account to start unverified")
+	}
+	err := svc.RequireVerifiedEmail(ctx, userID)
+	if appErr, ok := err.(*models.AppError); !ok || appErr.Code != models.ErrCodeEmailNotVerified {
+		t.Fatalf("expected EMAIL_NOT_VERIFIED, got %v", err)
+	}
+
+	// An expired token is rejected
+	svc.SetVerificationTTL(time.Nanosecond)
+	expired, err := svc.IssueEmailVerification(ctx, userID)
+	if err != nil {
+		t.Fatalf("issue failed: %v", err)
+	}
+	time.Sleep(time.Millisecond)
+	if _, err := svc.VerifyEmail(ctx, expired.Token); err == nil {
+		t.Error("expected an expired token to be rejected")
+	}
+
+	// Reissuing replaces the previous token
+	svc.SetVerificationTTL(time.Hour)
+	first, _ := svc.IssueEmailVerification(ctx, userID)
+	second, err := svc.IssueEmailVerification(ctx, userID)
+	if err != nil {
+		t.Fatalf("reissue failed: %v", err)
+	}
+	if _, err := svc.VerifyEmail(ctx, first.Token); err == nil {
+		t.Error("expected a replaced token to be rejected")
+	}
+
+	user, err := svc.VerifyEmail(ctx, second.Token)
+	if err != nil {
+		t.Fatalf("verify failed: %v", err)
+	}
+	if !user.EmailVerified {
+		t.Error("expected the user to be verified")
+	}
+	if err := svc.RequireVerifiedEmail(ctx, userID); err != nil {
+		t.Errorf("expected verified user to pass, got %v", err)
+	}
+	if _, err := svc.VerifyEmail(ctx, second.Token); err == nil {
+		t.Error("expected a used token to be rejected")
+	}
+	if _, err := svc.IssueEmailVerification(ctx, userID); err == nil {
+		t.Error("expected no new token once verified")
+	}
+}
@@ -3,13 +3,15 @@
 // Chức năng:
 //   - Lưu trữ chat messages vào database
 //   - Load lịch sử chat khi user join room
-//   - Quản lý chat rooms
+//   - Quản lý chat rooms (directory theo room_type, mỗi manga một room)
 //   - Support pagination cho message history
+//   - Read receipts: chat_room_members.last_read_at và số tin chưa đọc mỗi room
 package chat
 
 import (
 	"context"
 	"database/sql"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
@@ -55,6 +57,38 @@ type MessageListResponse struct {
 	HasMore  bool      `json:"has_more"`
 }
 
+// RoomSummary is a room in the directory with how many users are in it now
+type RoomSummary struct {
+	Room
+	MemberCount int `json:"member_count"`
+}
+
+// RoomPage is one page of the rooms directory, busiest rooms first
+type RoomPage struct {
+	Rooms   []RoomSummary `json:"rooms"`
+	Total   int           `json:"total"`
+	Page    int           `json:"page"`
+	Limit   int           `json:"limit"`
+	HasMore bool          `json:"has_more"`
+}
+
+// MessagePage is one page of history, oldest message first.
+// Pass the first message's ID as "before" to fetch the previous page.
+// For pages read forward with "after", HasMore means newer messages remain.
+type MessagePage struct {
+	Messages []Message `json:"messages"`
+	HasMore  bool      `json:"has_more"`
+}
+
+// RoomUnread is how many messages of a room a user has not read yet.
+// Their own and deleted messages are not counted.
+type RoomUnread struct {
+	RoomID     string    `json:"room_id"`
+	RoomName   string    `json:"room_name"`
+	Unread     int       `json:"unread"`
+	LastReadAt time.Time `json:"last_read_at"`
+}
+
 // =====================================
 // REPOSITORY - Database operations
 // =====================================
@@ -64,13 +98,29 @@ type Repository interface {
 	// Message operations
 	SaveMessage(ctx context.Context, msg *Message) error
 	GetMessagesByRoom(ctx context.Context, roomID string, limit, offset int) ([]Message, int, error)
+	GetMessages(ctx context.Context, roomID, beforeID string, limit int) (*MessagePage, error)
+	// GetMessagesAfter returns messages newer than afterID, oldest first;
+	// nil when afterID is not a message of the room
+	GetMessagesAfter(ctx context.Context, roomID, afterID string, limit int) (*MessagePage, error)
 	DeleteMessage(ctx context.Context, messageID, userID string) error
 	
 	// Room operations
 	CreateRoom(ctx context.Context, room *Room) error
 	GetRoom(ctx context.Context, roomID string) (*Room, error)
 	GetRoomByMangaID(ctx context.Context, mangaID string) (*Room, error)
-	GetOrCreateMangaRoom(ctx context.Context, mangaID, mangaTitle string) (*Room, error)
+	GetOrCreateMangaRoom(ctx context.Context, mangaID, ownerID string) (*Room, error)
+	EnsureRoom(ctx context.Context, roomID, ownerID string) error
+	// ListRooms returns rooms of one type ("" for all), oldest first;
+	// inactive rooms are left out unless includeInactive
+	ListRooms(ctx context.Context, roomType string, includeInactive bool) ([]Room, error)
+
+	// Read receipts
+	// MarkRead moves the user's read position in a room up to messageID, or to
+	// the room's newest message when messageID is empty. It never moves back.
+	// Returns false when the room or the message does not exist.
+	MarkRead(ctx context.Context, roomID, userID, messageID string) (bool, error)
+	// UnreadCounts lists the active rooms the user has read, with their unread counts
+	UnreadCounts(ctx context.Context, userID string) ([]RoomUnread, error)
 }
 
 type repository struct {
@@ -152,6 +202,115 @@ func (r *repository) GetMessagesByRoom(ctx context.Context, roomID string, limit
 	return messages, total, nil
 }
 
+// GetMessages loads one page of history older than beforeID (cursor pagination)
+// Empty beforeID lấy trang mới nhất; trả về oldest first để hiển thị
+func (r *repository) GetMessages(ctx context.Context, roomID, beforeID string, limit int) (*MessagePage, error) {
+	query := `
+		SELECT cm.id, cm.room_id, cm.user_id, COALESCE(u.username, 'Anonymous') as username,
+		       cm.content, cm.reply_to_id, cm.is_edited, cm.is_deleted,
+		       cm.created_at, cm.updated_at
+		FROM chat_messages cm
+		LEFT JOIN users u ON cm.user_id = u.id
+		WHERE cm.room_id = ? AND cm.is_deleted = 0`
+	args := []interface{}{roomID}
+	if beforeID != "" {
+		// Row-value comparison keeps the order stable when timestamps tie
+		query += ` AND (cm.created_at, cm.id) < (SELECT created_at, id FROM chat_messages WHERE id = ?)`
+		args = append(args, beforeID)
+	}
+	// Fetch one extra row to know whether an older page exists
+	query += ` ORDER BY cm.created_at DESC, cm.id DESC LIMIT ?`
+	args = append(args, limit+1)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []Message{}
+	for rows.Next() {
+		var msg Message
+		if err := rows.Scan(
+			&msg.ID, &msg.RoomID, &msg.UserID, &msg.Username,
+			&msg.Content, &msg.ReplyToID,
+			&msg.IsEdited, &msg.IsDeleted, &msg.CreatedAt, &msg.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &MessagePage{HasMore: len(messages) > limit}
+	if page.HasMore {
+		messages = messages[:limit]
+	}
+	// Reverse để có chronological order (oldest first)
+	for i, j := 0, len(messages)-1; i < j; i, j = i+1, j-1 {
+		messages[i], messages[j] = messages[j], messages[i]
+	}
+	page.Messages = messages
+	return page, nil
+}
+
+// GetMessagesAfter loads up to limit messages newer than afterID, oldest first
+// Dùng để replay tin nhắn bị lỡ khi WebSocket reconnect.
+// Returns nil, nil when afterID is not a message of the room.
+func (r *repository) GetMessagesAfter(ctx context.Context, roomID, afterID string, limit int) (*MessagePage, error) {
+	var known bool
+	if err := r.db.QueryRowContext(ctx,
+		`SELECT EXISTS(SELECT 1 FROM chat_messages WHERE id = ? AND room_id = ?)`, afterID, roomID,
+	).Scan(&known); err != nil {
+		return nil, err
+	}
+	if !known {
+		return nil, nil
+	}
+
+	// Fetch one extra row to know whether more newer messages exist
+	query := `
+		SELECT cm.id, cm.room_id, cm.user_id, COALESCE(u.username, 'Anonymous') as username,
+		       cm.content, cm.reply_to_id, cm.is_edited, cm.is_deleted,
+		       cm.created_at, cm.updated_at
+		FROM chat_messages cm
+		LEFT JOIN users u ON cm.user_id = u.id
+		WHERE cm.room_id = ? AND cm.is_deleted = 0
+		  AND (cm.created_at, cm.id) > (SELECT created_at, id FROM chat_messages WHERE id = ?)
+		ORDER BY cm.created_at ASC, cm.id ASC
+		LIMIT ?`
+	rows, err := r.db.QueryContext(ctx, query, roomID, afterID, limit+1)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	messages := []Message{}
+	for rows.Next() {
+		var msg Message
+		if err := rows.Scan(
+			&msg.ID, &msg.RoomID, &msg.UserID, &msg.Username,
+			&msg.Content, &msg.ReplyToID,
+			&msg.IsEdited, &msg.IsDeleted, &msg.CreatedAt, &msg.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	page := &MessagePage{HasMore: len(messages) > limit}
+	if page.HasMore {
+		messages = messages[:limit]
+	}
+	page.Messages = messages
+	return page, nil
+}
+
 // DeleteMessage soft-deletes a message
 // Chỉ user tạo message mới được xóa
 func (r *repository) DeleteMessage(ctx context.Context, messageID, userID string) error {
@@ -180,7 +339,7 @@ func (r *repository) CreateRoom(ctx context.Context, room *Room) error {
 
 // GetRoom retrieves a room by ID
 func (r *repository) GetRoom(ctx context.Context, roomID string) (*Room, error) {
-	query := `SELECT id, name, room_type, manga_id, owner_id, description, is_active, created_at, updated_at
+	query := `SELECT id, name, room_type, manga_id, owner_id, COALESCE(description, ''), is_active, created_at, updated_at
 	          FROM chat_rooms WHERE id = ?`
 	
 	var room Room
@@ -198,9 +357,11 @@ func (r *repository) GetRoom(ctx context.Context, roomID string) (*Room, error)
 }
 
 // GetRoomByMangaID retrieves a room by manga ID
+// Nếu có nhiều room cho cùng manga thì lấy room tạo sớm nhất
 func (r *repository) GetRoomByMangaID(ctx context.Context, mangaID string) (*Room, error) {
-	query := `SELECT id, name, room_type, manga_id, owner_id, description, is_active, created_at, updated_at
-	          FROM chat_rooms WHERE manga_id = ?`
+	query := `SELECT id, name, room_type, manga_id, owner_id, COALESCE(description, ''), is_active, created_at, updated_at
+	          FROM chat_rooms WHERE manga_id = ? AND room_type = 'manga'
+	          ORDER BY created_at ASC, id ASC LIMIT 1`
 	
 	var room Room
 	err := r.db.QueryRowContext(ctx, query, mangaID).Scan(
@@ -217,32 +378,151 @@ func (r *repository) GetRoomByMangaID(ctx context.Context, mangaID string) (*Roo
 }
 
 // GetOrCreateMangaRoom gets or creates a chat room for a manga
-// Tự động tạo room nếu chưa tồn tại khi user join chat của manga
-func (r *repository) GetOrCreateMangaRoom(ctx context.Context, mangaID, mangaTitle string) (*Room, error) {
-	// Check if room exists
+// Tự động tạo room nếu chưa tồn tại khi user join chat của manga.
+// The room ID is "manga_<id>", the ID EnsureRoom links to the manga, so the
+// two paths never create a second room; the user opening it becomes its owner.
+// Returns nil, nil when the manga does not exist.
+func (r *repository) GetOrCreateMangaRoom(ctx context.Context, mangaID, ownerID string) (*Room, error) {
 	room, err := r.GetRoomByMangaID(ctx, mangaID)
+	if err != nil || room != nil {
+		return room, err
+	}
+
+	// INSERT OR IGNORE keeps two users opening the room at once from failing
+	query := `
+		INSERT OR IGNORE INTO chat_rooms (id, name, room_type, manga_id, owner_id, description, is_active, created_at, updated_at)
+		SELECT ?, title || ' Discussion', 'manga', id, ?, 'Discussion room for ' || title, 1, ?, ?
+		FROM manga WHERE id = ?`
+	now := time.Now()
+	if _, err := r.db.ExecContext(ctx, query, "manga_"+mangaID, ownerID, now, now, mangaID); err != nil {
+		return nil, err
+	}
+	if room, err = r.GetRoomByMangaID(ctx, mangaID); err != nil || room != nil {
+		return room, err
+	}
+	// EnsureRoom may have created "manga_<id>" before the manga existed, unlinked
+	return r.GetRoom(ctx, "manga_"+mangaID)
+}
+
+// ListRooms returns rooms of one type ("" for all), oldest first
+func (r *repository) ListRooms(ctx context.Context, roomType string, includeInactive bool) ([]Room, error) {
+	query := `SELECT id, name, room_type, manga_id, owner_id, COALESCE(description, ''), is_active, created_at, updated_at
+	          FROM chat_rooms WHERE 1 = 1`
+	var args []interface{}
+	if roomType != "" {
+		query += ` AND room_type = ?`
+		args = append(args, roomType)
+	}
+	if !includeInactive {
+		query += ` AND is_active = 1`
+	}
+	query += ` ORDER BY created_at ASC, id ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
-	if room != nil {
-		return room, nil
+	defer rows.Close()
+
+	rooms := []Room{}
+	for rows.Next() {
+		var room Room
+		if err := rows.Scan(
+			&room.ID, &room.Name, &room.RoomType, &room.MangaID, &room.OwnerID,
+			&room.Description, &room.IsActive, &room.CreatedAt, &room.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		rooms = append(rooms, room)
 	}
+	return rooms, rows.Err()
+}
 
-	// Create new room for manga
-	newRoom := &Room{
-		ID:          uuid.New().String(),
-		Name:        mangaTitle + " Discussion",
-		RoomType:    "manga",
-		MangaID:     &mangaID,
-		OwnerID:     "system", // System-created room
-		Description: "Discussion room for " + mangaTitle,
-		IsActive: true,
-		CreatedAt: time.Now(),
-		UpdatedAt: time.Now(),
+// EnsureRoom creates the chat_rooms row for a room ID the first time it is used
+// chat_messages.room_id là foreign key nên room phải tồn tại trước khi lưu message.
+// "manga_<id>" rooms are linked to the manga when it exists; anything else is general.
+func (r *repository) EnsureRoom(ctx context.Context, roomID, ownerID string) error {
+	roomType := "general"
+	mangaID := ""
+	if strings.HasPrefix(roomID, "manga_") {
+		roomType = "manga"
+		mangaID = strings.TrimPrefix(roomID, "manga_")
 	}
-	
-	if err := r.CreateRoom(ctx, newRoom); err != nil {
+
+	query := `
+		INSERT OR IGNORE INTO chat_rooms (id, name, room_type, manga_id, owner_id, is_active, created_at, updated_at)
+		VALUES (?, ?, ?, (SELECT id FROM manga WHERE id = ?), ?, 1, ?, ?)`
+
+	now := time.Now()
+	_, err := r.db.ExecContext(ctx, query, roomID, roomID, roomType, mangaID, ownerID, now, now)
+	return err
+}
+
+// MarkRead records how far a user has read a room
+// Read position là created_at của message, nên replay/history cũ không làm sai số đếm.
+func (r *repository) MarkRead(ctx context.Context, roomID, userID, messageID string) (bool, error) {
+	// Read up to the given message, or the newest one; an empty room is read up to now
+	query := `SELECT created_at FROM chat_messages WHERE room_id = ? AND id = ?`
+	args := []interface{}{roomID, messageID}
+	if messageID == "" {
+		query = `SELECT created_at FROM chat_messages WHERE room_id = ? ORDER BY created_at DESC, id DESC LIMIT 1`
+		args = args[:1]
+	}
+	var readAt time.Time
+	err := r.db.QueryRowContext(ctx, query, args...).Scan(&readAt)
+	switch {
+	case err == sql.ErrNoRows && messageID != "":
+		return false, nil
+	case err == sql.ErrNoRows:
+		readAt = time.Now()
+	case err != nil:
+		return false, err
+	}
+
+	// Membership is created on the first read; the WHERE on the upsert keeps
+	// an older position (a history page, a late request) from moving it back
+	result, err := r.db.ExecContext(ctx, `
+		INSERT INTO chat_room_members (id, room_id, user_id, joined_at, last_read_at)
+		SELECT ?, id, ?, ?, ? FROM chat_rooms WHERE id = ?
+		ON CONFLICT(room_id, user_id) DO UPDATE SET last_read_at = excluded.last_read_at
+		WHERE julianday(excluded.last_read_at) > julianday(chat_room_members.last_read_at)`,
+		uuid.New().String(), userID, time.Now(), readAt, roomID)
+	if err != nil {
+		return false, err
+	}
+	if n, _ := result.RowsAffected(); n > 0 {
+		return true, nil
+	}
+	// Nothing written: either the position did not move or the room is unknown
+	var exists bool
+	err = r.db.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM chat_rooms WHERE id = ?)`, roomID).Scan(&exists)
+	return exists, err
+}
+
+// UnreadCounts counts, per room, the messages newer than the user's read position
+func (r *repository) UnreadCounts(ctx context.Context, userID string) ([]RoomUnread, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.room_id, cr.name, m.last_read_at, COUNT(cm.id)
+		FROM chat_room_members m
+		JOIN chat_rooms cr ON cr.id = m.room_id AND cr.is_active = 1
+		LEFT JOIN chat_messages cm ON cm.room_id = m.room_id
+		     AND cm.is_deleted = 0 AND cm.user_id != m.user_id
+		     AND julianday(cm.created_at) > julianday(m.last_read_at)
+		WHERE m.user_id = ?
+		GROUP BY m.room_id, cr.name, m.last_read_at
+		ORDER BY m.room_id`, userID)
+	if err != nil {
 		return nil, err
 	}
-	return newRoom, nil
+	defer rows.Close()
+
+	counts := []RoomUnread{}
+	for rows.Next() {
+		var u RoomUnread
+		if err := rows.Scan(&u.RoomID, &u.RoomName, &u.LastReadAt, &u.Unread); err != nil {
+			return nil, err
+		}
+		counts = append(counts, u)
+	}
+	return counts, rows.Err()
 }
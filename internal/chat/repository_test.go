@@ -0,0 +1,267 @@
+// Package chat - Repository Tests
+// Unit tests cho message history pagination (cursor "before") và read receipts
+package chat
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"testing"
+	"time"
+
+	"mangahub/internal/testutil"
+)
+
+// setupTestDB opens the shared test database and seeds the rows these tests use
+func setupTestDB(t *testing.T) *sql.DB {
+	db := testutil.OpenDB(t)
+	// Foreign keys on, as the server's DSN has them
+	if _, err := db.Exec(`PRAGMA foreign_keys = ON`); err != nil {
+		t.Fatalf("enable foreign keys failed: %v", err)
+	}
+
+	_, err := db.Exec(`INSERT INTO users (id, username, email, password_hash, display_name) VALUES
+		('u1', 'alice', 'a@example.com', 'x', 'Alice'),
+		('u2', 'bob', 'b@example.com', 'x', 'Bob')`)
+	if err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+
+	return db
+}
+
+func TestGetMessagesPaging(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	repo := NewRepository(sqlDB)
+	ctx := context.Background()
+
+	// Messages need their room to exist (foreign key)
+	if err := repo.EnsureRoom(ctx, "general", "u1"); err != nil {
+		t.Fatalf("EnsureRoom failed: %v", err)
+	}
+	if err := repo.EnsureRoom(ctx, "general", "u2"); err != nil {
+		t.Fatalf("EnsureRoom should be idempotent: %v", err)
+	}
+
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 5; i++ {
+		userID := []string{"u1", "u2"}[i%2]
+		_, err := sqlDB.Exec(`INSERT INTO chat_messages (id, room_id, user_id, content, is_deleted, created_at, updated_at)
+			VALUES (?, 'general', ?, ?, ?, ?, ?)`,
+			fmt.Sprintf("m%d", i), userID, fmt.Sprintf("msg %d", i), i == 2,
+			base.Add(time.Duration(i)*time.Minute), base)
+		if err != nil {
+			t.Fatalf("insert message failed: %v", err)
+		}
+	}
+
+	// Latest page: m4, m3 (m2 is deleted) in chronological order
+	page, err := repo.GetMessages(ctx, "general", "", 2)
+	if err != nil {
+		t.Fatalf("GetMessages failed: %v", err)
+	}
+	if len(page.Messages) != 2 || page.Messages[0].ID != "m3" || page.Messages[1].ID != "m4" || !page.HasMore {
+		t.Fatalf("unexpected latest page: %+v", page)
+	}
+	if page.Messages[0].Username != "bob" || page.Messages[1].Username != "alice" {
+		t.Errorf("expected usernames joined from users, got %q and %q",
+			page.Messages[0].Username, page.Messages[1].Username)
+	}
+
+	// Previous page skips the deleted message and reports no more history
+	page, err = repo.GetMessages(ctx, "general", page.Messages[0].ID, 2)
+	if err != nil {
+		t.Fatalf("GetMessages(before) failed: %v", err)
+	}
+	if len(page.Messages) != 2 || page.Messages[0].ID != "m0" || page.Messages[1].ID != "m1" || page.HasMore {
+		t.Fatalf("unexpected previous page: %+v", page)
+	}
+
+	// A room with no history returns an empty page, not nil
+	page, err = repo.GetMessages(ctx, "empty", "", 50)
+	if err != nil {
+		t.Fatalf("GetMessages(empty) failed: %v", err)
+	}
+	if page.Messages == nil || len(page.Messages) != 0 || page.HasMore {
+		t.Errorf("expected empty page, got %+v", page)
+	}
+}
+
+func TestGetMessagesAfter(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	repo := NewRepository(sqlDB)
+	ctx := context.Background()
+
+	if err := repo.EnsureRoom(ctx, "general", "u1"); err != nil {
+		t.Fatalf("EnsureRoom failed: %v", err)
+	}
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < 5; i++ {
+		_, err := sqlDB.Exec(`INSERT INTO chat_messages (id, room_id, user_id, content, is_deleted, created_at, updated_at)
+			VALUES (?, 'general', 'u1', ?, ?, ?, ?)`,
+			fmt.Sprintf("m%d", i), fmt.Sprintf("msg %d", i), i == 2,
+			base.Add(time.Duration(i)*time.Minute), base)
+		if err != nil {
+			t.Fatalf("insert message failed: %v", err)
+		}
+	}
+
+	// Newer than m0: m1, m3 (m2 is deleted), then m4 is left over
+	page, err := repo.GetMessagesAfter(ctx, "general", "m0", 2)
+	if err != nil {
+		t.Fatalf("GetMessagesAfter failed: %v", err)
+	}
+	if len(page.Messages) != 2 || page.Messages[0].ID != "m1" || page.Messages[1].ID != "m3" || !page.HasMore {
+		t.Fatalf("unexpected page after m0: %+v", page)
+	}
+
+	// Caught up: empty page, nothing more
+	page, err = repo.GetMessagesAfter(ctx, "general", "m4", 2)
+	if err != nil {
+		t.Fatalf("GetMessagesAfter(latest) failed: %v", err)
+	}
+	if len(page.Messages) != 0 || page.HasMore {
+		t.Errorf("expected empty page after the newest message, got %+v", page)
+	}
+
+	// A message of another room cannot be replayed from
+	page, err = repo.GetMessagesAfter(ctx, "other", "m0", 2)
+	if err != nil || page != nil {
+		t.Errorf("GetMessagesAfter(other, m0) = %+v, %v; want nil, nil", page, err)
+	}
+}
+
+func TestMangaRoomCreatedOnce(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	repo := NewRepository(sqlDB)
+	ctx := context.Background()
+
+	if _, err := sqlDB.Exec(`INSERT INTO manga (id, title) VALUES ('m1', 'Berserk'), ('m2', 'Monster')`); err != nil {
+		t.Fatalf("seed manga failed: %v", err)
+	}
+
+	room, err := repo.GetOrCreateMangaRoom(ctx, "m1", "u1")
+	if err != nil {
+		t.Fatalf("GetOrCreateMangaRoom failed: %v", err)
+	}
+	if room == nil || room.ID != "manga_m1" || room.RoomType != "manga" || room.Name != "Berserk Discussion" {
+		t.Fatalf("unexpected room: %+v", room)
+	}
+	again, err := repo.GetOrCreateMangaRoom(ctx, "m1", "u2")
+	if err != nil || again == nil || again.ID != room.ID || again.OwnerID != "u1" {
+		t.Fatalf("second open should return the existing room, got %+v, %v", again, err)
+	}
+
+	// A room the WebSocket path already created is reused, not duplicated
+	if err := repo.EnsureRoom(ctx, "manga_m2", "u2"); err != nil {
+		t.Fatalf("EnsureRoom failed: %v", err)
+	}
+	if room, err := repo.GetOrCreateMangaRoom(ctx, "m2", "u1"); err != nil || room == nil || room.ID != "manga_m2" {
+		t.Fatalf("expected the existing manga_m2 room, got %+v, %v", room, err)
+	}
+
+	if room, err := repo.GetOrCreateMangaRoom(ctx, "missing", "u1"); err != nil || room != nil {
+		t.Fatalf("unknown manga should return nil, got %+v, %v", room, err)
+	}
+
+	// Directory: filtered by type, inactive rooms only on request
+	if err := repo.EnsureRoom(ctx, "general", "u1"); err != nil {
+		t.Fatalf("EnsureRoom failed: %v", err)
+	}
+	if _, err := sqlDB.Exec(`UPDATE chat_rooms SET is_active = 0 WHERE id = 'manga_m2'`); err != nil {
+		t.Fatalf("deactivate failed: %v", err)
+	}
+	for _, tc := range []struct {
+		roomType        string
+		includeInactive bool
+		want            int
+	}{
+		{"", false, 2},
+		{"manga", false, 1},
+		{"manga", true, 2},
+		{"general", false, 1},
+	} {
+		rooms, err := repo.ListRooms(ctx, tc.roomType, tc.includeInactive)
+		if err != nil {
+			t.Fatalf("ListRooms failed: %v", err)
+		}
+		if len(rooms) != tc.want {
+			t.Errorf("ListRooms(%q, %v) = %d rooms, want %d", tc.roomType, tc.includeInactive, len(rooms), tc.want)
+		}
+	}
+}
+
+func TestUnreadCounts(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	repo := NewRepository(sqlDB)
+	ctx := context.Background()
+
+	if err := repo.EnsureRoom(ctx, "general", "u1"); err != nil {
+		t.Fatalf("EnsureRoom failed: %v", err)
+	}
+	base := time.Now().Add(-time.Hour)
+	insert := func(id, userID string, minute int, deleted bool) {
+		t.Helper()
+		_, err := sqlDB.Exec(`INSERT INTO chat_messages (id, room_id, user_id, content, is_deleted, created_at, updated_at)
+			VALUES (?, 'general', ?, 'hi', ?, ?, ?)`,
+			id, userID, deleted, base.Add(time.Duration(minute)*time.Minute), base)
+		if err != nil {
+			t.Fatalf("insert message failed: %v", err)
+		}
+	}
+	unread := func(userID string) int {
+		t.Helper()
+		counts, err := repo.UnreadCounts(ctx, userID)
+		if err != nil {
+			t.Fatalf("UnreadCounts failed: %v", err)
+		}
+		if len(counts) != 1 || counts[0].RoomID != "general" {
+			t.Fatalf("expected only general, got %+v", counts)
+		}
+		return counts[0].Unread
+	}
+
+	insert("m0", "u1", 0, false)
+	insert("m1", "u1", 1, false)
+	if ok, err := repo.MarkRead(ctx, "general", "u2", "m0"); err != nil || !ok {
+		t.Fatalf("MarkRead: ok=%v err=%v", ok, err)
+	}
+	// Own and deleted messages never count
+	insert("m2", "u2", 2, false)
+	insert("m3", "u1", 3, true)
+	insert("m4", "u1", 4, false)
+	if got := unread("u2"); got != 2 {
+		t.Errorf("unread = %d, want 2 (m1, m4)", got)
+	}
+
+	// Reading an older page does not move the position back
+	if _, err := repo.MarkRead(ctx, "general", "u2", "m4"); err != nil {
+		t.Fatalf("MarkRead failed: %v", err)
+	}
+	if _, err := repo.MarkRead(ctx, "general", "u2", "m1"); err != nil {
+		t.Fatalf("MarkRead failed: %v", err)
+	}
+	if got := unread("u2"); got != 0 {
+		t.Errorf("unread after reading m4 = %d, want 0", got)
+	}
+
+	// A newer message is unread again
+	insert("m5", "u1", 5, false)
+	if got := unread("u2"); got != 1 {
+		t.Errorf("unread = %d, want 1", got)
+	}
+	// No message ID reads up to the newest message
+	if _, err := repo.MarkRead(ctx, "general", "u2", ""); err != nil {
+		t.Fatalf("MarkRead failed: %v", err)
+	}
+	if got := unread("u2"); got != 0 {
+		t.Errorf("unread after marking all = %d, want 0", got)
+	}
+
+	if ok, _ := repo.MarkRead(ctx, "general", "u2", "nope"); ok {
+		t.Error("expected an unknown message to be reported")
+	}
+	if ok, _ := repo.MarkRead(ctx, "missing-room", "u2", ""); ok {
+		t.Error("expected an unknown room to be reported")
+	}
+}
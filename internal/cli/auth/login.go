@@ -64,6 +64,9 @@ var loginCmd = &cobra.Command{
 			viper.Set("user.username", username)
 			viper.Set("user.id", user["id"])
 			viper.Set("user.token", token)
+			if refreshToken, ok := data["refresh_token"].(string); ok {
+				viper.Set("user.refresh_token", refreshToken)
+			}
 			viper.WriteConfigAs(filepath.Join(configDir, "config.yaml"))
 
 			fmt.Println("✓ Login successful!")
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"net"
+	"time"
 
 	"mangahub/internal/udp"
 
@@ -29,8 +30,9 @@ var notifyCmd = &cobra.Command{
 			Type:      notifType,
 			MangaID:   mangaID,
 			Message:   message,
-			Timestamp: 0, // Server will likely set it or we set it here? Protocol says int64.
+			Timestamp: time.Now().Unix(),
 		}
+		udp.Sign(&notification, viper.GetString("udp.secret"))
 
 		// Connect to UDP server
 		host := viper.GetString("server.host")
@@ -0,0 +1,92 @@
+// Package comment - Chapter Filter Tests
+// Unit tests cho ?chapter= filter và index idx_comments_chapter
+package comment
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+func TestChapterFilter(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	ch5, ch6 := 5, 6
+	svc.Create(ctx, "user1", "manga1", models.CreateCommentRequest{Content: "General"})
+	root, _ := svc.Create(ctx, "user1", "manga1", models.CreateCommentRequest{Content: "Chapter 5!", ChapterNumber: &ch5})
+	svc.Create(ctx, "user2", "manga1", models.CreateCommentRequest{Content: "Chapter 6!", ChapterNumber: &ch6})
+	// A reply without a chapter still belongs to its parent's chapter
+	reply, err := svc.Create(ctx, "user2", "manga1", models.CreateCommentRequest{Content: "Agreed", ParentID: root.ID})
+	if err != nil {
+		t.Fatalf("create reply: %v", err)
+	}
+	if reply.ChapterNumber == nil || *reply.ChapterNumber != 5 {
+		t.Errorf("reply chapter = %v, want 5", reply.ChapterNumber)
+	}
+
+	tests := []struct {
+		query string
+		roots int
+	}{
+		{"", 3},
+		{"general", 1},
+		{"5", 1},
+		{"7", 0},
+	}
+	for _, tt := range tests {
+		filter, err := models.ParseCommentFilter(tt.query)
+		if err != nil {
+			t.Fatalf("ParseCommentFilter(%q): %v", tt.query, err)
+		}
+		resp, err := svc.GetThreadedComments(ctx, "manga1", filter, "", "", 1, 20, 2)
+		if err != nil {
+			t.Fatalf("chapter=%q: %v", tt.query, err)
+		}
+		if len(resp.Comments) != tt.roots {
+			t.Errorf("chapter=%q: got %d threads, want %d", tt.query, len(resp.Comments), tt.roots)
+		}
+	}
+
+	if _, err := models.ParseCommentFilter("latest"); err == nil {
+		t.Error("expected an error for a non-numeric chapter")
+	}
+}
+
+func TestChapterFilterUsesIndex(t *testing.T) {
+	sqlDB := testutil.OpenDB(t)
+
+	ch := 5
+	for _, filter := range []models.CommentFilter{{Chapter: &ch}, {General: true}} {
+		chapterFilter, args := chapterClause(filter)
+		args = append([]interface{}{"manga1"}, args...)
+		rows, err := sqlDB.Query(`EXPLAIN QUERY PLAN
+			SELECT c.id FROM comments c JOIN users u ON c.user_id = u.id
+			WHERE c.manga_id = ?`+chapterFilter+` AND c.parent_id IS NULL AND c.is_deleted = 0
+			ORDER BY c.created_at DESC, c.id DESC LIMIT 20`, args...)
+		if err != nil {
+			t.Fatalf("explain: %v", err)
+		}
+
+		var plan []string
+		for rows.Next() {
+			var id, parent, notused int
+			var detail string
+			if err := rows.Scan(&id, &parent, &notused, &detail); err != nil {
+				t.Fatalf("scan plan: %v", err)
+			}
+			plan = append(plan, detail)
+		}
+		rows.Close()
+
+		if !strings.Contains(strings.Join(plan, "\n"), "idx_comments_chapter") {
+			t.Errorf("filter %+v does not use idx_comments_chapter:\n%s", filter, strings.Join(plan, "\n"))
+		}
+	}
+}
@@ -5,10 +5,13 @@ package comment
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"fmt"
 	"testing"
 
 	_ "github.com/mattn/go-sqlite3"
 	"mangahub/pkg/models"
+	"mangahub/pkg/moderation"
 )
 
 // setupTestDB creates an in-memory SQLite database for testing
@@ -63,6 +66,25 @@ func setupTestDB(t *testing.T) *sql.DB {
 			FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
 			FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
 		)`,
+		`CREATE TABLE IF NOT EXISTS activity_feed (
+			id TEXT PRIMARY KEY,
+			user_id TEXT NOT NULL,
+			username TEXT NOT NULL,
+			activity_type TEXT NOT NULL,
+			manga_id TEXT NOT NULL,
+			manga_title TEXT NOT NULL,
+			comment_text TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE TABLE IF NOT EXISTS audit_log (
+			id TEXT PRIMARY KEY,
+			actor_id TEXT NOT NULL,
+			action TEXT NOT NULL,
+			target_type TEXT NOT NULL,
+			target_id TEXT NOT NULL,
+			details TEXT,
+			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+		)`,
 	}
 
 	for _, table := range tables {
@@ -149,7 +171,7 @@ func TestCommentRepository_GetByManga(t *testing.T) {
 	repo.Create(ctx, "user2", "manga1", models.CreateCommentRequest{Content: "Comment 2"})
 
 	// Get comments (no chapter filter = manga-level comments)
-	comments, err := repo.GetByManga(ctx, "manga1", nil, 10, 0)
+	comments, _, err := repo.GetByManga(ctx, "manga1", models.CommentFilter{}, "", 10, 0)
 	if err != nil {
 		t.Fatalf("GetByManga failed: %v", err)
 	}
@@ -206,6 +228,57 @@ func TestCommentRepository_Delete(t *testing.T) {
 	}
 }
 
+func TestCommentService_DeleteAsModerator(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRepository(db)
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	comment, _ := repo.Create(ctx, "user1", "manga1", models.CreateCommentRequest{Content: "Abusive comment"})
+	repo.Like(ctx, comment.ID, "user2")
+	db.Exec(`INSERT INTO activity_feed (id, user_id, username, activity_type, manga_id, manga_title, comment_text)
+		VALUES ('act-' || ?, 'user1', 'testuser', 'comment', 'manga1', 'Test Manga', 'Abusive comment')`, comment.ID)
+
+	// A regular user can't delete someone else's comment
+	err := svc.Delete(ctx, comment.ID, "user2", models.UserRoleUser)
+	if appErr, ok := err.(*models.AppError); !ok || appErr.StatusCode != 403 {
+		t.Fatalf("expected 403 for non-owner, got %v", err)
+	}
+
+	if err := svc.Delete(ctx, comment.ID, "user2", models.UserRoleModerator); err != nil {
+		t.Fatalf("moderator Delete failed: %v", err)
+	}
+
+	// Row and likes are kept, content is blanked
+	deleted, _ := repo.GetByID(ctx, comment.ID)
+	if deleted == nil || !deleted.IsDeleted || deleted.Content != models.DeletedCommentText {
+		t.Fatalf("expected soft-deleted comment, got %+v", deleted)
+	}
+	if deleted.LikesCount != 1 {
+		t.Errorf("expected likes_count to stay 1, got %d", deleted.LikesCount)
+	}
+
+	var activityText string
+	db.QueryRow(`SELECT comment_text FROM activity_feed WHERE id = 'act-' || ?`, comment.ID).Scan(&activityText)
+	if activityText != models.DeletedCommentText {
+		t.Errorf("expected activity text to be scrubbed, got %q", activityText)
+	}
+
+	var actor, action string
+	err = db.QueryRow(`SELECT actor_id, action FROM audit_log WHERE target_type = 'comment' AND target_id = ?`, comment.ID).Scan(&actor, &action)
+	if err != nil || actor != "user2" || action != "comment.moderate" {
+		t.Errorf("expected moderation audit row by user2, got %q/%q (%v)", actor, action, err)
+	}
+
+	// Deleting again is a 404, not a second audit row
+	err = svc.Delete(ctx, comment.ID, "user2", models.UserRoleAdmin)
+	if appErr, ok := err.(*models.AppError); !ok || appErr.StatusCode != 404 {
+		t.Errorf("expected 404 for already deleted comment, got %v", err)
+	}
+}
+
 func TestCommentRepository_Like(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -266,3 +339,173 @@ func TestCommentService_Create(t *testing.T) {
 		t.Error("expected error for empty content")
 	}
 }
+
+func TestCommentService_WordFilter(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	filter, err := moderation.New([]string{"damn"}, []string{"shit"})
+	if err != nil {
+		t.Fatalf("moderation.New failed: %v", err)
+	}
+	svc := NewServiceWithFilter(NewRepository(db), filter)
+	ctx := context.Background()
+
+	comment, err := svc.Create(ctx, "user1", "manga1", models.CreateCommentRequest{Content: "D4mn, what a class act"})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	if comment.Content != "****, what a class act" {
+		t.Errorf("expected masked content, got %q", comment.Content)
+	}
+
+	_, err = svc.Create(ctx, "user1", "manga1", models.CreateCommentRequest{Content: "s h i t"})
+	var appErr *models.AppError
+	if !errors.As(err, &appErr) || appErr.Code != models.ErrCodeValidation || appErr.Details["field"] != "content" {
+		t.Fatalf("expected content validation error, got %v", err)
+	}
+
+	// Edits are filtered too
+	if _, err := svc.Update(ctx, comment.ID, "user1", models.UpdateCommentRequest{Content: "$hit"}); err == nil {
+		t.Error("expected blocked edit to fail")
+	}
+}
+
+func TestCommentService_GetThreadedComments(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRepository(db)
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	// root -> reply -> nested -> too deep
+	root, _ := repo.Create(ctx, "user1", "manga1", models.CreateCommentRequest{Content: "Root"})
+	reply, _ := repo.Create(ctx, "user2", "manga1", models.CreateCommentRequest{Content: "Reply", ParentID: root.ID})
+	nested, _ := repo.Create(ctx, "user1", "manga1", models.CreateCommentRequest{Content: "Nested", ParentID: reply.ID})
+	repo.Create(ctx, "user2", "manga1", models.CreateCommentRequest{Content: "Too deep", ParentID: nested.ID})
+	repo.Like(ctx, reply.ID, "user1")
+
+	// Deleting the root must not hide its replies
+	if err := repo.Delete(ctx, root.ID, "user1"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+
+	resp, err := svc.GetThreadedComments(ctx, "manga1", models.CommentFilter{}, "user1", "", 1, 20, 2)
+	if err != nil {
+		t.Fatalf("GetThreadedComments failed: %v", err)
+	}
+	if len(resp.Comments) != 1 {
+		t.Fatalf("expected the deleted root to stay visible, got %d threads", len(resp.Comments))
+	}
+
+	thread := resp.Comments[0]
+	if thread.Content != models.DeletedCommentText {
+		t.Errorf("expected deleted root to show %q, got %q", models.DeletedCommentText, thread.Content)
+	}
+	if len(thread.Replies) != 1 || thread.Replies[0].ID != reply.ID {
+		t.Fatalf("expected one direct reply, got %d", len(thread.Replies))
+	}
+	if !thread.Replies[0].LikedByMe {
+		t.Error("expected reply to be marked as liked by user1")
+	}
+
+	second := thread.Replies[0].Replies
+	if len(second) != 1 || second[0].ID != nested.ID {
+		t.Fatalf("expected nested reply at depth 2, got %d", len(second))
+	}
+	if len(second[0].Replies) != 0 {
+		t.Errorf("expected replies beyond depth 2 to be cut off, got %d", len(second[0].Replies))
+	}
+}
+
+func TestCommentService_CursorPagination(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRepository(db)
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	var ids []string
+	for i := 1; i <= 5; i++ {
+		c, _ := repo.Create(ctx, "user1", "manga1", models.CreateCommentRequest{Content: fmt.Sprintf("Comment %d", i)})
+		ids = append(ids, c.ID)
+	}
+	repo.Create(ctx, "user2", "manga1", models.CreateCommentRequest{Content: "Reply", ParentID: ids[2]})
+
+	first, err := svc.GetThreadedComments(ctx, "manga1", models.CommentFilter{}, "", "", 1, 2, 2)
+	if err != nil {
+		t.Fatalf("first page: %v", err)
+	}
+	if len(first.Comments) != 2 || first.Comments[0].ID != ids[4] || first.NextCursor == "" {
+		t.Fatalf("unexpected first page: %d threads, cursor %q", len(first.Comments), first.NextCursor)
+	}
+
+	// A comment posted while reading must not shift the next page
+	repo.Create(ctx, "user2", "manga1", models.CreateCommentRequest{Content: "Newest"})
+
+	second, err := svc.GetThreadedComments(ctx, "manga1", models.CommentFilter{}, "", first.NextCursor, 1, 2, 2)
+	if err != nil {
+		t.Fatalf("second page: %v", err)
+	}
+	if len(second.Comments) != 2 || second.Comments[0].ID != ids[2] || second.Comments[1].ID != ids[1] {
+		t.Fatalf("second page should continue after the first, got %d threads", len(second.Comments))
+	}
+	if len(second.Comments[0].Replies) != 1 {
+		t.Errorf("expected the thread's reply on its page, got %d", len(second.Comments[0].Replies))
+	}
+
+	last, err := svc.GetThreadedComments(ctx, "manga1", models.CommentFilter{}, "", second.NextCursor, 1, 2, 2)
+	if err != nil {
+		t.Fatalf("last page: %v", err)
+	}
+	if len(last.Comments) != 1 || last.Comments[0].ID != ids[0] || last.HasMore || last.NextCursor != "" {
+		t.Errorf("unexpected last page: %d threads, has_more %v", len(last.Comments), last.HasMore)
+	}
+
+	if _, err := svc.GetComments(ctx, "manga1", models.CommentFilter{}, "", "not-a-cursor", 1, 2); err == nil {
+		t.Error("expected an error for an invalid cursor")
+	}
+}
+
+func TestCommentService_ThreadKeepsDeepLiveReplies(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRepository(db)
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	// Deleted root -> deleted reply -> live grandchild
+	root, _ := repo.Create(ctx, "user1", "manga1", models.CreateCommentRequest{Content: "Root"})
+	reply, _ := repo.Create(ctx, "user2", "manga1", models.CreateCommentRequest{Content: "Reply", ParentID: root.ID})
+	grandchild, _ := repo.Create(ctx, "user1", "manga1", models.CreateCommentRequest{Content: "Grandchild", ParentID: reply.ID})
+	repo.Delete(ctx, reply.ID, "user2")
+	repo.Delete(ctx, root.ID, "user1")
+
+	// A thread with nothing live left disappears
+	gone, _ := repo.Create(ctx, "user1", "manga1", models.CreateCommentRequest{Content: "Gone"})
+	goneReply, _ := repo.Create(ctx, "user2", "manga1", models.CreateCommentRequest{Content: "Gone too", ParentID: gone.ID})
+	repo.Delete(ctx, goneReply.ID, "user2")
+	repo.Delete(ctx, gone.ID, "user1")
+
+	resp, err := svc.GetThreadedComments(ctx, "manga1", models.CommentFilter{}, "", "", 1, 20, 3)
+	if err != nil {
+		t.Fatalf("GetThreadedComments failed: %v", err)
+	}
+	if len(resp.Comments) != 1 || resp.Comments[0].ID != root.ID {
+		t.Fatalf("expected only the thread with a live grandchild, got %d threads", len(resp.Comments))
+	}
+	if resp.TotalCount != 1 || resp.HasMore {
+		t.Errorf("expected total 1 without more pages, got total %d, has_more %v", resp.TotalCount, resp.HasMore)
+	}
+
+	replies := resp.Comments[0].Replies
+	if len(replies) != 1 || replies[0].Content != models.DeletedCommentText {
+		t.Fatalf("expected the deleted reply as a placeholder, got %d replies", len(replies))
+	}
+	if deep := replies[0].Replies; len(deep) != 1 || deep[0].ID != grandchild.ID {
+		t.Fatalf("expected the live grandchild below the deleted reply, got %d", len(deep))
+	}
+}
@@ -2,7 +2,7 @@
 // HTTP handlers cho comment API endpoints
 // Endpoints:
 //   - POST /manga/:id/comments - Create comment
-//   - GET /manga/:id/comments - Get comments (with optional ?chapter=N)
+//   - GET /manga/:id/comments - Get comments (with optional ?chapter=N|general, ?cursor=, ?spoilers=show)
 //   - PUT /comments/:id - Update comment
 //   - DELETE /comments/:id - Delete comment
 //   - POST /comments/:id/like - Like comment
@@ -10,17 +10,25 @@
 package comment
 
 import (
+	"context"
 	"net/http"
 	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"mangahub/internal/auth"
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/models"
 )
 
 // Handler handles HTTP requests for comments
 type Handler struct {
-	svc Service
+	svc   Service
+	prefs SpoilerPreferences
+}
+
+// SpoilerPreferences reads the viewer's show_spoilers preference
+type SpoilerPreferences interface {
+	GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error)
 }
 
 // NewHandler creates a new comment handler
@@ -28,6 +36,24 @@ func NewHandler(svc Service) *Handler {
 	return &Handler{svc: svc}
 }
 
+// SetSpoilerPreferences lets viewers who enabled show_spoilers get spoiler
+// comments unmasked; without it they are masked unless ?spoilers=show
+func (h *Handler) SetSpoilerPreferences(prefs SpoilerPreferences) {
+	h.prefs = prefs
+}
+
+// showSpoilers reports whether spoiler content may be sent to the viewer
+func (h *Handler) showSpoilers(c *gin.Context, viewerID string) bool {
+	if c.Query("spoilers") == models.SpoilersShow {
+		return true
+	}
+	if h.prefs == nil || viewerID == "" {
+		return false
+	}
+	prefs, err := h.prefs.GetPreferences(c.Request.Context(), viewerID)
+	return err == nil && prefs.ShowSpoilers
+}
+
 // CreateComment handles POST /manga/:id/comments
 // Creates a new comment on a manga or chapter
 // Request body: { content, chapter_number?, is_spoiler, parent_id? }
@@ -43,8 +69,7 @@ func (h *Handler) CreateComment(c *gin.Context) {
 	// Get manga ID from URL
 	mangaID := c.Param("id")
 	if mangaID == "" {
-		c.JSON(http.StatusBadRequest,
-			models.NewErrorResponse(models.ErrCodeBadRequest, "manga_id is required", nil))
+		apperrors.Respond(c, apperrors.Validation("manga_id", "manga_id is required"), "")
 		return
 	}
 
@@ -59,13 +84,7 @@ func (h *Handler) CreateComment(c *gin.Context) {
 	// Create comment
 	comment, err := h.svc.Create(c.Request.Context(), user.ID, mangaID, req)
 	if err != nil {
-		if appErr, ok := err.(*models.AppError); ok {
-			c.JSON(appErr.StatusCode,
-				models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
-			return
-		}
-		c.JSON(http.StatusInternalServerError,
-			models.NewErrorResponse(models.ErrCodeInternal, "failed to create comment", nil))
+		apperrors.Respond(c, err, "failed to create comment")
 		return
 	}
 
@@ -75,28 +94,29 @@ func (h *Handler) CreateComment(c *gin.Context) {
 
 // GetComments handles GET /manga/:id/comments
 // Retrieves comments for a manga with optional chapter filter
-// Query params: ?chapter=N&page=1&page_size=20
+// ?chapter=N lists one chapter's discussion, ?chapter=general the chapter-less comments
+// Query params: ?chapter=N&page=1&page_size=20&threaded=true&depth=2&cursor=<next_cursor>
+// Pages are top-level comments, newest first; ?cursor= continues after the previous
+// page (page is then ignored) and stays stable while new comments are posted
 func (h *Handler) GetComments(c *gin.Context) {
 	// Get manga ID from URL
 	mangaID := c.Param("id")
 	if mangaID == "" {
-		c.JSON(http.StatusBadRequest,
-			models.NewErrorResponse(models.ErrCodeBadRequest, "manga_id is required", nil))
+		apperrors.Respond(c, apperrors.Validation("manga_id", "manga_id is required"), "")
 		return
 	}
 
-	// Parse optional chapter number from query
-	var chapterNumber *int
-	if chapterStr := c.Query("chapter"); chapterStr != "" {
-		ch, err := strconv.Atoi(chapterStr)
-		if err == nil {
-			chapterNumber = &ch
-		}
+	// Parse optional chapter filter: ?chapter=N or ?chapter=general
+	filter, err := models.ParseCommentFilter(c.Query("chapter"))
+	if err != nil {
+		apperrors.Respond(c, apperrors.Validation("chapter", err.Error()), "")
+		return
 	}
 
 	// Parse pagination
 	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
 	pageSize, _ := strconv.Atoi(c.DefaultQuery("page_size", "20"))
+	cursor := c.Query("cursor")
 
 	// Get current user ID if authenticated (optional)
 	var currentUserID string
@@ -104,18 +124,21 @@ func (h *Handler) GetComments(c *gin.Context) {
 		currentUserID = user.ID
 	}
 
-	// Get comments
-	response, err := h.svc.GetComments(c.Request.Context(), mangaID, chapterNumber, currentUserID, page, pageSize)
+	// Get comments (threaded returns replies nested up to depth levels)
+	var response *models.CommentListResponse
+	if threaded, _ := strconv.ParseBool(c.Query("threaded")); threaded {
+		depth, _ := strconv.Atoi(c.DefaultQuery("depth", strconv.Itoa(DefaultThreadDepth)))
+		response, err = h.svc.GetThreadedComments(c.Request.Context(), mangaID, filter, currentUserID, cursor, page, pageSize, depth)
+	} else {
+		response, err = h.svc.GetComments(c.Request.Context(), mangaID, filter, currentUserID, cursor, page, pageSize)
+	}
 	if err != nil {
-		if appErr, ok := err.(*models.AppError); ok {
-			c.JSON(appErr.StatusCode,
-				models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
-			return
-		}
-		c.JSON(http.StatusInternalServerError,
-			models.NewErrorResponse(models.ErrCodeInternal, "failed to get comments", nil))
+		apperrors.Respond(c, err, "failed to get comments")
 		return
 	}
+	if !h.showSpoilers(c, currentUserID) {
+		models.MaskCommentSpoilers(response.Comments, currentUserID)
+	}
 
 	c.JSON(http.StatusOK,
 		models.NewSuccessResponse(response, "comments retrieved"))
@@ -136,8 +159,7 @@ func (h *Handler) UpdateComment(c *gin.Context) {
 	// Get comment ID from URL
 	commentID := c.Param("id")
 	if commentID == "" {
-		c.JSON(http.StatusBadRequest,
-			models.NewErrorResponse(models.ErrCodeBadRequest, "comment_id is required", nil))
+		apperrors.Respond(c, apperrors.Validation("comment_id", "comment_id is required"), "")
 		return
 	}
 
@@ -152,13 +174,7 @@ func (h *Handler) UpdateComment(c *gin.Context) {
 	// Update comment
 	comment, err := h.svc.Update(c.Request.Context(), commentID, user.ID, req)
 	if err != nil {
-		if appErr, ok := err.(*models.AppError); ok {
-			c.JSON(appErr.StatusCode,
-				models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
-			return
-		}
-		c.JSON(http.StatusInternalServerError,
-			models.NewErrorResponse(models.ErrCodeInternal, "failed to update comment", nil))
+		apperrors.Respond(c, err, "failed to update comment")
 		return
 	}
 
@@ -167,7 +183,7 @@ func (h *Handler) UpdateComment(c *gin.Context) {
 }
 
 // DeleteComment handles DELETE /comments/:id
-// Soft-deletes a comment (only owner can delete)
+// Soft-deletes a comment (its author, or any comment for admins/moderators)
 func (h *Handler) DeleteComment(c *gin.Context) {
 	// Get authenticated user
 	user := auth.GetCurrentUser(c)
@@ -180,21 +196,14 @@ func (h *Handler) DeleteComment(c *gin.Context) {
 	// Get comment ID from URL
 	commentID := c.Param("id")
 	if commentID == "" {
-		c.JSON(http.StatusBadRequest,
-			models.NewErrorResponse(models.ErrCodeBadRequest, "comment_id is required", nil))
+		apperrors.Respond(c, apperrors.Validation("comment_id", "comment_id is required"), "")
 		return
 	}
 
 	// Delete comment
-	err := h.svc.Delete(c.Request.Context(), commentID, user.ID)
+	err := h.svc.Delete(c.Request.Context(), commentID, user.ID, user.Role)
 	if err != nil {
-		if appErr, ok := err.(*models.AppError); ok {
-			c.JSON(appErr.StatusCode,
-				models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
-			return
-		}
-		c.JSON(http.StatusInternalServerError,
-			models.NewErrorResponse(models.ErrCodeInternal, "failed to delete comment", nil))
+		apperrors.Respond(c, err, "failed to delete comment")
 		return
 	}
 
@@ -219,21 +228,14 @@ func (h *Handler) LikeComment(c *gin.Context) {
 	// Get comment ID from URL
 	commentID := c.Param("id")
 	if commentID == "" {
-		c.JSON(http.StatusBadRequest,
-			models.NewErrorResponse(models.ErrCodeBadRequest, "comment_id is required", nil))
+		apperrors.Respond(c, apperrors.Validation("comment_id", "comment_id is required"), "")
 		return
 	}
 
 	// Like comment
 	err := h.svc.Like(c.Request.Context(), commentID, user.ID)
 	if err != nil {
-		if appErr, ok := err.(*models.AppError); ok {
-			c.JSON(appErr.StatusCode,
-				models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
-			return
-		}
-		c.JSON(http.StatusInternalServerError,
-			models.NewErrorResponse(models.ErrCodeInternal, "failed to like comment", nil))
+		apperrors.Respond(c, err, "failed to like comment")
 		return
 	}
 
@@ -258,21 +260,14 @@ func (h *Handler) UnlikeComment(c *gin.Context) {
 	// Get comment ID from URL
 	commentID := c.Param("id")
 	if commentID == "" {
-		c.JSON(http.StatusBadRequest,
-			models.NewErrorResponse(models.ErrCodeBadRequest, "comment_id is required", nil))
+		apperrors.Respond(c, apperrors.Validation("comment_id", "comment_id is required"), "")
 		return
 	}
 
 	// Unlike comment
 	err := h.svc.Unlike(c.Request.Context(), commentID, user.ID)
 	if err != nil {
-		if appErr, ok := err.(*models.AppError); ok {
-			c.JSON(appErr.StatusCode,
-				models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
-			return
-		}
-		c.JSON(http.StatusInternalServerError,
-			models.NewErrorResponse(models.ErrCodeInternal, "failed to unlike comment", nil))
+		apperrors.Respond(c, err, "failed to unlike comment")
 		return
 	}
 
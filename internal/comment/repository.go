@@ -2,21 +2,29 @@
 // Data access layer cho comment system
 // Chức năng:
 //   - CRUD operations for comments
-//   - Threaded replies support
+//   - Threaded replies support (recursive CTE, không N+1)
 //   - Like/unlike comments
-//   - Pagination for comment lists
+//   - Keyset pagination (created_at, id) for comment lists
+//   - Soft-delete bởi tác giả hoặc moderator, có ghi audit_log
 package comment
 
 import (
 	"context"
 	"database/sql"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/google/uuid"
 	"mangahub/pkg/models"
 )
 
+// ErrInvalidCursor is returned for a ?cursor= value that was not issued by a comment listing
+var ErrInvalidCursor = errors.New("invalid comment cursor")
+
 // Repository defines data access operations for comments
 type Repository interface {
 	// Create creates a new comment
@@ -25,14 +33,28 @@ type Repository interface {
 	// GetByID retrieves a comment by ID
 	GetByID(ctx context.Context, id string) (*models.Comment, error)
 
-	// GetByManga retrieves comments for a manga with optional chapter filter
-	GetByManga(ctx context.Context, mangaID string, chapterNumber *int, limit, offset int) ([]models.CommentWithUser, error)
+	// GetByManga retrieves top-level comments for a manga with optional chapter filter,
+	// continuing after cursor when set; the returned cursor is "" on the last page
+	GetByManga(ctx context.Context, mangaID string, filter models.CommentFilter, cursor string, limit, offset int) ([]models.CommentWithUser, string, error)
 
 	// GetReplies retrieves replies for a comment
 	GetReplies(ctx context.Context, parentID string) ([]models.CommentWithUser, error)
 
+	// GetThreadRoots retrieves top-level comments for threaded display,
+	// keeping deleted comments that still have live replies; paged like GetByManga
+	GetThreadRoots(ctx context.Context, mangaID string, filter models.CommentFilter, cursor string, limit, offset int) ([]models.CommentWithUser, string, error)
+
+	// GetThreadReplies retrieves every reply below the given roots, up to maxDepth levels, in one query
+	GetThreadReplies(ctx context.Context, rootIDs []string, maxDepth int) ([]models.CommentWithUser, error)
+
+	// GetLikedIDs reports which of the given comments a user has liked
+	GetLikedIDs(ctx context.Context, userID string, commentIDs []string) (map[string]bool, error)
+
+	// CountThreadRoots counts the top-level comments GetThreadRoots pages through
+	CountThreadRoots(ctx context.Context, mangaID string, filter models.CommentFilter) (int, error)
+
 	// CountByManga counts total comments for a manga/chapter
-	CountByManga(ctx context.Context, mangaID string, chapterNumber *int) (int, error)
+	CountByManga(ctx context.Context, mangaID string, filter models.CommentFilter) (int, error)
 
 	// Update updates a comment's content
 	Update(ctx context.Context, id, userID string, req models.UpdateCommentRequest) (*models.Comment, error)
@@ -40,6 +62,9 @@ type Repository interface {
 	// Delete soft-deletes a comment (sets is_deleted = true)
 	Delete(ctx context.Context, id, userID string) error
 
+	// DeleteAsModerator soft-deletes any user's comment on behalf of a moderator
+	DeleteAsModerator(ctx context.Context, id, moderatorID string) error
+
 	// Like adds a like to a comment
 	Like(ctx context.Context, commentID, userID string) error
 
@@ -118,43 +143,8 @@ func (r *repository) GetByID(ctx context.Context, id string) (*models.Comment, e
 }
 
 // GetByManga retrieves top-level comments for a manga (optionally filtered by chapter)
-func (r *repository) GetByManga(ctx context.Context, mangaID string, chapterNumber *int, limit, offset int) ([]models.CommentWithUser, error) {
-	var query string
-	var args []interface{}
-
-	// Build query based on whether chapter filter is provided
-	if chapterNumber != nil {
-		query = `
-			SELECT c.id, c.manga_id, c.chapter_number, c.user_id, c.content, c.is_spoiler,
-			       c.parent_id, c.likes_count, c.is_edited, c.is_deleted, c.created_at, c.updated_at,
-			       u.username, u.display_name
-			FROM comments c
-			JOIN users u ON c.user_id = u.id
-			WHERE c.manga_id = ? AND c.chapter_number = ? AND c.parent_id IS NULL AND c.is_deleted = 0
-			ORDER BY c.created_at DESC
-			LIMIT ? OFFSET ?`
-		args = []interface{}{mangaID, *chapterNumber, limit, offset}
-	} else {
-		// Get manga-level comments (where chapter_number is NULL)
-		query = `
-			SELECT c.id, c.manga_id, c.chapter_number, c.user_id, c.content, c.is_spoiler,
-			       c.parent_id, c.likes_count, c.is_edited, c.is_deleted, c.created_at, c.updated_at,
-			       u.username, u.display_name
-			FROM comments c
-			JOIN users u ON c.user_id = u.id
-			WHERE c.manga_id = ? AND c.chapter_number IS NULL AND c.parent_id IS NULL AND c.is_deleted = 0
-			ORDER BY c.created_at DESC
-			LIMIT ? OFFSET ?`
-		args = []interface{}{mangaID, limit, offset}
-	}
-
-	rows, err := r.db.QueryContext(ctx, query, args...)
-	if err != nil {
-		return nil, fmt.Errorf("get comments: %w", err)
-	}
-	defer rows.Close()
-
-	return r.scanComments(rows)
+func (r *repository) GetByManga(ctx context.Context, mangaID string, filter models.CommentFilter, cursor string, limit, offset int) ([]models.CommentWithUser, string, error) {
+	return r.getRoots(ctx, mangaID, filter, " AND c.is_deleted = 0", cursor, limit, offset)
 }
 
 // GetReplies retrieves replies for a parent comment
@@ -176,50 +166,243 @@ func (r *repository) GetReplies(ctx context.Context, parentID string) ([]models.
 	return r.scanComments(rows)
 }
 
-// scanComments is a helper to scan comment rows
-func (r *repository) scanComments(rows *sql.Rows) ([]models.CommentWithUser, error) {
-	var comments []models.CommentWithUser
-	for rows.Next() {
-		var c models.CommentWithUser
-		var chapterNum sql.NullInt64
-		var parentIDStr sql.NullString
-
-		err := rows.Scan(
-			&c.ID, &c.MangaID, &chapterNum, &c.UserID, &c.Content, &c.IsSpoiler,
-			&parentIDStr, &c.LikesCount, &c.IsEdited, &c.IsDeleted, &c.CreatedAt, &c.UpdatedAt,
-			&c.Username, &c.DisplayName,
+// GetThreadRoots retrieves top-level comments for threaded display.
+// Deleted comments are kept when they still have live replies so the thread isn't orphaned.
+func (r *repository) GetThreadRoots(ctx context.Context, mangaID string, filter models.CommentFilter, cursor string, limit, offset int) ([]models.CommentWithUser, string, error) {
+	return r.getRoots(ctx, mangaID, filter, " AND "+liveThreadClause, cursor, limit, offset)
+}
+
+// liveThreadClause keeps comment c unless it is deleted with no live reply at
+// any depth, so replies below a deleted parent and grandparent stay reachable
+const liveThreadClause = `(c.is_deleted = 0 OR EXISTS (
+		WITH RECURSIVE below(id, is_deleted) AS (
+			SELECT id, is_deleted FROM comments WHERE parent_id = c.id
+			UNION ALL
+			SELECT d.id, d.is_deleted FROM comments d JOIN below b ON d.parent_id = b.id
 		)
+		SELECT 1 FROM below WHERE is_deleted = 0))`
+
+// CountThreadRoots counts top-level comments with the same filter as GetThreadRoots
+func (r *repository) CountThreadRoots(ctx context.Context, mangaID string, filter models.CommentFilter) (int, error) {
+	chapterFilter, chapterArgs := chapterClause(filter)
+	query := "SELECT COUNT(*) FROM comments c WHERE c.manga_id = ?" + chapterFilter +
+		" AND c.parent_id IS NULL AND " + liveThreadClause
+	args := append([]interface{}{mangaID}, chapterArgs...)
+
+	var count int
+	if err := r.db.QueryRowContext(ctx, query, args...).Scan(&count); err != nil {
+		return 0, fmt.Errorf("count thread roots: %w", err)
+	}
+	return count, nil
+}
+
+// getRoots loads one page of top-level comments, newest first, plus the cursor for the next page.
+// With a cursor the page starts strictly after that comment, so comments posted in the
+// meantime land before it instead of shifting every later page the way offsets do.
+func (r *repository) getRoots(ctx context.Context, mangaID string, filter models.CommentFilter, deletedClause, cursor string, limit, offset int) ([]models.CommentWithUser, string, error) {
+	chapterFilter, chapterArgs := chapterClause(filter)
+	where := "c.manga_id = ?" + chapterFilter + " AND c.parent_id IS NULL" + deletedClause
+	args := append([]interface{}{mangaID}, chapterArgs...)
+
+	if cursor != "" {
+		createdAt, id, err := decodeCursor(cursor)
 		if err != nil {
-			return nil, fmt.Errorf("scan comment: %w", err)
+			return nil, "", err
 		}
+		// created_at <= ? keeps the range scan on idx_comments_manga_created; id breaks ties
+		where += " AND c.created_at <= ? AND (c.created_at < ? OR c.id < ?)"
+		args = append(args, createdAt, createdAt, id)
+	}
+	// One extra row tells whether another page follows
+	args = append(args, limit+1, offset)
 
-		if chapterNum.Valid {
-			ch := int(chapterNum.Int64)
-			c.ChapterNumber = &ch
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.id, c.manga_id, c.chapter_number, c.user_id, c.content, c.is_spoiler,
+		       c.parent_id, c.likes_count, c.is_edited, c.is_deleted, c.created_at, c.updated_at,
+		       u.username, u.display_name, CAST(c.created_at AS TEXT)
+		FROM comments c
+		JOIN users u ON c.user_id = u.id
+		WHERE `+where+`
+		ORDER BY c.created_at DESC, c.id DESC
+		LIMIT ? OFFSET ?`, args...,
+	)
+	if err != nil {
+		return nil, "", fmt.Errorf("get comments: %w", err)
+	}
+	defer rows.Close()
+
+	var comments []models.CommentWithUser
+	var next, lastCreatedAt string
+	for rows.Next() {
+		if len(comments) == limit {
+			next = encodeCursor(lastCreatedAt, comments[len(comments)-1].ID)
+			break
 		}
-		if parentIDStr.Valid {
-			c.ParentID = &parentIDStr.String
+		c, err := scanComment(rows, &lastCreatedAt)
+		if err != nil {
+			return nil, "", err
 		}
-		// Avatar can be generated from external service (Gravatar, etc.)
-		c.AvatarURL = ""
+		comments = append(comments, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, "", fmt.Errorf("iterate comments: %w", err)
+	}
+	return comments, next, nil
+}
+
+// encodeCursor packs a comment's stored created_at text and id into an opaque cursor.
+// The raw text is kept so the cursor compares equal to what SQLite holds.
+func encodeCursor(createdAt, id string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(createdAt + "|" + id))
+}
+
+// decodeCursor unpacks a cursor made by encodeCursor
+func decodeCursor(cursor string) (string, string, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", "", ErrInvalidCursor
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok || createdAt == "" || id == "" {
+		return "", "", ErrInvalidCursor
+	}
+	return createdAt, id, nil
+}
 
+// GetThreadReplies retrieves every reply below the given roots, up to maxDepth levels.
+// Uses a recursive CTE so the whole page of threads loads in a single query.
+func (r *repository) GetThreadReplies(ctx context.Context, rootIDs []string, maxDepth int) ([]models.CommentWithUser, error) {
+	if len(rootIDs) == 0 || maxDepth < 1 {
+		return nil, nil
+	}
+
+	args := make([]interface{}, 0, len(rootIDs)+1)
+	for _, id := range rootIDs {
+		args = append(args, id)
+	}
+	args = append(args, maxDepth)
+
+	rows, err := r.db.QueryContext(ctx, `
+		WITH RECURSIVE thread(id, depth) AS (
+			SELECT id, 1 FROM comments WHERE parent_id IN (`+placeholders(len(rootIDs))+`)
+			UNION ALL
+			SELECT c.id, t.depth + 1
+			FROM comments c
+			JOIN thread t ON c.parent_id = t.id
+			WHERE t.depth < ?
+		)
+		SELECT c.id, c.manga_id, c.chapter_number, c.user_id, c.content, c.is_spoiler,
+		       c.parent_id, c.likes_count, c.is_edited, c.is_deleted, c.created_at, c.updated_at,
+		       u.username, u.display_name
+		FROM thread t
+		JOIN comments c ON c.id = t.id
+		JOIN users u ON c.user_id = u.id
+		WHERE `+liveThreadClause+`
+		ORDER BY c.created_at ASC`, args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get thread replies: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanComments(rows)
+}
+
+// GetLikedIDs reports which of the given comments a user has liked
+func (r *repository) GetLikedIDs(ctx context.Context, userID string, commentIDs []string) (map[string]bool, error) {
+	liked := make(map[string]bool)
+	if userID == "" || len(commentIDs) == 0 {
+		return liked, nil
+	}
+
+	args := make([]interface{}, 0, len(commentIDs)+1)
+	args = append(args, userID)
+	for _, id := range commentIDs {
+		args = append(args, id)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT comment_id FROM comment_likes
+		WHERE user_id = ? AND comment_id IN (`+placeholders(len(commentIDs))+`)`, args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get liked comments: %w", err)
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan liked comment: %w", err)
+		}
+		liked[id] = true
+	}
+	return liked, rows.Err()
+}
+
+// chapterClause returns the SQL condition on comments alias c for a chapter filter.
+// Chapter filters compare (manga_id, chapter_number) so idx_comments_chapter is used.
+func chapterClause(filter models.CommentFilter) (string, []interface{}) {
+	switch {
+	case filter.Chapter != nil:
+		return " AND c.chapter_number = ?", []interface{}{*filter.Chapter}
+	case filter.General:
+		return " AND c.chapter_number IS NULL", nil
+	}
+	return "", nil
+}
+
+// placeholders returns "?, ?, ..." for an IN clause
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?, ", n), ", ")
+}
+
+// scanComments is a helper to scan comment rows
+func (r *repository) scanComments(rows *sql.Rows) ([]models.CommentWithUser, error) {
+	var comments []models.CommentWithUser
+	for rows.Next() {
+		c, err := scanComment(rows)
+		if err != nil {
+			return nil, err
+		}
 		comments = append(comments, c)
 	}
 	return comments, nil
 }
 
-// CountByManga counts total comments for a manga/chapter
-func (r *repository) CountByManga(ctx context.Context, mangaID string, chapterNumber *int) (int, error) {
-	var query string
-	var args []interface{}
+// scanComment scans one comment row; extra receives any columns selected after display_name
+func scanComment(rows *sql.Rows, extra ...interface{}) (models.CommentWithUser, error) {
+	var c models.CommentWithUser
+	var chapterNum sql.NullInt64
+	var parentIDStr sql.NullString
 
-	if chapterNumber != nil {
-		query = "SELECT COUNT(*) FROM comments WHERE manga_id = ? AND chapter_number = ? AND is_deleted = 0"
-		args = []interface{}{mangaID, *chapterNumber}
-	} else {
-		query = "SELECT COUNT(*) FROM comments WHERE manga_id = ? AND chapter_number IS NULL AND is_deleted = 0"
-		args = []interface{}{mangaID}
+	dest := []interface{}{
+		&c.ID, &c.MangaID, &chapterNum, &c.UserID, &c.Content, &c.IsSpoiler,
+		&parentIDStr, &c.LikesCount, &c.IsEdited, &c.IsDeleted, &c.CreatedAt, &c.UpdatedAt,
+		&c.Username, &c.DisplayName,
+	}
+	if err := rows.Scan(append(dest, extra...)...); err != nil {
+		return c, fmt.Errorf("scan comment: %w", err)
+	}
+
+	if chapterNum.Valid {
+		ch := int(chapterNum.Int64)
+		c.ChapterNumber = &ch
 	}
+	if parentIDStr.Valid {
+		c.ParentID = &parentIDStr.String
+	}
+	// Avatar can be generated from external service (Gravatar, etc.)
+	c.AvatarURL = ""
+
+	return c, nil
+}
+
+// CountByManga counts total comments for a manga/chapter
+func (r *repository) CountByManga(ctx context.Context, mangaID string, filter models.CommentFilter) (int, error) {
+	chapterFilter, chapterArgs := chapterClause(filter)
+	query := "SELECT COUNT(*) FROM comments c WHERE c.manga_id = ?" + chapterFilter + " AND c.is_deleted = 0"
+	args := append([]interface{}{mangaID}, chapterArgs...)
 
 	var count int
 	err := r.db.QueryRowContext(ctx, query, args...).Scan(&count)
@@ -253,19 +436,67 @@ func (r *repository) Update(ctx context.Context, id, userID string, req models.U
 
 // Delete soft-deletes a comment (only owner can delete)
 func (r *repository) Delete(ctx context.Context, id, userID string) error {
-	result, err := r.db.ExecContext(ctx, `
+	return r.softDelete(ctx, id, userID, "comment.delete", true)
+}
+
+// DeleteAsModerator soft-deletes any user's comment on behalf of a moderator
+func (r *repository) DeleteAsModerator(ctx context.Context, id, moderatorID string) error {
+	return r.softDelete(ctx, id, moderatorID, "comment.moderate", false)
+}
+
+// softDelete blanks a comment but keeps the row so replies stay attached.
+// Likes are left alone so likes_count and the like triggers stay consistent;
+// the activity_feed row is kept but its copy of the text is scrubbed too.
+func (r *repository) softDelete(ctx context.Context, id, actorID, action string, ownerOnly bool) error {
+	now := time.Now()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin delete comment: %w", err)
+	}
+	defer tx.Rollback()
+
+	var authorID, mangaID string
+	err = tx.QueryRowContext(ctx, `
+		SELECT user_id, manga_id FROM comments WHERE id = ? AND is_deleted = 0`, id,
+	).Scan(&authorID, &mangaID)
+	if err == sql.ErrNoRows || (err == nil && ownerOnly && authorID != actorID) {
+		return fmt.Errorf("comment not found or not owned by user")
+	}
+	if err != nil {
+		return fmt.Errorf("get comment: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
 		UPDATE comments 
-		SET is_deleted = 1, content = '[deleted]', updated_at = ?
-		WHERE id = ? AND user_id = ?`,
-		time.Now(), id, userID,
+		SET is_deleted = 1, content = ?, updated_at = ?
+		WHERE id = ?`,
+		models.DeletedCommentText, now, id,
 	)
 	if err != nil {
 		return fmt.Errorf("delete comment: %w", err)
 	}
 
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("comment not found or not owned by user")
+	_, err = tx.ExecContext(ctx, `
+		UPDATE activity_feed SET comment_text = ? WHERE id = 'act-' || ?`,
+		models.DeletedCommentText, id,
+	)
+	if err != nil {
+		return fmt.Errorf("scrub comment activity: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]string{"author_id": authorID, "manga_id": mangaID})
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO audit_log (id, actor_id, action, target_type, target_id, details, created_at)
+		VALUES (?, ?, ?, 'comment', ?, ?, ?)`,
+		uuid.New().String(), actorID, action, id, string(details), now,
+	)
+	if err != nil {
+		return fmt.Errorf("insert audit log: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit delete comment: %w", err)
 	}
 	return nil
 }
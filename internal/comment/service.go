@@ -4,13 +4,18 @@
 //   - Validate comment requests
 //   - Build comment threads with replies
 //   - Coordinate likes/unlikes
-//   - Handle pagination
+//   - Handle pagination (cursor, or page offsets for old clients)
+//   - Authorize deletes (author, admin hoặc moderator)
+//   - Wordlist filter: mask hoặc từ chối nội dung khi tạo/sửa comment
 package comment
 
 import (
 	"context"
+	"errors"
 
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/models"
+	"mangahub/pkg/moderation"
 	"mangahub/pkg/utils"
 )
 
@@ -19,14 +24,19 @@ type Service interface {
 	// Create creates a new comment
 	Create(ctx context.Context, userID, mangaID string, req models.CreateCommentRequest) (*models.Comment, error)
 
-	// GetComments retrieves comments for a manga with optional chapter filter
-	GetComments(ctx context.Context, mangaID string, chapterNumber *int, currentUserID string, page, pageSize int) (*models.CommentListResponse, error)
+	// GetComments retrieves comments for a manga with optional chapter filter.
+	// A cursor from a previous response takes precedence over page.
+	GetComments(ctx context.Context, mangaID string, filter models.CommentFilter, currentUserID, cursor string, page, pageSize int) (*models.CommentListResponse, error)
+
+	// GetThreadedComments retrieves top-level comments with replies nested up to maxDepth levels.
+	// Pages count top-level comments only; every page carries its threads' replies.
+	GetThreadedComments(ctx context.Context, mangaID string, filter models.CommentFilter, currentUserID, cursor string, page, pageSize, maxDepth int) (*models.CommentListResponse, error)
 
 	// Update updates a comment's content
 	Update(ctx context.Context, id, userID string, req models.UpdateCommentRequest) (*models.Comment, error)
 
-	// Delete soft-deletes a comment
-	Delete(ctx context.Context, id, userID string) error
+	// Delete soft-deletes a comment written by userID, or any comment if role is admin/moderator
+	Delete(ctx context.Context, id, userID, role string) error
 
 	// Like adds a like to a comment
 	Like(ctx context.Context, commentID, userID string) error
@@ -35,8 +45,15 @@ type Service interface {
 	Unlike(ctx context.Context, commentID, userID string) error
 }
 
+// Reply depth limits for threaded comments
+const (
+	DefaultThreadDepth = 2
+	MaxThreadDepth     = 5
+)
+
 type service struct {
-	repo Repository
+	repo   Repository
+	filter *moderation.Filter // nil: content is not filtered
 }
 
 // NewService creates a new comment service
@@ -44,39 +61,64 @@ func NewService(repo Repository) Service {
 	return &service{repo: repo}
 }
 
+// NewServiceWithFilter creates a comment service that masks or rejects
+// content matching the moderation wordlist
+func NewServiceWithFilter(repo Repository, filter *moderation.Filter) Service {
+	return &service{repo: repo, filter: filter}
+}
+
+// moderate applies the wordlist to comment content
+func (s *service) moderate(content string) (string, error) {
+	content, blocked := s.filter.Apply(content)
+	if blocked {
+		return "", apperrors.Validation("content", "comment contains language that is not allowed")
+	}
+	return content, nil
+}
+
 // Create creates a new comment after validation
 func (s *service) Create(ctx context.Context, userID, mangaID string, req models.CreateCommentRequest) (*models.Comment, error) {
 	// Validate request
 	if err := utils.ValidateStruct(req); err != nil {
-		return nil, models.NewAppError(models.ErrCodeValidation, "invalid comment data", 400, err)
+		return nil, apperrors.Invalid("invalid comment data", err)
 	}
 
 	// Validate content length
 	if len(req.Content) < 1 || len(req.Content) > 2000 {
-		return nil, models.NewAppError(models.ErrCodeValidation, "comment must be 1-2000 characters", 400, nil)
+		return nil, apperrors.Validation("content", "comment must be 1-2000 characters")
 	}
+	content, err := s.moderate(req.Content)
+	if err != nil {
+		return nil, err
+	}
+	req.Content = content
 
 	// If replying, verify parent exists
 	if req.ParentID != "" {
 		parent, err := s.repo.GetByID(ctx, req.ParentID)
 		if err != nil {
-			return nil, models.NewAppError(models.ErrCodeInternal, "failed to verify parent comment", 500, err)
+			return nil, apperrors.Internal("failed to verify parent comment", err)
 		}
 		if parent == nil {
-			return nil, models.NewAppError(models.ErrCodeNotFound, "parent comment not found", 404, nil)
+			return nil, apperrors.NotFound("parent comment not found", nil)
 		}
+		// Replies stay in their thread's chapter bucket
+		req.ChapterNumber = parent.ChapterNumber
+	}
+	if req.ChapterNumber != nil && *req.ChapterNumber < 0 {
+		return nil, apperrors.Validation("chapter_number", "chapter_number must not be negative")
 	}
 
 	comment, err := s.repo.Create(ctx, userID, mangaID, req)
 	if err != nil {
-		return nil, models.NewAppError(models.ErrCodeInternal, "failed to create comment", 500, err)
+		return nil, apperrors.Internal("failed to create comment", err)
 	}
 
 	return comment, nil
 }
 
 // GetComments retrieves comments with pagination and nested replies
-func (s *service) GetComments(ctx context.Context, mangaID string, chapterNumber *int, currentUserID string, page, pageSize int) (*models.CommentListResponse, error) {
+func (s *service) GetComments(ctx context.Context, mangaID string, filter models.CommentFilter, currentUserID, cursor string, page, pageSize int) (*models.CommentListResponse, error) {
 	// Default pagination values
 	if page < 1 {
 		page = 1
@@ -88,18 +130,18 @@ func (s *service) GetComments(ctx context.Context, mangaID string, chapterNumber
 		pageSize = 50
 	}
 
-	offset := (page - 1) * pageSize
+	offset := pageOffset(cursor, page, pageSize)
 
 	// Get total count
-	totalCount, err := s.repo.CountByManga(ctx, mangaID, chapterNumber)
+	totalCount, err := s.repo.CountByManga(ctx, mangaID, filter)
 	if err != nil {
-		return nil, models.NewAppError(models.ErrCodeInternal, "failed to count comments", 500, err)
+		return nil, apperrors.Internal("failed to count comments", err)
 	}
 
 	// Get top-level comments
-	comments, err := s.repo.GetByManga(ctx, mangaID, chapterNumber, pageSize, offset)
+	comments, next, err := s.repo.GetByManga(ctx, mangaID, filter, cursor, pageSize, offset)
 	if err != nil {
-		return nil, models.NewAppError(models.ErrCodeInternal, "failed to get comments", 500, err)
+		return nil, pageError(err)
 	}
 
 	// Build response with nested replies
@@ -118,14 +160,14 @@ func (s *service) GetComments(ctx context.Context, mangaID string, chapterNumber
 		// Get replies for this comment
 		replies, err := s.repo.GetReplies(ctx, c.ID)
 		if err == nil && len(replies) > 0 {
-			// Check like status for replies too
-			if currentUserID != "" {
-				for i := range replies {
+			for i := range replies {
+				// Check like status for replies too
+				if currentUserID != "" {
 					liked, _ := s.repo.HasLiked(ctx, replies[i].ID, currentUserID)
 					replies[i].LikedByMe = liked
 				}
+				cwr.Replies = append(cwr.Replies, models.CommentWithReplies{CommentWithUser: replies[i]})
 			}
-			cwr.Replies = replies
 		}
 
 		commentsWithReplies = append(commentsWithReplies, cwr)
@@ -136,30 +178,156 @@ func (s *service) GetComments(ctx context.Context, mangaID string, chapterNumber
 		TotalCount: totalCount,
 		Page:       page,
 		PageSize:   pageSize,
-		HasMore:    offset+len(comments) < totalCount,
+		HasMore:    next != "",
+		NextCursor: next,
 	}, nil
 }
 
+// GetThreadedComments retrieves top-level comments with replies nested up to maxDepth levels.
+// Replies and like status load in one query each, regardless of thread size.
+// Deleted comments with live replies at any depth are shown as "[deleted]".
+func (s *service) GetThreadedComments(ctx context.Context, mangaID string, filter models.CommentFilter, currentUserID, cursor string, page, pageSize, maxDepth int) (*models.CommentListResponse, error) {
+	if page < 1 {
+		page = 1
+	}
+	if pageSize <= 0 {
+		pageSize = 20
+	}
+	if pageSize > 50 {
+		pageSize = 50
+	}
+	if maxDepth <= 0 {
+		maxDepth = DefaultThreadDepth
+	}
+	if maxDepth > MaxThreadDepth {
+		maxDepth = MaxThreadDepth
+	}
+
+	offset := pageOffset(cursor, page, pageSize)
+
+	totalCount, err := s.repo.CountThreadRoots(ctx, mangaID, filter)
+	if err != nil {
+		return nil, apperrors.Internal("failed to count comments", err)
+	}
+
+	roots, next, err := s.repo.GetThreadRoots(ctx, mangaID, filter, cursor, pageSize, offset)
+	if err != nil {
+		return nil, pageError(err)
+	}
+
+	rootIDs := make([]string, len(roots))
+	for i, c := range roots {
+		rootIDs[i] = c.ID
+	}
+	replies, err := s.repo.GetThreadReplies(ctx, rootIDs, maxDepth)
+	if err != nil {
+		return nil, apperrors.Internal("failed to get replies", err)
+	}
+
+	// Like status for every comment on the page in one lookup
+	allIDs := append([]string{}, rootIDs...)
+	for _, r := range replies {
+		allIDs = append(allIDs, r.ID)
+	}
+	liked, err := s.repo.GetLikedIDs(ctx, currentUserID, allIDs)
+	if err != nil {
+		return nil, apperrors.Internal("failed to get likes", err)
+	}
+
+	// Group replies by parent; replies arrive oldest first
+	children := make(map[string][]models.CommentWithUser)
+	for _, r := range replies {
+		if r.ParentID != nil {
+			children[*r.ParentID] = append(children[*r.ParentID], r)
+		}
+	}
+
+	var build func(c models.CommentWithUser) models.CommentWithReplies
+	build = func(c models.CommentWithUser) models.CommentWithReplies {
+		c.LikedByMe = liked[c.ID]
+		if c.IsDeleted {
+			c.Content = models.DeletedCommentText
+			c.Username = models.DeletedCommentText
+			c.DisplayName = ""
+		}
+		node := models.CommentWithReplies{CommentWithUser: c}
+		for _, child := range children[c.ID] {
+			node.Replies = append(node.Replies, build(child))
+		}
+		return node
+	}
+
+	threads := make([]models.CommentWithReplies, 0, len(roots))
+	for _, c := range roots {
+		threads = append(threads, build(c))
+	}
+
+	return &models.CommentListResponse{
+		Comments:   threads,
+		TotalCount: totalCount,
+		Page:       page,
+		PageSize:   pageSize,
+		HasMore:    next != "",
+		NextCursor: next,
+	}, nil
+}
+
+// pageOffset returns the row offset for page; a cursor already marks where the page starts
+func pageOffset(cursor string, page, pageSize int) int {
+	if cursor != "" {
+		return 0
+	}
+	return (page - 1) * pageSize
+}
+
+// pageError maps a failed page load to an AppError
+func pageError(err error) error {
+	if errors.Is(err, ErrInvalidCursor) {
+		return apperrors.Validation("cursor", err.Error())
+	}
+	return apperrors.Internal("failed to get comments", err)
+}
+
 // Update updates a comment's content
 func (s *service) Update(ctx context.Context, id, userID string, req models.UpdateCommentRequest) (*models.Comment, error) {
 	// Validate request
 	if err := utils.ValidateStruct(req); err != nil {
-		return nil, models.NewAppError(models.ErrCodeValidation, "invalid comment data", 400, err)
+		return nil, apperrors.Invalid("invalid comment data", err)
+	}
+	content, err := s.moderate(req.Content)
+	if err != nil {
+		return nil, err
 	}
+	req.Content = content
 
 	comment, err := s.repo.Update(ctx, id, userID, req)
 	if err != nil {
-		return nil, models.NewAppError(models.ErrCodeNotFound, "comment not found or not owned by you", 404, err)
+		return nil, apperrors.NotFound("comment not found or not owned by you", err)
 	}
 
 	return comment, nil
 }
 
-// Delete soft-deletes a comment
-func (s *service) Delete(ctx context.Context, id, userID string) error {
-	err := s.repo.Delete(ctx, id, userID)
+// Delete soft-deletes a comment written by userID, or any comment if role is admin/moderator
+func (s *service) Delete(ctx context.Context, id, userID, role string) error {
+	comment, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return apperrors.Internal("failed to get comment", err)
+	}
+	if comment == nil || comment.IsDeleted {
+		return apperrors.NotFound("comment not found", nil)
+	}
+
+	switch {
+	case comment.UserID == userID:
+		err = s.repo.Delete(ctx, id, userID)
+	case models.IsModerator(role):
+		err = s.repo.DeleteAsModerator(ctx, id, userID)
+	default:
+		return apperrors.Forbidden("you can only delete your own comments", nil)
+	}
 	if err != nil {
-		return models.NewAppError(models.ErrCodeNotFound, "comment not found or not owned by you", 404, err)
+		return apperrors.NotFound("comment not found", err)
 	}
 	return nil
 }
@@ -169,15 +337,15 @@ func (s *service) Like(ctx context.Context, commentID, userID string) error {
 	// Verify comment exists
 	comment, err := s.repo.GetByID(ctx, commentID)
 	if err != nil {
-		return models.NewAppError(models.ErrCodeInternal, "failed to get comment", 500, err)
+		return apperrors.Internal("failed to get comment", err)
 	}
 	if comment == nil {
-		return models.NewAppError(models.ErrCodeNotFound, "comment not found", 404, nil)
+		return apperrors.NotFound("comment not found", nil)
 	}
 
 	err = s.repo.Like(ctx, commentID, userID)
 	if err != nil {
-		return models.NewAppError(models.ErrCodeInternal, "failed to like comment", 500, err)
+		return apperrors.Internal("failed to like comment", err)
 	}
 	return nil
 }
@@ -186,7 +354,7 @@ func (s *service) Like(ctx context.Context, commentID, userID string) error {
 func (s *service) Unlike(ctx context.Context, commentID, userID string) error {
 	err := s.repo.Unlike(ctx, commentID, userID)
 	if err != nil {
-		return models.NewAppError(models.ErrCodeNotFound, "like not found", 404, err)
+		return apperrors.NotFound("like not found", err)
 	}
 	return nil
 }
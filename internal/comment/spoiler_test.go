@@ -0,0 +1,83 @@
+// Package comment - Spoiler Masking Tests
+// Unit tests cho GET /manga/:id/comments: spoiler bị che theo preference của người xem
+package comment
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"mangahub/internal/auth"
+	"mangahub/pkg/models"
+)
+
+// fakeSpoilerPrefs enables show_spoilers for the listed users
+type fakeSpoilerPrefs map[string]bool
+
+func (f fakeSpoilerPrefs) GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	prefs := models.DefaultUserPreferences()
+	prefs.ShowSpoilers = f[userID]
+	return &prefs, nil
+}
+
+func TestGetCommentsMasksSpoilers(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewRepository(db)
+	ctx := context.Background()
+	root, err := repo.Create(ctx, "user1", "manga1", models.CreateCommentRequest{Content: "Guts survives", IsSpoiler: true})
+	if err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	repo.Create(ctx, "user2", "manga1", models.CreateCommentRequest{Content: "Casca too", IsSpoiler: true, ParentID: root.ID})
+
+	handler := NewHandler(NewService(repo))
+	handler.SetSpoilerPreferences(fakeSpoilerPrefs{"user3": true})
+
+	list := func(viewerID, query string) (rootText, replyText string, rootMasked, replyMasked bool) {
+		t.Helper()
+		router := gin.New()
+		router.GET("/manga/:id/comments", func(c *gin.Context) {
+			if viewerID != "" {
+				c.Set(auth.ContextUserKey, &models.UserProfile{ID: viewerID})
+			}
+		}, handler.GetComments)
+
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/manga/manga1/comments?threaded=true"+query, nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("status = %d: %s", w.Code, w.Body.String())
+		}
+		var resp struct {
+			Data models.CommentListResponse `json:"data"`
+		}
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode: %v", err)
+		}
+		if len(resp.Data.Comments) != 1 || len(resp.Data.Comments[0].Replies) != 1 {
+			t.Fatalf("unexpected thread: %+v", resp.Data.Comments)
+		}
+		top, reply := resp.Data.Comments[0], resp.Data.Comments[0].Replies[0]
+		return top.Content, reply.Content, top.SpoilerMasked, reply.SpoilerMasked
+	}
+
+	// Guests get neither text
+	if rt, pt, rm, pm := list("", ""); rt != "" || pt != "" || !rm || !pm {
+		t.Errorf("guest: got %q/%q masked %v/%v, want both masked", rt, pt, rm, pm)
+	}
+	// The author still sees their own spoiler
+	if rt, pt, rm, pm := list("user1", ""); rt != "Guts survives" || rm || pt != "" || !pm {
+		t.Errorf("author: got %q/%q masked %v/%v, want own comment only", rt, pt, rm, pm)
+	}
+	// show_spoilers and ?spoilers=show both unmask
+	for name, query := range map[string]string{"user3": "", "": "&spoilers=show"} {
+		if rt, pt, rm, pm := list(name, query); rt != "Guts survives" || pt != "Casca too" || rm || pm {
+			t.Errorf("viewer %q query %q: got %q/%q masked %v/%v, want both shown", name, query, rt, pt, rm, pm)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package customlist - Custom Lists Tests
+// Unit tests cho list CRUD, "already in list" và quyền xem list public
+package customlist
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+// setupTestDB opens the shared test database and seeds the rows these tests use
+func setupTestDB(t *testing.T) *sql.DB {
+	db := testutil.OpenDB(t)
+
+	for _, q := range []string{
+		`INSERT INTO users (id, username, email, password_hash, display_name) VALUES
+			('owner', 'owner', 'o@example.com', 'x', 'Owner'),
+			('other', 'other', 'x@example.com', 'x', 'Other')`,
+		`INSERT INTO manga (id, title) VALUES ('m1', 'Berserk'), ('m2', 'Vagabond'), ('m3', 'Monster')`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("seed failed: %v", err)
+		}
+	}
+
+	return db
+}
+
+// statusOf returns the HTTP status of an AppError, 0 for nil
+func statusOf(t *testing.T, err error) int {
+	t.Helper()
+	if err == nil {
+		return 0
+	}
+	appErr, ok := err.(*models.AppError)
+	if !ok {
+		t.Fatalf("expected *models.AppError, got %T: %v", err, err)
+	}
+	return appErr.StatusCode
+}
+
+func TestCustomListItems(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)))
+	ctx := context.Background()
+
+	list, err := svc.CreateList(ctx, "owner", models.CreateListRequest{Name: "Top 3", Icon: "🏆"})
+	if err != nil {
+		t.Fatalf("CreateList failed: %v", err)
+	}
+
+	var itemIDs []string
+	for _, mangaID := range []string{"m1", "m2", "m3"} {
+		item, err := svc.AddItem(ctx, list.ID, "owner", models.AddToListRequest{MangaID: mangaID})
+		if err != nil {
+			t.Fatalf("AddItem(%s) failed: %v", mangaID, err)
+		}
+		itemIDs = append(itemIDs, item.ID)
+	}
+
+	// The UNIQUE(list_id, manga_id) constraint surfaces as a conflict, not a 500
+	_, err = svc.AddItem(ctx, list.ID, "owner", models.AddToListRequest{MangaID: "m1"})
+	if status := statusOf(t, err); status != 409 {
+		t.Errorf("expected 409 for duplicate manga, got %d (%v)", status, err)
+	}
+	_, err = svc.AddItem(ctx, list.ID, "owner", models.AddToListRequest{MangaID: "missing"})
+	if status := statusOf(t, err); status != 404 {
+		t.Errorf("expected 404 for unknown manga, got %d", status)
+	}
+
+	// Reverse the order, then drop the middle item
+	if err := svc.ReorderItems(ctx, list.ID, "owner", models.ReorderListRequest{
+		ItemIDs: []string{itemIDs[2], itemIDs[1], itemIDs[0]},
+	}); err != nil {
+		t.Fatalf("ReorderItems failed: %v", err)
+	}
+	if err := svc.RemoveItem(ctx, list.ID, "owner", "m2"); err != nil {
+		t.Fatalf("RemoveItem failed: %v", err)
+	}
+
+	got, err := svc.GetList(ctx, list.ID, "owner")
+	if err != nil {
+		t.Fatalf("GetList failed: %v", err)
+	}
+	if len(got.Items) != 2 || got.Items[0].MangaID != "m3" || got.Items[1].MangaID != "m1" {
+		t.Errorf("unexpected items after reorder/remove: %+v", got.Items)
+	}
+	if got.ItemCount != 2 || got.Icon != "🏆" {
+		t.Errorf("unexpected list metadata: count=%d icon=%q", got.ItemCount, got.Icon)
+	}
+
+	// Item IDs from another list are rejected without changing anything
+	err = svc.ReorderItems(ctx, list.ID, "owner", models.ReorderListRequest{ItemIDs: []string{"bogus"}})
+	if status := statusOf(t, err); status != 400 {
+		t.Errorf("expected 400 for foreign item IDs, got %d", status)
+	}
+}
+
+func TestCustomListVisibility(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)))
+	ctx := context.Background()
+
+	list, err := svc.CreateList(ctx, "owner", models.CreateListRequest{Name: "Secret"})
+	if err != nil {
+		t.Fatalf("CreateList failed: %v", err)
+	}
+
+	// Private: hidden from other users and anonymous viewers
+	for _, viewer := range []string{"other", ""} {
+		if _, err := svc.GetList(ctx, list.ID, viewer); statusOf(t, err) != 404 {
+			t.Errorf("viewer %q should not see a private list", viewer)
+		}
+	}
+	if _, err := svc.AddItem(ctx, list.ID, "other", models.AddToListRequest{MangaID: "m1"}); statusOf(t, err) != 404 {
+		t.Errorf("other user should not find a private list to modify")
+	}
+
+	public := true
+	if _, err := svc.UpdateList(ctx, list.ID, "owner", models.UpdateListRequest{IsPublic: &public}); err != nil {
+		t.Fatalf("UpdateList failed: %v", err)
+	}
+
+	// Public: viewable by anyone, still only editable by the owner
+	if _, err := svc.GetList(ctx, list.ID, ""); err != nil {
+		t.Errorf("anonymous viewer should see a public list: %v", err)
+	}
+	if err := svc.DeleteList(ctx, list.ID, "other"); statusOf(t, err) != 403 {
+		t.Errorf("other user should be forbidden from deleting a public list")
+	}
+	if err := svc.DeleteList(ctx, list.ID, "owner"); err != nil {
+		t.Errorf("owner should delete the list: %v", err)
+	}
+}
@@ -1,237 +1,227 @@
 // Package customlist - Custom Lists HTTP Handlers
-// REST API endpoints for custom manga lists
+// HTTP handlers cho custom list API endpoints
+// Endpoints:
+//   - GET /users/lists - List the user's lists
+//   - POST /users/lists - Create a list
+//   - GET /users/lists/:id - Get a list with its items
+//   - PUT /users/lists/:id - Update name, description, icon or is_public
+//   - DELETE /users/lists/:id - Delete a list
+//   - POST /users/lists/:id/items - Add a manga
+//   - PUT /users/lists/:id/items - Reorder items
+//   - DELETE /users/lists/:id/items/:manga_id - Remove a manga
+//   - GET /lists/:id - View a public list (no auth)
 package customlist
 
 import (
-	"encoding/json"
 	"net/http"
 
+	"github.com/gin-gonic/gin"
+	"mangahub/internal/auth"
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/models"
 )
 
 // Handler handles HTTP requests for custom lists
 type Handler struct {
-	service *Service
+	svc Service
 }
 
 // NewHandler creates a new custom list handler
-func NewHandler(service *Service) *Handler {
-	return &Handler{service: service}
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
 }
 
-// GetUserLists returns all lists for the current user
-// GET /api/v1/lists
-func (h *Handler) GetUserLists(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	userID := getUserIDFromContext(ctx)
-	if userID == "" {
-		respondError(w, http.StatusUnauthorized, "Unauthorized")
+// GetUserLists handles GET /users/lists
+func (h *Handler) GetUserLists(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
 		return
 	}
 
-	lists, err := h.service.GetUserLists(ctx, userID)
+	lists, err := h.svc.GetUserLists(c.Request.Context(), user.ID)
 	if err != nil {
-		respondError(w, http.StatusInternalServerError, "Failed to get lists")
+		apperrors.Respond(c, err, "failed to get lists")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, lists)
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(lists, "lists retrieved successfully"))
 }
 
-// CreateList creates a new custom list
-// POST /api/v1/lists
-func (h *Handler) CreateList(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	userID := getUserIDFromContext(ctx)
-	if userID == "" {
-		respondError(w, http.StatusUnauthorized, "Unauthorized")
+// CreateList handles POST /users/lists
+// Request body: { name, description?, icon?, is_public }
+func (h *Handler) CreateList(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
 		return
 	}
 
 	var req models.CreateListRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeBadRequest, "invalid JSON body", map[string]interface{}{"error": err.Error()}))
 		return
 	}
 
-	list, err := h.service.CreateList(ctx, userID, &req)
+	list, err := h.svc.CreateList(c.Request.Context(), user.ID, req)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		apperrors.Respond(c, err, "failed to create list")
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, list)
+	c.JSON(http.StatusCreated,
+		models.NewSuccessResponse(list, "list created successfully"))
 }
 
-// GetList returns a single list with its items
-// GET /api/v1/lists/:id
-func (h *Handler) GetList(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	userID := getUserIDFromContext(ctx)
-	if userID == "" {
-		respondError(w, http.StatusUnauthorized, "Unauthorized")
+// GetList handles GET /users/lists/:id
+// Owners see their private lists; other users only see public ones
+func (h *Handler) GetList(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
 		return
 	}
 
-	listID := getListIDFromPath(r)
-
-	list, err := h.service.GetListWithItems(ctx, listID, userID)
+	list, err := h.svc.GetList(c.Request.Context(), c.Param("id"), user.ID)
 	if err != nil {
-		respondError(w, http.StatusNotFound, err.Error())
+		apperrors.Respond(c, err, "failed to get list")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, list)
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(list, "list retrieved successfully"))
 }
 
-// UpdateList updates a custom list
-// PUT /api/v1/lists/:id
-func (h *Handler) UpdateList(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	userID := getUserIDFromContext(ctx)
-	if userID == "" {
-		respondError(w, http.StatusUnauthorized, "Unauthorized")
+// GetPublicList handles GET /lists/:id
+// Anyone can view a public list; private lists respond 404
+func (h *Handler) GetPublicList(c *gin.Context) {
+	list, err := h.svc.GetList(c.Request.Context(), c.Param("id"), "")
+	if err != nil {
+		apperrors.Respond(c, err, "failed to get list")
 		return
 	}
 
-	listID := getListIDFromPath(r)
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(list, "list retrieved successfully"))
+}
+
+// UpdateList handles PUT /users/lists/:id
+// Request body: { name?, description?, icon?, is_public? }
+func (h *Handler) UpdateList(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
 
 	var req models.UpdateListRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeBadRequest, "invalid JSON body", map[string]interface{}{"error": err.Error()}))
 		return
 	}
 
-	list, err := h.service.UpdateList(ctx, listID, userID, &req)
+	list, err := h.svc.UpdateList(c.Request.Context(), c.Param("id"), user.ID, req)
 	if err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+		apperrors.Respond(c, err, "failed to update list")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, list)
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(list, "list updated successfully"))
 }
 
-// DeleteList deletes a custom list
-// DELETE /api/v1/lists/:id
-func (h *Handler) DeleteList(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	userID := getUserIDFromContext(ctx)
-	if userID == "" {
-		respondError(w, http.StatusUnauthorized, "Unauthorized")
+// DeleteList handles DELETE /users/lists/:id
+func (h *Handler) DeleteList(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
 		return
 	}
 
-	listID := getListIDFromPath(r)
-
-	if err := h.service.DeleteList(ctx, listID, userID); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	if err := h.svc.DeleteList(c.Request.Context(), c.Param("id"), user.ID); err != nil {
+		apperrors.Respond(c, err, "failed to delete list")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"message": "List deleted successfully"})
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(nil, "list deleted successfully"))
 }
 
-// AddToList adds a manga to a list
-// POST /api/v1/lists/:id/items
-func (h *Handler) AddToList(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	userID := getUserIDFromContext(ctx)
-	if userID == "" {
-		respondError(w, http.StatusUnauthorized, "Unauthorized")
+// AddItem handles POST /users/lists/:id/items
+// Request body: { manga_id, notes? }
+func (h *Handler) AddItem(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
 		return
 	}
 
-	listID := getListIDFromPath(r)
-
 	var req models.AddToListRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeBadRequest, "invalid JSON body", map[string]interface{}{"error": err.Error()}))
 		return
 	}
 
-	if err := h.service.AddToList(ctx, listID, userID, &req); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	item, err := h.svc.AddItem(c.Request.Context(), c.Param("id"), user.ID, req)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to add manga to list")
 		return
 	}
 
-	respondJSON(w, http.StatusCreated, map[string]string{"message": "Manga added to list"})
+	c.JSON(http.StatusCreated,
+		models.NewSuccessResponse(item, "manga added to list"))
 }
 
-// RemoveFromList removes a manga from a list
-// DELETE /api/v1/lists/:id/items/:mangaId
-func (h *Handler) RemoveFromList(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	userID := getUserIDFromContext(ctx)
-	if userID == "" {
-		respondError(w, http.StatusUnauthorized, "Unauthorized")
+// ReorderItems handles PUT /users/lists/:id/items
+// Request body: { item_ids: [...] } in the new order
+func (h *Handler) ReorderItems(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
 		return
 	}
 
-	listID := getListIDFromPath(r)
-	mangaID := getMangaIDFromPath(r)
-
-	if err := h.service.RemoveFromList(ctx, listID, mangaID, userID); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	var req models.ReorderListRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeBadRequest, "invalid JSON body", map[string]interface{}{"error": err.Error()}))
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"message": "Manga removed from list"})
-}
-
-// ReorderList reorders items in a list
-// PUT /api/v1/lists/:id/reorder
-func (h *Handler) ReorderList(w http.ResponseWriter, r *http.Request) {
-	ctx := r.Context()
-	userID := getUserIDFromContext(ctx)
-	if userID == "" {
-		respondError(w, http.StatusUnauthorized, "Unauthorized")
+	if err := h.svc.ReorderItems(c.Request.Context(), c.Param("id"), user.ID, req); err != nil {
+		apperrors.Respond(c, err, "failed to reorder list")
 		return
 	}
 
-	listID := getListIDFromPath(r)
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(nil, "list reordered successfully"))
+}
 
-	var req models.ReorderListRequest
-	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
-		respondError(w, http.StatusBadRequest, "Invalid request body")
+// RemoveItem handles DELETE /users/lists/:id/items/:manga_id
+func (h *Handler) RemoveItem(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
 		return
 	}
 
-	if err := h.service.ReorderList(ctx, listID, userID, &req); err != nil {
-		respondError(w, http.StatusBadRequest, err.Error())
+	if err := h.svc.RemoveItem(c.Request.Context(), c.Param("id"), user.ID, c.Param("manga_id")); err != nil {
+		apperrors.Respond(c, err, "failed to remove manga from list")
 		return
 	}
 
-	respondJSON(w, http.StatusOK, map[string]string{"message": "List reordered successfully"})
-}
-
-// Helper functions
-
-func getUserIDFromContext(ctx interface{}) string {
-	if c, ok := ctx.(interface{ Value(interface{}) interface{} }); ok {
-		if userID, ok := c.Value("user_id").(string); ok {
-			return userID
-		}
-	}
-	return ""
-}
-
-func getListIDFromPath(r *http.Request) string {
-	return r.PathValue("id") // Go 1.22+
-}
-
-func getMangaIDFromPath(r *http.Request) string {
-	return r.PathValue("mangaId") // Go 1.22+
-}
-
-func respondJSON(w http.ResponseWriter, status int, data interface{}) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(data)
-}
-
-func respondError(w http.ResponseWriter, status int, message string) {
-	respondJSON(w, status, models.APIResponse{
-		Success: false,
-		Message: message,
-	})
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(nil, "manga removed from list"))
 }
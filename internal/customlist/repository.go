@@ -1,327 +1,299 @@
 // Package customlist - Custom Lists Repository
-// Handles database operations for user-created manga lists
+// Data access layer cho custom manga lists
+// Chức năng:
+//   - CRUD cho custom_lists
+//   - Thêm/xoá/sắp xếp manga trong list (custom_list_items)
+//   - UNIQUE(list_id, manga_id) được map thành ErrAlreadyInList
 package customlist
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"fmt"
+	"strings"
 	"time"
 
-	"mangahub/pkg/database"
-	"mangahub/pkg/models"
-
 	"github.com/google/uuid"
+	"mangahub/pkg/models"
 )
 
-// Repository handles custom list database operations
-type Repository struct {
-	db *database.DB
+// Repository defines data access operations for custom lists
+type Repository interface {
+	// CreateList inserts a new list
+	CreateList(ctx context.Context, list *models.CustomList) error
+
+	// GetList returns a list by ID, or models.ErrListNotFound
+	GetList(ctx context.Context, listID string) (*models.CustomList, error)
+
+	// GetUserLists returns every list owned by a user
+	GetUserLists(ctx context.Context, userID string) ([]models.CustomList, error)
+
+	// UpdateList saves name, description, icon and visibility
+	UpdateList(ctx context.Context, list *models.CustomList) error
+
+	// DeleteList removes a list and its items
+	DeleteList(ctx context.Context, listID, userID string) error
+
+	// AddItem appends a manga to the end of a list
+	AddItem(ctx context.Context, listID, mangaID, notes string) (*models.CustomListItem, error)
+
+	// RemoveItem removes a manga from a list
+	RemoveItem(ctx context.Context, listID, mangaID string) error
+
+	// GetListItems returns a list's manga in sort order
+	GetListItems(ctx context.Context, listID string) ([]models.CustomListWithManga, error)
+
+	// ReorderItems sets sort_order from the position of each item ID
+	ReorderItems(ctx context.Context, listID string, itemIDs []string) error
+
+	// MangaExists reports whether a manga ID is in the catalogue
+	MangaExists(ctx context.Context, mangaID string) (bool, error)
+}
+
+type repository struct {
+	db *sql.DB
 }
 
 // NewRepository creates a new custom list repository
-func NewRepository(db *database.DB) *Repository {
-	return &Repository{db: db}
+func NewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
 }
 
-// CreateList creates a new custom list
-func (r *Repository) CreateList(list *models.CustomList) error {
-	if list.ID == "" {
-		list.ID = uuid.New().String()
-	}
-	list.CreatedAt = time.Now()
-	list.UpdatedAt = time.Now()
+const listColumns = `id, user_id, name, COALESCE(description, ''), COALESCE(icon, ''),
+	is_public, is_default, sort_order, item_count, created_at, updated_at`
+
+func scanList(row interface{ Scan(...interface{}) error }, list *models.CustomList) error {
+	return row.Scan(
+		&list.ID, &list.UserID, &list.Name, &list.Description, &list.Icon,
+		&list.IsPublic, &list.IsDefault, &list.SortOrder, &list.ItemCount,
+		&list.CreatedAt, &list.UpdatedAt,
+	)
+}
+
+// CreateList inserts a new list after the user's existing lists
+func (r *repository) CreateList(ctx context.Context, list *models.CustomList) error {
+	now := time.Now()
+	list.ID = uuid.New().String()
+	list.CreatedAt = now
+	list.UpdatedAt = now
 
-	query := `
-		INSERT INTO custom_lists (id, user_id, name, description, is_public, sort_order, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	err := r.db.QueryRowContext(ctx,
+		"SELECT COALESCE(MAX(sort_order) + 1, 0) FROM custom_lists WHERE user_id = ?", list.UserID,
+	).Scan(&list.SortOrder)
+	if err != nil {
+		return fmt.Errorf("get list sort order: %w", err)
+	}
 
-	_, err := r.db.Exec(query,
-		list.ID, list.UserID, list.Name, list.Description,
-		list.IsPublic, list.SortOrder,
-		list.CreatedAt, list.UpdatedAt,
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO custom_lists
+		(id, user_id, name, description, icon, is_public, is_default, sort_order, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		list.ID, list.UserID, list.Name, list.Description, list.Icon,
+		list.IsPublic, list.IsDefault, list.SortOrder, list.CreatedAt, list.UpdatedAt,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to create list: %w", err)
+		return fmt.Errorf("insert list: %w", err)
 	}
 	return nil
 }
 
-// GetList retrieves a list by ID
-func (r *Repository) GetList(id string) (*models.CustomList, error) {
-	query := `
-		SELECT id, user_id, name, description, is_public, sort_order, created_at, updated_at
-		FROM custom_lists WHERE id = ?`
-
+// GetList returns a list by ID, or models.ErrListNotFound
+func (r *repository) GetList(ctx context.Context, listID string) (*models.CustomList, error) {
 	var list models.CustomList
-	var description sql.NullString
-	err := r.db.QueryRow(query, id).Scan(
-		&list.ID, &list.UserID, &list.Name, &description,
-		&list.IsPublic, &list.SortOrder,
-		&list.CreatedAt, &list.UpdatedAt,
-	)
+	err := scanList(r.db.QueryRowContext(ctx,
+		"SELECT "+listColumns+" FROM custom_lists WHERE id = ?", listID), &list)
 	if err == sql.ErrNoRows {
-		return nil, nil
+		return nil, models.ErrListNotFound
 	}
 	if err != nil {
-		return nil, fmt.Errorf("failed to get list: %w", err)
-	}
-
-	if description.Valid {
-		list.Description = description.String
+		return nil, fmt.Errorf("get list: %w", err)
 	}
-
 	return &list, nil
 }
 
-// GetUserLists retrieves all lists for a user
-func (r *Repository) GetUserLists(userID string) ([]models.CustomList, error) {
-	query := `
-		SELECT id, user_id, name, description, is_public, sort_order, created_at, updated_at
-		FROM custom_lists 
+// GetUserLists returns every list owned by a user
+func (r *repository) GetUserLists(ctx context.Context, userID string) ([]models.CustomList, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+listColumns+`
+		FROM custom_lists
 		WHERE user_id = ?
-		ORDER BY sort_order ASC, name ASC`
-
-	rows, err := r.db.Query(query, userID)
+		ORDER BY sort_order ASC, name ASC`, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query lists: %w", err)
+		return nil, fmt.Errorf("get user lists: %w", err)
 	}
 	defer rows.Close()
 
-	var lists []models.CustomList
+	lists := []models.CustomList{}
 	for rows.Next() {
 		var list models.CustomList
-		var description sql.NullString
-		err := rows.Scan(
-			&list.ID, &list.UserID, &list.Name, &description,
-			&list.IsPublic, &list.SortOrder,
-			&list.CreatedAt, &list.UpdatedAt,
-		)
-		if err != nil {
-			continue
-		}
-		if description.Valid {
-			list.Description = description.String
+		if err := scanList(rows, &list); err != nil {
+			return nil, fmt.Errorf("scan list: %w", err)
 		}
 		lists = append(lists, list)
 	}
-
-	return lists, nil
+	return lists, rows.Err()
 }
 
-// UpdateList updates a custom list
-func (r *Repository) UpdateList(list *models.CustomList) error {
+// UpdateList saves name, description, icon and visibility
+func (r *repository) UpdateList(ctx context.Context, list *models.CustomList) error {
 	list.UpdatedAt = time.Now()
 
-	query := `
-		UPDATE custom_lists 
-		SET name = ?, description = ?, is_public = ?, sort_order = ?, updated_at = ?
-		WHERE id = ? AND user_id = ?`
-
-	result, err := r.db.Exec(query,
-		list.Name, list.Description, list.IsPublic, list.SortOrder,
-		list.UpdatedAt, list.ID, list.UserID,
+	result, err := r.db.ExecContext(ctx, `
+		UPDATE custom_lists
+		SET name = ?, description = ?, icon = ?, is_public = ?, updated_at = ?
+		WHERE id = ? AND user_id = ?`,
+		list.Name, list.Description, list.Icon, list.IsPublic, list.UpdatedAt, list.ID, list.UserID,
 	)
 	if err != nil {
-		return fmt.Errorf("failed to update list: %w", err)
+		return fmt.Errorf("update list: %w", err)
 	}
-
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("list not found")
+	if n, _ := result.RowsAffected(); n == 0 {
+		return models.ErrListNotFound
 	}
-
 	return nil
 }
 
-	// DeleteList deletes a custom list
-func (r *Repository) DeleteList(id, userID string) error {
-	result, err := r.db.Exec(`
-		DELETE FROM custom_lists 
-		WHERE id = ? AND user_id = ?`, id, userID)
+// DeleteList removes a list; items go with it via ON DELETE CASCADE
+func (r *repository) DeleteList(ctx context.Context, listID, userID string) error {
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM custom_lists WHERE id = ? AND user_id = ?", listID, userID)
 	if err != nil {
-		return fmt.Errorf("failed to delete list: %w", err)
+		return fmt.Errorf("delete list: %w", err)
 	}
-
-	rowsAffected, _ := result.RowsAffected()
-	if rowsAffected == 0 {
-		return fmt.Errorf("list not found")
+	if n, _ := result.RowsAffected(); n == 0 {
+		return models.ErrListNotFound
 	}
-
 	return nil
 }
 
-// AddMangaToList adds a manga to a list
-func (r *Repository) AddMangaToList(listID, mangaID, userID, notes string) error {
-	// Verify list ownership
-	var ownerID string
-	err := r.db.QueryRow(`SELECT user_id FROM custom_lists WHERE id = ?`, listID).Scan(&ownerID)
-	if err != nil {
-		return fmt.Errorf("list not found")
-	}
-	if ownerID != userID {
-		return fmt.Errorf("unauthorized")
+// AddItem appends a manga to the end of a list.
+// Returns models.ErrAlreadyInList when the manga is already there.
+func (r *repository) AddItem(ctx context.Context, listID, mangaID, notes string) (*models.CustomListItem, error) {
+	item := models.CustomListItem{
+		ID:      uuid.New().String(),
+		ListID:  listID,
+		MangaID: mangaID,
+		Notes:   notes,
+		AddedAt: time.Now(),
 	}
 
-	// Get max sort order
-	var maxOrder int
-	r.db.QueryRow(`SELECT COALESCE(MAX(sort_order), 0) FROM custom_list_items WHERE list_id = ?`, listID).Scan(&maxOrder)
-
-	id := uuid.New().String()
-	now := time.Now()
+	err := r.db.QueryRowContext(ctx,
+		"SELECT COALESCE(MAX(sort_order) + 1, 0) FROM custom_list_items WHERE list_id = ?", listID,
+	).Scan(&item.SortOrder)
+	if err != nil {
+		return nil, fmt.Errorf("get item sort order: %w", err)
+	}
 
-	_, err = r.db.Exec(`
-		INSERT INTO custom_list_items (id, list_id, manga_id, sort_order, notes, added_at, created_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?)
-		ON CONFLICT(list_id, manga_id) DO UPDATE SET notes = ?, sort_order = ?`,
-		id, listID, mangaID, maxOrder+1, notes, now, now, notes, maxOrder+1)
+	_, err = r.db.ExecContext(ctx, `
+		INSERT INTO custom_list_items (id, list_id, manga_id, notes, sort_order, added_at, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		item.ID, item.ListID, item.MangaID, item.Notes, item.SortOrder, item.AddedAt, item.AddedAt,
+	)
 	if err != nil {
-		return fmt.Errorf("failed to add manga: %w", err)
+		if isUniqueViolation(err) {
+			return nil, models.ErrAlreadyInList
+		}
+		return nil, fmt.Errorf("insert list item: %w", err)
 	}
 
-	return nil
+	r.touchList(ctx, listID)
+	return &item, nil
 }
 
-// RemoveMangaFromList removes a manga from a list
-func (r *Repository) RemoveMangaFromList(listID, mangaID, userID string) error {
-	// Verify list ownership
-	var ownerID string
-	err := r.db.QueryRow(`SELECT user_id FROM custom_lists WHERE id = ?`, listID).Scan(&ownerID)
+// RemoveItem removes a manga from a list
+func (r *repository) RemoveItem(ctx context.Context, listID, mangaID string) error {
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM custom_list_items WHERE list_id = ? AND manga_id = ?", listID, mangaID)
 	if err != nil {
-		return fmt.Errorf("list not found")
+		return fmt.Errorf("delete list item: %w", err)
 	}
-	if ownerID != userID {
-		return fmt.Errorf("unauthorized")
-	}
-
-	_, err = r.db.Exec(`DELETE FROM custom_list_items WHERE list_id = ? AND manga_id = ?`, listID, mangaID)
-	if err != nil {
-		return fmt.Errorf("failed to remove manga: %w", err)
+	if n, _ := result.RowsAffected(); n == 0 {
+		return models.ErrListItemNotFound
 	}
 
+	r.touchList(ctx, listID)
 	return nil
 }
 
-// GetListItems retrieves all manga in a list with details
-func (r *Repository) GetListItems(listID string) ([]models.CustomListWithManga, error) {
-	query := `
-		SELECT 
-			cli.id, cli.list_id, cli.manga_id, cli.sort_order, cli.notes, cli.added_at, cli.created_at,
-			m.id, m.title, m.author, m.artist, m.description, m.cover_url, m.status, m.type,
-			m.total_chapters, m.average_rating, m.rating_count, m.year, m.created_at, m.updated_at
+// GetListItems returns a list's manga in sort order
+func (r *repository) GetListItems(ctx context.Context, listID string) ([]models.CustomListWithManga, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT
+			cli.id, cli.list_id, cli.manga_id, COALESCE(cli.notes, ''), cli.sort_order, cli.added_at,
+			m.id, m.title, COALESCE(m.author, ''), COALESCE(m.artist, ''), COALESCE(m.description, ''),
+			COALESCE(m.cover_url, ''), COALESCE(m.status, ''), COALESCE(m.type, ''),
+			COALESCE(m.total_chapters, 0), COALESCE(m.average_rating, 0), COALESCE(m.rating_count, 0),
+			COALESCE(m.year, 0), m.created_at, m.updated_at
 		FROM custom_list_items cli
-		JOIN manga m ON cli.manga_id = m.id
+		JOIN manga m ON m.id = cli.manga_id
 		WHERE cli.list_id = ?
-		ORDER BY cli.sort_order ASC`
-
-	rows, err := r.db.Query(query, listID)
+		ORDER BY cli.sort_order ASC, cli.added_at ASC`, listID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query list items: %w", err)
+		return nil, fmt.Errorf("get list items: %w", err)
 	}
 	defer rows.Close()
 
-	var items []models.CustomListWithManga
+	items := []models.CustomListWithManga{}
 	for rows.Next() {
 		var item models.CustomListWithManga
-		var notes sql.NullString
-
-		err := rows.Scan(
-			&item.ID, &item.ListID, &item.MangaID, &item.SortOrder, &notes, &item.AddedAt,
-			&item.Manga.ID, &item.Manga.Title, &item.Manga.Author, &item.Manga.Artist,
-			&item.Manga.Description, &item.Manga.CoverURL, &item.Manga.Status, &item.Manga.Type,
-			&item.Manga.TotalChapters, &item.Manga.AverageRating, &item.Manga.RatingCount, &item.Manga.Year,
-			&item.Manga.CreatedAt, &item.Manga.UpdatedAt,
-		)
-		if err != nil {
-			continue
+		if err := rows.Scan(
+			&item.ID, &item.ListID, &item.MangaID, &item.Notes, &item.SortOrder, &item.AddedAt,
+			&item.Manga.ID, &item.Manga.Title, &item.Manga.Author, &item.Manga.Artist, &item.Manga.Description,
+			&item.Manga.CoverURL, &item.Manga.Status, &item.Manga.Type,
+			&item.Manga.TotalChapters, &item.Manga.AverageRating, &item.Manga.RatingCount,
+			&item.Manga.Year, &item.Manga.CreatedAt, &item.Manga.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan list item: %w", err)
 		}
-
-		if notes.Valid {
-			item.Notes = notes.String
-		}
-		// Genres are loaded separately via JOIN in service layer
-		item.Manga.Genres = []models.Genre{}
-
 		items = append(items, item)
 	}
-
-	return items, nil
-}
-
-// GetListWithItems retrieves a list with all its items
-func (r *Repository) GetListWithItems(listID string) (*models.CustomListWithItems, error) {
-	list, err := r.GetList(listID)
-	if err != nil || list == nil {
-		return nil, err
-	}
-
-	items, err := r.GetListItems(listID)
-	if err != nil {
-		return nil, err
-	}
-
-	return &models.CustomListWithItems{
-		CustomList: *list,
-		Items:      items,
-	}, nil
+	return items, rows.Err()
 }
 
-// ReorderListItems reorders items in a list
-func (r *Repository) ReorderListItems(listID, userID string, itemIDs []string) error {
-	// Verify list ownership
-	var ownerID string
-	err := r.db.QueryRow(`SELECT user_id FROM custom_lists WHERE id = ?`, listID).Scan(&ownerID)
+// ReorderItems sets sort_order from the position of each item ID.
+// Every ID must belong to the list, otherwise nothing changes.
+func (r *repository) ReorderItems(ctx context.Context, listID string, itemIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
 	if err != nil {
-		return fmt.Errorf("list not found")
-	}
-	if ownerID != userID {
-		return fmt.Errorf("unauthorized")
+		return fmt.Errorf("begin tx: %w", err)
 	}
+	defer tx.Rollback()
 
-	// Update sort orders
 	for i, itemID := range itemIDs {
-		_, err := r.db.Exec(`
-			UPDATE custom_list_items SET sort_order = ? WHERE id = ? AND list_id = ?`,
-			i, itemID, listID)
+		result, err := tx.ExecContext(ctx,
+			"UPDATE custom_list_items SET sort_order = ? WHERE id = ? AND list_id = ?", i, itemID, listID)
 		if err != nil {
-			return fmt.Errorf("failed to update order: %w", err)
+			return fmt.Errorf("reorder list item: %w", err)
+		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			return models.ErrListItemNotFound
 		}
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit reorder: %w", err)
+	}
+	r.touchList(ctx, listID)
 	return nil
 }
 
-// EnsureDefaultLists creates default lists for a user if they don't exist
-func (r *Repository) EnsureDefaultLists(userID string) error {
-	// Check if default lists exist
-	var count int
-	r.db.QueryRow(`SELECT COUNT(*) FROM custom_lists WHERE user_id = ? AND is_default = 1`, userID).Scan(&count)
-	if count > 0 {
-		return nil // Already have default lists
-	}
-
-	defaultLists := []struct {
-		Name      string
-		Emoji     string
-		SortOrder int
-	}{
-		{"Favorites", "❤️", 0},
-		{"Plan to Read", "📋", 1},
-		{"Top 10", "🏆", 2},
+// MangaExists reports whether a manga ID is in the catalogue
+func (r *repository) MangaExists(ctx context.Context, mangaID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx, "SELECT EXISTS(SELECT 1 FROM manga WHERE id = ?)", mangaID).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check manga exists: %w", err)
 	}
+	return exists, nil
+}
 
-	for i, dl := range defaultLists {
-		list := &models.CustomList{
-			ID:          uuid.New().String(),
-			UserID:      userID,
-			Name:        dl.Name,
-			Description: "",
-			IsPublic:    false,
-			SortOrder:   i,
-		}
-		if err := r.CreateList(list); err != nil {
-			return err
-		}
-	}
+// touchList bumps updated_at after an item change; failures only affect sorting by recency
+func (r *repository) touchList(ctx context.Context, listID string) {
+	_, _ = r.db.ExecContext(ctx, "UPDATE custom_lists SET updated_at = ? WHERE id = ?", time.Now(), listID)
+}
 
-	return nil
+// isUniqueViolation reports whether err comes from a UNIQUE constraint
+func isUniqueViolation(err error) bool {
+	return err != nil && !errors.Is(err, sql.ErrNoRows) && strings.Contains(err.Error(), "UNIQUE constraint failed")
 }
@@ -1,152 +1,222 @@
 // Package customlist - Custom Lists Service
-// Business logic for user-created manga lists
+// Business logic layer cho custom manga lists
+// Chức năng:
+//   - Validate requests
+//   - Kiểm tra quyền: chỉ owner được sửa, list public ai cũng xem được
+//   - Map lỗi repository thành AppError (404, 403, 409 "already in list")
 package customlist
 
 import (
 	"context"
-	"fmt"
+	"errors"
 
-	"mangahub/pkg/database"
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/models"
+	"mangahub/pkg/utils"
 )
 
-// Service provides custom list business logic
-type Service struct {
-	repo *Repository
-	db   *database.DB
+// Service defines business operations for custom lists
+type Service interface {
+	// CreateList creates a list owned by the user
+	CreateList(ctx context.Context, userID string, req models.CreateListRequest) (*models.CustomList, error)
+
+	// GetUserLists returns the user's lists
+	GetUserLists(ctx context.Context, userID string) (*models.CustomListsResponse, error)
+
+	// GetList returns a list with its items if the viewer may see it.
+	// viewerID is empty for anonymous requests, which only see public lists.
+	GetList(ctx context.Context, listID, viewerID string) (*models.CustomListWithItems, error)
+
+	// UpdateList changes name, description, icon or visibility
+	UpdateList(ctx context.Context, listID, userID string, req models.UpdateListRequest) (*models.CustomList, error)
+
+	// DeleteList removes a list
+	DeleteList(ctx context.Context, listID, userID string) error
+
+	// AddItem adds a manga to one of the user's lists
+	AddItem(ctx context.Context, listID, userID string, req models.AddToListRequest) (*models.CustomListItem, error)
+
+	// RemoveItem removes a manga from one of the user's lists
+	RemoveItem(ctx context.Context, listID, userID, mangaID string) error
+
+	// ReorderItems reorders the items of one of the user's lists
+	ReorderItems(ctx context.Context, listID, userID string, req models.ReorderListRequest) error
+}
+
+type service struct {
+	repo Repository
 }
 
 // NewService creates a new custom list service
-func NewService(db *database.DB) *Service {
-	return &Service{
-		repo: NewRepository(db),
-		db:   db,
-	}
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
 }
 
-// CreateList creates a new custom list for a user
-func (s *Service) CreateList(ctx context.Context, userID string, req *models.CreateListRequest) (*models.CustomList, error) {
-	// Validate
-	if req.Name == "" {
-		return nil, fmt.Errorf("name is required")
+// CreateList creates a list owned by the user
+func (s *service) CreateList(ctx context.Context, userID string, req models.CreateListRequest) (*models.CustomList, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, apperrors.Invalid("invalid list data", err)
 	}
 
 	list := &models.CustomList{
 		UserID:      userID,
 		Name:        req.Name,
 		Description: req.Description,
+		Icon:        req.Icon,
 		IsPublic:    req.IsPublic,
 	}
-
-	if err := s.repo.CreateList(list); err != nil {
-		return nil, err
+	if err := s.repo.CreateList(ctx, list); err != nil {
+		return nil, apperrors.Internal("failed to create list", err)
 	}
-
 	return list, nil
 }
 
-// GetUserLists returns all lists for a user
-func (s *Service) GetUserLists(ctx context.Context, userID string) (*models.CustomListsResponse, error) {
-	lists, err := s.repo.GetUserLists(userID)
+// GetUserLists returns the user's lists
+func (s *service) GetUserLists(ctx context.Context, userID string) (*models.CustomListsResponse, error) {
+	lists, err := s.repo.GetUserLists(ctx, userID)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.Internal("failed to get lists", err)
 	}
-
-	return &models.CustomListsResponse{
-		Lists: lists,
-		Total: len(lists),
-	}, nil
+	return &models.CustomListsResponse{Lists: lists, Total: len(lists)}, nil
 }
 
-// GetList returns a list by ID (with permission check)
-func (s *Service) GetList(ctx context.Context, listID, userID string) (*models.CustomList, error) {
-	list, err := s.repo.GetList(listID)
+// GetList returns a list with its items if the viewer may see it
+func (s *service) GetList(ctx context.Context, listID, viewerID string) (*models.CustomListWithItems, error) {
+	list, err := s.repo.GetList(ctx, listID)
 	if err != nil {
-		return nil, err
+		return nil, listError(err, "failed to get list")
 	}
-	if list == nil {
-		return nil, fmt.Errorf("list not found")
-	}
-
-	// Check permission
-	if list.UserID != userID && !list.IsPublic {
-		return nil, fmt.Errorf("unauthorized")
+	// Private lists look the same as missing ones to everyone but the owner
+	if !list.IsPublic && list.UserID != viewerID {
+		return nil, listError(models.ErrListNotFound, "")
 	}
 
-	return list, nil
-}
-
-// GetListWithItems returns a list with all its items
-func (s *Service) GetListWithItems(ctx context.Context, listID, userID string) (*models.CustomListWithItems, error) {
-	list, err := s.GetList(ctx, listID, userID)
+	items, err := s.repo.GetListItems(ctx, listID)
 	if err != nil {
-		return nil, err
+		return nil, apperrors.Internal("failed to get list items", err)
 	}
+	return &models.CustomListWithItems{CustomList: *list, Items: items}, nil
+}
 
-	items, err := s.repo.GetListItems(listID)
-	if err != nil {
-		return nil, err
+// UpdateList changes name, description, icon or visibility
+func (s *service) UpdateList(ctx context.Context, listID, userID string, req models.UpdateListRequest) (*models.CustomList, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, apperrors.Invalid("invalid list data", err)
 	}
 
-	return &models.CustomListWithItems{
-		CustomList: *list,
-		Items:      items,
-	}, nil
-}
-
-// UpdateList updates a custom list
-func (s *Service) UpdateList(ctx context.Context, listID, userID string, req *models.UpdateListRequest) (*models.CustomList, error) {
-	list, err := s.repo.GetList(listID)
+	list, err := s.ownedList(ctx, listID, userID)
 	if err != nil {
 		return nil, err
 	}
-	if list == nil {
-		return nil, fmt.Errorf("list not found")
-	}
-	if list.UserID != userID {
-		return nil, fmt.Errorf("unauthorized")
-	}
 
-	// Apply updates
 	if req.Name != nil {
 		list.Name = *req.Name
 	}
 	if req.Description != nil {
 		list.Description = *req.Description
 	}
+	if req.Icon != nil {
+		list.Icon = *req.Icon
+	}
 	if req.IsPublic != nil {
 		list.IsPublic = *req.IsPublic
 	}
 
-	if err := s.repo.UpdateList(list); err != nil {
+	if err := s.repo.UpdateList(ctx, list); err != nil {
+		return nil, listError(err, "failed to update list")
+	}
+	return list, nil
+}
+
+// DeleteList removes a list
+func (s *service) DeleteList(ctx context.Context, listID, userID string) error {
+	if _, err := s.ownedList(ctx, listID, userID); err != nil {
+		return err
+	}
+	if err := s.repo.DeleteList(ctx, listID, userID); err != nil {
+		return listError(err, "failed to delete list")
+	}
+	return nil
+}
+
+// AddItem adds a manga to one of the user's lists
+func (s *service) AddItem(ctx context.Context, listID, userID string, req models.AddToListRequest) (*models.CustomListItem, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, apperrors.Invalid("invalid list item data", err)
+	}
+	if _, err := s.ownedList(ctx, listID, userID); err != nil {
 		return nil, err
 	}
 
-	return list, nil
+	exists, err := s.repo.MangaExists(ctx, req.MangaID)
+	if err != nil {
+		return nil, apperrors.Internal("failed to check manga", err)
+	}
+	if !exists {
+		return nil, apperrors.NotFound("manga not found", models.ErrMangaNotFound)
+	}
+
+	item, err := s.repo.AddItem(ctx, listID, req.MangaID, req.Notes)
+	if err != nil {
+		return nil, listError(err, "failed to add manga to list")
+	}
+	return item, nil
 }
 
-// DeleteList deletes a custom list
-func (s *Service) DeleteList(ctx context.Context, listID, userID string) error {
-	return s.repo.DeleteList(listID, userID)
+// RemoveItem removes a manga from one of the user's lists
+func (s *service) RemoveItem(ctx context.Context, listID, userID, mangaID string) error {
+	if _, err := s.ownedList(ctx, listID, userID); err != nil {
+		return err
+	}
+	if err := s.repo.RemoveItem(ctx, listID, mangaID); err != nil {
+		return listError(err, "failed to remove manga from list")
+	}
+	return nil
 }
 
-// AddToList adds a manga to a list
-func (s *Service) AddToList(ctx context.Context, listID, userID string, req *models.AddToListRequest) error {
-	if req.MangaID == "" {
-		return fmt.Errorf("manga_id is required")
+// ReorderItems reorders the items of one of the user's lists
+func (s *service) ReorderItems(ctx context.Context, listID, userID string, req models.ReorderListRequest) error {
+	if err := utils.ValidateStruct(req); err != nil {
+		return apperrors.Invalid("item_ids is required", err)
 	}
-	return s.repo.AddMangaToList(listID, req.MangaID, userID, req.Notes)
+	if _, err := s.ownedList(ctx, listID, userID); err != nil {
+		return err
+	}
+	if err := s.repo.ReorderItems(ctx, listID, req.ItemIDs); err != nil {
+		if errors.Is(err, models.ErrListItemNotFound) {
+			return apperrors.Validation("item_ids", "item_ids must all belong to the list")
+		}
+		return listError(err, "failed to reorder list")
+	}
+	return nil
 }
 
-// RemoveFromList removes a manga from a list
-func (s *Service) RemoveFromList(ctx context.Context, listID, mangaID, userID string) error {
-	return s.repo.RemoveMangaFromList(listID, mangaID, userID)
+// ownedList loads a list the user is allowed to modify.
+// Someone else's public list is forbidden; a private one stays hidden.
+func (s *service) ownedList(ctx context.Context, listID, userID string) (*models.CustomList, error) {
+	list, err := s.repo.GetList(ctx, listID)
+	if err != nil {
+		return nil, listError(err, "failed to get list")
+	}
+	if list.UserID != userID {
+		if list.IsPublic {
+			return nil, apperrors.Forbidden("you can only modify your own lists", models.ErrForbidden)
+		}
+		return nil, listError(models.ErrListNotFound, "")
+	}
+	return list, nil
 }
 
-// ReorderList reorders items in a list
-func (s *Service) ReorderList(ctx context.Context, listID, userID string, req *models.ReorderListRequest) error {
-	if len(req.ItemIDs) == 0 {
-		return fmt.Errorf("item_ids is required")
+// listError maps repository errors to AppErrors
+func listError(err error, internalMsg string) error {
+	switch {
+	case errors.Is(err, models.ErrListNotFound):
+		return apperrors.NotFound("list not found", err)
+	case errors.Is(err, models.ErrListItemNotFound):
+		return apperrors.NotFound("manga is not in this list", err)
+	case errors.Is(err, models.ErrAlreadyInList):
+		return apperrors.Conflict("manga is already in this list", err)
+	default:
+		return apperrors.Internal(internalMsg, err)
 	}
-	return s.repo.ReorderListItems(listID, userID, req.ItemIDs)
 }
@@ -0,0 +1,88 @@
+// Package goals - Reading Goals Tests
+// Unit tests cho tiến độ goal và rollover khi qua năm mới
+package goals
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+// setupTestDB opens the shared test database and seeds the rows these tests use
+func setupTestDB(t *testing.T) *sql.DB {
+	db := testutil.OpenDB(t)
+
+	for _, q := range []string{
+		`INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`,
+		`INSERT INTO manga (id, title) VALUES ('m1', 'Berserk')`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("seed failed: %v", err)
+		}
+	}
+
+	return db
+}
+
+func TestYearlyGoalRollsOver(t *testing.T) {
+	db := setupTestDB(t)
+	ctx := context.Background()
+
+	now := time.Date(2025, time.December, 30, 12, 0, 0, 0, time.UTC)
+	svc := &service{repo: NewRepository(db), now: func() time.Time { return now }}
+
+	read := func(id string, at time.Time) {
+		t.Helper()
+		if _, err := db.Exec(`INSERT INTO chapter_history (id, user_id, manga_id, chapter_number, read_at) VALUES (?, 'u1', 'm1', 1, ?)`, id, at); err != nil {
+			t.Fatalf("insert chapter history failed: %v", err)
+		}
+	}
+	read("2024", time.Date(2024, time.December, 31, 23, 0, 0, 0, time.UTC))
+	read("2025-a", time.Date(2025, time.January, 1, 0, 30, 0, 0, time.UTC))
+	read("2025-b", time.Date(2025, time.December, 31, 23, 0, 0, 0, time.UTC))
+
+	goal, err := svc.SetGoal(ctx, "u1", models.SetGoalRequest{Period: models.GoalPeriodYearly, TargetChapters: 4})
+	if err != nil {
+		t.Fatalf("SetGoal failed: %v", err)
+	}
+	if goal.StartDate != "2025-01-01" || goal.EndDate != "2025-12-31" || goal.ChaptersRead != 2 || goal.Percentage != 50 || goal.DaysLeft != 2 {
+		t.Fatalf("unexpected 2025 goal: %+v", goal)
+	}
+
+	// Goals live in their own table, so clearing preferences leaves them alone
+	if _, err := db.Exec(`DELETE FROM user_preferences WHERE user_id = 'u1'`); err != nil {
+		t.Fatalf("delete preferences failed: %v", err)
+	}
+
+	// Queried after end_date: next year's goal appears with the same target
+	now = time.Date(2026, time.January, 2, 8, 0, 0, 0, time.UTC)
+	read("2026", time.Date(2026, time.January, 1, 9, 0, 0, 0, time.UTC))
+
+	goals, err := svc.GetGoals(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetGoals failed: %v", err)
+	}
+	if len(goals) != 1 {
+		t.Fatalf("expected one current goal, got %+v", goals)
+	}
+	g := goals[0]
+	if g.StartDate != "2026-01-01" || g.EndDate != "2026-12-31" || g.TargetChapters != 4 || g.ChaptersRead != 1 || g.Completed {
+		t.Errorf("unexpected rolled over goal: %+v", g)
+	}
+
+	// The old goal is kept and querying again does not create duplicates
+	if _, err := svc.GetGoals(ctx, "u1"); err != nil {
+		t.Fatalf("GetGoals failed: %v", err)
+	}
+	all, err := svc.repo.List(ctx, "u1")
+	if err != nil {
+		t.Fatalf("List failed: %v", err)
+	}
+	if len(all) != 2 || all[1].StartDate != "2025-01-01" {
+		t.Errorf("expected the 2025 and 2026 goals, got %+v", all)
+	}
+}
@@ -0,0 +1,90 @@
+// Package goals - Reading Goals HTTP Handlers
+// HTTP handlers cho reading goals API endpoints
+// Endpoints:
+//   - GET /users/goals - Current goals with progress (expired goals roll over)
+//   - POST /users/goals - Set the target for the current year or month
+//   - DELETE /users/goals/:id - Remove a goal
+package goals
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"mangahub/internal/auth"
+	"mangahub/pkg/apperrors"
+	"mangahub/pkg/models"
+)
+
+// Handler handles HTTP requests for reading goals
+type Handler struct {
+	svc Service
+}
+
+// NewHandler creates a new reading goals handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GetGoals handles GET /users/goals
+func (h *Handler) GetGoals(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	goals, err := h.svc.GetGoals(c.Request.Context(), user.ID)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to get reading goals")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(goals, "reading goals"))
+}
+
+// SetGoal handles POST /users/goals
+// Request body: { period: "yearly"|"monthly", target_chapters }
+func (h *Handler) SetGoal(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	var req models.SetGoalRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeBadRequest, "invalid JSON body", map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	goal, err := h.svc.SetGoal(c.Request.Context(), user.ID, req)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to save reading goal")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(goal, "reading goal saved"))
+}
+
+// DeleteGoal handles DELETE /users/goals/:id
+func (h *Handler) DeleteGoal(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	if err := h.svc.DeleteGoal(c.Request.Context(), user.ID, c.Param("id")); err != nil {
+		apperrors.Respond(c, err, "failed to delete reading goal")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(nil, "reading goal deleted"))
+}
@@ -0,0 +1,140 @@
+// Package goals - Reading Goals Repository
+// Data access layer cho reading_goals
+// Chức năng:
+//   - Tạo/cập nhật goal theo period window (upsert)
+//   - Lấy goal mới nhất của từng period (cho rollover)
+//   - Đếm chapter_history trong khoảng ngày của goal
+package goals
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"mangahub/pkg/models"
+)
+
+// Repository defines data access operations for reading goals
+type Repository interface {
+	// Upsert creates the goal for its window or updates the target of the existing one
+	Upsert(ctx context.Context, goal *models.ReadingGoal) error
+
+	// GetLatest returns the user's most recent goal for a period, nil if none
+	GetLatest(ctx context.Context, userID, period string) (*models.ReadingGoal, error)
+
+	// List returns all of a user's goals, newest first
+	List(ctx context.Context, userID string) ([]models.ReadingGoal, error)
+
+	// Delete removes one of the user's goals, reporting whether it existed
+	Delete(ctx context.Context, id, userID string) (bool, error)
+
+	// CountChapters counts chapters read between start and end (YYYY-MM-DD, inclusive, UTC)
+	CountChapters(ctx context.Context, userID, start, end string) (int, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new reading goals repository
+func NewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+// Upsert creates the goal for its window or updates the target of the existing one
+func (r *repository) Upsert(ctx context.Context, goal *models.ReadingGoal) error {
+	now := time.Now()
+	if goal.ID == "" {
+		goal.ID = uuid.New().String()
+	}
+
+	err := r.db.QueryRowContext(ctx, `
+		INSERT INTO reading_goals (id, user_id, period, target_chapters, start_date, end_date, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, period, start_date) DO UPDATE SET
+			target_chapters = excluded.target_chapters,
+			end_date = excluded.end_date,
+			updated_at = excluded.updated_at
+		RETURNING id, created_at, updated_at`,
+		goal.ID, goal.UserID, goal.Period, goal.TargetChapters, goal.StartDate, goal.EndDate, now, now,
+	).Scan(&goal.ID, &goal.CreatedAt, &goal.UpdatedAt)
+	if err != nil {
+		return fmt.Errorf("upsert reading goal: %w", err)
+	}
+	return nil
+}
+
+// GetLatest returns the user's most recent goal for a period, nil if none
+func (r *repository) GetLatest(ctx context.Context, userID, period string) (*models.ReadingGoal, error) {
+	var g models.ReadingGoal
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, user_id, period, target_chapters, start_date, end_date, created_at, updated_at
+		FROM reading_goals
+		WHERE user_id = ? AND period = ?
+		ORDER BY end_date DESC
+		LIMIT 1`,
+		userID, period,
+	).Scan(&g.ID, &g.UserID, &g.Period, &g.TargetChapters, &g.StartDate, &g.EndDate, &g.CreatedAt, &g.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get latest reading goal: %w", err)
+	}
+	return &g, nil
+}
+
+// List returns all of a user's goals, newest first
+func (r *repository) List(ctx context.Context, userID string) ([]models.ReadingGoal, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, period, target_chapters, start_date, end_date, created_at, updated_at
+		FROM reading_goals
+		WHERE user_id = ?
+		ORDER BY end_date DESC, period`,
+		userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list reading goals: %w", err)
+	}
+	defer rows.Close()
+
+	goals := []models.ReadingGoal{}
+	for rows.Next() {
+		var g models.ReadingGoal
+		if err := rows.Scan(&g.ID, &g.UserID, &g.Period, &g.TargetChapters, &g.StartDate, &g.EndDate, &g.CreatedAt, &g.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan reading goal: %w", err)
+		}
+		goals = append(goals, g)
+	}
+	return goals, rows.Err()
+}
+
+// Delete removes one of the user's goals, reporting whether it existed
+func (r *repository) Delete(ctx context.Context, id, userID string) (bool, error) {
+	result, err := r.db.ExecContext(ctx,
+		"DELETE FROM reading_goals WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return false, fmt.Errorf("delete reading goal: %w", err)
+	}
+	n, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("delete reading goal: %w", err)
+	}
+	return n > 0, nil
+}
+
+// CountChapters counts chapters read between start and end (YYYY-MM-DD, inclusive, UTC)
+func (r *repository) CountChapters(ctx context.Context, userID, start, end string) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM chapter_history
+		WHERE user_id = ? AND date(read_at) BETWEEN ? AND ?`,
+		userID, start, end,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count chapters in goal window: %w", err)
+	}
+	return n, nil
+}
@@ -0,0 +1,158 @@
+// Package goals - Reading Goals Service
+// Business logic layer cho reading goals
+// Chức năng:
+//   - Đặt target cho period hiện tại (năm/tháng, theo ngày UTC)
+//   - Rollover: goal đã hết hạn tự tạo goal cho period hiện tại khi được query
+//   - Tính tiến độ từ chapter_history trong khoảng ngày của goal
+package goals
+
+import (
+	"context"
+	"math"
+	"time"
+
+	"mangahub/pkg/apperrors"
+	"mangahub/pkg/models"
+	"mangahub/pkg/utils"
+)
+
+// Service defines business operations for reading goals
+type Service interface {
+	// SetGoal sets the chapter target for the current period
+	SetGoal(ctx context.Context, userID string, req models.SetGoalRequest) (*models.GoalProgress, error)
+
+	// GetGoals returns the user's current goals with progress, rolling expired ones over
+	GetGoals(ctx context.Context, userID string) ([]models.GoalProgress, error)
+
+	// DeleteGoal removes one of the user's goals
+	DeleteGoal(ctx context.Context, userID, goalID string) error
+}
+
+// dateLayout is the YYYY-MM-DD format of goal start/end dates
+const dateLayout = "2006-01-02"
+
+// periods lists goal periods in display order
+var periods = []string{models.GoalPeriodYearly, models.GoalPeriodMonthly}
+
+type service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+// NewService creates a new reading goals service
+func NewService(repo Repository) Service {
+	return &service{repo: repo, now: time.Now}
+}
+
+// today returns the current UTC day at midnight
+func (s *service) today() time.Time {
+	return s.now().UTC().Truncate(24 * time.Hour)
+}
+
+// SetGoal sets the chapter target for the current period
+func (s *service) SetGoal(ctx context.Context, userID string, req models.SetGoalRequest) (*models.GoalProgress, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, apperrors.Invalid("period must be yearly or monthly and target_chapters at least 1", err)
+	}
+
+	start, end := periodWindow(req.Period, s.today())
+	goal := &models.ReadingGoal{
+		UserID:         userID,
+		Period:         req.Period,
+		TargetChapters: req.TargetChapters,
+		StartDate:      start.Format(dateLayout),
+		EndDate:        end.Format(dateLayout),
+	}
+	if err := s.repo.Upsert(ctx, goal); err != nil {
+		return nil, apperrors.Internal("failed to save reading goal", err)
+	}
+
+	progress, err := s.progress(ctx, *goal)
+	if err != nil {
+		return nil, apperrors.Internal("failed to compute goal progress", err)
+	}
+	return progress, nil
+}
+
+// GetGoals returns the user's current goals with progress.
+// A goal whose end_date has passed is carried over to the current period with
+// the same target, so "500 chapters a year" keeps going on January 1st.
+func (s *service) GetGoals(ctx context.Context, userID string) ([]models.GoalProgress, error) {
+	today := s.today()
+	result := []models.GoalProgress{}
+
+	for _, period := range periods {
+		goal, err := s.repo.GetLatest(ctx, userID, period)
+		if err != nil {
+			return nil, apperrors.Internal("failed to get reading goals", err)
+		}
+		if goal == nil {
+			continue
+		}
+
+		if goal.EndDate < today.Format(dateLayout) {
+			start, end := periodWindow(period, today)
+			goal = &models.ReadingGoal{
+				UserID:         userID,
+				Period:         period,
+				TargetChapters: goal.TargetChapters,
+				StartDate:      start.Format(dateLayout),
+				EndDate:        end.Format(dateLayout),
+			}
+			if err := s.repo.Upsert(ctx, goal); err != nil {
+				return nil, apperrors.Internal("failed to roll over reading goal", err)
+			}
+		}
+
+		progress, err := s.progress(ctx, *goal)
+		if err != nil {
+			return nil, apperrors.Internal("failed to compute goal progress", err)
+		}
+		result = append(result, *progress)
+	}
+	return result, nil
+}
+
+// DeleteGoal removes one of the user's goals
+func (s *service) DeleteGoal(ctx context.Context, userID, goalID string) error {
+	deleted, err := s.repo.Delete(ctx, goalID, userID)
+	if err != nil {
+		return apperrors.Internal("failed to delete reading goal", err)
+	}
+	if !deleted {
+		return apperrors.NotFound("reading goal not found", nil)
+	}
+	return nil
+}
+
+// progress counts the chapters read in a goal's window
+func (s *service) progress(ctx context.Context, goal models.ReadingGoal) (*models.GoalProgress, error) {
+	read, err := s.repo.CountChapters(ctx, goal.UserID, goal.StartDate, goal.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &models.GoalProgress{
+		ReadingGoal:  goal,
+		ChaptersRead: read,
+		Completed:    read >= goal.TargetChapters,
+	}
+	p.Percentage = math.Min(100, math.Round(float64(read)/float64(goal.TargetChapters)*1000)/10)
+
+	if end, err := time.Parse(dateLayout, goal.EndDate); err == nil {
+		if days := int(end.Sub(s.today()).Hours()/24) + 1; days > 0 {
+			p.DaysLeft = days
+		}
+	}
+	return p, nil
+}
+
+// periodWindow returns the first and last day of the period containing day
+func periodWindow(period string, day time.Time) (time.Time, time.Time) {
+	if period == models.GoalPeriodMonthly {
+		start := time.Date(day.Year(), day.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return start, start.AddDate(0, 1, -1)
+	}
+	start := time.Date(day.Year(), time.January, 1, 0, 0, 0, 0, time.UTC)
+	return start, start.AddDate(1, 0, -1)
+}
@@ -0,0 +1,247 @@
+// Package grpc - Catalog Mutation RPCs
+// CreateManga / UpdateManga / DeleteManga cho internal tools (import pipeline)
+// Chức năng:
+//   - Xác thực bằng metadata x-api-key (gRPC chỉ dùng nội bộ)
+//   - Ghi qua manga.Repository, genre liên kết qua manga_genres
+//   - UpdateManga chỉ ghi các field trong update_fields, đổi status thì gửi UDP status_change
+//   - Field UpdateManga ghi được đánh dấu "manual": import/resync sau đó không ghi đè
+//   - DeleteManga dựa vào ON DELETE CASCADE của các FK
+package grpc
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"strings"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "mangahub/internal/grpc/pb"
+	"mangahub/internal/udp"
+	"mangahub/pkg/importer"
+	"mangahub/pkg/logger"
+	"mangahub/pkg/models"
+)
+
+// apiKeyHeader is the metadata key carrying the mutation API key
+const apiKeyHeader = "x-api-key"
+
+var (
+	validStatuses = map[string]bool{"ongoing": true, "completed": true, "hiatus": true, "cancelled": true}
+	validTypes    = map[string]bool{"manga": true, "manhwa": true, "manhua": true, "novel": true}
+)
+
+// SetAPIKey sets the key mutation RPCs require; empty disables them
+func (s *MangaServiceServer) SetAPIKey(key string) {
+	s.apiKey = key
+}
+
+// authorize checks the caller's x-api-key metadata against the configured key
+func (s *MangaServiceServer) authorize(ctx context.Context) error {
+	if s.apiKey == "" {
+		return status.Error(codes.PermissionDenied, "catalog mutations are disabled: no API key configured")
+	}
+	md, _ := metadata.FromIncomingContext(ctx)
+	keys := md.Get(apiKeyHeader)
+	if len(keys) == 0 {
+		return status.Error(codes.Unauthenticated, "missing "+apiKeyHeader+" metadata")
+	}
+	if subtle.ConstantTimeCompare([]byte(keys[0]), []byte(s.apiKey)) != 1 {
+		return status.Error(codes.Unauthenticated, "invalid API key")
+	}
+	return nil
+}
+
+// CreateManga adds a manga to the catalog. Status and type default to
+// "ongoing" and "manga"; unknown genres are created.
+func (s *MangaServiceServer) CreateManga(ctx context.Context, req *pb.CreateMangaRequest) (*pb.MangaResponse, error) {
+	logger.GRPC("CreateManga", "id="+req.Id, 0)
+
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	in := req.GetManga()
+	if in == nil {
+		return nil, status.Error(codes.InvalidArgument, "manga is required")
+	}
+
+	m := &models.Manga{
+		ID:            strings.TrimSpace(req.Id),
+		Title:         strings.TrimSpace(in.Title),
+		Author:        in.Author,
+		Artist:        in.Artist,
+		Description:   in.Description,
+		CoverURL:      in.CoverUrl,
+		Status:        in.Status,
+		Type:          in.Type,
+		TotalChapters: int(in.TotalChapters),
+		Year:          int(in.Year),
+	}
+	if m.Status == "" {
+		m.Status = "ongoing"
+	}
+	if m.Type == "" {
+		m.Type = "manga"
+	}
+	if err := validateManga(m); err != nil {
+		return nil, err
+	}
+
+	if err := s.manga.Create(ctx, m, in.Genres); err != nil {
+		return nil, toStatus("CreateManga", err)
+	}
+	logger.Infof("gRPC: CreateManga created %s (%s)", m.ID, m.Title)
+	return toMangaResponse(m), nil
+}
+
+// UpdateManga writes the MangaInput fields listed in update_fields.
+// "genres" replaces the manga's genre links with the given list.
+// The written fields are marked manual, so imports and resyncs keep them.
+func (s *MangaServiceServer) UpdateManga(ctx context.Context, req *pb.UpdateMangaRequest) (*pb.MangaResponse, error) {
+	logger.GRPC("UpdateManga", fmt.Sprintf("manga_id=%s fields=%v", req.MangaId, req.UpdateFields), 0)
+
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if req.MangaId == "" {
+		return nil, status.Error(codes.InvalidArgument, "manga_id is required")
+	}
+	if len(req.UpdateFields) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "update_fields is required")
+	}
+	in := req.GetManga()
+	if in == nil {
+		in = &pb.MangaInput{}
+	}
+
+	m, err := s.manga.GetByID(ctx, req.MangaId)
+	if err != nil {
+		return nil, toStatus("UpdateManga", err)
+	}
+	previousStatus := m.Status
+
+	var genres []string
+	for _, field := range req.UpdateFields {
+		switch field {
+		case "title":
+			m.Title = strings.TrimSpace(in.Title)
+		case "author":
+			m.Author = in.Author
+		case "artist":
+			m.Artist = in.Artist
+		case "description":
+			m.Description = in.Description
+		case "cover_url":
+			m.CoverURL = in.CoverUrl
+		case "status":
+			m.Status = in.Status
+		case "type":
+			m.Type = in.Type
+		case "total_chapters":
+			m.TotalChapters = int(in.TotalChapters)
+		case "year":
+			m.Year = int(in.Year)
+		case "genres":
+			// Non-nil even when empty, so an empty list clears the genres
+			genres = append([]string{}, in.Genres...)
+		default:
+			return nil, status.Errorf(codes.InvalidArgument, "unknown update field %q", field)
+		}
+	}
+	if err := validateManga(m); err != nil {
+		return nil, err
+	}
+
+	if err := s.manga.Update(ctx, m, genres); err != nil {
+		return nil, toStatus("UpdateManga", err)
+	}
+	// Hand edits win over later imports and resyncs
+	if err := importer.MarkManual(ctx, s.db, m.ID, req.UpdateFields...); err != nil {
+		return nil, toStatus("UpdateManga", err)
+	}
+	logger.Infof("gRPC: UpdateManga updated %s", m.ID)
+
+	// The status history row and activity entry come from the manga_status_change trigger
+	if m.Status != previousStatus && s.notify != nil {
+		if err := s.notify(udp.NewStatusChangeNotification(m.ID, m.Title, previousStatus, m.Status)); err != nil {
+			logger.Warnf("gRPC: status_change broadcast for %s failed: %v", m.ID, err)
+		}
+	}
+	return toMangaResponse(m), nil
+}
+
+// DeleteManga removes a manga; the FK cascades remove its progress,
+// ratings, comments, chapters, genre links and list items
+func (s *MangaServiceServer) DeleteManga(ctx context.Context, req *pb.DeleteMangaRequest) (*pb.DeleteMangaResponse, error) {
+	logger.GRPC("DeleteManga", "manga_id="+req.MangaId, 0)
+
+	if err := s.authorize(ctx); err != nil {
+		return nil, err
+	}
+	if req.MangaId == "" {
+		return nil, status.Error(codes.InvalidArgument, "manga_id is required")
+	}
+
+	if err := s.manga.Delete(ctx, req.MangaId); err != nil {
+		return nil, toStatus("DeleteManga", err)
+	}
+	logger.Infof("gRPC: DeleteManga deleted %s", req.MangaId)
+	return &pb.DeleteMangaResponse{MangaId: req.MangaId}, nil
+}
+
+// validateManga checks the fields the manga table constrains
+func validateManga(m *models.Manga) error {
+	switch {
+	case m.Title == "":
+		return status.Error(codes.InvalidArgument, "title is required")
+	case !validStatuses[m.Status]:
+		return status.Errorf(codes.InvalidArgument, "invalid status %q", m.Status)
+	case !validTypes[m.Type]:
+		return status.Errorf(codes.InvalidArgument, "invalid type %q", m.Type)
+	case m.TotalChapters < 0:
+		return status.Error(codes.InvalidArgument, "total_chapters must not be negative")
+	case m.Year < 0:
+		return status.Error(codes.InvalidArgument, "year must not be negative")
+	}
+	return nil
+}
+
+// toStatus maps repository errors to gRPC status codes
+func toStatus(method string, err error) error {
+	var appErr *models.AppError
+	if errors.As(err, &appErr) {
+		switch appErr.StatusCode {
+		case 404:
+			return status.Error(codes.NotFound, appErr.Message)
+		case 409:
+			return status.Error(codes.AlreadyExists, appErr.Message)
+		}
+	}
+	logger.Errorf("gRPC: %s failed: %v", method, err)
+	return status.Error(codes.Internal, "internal error")
+}
+
+// toMangaResponse converts a stored manga to its protobuf message
+func toMangaResponse(m *models.Manga) *pb.MangaResponse {
+	resp := &pb.MangaResponse{
+		Id:            m.ID,
+		Title:         m.Title,
+		Author:        m.Author,
+		Artist:        m.Artist,
+		Description:   m.Description,
+		CoverUrl:      m.CoverURL,
+		Status:        m.Status,
+		Type:          m.Type,
+		TotalChapters: int32(m.TotalChapters),
+		AverageRating: m.AverageRating,
+		RatingCount:   int32(m.RatingCount),
+		Year:          int32(m.Year),
+	}
+	for _, g := range m.Genres {
+		resp.Genres = append(resp.Genres, &pb.Genre{Id: g.ID, Name: g.Name, Slug: g.Slug})
+	}
+	return resp
+}
@@ -0,0 +1,75 @@
+// Package grpc - Catalog Mutation Tests
+// Unit tests cho API key check và update_fields của mutation RPCs
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	pb "mangahub/internal/grpc/pb"
+	"mangahub/internal/testutil"
+)
+
+func setupTestServer(t *testing.T, apiKey string) *MangaServiceServer {
+	s := NewMangaServiceServer(testutil.OpenDB(t))
+	s.SetAPIKey(apiKey)
+	return s
+}
+
+func withKey(key string) context.Context {
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(apiKeyHeader, key))
+}
+
+func TestMutationsRequireAPIKey(t *testing.T) {
+	req := &pb.CreateMangaRequest{Manga: &pb.MangaInput{Title: "Dorohedoro"}}
+
+	disabled := setupTestServer(t, "")
+	if _, err := disabled.CreateManga(withKey(""), req); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("no configured key: got %v, want PermissionDenied", err)
+	}
+
+	s := setupTestServer(t, "secret")
+	if _, err := s.CreateManga(context.Background(), req); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("missing key: got %v, want Unauthenticated", err)
+	}
+	if _, err := s.DeleteManga(withKey("wrong"), &pb.DeleteMangaRequest{MangaId: "x"}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("wrong key: got %v, want Unauthenticated", err)
+	}
+
+	created, err := s.CreateManga(withKey("secret"), req)
+	if err != nil {
+		t.Fatalf("CreateManga failed: %v", err)
+	}
+	if created.Status != "ongoing" || created.Type != "manga" {
+		t.Errorf("defaults not applied: status=%q type=%q", created.Status, created.Type)
+	}
+
+	// Only the listed fields are written
+	updated, err := s.UpdateManga(withKey("secret"), &pb.UpdateMangaRequest{
+		MangaId:      created.Id,
+		Manga:        &pb.MangaInput{Title: "ignored", Status: "completed"},
+		UpdateFields: []string{"status"},
+	})
+	if err != nil {
+		t.Fatalf("UpdateManga failed: %v", err)
+	}
+	if updated.Title != "Dorohedoro" || updated.Status != "completed" {
+		t.Errorf("update wrote title=%q status=%q", updated.Title, updated.Status)
+	}
+	if _, err := s.UpdateManga(withKey("secret"), &pb.UpdateMangaRequest{
+		MangaId: created.Id, UpdateFields: []string{"rating_count"},
+	}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("unknown field: got %v, want InvalidArgument", err)
+	}
+
+	if _, err := s.DeleteManga(withKey("secret"), &pb.DeleteMangaRequest{MangaId: created.Id}); err != nil {
+		t.Fatalf("DeleteManga failed: %v", err)
+	}
+	if _, err := s.DeleteManga(withKey("secret"), &pb.DeleteMangaRequest{MangaId: created.Id}); status.Code(err) != codes.NotFound {
+		t.Errorf("second delete: got %v, want NotFound", err)
+	}
+}
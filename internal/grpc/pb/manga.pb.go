@@ -1,17 +1,17 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // versions:
-// 	protoc-gen-go v1.35.2
+// 	protoc-gen-go v1.36.10
 // 	protoc        v6.33.1
 // source: proto/manga.proto
 
 package pb
 
 import (
-	reflect "reflect"
-	sync "sync"
-
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
 )
 
 const (
@@ -23,11 +23,10 @@ const (
 
 // Request to get a single manga by ID
 type GetMangaRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MangaId       string                 `protobuf:"bytes,1,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	MangaId string `protobuf:"bytes,1,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *GetMangaRequest) Reset() {
@@ -69,13 +68,12 @@ func (x *GetMangaRequest) GetMangaId() string {
 
 // Genre message
 type Genre struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Slug          string                 `protobuf:"bytes,3,opt,name=slug,proto3" json:"slug,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id   string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Name string `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Slug string `protobuf:"bytes,3,opt,name=slug,proto3" json:"slug,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *Genre) Reset() {
@@ -103,7 +101,8 @@ func (x *Genre) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-func (x *Genre) Descriptor() ([]byte, []int) {
+// Deprecated: Use Genre.ProtoReflect.Descriptor instead.
+func (*Genre) Descriptor() ([]byte, []int) {
 	return file_proto_manga_proto_rawDescGZIP(), []int{1}
 }
 
@@ -130,23 +129,22 @@ func (x *Genre) GetSlug() string {
 
 // Manga data response
 type MangaResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Title         string                 `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
+	Author        string                 `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
+	Artist        string                 `protobuf:"bytes,4,opt,name=artist,proto3" json:"artist,omitempty"`
+	Description   string                 `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
+	CoverUrl      string                 `protobuf:"bytes,6,opt,name=cover_url,json=coverUrl,proto3" json:"cover_url,omitempty"`
+	Status        string                 `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
+	Type          string                 `protobuf:"bytes,8,opt,name=type,proto3" json:"type,omitempty"`
+	Genres        []*Genre               `protobuf:"bytes,9,rep,name=genres,proto3" json:"genres,omitempty"`
+	TotalChapters int32                  `protobuf:"varint,10,opt,name=total_chapters,json=totalChapters,proto3" json:"total_chapters,omitempty"`
+	AverageRating float64                `protobuf:"fixed64,11,opt,name=average_rating,json=averageRating,proto3" json:"average_rating,omitempty"`
+	RatingCount   int32                  `protobuf:"varint,12,opt,name=rating_count,json=ratingCount,proto3" json:"rating_count,omitempty"`
+	Year          int32                  `protobuf:"varint,13,opt,name=year,proto3" json:"year,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Id            string   `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	Title         string   `protobuf:"bytes,2,opt,name=title,proto3" json:"title,omitempty"`
-	Author        string   `protobuf:"bytes,3,opt,name=author,proto3" json:"author,omitempty"`
-	Artist        string   `protobuf:"bytes,4,opt,name=artist,proto3" json:"artist,omitempty"`
-	Description   string   `protobuf:"bytes,5,opt,name=description,proto3" json:"description,omitempty"`
-	CoverUrl      string   `protobuf:"bytes,6,opt,name=cover_url,json=coverUrl,proto3" json:"cover_url,omitempty"`
-	Status        string   `protobuf:"bytes,7,opt,name=status,proto3" json:"status,omitempty"`
-	Type          string   `protobuf:"bytes,8,opt,name=type,proto3" json:"type,omitempty"`
-	Genres        []*Genre `protobuf:"bytes,9,rep,name=genres,proto3" json:"genres,omitempty"`
-	TotalChapters int32    `protobuf:"varint,10,opt,name=total_chapters,json=totalChapters,proto3" json:"total_chapters,omitempty"`
-	AverageRating float64  `protobuf:"fixed64,11,opt,name=average_rating,json=averageRating,proto3" json:"average_rating,omitempty"`
-	RatingCount   int32    `protobuf:"varint,12,opt,name=rating_count,json=ratingCount,proto3" json:"rating_count,omitempty"`
-	Year          int32    `protobuf:"varint,13,opt,name=year,proto3" json:"year,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *MangaResponse) Reset() {
@@ -272,15 +270,14 @@ func (x *MangaResponse) GetYear() int32 {
 
 // Search request with filters
 type SearchRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Genres        []string               `protobuf:"bytes,2,rep,name=genres,proto3" json:"genres,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
+	Limit         int32                  `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Query  string   `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
-	Genres []string `protobuf:"bytes,2,rep,name=genres,proto3" json:"genres,omitempty"`
-	Status string   `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"`
-	Limit  int32    `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset int32    `protobuf:"varint,5,opt,name=offset,proto3" json:"offset,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SearchRequest) Reset() {
@@ -350,14 +347,13 @@ func (x *SearchRequest) GetOffset() int32 {
 
 // Search results response
 type SearchResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Manga         []*MangaResponse       `protobuf:"bytes,1,rep,name=manga,proto3" json:"manga,omitempty"`
+	Total         int32                  `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
+	Limit         int32                  `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
 	unknownFields protoimpl.UnknownFields
-
-	Manga  []*MangaResponse `protobuf:"bytes,1,rep,name=manga,proto3" json:"manga,omitempty"`
-	Total  int32            `protobuf:"varint,2,opt,name=total,proto3" json:"total,omitempty"`
-	Limit  int32            `protobuf:"varint,3,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset int32            `protobuf:"varint,4,opt,name=offset,proto3" json:"offset,omitempty"`
+	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SearchResponse) Reset() {
@@ -420,15 +416,13 @@ func (x *SearchResponse) GetOffset() int32 {
 
 // Progress update request
 type ProgressRequest struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	UserId         string `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	MangaId        string `protobuf:"bytes,2,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
-	CurrentChapter int32  `protobuf:"varint,3,opt,name=current_chapter,json=currentChapter,proto3" json:"current_chapter,omitempty"`
-	Status         string `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
-	Rating         int32  `protobuf:"varint,5,opt,name=rating,proto3" json:"rating,omitempty"`
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	UserId         string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MangaId        string                 `protobuf:"bytes,2,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	CurrentChapter int32                  `protobuf:"varint,3,opt,name=current_chapter,json=currentChapter,proto3" json:"current_chapter,omitempty"`
+	Status         string                 `protobuf:"bytes,4,opt,name=status,proto3" json:"status,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ProgressRequest) Reset() {
@@ -489,26 +483,17 @@ func (x *ProgressRequest) GetStatus() string {
 	return ""
 }
 
-func (x *ProgressRequest) GetRating() int32 {
-	if x != nil {
-		return x.Rating
-	}
-	return 0
-}
-
 // Progress update response
 type ProgressResponse struct {
-	state         protoimpl.MessageState
-	sizeCache     protoimpl.SizeCache
-	unknownFields protoimpl.UnknownFields
-
-	Id             string `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
-	UserId         string `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	MangaId        string `protobuf:"bytes,3,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
-	CurrentChapter int32  `protobuf:"varint,4,opt,name=current_chapter,json=currentChapter,proto3" json:"current_chapter,omitempty"`
-	Status         string `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
-	Rating         int32  `protobuf:"varint,6,opt,name=rating,proto3" json:"rating,omitempty"`
-	Timestamp      int64  `protobuf:"varint,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Id             string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId         string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MangaId        string                 `protobuf:"bytes,3,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	CurrentChapter int32                  `protobuf:"varint,4,opt,name=current_chapter,json=currentChapter,proto3" json:"current_chapter,omitempty"`
+	Status         string                 `protobuf:"bytes,5,opt,name=status,proto3" json:"status,omitempty"`
+	Timestamp      int64                  `protobuf:"varint,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *ProgressResponse) Reset() {
@@ -576,143 +561,655 @@ func (x *ProgressResponse) GetStatus() string {
 	return ""
 }
 
-func (x *ProgressResponse) GetRating() int32 {
+func (x *ProgressResponse) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+// Live activity feed subscription, empty filters match everything
+type StreamActivityRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	MangaId       string                 `protobuf:"bytes,2,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamActivityRequest) Reset() {
+	*x = StreamActivityRequest{}
+	mi := &file_proto_manga_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamActivityRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamActivityRequest) ProtoMessage() {}
+
+func (x *StreamActivityRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamActivityRequest.ProtoReflect.Descriptor instead.
+func (*StreamActivityRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *StreamActivityRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *StreamActivityRequest) GetMangaId() string {
+	if x != nil {
+		return x.MangaId
+	}
+	return ""
+}
+
+// A single activity feed entry
+type ActivityEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Username      string                 `protobuf:"bytes,3,opt,name=username,proto3" json:"username,omitempty"`
+	ActivityType  string                 `protobuf:"bytes,4,opt,name=activity_type,json=activityType,proto3" json:"activity_type,omitempty"`
+	MangaId       string                 `protobuf:"bytes,5,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	MangaTitle    string                 `protobuf:"bytes,6,opt,name=manga_title,json=mangaTitle,proto3" json:"manga_title,omitempty"`
+	ChapterNumber int32                  `protobuf:"varint,7,opt,name=chapter_number,json=chapterNumber,proto3" json:"chapter_number,omitempty"`
+	Rating        float64                `protobuf:"fixed64,8,opt,name=rating,proto3" json:"rating,omitempty"`
+	CommentText   string                 `protobuf:"bytes,9,opt,name=comment_text,json=commentText,proto3" json:"comment_text,omitempty"`
+	Timestamp     int64                  `protobuf:"varint,10,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ActivityEvent) Reset() {
+	*x = ActivityEvent{}
+	mi := &file_proto_manga_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ActivityEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ActivityEvent) ProtoMessage() {}
+
+func (x *ActivityEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ActivityEvent.ProtoReflect.Descriptor instead.
+func (*ActivityEvent) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ActivityEvent) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ActivityEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *ActivityEvent) GetUsername() string {
+	if x != nil {
+		return x.Username
+	}
+	return ""
+}
+
+func (x *ActivityEvent) GetActivityType() string {
+	if x != nil {
+		return x.ActivityType
+	}
+	return ""
+}
+
+func (x *ActivityEvent) GetMangaId() string {
+	if x != nil {
+		return x.MangaId
+	}
+	return ""
+}
+
+func (x *ActivityEvent) GetMangaTitle() string {
+	if x != nil {
+		return x.MangaTitle
+	}
+	return ""
+}
+
+func (x *ActivityEvent) GetChapterNumber() int32 {
+	if x != nil {
+		return x.ChapterNumber
+	}
+	return 0
+}
+
+func (x *ActivityEvent) GetRating() float64 {
 	if x != nil {
 		return x.Rating
 	}
 	return 0
 }
 
-func (x *ProgressResponse) GetTimestamp() int64 {
+func (x *ActivityEvent) GetCommentText() string {
+	if x != nil {
+		return x.CommentText
+	}
+	return ""
+}
+
+func (x *ActivityEvent) GetTimestamp() int64 {
 	if x != nil {
 		return x.Timestamp
 	}
 	return 0
 }
 
-var File_proto_manga_proto protoreflect.FileDescriptor
+// Manga fields written by CreateManga and UpdateManga
+type MangaInput struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Title         string                 `protobuf:"bytes,1,opt,name=title,proto3" json:"title,omitempty"`
+	Author        string                 `protobuf:"bytes,2,opt,name=author,proto3" json:"author,omitempty"`
+	Artist        string                 `protobuf:"bytes,3,opt,name=artist,proto3" json:"artist,omitempty"`
+	Description   string                 `protobuf:"bytes,4,opt,name=description,proto3" json:"description,omitempty"`
+	CoverUrl      string                 `protobuf:"bytes,5,opt,name=cover_url,json=coverUrl,proto3" json:"cover_url,omitempty"`
+	Status        string                 `protobuf:"bytes,6,opt,name=status,proto3" json:"status,omitempty"`
+	Type          string                 `protobuf:"bytes,7,opt,name=type,proto3" json:"type,omitempty"`
+	TotalChapters int32                  `protobuf:"varint,8,opt,name=total_chapters,json=totalChapters,proto3" json:"total_chapters,omitempty"`
+	Year          int32                  `protobuf:"varint,9,opt,name=year,proto3" json:"year,omitempty"`
+	Genres        []string               `protobuf:"bytes,10,rep,name=genres,proto3" json:"genres,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
 
-var file_proto_manga_proto_rawDesc = []byte{
-	0x0a, 0x11, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x6d, 0x61, 0x6e, 0x67, 0x61, 0x2e, 0x70, 0x72,
-	0x6f, 0x74, 0x6f, 0x12, 0x0b, 0x6d, 0x61, 0x6e, 0x67, 0x61, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31,
-	0x22, 0x2c, 0x0a, 0x0f, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x6e, 0x67, 0x61, 0x52, 0x65, 0x71, 0x75,
-	0x65, 0x73, 0x74, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61, 0x6e, 0x67, 0x61, 0x5f, 0x69, 0x64, 0x18,
-	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x61, 0x6e, 0x67, 0x61, 0x49, 0x64, 0x22, 0xbb,
-	0x02, 0x0a, 0x0d, 0x4d, 0x61, 0x6e, 0x67, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69, 0x64,
-	0x12, 0x14, 0x0a, 0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
-	0x05, 0x74, 0x69, 0x74, 0x6c, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72,
-	0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x61, 0x75, 0x74, 0x68, 0x6f, 0x72, 0x12, 0x16,
-	0x0a, 0x06, 0x61, 0x72, 0x74, 0x69, 0x73, 0x74, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x61, 0x72, 0x74, 0x69, 0x73, 0x74, 0x12, 0x20, 0x0a, 0x0b, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
-	0x70, 0x74, 0x69, 0x6f, 0x6e, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0b, 0x64, 0x65, 0x73,
-	0x63, 0x72, 0x69, 0x70, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x1b, 0x0a, 0x09, 0x63, 0x6f, 0x76, 0x65,
-	0x72, 0x5f, 0x75, 0x72, 0x6c, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x63, 0x6f, 0x76,
-	0x65, 0x72, 0x55, 0x72, 0x6c, 0x12, 0x16, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18,
-	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x12, 0x0a,
-	0x04, 0x74, 0x79, 0x70, 0x65, 0x18, 0x08, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x74, 0x79, 0x70,
-	0x65, 0x12, 0x16, 0x0a, 0x06, 0x67, 0x65, 0x6e, 0x72, 0x65, 0x73, 0x18, 0x09, 0x20, 0x03, 0x28,
-	0x09, 0x52, 0x06, 0x67, 0x65, 0x6e, 0x72, 0x65, 0x73, 0x12, 0x25, 0x0a, 0x0e, 0x74, 0x6f, 0x74,
-	0x61, 0x6c, 0x5f, 0x63, 0x68, 0x61, 0x70, 0x74, 0x65, 0x72, 0x73, 0x18, 0x0a, 0x20, 0x01, 0x28,
-	0x05, 0x52, 0x0d, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x43, 0x68, 0x61, 0x70, 0x74, 0x65, 0x72, 0x73,
-	0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x01,
-	0x52, 0x06, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x12, 0x0a, 0x04, 0x79, 0x65, 0x61, 0x72,
-	0x18, 0x0c, 0x20, 0x01, 0x28, 0x05, 0x52, 0x04, 0x79, 0x65, 0x61, 0x72, 0x22, 0x83, 0x01, 0x0a,
-	0x0d, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x14,
-	0x0a, 0x05, 0x71, 0x75, 0x65, 0x72, 0x79, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x71,
-	0x75, 0x65, 0x72, 0x79, 0x12, 0x16, 0x0a, 0x06, 0x67, 0x65, 0x6e, 0x72, 0x65, 0x73, 0x18, 0x02,
-	0x20, 0x03, 0x28, 0x09, 0x52, 0x06, 0x67, 0x65, 0x6e, 0x72, 0x65, 0x73, 0x12, 0x16, 0x0a, 0x06,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x12, 0x14, 0x0a, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66,
-	0x66, 0x73, 0x65, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73,
-	0x65, 0x74, 0x22, 0x86, 0x01, 0x0a, 0x0e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x73,
-	0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x30, 0x0a, 0x05, 0x6d, 0x61, 0x6e, 0x67, 0x61, 0x18, 0x01,
-	0x20, 0x03, 0x28, 0x0b, 0x32, 0x1a, 0x2e, 0x6d, 0x61, 0x6e, 0x67, 0x61, 0x68, 0x75, 0x62, 0x2e,
-	0x76, 0x31, 0x2e, 0x4d, 0x61, 0x6e, 0x67, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65,
-	0x52, 0x05, 0x6d, 0x61, 0x6e, 0x67, 0x61, 0x12, 0x14, 0x0a, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c,
-	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x74, 0x6f, 0x74, 0x61, 0x6c, 0x12, 0x14, 0x0a,
-	0x05, 0x6c, 0x69, 0x6d, 0x69, 0x74, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x05, 0x6c, 0x69,
-	0x6d, 0x69, 0x74, 0x12, 0x16, 0x0a, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x18, 0x04, 0x20,
-	0x01, 0x28, 0x05, 0x52, 0x06, 0x6f, 0x66, 0x66, 0x73, 0x65, 0x74, 0x22, 0x9e, 0x01, 0x0a, 0x0f,
-	0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12,
-	0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09,
-	0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61, 0x6e, 0x67,
-	0x61, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x61, 0x6e, 0x67,
-	0x61, 0x49, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x63,
-	0x68, 0x61, 0x70, 0x74, 0x65, 0x72, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e, 0x63, 0x75,
-	0x72, 0x72, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x61, 0x70, 0x74, 0x65, 0x72, 0x12, 0x16, 0x0a, 0x06,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x04, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06, 0x73, 0x74,
-	0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x18, 0x05,
-	0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x22, 0xcd, 0x01, 0x0a,
-	0x10, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73,
-	0x65, 0x12, 0x0e, 0x0a, 0x02, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x02, 0x69,
-	0x64, 0x12, 0x17, 0x0a, 0x07, 0x75, 0x73, 0x65, 0x72, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01,
-	0x28, 0x09, 0x52, 0x06, 0x75, 0x73, 0x65, 0x72, 0x49, 0x64, 0x12, 0x19, 0x0a, 0x08, 0x6d, 0x61,
-	0x6e, 0x67, 0x61, 0x5f, 0x69, 0x64, 0x18, 0x03, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x61,
-	0x6e, 0x67, 0x61, 0x49, 0x64, 0x12, 0x27, 0x0a, 0x0f, 0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74,
-	0x5f, 0x63, 0x68, 0x61, 0x70, 0x74, 0x65, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0e,
-	0x63, 0x75, 0x72, 0x72, 0x65, 0x6e, 0x74, 0x43, 0x68, 0x61, 0x70, 0x74, 0x65, 0x72, 0x12, 0x16,
-	0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x06,
-	0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x16, 0x0a, 0x06, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67,
-	0x18, 0x06, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x72, 0x61, 0x74, 0x69, 0x6e, 0x67, 0x12, 0x1c,
-	0x0a, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x18, 0x07, 0x20, 0x01, 0x28,
-	0x03, 0x52, 0x09, 0x74, 0x69, 0x6d, 0x65, 0x73, 0x74, 0x61, 0x6d, 0x70, 0x32, 0xeb, 0x01, 0x0a,
-	0x0c, 0x4d, 0x61, 0x6e, 0x67, 0x61, 0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x44, 0x0a,
-	0x08, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x6e, 0x67, 0x61, 0x12, 0x1c, 0x2e, 0x6d, 0x61, 0x6e, 0x67,
-	0x61, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x47, 0x65, 0x74, 0x4d, 0x61, 0x6e, 0x67, 0x61,
-	0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1a, 0x2e, 0x6d, 0x61, 0x6e, 0x67, 0x61, 0x68,
-	0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x4d, 0x61, 0x6e, 0x67, 0x61, 0x52, 0x65, 0x73, 0x70, 0x6f,
-	0x6e, 0x73, 0x65, 0x12, 0x46, 0x0a, 0x0b, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x4d, 0x61, 0x6e,
-	0x67, 0x61, 0x12, 0x1a, 0x2e, 0x6d, 0x61, 0x6e, 0x67, 0x61, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31,
-	0x2e, 0x53, 0x65, 0x61, 0x72, 0x63, 0x68, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1b,
-	0x2e, 0x6d, 0x61, 0x6e, 0x67, 0x61, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x53, 0x65, 0x61,
-	0x72, 0x63, 0x68, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x12, 0x4d, 0x0a, 0x0e, 0x55,
-	0x70, 0x64, 0x61, 0x74, 0x65, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x12, 0x1c, 0x2e,
-	0x6d, 0x61, 0x6e, 0x67, 0x61, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x67,
-	0x72, 0x65, 0x73, 0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x1d, 0x2e, 0x6d, 0x61,
-	0x6e, 0x67, 0x61, 0x68, 0x75, 0x62, 0x2e, 0x76, 0x31, 0x2e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65,
-	0x73, 0x73, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x42, 0x35, 0x5a, 0x33, 0x67, 0x69,
-	0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6e, 0x6d, 0x69, 0x68, 0x74, 0x75, 0x6e,
-	0x61, 0x32, 0x30, 0x34, 0x2f, 0x6d, 0x61, 0x6e, 0x67, 0x61, 0x68, 0x75, 0x62, 0x2f, 0x69, 0x6e,
-	0x74, 0x65, 0x72, 0x6e, 0x61, 0x6c, 0x2f, 0x67, 0x72, 0x70, 0x63, 0x2f, 0x70, 0x62, 0x3b, 0x70,
-	0x62, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+func (x *MangaInput) Reset() {
+	*x = MangaInput{}
+	mi := &file_proto_manga_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *MangaInput) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*MangaInput) ProtoMessage() {}
+
+func (x *MangaInput) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use MangaInput.ProtoReflect.Descriptor instead.
+func (*MangaInput) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *MangaInput) GetTitle() string {
+	if x != nil {
+		return x.Title
+	}
+	return ""
+}
+
+func (x *MangaInput) GetAuthor() string {
+	if x != nil {
+		return x.Author
+	}
+	return ""
+}
+
+func (x *MangaInput) GetArtist() string {
+	if x != nil {
+		return x.Artist
+	}
+	return ""
 }
 
+func (x *MangaInput) GetDescription() string {
+	if x != nil {
+		return x.Description
+	}
+	return ""
+}
+
+func (x *MangaInput) GetCoverUrl() string {
+	if x != nil {
+		return x.CoverUrl
+	}
+	return ""
+}
+
+func (x *MangaInput) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *MangaInput) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *MangaInput) GetTotalChapters() int32 {
+	if x != nil {
+		return x.TotalChapters
+	}
+	return 0
+}
+
+func (x *MangaInput) GetYear() int32 {
+	if x != nil {
+		return x.Year
+	}
+	return 0
+}
+
+func (x *MangaInput) GetGenres() []string {
+	if x != nil {
+		return x.Genres
+	}
+	return nil
+}
+
+// Create a manga; an ID is generated when id is empty
+type CreateMangaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Manga         *MangaInput            `protobuf:"bytes,2,opt,name=manga,proto3" json:"manga,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateMangaRequest) Reset() {
+	*x = CreateMangaRequest{}
+	mi := &file_proto_manga_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateMangaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateMangaRequest) ProtoMessage() {}
+
+func (x *CreateMangaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateMangaRequest.ProtoReflect.Descriptor instead.
+func (*CreateMangaRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *CreateMangaRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateMangaRequest) GetManga() *MangaInput {
+	if x != nil {
+		return x.Manga
+	}
+	return nil
+}
+
+// Update the MangaInput fields named in update_fields, e.g. "title", "genres"
+type UpdateMangaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MangaId       string                 `protobuf:"bytes,1,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	Manga         *MangaInput            `protobuf:"bytes,2,opt,name=manga,proto3" json:"manga,omitempty"`
+	UpdateFields  []string               `protobuf:"bytes,3,rep,name=update_fields,json=updateFields,proto3" json:"update_fields,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateMangaRequest) Reset() {
+	*x = UpdateMangaRequest{}
+	mi := &file_proto_manga_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateMangaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateMangaRequest) ProtoMessage() {}
+
+func (x *UpdateMangaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateMangaRequest.ProtoReflect.Descriptor instead.
+func (*UpdateMangaRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *UpdateMangaRequest) GetMangaId() string {
+	if x != nil {
+		return x.MangaId
+	}
+	return ""
+}
+
+func (x *UpdateMangaRequest) GetManga() *MangaInput {
+	if x != nil {
+		return x.Manga
+	}
+	return nil
+}
+
+func (x *UpdateMangaRequest) GetUpdateFields() []string {
+	if x != nil {
+		return x.UpdateFields
+	}
+	return nil
+}
+
+// Delete a manga and everything that references it
+type DeleteMangaRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MangaId       string                 `protobuf:"bytes,1,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMangaRequest) Reset() {
+	*x = DeleteMangaRequest{}
+	mi := &file_proto_manga_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMangaRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMangaRequest) ProtoMessage() {}
+
+func (x *DeleteMangaRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMangaRequest.ProtoReflect.Descriptor instead.
+func (*DeleteMangaRequest) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteMangaRequest) GetMangaId() string {
+	if x != nil {
+		return x.MangaId
+	}
+	return ""
+}
+
+// Deleted manga
+type DeleteMangaResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	MangaId       string                 `protobuf:"bytes,1,opt,name=manga_id,json=mangaId,proto3" json:"manga_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteMangaResponse) Reset() {
+	*x = DeleteMangaResponse{}
+	mi := &file_proto_manga_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteMangaResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteMangaResponse) ProtoMessage() {}
+
+func (x *DeleteMangaResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_manga_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteMangaResponse.ProtoReflect.Descriptor instead.
+func (*DeleteMangaResponse) Descriptor() ([]byte, []int) {
+	return file_proto_manga_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *DeleteMangaResponse) GetMangaId() string {
+	if x != nil {
+		return x.MangaId
+	}
+	return ""
+}
+
+var File_proto_manga_proto protoreflect.FileDescriptor
+
+const file_proto_manga_proto_rawDesc = "" +
+	"\n" +
+	"\x11proto/manga.proto\x12\vmangahub.v1\",\n" +
+	"\x0fGetMangaRequest\x12\x19\n" +
+	"\bmanga_id\x18\x01 \x01(\tR\amangaId\"?\n" +
+	"\x05Genre\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x12\n" +
+	"\x04slug\x18\x03 \x01(\tR\x04slug\"\x81\x03\n" +
+	"\rMangaResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x14\n" +
+	"\x05title\x18\x02 \x01(\tR\x05title\x12\x16\n" +
+	"\x06author\x18\x03 \x01(\tR\x06author\x12\x16\n" +
+	"\x06artist\x18\x04 \x01(\tR\x06artist\x12 \n" +
+	"\vdescription\x18\x05 \x01(\tR\vdescription\x12\x1b\n" +
+	"\tcover_url\x18\x06 \x01(\tR\bcoverUrl\x12\x16\n" +
+	"\x06status\x18\a \x01(\tR\x06status\x12\x12\n" +
+	"\x04type\x18\b \x01(\tR\x04type\x12*\n" +
+	"\x06genres\x18\t \x03(\v2\x12.mangahub.v1.GenreR\x06genres\x12%\n" +
+	"\x0etotal_chapters\x18\n" +
+	" \x01(\x05R\rtotalChapters\x12%\n" +
+	"\x0eaverage_rating\x18\v \x01(\x01R\raverageRating\x12!\n" +
+	"\frating_count\x18\f \x01(\x05R\vratingCount\x12\x12\n" +
+	"\x04year\x18\r \x01(\x05R\x04year\"\x83\x01\n" +
+	"\rSearchRequest\x12\x14\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x16\n" +
+	"\x06genres\x18\x02 \x03(\tR\x06genres\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x05 \x01(\x05R\x06offset\"\x86\x01\n" +
+	"\x0eSearchResponse\x120\n" +
+	"\x05manga\x18\x01 \x03(\v2\x1a.mangahub.v1.MangaResponseR\x05manga\x12\x14\n" +
+	"\x05total\x18\x02 \x01(\x05R\x05total\x12\x14\n" +
+	"\x05limit\x18\x03 \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\x04 \x01(\x05R\x06offset\"\x86\x01\n" +
+	"\x0fProgressRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\bmanga_id\x18\x02 \x01(\tR\amangaId\x12'\n" +
+	"\x0fcurrent_chapter\x18\x03 \x01(\x05R\x0ecurrentChapter\x12\x16\n" +
+	"\x06status\x18\x04 \x01(\tR\x06status\"\xb5\x01\n" +
+	"\x10ProgressResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x19\n" +
+	"\bmanga_id\x18\x03 \x01(\tR\amangaId\x12'\n" +
+	"\x0fcurrent_chapter\x18\x04 \x01(\x05R\x0ecurrentChapter\x12\x16\n" +
+	"\x06status\x18\x05 \x01(\tR\x06status\x12\x1c\n" +
+	"\ttimestamp\x18\x06 \x01(\x03R\ttimestamp\"K\n" +
+	"\x15StreamActivityRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x19\n" +
+	"\bmanga_id\x18\x02 \x01(\tR\amangaId\"\xb5\x02\n" +
+	"\rActivityEvent\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1a\n" +
+	"\busername\x18\x03 \x01(\tR\busername\x12#\n" +
+	"\ractivity_type\x18\x04 \x01(\tR\factivityType\x12\x19\n" +
+	"\bmanga_id\x18\x05 \x01(\tR\amangaId\x12\x1f\n" +
+	"\vmanga_title\x18\x06 \x01(\tR\n" +
+	"mangaTitle\x12%\n" +
+	"\x0echapter_number\x18\a \x01(\x05R\rchapterNumber\x12\x16\n" +
+	"\x06rating\x18\b \x01(\x01R\x06rating\x12!\n" +
+	"\fcomment_text\x18\t \x01(\tR\vcommentText\x12\x1c\n" +
+	"\ttimestamp\x18\n" +
+	" \x01(\x03R\ttimestamp\"\x90\x02\n" +
+	"\n" +
+	"MangaInput\x12\x14\n" +
+	"\x05title\x18\x01 \x01(\tR\x05title\x12\x16\n" +
+	"\x06author\x18\x02 \x01(\tR\x06author\x12\x16\n" +
+	"\x06artist\x18\x03 \x01(\tR\x06artist\x12 \n" +
+	"\vdescription\x18\x04 \x01(\tR\vdescription\x12\x1b\n" +
+	"\tcover_url\x18\x05 \x01(\tR\bcoverUrl\x12\x16\n" +
+	"\x06status\x18\x06 \x01(\tR\x06status\x12\x12\n" +
+	"\x04type\x18\a \x01(\tR\x04type\x12%\n" +
+	"\x0etotal_chapters\x18\b \x01(\x05R\rtotalChapters\x12\x12\n" +
+	"\x04year\x18\t \x01(\x05R\x04year\x12\x16\n" +
+	"\x06genres\x18\n" +
+	" \x03(\tR\x06genres\"S\n" +
+	"\x12CreateMangaRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12-\n" +
+	"\x05manga\x18\x02 \x01(\v2\x17.mangahub.v1.MangaInputR\x05manga\"\x83\x01\n" +
+	"\x12UpdateMangaRequest\x12\x19\n" +
+	"\bmanga_id\x18\x01 \x01(\tR\amangaId\x12-\n" +
+	"\x05manga\x18\x02 \x01(\v2\x17.mangahub.v1.MangaInputR\x05manga\x12#\n" +
+	"\rupdate_fields\x18\x03 \x03(\tR\fupdateFields\"/\n" +
+	"\x12DeleteMangaRequest\x12\x19\n" +
+	"\bmanga_id\x18\x01 \x01(\tR\amangaId\"0\n" +
+	"\x13DeleteMangaResponse\x12\x19\n" +
+	"\bmanga_id\x18\x01 \x01(\tR\amangaId2\xa9\x04\n" +
+	"\fMangaService\x12D\n" +
+	"\bGetManga\x12\x1c.mangahub.v1.GetMangaRequest\x1a\x1a.mangahub.v1.MangaResponse\x12F\n" +
+	"\vSearchManga\x12\x1a.mangahub.v1.SearchRequest\x1a\x1b.mangahub.v1.SearchResponse\x12M\n" +
+	"\x0eUpdateProgress\x12\x1c.mangahub.v1.ProgressRequest\x1a\x1d.mangahub.v1.ProgressResponse\x12R\n" +
+	"\x0eStreamActivity\x12\".mangahub.v1.StreamActivityRequest\x1a\x1a.mangahub.v1.ActivityEvent0\x01\x12J\n" +
+	"\vCreateManga\x12\x1f.mangahub.v1.CreateMangaRequest\x1a\x1a.mangahub.v1.MangaResponse\x12J\n" +
+	"\vUpdateManga\x12\x1f.mangahub.v1.UpdateMangaRequest\x1a\x1a.mangahub.v1.MangaResponse\x12P\n" +
+	"\vDeleteManga\x12\x1f.mangahub.v1.DeleteMangaRequest\x1a .mangahub.v1.DeleteMangaResponseB5Z3github.com/nmihtuna204/mangahub/internal/grpc/pb;pbb\x06proto3"
+
 var (
 	file_proto_manga_proto_rawDescOnce sync.Once
-	file_proto_manga_proto_rawDescData = file_proto_manga_proto_rawDesc
+	file_proto_manga_proto_rawDescData []byte
 )
 
 func file_proto_manga_proto_rawDescGZIP() []byte {
 	file_proto_manga_proto_rawDescOnce.Do(func() {
-		file_proto_manga_proto_rawDescData = protoimpl.X.CompressGZIP(file_proto_manga_proto_rawDescData)
+		file_proto_manga_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_manga_proto_rawDesc), len(file_proto_manga_proto_rawDesc)))
 	})
 	return file_proto_manga_proto_rawDescData
 }
 
-var file_proto_manga_proto_msgTypes = make([]protoimpl.MessageInfo, 6)
+var file_proto_manga_proto_msgTypes = make([]protoimpl.MessageInfo, 14)
 var file_proto_manga_proto_goTypes = []any{
-	(*GetMangaRequest)(nil),  // 0: mangahub.v1.GetMangaRequest
-	(*MangaResponse)(nil),    // 1: mangahub.v1.MangaResponse
-	(*SearchRequest)(nil),    // 2: mangahub.v1.SearchRequest
-	(*SearchResponse)(nil),   // 3: mangahub.v1.SearchResponse
-	(*ProgressRequest)(nil),  // 4: mangahub.v1.ProgressRequest
-	(*ProgressResponse)(nil), // 5: mangahub.v1.ProgressResponse
+	(*GetMangaRequest)(nil),       // 0: mangahub.v1.GetMangaRequest
+	(*Genre)(nil),                 // 1: mangahub.v1.Genre
+	(*MangaResponse)(nil),         // 2: mangahub.v1.MangaResponse
+	(*SearchRequest)(nil),         // 3: mangahub.v1.SearchRequest
+	(*SearchResponse)(nil),        // 4: mangahub.v1.SearchResponse
+	(*ProgressRequest)(nil),       // 5: mangahub.v1.ProgressRequest
+	(*ProgressResponse)(nil),      // 6: mangahub.v1.ProgressResponse
+	(*StreamActivityRequest)(nil), // 7: mangahub.v1.StreamActivityRequest
+	(*ActivityEvent)(nil),         // 8: mangahub.v1.ActivityEvent
+	(*MangaInput)(nil),            // 9: mangahub.v1.MangaInput
+	(*CreateMangaRequest)(nil),    // 10: mangahub.v1.CreateMangaRequest
+	(*UpdateMangaRequest)(nil),    // 11: mangahub.v1.UpdateMangaRequest
+	(*DeleteMangaRequest)(nil),    // 12: mangahub.v1.DeleteMangaRequest
+	(*DeleteMangaResponse)(nil),   // 13: mangahub.v1.DeleteMangaResponse
 }
 var file_proto_manga_proto_depIdxs = []int32{
-	1, // 0: mangahub.v1.SearchResponse.manga:type_name -> mangahub.v1.MangaResponse
-	0, // 1: mangahub.v1.MangaService.GetManga:input_type -> mangahub.v1.GetMangaRequest
-	2, // 2: mangahub.v1.MangaService.SearchManga:input_type -> mangahub.v1.SearchRequest
-	4, // 3: mangahub.v1.MangaService.UpdateProgress:input_type -> mangahub.v1.ProgressRequest
-	1, // 4: mangahub.v1.MangaService.GetManga:output_type -> mangahub.v1.MangaResponse
-	3, // 5: mangahub.v1.MangaService.SearchManga:output_type -> mangahub.v1.SearchResponse
-	5, // 6: mangahub.v1.MangaService.UpdateProgress:output_type -> mangahub.v1.ProgressResponse
-	4, // [4:7] is the sub-list for method output_type
-	1, // [1:4] is the sub-list for method input_type
-	1, // [1:1] is the sub-list for extension type_name
-	1, // [1:1] is the sub-list for extension extendee
-	0, // [0:1] is the sub-list for field type_name
+	1,  // 0: mangahub.v1.MangaResponse.genres:type_name -> mangahub.v1.Genre
+	2,  // 1: mangahub.v1.SearchResponse.manga:type_name -> mangahub.v1.MangaResponse
+	9,  // 2: mangahub.v1.CreateMangaRequest.manga:type_name -> mangahub.v1.MangaInput
+	9,  // 3: mangahub.v1.UpdateMangaRequest.manga:type_name -> mangahub.v1.MangaInput
+	0,  // 4: mangahub.v1.MangaService.GetManga:input_type -> mangahub.v1.GetMangaRequest
+	3,  // 5: mangahub.v1.MangaService.SearchManga:input_type -> mangahub.v1.SearchRequest
+	5,  // 6: mangahub.v1.MangaService.UpdateProgress:input_type -> mangahub.v1.ProgressRequest
+	7,  // 7: mangahub.v1.MangaService.StreamActivity:input_type -> mangahub.v1.StreamActivityRequest
+	10, // 8: mangahub.v1.MangaService.CreateManga:input_type -> mangahub.v1.CreateMangaRequest
+	11, // 9: mangahub.v1.MangaService.UpdateManga:input_type -> mangahub.v1.UpdateMangaRequest
+	12, // 10: mangahub.v1.MangaService.DeleteManga:input_type -> mangahub.v1.DeleteMangaRequest
+	2,  // 11: mangahub.v1.MangaService.GetManga:output_type -> mangahub.v1.MangaResponse
+	4,  // 12: mangahub.v1.MangaService.SearchManga:output_type -> mangahub.v1.SearchResponse
+	6,  // 13: mangahub.v1.MangaService.UpdateProgress:output_type -> mangahub.v1.ProgressResponse
+	8,  // 14: mangahub.v1.MangaService.StreamActivity:output_type -> mangahub.v1.ActivityEvent
+	2,  // 15: mangahub.v1.MangaService.CreateManga:output_type -> mangahub.v1.MangaResponse
+	2,  // 16: mangahub.v1.MangaService.UpdateManga:output_type -> mangahub.v1.MangaResponse
+	13, // 17: mangahub.v1.MangaService.DeleteManga:output_type -> mangahub.v1.DeleteMangaResponse
+	11, // [11:18] is the sub-list for method output_type
+	4,  // [4:11] is the sub-list for method input_type
+	4,  // [4:4] is the sub-list for extension type_name
+	4,  // [4:4] is the sub-list for extension extendee
+	0,  // [0:4] is the sub-list for field type_name
 }
 
 func init() { file_proto_manga_proto_init() }
@@ -724,9 +1221,9 @@ func file_proto_manga_proto_init() {
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
-			RawDescriptor: file_proto_manga_proto_rawDesc,
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_manga_proto_rawDesc), len(file_proto_manga_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   6,
+			NumMessages:   14,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
@@ -735,7 +1232,6 @@ func file_proto_manga_proto_init() {
 		MessageInfos:      file_proto_manga_proto_msgTypes,
 	}.Build()
 	File_proto_manga_proto = out.File
-	file_proto_manga_proto_rawDesc = nil
 	file_proto_manga_proto_goTypes = nil
 	file_proto_manga_proto_depIdxs = nil
 }
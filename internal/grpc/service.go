@@ -4,6 +4,8 @@
 //   - GetManga RPC: Lấy thông tin manga theo ID
 //   - SearchManga RPC: Tìm kiếm manga với filters
 //   - UpdateProgress RPC: Cập nhật reading progress
+//   - StreamActivity RPC: Server-streaming activity feed realtime
+//   - CreateManga/UpdateManga/DeleteManga RPCs: Quản lý catalog (xem mutations.go)
 //   - High-performance binary protocol
 //   - Type-safe communication với protobuf
 //   - Reflection support cho debugging
@@ -14,22 +16,44 @@ import (
 	"database/sql"
 	"fmt"
 
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"mangahub/internal/activity"
 	pb "mangahub/internal/grpc/pb"
+	"mangahub/internal/manga"
+	"mangahub/internal/udp"
 	"mangahub/pkg/logger"
 	"mangahub/pkg/models"
 )
 
 type MangaServiceServer struct {
 	pb.UnimplementedMangaServiceServer
-	db *sql.DB
+	db     *sql.DB
+	manga  manga.Repository
+	feed   *activity.Feed
+	apiKey string
+	notify func(udp.Notification) error
 }
 
 func NewMangaServiceServer(db *sql.DB) *MangaServiceServer {
 	return &MangaServiceServer{
-		db: db,
+		db:    db,
+		manga: manga.NewRepository(db),
 	}
 }
 
+// SetActivityFeed enables StreamActivity with the given live feed
+func (s *MangaServiceServer) SetActivityFeed(feed *activity.Feed) {
+	s.feed = feed
+}
+
+// SetNotifier sends a status_change notification whenever UpdateManga
+// changes a manga's status; nil disables it
+func (s *MangaServiceServer) SetNotifier(notify func(udp.Notification) error) {
+	s.notify = notify
+}
+
 // GetManga retrieves a single manga by ID
 func (s *MangaServiceServer) GetManga(ctx context.Context, req *pb.GetMangaRequest) (*pb.MangaResponse, error) {
 	// Protocol trace logging
@@ -277,3 +301,59 @@ func (s *MangaServiceServer) UpdateProgress(ctx context.Context, req *pb.Progres
 		Timestamp:      0, // Set by server
 	}, nil
 }
+
+// StreamActivity pushes new activity feed entries until the client disconnects.
+// Empty user_id / manga_id filters match every activity.
+func (s *MangaServiceServer) StreamActivity(req *pb.StreamActivityRequest, stream pb.MangaService_StreamActivityServer) error {
+	logger.GRPC("StreamActivity", fmt.Sprintf("user_id=%s manga_id=%s", req.UserId, req.MangaId), 0)
+
+	if s.feed == nil {
+		return status.Error(codes.Unavailable, "activity feed is not enabled")
+	}
+
+	events, cancel := s.feed.Subscribe()
+	defer cancel()
+
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Infof("gRPC: StreamActivity client disconnected")
+			return nil
+		case a, ok := <-events:
+			if !ok {
+				return status.Error(codes.Unavailable, "activity feed stopped")
+			}
+			if req.UserId != "" && a.UserID != req.UserId {
+				continue
+			}
+			if req.MangaId != "" && a.MangaID != req.MangaId {
+				continue
+			}
+			if err := stream.Send(toActivityEvent(a)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toActivityEvent converts an activity row to its protobuf message
+func toActivityEvent(a models.Activity) *pb.ActivityEvent {
+	event := &pb.ActivityEvent{
+		Id:           a.ID,
+		UserId:       a.UserID,
+		Username:     a.Username,
+		ActivityType: a.ActivityType,
+		MangaId:      a.MangaID,
+		MangaTitle:   a.MangaTitle,
+		CommentText:  a.CommentText,
+		Timestamp:    a.CreatedAt.Unix(),
+	}
+	if a.ChapterNumber != nil {
+		event.ChapterNumber = int32(*a.ChapterNumber)
+	}
+	if a.Rating != nil {
+		event.Rating = *a.Rating
+	}
+	return event
+}
@@ -0,0 +1,41 @@
+// Package health - Health HTTP Handlers
+// Endpoints:
+//   - GET /health - Liveness probe (không chạm dependency)
+//   - GET /ready - Readiness probe (503 khi hard dependency down)
+package health
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Handler serves the liveness and readiness probes
+type Handler struct {
+	checker *Checker
+}
+
+// NewHandler creates a new health handler
+func NewHandler(checker *Checker) *Handler {
+	return &Handler{checker: checker}
+}
+
+// Liveness handles GET /health
+// Cheap: answers as long as the process can serve HTTP
+func (h *Handler) Liveness(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status": "ok",
+		"server": "running",
+	})
+}
+
+// Readiness handles GET /ready
+// Returns 503 when any required dependency is down
+func (h *Handler) Readiness(c *gin.Context) {
+	report := h.checker.Check(c.Request.Context())
+	status := http.StatusOK
+	if !report.Ready {
+		status = http.StatusServiceUnavailable
+	}
+	c.JSON(status, report)
+}
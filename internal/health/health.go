@@ -0,0 +1,143 @@
+// Package health - Liveness và Readiness Checks
+// Kiểm tra trạng thái các dependency của api-server
+// Chức năng:
+//   - Đăng ký check theo tên (hard = bắt buộc, soft = chỉ báo cáo)
+//   - Phân biệt "not_configured" với "down" cho dependency tùy chọn
+//   - Đo latency từng check, chạy song song với timeout chung
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Dependency statuses
+const (
+	StatusUp            = "up"
+	StatusDown          = "down"
+	StatusNotConfigured = "not_configured"
+)
+
+// ErrNotConfigured is returned by a check whose dependency is intentionally absent
+var ErrNotConfigured = errors.New("not configured")
+
+// CheckFunc probes one dependency; details are optional and included in the report
+type CheckFunc func(ctx context.Context) (details map[string]interface{}, err error)
+
+// DependencyStatus is the result of one check
+type DependencyStatus struct {
+	Status    string                 `json:"status"`
+	Required  bool                   `json:"required"`
+	LatencyMs int64                  `json:"latency_ms"`
+	Error     string                 `json:"error,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// Report is the readiness result across all dependencies
+type Report struct {
+	Ready        bool                        `json:"ready"`
+	Dependencies map[string]DependencyStatus `json:"dependencies"`
+	CheckedAt    time.Time                   `json:"checked_at"`
+}
+
+type check struct {
+	name     string
+	required bool
+	fn       CheckFunc
+}
+
+// Checker runs the registered dependency checks
+type Checker struct {
+	checks  []check
+	timeout time.Duration
+}
+
+// NewChecker creates a checker; timeout bounds each readiness run (default 2s)
+func NewChecker(timeout time.Duration) *Checker {
+	if timeout <= 0 {
+		timeout = 2 * time.Second
+	}
+	return &Checker{timeout: timeout}
+}
+
+// Require registers a hard dependency: when it is down the service is not ready
+func (c *Checker) Require(name string, fn CheckFunc) {
+	c.checks = append(c.checks, check{name: name, required: true, fn: fn})
+}
+
+// Observe registers a soft dependency: it is reported but never fails readiness
+func (c *Checker) Observe(name string, fn CheckFunc) {
+	c.checks = append(c.checks, check{name: name, required: false, fn: fn})
+}
+
+// Check runs every check concurrently and builds the report
+// A required check returning ErrNotConfigured does not fail readiness
+func (c *Checker) Check(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	report := Report{
+		Ready:        true,
+		Dependencies: make(map[string]DependencyStatus, len(c.checks)),
+		CheckedAt:    time.Now(),
+	}
+
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for _, chk := range c.checks {
+		wg.Add(1)
+		go func(chk check) {
+			defer wg.Done()
+			status := run(ctx, chk)
+
+			mu.Lock()
+			defer mu.Unlock()
+			report.Dependencies[chk.name] = status
+			if chk.required && status.Status == StatusDown {
+				report.Ready = false
+			}
+		}(chk)
+	}
+	wg.Wait()
+
+	return report
+}
+
+// run executes one check, treating a check that outlives ctx as down
+func run(ctx context.Context, chk check) DependencyStatus {
+	type result struct {
+		details map[string]interface{}
+		err     error
+	}
+
+	start := time.Now()
+	done := make(chan result, 1)
+	go func() {
+		details, err := chk.fn(ctx)
+		done <- result{details, err}
+	}()
+
+	var res result
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		res = result{err: ctx.Err()}
+	}
+
+	status := DependencyStatus{
+		Status:    StatusUp,
+		Required:  chk.required,
+		LatencyMs: time.Since(start).Milliseconds(),
+		Details:   res.details,
+	}
+	switch {
+	case errors.Is(res.err, ErrNotConfigured):
+		status.Status = StatusNotConfigured
+	case res.err != nil:
+		status.Status = StatusDown
+		status.Error = res.err.Error()
+	}
+	return status
+}
@@ -0,0 +1,118 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func up(ctx context.Context) (map[string]interface{}, error) { return nil, nil }
+func down(ctx context.Context) (map[string]interface{}, error) {
+	return nil, errors.New("connection refused")
+}
+func absent(ctx context.Context) (map[string]interface{}, error) {
+	return nil, ErrNotConfigured
+}
+
+func TestReadiness(t *testing.T) {
+	tests := []struct {
+		name       string
+		setup      func(c *Checker)
+		wantReady  bool
+		wantStatus map[string]string
+	}{
+		{
+			name: "all up",
+			setup: func(c *Checker) {
+				c.Require("database", up)
+				c.Require("redis", up)
+			},
+			wantReady:  true,
+			wantStatus: map[string]string{"database": StatusUp, "redis": StatusUp},
+		},
+		{
+			name: "redis intentionally absent",
+			setup: func(c *Checker) {
+				c.Require("database", up)
+				c.Require("redis", absent)
+			},
+			wantReady:  true,
+			wantStatus: map[string]string{"database": StatusUp, "redis": StatusNotConfigured},
+		},
+		{
+			name: "redis configured but down",
+			setup: func(c *Checker) {
+				c.Require("database", up)
+				c.Require("redis", down)
+			},
+			wantReady:  false,
+			wantStatus: map[string]string{"database": StatusUp, "redis": StatusDown},
+		},
+		{
+			name: "soft dependency down",
+			setup: func(c *Checker) {
+				c.Require("database", up)
+				c.Observe("protocol_bridge", down)
+			},
+			wantReady:  true,
+			wantStatus: map[string]string{"database": StatusUp, "protocol_bridge": StatusDown},
+		},
+	}
+
+	gin.SetMode(gin.TestMode)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checker := NewChecker(time.Second)
+			tt.setup(checker)
+
+			router := gin.New()
+			router.GET("/ready", NewHandler(checker).Readiness)
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/ready", nil))
+
+			wantCode := http.StatusOK
+			if !tt.wantReady {
+				wantCode = http.StatusServiceUnavailable
+			}
+			if w.Code != wantCode {
+				t.Fatalf("status = %d, want %d", w.Code, wantCode)
+			}
+
+			var report Report
+			if err := json.Unmarshal(w.Body.Bytes(), &report); err != nil {
+				t.Fatalf("decode: %v", err)
+			}
+			if report.Ready != tt.wantReady {
+				t.Errorf("ready = %v, want %v", report.Ready, tt.wantReady)
+			}
+			for name, want := range tt.wantStatus {
+				if got := report.Dependencies[name].Status; got != want {
+					t.Errorf("%s status = %q, want %q", name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestReadinessTimesOutHungCheck(t *testing.T) {
+	checker := NewChecker(50 * time.Millisecond)
+	checker.Require("database", func(ctx context.Context) (map[string]interface{}, error) {
+		time.Sleep(time.Second)
+		return nil, nil
+	})
+
+	start := time.Now()
+	report := checker.Check(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Fatalf("check took %v, want it bounded by the timeout", elapsed)
+	}
+	if report.Ready || report.Dependencies["database"].Status != StatusDown {
+		t.Errorf("hung required check should mark the service not ready: %+v", report)
+	}
+}
@@ -1,16 +1,20 @@
 // Package leaderboard - Leaderboard HTTP Handlers
 // HTTP handlers cho leaderboard API endpoints
 // Endpoints:
-//   - GET /leaderboards/manga - Top rated manga
+//   - GET /leaderboards/manga - Top rated manga (?genre=romance&type=manhwa)
+//   - GET /leaderboards/manga/hidden-gems - Manga chưa đủ vote để vào top rated
 //   - GET /leaderboards/users - Most active users
-//   - GET /leaderboards/trending - Trending manga (with ?days=7 or 30)
+//   - GET /leaderboards/trending - Trending manga (?window=day|week|month&genre=action)
 package leaderboard
 
 import (
 	"net/http"
+	"slices"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/models"
 )
 
@@ -25,13 +29,17 @@ func NewHandler(svc Service) *Handler {
 }
 
 // GetTopRatedManga handles GET /leaderboards/manga
-// Returns manga sorted by rating
-// Query params: ?limit=20&offset=0
+// Returns manga with enough ratings, sorted by Bayesian average
+// Query params: ?limit=20&offset=0&genre=romance&type=manhwa
 func (h *Handler) GetTopRatedManga(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	filter, ok := mangaFilter(c)
+	if !ok {
+		return
+	}
 
-	response, err := h.svc.GetTopRatedManga(c.Request.Context(), limit, offset)
+	response, err := h.svc.GetTopRatedManga(c.Request.Context(), limit, offset, filter)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError,
 			models.NewErrorResponse(models.ErrCodeInternal, "failed to get leaderboard", map[string]interface{}{"error": err.Error()}))
@@ -42,6 +50,41 @@ func (h *Handler) GetTopRatedManga(c *gin.Context) {
 		models.NewSuccessResponse(response, "top rated manga"))
 }
 
+// GetHiddenGems handles GET /leaderboards/manga/hidden-gems
+// Returns manga with too few ratings for the top-rated list, best average first
+// Query params: ?limit=20&offset=0&genre=romance&type=manhwa
+func (h *Handler) GetHiddenGems(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+	filter, ok := mangaFilter(c)
+	if !ok {
+		return
+	}
+
+	response, err := h.svc.GetHiddenGems(c.Request.Context(), limit, offset, filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError,
+			models.NewErrorResponse(models.ErrCodeInternal, "failed to get leaderboard", map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(response, "hidden gems"))
+}
+
+// mangaFilter reads ?genre= and ?type=, responding 400 for an unknown type
+func mangaFilter(c *gin.Context) (MangaFilter, bool) {
+	filter := MangaFilter{
+		Genre: c.Query("genre"),
+		Type:  strings.ToLower(strings.TrimSpace(c.Query("type"))),
+	}
+	if filter.Type != "" && !slices.Contains(MangaTypes, filter.Type) {
+		apperrors.Respond(c, apperrors.Validation("type", "type must be one of "+strings.Join(MangaTypes, ", ")), "")
+		return filter, false
+	}
+	return filter, true
+}
+
 // GetMostActiveUsers handles GET /leaderboards/users
 // Returns users sorted by engagement score
 // Query params: ?limit=20&offset=0
@@ -62,13 +105,22 @@ func (h *Handler) GetMostActiveUsers(c *gin.Context) {
 
 // GetTrendingManga handles GET /leaderboards/trending
 // Returns manga with most activity recently
-// Query params: ?limit=20&offset=0&days=7 (7=weekly, 30=monthly)
+// Query params: ?limit=20&offset=0&window=week&genre=action
+// The older ?days=1|7|30 param is still accepted when window is absent.
 func (h *Handler) GetTrendingManga(c *gin.Context) {
 	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
 	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
-	days, _ := strconv.Atoi(c.DefaultQuery("days", "7"))
 
-	response, err := h.svc.GetTrendingManga(c.Request.Context(), limit, offset, days)
+	window := c.Query("window")
+	if window == "" {
+		window = windowFromDays(c.DefaultQuery("days", "7"))
+	}
+	if _, ok := trendingWindows[window]; !ok {
+		apperrors.Respond(c, apperrors.Validation("window", "window must be day, week or month"), "")
+		return
+	}
+
+	response, err := h.svc.GetTrendingManga(c.Request.Context(), limit, offset, window, c.Query("genre"))
 	if err != nil {
 		c.JSON(http.StatusInternalServerError,
 			models.NewErrorResponse(models.ErrCodeInternal, "failed to get leaderboard", map[string]interface{}{"error": err.Error()}))
@@ -78,3 +130,16 @@ func (h *Handler) GetTrendingManga(c *gin.Context) {
 	c.JSON(http.StatusOK,
 		models.NewSuccessResponse(response, "trending manga"))
 }
+
+// windowFromDays maps the legacy days param to the closest window
+func windowFromDays(days string) string {
+	n, _ := strconv.Atoi(days)
+	switch {
+	case n > 0 && n <= 1:
+		return TrendingWindowDay
+	case n > 7:
+		return TrendingWindowMonth
+	default:
+		return TrendingWindowWeek
+	}
+}
@@ -5,6 +5,8 @@ package leaderboard
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
+	"fmt"
 	"testing"
 	"time"
 
@@ -36,8 +38,20 @@ func setupTestDB(t *testing.T) *sql.DB {
 			title TEXT NOT NULL,
 			cover_url TEXT,
 			author TEXT,
+			average_rating REAL DEFAULT 0.0,
+			rating_count INTEGER DEFAULT 0,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)`,
+		`CREATE TABLE IF NOT EXISTS genres (
+			id TEXT PRIMARY KEY,
+			name TEXT UNIQUE NOT NULL,
+			slug TEXT UNIQUE NOT NULL
+		)`,
+		`CREATE TABLE IF NOT EXISTS manga_genres (
+			manga_id TEXT NOT NULL,
+			genre_id TEXT NOT NULL,
+			PRIMARY KEY (manga_id, genre_id)
+		)`,
 		`CREATE TABLE IF NOT EXISTS manga_ratings (
 			id TEXT PRIMARY KEY,
 			manga_id TEXT NOT NULL,
@@ -64,13 +78,24 @@ func setupTestDB(t *testing.T) *sql.DB {
 			is_deleted BOOLEAN DEFAULT 0,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)`,
-		`CREATE TABLE IF NOT EXISTS activities (
+		`CREATE TABLE IF NOT EXISTS activity_feed (
 			id TEXT PRIMARY KEY,
 			user_id TEXT NOT NULL,
-			manga_id TEXT,
+			username TEXT NOT NULL,
 			activity_type TEXT NOT NULL,
+			manga_id TEXT NOT NULL,
+			manga_title TEXT NOT NULL,
 			created_at DATETIME DEFAULT CURRENT_TIMESTAMP
 		)`,
+		`CREATE TABLE IF NOT EXISTS trending_scores (
+			trend_window TEXT NOT NULL,
+			manga_id TEXT NOT NULL,
+			score REAL NOT NULL,
+			total_ratings INTEGER NOT NULL DEFAULT 0,
+			total_readers INTEGER NOT NULL DEFAULT 0,
+			computed_at DATETIME NOT NULL,
+			PRIMARY KEY (trend_window, manga_id)
+		)`,
 	}
 
 	for _, table := range tables {
@@ -86,9 +111,13 @@ func setupTestDB(t *testing.T) *sql.DB {
 	db.Exec(`INSERT INTO users (id, username, email, password_hash, display_name, is_active) VALUES ('user3', 'lowuser', 'test3@test.com', 'hash789', 'Low User', 1)`)
 
 	// Manga
-	db.Exec(`INSERT INTO manga (id, title, author) VALUES ('manga1', 'Top Rated Manga', 'Author A')`)
-	db.Exec(`INSERT INTO manga (id, title, author) VALUES ('manga2', 'Medium Rated Manga', 'Author B')`)
-	db.Exec(`INSERT INTO manga (id, title, author) VALUES ('manga3', 'Low Rated Manga', 'Author C')`)
+	db.Exec(`INSERT INTO manga (id, title, author, average_rating, rating_count) VALUES ('manga1', 'Top Rated Manga', 'Author A', 9.3, 3)`)
+	db.Exec(`INSERT INTO manga (id, title, author, average_rating, rating_count) VALUES ('manga2', 'Medium Rated Manga', 'Author B', 6.5, 2)`)
+	db.Exec(`INSERT INTO manga (id, title, author, average_rating, rating_count) VALUES ('manga3', 'Low Rated Manga', 'Author C', 4, 1)`)
+
+	// Genres
+	db.Exec(`INSERT INTO genres (id, name, slug) VALUES ('g-action', 'Action', 'action'), ('g-romance', 'Romance', 'romance')`)
+	db.Exec(`INSERT INTO manga_genres (manga_id, genre_id) VALUES ('manga1', 'g-action'), ('manga2', 'g-romance'), ('manga3', 'g-romance')`)
 
 	// Ratings for manga1 (high ratings)
 	db.Exec(`INSERT INTO manga_ratings (id, manga_id, user_id, overall_rating) VALUES ('r1', 'manga1', 'user1', 10)`)
@@ -114,15 +143,20 @@ func setupTestDB(t *testing.T) *sql.DB {
 	db.Exec(`INSERT INTO comments (id, manga_id, user_id, content, is_deleted) VALUES ('c3', 'manga2', 'user2', 'Comment 3', 0)`)
 
 	// Recent activities for trending
-	now := time.Now().Format("2006-01-02 15:04:05")
-	db.Exec(`INSERT INTO activities (id, user_id, manga_id, activity_type, created_at) VALUES ('a1', 'user1', 'manga1', 'read', ?)`, now)
-	db.Exec(`INSERT INTO activities (id, user_id, manga_id, activity_type, created_at) VALUES ('a2', 'user2', 'manga1', 'read', ?)`, now)
-	db.Exec(`INSERT INTO activities (id, user_id, manga_id, activity_type, created_at) VALUES ('a3', 'user3', 'manga1', 'rate', ?)`, now)
-	db.Exec(`INSERT INTO activities (id, user_id, manga_id, activity_type, created_at) VALUES ('a4', 'user1', 'manga2', 'read', ?)`, now)
+	now := time.Now().UTC().Format("2006-01-02 15:04:05")
+	insertActivity(db, "a1", "user1", "manga1", "progress", now)
+	insertActivity(db, "a2", "user2", "manga1", "progress", now)
+	insertActivity(db, "a3", "user3", "manga1", "rating", now)
+	insertActivity(db, "a4", "user1", "manga2", "progress", now)
 
 	return db
 }
 
+func insertActivity(db *sql.DB, id, userID, mangaID, activityType, createdAt string) {
+	db.Exec(`INSERT INTO activity_feed (id, user_id, username, activity_type, manga_id, manga_title, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)`, id, userID, userID, activityType, mangaID, mangaID, createdAt)
+}
+
 func TestLeaderboardService_GetTopRatedManga(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -130,7 +164,7 @@ func TestLeaderboardService_GetTopRatedManga(t *testing.T) {
 	svc := NewService(db)
 	ctx := context.Background()
 
-	response, err := svc.GetTopRatedManga(ctx, 10, 0)
+	response, err := svc.GetTopRatedManga(ctx, 10, 0, MangaFilter{})
 	if err != nil {
 		t.Fatalf("GetTopRatedManga failed: %v", err)
 	}
@@ -161,6 +195,59 @@ func TestLeaderboardService_GetTopRatedManga(t *testing.T) {
 	}
 }
 
+func TestLeaderboardService_TopRatedPriorAndFilters(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	db.Exec(`ALTER TABLE manga ADD COLUMN type TEXT DEFAULT 'manga'`)
+	db.Exec(`UPDATE manga SET type = 'manhwa' WHERE id IN ('manga2', 'manga3')`)
+	// One perfect score must not beat manga1's three 9+ ratings
+	db.Exec(`INSERT INTO manga (id, title, type) VALUES ('manga4', 'One Vote Wonder', 'manhwa')`)
+	db.Exec(`INSERT INTO manga_genres (manga_id, genre_id) VALUES ('manga4', 'g-romance')`)
+	db.Exec(`INSERT INTO manga_ratings (id, manga_id, user_id, overall_rating) VALUES ('r7', 'manga4', 'user2', 10)`)
+
+	svc := NewServiceWithPrior(db, nil, Prior{MinVotes: 2, Mean: 5})
+	ctx := context.Background()
+
+	ids := func(resp *LeaderboardResponse) string {
+		t.Helper()
+		var out []string
+		for _, e := range resp.Entries.([]MangaLeaderboardEntry) {
+			out = append(out, e.MangaID)
+		}
+		return fmt.Sprint(out)
+	}
+
+	resp, err := svc.GetTopRatedManga(ctx, 10, 0, MangaFilter{})
+	if err != nil {
+		t.Fatalf("GetTopRatedManga failed: %v", err)
+	}
+	if got := ids(resp); got != "[manga1 manga2]" {
+		t.Errorf("top rated = %s, want [manga1 manga2]", got)
+	}
+	// (3*9.33 + 2*5) / 5
+	if w := resp.Entries.([]MangaLeaderboardEntry)[0].WeightedRating; w < 7.5 || w > 7.7 {
+		t.Errorf("weighted rating = %f, want ~7.6", w)
+	}
+
+	resp, err = svc.GetTopRatedManga(ctx, 10, 0, MangaFilter{Genre: "romance", Type: "manhwa"})
+	if err != nil {
+		t.Fatalf("filtered GetTopRatedManga failed: %v", err)
+	}
+	if got := ids(resp); got != "[manga2]" {
+		t.Errorf("top romance manhwa = %s, want [manga2]", got)
+	}
+
+	// Below the threshold but still reachable
+	resp, err = svc.GetHiddenGems(ctx, 10, 0, MangaFilter{Type: "manhwa"})
+	if err != nil {
+		t.Fatalf("GetHiddenGems failed: %v", err)
+	}
+	if got := ids(resp); got != "[manga4 manga3]" {
+		t.Errorf("hidden gems = %s, want [manga4 manga3]", got)
+	}
+}
+
 func TestLeaderboardService_GetMostActiveUsers(t *testing.T) {
 	db := setupTestDB(t)
 	defer db.Close()
@@ -199,7 +286,7 @@ func TestLeaderboardService_GetTrendingManga(t *testing.T) {
 	ctx := context.Background()
 
 	// Get trending for last 7 days
-	response, err := svc.GetTrendingManga(ctx, 10, 0, 7)
+	response, err := svc.GetTrendingManga(ctx, 10, 0, TrendingWindowWeek, "")
 	if err != nil {
 		t.Fatalf("GetTrendingManga failed: %v", err)
 	}
@@ -226,7 +313,7 @@ func TestLeaderboardService_Pagination(t *testing.T) {
 	ctx := context.Background()
 
 	// Test with limit=1
-	response, err := svc.GetTopRatedManga(ctx, 1, 0)
+	response, err := svc.GetTopRatedManga(ctx, 1, 0, MangaFilter{})
 	if err != nil {
 		t.Fatalf("GetTopRatedManga failed: %v", err)
 	}
@@ -241,7 +328,7 @@ func TestLeaderboardService_Pagination(t *testing.T) {
 	}
 
 	// Test offset
-	response, err = svc.GetTopRatedManga(ctx, 1, 1)
+	response, err = svc.GetTopRatedManga(ctx, 1, 1, MangaFilter{})
 	if err != nil {
 		t.Fatalf("GetTopRatedManga with offset failed: %v", err)
 	}
@@ -260,3 +347,142 @@ func TestLeaderboardService_Pagination(t *testing.T) {
 		t.Errorf("expected manga2 at offset 1, got '%s'", entries[0].MangaID)
 	}
 }
+
+func TestLeaderboardService_TrendingGenreAndDecay(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	// Two old events for manga3 lose to one fresh event for manga2
+	old := time.Now().UTC().AddDate(0, 0, -6).Format("2006-01-02 15:04:05")
+	insertActivity(db, "a5", "user2", "manga3", "progress", old)
+	insertActivity(db, "a6", "user3", "manga3", "progress", old)
+
+	svc := NewService(db)
+	response, err := svc.GetTrendingManga(context.Background(), 10, 0, TrendingWindowWeek, "romance")
+	if err != nil {
+		t.Fatalf("GetTrendingManga failed: %v", err)
+	}
+
+	entries := response.Entries.([]MangaLeaderboardEntry)
+	if len(entries) != 2 {
+		t.Fatalf("expected 2 romance manga, got %d", len(entries))
+	}
+	if entries[0].MangaID != "manga2" || entries[1].MangaID != "manga3" {
+		t.Errorf("expected recent activity to rank first, got %s then %s", entries[0].MangaID, entries[1].MangaID)
+	}
+	if response.Genre != "romance" || response.Fallback {
+		t.Errorf("unexpected response metadata: genre=%q fallback=%v", response.Genre, response.Fallback)
+	}
+}
+
+func TestLeaderboardService_TrendingFallsBackToTopRated(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	db.Exec(`DELETE FROM activity_feed`)
+
+	svc := NewService(db)
+	response, err := svc.GetTrendingManga(context.Background(), 10, 0, TrendingWindowDay, "")
+	if err != nil {
+		t.Fatalf("GetTrendingManga failed: %v", err)
+	}
+
+	entries := response.Entries.([]MangaLeaderboardEntry)
+	if !response.Fallback || len(entries) != 3 || entries[0].MangaID != "manga1" {
+		t.Errorf("expected top-rated fallback led by manga1, got fallback=%v entries=%v", response.Fallback, entries)
+	}
+}
+
+func TestLeaderboardService_TrendingReadsFreshScores(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+	ctx := context.Background()
+
+	if err := NewWorker(db).Refresh(ctx); err != nil {
+		t.Fatalf("Refresh failed: %v", err)
+	}
+	var rows int
+	db.QueryRow(`SELECT COUNT(*) FROM trending_scores WHERE trend_window = ?`, TrendingWindowWeek).Scan(&rows)
+	if rows != 2 {
+		t.Fatalf("expected 2 weekly scores, got %d", rows)
+	}
+
+	// Activity gone since the refresh: fresh scores still rank, stale ones don't
+	db.Exec(`DELETE FROM activity_feed`)
+	svc := NewServiceWithScores(db, nil, Prior{}, time.Hour)
+	response, err := svc.GetTrendingManga(ctx, 10, 0, TrendingWindowWeek, "")
+	if err != nil {
+		t.Fatalf("GetTrendingManga failed: %v", err)
+	}
+	entries := response.Entries.([]MangaLeaderboardEntry)
+	if response.Fallback || len(entries) != 2 || entries[0].MangaID != "manga1" || entries[0].TotalReaders != 3 {
+		t.Errorf("expected precomputed ranking led by manga1, got fallback=%v entries=%v", response.Fallback, entries)
+	}
+
+	db.Exec(`UPDATE trending_scores SET computed_at = datetime('now', '-2 hours')`)
+	response, err = svc.GetTrendingManga(ctx, 10, 0, TrendingWindowWeek, "")
+	if err != nil {
+		t.Fatalf("GetTrendingManga failed: %v", err)
+	}
+	if !response.Fallback {
+		t.Error("expected stale scores to be ignored for the live computation")
+	}
+}
+
+// memoryCache is a minimal cache.Cache for tests
+type memoryCache struct {
+	values map[string]string
+	gets   int
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	c.gets++
+	return c.values[key], nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	c.values[key] = string(data)
+	return nil
+}
+
+func (c *memoryCache) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return c.Set(ctx, key, value, ttl)
+}
+
+func (c *memoryCache) Delete(ctx context.Context, key string) error                  { return nil }
+func (c *memoryCache) Exists(ctx context.Context, key string) (bool, error)          { return false, nil }
+func (c *memoryCache) GetTTL(ctx context.Context, key string) (time.Duration, error) { return 0, nil }
+func (c *memoryCache) FlushByPrefix(ctx context.Context, prefix string) error        { return nil }
+func (c *memoryCache) Close() error                                                  { return nil }
+func (c *memoryCache) Ping(ctx context.Context) error                                { return nil }
+
+func TestLeaderboardService_TrendingIsCached(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	mc := &memoryCache{values: map[string]string{}}
+	svc := NewServiceWithCache(db, mc)
+	ctx := context.Background()
+
+	if _, err := svc.GetTrendingManga(ctx, 10, 0, TrendingWindowWeek, ""); err != nil {
+		t.Fatalf("GetTrendingManga failed: %v", err)
+	}
+
+	// New activity must not show up until the cached entry expires
+	insertActivity(db, "a7", "user2", "manga3", "rating", time.Now().UTC().Format("2006-01-02 15:04:05"))
+	response, err := svc.GetTrendingManga(ctx, 10, 0, TrendingWindowWeek, "")
+	if err != nil {
+		t.Fatalf("GetTrendingManga failed: %v", err)
+	}
+
+	entries := response.Entries.([]MangaLeaderboardEntry)
+	if len(entries) != 2 {
+		t.Errorf("expected the cached 2 entries, got %d", len(entries))
+	}
+	if len(mc.values) != 1 {
+		t.Errorf("expected one cache key for (window, genre), got %d", len(mc.values))
+	}
+}
@@ -1,7 +1,8 @@
 // Package leaderboard - Leaderboard Service
 // Business logic layer cho leaderboard system
 // Chức năng:
-//   - Top rated manga
+//   - Top rated manga (Bayesian average, lọc theo genre/type)
+//   - Hidden gems: manga chưa đủ số vote để vào top rated
 //   - Most active users
 //   - Trending manga (most reads/ratings recently)
 package leaderboard
@@ -9,8 +10,12 @@ package leaderboard
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
+	"strings"
 	"time"
+
+	"mangahub/pkg/cache"
 )
 
 // MangaLeaderboardEntry represents a manga in the leaderboard
@@ -23,6 +28,9 @@ type MangaLeaderboardEntry struct {
 	AverageRating float64 `json:"average_rating"`
 	TotalRatings  int     `json:"total_ratings"`
 	TotalReaders  int     `json:"total_readers"`
+	TrendingScore float64 `json:"trending_score,omitempty"` // Decayed activity score (trending only)
+	// Bayesian average the top-rated ranking sorts by (top rated and hidden gems only)
+	WeightedRating float64 `json:"weighted_rating,omitempty"`
 }
 
 // UserLeaderboardEntry represents a user in the leaderboard
@@ -41,26 +49,102 @@ type UserLeaderboardEntry struct {
 
 // LeaderboardResponse contains leaderboard data
 type LeaderboardResponse struct {
-	Type      string      `json:"type"`             // manga, users, trending
-	Period    string      `json:"period,omitempty"` // all_time, weekly, monthly
+	Type      string      `json:"type"`                 // manga, users, trending
+	Period    string      `json:"period,omitempty"`     // all_time, daily, weekly, monthly
+	Genre     string      `json:"genre,omitempty"`      // genre filter
+	MangaType string      `json:"manga_type,omitempty"` // manga type filter (top rated, hidden gems)
+	MinVotes  int         `json:"min_votes,omitempty"`  // ratings needed to be top rated
+	Fallback  bool        `json:"fallback,omitempty"`
 	Entries   interface{} `json:"entries"`
 	UpdatedAt time.Time   `json:"updated_at"`
 }
 
 // Service defines business operations for leaderboards
 type Service interface {
-	// GetTopRatedManga returns manga sorted by rating
-	GetTopRatedManga(ctx context.Context, limit, offset int) (*LeaderboardResponse, error)
+	// GetTopRatedManga returns manga with enough ratings, sorted by Bayesian average
+	GetTopRatedManga(ctx context.Context, limit, offset int, filter MangaFilter) (*LeaderboardResponse, error)
+
+	// GetHiddenGems returns manga with too few ratings to be top rated, best average first
+	GetHiddenGems(ctx context.Context, limit, offset int, filter MangaFilter) (*LeaderboardResponse, error)
 
 	// GetMostActiveUsers returns users sorted by activity
 	GetMostActiveUsers(ctx context.Context, limit, offset int) (*LeaderboardResponse, error)
 
-	// GetTrendingManga returns manga with most activity recently
-	GetTrendingManga(ctx context.Context, limit, offset int, days int) (*LeaderboardResponse, error)
+	// GetTrendingManga returns manga with most activity recently.
+	// window is day, week or month; genre is optional.
+	GetTrendingManga(ctx context.Context, limit, offset int, window, genre string) (*LeaderboardResponse, error)
+}
+
+// MangaFilter narrows a manga ranking. Empty fields match everything.
+type MangaFilter struct {
+	Genre string // genre slug or name
+	Type  string // one of MangaTypes
+}
+
+// MangaTypes are the values of manga.type
+var MangaTypes = []string{"manga", "manhwa", "manhua", "novel"}
+
+// Prior is the Bayesian prior of the top-rated ranking. The zero Prior ranks
+// every rated manga by its plain average.
+type Prior struct {
+	// MinVotes weighs the prior and is the rating count a manga needs to be
+	// top rated; manga with fewer ratings are hidden gems
+	MinVotes int
+	// Mean is the rating few-vote manga are pulled toward; 0 uses the
+	// average of all ratings
+	Mean float64
+}
+
+// Trending windows
+const (
+	TrendingWindowDay   = "day"
+	TrendingWindowWeek  = "week"
+	TrendingWindowMonth = "month"
+)
+
+// trendingWindow sets how far back trending looks and how fast activity decays
+type trendingWindow struct {
+	days         int
+	halfLifeDays float64 // age at which an event counts half
+	period       string
+}
+
+var trendingWindows = map[string]trendingWindow{
+	TrendingWindowDay:   {days: 1, halfLifeDays: 0.25, period: "daily"},
+	TrendingWindowWeek:  {days: 7, halfLifeDays: 2, period: "weekly"},
+	TrendingWindowMonth: {days: 30, halfLifeDays: 7, period: "monthly"},
 }
 
+// trendingCacheTTL keeps dashboard loads from recomputing the aggregation
+const trendingCacheTTL = 2 * time.Minute
+
+// trendingAggregates scores the activity_feed rows a of one manga; its one
+// argument is the window's half-life in days
+const trendingAggregates = `
+			SUM(CASE WHEN a.activity_type = 'rating' THEN 1 ELSE 0 END) as total_ratings,
+			COUNT(DISTINCT a.user_id) as total_readers,
+			SUM(
+				(CASE a.activity_type
+					WHEN 'rating' THEN 3.0
+					WHEN 'comment' THEN 2.0
+					WHEN 'list_add' THEN 2.0
+					ELSE 1.0
+				END) / (1.0 + MAX(julianday('now') - julianday(a.created_at), 0) / ?)
+			) as score`
+
+// trendingActivity is the activity in a window joined to manga m; its one
+// argument is the window's "-N days" cutoff
+const trendingActivity = `
+		FROM activity_feed a
+		JOIN manga m ON m.id = a.manga_id
+		WHERE julianday(a.created_at) >= julianday('now', ?)
+		  AND a.activity_type != 'status_change'`
+
 type service struct {
-	db *sql.DB
+	db           *sql.DB
+	cache        cache.Cache // optional
+	prior        Prior
+	scoresMaxAge time.Duration // read trending_scores while younger than this; 0 never reads them
 }
 
 // NewService creates a new leaderboard service
@@ -68,33 +152,85 @@ func NewService(db *sql.DB) Service {
 	return &service{db: db}
 }
 
-// GetTopRatedManga returns manga sorted by weighted rating
-// Uses Bayesian average to balance popular and highly-rated manga
-func (s *service) GetTopRatedManga(ctx context.Context, limit, offset int) (*LeaderboardResponse, error) {
+// NewServiceWithCache creates a leaderboard service that caches trending results
+func NewServiceWithCache(db *sql.DB, c cache.Cache) Service {
+	return &service{db: db, cache: c}
+}
+
+// NewServiceWithPrior creates a leaderboard service that ranks top-rated
+// manga with prior. c may be nil.
+func NewServiceWithPrior(db *sql.DB, c cache.Cache, prior Prior) Service {
+	return &service{db: db, cache: c, prior: prior}
+}
+
+// NewServiceWithScores creates a leaderboard service that serves trending from
+// the scores a Worker precomputes, as long as they are at most maxAge old.
+// Older or missing scores fall back to the live aggregation. c may be nil.
+func NewServiceWithScores(db *sql.DB, c cache.Cache, prior Prior, maxAge time.Duration) Service {
+	return &service{db: db, cache: c, prior: prior, scoresMaxAge: maxAge}
+}
+
+// GetTopRatedManga returns manga with at least MinVotes ratings, sorted by
+// Bayesian average: (votes*avg + MinVotes*Mean) / (votes + MinVotes), so a
+// single 10/10 cannot outrank a series many readers rated well
+func (s *service) GetTopRatedManga(ctx context.Context, limit, offset int, filter MangaFilter) (*LeaderboardResponse, error) {
+	return s.rankedManga(ctx, limit, offset, filter, false)
+}
+
+// GetHiddenGems returns manga rated fewer than MinVotes times, best average
+// first, so they stay discoverable while too new for the main ranking
+func (s *service) GetHiddenGems(ctx context.Context, limit, offset int, filter MangaFilter) (*LeaderboardResponse, error) {
+	return s.rankedManga(ctx, limit, offset, filter, true)
+}
+
+// rankedManga lists rated manga above (top rated) or below (hidden gems) the
+// vote threshold
+func (s *service) rankedManga(ctx context.Context, limit, offset int, filter MangaFilter, gems bool) (*LeaderboardResponse, error) {
 	if limit <= 0 {
 		limit = 20
 	}
 	if limit > 100 {
 		limit = 100
 	}
+	filter.Genre = strings.TrimSpace(filter.Genre)
+
+	minVotes := max(s.prior.MinVotes, 0)
+	mean, err := s.priorMean(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	voteCondition, order, kind := "votes.cnt >= ?", "weighted_rating DESC, total_ratings DESC", "top_rated"
+	if gems {
+		voteCondition, order, kind = "votes.cnt < ?", "avg_rating DESC, total_ratings DESC", "hidden_gems"
+	}
+	genreFilter, genreArgs := genreCondition(filter.Genre)
+	typeFilter, typeArgs := typeCondition(filter.Type)
+
+	args := []interface{}{minVotes, mean, minVotes, max(minVotes, 1)}
+	args = append(args, genreArgs...)
+	args = append(args, typeArgs...)
+	args = append(args, limit, offset)
 
-	// Query manga with their rating stats and reader counts
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT 
+		SELECT
 			m.id, m.title, m.cover_url, m.author,
-			COALESCE(AVG(r.overall_rating), 0) as avg_rating,
-			COUNT(DISTINCT r.id) as total_ratings,
-			COUNT(DISTINCT p.user_id) as total_readers
+			votes.avg as avg_rating,
+			votes.cnt as total_ratings,
+			(SELECT COUNT(DISTINCT p.user_id) FROM reading_progress p WHERE p.manga_id = m.id) as total_readers,
+			(votes.cnt * votes.avg + ? * ?) / (votes.cnt + ?) as weighted_rating
 		FROM manga m
-		LEFT JOIN manga_ratings r ON m.id = r.manga_id
-		LEFT JOIN reading_progress p ON m.id = p.manga_id
-		GROUP BY m.id
-		HAVING COUNT(DISTINCT r.id) >= 1
-		ORDER BY avg_rating DESC, total_ratings DESC
-		LIMIT ? OFFSET ?`, limit, offset,
+		JOIN (
+			SELECT manga_id, AVG(overall_rating) as avg, COUNT(*) as cnt
+			FROM manga_ratings
+			GROUP BY manga_id
+		) votes ON votes.manga_id = m.id
+		WHERE `+voteCondition+genreFilter+typeFilter+`
+		ORDER BY `+order+`, m.title ASC
+		LIMIT ? OFFSET ?`, args...,
 	)
 	if err != nil {
-		return nil, fmt.Errorf("get top rated manga: %w", err)
+		return nil, fmt.Errorf("get %s manga: %w", kind, err)
 	}
 	defer rows.Close()
 
@@ -106,7 +242,7 @@ func (s *service) GetTopRatedManga(ctx context.Context, limit, offset int) (*Lea
 
 		err := rows.Scan(
 			&e.MangaID, &e.Title, &coverURL, &author,
-			&e.AverageRating, &e.TotalRatings, &e.TotalReaders,
+			&e.AverageRating, &e.TotalRatings, &e.TotalReaders, &e.WeightedRating,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan manga entry: %w", err)
@@ -118,15 +254,34 @@ func (s *service) GetTopRatedManga(ctx context.Context, limit, offset int) (*Lea
 		entries = append(entries, e)
 		rank++
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate %s manga: %w", kind, err)
+	}
 
 	return &LeaderboardResponse{
-		Type:      "top_rated",
+		Type:      kind,
 		Period:    "all_time",
+		Genre:     filter.Genre,
+		MangaType: filter.Type,
+		MinVotes:  minVotes,
 		Entries:   entries,
 		UpdatedAt: time.Now(),
 	}, nil
 }
 
+// priorMean is the configured prior mean, or the average of every rating
+func (s *service) priorMean(ctx context.Context) (float64, error) {
+	if s.prior.Mean > 0 {
+		return s.prior.Mean, nil
+	}
+	var mean float64
+	err := s.db.QueryRowContext(ctx, `SELECT COALESCE(AVG(overall_rating), 0) FROM manga_ratings`).Scan(&mean)
+	if err != nil {
+		return 0, fmt.Errorf("get average rating: %w", err)
+	}
+	return mean, nil
+}
+
 // GetMostActiveUsers returns users sorted by engagement score
 // Score = completed*10 + chapters*1 + ratings*5 + comments*3
 func (s *service) GetMostActiveUsers(ctx context.Context, limit, offset int) (*LeaderboardResponse, error) {
@@ -206,40 +361,136 @@ func (s *service) GetMostActiveUsers(ctx context.Context, limit, offset int) (*L
 	}, nil
 }
 
-// GetTrendingManga returns manga with most activity in last N days
-// Activity = new ratings + new library adds + comments
-// Falls back to top manga by MAL score if no recent activity
-func (s *service) GetTrendingManga(ctx context.Context, limit, offset int, days int) (*LeaderboardResponse, error) {
+// GetTrendingManga returns manga with the highest decayed activity score in a window.
+// Each activity_feed event counts by type (rating 3, comment/list_add 2, progress 1)
+// and is divided by 1 + age/halfLife, so recent activity weighs more.
+// Scores precomputed by a Worker are used when fresh enough.
+// Falls back to top-rated manga when the window has no activity.
+func (s *service) GetTrendingManga(ctx context.Context, limit, offset int, window, genre string) (*LeaderboardResponse, error) {
 	if limit <= 0 {
 		limit = 20
 	}
 	if limit > 100 {
 		limit = 100
 	}
-	if days <= 0 {
-		days = 7 // Default to weekly trending
+	spec, ok := trendingWindows[window]
+	if !ok {
+		window = TrendingWindowWeek
+		spec = trendingWindows[window]
 	}
+	genre = strings.TrimSpace(genre)
 
-	// Calculate date threshold
-	threshold := time.Now().AddDate(0, 0, -days)
+	cacheKey := cache.BuildKey(cache.PrefixLeaderboard,
+		fmt.Sprintf("trending:%s:%s:%d:%d", window, strings.ToLower(genre), limit, offset))
+	if cached := s.cachedTrending(ctx, cacheKey); cached != nil {
+		return cached, nil
+	}
+
+	fresh, err := s.scoresFresh(ctx, window)
+	if err != nil {
+		return nil, err
+	}
+	var entries []MangaLeaderboardEntry
+	if fresh {
+		entries, err = s.precomputedTrending(ctx, window, genre, limit, offset)
+	} else {
+		entries, err = s.liveTrending(ctx, spec, genre, limit, offset)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Fallback: If no trending data, show top manga by rating
+	fallback := false
+	if len(entries) == 0 {
+		fallback = true
+		if entries, err = s.topRatedFallback(ctx, limit, offset, genre); err != nil {
+			return nil, err
+		}
+	}
+
+	response := &LeaderboardResponse{
+		Type:      "trending",
+		Period:    spec.period,
+		Genre:     genre,
+		Fallback:  fallback,
+		Entries:   entries,
+		UpdatedAt: time.Now(),
+	}
+	s.storeTrending(ctx, cacheKey, response, entries)
+	return response, nil
+}
+
+// liveTrending aggregates activity_feed for one page of a window
+func (s *service) liveTrending(ctx context.Context, spec trendingWindow, genre string, limit, offset int) ([]MangaLeaderboardEntry, error) {
+	genreFilter, genreArgs := genreCondition(genre)
+
+	args := []interface{}{spec.halfLifeDays, fmt.Sprintf("-%d days", spec.days)}
+	args = append(args, genreArgs...)
+	args = append(args, limit, offset)
 
 	rows, err := s.db.QueryContext(ctx, `
-		SELECT 
+		SELECT
 			m.id, m.title, m.cover_url, m.author,
-			COALESCE(AVG(r.overall_rating), 0) as avg_rating,
-			COUNT(DISTINCT r.id) as total_ratings,
-			COUNT(DISTINCT p.user_id) as total_readers
-		FROM manga m
-		LEFT JOIN manga_ratings r ON m.id = r.manga_id AND r.created_at >= ?
-		LEFT JOIN reading_progress p ON m.id = p.manga_id AND p.created_at >= ?
+			COALESCE(m.average_rating, 0) as avg_rating,`+trendingAggregates+
+		trendingActivity+genreFilter+`
 		GROUP BY m.id
-		HAVING (COUNT(DISTINCT r.id) + COUNT(DISTINCT p.user_id)) >= 1
-		ORDER BY (COUNT(DISTINCT r.id) + COUNT(DISTINCT p.user_id)) DESC
-		LIMIT ? OFFSET ?`, threshold, threshold, limit, offset,
+		ORDER BY score DESC, m.title ASC
+		LIMIT ? OFFSET ?`, args...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("get trending manga: %w", err)
 	}
+	return scanTrending(rows, offset)
+}
+
+// scoresFresh reports whether the worker has written scores for window
+// within scoresMaxAge
+func (s *service) scoresFresh(ctx context.Context, window string) (bool, error) {
+	if s.scoresMaxAge <= 0 {
+		return false, nil
+	}
+	var fresh bool
+	err := s.db.QueryRowContext(ctx, `
+		SELECT EXISTS (
+			SELECT 1 FROM trending_scores
+			WHERE trend_window = ? AND computed_at >= datetime('now', ?)
+		)`, window, fmt.Sprintf("-%d seconds", int(s.scoresMaxAge.Seconds())),
+	).Scan(&fresh)
+	if err != nil {
+		return false, fmt.Errorf("check trending scores: %w", err)
+	}
+	return fresh, nil
+}
+
+// precomputedTrending reads one page of a window from trending_scores.
+// The average rating comes from manga, which its triggers keep current.
+func (s *service) precomputedTrending(ctx context.Context, window, genre string, limit, offset int) ([]MangaLeaderboardEntry, error) {
+	genreFilter, genreArgs := genreCondition(genre)
+
+	args := []interface{}{window}
+	args = append(args, genreArgs...)
+	args = append(args, limit, offset)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			m.id, m.title, m.cover_url, m.author,
+			COALESCE(m.average_rating, 0) as avg_rating,
+			t.total_ratings, t.total_readers, t.score
+		FROM trending_scores t
+		JOIN manga m ON m.id = t.manga_id
+		WHERE t.trend_window = ?`+genreFilter+`
+		ORDER BY t.score DESC, m.title ASC
+		LIMIT ? OFFSET ?`, args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get precomputed trending manga: %w", err)
+	}
+	return scanTrending(rows, offset)
+}
+
+// scanTrending reads ranked trending rows and closes them
+func scanTrending(rows *sql.Rows, offset int) ([]MangaLeaderboardEntry, error) {
 	defer rows.Close()
 
 	var entries []MangaLeaderboardEntry
@@ -250,7 +501,7 @@ func (s *service) GetTrendingManga(ctx context.Context, limit, offset int, days
 
 		err := rows.Scan(
 			&e.MangaID, &e.Title, &coverURL, &author,
-			&e.AverageRating, &e.TotalRatings, &e.TotalReaders,
+			&e.AverageRating, &e.TotalRatings, &e.TotalReaders, &e.TrendingScore,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("scan trending entry: %w", err)
@@ -262,53 +513,120 @@ func (s *service) GetTrendingManga(ctx context.Context, limit, offset int, days
 		entries = append(entries, e)
 		rank++
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate trending manga: %w", err)
+	}
+	return entries, nil
+}
 
-	// Fallback: If no trending data, show top manga by rating
-	if len(entries) == 0 {
-		fallbackRows, err := s.db.QueryContext(ctx, `
-			SELECT 
-				m.id, m.title, m.cover_url, m.author,
-				COALESCE(m.rating, 0) as avg_rating,
-				0 as total_ratings,
-				0 as total_readers
-			FROM manga m
-			ORDER BY m.rating DESC, m.title ASC
-			LIMIT ? OFFSET ?`, limit, offset,
+// topRatedFallback lists manga by stored average rating, optionally within a genre
+func (s *service) topRatedFallback(ctx context.Context, limit, offset int, genre string) ([]MangaLeaderboardEntry, error) {
+	genreFilter, genreArgs := genreCondition(genre)
+	args := append(genreArgs, limit, offset)
+
+	rows, err := s.db.QueryContext(ctx, `
+		SELECT
+			m.id, m.title, m.cover_url, m.author,
+			COALESCE(m.average_rating, 0) as avg_rating,
+			COALESCE(m.rating_count, 0) as total_ratings,
+			0 as total_readers
+		FROM manga m
+		WHERE 1 = 1`+genreFilter+`
+		ORDER BY m.average_rating DESC, m.rating_count DESC, m.title ASC
+		LIMIT ? OFFSET ?`, args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get fallback trending manga: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []MangaLeaderboardEntry
+	rank := offset + 1
+	for rows.Next() {
+		var e MangaLeaderboardEntry
+		var coverURL, author sql.NullString
+
+		err := rows.Scan(
+			&e.MangaID, &e.Title, &coverURL, &author,
+			&e.AverageRating, &e.TotalRatings, &e.TotalReaders,
 		)
 		if err != nil {
-			return nil, fmt.Errorf("get fallback trending manga: %w", err)
-		}
-		defer fallbackRows.Close()
-
-		for fallbackRows.Next() {
-			var e MangaLeaderboardEntry
-			var coverURL, author sql.NullString
-
-			err := fallbackRows.Scan(
-				&e.MangaID, &e.Title, &coverURL, &author,
-				&e.AverageRating, &e.TotalRatings, &e.TotalReaders,
-			)
-			if err != nil {
-				return nil, fmt.Errorf("scan fallback entry: %w", err)
-			}
-
-			e.Rank = rank
-			e.CoverURL = coverURL.String
-			e.Author = author.String
-			entries = append(entries, e)
-			rank++
+			return nil, fmt.Errorf("scan fallback entry: %w", err)
 		}
+
+		e.Rank = rank
+		e.CoverURL = coverURL.String
+		e.Author = author.String
+		entries = append(entries, e)
+		rank++
 	}
+	return entries, rows.Err()
+}
 
-	period := "weekly"
-	if days == 30 {
-		period = "monthly"
+// genreCondition restricts a query on alias m to one genre, matched by slug or name
+func genreCondition(genre string) (string, []interface{}) {
+	if genre == "" {
+		return "", nil
 	}
+	return `
+			AND EXISTS (
+				SELECT 1 FROM manga_genres mg
+				JOIN genres g ON g.id = mg.genre_id
+				WHERE mg.manga_id = m.id AND (g.slug = LOWER(?) OR LOWER(g.name) = LOWER(?))
+			)`, []interface{}{genre, genre}
+}
+
+// typeCondition restricts a query on alias m to one manga type
+func typeCondition(mangaType string) (string, []interface{}) {
+	if mangaType == "" {
+		return "", nil
+	}
+	return `
+			AND m.type = ?`, []interface{}{mangaType}
+}
 
+// trendingCacheEntry is the cached form of a trending response
+type trendingCacheEntry struct {
+	Period    string                  `json:"period"`
+	Genre     string                  `json:"genre"`
+	Fallback  bool                    `json:"fallback"`
+	Entries   []MangaLeaderboardEntry `json:"entries"`
+	UpdatedAt time.Time               `json:"updated_at"`
+}
+
+// cachedTrending returns a cached response, or nil on a miss.
+// Cache errors are treated as misses.
+func (s *service) cachedTrending(ctx context.Context, key string) *LeaderboardResponse {
+	if s.cache == nil {
+		return nil
+	}
+	raw, err := s.cache.Get(ctx, key)
+	if err != nil || raw == "" {
+		return nil
+	}
+	var entry trendingCacheEntry
+	if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+		return nil
+	}
 	return &LeaderboardResponse{
 		Type:      "trending",
-		Period:    period,
+		Period:    entry.Period,
+		Genre:     entry.Genre,
+		Fallback:  entry.Fallback,
+		Entries:   entry.Entries,
+		UpdatedAt: entry.UpdatedAt,
+	}
+}
+
+func (s *service) storeTrending(ctx context.Context, key string, resp *LeaderboardResponse, entries []MangaLeaderboardEntry) {
+	if s.cache == nil {
+		return
+	}
+	_ = s.cache.Set(ctx, key, trendingCacheEntry{
+		Period:    resp.Period,
+		Genre:     resp.Genre,
+		Fallback:  resp.Fallback,
 		Entries:   entries,
-		UpdatedAt: time.Now(),
-	}, nil
+		UpdatedAt: resp.UpdatedAt,
+	}, trendingCacheTTL)
 }
@@ -0,0 +1,112 @@
+// Package leaderboard - Trending Worker
+// Background job tính trước trending score vào bảng trending_scores
+// Chức năng:
+//   - Refresh: tính lại mọi window (decay theo thời gian hiện tại) trong một transaction
+//   - Run: chạy định kỳ, dừng khi context bị cancel
+//   - Leader election (tùy chọn): chỉ instance giữ lease mới tính
+package leaderboard
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"mangahub/pkg/database"
+	"mangahub/pkg/logger"
+)
+
+// trendingLeaseName is the worker_leases row instances compete for
+const trendingLeaseName = "leaderboard.trending"
+
+// Worker precomputes trending scores for every window into trending_scores
+type Worker struct {
+	db     *sql.DB
+	holder string // lease holder ID; empty refreshes without leader election
+}
+
+// NewWorker creates a trending worker
+func NewWorker(db *sql.DB) *Worker {
+	return &Worker{db: db}
+}
+
+// SetLeaderElection makes the worker refresh only while it holds the shared
+// lease, so one of several instances on the same database does the work.
+// holder must be unique per instance.
+func (w *Worker) SetLeaderElection(holder string) {
+	w.holder = holder
+}
+
+// Refresh recomputes the scores of every window. Scores decay with age, so
+// they are rewritten from scratch rather than updated.
+func (w *Worker) Refresh(ctx context.Context) error {
+	return database.WithTx(ctx, w.db, func(tx *sql.Tx) error {
+		for window, spec := range trendingWindows {
+			if _, err := tx.ExecContext(ctx,
+				`DELETE FROM trending_scores WHERE trend_window = ?`, window,
+			); err != nil {
+				return fmt.Errorf("clear %s trending scores: %w", window, err)
+			}
+			if _, err := tx.ExecContext(ctx, `
+				INSERT INTO trending_scores (trend_window, manga_id, total_ratings, total_readers, score, computed_at)
+				SELECT ?, m.id,`+trendingAggregates+`, datetime('now')`+
+				trendingActivity+`
+				GROUP BY m.id`,
+				window, spec.halfLifeDays, fmt.Sprintf("-%d days", spec.days),
+			); err != nil {
+				return fmt.Errorf("compute %s trending scores: %w", window, err)
+			}
+		}
+		return nil
+	})
+}
+
+// Run refreshes once and then every interval until ctx is done.
+// It never holds up shutdown: queries stop with ctx and nothing waits on Run.
+func (w *Worker) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	if w.holder != "" {
+		defer w.release()
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		w.tick(ctx, interval)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick runs one refresh if this instance may
+func (w *Worker) tick(ctx context.Context, interval time.Duration) {
+	if w.holder != "" {
+		// The lease outlives a couple of missed ticks before another instance takes over
+		leader, err := database.AcquireLease(ctx, w.db, trendingLeaseName, w.holder, 3*interval)
+		if err != nil {
+			logger.Warnf("trending worker lease failed: %v", err)
+			return
+		}
+		if !leader {
+			return
+		}
+	}
+	if err := w.Refresh(ctx); err != nil && ctx.Err() == nil {
+		logger.Warnf("trending refresh failed: %v", err)
+	}
+}
+
+// release hands the lease over on shutdown instead of letting it expire
+func (w *Worker) release() {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	if err := database.ReleaseLease(ctx, w.db, trendingLeaseName, w.holder); err != nil {
+		logger.Warnf("trending worker: %v", err)
+	}
+}
@@ -5,23 +5,40 @@ import (
 	"strconv"
 
 	"github.com/gin-gonic/gin"
+	"mangahub/internal/auth"
+	"mangahub/internal/udp"
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/models"
 )
 
 type Handler struct {
 	svc Service
+
+	// Optional external resync (see SetResync)
+	resyncer Resyncer
+	notify   func(udp.Notification) error
+
+	// Optional duplicate merge (see SetMerger)
+	merger Merger
 }
 
 func NewHandler(svc Service) *Handler {
 	return &Handler{svc: svc}
 }
 
+// ListManga handles GET /manga
+// Query params: q, status, sort (rating|year|chapters|title, default rating),
+// order (asc|desc, default desc; asc for title), limit, offset, mode=fts
 func (h *Handler) ListManga(c *gin.Context) {
 	var req models.MangaSearchRequest
 	req.Query = c.Query("q")
 	req.Status = c.Query("status")
-	req.SortBy = c.Query("sort_by")
+	// sort_by is the older name of sort
+	req.SortBy = c.DefaultQuery("sort", c.Query("sort_by"))
 	req.Order = c.Query("order")
+	req.Mode = c.Query("mode")
+	// genre is a slug and may repeat: ?genre=action&genre=comedy
+	req.Genres = c.QueryArray("genre")
 
 	if limitStr := c.Query("limit"); limitStr != "" {
 		if v, err := strconv.Atoi(limitStr); err == nil {
@@ -42,13 +59,7 @@ func (h *Handler) ListManga(c *gin.Context) {
 
 	resp, err := h.svc.List(c.Request.Context(), req)
 	if err != nil {
-		if appErr, ok := err.(*models.AppError); ok {
-			c.JSON(appErr.StatusCode,
-				models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
-			return
-		}
-		c.JSON(http.StatusInternalServerError,
-			models.NewErrorResponse(models.ErrCodeInternal, "unexpected error", nil))
+		apperrors.Respond(c, err, "unexpected error")
 		return
 	}
 
@@ -60,15 +71,111 @@ func (h *Handler) GetManga(c *gin.Context) {
 	id := c.Param("id")
 	m, err := h.svc.GetByID(c.Request.Context(), id)
 	if err != nil {
-		if appErr, ok := err.(*models.AppError); ok {
-			c.JSON(appErr.StatusCode,
-				models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
-			return
-		}
-		c.JSON(http.StatusInternalServerError,
-			models.NewErrorResponse(models.ErrCodeInternal, "unexpected error", nil))
+		apperrors.Respond(c, err, "unexpected error")
 		return
 	}
 	c.JSON(http.StatusOK,
 		models.NewSuccessResponse(m, "manga details"))
 }
+
+// GetChapters handles GET /manga/:id/chapters
+func (h *Handler) GetChapters(c *gin.Context) {
+	list, err := h.svc.Chapters(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, err, "unexpected error")
+		return
+	}
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(list, "manga chapters"))
+}
+
+// SuggestManga handles GET /manga/suggest
+// Query params: ?q=<prefix>&limit=10 (max 20). q needs at least 2 characters.
+func (h *Handler) SuggestManga(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(models.DefaultSuggestLimit)))
+
+	suggestions, err := h.svc.Suggest(c.Request.Context(), c.Query("q"), limit)
+	if err != nil {
+		apperrors.Respond(c, err, "unexpected error")
+		return
+	}
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(suggestions, "manga suggestions"))
+}
+
+// GetRandomManga handles GET /manga/random
+// Query params: genre (name or slug), type (manga|manhwa|manhua|novel),
+// exclude (the id shown last, so asking again moves on)
+func (h *Handler) GetRandomManga(c *gin.Context) {
+	req := models.RandomMangaRequest{
+		Genre:   c.Query("genre"),
+		Type:    c.Query("type"),
+		Exclude: c.Query("exclude"),
+	}
+
+	manga, err := h.svc.Random(c.Request.Context(), req)
+	if err != nil {
+		apperrors.Respond(c, err, "unexpected error")
+		return
+	}
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(manga, "random manga"))
+}
+
+// ListGenres handles GET /genres
+// Query params: ?q=act&hide_empty=true&sort=name|count&limit=50&offset=0
+func (h *Handler) ListGenres(c *gin.Context) {
+	req := models.GenreListRequest{
+		Query:     c.Query("q"),
+		HideEmpty: c.Query("hide_empty") == "true",
+		Sort:      c.Query("sort"),
+	}
+	req.Limit, _ = strconv.Atoi(c.DefaultQuery("limit", "0"))
+	req.Offset, _ = strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	resp, err := h.svc.Genres(c.Request.Context(), req)
+	if err != nil {
+		apperrors.Respond(c, err, "unexpected error")
+		return
+	}
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(resp, "genres"))
+}
+
+// GetSimilarManga handles GET /manga/:id/similar
+// Query params: ?limit=10 (max 50). Authenticated callers don't get manga already in their library.
+func (h *Handler) GetSimilarManga(c *gin.Context) {
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "10"))
+
+	userID := ""
+	if user := auth.GetCurrentUser(c); user != nil {
+		userID = user.ID
+	}
+
+	similar, err := h.svc.Similar(c.Request.Context(), c.Param("id"), userID, limit)
+	if err != nil {
+		apperrors.Respond(c, err, "unexpected error")
+		return
+	}
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(similar, "similar manga"))
+}
+
+// BatchGetManga handles POST /manga/batch
+// Body: {"ids": ["id1", "id2", ...]} (at most models.MaxMangaBatch)
+func (h *Handler) BatchGetManga(c *gin.Context) {
+	var req models.MangaBatchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeBadRequest, "invalid JSON body", map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	resp, err := h.svc.GetBatch(c.Request.Context(), req)
+	if err != nil {
+		apperrors.Respond(c, err, "unexpected error")
+		return
+	}
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(resp, "manga batch"))
+}
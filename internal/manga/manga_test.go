@@ -0,0 +1,570 @@
+// Package manga - Manga Repository Tests
+// Unit tests cho full-text search ranking, similar và batch lookup
+package manga
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+func insertTestManga(t *testing.T, db *sql.DB, id, title, author, description string) {
+	_, err := db.Exec(`
+		INSERT INTO manga (id, title, author, artist, description, cover_url, status, type, total_chapters, year)
+		VALUES (?, ?, ?, ?, ?, '', 'ongoing', 'manga', 10, 2000)`,
+		id, title, author, author, description,
+	)
+	if err != nil {
+		t.Fatalf("failed to insert manga: %v", err)
+	}
+}
+
+func TestSearchMangaFTSRanking(t *testing.T) {
+	db := testutil.OpenDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	insertTestManga(t, db, "m-cook", "Sea Kitchen", "Someone",
+		"A cook runs a tavern by the sea. One night a pirate orders stew and leaves behind a treasure map.")
+	insertTestManga(t, db, "m-onepiece", "One Piece", "Eiichiro Oda",
+		"Luffy sets out to become King of the Pirates. The pirate crew sails the Grand Line in search of the legendary pirate treasure, the One Piece.")
+	insertTestManga(t, db, "m-other", "Vagabond", "Takehiko Inoue",
+		"A swordsman wanders feudal Japan.")
+
+	results, total, err := repo.SearchMangaFTS(ctx, "pirate treasure", 10, 0)
+	if err != nil {
+		t.Fatalf("SearchMangaFTS failed: %v", err)
+	}
+
+	if total != 2 || len(results) != 2 {
+		t.Fatalf("expected 2 matches, got total=%d len=%d", total, len(results))
+	}
+	if results[0].ID != "m-onepiece" {
+		t.Errorf("expected One Piece to rank first, got %s", results[0].Title)
+	}
+}
+
+func TestSearchMangaFTSFallsBackOnBadSyntax(t *testing.T) {
+	db := testutil.OpenDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	insertTestManga(t, db, "m-quote", `The "Quoted" Chef`, "Someone", "Cooking battles.")
+
+	// Unbalanced quote is rejected by FTS5 and must use substring search
+	results, _, err := repo.SearchMangaFTS(ctx, `"Quoted`, 10, 0)
+	if err != nil {
+		t.Fatalf("expected fallback instead of error, got %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "m-quote" {
+		t.Errorf("expected substring fallback to find the manga, got %d results", len(results))
+	}
+
+	// Lone "*" must not error either
+	if _, _, err := repo.SearchMangaFTS(ctx, "*", 10, 0); err != nil {
+		t.Errorf("expected fallback for lone *, got %v", err)
+	}
+}
+
+// tagGenres links a manga to genres by slug, creating the genres as needed
+func tagGenres(t *testing.T, db *sql.DB, mangaID string, slugs ...string) {
+	for _, slug := range slugs {
+		if _, err := db.Exec(`INSERT OR IGNORE INTO genres (id, name, slug) VALUES (?, ?, ?)`, "g-"+slug, slug, slug); err != nil {
+			t.Fatalf("failed to insert genre: %v", err)
+		}
+		if _, err := db.Exec(`INSERT INTO manga_genres (id, manga_id, genre_id) VALUES (?, ?, ?)`, mangaID+"-"+slug, mangaID, "g-"+slug); err != nil {
+			t.Fatalf("failed to tag manga: %v", err)
+		}
+	}
+}
+
+func setRating(t *testing.T, db *sql.DB, mangaID string, rating float64) {
+	if _, err := db.Exec(`UPDATE manga SET average_rating = ? WHERE id = ?`, rating, mangaID); err != nil {
+		t.Fatalf("failed to set rating: %v", err)
+	}
+}
+
+func TestSimilarScoresGenreOverlapAndSkipsLibrary(t *testing.T) {
+	db := testutil.OpenDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	insertTestManga(t, db, "berserk", "Berserk", "Miura", "")
+	insertTestManga(t, db, "claymore", "Claymore", "Yagi", "")
+	insertTestManga(t, db, "vinland", "Vinland Saga", "Yukimura", "")
+	insertTestManga(t, db, "yotsuba", "Yotsuba&!", "Azuma", "")
+	tagGenres(t, db, "berserk", "action", "dark-fantasy", "horror")
+	tagGenres(t, db, "claymore", "action", "dark-fantasy")
+	tagGenres(t, db, "vinland", "action", "historical")
+	tagGenres(t, db, "yotsuba", "comedy")
+
+	similar, err := svc.Similar(ctx, "berserk", "", 10)
+	if err != nil {
+		t.Fatalf("Similar failed: %v", err)
+	}
+	if len(similar) != 3 {
+		t.Fatalf("expected 3 suggestions, got %d", len(similar))
+	}
+	if similar[0].ID != "claymore" || similar[0].SharedGenres != 2 {
+		t.Errorf("expected Claymore first with 2 shared genres, got %s (%d)", similar[0].ID, similar[0].SharedGenres)
+	}
+	if similar[2].ID != "yotsuba" {
+		t.Errorf("expected Yotsuba last, got %s", similar[2].ID)
+	}
+
+	// Manga already in the caller's library are excluded
+	if _, err := db.Exec(`INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'guts', 'g@example.com', 'x', 'Guts')`); err != nil {
+		t.Fatalf("failed to insert user: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO reading_progress (id, user_id, manga_id) VALUES ('p1', 'u1', 'claymore')`); err != nil {
+		t.Fatalf("failed to insert progress: %v", err)
+	}
+	similar, _ = svc.Similar(ctx, "berserk", "u1", 10)
+	for _, s := range similar {
+		if s.ID == "claymore" {
+			t.Error("Claymore is in the user's library and should be excluded")
+		}
+	}
+
+	if _, err := svc.Similar(ctx, "missing", "", 10); err == nil {
+		t.Error("expected an error for an unknown manga")
+	}
+}
+
+func TestSimilarWithoutGenresFallsBackToRating(t *testing.T) {
+	db := testutil.OpenDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	insertTestManga(t, db, "untagged", "Untagged", "Someone", "")
+	insertTestManga(t, db, "close", "Close Rating", "Someone", "")
+	insertTestManga(t, db, "far", "Far Rating", "Someone", "")
+	setRating(t, db, "untagged", 8.5)
+	setRating(t, db, "close", 8.0)
+	setRating(t, db, "far", 3.0)
+	tagGenres(t, db, "far", "action")
+
+	similar, err := svc.Similar(ctx, "untagged", "", 10)
+	if err != nil {
+		t.Fatalf("Similar failed: %v", err)
+	}
+	if len(similar) != 2 {
+		t.Fatalf("a manga without genres should still get suggestions, got %d", len(similar))
+	}
+	if similar[0].ID != "close" {
+		t.Errorf("expected the closest rating first, got %s", similar[0].ID)
+	}
+}
+
+func TestGetBatchPreservesOrderAndReportsMissing(t *testing.T) {
+	db := testutil.OpenDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	insertTestManga(t, db, "a", "Alpha", "Someone", "")
+	insertTestManga(t, db, "b", "Beta", "Someone", "")
+	insertTestManga(t, db, "c", "Gamma", "Someone", "")
+	tagGenres(t, db, "b", "action", "drama")
+
+	resp, err := svc.GetBatch(ctx, models.MangaBatchRequest{IDs: []string{"c", "missing", "a", "b", "a"}})
+	if err != nil {
+		t.Fatalf("GetBatch failed: %v", err)
+	}
+
+	var got []string
+	for _, m := range resp.Data {
+		got = append(got, m.ID)
+	}
+	if strings.Join(got, ",") != "c,a,b" {
+		t.Errorf("expected request order c,a,b without duplicates, got %v", got)
+	}
+	if len(resp.Missing) != 1 || resp.Missing[0] != "missing" {
+		t.Errorf("expected [missing], got %v", resp.Missing)
+	}
+	if len(resp.Data[2].Genres) != 2 {
+		t.Errorf("expected genres to be loaded, got %d", len(resp.Data[2].Genres))
+	}
+
+	tooMany := make([]string, models.MaxMangaBatch+1)
+	for i := range tooMany {
+		tooMany[i] = "a"
+	}
+	if _, err := svc.GetBatch(ctx, models.MangaBatchRequest{IDs: tooMany}); err == nil {
+		t.Error("expected an error above the batch limit")
+	}
+}
+
+func TestSuggestMatchesTitlePrefixes(t *testing.T) {
+	db := testutil.OpenDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	insertTestManga(t, db, "berserk", "Berserk", "Kentaro Miura", "")
+	insertTestManga(t, db, "kill", "The Berserker King", "Someone", "")
+	insertTestManga(t, db, "onepiece", "One Piece", "Eiichiro Oda", "")
+	insertTestManga(t, db, "onepunch", "One-Punch Man", "ONE", "Berserk mentioned only in the description")
+	db.Exec(`UPDATE manga SET average_rating = 9.5 WHERE id = 'kill'`)
+
+	suggest := func(prefix string) string {
+		t.Helper()
+		got, err := svc.Suggest(ctx, prefix, 0)
+		if err != nil {
+			t.Fatalf("Suggest(%q) failed: %v", prefix, err)
+		}
+		var ids []string
+		for _, s := range got {
+			ids = append(ids, s.ID)
+		}
+		return strings.Join(ids, ",")
+	}
+
+	// A title starting with the prefix beats a better rated mid-title match;
+	// descriptions are not searched
+	if got := suggest("BER"); got != "berserk,kill" {
+		t.Errorf("BER: got %q, want berserk,kill", got)
+	}
+	if got := suggest("one pi"); got != "onepiece" {
+		t.Errorf("one pi: got %q, want onepiece", got)
+	}
+	// FTS operators and quotes are matched literally instead of failing
+	if got := suggest(`one" OR pu`); got != "" {
+		t.Errorf("operator prefix: got %q, want nothing", got)
+	}
+
+	if _, err := svc.Suggest(ctx, " b ", 0); err == nil {
+		t.Error("expected a one-character prefix to be rejected")
+	}
+}
+
+func TestChaptersFillsMissingNumbers(t *testing.T) {
+	db := testutil.OpenDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	insertTestManga(t, db, "berserk", "Berserk", "Kentaro Miura", "")
+	insertTestManga(t, db, "vagabond", "Vagabond", "Takehiko Inoue", "")
+	db.Exec(`UPDATE manga SET total_chapters = 4 WHERE id IN ('berserk', 'vagabond')`)
+	db.Exec(`INSERT INTO chapters (manga_id, number, title) VALUES ('berserk', 2, 'The Brand'), ('berserk', 5, 'Extra')`)
+
+	describe := func(id string) string {
+		t.Helper()
+		list, err := svc.Chapters(ctx, id)
+		if err != nil {
+			t.Fatalf("Chapters(%s) failed: %v", id, err)
+		}
+		var parts []string
+		for _, ch := range list.Chapters {
+			label := ch.Title
+			if ch.Synthetic {
+				label = "-"
+			}
+			parts = append(parts, fmt.Sprintf("%d%s", ch.Number, label))
+		}
+		return strings.Join(parts, ",")
+	}
+
+	// Stored titles are merged in; chapters past total_chapters are kept
+	if got := describe("berserk"); got != "1-,2The Brand,3-,4-,5Extra" {
+		t.Errorf("berserk chapters = %q", got)
+	}
+	// Imported before chapters existed: a numbered list up to total_chapters
+	if got := describe("vagabond"); got != "1-,2-,3-,4-" {
+		t.Errorf("vagabond chapters = %q", got)
+	}
+	if _, err := svc.Chapters(ctx, "missing"); err == nil {
+		t.Error("expected an error for an unknown manga")
+	}
+}
+
+func TestCreateUpdateDeleteManga(t *testing.T) {
+	db := testutil.OpenDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+	// The server opens its database with foreign keys on; Delete relies on the cascades
+	if _, err := db.Exec(`PRAGMA foreign_keys = ON`); err != nil {
+		t.Fatalf("enable foreign keys: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO genres (id, name, slug) VALUES ('g-action', 'Action', 'action')`); err != nil {
+		t.Fatalf("insert genre: %v", err)
+	}
+
+	m := &models.Manga{Title: "Blame!", Author: "Tsutomu Nihei", Status: "completed", Type: "manga"}
+	if err := repo.Create(ctx, m, []string{"Action", "Sci-Fi", "sci-fi", " "}); err != nil {
+		t.Fatalf("Create failed: %v", err)
+	}
+	genreNames := func() string {
+		got, err := repo.GetByID(ctx, m.ID)
+		if err != nil {
+			t.Fatalf("GetByID failed: %v", err)
+		}
+		var names []string
+		for _, g := range got.Genres {
+			names = append(names, g.Name)
+		}
+		return strings.Join(names, ",")
+	}
+	// "Action" reuses the existing genre; "sci-fi" matches the one just created
+	if got := genreNames(); got != "Action,Sci-Fi" {
+		t.Errorf("genres after create = %q", got)
+	}
+	if err := repo.Create(ctx, &models.Manga{ID: m.ID, Title: "Dup", Status: "ongoing", Type: "manga"}, nil); err == nil {
+		t.Error("expected a conflict creating a duplicate id")
+	}
+
+	// nil genres leave the links alone; a list replaces them
+	m.Title = "BLAME!"
+	if err := repo.Update(ctx, m, nil); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if got := genreNames(); got != "Action,Sci-Fi" {
+		t.Errorf("genres after metadata update = %q", got)
+	}
+	if err := repo.Update(ctx, m, []string{"Horror"}); err != nil {
+		t.Fatalf("Update with genres failed: %v", err)
+	}
+	if got := genreNames(); got != "Horror" {
+		t.Errorf("genres after genre update = %q", got)
+	}
+
+	// Delete cascades through every table that references the manga
+	for _, stmt := range []string{
+		`INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'killy', 'k@example.com', 'x', 'Killy')`,
+		`INSERT INTO reading_progress (id, user_id, manga_id) VALUES ('p1', 'u1', '` + m.ID + `')`,
+		`INSERT INTO manga_ratings (id, manga_id, user_id, rating) VALUES ('r1', '` + m.ID + `', 'u1', 9)`,
+		`INSERT INTO chapters (manga_id, number, title) VALUES ('` + m.ID + `', 1, 'Net Sphere')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("seed %q: %v", stmt, err)
+		}
+	}
+	if err := repo.Delete(ctx, m.ID); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	for _, table := range []string{"manga", "manga_genres", "reading_progress", "manga_ratings", "chapters", "activity_feed", "manga_fts"} {
+		col := "manga_id"
+		if table == "manga" || table == "manga_fts" {
+			col = "id"
+		}
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM `+table+` WHERE `+col+` = ?`, m.ID).Scan(&n); err != nil {
+			t.Fatalf("count %s: %v", table, err)
+		}
+		if n != 0 {
+			t.Errorf("%s still has %d rows for the deleted manga", table, n)
+		}
+	}
+	if err := repo.Delete(ctx, m.ID); err == nil {
+		t.Error("expected not found deleting twice")
+	}
+	if err := repo.Update(ctx, m, nil); err == nil {
+		t.Error("expected not found updating a deleted manga")
+	}
+}
+
+func TestListSortsStablyAndRejectsUnknownSort(t *testing.T) {
+	db := testutil.OpenDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	insertTestManga(t, db, "c", "Gamma", "Someone", "")
+	insertTestManga(t, db, "a", "Alpha", "Someone", "")
+	insertTestManga(t, db, "b", "Beta", "Someone", "")
+	if _, err := db.Exec(`UPDATE manga SET year = 2010 WHERE id = 'b'`); err != nil {
+		t.Fatal(err)
+	}
+
+	ids := func(req models.MangaSearchRequest) string {
+		t.Helper()
+		req.Limit = 10
+		resp, err := svc.List(ctx, req)
+		if err != nil {
+			t.Fatalf("List failed: %v", err)
+		}
+		var got []string
+		for _, m := range resp.Data {
+			got = append(got, m.ID)
+		}
+		return strings.Join(got, ",")
+	}
+
+	// Equal years fall back to id order, whichever direction the year goes
+	if got := ids(models.MangaSearchRequest{SortBy: "year"}); got != "b,a,c" {
+		t.Errorf("year desc: expected b,a,c, got %s", got)
+	}
+	if got := ids(models.MangaSearchRequest{SortBy: "year", Order: "asc"}); got != "a,c,b" {
+		t.Errorf("year asc: expected a,c,b, got %s", got)
+	}
+	if got := ids(models.MangaSearchRequest{SortBy: "title"}); got != "a,b,c" {
+		t.Errorf("title: expected a,b,c, got %s", got)
+	}
+
+	_, err := svc.List(ctx, models.MangaSearchRequest{SortBy: "popularity", Limit: 10})
+	appErr, ok := err.(*models.AppError)
+	if !ok || appErr.Details["field"] != "sort" {
+		t.Errorf("expected a validation error on sort, got %v", err)
+	}
+}
+
+func TestStatusChangesAreRecordedOnce(t *testing.T) {
+	db := testutil.OpenDB(t)
+	repo := NewRepository(db)
+	ctx := context.Background()
+
+	count := func(query string) int {
+		t.Helper()
+		var n int
+		if err := db.QueryRow(query).Scan(&n); err != nil {
+			t.Fatalf("count failed: %v", err)
+		}
+		return n
+	}
+
+	// Importing a manga that is already completed is not a transition
+	done := &models.Manga{Title: "Done", Status: "completed", Type: "manga"}
+	if err := repo.Create(ctx, done, nil); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	m := &models.Manga{Title: "Running", Status: "ongoing", Type: "manga"}
+	if err := repo.Create(ctx, m, nil); err != nil {
+		t.Fatalf("create failed: %v", err)
+	}
+	if n := count(`SELECT COUNT(*) FROM manga_status_history`); n != 0 {
+		t.Fatalf("expected no history for inserts, got %d", n)
+	}
+
+	// Updating other fields or writing the same status records nothing
+	m.TotalChapters = 12
+	if err := repo.Update(ctx, m, nil); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	if n := count(`SELECT COUNT(*) FROM manga_status_history`); n != 0 {
+		t.Fatalf("expected no history for a no-op status, got %d", n)
+	}
+
+	m.Status = "completed"
+	if err := repo.Update(ctx, m, nil); err != nil {
+		t.Fatalf("update failed: %v", err)
+	}
+	var from, to string
+	if err := db.QueryRow(`SELECT old_status, new_status FROM manga_status_history WHERE manga_id = ?`, m.ID).Scan(&from, &to); err != nil {
+		t.Fatalf("expected a history row: %v", err)
+	}
+	if from != "ongoing" || to != "completed" {
+		t.Errorf("expected ongoing → completed, got %s → %s", from, to)
+	}
+	if n := count(`SELECT COUNT(*) FROM activity_feed WHERE activity_type = 'status_change' AND user_id IS NULL`); n != 1 {
+		t.Errorf("expected one status_change activity, got %d", n)
+	}
+
+	got, err := repo.GetByID(ctx, m.ID)
+	if err != nil {
+		t.Fatalf("get failed: %v", err)
+	}
+	if !got.RecentlyCompleted(time.Now()) {
+		t.Errorf("expected manga to be recently completed, completed_at=%v", got.CompletedAt)
+	}
+	if got, _ := repo.GetByID(ctx, done.ID); got.CompletedAt != nil {
+		t.Errorf("expected no completed_at for a manga imported as completed")
+	}
+}
+
+func TestRandomFiltersAndSkipsLastShown(t *testing.T) {
+	db := testutil.OpenDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	insertTestManga(t, db, "berserk", "Berserk", "Miura", "")
+	insertTestManga(t, db, "claymore", "Claymore", "Yagi", "")
+	insertTestManga(t, db, "yotsuba", "Yotsuba&!", "Azuma", "")
+	tagGenres(t, db, "berserk", "action", "slice-of-life")
+	tagGenres(t, db, "claymore", "action")
+	tagGenres(t, db, "yotsuba", "slice-of-life")
+
+	// Two action manga: excluding one always yields the other
+	for i := 0; i < 20; i++ {
+		m, err := svc.Random(ctx, models.RandomMangaRequest{Genre: "Action", Exclude: "berserk"})
+		if err != nil {
+			t.Fatalf("Random failed: %v", err)
+		}
+		if m.ID != "claymore" {
+			t.Fatalf("got %s, want claymore", m.ID)
+		}
+	}
+
+	// Genre names are matched by slug
+	m, err := svc.Random(ctx, models.RandomMangaRequest{Genre: "Slice of Life", Exclude: "yotsuba"})
+	if err != nil || m.ID != "berserk" {
+		t.Errorf("got %v, %v; want berserk", m, err)
+	}
+
+	// The only match comes back even when it was shown last
+	if _, err := db.Exec(`UPDATE manga SET type = 'manhwa' WHERE id = 'yotsuba'`); err != nil {
+		t.Fatalf("update type: %v", err)
+	}
+	m, err = svc.Random(ctx, models.RandomMangaRequest{Type: "manhwa", Exclude: "yotsuba"})
+	if err != nil || m.ID != "yotsuba" {
+		t.Errorf("got %v, %v; want yotsuba", m, err)
+	}
+
+	if _, err := svc.Random(ctx, models.RandomMangaRequest{Type: "novel"}); err == nil {
+		t.Error("expected not found when nothing matches")
+	}
+	if _, err := svc.Random(ctx, models.RandomMangaRequest{Type: "comic"}); err == nil {
+		t.Error("expected an unknown type to be rejected")
+	}
+}
+
+func TestGenresCountsIncludeEmptyAndPage(t *testing.T) {
+	db := testutil.OpenDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+
+	insertTestManga(t, db, "m-1", "Berserk", "Miura", "")
+	insertTestManga(t, db, "m-2", "Vagabond", "Inoue", "")
+	tagGenres(t, db, "m-1", "action", "horror")
+	tagGenres(t, db, "m-2", "action")
+	if _, err := db.Exec(`INSERT INTO genres (id, name, slug) VALUES ('g-mecha', 'mecha', 'mecha')`); err != nil {
+		t.Fatalf("failed to insert genre: %v", err)
+	}
+
+	counts := func(resp *models.GenreListResponse) string {
+		var parts []string
+		for _, g := range resp.Data {
+			parts = append(parts, fmt.Sprintf("%s=%d", g.Slug, g.MangaCount))
+		}
+		return strings.Join(parts, " ")
+	}
+
+	all, err := svc.Genres(ctx, models.GenreListRequest{})
+	if err != nil {
+		t.Fatalf("Genres failed: %v", err)
+	}
+	if got := counts(all); got != "action=2 horror=1 mecha=0" || all.Total != 3 {
+		t.Errorf("expected every genre by name with counts, got %q (total %d)", got, all.Total)
+	}
+
+	used, _ := svc.Genres(ctx, models.GenreListRequest{HideEmpty: true, Sort: models.GenreSortCount})
+	if got := counts(used); got != "action=2 horror=1" {
+		t.Errorf("expected empty genres hidden, most used first, got %q", got)
+	}
+
+	page, _ := svc.Genres(ctx, models.GenreListRequest{Limit: 1, Offset: 1})
+	if got := counts(page); got != "horror=1" || page.Total != 3 || !page.HasMore {
+		t.Errorf("expected second page of one, got %q (total %d, more %v)", got, page.Total, page.HasMore)
+	}
+
+	found, _ := svc.Genres(ctx, models.GenreListRequest{Query: "HOR"})
+	if got := counts(found); got != "horror=1" {
+		t.Errorf("expected name search to ignore case, got %q", got)
+	}
+
+	if _, err := svc.Genres(ctx, models.GenreListRequest{Sort: "popularity"}); err == nil {
+		t.Error("expected unknown sort to be rejected")
+	}
+}
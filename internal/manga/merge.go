@@ -0,0 +1,79 @@
+// Package manga - Manga Merge Endpoint
+// Admin endpoint gộp manga trùng vào manga giữ lại
+// Chức năng:
+//   - POST /manga/:id/merge (admin only), body {"target_id": "..."}
+//   - :id là manga bị gộp (source), bị xóa sau khi reparent
+//   - Map lỗi importer sang HTTP status
+package manga
+
+import (
+	"context"
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"mangahub/internal/auth"
+	"mangahub/pkg/importer"
+	"mangahub/pkg/models"
+)
+
+// Merger folds a duplicate manga into another one
+type Merger interface {
+	Merge(ctx context.Context, actorID, sourceID, targetID string) (*importer.MergeResult, error)
+}
+
+// mergeRequest is the body of POST /manga/:id/merge
+type mergeRequest struct {
+	TargetID string `json:"target_id" binding:"required"`
+}
+
+// SetMerger enables POST /manga/:id/merge
+func (h *Handler) SetMerger(merger Merger) {
+	h.merger = merger
+}
+
+// MergeManga merges the manga in the path into target_id and deletes it (admin only)
+func (h *Handler) MergeManga(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+	if user.Role != models.UserRoleAdmin {
+		c.JSON(http.StatusForbidden,
+			models.NewErrorResponse(models.ErrCodeForbidden, "admin role required", nil))
+		return
+	}
+	if h.merger == nil {
+		c.JSON(http.StatusServiceUnavailable,
+			models.NewErrorResponse(models.ErrCodeServiceUnavailable, "merge is not configured", nil))
+		return
+	}
+
+	var req mergeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeValidation, "target_id is required", nil))
+		return
+	}
+
+	result, err := h.merger.Merge(c.Request.Context(), user.ID, c.Param("id"), req.TargetID)
+	switch {
+	case errors.Is(err, importer.ErrMergeSameManga):
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeValidation, err.Error(), nil))
+		return
+	case errors.Is(err, importer.ErrMangaNotFound):
+		c.JSON(http.StatusNotFound,
+			models.NewErrorResponse(models.ErrCodeNotFound, err.Error(), nil))
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError,
+			models.NewErrorResponse(models.ErrCodeInternal, "unexpected error", nil))
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(result, "manga merged"))
+}
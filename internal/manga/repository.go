@@ -4,14 +4,31 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"math/rand/v2"
 	"strings"
+	"time"
 
+	"github.com/google/uuid"
+
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/models"
 )
 
 type Repository interface {
 	List(ctx context.Context, req models.MangaSearchRequest) ([]models.Manga, int, error)
+	SearchMangaFTS(ctx context.Context, query string, limit, offset int) ([]models.Manga, int, error)
+	Suggest(ctx context.Context, prefix string, limit int) ([]models.MangaSuggestion, error)
 	GetByID(ctx context.Context, id string) (*models.Manga, error)
+	GetByIDs(ctx context.Context, ids []string) ([]models.Manga, error)
+	Similar(ctx context.Context, mangaID, excludeUserID string, limit int) ([]models.SimilarManga, error)
+	// Random returns one manga matching the filters, nil when none does
+	Random(ctx context.Context, req models.RandomMangaRequest) (*models.Manga, error)
+	// Genres lists genres with their manga counts and the total matching req
+	Genres(ctx context.Context, req models.GenreListRequest) ([]models.GenreWithCount, int, error)
+	ListChapters(ctx context.Context, mangaID string) ([]models.Chapter, error)
+	Create(ctx context.Context, m *models.Manga, genres []string) error
+	Update(ctx context.Context, m *models.Manga, genres []string) error
+	Delete(ctx context.Context, id string) error
 }
 
 type repository struct {
@@ -59,17 +76,12 @@ func (r *repository) List(ctx context.Context, req models.MangaSearchRequest) ([
 		return nil, 0, fmt.Errorf("count manga: %w", err)
 	}
 
-	orderBy := "title ASC"
-	switch req.SortBy {
-	case "rating":
-		orderBy = "average_rating DESC"
-	case "year":
-		orderBy = "year DESC"
-	}
+	orderBy := listOrderBy(req.SortBy, req.Order)
 
 	listSQL := fmt.Sprintf(`
 		SELECT id, title, author, artist, description, cover_url, status, type,
-		       total_chapters, average_rating, rating_count, year, created_at, updated_at
+		       total_chapters, average_rating, rating_count, year, created_at, updated_at,
+		       completed_at
 		FROM manga
 		WHERE %s
 		ORDER BY %s
@@ -89,23 +101,188 @@ func (r *repository) List(ctx context.Context, req models.MangaSearchRequest) ([
 		if err := rows.Scan(
 			&m.ID, &m.Title, &m.Author, &m.Artist, &m.Description, &m.CoverURL,
 			&m.Status, &m.Type, &m.TotalChapters, &m.AverageRating, &m.RatingCount,
-			&m.Year, &m.CreatedAt, &m.UpdatedAt,
+			&m.Year, &m.CreatedAt, &m.UpdatedAt, &m.CompletedAt,
 		); err != nil {
 			return nil, 0, fmt.Errorf("scan manga: %w", err)
 		}
-		// Load genres for each manga
-		m.Genres = r.loadGenresForManga(ctx, m.ID)
 		result = append(result, m)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate manga: %w", err)
+	}
+	rows.Close()
+
+	// Load genres for each manga after the cursor is closed (single-connection pools)
+	for i := range result {
+		result[i].Genres = r.loadGenresForManga(ctx, result[i].ID)
+	}
 
 	return result, total, nil
 }
 
+// mangaSortColumns maps the GET /manga sort fields to indexed manga columns
+var mangaSortColumns = map[string]string{
+	models.MangaSortRating:   "average_rating",
+	models.MangaSortYear:     "year",
+	models.MangaSortChapters: "total_chapters",
+	models.MangaSortTitle:    "title",
+}
+
+// listOrderBy builds the ORDER BY for List. Ties are broken by id so pages
+// never overlap or skip rows; unknown fields sort by rating, highest first.
+func listOrderBy(sortBy, order string) string {
+	column, ok := mangaSortColumns[sortBy]
+	if !ok {
+		column, order = mangaSortColumns[models.MangaSortRating], models.SortDesc
+	}
+	direction := "DESC"
+	if order == models.SortAsc {
+		direction = "ASC"
+	}
+	return column + " " + direction + ", id ASC"
+}
+
+// SearchMangaFTS runs a BM25-ranked full-text search over manga_fts.
+// Queries FTS5 cannot parse (lone "*", unbalanced quotes, ...) fall back to
+// the substring search used by List.
+func (r *repository) SearchMangaFTS(ctx context.Context, query string, limit, offset int) ([]models.Manga, int, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	if limit > 100 {
+		limit = 100
+	}
+
+	fallback := func() ([]models.Manga, int, error) {
+		return r.List(ctx, models.MangaSearchRequest{Query: query, Limit: limit, Offset: offset})
+	}
+
+	var total int
+	err := r.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM manga_fts WHERE manga_fts MATCH ?", query,
+	).Scan(&total)
+	if err != nil {
+		if isFTSQueryError(err) {
+			return fallback()
+		}
+		return nil, 0, fmt.Errorf("count fts manga: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.id, m.title, m.author, m.artist, m.description, m.cover_url, m.status, m.type,
+		       m.total_chapters, m.average_rating, m.rating_count, m.year, m.created_at, m.updated_at,
+		       m.completed_at
+		FROM manga_fts
+		JOIN manga m ON m.rowid = manga_fts.rowid
+		WHERE manga_fts MATCH ?
+		ORDER BY rank
+		LIMIT ? OFFSET ?`, query, limit, offset)
+	if err != nil {
+		if isFTSQueryError(err) {
+			return fallback()
+		}
+		return nil, 0, fmt.Errorf("search fts manga: %w", err)
+	}
+	defer rows.Close()
+
+	var result []models.Manga
+	for rows.Next() {
+		var m models.Manga
+		if err := rows.Scan(
+			&m.ID, &m.Title, &m.Author, &m.Artist, &m.Description, &m.CoverURL,
+			&m.Status, &m.Type, &m.TotalChapters, &m.AverageRating, &m.RatingCount,
+			&m.Year, &m.CreatedAt, &m.UpdatedAt, &m.CompletedAt,
+		); err != nil {
+			return nil, 0, fmt.Errorf("scan manga: %w", err)
+		}
+		result = append(result, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("iterate fts manga: %w", err)
+	}
+	rows.Close()
+
+	// Load genres after the cursor is closed (single-connection pools)
+	for i := range result {
+		result[i].Genres = r.loadGenresForManga(ctx, result[i].ID)
+	}
+
+	return result, total, nil
+}
+
+// Suggest returns titles whose words start with the words of prefix, as
+// typed so far: "one pi" matches "One Piece". It is an FTS5 prefix query on
+// the title column only and reads just id and title, so it stays far cheaper
+// than SearchMangaFTS. Titles starting with the prefix come first, then the
+// best rated.
+func (r *repository) Suggest(ctx context.Context, prefix string, limit int) ([]models.MangaSuggestion, error) {
+	match := suggestMatch(prefix)
+	if match == "" {
+		return []models.MangaSuggestion{}, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT m.id, m.title
+		FROM manga_fts
+		JOIN manga m ON m.rowid = manga_fts.rowid
+		WHERE manga_fts MATCH ?
+		ORDER BY m.title LIKE ? ESCAPE '\' DESC, m.average_rating DESC, m.title ASC
+		LIMIT ?`, match, escapeLike(strings.TrimSpace(prefix))+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("suggest manga: %w", err)
+	}
+	defer rows.Close()
+
+	suggestions := []models.MangaSuggestion{}
+	for rows.Next() {
+		var s models.MangaSuggestion
+		if err := rows.Scan(&s.ID, &s.Title); err != nil {
+			return nil, fmt.Errorf("scan manga suggestion: %w", err)
+		}
+		suggestions = append(suggestions, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("suggest manga: %w", err)
+	}
+	return suggestions, nil
+}
+
+// suggestMatch turns typed text into an FTS5 title query: every word quoted
+// (so operators and punctuation are literal), the last one as a prefix.
+// "one pi" becomes title : ("one" "pi"*). Empty if there are no words.
+func suggestMatch(prefix string) string {
+	words := strings.Fields(prefix)
+	if len(words) == 0 {
+		return ""
+	}
+	for i, w := range words {
+		words[i] = `"` + strings.ReplaceAll(w, `"`, `""`) + `"`
+	}
+	words[len(words)-1] += "*"
+	return "title : (" + strings.Join(words, " ") + ")"
+}
+
+// escapeLike escapes LIKE wildcards so s matches literally (with ESCAPE '\')
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
+
+// isFTSQueryError reports whether err is FTS5 rejecting the MATCH expression.
+// The MATCH statements are static, so a SQLite logic error can only come from
+// the user-supplied query (syntax error, unterminated string, "*", ...).
+func isFTSQueryError(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "fts5") ||
+		strings.Contains(msg, "sql logic error") ||
+		strings.Contains(msg, "syntax error") ||
+		strings.Contains(msg, "unterminated string")
+}
+
 func (r *repository) GetByID(ctx context.Context, id string) (*models.Manga, error) {
 	row := r.db.QueryRowContext(ctx, `
 		SELECT id, title, author, artist, description, cover_url, status, type,
-		       genres, total_chapters, rating, year, created_at, updated_at
-		FROM matotal_chapters, average_rating, rating_count, year, created_at, updated_at
+		       total_chapters, average_rating, rating_count, year, created_at, updated_at,
+		       completed_at
 		FROM manga
 		WHERE id = ?`, id)
 
@@ -113,10 +290,10 @@ func (r *repository) GetByID(ctx context.Context, id string) (*models.Manga, err
 	if err := row.Scan(
 		&m.ID, &m.Title, &m.Author, &m.Artist, &m.Description, &m.CoverURL,
 		&m.Status, &m.Type, &m.TotalChapters, &m.AverageRating, &m.RatingCount,
-		&m.Year, &m.CreatedAt, &m.UpdatedAt,
+		&m.Year, &m.CreatedAt, &m.UpdatedAt, &m.CompletedAt,
 	); err != nil {
 		if err == sql.ErrNoRows {
-			return nil, models.NewAppError(models.ErrCodeNotFound, "manga not found", 404, models.ErrMangaNotFound)
+			return nil, apperrors.NotFound("manga not found", models.ErrMangaNotFound)
 		}
 		return nil, fmt.Errorf("get manga: %w", err)
 	}
@@ -125,6 +302,280 @@ func (r *repository) GetByID(ctx context.Context, id string) (*models.Manga, err
 	return &m, nil
 }
 
+// ListChapters returns the stored chapters of a manga, lowest number first
+func (r *repository) ListChapters(ctx context.Context, mangaID string) ([]models.Chapter, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT number, COALESCE(title, ''), released_at, COALESCE(external_id, '')
+		FROM chapters
+		WHERE manga_id = ?
+		ORDER BY number`, mangaID)
+	if err != nil {
+		return nil, fmt.Errorf("list chapters: %w", err)
+	}
+	defer rows.Close()
+
+	var chapters []models.Chapter
+	for rows.Next() {
+		var ch models.Chapter
+		var releasedAt sql.NullTime
+		if err := rows.Scan(&ch.Number, &ch.Title, &releasedAt, &ch.ExternalID); err != nil {
+			return nil, fmt.Errorf("scan chapter: %w", err)
+		}
+		if releasedAt.Valid {
+			ch.ReleasedAt = &releasedAt.Time
+		}
+		chapters = append(chapters, ch)
+	}
+	return chapters, rows.Err()
+}
+
+// GetByIDs loads several manga and their genres in two queries (order not guaranteed)
+func (r *repository) GetByIDs(ctx context.Context, ids []string) ([]models.Manga, error) {
+	if len(ids) == 0 {
+		return []models.Manga{}, nil
+	}
+	placeholders := strings.Repeat("?,", len(ids)-1) + "?"
+	args := make([]interface{}, len(ids))
+	for i, id := range ids {
+		args[i] = id
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, title, author, artist, description, cover_url, status, type,
+		       total_chapters, average_rating, rating_count, year, created_at, updated_at,
+		       completed_at
+		FROM manga
+		WHERE id IN (`+placeholders+`)`, args...)
+	if err != nil {
+		return nil, fmt.Errorf("query manga batch: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.Manga{}
+	for rows.Next() {
+		var m models.Manga
+		if err := rows.Scan(
+			&m.ID, &m.Title, &m.Author, &m.Artist, &m.Description, &m.CoverURL,
+			&m.Status, &m.Type, &m.TotalChapters, &m.AverageRating, &m.RatingCount,
+			&m.Year, &m.CreatedAt, &m.UpdatedAt, &m.CompletedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan manga: %w", err)
+		}
+		result = append(result, m)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate manga batch: %w", err)
+	}
+	rows.Close()
+
+	genres, err := r.loadGenresForMangaIDs(ctx, placeholders, args)
+	if err != nil {
+		return nil, err
+	}
+	for i := range result {
+		result[i].Genres = genres[result[i].ID]
+	}
+	return result, nil
+}
+
+// Genres lists genres with the number of manga tagged with each. The counts
+// come from one grouped LEFT JOIN over idx_manga_genres_genre, so genres no
+// manga uses still show up with 0 unless req.HideEmpty drops them.
+func (r *repository) Genres(ctx context.Context, req models.GenreListRequest) ([]models.GenreWithCount, int, error) {
+	counted := `
+		SELECT g.id, g.name, g.slug, g.created_at, COUNT(mg.manga_id) AS manga_count
+		FROM genres g
+		LEFT JOIN manga_genres mg ON mg.genre_id = g.id
+		WHERE g.name LIKE ? ESCAPE '\'
+		GROUP BY g.id`
+	if req.HideEmpty {
+		counted += " HAVING COUNT(mg.manga_id) > 0"
+	}
+	args := []interface{}{"%" + escapeLike(strings.TrimSpace(req.Query)) + "%"}
+
+	var total int
+	if err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM ("+counted+")", args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("count genres: %w", err)
+	}
+
+	order := "g.name COLLATE NOCASE"
+	if req.Sort == models.GenreSortCount {
+		order = "manga_count DESC, " + order
+	}
+	rows, err := r.db.QueryContext(ctx, counted+" ORDER BY "+order+" LIMIT ? OFFSET ?",
+		append(args, req.Limit, req.Offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("list genres: %w", err)
+	}
+	defer rows.Close()
+
+	genres := []models.GenreWithCount{}
+	for rows.Next() {
+		var g models.GenreWithCount
+		if err := rows.Scan(&g.ID, &g.Name, &g.Slug, &g.CreatedAt, &g.MangaCount); err != nil {
+			return nil, 0, fmt.Errorf("scan genre: %w", err)
+		}
+		genres = append(genres, g)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("list genres: %w", err)
+	}
+	return genres, total, nil
+}
+
+// loadGenresForMangaIDs loads genres for many manga at once, keyed by manga id
+func (r *repository) loadGenresForMangaIDs(ctx context.Context, placeholders string, ids []interface{}) (map[string][]models.Genre, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT mg.manga_id, g.id, g.name, g.slug, g.created_at
+		FROM manga_genres mg
+		INNER JOIN genres g ON g.id = mg.genre_id
+		WHERE mg.manga_id IN (`+placeholders+`)
+		ORDER BY g.name`, ids...)
+	if err != nil {
+		return nil, fmt.Errorf("query batch genres: %w", err)
+	}
+	defer rows.Close()
+
+	genres := make(map[string][]models.Genre)
+	for rows.Next() {
+		var mangaID string
+		var g models.Genre
+		if err := rows.Scan(&mangaID, &g.ID, &g.Name, &g.Slug, &g.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan batch genre: %w", err)
+		}
+		genres[mangaID] = append(genres[mangaID], g)
+	}
+	return genres, rows.Err()
+}
+
+// Similar scores every other manga against mangaID in one query:
+// Jaccard overlap of genres (70%) plus closeness of average rating (30%).
+// A source without genres scores 0 overlap everywhere, so results fall back
+// to rating closeness. Manga in excludeUserID's library are skipped.
+func (r *repository) Similar(ctx context.Context, mangaID, excludeUserID string, limit int) ([]models.SimilarManga, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		WITH src_genres AS (
+			SELECT genre_id FROM manga_genres WHERE manga_id = ?
+		),
+		src AS (
+			SELECT (SELECT COUNT(*) FROM src_genres) AS genre_count,
+			       COALESCE(average_rating, 0) AS rating
+			FROM manga WHERE id = ?
+		),
+		candidates AS (
+			SELECT m.*,
+			       (SELECT COUNT(*) FROM manga_genres mg
+			        WHERE mg.manga_id = m.id AND mg.genre_id IN (SELECT genre_id FROM src_genres)) AS shared,
+			       (SELECT COUNT(*) FROM manga_genres mg WHERE mg.manga_id = m.id) AS genre_count
+			FROM manga m
+			WHERE m.id != ?
+			  AND m.id NOT IN (SELECT manga_id FROM reading_progress WHERE user_id = ?)
+		)
+		SELECT c.id, c.title, c.author, c.artist, c.description, c.cover_url, c.status, c.type,
+		       c.total_chapters, c.average_rating, c.rating_count, c.year, c.created_at, c.updated_at,
+		       c.shared,
+		       0.7 * CASE WHEN src.genre_count + c.genre_count - c.shared > 0
+		                  THEN CAST(c.shared AS REAL) / (src.genre_count + c.genre_count - c.shared)
+		                  ELSE 0 END
+		       + 0.3 * (1.0 - ABS(COALESCE(c.average_rating, 0) - src.rating) / 10.0) AS score
+		FROM candidates c, src
+		ORDER BY score DESC, c.average_rating DESC, c.title ASC
+		LIMIT ?`,
+		mangaID, mangaID, mangaID, excludeUserID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query similar manga: %w", err)
+	}
+	defer rows.Close()
+
+	result := []models.SimilarManga{}
+	for rows.Next() {
+		var s models.SimilarManga
+		if err := rows.Scan(
+			&s.ID, &s.Title, &s.Author, &s.Artist, &s.Description, &s.CoverURL,
+			&s.Status, &s.Type, &s.TotalChapters, &s.AverageRating, &s.RatingCount,
+			&s.Year, &s.CreatedAt, &s.UpdatedAt, &s.SharedGenres, &s.Score,
+		); err != nil {
+			return nil, fmt.Errorf("scan similar manga: %w", err)
+		}
+		result = append(result, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate similar manga: %w", err)
+	}
+	rows.Close()
+
+	// Load genres after the cursor is closed (single-connection pools)
+	for i := range result {
+		result[i].Genres = r.loadGenresForManga(ctx, result[i].ID)
+	}
+	return result, nil
+}
+
+// Random picks a manga without sorting the catalogue (ORDER BY RANDOM() would
+// sort every matching row on each call). It draws a rowid between the
+// smallest and largest and takes the first match at or after it, wrapping
+// around to the start; both lookups walk the rowid b-tree.
+// Tradeoff: the draw is not uniform. A manga that follows a gap in the rowids
+// (deleted or merged rows, or rows the filters skip) is picked more often,
+// and a very selective filter can still walk many rows before a match. That
+// is fine for discovery, which only needs variety.
+func (r *repository) Random(ctx context.Context, req models.RandomMangaRequest) (*models.Manga, error) {
+	var lo, hi sql.NullInt64
+	if err := r.db.QueryRowContext(ctx, `SELECT MIN(rowid), MAX(rowid) FROM manga`).Scan(&lo, &hi); err != nil {
+		return nil, fmt.Errorf("rowid range: %w", err)
+	}
+	if !lo.Valid {
+		return nil, nil
+	}
+	pick := lo.Int64 + rand.Int64N(hi.Int64-lo.Int64+1)
+
+	conditions := []string{"1=1"}
+	var args []interface{}
+	if req.Genre != "" {
+		conditions = append(conditions, "id IN (SELECT manga_id FROM manga_genres mg JOIN genres g ON mg.genre_id = g.id WHERE g.slug = ?)")
+		args = append(args, genreSlug(req.Genre))
+	}
+	if req.Type != "" {
+		conditions = append(conditions, "type = ?")
+		args = append(args, req.Type)
+	}
+	where := strings.Join(conditions, " AND ")
+
+	// find returns the first match from pick onwards, wrapping to the start
+	find := func(exclude string) (string, error) {
+		filter, filterArgs := where, args
+		if exclude != "" {
+			filter += " AND id != ?"
+			filterArgs = append(append([]interface{}{}, args...), exclude)
+		}
+		for _, from := range []string{"rowid >= ?", "rowid < ?"} {
+			var id string
+			err := r.db.QueryRowContext(ctx,
+				fmt.Sprintf(`SELECT id FROM manga WHERE %s AND %s ORDER BY rowid LIMIT 1`, from, filter),
+				append([]interface{}{pick}, filterArgs...)...,
+			).Scan(&id)
+			if err == nil {
+				return id, nil
+			}
+			if err != sql.ErrNoRows {
+				return "", fmt.Errorf("random manga: %w", err)
+			}
+		}
+		return "", nil
+	}
+
+	id, err := find(req.Exclude)
+	if err == nil && id == "" && req.Exclude != "" {
+		// The excluded manga is the only match; showing it again beats nothing
+		id, err = find("")
+	}
+	if err != nil || id == "" {
+		return nil, err
+	}
+	return r.GetByID(ctx, id)
+}
+
 // loadGenresForManga loads all genres for a manga from the manga_genres junction table
 func (r *repository) loadGenresForManga(ctx context.Context, mangaID string) []models.Genre {
 	rows, err := r.db.QueryContext(ctx, `
@@ -148,3 +599,135 @@ func (r *repository) loadGenresForManga(ctx context.Context, mangaID string) []m
 	}
 	return genres
 }
+
+// Create inserts a manga and links it to the named genres, creating genres
+// that do not exist yet. An empty m.ID gets a new UUID.
+func (r *repository) Create(ctx context.Context, m *models.Manga, genres []string) error {
+	if m.ID == "" {
+		m.ID = uuid.New().String()
+	}
+	now := time.Now()
+	m.CreatedAt, m.UpdatedAt = now, now
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin create manga: %w", err)
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO manga (id, title, author, artist, description, cover_url, status, type,
+		                   total_chapters, year, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		m.ID, m.Title, m.Author, m.Artist, m.Description, m.CoverURL, m.Status, m.Type,
+		m.TotalChapters, m.Year, now, now,
+	); err != nil {
+		if strings.Contains(err.Error(), "UNIQUE constraint failed") {
+			return apperrors.Conflict("manga already exists", err)
+		}
+		return fmt.Errorf("create manga: %w", err)
+	}
+	if err := setMangaGenres(ctx, tx, m.ID, genres); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit create manga: %w", err)
+	}
+	m.Genres = r.loadGenresForManga(ctx, m.ID)
+	return nil
+}
+
+// Update overwrites the stored metadata of m. Non-nil genres replace the
+// manga's genre links; nil leaves them untouched. Ratings are not written,
+// they stay owned by the rating triggers.
+func (r *repository) Update(ctx context.Context, m *models.Manga, genres []string) error {
+	m.UpdatedAt = time.Now()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin update manga: %w", err)
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE manga
+		SET title = ?, author = ?, artist = ?, description = ?, cover_url = ?, status = ?,
+		    type = ?, total_chapters = ?, year = ?, updated_at = ?
+		WHERE id = ?`,
+		m.Title, m.Author, m.Artist, m.Description, m.CoverURL, m.Status,
+		m.Type, m.TotalChapters, m.Year, m.UpdatedAt, m.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update manga: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperrors.NotFound("manga not found", models.ErrMangaNotFound)
+	}
+	if genres != nil {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM manga_genres WHERE manga_id = ?`, m.ID); err != nil {
+			return fmt.Errorf("clear manga genres: %w", err)
+		}
+		if err := setMangaGenres(ctx, tx, m.ID, genres); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit update manga: %w", err)
+	}
+	m.Genres = r.loadGenresForManga(ctx, m.ID)
+	return nil
+}
+
+// Delete removes a manga. Rows referencing it (genre links, chapters,
+// progress, ratings, comments, list items, activity, ...) go with it through
+// the ON DELETE CASCADE foreign keys; chat rooms stay with manga_id set to NULL.
+func (r *repository) Delete(ctx context.Context, id string) error {
+	res, err := r.db.ExecContext(ctx, `DELETE FROM manga WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("delete manga: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return apperrors.NotFound("manga not found", models.ErrMangaNotFound)
+	}
+	return nil
+}
+
+// setMangaGenres links mangaID to each named genre, matched by name or slug
+// case-insensitively. Unknown genres are created; blanks and duplicates are skipped.
+func setMangaGenres(ctx context.Context, tx *sql.Tx, mangaID string, genres []string) error {
+	for _, name := range genres {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+		slug := genreSlug(name)
+
+		var genreID string
+		err := tx.QueryRowContext(ctx, `
+			SELECT id FROM genres WHERE name = ? COLLATE NOCASE OR slug = ?`,
+			name, slug).Scan(&genreID)
+		if err == sql.ErrNoRows {
+			genreID = uuid.New().String()
+			_, err = tx.ExecContext(ctx, `
+				INSERT INTO genres (id, name, slug, created_at) VALUES (?, ?, ?, ?)`,
+				genreID, name, slug, time.Now())
+		}
+		if err != nil {
+			return fmt.Errorf("resolve genre %q: %w", name, err)
+		}
+
+		if _, err := tx.ExecContext(ctx, `
+			INSERT OR IGNORE INTO manga_genres (id, manga_id, genre_id, created_at)
+			VALUES (?, ?, ?, ?)`,
+			uuid.New().String(), mangaID, genreID, time.Now(),
+		); err != nil {
+			return fmt.Errorf("link genre %q: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// genreSlug derives a slug in the seed data's style: "Slice of Life" -> "slice-of-life"
+func genreSlug(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), "-")
+}
@@ -0,0 +1,93 @@
+// Package manga - Manga Resync Endpoint
+// Admin endpoint refetch manga từ external sources
+// Chức năng:
+//   - POST /manga/:id/resync (admin only)
+//   - Map lỗi importer sang HTTP status
+//   - UDP chapter_release broadcast khi total_chapters tăng
+//   - UDP status_change broadcast khi status đổi (vd. ongoing → completed)
+package manga
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"mangahub/internal/auth"
+	"mangahub/internal/udp"
+	"mangahub/pkg/importer"
+	"mangahub/pkg/logger"
+	"mangahub/pkg/models"
+)
+
+// Resyncer refreshes a manga from its external sources
+type Resyncer interface {
+	Resync(ctx context.Context, mangaID string) (*importer.ResyncResult, error)
+}
+
+// SetResync enables POST /manga/:id/resync
+// notify is called with a chapter_release notification when new chapters are
+// found and a status_change notification when the status changes; may be nil
+func (h *Handler) SetResync(resyncer Resyncer, notify func(udp.Notification) error) {
+	h.resyncer = resyncer
+	h.notify = notify
+}
+
+// ResyncManga refetches a manga from its external sources (admin only)
+func (h *Handler) ResyncManga(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+	if user.Role != models.UserRoleAdmin {
+		c.JSON(http.StatusForbidden,
+			models.NewErrorResponse(models.ErrCodeForbidden, "admin role required", nil))
+		return
+	}
+	if h.resyncer == nil {
+		c.JSON(http.StatusServiceUnavailable,
+			models.NewErrorResponse(models.ErrCodeServiceUnavailable, "resync is not configured", nil))
+		return
+	}
+
+	mangaID := c.Param("id")
+	result, err := h.resyncer.Resync(c.Request.Context(), mangaID)
+	switch {
+	case errors.Is(err, importer.ErrMangaNotFound):
+		c.JSON(http.StatusNotFound,
+			models.NewErrorResponse(models.ErrCodeNotFound, "manga not found", nil))
+		return
+	case errors.Is(err, importer.ErrNoExternalIDs):
+		c.JSON(http.StatusConflict,
+			models.NewErrorResponse(models.ErrCodeConflict, "manga has no external source to resync from", nil))
+		return
+	case errors.Is(err, importer.ErrAllSourcesFailed):
+		c.JSON(http.StatusBadGateway,
+			models.NewErrorResponse(models.ErrCodeServiceUnavailable, "external sources unavailable", map[string]interface{}{"error": err.Error()}))
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError,
+			models.NewErrorResponse(models.ErrCodeInternal, "unexpected error", nil))
+		return
+	}
+
+	if result.NewChapters() && h.notify != nil {
+		notification := udp.NewChapterNotification(mangaID,
+			fmt.Sprintf("Chapter %d is out!", result.TotalChapters))
+		if err := h.notify(notification); err != nil {
+			logger.Warnf("resync: chapter_release broadcast for %s failed: %v", mangaID, err)
+		}
+	}
+	if result.StatusChanged() && h.notify != nil {
+		notification := udp.NewStatusChangeNotification(mangaID, result.Title, result.PreviousStatus, result.Status)
+		if err := h.notify(notification); err != nil {
+			logger.Warnf("resync: status_change broadcast for %s failed: %v", mangaID, err)
+		}
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(result, "manga resynced"))
+}
@@ -2,20 +2,45 @@
 // Xử lý tất cả logic liên quan đến manga data
 // Chức năng:
 //   - Search manga với filters (query, status, genre)
+//   - Sort theo rating (mặc định), year, chapters, title; asc/desc
+//   - Full-text search (FTS5, BM25 ranking)
+//   - Gợi ý title theo prefix cho autocomplete
 //   - Get manga details theo ID
+//   - Danh sách chapter (metadata từ import, số chapter giả lập khi chưa có)
+//   - Gợi ý manga tương tự (genre overlap + rating)
+//   - Random manga cho discovery (lọc genre/type, bỏ qua manga vừa hiện)
+//   - Danh sách genre kèm số manga (tìm theo tên, ẩn genre rỗng, phân trang)
 //   - Pagination support
 //   - Tích hợp với database layer
 package manga
 
 import (
 	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
 
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/models"
+	"mangahub/pkg/utils"
 )
 
 type Service interface {
 	List(ctx context.Context, req models.MangaSearchRequest) (*models.MangaListResponse, error)
 	GetByID(ctx context.Context, id string) (*models.Manga, error)
+	// Suggest returns up to limit titles matching a typed prefix
+	Suggest(ctx context.Context, prefix string, limit int) ([]models.MangaSuggestion, error)
+	// GetBatch returns the requested manga in request order, listing unknown ids as missing
+	GetBatch(ctx context.Context, req models.MangaBatchRequest) (*models.MangaBatchResponse, error)
+	// Similar returns up to limit manga like id; userID (optional) excludes their library
+	Similar(ctx context.Context, id, userID string, limit int) ([]models.SimilarManga, error)
+	// Random returns one manga matching the optional genre and type, avoiding req.Exclude
+	Random(ctx context.Context, req models.RandomMangaRequest) (*models.Manga, error)
+	// Genres pages through the genres with their manga counts
+	Genres(ctx context.Context, req models.GenreListRequest) (*models.GenreListResponse, error)
+	// Chapters lists a manga's chapters, numbered 1..total_chapters at least
+	Chapters(ctx context.Context, id string) (*models.ChapterList, error)
 }
 
 type service struct {
@@ -27,9 +52,22 @@ func NewService(repo Repository) Service {
 }
 
 func (s *service) List(ctx context.Context, req models.MangaSearchRequest) (*models.MangaListResponse, error) {
-	manga, total, err := s.repo.List(ctx, req)
+	if err := normalizeSort(&req); err != nil {
+		return nil, err
+	}
+
+	var (
+		manga []models.Manga
+		total int
+		err   error
+	)
+	if req.Mode == "fts" && req.Query != "" {
+		manga, total, err = s.repo.SearchMangaFTS(ctx, req.Query, req.Limit, req.Offset)
+	} else {
+		manga, total, err = s.repo.List(ctx, req)
+	}
 	if err != nil {
-		return nil, models.NewAppError(models.ErrCodeInternal, "failed to list manga", 500, err)
+		return nil, apperrors.Internal("failed to list manga", err)
 	}
 
 	hasMore := req.Offset+req.Limit < total
@@ -42,6 +80,184 @@ func (s *service) List(ctx context.Context, req models.MangaSearchRequest) (*mod
 	}, nil
 }
 
+// normalizeSort fills in the default sort (rating, highest first) and rejects
+// unknown fields and orders. Ranked full-text search ignores the sort.
+func normalizeSort(req *models.MangaSearchRequest) error {
+	req.SortBy = strings.ToLower(strings.TrimSpace(req.SortBy))
+	req.Order = strings.ToLower(strings.TrimSpace(req.Order))
+	if req.SortBy == "" {
+		req.SortBy = models.MangaSortRating
+	}
+	if !slices.Contains(models.MangaSortFields, req.SortBy) {
+		return apperrors.Validation("sort", "sort must be one of "+strings.Join(models.MangaSortFields, ", "))
+	}
+	switch req.Order {
+	case "":
+		req.Order = models.DefaultMangaSortOrder(req.SortBy)
+	case models.SortAsc, models.SortDesc:
+	default:
+		return apperrors.Validation("order", "order must be asc or desc")
+	}
+	return nil
+}
+
 func (s *service) GetByID(ctx context.Context, id string) (*models.Manga, error) {
 	return s.repo.GetByID(ctx, id)
 }
+
+// Chapters lists a manga's chapters. Numbers up to total_chapters without
+// stored metadata (e.g. manga imported before chapters were) are filled with
+// synthetic entries, so the list always covers every known chapter.
+func (s *service) Chapters(ctx context.Context, id string) (*models.ChapterList, error) {
+	manga, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	stored, err := s.repo.ListChapters(ctx, id)
+	if err != nil {
+		return nil, apperrors.Internal("failed to list chapters", err)
+	}
+
+	return &models.ChapterList{
+		MangaID:       manga.ID,
+		TotalChapters: manga.TotalChapters,
+		Chapters:      fillChapters(stored, manga.TotalChapters),
+	}, nil
+}
+
+// fillChapters merges stored chapters (sorted by number) with synthetic ones
+// for the missing numbers in 1..total. Stored chapters past total are kept.
+func fillChapters(stored []models.Chapter, total int) []models.Chapter {
+	chapters := make([]models.Chapter, 0, max(total, len(stored)))
+	i := 0
+	for n := 1; n <= total; n++ {
+		for i < len(stored) && stored[i].Number < n {
+			i++
+		}
+		if i < len(stored) && stored[i].Number == n {
+			chapters = append(chapters, stored[i])
+			continue
+		}
+		chapters = append(chapters, models.Chapter{Number: n, Synthetic: true})
+	}
+	for ; i < len(stored); i++ {
+		if stored[i].Number > total {
+			chapters = append(chapters, stored[i])
+		}
+	}
+	return chapters
+}
+
+// Suggest returns up to limit titles matching a typed prefix.
+// Prefixes under models.MinSuggestPrefix characters are rejected rather than
+// matched against most of the table.
+func (s *service) Suggest(ctx context.Context, prefix string, limit int) ([]models.MangaSuggestion, error) {
+	prefix = strings.TrimSpace(prefix)
+	if utf8.RuneCountInString(prefix) < models.MinSuggestPrefix {
+		return nil, apperrors.Validation("q", fmt.Sprintf("q must be at least %d characters", models.MinSuggestPrefix))
+	}
+	if limit <= 0 {
+		limit = models.DefaultSuggestLimit
+	}
+	if limit > models.MaxSuggestLimit {
+		limit = models.MaxSuggestLimit
+	}
+
+	suggestions, err := s.repo.Suggest(ctx, prefix, limit)
+	if err != nil {
+		return nil, apperrors.Internal("failed to suggest manga", err)
+	}
+	return suggestions, nil
+}
+
+// Random picks one manga for discovery
+func (s *service) Random(ctx context.Context, req models.RandomMangaRequest) (*models.Manga, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, apperrors.Invalid("invalid random manga filters", err)
+	}
+	m, err := s.repo.Random(ctx, req)
+	if err != nil {
+		return nil, apperrors.Internal("failed to pick a manga", err)
+	}
+	if m == nil {
+		return nil, apperrors.NotFound("no manga matches the filters", models.ErrMangaNotFound)
+	}
+	return m, nil
+}
+
+// Genres returns a page of genres with their manga counts, alphabetical
+// unless req.Sort asks for the most used first
+func (s *service) Genres(ctx context.Context, req models.GenreListRequest) (*models.GenreListResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, apperrors.Invalid("invalid genre list parameters", err)
+	}
+	if req.Limit == 0 {
+		req.Limit = models.DefaultGenreLimit
+	}
+	genres, total, err := s.repo.Genres(ctx, req)
+	if err != nil {
+		return nil, apperrors.Internal("failed to list genres", err)
+	}
+	return &models.GenreListResponse{
+		Data:    genres,
+		Total:   total,
+		Limit:   req.Limit,
+		Offset:  req.Offset,
+		HasMore: req.Offset+len(genres) < total,
+	}, nil
+}
+
+// GetBatch returns the requested manga in request order.
+// Duplicate ids are returned once; ids that don't exist are listed in Missing.
+func (s *service) GetBatch(ctx context.Context, req models.MangaBatchRequest) (*models.MangaBatchResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, apperrors.Invalid(fmt.Sprintf("ids must hold 1 to %d non-empty ids", models.MaxMangaBatch), err)
+	}
+
+	ids := make([]string, 0, len(req.IDs))
+	seen := make(map[string]bool, len(req.IDs))
+	for _, id := range req.IDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	found, err := s.repo.GetByIDs(ctx, ids)
+	if err != nil {
+		return nil, apperrors.Internal("failed to load manga", err)
+	}
+	byID := make(map[string]models.Manga, len(found))
+	for _, m := range found {
+		byID[m.ID] = m
+	}
+
+	resp := &models.MangaBatchResponse{Data: []models.Manga{}, Missing: []string{}}
+	for _, id := range ids {
+		if m, ok := byID[id]; ok {
+			resp.Data = append(resp.Data, m)
+		} else {
+			resp.Missing = append(resp.Missing, id)
+		}
+	}
+	return resp, nil
+}
+
+// Similar returns up to limit manga like id, best match first
+func (s *service) Similar(ctx context.Context, id, userID string, limit int) ([]models.SimilarManga, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = 10
+	}
+	if limit > 50 {
+		limit = 50
+	}
+
+	similar, err := s.repo.Similar(ctx, id, userID, limit)
+	if err != nil {
+		return nil, apperrors.Internal("failed to load similar manga", err)
+	}
+	return similar, nil
+}
@@ -0,0 +1,188 @@
+// Package middleware - HTTP Rate Limiting Middleware
+// Giới hạn số request bằng Redis sliding window
+// Chức năng:
+//   - Per-IP limit cho public API endpoints
+//   - Per-username limit chặt hơn cho /auth/login (chống credential stuffing),
+//     reset khi login thành công nên chỉ lần sai bị tính
+//   - Budget đọc lại mỗi request (đổi được khi reload config)
+//   - Fail open khi Redis không khả dụng (log warning, không block traffic)
+//   - Trả về 429 kèm Retry-After header
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"mangahub/pkg/cache"
+	"mangahub/pkg/logger"
+	"mangahub/pkg/models"
+)
+
+// ErrCodeRateLimited is returned when a client exceeds its request budget
+const ErrCodeRateLimited = "RATE_LIMITED"
+
+// ErrCodePayloadTooLarge is returned when a login body exceeds maxLoginBody
+const ErrCodePayloadTooLarge = "PAYLOAD_TOO_LARGE"
+
+// maxLoginBody caps the login body read before the attempt is rate limited
+const maxLoginBody = 4 << 10
+
+// limiterTimeout bounds each Redis round-trip so a slow Redis can't stall requests
+const limiterTimeout = 200 * time.Millisecond
+
+// warnInterval throttles "Redis unavailable" warnings
+const warnInterval = time.Minute
+
+// Limiter records a hit for a key and reports whether it is allowed.
+// Implemented by cache.RedisCache.
+type Limiter interface {
+	AllowSlidingWindow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error)
+	// Delete clears every hit recorded for key
+	Delete(ctx context.Context, key string) error
+}
+
+var (
+	warnMu   sync.Mutex
+	lastWarn time.Time
+)
+
+// Budget is how many requests a client may make per window; zero Requests disables limiting
+type Budget struct {
+	Requests int
+	Window   time.Duration
+}
+
+// fixed returns a budget func that never changes
+func fixed(limit int, window time.Duration) func() Budget {
+	return func() Budget { return Budget{Requests: limit, Window: window} }
+}
+
+// RateLimit limits requests per client IP. A nil limiter disables limiting.
+func RateLimit(limiter Limiter, limit int, window time.Duration) gin.HandlerFunc {
+	return RateLimitFunc(limiter, fixed(limit, window))
+}
+
+// RateLimitFunc is RateLimit with the budget read on every request,
+// so it can change while the server runs (config reload)
+func RateLimitFunc(limiter Limiter, budget func() Budget) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		b := budget()
+		if limiter == nil || b.Requests <= 0 {
+			c.Next()
+			return
+		}
+
+		key := cache.BuildKey(cache.PrefixRateLimit, "ip:"+c.ClientIP())
+		if !allow(c, limiter, key, b.Requests, b.Window) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// LoginRateLimit limits login attempts per username.
+// The JSON body is read and restored so the handler can bind it again; bodies
+// over maxLoginBody get 413. A successful login clears the username's hits,
+// so only failed attempts count against it.
+func LoginRateLimit(limiter Limiter, limit int, window time.Duration) gin.HandlerFunc {
+	return LoginRateLimitFunc(limiter, fixed(limit, window))
+}
+
+// LoginRateLimitFunc is LoginRateLimit with the budget read on every attempt
+func LoginRateLimitFunc(limiter Limiter, budget func() Budget) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		b := budget()
+		if limiter == nil || b.Requests <= 0 || c.Request.Body == nil {
+			c.Next()
+			return
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(c.Writer, c.Request.Body, maxLoginBody))
+		c.Request.Body = io.NopCloser(bytes.NewReader(body))
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.AbortWithStatusJSON(http.StatusRequestEntityTooLarge,
+				models.NewErrorResponse(ErrCodePayloadTooLarge, "request body too large", nil))
+			return
+		}
+		if err != nil {
+			c.Next()
+			return
+		}
+
+		var req models.LoginRequest
+		if json.Unmarshal(body, &req) != nil || req.Username == "" {
+			// Malformed body: let the handler reject it
+			c.Next()
+			return
+		}
+
+		username := strings.ToLower(strings.TrimSpace(req.Username))
+		key := cache.BuildKey(cache.PrefixRateLimit, "login:"+username)
+		if !allow(c, limiter, key, b.Requests, b.Window) {
+			return
+		}
+		c.Next()
+
+		if c.Writer.Status() < http.StatusMultipleChoices {
+			reset(c, limiter, key)
+		}
+	}
+}
+
+// reset clears key after a successful login. Limiter errors are only logged.
+func reset(c *gin.Context, limiter Limiter, key string) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), limiterTimeout)
+	defer cancel()
+
+	if err := limiter.Delete(ctx, key); err != nil {
+		warnUnavailable(err)
+	}
+}
+
+// allow checks the limiter and aborts with 429 when the budget is exhausted.
+// Limiter errors fail open.
+func allow(c *gin.Context, limiter Limiter, key string, limit int, window time.Duration) bool {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), limiterTimeout)
+	defer cancel()
+
+	ok, retryAfter, err := limiter.AllowSlidingWindow(ctx, key, limit, window)
+	if err != nil {
+		warnUnavailable(err)
+		return true
+	}
+	if ok {
+		return true
+	}
+
+	seconds := int(math.Ceil(retryAfter.Seconds()))
+	if seconds < 1 {
+		seconds = 1
+	}
+	c.Header("Retry-After", strconv.Itoa(seconds))
+	c.AbortWithStatusJSON(http.StatusTooManyRequests,
+		models.NewErrorResponse(ErrCodeRateLimited, "too many requests, please slow down",
+			map[string]interface{}{"retry_after_seconds": seconds}))
+	return false
+}
+
+// warnUnavailable logs a limiter failure at most once per warnInterval
+func warnUnavailable(err error) {
+	warnMu.Lock()
+	defer warnMu.Unlock()
+	if time.Since(lastWarn) < warnInterval {
+		return
+	}
+	lastWarn = time.Now()
+	logger.Warnf("Rate limiter unavailable, allowing requests: %v", err)
+}
@@ -0,0 +1,188 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeLimiter counts hits per key in memory
+type fakeLimiter struct {
+	hits map[string]int
+	err  error
+}
+
+func (f *fakeLimiter) AllowSlidingWindow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	if f.err != nil {
+		return false, 0, f.err
+	}
+	if f.hits[key] >= limit {
+		return false, 1500 * time.Millisecond, nil
+	}
+	f.hits[key]++
+	return true, 0, nil
+}
+
+func (f *fakeLimiter) Delete(ctx context.Context, key string) error {
+	delete(f.hits, key)
+	return nil
+}
+
+func TestRateLimitReturns429WithRetryAfter(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := &fakeLimiter{hits: map[string]int{}}
+	router := gin.New()
+	router.Use(RateLimit(limiter, 2, time.Minute))
+	router.GET("/manga", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/manga", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "/manga", nil))
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+}
+
+func TestRateLimitFailsOpen(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := &fakeLimiter{err: errors.New("connection refused")}
+	router := gin.New()
+	router.Use(RateLimit(limiter, 1, time.Minute))
+	router.GET("/manga", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/manga", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+}
+
+func TestLoginRateLimitPerUsername(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := &fakeLimiter{hits: map[string]int{}}
+	router := gin.New()
+	router.POST("/auth/login", LoginRateLimit(limiter, 1, time.Minute), func(c *gin.Context) {
+		// Body must still be readable by the handler
+		body, _ := io.ReadAll(c.Request.Body)
+		status := http.StatusUnauthorized
+		if strings.Contains(string(body), `"password":"right"`) {
+			status = http.StatusOK
+		}
+		c.String(status, string(body))
+	})
+
+	login := func(username, password string) *httptest.ResponseRecorder {
+		body := `{"username":"` + username + `","password":"` + password + `"}`
+		req := httptest.NewRequest("POST", "/auth/login", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	first := login("alice", "wrong")
+	assert.Equal(t, http.StatusUnauthorized, first.Code)
+	assert.Contains(t, first.Body.String(), "alice")
+
+	assert.Equal(t, http.StatusTooManyRequests, login("Alice", "wrong").Code)
+	assert.Equal(t, http.StatusOK, login("bob", "right").Code)
+
+	// Successful logins don't use up the budget
+	assert.Equal(t, http.StatusOK, login("bob", "right").Code)
+	assert.Equal(t, http.StatusUnauthorized, login("bob", "wrong").Code)
+	assert.Equal(t, http.StatusTooManyRequests, login("bob", "wrong").Code)
+}
+
+func TestLoginRateLimitRejectsLargeBody(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := &fakeLimiter{hits: map[string]int{}}
+	router := gin.New()
+	router.POST("/auth/login", LoginRateLimit(limiter, 5, time.Minute), func(c *gin.Context) {
+		t.Error("handler ran for an oversized body")
+	})
+
+	body := `{"username":"alice","password":"` + strings.Repeat("x", maxLoginBody) + `"}`
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("POST", "/auth/login", strings.NewReader(body)))
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	assert.Empty(t, limiter.hits)
+}
+
+func TestRateLimitFuncPicksUpNewBudget(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	limiter := &fakeLimiter{hits: map[string]int{}}
+	budget := Budget{Requests: 1, Window: time.Minute}
+	router := gin.New()
+	router.Use(RateLimitFunc(limiter, func() Budget { return budget }))
+	router.GET("/manga", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	get := func() int {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/manga", nil))
+		return w.Code
+	}
+	assert.Equal(t, http.StatusOK, get())
+	assert.Equal(t, http.StatusTooManyRequests, get())
+
+	// Raised on reload: the next request gets the new budget
+	budget.Requests = 2
+	assert.Equal(t, http.StatusOK, get())
+
+	// Disabled on reload
+	budget.Requests = 0
+	assert.Equal(t, http.StatusOK, get())
+}
+
+func TestRateLimitIgnoresSpoofedForwardedFor(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	get := func(router *gin.Engine, remote, forwardedFor string) int {
+		req := httptest.NewRequest("GET", "/manga", nil)
+		req.RemoteAddr = remote
+		req.Header.Set("X-Forwarded-For", forwardedFor)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+	newRouter := func(trusted []string) *gin.Engine {
+		router := gin.New()
+		if err := router.SetTrustedProxies(trusted); err != nil {
+			t.Fatalf("SetTrustedProxies failed: %v", err)
+		}
+		router.Use(RateLimit(&fakeLimiter{hits: map[string]int{}}, 2, time.Minute))
+		router.GET("/manga", func(c *gin.Context) { c.Status(http.StatusOK) })
+		return router
+	}
+
+	// server.trusted_proxies defaults to none: a new X-Forwarded-For per
+	// request must not buy a fresh bucket
+	router := newRouter([]string{})
+	assert.Equal(t, http.StatusOK, get(router, "203.0.113.7:4000", "198.51.100.1"))
+	assert.Equal(t, http.StatusOK, get(router, "203.0.113.7:4000", "198.51.100.2"))
+	assert.Equal(t, http.StatusTooManyRequests, get(router, "203.0.113.7:4000", "198.51.100.3"))
+
+	// Behind a listed proxy each forwarded client gets its own bucket
+	router = newRouter([]string{"10.0.0.0/8"})
+	for i := 0; i < 2; i++ {
+		assert.Equal(t, http.StatusOK, get(router, "10.0.0.2:4000", "198.51.100.1"))
+	}
+	assert.Equal(t, http.StatusTooManyRequests, get(router, "10.0.0.2:4000", "198.51.100.1"))
+	assert.Equal(t, http.StatusOK, get(router, "10.0.0.2:4000", "198.51.100.2"))
+}
@@ -0,0 +1,32 @@
+// Package middleware - Request Timeout Middleware
+// Gắn deadline vào context của mỗi request
+// Chức năng:
+//   - Repository dùng QueryContext/ExecContext nên query chậm bị huỷ khi hết giờ
+//     hoặc khi client ngắt kết nối (SQLite driver interrupt query đang chạy)
+//   - Bỏ qua WebSocket: kết nối sống lâu hơn mọi request timeout
+package middleware
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequestTimeout cancels the request context after d. A handler whose query
+// is cancelled reports the error like any other failed query. d <= 0 disables
+// the timeout.
+func RequestTimeout(d time.Duration) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if d <= 0 || c.IsWebsocket() {
+			c.Next()
+			return
+		}
+
+		ctx, cancel := context.WithTimeout(c.Request.Context(), d)
+		defer cancel()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+	}
+}
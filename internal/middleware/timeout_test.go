@@ -0,0 +1,50 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRequestTimeoutCancelsHandlerContext(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var ctxErr error
+	router := gin.New()
+	router.Use(RequestTimeout(20 * time.Millisecond))
+	router.GET("/slow", func(c *gin.Context) {
+		select {
+		case <-c.Request.Context().Done():
+			ctxErr = c.Request.Context().Err()
+		case <-time.After(time.Second):
+		}
+		c.Status(http.StatusInternalServerError)
+	})
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/slow", nil))
+	assert.True(t, errors.Is(ctxErr, context.DeadlineExceeded), "handler context error = %v", ctxErr)
+}
+
+func TestRequestTimeoutSkipsWebSocket(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var hasDeadline bool
+	router := gin.New()
+	router.Use(RequestTimeout(time.Minute))
+	router.GET("/ws/chat", func(c *gin.Context) {
+		_, hasDeadline = c.Request.Context().Deadline()
+	})
+
+	req := httptest.NewRequest(http.MethodGet, "/ws/chat", nil)
+	req.Header.Set("Connection", "upgrade")
+	req.Header.Set("Upgrade", "websocket")
+	router.ServeHTTP(httptest.NewRecorder(), req)
+	assert.False(t, hasDeadline, "websocket requests must not get a deadline")
+}
@@ -0,0 +1,138 @@
+// Package notify - Email Digest Tests
+// Unit tests cho mute, lỗi từng user và sender mặc định
+package notify
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"mangahub/internal/testutil"
+)
+
+// fakeSender records messages and fails for one address
+type fakeSender struct {
+	sent   []Message
+	failTo string
+}
+
+func (f *fakeSender) Send(ctx context.Context, msg Message) error {
+	if msg.To == f.failTo {
+		return errors.New("mailbox unavailable")
+	}
+	f.sent = append(f.sent, msg)
+	return nil
+}
+
+func TestSendDigestsSkipsMutedAndSurvivesFailures(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+	now := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+
+	for _, q := range []string{
+		`INSERT INTO users (id, username, email, password_hash, display_name, email_verified) VALUES
+			('u1', 'alice', 'a@example.com', 'x', 'Alice', 1), ('u2', 'bob', 'b@example.com', 'x', 'Bob', 1),
+			('u3', 'carol', 'c@example.com', 'x', 'Carol', 1), ('u4', 'dave', 'd@example.com', 'x', 'Dave', 0)`,
+		`INSERT INTO user_preferences (user_id, email_notifications) VALUES ('u1', 1), ('u2', 1), ('u3', 0), ('u4', 1)`,
+		`INSERT INTO manga (id, title) VALUES ('m1', 'Berserk'), ('m2', 'Vagabond')`,
+		`INSERT INTO reading_progress (id, user_id, manga_id, status) VALUES
+			('p1', 'u1', 'm1', 'reading'), ('p2', 'u1', 'm2', 'reading'), ('p3', 'u2', 'm1', 'reading'),
+			('p4', 'u3', 'm1', 'reading'), ('p5', 'u4', 'm1', 'reading')`,
+		`INSERT INTO notification_mutes (user_id, manga_id) VALUES ('u1', 'm2')`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("seed failed: %v", err)
+		}
+	}
+	addChapter := func(manga string, number int, at time.Time) {
+		t.Helper()
+		if _, err := db.Exec(`INSERT INTO chapters (manga_id, number, created_at) VALUES (?, ?, ?)`, manga, number, at); err != nil {
+			t.Fatalf("insert chapter: %v", err)
+		}
+	}
+	addChapter("m1", 1, now.Add(-48*time.Hour)) // older than the first digest window
+	addChapter("m1", 2, now.Add(-2*time.Hour))
+	addChapter("m2", 1, now.Add(-2*time.Hour))
+
+	sender := &fakeSender{failTo: "b@example.com"}
+	svc := NewService(NewRepository(db), sender)
+	svc.now = func() time.Time { return now }
+
+	result, err := svc.SendDigests(ctx)
+	if err != nil {
+		t.Fatalf("SendDigests: %v", err)
+	}
+	// carol opted out and dave is unverified; bob's failure doesn't stop alice's
+	if result.Recipients != 2 || result.Sent != 1 || result.Failed != 1 {
+		t.Errorf("result = %+v, want 2 recipients, 1 sent, 1 failed", result)
+	}
+	if len(sender.sent) != 1 || sender.sent[0].To != "a@example.com" {
+		t.Fatalf("sent = %+v, want one email to alice", sender.sent)
+	}
+	body := sender.sent[0].Body
+	if !strings.Contains(body, "Chapter 2") || strings.Contains(body, "Chapter 1") || strings.Contains(body, "Vagabond") {
+		t.Errorf("digest body should only list Berserk chapter 2:\n%s", body)
+	}
+
+	// Nothing new since alice's digest: no second email; bob is retried
+	sender.failTo = ""
+	svc.now = func() time.Time { return now.Add(time.Hour) }
+	result, err = svc.SendDigests(ctx)
+	if err != nil {
+		t.Fatalf("SendDigests: %v", err)
+	}
+	if result.Sent != 1 || result.Empty != 1 || sender.sent[1].To != "b@example.com" {
+		t.Errorf("second run = %+v, sent to %s; want only bob's retried digest", result, sender.sent[len(sender.sent)-1].To)
+	}
+}
+
+func TestNewChaptersComparesAcrossZones(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+	since := time.Date(2026, 10, 17, 8, 0, 0, 0, time.UTC)
+
+	for _, q := range []string{
+		`INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'alice', 'a@example.com', 'x', 'Alice')`,
+		`INSERT INTO manga (id, title) VALUES ('m1', 'Berserk')`,
+		`INSERT INTO reading_progress (id, user_id, manga_id, status) VALUES ('p1', 'u1', 'm1', 'reading')`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("seed failed: %v", err)
+		}
+	}
+	// The importer stores local time: an hour before since on a UTC+7 host
+	// reads as later text, an hour after since on a UTC-5 host as earlier
+	east := time.FixedZone("ICT", 7*60*60)
+	west := time.FixedZone("EST", -5*60*60)
+	for number, at := range map[int]time.Time{
+		1: since.Add(-time.Hour).In(east),
+		2: since.Add(time.Hour).In(west),
+	} {
+		if _, err := db.Exec(`INSERT INTO chapters (manga_id, number, created_at) VALUES ('m1', ?, ?)`, number, at); err != nil {
+			t.Fatalf("insert chapter: %v", err)
+		}
+	}
+
+	chapters, err := NewRepository(db).NewChapters(ctx, "u1", since)
+	if err != nil {
+		t.Fatalf("NewChapters: %v", err)
+	}
+	if len(chapters) != 1 || chapters[0].Number != 2 {
+		t.Errorf("chapters = %+v, want only chapter 2", chapters)
+	}
+}
+
+func TestNewSenderDefaultsToLog(t *testing.T) {
+	smtp := SMTPConfig{Host: "smtp.example.com", Port: 587, From: "MangaHub <noreply@example.com>"}
+	if _, ok := NewSender("", smtp).(LogSender); !ok {
+		t.Error("an unset sender should only log")
+	}
+	if _, ok := NewSender(SenderSMTP, SMTPConfig{}).(LogSender); !ok {
+		t.Error("smtp without a host should fall back to logging")
+	}
+	if _, ok := NewSender(SenderSMTP, smtp).(*SMTPSender); !ok {
+		t.Error("a configured smtp sender should send")
+	}
+}
@@ -0,0 +1,114 @@
+// Package notify - Digest Repository
+// Data access cho email digest
+// Chức năng:
+//   - Recipients: email_notifications bật và email đã verify
+//   - Chapter mới (chapters.created_at) của manga trong library, trừ manga bị mute
+//   - Ghi thời điểm gửi digest gần nhất (email_digests)
+package notify
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"mangahub/pkg/models"
+)
+
+// Repository defines data access operations for email digests
+type Repository interface {
+	// DigestRecipients returns the verified users who opted in to email digests
+	DigestRecipients(ctx context.Context) ([]models.DigestRecipient, error)
+
+	// NewChapters returns chapters added after since to manga in the user's
+	// library, leaving out manga the user muted
+	NewChapters(ctx context.Context, userID string, since time.Time) ([]models.DigestChapter, error)
+
+	// MarkDigestSent records when the user's last digest was sent
+	MarkDigestSent(ctx context.Context, userID string, sentAt time.Time) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new digest repository
+func NewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+// DigestRecipients returns the verified users who opted in to email digests
+func (r *repository) DigestRecipients(ctx context.Context) ([]models.DigestRecipient, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT u.id, u.username, u.email, d.last_sent_at
+		FROM users u
+		JOIN user_preferences p ON p.user_id = u.id
+		LEFT JOIN email_digests d ON d.user_id = u.id
+		WHERE p.email_notifications = 1 AND u.email_verified = 1 AND u.email != ''
+		ORDER BY u.id`)
+	if err != nil {
+		return nil, fmt.Errorf("query digest recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var recipients []models.DigestRecipient
+	for rows.Next() {
+		var rc models.DigestRecipient
+		var lastSent sql.NullTime
+		if err := rows.Scan(&rc.UserID, &rc.Username, &rc.Email, &lastSent); err != nil {
+			return nil, fmt.Errorf("scan digest recipient: %w", err)
+		}
+		if lastSent.Valid {
+			t := lastSent.Time
+			rc.LastSentAt = &t
+		}
+		recipients = append(recipients, rc)
+	}
+	return recipients, rows.Err()
+}
+
+// NewChapters returns chapters added after since to manga in the user's
+// library (dropped manga excluded), leaving out manga the user muted.
+// created_at may carry any zone offset, so times compare with julianday().
+func (r *repository) NewChapters(ctx context.Context, userID string, since time.Time) ([]models.DigestChapter, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT c.manga_id, m.title, c.number, COALESCE(c.title, ''), c.created_at
+		FROM chapters c
+		JOIN reading_progress rp ON rp.manga_id = c.manga_id AND rp.user_id = ?
+		JOIN manga m ON m.id = c.manga_id
+		WHERE julianday(c.created_at) > julianday(?) AND rp.status != 'dropped'
+		  AND NOT EXISTS (
+			SELECT 1 FROM notification_mutes nm
+			WHERE nm.user_id = rp.user_id AND nm.manga_id = c.manga_id
+		  )
+		ORDER BY m.title ASC, c.number ASC`,
+		userID, since.UTC().Format("2006-01-02 15:04:05.000"),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query new chapters: %w", err)
+	}
+	defer rows.Close()
+
+	var chapters []models.DigestChapter
+	for rows.Next() {
+		var ch models.DigestChapter
+		if err := rows.Scan(&ch.MangaID, &ch.MangaTitle, &ch.Number, &ch.Title, &ch.AddedAt); err != nil {
+			return nil, fmt.Errorf("scan new chapter: %w", err)
+		}
+		chapters = append(chapters, ch)
+	}
+	return chapters, rows.Err()
+}
+
+// MarkDigestSent records when the user's last digest was sent
+func (r *repository) MarkDigestSent(ctx context.Context, userID string, sentAt time.Time) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO email_digests (user_id, last_sent_at) VALUES (?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET last_sent_at = excluded.last_sent_at`,
+		userID, sentAt,
+	)
+	if err != nil {
+		return fmt.Errorf("mark digest sent: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,117 @@
+// Package notify - Email Senders
+// Gửi email qua SMTP hoặc chỉ ghi log (mặc định)
+// Chức năng:
+//   - Sender interface để thay đổi cách gửi (SMTP, log, test)
+//   - LogSender: không gửi gì, chỉ log - an toàn cho dev
+//   - SMTPSender: net/smtp với PLAIN auth
+package notify
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+
+	"mangahub/pkg/logger"
+)
+
+// Sender names (email.sender)
+const (
+	SenderLog  = "log"
+	SenderSMTP = "smtp"
+)
+
+// Message is one outgoing plain-text email
+type Message struct {
+	To      string
+	Subject string
+	Body    string
+}
+
+// Sender delivers email
+type Sender interface {
+	Send(ctx context.Context, msg Message) error
+}
+
+// LogSender writes emails to the log instead of sending them
+type LogSender struct{}
+
+// Send logs the message
+func (LogSender) Send(ctx context.Context, msg Message) error {
+	logger.Infof("email (not sent) to %s: %s\n%s", msg.To, msg.Subject, msg.Body)
+	return nil
+}
+
+// SMTPConfig is where and as whom SMTPSender sends
+type SMTPConfig struct {
+	Host     string
+	Port     int
+	Username string
+	Password string
+	From     string
+}
+
+// SMTPSender sends email through an SMTP server
+type SMTPSender struct {
+	cfg SMTPConfig
+}
+
+// NewSMTPSender creates an SMTP sender
+func NewSMTPSender(cfg SMTPConfig) *SMTPSender {
+	return &SMTPSender{cfg: cfg}
+}
+
+// Send delivers the message; ctx is only checked before connecting
+func (s *SMTPSender) Send(ctx context.Context, msg Message) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+
+	var auth smtp.Auth
+	if s.cfg.Username != "" {
+		auth = smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", headerValue(s.cfg.From))
+	fmt.Fprintf(&b, "To: %s\r\n", headerValue(msg.To))
+	fmt.Fprintf(&b, "Subject: %s\r\n", headerValue(msg.Subject))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+
+	if err := smtp.SendMail(addr, auth, fromAddress(s.cfg.From), []string{msg.To}, []byte(b.String())); err != nil {
+		return fmt.Errorf("send mail to %s: %w", msg.To, err)
+	}
+	return nil
+}
+
+// headerValue keeps a value (e.g. an imported manga title) on one header line
+func headerValue(v string) string {
+	return strings.NewReplacer("\r", " ", "\n", " ").Replace(v)
+}
+
+// fromAddress extracts the address from "Name <addr>"
+func fromAddress(from string) string {
+	if i := strings.LastIndex(from, "<"); i >= 0 {
+		return strings.TrimSuffix(from[i+1:], ">")
+	}
+	return from
+}
+
+// NewSender returns the sender named by name. Anything but a fully
+// configured "smtp" falls back to the LogSender, so no environment emails
+// users unless it was set up to.
+func NewSender(name string, cfg SMTPConfig) Sender {
+	if name != SenderSMTP {
+		return LogSender{}
+	}
+	if cfg.Host == "" || cfg.From == "" {
+		logger.Warnf("email.sender is smtp but smtp_host/from are not set; emails are only logged")
+		return LogSender{}
+	}
+	return NewSMTPSender(cfg)
+}
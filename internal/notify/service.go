@@ -0,0 +1,142 @@
+// Package notify - Email Digest Service
+// Gom chapter mới theo manga và gửi một email mỗi ngày cho user đã opt-in
+// Chức năng:
+//   - Digest đầu tiên chỉ gồm chapter của 24h gần nhất, sau đó tính từ lần gửi trước
+//   - Không có gì mới thì không gửi
+//   - Lỗi của một user được log và bỏ qua, các user khác vẫn nhận
+//   - Scheduler chạy trong API server (email.digest_interval)
+package notify
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"mangahub/pkg/logger"
+	"mangahub/pkg/models"
+)
+
+// DigestWindow is how far back the first digest of a user looks
+const DigestWindow = 24 * time.Hour
+
+// Service sends new-chapter email digests
+type Service struct {
+	repo   Repository
+	sender Sender
+	now    func() time.Time
+}
+
+// NewService creates a digest service sending through sender
+func NewService(repo Repository, sender Sender) *Service {
+	return &Service{repo: repo, sender: sender, now: time.Now}
+}
+
+// SendDigests emails every opted-in user the chapters added since their last
+// digest. A failure for one user is logged and counted; the run goes on.
+// Only a failure to list the recipients returns an error.
+func (s *Service) SendDigests(ctx context.Context) (models.DigestRunResult, error) {
+	var result models.DigestRunResult
+
+	recipients, err := s.repo.DigestRecipients(ctx)
+	if err != nil {
+		return result, err
+	}
+	result.Recipients = len(recipients)
+
+	for _, rc := range recipients {
+		if err := ctx.Err(); err != nil {
+			return result, err
+		}
+		sent, err := s.sendDigest(ctx, rc)
+		switch {
+		case err != nil:
+			result.Failed++
+			logger.Warnf("email digest for user %s failed: %v", rc.UserID, err)
+		case sent:
+			result.Sent++
+		default:
+			result.Empty++
+		}
+	}
+	return result, nil
+}
+
+// sendDigest sends one user's digest; false when there was nothing new
+func (s *Service) sendDigest(ctx context.Context, rc models.DigestRecipient) (bool, error) {
+	now := s.now().UTC()
+	since := now.Add(-DigestWindow)
+	if rc.LastSentAt != nil {
+		since = *rc.LastSentAt
+	}
+
+	chapters, err := s.repo.NewChapters(ctx, rc.UserID, since)
+	if err != nil {
+		return false, err
+	}
+	if len(chapters) == 0 {
+		return false, nil
+	}
+
+	if err := s.sender.Send(ctx, buildDigest(rc, chapters)); err != nil {
+		return false, err
+	}
+	// A failure here means the next digest repeats these chapters
+	if err := s.repo.MarkDigestSent(ctx, rc.UserID, now); err != nil {
+		return true, err
+	}
+	return true, nil
+}
+
+// buildDigest writes the digest email, chapters grouped by manga
+func buildDigest(rc models.DigestRecipient, chapters []models.DigestChapter) Message {
+	var b strings.Builder
+	fmt.Fprintf(&b, "Hi %s,\n\nNew chapters in your library:\n", rc.Username)
+
+	manga := 0
+	for i, ch := range chapters {
+		if i == 0 || ch.MangaID != chapters[i-1].MangaID {
+			manga++
+			fmt.Fprintf(&b, "\n%s\n", ch.MangaTitle)
+		}
+		if ch.Title != "" {
+			fmt.Fprintf(&b, "  - Chapter %d: %s\n", ch.Number, ch.Title)
+		} else {
+			fmt.Fprintf(&b, "  - Chapter %d\n", ch.Number)
+		}
+	}
+	b.WriteString("\nTurn these emails off in Settings, or mute a manga to leave it out.\n")
+
+	subject := fmt.Sprintf("%d new chapters in your library", len(chapters))
+	if len(chapters) == 1 {
+		subject = "1 new chapter in your library"
+	}
+	if manga == 1 {
+		subject += " (" + chapters[0].MangaTitle + ")"
+	}
+	return Message{To: rc.Email, Subject: subject, Body: b.String()}
+}
+
+// Run sends digests every interval until ctx is cancelled
+func (s *Service) Run(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			result, err := s.SendDigests(ctx)
+			if err != nil {
+				logger.Warnf("email digest run failed: %v", err)
+				continue
+			}
+			logger.Infof("email digests: %d sent, %d with nothing new, %d failed",
+				result.Sent, result.Empty, result.Failed)
+		}
+	}
+}
@@ -0,0 +1,127 @@
+// Package preferences - CSV Export Writers
+// Ghi dữ liệu export ra CSV (encoding/csv tự quote dấu phẩy, xuống dòng, dấu ngoặc kép)
+// Layout của file zip:
+//
+//	mangahub-export-YYYYMMDD.zip
+//	├── library.csv
+//	├── history.csv
+//	└── lists.csv
+package preferences
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/csv"
+	"strconv"
+	"time"
+
+	"mangahub/pkg/models"
+)
+
+// csvFile is one CSV inside the export archive
+type csvFile struct {
+	name   string
+	header []string
+	rows   [][]string
+}
+
+// writeCSVArchive zips one CSV per data type.
+// Empty data types still get a header-only file.
+func writeCSVArchive(export *models.UserDataExport) ([]byte, error) {
+	files := []csvFile{
+		libraryCSV(export.Library),
+		historyCSV(export.History),
+		listsCSV(export.Lists),
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, f := range files {
+		w, err := zw.CreateHeader(&zip.FileHeader{
+			Name:     f.name,
+			Method:   zip.Deflate,
+			Modified: export.ExportedAt,
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		cw := csv.NewWriter(w)
+		if err := cw.Write(f.header); err != nil {
+			return nil, err
+		}
+		if err := cw.WriteAll(f.rows); err != nil {
+			return nil, err
+		}
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func libraryCSV(entries []models.ExportLibraryEntry) csvFile {
+	f := csvFile{
+		name: "library.csv",
+		header: []string{
+			"manga_id", "title", "status", "current_chapter", "total_chapters",
+			"is_favorite", "rating", "review", "started_at", "completed_at", "last_read_at",
+		},
+	}
+	for _, e := range entries {
+		rating := ""
+		if e.Rating != nil {
+			rating = strconv.Itoa(*e.Rating)
+		}
+		f.rows = append(f.rows, []string{
+			e.MangaID, e.Title, e.Status,
+			strconv.Itoa(e.CurrentChapter), strconv.Itoa(e.TotalChapters),
+			strconv.FormatBool(e.IsFavorite), rating, e.Review,
+			formatTimePtr(e.StartedAt), formatTimePtr(e.CompletedAt), formatTime(e.LastReadAt),
+		})
+	}
+	return f
+}
+
+func historyCSV(entries []models.ExportHistoryEntry) csvFile {
+	f := csvFile{
+		name:   "history.csv",
+		header: []string{"manga_id", "title", "chapter_number", "pages_read", "time_minutes", "read_at"},
+	}
+	for _, e := range entries {
+		f.rows = append(f.rows, []string{
+			e.MangaID, e.Title,
+			strconv.Itoa(e.ChapterNumber), strconv.Itoa(e.PagesRead), strconv.Itoa(e.TimeMinutes),
+			formatTime(e.ReadAt),
+		})
+	}
+	return f
+}
+
+func listsCSV(entries []models.ExportListEntry) csvFile {
+	f := csvFile{
+		name:   "lists.csv",
+		header: []string{"list_name", "description", "manga_id", "title", "notes", "added_at"},
+	}
+	for _, e := range entries {
+		f.rows = append(f.rows, []string{
+			e.ListName, e.Description, e.MangaID, e.Title, e.Notes, formatTimePtr(e.AddedAt),
+		})
+	}
+	return f
+}
+
+// formatTime renders timestamps as RFC 3339 in UTC
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func formatTimePtr(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return formatTime(*t)
+}
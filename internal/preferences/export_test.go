@@ -0,0 +1,149 @@
+// Package preferences - Data Export Tests
+// Unit tests cho CSV export (quoting, header-only khi rỗng)
+package preferences
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"strings"
+	"testing"
+	"time"
+
+	"mangahub/pkg/models"
+)
+
+// fakeRepository serves fixed export rows and keeps saved preferences in memory
+type fakeRepository struct {
+	library []models.ExportLibraryEntry
+	history []models.ExportHistoryEntry
+	lists   []models.ExportListEntry
+	prefs   *models.UserPreferences
+}
+
+func (f *fakeRepository) GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	return f.prefs, nil
+}
+
+func (f *fakeRepository) SavePreferences(ctx context.Context, userID string, prefs models.UserPreferences) error {
+	f.prefs = &prefs
+	return nil
+}
+
+func (f *fakeRepository) MangaExists(ctx context.Context, mangaID string) (bool, error) {
+	return true, nil
+}
+
+func (f *fakeRepository) IsMangaMuted(ctx context.Context, userID, mangaID string) (bool, error) {
+	return false, nil
+}
+
+func (f *fakeRepository) SetMangaMuted(ctx context.Context, userID, mangaID string, muted bool) error {
+	return nil
+}
+
+func (f *fakeRepository) BlockedRecipients(ctx context.Context, mangaID string, userIDs []string) ([]string, error) {
+	return nil, nil
+}
+
+func (f *fakeRepository) GetLibraryExport(ctx context.Context, userID string) ([]models.ExportLibraryEntry, error) {
+	return f.library, nil
+}
+
+func (f *fakeRepository) GetHistoryExport(ctx context.Context, userID string) ([]models.ExportHistoryEntry, error) {
+	return f.history, nil
+}
+
+func (f *fakeRepository) GetListsExport(ctx context.Context, userID string) ([]models.ExportListEntry, error) {
+	return f.lists, nil
+}
+
+// readArchive unzips an export and parses every CSV inside
+func readArchive(t *testing.T, data []byte) map[string][][]string {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		t.Fatalf("export is not a valid zip: %v", err)
+	}
+
+	files := map[string][][]string{}
+	for _, f := range zr.File {
+		rc, err := f.Open()
+		if err != nil {
+			t.Fatalf("failed to open %s: %v", f.Name, err)
+		}
+		records, err := csv.NewReader(rc).ReadAll()
+		rc.Close()
+		if err != nil {
+			t.Fatalf("failed to parse %s: %v", f.Name, err)
+		}
+		files[f.Name] = records
+	}
+	return files
+}
+
+func TestExportCSVQuotesSpecialCharacters(t *testing.T) {
+	rating := 9
+	repo := &fakeRepository{
+		library: []models.ExportLibraryEntry{{
+			MangaID:    "m-1",
+			Title:      `Kaguya-sama: Love Is War, "Ultra Romantic"`,
+			Status:     "reading",
+			Rating:     &rating,
+			Review:     "Great comedy.\nSecond line, with a comma.",
+			LastReadAt: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		}},
+	}
+	svc := NewService(repo)
+
+	resp, err := svc.ExportData(context.Background(), "user-1", models.ExportDataRequest{Format: "csv"})
+	if err != nil {
+		t.Fatalf("ExportData failed: %v", err)
+	}
+	if !strings.HasSuffix(resp.Filename, ".zip") || resp.ContentType != "application/zip" {
+		t.Errorf("expected a zip export, got %s (%s)", resp.Filename, resp.ContentType)
+	}
+
+	files := readArchive(t, resp.Data)
+	library := files["library.csv"]
+	if len(library) != 2 {
+		t.Fatalf("expected header + 1 row, got %d rows", len(library))
+	}
+	if library[1][1] != repo.library[0].Title {
+		t.Errorf("title did not round-trip: %q", library[1][1])
+	}
+	if library[1][7] != repo.library[0].Review {
+		t.Errorf("review did not round-trip: %q", library[1][7])
+	}
+}
+
+func TestExportCSVEmptyLibraryIsHeaderOnly(t *testing.T) {
+	svc := NewService(&fakeRepository{})
+
+	resp, err := svc.ExportData(context.Background(), "user-1", models.ExportDataRequest{Format: "csv"})
+	if err != nil {
+		t.Fatalf("expected empty export to succeed, got %v", err)
+	}
+
+	files := readArchive(t, resp.Data)
+	for _, name := range []string{"library.csv", "history.csv", "lists.csv"} {
+		records, ok := files[name]
+		if !ok {
+			t.Errorf("missing %s in export", name)
+			continue
+		}
+		if len(records) != 1 {
+			t.Errorf("expected %s to contain only a header, got %d rows", name, len(records))
+		}
+	}
+}
+
+func TestExportRejectsUnknownFormat(t *testing.T) {
+	svc := NewService(&fakeRepository{})
+
+	_, err := svc.ExportData(context.Background(), "user-1", models.ExportDataRequest{Format: "xml"})
+	appErr, ok := err.(*models.AppError)
+	if !ok || appErr.StatusCode != 400 {
+		t.Fatalf("expected 400 for unknown format, got %v", err)
+	}
+}
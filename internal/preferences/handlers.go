@@ -0,0 +1,146 @@
+// Package preferences - User Preferences HTTP Handlers
+// HTTP handlers cho user preferences API endpoints
+// Endpoints:
+//   - GET /users/preferences - Get app preferences
+//   - PUT /users/preferences - Update app preferences (partial)
+//   - GET /manga/:id/mute - Whether updates for a manga are muted
+//   - PUT /manga/:id/mute - Mute updates for a manga
+//   - DELETE /manga/:id/mute - Unmute updates for a manga
+//   - GET /users/export?format=json|csv - Download user data export
+package preferences
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"mangahub/internal/auth"
+	"mangahub/pkg/apperrors"
+	"mangahub/pkg/models"
+)
+
+// Handler handles HTTP requests for user preferences
+type Handler struct {
+	svc Service
+}
+
+// NewHandler creates a new preferences handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GetPreferences handles GET /users/preferences
+func (h *Handler) GetPreferences(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	prefs, err := h.svc.GetPreferences(c.Request.Context(), user.ID)
+	if err != nil {
+		apperrors.Respond(c, err, "unexpected error")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(prefs, "preferences retrieved"))
+}
+
+// UpdatePreferences handles PUT /users/preferences
+// Request body: { theme?, language?, default_status?, notifications_enabled?, show_spoilers?, activity_public?, library_public? }
+func (h *Handler) UpdatePreferences(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	var req models.UpdatePreferencesRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeBadRequest, "invalid JSON body", map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	prefs, err := h.svc.UpdatePreferences(c.Request.Context(), user.ID, req)
+	if err != nil {
+		apperrors.Respond(c, err, "unexpected error")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(prefs, "preferences updated"))
+}
+
+// GetMangaMute handles GET /manga/:id/mute
+func (h *Handler) GetMangaMute(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	mute, err := h.svc.GetMangaMute(c.Request.Context(), user.ID, c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, err, "unexpected error")
+		return
+	}
+
+	c.JSON(http.StatusOK, models.NewSuccessResponse(mute, "mute status retrieved"))
+}
+
+// MuteManga handles PUT /manga/:id/mute
+func (h *Handler) MuteManga(c *gin.Context) {
+	h.setMangaMute(c, true)
+}
+
+// UnmuteManga handles DELETE /manga/:id/mute
+func (h *Handler) UnmuteManga(c *gin.Context) {
+	h.setMangaMute(c, false)
+}
+
+func (h *Handler) setMangaMute(c *gin.Context, muted bool) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	mute, err := h.svc.SetMangaMute(c.Request.Context(), user.ID, c.Param("id"), muted)
+	if err != nil {
+		apperrors.Respond(c, err, "unexpected error")
+		return
+	}
+
+	message := "manga unmuted"
+	if muted {
+		message = "manga muted"
+	}
+	c.JSON(http.StatusOK, models.NewSuccessResponse(mute, message))
+}
+
+// ExportData handles GET /users/export
+// Query params: format (json|csv, default json)
+// Responds with the file itself as an attachment.
+func (h *Handler) ExportData(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	req := models.ExportDataRequest{Format: c.Query("format")}
+
+	export, err := h.svc.ExportData(c.Request.Context(), user.ID, req)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to export data")
+		return
+	}
+
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=%q", export.Filename))
+	c.Data(http.StatusOK, export.ContentType, export.Data)
+}
@@ -0,0 +1,175 @@
+// Package preferences - Preferences Tests
+// Unit tests cho partial update, validate theme và notification mutes
+package preferences
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+// setupTestDB opens the shared test database and seeds the rows these tests use
+func setupTestDB(t *testing.T) *sql.DB {
+	db := testutil.OpenDB(t)
+
+	for _, q := range []string{
+		`INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'alice', 'a@example.com', 'x', 'Alice')`,
+		`INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u2', 'bob', 'b@example.com', 'x', 'Bob')`,
+		`INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u3', 'carol', 'c@example.com', 'x', 'Carol')`,
+		`INSERT INTO manga (id, title) VALUES ('m1', 'Berserk')`,
+		`INSERT INTO manga (id, title) VALUES ('m2', 'Vagabond')`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("seed failed: %v", err)
+		}
+	}
+
+	return db
+}
+
+func TestUpdatePreferencesKeepsOmittedFields(t *testing.T) {
+	repo := &fakeRepository{}
+	svc := NewService(repo)
+	ctx := context.Background()
+
+	off := false
+	if _, err := svc.UpdatePreferences(ctx, "u1", models.UpdatePreferencesRequest{NotificationsEnabled: &off}); err != nil {
+		t.Fatalf("UpdatePreferences: %v", err)
+	}
+	nord := models.ThemeNord
+	prefs, err := svc.UpdatePreferences(ctx, "u1", models.UpdatePreferencesRequest{Theme: &nord})
+	if err != nil {
+		t.Fatalf("UpdatePreferences: %v", err)
+	}
+	if prefs.Theme != models.ThemeNord || prefs.NotificationsEnabled {
+		t.Errorf("got theme=%s notifications=%v, want nord and notifications kept off", prefs.Theme, prefs.NotificationsEnabled)
+	}
+
+	unknown := "solarized"
+	_, err = svc.UpdatePreferences(ctx, "u1", models.UpdatePreferencesRequest{Theme: &unknown})
+	if appErr, ok := err.(*models.AppError); !ok || appErr.StatusCode != 400 {
+		t.Fatalf("err = %v, want 400 for an unknown theme", err)
+	}
+}
+
+func TestBlockedRecipientsHonoursMutesAndPreference(t *testing.T) {
+	db := setupTestDB(t)
+	svc := NewService(NewRepository(db))
+	ctx := context.Background()
+	all := []string{"u1", "u2", "u3"}
+
+	// u1 mutes m1, u2 turns notifications off, u3 keeps defaults
+	if _, err := svc.SetMangaMute(ctx, "u1", "m1", true); err != nil {
+		t.Fatalf("SetMangaMute: %v", err)
+	}
+	off := false
+	if _, err := svc.UpdatePreferences(ctx, "u2", models.UpdatePreferencesRequest{NotificationsEnabled: &off}); err != nil {
+		t.Fatalf("UpdatePreferences: %v", err)
+	}
+
+	blocked, err := svc.BlockedRecipients(ctx, "m1", all)
+	if err != nil {
+		t.Fatalf("BlockedRecipients: %v", err)
+	}
+	if !blocked["u1"] || !blocked["u2"] || blocked["u3"] {
+		t.Errorf("m1 blocked = %v, want u1 (muted) and u2 (disabled)", blocked)
+	}
+
+	// The mute is per manga
+	blocked, _ = svc.BlockedRecipients(ctx, "m2", all)
+	if blocked["u1"] {
+		t.Errorf("u1 muted m1 only, but is blocked for m2")
+	}
+
+	// Re-enabling and unmuting resume delivery without anything else
+	on := true
+	if _, err := svc.UpdatePreferences(ctx, "u2", models.UpdatePreferencesRequest{NotificationsEnabled: &on}); err != nil {
+		t.Fatalf("UpdatePreferences: %v", err)
+	}
+	if _, err := svc.SetMangaMute(ctx, "u1", "m1", false); err != nil {
+		t.Fatalf("SetMangaMute: %v", err)
+	}
+	blocked, _ = svc.BlockedRecipients(ctx, "m1", all)
+	if len(blocked) != 0 {
+		t.Errorf("blocked = %v after re-enabling, want none", blocked)
+	}
+
+	if _, err := svc.SetMangaMute(ctx, "u1", "missing", true); err == nil {
+		t.Error("muting an unknown manga should fail")
+	} else if appErr, ok := err.(*models.AppError); !ok || appErr.StatusCode != 404 {
+		t.Errorf("err = %v, want 404", err)
+	}
+}
+
+func TestStartupPreferencesRoundTrip(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)))
+	ctx := context.Background()
+
+	prefs, err := svc.GetPreferences(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetPreferences: %v", err)
+	}
+	if prefs.DefaultView != models.HomeViewDashboard || !prefs.AutoConnectNotifications {
+		t.Errorf("defaults = %q/%v, want dashboard and auto-connect on", prefs.DefaultView, prefs.AutoConnectNotifications)
+	}
+
+	library, off := models.HomeViewLibrary, false
+	if _, err := svc.UpdatePreferences(ctx, "u1", models.UpdatePreferencesRequest{
+		DefaultView: &library, AutoConnectNotifications: &off,
+	}); err != nil {
+		t.Fatalf("UpdatePreferences: %v", err)
+	}
+	prefs, err = svc.GetPreferences(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetPreferences: %v", err)
+	}
+	if prefs.DefaultView != models.HomeViewLibrary || prefs.AutoConnectNotifications {
+		t.Errorf("saved = %q/%v, want library and auto-connect off", prefs.DefaultView, prefs.AutoConnectNotifications)
+	}
+
+	removed := "reader"
+	_, err = svc.UpdatePreferences(ctx, "u1", models.UpdatePreferencesRequest{DefaultView: &removed})
+	if appErr, ok := err.(*models.AppError); !ok || appErr.StatusCode != 400 {
+		t.Fatalf("err = %v, want 400 for a view that cannot be home", err)
+	}
+}
+
+func TestKeybindingsRoundTripAndConflicts(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)))
+	ctx := context.Background()
+
+	if _, err := svc.UpdatePreferences(ctx, "u1", models.UpdatePreferencesRequest{
+		Keybindings: map[string]string{"search": "f", "library": "ctrl+l"},
+	}); err != nil {
+		t.Fatalf("UpdatePreferences: %v", err)
+	}
+	// Other updates leave the bindings alone
+	off := false
+	if _, err := svc.UpdatePreferences(ctx, "u1", models.UpdatePreferencesRequest{ShowSpoilers: &off}); err != nil {
+		t.Fatalf("UpdatePreferences: %v", err)
+	}
+	prefs, err := svc.GetPreferences(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetPreferences: %v", err)
+	}
+	if prefs.Keybindings["search"] != "f" || prefs.Keybindings["library"] != "ctrl+l" {
+		t.Errorf("keybindings = %v, want search=f library=ctrl+l", prefs.Keybindings)
+	}
+
+	_, err = svc.UpdatePreferences(ctx, "u1", models.UpdatePreferencesRequest{
+		Keybindings: map[string]string{"search": "f", "browse": "f"},
+	})
+	if appErr, ok := err.(*models.AppError); !ok || appErr.StatusCode != 400 {
+		t.Fatalf("err = %v, want 400 for two actions on one key", err)
+	}
+
+	if _, err := svc.UpdatePreferences(ctx, "u1", models.UpdatePreferencesRequest{Keybindings: map[string]string{}}); err != nil {
+		t.Fatalf("reset: %v", err)
+	}
+	if prefs, _ = svc.GetPreferences(ctx, "u1"); len(prefs.Keybindings) != 0 {
+		t.Errorf("keybindings after reset = %v, want none", prefs.Keybindings)
+	}
+}
@@ -0,0 +1,318 @@
+// Package preferences - User Preferences Repository
+// Data access layer cho user preferences và data export
+// Chức năng:
+//   - Đọc/ghi user_preferences
+//   - Mute/unmute notification theo manga (notification_mutes)
+//   - Lọc recipients bị chặn (tắt notification hoặc mute manga)
+//   - Load library (kèm rating/review) cho export
+//   - Load chapter history và custom lists cho export
+package preferences
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"mangahub/pkg/models"
+)
+
+// Repository defines data access operations for preferences and export
+type Repository interface {
+	// GetPreferences returns the user's saved preferences, or nil if none are saved
+	GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error)
+
+	// SavePreferences inserts or replaces the user's preferences
+	SavePreferences(ctx context.Context, userID string, prefs models.UserPreferences) error
+
+	// MangaExists reports whether a manga exists
+	MangaExists(ctx context.Context, mangaID string) (bool, error)
+
+	// IsMangaMuted reports whether the user muted notifications for a manga
+	IsMangaMuted(ctx context.Context, userID, mangaID string) (bool, error)
+
+	// SetMangaMuted mutes or unmutes notifications for a manga (idempotent)
+	SetMangaMuted(ctx context.Context, userID, mangaID string, muted bool) error
+
+	// BlockedRecipients returns the users among userIDs who must not be notified
+	// about mangaID: notifications disabled, or the manga muted
+	BlockedRecipients(ctx context.Context, mangaID string, userIDs []string) ([]string, error)
+
+	// GetLibraryExport returns the user's library joined with manga and ratings
+	GetLibraryExport(ctx context.Context, userID string) ([]models.ExportLibraryEntry, error)
+
+	// GetHistoryExport returns every chapter read by the user
+	GetHistoryExport(ctx context.Context, userID string) ([]models.ExportHistoryEntry, error)
+
+	// GetListsExport returns the user's custom lists and their manga
+	GetListsExport(ctx context.Context, userID string) ([]models.ExportListEntry, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new preferences repository
+func NewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+// GetPreferences returns the user's saved preferences, or nil if none are saved
+func (r *repository) GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	var p models.UserPreferences
+	var keybindings string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(theme, ''), COALESCE(language, ''), COALESCE(default_status, ''),
+		       COALESCE(notifications_enabled, 1), COALESCE(show_spoilers, 0),
+		       COALESCE(activity_public, 1), COALESCE(library_public, 1),
+		       COALESCE(default_view, ''), COALESCE(auto_connect_notifications, 1),
+		       COALESCE(email_notifications, 0), COALESCE(keybindings, ''), updated_at
+		FROM user_preferences WHERE user_id = ?`, userID,
+	).Scan(&p.Theme, &p.Language, &p.DefaultStatus, &p.NotificationsEnabled, &p.ShowSpoilers,
+		&p.ActivityPublic, &p.LibraryPublic, &p.DefaultView, &p.AutoConnectNotifications,
+		&p.EmailNotifications, &keybindings, &p.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get preferences: %w", err)
+	}
+	if keybindings != "" {
+		if err := json.Unmarshal([]byte(keybindings), &p.Keybindings); err != nil {
+			return nil, fmt.Errorf("decode keybindings: %w", err)
+		}
+	}
+	return &p, nil
+}
+
+// SavePreferences inserts or replaces the user's preferences
+func (r *repository) SavePreferences(ctx context.Context, userID string, prefs models.UserPreferences) error {
+	var keybindings sql.NullString
+	if len(prefs.Keybindings) > 0 {
+		data, err := json.Marshal(prefs.Keybindings)
+		if err != nil {
+			return fmt.Errorf("encode keybindings: %w", err)
+		}
+		keybindings = sql.NullString{String: string(data), Valid: true}
+	}
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO user_preferences (user_id, theme, language, default_status, notifications_enabled, show_spoilers,
+			activity_public, library_public, default_view, auto_connect_notifications, email_notifications,
+			keybindings, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			theme = excluded.theme,
+			language = excluded.language,
+			default_status = excluded.default_status,
+			notifications_enabled = excluded.notifications_enabled,
+			show_spoilers = excluded.show_spoilers,
+			activity_public = excluded.activity_public,
+			library_public = excluded.library_public,
+			default_view = excluded.default_view,
+			auto_connect_notifications = excluded.auto_connect_notifications,
+			email_notifications = excluded.email_notifications,
+			keybindings = excluded.keybindings,
+			updated_at = excluded.updated_at`,
+		userID, prefs.Theme, prefs.Language, prefs.DefaultStatus, prefs.NotificationsEnabled,
+		prefs.ShowSpoilers, prefs.ActivityPublic, prefs.LibraryPublic, prefs.DefaultView, prefs.AutoConnectNotifications,
+		prefs.EmailNotifications, keybindings, prefs.UpdatedAt, prefs.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("save preferences: %w", err)
+	}
+	return nil
+}
+
+// MangaExists reports whether a manga exists
+func (r *repository) MangaExists(ctx context.Context, mangaID string) (bool, error) {
+	var exists bool
+	err := r.db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM manga WHERE id = ?)", mangaID,
+	).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check manga: %w", err)
+	}
+	return exists, nil
+}
+
+// IsMangaMuted reports whether the user muted notifications for a manga
+func (r *repository) IsMangaMuted(ctx context.Context, userID, mangaID string) (bool, error) {
+	var muted bool
+	err := r.db.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM notification_mutes WHERE user_id = ? AND manga_id = ?)",
+		userID, mangaID,
+	).Scan(&muted)
+	if err != nil {
+		return false, fmt.Errorf("get mute: %w", err)
+	}
+	return muted, nil
+}
+
+// SetMangaMuted mutes or unmutes notifications for a manga (idempotent)
+func (r *repository) SetMangaMuted(ctx context.Context, userID, mangaID string, muted bool) error {
+	var err error
+	if muted {
+		_, err = r.db.ExecContext(ctx,
+			"INSERT OR IGNORE INTO notification_mutes (user_id, manga_id, created_at) VALUES (?, ?, ?)",
+			userID, mangaID, time.Now().UTC(),
+		)
+	} else {
+		_, err = r.db.ExecContext(ctx,
+			"DELETE FROM notification_mutes WHERE user_id = ? AND manga_id = ?",
+			userID, mangaID,
+		)
+	}
+	if err != nil {
+		return fmt.Errorf("set mute: %w", err)
+	}
+	return nil
+}
+
+// BlockedRecipients returns the users among userIDs who must not be notified about mangaID
+func (r *repository) BlockedRecipients(ctx context.Context, mangaID string, userIDs []string) ([]string, error) {
+	if len(userIDs) == 0 {
+		return nil, nil
+	}
+
+	placeholders := strings.TrimSuffix(strings.Repeat("?,", len(userIDs)), ",")
+	args := make([]interface{}, 0, 2*len(userIDs)+1)
+	for _, id := range userIDs {
+		args = append(args, id)
+	}
+	args = append(args, mangaID)
+	for _, id := range userIDs {
+		args = append(args, id)
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT user_id FROM user_preferences
+		WHERE notifications_enabled = 0 AND user_id IN (`+placeholders+`)
+		UNION
+		SELECT user_id FROM notification_mutes
+		WHERE manga_id = ? AND user_id IN (`+placeholders+`)`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query blocked recipients: %w", err)
+	}
+	defer rows.Close()
+
+	var blocked []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, fmt.Errorf("scan blocked recipient: %w", err)
+		}
+		blocked = append(blocked, id)
+	}
+	return blocked, rows.Err()
+}
+
+// GetLibraryExport returns the user's library joined with manga and ratings
+func (r *repository) GetLibraryExport(ctx context.Context, userID string) ([]models.ExportLibraryEntry, error) {
+	query := `
+		SELECT rp.manga_id, m.title, rp.status, rp.current_chapter, COALESCE(m.total_chapters, 0),
+		       rp.is_favorite, mr.rating, COALESCE(mr.review_text, ''),
+		       rp.started_at, rp.completed_at, rp.last_read_at
+		FROM reading_progress rp
+		JOIN manga m ON m.id = rp.manga_id
+		LEFT JOIN manga_ratings mr ON mr.manga_id = rp.manga_id AND mr.user_id = rp.user_id
+		WHERE rp.user_id = ?
+		ORDER BY m.title ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query library export: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.ExportLibraryEntry{}
+	for rows.Next() {
+		var e models.ExportLibraryEntry
+		var rating sql.NullInt64
+		var startedAt, completedAt sql.NullTime
+		if err := rows.Scan(
+			&e.MangaID, &e.Title, &e.Status, &e.CurrentChapter, &e.TotalChapters,
+			&e.IsFavorite, &rating, &e.Review,
+			&startedAt, &completedAt, &e.LastReadAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan library export: %w", err)
+		}
+		if rating.Valid {
+			v := int(rating.Int64)
+			e.Rating = &v
+		}
+		e.StartedAt = nullTimePtr(startedAt)
+		e.CompletedAt = nullTimePtr(completedAt)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// GetHistoryExport returns every chapter read by the user, newest first
+func (r *repository) GetHistoryExport(ctx context.Context, userID string) ([]models.ExportHistoryEntry, error) {
+	query := `
+		SELECT ch.manga_id, m.title, ch.chapter_number, ch.pages_read, ch.time_minutes, ch.read_at
+		FROM chapter_history ch
+		JOIN manga m ON m.id = ch.manga_id
+		WHERE ch.user_id = ?
+		ORDER BY ch.read_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query history export: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.ExportHistoryEntry{}
+	for rows.Next() {
+		var e models.ExportHistoryEntry
+		if err := rows.Scan(&e.MangaID, &e.Title, &e.ChapterNumber, &e.PagesRead, &e.TimeMinutes, &e.ReadAt); err != nil {
+			return nil, fmt.Errorf("scan history export: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// GetListsExport returns the user's custom lists and their manga
+func (r *repository) GetListsExport(ctx context.Context, userID string) ([]models.ExportListEntry, error) {
+	query := `
+		SELECT cl.name, COALESCE(cl.description, ''),
+		       COALESCE(cli.manga_id, ''), COALESCE(m.title, ''), COALESCE(cli.notes, ''), cli.added_at
+		FROM custom_lists cl
+		LEFT JOIN custom_list_items cli ON cli.list_id = cl.id
+		LEFT JOIN manga m ON m.id = cli.manga_id
+		WHERE cl.user_id = ?
+		ORDER BY cl.sort_order ASC, cl.name ASC, cli.sort_order ASC`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, fmt.Errorf("query lists export: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.ExportListEntry{}
+	for rows.Next() {
+		var e models.ExportListEntry
+		var addedAt sql.NullTime
+		if err := rows.Scan(&e.ListName, &e.Description, &e.MangaID, &e.Title, &e.Notes, &addedAt); err != nil {
+			return nil, fmt.Errorf("scan lists export: %w", err)
+		}
+		e.AddedAt = nullTimePtr(addedAt)
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
+// nullTimePtr converts a nullable timestamp to a pointer
+func nullTimePtr(t sql.NullTime) *time.Time {
+	if !t.Valid {
+		return nil
+	}
+	v := t.Time
+	return &v
+}
@@ -0,0 +1,252 @@
+// Package preferences - User Preferences Service
+// Business logic layer cho user preferences và data export
+// Chức năng:
+//   - Get/update preferences (theme, home view, keybindings...); field bỏ trống giữ nguyên giá trị cũ
+//   - Mute/unmute update notifications theo manga
+//   - Quyết định recipients cho UDP push (notifications_enabled + mutes)
+//   - Export library, lịch sử đọc, custom lists
+//   - Định dạng JSON (một file) hoặc CSV (zip nhiều file)
+package preferences
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"mangahub/pkg/apperrors"
+	"mangahub/pkg/models"
+	"mangahub/pkg/utils"
+)
+
+// Service defines business operations for user preferences
+type Service interface {
+	// GetPreferences returns the user's preferences, defaults if never saved
+	GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error)
+
+	// UpdatePreferences applies the fields set in req and returns the result
+	UpdatePreferences(ctx context.Context, userID string, req models.UpdatePreferencesRequest) (*models.UserPreferences, error)
+
+	// GetMangaMute returns whether the user muted updates for a manga
+	GetMangaMute(ctx context.Context, userID, mangaID string) (*models.MangaMute, error)
+
+	// SetMangaMute mutes or unmutes updates for a manga
+	SetMangaMute(ctx context.Context, userID, mangaID string, muted bool) (*models.MangaMute, error)
+
+	// BlockedRecipients returns the set of users among userIDs who must not
+	// receive a notification about mangaID (empty mangaID: preference only)
+	BlockedRecipients(ctx context.Context, mangaID string, userIDs []string) (map[string]bool, error)
+
+	// ExportData builds a downloadable export of the user's data
+	ExportData(ctx context.Context, userID string, req models.ExportDataRequest) (*models.ExportDataResponse, error)
+}
+
+type service struct {
+	repo Repository
+	now  func() time.Time
+}
+
+// NewService creates a new preferences service
+func NewService(repo Repository) Service {
+	return &service{repo: repo, now: time.Now}
+}
+
+// GetPreferences returns the user's preferences, defaults if never saved
+func (s *service) GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error) {
+	prefs, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Internal("failed to load preferences", err)
+	}
+	if prefs == nil {
+		defaults := models.DefaultUserPreferences()
+		return &defaults, nil
+	}
+	return prefs, nil
+}
+
+// UpdatePreferences applies the fields set in req and returns the result
+func (s *service) UpdatePreferences(ctx context.Context, userID string, req models.UpdatePreferencesRequest) (*models.UserPreferences, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, apperrors.Invalid("invalid preferences", err)
+	}
+
+	prefs, err := s.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if req.Theme != nil {
+		prefs.Theme = *req.Theme
+	}
+	if req.Language != nil {
+		prefs.Language = *req.Language
+	}
+	if req.DefaultStatus != nil {
+		prefs.DefaultStatus = *req.DefaultStatus
+	}
+	if req.NotificationsEnabled != nil {
+		prefs.NotificationsEnabled = *req.NotificationsEnabled
+	}
+	if req.ShowSpoilers != nil {
+		prefs.ShowSpoilers = *req.ShowSpoilers
+	}
+	if req.ActivityPublic != nil {
+		prefs.ActivityPublic = *req.ActivityPublic
+	}
+	if req.LibraryPublic != nil {
+		prefs.LibraryPublic = *req.LibraryPublic
+	}
+	if req.DefaultView != nil {
+		prefs.DefaultView = *req.DefaultView
+	}
+	if req.AutoConnectNotifications != nil {
+		prefs.AutoConnectNotifications = *req.AutoConnectNotifications
+	}
+	if req.EmailNotifications != nil {
+		prefs.EmailNotifications = *req.EmailNotifications
+	}
+	if req.Keybindings != nil {
+		if err := validateKeybindings(req.Keybindings); err != nil {
+			return nil, err
+		}
+		prefs.Keybindings = req.Keybindings
+	}
+	prefs.UpdatedAt = s.now().UTC()
+
+	if err := s.repo.SavePreferences(ctx, userID, *prefs); err != nil {
+		return nil, apperrors.Internal("failed to save preferences", err)
+	}
+	return prefs, nil
+}
+
+// validateKeybindings rejects oversized maps, blank names and two actions on one key.
+// Action names belong to the TUI, so unknown ones are stored as they are.
+func validateKeybindings(bindings map[string]string) error {
+	if len(bindings) > models.MaxKeybindings {
+		return apperrors.Invalid(fmt.Sprintf("at most %d keybindings", models.MaxKeybindings), nil)
+	}
+	owner := make(map[string]string, len(bindings))
+	for action, key := range bindings {
+		if action == "" || key == "" || len(action) > 32 || len(key) > 32 {
+			return apperrors.Invalid("keybinding actions and keys must be 1-32 characters", nil)
+		}
+		if other, taken := owner[key]; taken {
+			return apperrors.Invalid(fmt.Sprintf("key %q is bound to both %s and %s", key, other, action), nil)
+		}
+		owner[key] = action
+	}
+	return nil
+}
+
+// GetMangaMute returns whether the user muted updates for a manga
+func (s *service) GetMangaMute(ctx context.Context, userID, mangaID string) (*models.MangaMute, error) {
+	if err := s.requireManga(ctx, mangaID); err != nil {
+		return nil, err
+	}
+	muted, err := s.repo.IsMangaMuted(ctx, userID, mangaID)
+	if err != nil {
+		return nil, apperrors.Internal("failed to load mute", err)
+	}
+	return &models.MangaMute{MangaID: mangaID, Muted: muted}, nil
+}
+
+// SetMangaMute mutes or unmutes updates for a manga.
+// Delivery is decided per broadcast, so unmuting resumes pushes immediately.
+func (s *service) SetMangaMute(ctx context.Context, userID, mangaID string, muted bool) (*models.MangaMute, error) {
+	if err := s.requireManga(ctx, mangaID); err != nil {
+		return nil, err
+	}
+	if err := s.repo.SetMangaMuted(ctx, userID, mangaID, muted); err != nil {
+		return nil, apperrors.Internal("failed to save mute", err)
+	}
+	return &models.MangaMute{MangaID: mangaID, Muted: muted}, nil
+}
+
+// requireManga returns a 404 AppError if the manga does not exist
+func (s *service) requireManga(ctx context.Context, mangaID string) error {
+	exists, err := s.repo.MangaExists(ctx, mangaID)
+	if err != nil {
+		return apperrors.Internal("failed to load manga", err)
+	}
+	if !exists {
+		return apperrors.NotFound("manga not found", nil)
+	}
+	return nil
+}
+
+// BlockedRecipients returns the set of users among userIDs who must not
+// receive a notification about mangaID
+func (s *service) BlockedRecipients(ctx context.Context, mangaID string, userIDs []string) (map[string]bool, error) {
+	ids, err := s.repo.BlockedRecipients(ctx, mangaID, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	blocked := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		blocked[id] = true
+	}
+	return blocked, nil
+}
+
+// ExportData builds a downloadable export of the user's data.
+// JSON produces a single document; CSV produces a zip with one CSV per data type.
+func (s *service) ExportData(ctx context.Context, userID string, req models.ExportDataRequest) (*models.ExportDataResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, apperrors.Invalid("format must be json or csv", err)
+	}
+	if req.Format == "" {
+		req.Format = models.ExportFormatJSON
+	}
+
+	export, err := s.collect(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Internal("failed to load export data", err)
+	}
+
+	base := "mangahub-export-" + export.ExportedAt.Format("20060102")
+
+	switch req.Format {
+	case models.ExportFormatCSV:
+		data, err := writeCSVArchive(export)
+		if err != nil {
+			return nil, apperrors.Internal("failed to build CSV export", err)
+		}
+		return &models.ExportDataResponse{
+			Filename:    base + ".zip",
+			ContentType: "application/zip",
+			Data:        data,
+		}, nil
+	default:
+		data, err := json.MarshalIndent(export, "", "  ")
+		if err != nil {
+			return nil, apperrors.Internal("failed to build JSON export", err)
+		}
+		return &models.ExportDataResponse{
+			Filename:    base + ".json",
+			ContentType: "application/json",
+			Data:        data,
+		}, nil
+	}
+}
+
+// collect loads every exported data type for a user
+func (s *service) collect(ctx context.Context, userID string) (*models.UserDataExport, error) {
+	library, err := s.repo.GetLibraryExport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	history, err := s.repo.GetHistoryExport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	lists, err := s.repo.GetListsExport(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.UserDataExport{
+		ExportedAt: s.now().UTC(),
+		Library:    library,
+		History:    history,
+		Lists:      lists,
+	}, nil
+}
@@ -0,0 +1,35 @@
+// Package profile - Public Profile HTTP Handlers
+// HTTP handlers cho public profile API endpoints
+// Endpoints:
+//   - GET /users/:username - Public profile (activity/library theo privacy của user)
+package profile
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"mangahub/pkg/apperrors"
+	"mangahub/pkg/models"
+)
+
+// Handler handles HTTP requests for public profiles
+type Handler struct {
+	svc Service
+}
+
+// NewHandler creates a new profile handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GetProfile handles GET /users/:username
+func (h *Handler) GetProfile(c *gin.Context) {
+	profile, err := h.svc.GetProfile(c.Request.Context(), c.Param("username"))
+	if err != nil {
+		apperrors.Respond(c, err, "failed to load profile")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(profile, "profile retrieved"))
+}
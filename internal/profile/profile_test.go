@@ -0,0 +1,106 @@
+// Package profile - Public Profile Tests
+// Unit tests cho privacy của public profile và 404 khi không có username
+package profile
+
+import (
+	"database/sql"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"mangahub/internal/testutil"
+)
+
+// setupTestDB opens the shared test database and seeds the rows these tests use
+func setupTestDB(t *testing.T) *sql.DB {
+	db := testutil.OpenDB(t)
+
+	for _, q := range []string{
+		`INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'guts', 'guts@example.com', 'x', 'Guts')`,
+		`INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u2', 'casca', 'casca@example.com', 'x', 'Casca')`,
+		`INSERT INTO users (id, username, email, password_hash, display_name, is_active) VALUES ('u3', 'griffith', 'g@example.com', 'x', 'Griffith', 0)`,
+		`INSERT INTO manga (id, title) VALUES ('m1', 'Berserk')`,
+		`INSERT INTO reading_progress (id, user_id, manga_id, status, is_favorite) VALUES ('p1', 'u1', 'm1', 'reading', 1)`,
+		`INSERT INTO reading_progress (id, user_id, manga_id, status) VALUES ('p2', 'u2', 'm1', 'completed')`,
+		`INSERT INTO chapter_history (id, user_id, manga_id, chapter_number) VALUES ('h1', 'u1', 'm1', 1), ('h2', 'u1', 'm1', 2)`,
+		`INSERT INTO activity_feed (id, user_id, username, activity_type, manga_id, manga_title) VALUES ('a1', 'u1', 'guts', 'progress', 'm1', 'Berserk')`,
+		`INSERT INTO activity_feed (id, user_id, username, activity_type, manga_id, manga_title) VALUES ('a2', 'u2', 'casca', 'progress', 'm1', 'Berserk')`,
+		`INSERT INTO user_preferences (user_id, activity_public, library_public) VALUES ('u2', 0, 0)`,
+	} {
+		if _, err := db.Exec(q); err != nil {
+			t.Fatalf("seed failed: %v", err)
+		}
+	}
+
+	return db
+}
+
+// getProfile requests a profile through a router that also has a static /users route
+func getProfile(t *testing.T, db *sql.DB, username string) (int, map[string]interface{}) {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/users/library", func(c *gin.Context) { c.Status(http.StatusTeapot) })
+	router.GET("/users/:username", NewHandler(NewService(NewRepository(db))).GetProfile)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/users/"+username, nil))
+
+	var body struct {
+		Data map[string]interface{} `json:"data"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if strings.Contains(w.Body.String(), "@example.com") {
+		t.Errorf("profile leaks an email: %s", w.Body.String())
+	}
+	return w.Code, body.Data
+}
+
+func TestPublicProfile(t *testing.T) {
+	db := setupTestDB(t)
+
+	code, data := getProfile(t, db, "Guts")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	if data["display_name"] != "Guts" || data["chapters_read"] != float64(2) {
+		t.Errorf("profile = %v", data)
+	}
+	if rank := data["rank"].(map[string]interface{}); rank["name"] != "Bronze" {
+		t.Errorf("rank = %v, want Bronze", rank)
+	}
+	if library := data["library"].(map[string]interface{}); library["reading"] != float64(1) || library["favorites"] != float64(1) {
+		t.Errorf("library = %v", library)
+	}
+	if activity := data["recent_activity"].([]interface{}); len(activity) != 1 {
+		t.Errorf("recent_activity = %v, want 1 entry", activity)
+	}
+}
+
+func TestPrivateProfileHidesActivityAndLibrary(t *testing.T) {
+	code, data := getProfile(t, setupTestDB(t), "casca")
+	if code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", code)
+	}
+	for _, field := range []string{"chapters_read", "recent_activity", "library", "id", "email"} {
+		if _, ok := data[field]; ok {
+			t.Errorf("private profile includes %q: %v", field, data)
+		}
+	}
+	if data["display_name"] != "Casca" || data["joined_at"] == nil || data["rank"] == nil {
+		t.Errorf("private profile lost its public fields: %v", data)
+	}
+}
+
+func TestUnknownOrInactiveUserIsNotFound(t *testing.T) {
+	db := setupTestDB(t)
+	for _, username := range []string{"zodd", "griffith"} {
+		if code, _ := getProfile(t, db, username); code != http.StatusNotFound {
+			t.Errorf("%s: status = %d, want 404", username, code)
+		}
+	}
+}
@@ -0,0 +1,135 @@
+// Package profile - Public Profile Repository
+// Data access layer cho public user profiles
+// Chức năng:
+//   - Tìm user active theo username
+//   - Privacy flags từ user_preferences (mặc định public khi chưa lưu)
+//   - Tổng chapter đã đọc, library counts theo status, activity gần đây
+package profile
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"mangahub/pkg/models"
+)
+
+// Repository defines data access operations for public profiles
+type Repository interface {
+	// GetUserByUsername returns an active user by case-insensitive username, nil if none
+	GetUserByUsername(ctx context.Context, username string) (*models.UserProfile, error)
+
+	// GetPrivacy returns whether the user's activity and library are public
+	GetPrivacy(ctx context.Context, userID string) (activityPublic, libraryPublic bool, err error)
+
+	// CountChaptersRead counts the user's chapter_history rows
+	CountChaptersRead(ctx context.Context, userID string) (int, error)
+
+	// GetLibraryCounts summarizes the user's library by status
+	GetLibraryCounts(ctx context.Context, userID string) (*models.LibraryCounts, error)
+
+	// GetRecentActivity returns the user's newest activities
+	GetRecentActivity(ctx context.Context, userID string, limit int) ([]models.Activity, error)
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new profile repository
+func NewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+// GetUserByUsername returns an active user by case-insensitive username, nil if none
+func (r *repository) GetUserByUsername(ctx context.Context, username string) (*models.UserProfile, error) {
+	var u models.UserProfile
+	err := r.db.QueryRowContext(ctx, `
+		SELECT id, username, display_name, created_at
+		FROM users
+		WHERE LOWER(username) = LOWER(?) AND COALESCE(is_active, 1) = 1`,
+		strings.TrimSpace(username),
+	).Scan(&u.ID, &u.Username, &u.DisplayName, &u.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get user by username: %w", err)
+	}
+	return &u, nil
+}
+
+// GetPrivacy returns whether the user's activity and library are public
+func (r *repository) GetPrivacy(ctx context.Context, userID string) (bool, bool, error) {
+	defaults := models.DefaultUserPreferences()
+	activityPublic, libraryPublic := defaults.ActivityPublic, defaults.LibraryPublic
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COALESCE(activity_public, 1), COALESCE(library_public, 1)
+		FROM user_preferences WHERE user_id = ?`, userID,
+	).Scan(&activityPublic, &libraryPublic)
+	if err != nil && err != sql.ErrNoRows {
+		return false, false, fmt.Errorf("get privacy: %w", err)
+	}
+	return activityPublic, libraryPublic, nil
+}
+
+// CountChaptersRead counts the user's chapter_history rows
+func (r *repository) CountChaptersRead(ctx context.Context, userID string) (int, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM chapter_history WHERE user_id = ?", userID,
+	).Scan(&n)
+	if err != nil {
+		return 0, fmt.Errorf("count chapters read: %w", err)
+	}
+	return n, nil
+}
+
+// GetLibraryCounts summarizes the user's library by status
+func (r *repository) GetLibraryCounts(ctx context.Context, userID string) (*models.LibraryCounts, error) {
+	var c models.LibraryCounts
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*),
+		       COALESCE(SUM(status = 'reading'), 0),
+		       COALESCE(SUM(status = 'completed'), 0),
+		       COALESCE(SUM(status = 'plan_to_read'), 0),
+		       COALESCE(SUM(status = 'on_hold'), 0),
+		       COALESCE(SUM(status = 'dropped'), 0),
+		       COALESCE(SUM(is_favorite = 1), 0)
+		FROM reading_progress
+		WHERE user_id = ?`, userID,
+	).Scan(&c.Total, &c.Reading, &c.Completed, &c.PlanToRead, &c.OnHold, &c.Dropped, &c.Favorites)
+	if err != nil {
+		return nil, fmt.Errorf("get library counts: %w", err)
+	}
+	return &c, nil
+}
+
+// GetRecentActivity returns the user's newest activities
+func (r *repository) GetRecentActivity(ctx context.Context, userID string, limit int) ([]models.Activity, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, username, activity_type, manga_id, manga_title,
+		       chapter_number, rating, COALESCE(comment_text, ''), created_at
+		FROM activity_feed
+		WHERE user_id = ?
+		ORDER BY created_at DESC
+		LIMIT ?`, userID, limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get recent activity: %w", err)
+	}
+	defer rows.Close()
+
+	activities := make([]models.Activity, 0, limit)
+	for rows.Next() {
+		var a models.Activity
+		if err := rows.Scan(&a.ID, &a.UserID, &a.Username, &a.ActivityType, &a.MangaID, &a.MangaTitle,
+			&a.ChapterNumber, &a.Rating, &a.CommentText, &a.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan activity: %w", err)
+		}
+		activities = append(activities, a)
+	}
+	return activities, rows.Err()
+}
@@ -0,0 +1,80 @@
+// Package profile - Public Profile Service
+// Business logic layer cho public user profiles
+// Chức năng:
+//   - Ghép profile: display name, ngày tham gia, rank theo chapter đã đọc
+//   - Tôn trọng privacy: activity/library chỉ trả về khi user để public
+//   - Username không tồn tại (hoặc bị khoá) trả về 404
+package profile
+
+import (
+	"context"
+
+	"mangahub/pkg/apperrors"
+	"mangahub/pkg/models"
+)
+
+// RecentActivityLimit is how many activities a profile shows
+const RecentActivityLimit = 10
+
+// Service defines business operations for public profiles
+type Service interface {
+	// GetProfile returns the public profile for a username
+	GetProfile(ctx context.Context, username string) (*models.PublicProfile, error)
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new profile service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// GetProfile returns the public profile for a username.
+// The rank is always shown; the exact chapter count, recent activity and
+// library counts only when the user made them public.
+func (s *service) GetProfile(ctx context.Context, username string) (*models.PublicProfile, error) {
+	user, err := s.repo.GetUserByUsername(ctx, username)
+	if err != nil {
+		return nil, apperrors.Internal("failed to load profile", err)
+	}
+	if user == nil {
+		return nil, apperrors.NotFound("user not found", nil)
+	}
+
+	activityPublic, libraryPublic, err := s.repo.GetPrivacy(ctx, user.ID)
+	if err != nil {
+		return nil, apperrors.Internal("failed to load profile", err)
+	}
+	chapters, err := s.repo.CountChaptersRead(ctx, user.ID)
+	if err != nil {
+		return nil, apperrors.Internal("failed to load profile", err)
+	}
+
+	rank, _ := models.RankFor(chapters)
+	profile := &models.PublicProfile{
+		Username:       user.Username,
+		DisplayName:    user.DisplayName,
+		JoinedAt:       user.CreatedAt,
+		Rank:           rank,
+		ActivityPublic: activityPublic,
+		LibraryPublic:  libraryPublic,
+	}
+
+	if activityPublic {
+		profile.ChaptersRead = &chapters
+		profile.RecentActivity, err = s.repo.GetRecentActivity(ctx, user.ID, RecentActivityLimit)
+		if err != nil {
+			return nil, apperrors.Internal("failed to load profile", err)
+		}
+	}
+	if libraryPublic {
+		profile.Library, err = s.repo.GetLibraryCounts(ctx, user.ID)
+		if err != nil {
+			return nil, apperrors.Internal("failed to load profile", err)
+		}
+	}
+
+	return profile, nil
+}
@@ -0,0 +1,122 @@
+// Package progress - Bulk Library Import Tests
+// Unit tests cho bulk import (match external ID, fuzzy title, hàng đợi fetch)
+// và bulk status (manga không có trong library báo lỗi riêng)
+package progress
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+func mustExec(t *testing.T, db *sql.DB, query string, args ...interface{}) {
+	t.Helper()
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+}
+
+func TestBulkImportResolvesAndQueues(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`)
+	mustExec(t, db, `INSERT INTO manga (id, title, author) VALUES ('m-berserk', 'Berserk', 'Kentaro Miura')`)
+	mustExec(t, db, `INSERT INTO manga (id, title, author) VALUES ('m-kaguya', 'Kaguya-sama: Love Is War', 'Aka Akasaka')`)
+	mustExec(t, db, `INSERT INTO manga_external_ids (manga_id, mal_id) VALUES ('m-berserk', 2)`)
+	// Already in the library, so the import updates it
+	mustExec(t, db, `INSERT INTO reading_progress (id, user_id, manga_id, current_chapter, status) VALUES ('p1', 'u1', 'm-berserk', 10, 'reading')`)
+
+	svc := NewService(NewRepository(db))
+	resp, err := svc.BulkImport(ctx, "u1", []models.BulkLibraryEntry{
+		{ExternalID: "2", Source: "mal", Title: "Berserk", Status: "reading", CurrentChapter: 350},
+		{ExternalID: "37517", Source: "mal", Title: "Kaguya sama love is war", Status: "completed", CurrentChapter: 281},
+		{ExternalID: "999999", Source: "mal", Title: "Not In The Database", Status: "on_hold", CurrentChapter: 4},
+		{ExternalID: "abc", Source: "mal"},
+		{ExternalID: "1", Source: "myspace"},
+	})
+	if err != nil {
+		t.Fatalf("BulkImport failed: %v", err)
+	}
+
+	want := []string{models.ImportRowUpdated, models.ImportRowInserted, models.ImportRowQueued, models.ImportRowFailed, models.ImportRowFailed}
+	for i, row := range resp.Rows {
+		if row.Result != want[i] {
+			t.Errorf("row %d: expected %s, got %s (%s)", i, want[i], row.Result, row.Error)
+		}
+	}
+	if resp.Rows[1].MangaID != "m-kaguya" || resp.Rows[1].MatchedBy != "title" {
+		t.Errorf("expected fuzzy title match to m-kaguya, got %+v", resp.Rows[1])
+	}
+
+	stats := resp.Stats
+	if stats.Total != 5 || stats.Inserted != 1 || stats.Updated != 1 || stats.Queued != 1 || stats.Failed != 2 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// The unknown manga must be kept for an external fetch, not dropped
+	var state string
+	var chapter int
+	err = db.QueryRow(`SELECT state, current_chapter FROM library_import_queue WHERE user_id = 'u1' AND source = 'mal' AND external_id = '999999'`).Scan(&state, &chapter)
+	if err != nil {
+		t.Fatalf("queued entry missing: %v", err)
+	}
+	if state != models.ImportQueuePending || chapter != 4 {
+		t.Errorf("unexpected queue entry: state=%s chapter=%d", state, chapter)
+	}
+}
+
+func TestBulkUpdateStatusReportsMissing(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`)
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u2', 'other', 'o@example.com', 'x', 'Other')`)
+	for _, id := range []string{"m1", "m2", "m3"} {
+		mustExec(t, db, `INSERT INTO manga (id, title, author) VALUES (?, ?, 'A')`, id, "Manga "+id)
+	}
+	mustExec(t, db, `INSERT INTO reading_progress (id, user_id, manga_id, current_chapter, status) VALUES ('p1', 'u1', 'm1', 12, 'on_hold')`)
+	mustExec(t, db, `INSERT INTO reading_progress (id, user_id, manga_id, current_chapter, status) VALUES ('p2', 'u1', 'm2', 3, 'on_hold')`)
+	// Someone else's entry must not be touched
+	mustExec(t, db, `INSERT INTO reading_progress (id, user_id, manga_id, current_chapter, status) VALUES ('p3', 'u2', 'm3', 1, 'on_hold')`)
+
+	svc := NewService(NewRepository(db))
+	resp, updated, err := svc.BulkUpdateStatus(ctx, "u1", models.BulkStatusRequest{
+		MangaIDs: []string{"m1", "m2", "m3", "m1"},
+		Status:   "dropped",
+	})
+	if err != nil {
+		t.Fatalf("BulkUpdateStatus failed: %v", err)
+	}
+	if len(resp.Updated) != 2 || resp.Updated[0] != "m1" || resp.Updated[1] != "m2" {
+		t.Errorf("updated = %v, want [m1 m2]", resp.Updated)
+	}
+	if len(resp.Failed) != 1 || resp.Failed[0].MangaID != "m3" {
+		t.Errorf("failed = %+v, want m3 only", resp.Failed)
+	}
+	if len(updated) != 2 || updated[0].CurrentChapter != 12 || updated[0].Status != "dropped" {
+		t.Errorf("unexpected updated rows: %+v", updated)
+	}
+
+	mustScan := func(id string) string {
+		t.Helper()
+		var status string
+		if err := db.QueryRow(`SELECT status FROM reading_progress WHERE id = ?`, id).Scan(&status); err != nil {
+			t.Fatalf("select %s: %v", id, err)
+		}
+		return status
+	}
+	if got := mustScan("p2"); got != "dropped" {
+		t.Errorf("p2 status = %s, want dropped", got)
+	}
+	if got := mustScan("p3"); got != "on_hold" {
+		t.Errorf("other user's entry changed to %s", got)
+	}
+
+	if _, _, err := svc.BulkUpdateStatus(ctx, "u1", models.BulkStatusRequest{MangaIDs: []string{"m1"}, Status: "shelved"}); err == nil {
+		t.Error("expected an invalid status to be rejected")
+	}
+}
@@ -0,0 +1,53 @@
+// Package progress - Catch Up Tests
+// Unit tests cho "mark all caught up": idempotent và không ghi trùng chapter history
+package progress
+
+import (
+	"context"
+	"testing"
+
+	"mangahub/internal/statistics"
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+func TestCatchUpRecordsOnlyMissingChapters(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`)
+	mustExec(t, db, `INSERT INTO manga (id, title, total_chapters) VALUES ('m1', 'Berserk', 10), ('m2', 'Vagabond', 0)`)
+	mustExec(t, db, `INSERT INTO reading_progress (id, user_id, manga_id, current_chapter, status, is_favorite) VALUES ('p1', 'u1', 'm1', 4, 'reading', 1)`)
+	// Chapter 6 was already read through the reader
+	mustExec(t, db, `INSERT INTO chapter_history (id, user_id, manga_id, chapter_number) VALUES ('h6', 'u1', 'm1', 6)`)
+
+	svc := NewServiceWithHistory(NewRepository(db), statistics.NewService(statistics.NewRepository(db)))
+
+	resp, err := svc.CatchUp(ctx, "u1", models.CatchUpRequest{MangaID: "m1"})
+	if err != nil {
+		t.Fatalf("CatchUp failed: %v", err)
+	}
+	p := resp.Progress
+	if p.CurrentChapter != 10 || p.Status != "completed" || !p.IsFavorite || resp.ChaptersRecorded != 5 {
+		t.Errorf("unexpected catch-up: %+v recorded %d, want ch 10 completed favorite with 5 recorded", p, resp.ChaptersRecorded)
+	}
+
+	// A second catch-up is a no-op
+	again, err := svc.CatchUp(ctx, "u1", models.CatchUpRequest{MangaID: "m1"})
+	if err != nil || !again.AlreadyCaughtUp || again.ChaptersRecorded != 0 {
+		t.Errorf("second CatchUp = %+v (%v), want already caught up", again, err)
+	}
+
+	var rows, chapters int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM chapter_history WHERE user_id = 'u1' AND manga_id = 'm1'`).Scan(&rows); err != nil || rows != 6 {
+		t.Errorf("chapter_history has %d rows (%v), want 6 (5..10 once each)", rows, err)
+	}
+	if err := db.QueryRow(`SELECT chapters_read FROM daily_stats WHERE user_id = 'u1'`).Scan(&chapters); err != nil || chapters != 5 {
+		t.Errorf("daily_stats counted %d chapters (%v), want 5", chapters, err)
+	}
+
+	// Without a known chapter count there is nothing to catch up to
+	if _, err := svc.CatchUp(ctx, "u1", models.CatchUpRequest{MangaID: "m2"}); err == nil {
+		t.Error("expected an error for a manga without total_chapters")
+	}
+}
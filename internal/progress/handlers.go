@@ -3,9 +3,11 @@ package progress
 import (
 	"context"
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"mangahub/internal/auth"
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/models"
 )
 
@@ -67,13 +69,7 @@ func (h *Handler) AddToLibrary(c *gin.Context) {
 
 	progress, err := h.svc.Update(c.Request.Context(), user.ID, req)
 	if err != nil {
-		if appErr, ok := err.(*models.AppError); ok {
-			c.JSON(appErr.StatusCode,
-				models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
-			return
-		}
-		c.JSON(http.StatusInternalServerError,
-			models.NewErrorResponse(models.ErrCodeInternal, "unexpected error", nil))
+		apperrors.Respond(c, err, "unexpected error")
 		return
 	}
 
@@ -82,6 +78,7 @@ func (h *Handler) AddToLibrary(c *gin.Context) {
 }
 
 // GET /users/library
+// Query params: favorites=true lists only favorites
 func (h *Handler) GetLibrary(c *gin.Context) {
 	user := auth.GetCurrentUser(c)
 	if user == nil {
@@ -90,15 +87,10 @@ func (h *Handler) GetLibrary(c *gin.Context) {
 		return
 	}
 
-	list, err := h.svc.List(c.Request.Context(), user.ID)
+	favoritesOnly, _ := strconv.ParseBool(c.Query("favorites"))
+	list, err := h.svc.List(c.Request.Context(), user.ID, favoritesOnly)
 	if err != nil {
-		if appErr, ok := err.(*models.AppError); ok {
-			c.JSON(appErr.StatusCode,
-				models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
-			return
-		}
-		c.JSON(http.StatusInternalServerError,
-			models.NewErrorResponse(models.ErrCodeInternal, "unexpected error", nil))
+		apperrors.Respond(c, err, "unexpected error")
 		return
 	}
 
@@ -106,6 +98,97 @@ func (h *Handler) GetLibrary(c *gin.Context) {
 		models.NewSuccessResponse(list, "user library"))
 }
 
+// GET /users/continue
+// Query params: limit (default 5, max 50)
+func (h *Handler) GetContinueReading(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "unauthorized", nil))
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", strconv.Itoa(models.DefaultContinueLimit)))
+	entries, err := h.svc.ContinueReading(c.Request.Context(), user.ID, limit)
+	if err != nil {
+		apperrors.Respond(c, err, "unexpected error")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(entries, "continue reading"))
+}
+
+// POST /users/library/bulk
+// Body: JSON array of {external_id, source, title?, status, current_chapter, is_favorite}
+func (h *Handler) BulkImportLibrary(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "unauthorized", nil))
+		return
+	}
+
+	var entries []models.BulkLibraryEntry
+	if err := c.ShouldBindJSON(&entries); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeBadRequest, "invalid JSON body", map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	result, err := h.svc.BulkImport(c.Request.Context(), user.ID, entries)
+	if err != nil {
+		apperrors.Respond(c, err, "unexpected error")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(result, "library import processed"))
+}
+
+// PUT /users/library/bulk-status
+// Body: {manga_ids, status}. Manga not in the library are listed in data.failed.
+func (h *Handler) BulkUpdateStatus(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "unauthorized", nil))
+		return
+	}
+
+	var req models.BulkStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeBadRequest, "invalid JSON body", map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	result, updated, err := h.svc.BulkUpdateStatus(c.Request.Context(), user.ID, req)
+	if err != nil {
+		apperrors.Respond(c, err, "unexpected error")
+		return
+	}
+
+	// 🔄 BRIDGE: Other clients see each moved entry. Like bulk imports, a
+	// cleanup is not announced in the activity feed.
+	if h.bridge != nil && len(updated) > 0 {
+		go func() {
+			for _, p := range updated {
+				_ = h.bridge.BroadcastProgressUpdate(
+					user.ID,
+					user.Username,
+					p.MangaID,
+					int32(p.CurrentChapter),
+					p.Status,
+				)
+			}
+		}()
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(result, "library status updated"))
+}
+
 // DELETE /users/library/:manga_id
 func (h *Handler) RemoveFromLibrary(c *gin.Context) {
 	user := auth.GetCurrentUser(c)
@@ -117,20 +200,13 @@ func (h *Handler) RemoveFromLibrary(c *gin.Context) {
 
 	mangaID := c.Param("manga_id")
 	if mangaID == "" {
-		c.JSON(http.StatusBadRequest,
-			models.NewErrorResponse(models.ErrCodeBadRequest, "manga_id is required", nil))
+		apperrors.Respond(c, apperrors.Validation("manga_id", "manga_id is required"), "")
 		return
 	}
 
 	err := h.svc.Delete(c.Request.Context(), user.ID, mangaID)
 	if err != nil {
-		if appErr, ok := err.(*models.AppError); ok {
-			c.JSON(appErr.StatusCode,
-				models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
-			return
-		}
-		c.JSON(http.StatusInternalServerError,
-			models.NewErrorResponse(models.ErrCodeInternal, "unexpected error", nil))
+		apperrors.Respond(c, err, "unexpected error")
 		return
 	}
 
@@ -141,7 +217,70 @@ func (h *Handler) RemoveFromLibrary(c *gin.Context) {
 		}, "manga removed from library"))
 }
 
+// PUT /users/progress/catchup
+// Body: {manga_id}. Sets progress to the manga's latest chapter and status to completed.
+func (h *Handler) CatchUpProgress(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "unauthorized", nil))
+		return
+	}
+
+	var req models.CatchUpRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeBadRequest, "invalid JSON body", map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	result, err := h.svc.CatchUp(c.Request.Context(), user.ID, req)
+	if err != nil {
+		apperrors.Respond(c, err, "unexpected error")
+		return
+	}
+
+	if result.AlreadyCaughtUp {
+		c.JSON(http.StatusOK,
+			models.NewSuccessResponse(result, "already caught up"))
+		return
+	}
+
+	progress := result.Progress
+	if h.bridge != nil {
+		go func() {
+			_ = h.bridge.BroadcastProgressUpdate(
+				user.ID,
+				user.Username,
+				progress.MangaID,
+				int32(progress.CurrentChapter),
+				progress.Status,
+			)
+		}()
+	}
+
+	// 🎉 ACTIVITY: Catching up completes the manga
+	if h.activityRecorder != nil && h.mangaSvc != nil {
+		go func() {
+			manga, err := h.mangaSvc.GetByID(context.Background(), progress.MangaID)
+			if err == nil {
+				_ = h.activityRecorder.RecordMangaCompleted(
+					context.Background(),
+					user.ID,
+					user.Username,
+					progress.MangaID,
+					manga.Title,
+				)
+			}
+		}()
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(result, "caught up to latest chapter"))
+}
+
 // PUT /users/progress
+// 409 Conflict (with the current row in error.details.current) when expected_updated_at is stale
 func (h *Handler) UpdateProgress(c *gin.Context) {
 	user := auth.GetCurrentUser(c)
 	if user == nil {
@@ -159,13 +298,7 @@ func (h *Handler) UpdateProgress(c *gin.Context) {
 
 	progress, err := h.svc.Update(c.Request.Context(), user.ID, req)
 	if err != nil {
-		if appErr, ok := err.(*models.AppError); ok {
-			c.JSON(appErr.StatusCode,
-				models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
-			return
-		}
-		c.JSON(http.StatusInternalServerError,
-			models.NewErrorResponse(models.ErrCodeInternal, "unexpected error", nil))
+		apperrors.Respond(c, err, "unexpected error")
 		return
 	}
 
@@ -185,10 +318,10 @@ func (h *Handler) UpdateProgress(c *gin.Context) {
 	// 📝 ACTIVITY: Record chapter read activity
 	if h.activityRecorder != nil && h.mangaSvc != nil && req.CurrentChapter > 0 {
 		go func() {
-			manga, err := h.mangaSvc.GetByID(c.Request.Context(), progress.MangaID)
+			manga, err := h.mangaSvc.GetByID(context.Background(), progress.MangaID)
 			if err == nil {
 				_ = h.activityRecorder.RecordChapterRead(
-					c.Request.Context(),
+					context.Background(),
 					user.ID,
 					user.Username,
 					progress.MangaID,
@@ -202,10 +335,10 @@ func (h *Handler) UpdateProgress(c *gin.Context) {
 	// 🎉 ACTIVITY: Record completion if manga is completed
 	if h.activityRecorder != nil && h.mangaSvc != nil && req.Status == "completed" {
 		go func() {
-			manga, err := h.mangaSvc.GetByID(c.Request.Context(), progress.MangaID)
+			manga, err := h.mangaSvc.GetByID(context.Background(), progress.MangaID)
 			if err == nil {
 				_ = h.activityRecorder.RecordMangaCompleted(
-					c.Request.Context(),
+					context.Background(),
 					user.ID,
 					user.Username,
 					progress.MangaID,
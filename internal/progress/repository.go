@@ -4,17 +4,41 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 
+	"mangahub/pkg/database"
 	"mangahub/pkg/models"
 
 	"github.com/google/uuid"
 )
 
 type Repository interface {
+	// AddOrUpdate upserts a progress row. If req.ExpectedUpdatedAt is older than the
+	// stored row, nothing is written and the current row is returned with ErrProgressConflict.
 	AddOrUpdate(ctx context.Context, userID string, req models.UpdateProgressRequest) (*models.ReadingProgress, error)
-	ListByUser(ctx context.Context, userID string) ([]models.ProgressWithManga, error)
+	// ListByUser returns the user's library, most recently read first;
+	// favoritesOnly keeps only entries marked as favorite
+	ListByUser(ctx context.Context, userID string, favoritesOnly bool) ([]models.ProgressWithManga, error)
 	Delete(ctx context.Context, userID, mangaID string) error
+	// UpdateStatuses sets status on the user's entries for mangaIDs in one
+	// transaction; IDs with no library entry are returned as missing
+	UpdateStatuses(ctx context.Context, userID string, mangaIDs []string, status string) (updated []models.ReadingProgress, missing []string, err error)
+	// Get returns a user's progress for a manga, nil if it is not in the library
+	Get(ctx context.Context, userID, mangaID string) (*models.ReadingProgress, error)
+	// GetTotalChapters returns a manga's chapter count; found is false if the manga does not exist
+	GetTotalChapters(ctx context.Context, mangaID string) (total int, found bool, err error)
+	ListUpdatedSince(ctx context.Context, userID string, since time.Time, limit int) ([]models.ReadingProgress, error)
+	// ListContinueReading returns up to limit entries with status reading, most recently read first
+	ListContinueReading(ctx context.Context, userID string, limit int) ([]models.ContinueReadingEntry, error)
+
+	// Bulk import helpers
+	Exists(ctx context.Context, userID, mangaID string) (bool, error)
+	FindMangaByExternalID(ctx context.Context, source, externalID string) (string, error)
+	FindMangaByTitle(ctx context.Context, title string) (string, error)
+	EnqueueImport(ctx context.Context, userID string, entry models.BulkLibraryEntry) error
 }
 
 type repository struct {
@@ -53,26 +77,49 @@ func (r *repository) AddOrUpdate(ctx context.Context, userID string, req models.
 		}
 		existingID = id
 	} else {
-		_, err = r.db.ExecContext(ctx, `
+		// Compare-and-set on updated_at when the client says what it last saw.
+		// julianday() normalizes the mixed timestamp formats SQLite may hold,
+		// but only to the millisecond, so the new updated_at must land at
+		// least 1ms after the one being replaced for later checks to see it.
+		var expected interface{}
+		if req.ExpectedUpdatedAt != nil {
+			expected = req.ExpectedUpdatedAt.UTC().Format("2006-01-02 15:04:05.000000000")
+			if next := req.ExpectedUpdatedAt.Add(time.Millisecond); now.Before(next) {
+				now = next
+			}
+		}
+		result, err := r.db.ExecContext(ctx, `
 			UPDATE reading_progress
 			SET current_chapter = ?, status = ?, is_favorite = ?, 
 			    last_read_at = ?, updated_at = ?
-			WHERE id = ?`,
-			req.CurrentChapter, req.Status, req.IsFavorite, now, now, existingID,
+			WHERE id = ? AND (? IS NULL OR julianday(updated_at) <= julianday(?))`,
+			req.CurrentChapter, req.Status, req.IsFavorite, now, now, existingID, expected, expected,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("update progress: %w", err)
 		}
+		if n, _ := result.RowsAffected(); n == 0 {
+			current, err := r.getByID(ctx, existingID)
+			if err != nil {
+				return nil, err
+			}
+			return current, models.ErrProgressConflict
+		}
 	}
 
+	return r.getByID(ctx, existingID)
+}
+
+// getByID loads a single progress row
+func (r *repository) getByID(ctx context.Context, id string) (*models.ReadingProgress, error) {
 	row := r.db.QueryRowContext(ctx, `
 		SELECT id, user_id, manga_id, current_chapter, status,
 		       is_favorite, started_at, completed_at,
 		       last_read_at, created_at, updated_at
-		FROM reading_progress WHERE id = ?`, existingID)
+		FROM reading_progress WHERE id = ?`, id)
 
 	var p models.ReadingProgress
-	err = row.Scan(
+	err := row.Scan(
 		&p.ID, &p.UserID, &p.MangaID, &p.CurrentChapter, &p.Status,
 		&p.IsFavorite, &p.StartedAt, &p.CompletedAt,
 		&p.LastReadAt, &p.CreatedAt, &p.UpdatedAt,
@@ -83,7 +130,44 @@ func (r *repository) AddOrUpdate(ctx context.Context, userID string, req models.
 	return &p, nil
 }
 
-func (r *repository) ListByUser(ctx context.Context, userID string) ([]models.ProgressWithManga, error) {
+// Get returns a user's progress for a manga, nil if it is not in the library
+func (r *repository) Get(ctx context.Context, userID, mangaID string) (*models.ReadingProgress, error) {
+	var id string
+	err := r.db.QueryRowContext(ctx,
+		"SELECT id FROM reading_progress WHERE user_id = ? AND manga_id = ?",
+		userID, mangaID,
+	).Scan(&id)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("check progress: %w", err)
+	}
+	return r.getByID(ctx, id)
+}
+
+// GetTotalChapters returns a manga's chapter count; found is false if the manga does not exist
+func (r *repository) GetTotalChapters(ctx context.Context, mangaID string) (int, bool, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx,
+		"SELECT COALESCE(total_chapters, 0) FROM manga WHERE id = ?", mangaID,
+	).Scan(&total)
+	if err == sql.ErrNoRows {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, fmt.Errorf("get total chapters: %w", err)
+	}
+	return total, true, nil
+}
+
+func (r *repository) ListByUser(ctx context.Context, userID string, favoritesOnly bool) ([]models.ProgressWithManga, error) {
+	// Favorites are a small subset, read through the partial index
+	// idx_progress_favorite instead of the user's whole library
+	from, where := "reading_progress r", "r.user_id = ?"
+	if favoritesOnly {
+		from, where = "reading_progress r INDEXED BY idx_progress_favorite", "r.is_favorite = 1 AND r.user_id = ?"
+	}
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT
 			r.id, r.user_id, r.manga_id, r.current_chapter, r.status,
@@ -92,9 +176,9 @@ func (r *repository) ListByUser(ctx context.Context, userID string) ([]models.Pr
 			m.id, m.title, m.author, m.artist, m.description, m.cover_url,
 			m.status, m.type, m.total_chapters, m.average_rating, m.rating_count, m.year,
 			m.created_at, m.updated_at
-		FROM reading_progress r
+		FROM `+from+`
 		JOIN manga m ON r.manga_id = m.id
-		WHERE r.user_id = ?
+		WHERE `+where+`
 		ORDER BY r.last_read_at DESC`, userID)
 	if err != nil {
 		return nil, fmt.Errorf("list progress: %w", err)
@@ -115,16 +199,50 @@ func (r *repository) ListByUser(ctx context.Context, userID string) ([]models.Pr
 		); err != nil {
 			return nil, fmt.Errorf("scan: %w", err)
 		}
-		// Load genres for manga
-		m.Genres = r.loadGenresForManga(ctx, m.ID)
 		result = append(result, models.ProgressWithManga{
 			ReadingProgress: p,
 			Manga:           m,
 		})
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list progress: %w", err)
+	}
+	rows.Close()
+
+	// Genres are loaded once the rows are released, so a single-connection pool cannot deadlock
+	for i := range result {
+		result[i].Manga.Genres = r.loadGenresForManga(ctx, result[i].Manga.ID)
+	}
 	return result, nil
 }
 
+func (r *repository) ListContinueReading(ctx context.Context, userID string, limit int) ([]models.ContinueReadingEntry, error) {
+	// Walks idx_progress_last_read (user_id, last_read_at DESC) and stops after limit rows
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT r.manga_id, m.title, COALESCE(m.cover_url, ''), COALESCE(m.status, ''),
+		       r.current_chapter, COALESCE(m.total_chapters, 0), r.last_read_at, r.updated_at
+		FROM reading_progress r INDEXED BY idx_progress_last_read
+		JOIN manga m ON r.manga_id = m.id
+		WHERE r.user_id = ? AND r.status = 'reading'
+		ORDER BY r.last_read_at DESC
+		LIMIT ?`, userID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list continue reading: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []models.ContinueReadingEntry{}
+	for rows.Next() {
+		var e models.ContinueReadingEntry
+		if err := rows.Scan(&e.MangaID, &e.Title, &e.CoverURL, &e.MangaStatus,
+			&e.CurrentChapter, &e.TotalChapters, &e.LastReadAt, &e.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan continue reading: %w", err)
+		}
+		entries = append(entries, e)
+	}
+	return entries, rows.Err()
+}
+
 // loadGenresForManga loads all genres for a manga from the manga_genres junction table
 func (r *repository) loadGenresForManga(ctx context.Context, mangaID string) []models.Genre {
 	rows, err := r.db.QueryContext(ctx, `
@@ -149,6 +267,44 @@ func (r *repository) loadGenresForManga(ctx context.Context, mangaID string) []m
 	return genres
 }
 
+func (r *repository) UpdateStatuses(ctx context.Context, userID string, mangaIDs []string, status string) ([]models.ReadingProgress, []string, error) {
+	var updated []models.ReadingProgress
+	var missing []string
+	err := database.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		// WithTx may run this again after a busy error
+		updated, missing = nil, nil
+		now := time.Now()
+		for _, mangaID := range mangaIDs {
+			var p models.ReadingProgress
+			err := tx.QueryRowContext(ctx, `
+				UPDATE reading_progress SET status = ?, updated_at = ?
+				WHERE user_id = ? AND manga_id = ?
+				RETURNING id, user_id, manga_id, current_chapter, status,
+				          is_favorite, started_at, completed_at,
+				          last_read_at, created_at, updated_at`,
+				status, now, userID, mangaID,
+			).Scan(
+				&p.ID, &p.UserID, &p.MangaID, &p.CurrentChapter, &p.Status,
+				&p.IsFavorite, &p.StartedAt, &p.CompletedAt,
+				&p.LastReadAt, &p.CreatedAt, &p.UpdatedAt,
+			)
+			if err == sql.ErrNoRows {
+				missing = append(missing, mangaID)
+				continue
+			}
+			if err != nil {
+				return fmt.Errorf("update status of %s: %w", mangaID, err)
+			}
+			updated = append(updated, p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return updated, missing, nil
+}
+
 // Delete removes a manga from user's library
 func (r *repository) Delete(ctx context.Context, userID, mangaID string) error {
 	result, err := r.db.ExecContext(ctx,
@@ -168,3 +324,160 @@ func (r *repository) Delete(ctx context.Context, userID, mangaID string) error {
 	}
 	return nil
 }
+
+// ListUpdatedSince returns a user's progress rows changed after since, oldest change first.
+// julianday() normalizes the mixed timestamp formats SQLite may hold.
+func (r *repository) ListUpdatedSince(ctx context.Context, userID string, since time.Time, limit int) ([]models.ReadingProgress, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, manga_id, current_chapter, status,
+		       is_favorite, started_at, completed_at,
+		       last_read_at, created_at, updated_at
+		FROM reading_progress
+		WHERE user_id = ? AND julianday(updated_at) > julianday(?)
+		ORDER BY julianday(updated_at) ASC, id ASC
+		LIMIT ?`,
+		userID, since.UTC().Format("2006-01-02 15:04:05.000"), limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("list progress since: %w", err)
+	}
+	defer rows.Close()
+
+	var list []models.ReadingProgress
+	for rows.Next() {
+		var p models.ReadingProgress
+		if err := rows.Scan(
+			&p.ID, &p.UserID, &p.MangaID, &p.CurrentChapter, &p.Status,
+			&p.IsFavorite, &p.StartedAt, &p.CompletedAt,
+			&p.LastReadAt, &p.CreatedAt, &p.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan progress: %w", err)
+		}
+		list = append(list, p)
+	}
+	return list, rows.Err()
+}
+
+// Exists reports whether the manga is already in the user's library
+func (r *repository) Exists(ctx context.Context, userID, mangaID string) (bool, error) {
+	var n int
+	err := r.db.QueryRowContext(ctx,
+		"SELECT COUNT(*) FROM reading_progress WHERE user_id = ? AND manga_id = ?",
+		userID, mangaID,
+	).Scan(&n)
+	if err != nil {
+		return false, fmt.Errorf("check progress: %w", err)
+	}
+	return n > 0, nil
+}
+
+// externalIDColumns maps an import source to its manga_external_ids column.
+// MAL and AniList IDs are stored as integers.
+var externalIDColumns = map[string]struct {
+	column  string
+	numeric bool
+}{
+	models.ImportSourceMAL: {"mal_id", true},
+	models.SourceAniList:   {"anilist_id", true},
+	models.SourceMangaDex:  {"mangadex_id", false},
+	models.SourceKitsu:     {"kitsu_id", false},
+}
+
+// FindMangaByExternalID resolves an external ID to a local manga ID.
+// Returns "" when no mapping exists.
+func (r *repository) FindMangaByExternalID(ctx context.Context, source, externalID string) (string, error) {
+	col, ok := externalIDColumns[source]
+	if !ok {
+		return "", fmt.Errorf("unknown source %q", source)
+	}
+
+	var arg interface{} = externalID
+	if col.numeric {
+		n, err := strconv.ParseInt(externalID, 10, 64)
+		if err != nil {
+			return "", fmt.Errorf("%s id must be numeric", source)
+		}
+		arg = n
+	}
+
+	var mangaID string
+	err := r.db.QueryRowContext(ctx,
+		"SELECT manga_id FROM manga_external_ids WHERE "+col.column+" = ? LIMIT 1", arg,
+	).Scan(&mangaID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("find external id: %w", err)
+	}
+	return mangaID, nil
+}
+
+// FindMangaByTitle returns the best FTS match for a title, or "" when nothing matches.
+// Every word must appear, in any order, so punctuation and word order differences
+// between platforms still match.
+func (r *repository) FindMangaByTitle(ctx context.Context, title string) (string, error) {
+	query := ftsAllWords(title)
+	if query == "" {
+		return "", nil
+	}
+
+	var mangaID string
+	err := r.db.QueryRowContext(ctx, `
+		SELECT m.id
+		FROM manga_fts
+		JOIN manga m ON m.rowid = manga_fts.rowid
+		WHERE manga_fts MATCH ?
+		ORDER BY rank
+		LIMIT 1`, "title : ("+query+")",
+	).Scan(&mangaID)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", fmt.Errorf("find manga by title: %w", err)
+	}
+	return mangaID, nil
+}
+
+// ftsAllWords turns free text into an FTS5 query of quoted words,
+// which keeps user input from being parsed as FTS syntax.
+func ftsAllWords(text string) string {
+	words := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) > 8 {
+		words = words[:8]
+	}
+	for i, w := range words {
+		words[i] = `"` + w + `"`
+	}
+	return strings.Join(words, " ")
+}
+
+// EnqueueImport stores an entry whose manga must be fetched first.
+// Re-importing the same entry refreshes it and makes it pending again.
+func (r *repository) EnqueueImport(ctx context.Context, userID string, entry models.BulkLibraryEntry) error {
+	now := time.Now()
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO library_import_queue
+		(id, user_id, source, external_id, title, status, current_chapter, is_favorite,
+		 state, last_error, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, '', ?, ?)
+		ON CONFLICT(user_id, source, external_id) DO UPDATE SET
+			title = excluded.title,
+			status = excluded.status,
+			current_chapter = excluded.current_chapter,
+			is_favorite = excluded.is_favorite,
+			state = excluded.state,
+			last_error = '',
+			updated_at = excluded.updated_at`,
+		uuid.New().String(), userID, entry.Source, entry.ExternalID, entry.Title,
+		entry.Status, entry.CurrentChapter, entry.IsFavorite,
+		models.ImportQueuePending, now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("enqueue import: %w", err)
+	}
+	return nil
+}
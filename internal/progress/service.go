@@ -2,50 +2,287 @@
 // Xử lý logic theo dõi tiến độ đọc truyện của user
 // Chức năng:
 //   - Update reading progress (chapter, status, rating)
+//   - Optimistic concurrency: 409 kèm state hiện tại nếu row đã đổi từ lần client đọc
 //   - List user's manga library với progress
+//   - Continue reading: manga đang đọc, mới đọc gần nhất trước
 //   - Trigger protocol bridge khi có update
 //   - Manage reading history
+//   - Bulk import library từ nền tảng khác (MAL, MangaDex, ...)
+//   - Catch up: nhảy tới chapter mới nhất, ghi chapter history cho phần chưa đọc
+//   - Bulk status: đổi status nhiều manga trong một transaction, báo lại manga không đổi được
 package progress
 
 import (
 	"context"
+	"errors"
+	"fmt"
 
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/models"
 	"mangahub/pkg/utils"
 )
 
 type Service interface {
 	Update(ctx context.Context, userID string, req models.UpdateProgressRequest) (*models.ReadingProgress, error)
-	List(ctx context.Context, userID string) ([]models.ProgressWithManga, error)
+	List(ctx context.Context, userID string, favoritesOnly bool) ([]models.ProgressWithManga, error)
+	// ContinueReading returns up to limit manga the user is reading, most recently read first
+	ContinueReading(ctx context.Context, userID string, limit int) ([]models.ContinueReadingEntry, error)
 	Delete(ctx context.Context, userID, mangaID string) error
+	BulkImport(ctx context.Context, userID string, entries []models.BulkLibraryEntry) (*models.BulkImportLibraryResponse, error)
+	CatchUp(ctx context.Context, userID string, req models.CatchUpRequest) (*models.CatchUpResponse, error)
+	// BulkUpdateStatus moves many library entries to one status. The updated
+	// rows are returned too, for broadcasting.
+	BulkUpdateStatus(ctx context.Context, userID string, req models.BulkStatusRequest) (*models.BulkStatusResponse, []models.ReadingProgress, error)
+}
+
+// HistoryRecorder records chapter reads for reading statistics
+type HistoryRecorder interface {
+	RecordChapterRange(ctx context.Context, userID, mangaID string, from, to int) (int, error)
 }
 
 type service struct {
-	repo Repository
+	repo    Repository
+	history HistoryRecorder // optional
 }
 
 func NewService(repo Repository) Service {
 	return &service{repo: repo}
 }
 
+// NewServiceWithHistory creates a progress service that records the chapters
+// skipped by a catch-up in the chapter history
+func NewServiceWithHistory(repo Repository, history HistoryRecorder) Service {
+	return &service{repo: repo, history: history}
+}
+
 func (s *service) Update(ctx context.Context, userID string, req models.UpdateProgressRequest) (*models.ReadingProgress, error) {
 	if err := utils.ValidateStruct(req); err != nil {
-		return nil, models.NewAppError(models.ErrCodeValidation, "invalid progress data", 400, err)
+		return nil, apperrors.Invalid("invalid progress data", err)
+	}
+	progress, err := s.repo.AddOrUpdate(ctx, userID, req)
+	if errors.Is(err, models.ErrProgressConflict) {
+		appErr := apperrors.Conflict("progress was updated elsewhere", err)
+		appErr.Details["current"] = progress
+		return nil, appErr
 	}
-	return s.repo.AddOrUpdate(ctx, userID, req)
+	return progress, err
 }
 
-func (s *service) List(ctx context.Context, userID string) ([]models.ProgressWithManga, error) {
-	return s.repo.ListByUser(ctx, userID)
+func (s *service) List(ctx context.Context, userID string, favoritesOnly bool) ([]models.ProgressWithManga, error) {
+	return s.repo.ListByUser(ctx, userID, favoritesOnly)
+}
+
+func (s *service) ContinueReading(ctx context.Context, userID string, limit int) ([]models.ContinueReadingEntry, error) {
+	if limit <= 0 {
+		limit = models.DefaultContinueLimit
+	}
+	if limit > models.MaxContinueLimit {
+		limit = models.MaxContinueLimit
+	}
+	entries, err := s.repo.ListContinueReading(ctx, userID, limit)
+	if err != nil {
+		return nil, apperrors.Internal("failed to list continue reading", err)
+	}
+	return entries, nil
 }
 
 func (s *service) Delete(ctx context.Context, userID, mangaID string) error {
 	if mangaID == "" {
-		return models.NewAppError(models.ErrCodeValidation, "manga_id is required", 400, nil)
+		return apperrors.Validation("manga_id", "manga_id is required")
 	}
 	err := s.repo.Delete(ctx, userID, mangaID)
 	if err != nil {
-		return models.NewAppError(models.ErrCodeNotFound, "manga not found in library", 404, err)
+		return apperrors.NotFound("manga not found in library", err)
 	}
 	return nil
 }
+
+// CatchUp sets a manga's progress to its latest chapter and marks it completed.
+// The chapters between the old and new progress that have no history entry yet
+// are recorded, so reading stats count them. Calling it again changes nothing.
+// History is written before progress: if the progress update fails, a retry
+// starts from the same chapter and the recorder skips what it already added.
+func (s *service) CatchUp(ctx context.Context, userID string, req models.CatchUpRequest) (*models.CatchUpResponse, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, apperrors.Invalid("invalid catch-up request", err)
+	}
+
+	total, found, err := s.repo.GetTotalChapters(ctx, req.MangaID)
+	if err != nil {
+		return nil, err
+	}
+	if !found {
+		return nil, apperrors.NotFound("manga not found", nil)
+	}
+	if total <= 0 {
+		return nil, apperrors.Validation("manga_id", "manga has no known chapter count")
+	}
+
+	current, err := s.repo.Get(ctx, userID, req.MangaID)
+	if err != nil {
+		return nil, err
+	}
+	from, isFavorite := 1, false
+	if current != nil {
+		if current.CurrentChapter >= total && current.Status == "completed" {
+			return &models.CatchUpResponse{Progress: current, AlreadyCaughtUp: true}, nil
+		}
+		from, isFavorite = current.CurrentChapter+1, current.IsFavorite
+	}
+
+	resp := &models.CatchUpResponse{}
+	if s.history != nil && from <= total {
+		if resp.ChaptersRecorded, err = s.history.RecordChapterRange(ctx, userID, req.MangaID, from, total); err != nil {
+			return nil, err
+		}
+	}
+
+	// Progress never moves backwards when the catalogue's count is behind the user
+	chapter := total
+	if current != nil && current.CurrentChapter > total {
+		chapter = current.CurrentChapter
+	}
+	resp.Progress, err = s.repo.AddOrUpdate(ctx, userID, models.UpdateProgressRequest{
+		MangaID:        req.MangaID,
+		CurrentChapter: chapter,
+		Status:         "completed",
+		IsFavorite:     isFavorite,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return resp, nil
+}
+
+// BulkUpdateStatus changes the status of every listed manga in one transaction.
+// A manga that is not in the library does not stop the others; it is reported
+// in Failed. Repeated IDs are only updated once.
+func (s *service) BulkUpdateStatus(ctx context.Context, userID string, req models.BulkStatusRequest) (*models.BulkStatusResponse, []models.ReadingProgress, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, nil, apperrors.Invalid("invalid bulk status request", err)
+	}
+	if len(req.MangaIDs) > models.MaxBulkStatusMangaIDs {
+		return nil, nil, apperrors.Validation("manga_ids", fmt.Sprintf("at most %d manga per update", models.MaxBulkStatusMangaIDs))
+	}
+
+	ids := make([]string, 0, len(req.MangaIDs))
+	seen := make(map[string]bool, len(req.MangaIDs))
+	for _, id := range req.MangaIDs {
+		if !seen[id] {
+			seen[id] = true
+			ids = append(ids, id)
+		}
+	}
+
+	updated, missing, err := s.repo.UpdateStatuses(ctx, userID, ids, req.Status)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	resp := &models.BulkStatusResponse{
+		Status:  req.Status,
+		Updated: make([]string, 0, len(updated)),
+		Failed:  make([]models.BulkStatusFailure, 0, len(missing)),
+	}
+	for _, p := range updated {
+		resp.Updated = append(resp.Updated, p.MangaID)
+	}
+	for _, id := range missing {
+		resp.Failed = append(resp.Failed, models.BulkStatusFailure{MangaID: id, Error: "not in library"})
+	}
+	return resp, updated, nil
+}
+
+// BulkImport adds many library entries at once.
+// Each entry is resolved through manga_external_ids, then by title. Entries whose
+// manga is not in the DB are queued for an external fetch instead of dropped.
+// A bad row never fails the whole import; it is reported in the row results.
+func (s *service) BulkImport(ctx context.Context, userID string, entries []models.BulkLibraryEntry) (*models.BulkImportLibraryResponse, error) {
+	if len(entries) == 0 {
+		return nil, apperrors.Validation("entries", "at least one entry is required")
+	}
+	if len(entries) > models.MaxBulkImportEntries {
+		return nil, apperrors.Validation("entries", fmt.Sprintf("at most %d entries per import", models.MaxBulkImportEntries))
+	}
+
+	resp := &models.BulkImportLibraryResponse{
+		Rows: make([]models.BulkImportRowResult, 0, len(entries)),
+	}
+	for i, entry := range entries {
+		row := s.importEntry(ctx, userID, entry)
+		row.Index = i
+		resp.Rows = append(resp.Rows, row)
+
+		resp.Stats.Total++
+		switch row.Result {
+		case models.ImportRowInserted:
+			resp.Stats.Inserted++
+		case models.ImportRowUpdated:
+			resp.Stats.Updated++
+		case models.ImportRowQueued:
+			resp.Stats.Queued++
+		default:
+			resp.Stats.Failed++
+		}
+	}
+	return resp, nil
+}
+
+// importEntry resolves and stores a single bulk import row
+func (s *service) importEntry(ctx context.Context, userID string, entry models.BulkLibraryEntry) models.BulkImportRowResult {
+	row := models.BulkImportRowResult{ExternalID: entry.ExternalID, Source: entry.Source}
+	fail := func(err error) models.BulkImportRowResult {
+		row.Result = models.ImportRowFailed
+		row.Error = err.Error()
+		return row
+	}
+
+	if err := utils.ValidateStruct(entry); err != nil {
+		return fail(err)
+	}
+	if entry.Status == "" {
+		entry.Status = "plan_to_read"
+	}
+
+	mangaID, err := s.repo.FindMangaByExternalID(ctx, entry.Source, entry.ExternalID)
+	if err != nil {
+		return fail(err)
+	}
+	row.MatchedBy = "external_id"
+	if mangaID == "" && entry.Title != "" {
+		if mangaID, err = s.repo.FindMangaByTitle(ctx, entry.Title); err != nil {
+			return fail(err)
+		}
+		row.MatchedBy = "title"
+	}
+
+	if mangaID == "" {
+		if err := s.repo.EnqueueImport(ctx, userID, entry); err != nil {
+			return fail(err)
+		}
+		row.MatchedBy = ""
+		row.Result = models.ImportRowQueued
+		return row
+	}
+
+	existed, err := s.repo.Exists(ctx, userID, mangaID)
+	if err != nil {
+		return fail(err)
+	}
+	_, err = s.repo.AddOrUpdate(ctx, userID, models.UpdateProgressRequest{
+		MangaID:        mangaID,
+		CurrentChapter: entry.CurrentChapter,
+		Status:         entry.Status,
+		IsFavorite:     entry.IsFavorite,
+	})
+	if err != nil {
+		return fail(err)
+	}
+
+	row.MangaID = mangaID
+	row.Result = models.ImportRowInserted
+	if existed {
+		row.Result = models.ImportRowUpdated
+	}
+	return row
+}
@@ -0,0 +1,99 @@
+// Package progress - Graceful Shutdown Test
+// POST /users/library đang chạy vẫn phải hoàn tất khi server gọi Shutdown
+package progress
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+
+	"mangahub/internal/auth"
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+// slowService holds Update until released, keeping the request in flight
+type slowService struct {
+	Service
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (s *slowService) Update(ctx context.Context, userID string, req models.UpdateProgressRequest) (*models.ReadingProgress, error) {
+	close(s.entered)
+	<-s.release
+	return s.Service.Update(ctx, userID, req)
+}
+
+func TestAddToLibraryCompletesDuringShutdown(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	db := testutil.OpenDB(t)
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`)
+	mustExec(t, db, `INSERT INTO manga (id, title) VALUES ('m1', 'Berserk')`)
+
+	svc := &slowService{
+		Service: NewService(NewRepository(db)),
+		entered: make(chan struct{}),
+		release: make(chan struct{}),
+	}
+	router := gin.New()
+	router.POST("/users/library", func(c *gin.Context) {
+		c.Set(auth.ContextUserKey, &models.UserProfile{ID: "u1"})
+	}, NewHandler(svc).AddToLibrary)
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	srv := &http.Server{Handler: router}
+	go srv.Serve(ln)
+
+	status := make(chan int, 1)
+	go func() {
+		resp, err := http.Post("http://"+ln.Addr().String()+"/users/library", "application/json",
+			strings.NewReader(`{"manga_id":"m1","current_chapter":1,"status":"reading"}`))
+		if err != nil {
+			t.Errorf("request failed: %v", err)
+			status <- 0
+			return
+		}
+		resp.Body.Close()
+		status <- resp.StatusCode
+	}()
+
+	select {
+	case <-svc.entered:
+	case <-time.After(5 * time.Second):
+		t.Fatal("request never reached the handler")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	shutdown := make(chan error, 1)
+	go func() { shutdown <- srv.Shutdown(ctx) }()
+
+	// Shutdown closes the listener first; release the request only after that
+	for deadline := time.Now().Add(5 * time.Second); ; time.Sleep(5 * time.Millisecond) {
+		conn, err := net.Dial("tcp", ln.Addr().String())
+		if err != nil {
+			break
+		}
+		conn.Close()
+		if time.Now().After(deadline) {
+			t.Fatal("server kept accepting connections after Shutdown")
+		}
+	}
+	close(svc.release)
+
+	if code := <-status; code != http.StatusCreated {
+		t.Fatalf("in-flight request got status %d, want 201", code)
+	}
+	if err := <-shutdown; err != nil {
+		t.Fatalf("Shutdown returned %v", err)
+	}
+}
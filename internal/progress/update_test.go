@@ -0,0 +1,153 @@
+// Package progress - Optimistic Concurrency Tests
+// Unit tests cho expected_updated_at: hai thiết bị cùng update một manga
+package progress
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+func TestConcurrentUpdatesDoNotClobber(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`)
+	mustExec(t, db, `INSERT INTO manga (id, title) VALUES ('m1', 'Berserk')`)
+
+	svc := NewService(NewRepository(db))
+	base, err := svc.Update(ctx, "u1", models.UpdateProgressRequest{MangaID: "m1", CurrentChapter: 3, Status: "reading"})
+	if err != nil {
+		t.Fatalf("initial Update failed: %v", err)
+	}
+
+	// Both devices last saw the same row and write at the same time
+	seen := base.UpdatedAt
+	chapters := []int{10, 5}
+	errs := make([]error, len(chapters))
+	var wg sync.WaitGroup
+	for i, ch := range chapters {
+		wg.Add(1)
+		go func(i, ch int) {
+			defer wg.Done()
+			_, errs[i] = svc.Update(ctx, "u1", models.UpdateProgressRequest{
+				MangaID: "m1", CurrentChapter: ch, Status: "reading", ExpectedUpdatedAt: &seen,
+			})
+		}(i, ch)
+	}
+	wg.Wait()
+
+	// Exactly one write wins; the other gets a 409 with the winner's row
+	var conflict *models.AppError
+	for i, err := range errs {
+		if err == nil {
+			continue
+		}
+		if conflict != nil || !errors.As(err, &conflict) || conflict.StatusCode != 409 {
+			t.Fatalf("expected exactly one 409, got %v", errs)
+		}
+		current, ok := conflict.Details["current"].(*models.ReadingProgress)
+		if !ok || current.CurrentChapter != chapters[1-i] {
+			t.Fatalf("expected the conflict to carry the winning chapter %d, got %+v", chapters[1-i], conflict.Details["current"])
+		}
+
+		// The losing device merges by taking the max chapter and retries
+		merged := max(chapters[i], current.CurrentChapter)
+		if _, err := svc.Update(ctx, "u1", models.UpdateProgressRequest{
+			MangaID: "m1", CurrentChapter: merged, Status: "reading", ExpectedUpdatedAt: &current.UpdatedAt,
+		}); err != nil {
+			t.Fatalf("retry after merge failed: %v", err)
+		}
+	}
+	if conflict == nil {
+		t.Fatal("expected one of the concurrent updates to conflict")
+	}
+
+	var chapter int
+	db.QueryRow(`SELECT current_chapter FROM reading_progress WHERE user_id = 'u1' AND manga_id = 'm1'`).Scan(&chapter)
+	if chapter != 10 {
+		t.Errorf("expected chapter 10 to survive, got %d", chapter)
+	}
+
+	// Without a precondition the update is a plain upsert
+	if _, err := svc.Update(ctx, "u1", models.UpdateProgressRequest{MangaID: "m1", CurrentChapter: 11, Status: "reading"}); err != nil {
+		t.Errorf("unconditional Update failed: %v", err)
+	}
+}
+
+func TestListFavoritesOnly(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`)
+	mustExec(t, db, `INSERT INTO manga (id, title, author, artist, description, cover_url, status, type, year) VALUES
+		('m1', 'Berserk', 'Miura', 'Miura', '', '', 'completed', 'manga', 1989),
+		('m2', 'Vagabond', 'Inoue', 'Inoue', '', '', 'hiatus', 'manga', 1998)`)
+
+	svc := NewService(NewRepository(db))
+	for _, req := range []models.UpdateProgressRequest{
+		{MangaID: "m1", Status: "reading", IsFavorite: true},
+		{MangaID: "m2", Status: "reading"},
+	} {
+		if _, err := svc.Update(ctx, "u1", req); err != nil {
+			t.Fatalf("Update %s: %v", req.MangaID, err)
+		}
+	}
+
+	favorites, err := svc.List(ctx, "u1", true)
+	if err != nil {
+		t.Fatalf("List favorites: %v", err)
+	}
+	if len(favorites) != 1 || favorites[0].MangaID != "m1" {
+		t.Fatalf("favorites = %+v, want only m1", favorites)
+	}
+
+	// A status change keeps the favorite flag when the client sends it back
+	if _, err := svc.Update(ctx, "u1", models.UpdateProgressRequest{MangaID: "m1", Status: "completed", IsFavorite: true}); err != nil {
+		t.Fatalf("status change: %v", err)
+	}
+	all, err := svc.List(ctx, "u1", false)
+	if err != nil {
+		t.Fatalf("List: %v", err)
+	}
+	if len(all) != 2 {
+		t.Fatalf("library has %d entries, want 2", len(all))
+	}
+	if favorites, _ = svc.List(ctx, "u1", true); len(favorites) != 1 || favorites[0].Status != "completed" {
+		t.Errorf("favorites after status change = %+v", favorites)
+	}
+}
+
+func TestContinueReading(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`)
+	mustExec(t, db, `INSERT INTO manga (id, title, total_chapters) VALUES
+		('m1', 'Berserk', 374), ('m2', 'Vagabond', 327), ('m3', 'Monster', 162), ('m4', 'Pluto', 65)`)
+	mustExec(t, db, `INSERT INTO reading_progress (id, user_id, manga_id, current_chapter, status, last_read_at) VALUES
+		('p1', 'u1', 'm1', 100, 'reading', '2026-01-01 10:00:00'),
+		('p2', 'u1', 'm2', 50, 'reading', '2026-01-03 10:00:00'),
+		('p3', 'u1', 'm3', 162, 'completed', '2026-01-04 10:00:00'),
+		('p4', 'u1', 'm4', 20, 'dropped', '2026-01-05 10:00:00')`)
+
+	svc := NewService(NewRepository(db))
+	entries, err := svc.ContinueReading(ctx, "u1", 0)
+	if err != nil {
+		t.Fatalf("ContinueReading: %v", err)
+	}
+	if len(entries) != 2 || entries[0].MangaID != "m2" || entries[1].MangaID != "m1" {
+		t.Fatalf("entries = %+v, want m2 then m1 (completed and dropped excluded)", entries)
+	}
+	if entries[0].TotalChapters != 327 || entries[0].CurrentChapter != 50 || entries[0].Title != "Vagabond" {
+		t.Errorf("entry missing progress fields: %+v", entries[0])
+	}
+
+	if entries, _ = svc.ContinueReading(ctx, "u1", 1); len(entries) != 1 || entries[0].MangaID != "m2" {
+		t.Errorf("limit 1 = %+v", entries)
+	}
+}
@@ -0,0 +1,57 @@
+// Package protocols - Reconnect Backoff
+// Exponential backoff cho vòng lặp reconnect TCP/gRPC của bridge
+// Log thưa dần (attempt 1, 2, 4, 8, ...) để backend chập chờn không spam log
+package protocols
+
+import (
+	"context"
+	"time"
+)
+
+// backoff tracks consecutive failed connection attempts
+type backoff struct {
+	policy   RetryPolicy
+	attempts int
+	delay    time.Duration
+}
+
+func newBackoff(policy RetryPolicy) *backoff {
+	return &backoff{policy: policy, delay: policy.InitialBackoff}
+}
+
+// next records a failed attempt and doubles the delay up to the cap
+func (b *backoff) next() {
+	b.attempts++
+	b.delay *= 2
+	if b.delay > b.policy.MaxBackoff {
+		b.delay = b.policy.MaxBackoff
+	}
+}
+
+// reset starts over after a successful connection
+func (b *backoff) reset() {
+	b.attempts = 0
+	b.delay = b.policy.InitialBackoff
+}
+
+// shouldLog reports whether the upcoming attempt is worth a log line:
+// the 1st, 2nd, 4th, 8th, ... failure in a row
+func (b *backoff) shouldLog() bool {
+	n := b.attempts + 1
+	return n&(n-1) == 0
+}
+
+// wait sleeps for the current delay, then records the failed attempt.
+// It returns false if ctx is cancelled first.
+func (b *backoff) wait(ctx context.Context) bool {
+	timer := time.NewTimer(b.delay)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		b.next()
+		return true
+	}
+}
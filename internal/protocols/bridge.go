@@ -7,6 +7,7 @@
 //   - WebSocket: Notify chat rooms
 //   - gRPC: Log audit trail
 //   - HTTP: Tiếp nhận request ban đầu
+//   - Tự động reconnect TCP/gRPC với exponential backoff
 //
 // Đây là core feature thể hiện multi-protocol integration!
 package protocols
@@ -15,6 +16,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
+	"sync"
 	"time"
 
 	pb "mangahub/internal/grpc/pb"
@@ -22,45 +25,203 @@ import (
 	"mangahub/internal/udp"
 	"mangahub/pkg/logger"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/connectivity"
 	"google.golang.org/grpc/credentials/insecure"
 )
 
+// Connection health values reported by Health
+const (
+	StatusUp   = "up"
+	StatusDown = "down"
+)
+
+// RetryPolicy controls how often the bridge retries lost TCP/gRPC connections
+type RetryPolicy struct {
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+}
+
+// DefaultRetryPolicy retries after 1s, doubling up to one attempt per minute
+var DefaultRetryPolicy = RetryPolicy{InitialBackoff: time.Second, MaxBackoff: time.Minute}
+
 // ProtocolBridge connects all protocols together
 type ProtocolBridge struct {
-	tcpClient  *tcp.Client
+	tcpHost string
+	tcpPort int
+	retry   RetryPolicy
+
+	mu         sync.RWMutex
+	tcpClient  *tcp.Client // nil while disconnected
+	tcpDown    chan struct{}
 	udpServer  *udp.NotificationServer
 	grpcClient pb.MangaServiceClient
 	grpcConn   *grpc.ClientConn
+
+	stop context.CancelFunc
+	wg   sync.WaitGroup
 }
 
-// NewProtocolBridge creates a new bridge connecting all protocols
-func NewProtocolBridge(tcpHost string, tcpPort int, udpServer *udp.NotificationServer, grpcHost string, grpcPort int) (*ProtocolBridge, error) {
-	// Connect to TCP server
-	tcpClient := tcp.NewClient(tcpHost, tcpPort)
-	if err := tcpClient.Connect(); err != nil {
-		logger.Warnf("TCP client connection failed: %v (will retry on use)", err)
-		// Don't fail bridge creation, just log warning
+// NewProtocolBridge creates a new bridge connecting all protocols.
+// TCP and gRPC connect in the background and reconnect with exponential
+// backoff, so the bridge is usable before the other daemons are up.
+func NewProtocolBridge(tcpHost string, tcpPort int, udpServer *udp.NotificationServer, grpcHost string, grpcPort int, retry RetryPolicy) (*ProtocolBridge, error) {
+	if retry.InitialBackoff <= 0 {
+		retry.InitialBackoff = DefaultRetryPolicy.InitialBackoff
+	}
+	if retry.MaxBackoff < retry.InitialBackoff {
+		retry.MaxBackoff = retry.InitialBackoff
 	}
 
-	// Connect to gRPC server
+	// grpc.NewClient does not dial; it only fails on an invalid target
 	grpcAddr := fmt.Sprintf("%s:%d", grpcHost, grpcPort)
 	grpcConn, err := grpc.NewClient(grpcAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	if err != nil {
-		logger.Warnf("gRPC client connection failed: %v (will retry on use)", err)
-		// Don't fail bridge creation, just log warning
-	}
-
-	var grpcClient pb.MangaServiceClient
-	if grpcConn != nil {
-		grpcClient = pb.NewMangaServiceClient(grpcConn)
+		return nil, fmt.Errorf("create gRPC client: %w", err)
 	}
 
-	return &ProtocolBridge{
-		tcpClient:  tcpClient,
+	ctx, cancel := context.WithCancel(context.Background())
+	b := &ProtocolBridge{
+		tcpHost:    tcpHost,
+		tcpPort:    tcpPort,
+		retry:      retry,
+		tcpDown:    make(chan struct{}, 1),
 		udpServer:  udpServer,
-		grpcClient: grpcClient,
+		grpcClient: pb.NewMangaServiceClient(grpcConn),
 		grpcConn:   grpcConn,
-	}, nil
+		stop:       cancel,
+	}
+
+	b.wg.Add(2)
+	go b.maintainTCP(ctx)
+	go b.maintainGRPC(ctx)
+
+	return b, nil
+}
+
+// Health reports whether the TCP and gRPC connections are currently up
+func (b *ProtocolBridge) Health() map[string]string {
+	health := map[string]string{"tcp": StatusDown, "grpc": StatusDown}
+
+	b.mu.RLock()
+	if b.tcpClient != nil {
+		health["tcp"] = StatusUp
+	}
+	b.mu.RUnlock()
+
+	if b.grpcConn.GetState() == connectivity.Ready {
+		health["grpc"] = StatusUp
+	}
+	return health
+}
+
+// maintainTCP keeps a TCP connection open, reconnecting after it drops
+func (b *ProtocolBridge) maintainTCP(ctx context.Context) {
+	defer b.wg.Done()
+
+	retry := newBackoff(b.retry)
+	for {
+		client := tcp.NewClient(b.tcpHost, b.tcpPort)
+		if err := client.Connect(); err != nil {
+			if retry.shouldLog() {
+				logger.Warnf("Bridge: TCP unavailable (attempt %d, retrying in %v): %v", retry.attempts+1, retry.delay, err)
+			}
+			if !retry.wait(ctx) {
+				return
+			}
+			continue
+		}
+
+		if retry.attempts > 0 {
+			logger.Infof("Bridge: TCP connected after %d failed attempts", retry.attempts)
+		}
+		retry.reset()
+
+		b.mu.Lock()
+		b.tcpClient = client
+		b.mu.Unlock()
+
+		// The sync server pushes broadcasts to every client; drain them so
+		// its writes never block, and notice when the connection closes
+		go func() {
+			_, _ = io.Copy(io.Discard, client.Conn)
+			b.dropTCP(client)
+		}()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-b.tcpDown:
+			logger.Warnf("Bridge: TCP connection lost, reconnecting")
+		}
+	}
+}
+
+// dropTCP closes a failed TCP client so maintainTCP reconnects.
+// Only the current client is dropped; stale clients are ignored.
+func (b *ProtocolBridge) dropTCP(client *tcp.Client) {
+	b.mu.Lock()
+	if b.tcpClient != client {
+		b.mu.Unlock()
+		return
+	}
+	b.tcpClient = nil
+	b.mu.Unlock()
+
+	_ = client.Close()
+	select {
+	case b.tcpDown <- struct{}{}:
+	default:
+	}
+}
+
+// maintainGRPC nudges an idle or failed gRPC channel to reconnect. gRPC
+// retries a broken transport on its own, so this loop mostly tracks state
+// changes for logging and wakes channels that went idle.
+func (b *ProtocolBridge) maintainGRPC(ctx context.Context) {
+	defer b.wg.Done()
+
+	retry := newBackoff(b.retry)
+	wasReady := false
+	for {
+		state := b.grpcConn.GetState()
+		if state == connectivity.Idle {
+			b.grpcConn.Connect()
+		}
+
+		if state == connectivity.Ready {
+			if !wasReady {
+				if retry.attempts > 0 {
+					logger.Infof("Bridge: gRPC connected after %d failed checks", retry.attempts)
+				} else {
+					logger.Infof("Bridge: gRPC connected")
+				}
+			}
+			wasReady = true
+			retry.reset()
+			// Block until the channel leaves Ready
+			if !b.grpcConn.WaitForStateChange(ctx, connectivity.Ready) {
+				return
+			}
+			logger.Warnf("Bridge: gRPC connection lost, reconnecting")
+			wasReady = false
+			continue
+		}
+
+		if state != connectivity.Connecting && retry.shouldLog() {
+			logger.Warnf("Bridge: gRPC unavailable (%s, attempt %d, retrying in %v)", state, retry.attempts+1, retry.delay)
+		}
+
+		// Wake on the next state change or when the backoff expires
+		waitCtx, cancel := context.WithTimeout(ctx, retry.delay)
+		b.grpcConn.WaitForStateChange(waitCtx, state)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+		if b.grpcConn.GetState() != connectivity.Ready {
+			retry.next()
+		}
+	}
 }
 
 // BroadcastProgressUpdate sends progress update through all protocols
@@ -68,8 +229,11 @@ func (b *ProtocolBridge) BroadcastProgressUpdate(userID, username, mangaID strin
 	logger.Infof("Bridge: Broadcasting progress update - user=%s, manga=%s, chapter=%d", userID, mangaID, chapter)
 
 	// 1. TCP Broadcast: Send to sync server
-	if b.tcpClient != nil && b.tcpClient.Conn != nil {
-		go b.broadcastToTCP(userID, mangaID, int(chapter))
+	b.mu.RLock()
+	tcpClient := b.tcpClient
+	b.mu.RUnlock()
+	if tcpClient != nil {
+		go b.broadcastToTCP(tcpClient, userID, mangaID, int(chapter))
 	}
 
 	// 2. UDP Notification: Alert subscribers
@@ -77,8 +241,9 @@ func (b *ProtocolBridge) BroadcastProgressUpdate(userID, username, mangaID strin
 		go b.notifyViaUDP(mangaID)
 	}
 
-	// 3. gRPC Audit: Log to audit service
-	if b.grpcClient != nil {
+	// 3. gRPC Audit: Log to audit service (skipped while the channel is down
+	// so requests never wait on a dead backend)
+	if b.grpcConn.GetState() == connectivity.Ready {
 		go b.auditViaGRPC(userID, mangaID, chapter, status)
 	}
 
@@ -86,7 +251,7 @@ func (b *ProtocolBridge) BroadcastProgressUpdate(userID, username, mangaID strin
 }
 
 // broadcastToTCP sends progress update to TCP sync server
-func (b *ProtocolBridge) broadcastToTCP(userID, mangaID string, chapter int) {
+func (b *ProtocolBridge) broadcastToTCP(client *tcp.Client, userID, mangaID string, chapter int) {
 	progressUpdate := tcp.NewProgressUpdate(userID, mangaID, chapter)
 	data, err := json.Marshal(progressUpdate)
 	if err != nil {
@@ -94,9 +259,10 @@ func (b *ProtocolBridge) broadcastToTCP(userID, mangaID string, chapter int) {
 		return
 	}
 
-	_, err = b.tcpClient.Conn.Write(append(data, '\n'))
+	_, err = client.Conn.Write(append(data, '\n'))
 	if err != nil {
 		logger.Warnf("Bridge: TCP broadcast failed: %v", err)
+		b.dropTCP(client)
 	} else {
 		logger.Infof("Bridge: Progress update sent via TCP")
 	}
@@ -130,13 +296,18 @@ func (b *ProtocolBridge) auditViaGRPC(userID, mangaID string, chapter int32, sta
 	}
 }
 
-// Close closes all protocol connections
+// Close stops reconnecting and closes all protocol connections
 func (b *ProtocolBridge) Close() error {
-	if b.tcpClient != nil {
-		_ = b.tcpClient.Close()
-	}
-	if b.grpcConn != nil {
-		_ = b.grpcConn.Close()
+	b.stop()
+	b.wg.Wait()
+
+	b.mu.Lock()
+	tcpClient := b.tcpClient
+	b.tcpClient = nil
+	b.mu.Unlock()
+
+	if tcpClient != nil {
+		_ = tcpClient.Close()
 	}
-	return nil
+	return b.grpcConn.Close()
 }
@@ -0,0 +1,114 @@
+// Package protocols - Bridge Tests
+// Unit tests cho backoff và TCP reconnect khi server khởi động sau API
+package protocols
+
+import (
+	"net"
+	"strconv"
+	"testing"
+	"time"
+
+	"mangahub/pkg/logger"
+)
+
+func TestBackoffDoublesUpToCap(t *testing.T) {
+	b := newBackoff(RetryPolicy{InitialBackoff: time.Second, MaxBackoff: 5 * time.Second})
+
+	var delays []time.Duration
+	var logged []int
+	for i := 0; i < 6; i++ {
+		if b.shouldLog() {
+			logged = append(logged, b.attempts+1)
+		}
+		delays = append(delays, b.delay)
+		b.next()
+	}
+
+	want := []time.Duration{1, 2, 4, 5, 5, 5}
+	for i, d := range delays {
+		if d != want[i]*time.Second {
+			t.Errorf("delay %d = %v, want %v", i, d, want[i]*time.Second)
+		}
+	}
+	if len(logged) != 3 || logged[0] != 1 || logged[1] != 2 || logged[2] != 4 {
+		t.Errorf("expected logs on attempts 1, 2, 4, got %v", logged)
+	}
+
+	b.reset()
+	if b.attempts != 0 || b.delay != time.Second {
+		t.Errorf("reset should restore the initial delay, got %v after %d attempts", b.delay, b.attempts)
+	}
+}
+
+// freePort returns a local port with nothing listening on it
+func freePort(t *testing.T) int {
+	t.Helper()
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	port := ln.Addr().(*net.TCPAddr).Port
+	ln.Close()
+	return port
+}
+
+// waitForHealth polls Health until the protocol reaches the wanted status
+func waitForHealth(t *testing.T, b *ProtocolBridge, protocol, want string) {
+	t.Helper()
+	deadline := time.Now().Add(3 * time.Second)
+	for time.Now().Before(deadline) {
+		if b.Health()[protocol] == want {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("%s never became %s, health: %v", protocol, want, b.Health())
+}
+
+func TestBridgeReconnectsTCP(t *testing.T) {
+	// Initialize before the reconnect goroutines log concurrently
+	logger.Init(logger.Config{Level: "error", Format: "text", Output: "stdout"})
+
+	tcpPort := freePort(t)
+	policy := RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+
+	bridge, err := NewProtocolBridge("127.0.0.1", tcpPort, nil, "127.0.0.1", freePort(t), policy)
+	if err != nil {
+		t.Fatalf("NewProtocolBridge failed: %v", err)
+	}
+	defer bridge.Close()
+
+	// Nothing listening yet: the bridge starts degraded without blocking
+	if health := bridge.Health(); health["tcp"] != StatusDown || health["grpc"] != StatusDown {
+		t.Fatalf("expected tcp and grpc down, got %v", health)
+	}
+
+	// The sync server starts after the API server
+	ln, err := net.Listen("tcp", net.JoinHostPort("127.0.0.1", strconv.Itoa(tcpPort)))
+	if err != nil {
+		t.Fatalf("listen failed: %v", err)
+	}
+	defer ln.Close()
+	accepted := make(chan net.Conn, 4)
+	go func() {
+		for {
+			conn, err := ln.Accept()
+			if err != nil {
+				return
+			}
+			accepted <- conn
+		}
+	}()
+
+	waitForHealth(t, bridge, "tcp", StatusUp)
+
+	// The server drops the connection: the bridge notices and reconnects
+	(<-accepted).Close()
+	select {
+	case conn := <-accepted:
+		defer conn.Close()
+	case <-time.After(3 * time.Second):
+		t.Fatal("bridge did not reconnect after the connection dropped")
+	}
+	waitForHealth(t, bridge, "tcp", StatusUp)
+}
@@ -3,6 +3,7 @@
 // Endpoints:
 //   - POST /manga/:id/ratings - Submit/update rating
 //   - GET /manga/:id/ratings - Get ratings summary
+//   - GET /manga/:id/reviews - Paginated written reviews (spoilers masked unless the viewer opts in)
 //   - DELETE /manga/:id/ratings - Remove user's rating
 package rating
 
@@ -12,6 +13,7 @@ import (
 	"net/http"
 
 	"mangahub/internal/auth"
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/models"
 
 	"github.com/gin-gonic/gin"
@@ -26,6 +28,12 @@ type Handler struct {
 	svc              Service
 	activityRecorder ActivityRecorder
 	mangaSvc         MangaService
+	prefs            SpoilerPreferences
+}
+
+// SpoilerPreferences reads whether a viewer expands spoiler reviews by default
+type SpoilerPreferences interface {
+	GetPreferences(ctx context.Context, userID string) (*models.UserPreferences, error)
 }
 
 type MangaService interface {
@@ -53,46 +61,39 @@ func (h *Handler) SubmitRating(c *gin.Context) {
 	// Get authenticated user
 	user := auth.GetCurrentUser(c)
 	if user == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "authentication required",
-		})
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
 		return
 	}
 
 	// Get manga ID from URL
 	mangaID := c.Param("id")
 	if mangaID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "manga_id is required",
-		})
+		apperrors.Respond(c, apperrors.Validation("manga_id", "manga_id is required"), "")
 		return
 	}
 
 	// Parse request body
 	var req models.CreateRatingRequest
 	if err := c.ShouldBindJSON(&req); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": err.Error(),
-		})
+		apperrors.Respond(c, apperrors.BadRequest("invalid JSON body", err), "")
 		return
 	}
 
 	// Submit rating
 	rating, err := h.svc.Rate(c.Request.Context(), user.ID, mangaID, req)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to submit rating",
-		})
+		apperrors.Respond(c, err, "failed to submit rating")
 		return
 	}
 
 	// 📝 ACTIVITY: Record rating activity
 	if h.activityRecorder != nil && h.mangaSvc != nil {
 		go func() {
-			manga, err := h.mangaSvc.GetByID(c.Request.Context(), rating.MangaID)
+			manga, err := h.mangaSvc.GetByID(context.Background(), rating.MangaID)
 			if err == nil {
 				_ = h.activityRecorder.RecordMangaRated(
-					c.Request.Context(),
+					context.Background(),
 					user.ID,
 					user.Username,
 					rating.MangaID,
@@ -116,9 +117,7 @@ func (h *Handler) GetRatings(c *gin.Context) {
 	// Get manga ID from URL
 	mangaID := c.Param("id")
 	if mangaID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "manga_id is required",
-		})
+		apperrors.Respond(c, apperrors.Validation("manga_id", "manga_id is required"), "")
 		return
 	}
 
@@ -141,11 +140,10 @@ func (h *Handler) GetRatings(c *gin.Context) {
 	// Get ratings summary and recent ratings
 	response, err := h.svc.GetMangaRatings(c.Request.Context(), mangaID, limit, offset)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to get ratings",
-		})
+		apperrors.Respond(c, err, "failed to get ratings")
 		return
 	}
+	h.maskSpoilers(c, response.Ratings)
 
 	c.JSON(http.StatusOK, gin.H{
 		"data":    response,
@@ -153,33 +151,87 @@ func (h *Handler) GetRatings(c *gin.Context) {
 	})
 }
 
+// GetReviews handles GET /manga/:id/reviews
+// Returns written reviews with author and timestamps
+// Query params: ?sort=recent|helpful&page=1&limit=20
+func (h *Handler) GetReviews(c *gin.Context) {
+	mangaID := c.Param("id")
+	if mangaID == "" {
+		apperrors.Respond(c, apperrors.Validation("manga_id", "manga_id is required"), "")
+		return
+	}
+
+	page := 1
+	limit := 20
+	if p := c.Query("page"); p != "" {
+		if val, err := parseInt(p); err == nil && val > 0 {
+			page = val
+		}
+	}
+	if l := c.Query("limit"); l != "" {
+		if val, err := parseInt(l); err == nil && val > 0 && val <= 100 {
+			limit = val
+		}
+	}
+
+	response, err := h.svc.GetReviews(c.Request.Context(), mangaID, c.Query("sort"), page, limit)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to get reviews")
+		return
+	}
+	h.maskSpoilers(c, response.Reviews)
+
+	c.JSON(http.StatusOK, gin.H{
+		"data":    response,
+		"message": "reviews retrieved",
+	})
+}
+
+// SetSpoilerPreferences unmasks spoiler reviews for viewers with show_spoilers on
+func (h *Handler) SetSpoilerPreferences(prefs SpoilerPreferences) {
+	h.prefs = prefs
+}
+
+// maskSpoilers withholds other users' spoiler reviews unless the request asks
+// for them (?spoilers=show) or the viewer's preferences show spoilers
+func (h *Handler) maskSpoilers(c *gin.Context, reviews []models.RatingWithUser) {
+	if c.Query("spoilers") == models.SpoilersShow {
+		return
+	}
+	var viewerID string
+	if user := auth.GetCurrentUser(c); user != nil {
+		viewerID = user.ID
+		if h.prefs != nil {
+			if prefs, err := h.prefs.GetPreferences(c.Request.Context(), viewerID); err == nil && prefs.ShowSpoilers {
+				return
+			}
+		}
+	}
+	models.MaskReviewSpoilers(reviews, viewerID)
+}
+
 // DeleteRating handles DELETE /manga/:id/ratings
 // Removes the current user's rating for a manga
 func (h *Handler) DeleteRating(c *gin.Context) {
 	// Get authenticated user
 	user := auth.GetCurrentUser(c)
 	if user == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{
-			"error": "authentication required",
-		})
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
 		return
 	}
 
 	// Get manga ID from URL
 	mangaID := c.Param("id")
 	if mangaID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{
-			"error": "manga_id is required",
-		})
+		apperrors.Respond(c, apperrors.Validation("manga_id", "manga_id is required"), "")
 		return
 	}
 
 	// Delete rating
 	err := h.svc.DeleteRating(c.Request.Context(), user.ID, mangaID)
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{
-			"error": "failed to delete rating",
-		})
+		apperrors.Respond(c, err, "failed to delete rating")
 		return
 	}
 
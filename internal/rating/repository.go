@@ -4,6 +4,7 @@
 //   - CRUD operations for manga ratings (simplified single rating 1-10)
 //   - Aggregate calculations (average, distribution) from manga table (auto-calculated by triggers)
 //   - User rating lookup
+//   - Paginated written reviews (recent/helpful)
 package rating
 
 import (
@@ -31,6 +32,9 @@ type Repository interface {
 	// GetByManga retrieves all ratings for a manga with pagination
 	GetByManga(ctx context.Context, mangaID string, limit, offset int) ([]models.RatingWithUser, error)
 
+	// GetReviews retrieves ratings with review text, ordered by sort, plus the total count
+	GetReviews(ctx context.Context, mangaID, sort string, limit, offset int) ([]models.RatingWithUser, int, error)
+
 	// GetSummary gets rating summary for a manga from manga table (auto-calculated)
 	GetSummary(ctx context.Context, mangaID string) (*models.RatingSummary, error)
 
@@ -69,21 +73,35 @@ func (r *repository) CreateOrUpdate(ctx context.Context, userID, mangaID string,
 		ratingID = uuid.New().String()
 		_, err = r.db.ExecContext(ctx, `
 			INSERT INTO manga_ratings 
-			(id, manga_id, user_id, rating, review, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?)`,
-			ratingID, mangaID, userID, req.Rating, req.ReviewText, now, now,
+			(id, manga_id, user_id, rating, review_text, is_spoiler, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+			ratingID, mangaID, userID, req.Rating, req.ReviewText, req.IsSpoiler, now, now,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("insert rating: %w", err)
 		}
+	} else if req.KeepReview {
+		// Score-only change: the review and spoiler flag stay as they are
+		ratingID = existingID
+		_, err = r.db.ExecContext(ctx,
+			"UPDATE manga_ratings SET rating = ?, updated_at = ? WHERE id = ?",
+			req.Rating, now, ratingID,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("update rating: %w", err)
+		}
 	} else {
-		// Update existing rating
+		// Update existing rating; rewriting an existing review marks it edited
 		ratingID = existingID
 		_, err = r.db.ExecContext(ctx, `
 			UPDATE manga_ratings 
-			SET rating = ?, review = ?, updated_at = ?
+			SET is_edited = CASE
+			        WHEN COALESCE(review_text, '') != '' AND COALESCE(review_text, '') != ? THEN 1
+			        ELSE COALESCE(is_edited, 0)
+			    END,
+			    rating = ?, review_text = ?, is_spoiler = ?, updated_at = ?
 			WHERE id = ?`,
-			req.Rating, req.ReviewText, now, ratingID,
+			req.ReviewText, req.Rating, req.ReviewText, req.IsSpoiler, now, ratingID,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("update rating: %w", err)
@@ -97,11 +115,14 @@ func (r *repository) CreateOrUpdate(ctx context.Context, userID, mangaID string,
 func (r *repository) GetByID(ctx context.Context, id string) (*models.MangaRating, error) {
 	var rating models.MangaRating
 	err := r.db.QueryRowContext(ctx, `
-		SELECT id, manga_id, user_id, rating, review, created_at, updated_at
+		SELECT id, manga_id, user_id, rating, COALESCE(review_text, ''),
+		       COALESCE(is_spoiler, 0), COALESCE(is_edited, 0), COALESCE(helpful_count, 0),
+		       created_at, updated_at
 		FROM manga_ratings WHERE id = ?`, id,
 	).Scan(
-		&rating.ID, &rating.MangaID, &rating.UserID, &rating.Rating,
-		&rating.ReviewText, &rating.CreatedAt, &rating.UpdatedAt,
+		&rating.ID, &rating.MangaID, &rating.UserID, &rating.Rating, &rating.ReviewText,
+		&rating.IsSpoiler, &rating.IsEdited, &rating.HelpfulCount,
+		&rating.CreatedAt, &rating.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -116,11 +137,14 @@ func (r *repository) GetByID(ctx context.Context, id string) (*models.MangaRatin
 func (r *repository) GetByUserAndManga(ctx context.Context, userID, mangaID string) (*models.MangaRating, error) {
 	var rating models.MangaRating
 	err := r.db.QueryRowContext(ctx, `
-		SELECT id, manga_id, user_id, rating, review, created_at, updated_at
+		SELECT id, manga_id, user_id, rating, COALESCE(review_text, ''),
+		       COALESCE(is_spoiler, 0), COALESCE(is_edited, 0), COALESCE(helpful_count, 0),
+		       created_at, updated_at
 		FROM manga_ratings WHERE user_id = ? AND manga_id = ?`, userID, mangaID,
 	).Scan(
-		&rating.ID, &rating.MangaID, &rating.UserID, &rating.Rating,
-		&rating.ReviewText, &rating.CreatedAt, &rating.UpdatedAt,
+		&rating.ID, &rating.MangaID, &rating.UserID, &rating.Rating, &rating.ReviewText,
+		&rating.IsSpoiler, &rating.IsEdited, &rating.HelpfulCount,
+		&rating.CreatedAt, &rating.UpdatedAt,
 	)
 	if err != nil {
 		if err == sql.ErrNoRows {
@@ -134,9 +158,7 @@ func (r *repository) GetByUserAndManga(ctx context.Context, userID, mangaID stri
 // GetByManga retrieves all ratings for a manga with user info
 func (r *repository) GetByManga(ctx context.Context, mangaID string, limit, offset int) ([]models.RatingWithUser, error) {
 	rows, err := r.db.QueryContext(ctx, `
-		SELECT r.id, r.manga_id, r.user_id, r.rating, r.review,
-		       r.created_at, r.updated_at,
-		       u.username, u.display_name
+		SELECT `+ratingWithUserColumns+`
 		FROM manga_ratings r
 		JOIN users u ON r.user_id = u.id
 		WHERE r.manga_id = ?
@@ -148,11 +170,23 @@ func (r *repository) GetByManga(ctx context.Context, mangaID string, limit, offs
 	}
 	defer rows.Close()
 
+	return scanRatingsWithUser(rows)
+}
+
+// ratingWithUserColumns are the columns read by scanRatingsWithUser
+const ratingWithUserColumns = `r.id, r.manga_id, r.user_id, r.rating, COALESCE(r.review_text, ''),
+		       COALESCE(r.is_spoiler, 0), COALESCE(r.is_edited, 0), COALESCE(r.helpful_count, 0),
+		       r.created_at, r.updated_at,
+		       u.username, COALESCE(u.display_name, '')`
+
+// scanRatingsWithUser reads rows selected with ratingWithUserColumns
+func scanRatingsWithUser(rows *sql.Rows) ([]models.RatingWithUser, error) {
 	var ratings []models.RatingWithUser
 	for rows.Next() {
 		var r models.RatingWithUser
 		err := rows.Scan(
 			&r.ID, &r.MangaID, &r.UserID, &r.Rating, &r.ReviewText,
+			&r.IsSpoiler, &r.IsEdited, &r.HelpfulCount,
 			&r.CreatedAt, &r.UpdatedAt,
 			&r.Username, &r.DisplayName,
 		)
@@ -161,9 +195,54 @@ func (r *repository) GetByManga(ctx context.Context, mangaID string, limit, offs
 		}
 		ratings = append(ratings, r)
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate ratings: %w", err)
+	}
 	return ratings, nil
 }
 
+// GetReviews retrieves ratings that carry review text, newest or most helpful first
+func (r *repository) GetReviews(ctx context.Context, mangaID, sort string, limit, offset int) ([]models.RatingWithUser, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*) FROM manga_ratings
+		WHERE manga_id = ? AND COALESCE(review_text, '') != ''`, mangaID,
+	).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("count reviews: %w", err)
+	}
+	if total == 0 {
+		return []models.RatingWithUser{}, 0, nil
+	}
+
+	orderBy := "r.created_at DESC"
+	if sort == models.ReviewSortHelpful {
+		orderBy = "r.helpful_count DESC, r.created_at DESC"
+	}
+
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT `+ratingWithUserColumns+`
+		FROM manga_ratings r
+		JOIN users u ON r.user_id = u.id
+		WHERE r.manga_id = ? AND COALESCE(r.review_text, '') != ''
+		ORDER BY `+orderBy+`, r.id
+		LIMIT ? OFFSET ?`, mangaID, limit, offset,
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("get reviews: %w", err)
+	}
+	defer rows.Close()
+
+	reviews, err := scanRatingsWithUser(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+	if reviews == nil {
+		reviews = []models.RatingWithUser{}
+	}
+	return reviews, total, nil
+}
+
 // GetSummary gets rating summary from manga table (auto-calculated by triggers)
 func (r *repository) GetSummary(ctx context.Context, mangaID string) (*models.RatingSummary, error) {
 	var summary models.RatingSummary
@@ -185,6 +264,7 @@ func (r *repository) GetSummary(ctx context.Context, mangaID string) (*models.Ra
 	summary.RatingCount = ratingCount
 
 	// Get rating distribution (count per score 1-10)
+	// Một query GROUP BY dùng idx_ratings_manga; scores with no ratings stay 0
 	rows, err := r.db.QueryContext(ctx, `
 		SELECT rating, COUNT(*) as cnt
 		FROM manga_ratings 
@@ -206,6 +286,9 @@ func (r *repository) GetSummary(ctx context.Context, mangaID string) (*models.Ra
 			summary.RatingDistribution[score-1] = count
 		}
 	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate distribution: %w", err)
+	}
 
 	return &summary, nil
 }
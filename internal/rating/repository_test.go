@@ -0,0 +1,157 @@
+// Package rating - Repository Tests
+// Unit tests cho rating summary và phân bố điểm 1-10
+package rating
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"testing"
+
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+// setupTestDB opens the shared test database and seeds the rows these tests use
+func setupTestDB(t *testing.T) *sql.DB {
+	db := testutil.OpenDB(t)
+
+	if _, err := db.Exec(`INSERT INTO manga (id, title) VALUES ('m1', 'Berserk'), ('m2', 'Unrated')`); err != nil {
+		t.Fatalf("seed manga failed: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		_, err := db.Exec(`INSERT INTO users (id, username, email, password_hash, display_name) VALUES (?, ?, ?, 'x', ?)`,
+			fmt.Sprintf("u%d", i), fmt.Sprintf("user%d", i), fmt.Sprintf("u%d@example.com", i), fmt.Sprintf("User %d", i))
+		if err != nil {
+			t.Fatalf("seed users failed: %v", err)
+		}
+	}
+
+	return db
+}
+
+func TestGetSummaryDistribution(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	repo := NewRepository(sqlDB)
+	ctx := context.Background()
+
+	for i, score := range []int{8, 8, 8, 10, 3} {
+		if _, err := repo.CreateOrUpdate(ctx, fmt.Sprintf("u%d", i), "m1", models.CreateRatingRequest{Rating: score}); err != nil {
+			t.Fatalf("CreateOrUpdate failed: %v", err)
+		}
+	}
+
+	summary, err := repo.GetSummary(ctx, "m1")
+	if err != nil {
+		t.Fatalf("GetSummary failed: %v", err)
+	}
+	want := [10]int{0, 0, 1, 0, 0, 0, 0, 3, 0, 1}
+	if summary.RatingDistribution != want {
+		t.Errorf("distribution = %v, want %v", summary.RatingDistribution, want)
+	}
+	if summary.RatingCount != 5 {
+		t.Errorf("rating count = %d, want 5", summary.RatingCount)
+	}
+
+	// No ratings: all zeros, serialized as an array rather than null
+	summary, err = repo.GetSummary(ctx, "m2")
+	if err != nil {
+		t.Fatalf("GetSummary(unrated) failed: %v", err)
+	}
+	if summary.RatingDistribution != ([10]int{}) {
+		t.Errorf("expected all-zero distribution, got %v", summary.RatingDistribution)
+	}
+}
+
+func TestDistributionQueryUsesIndex(t *testing.T) {
+	sqlDB := setupTestDB(t)
+
+	rows, err := sqlDB.Query(`EXPLAIN QUERY PLAN
+		SELECT rating, COUNT(*) FROM manga_ratings WHERE manga_id = ? GROUP BY rating ORDER BY rating`, "m1")
+	if err != nil {
+		t.Fatalf("explain failed: %v", err)
+	}
+	defer rows.Close()
+
+	var plan []string
+	for rows.Next() {
+		var id, parent, notused int
+		var detail string
+		if err := rows.Scan(&id, &parent, &notused, &detail); err != nil {
+			t.Fatalf("scan plan failed: %v", err)
+		}
+		plan = append(plan, detail)
+	}
+
+	joined := strings.Join(plan, "; ")
+	if !strings.Contains(joined, "USING INDEX") && !strings.Contains(joined, "USING COVERING INDEX") {
+		t.Errorf("distribution query should search by manga_id index, plan: %s", joined)
+	}
+}
+
+func TestGetReviewsAndEditFlag(t *testing.T) {
+	sqlDB := setupTestDB(t)
+	repo := NewRepository(sqlDB)
+	ctx := context.Background()
+
+	seed := []struct {
+		user   string
+		req    models.CreateRatingRequest
+		helped int
+	}{
+		{"u0", models.CreateRatingRequest{Rating: 9, ReviewText: "Masterpiece."}, 1},
+		{"u1", models.CreateRatingRequest{Rating: 7}, 0}, // score only, not a review
+		{"u2", models.CreateRatingRequest{Rating: 10, ReviewText: "The eclipse arc...", IsSpoiler: true}, 5},
+	}
+	for _, s := range seed {
+		if _, err := repo.CreateOrUpdate(ctx, s.user, "m1", s.req); err != nil {
+			t.Fatalf("CreateOrUpdate failed: %v", err)
+		}
+		sqlDB.Exec(`UPDATE manga_ratings SET helpful_count = ? WHERE user_id = ?`, s.helped, s.user)
+	}
+
+	reviews, total, err := repo.GetReviews(ctx, "m1", models.ReviewSortHelpful, 10, 0)
+	if err != nil {
+		t.Fatalf("GetReviews failed: %v", err)
+	}
+	if total != 2 || len(reviews) != 2 {
+		t.Fatalf("got %d reviews (total %d), want only the 2 with text", len(reviews), total)
+	}
+	if reviews[0].UserID != "u2" || !reviews[0].IsSpoiler || reviews[0].Username != "user2" {
+		t.Errorf("most helpful review = %+v, want u2's spoiler review", reviews[0])
+	}
+
+	// Re-rating keeps the review unedited; changing its text marks it edited
+	rating, err := repo.CreateOrUpdate(ctx, "u0", "m1", models.CreateRatingRequest{Rating: 10, ReviewText: "Masterpiece."})
+	if err != nil {
+		t.Fatalf("re-rate failed: %v", err)
+	}
+	if rating.IsEdited {
+		t.Error("changing only the score should not mark the review edited")
+	}
+	rating, err = repo.CreateOrUpdate(ctx, "u0", "m1", models.CreateRatingRequest{Rating: 10, ReviewText: "Masterpiece. Still reading."})
+	if err != nil {
+		t.Fatalf("edit failed: %v", err)
+	}
+	if !rating.IsEdited {
+		t.Error("rewriting the review should mark it edited")
+	}
+
+	// Writing a first review under an existing score is not an edit
+	rating, err = repo.CreateOrUpdate(ctx, "u1", "m1", models.CreateRatingRequest{Rating: 7, ReviewText: "Solid."})
+	if err != nil {
+		t.Fatalf("add review failed: %v", err)
+	}
+	if rating.IsEdited {
+		t.Error("adding a first review should not mark it edited")
+	}
+	// A quick re-rate changes only the score
+	rating, err = repo.CreateOrUpdate(ctx, "u2", "m1", models.CreateRatingRequest{Rating: 8, KeepReview: true})
+	if err != nil {
+		t.Fatalf("quick re-rate failed: %v", err)
+	}
+	if rating.Rating != 8 || rating.ReviewText != "The eclipse arc..." || !rating.IsSpoiler || rating.IsEdited {
+		t.Errorf("quick re-rate = %+v, want score 8 with the review untouched", rating)
+	}
+}
@@ -9,6 +9,7 @@ package rating
 import (
 	"context"
 
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/models"
 	"mangahub/pkg/utils"
 )
@@ -21,6 +22,9 @@ type Service interface {
 	// GetMangaRatings returns aggregate stats + recent ratings for a manga
 	GetMangaRatings(ctx context.Context, mangaID string, limit, offset int) (*models.MangaRatingsResponse, error)
 
+	// GetReviews returns a page of written reviews sorted by recent or helpful
+	GetReviews(ctx context.Context, mangaID, sort string, page, limit int) (*models.ReviewsResponse, error)
+
 	// GetUserRating returns a user's rating for a manga
 	GetUserRating(ctx context.Context, userID, mangaID string) (*models.MangaRating, error)
 
@@ -41,13 +45,13 @@ func NewService(repo Repository) Service {
 func (s *service) Rate(ctx context.Context, userID, mangaID string, req models.CreateRatingRequest) (*models.MangaRating, error) {
 	// Validate request using struct validation
 	if err := utils.ValidateStruct(req); err != nil {
-		return nil, models.NewAppError(models.ErrCodeValidation, "invalid rating data", 400, err)
+		return nil, apperrors.Invalid("invalid rating data", err)
 	}
 
 	// Validation is handled by struct tags in CreateRatingRequest (min=1, max=10)
 	rating, err := s.repo.CreateOrUpdate(ctx, userID, mangaID, req)
 	if err != nil {
-		return nil, models.NewAppError(models.ErrCodeInternal, "failed to save rating", 500, err)
+		return nil, apperrors.Internal("failed to save rating", err)
 	}
 
 	return rating, nil
@@ -65,13 +69,13 @@ func (s *service) GetMangaRatings(ctx context.Context, mangaID string, limit, of
 	// Get summary (aggregate stats from manga table)
 	summary, err := s.repo.GetSummary(ctx, mangaID)
 	if err != nil {
-		return nil, models.NewAppError(models.ErrCodeInternal, "failed to get rating summary", 500, err)
+		return nil, apperrors.Internal("failed to get rating summary", err)
 	}
 
 	// Get recent ratings with user info
 	ratings, err := s.repo.GetByManga(ctx, mangaID, limit, offset)
 	if err != nil {
-		return nil, models.NewAppError(models.ErrCodeInternal, "failed to get recent ratings", 500, err)
+		return nil, apperrors.Internal("failed to get recent ratings", err)
 	}
 
 	return &models.MangaRatingsResponse{
@@ -83,15 +87,47 @@ func (s *service) GetMangaRatings(ctx context.Context, mangaID string, limit, of
 	}, nil
 }
 
+// GetReviews returns a page of written reviews; sort defaults to recent
+func (s *service) GetReviews(ctx context.Context, mangaID, sort string, page, limit int) (*models.ReviewsResponse, error) {
+	switch sort {
+	case "":
+		sort = models.ReviewSortRecent
+	case models.ReviewSortRecent, models.ReviewSortHelpful:
+	default:
+		return nil, apperrors.Validation("sort", "sort must be recent or helpful")
+	}
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if page < 1 {
+		page = 1
+	}
+	offset := (page - 1) * limit
+
+	reviews, total, err := s.repo.GetReviews(ctx, mangaID, sort, limit, offset)
+	if err != nil {
+		return nil, apperrors.Internal("failed to get reviews", err)
+	}
+
+	return &models.ReviewsResponse{
+		Reviews: reviews,
+		Sort:    sort,
+		Total:   total,
+		Page:    page,
+		Limit:   limit,
+		HasMore: offset+len(reviews) < total,
+	}, nil
+}
+
 // GetUserRating returns a specific user's rating for a manga
 func (s *service) GetUserRating(ctx context.Context, userID, mangaID string) (*models.MangaRating, error) {
 	if userID == "" || mangaID == "" {
-		return nil, models.NewAppError(models.ErrCodeValidation, "user_id and manga_id are required", 400, nil)
+		return nil, apperrors.Validation("manga_id", "user_id and manga_id are required")
 	}
 
 	rating, err := s.repo.GetByUserAndManga(ctx, userID, mangaID)
 	if err != nil {
-		return nil, models.NewAppError(models.ErrCodeInternal, "failed to get user rating", 500, err)
+		return nil, apperrors.Internal("failed to get user rating", err)
 	}
 	return rating, nil
 }
@@ -99,12 +135,12 @@ func (s *service) GetUserRating(ctx context.Context, userID, mangaID string) (*m
 // DeleteRating removes a user's rating for a manga
 func (s *service) DeleteRating(ctx context.Context, userID, mangaID string) error {
 	if userID == "" || mangaID == "" {
-		return models.NewAppError(models.ErrCodeValidation, "user_id and manga_id are required", 400, nil)
+		return apperrors.Validation("manga_id", "user_id and manga_id are required")
 	}
 
 	err := s.repo.Delete(ctx, userID, mangaID)
 	if err != nil {
-		return models.NewAppError(models.ErrCodeNotFound, "rating not found", 404, err)
+		return apperrors.NotFound("rating not found", err)
 	}
 	return nil
 }
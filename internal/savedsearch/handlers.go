@@ -0,0 +1,136 @@
+// Package savedsearch - Search History & Saved Searches HTTP Handlers
+// HTTP handlers cho search history và saved searches
+// Endpoints:
+//   - GET /users/searches - Saved searches and recent history
+//   - POST /users/searches - Save a named search
+//   - DELETE /users/searches/:id - Delete a saved search
+//   - POST /users/search-history - Record a search
+//   - DELETE /users/search-history - Clear the history
+package savedsearch
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"mangahub/internal/auth"
+	"mangahub/pkg/apperrors"
+	"mangahub/pkg/models"
+)
+
+// Handler handles HTTP requests for search history and saved searches
+type Handler struct {
+	svc Service
+}
+
+// NewHandler creates a new search history handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// GetSearches handles GET /users/searches
+func (h *Handler) GetSearches(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	searches, err := h.svc.GetSearches(c.Request.Context(), user.ID)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to get searches")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(searches, "searches retrieved successfully"))
+}
+
+// SaveSearch handles POST /users/searches
+// Request body: { name, query?, genre?, sort?, order? }
+func (h *Handler) SaveSearch(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	var req models.SaveSearchRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeBadRequest, "invalid JSON body", map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	saved, err := h.svc.SaveSearch(c.Request.Context(), user.ID, req)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to save search")
+		return
+	}
+
+	c.JSON(http.StatusCreated,
+		models.NewSuccessResponse(saved, "search saved successfully"))
+}
+
+// DeleteSavedSearch handles DELETE /users/searches/:id
+func (h *Handler) DeleteSavedSearch(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	if err := h.svc.DeleteSavedSearch(c.Request.Context(), c.Param("id"), user.ID); err != nil {
+		apperrors.Respond(c, err, "failed to delete saved search")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(nil, "saved search deleted successfully"))
+}
+
+// RecordSearch handles POST /users/search-history
+// Request body: { query?, genre?, sort?, order? }
+func (h *Handler) RecordSearch(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	var req models.SearchFilters
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeBadRequest, "invalid JSON body", map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	if err := h.svc.RecordSearch(c.Request.Context(), user.ID, req); err != nil {
+		apperrors.Respond(c, err, "failed to record search")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(nil, "search recorded"))
+}
+
+// ClearHistory handles DELETE /users/search-history
+func (h *Handler) ClearHistory(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	if err := h.svc.ClearHistory(c.Request.Context(), user.ID); err != nil {
+		apperrors.Respond(c, err, "failed to clear search history")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(nil, "search history cleared"))
+}
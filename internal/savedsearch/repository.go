@@ -0,0 +1,171 @@
+// Package savedsearch - Search History & Saved Searches Repository
+// Data access layer cho search_history và saved_searches
+// Chức năng:
+//   - Ghi lịch sử tìm kiếm (upsert theo query + filters, giữ tối đa MaxRecentSearches)
+//   - CRUD cho saved searches; UNIQUE(user_id, name) được map thành ErrSearchNameExists
+package savedsearch
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"mangahub/pkg/models"
+)
+
+// Repository defines data access operations for search history and saved searches
+type Repository interface {
+	// RecordSearch adds a search to the user's history, or moves an identical one to the top,
+	// and trims the history to models.MaxRecentSearches entries
+	RecordSearch(ctx context.Context, userID string, filters models.SearchFilters) error
+
+	// RecentSearches returns the user's history, newest first
+	RecentSearches(ctx context.Context, userID string) ([]models.RecentSearch, error)
+
+	// ClearHistory removes the user's whole search history
+	ClearHistory(ctx context.Context, userID string) error
+
+	// CreateSavedSearch inserts a named search
+	CreateSavedSearch(ctx context.Context, search *models.SavedSearch) error
+
+	// SavedSearches returns the user's saved searches by name
+	SavedSearches(ctx context.Context, userID string) ([]models.SavedSearch, error)
+
+	// DeleteSavedSearch removes one of the user's saved searches, or returns models.ErrSearchNotFound
+	DeleteSavedSearch(ctx context.Context, id, userID string) error
+}
+
+type repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new search history repository
+func NewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+// RecordSearch upserts the search and trims the history in one transaction
+func (r *repository) RecordSearch(ctx context.Context, userID string, f models.SearchFilters) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin record search: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = tx.ExecContext(ctx, `
+		INSERT INTO search_history (user_id, query, genre, sort, sort_order, searched_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, query, genre, sort, sort_order) DO UPDATE SET searched_at = excluded.searched_at`,
+		userID, f.Query, f.Genre, f.Sort, f.Order, time.Now().UTC())
+	if err != nil {
+		return fmt.Errorf("insert search history: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		DELETE FROM search_history
+		WHERE user_id = ? AND rowid NOT IN (
+			SELECT rowid FROM search_history
+			WHERE user_id = ?
+			ORDER BY searched_at DESC
+			LIMIT ?
+		)`, userID, userID, models.MaxRecentSearches)
+	if err != nil {
+		return fmt.Errorf("trim search history: %w", err)
+	}
+	return tx.Commit()
+}
+
+// RecentSearches returns the user's history, newest first
+func (r *repository) RecentSearches(ctx context.Context, userID string) ([]models.RecentSearch, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT query, genre, sort, sort_order, searched_at
+		FROM search_history
+		WHERE user_id = ?
+		ORDER BY searched_at DESC
+		LIMIT ?`, userID, models.MaxRecentSearches)
+	if err != nil {
+		return nil, fmt.Errorf("get search history: %w", err)
+	}
+	defer rows.Close()
+
+	recent := []models.RecentSearch{}
+	for rows.Next() {
+		var s models.RecentSearch
+		if err := rows.Scan(&s.Query, &s.Genre, &s.Sort, &s.Order, &s.SearchedAt); err != nil {
+			return nil, fmt.Errorf("scan search history: %w", err)
+		}
+		recent = append(recent, s)
+	}
+	return recent, rows.Err()
+}
+
+// ClearHistory removes the user's whole search history
+func (r *repository) ClearHistory(ctx context.Context, userID string) error {
+	if _, err := r.db.ExecContext(ctx, "DELETE FROM search_history WHERE user_id = ?", userID); err != nil {
+		return fmt.Errorf("clear search history: %w", err)
+	}
+	return nil
+}
+
+// CreateSavedSearch inserts a named search
+func (r *repository) CreateSavedSearch(ctx context.Context, s *models.SavedSearch) error {
+	s.ID = uuid.New().String()
+	s.CreatedAt = time.Now()
+
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO saved_searches (id, user_id, name, query, genre, sort, sort_order, created_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		s.ID, s.UserID, s.Name, s.Query, s.Genre, s.Sort, s.Order, s.CreatedAt)
+	if isUniqueViolation(err) {
+		return models.ErrSearchNameExists
+	}
+	if err != nil {
+		return fmt.Errorf("insert saved search: %w", err)
+	}
+	return nil
+}
+
+// SavedSearches returns the user's saved searches by name
+func (r *repository) SavedSearches(ctx context.Context, userID string) ([]models.SavedSearch, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, name, query, genre, sort, sort_order, created_at
+		FROM saved_searches
+		WHERE user_id = ?
+		ORDER BY name COLLATE NOCASE ASC`, userID)
+	if err != nil {
+		return nil, fmt.Errorf("get saved searches: %w", err)
+	}
+	defer rows.Close()
+
+	saved := []models.SavedSearch{}
+	for rows.Next() {
+		var s models.SavedSearch
+		if err := rows.Scan(&s.ID, &s.UserID, &s.Name, &s.Query, &s.Genre, &s.Sort, &s.Order, &s.CreatedAt); err != nil {
+			return nil, fmt.Errorf("scan saved search: %w", err)
+		}
+		saved = append(saved, s)
+	}
+	return saved, rows.Err()
+}
+
+// DeleteSavedSearch removes one of the user's saved searches
+func (r *repository) DeleteSavedSearch(ctx context.Context, id, userID string) error {
+	res, err := r.db.ExecContext(ctx,
+		"DELETE FROM saved_searches WHERE id = ? AND user_id = ?", id, userID)
+	if err != nil {
+		return fmt.Errorf("delete saved search: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return models.ErrSearchNotFound
+	}
+	return nil
+}
+
+// isUniqueViolation reports whether err comes from a UNIQUE constraint
+func isUniqueViolation(err error) bool {
+	return err != nil && !errors.Is(err, sql.ErrNoRows) && strings.Contains(err.Error(), "UNIQUE constraint failed")
+}
@@ -0,0 +1,117 @@
+// Package savedsearch - Search History & Saved Searches Tests
+// Unit tests cho history (cap, không trùng lặp) và saved searches round-trip
+package savedsearch
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"net/http"
+	"testing"
+
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+// setupTestDB opens the shared test database and seeds the rows these tests use
+func setupTestDB(t *testing.T) *sql.DB {
+	db := testutil.OpenDB(t)
+	if _, err := db.Exec(`INSERT INTO users (id, username, email, password_hash, display_name) VALUES
+		('u1', 'reader', 'r@example.com', 'x', 'Reader'),
+		('u2', 'other', 'o@example.com', 'x', 'Other')`); err != nil {
+		t.Fatalf("seed failed: %v", err)
+	}
+
+	return db
+}
+
+func TestRecentSearchesCappedAndDeduplicated(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)))
+	ctx := context.Background()
+
+	for i := 0; i < models.MaxRecentSearches+5; i++ {
+		if err := svc.RecordSearch(ctx, "u1", models.SearchFilters{Query: fmt.Sprintf("query %d", i)}); err != nil {
+			t.Fatalf("RecordSearch %d: %v", i, err)
+		}
+	}
+	// Searching again for a kept query moves it to the top instead of adding a row;
+	// the spacing and case differences are normalized away
+	repeat := models.SearchFilters{Query: "  query 10 ", Genre: "Action", Sort: "rating", Order: "desc"}
+	for i := 0; i < 2; i++ {
+		if err := svc.RecordSearch(ctx, "u1", repeat); err != nil {
+			t.Fatalf("RecordSearch repeat: %v", err)
+		}
+	}
+	// Empty searches are not history
+	if err := svc.RecordSearch(ctx, "u1", models.SearchFilters{Query: "   "}); err != nil {
+		t.Fatalf("RecordSearch empty: %v", err)
+	}
+
+	got, err := svc.GetSearches(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetSearches: %v", err)
+	}
+	if len(got.Recent) != models.MaxRecentSearches {
+		t.Fatalf("got %d recent searches, want %d", len(got.Recent), models.MaxRecentSearches)
+	}
+	want := models.SearchFilters{Query: "query 10", Genre: "action", Sort: "rating", Order: "desc"}
+	if got.Recent[0].SearchFilters != want {
+		t.Errorf("newest search = %+v, want %+v", got.Recent[0].SearchFilters, want)
+	}
+	for _, r := range got.Recent {
+		if r.Query == "query 0" {
+			t.Errorf("oldest search should have been trimmed")
+		}
+	}
+
+	if err := svc.RecordSearch(ctx, "u1", models.SearchFilters{Query: "x", Sort: "popularity"}); statusOf(err) != http.StatusBadRequest {
+		t.Errorf("unknown sort: got %v, want 400", err)
+	}
+}
+
+func TestSavedSearchRoundTrip(t *testing.T) {
+	svc := NewService(NewRepository(setupTestDB(t)))
+	ctx := context.Background()
+
+	req := models.SaveSearchRequest{
+		Name:          "Long action",
+		SearchFilters: models.SearchFilters{Query: "blade", Genre: "action", Sort: "chapters", Order: "asc"},
+	}
+	saved, err := svc.SaveSearch(ctx, "u1", req)
+	if err != nil {
+		t.Fatalf("SaveSearch: %v", err)
+	}
+	if _, err := svc.SaveSearch(ctx, "u1", req); statusOf(err) != http.StatusConflict {
+		t.Errorf("duplicate name: got %v, want 409", err)
+	}
+	if _, err := svc.SaveSearch(ctx, "u1", models.SaveSearchRequest{Name: "Nothing"}); statusOf(err) != http.StatusBadRequest {
+		t.Errorf("empty search: got %v, want 400", err)
+	}
+
+	got, err := svc.GetSearches(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetSearches: %v", err)
+	}
+	if len(got.Saved) != 1 || got.Saved[0].SearchFilters != req.SearchFilters || got.Saved[0].Name != req.Name {
+		t.Fatalf("saved searches = %+v, want the one saved", got.Saved)
+	}
+
+	// Only the owner can delete it
+	if err := svc.DeleteSavedSearch(ctx, saved.ID, "u2"); statusOf(err) != http.StatusNotFound {
+		t.Errorf("delete by other user: got %v, want 404", err)
+	}
+	if err := svc.DeleteSavedSearch(ctx, saved.ID, "u1"); err != nil {
+		t.Fatalf("DeleteSavedSearch: %v", err)
+	}
+	if got, _ := svc.GetSearches(ctx, "u1"); len(got.Saved) != 0 {
+		t.Errorf("saved search still listed after delete")
+	}
+}
+
+// statusOf returns the HTTP status of an AppError, 0 for nil
+func statusOf(err error) int {
+	if appErr, ok := err.(*models.AppError); ok {
+		return appErr.StatusCode
+	}
+	return 0
+}
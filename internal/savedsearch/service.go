@@ -0,0 +1,132 @@
+// Package savedsearch - Search History & Saved Searches Service
+// Business logic layer cho lịch sử tìm kiếm và saved searches
+// Chức năng:
+//   - Chuẩn hoá query/filters (trim, genre slug chữ thường) trước khi lưu
+//   - Validate requests
+//   - Map lỗi repository thành AppError (404, 409 "name already used")
+package savedsearch
+
+import (
+	"context"
+	"errors"
+	"strings"
+
+	"mangahub/pkg/apperrors"
+	"mangahub/pkg/models"
+	"mangahub/pkg/utils"
+)
+
+// Service defines business operations for search history and saved searches
+type Service interface {
+	// GetSearches returns the user's saved searches and recent history
+	GetSearches(ctx context.Context, userID string) (*models.SearchesResponse, error)
+
+	// RecordSearch adds a search to the user's history; empty searches are ignored
+	RecordSearch(ctx context.Context, userID string, filters models.SearchFilters) error
+
+	// ClearHistory removes the user's search history
+	ClearHistory(ctx context.Context, userID string) error
+
+	// SaveSearch stores a named search
+	SaveSearch(ctx context.Context, userID string, req models.SaveSearchRequest) (*models.SavedSearch, error)
+
+	// DeleteSavedSearch removes one of the user's saved searches
+	DeleteSavedSearch(ctx context.Context, id, userID string) error
+}
+
+type service struct {
+	repo Repository
+}
+
+// NewService creates a new search history service
+func NewService(repo Repository) Service {
+	return &service{repo: repo}
+}
+
+// GetSearches returns the user's saved searches and recent history
+func (s *service) GetSearches(ctx context.Context, userID string) (*models.SearchesResponse, error) {
+	saved, err := s.repo.SavedSearches(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Internal("failed to get saved searches", err)
+	}
+	recent, err := s.repo.RecentSearches(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Internal("failed to get search history", err)
+	}
+	return &models.SearchesResponse{Saved: saved, Recent: recent}, nil
+}
+
+// RecordSearch adds a search to the user's history
+func (s *service) RecordSearch(ctx context.Context, userID string, filters models.SearchFilters) error {
+	filters = normalizeFilters(filters)
+	if err := utils.ValidateStruct(filters); err != nil {
+		return apperrors.Invalid("invalid search", err)
+	}
+	if filters.IsEmpty() {
+		return nil
+	}
+	if err := s.repo.RecordSearch(ctx, userID, filters); err != nil {
+		return apperrors.Internal("failed to record search", err)
+	}
+	return nil
+}
+
+// ClearHistory removes the user's search history
+func (s *service) ClearHistory(ctx context.Context, userID string) error {
+	if err := s.repo.ClearHistory(ctx, userID); err != nil {
+		return apperrors.Internal("failed to clear search history", err)
+	}
+	return nil
+}
+
+// SaveSearch stores a named search
+func (s *service) SaveSearch(ctx context.Context, userID string, req models.SaveSearchRequest) (*models.SavedSearch, error) {
+	req.Name = strings.TrimSpace(req.Name)
+	req.SearchFilters = normalizeFilters(req.SearchFilters)
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, apperrors.Invalid("invalid saved search", err)
+	}
+	if req.SearchFilters.IsEmpty() {
+		return nil, apperrors.Validation("query", "a saved search needs a query or a filter")
+	}
+
+	saved := &models.SavedSearch{
+		UserID:        userID,
+		Name:          req.Name,
+		SearchFilters: req.SearchFilters,
+	}
+	if err := s.repo.CreateSavedSearch(ctx, saved); err != nil {
+		return nil, searchError(err, "failed to save search")
+	}
+	return saved, nil
+}
+
+// DeleteSavedSearch removes one of the user's saved searches
+func (s *service) DeleteSavedSearch(ctx context.Context, id, userID string) error {
+	if err := s.repo.DeleteSavedSearch(ctx, id, userID); err != nil {
+		return searchError(err, "failed to delete saved search")
+	}
+	return nil
+}
+
+// normalizeFilters trims the query and lowercases the genre slug, sort and order
+// so the same search made twice is stored once
+func normalizeFilters(f models.SearchFilters) models.SearchFilters {
+	f.Query = strings.TrimSpace(f.Query)
+	f.Genre = strings.ToLower(strings.TrimSpace(f.Genre))
+	f.Sort = strings.ToLower(strings.TrimSpace(f.Sort))
+	f.Order = strings.ToLower(strings.TrimSpace(f.Order))
+	return f
+}
+
+// searchError maps repository errors to AppErrors
+func searchError(err error, internalMsg string) error {
+	switch {
+	case errors.Is(err, models.ErrSearchNotFound):
+		return apperrors.NotFound("saved search not found", err)
+	case errors.Is(err, models.ErrSearchNameExists):
+		return apperrors.Conflict("a saved search with this name already exists", err)
+	default:
+		return apperrors.Internal(internalMsg, err)
+	}
+}
@@ -0,0 +1,95 @@
+// Package statistics - Reading Stats Cache
+// Cache models.ReadingStats theo user (Redis, hoặc in-memory khi không có Redis)
+// Chức năng:
+//   - Key riêng cho từng user: không lẫn stats giữa các user
+//   - Single-flight: nhiều request cùng lúc chỉ tính stats một lần
+//   - Invalidate khi user ghi (hoặc undo) một chapter read
+package statistics
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+	"sync"
+	"time"
+
+	"golang.org/x/sync/singleflight"
+	"mangahub/pkg/cache"
+	"mangahub/pkg/models"
+)
+
+// statsCacheTTL bounds staleness from things that are not invalidated,
+// such as a streak ending at midnight
+const statsCacheTTL = 2 * time.Minute
+
+// statsCache caches assembled reading stats per user.
+// Each invalidation bumps the user's generation: a computation started
+// before it neither stores its result nor is joined by later callers.
+type statsCache struct {
+	store cache.Cache
+	group singleflight.Group
+
+	mu   sync.Mutex
+	gens map[string]uint64 // userID -> generation, only for users invalidated so far
+}
+
+func newStatsCache(store cache.Cache) *statsCache {
+	return &statsCache{store: store, gens: make(map[string]uint64)}
+}
+
+func (c *statsCache) generation(userID string) uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.gens[userID]
+}
+
+// get returns the cached stats for a user, or computes and stores them.
+// Concurrent misses for the same user share one computation. Cache errors
+// are treated as misses.
+func (c *statsCache) get(ctx context.Context, userID string, compute func(context.Context) (*models.ReadingStats, error)) (*models.ReadingStats, error) {
+	if raw, err := c.store.Get(ctx, cache.StatsKey(userID)); err == nil && raw != "" {
+		var stats models.ReadingStats
+		if json.Unmarshal([]byte(raw), &stats) == nil {
+			return &stats, nil
+		}
+	}
+
+	gen := c.generation(userID)
+	v, err, _ := c.group.Do(userID+"#"+strconv.FormatUint(gen, 10), func() (interface{}, error) {
+		// Detached so one caller going away does not fail the others waiting on it
+		stats, err := compute(context.WithoutCancel(ctx))
+		if err != nil {
+			return nil, err
+		}
+		c.storeIfCurrent(context.WithoutCancel(ctx), userID, gen, stats)
+		return stats, nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// Callers sharing a computation each get their own copy
+	stats := *v.(*models.ReadingStats)
+	return &stats, nil
+}
+
+// storeIfCurrent caches stats computed at generation gen unless the user
+// was invalidated since. The check and the write happen under mu, so an
+// invalidation either sees the write and deletes it, or prevents it.
+func (c *statsCache) storeIfCurrent(ctx context.Context, userID string, gen uint64, stats *models.ReadingStats) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.gens[userID] != gen {
+		return
+	}
+	_ = c.store.Set(ctx, cache.StatsKey(userID), stats, statsCacheTTL)
+}
+
+// invalidate drops a user's cached stats
+func (c *statsCache) invalidate(ctx context.Context, userID string) {
+	c.mu.Lock()
+	c.gens[userID]++
+	c.mu.Unlock()
+
+	_ = c.store.Delete(ctx, cache.StatsKey(userID))
+}
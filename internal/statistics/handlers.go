@@ -0,0 +1,180 @@
+// Package statistics - Reading Statistics HTTP Handlers
+// HTTP handlers cho reading history API endpoints
+// Endpoints:
+//   - POST /users/chapter-history - Record a chapter read
+//   - GET /users/chapter-history - List recent chapter reads
+//   - DELETE /users/chapter-history/:id - Undo a chapter read
+//   - GET /users/stats - Reading totals, streaks, genres, monthly totals and records
+//   - GET /users/stats/overview - Dashboard summary (totals, streaks, this week)
+//   - GET /users/stats/heatmap - Chapters read per day, zero days included
+//   - GET /users/stats/genres - Genre distribution of chapters read
+package statistics
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"mangahub/internal/auth"
+	"mangahub/pkg/apperrors"
+	"mangahub/pkg/models"
+)
+
+// Handler handles HTTP requests for reading statistics
+type Handler struct {
+	svc Service
+}
+
+// NewHandler creates a new statistics handler
+func NewHandler(svc Service) *Handler {
+	return &Handler{svc: svc}
+}
+
+// RecordChapterRead handles POST /users/chapter-history
+// Request body: { manga_id, chapter_number, pages_read, time_minutes }
+func (h *Handler) RecordChapterRead(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	var req models.RecordChapterRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest,
+			models.NewErrorResponse(models.ErrCodeBadRequest, "invalid JSON body", map[string]interface{}{"error": err.Error()}))
+		return
+	}
+
+	entry, err := h.svc.RecordChapterRead(c.Request.Context(), user.ID, req)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to record chapter read")
+		return
+	}
+
+	c.JSON(http.StatusCreated,
+		models.NewSuccessResponse(entry, "chapter read recorded"))
+}
+
+// GetHistory handles GET /users/chapter-history
+// Query params: ?limit=20&offset=0
+func (h *Handler) GetHistory(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	offset, _ := strconv.Atoi(c.DefaultQuery("offset", "0"))
+
+	history, err := h.svc.GetHistory(c.Request.Context(), user.ID, limit, offset)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to get chapter history")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(history, "chapter history"))
+}
+
+// DeleteChapterRead handles DELETE /users/chapter-history/:id
+// Returns the removed entry so the client can rewind its progress
+func (h *Handler) DeleteChapterRead(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	entry, err := h.svc.DeleteChapterRead(c.Request.Context(), user.ID, c.Param("id"))
+	if err != nil {
+		apperrors.Respond(c, err, "failed to delete chapter read")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(entry, "chapter read removed"))
+}
+
+// GetGenreDistribution handles GET /users/stats/genres
+func (h *Handler) GetGenreDistribution(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	stats, err := h.svc.GetGenreDistribution(c.Request.Context(), user.ID)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to get genre distribution")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(stats, "genre distribution"))
+}
+
+// GetReadingStats handles GET /users/stats
+func (h *Handler) GetReadingStats(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	stats, err := h.svc.GetReadingStats(c.Request.Context(), user.ID)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to get reading stats")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(stats, "reading stats"))
+}
+
+// GetStatsOverview handles GET /users/stats/overview
+func (h *Handler) GetStatsOverview(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	overview, err := h.svc.GetStatsOverview(c.Request.Context(), user.ID)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to get stats overview")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(overview, "stats overview"))
+}
+
+// GetReadingHeatmap handles GET /users/stats/heatmap
+// Query params: ?days=90 (max 366)
+func (h *Handler) GetReadingHeatmap(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		c.JSON(http.StatusUnauthorized,
+			models.NewErrorResponse(models.ErrCodeUnauthorized, "authentication required", nil))
+		return
+	}
+
+	days, _ := strconv.Atoi(c.DefaultQuery("days", strconv.Itoa(models.DefaultHeatmapDays)))
+
+	heatmap, err := h.svc.GetReadingHeatmap(c.Request.Context(), user.ID, days)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to get reading heatmap")
+		return
+	}
+
+	c.JSON(http.StatusOK,
+		models.NewSuccessResponse(heatmap, "reading heatmap"))
+}
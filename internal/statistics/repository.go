@@ -0,0 +1,470 @@
+// Package statistics - Reading Statistics Repository
+// Data access layer cho chapter reading history
+// Chức năng:
+//   - Record chapter reads (pages, minutes) và undo một lần đọc
+//   - Ghi nhiều chapter trong một transaction (catch up, binge), daily_stats cập nhật một lần mỗi ngày
+//   - Ghi một dải chapter khi "catch up", bỏ qua chapter đã có history
+//   - Query reading history cho streaks/heatmap
+//   - Phân bố thể loại (join manga_genres/genres)
+//   - daily_stats: rollup theo ngày (UTC) cho streaks, monthly và heatmap
+//   - streak_state: grace day đã dùng của current streak
+package statistics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"mangahub/pkg/models"
+)
+
+// Repository defines data access operations for reading statistics
+type Repository interface {
+	// RecordChapterRead inserts a chapter history entry
+	RecordChapterRead(ctx context.Context, userID string, req models.RecordChapterRequest) (*models.ChapterHistory, error)
+
+	// RecordChaptersRead inserts several chapter history entries at once, all or nothing
+	RecordChaptersRead(ctx context.Context, histories []models.ChapterHistory) error
+
+	// RecordChapterRange records chapters from..to of a manga that have no
+	// history entry yet, and returns how many were recorded
+	RecordChapterRange(ctx context.Context, userID, mangaID string, from, to int) (int, error)
+
+	// DeleteChapterRead removes one of the user's history entries, nil if none
+	DeleteChapterRead(ctx context.Context, userID, historyID string) (*models.ChapterHistory, error)
+
+	// GetHistory retrieves a user's most recent chapter reads
+	GetHistory(ctx context.Context, userID string, limit, offset int) ([]models.ChapterHistory, error)
+
+	// GetReadingStats aggregates a user's chapter history
+	GetReadingStats(ctx context.Context, userID string) (*models.ReadingStats, error)
+
+	// GetGenreDistribution counts the manga and chapters a user has read per genre
+	GetGenreDistribution(ctx context.Context, userID string) ([]models.GenreStat, error)
+
+	// GetDailyStats returns the days since from (YYYY-MM-DD, "" for all) with any reading, oldest first
+	GetDailyStats(ctx context.Context, userID, from string) ([]models.HeatmapDay, error)
+
+	// GetRecords returns a user's best day and most read manga
+	GetRecords(ctx context.Context, userID string) (*models.ReadingRecords, error)
+
+	// SaveStreakState records a user's current streak and the grace days it
+	// consumed in period (YYYY-MM)
+	SaveStreakState(ctx context.Context, userID string, current int, period string, grace models.StreakGrace) error
+}
+
+// dateLayout is the YYYY-MM-DD format of daily_stats.stat_date
+const dateLayout = "2006-01-02"
+
+type repository struct {
+	db *sql.DB
+}
+
+// NewRepository creates a new statistics repository
+func NewRepository(db *sql.DB) Repository {
+	return &repository{db: db}
+}
+
+// RecordChapterRead inserts a chapter history entry
+func (r *repository) RecordChapterRead(ctx context.Context, userID string, req models.RecordChapterRequest) (*models.ChapterHistory, error) {
+	h := models.ChapterHistory{
+		ID:            uuid.New().String(),
+		UserID:        userID,
+		MangaID:       req.MangaID,
+		ChapterNumber: req.ChapterNumber,
+		PagesRead:     req.PagesRead,
+		TimeMinutes:   req.TimeMinutes,
+		ReadAt:        time.Now(),
+	}
+
+	if err := r.RecordChaptersRead(ctx, []models.ChapterHistory{h}); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// RecordChaptersRead inserts several chapter history entries in one
+// transaction: either all are recorded or none. daily_stats gets one upsert
+// and one manga_count recount per user and UTC day touched, instead of one
+// per chapter. Entries without an ID or ReadAt get them filled in place.
+func (r *repository) RecordChaptersRead(ctx context.Context, histories []models.ChapterHistory) error {
+	if len(histories) == 0 {
+		return nil
+	}
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin chapter history tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := insertChapterHistories(ctx, tx, histories); err != nil {
+		return err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit chapter history: %w", err)
+	}
+	return nil
+}
+
+// insertChapterHistories inserts histories within tx and rolls them into
+// daily_stats, one row update per user and day
+func insertChapterHistories(ctx context.Context, tx *sql.Tx, histories []models.ChapterHistory) error {
+	type dayKey struct {
+		userID string
+		day    string
+	}
+	type dayTotals struct {
+		chapters, pages, minutes int
+		readAt                   time.Time
+	}
+	totals := make(map[dayKey]*dayTotals)
+	var days []dayKey
+
+	now := time.Now()
+	for i := range histories {
+		h := &histories[i]
+		if h.ID == "" {
+			h.ID = uuid.New().String()
+		}
+		if h.ReadAt.IsZero() {
+			h.ReadAt = now
+		}
+
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO chapter_history
+			(id, user_id, manga_id, chapter_number, pages_read, time_minutes, read_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)`,
+			h.ID, h.UserID, h.MangaID, h.ChapterNumber, h.PagesRead, h.TimeMinutes, h.ReadAt,
+		)
+		if err != nil {
+			return fmt.Errorf("insert chapter history: %w", err)
+		}
+
+		key := dayKey{userID: h.UserID, day: h.ReadAt.UTC().Format(dateLayout)}
+		t, ok := totals[key]
+		if !ok {
+			t = &dayTotals{readAt: h.ReadAt}
+			totals[key] = t
+			days = append(days, key)
+		}
+		t.chapters++
+		t.pages += h.PagesRead
+		t.minutes += h.TimeMinutes
+	}
+
+	// Keep the per-day rollup in step with the history
+	for _, key := range days {
+		t := totals[key]
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO daily_stats (user_id, stat_date, chapters_read, pages_read, time_minutes, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?)
+			ON CONFLICT(user_id, stat_date) DO UPDATE SET
+				chapters_read = chapters_read + excluded.chapters_read,
+				pages_read = pages_read + excluded.pages_read,
+				time_minutes = time_minutes + excluded.time_minutes,
+				updated_at = excluded.updated_at`,
+			key.userID, key.day, t.chapters, t.pages, t.minutes, now,
+		)
+		if err != nil {
+			return fmt.Errorf("update daily stats: %w", err)
+		}
+		if err := refreshMangaCount(ctx, tx, key.userID, t.readAt); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RecordChapterRange records chapters from..to of a manga that have no history
+// entry yet, with no pages or minutes, and returns how many were recorded.
+// Chapters already in the history are skipped, so repeating a range adds nothing.
+func (r *repository) RecordChapterRange(ctx context.Context, userID, mangaID string, from, to int) (int, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("begin chapter history tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	rows, err := tx.QueryContext(ctx, `
+		SELECT DISTINCT chapter_number FROM chapter_history
+		WHERE user_id = ? AND manga_id = ? AND chapter_number BETWEEN ? AND ?`,
+		userID, mangaID, from, to,
+	)
+	if err != nil {
+		return 0, fmt.Errorf("list recorded chapters: %w", err)
+	}
+	recorded := make(map[int]bool)
+	for rows.Next() {
+		var ch int
+		if err := rows.Scan(&ch); err != nil {
+			rows.Close()
+			return 0, fmt.Errorf("scan recorded chapter: %w", err)
+		}
+		recorded[ch] = true
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, fmt.Errorf("list recorded chapters: %w", err)
+	}
+
+	now := time.Now()
+	var histories []models.ChapterHistory
+	for ch := from; ch <= to; ch++ {
+		if recorded[ch] {
+			continue
+		}
+		histories = append(histories, models.ChapterHistory{
+			UserID:        userID,
+			MangaID:       mangaID,
+			ChapterNumber: ch,
+			ReadAt:        now,
+		})
+	}
+	if len(histories) == 0 {
+		return 0, nil
+	}
+
+	// The whole range lands on one day: a single daily_stats update
+	if err := insertChapterHistories(ctx, tx, histories); err != nil {
+		return 0, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("commit chapter history: %w", err)
+	}
+	return len(histories), nil
+}
+
+// DeleteChapterRead removes one of the user's history entries, nil if none.
+// The daily_stats row of the day it was read on (not today) is decremented,
+// clamped at zero; a day left at zero chapters drops out of streaks.
+func (r *repository) DeleteChapterRead(ctx context.Context, userID, historyID string) (*models.ChapterHistory, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("begin chapter history tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var h models.ChapterHistory
+	err = tx.QueryRowContext(ctx, `
+		SELECT id, user_id, manga_id, chapter_number, pages_read, time_minutes, read_at
+		FROM chapter_history
+		WHERE id = ? AND user_id = ?`, historyID, userID,
+	).Scan(&h.ID, &h.UserID, &h.MangaID, &h.ChapterNumber, &h.PagesRead, &h.TimeMinutes, &h.ReadAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get chapter history: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM chapter_history WHERE id = ?", h.ID); err != nil {
+		return nil, fmt.Errorf("delete chapter history: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `
+		UPDATE daily_stats SET
+			chapters_read = MAX(chapters_read - 1, 0),
+			pages_read = MAX(pages_read - ?, 0),
+			time_minutes = MAX(time_minutes - ?, 0),
+			updated_at = ?
+		WHERE user_id = ? AND stat_date = ?`,
+		h.PagesRead, h.TimeMinutes, time.Now(), h.UserID, h.ReadAt.UTC().Format(dateLayout),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("update daily stats: %w", err)
+	}
+	if err := refreshMangaCount(ctx, tx, h.UserID, h.ReadAt); err != nil {
+		return nil, err
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("commit chapter history: %w", err)
+	}
+	return &h, nil
+}
+
+// refreshMangaCount recounts the distinct manga read on readAt's day.
+// A count can't be adjusted by one like chapters_read: removing a chapter
+// only lowers it if no other chapter of that manga was read the same day.
+func refreshMangaCount(ctx context.Context, tx *sql.Tx, userID string, readAt time.Time) error {
+	day := readAt.UTC().Format(dateLayout)
+	_, err := tx.ExecContext(ctx, `
+		UPDATE daily_stats SET manga_count = (
+			SELECT COUNT(DISTINCT manga_id) FROM chapter_history
+			WHERE user_id = ? AND date(read_at) = ?
+		)
+		WHERE user_id = ? AND stat_date = ?`,
+		userID, day, userID, day,
+	)
+	if err != nil {
+		return fmt.Errorf("update daily manga count: %w", err)
+	}
+	return nil
+}
+
+// GetHistory retrieves a user's most recent chapter reads
+func (r *repository) GetHistory(ctx context.Context, userID string, limit, offset int) ([]models.ChapterHistory, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT id, user_id, manga_id, chapter_number, pages_read, time_minutes, read_at
+		FROM chapter_history
+		WHERE user_id = ?
+		ORDER BY read_at DESC
+		LIMIT ? OFFSET ?`, userID, limit, offset,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get chapter history: %w", err)
+	}
+	defer rows.Close()
+
+	var history []models.ChapterHistory
+	for rows.Next() {
+		var h models.ChapterHistory
+		if err := rows.Scan(
+			&h.ID, &h.UserID, &h.MangaID, &h.ChapterNumber,
+			&h.PagesRead, &h.TimeMinutes, &h.ReadAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan chapter history: %w", err)
+		}
+		history = append(history, h)
+	}
+	return history, nil
+}
+
+// GetGenreDistribution counts the manga and chapters a user has read per genre.
+// A manga with several genres counts towards each of them.
+func (r *repository) GetGenreDistribution(ctx context.Context, userID string) ([]models.GenreStat, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT g.name, COUNT(DISTINCT ch.manga_id), COUNT(*)
+		FROM chapter_history ch
+		JOIN manga m ON m.id = ch.manga_id
+		JOIN manga_genres mg ON mg.manga_id = m.id
+		JOIN genres g ON g.id = mg.genre_id
+		WHERE ch.user_id = ?
+		GROUP BY g.name
+		ORDER BY COUNT(DISTINCT ch.manga_id) DESC, COUNT(*) DESC, g.name ASC`, userID,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get genre distribution: %w", err)
+	}
+	defer rows.Close()
+
+	stats := []models.GenreStat{}
+	total := 0
+	for rows.Next() {
+		var s models.GenreStat
+		if err := rows.Scan(&s.Genre, &s.MangaCount, &s.Chapters); err != nil {
+			return nil, fmt.Errorf("scan genre distribution: %w", err)
+		}
+		total += s.MangaCount
+		stats = append(stats, s)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get genre distribution: %w", err)
+	}
+
+	for i := range stats {
+		stats[i].Percentage = float64(stats[i].MangaCount) * 100 / float64(total)
+	}
+	return stats, nil
+}
+
+// GetReadingStats aggregates a user's chapter history
+func (r *repository) GetReadingStats(ctx context.Context, userID string) (*models.ReadingStats, error) {
+	var stats models.ReadingStats
+	err := r.db.QueryRowContext(ctx, `
+		SELECT COUNT(*), COALESCE(SUM(time_minutes), 0), COUNT(DISTINCT manga_id)
+		FROM chapter_history
+		WHERE user_id = ?`, userID,
+	).Scan(&stats.TotalChapters, &stats.TotalMinutes, &stats.MangaRead)
+	if err != nil {
+		return nil, fmt.Errorf("get reading stats: %w", err)
+	}
+
+	if stats.TotalChapters > 0 {
+		stats.AvgMinutesPerChapter = float64(stats.TotalMinutes) / float64(stats.TotalChapters)
+	}
+	return &stats, nil
+}
+
+// GetDailyStats returns the days since from (YYYY-MM-DD, "" for all) with any reading, oldest first
+func (r *repository) GetDailyStats(ctx context.Context, userID, from string) ([]models.HeatmapDay, error) {
+	rows, err := r.db.QueryContext(ctx, `
+		SELECT stat_date, chapters_read, time_minutes, COALESCE(manga_count, 0)
+		FROM daily_stats
+		WHERE user_id = ? AND stat_date >= ? AND chapters_read > 0
+		ORDER BY stat_date ASC`, userID, from,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("get daily stats: %w", err)
+	}
+	defer rows.Close()
+
+	var days []models.HeatmapDay
+	for rows.Next() {
+		var d models.HeatmapDay
+		if err := rows.Scan(&d.Date, &d.Chapters, &d.Minutes, &d.Manga); err != nil {
+			return nil, fmt.Errorf("scan daily stats: %w", err)
+		}
+		days = append(days, d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("get daily stats: %w", err)
+	}
+	return days, nil
+}
+
+// GetRecords returns a user's best day and most read manga
+func (r *repository) GetRecords(ctx context.Context, userID string) (*models.ReadingRecords, error) {
+	var rec models.ReadingRecords
+
+	err := r.db.QueryRowContext(ctx, `
+		SELECT stat_date, chapters_read
+		FROM daily_stats
+		WHERE user_id = ? AND chapters_read > 0
+		ORDER BY chapters_read DESC, stat_date DESC
+		LIMIT 1`, userID,
+	).Scan(&rec.BestDay, &rec.BestDayChapters)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("get best day: %w", err)
+	}
+
+	err = r.db.QueryRowContext(ctx, `
+		SELECT ch.manga_id, m.title, COUNT(*)
+		FROM chapter_history ch
+		JOIN manga m ON m.id = ch.manga_id
+		WHERE ch.user_id = ?
+		GROUP BY ch.manga_id
+		ORDER BY COUNT(*) DESC, MAX(ch.read_at) DESC
+		LIMIT 1`, userID,
+	).Scan(&rec.TopMangaID, &rec.TopMangaTitle, &rec.TopMangaChapters)
+	if err != nil && err != sql.ErrNoRows {
+		return nil, fmt.Errorf("get top manga: %w", err)
+	}
+
+	return &rec, nil
+}
+
+// SaveStreakState upserts the user's streak_state row
+func (r *repository) SaveStreakState(ctx context.Context, userID string, current int, period string, grace models.StreakGrace) error {
+	_, err := r.db.ExecContext(ctx, `
+		INSERT INTO streak_state (user_id, current_streak, grace_period, grace_used, grace_dates, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id) DO UPDATE SET
+			current_streak = excluded.current_streak,
+			grace_period = excluded.grace_period,
+			grace_used = excluded.grace_used,
+			grace_dates = excluded.grace_dates,
+			updated_at = excluded.updated_at`,
+		userID, current, period, grace.Used, strings.Join(grace.Dates, ","), time.Now(),
+	)
+	if err != nil {
+		return fmt.Errorf("save streak state: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,316 @@
+// Package statistics - Reading Statistics Service
+// Business logic layer cho reading history & statistics
+// Chức năng:
+//   - Validate chapter read records, undo một lần đọc nhầm
+//   - Paginate reading history
+//   - Genre distribution
+//   - Streaks, monthly totals, records và heatmap (từ daily_stats, theo ngày UTC)
+//   - Grace day cho current streak (streak.go), lưu vào streak_state
+//   - Cache reading stats theo user, invalidate khi có chapter read mới hoặc undo
+package statistics
+
+import (
+	"context"
+	"time"
+
+	"mangahub/pkg/apperrors"
+	"mangahub/pkg/cache"
+	"mangahub/pkg/logger"
+	"mangahub/pkg/models"
+	"mangahub/pkg/utils"
+)
+
+// Service defines business operations for reading statistics
+type Service interface {
+	// RecordChapterRead records that a user finished reading a chapter
+	RecordChapterRead(ctx context.Context, userID string, req models.RecordChapterRequest) (*models.ChapterHistory, error)
+
+	// RecordChapterRange records the chapters from..to of a manga not read before
+	RecordChapterRange(ctx context.Context, userID, mangaID string, from, to int) (int, error)
+
+	// DeleteChapterRead undoes a chapter read recorded by mistake
+	DeleteChapterRead(ctx context.Context, userID, historyID string) (*models.ChapterHistory, error)
+
+	// GetHistory returns a user's recent chapter reads
+	GetHistory(ctx context.Context, userID string, limit, offset int) ([]models.ChapterHistory, error)
+
+	// GetReadingStats returns totals and averages over a user's chapter history
+	GetReadingStats(ctx context.Context, userID string) (*models.ReadingStats, error)
+
+	// GetGenreDistribution returns the share of each genre in a user's reading
+	GetGenreDistribution(ctx context.Context, userID string) ([]models.GenreStat, error)
+
+	// GetStatsOverview returns the dashboard summary of a user's reading
+	GetStatsOverview(ctx context.Context, userID string) (*models.StatsOverview, error)
+
+	// GetReadingHeatmap returns one entry per day for the last days days, today last
+	GetReadingHeatmap(ctx context.Context, userID string, days int) ([]models.HeatmapDay, error)
+}
+
+type service struct {
+	repo      Repository
+	now       func() time.Time
+	stats     *statsCache // optional
+	graceDays int         // grace days per month for the current streak
+}
+
+// NewService creates a new statistics service
+func NewService(repo Repository) Service {
+	return &service{repo: repo, now: time.Now, graceDays: StreakGraceDays}
+}
+
+// NewServiceWithCache creates a statistics service that caches reading stats per user
+func NewServiceWithCache(repo Repository, c cache.Cache) Service {
+	return &service{repo: repo, now: time.Now, stats: newStatsCache(c), graceDays: StreakGraceDays}
+}
+
+// today returns the current UTC day at midnight
+func (s *service) today() time.Time {
+	return s.now().UTC().Truncate(24 * time.Hour)
+}
+
+// RecordChapterRead validates and stores a chapter read
+func (s *service) RecordChapterRead(ctx context.Context, userID string, req models.RecordChapterRequest) (*models.ChapterHistory, error) {
+	if err := utils.ValidateStruct(req); err != nil {
+		return nil, apperrors.Invalid("invalid chapter history data", err)
+	}
+
+	entry, err := s.repo.RecordChapterRead(ctx, userID, req)
+	if err != nil {
+		return nil, apperrors.Internal("failed to record chapter read", err)
+	}
+	s.invalidateStats(ctx, userID)
+	return entry, nil
+}
+
+// RecordChapterRange records the chapters from..to of a manga that are not in
+// the user's history yet, e.g. when catching up on chapters read elsewhere.
+// It returns how many chapters were recorded.
+func (s *service) RecordChapterRange(ctx context.Context, userID, mangaID string, from, to int) (int, error) {
+	switch {
+	case mangaID == "":
+		return 0, apperrors.Validation("manga_id", "manga_id is required")
+	case from < 1:
+		return 0, apperrors.Validation("from", "invalid chapter range")
+	case to < from:
+		return 0, apperrors.Validation("to", "invalid chapter range")
+	}
+
+	added, err := s.repo.RecordChapterRange(ctx, userID, mangaID, from, to)
+	if err != nil {
+		return 0, apperrors.Internal("failed to record chapter reads", err)
+	}
+	if added > 0 {
+		s.invalidateStats(ctx, userID)
+	}
+	return added, nil
+}
+
+// DeleteChapterRead undoes a chapter read recorded by mistake.
+// Entries of other users are reported as not found.
+func (s *service) DeleteChapterRead(ctx context.Context, userID, historyID string) (*models.ChapterHistory, error) {
+	entry, err := s.repo.DeleteChapterRead(ctx, userID, historyID)
+	if err != nil {
+		return nil, apperrors.Internal("failed to delete chapter read", err)
+	}
+	if entry == nil {
+		return nil, apperrors.NotFound("chapter history entry not found", nil)
+	}
+	s.invalidateStats(ctx, userID)
+	return entry, nil
+}
+
+// GetHistory returns a user's recent chapter reads
+func (s *service) GetHistory(ctx context.Context, userID string, limit, offset int) ([]models.ChapterHistory, error) {
+	if limit <= 0 || limit > 100 {
+		limit = 20
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	history, err := s.repo.GetHistory(ctx, userID, limit, offset)
+	if err != nil {
+		return nil, apperrors.Internal("failed to get chapter history", err)
+	}
+	return history, nil
+}
+
+// GetGenreDistribution returns the share of each genre in a user's reading
+func (s *service) GetGenreDistribution(ctx context.Context, userID string) ([]models.GenreStat, error) {
+	stats, err := s.repo.GetGenreDistribution(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Internal("failed to get genre distribution", err)
+	}
+	return stats, nil
+}
+
+// GetReadingStats returns totals and averages over a user's chapter history.
+// With a cache, concurrent loads for a user share one computation and the
+// result is reused until the user records or undoes a chapter read.
+func (s *service) GetReadingStats(ctx context.Context, userID string) (*models.ReadingStats, error) {
+	if s.stats == nil {
+		return s.computeReadingStats(ctx, userID)
+	}
+	return s.stats.get(ctx, userID, func(ctx context.Context) (*models.ReadingStats, error) {
+		return s.computeReadingStats(ctx, userID)
+	})
+}
+
+// invalidateStats drops a user's cached reading stats
+func (s *service) invalidateStats(ctx context.Context, userID string) {
+	if s.stats != nil {
+		s.stats.invalidate(ctx, userID)
+	}
+}
+
+// computeReadingStats assembles reading stats from the repository
+func (s *service) computeReadingStats(ctx context.Context, userID string) (*models.ReadingStats, error) {
+	stats, err := s.repo.GetReadingStats(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Internal("failed to get reading stats", err)
+	}
+
+	days, err := s.repo.GetDailyStats(ctx, userID, "")
+	if err != nil {
+		return nil, apperrors.Internal("failed to get reading stats", err)
+	}
+	stats.CurrentStreak, stats.LongestStreak, stats.Grace = s.streakSummary(ctx, userID, days, s.today())
+	stats.Monthly = monthlyStats(days, s.today())
+
+	if stats.Genres, err = s.repo.GetGenreDistribution(ctx, userID); err != nil {
+		return nil, apperrors.Internal("failed to get reading stats", err)
+	}
+
+	records, err := s.repo.GetRecords(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Internal("failed to get reading stats", err)
+	}
+	stats.Records = *records
+	stats.UpdatedAt = s.now().UTC()
+
+	return stats, nil
+}
+
+// GetStatsOverview returns the dashboard summary of a user's reading
+func (s *service) GetStatsOverview(ctx context.Context, userID string) (*models.StatsOverview, error) {
+	stats, err := s.repo.GetReadingStats(ctx, userID)
+	if err != nil {
+		return nil, apperrors.Internal("failed to get stats overview", err)
+	}
+	days, err := s.repo.GetDailyStats(ctx, userID, "")
+	if err != nil {
+		return nil, apperrors.Internal("failed to get stats overview", err)
+	}
+
+	today := s.today()
+	overview := &models.StatsOverview{
+		TotalChapters: stats.TotalChapters,
+		TotalMinutes:  stats.TotalMinutes,
+		MangaRead:     stats.MangaRead,
+	}
+	overview.CurrentStreak, overview.LongestStreak, overview.Grace = s.streakSummary(ctx, userID, days, today)
+
+	weekStart := today.AddDate(0, 0, -6).Format(dateLayout)
+	for _, d := range days {
+		if d.Date >= weekStart {
+			overview.ChaptersThisWeek += d.Chapters
+		}
+	}
+	return overview, nil
+}
+
+// GetReadingHeatmap returns one entry per day for the last days days, today last.
+// Days without reading are included with zero counts.
+func (s *service) GetReadingHeatmap(ctx context.Context, userID string, days int) ([]models.HeatmapDay, error) {
+	if days <= 0 {
+		days = models.DefaultHeatmapDays
+	}
+	if days > models.MaxHeatmapDays {
+		days = models.MaxHeatmapDays
+	}
+
+	start := s.today().AddDate(0, 0, -(days - 1))
+	active, err := s.repo.GetDailyStats(ctx, userID, start.Format(dateLayout))
+	if err != nil {
+		return nil, apperrors.Internal("failed to get reading heatmap", err)
+	}
+
+	byDate := make(map[string]models.HeatmapDay, len(active))
+	for _, d := range active {
+		byDate[d.Date] = d
+	}
+
+	heatmap := make([]models.HeatmapDay, days)
+	for i := range heatmap {
+		date := start.AddDate(0, 0, i).Format(dateLayout)
+		if d, ok := byDate[date]; ok {
+			heatmap[i] = d
+		} else {
+			heatmap[i] = models.HeatmapDay{Date: date}
+		}
+	}
+	return heatmap, nil
+}
+
+// streakSummary returns the current streak with grace days applied and the
+// longest streak without them, and records the grace consumed in streak_state.
+// Failing to record it does not fail the read.
+func (s *service) streakSummary(ctx context.Context, userID string, days []models.HeatmapDay, today time.Time) (current, longest int, grace models.StreakGrace) {
+	_, longest = streaks(days, today)
+	gs := currentStreakWithGrace(days, today, s.graceDays)
+	if err := s.repo.SaveStreakState(ctx, userID, gs.current, today.Format(monthLayout), gs.grace); err != nil {
+		logger.Warnf("Failed to save streak state for user %s: %v", userID, err)
+	}
+	return gs.current, longest, gs.grace
+}
+
+// streaks returns the current and longest runs of consecutive reading days.
+// The current streak survives until the end of the day after the last read.
+func streaks(days []models.HeatmapDay, today time.Time) (current, longest int) {
+	var run int
+	var prev time.Time
+	for _, d := range days {
+		date, err := time.Parse(dateLayout, d.Date)
+		if err != nil {
+			continue
+		}
+		if run > 0 && date.Sub(prev) == 24*time.Hour {
+			run++
+		} else {
+			run = 1
+		}
+		if run > longest {
+			longest = run
+		}
+		prev = date
+	}
+
+	if run > 0 && today.Sub(prev) <= 24*time.Hour {
+		current = run
+	}
+	return current, longest
+}
+
+// monthlyStats totals the last 12 calendar months, oldest first
+func monthlyStats(days []models.HeatmapDay, today time.Time) []models.MonthlyStat {
+	first := time.Date(today.Year(), today.Month(), 1, 0, 0, 0, 0, time.UTC).AddDate(0, -11, 0)
+
+	months := make([]models.MonthlyStat, 12)
+	index := make(map[string]int, len(months))
+	for i := range months {
+		months[i].Month = first.AddDate(0, i, 0).Format("2006-01")
+		index[months[i].Month] = i
+	}
+
+	for _, d := range days {
+		if len(d.Date) < 7 {
+			continue
+		}
+		if i, ok := index[d.Date[:7]]; ok {
+			months[i].Chapters += d.Chapters
+			months[i].Minutes += d.Minutes
+		}
+	}
+	return months
+}
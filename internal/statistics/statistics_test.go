@@ -0,0 +1,459 @@
+// Package statistics - Reading Statistics Tests
+// Unit tests cho reading stats, streak grace và genre distribution
+package statistics
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mangahub/internal/testutil"
+	"mangahub/pkg/cache"
+	"mangahub/pkg/models"
+)
+
+func mustExec(t *testing.T, db *sql.DB, query string, args ...interface{}) {
+	t.Helper()
+	if _, err := db.Exec(query, args...); err != nil {
+		t.Fatalf("exec failed: %v", err)
+	}
+}
+
+func TestGetGenreDistribution(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`)
+	mustExec(t, db, `INSERT INTO genres (id, name, slug) VALUES ('g-action', 'Action', 'action'), ('g-romance', 'Romance', 'romance')`)
+	mustExec(t, db, `INSERT INTO manga (id, title) VALUES ('m1', 'Berserk'), ('m2', 'Vagabond'), ('m3', 'Horimiya')`)
+	mustExec(t, db, `INSERT INTO manga_genres (id, manga_id, genre_id) VALUES ('mg1', 'm1', 'g-action'), ('mg2', 'm2', 'g-action'), ('mg3', 'm3', 'g-romance')`)
+
+	repo := NewRepository(db)
+	// Several chapters of one manga must not outweigh the number of manga read
+	for _, ch := range []struct {
+		manga   string
+		chapter int
+	}{{"m1", 1}, {"m2", 1}, {"m3", 1}, {"m3", 2}, {"m3", 3}} {
+		mustExec(t, db, `INSERT INTO chapter_history (id, user_id, manga_id, chapter_number) VALUES (?, 'u1', ?, ?)`,
+			fmt.Sprintf("%s-%d", ch.manga, ch.chapter), ch.manga, ch.chapter)
+	}
+
+	stats, err := NewService(repo).GetGenreDistribution(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetGenreDistribution failed: %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 genres, got %d: %+v", len(stats), stats)
+	}
+
+	action := stats[0]
+	if action.Genre != "Action" || action.MangaCount != 2 || action.Chapters != 2 {
+		t.Errorf("unexpected Action stat: %+v", action)
+	}
+	if math.Abs(action.Percentage-66.67) > 0.01 {
+		t.Errorf("expected Action at ~66%%, got %.2f", action.Percentage)
+	}
+	if stats[1].Genre != "Romance" || stats[1].Chapters != 3 {
+		t.Errorf("unexpected Romance stat: %+v", stats[1])
+	}
+
+	// A user with no history gets an empty distribution, not an error
+	empty, err := repo.GetGenreDistribution(ctx, "nobody")
+	if err != nil || len(empty) != 0 {
+		t.Errorf("expected empty distribution, got %+v (%v)", empty, err)
+	}
+}
+
+func TestGetReadingStats(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`)
+	mustExec(t, db, `INSERT INTO manga (id, title) VALUES ('m1', 'Berserk'), ('m2', 'Vagabond')`)
+
+	repo := NewRepository(db)
+	for _, req := range []models.RecordChapterRequest{
+		{MangaID: "m1", ChapterNumber: 1, TimeMinutes: 5},
+		{MangaID: "m1", ChapterNumber: 2, TimeMinutes: 12},
+		{MangaID: "m2", ChapterNumber: 1, TimeMinutes: 7},
+	} {
+		if _, err := repo.RecordChapterRead(ctx, "u1", req); err != nil {
+			t.Fatalf("RecordChapterRead failed: %v", err)
+		}
+	}
+
+	// Every read also lands in today's daily_stats row
+	var chapters, minutes, manga int
+	err := db.QueryRow(`SELECT chapters_read, time_minutes, manga_count FROM daily_stats WHERE user_id = 'u1' AND stat_date = ?`,
+		time.Now().UTC().Format(dateLayout)).Scan(&chapters, &minutes, &manga)
+	if err != nil || chapters != 3 || minutes != 24 || manga != 2 {
+		t.Errorf("expected daily_stats 3 chapters/24 min/2 manga, got %d/%d/%d (%v)", chapters, minutes, manga, err)
+	}
+
+	stats, err := NewService(repo).GetReadingStats(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetReadingStats failed: %v", err)
+	}
+	if stats.TotalChapters != 3 || stats.TotalMinutes != 24 || stats.MangaRead != 2 || stats.AvgMinutesPerChapter != 8 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// No history means zero averages rather than a division by zero
+	empty, err := repo.GetReadingStats(ctx, "nobody")
+	if err != nil || empty.AvgMinutesPerChapter != 0 {
+		t.Errorf("expected empty stats, got %+v (%v)", empty, err)
+	}
+}
+
+func TestHeatmapAndStreaks(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`)
+	// Read on 03-01..03-03 (3 days), skipped 03-04, then 03-05..03-06
+	for _, d := range []struct {
+		date     string
+		chapters int
+	}{{"2026-02-20", 9}, {"2026-03-01", 2}, {"2026-03-02", 1}, {"2026-03-03", 4}, {"2026-03-05", 1}, {"2026-03-06", 3}} {
+		mustExec(t, db, `INSERT INTO daily_stats (user_id, stat_date, chapters_read, time_minutes) VALUES ('u1', ?, ?, ?)`,
+			d.date, d.chapters, d.chapters*10)
+	}
+
+	svc := &service{repo: NewRepository(db), now: func() time.Time {
+		return time.Date(2026, 3, 7, 15, 0, 0, 0, time.UTC)
+	}}
+
+	heatmap, err := svc.GetReadingHeatmap(ctx, "u1", 7)
+	if err != nil {
+		t.Fatalf("GetReadingHeatmap failed: %v", err)
+	}
+	want := []int{2, 1, 4, 0, 1, 3, 0}
+	if len(heatmap) != len(want) {
+		t.Fatalf("expected %d days, got %d: %+v", len(want), len(heatmap), heatmap)
+	}
+	for i, d := range heatmap {
+		if d.Chapters != want[i] {
+			t.Errorf("day %d (%s): expected %d chapters, got %d", i, d.Date, want[i], d.Chapters)
+		}
+	}
+	if heatmap[0].Date != "2026-03-01" || heatmap[6].Date != "2026-03-07" {
+		t.Errorf("unexpected range %s..%s", heatmap[0].Date, heatmap[6].Date)
+	}
+
+	// Out of range values fall back to the limits
+	if heatmap, _ := svc.GetReadingHeatmap(ctx, "u1", 0); len(heatmap) != models.DefaultHeatmapDays {
+		t.Errorf("expected %d days by default, got %d", models.DefaultHeatmapDays, len(heatmap))
+	}
+	if heatmap, _ := svc.GetReadingHeatmap(ctx, "u1", 5000); len(heatmap) != models.MaxHeatmapDays {
+		t.Errorf("expected at most %d days, got %d", models.MaxHeatmapDays, len(heatmap))
+	}
+
+	// Nothing read today yet, but yesterday keeps the streak alive
+	overview, err := svc.GetStatsOverview(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetStatsOverview failed: %v", err)
+	}
+	if overview.CurrentStreak != 2 || overview.LongestStreak != 3 || overview.ChaptersThisWeek != 11 {
+		t.Errorf("unexpected overview: %+v", overview)
+	}
+
+	// Two days without reading break it
+	svc.now = func() time.Time { return time.Date(2026, 3, 8, 0, 30, 0, 0, time.UTC) }
+	stats, err := svc.GetReadingStats(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetReadingStats failed: %v", err)
+	}
+	if stats.CurrentStreak != 0 || stats.LongestStreak != 3 {
+		t.Errorf("expected streaks 0/3, got %d/%d", stats.CurrentStreak, stats.LongestStreak)
+	}
+	if len(stats.Monthly) != 12 || stats.Monthly[11].Month != "2026-03" || stats.Monthly[11].Chapters != 11 || stats.Monthly[10].Chapters != 9 {
+		t.Errorf("unexpected monthly stats: %+v", stats.Monthly)
+	}
+	if stats.Records.BestDay != "2026-02-20" || stats.Records.BestDayChapters != 9 {
+		t.Errorf("unexpected records: %+v", stats.Records)
+	}
+}
+
+func TestCurrentStreakWithGrace(t *testing.T) {
+	today := time.Date(2026, 3, 10, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		name     string
+		days     []string
+		perMonth int
+		current  int
+		used     int
+		bridged  string
+	}{
+		{"read today, yesterday missed", []string{"2026-03-07", "2026-03-08", "2026-03-10"}, 1, 3, 1, "2026-03-09"},
+		{"not read today, yesterday missed", []string{"2026-03-07", "2026-03-08"}, 1, 2, 1, "2026-03-09"},
+		{"not read today, yesterday read", []string{"2026-03-08", "2026-03-09"}, 1, 2, 0, ""},
+		{"two missed days in a row", []string{"2026-03-06", "2026-03-07", "2026-03-10"}, 1, 1, 0, ""},
+		{"not read for two days", []string{"2026-03-06", "2026-03-07"}, 1, 0, 0, ""},
+		// 03-03 is bridged; 03-05 has no grace left, so the streak restarts on 03-06
+		{"second gap exceeds grace", []string{"2026-03-02", "2026-03-04", "2026-03-06", "2026-03-07", "2026-03-08", "2026-03-09"}, 1, 4, 1, ""},
+		// Grace spent on 02-28 is February's, so March still has one for 03-09
+		{"grace counted by month of the missed day", []string{"2026-02-27", "2026-03-01", "2026-03-08", "2026-03-10"}, 1, 2, 1, "2026-03-09"},
+		{"two grace days a month", []string{"2026-03-05", "2026-03-07", "2026-03-09"}, 2, 3, 2, "2026-03-06,2026-03-08"},
+		{"grace disabled", []string{"2026-03-07", "2026-03-08", "2026-03-10"}, 0, 1, 0, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var days []models.HeatmapDay
+			for _, d := range tt.days {
+				days = append(days, models.HeatmapDay{Date: d, Chapters: 1})
+			}
+			got := currentStreakWithGrace(days, today, tt.perMonth)
+			bridged := strings.Join(got.grace.Dates, ",")
+			if got.current != tt.current || got.grace.Used != tt.used || bridged != tt.bridged {
+				t.Errorf("got streak %d, used %d, bridged %q; want %d, %d, %q",
+					got.current, got.grace.Used, bridged, tt.current, tt.used, tt.bridged)
+			}
+			if got.grace.Remaining != max(0, tt.perMonth-tt.used) {
+				t.Errorf("remaining = %d, want %d", got.grace.Remaining, tt.perMonth-tt.used)
+			}
+		})
+	}
+}
+
+func TestStreakGraceLeavesLongestUnbroken(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`)
+	for _, date := range []string{"2026-03-01", "2026-03-02", "2026-03-03", "2026-03-05", "2026-03-06"} {
+		mustExec(t, db, `INSERT INTO daily_stats (user_id, stat_date, chapters_read, time_minutes) VALUES ('u1', ?, 1, 10)`, date)
+	}
+
+	svc := &service{repo: NewRepository(db), graceDays: 1, now: func() time.Time {
+		return time.Date(2026, 3, 7, 15, 0, 0, 0, time.UTC)
+	}}
+	overview, err := svc.GetStatsOverview(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetStatsOverview failed: %v", err)
+	}
+	if overview.CurrentStreak != 5 || overview.LongestStreak != 3 || overview.Grace.Remaining != 0 {
+		t.Errorf("unexpected overview: %+v, want current 5 (03-04 covered), longest 3, no grace left", overview)
+	}
+
+	var current, used int
+	var period, dates string
+	err = db.QueryRow(`SELECT current_streak, grace_period, grace_used, grace_dates FROM streak_state WHERE user_id = 'u1'`).
+		Scan(&current, &period, &used, &dates)
+	if err != nil || current != 5 || period != "2026-03" || used != 1 || dates != "2026-03-04" {
+		t.Errorf("streak_state = %d %s %d %q (%v), want 5 2026-03 1 \"2026-03-04\"", current, period, used, dates, err)
+	}
+}
+
+func TestDeleteChapterReadAcrossDayBoundary(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`)
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u2', 'other', 'o@example.com', 'x', 'Other')`)
+	mustExec(t, db, `INSERT INTO manga (id, title) VALUES ('m1', 'Berserk'), ('m2', 'Vagabond')`)
+
+	// 03-06 23:30 in UTC-5 is already 03-07 in UTC, the day daily_stats uses
+	est := time.FixedZone("EST", -5*3600)
+	reads := []struct {
+		id, manga string
+		at        time.Time
+	}{
+		{"h1", "m1", time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)},
+		{"h2", "m1", time.Date(2026, 3, 6, 23, 30, 0, 0, est)},
+		{"h3", "m1", time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC)},
+		{"h4", "m2", time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)},
+	}
+	for _, rd := range reads {
+		mustExec(t, db, `INSERT INTO chapter_history (id, user_id, manga_id, chapter_number, pages_read, time_minutes, read_at) VALUES (?, 'u1', ?, 1, 20, 10, ?)`,
+			rd.id, rd.manga, rd.at)
+	}
+	mustExec(t, db, `INSERT INTO daily_stats (user_id, stat_date, chapters_read, pages_read, time_minutes, manga_count) VALUES
+		('u1', '2026-03-06', 1, 20, 10, 1), ('u1', '2026-03-07', 3, 60, 30, 2)`)
+
+	repo := NewRepository(db)
+	svc := &service{repo: repo, now: func() time.Time { return time.Date(2026, 3, 7, 15, 0, 0, 0, time.UTC) }}
+
+	day := func(date string) (chapters, pages, minutes, manga int) {
+		t.Helper()
+		err := db.QueryRow(`SELECT chapters_read, pages_read, time_minutes, manga_count FROM daily_stats WHERE user_id = 'u1' AND stat_date = ?`, date).
+			Scan(&chapters, &pages, &minutes, &manga)
+		if err != nil {
+			t.Fatalf("read daily stats %s: %v", date, err)
+		}
+		return
+	}
+
+	// Another user's entry is not found and changes nothing
+	if _, err := svc.DeleteChapterRead(ctx, "u2", "h4"); err == nil {
+		t.Error("expected not found deleting another user's entry")
+	}
+
+	// m1 was read twice on 03-07, so removing one read keeps it in manga_count
+	if _, err := svc.DeleteChapterRead(ctx, "u1", "h2"); err != nil {
+		t.Fatalf("DeleteChapterRead failed: %v", err)
+	}
+	if c, p, m, n := day("2026-03-07"); c != 2 || p != 40 || m != 20 || n != 2 {
+		t.Errorf("03-07 after undo = %d/%d/%d/%d, want 2/40/20/2", c, p, m, n)
+	}
+	if _, err := svc.DeleteChapterRead(ctx, "u1", "h4"); err != nil {
+		t.Fatalf("DeleteChapterRead failed: %v", err)
+	}
+	if c, _, _, n := day("2026-03-07"); c != 1 || n != 1 {
+		t.Errorf("03-07 after second undo = %d chapters/%d manga, want 1/1", c, n)
+	}
+
+	// Undoing yesterday's only read clears that day, not today, and ends the run
+	if _, err := svc.DeleteChapterRead(ctx, "u1", "h1"); err != nil {
+		t.Fatalf("DeleteChapterRead failed: %v", err)
+	}
+	if c, p, m, n := day("2026-03-06"); c != 0 || p != 0 || m != 0 || n != 0 {
+		t.Errorf("03-06 after undo = %d/%d/%d/%d, want all zero", c, p, m, n)
+	}
+	overview, err := svc.GetStatsOverview(ctx, "u1")
+	if err != nil {
+		t.Fatalf("GetStatsOverview failed: %v", err)
+	}
+	if overview.CurrentStreak != 1 || overview.LongestStreak != 1 || overview.TotalChapters != 1 {
+		t.Errorf("unexpected overview after undo: %+v", overview)
+	}
+
+	// A row that is already zero stays at zero
+	mustExec(t, db, `INSERT INTO chapter_history (id, user_id, manga_id, chapter_number, read_at) VALUES ('h5', 'u1', 'm1', 2, ?)`,
+		time.Date(2026, 3, 6, 13, 0, 0, 0, time.UTC))
+	if _, err := svc.DeleteChapterRead(ctx, "u1", "h5"); err != nil {
+		t.Fatalf("DeleteChapterRead failed: %v", err)
+	}
+	if c, _, _, _ := day("2026-03-06"); c != 0 {
+		t.Errorf("03-06 went to %d chapters, want clamped at 0", c)
+	}
+}
+
+// countingRepo counts reading stats computations and can hold them until released
+type countingRepo struct {
+	Repository
+	calls   atomic.Int32
+	release chan struct{}
+}
+
+func (r *countingRepo) GetReadingStats(ctx context.Context, userID string) (*models.ReadingStats, error) {
+	r.calls.Add(1)
+	if r.release != nil {
+		<-r.release
+	}
+	return r.Repository.GetReadingStats(ctx, userID)
+}
+
+func TestReadingStatsCache(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'guts', 'g@example.com', 'x', 'Guts'), ('u2', 'casca', 'c@example.com', 'x', 'Casca')`)
+	mustExec(t, db, `INSERT INTO manga (id, title) VALUES ('m1', 'Berserk')`)
+	mustExec(t, db, `INSERT INTO chapter_history (id, user_id, manga_id, chapter_number) VALUES ('h1', 'u1', 'm1', 1)`)
+
+	repo := &countingRepo{Repository: NewRepository(db), release: make(chan struct{})}
+	svc := NewServiceWithCache(repo, cache.NewMemoryCache())
+
+	// Concurrent loads for one user share a single computation
+	var wg sync.WaitGroup
+	results := make([]*models.ReadingStats, 5)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			results[i], _ = svc.GetReadingStats(ctx, "u1")
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(repo.release)
+	wg.Wait()
+	if n := repo.calls.Load(); n != 1 {
+		t.Errorf("computed stats %d times for concurrent loads, want 1", n)
+	}
+	for _, r := range results {
+		if r == nil || r.TotalChapters != 1 || r.UpdatedAt.IsZero() {
+			t.Fatalf("unexpected stats: %+v", r)
+		}
+	}
+
+	// Cached per user: another user's stats are computed separately
+	if other, err := svc.GetReadingStats(ctx, "u2"); err != nil || other.TotalChapters != 0 {
+		t.Errorf("u2 stats = %+v (%v), want its own empty stats", other, err)
+	}
+	cached, _ := svc.GetReadingStats(ctx, "u1")
+	if n := repo.calls.Load(); n != 2 || !cached.UpdatedAt.Equal(results[0].UpdatedAt) {
+		t.Errorf("calls = %d, updated_at %v vs %v; want u1 served from cache", n, cached.UpdatedAt, results[0].UpdatedAt)
+	}
+
+	// Recording a read invalidates the user's stats
+	if _, err := svc.RecordChapterRead(ctx, "u1", models.RecordChapterRequest{MangaID: "m1", ChapterNumber: 2}); err != nil {
+		t.Fatalf("RecordChapterRead failed: %v", err)
+	}
+	fresh, err := svc.GetReadingStats(ctx, "u1")
+	if err != nil || fresh.TotalChapters != 2 {
+		t.Errorf("stats after a new read = %+v (%v), want 2 chapters", fresh, err)
+	}
+}
+
+func TestRecordChaptersReadBatch(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+	repo := NewRepository(db)
+
+	mustExec(t, db, `INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`)
+	mustExec(t, db, `INSERT INTO manga (id, title) VALUES ('m1', 'Berserk'), ('m2', 'Vagabond')`)
+
+	day1 := time.Date(2026, 3, 6, 12, 0, 0, 0, time.UTC)
+	day2 := time.Date(2026, 3, 7, 9, 0, 0, 0, time.UTC)
+	batch := []models.ChapterHistory{
+		{UserID: "u1", MangaID: "m1", ChapterNumber: 1, PagesRead: 20, TimeMinutes: 10, ReadAt: day1},
+		{UserID: "u1", MangaID: "m1", ChapterNumber: 2, PagesRead: 20, TimeMinutes: 10, ReadAt: day2},
+		{UserID: "u1", MangaID: "m1", ChapterNumber: 3, PagesRead: 20, TimeMinutes: 10, ReadAt: day2},
+		{UserID: "u1", MangaID: "m2", ChapterNumber: 1, PagesRead: 30, TimeMinutes: 15, ReadAt: day2},
+	}
+	if err := repo.RecordChaptersRead(ctx, batch); err != nil {
+		t.Fatalf("RecordChaptersRead: %v", err)
+	}
+	if batch[0].ID == "" {
+		t.Error("history IDs were not filled in")
+	}
+
+	day := func(date string) string {
+		t.Helper()
+		var c, p, m, n int
+		err := db.QueryRow(`SELECT chapters_read, pages_read, time_minutes, manga_count FROM daily_stats WHERE user_id = 'u1' AND stat_date = ?`, date).
+			Scan(&c, &p, &m, &n)
+		if err != nil {
+			t.Fatalf("read daily stats %s: %v", date, err)
+		}
+		return fmt.Sprintf("%d/%d/%d/%d", c, p, m, n)
+	}
+	if got := day("2026-03-06"); got != "1/20/10/1" {
+		t.Errorf("03-06 = %s, want 1/20/10/1", got)
+	}
+	if got := day("2026-03-07"); got != "3/70/35/2" {
+		t.Errorf("03-07 = %s, want 3/70/35/2", got)
+	}
+
+	// A batch with a failing entry (duplicate ID) records nothing
+	failing := []models.ChapterHistory{
+		{UserID: "u1", MangaID: "m2", ChapterNumber: 2, ReadAt: day2},
+		{ID: batch[0].ID, UserID: "u1", MangaID: "m2", ChapterNumber: 3, ReadAt: day2},
+	}
+	if err := repo.RecordChaptersRead(ctx, failing); err == nil {
+		t.Fatal("expected the batch with a duplicate ID to fail")
+	}
+	var n int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM chapter_history`).Scan(&n); err != nil {
+		t.Fatalf("count history: %v", err)
+	}
+	if n != 4 || day("2026-03-07") != "3/70/35/2" {
+		t.Errorf("failed batch left %d history rows and 03-07 = %s, want 4 and unchanged", n, day("2026-03-07"))
+	}
+}
@@ -0,0 +1,93 @@
+// Package statistics - Streak Grace Days
+// Grace day: một ngày không đọc không làm mất streak hiện tại
+// Chức năng:
+//   - Tối đa StreakGraceDays grace day mỗi tháng (UTC), tính theo tháng của ngày bị lỡ
+//   - Chỉ nối được khoảng trống đúng một ngày; lỡ 2 ngày liên tiếp là mất streak
+//   - Tính lại từ daily_stats mỗi lần (deterministic); streak_state lưu kết quả
+//   - Longest streak không dùng grace
+package statistics
+
+import (
+	"time"
+
+	"mangahub/pkg/models"
+)
+
+// StreakGraceDays is how many missed days per calendar month the current
+// streak survives, set from the stats config in cmd/api-server. 0 disables grace.
+var StreakGraceDays = 1
+
+// monthLayout is the YYYY-MM format grace allowances are counted by
+const monthLayout = "2006-01"
+
+// graceStreak is the current streak with grace days applied
+type graceStreak struct {
+	current int
+	grace   models.StreakGrace
+}
+
+// currentStreakWithGrace walks the reading days oldest first. A gap of exactly
+// one missed day is bridged when the missed day's month has grace left; grace
+// spent on a streak that later broke stays spent. Bridged days do not count
+// toward the streak length.
+//
+// Today is not a missed day until it is over: the streak is alive when the
+// last read was today or yesterday, or the day before yesterday with
+// yesterday covered by grace.
+func currentStreakWithGrace(days []models.HeatmapDay, today time.Time, perMonth int) graceStreak {
+	used := make(map[string]int)
+	var bridged []string
+
+	// bridge spends a grace day on missed, if its month has one left
+	bridge := func(missed time.Time) bool {
+		month := missed.Format(monthLayout)
+		if used[month] >= perMonth {
+			return false
+		}
+		used[month]++
+		bridged = append(bridged, missed.Format(dateLayout))
+		return true
+	}
+
+	var run int
+	var prev time.Time
+	for _, d := range days {
+		date, err := time.Parse(dateLayout, d.Date)
+		if err != nil {
+			continue
+		}
+		switch gap := date.Sub(prev); {
+		case run > 0 && gap == 24*time.Hour:
+			run++
+		case run > 0 && gap == 48*time.Hour && bridge(prev.AddDate(0, 0, 1)):
+			run++
+		default:
+			run = 1
+			bridged = nil
+		}
+		prev = date
+	}
+
+	var current int
+	switch gap := today.Sub(prev); {
+	case run == 0:
+	case gap <= 24*time.Hour:
+		current = run
+	case gap == 48*time.Hour && bridge(today.AddDate(0, 0, -1)):
+		current = run
+	}
+	if current == 0 {
+		bridged = nil
+	}
+
+	month := today.Format(monthLayout)
+	return graceStreak{
+		current: current,
+		grace: models.StreakGrace{
+			PerMonth:  perMonth,
+			Used:      used[month],
+			Remaining: max(0, perMonth-used[month]),
+			Dates:     bridged,
+		},
+	}
+}
@@ -7,10 +7,12 @@
 //   - Handle client disconnect gracefully
 //   - JSON message protocol
 //   - Concurrent goroutine cho mỗi client
+//   - Text commands (AUTH, SYNC_SINCE) cho delta sync, xem sync.go
 package tcp
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
 	"net"
@@ -22,9 +24,11 @@ import (
 type ClientID string
 
 type client struct {
-	id   ClientID
-	conn net.Conn
-	send chan []byte
+	id     ClientID
+	conn   net.Conn
+	send   chan []byte
+	quit   chan struct{} // closed when writeLoop exits
+	userID string        // set by AUTH; only touched by readLoop
 }
 
 type ProgressSyncServer struct {
@@ -36,6 +40,10 @@ type ProgressSyncServer struct {
 	register   chan *client
 	unregister chan *client
 	stop       chan struct{}
+
+	// Delta sync (optional, see EnableSync)
+	store  ProgressStore
+	tokens TokenParser
 }
 
 func NewProgressSyncServer(host string, port int) *ProgressSyncServer {
@@ -134,6 +142,7 @@ func (s *ProgressSyncServer) handleConnection(conn net.Conn) {
 		id:   id,
 		conn: conn,
 		send: make(chan []byte, 16),
+		quit: make(chan struct{}),
 	}
 
 	s.register <- c
@@ -159,7 +168,16 @@ func (s *ProgressSyncServer) handleConnection(conn net.Conn) {
 func (s *ProgressSyncServer) readLoop(c *client) {
 	reader := bufio.NewScanner(c.conn)
 	for reader.Scan() {
-		line := reader.Bytes()
+		line := bytes.TrimSpace(reader.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		// JSON lines are progress updates; anything else is a command
+		if line[0] != '{' {
+			s.handleCommand(c, string(line))
+			continue
+		}
+
 		var update ProgressUpdate
 		if err := json.Unmarshal(line, &update); err != nil {
 			logger.Warnf("invalid JSON from %s: %v", c.id, err)
@@ -175,6 +193,7 @@ func (s *ProgressSyncServer) readLoop(c *client) {
 }
 
 func (s *ProgressSyncServer) writeLoop(c *client) {
+	defer close(c.quit)
 	for msg := range c.send {
 		_, err := c.conn.Write(append(msg, '\n'))
 		if err != nil {
@@ -0,0 +1,215 @@
+// Package tcp - Delta Sync Commands
+// Cho phép client đồng bộ lại reading progress sau khi offline
+// Protocol (mỗi dòng một lệnh, response là newline-delimited JSON):
+//
+//	AUTH <jwt>                → {"type":"auth_ok","user_id":"..."}
+//	SYNC_SINCE <timestamp>    → {"type":"sync","progress":{...}} (mỗi row một dòng)
+//	                            {"type":"sync_end","count":N,"has_more":bool,"next_since":"..."}
+//
+// Timestamp nhận RFC 3339 hoặc unix seconds; timestamp ở tương lai bị clamp về now.
+// Khi has_more=true, gọi lại SYNC_SINCE với next_since để lấy batch tiếp theo.
+// Client offline nên gửi updated_at của row đã sync làm expected_updated_at khi
+// PUT /users/progress, để update cũ không ghi đè progress mới hơn (409 Conflict).
+package tcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"mangahub/pkg/logger"
+	"mangahub/pkg/models"
+)
+
+// Sync command names
+const (
+	CmdAuth      = "AUTH"
+	CmdSyncSince = "SYNC_SINCE"
+)
+
+// SyncBatchSize caps how many rows a single SYNC_SINCE returns. A batch only
+// grows past it when more rows than that share one updated_at.
+const SyncBatchSize = 200
+
+// syncTimeout bounds the database query behind one SYNC_SINCE
+const syncTimeout = 5 * time.Second
+
+// ProgressStore loads progress rows changed after a point in time.
+// Implemented by progress.Repository.
+type ProgressStore interface {
+	ListUpdatedSince(ctx context.Context, userID string, since time.Time, limit int) ([]models.ReadingProgress, error)
+}
+
+// TokenParser validates a JWT and returns its user.
+// Implemented by auth.Service.
+type TokenParser interface {
+	ParseToken(token string) (*models.UserProfile, error)
+}
+
+// SyncRecord wraps one progress row in a SYNC_SINCE response
+type SyncRecord struct {
+	Type     string                 `json:"type"` // "sync"
+	Progress models.ReadingProgress `json:"progress"`
+}
+
+// SyncEnd terminates a SYNC_SINCE response
+type SyncEnd struct {
+	Type      string    `json:"type"` // "sync_end"
+	Count     int       `json:"count"`
+	HasMore   bool      `json:"has_more"`
+	NextSince time.Time `json:"next_since"`
+}
+
+// commandResponse is used for auth acknowledgements and errors
+type commandResponse struct {
+	Type   string `json:"type"`
+	UserID string `json:"user_id,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// EnableSync turns on the AUTH and SYNC_SINCE commands
+func (s *ProgressSyncServer) EnableSync(store ProgressStore, tokens TokenParser) {
+	s.store = store
+	s.tokens = tokens
+}
+
+// handleCommand runs a text command line from a client
+func (s *ProgressSyncServer) handleCommand(c *client, line string) {
+	cmd, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	if s.store == nil || s.tokens == nil {
+		s.reply(c, commandResponse{Type: "error", Error: "sync is not enabled on this server"})
+		return
+	}
+
+	switch strings.ToUpper(cmd) {
+	case CmdAuth:
+		user, err := s.tokens.ParseToken(arg)
+		if err != nil || user == nil {
+			s.reply(c, commandResponse{Type: "error", Error: "invalid token"})
+			return
+		}
+		c.userID = user.ID
+		logger.TCP("AUTH", c.conn.RemoteAddr().String(), user.ID, "connection authenticated")
+		s.reply(c, commandResponse{Type: "auth_ok", UserID: user.ID})
+
+	case CmdSyncSince:
+		if c.userID == "" {
+			s.reply(c, commandResponse{Type: "error", Error: "AUTH required before SYNC_SINCE"})
+			return
+		}
+		since, err := parseSyncTimestamp(arg)
+		if err != nil {
+			s.reply(c, commandResponse{Type: "error", Error: err.Error()})
+			return
+		}
+		s.syncSince(c, since)
+
+	default:
+		s.reply(c, commandResponse{Type: "error", Error: "unknown command " + cmd})
+	}
+}
+
+// syncSince streams one batch of progress rows updated after since
+func (s *ProgressSyncServer) syncSince(c *client, since time.Time) {
+	// A client clock running ahead of ours must not hide future changes
+	if now := time.Now(); since.After(now) {
+		since = now
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), syncTimeout)
+	defer cancel()
+
+	rows, hasMore, err := s.loadSyncBatch(ctx, c.userID, since)
+	if err != nil {
+		logger.Errorf("sync query failed for %s: %v", c.userID, err)
+		s.reply(c, commandResponse{Type: "error", Error: "sync failed"})
+		return
+	}
+
+	nextSince := since
+	for _, p := range rows {
+		s.reply(c, SyncRecord{Type: "sync", Progress: p})
+		nextSince = p.UpdatedAt
+	}
+	s.reply(c, SyncEnd{Type: "sync_end", Count: len(rows), HasMore: hasMore, NextSince: nextSince})
+
+	logger.TCP("SYNC", c.conn.RemoteAddr().String(), c.userID,
+		"rows="+strconv.Itoa(len(rows))+" has_more="+strconv.FormatBool(hasMore))
+}
+
+// loadSyncBatch fetches the next batch after since. When the rows tied at
+// the batch boundary run past everything fetched, it fetches more until the
+// tied group ends, since the next batch resumes strictly after its timestamp.
+func (s *ProgressSyncServer) loadSyncBatch(ctx context.Context, userID string, since time.Time) ([]models.ReadingProgress, bool, error) {
+	// Fetch one extra row to learn whether another batch follows
+	for limit := SyncBatchSize + 1; ; limit *= 2 {
+		rows, err := s.store.ListUpdatedSince(ctx, userID, since, limit)
+		if err != nil {
+			return nil, false, err
+		}
+		batch, hasMore := trimSyncBatch(rows, SyncBatchSize)
+		if hasMore || len(rows) < limit {
+			return batch, hasMore, nil
+		}
+	}
+}
+
+// trimSyncBatch cuts rows to size without splitting rows that share the
+// boundary timestamp, since the next batch resumes strictly after it. When
+// the first row's timestamp is shared past size, the whole tied group is
+// returned instead; hasMore is false if that group ends the rows.
+func trimSyncBatch(rows []models.ReadingProgress, size int) ([]models.ReadingProgress, bool) {
+	if len(rows) <= size {
+		return rows, false
+	}
+
+	// julianday() compares at millisecond precision
+	boundary := rows[size].UpdatedAt.Truncate(time.Millisecond)
+	cut := size
+	for cut > 0 && rows[cut-1].UpdatedAt.Truncate(time.Millisecond).Equal(boundary) {
+		cut--
+	}
+	if cut == 0 {
+		// Whole batch shares one timestamp: send all of it rather than
+		// stall or skip the rest of the group
+		cut = size
+		for cut < len(rows) && rows[cut].UpdatedAt.Truncate(time.Millisecond).Equal(boundary) {
+			cut++
+		}
+	}
+	return rows[:cut], cut < len(rows)
+}
+
+// parseSyncTimestamp accepts RFC 3339 or unix seconds
+func parseSyncTimestamp(arg string) (time.Time, error) {
+	if arg == "" {
+		return time.Time{}, errors.New("SYNC_SINCE requires a timestamp")
+	}
+	if unix, err := strconv.ParseInt(arg, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+	t, err := time.Parse(time.RFC3339Nano, arg)
+	if err != nil {
+		return time.Time{}, errors.New("timestamp must be RFC 3339 or unix seconds")
+	}
+	return t, nil
+}
+
+// reply queues a JSON line for one client
+func (s *ProgressSyncServer) reply(c *client, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		logger.Errorf("failed to marshal reply: %v", err)
+		return
+	}
+	select {
+	case c.send <- data:
+	case <-c.quit:
+		// Writer is gone; drop the reply
+	}
+}
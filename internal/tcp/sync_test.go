@@ -0,0 +1,214 @@
+// Package tcp - Delta Sync Tests
+// Unit tests cho AUTH / SYNC_SINCE (batching, has_more, clamp timestamp tương lai)
+package tcp
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"mangahub/pkg/logger"
+	"mangahub/pkg/models"
+)
+
+// fakeStore serves rows ordered by UpdatedAt and records the last query
+type fakeStore struct {
+	rows      []models.ReadingProgress
+	lastSince time.Time
+}
+
+func (f *fakeStore) ListUpdatedSince(ctx context.Context, userID string, since time.Time, limit int) ([]models.ReadingProgress, error) {
+	f.lastSince = since
+	var out []models.ReadingProgress
+	for _, p := range f.rows {
+		if p.UserID == userID && p.UpdatedAt.After(since) && len(out) < limit {
+			out = append(out, p)
+		}
+	}
+	return out, nil
+}
+
+// fakeTokens accepts a single token
+type fakeTokens struct{}
+
+func (fakeTokens) ParseToken(token string) (*models.UserProfile, error) {
+	if token != "good-token" {
+		return nil, errors.New("bad token")
+	}
+	return &models.UserProfile{ID: "user-1"}, nil
+}
+
+// dialSync starts a hub and one connection served through net.Pipe
+func dialSync(t *testing.T, store ProgressStore) (net.Conn, *bufio.Scanner) {
+	t.Helper()
+	logger.Get() // initialise the default logger before goroutines race on it
+	s := NewProgressSyncServer("127.0.0.1", 0)
+	s.EnableSync(store, fakeTokens{})
+	go s.runHub()
+	t.Cleanup(func() { close(s.stop) })
+
+	serverConn, clientConn := net.Pipe()
+	go s.handleConnection(serverConn)
+	t.Cleanup(func() { clientConn.Close() })
+
+	clientConn.SetDeadline(time.Now().Add(5 * time.Second))
+	return clientConn, bufio.NewScanner(clientConn)
+}
+
+func send(t *testing.T, conn net.Conn, line string) {
+	t.Helper()
+	if _, err := conn.Write([]byte(line + "\n")); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+}
+
+func readLine(t *testing.T, sc *bufio.Scanner) map[string]interface{} {
+	t.Helper()
+	if !sc.Scan() {
+		t.Fatalf("connection closed: %v", sc.Err())
+	}
+	var msg map[string]interface{}
+	if err := json.Unmarshal(sc.Bytes(), &msg); err != nil {
+		t.Fatalf("invalid JSON line %q: %v", sc.Text(), err)
+	}
+	return msg
+}
+
+// readBatch collects sync records until sync_end
+func readBatch(t *testing.T, sc *bufio.Scanner) (int, SyncEnd) {
+	t.Helper()
+	records := 0
+	for {
+		if !sc.Scan() {
+			t.Fatalf("connection closed: %v", sc.Err())
+		}
+		var head struct {
+			Type string `json:"type"`
+		}
+		json.Unmarshal(sc.Bytes(), &head)
+		switch head.Type {
+		case "sync":
+			records++
+		case "sync_end":
+			var end SyncEnd
+			if err := json.Unmarshal(sc.Bytes(), &end); err != nil {
+				t.Fatalf("invalid sync_end: %v", err)
+			}
+			return records, end
+		default:
+			t.Fatalf("unexpected line %q", sc.Text())
+		}
+	}
+}
+
+func TestSyncSinceRequiresAuth(t *testing.T) {
+	conn, sc := dialSync(t, &fakeStore{})
+
+	send(t, conn, "SYNC_SINCE 0")
+	if msg := readLine(t, sc); msg["type"] != "error" {
+		t.Fatalf("expected error before AUTH, got %v", msg)
+	}
+
+	send(t, conn, "AUTH wrong-token")
+	if msg := readLine(t, sc); msg["type"] != "error" {
+		t.Fatalf("expected error for bad token, got %v", msg)
+	}
+
+	send(t, conn, "AUTH good-token")
+	if msg := readLine(t, sc); msg["type"] != "auth_ok" || msg["user_id"] != "user-1" {
+		t.Fatalf("expected auth_ok, got %v", msg)
+	}
+}
+
+func TestSyncSinceBatchesWithHasMore(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &fakeStore{}
+	for i := 0; i < SyncBatchSize+50; i++ {
+		store.rows = append(store.rows, models.ReadingProgress{
+			ID:        "p",
+			UserID:    "user-1",
+			MangaID:   "m",
+			UpdatedAt: base.Add(time.Duration(i+1) * time.Second),
+		})
+	}
+	// Another user's row must never leak
+	store.rows = append(store.rows, models.ReadingProgress{UserID: "user-2", UpdatedAt: base.Add(time.Hour)})
+
+	conn, sc := dialSync(t, store)
+	send(t, conn, "AUTH good-token")
+	readLine(t, sc)
+
+	send(t, conn, "SYNC_SINCE "+base.Format(time.RFC3339))
+	count, end := readBatch(t, sc)
+	if count != SyncBatchSize || end.Count != SyncBatchSize || !end.HasMore {
+		t.Fatalf("first batch: got %d rows, end=%+v", count, end)
+	}
+
+	send(t, conn, "SYNC_SINCE "+end.NextSince.Format(time.RFC3339Nano))
+	count, end = readBatch(t, sc)
+	if count != 50 || end.HasMore {
+		t.Fatalf("second batch: got %d rows, end=%+v", count, end)
+	}
+}
+
+func TestSyncSinceClampsFutureTimestamp(t *testing.T) {
+	store := &fakeStore{}
+	conn, sc := dialSync(t, store)
+	send(t, conn, "AUTH good-token")
+	readLine(t, sc)
+
+	future := time.Now().Add(24 * time.Hour)
+	send(t, conn, "SYNC_SINCE "+future.Format(time.RFC3339))
+	readBatch(t, sc)
+
+	if store.lastSince.After(time.Now()) {
+		t.Errorf("future timestamp was not clamped: %v", store.lastSince)
+	}
+}
+
+func TestTrimSyncBatchKeepsTiesTogether(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	rows := []models.ReadingProgress{
+		{UpdatedAt: base.Add(1 * time.Second)},
+		{UpdatedAt: base.Add(2 * time.Second)},
+		{UpdatedAt: base.Add(2 * time.Second)},
+		{UpdatedAt: base.Add(2 * time.Second)},
+	}
+
+	got, hasMore := trimSyncBatch(rows, 3)
+	if len(got) != 1 || !hasMore {
+		t.Errorf("expected the tied rows to move to the next batch, got %d rows (has_more=%v)", len(got), hasMore)
+	}
+}
+
+func TestSyncSinceSendsWholeTimestampGroup(t *testing.T) {
+	base := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := &fakeStore{}
+	// More rows than a batch share one timestamp (e.g. a bulk import)
+	for i := 0; i < 2*SyncBatchSize+10; i++ {
+		store.rows = append(store.rows, models.ReadingProgress{UserID: "user-1", UpdatedAt: base.Add(time.Second)})
+	}
+	for i := 0; i < 5; i++ {
+		store.rows = append(store.rows, models.ReadingProgress{UserID: "user-1", UpdatedAt: base.Add(time.Minute)})
+	}
+
+	conn, sc := dialSync(t, store)
+	send(t, conn, "AUTH good-token")
+	readLine(t, sc)
+
+	send(t, conn, "SYNC_SINCE "+base.Format(time.RFC3339))
+	count, end := readBatch(t, sc)
+	if count != 2*SyncBatchSize+10 || !end.HasMore || !end.NextSince.Equal(base.Add(time.Second)) {
+		t.Fatalf("first batch: got %d rows, end=%+v; want the whole tied group", count, end)
+	}
+
+	send(t, conn, "SYNC_SINCE "+end.NextSince.Format(time.RFC3339Nano))
+	count, end = readBatch(t, sc)
+	if count != 5 || end.HasMore {
+		t.Fatalf("second batch: got %d rows, end=%+v", count, end)
+	}
+}
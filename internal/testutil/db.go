@@ -0,0 +1,33 @@
+// Package testutil - Shared Test Helpers
+// Helper dùng chung cho test của các package
+// Chức năng:
+//   - OpenDB: SQLite in-memory với schema thật (đã chạy migrations)
+package testutil
+
+import (
+	"database/sql"
+	"testing"
+
+	_ "github.com/glebarez/go-sqlite"
+
+	"mangahub/pkg/database"
+)
+
+// OpenDB opens an in-memory SQLite database with every migration applied.
+// It uses a single connection so every query sees the same in-memory
+// database, and closes it when the test ends. The pure-Go driver ships with
+// FTS5 enabled, which the manga search migrations need.
+func OpenDB(t testing.TB) *sql.DB {
+	t.Helper()
+	sqlDB, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("failed to open test db: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	if err := (&database.DB{DB: sqlDB}).Migrate(); err != nil {
+		t.Fatalf("failed to migrate test db: %v", err)
+	}
+	return sqlDB
+}
@@ -1,6 +1,7 @@
 // Package api - In-Memory Cache
 // Simple TTL cache cho TUI API responses
 // Tránh request lặp lại trong session
+// Entry có thể gắn tag (vd "manga:<id>"); DeleteByTag xoá mọi key liên quan
 package api
 
 import (
@@ -12,11 +13,15 @@ import (
 type CacheItem struct {
 	Value      interface{}
 	Expiration time.Time
+	Tags       []string
 }
 
-// Cache is a simple in-memory cache with TTL
+// Cache is a simple in-memory cache with TTL.
+// Tags index keys so related entries can be evicted together; the index is
+// guarded by the same mutex as items and never points at a missing key.
 type Cache struct {
 	items map[string]*CacheItem
+	tags  map[string]map[string]struct{} // tag -> keys carrying it
 	mu    sync.RWMutex
 }
 
@@ -24,6 +29,7 @@ type Cache struct {
 func NewCache() *Cache {
 	c := &Cache{
 		items: make(map[string]*CacheItem),
+		tags:  make(map[string]map[string]struct{}),
 	}
 	// Start cleanup goroutine
 	go c.cleanup()
@@ -32,12 +38,27 @@ func NewCache() *Cache {
 
 // Set stores a value in the cache with TTL
 func (c *Cache) Set(key string, value interface{}, ttl time.Duration) {
+	c.SetTagged(key, value, ttl)
+}
+
+// SetTagged stores a value with TTL and tags; replacing a key drops its old tags
+func (c *Cache) SetTagged(key string, value interface{}, ttl time.Duration, tags ...string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.removeLocked(key)
 	c.items[key] = &CacheItem{
 		Value:      value,
 		Expiration: time.Now().Add(ttl),
+		Tags:       tags,
+	}
+	for _, tag := range tags {
+		keys, ok := c.tags[tag]
+		if !ok {
+			keys = make(map[string]struct{})
+			c.tags[tag] = keys
+		}
+		keys[key] = struct{}{}
 	}
 }
 
@@ -63,7 +84,34 @@ func (c *Cache) Delete(key string) {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	c.removeLocked(key)
+}
+
+// DeleteByTag removes every item carrying any of the tags
+func (c *Cache) DeleteByTag(tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, tag := range tags {
+		for key := range c.tags[tag] {
+			c.removeLocked(key)
+		}
+	}
+}
+
+// removeLocked deletes key and its tag links; c.mu must be held
+func (c *Cache) removeLocked(key string) {
+	item, ok := c.items[key]
+	if !ok {
+		return
+	}
 	delete(c.items, key)
+	for _, tag := range item.Tags {
+		delete(c.tags[tag], key)
+		if len(c.tags[tag]) == 0 {
+			delete(c.tags, tag)
+		}
+	}
 }
 
 // Clear removes all items from cache
@@ -72,6 +120,7 @@ func (c *Cache) Clear() {
 	defer c.mu.Unlock()
 
 	c.items = make(map[string]*CacheItem)
+	c.tags = make(map[string]map[string]struct{})
 }
 
 // cleanup periodically removes expired items
@@ -82,7 +131,7 @@ func (c *Cache) cleanup() {
 		now := time.Now()
 		for key, item := range c.items {
 			if now.After(item.Expiration) {
-				delete(c.items, key)
+				c.removeLocked(key)
 			}
 		}
 		c.mu.Unlock()
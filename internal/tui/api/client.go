@@ -3,19 +3,25 @@
 // Chức năng:
 //   - Singleton HTTP client với timeout
 //   - Automatic JWT token injection
+//   - Transparent token refresh on 401
 //   - Typed responses using pkg/models
 //   - Retry logic for transient failures
-//   - In-memory cache layer
+//   - In-memory cache layer (TTL cấu hình qua viper tui.cache.*, cache cả 404)
 package api
 
 import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
 	"net/url"
+	"path/filepath"
+	"strconv"
+	"strings"
 	"sync"
 	"time"
 
@@ -29,26 +35,180 @@ import (
 // =====================================
 
 const (
-	DefaultTimeout    = 30 * time.Second
-	DefaultRetries    = 3
-	RetryDelay        = 500 * time.Millisecond
-	CacheDuration     = 5 * time.Minute
-	DashboardCacheTTL = 30 * time.Second
-	TrendingCacheTTL  = 10 * time.Minute
-	LibraryCacheTTL   = 1 * time.Minute
+	DefaultTimeout = 30 * time.Second
+	DefaultRetries = 3
+	RetryDelay     = 500 * time.Millisecond
 )
 
+// CacheTTLs are how long each kind of response stays cached. Every entry
+// stores its own expiry, so changing a TTL only affects entries cached later.
+type CacheTTLs struct {
+	Default   time.Duration // manga details, chapters, ratings, search pages
+	Dashboard time.Duration // activity feed
+	Trending  time.Duration // leaderboards
+	Library   time.Duration
+	NotFound  time.Duration // 404 responses
+}
+
+// DefaultCacheTTLs are used for any TTL the config leaves unset
+func DefaultCacheTTLs() CacheTTLs {
+	return CacheTTLs{
+		Default:   5 * time.Minute,
+		Dashboard: 30 * time.Second,
+		Trending:  10 * time.Minute,
+		Library:   1 * time.Minute,
+		NotFound:  5 * time.Second,
+	}
+}
+
+// loadCacheTTLs reads the tui.cache.* durations from viper
+func loadCacheTTLs() CacheTTLs {
+	ttls := DefaultCacheTTLs()
+	for key, ttl := range map[string]*time.Duration{
+		"tui.cache.default_ttl":   &ttls.Default,
+		"tui.cache.dashboard_ttl": &ttls.Dashboard,
+		"tui.cache.trending_ttl":  &ttls.Trending,
+		"tui.cache.library_ttl":   &ttls.Library,
+		"tui.cache.not_found_ttl": &ttls.NotFound,
+	} {
+		if d := viper.GetDuration(key); d > 0 {
+			*ttl = d
+		}
+	}
+	return ttls
+}
+
+// HTTPError is an error response from the API. Code is the API error code
+// (models.ErrCodeValidation, models.ErrCodeNotFound, ...), empty when the
+// body was not a models.APIResponse; Field names the request field that
+// failed validation, if any.
+type HTTPError struct {
+	StatusCode int
+	Code       string
+	Message    string
+	Field      string
+	Details    map[string]interface{}
+}
+
+func (e *HTTPError) Error() string {
+	if e.Code == "" {
+		return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
+	}
+	return e.Code + ": " + e.Message
+}
+
+// decodeError builds the HTTPError for a failed response body
+func decodeError(statusCode int, body []byte) *HTTPError {
+	var errResp models.APIResponse
+	if json.Unmarshal(body, &errResp) != nil || errResp.Error == nil {
+		return &HTTPError{StatusCode: statusCode, Message: string(body)}
+	}
+	httpErr := &HTTPError{
+		StatusCode: statusCode,
+		Code:       errResp.Error.Code,
+		Message:    errResp.Error.Message,
+		Details:    errResp.Error.Details,
+	}
+	httpErr.Field, _ = errResp.Error.Details["field"].(string)
+	return httpErr
+}
+
+// ErrorCode returns the API error code of err, or "" if it is not an API error
+func ErrorCode(err error) string {
+	var httpErr *HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+	return ""
+}
+
+// IsNotFound reports whether err is a 404 from the API
+func IsNotFound(err error) bool {
+	var httpErr *HTTPError
+	return errors.As(err, &httpErr) && httpErr.StatusCode == http.StatusNotFound
+}
+
+// cachedError is a negative cache entry: the request behind its key failed
+type cachedError struct {
+	err error
+}
+
+// cacheNotFound remembers a 404 for key for the short NotFound TTL, so
+// repeatedly opening a deleted manga does not hit the server each time
+func (c *Client) cacheNotFound(key string, err error, tags ...string) {
+	if IsNotFound(err) && c.ttl.NotFound > 0 {
+		c.cache.SetTagged(key, cachedError{err: err}, c.ttl.NotFound, tags...)
+	}
+}
+
+// tagTopRated marks every top-rated and hidden-gems page: a new rating can move any manga into them
+const tagTopRated = "toprated"
+
+// tagLibrary marks views of the user's own progress (library, continue reading);
+// every progress mutation evicts them all
+const tagLibrary = "library"
+
+// mangaTag marks cache entries that show data about one manga
+func mangaTag(mangaID string) string {
+	return "manga:" + mangaID
+}
+
+// mangaTags tags a page of results with each manga on it, so a change to one
+// manga evicts only the pages that mention it
+func mangaTags(ids []string, extra ...string) []string {
+	tags := make([]string, 0, len(ids)+len(extra))
+	for _, id := range ids {
+		tags = append(tags, mangaTag(id))
+	}
+	return append(tags, extra...)
+}
+
+// mangaIDs lists the ids of a page of manga
+func mangaIDs(list []models.Manga) []string {
+	ids := make([]string, len(list))
+	for i := range list {
+		ids[i] = list[i].ID
+	}
+	return ids
+}
+
+// entryIDs lists the manga ids of a leaderboard page
+func entryIDs(entries []TrendingEntry) []string {
+	ids := make([]string, len(entries))
+	for i := range entries {
+		ids[i] = entries[i].MangaID
+	}
+	return ids
+}
+
+// invalidateManga evicts everything cached about a manga after a mutation:
+// its detail, rating summary and every search or leaderboard page showing it
+func (c *Client) invalidateManga(mangaID string, extraTags ...string) {
+	c.cache.DeleteByTag(append([]string{mangaTag(mangaID)}, extraTags...)...)
+}
+
+// InvalidateAll drops every cached response, e.g. for a manual refresh
+func (c *Client) InvalidateAll() {
+	c.cache.Clear()
+}
+
 // =====================================
 // CLIENT STRUCT
 // =====================================
 
 // Client is the shared HTTP client for TUI
 type Client struct {
-	httpClient *http.Client
-	baseURL    string
-	token      string
-	cache      *Cache
-	mu         sync.RWMutex
+	httpClient   *http.Client
+	baseURL      string
+	token        string
+	refreshToken string
+	cache        *Cache
+	ttl          CacheTTLs
+	mu           sync.RWMutex
+	refreshMu    sync.Mutex
+
+	// onTokenCleared runs after ClearToken (logout or expired session)
+	onTokenCleared func()
 }
 
 // singleton instance
@@ -74,9 +234,11 @@ func InitClient(baseURL string) {
 			httpClient: &http.Client{
 				Timeout: DefaultTimeout,
 			},
-			baseURL: baseURL,
-			token:   viper.GetString("user.token"),
-			cache:   NewCache(),
+			baseURL:      baseURL,
+			token:        viper.GetString("user.token"),
+			refreshToken: viper.GetString("user.refresh_token"),
+			cache:        NewCache(),
+			ttl:          loadCacheTTLs(),
 		}
 	})
 }
@@ -96,9 +258,11 @@ func NewClient() *Client {
 		httpClient: &http.Client{
 			Timeout: DefaultTimeout,
 		},
-		baseURL: fmt.Sprintf("http://%s:%d", host, port),
-		token:   viper.GetString("user.token"),
-		cache:   NewCache(),
+		baseURL:      fmt.Sprintf("http://%s:%d", host, port),
+		token:        viper.GetString("user.token"),
+		refreshToken: viper.GetString("user.refresh_token"),
+		cache:        NewCache(),
+		ttl:          loadCacheTTLs(),
 	}
 }
 
@@ -110,6 +274,23 @@ func (c *Client) SetToken(token string) {
 	viper.Set("user.token", token)
 }
 
+// SetTokens updates both the access and refresh tokens
+func (c *Client) SetTokens(token, refreshToken string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.token = token
+	c.refreshToken = refreshToken
+	viper.Set("user.token", token)
+	viper.Set("user.refresh_token", refreshToken)
+}
+
+// GetRefreshToken returns the current refresh token
+func (c *Client) GetRefreshToken() string {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return c.refreshToken
+}
+
 // GetToken returns the current authentication token
 func (c *Client) GetToken() string {
 	c.mu.RLock()
@@ -129,51 +310,101 @@ func (c *Client) IsAuthenticated() bool {
 	return c.GetToken() != ""
 }
 
-// ClearToken removes the authentication token (logout)
+// ClearToken removes the authentication tokens (logout)
 func (c *Client) ClearToken() {
-	c.SetToken("")
+	c.SetTokens("", "")
+
+	c.mu.RLock()
+	fn := c.onTokenCleared
+	c.mu.RUnlock()
+	if fn != nil {
+		fn()
+	}
+}
+
+// OnTokenCleared registers a callback run whenever the tokens are cleared,
+// so connections opened with the old token can be torn down
+func (c *Client) OnTokenCleared(fn func()) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.onTokenCleared = fn
 }
 
 // =====================================
 // HTTP REQUEST METHODS
 // =====================================
 
-// doRequest performs an HTTP request with retry logic
+// doRequest performs an HTTP request with retry logic.
+// On 401 it refreshes the access token once and replays the request.
 func (c *Client) doRequest(ctx context.Context, method, endpoint string, body interface{}) (*http.Response, error) {
-	var reqBody io.Reader
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		var err error
+		jsonData, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
-		reqBody = bytes.NewBuffer(jsonData)
 	}
 
-	fullURL := c.baseURL + endpoint
-	req, err := http.NewRequestWithContext(ctx, method, fullURL, reqBody)
+	resp, err := c.send(ctx, method, endpoint, jsonData)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	// Set headers
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("Accept", "application/json")
-
-	// Add auth token if available
-	token := c.GetToken()
-	if token != "" {
-		req.Header.Set("Authorization", "Bearer "+token)
+	if resp.StatusCode == http.StatusUnauthorized && endpoint != "/auth/refresh" && c.GetRefreshToken() != "" {
+		staleToken := c.GetToken()
+		resp.Body.Close()
+		if err := c.refreshAccessToken(ctx, staleToken); err != nil {
+			return nil, err
+		}
+		return c.send(ctx, method, endpoint, jsonData)
 	}
 
-	// Retry logic
+	return resp, nil
+}
+
+// send builds and executes a request, retrying transient failures
+func (c *Client) send(ctx context.Context, method, endpoint string, jsonData []byte) (*http.Response, error) {
+	fullURL := c.baseURL + endpoint
+
 	var resp *http.Response
 	var lastErr error
 	for i := 0; i < DefaultRetries; i++ {
+		var reqBody io.Reader
+		if jsonData != nil {
+			reqBody = bytes.NewReader(jsonData)
+		}
+
+		req, err := http.NewRequestWithContext(ctx, method, fullURL, reqBody)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create request: %w", err)
+		}
+
+		// Set headers
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Accept", "application/json")
+
+		// Add auth token if available
+		token := c.GetToken()
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+
 		resp, lastErr = c.httpClient.Do(req)
 		if lastErr == nil && resp.StatusCode < 500 {
 			return resp, nil
 		}
+		// A cancelled caller (e.g. a superseded search) is not worth retrying
+		if ctx.Err() != nil {
+			if lastErr == nil {
+				resp.Body.Close()
+			}
+			return nil, ctx.Err()
+		}
 		if i < DefaultRetries-1 {
+			if lastErr == nil {
+				resp.Body.Close()
+			}
 			time.Sleep(RetryDelay * time.Duration(i+1))
 		}
 	}
@@ -184,6 +415,41 @@ func (c *Client) doRequest(ctx context.Context, method, endpoint string, body in
 	return resp, nil
 }
 
+// refreshAccessToken rotates the refresh token and stores the new pair.
+// Concurrent callers that hit 401 with the same stale token share one refresh.
+func (c *Client) refreshAccessToken(ctx context.Context, staleToken string) error {
+	c.refreshMu.Lock()
+	defer c.refreshMu.Unlock()
+
+	// Another request already refreshed while we waited
+	if c.GetToken() != staleToken {
+		return nil
+	}
+
+	resp, err := c.send(ctx, "POST", "/auth/refresh", mustJSON(map[string]string{
+		"refresh_token": c.GetRefreshToken(),
+	}))
+	if err != nil {
+		return err
+	}
+
+	result, err := parseResponse[RefreshResponse](resp)
+	if err != nil {
+		// Refresh token expired or revoked: force a fresh login
+		c.ClearToken()
+		return fmt.Errorf("session expired, please login again: %w", err)
+	}
+
+	c.SetTokens(result.Data.Token, result.Data.RefreshToken)
+	return nil
+}
+
+// mustJSON marshals a value that is known to be encodable
+func mustJSON(v interface{}) []byte {
+	data, _ := json.Marshal(v)
+	return data
+}
+
 // parseResponse parses JSON response into target struct
 func parseResponse[T any](resp *http.Response) (*T, error) {
 	defer resp.Body.Close()
@@ -195,11 +461,7 @@ func parseResponse[T any](resp *http.Response) (*T, error) {
 
 	// Check for API error response
 	if resp.StatusCode >= 400 {
-		var errResp models.APIResponse
-		if json.Unmarshal(body, &errResp) == nil && errResp.Error != nil {
-			return nil, fmt.Errorf("%s: %s", errResp.Error.Code, errResp.Error.Message)
-		}
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(body))
+		return nil, decodeError(resp.StatusCode, body)
 	}
 
 	var result T
@@ -225,8 +487,18 @@ type LoginResponse struct {
 	Success bool   `json:"success"`
 	Message string `json:"message"`
 	Data    struct {
-		Token string       `json:"token"`
-		User  *models.User `json:"user"`
+		Token        string       `json:"token"`
+		RefreshToken string       `json:"refresh_token"`
+		User         *models.User `json:"user"`
+	} `json:"data"`
+}
+
+// RefreshResponse from token refresh API
+type RefreshResponse struct {
+	Success bool `json:"success"`
+	Data    struct {
+		Token        string `json:"token"`
+		RefreshToken string `json:"refresh_token"`
 	} `json:"data"`
 }
 
@@ -249,7 +521,7 @@ func (c *Client) Login(ctx context.Context, username, password string) (*models.
 		return nil, fmt.Errorf("login failed: %s", result.Message)
 	}
 
-	c.SetToken(result.Data.Token)
+	c.SetTokens(result.Data.Token, result.Data.RefreshToken)
 	return result.Data.User, nil
 }
 
@@ -304,13 +576,57 @@ func (c *Client) GetCurrentUser(ctx context.Context) (*models.User, error) {
 	return result.Data, nil
 }
 
-// Logout clears the auth token
+// Logout revokes the refresh token server-side and clears local tokens
 func (c *Client) Logout(ctx context.Context) error {
-	_, err := c.doRequest(ctx, "POST", "/auth/logout", nil)
+	_, err := c.doRequest(ctx, "POST", "/auth/logout", map[string]string{
+		"refresh_token": c.GetRefreshToken(),
+	})
 	c.ClearToken()
 	return err
 }
 
+// ChangePassword replaces the account password. The server revokes every
+// session, so the new token pair it returns is stored to stay logged in.
+func (c *Client) ChangePassword(ctx context.Context, currentPassword, newPassword string) error {
+	resp, err := c.doRequest(ctx, "PUT", "/auth/password", map[string]string{
+		"current_password": currentPassword,
+		"new_password":     newPassword,
+	})
+	if err != nil {
+		return err
+	}
+
+	result, err := parseResponse[LoginResponse](resp)
+	if err != nil {
+		return err
+	}
+
+	if !result.Success {
+		return fmt.Errorf("password change failed: %s", result.Message)
+	}
+
+	c.SetTokens(result.Data.Token, result.Data.RefreshToken)
+	return nil
+}
+
+// DeleteAccount permanently deletes the account and all of its data. The
+// server drops every session with it, so local tokens and cache are cleared.
+func (c *Client) DeleteAccount(ctx context.Context, password string) error {
+	resp, err := c.doRequest(ctx, "DELETE", "/auth/me", map[string]string{
+		"password": password,
+	})
+	if err != nil {
+		return err
+	}
+	if _, err := parseResponse[struct{}](resp); err != nil {
+		return err
+	}
+
+	c.cache.Clear()
+	c.ClearToken()
+	return nil
+}
+
 // =====================================
 // MANGA API
 // =====================================
@@ -327,19 +643,29 @@ type MangaListResponse struct {
 	} `json:"data"`
 }
 
-// SearchManga searches for manga by query
-func (c *Client) SearchManga(ctx context.Context, query string, page, pageSize int) ([]models.Manga, int, error) {
-	// Check cache first
+// CachedSearch returns a search result already in the cache without a request,
+// so views can show it instantly (e.g. when backspacing to an earlier query)
+func (c *Client) CachedSearch(query string, page, pageSize int) ([]models.Manga, int, bool) {
 	cacheKey := fmt.Sprintf("search:%s:%d:%d", query, page, pageSize)
 	if cached, found := c.cache.Get(cacheKey); found {
 		if result, ok := cached.(*MangaListResponse); ok {
-			return result.Data.Data, result.Data.Total, nil
+			return result.Data.Data, result.Data.Total, true
 		}
 	}
+	return nil, 0, false
+}
+
+// SearchManga searches for manga by query
+func (c *Client) SearchManga(ctx context.Context, query string, page, pageSize int) ([]models.Manga, int, error) {
+	// Check cache first
+	if results, total, ok := c.CachedSearch(query, page, pageSize); ok {
+		return results, total, nil
+	}
 
 	params := url.Values{}
 	if query != "" {
 		params.Set("q", query)
+		params.Set("mode", "fts") // BM25-ranked full-text search
 	}
 	params.Set("page", fmt.Sprintf("%d", page))
 	params.Set("page_size", fmt.Sprintf("%d", pageSize))
@@ -355,18 +681,64 @@ func (c *Client) SearchManga(ctx context.Context, query string, page, pageSize i
 		return nil, 0, err
 	}
 
-	// Cache the result
-	c.cache.Set(cacheKey, result, CacheDuration)
+	// Cache the result, tagged per manga so a rating change evicts only this page
+	c.cache.SetTagged(fmt.Sprintf("search:%s:%d:%d", query, page, pageSize), result, c.ttl.Default,
+		mangaTags(mangaIDs(result.Data.Data))...)
 
 	return result.Data.Data, result.Data.Total, nil
 }
 
+// MangaSuggestResponse from GET /manga/suggest
+type MangaSuggestResponse struct {
+	Success bool                     `json:"success"`
+	Data    []models.MangaSuggestion `json:"data"`
+}
+
+// SuggestManga retrieves titles matching a typed prefix (at least 2 characters)
+func (c *Client) SuggestManga(ctx context.Context, prefix string, limit int) ([]models.MangaSuggestion, error) {
+	params := url.Values{}
+	params.Set("q", prefix)
+	params.Set("limit", fmt.Sprintf("%d", limit))
+
+	resp, err := c.doRequest(ctx, "GET", "/manga/suggest?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[MangaSuggestResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// SimilarMangaResponse from GET /manga/:id/similar
+type SimilarMangaResponse struct {
+	Success bool                  `json:"success"`
+	Data    []models.SimilarManga `json:"data"`
+}
+
+// GetSimilarManga retrieves recommendations for a manga (excludes the user's library when logged in)
+func (c *Client) GetSimilarManga(ctx context.Context, mangaID string, limit int) ([]models.SimilarManga, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/manga/%s/similar?limit=%d", mangaID, limit), nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[SimilarMangaResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
 // GetManga retrieves a single manga by ID
 func (c *Client) GetManga(ctx context.Context, mangaID string) (*models.Manga, error) {
 	cacheKey := "manga:" + mangaID
 	if cached, found := c.cache.Get(cacheKey); found {
-		if result, ok := cached.(*models.Manga); ok {
+		switch result := cached.(type) {
+		case *models.Manga:
 			return result, nil
+		case cachedError:
+			return nil, result.err
 		}
 	}
 
@@ -382,17 +754,80 @@ func (c *Client) GetManga(ctx context.Context, mangaID string) (*models.Manga, e
 
 	result, err := parseResponse[SingleMangaResponse](resp)
 	if err != nil {
+		c.cacheNotFound(cacheKey, err, mangaTag(mangaID))
+		return nil, err
+	}
+
+	c.cache.SetTagged(cacheKey, result.Data, c.ttl.Default, mangaTag(mangaID))
+	return result.Data, nil
+}
+
+// GetChapters gets a manga's chapter list; chapters the server has no
+// metadata for come back numbered but untitled
+func (c *Client) GetChapters(ctx context.Context, mangaID string) (*models.ChapterList, error) {
+	cacheKey := "chapters:" + mangaID
+	if cached, found := c.cache.Get(cacheKey); found {
+		switch result := cached.(type) {
+		case *models.ChapterList:
+			return result, nil
+		case cachedError:
+			return nil, result.err
+		}
+	}
+
+	resp, err := c.doRequest(ctx, "GET", "/manga/"+mangaID+"/chapters", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	type ChapterListResponse struct {
+		Success bool                `json:"success"`
+		Data    *models.ChapterList `json:"data"`
+	}
+
+	result, err := parseResponse[ChapterListResponse](resp)
+	if err != nil {
+		c.cacheNotFound(cacheKey, err, mangaTag(mangaID))
 		return nil, err
 	}
 
-	c.cache.Set(cacheKey, result.Data, CacheDuration)
+	c.cache.SetTagged(cacheKey, result.Data, c.ttl.Default, mangaTag(mangaID))
 	return result.Data, nil
 }
 
-// SearchMangaByGenre searches for manga by genre
-func (c *Client) SearchMangaByGenre(ctx context.Context, genre string, page, pageSize int) ([]models.Manga, int, error) {
+// RandomManga picks a random manga matching the optional genre and type,
+// avoiding exclude (the one shown last). Not cached, so every call can differ.
+func (c *Client) RandomManga(ctx context.Context, genre, mangaType, exclude string) (*models.Manga, error) {
+	params := url.Values{}
+	if genre != "" {
+		params.Set("genre", genre)
+	}
+	if mangaType != "" {
+		params.Set("type", mangaType)
+	}
+	if exclude != "" {
+		params.Set("exclude", exclude)
+	}
+
+	resp, err := c.doRequest(ctx, "GET", "/manga/random?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[struct {
+		Data *models.Manga `json:"data"`
+	}](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// SearchMangaByGenre lists manga tagged with any of the genre slugs, ordered
+// by sortBy (rating, year, chapters, title) and order (asc, desc). Empty
+// values use the server default, rating descending.
+func (c *Client) SearchMangaByGenre(ctx context.Context, genres []string, sortBy, order string, page, pageSize int) ([]models.Manga, int, error) {
 	// Check cache first
-	cacheKey := fmt.Sprintf("genre:%s:%d:%d", genre, page, pageSize)
+	cacheKey := fmt.Sprintf("genre:%s:%s:%s:%d:%d", strings.Join(genres, ","), sortBy, order, page, pageSize)
 	if cached, found := c.cache.Get(cacheKey); found {
 		if result, ok := cached.(*MangaListResponse); ok {
 			return result.Data.Data, result.Data.Total, nil
@@ -400,9 +835,17 @@ func (c *Client) SearchMangaByGenre(ctx context.Context, genre string, page, pag
 	}
 
 	params := url.Values{}
-	params.Set("q", genre) // The API searches in genres JSON array
-	params.Set("page", fmt.Sprintf("%d", page))
-	params.Set("page_size", fmt.Sprintf("%d", pageSize))
+	for _, slug := range genres {
+		params.Add("genre", slug) // matched through manga_genres, not the title
+	}
+	params.Set("limit", fmt.Sprintf("%d", pageSize))
+	params.Set("offset", fmt.Sprintf("%d", (page-1)*pageSize))
+	if sortBy != "" {
+		params.Set("sort", sortBy)
+	}
+	if order != "" {
+		params.Set("order", order)
+	}
 
 	endpoint := "/manga?" + params.Encode()
 	resp, err := c.doRequest(ctx, "GET", endpoint, nil)
@@ -415,11 +858,121 @@ func (c *Client) SearchMangaByGenre(ctx context.Context, genre string, page, pag
 		return nil, 0, err
 	}
 
-	// Cache the result
-	c.cache.Set(cacheKey, result, CacheDuration)
+	// Cache the result, and each manga so opening its detail needs no request
+	c.cache.SetTagged(cacheKey, result, c.ttl.Default, mangaTags(mangaIDs(result.Data.Data))...)
+	c.cacheManga(result.Data.Data)
 	return result.Data.Data, result.Data.Total, nil
 }
 
+// GenreListResponse from GET /genres
+type GenreListResponse struct {
+	Success bool                      `json:"success"`
+	Data    *models.GenreListResponse `json:"data"`
+}
+
+// ListGenres returns every genre with its manga count, alphabetical, reading
+// as many pages as the server splits them into
+func (c *Client) ListGenres(ctx context.Context) ([]models.GenreWithCount, error) {
+	cacheKey := "genres"
+	if cached, found := c.cache.Get(cacheKey); found {
+		if result, ok := cached.([]models.GenreWithCount); ok {
+			return result, nil
+		}
+	}
+
+	genres := []models.GenreWithCount{}
+	for {
+		resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/genres?limit=100&offset=%d", len(genres)), nil)
+		if err != nil {
+			return nil, err
+		}
+		result, err := parseResponse[GenreListResponse](resp)
+		if err != nil {
+			return nil, err
+		}
+		if result.Data == nil {
+			return nil, fmt.Errorf("empty genres response")
+		}
+		genres = append(genres, result.Data.Data...)
+		if !result.Data.HasMore || len(result.Data.Data) == 0 {
+			break
+		}
+	}
+
+	c.cache.Set(cacheKey, genres, c.ttl.Default)
+	return genres, nil
+}
+
+// cacheManga stores each manga under the key GetManga and GetMangaBatch read
+func (c *Client) cacheManga(list []models.Manga) {
+	for i := range list {
+		m := list[i]
+		c.cache.SetTagged("manga:"+m.ID, &m, c.ttl.Default, mangaTag(m.ID))
+	}
+}
+
+// MangaBatchResponse from POST /manga/batch
+type MangaBatchResponse struct {
+	Success bool                      `json:"success"`
+	Data    models.MangaBatchResponse `json:"data"`
+}
+
+// GetMangaBatch retrieves several manga, in the order of ids.
+// Cached manga are served locally; only the misses go to the server, in
+// requests of at most models.MaxMangaBatch ids. Unknown ids are skipped.
+func (c *Client) GetMangaBatch(ctx context.Context, ids []string) ([]models.Manga, error) {
+	found := make(map[string]*models.Manga, len(ids))
+	var misses []string
+	for _, id := range ids {
+		if _, ok := found[id]; ok {
+			continue
+		}
+		if cached, ok := c.cache.Get("manga:" + id); ok {
+			switch m := cached.(type) {
+			case *models.Manga:
+				found[id] = m
+				continue
+			case cachedError:
+				// Recently missing: skipped like the server's unknown ids
+				found[id] = nil
+				continue
+			}
+		}
+		found[id] = nil
+		misses = append(misses, id)
+	}
+
+	for start := 0; start < len(misses); start += models.MaxMangaBatch {
+		end := min(start+models.MaxMangaBatch, len(misses))
+		resp, err := c.doRequest(ctx, "POST", "/manga/batch", models.MangaBatchRequest{IDs: misses[start:end]})
+		if err != nil {
+			return nil, err
+		}
+		result, err := parseResponse[MangaBatchResponse](resp)
+		if err != nil {
+			return nil, err
+		}
+		c.cacheManga(result.Data.Data)
+		for i := range result.Data.Data {
+			m := result.Data.Data[i]
+			found[m.ID] = &m
+		}
+		for _, id := range result.Data.Missing {
+			c.cacheNotFound("manga:"+id, &HTTPError{StatusCode: http.StatusNotFound, Code: models.ErrCodeNotFound, Message: "manga not found"}, mangaTag(id))
+		}
+	}
+
+	list := make([]models.Manga, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if m := found[id]; m != nil && !seen[id] {
+			seen[id] = true
+			list = append(list, *m)
+		}
+	}
+	return list, nil
+}
+
 // =====================================
 // LIBRARY API
 // ===================================== ==
@@ -433,6 +986,7 @@ type LibraryEntry struct {
 	IsFavorite     bool         `json:"is_favorite"`
 	LastReadAt     time.Time    `json:"last_read_at"`
 	AddedAt        time.Time    `json:"added_at"`
+	UpdatedAt      time.Time    `json:"updated_at"` // sent back as expected_updated_at
 }
 
 // LibraryResponse from library API
@@ -460,60 +1014,143 @@ func (c *Client) GetLibrary(ctx context.Context) ([]LibraryEntry, error) {
 		return nil, err
 	}
 
-	c.cache.Set(cacheKey, result.Data, LibraryCacheTTL)
+	c.cache.SetTagged(cacheKey, result.Data, c.ttl.Library, tagLibrary)
+	return result.Data, nil
+}
+
+// ContinueReadingResponse from GET /users/continue
+type ContinueReadingResponse struct {
+	Success bool                          `json:"success"`
+	Data    []models.ContinueReadingEntry `json:"data"`
+}
+
+// GetContinueReading returns up to limit manga being read, most recently read first
+func (c *Client) GetContinueReading(ctx context.Context, limit int) ([]models.ContinueReadingEntry, error) {
+	cacheKey := fmt.Sprintf("continue:%d", limit)
+	if cached, found := c.cache.Get(cacheKey); found {
+		if result, ok := cached.([]models.ContinueReadingEntry); ok {
+			return result, nil
+		}
+	}
+
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/users/continue?limit=%d", limit), nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[ContinueReadingResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+
+	c.cache.SetTagged(cacheKey, result.Data, c.ttl.Library, tagLibrary)
 	return result.Data, nil
 }
 
-// AddToLibrary adds a manga to user's library
+// AddToLibrary adds a manga to user's library. The library cache is dropped
+// whatever the outcome, so nothing cached assumes the add went through.
 func (c *Client) AddToLibrary(ctx context.Context, mangaID string) error {
-	_, err := c.doRequest(ctx, "POST", "/users/library", map[string]interface{}{
+	defer c.cache.DeleteByTag(tagLibrary) // Invalidate cache
+
+	resp, err := c.doRequest(ctx, "POST", "/users/library", map[string]interface{}{
 		"manga_id":        mangaID,
 		"status":          "plan_to_read",
 		"current_chapter": 0,
 	})
-	c.cache.Delete("library") // Invalidate cache
+	if err != nil {
+		return err
+	}
+	_, err = parseResponse[struct{}](resp)
 	return err
 }
 
 // RemoveFromLibrary removes a manga from user's library
 func (c *Client) RemoveFromLibrary(ctx context.Context, mangaID string) error {
 	_, err := c.doRequest(ctx, "DELETE", "/users/library/"+mangaID, nil)
-	c.cache.Delete("library") // Invalidate cache
+	c.cache.DeleteByTag(tagLibrary) // Invalidate cache
 	return err
 }
 
-// UpdateProgress updates reading progress with chapter, status, and favorite flag
-func (c *Client) UpdateProgress(ctx context.Context, mangaID string, chapter int, status string, isFavorite bool) error {
-	payload := map[string]interface{}{
-		"manga_id":        mangaID,
-		"current_chapter": chapter,
+// UpdateProgress updates reading progress with chapter, status, and favorite flag.
+// seen is the updated_at of the entry being edited (zero to skip the check). If
+// another device updated the entry since, the two are merged by keeping the
+// higher chapter and the update is retried once against the server's row.
+func (c *Client) UpdateProgress(ctx context.Context, mangaID string, chapter int, status string, isFavorite bool, seen time.Time) (*models.ReadingProgress, error) {
+	defer c.cache.DeleteByTag(tagLibrary) // Invalidate cache
+
+	progress, current, err := c.putProgress(ctx, mangaID, chapter, status, isFavorite, seen)
+	if current != nil {
+		progress, current, err = c.putProgress(ctx, mangaID, max(chapter, current.CurrentChapter), status, isFavorite, current.UpdatedAt)
+		if current != nil {
+			return nil, fmt.Errorf("progress keeps changing on another device, try again")
+		}
+	}
+	return progress, err
+}
+
+// putProgress sends one PUT /users/progress. On 409 it returns the server's current row.
+func (c *Client) putProgress(ctx context.Context, mangaID string, chapter int, status string, isFavorite bool, seen time.Time) (*models.ReadingProgress, *models.ReadingProgress, error) {
+	payload := map[string]interface{}{
+		"manga_id":        mangaID,
+		"current_chapter": chapter,
 	}
 	if status != "" {
 		payload["status"] = status
 	}
 	payload["is_favorite"] = isFavorite
+	if !seen.IsZero() {
+		payload["expected_updated_at"] = seen
+	}
 
-	_, err := c.doRequest(ctx, "PUT", "/users/progress", payload)
-	c.cache.Delete("library") // Invalidate cache
-	return err
+	resp, err := c.doRequest(ctx, "PUT", "/users/progress", payload)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if resp.StatusCode == http.StatusConflict {
+		defer resp.Body.Close()
+		var conflict struct {
+			Error struct {
+				Details struct {
+					Current *models.ReadingProgress `json:"current"`
+				} `json:"details"`
+			} `json:"error"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&conflict); err != nil || conflict.Error.Details.Current == nil {
+			return nil, nil, fmt.Errorf("progress was updated elsewhere")
+		}
+		return nil, conflict.Error.Details.Current, nil
+	}
+
+	type ProgressResponse struct {
+		Success bool                    `json:"success"`
+		Data    *models.ReadingProgress `json:"data"`
+	}
+	result, err := parseResponse[ProgressResponse](resp)
+	if err != nil {
+		return nil, nil, err
+	}
+	return result.Data, nil, nil
 }
 
 // =====================================
 // RATINGS API
 // =====================================
 
-// RatingSummaryResponse from ratings API
+// RatingSummaryResponse from ratings API; the summary is nested under data
 type RatingSummaryResponse struct {
-	Success bool                  `json:"success"`
-	Data    *models.RatingSummary `json:"data"`
+	Success bool                         `json:"success"`
+	Data    *models.MangaRatingsResponse `json:"data"`
 }
 
 // GetRatings retrieves rating summary for a manga
 func (c *Client) GetRatings(ctx context.Context, mangaID string) (*models.RatingSummary, error) {
 	cacheKey := "ratings:" + mangaID
 	if cached, found := c.cache.Get(cacheKey); found {
-		if result, ok := cached.(*models.RatingSummary); ok {
+		switch result := cached.(type) {
+		case *models.RatingSummary:
 			return result, nil
+		case cachedError:
+			return nil, result.err
 		}
 	}
 
@@ -524,23 +1161,76 @@ func (c *Client) GetRatings(ctx context.Context, mangaID string) (*models.Rating
 
 	result, err := parseResponse[RatingSummaryResponse](resp)
 	if err != nil {
+		c.cacheNotFound(cacheKey, err, mangaTag(mangaID))
 		return nil, err
 	}
+	if result.Data == nil {
+		return nil, fmt.Errorf("empty ratings response")
+	}
 
-	c.cache.Set(cacheKey, result.Data, CacheDuration)
-	return result.Data, nil
+	summary := &result.Data.Summary
+	c.cache.SetTagged(cacheKey, summary, c.ttl.Default, mangaTag(mangaID))
+	return summary, nil
 }
 
 // SubmitRating submits/updates a rating
-func (c *Client) SubmitRating(ctx context.Context, mangaID string, rating int, review string) error {
+// Resubmitting with different review text marks the review edited
+func (c *Client) SubmitRating(ctx context.Context, mangaID string, rating int, review string, isSpoiler bool) error {
 	_, err := c.doRequest(ctx, "POST", "/manga/"+mangaID+"/ratings", map[string]interface{}{
 		"rating":      rating, // 1-10 integer scale
 		"review_text": review,
+		"is_spoiler":  isSpoiler,
 	})
-	c.cache.Delete("ratings:" + mangaID)
+	c.invalidateManga(mangaID, tagTopRated)
 	return err
 }
 
+// SubmitScore sets only the score (1-10); an existing review is kept, so a
+// quick re-rate from the detail view never wipes what the user wrote
+func (c *Client) SubmitScore(ctx context.Context, mangaID string, rating int) error {
+	defer c.invalidateManga(mangaID, tagTopRated)
+
+	resp, err := c.doRequest(ctx, "POST", "/manga/"+mangaID+"/ratings", map[string]interface{}{
+		"rating":      rating,
+		"keep_review": true,
+	})
+	if err != nil {
+		return err
+	}
+	_, err = parseResponse[struct{}](resp)
+	return err
+}
+
+// GetReviews retrieves a page of written reviews (sort: recent or helpful).
+// Other users' spoiler reviews come back masked unless showSpoilers asks for them.
+func (c *Client) GetReviews(ctx context.Context, mangaID, sort string, page int, showSpoilers bool) (*models.ReviewsResponse, error) {
+	params := url.Values{}
+	params.Set("sort", sort)
+	params.Set("page", fmt.Sprintf("%d", page))
+	if showSpoilers {
+		params.Set("spoilers", models.SpoilersShow)
+	}
+
+	resp, err := c.doRequest(ctx, "GET", "/manga/"+mangaID+"/reviews?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	type ReviewsResponse struct {
+		Success bool                    `json:"success"`
+		Data    *models.ReviewsResponse `json:"data"`
+	}
+
+	result, err := parseResponse[ReviewsResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	if result.Data == nil {
+		return nil, fmt.Errorf("empty reviews response")
+	}
+	return result.Data, nil
+}
+
 // =====================================
 // LEADERBOARDS API
 // =====================================
@@ -564,9 +1254,10 @@ type TrendingEntry struct {
 	ActivityCount int     `json:"activity_count"`
 }
 
-// GetTrending retrieves trending manga
-func (c *Client) GetTrending(ctx context.Context, limit int, days int) ([]TrendingEntry, error) {
-	cacheKey := fmt.Sprintf("trending:%d:%d", limit, days)
+// GetTrending retrieves trending manga for a window (day, week, month),
+// optionally filtered by genre slug
+func (c *Client) GetTrending(ctx context.Context, limit int, window, genre string) ([]TrendingEntry, error) {
+	cacheKey := fmt.Sprintf("trending:%d:%s:%s", limit, window, genre)
 	if cached, found := c.cache.Get(cacheKey); found {
 		if result, ok := cached.([]TrendingEntry); ok {
 			return result, nil
@@ -575,7 +1266,10 @@ func (c *Client) GetTrending(ctx context.Context, limit int, days int) ([]Trendi
 
 	params := url.Values{}
 	params.Set("limit", fmt.Sprintf("%d", limit))
-	params.Set("days", fmt.Sprintf("%d", days))
+	params.Set("window", window)
+	if genre != "" {
+		params.Set("genre", genre)
+	}
 
 	resp, err := c.doRequest(ctx, "GET", "/leaderboards/trending?"+params.Encode(), nil)
 	if err != nil {
@@ -596,13 +1290,25 @@ func (c *Client) GetTrending(ctx context.Context, limit int, days int) ([]Trendi
 		return nil, err
 	}
 
-	c.cache.Set(cacheKey, rawResp.Data.Entries, TrendingCacheTTL)
+	c.cache.SetTagged(cacheKey, rawResp.Data.Entries, c.ttl.Trending, mangaTags(entryIDs(rawResp.Data.Entries))...)
 	return rawResp.Data.Entries, nil
 }
 
-// GetTopRated retrieves top rated manga
-func (c *Client) GetTopRated(ctx context.Context, limit int) ([]TrendingEntry, error) {
-	cacheKey := fmt.Sprintf("toprated:%d", limit)
+// GetTopRated retrieves top rated manga (Bayesian average), optionally
+// filtered by genre and manga type (manga, manhwa, manhua, novel)
+func (c *Client) GetTopRated(ctx context.Context, limit int, genre, mangaType string) ([]TrendingEntry, error) {
+	return c.getRanking(ctx, "/leaderboards/manga", "toprated", limit, genre, mangaType)
+}
+
+// GetHiddenGems retrieves well-rated manga with too few ratings to be top rated
+func (c *Client) GetHiddenGems(ctx context.Context, limit int, genre, mangaType string) ([]TrendingEntry, error) {
+	return c.getRanking(ctx, "/leaderboards/manga/hidden-gems", "hiddengems", limit, genre, mangaType)
+}
+
+// getRanking fetches one rating-based leaderboard; a new rating can move any
+// manga into it, so every page is tagged tagTopRated
+func (c *Client) getRanking(ctx context.Context, path, name string, limit int, genre, mangaType string) ([]TrendingEntry, error) {
+	cacheKey := fmt.Sprintf("%s:%d:%s:%s", name, limit, genre, mangaType)
 	if cached, found := c.cache.Get(cacheKey); found {
 		if result, ok := cached.([]TrendingEntry); ok {
 			return result, nil
@@ -611,8 +1317,14 @@ func (c *Client) GetTopRated(ctx context.Context, limit int) ([]TrendingEntry, e
 
 	params := url.Values{}
 	params.Set("limit", fmt.Sprintf("%d", limit))
+	if genre != "" {
+		params.Set("genre", genre)
+	}
+	if mangaType != "" {
+		params.Set("type", mangaType)
+	}
 
-	resp, err := c.doRequest(ctx, "GET", "/leaderboards/manga?"+params.Encode(), nil)
+	resp, err := c.doRequest(ctx, "GET", path+"?"+params.Encode(), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -630,7 +1342,8 @@ func (c *Client) GetTopRated(ctx context.Context, limit int) ([]TrendingEntry, e
 		return nil, err
 	}
 
-	c.cache.Set(cacheKey, rawResp.Data.Entries, TrendingCacheTTL)
+	c.cache.SetTagged(cacheKey, rawResp.Data.Entries, c.ttl.Trending,
+		mangaTags(entryIDs(rawResp.Data.Entries), tagTopRated)...)
 	return rawResp.Data.Entries, nil
 }
 
@@ -638,11 +1351,23 @@ func (c *Client) GetTopRated(ctx context.Context, limit int) ([]TrendingEntry, e
 // COMMENTS API
 // =====================================
 
-// GetComments retrieves comments for a manga
-func (c *Client) GetComments(ctx context.Context, mangaID string, page, pageSize int) (*models.CommentListResponse, error) {
+// GetComments retrieves a page of threaded comments for a manga (replies nested two levels deep)
+// chapter is "" for every comment, "general" for chapter-less ones, or a chapter number
+// cursor is "" for the newest page, or the previous page's NextCursor
+// showSpoilers asks for spoiler comments the server would otherwise mask
+func (c *Client) GetComments(ctx context.Context, mangaID, chapter, cursor string, pageSize int, showSpoilers bool) (*models.CommentListResponse, error) {
 	params := url.Values{}
-	params.Set("page", fmt.Sprintf("%d", page))
 	params.Set("page_size", fmt.Sprintf("%d", pageSize))
+	if showSpoilers {
+		params.Set("spoilers", models.SpoilersShow)
+	}
+	if cursor != "" {
+		params.Set("cursor", cursor)
+	}
+	params.Set("threaded", "true")
+	if chapter != "" {
+		params.Set("chapter", chapter)
+	}
 
 	resp, err := c.doRequest(ctx, "GET", "/manga/"+mangaID+"/comments?"+params.Encode(), nil)
 	if err != nil {
@@ -662,11 +1387,12 @@ func (c *Client) GetComments(ctx context.Context, mangaID string, page, pageSize
 	return result.Data, nil
 }
 
-// PostComment posts a new comment on a manga
-func (c *Client) PostComment(ctx context.Context, mangaID, content string, chapterNum *int, parentID *string) error {
+// PostComment posts a new comment on a manga, flagged as a spoiler when isSpoiler
+func (c *Client) PostComment(ctx context.Context, mangaID, content string, chapterNum *int, parentID *string, isSpoiler bool) error {
 	payload := map[string]interface{}{
-		"manga_id": mangaID,
-		"content":  content,
+		"manga_id":   mangaID,
+		"content":    content,
+		"is_spoiler": isSpoiler,
 	}
 	if chapterNum != nil {
 		payload["chapter_number"] = *chapterNum
@@ -676,6 +1402,7 @@ func (c *Client) PostComment(ctx context.Context, mangaID, content string, chapt
 	}
 
 	_, err := c.doRequest(ctx, "POST", "/manga/"+mangaID+"/comments", payload)
+	c.invalidateManga(mangaID) // trending counts comment activity
 	return err
 }
 
@@ -691,6 +1418,17 @@ func (c *Client) UnlikeComment(ctx context.Context, commentID string) error {
 	return err
 }
 
+// DeleteComment deletes a comment on mangaID (own comments, or any comment for moderators)
+func (c *Client) DeleteComment(ctx context.Context, mangaID, commentID string) error {
+	resp, err := c.doRequest(ctx, "DELETE", "/comments/"+commentID, nil)
+	c.invalidateManga(mangaID)
+	if err != nil {
+		return err
+	}
+	_, err = parseResponse[struct{}](resp)
+	return err
+}
+
 // =====================================
 // HEALTH CHECK
 // =====================================
@@ -752,41 +1490,682 @@ func (c *Client) GetActivities(ctx context.Context, limit int) ([]ActivityEntry,
 		return nil, err
 	}
 
-	c.cache.Set(cacheKey, rawResp.Activities, DashboardCacheTTL)
+	c.cache.Set(cacheKey, rawResp.Activities, c.ttl.Dashboard)
 	return rawResp.Activities, nil
 }
 
+// ActivityPage is one page of the activity feed
+type ActivityPage struct {
+	Activities []ActivityEntry `json:"activities"`
+	Total      int             `json:"total"`
+	HasMore    bool            `json:"has_more"`
+	NextBefore string          `json:"next_before"`
+}
+
+// GetActivityPage retrieves one page of the feed, optionally filtered by
+// activity type. Pass the previous page's NextBefore to continue; not cached.
+func (c *Client) GetActivityPage(ctx context.Context, activityType, before string, limit int) (*ActivityPage, error) {
+	params := url.Values{}
+	params.Set("limit", fmt.Sprintf("%d", limit))
+	if activityType != "" {
+		params.Set("type", activityType)
+	}
+	if before != "" {
+		params.Set("before", before)
+	}
+
+	resp, err := c.doRequest(ctx, "GET", "/activities?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, decodeError(resp.StatusCode, body)
+	}
+
+	// Same un-wrapped shape as GetActivities
+	var page ActivityPage
+	if err := json.Unmarshal(body, &page); err != nil {
+		return nil, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return &page, nil
+}
+
 // =====================================
 // LIBRARY STATUS UPDATES
 // =====================================
 
-// UpdateLibraryStatus updates the reading status of a manga in library
+// libraryEntry finds a manga in the (cached) library, nil if it is not there.
+// PUT /users/progress replaces the whole row, so partial updates start from it.
+func (c *Client) libraryEntry(ctx context.Context, mangaID string) (*LibraryEntry, error) {
+	entries, err := c.GetLibrary(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		if entries[i].MangaID == mangaID {
+			entry := entries[i]
+			return &entry, nil
+		}
+	}
+	return nil, nil
+}
+
+// UpdateLibraryStatus updates the reading status of a manga in library,
+// keeping its chapter and favorite flag
 func (c *Client) UpdateLibraryStatus(ctx context.Context, mangaID string, status string) error {
-	_, err := c.doRequest(ctx, "PUT", "/users/progress", map[string]interface{}{
-		"manga_id": mangaID,
-		"status":   status,
-	})
-	c.cache.Delete("library") // Invalidate cache
+	entry, err := c.libraryEntry(ctx, mangaID)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return fmt.Errorf("manga is not in your library")
+	}
+	_, err = c.UpdateProgress(ctx, mangaID, entry.CurrentChapter, status, entry.IsFavorite, entry.UpdatedAt)
 	return err
 }
 
-// UpdateLibraryProgress updates both status and chapter progress
+// UpdateLibraryProgress updates both status and chapter progress, keeping the favorite flag
 func (c *Client) UpdateLibraryProgress(ctx context.Context, mangaID string, status string, chapter int) error {
-	_, err := c.doRequest(ctx, "PUT", "/users/progress", map[string]interface{}{
-		"manga_id":        mangaID,
-		"status":          status,
-		"current_chapter": chapter,
-	})
-	c.cache.Delete("library") // Invalidate cache
+	entry, err := c.libraryEntry(ctx, mangaID)
+	if err != nil {
+		return err
+	}
+	isFavorite := entry != nil && entry.IsFavorite
+	// No concurrency check: the reader may move the chapter back on purpose
+	_, err = c.UpdateProgress(ctx, mangaID, chapter, status, isFavorite, time.Time{})
 	return err
 }
 
-// ToggleFavorite toggles favorite status for a manga
+// CatchUpResponse from PUT /users/progress/catchup
+type CatchUpResponse struct {
+	Success bool                    `json:"success"`
+	Data    *models.CatchUpResponse `json:"data"`
+}
+
+// CatchUpProgress jumps a manga's progress to its latest chapter and marks it completed
+func (c *Client) CatchUpProgress(ctx context.Context, mangaID string) (*models.CatchUpResponse, error) {
+	defer c.cache.DeleteByTag(tagLibrary) // Invalidate cache
+
+	resp, err := c.doRequest(ctx, "PUT", "/users/progress/catchup", map[string]interface{}{
+		"manga_id": mangaID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[CatchUpResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// BulkStatusResponse from PUT /users/library/bulk-status
+type BulkStatusResponse struct {
+	Success bool                       `json:"success"`
+	Data    *models.BulkStatusResponse `json:"data"`
+}
+
+// BulkUpdateStatus moves many library entries to one status in a single
+// request. The library cache is invalidated once for the whole batch; manga
+// the server could not update are listed in the result's Failed.
+func (c *Client) BulkUpdateStatus(ctx context.Context, mangaIDs []string, status string) (*models.BulkStatusResponse, error) {
+	defer c.cache.DeleteByTag(tagLibrary) // Invalidate cache
+
+	resp, err := c.doRequest(ctx, "PUT", "/users/library/bulk-status", models.BulkStatusRequest{
+		MangaIDs: mangaIDs,
+		Status:   status,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[BulkStatusResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// ToggleFavorite sets the favorite flag of a manga in the library, keeping its
+// chapter and status. The library cache is invalidated, so every view that
+// reads it next sees the change.
 func (c *Client) ToggleFavorite(ctx context.Context, mangaID string, isFavorite bool) error {
-	_, err := c.doRequest(ctx, "PUT", "/users/progress", map[string]interface{}{
-		"manga_id":    mangaID,
-		"is_favorite": isFavorite,
+	entry, err := c.libraryEntry(ctx, mangaID)
+	if err != nil {
+		return err
+	}
+	if entry == nil {
+		return fmt.Errorf("manga is not in your library")
+	}
+	_, err = c.UpdateProgress(ctx, mangaID, entry.CurrentChapter, entry.Status, isFavorite, entry.UpdatedAt)
+	return err
+}
+
+// =====================================
+// CHAPTER HISTORY
+// =====================================
+
+// ChapterHistoryResponse from POST/DELETE /users/chapter-history
+type ChapterHistoryResponse struct {
+	Success bool                   `json:"success"`
+	Data    *models.ChapterHistory `json:"data"`
+}
+
+// ChapterHistoryListResponse from GET /users/chapter-history
+type ChapterHistoryListResponse struct {
+	Success bool                    `json:"success"`
+	Data    []models.ChapterHistory `json:"data"`
+}
+
+// RecordChapterRead records a finished chapter in the user's reading history
+// The returned entry's ID can be passed to DeleteChapterRead to undo it
+func (c *Client) RecordChapterRead(ctx context.Context, mangaID string, chapter, pages, minutes int) (*models.ChapterHistory, error) {
+	resp, err := c.doRequest(ctx, "POST", "/users/chapter-history", map[string]interface{}{
+		"manga_id":       mangaID,
+		"chapter_number": chapter,
+		"pages_read":     pages,
+		"time_minutes":   minutes,
 	})
-	c.cache.Delete("library") // Invalidate cache
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[ChapterHistoryResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetChapterHistory retrieves the user's most recent chapter reads, newest first
+func (c *Client) GetChapterHistory(ctx context.Context, limit int) ([]models.ChapterHistory, error) {
+	resp, err := c.doRequest(ctx, "GET", fmt.Sprintf("/users/chapter-history?limit=%d", limit), nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[ChapterHistoryListResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// DeleteChapterRead removes a chapter read recorded by mistake
+func (c *Client) DeleteChapterRead(ctx context.Context, historyID string) (*models.ChapterHistory, error) {
+	resp, err := c.doRequest(ctx, "DELETE", "/users/chapter-history/"+url.PathEscape(historyID), nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[ChapterHistoryResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// =====================================
+// STATS & READING GOALS
+// =====================================
+
+// StatsOverviewResponse from GET /users/stats/overview
+type StatsOverviewResponse struct {
+	Success bool                  `json:"success"`
+	Data    *models.StatsOverview `json:"data"`
+}
+
+// HeatmapResponse from GET /users/stats/heatmap
+type HeatmapResponse struct {
+	Success bool                `json:"success"`
+	Data    []models.HeatmapDay `json:"data"`
+}
+
+// GoalsResponse from GET /users/goals
+type GoalsResponse struct {
+	Success bool                  `json:"success"`
+	Data    []models.GoalProgress `json:"data"`
+}
+
+// AchievementsResponse from GET /users/achievements
+type AchievementsResponse struct {
+	Success bool                 `json:"success"`
+	Data    []models.Achievement `json:"data"`
+}
+
+// GoalResponse from POST /users/goals
+type GoalResponse struct {
+	Success bool                 `json:"success"`
+	Data    *models.GoalProgress `json:"data"`
+}
+
+// GetStatsOverview retrieves the user's reading totals and streaks
+func (c *Client) GetStatsOverview(ctx context.Context) (*models.StatsOverview, error) {
+	resp, err := c.doRequest(ctx, "GET", "/users/stats/overview", nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[StatsOverviewResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetReadingHeatmap retrieves one entry per day for the last days days,
+// oldest first and ending on the server's today (UTC)
+func (c *Client) GetReadingHeatmap(ctx context.Context, days int) ([]models.HeatmapDay, error) {
+	resp, err := c.doRequest(ctx, "GET", "/users/stats/heatmap?days="+strconv.Itoa(days), nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[HeatmapResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetGoals retrieves the user's current reading goals with progress
+func (c *Client) GetGoals(ctx context.Context) ([]models.GoalProgress, error) {
+	resp, err := c.doRequest(ctx, "GET", "/users/goals", nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[GoalsResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// GetAchievements retrieves every badge, earned or locked, with the user's progress.
+// The server awards newly reached badges on this call.
+func (c *Client) GetAchievements(ctx context.Context) ([]models.Achievement, error) {
+	resp, err := c.doRequest(ctx, "GET", "/users/achievements", nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[AchievementsResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// SetGoal sets the chapter target for the current year or month
+func (c *Client) SetGoal(ctx context.Context, period string, target int) (*models.GoalProgress, error) {
+	resp, err := c.doRequest(ctx, "POST", "/users/goals", models.SetGoalRequest{
+		Period:         period,
+		TargetChapters: target,
+	})
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[GoalResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// =====================================
+// PUBLIC PROFILES
+// =====================================
+
+// ProfileResponse from GET /users/:username
+type ProfileResponse struct {
+	Success bool                  `json:"success"`
+	Data    *models.PublicProfile `json:"data"`
+}
+
+// GetProfile retrieves a user's public profile
+func (c *Client) GetProfile(ctx context.Context, username string) (*models.PublicProfile, error) {
+	resp, err := c.doRequest(ctx, "GET", "/users/"+url.PathEscape(username), nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[ProfileResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// =====================================
+// PREFERENCES
+// =====================================
+
+// PreferencesResponse from GET/PUT /users/preferences
+type PreferencesResponse struct {
+	Success bool                    `json:"success"`
+	Data    *models.UserPreferences `json:"data"`
+}
+
+// GetPreferences retrieves the user's app preferences
+func (c *Client) GetPreferences(ctx context.Context) (*models.UserPreferences, error) {
+	resp, err := c.doRequest(ctx, "GET", "/users/preferences", nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[PreferencesResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// UpdatePreferences saves the fields set in req
+func (c *Client) UpdatePreferences(ctx context.Context, req models.UpdatePreferencesRequest) (*models.UserPreferences, error) {
+	resp, err := c.doRequest(ctx, "PUT", "/users/preferences", req)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[PreferencesResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// MangaMuteResponse from GET/PUT/DELETE /manga/:id/mute
+type MangaMuteResponse struct {
+	Success bool              `json:"success"`
+	Data    *models.MangaMute `json:"data"`
+}
+
+// GetMangaMute reports whether updates for a manga are muted
+func (c *Client) GetMangaMute(ctx context.Context, mangaID string) (bool, error) {
+	resp, err := c.doRequest(ctx, "GET", "/manga/"+mangaID+"/mute", nil)
+	if err != nil {
+		return false, err
+	}
+	result, err := parseResponse[MangaMuteResponse](resp)
+	if err != nil {
+		return false, err
+	}
+	return result.Data != nil && result.Data.Muted, nil
+}
+
+// SetMangaMute mutes or unmutes update notifications for a manga
+func (c *Client) SetMangaMute(ctx context.Context, mangaID string, muted bool) error {
+	method := "DELETE"
+	if muted {
+		method = "PUT"
+	}
+	resp, err := c.doRequest(ctx, method, "/manga/"+mangaID+"/mute", nil)
+	if err != nil {
+		return err
+	}
+	_, err = parseResponse[MangaMuteResponse](resp)
+	return err
+}
+
+// =====================================
+// DATA EXPORT
+// =====================================
+
+// ExportData downloads the user's data export.
+// Format is "json" or "csv"; the returned filename carries the matching extension.
+func (c *Client) ExportData(ctx context.Context, format string) (string, []byte, error) {
+	resp, err := c.doRequest(ctx, "GET", "/users/export?format="+url.QueryEscape(format), nil)
+	if err != nil {
+		return "", nil, err
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to read export: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return "", nil, decodeError(resp.StatusCode, body)
+	}
+
+	filename := "mangahub-export." + format
+	if format == "csv" {
+		filename = "mangahub-export.zip"
+	}
+	if _, params, err := mime.ParseMediaType(resp.Header.Get("Content-Disposition")); err == nil && params["filename"] != "" {
+		filename = filepath.Base(params["filename"])
+	}
+	return filename, body, nil
+}
+
+// =====================================
+// LIBRARY IMPORT
+// =====================================
+
+// BulkImportResponse from library bulk import API
+type BulkImportResponse struct {
+	Success bool                              `json:"success"`
+	Data    *models.BulkImportLibraryResponse `json:"data"`
+}
+
+// ImportLibrary sends library entries to the bulk import endpoint.
+// Entries whose manga is not in the DB yet come back as "queued".
+func (c *Client) ImportLibrary(ctx context.Context, entries []models.BulkLibraryEntry) (*models.BulkImportLibraryResponse, error) {
+	resp, err := c.doRequest(ctx, "POST", "/users/library/bulk", entries)
+	if err != nil {
+		return nil, err
+	}
+	c.cache.DeleteByTag(tagLibrary) // Invalidate cache
+
+	result, err := parseResponse[BulkImportResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// =====================================
+// CUSTOM LISTS
+// =====================================
+
+// CustomListsResponse from GET /users/lists
+type CustomListsResponse struct {
+	Success bool                        `json:"success"`
+	Data    *models.CustomListsResponse `json:"data"`
+}
+
+// CustomListResponse from the single-list endpoints
+type CustomListResponse struct {
+	Success bool                        `json:"success"`
+	Data    *models.CustomListWithItems `json:"data"`
+}
+
+// GetLists retrieves the user's custom lists
+func (c *Client) GetLists(ctx context.Context) ([]models.CustomList, error) {
+	resp, err := c.doRequest(ctx, "GET", "/users/lists", nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[CustomListsResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	if result.Data == nil {
+		return nil, nil
+	}
+	return result.Data.Lists, nil
+}
+
+// GetList retrieves a list with its manga
+func (c *Client) GetList(ctx context.Context, listID string) (*models.CustomListWithItems, error) {
+	resp, err := c.doRequest(ctx, "GET", "/users/lists/"+url.PathEscape(listID), nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[CustomListResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// CreateList creates a new custom list
+func (c *Client) CreateList(ctx context.Context, req models.CreateListRequest) error {
+	return c.listRequest(ctx, "POST", "/users/lists", req)
+}
+
+// UpdateList changes a list's name, description, icon or visibility
+func (c *Client) UpdateList(ctx context.Context, listID string, req models.UpdateListRequest) error {
+	return c.listRequest(ctx, "PUT", "/users/lists/"+url.PathEscape(listID), req)
+}
+
+// DeleteList deletes a custom list
+func (c *Client) DeleteList(ctx context.Context, listID string) error {
+	return c.listRequest(ctx, "DELETE", "/users/lists/"+url.PathEscape(listID), nil)
+}
+
+// AddToList adds a manga to a list; fails with CONFLICT if it is already there
+func (c *Client) AddToList(ctx context.Context, listID, mangaID string) error {
+	return c.listRequest(ctx, "POST", "/users/lists/"+url.PathEscape(listID)+"/items",
+		models.AddToListRequest{MangaID: mangaID})
+}
+
+// RemoveFromList removes a manga from a list
+func (c *Client) RemoveFromList(ctx context.Context, listID, mangaID string) error {
+	return c.listRequest(ctx, "DELETE",
+		"/users/lists/"+url.PathEscape(listID)+"/items/"+url.PathEscape(mangaID), nil)
+}
+
+// ReorderList saves a new item order
+func (c *Client) ReorderList(ctx context.Context, listID string, itemIDs []string) error {
+	return c.listRequest(ctx, "PUT", "/users/lists/"+url.PathEscape(listID)+"/items",
+		models.ReorderListRequest{ItemIDs: itemIDs})
+}
+
+// listRequest sends a list mutation and surfaces API errors
+func (c *Client) listRequest(ctx context.Context, method, endpoint string, body interface{}) error {
+	resp, err := c.doRequest(ctx, method, endpoint, body)
+	if err != nil {
+		return err
+	}
+	_, err = parseResponse[models.APIResponse](resp)
+	return err
+}
+
+// =====================================
+// CHAT HISTORY
+// =====================================
+
+// ChatHistoryMessage is a persisted chat message
+type ChatHistoryMessage struct {
+	ID        string    `json:"id"`
+	RoomID    string    `json:"room_id"`
+	UserID    string    `json:"user_id"`
+	Username  string    `json:"username"`
+	Content   string    `json:"content"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// ChatHistoryResponse from GET /rooms/:room_id/messages, oldest message first
+type ChatHistoryResponse struct {
+	Messages []ChatHistoryMessage `json:"messages"`
+	HasMore  bool                 `json:"has_more"`
+}
+
+// GetRoomMessages retrieves a page of chat history older than beforeID
+// Empty beforeID returns the latest page
+func (c *Client) GetRoomMessages(ctx context.Context, roomID, beforeID string, limit int) (*ChatHistoryResponse, error) {
+	params := url.Values{}
+	params.Set("limit", strconv.Itoa(limit))
+	if beforeID != "" {
+		params.Set("before", beforeID)
+	}
+
+	resp, err := c.doRequest(ctx, "GET", "/rooms/"+url.PathEscape(roomID)+"/messages?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[ChatHistoryResponse](resp)
+}
+
+// ChatRoom is a room in the rooms directory
+type ChatRoom struct {
+	ID          string  `json:"id"`
+	Name        string  `json:"name"`
+	RoomType    string  `json:"room_type"` // general, manga
+	MangaID     *string `json:"manga_id,omitempty"`
+	Description string  `json:"description"`
+	IsActive    bool    `json:"is_active"`
+	MemberCount int     `json:"member_count"`
+}
+
+// RoomDirectoryResponse from GET /rooms, busiest rooms first
+type RoomDirectoryResponse struct {
+	Rooms   []ChatRoom `json:"rooms"`
+	Total   int        `json:"total"`
+	Page    int        `json:"page"`
+	HasMore bool       `json:"has_more"`
+}
+
+// ListRooms retrieves a page of the rooms directory.
+// roomType is "general", "manga" or "" for both; hideEmpty leaves out rooms nobody is in.
+func (c *Client) ListRooms(ctx context.Context, roomType string, hideEmpty bool, page, limit int) (*RoomDirectoryResponse, error) {
+	params := url.Values{}
+	params.Set("page", strconv.Itoa(page))
+	params.Set("limit", strconv.Itoa(limit))
+	if roomType != "" {
+		params.Set("type", roomType)
+	}
+	if hideEmpty {
+		params.Set("hide_empty", "true")
+	}
+
+	resp, err := c.doRequest(ctx, "GET", "/rooms?"+params.Encode(), nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[RoomDirectoryResponse](resp)
+}
+
+// OpenMangaRoom returns a manga's chat room, which the server creates on first use
+func (c *Client) OpenMangaRoom(ctx context.Context, mangaID string) (*ChatRoom, error) {
+	resp, err := c.doRequest(ctx, "POST", "/rooms/manga/"+url.PathEscape(mangaID), nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[ChatRoom](resp)
+}
+
+// RoomUnread is one room's unread message count
+type RoomUnread struct {
+	RoomID     string    `json:"room_id"`
+	RoomName   string    `json:"room_name"`
+	Unread     int       `json:"unread"`
+	LastReadAt time.Time `json:"last_read_at"`
+}
+
+// UnreadResponse from GET /rooms/unread
+type UnreadResponse struct {
+	Rooms []RoomUnread `json:"rooms"`
+	Total int          `json:"total"`
+}
+
+// GetUnread retrieves the unread message counts of the rooms the user has read
+func (c *Client) GetUnread(ctx context.Context) (*UnreadResponse, error) {
+	resp, err := c.doRequest(ctx, "GET", "/rooms/unread", nil)
+	if err != nil {
+		return nil, err
+	}
+	return parseResponse[UnreadResponse](resp)
+}
+
+// MarkRoomRead tells the server the user has read a room up to messageID
+// (empty: its newest message)
+func (c *Client) MarkRoomRead(ctx context.Context, roomID, messageID string) error {
+	var body interface{}
+	if messageID != "" {
+		body = map[string]string{"message_id": messageID}
+	}
+	resp, err := c.doRequest(ctx, "POST", "/rooms/"+url.PathEscape(roomID)+"/read", body)
+	if err != nil {
+		return err
+	}
+	_, err = parseResponse[struct{}](resp)
 	return err
 }
@@ -0,0 +1,140 @@
+// Package api - Library Import Files
+// Đọc file export từ nền tảng khác để bulk import vào library
+// Định dạng hỗ trợ:
+//   - MyAnimeList XML export (.xml hoặc .xml.gz)
+//   - MyAnimeList JSON list (manga_id, manga_title, num_read_chapters, status 1-6)
+//   - JSON array theo đúng format của POST /users/library/bulk
+package api
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"mangahub/pkg/models"
+)
+
+// malExportXML is the root of a MyAnimeList manga list export
+type malExportXML struct {
+	Manga []struct {
+		ID       string `xml:"manga_mangadb_id"`
+		Title    string `xml:"manga_title"`
+		Chapters int    `xml:"my_read_chapters"`
+		Status   string `xml:"my_status"`
+	} `xml:"manga"`
+}
+
+// libraryFileEntry accepts both the bulk import format and MAL's JSON list format
+type libraryFileEntry struct {
+	models.BulkLibraryEntry
+
+	// MAL JSON list fields
+	MALID        json.Number     `json:"manga_id"`
+	MALTitle     string          `json:"manga_title"`
+	ReadChapters int             `json:"num_read_chapters"`
+	MALStatus    json.RawMessage `json:"status"`
+}
+
+// ParseLibraryFile reads a library export and converts it to bulk import entries
+func ParseLibraryFile(path string) ([]models.BulkLibraryEntry, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	// MAL serves its exports gzipped
+	if bytes.HasPrefix(data, []byte{0x1f, 0x8b}) {
+		zr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("failed to open gzip: %w", err)
+		}
+		if data, err = io.ReadAll(zr); err != nil {
+			return nil, fmt.Errorf("failed to read gzip: %w", err)
+		}
+	}
+
+	trimmed := bytes.TrimSpace(data)
+	switch {
+	case bytes.HasPrefix(trimmed, []byte("<")):
+		return parseMALXML(trimmed)
+	case bytes.HasPrefix(trimmed, []byte("[")):
+		return parseLibraryJSON(trimmed)
+	default:
+		return nil, fmt.Errorf("unrecognised file format: expected MAL XML or a JSON array")
+	}
+}
+
+func parseMALXML(data []byte) ([]models.BulkLibraryEntry, error) {
+	var export malExportXML
+	if err := xml.Unmarshal(data, &export); err != nil {
+		return nil, fmt.Errorf("invalid MAL XML: %w", err)
+	}
+
+	entries := make([]models.BulkLibraryEntry, 0, len(export.Manga))
+	for _, m := range export.Manga {
+		entries = append(entries, models.BulkLibraryEntry{
+			ExternalID:     strings.TrimSpace(m.ID),
+			Source:         models.ImportSourceMAL,
+			Title:          strings.TrimSpace(m.Title),
+			Status:         malStatus(m.Status),
+			CurrentChapter: m.Chapters,
+		})
+	}
+	return entries, nil
+}
+
+func parseLibraryJSON(data []byte) ([]models.BulkLibraryEntry, error) {
+	var rows []libraryFileEntry
+	if err := json.Unmarshal(data, &rows); err != nil {
+		return nil, fmt.Errorf("invalid JSON: %w", err)
+	}
+
+	entries := make([]models.BulkLibraryEntry, 0, len(rows))
+	for _, r := range rows {
+		if r.ExternalID != "" {
+			// "status" is shadowed by the MAL field above
+			entry := r.BulkLibraryEntry
+			_ = json.Unmarshal(r.MALStatus, &entry.Status)
+			entries = append(entries, entry)
+			continue
+		}
+		// MAL JSON list row
+		var status string
+		if err := json.Unmarshal(r.MALStatus, &status); err != nil {
+			var code int
+			_ = json.Unmarshal(r.MALStatus, &code)
+			status = strconv.Itoa(code)
+		}
+		entries = append(entries, models.BulkLibraryEntry{
+			ExternalID:     r.MALID.String(),
+			Source:         models.ImportSourceMAL,
+			Title:          r.MALTitle,
+			Status:         malStatus(status),
+			CurrentChapter: r.ReadChapters,
+		})
+	}
+	return entries, nil
+}
+
+// malStatus maps MAL's status names and numeric codes to library statuses
+func malStatus(s string) string {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "reading", "1":
+		return "reading"
+	case "completed", "2":
+		return "completed"
+	case "on-hold", "on hold", "3":
+		return "on_hold"
+	case "dropped", "4":
+		return "dropped"
+	default:
+		// "Plan to Read" (6) and anything unknown
+		return "plan_to_read"
+	}
+}
@@ -0,0 +1,87 @@
+// Package api - First-Run State
+// Ghi nhớ TUI đã qua màn hình onboarding chưa, lưu bằng viper vào ~/.mangahub/tui.yaml
+// Chức năng:
+//   - First run: chưa có token, chưa có config CLI (~/.mangahub/config.yaml) và chưa đánh dấu onboarded
+//   - Lưu theme và default view đã chọn để guest vẫn giữ được lựa chọn
+package api
+
+import (
+	"os"
+	"path/filepath"
+
+	"github.com/spf13/viper"
+)
+
+// LocalSettings are the TUI choices kept on this machine
+type LocalSettings struct {
+	Onboarded   bool   // the onboarding screen was finished or dismissed
+	Theme       string // theme picked during onboarding ("" = default)
+	DefaultView string // startup view picked during onboarding ("" = dashboard)
+}
+
+// localSettingsFile is where the first-run state lives
+func localSettingsFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".mangahub", "tui.yaml"), nil
+}
+
+// LoadLocalSettings reads the local TUI settings; a missing file is a first run
+func LoadLocalSettings() (LocalSettings, error) {
+	path, err := localSettingsFile()
+	if err != nil {
+		return LocalSettings{}, err
+	}
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		return LocalSettings{}, nil
+	}
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if err := v.ReadInConfig(); err != nil {
+		return LocalSettings{}, err
+	}
+	return LocalSettings{
+		Onboarded:   v.GetBool("onboarded"),
+		Theme:       v.GetString("theme"),
+		DefaultView: v.GetString("default_view"),
+	}, nil
+}
+
+// SaveLocalSettings replaces the local TUI settings file
+func SaveLocalSettings(s LocalSettings) error {
+	path, err := localSettingsFile()
+	if err != nil {
+		return err
+	}
+	v := viper.New()
+	v.SetConfigType("yaml")
+	v.Set("onboarded", s.Onboarded)
+	v.Set("theme", s.Theme)
+	v.Set("default_view", s.DefaultView)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return v.WriteConfigAs(path)
+}
+
+// IsFirstRun reports whether onboarding should be shown: nobody is logged in,
+// the CLI was never configured here and onboarding was not finished or
+// dismissed before. An unreadable settings file counts as not first run, so a
+// broken file never traps the user.
+func (c *Client) IsFirstRun() bool {
+	if c.IsAuthenticated() {
+		return false
+	}
+	path, err := localSettingsFile()
+	if err != nil {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(filepath.Dir(path), "config.yaml")); err == nil {
+		return false
+	}
+	s, err := LoadLocalSettings()
+	return err == nil && !s.Onboarded
+}
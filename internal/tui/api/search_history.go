@@ -0,0 +1,226 @@
+// Package api - Search History & Saved Searches
+// Lịch sử tìm kiếm và saved searches cho Search view
+// Chức năng:
+//   - User đã login: lưu trên server (GET/POST/DELETE /users/searches, /users/search-history)
+//   - Guest: recent searches lưu bằng viper vào ~/.mangahub/search_history.yaml
+//   - Tìm kiếm có filter (genre, sort, order) qua GET /manga
+package api
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/spf13/viper"
+
+	"mangahub/pkg/models"
+)
+
+// SearchesResponse from GET /users/searches
+type SearchesResponse struct {
+	Success bool                     `json:"success"`
+	Data    *models.SearchesResponse `json:"data"`
+}
+
+// SavedSearchResponse from POST /users/searches
+type SavedSearchResponse struct {
+	Success bool                `json:"success"`
+	Data    *models.SavedSearch `json:"data"`
+}
+
+// GetSearches returns saved searches and recent history.
+// Guests get their local history and no saved searches.
+func (c *Client) GetSearches(ctx context.Context) (*models.SearchesResponse, error) {
+	if !c.IsAuthenticated() {
+		return &models.SearchesResponse{Recent: guestRecentSearches()}, nil
+	}
+	resp, err := c.doRequest(ctx, "GET", "/users/searches", nil)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[SearchesResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	if result.Data == nil {
+		return &models.SearchesResponse{}, nil
+	}
+	return result.Data, nil
+}
+
+// RecordSearch adds a search to the history, on the server or locally for guests
+func (c *Client) RecordSearch(ctx context.Context, filters models.SearchFilters) error {
+	if filters.IsEmpty() {
+		return nil
+	}
+	if !c.IsAuthenticated() {
+		return recordGuestSearch(filters)
+	}
+	return c.listRequest(ctx, "POST", "/users/search-history", filters)
+}
+
+// ClearSearchHistory removes every recent search
+func (c *Client) ClearSearchHistory(ctx context.Context) error {
+	if !c.IsAuthenticated() {
+		return writeGuestSearches(nil)
+	}
+	return c.listRequest(ctx, "DELETE", "/users/search-history", nil)
+}
+
+// SaveSearch stores a named search; fails with CONFLICT if the name is taken
+func (c *Client) SaveSearch(ctx context.Context, req models.SaveSearchRequest) (*models.SavedSearch, error) {
+	resp, err := c.doRequest(ctx, "POST", "/users/searches", req)
+	if err != nil {
+		return nil, err
+	}
+	result, err := parseResponse[SavedSearchResponse](resp)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, nil
+}
+
+// DeleteSavedSearch removes a saved search
+func (c *Client) DeleteSavedSearch(ctx context.Context, id string) error {
+	return c.listRequest(ctx, "DELETE", "/users/searches/"+url.PathEscape(id), nil)
+}
+
+// SearchMangaFiltered searches with a genre and sort. Without filters it is
+// SearchManga's ranked full-text search; filters need the substring search,
+// since the full-text mode ignores them.
+func (c *Client) SearchMangaFiltered(ctx context.Context, f models.SearchFilters, page, pageSize int) ([]models.Manga, int, error) {
+	if f.Genre == "" && f.Sort == "" && f.Order == "" {
+		return c.SearchManga(ctx, f.Query, page, pageSize)
+	}
+
+	cacheKey := fmt.Sprintf("search:%s|%s|%s|%s:%d:%d", f.Query, f.Genre, f.Sort, f.Order, page, pageSize)
+	if cached, found := c.cache.Get(cacheKey); found {
+		if result, ok := cached.(*MangaListResponse); ok {
+			return result.Data.Data, result.Data.Total, nil
+		}
+	}
+
+	params := url.Values{}
+	if f.Query != "" {
+		params.Set("q", f.Query)
+	}
+	if f.Genre != "" {
+		params.Set("genre", f.Genre)
+	}
+	if f.Sort != "" {
+		params.Set("sort", f.Sort)
+	}
+	if f.Order != "" {
+		params.Set("order", f.Order)
+	}
+	params.Set("page", fmt.Sprintf("%d", page))
+	params.Set("page_size", fmt.Sprintf("%d", pageSize))
+
+	resp, err := c.doRequest(ctx, "GET", "/manga?"+params.Encode(), nil)
+	if err != nil {
+		return nil, 0, err
+	}
+	result, err := parseResponse[MangaListResponse](resp)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	c.cache.SetTagged(cacheKey, result, c.ttl.Default, mangaTags(mangaIDs(result.Data.Data))...)
+	return result.Data.Data, result.Data.Total, nil
+}
+
+// =====================================
+// GUEST HISTORY
+// =====================================
+
+// guestSearchFile is where guests' recent searches survive restarts
+func guestSearchFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".mangahub", "search_history.yaml"), nil
+}
+
+// guestSearchStore loads the guest history file; a missing file is an empty history
+func guestSearchStore() (*viper.Viper, string, error) {
+	path, err := guestSearchFile()
+	if err != nil {
+		return nil, "", err
+	}
+	v := viper.New()
+	v.SetConfigFile(path)
+	v.SetConfigType("yaml")
+	if _, err := os.Stat(path); err == nil {
+		if err := v.ReadInConfig(); err != nil {
+			return nil, "", err
+		}
+	}
+	return v, path, nil
+}
+
+// guestRecentSearches returns the guest history, newest first
+func guestRecentSearches() []models.RecentSearch {
+	v, _, err := guestSearchStore()
+	if err != nil {
+		return nil
+	}
+	entries, _ := v.Get("recent").([]interface{})
+	var recent []models.RecentSearch
+	for _, entry := range entries {
+		fields, ok := entry.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		field := func(name string) string {
+			s, _ := fields[name].(string)
+			return s
+		}
+		recent = append(recent, models.RecentSearch{SearchFilters: models.SearchFilters{
+			Query: field("query"),
+			Genre: field("genre"),
+			Sort:  field("sort"),
+			Order: field("order"),
+		}})
+	}
+	return recent
+}
+
+// recordGuestSearch moves the search to the top of the guest history,
+// dropping an identical older entry and anything past models.MaxRecentSearches
+func recordGuestSearch(f models.SearchFilters) error {
+	recent := guestRecentSearches()
+	recent = slices.DeleteFunc(recent, func(r models.RecentSearch) bool {
+		return r.SearchFilters == f
+	})
+	recent = slices.Insert(recent, 0, models.RecentSearch{SearchFilters: f})
+	if len(recent) > models.MaxRecentSearches {
+		recent = recent[:models.MaxRecentSearches]
+	}
+	return writeGuestSearches(recent)
+}
+
+// writeGuestSearches replaces the guest history file
+func writeGuestSearches(recent []models.RecentSearch) error {
+	v, path, err := guestSearchStore()
+	if err != nil {
+		return err
+	}
+	entries := make([]map[string]string, 0, len(recent))
+	for _, r := range recent {
+		entries = append(entries, map[string]string{
+			"query": r.Query,
+			"genre": r.Genre,
+			"sort":  r.Sort,
+			"order": r.Order,
+		})
+	}
+	v.Set("recent", entries)
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return v.WriteConfigAs(path)
+}
@@ -11,6 +11,9 @@ package tui
 import (
 	"context"
 	"fmt"
+	"net"
+	"net/url"
+	"os"
 	"strings"
 	"time"
 
@@ -46,6 +49,9 @@ const (
 	ViewAuth
 	ViewHelp
 	ViewChat
+	ViewReader
+	ViewLists
+	ViewRooms
 )
 
 // =====================================
@@ -66,9 +72,24 @@ type ErrorMsg struct {
 	Error error
 }
 
+// PreferencesLoadedMsg carries the user's saved preferences after login.
+// On Err the preferences are the defaults and only startup behavior uses them.
+type PreferencesLoadedMsg struct {
+	Preferences models.UserPreferences
+	Startup     bool // login restored by the startup auth check
+	Err         error
+}
+
+// DataExportedMsg reports the result of a data export
+type DataExportedMsg struct {
+	Path  string
+	Error error
+}
+
 // UserLoggedInMsg signals successful login
 type UserLoggedInMsg struct {
-	User *models.User
+	User    *models.User
+	Startup bool // restored from a saved token when the app started
 }
 
 // MangaSelectedMsg signals a manga was selected
@@ -122,21 +143,36 @@ type Model struct {
 	libraryModel   views.LibraryModel
 	browseModel    views.BrowseModel
 	detailModel    views.DetailModel
+	readerModel    views.ReaderModel
 	activityModel  views.ActivityModel
+	profileModel   views.ProfileModel
 	authModel      views.AuthModel
 	helpModel      views.HelpModel
+	settingsModel  views.SettingsModel
+	listsModel     views.ListsModel
+	roomsModel     views.RoomsModel
+	statsModel     views.StatsModel
 
 	// Command palette
 	paletteModel views.PaletteModel
 
+	// First-run onboarding, shown over the app while it loads
+	onboarding     views.OnboardingModel
+	showOnboarding bool
+	// onboardingChoices are saved to the account's preferences at the next login
+	onboardingChoices *views.OnboardingDoneMsg
+
 	// Chat view
 	chatModel views.ChatModel
 
-	// Rating modal and comments view
+	// Rating modal, comments and reviews views
 	ratingModal  views.RatingModal
 	commentsView views.CommentsView
+	reviewsView  views.ReviewsView
 	showRating   bool
 	showComments bool
+	showReviews  bool
+	showSpoilers bool // user preference: show spoiler reviews and comments
 
 	// WebSocket client for real-time chat
 	wsClient *network.WSClient
@@ -145,7 +181,9 @@ type Model struct {
 	udpListener *network.UDPListener
 
 	// Notification state
-	unreadChatCount int
+	unreadChatCount int  // unread messages across rooms, as counted by the server
+	readPending     bool // a chat read receipt is scheduled
+	unreadPolling   bool // the unread count poll is running
 	toast           *ToastModel
 
 	// Input mode tracking
@@ -172,14 +210,20 @@ func New() Model {
 	s.Spinner = spinner.Dot
 	s.Style = styles.DefaultTheme.Spinner
 
-	return Model{
+	// Logging out or losing the session closes the chat socket
+	client := api.GetClient()
+	wsClient := network.NewWSClient()
+	client.OnTokenCleared(wsClient.Close)
+	local, _ := api.LoadLocalSettings()
+
+	m := Model{
 		currentView:    ViewDashboard,
 		previousView:   ViewDashboard,
 		keys:           DefaultKeyMap(),
 		theme:          styles.DefaultTheme,
 		spinner:        s,
-		client:         api.GetClient(),
-		authenticated:  api.GetClient().IsAuthenticated(),
+		client:         client,
+		authenticated:  client.IsAuthenticated(),
 		dashboardModel: views.NewDashboard(),
 		searchModel:    views.NewSearch(),
 		libraryModel:   views.NewLibrary(),
@@ -187,12 +231,27 @@ func New() Model {
 		activityModel:  views.NewActivity(),
 		authModel:      views.NewAuth(),
 		helpModel:      views.NewHelp(),
+		settingsModel:  views.NewSettings(),
+		listsModel:     views.NewLists(),
+		roomsModel:     views.NewRooms(),
+		statsModel:     views.NewStats(),
 		paletteModel:   views.NewPalette(),
 		chatModel:      views.NewChatModel(),
-		wsClient:       network.NewWSClient(),
+		wsClient:       wsClient,
 		udpListener:    network.NewUDPListener(),
 		toast:          NewToast(),
 	}
+	m.settingsModel.SetKeyBindings(shortcutList(m.keys))
+	if client.IsFirstRun() {
+		m.onboarding = views.NewOnboarding()
+		m.showOnboarding = true
+	}
+	// A guest keeps the theme picked during onboarding until the first login saves it to the account
+	if (local.Theme != "" || local.DefaultView != "") && !m.authenticated {
+		m.applyTheme(local.Theme)
+		m.onboardingChoices = &views.OnboardingDoneMsg{Theme: local.Theme, DefaultView: local.DefaultView}
+	}
+	return m
 }
 
 // =====================================
@@ -218,13 +277,228 @@ func (m Model) checkAuth() tea.Msg {
 			m.client.ClearToken()
 			return ViewChangeMsg{View: ViewAuth}
 		}
-		return UserLoggedInMsg{User: user}
+		return UserLoggedInMsg{User: user, Startup: true}
 	}
 	return ViewChangeMsg{View: ViewDashboard}
 }
 
-// Update handles messages
+// loadPreferences fetches the saved preferences (theme, spoilers, startup)
+func (m Model) loadPreferences(startup bool) tea.Cmd {
+	return func() tea.Msg {
+		prefs, err := m.client.GetPreferences(context.Background())
+		if err == nil && prefs == nil {
+			err = fmt.Errorf("no preferences returned")
+		}
+		if err != nil {
+			return PreferencesLoadedMsg{Preferences: models.DefaultUserPreferences(), Startup: startup, Err: err}
+		}
+		return PreferencesLoadedMsg{Preferences: *prefs, Startup: startup}
+	}
+}
+
+// afterLogin loads the user's preferences, first saving the choices made
+// during onboarding so they follow the account
+func (m *Model) afterLogin(startup bool) tea.Cmd {
+	load := m.loadPreferences(startup)
+	choices := m.onboardingChoices
+	if choices == nil {
+		return load
+	}
+	m.onboardingChoices = nil
+	return func() tea.Msg {
+		var req models.UpdatePreferencesRequest
+		if choices.Theme != "" {
+			req.Theme = &choices.Theme
+		}
+		if choices.DefaultView != "" {
+			req.DefaultView = &choices.DefaultView
+		}
+		// Not critical: on failure the account keeps its saved preferences.
+		// Once saved, the local copy is dropped so later logins don't reapply it.
+		if _, err := m.client.UpdatePreferences(context.Background(), req); err == nil {
+			api.SaveLocalSettings(api.LocalSettings{Onboarded: true})
+		}
+		return load()
+	}
+}
+
+// Read receipts: while chat is open the read position is sent at most once per
+// chatReadInterval (and once more on leaving), and the unread badge is refreshed
+// from the server every unreadPollInterval
+const (
+	chatReadInterval   = 2 * time.Second
+	unreadPollInterval = time.Minute
+)
+
+// chatReadTickMsg sends the pending chat read receipt
+type chatReadTickMsg struct{}
+
+// unreadPollMsg refreshes the unread badge
+type unreadPollMsg struct{}
+
+// UnreadCountsMsg carries the server's unread message count
+type UnreadCountsMsg struct {
+	Total int
+	Err   error
+}
+
+// scheduleChatRead queues a read receipt for the open chat room; further calls
+// before it is sent are folded into it
+func (m *Model) scheduleChatRead() tea.Cmd {
+	if m.readPending {
+		return nil
+	}
+	m.readPending = true
+	return tea.Tick(chatReadInterval, func(time.Time) tea.Msg { return chatReadTickMsg{} })
+}
+
+// markChatRead sends the read position of the open chat room, then refreshes
+// the unread count
+func (m Model) markChatRead() tea.Cmd {
+	roomID, lastID := m.chatModel.RoomID(), m.chatModel.LastMessageID()
+	if roomID == "" || !m.authenticated {
+		return nil
+	}
+	return func() tea.Msg {
+		ctx := context.Background()
+		if err := m.client.MarkRoomRead(ctx, roomID, lastID); err != nil {
+			return UnreadCountsMsg{Err: err}
+		}
+		return m.fetchUnread()
+	}
+}
+
+// fetchUnread asks the server for the unread message count
+func (m Model) fetchUnread() tea.Msg {
+	unread, err := m.client.GetUnread(context.Background())
+	if err != nil {
+		return UnreadCountsMsg{Err: err}
+	}
+	return UnreadCountsMsg{Total: unread.Total}
+}
+
+// pollUnread schedules the next unread count refresh
+func pollUnread() tea.Cmd {
+	return tea.Tick(unreadPollInterval, func(time.Time) tea.Msg { return unreadPollMsg{} })
+}
+
+// onboardingSavedMsg reports whether the first-run flag was written
+type onboardingSavedMsg struct {
+	Err error
+}
+
+// finishOnboarding marks onboarding done on this machine and remembers the choices
+func finishOnboarding(choices views.OnboardingDoneMsg) tea.Cmd {
+	return func() tea.Msg {
+		return onboardingSavedMsg{Err: api.SaveLocalSettings(api.LocalSettings{
+			Onboarded:   true,
+			Theme:       choices.Theme,
+			DefaultView: choices.DefaultView,
+		})}
+	}
+}
+
+// homeViewCommands maps a default_view preference to the palette command
+// that opens it, so the home view goes through the same auth gate
+var homeViewCommands = map[string]string{
+	models.HomeViewDashboard: "goto_dashboard",
+	models.HomeViewSearch:    "goto_search",
+	models.HomeViewBrowse:    "goto_browse",
+	models.HomeViewLibrary:   "goto_library",
+	models.HomeViewActivity:  "goto_activity",
+	models.HomeViewLists:     "goto_lists",
+	models.HomeViewStats:     "goto_stats",
+	models.HomeViewChat:      "goto_chat",
+}
+
+// openHomeView opens the preferred view on startup. Unknown or removed views
+// stay on the dashboard, as does a user who already navigated elsewhere or
+// logged out before the preferences arrived.
+func (m Model) openHomeView(name string) (Model, tea.Cmd) {
+	commandID, ok := homeViewCommands[name]
+	if !ok || name == models.HomeViewDashboard || m.currentView != ViewDashboard || !m.authenticated {
+		return m, nil
+	}
+	next, cmd := m.handleCommand(commandID)
+	return next.(Model), cmd
+}
+
+// udpServerPort is the UDP notification server's port (configs: udp.port)
+const udpServerPort = "9091"
+
+// startNotifications registers with the UDP notification server as userID.
+// The server runs next to the API server, so its host is taken from the API URL.
+func (m Model) startNotifications(userID string) tea.Cmd {
+	host := "localhost"
+	if u, err := url.Parse(m.client.GetBaseURL()); err == nil && u.Hostname() != "" {
+		host = u.Hostname()
+	}
+	return m.udpListener.Start(net.JoinHostPort(host, udpServerPort), userID)
+}
+
+// applyTheme switches the active theme on the app and every held view
+func (m *Model) applyTheme(name string) {
+	t := styles.LoadTheme(name)
+	styles.ApplyTheme(t)
+	m.theme = t
+	m.spinner.Style = t.Spinner
+	m.dashboardModel.SetTheme(t)
+	m.searchModel.SetTheme(t)
+	m.libraryModel.SetTheme(t)
+	m.browseModel.SetTheme(t)
+	m.detailModel.SetTheme(t)
+	m.readerModel.SetTheme(t)
+	m.activityModel.SetTheme(t)
+	m.profileModel.SetTheme(t)
+	m.authModel.SetTheme(t)
+	m.helpModel.SetTheme(t)
+	m.settingsModel.SetTheme(t)
+	m.listsModel.SetTheme(t)
+	m.roomsModel.SetTheme(t)
+	m.statsModel.SetTheme(t)
+	m.paletteModel.SetTheme(t)
+	m.onboarding.SetTheme(t)
+	m.ratingModal.SetTheme(t)
+	m.commentsView.SetTheme(t)
+	m.reviewsView.SetTheme(t)
+}
+
+// logout revokes the refresh token server-side and clears local tokens
+func (m Model) logout() tea.Msg {
+	_ = m.client.Logout(context.Background())
+	return nil
+}
+
+// exportData downloads the user's data and saves it in the working directory
+func (m Model) exportData(format string) tea.Cmd {
+	return func() tea.Msg {
+		filename, data, err := m.client.ExportData(context.Background(), format)
+		if err != nil {
+			return DataExportedMsg{Error: err}
+		}
+		if err := os.WriteFile(filename, data, 0o644); err != nil {
+			return DataExportedMsg{Error: err}
+		}
+		return DataExportedMsg{Path: filename}
+	}
+}
+
+// Update handles messages. Leaving the chat view closes its WebSocket so
+// nothing keeps reading or reconnecting in the background.
 func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	wasChat := m.currentView == ViewChat
+	next, cmd := m.update(msg)
+	if nm, ok := next.(Model); ok && wasChat && nm.currentView != ViewChat {
+		nm.wsClient.Close()
+		nm.chatModel.SetStatus(views.StatusDisconnected)
+		// Whatever was on screen has been read
+		return nm, tea.Batch(cmd, nm.markChatRead())
+	}
+	return next, cmd
+}
+
+// update routes a message to the current view and handles app-wide messages
+func (m Model) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
 	switch msg := msg.(type) {
@@ -236,6 +510,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.dashboardModel.SetHeight(msg.Height - 6)
 		// Update chat dimensions
 		m.chatModel, _ = m.chatModel.Update(msg)
+		m.settingsModel, _ = m.settingsModel.Update(msg)
+		m.listsModel, _ = m.listsModel.Update(msg)
+		m.roomsModel, _ = m.roomsModel.Update(msg)
+		m.statsModel, _ = m.statsModel.Update(msg)
 		m.searchModel.SetWidth(msg.Width - 4)
 		m.searchModel.SetHeight(msg.Height - 6)
 		m.libraryModel.SetWidth(msg.Width - 4)
@@ -244,12 +522,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.browseModel.SetHeight(msg.Height - 6)
 		m.activityModel.SetWidth(msg.Width - 4)
 		m.activityModel.SetHeight(msg.Height - 6)
+		m.profileModel.SetWidth(msg.Width - 4)
+		m.profileModel.SetHeight(msg.Height - 6)
 		m.authModel.SetWidth(msg.Width - 4)
 		m.authModel.SetHeight(msg.Height - 6)
 		m.helpModel.SetWidth(msg.Width - 4)
 		m.helpModel.SetHeight(msg.Height - 6)
+		m.readerModel.SetWidth(msg.Width - 4)
+		m.readerModel.SetHeight(msg.Height - 6)
 		m.paletteModel.SetWidth(msg.Width)
 		m.paletteModel.SetHeight(msg.Height)
+		m.onboarding, _ = m.onboarding.Update(msg)
 		// Update modal and overlay dimensions
 		if m.showRating {
 			m.ratingModal, _ = m.ratingModal.Update(msg)
@@ -257,9 +540,20 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.showComments {
 			m.commentsView, _ = m.commentsView.Update(msg)
 		}
+		if m.showReviews {
+			m.reviewsView, _ = m.reviewsView.Update(msg)
+		}
 		return m, nil
 
 	case tea.KeyMsg:
+		// Onboarding takes the keys until it is finished or dismissed
+		if m.showOnboarding && msg.String() != "ctrl+c" {
+			var cmd tea.Cmd
+			m.onboarding, cmd = m.onboarding.Update(msg)
+			m.showOnboarding = m.onboarding.IsActive()
+			return m, cmd
+		}
+
 		// Check if rating modal is open - handle it first
 		if m.showRating {
 			var cmd tea.Cmd
@@ -271,6 +565,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		if m.showComments {
 			var cmd tea.Cmd
 			m.commentsView, cmd = m.commentsView.Update(msg)
+			m.showComments = m.commentsView.IsActive()
+			return m, cmd
+		}
+
+		// Reviews list overlay
+		if m.showReviews {
+			var cmd tea.Cmd
+			m.reviewsView, cmd = m.reviewsView.Update(msg)
+			m.showReviews = m.reviewsView.IsActive()
 			return m, cmd
 		}
 
@@ -302,6 +605,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, nil
 
 		case "esc":
+			// A keybinding waiting for its key gives up instead of leaving settings
+			if m.currentView == ViewSettings && m.settingsModel.IsCapturingKey() {
+				m.settingsModel.CancelKeyCapture()
+				return m, nil
+			}
 			// Check if rating modal or comments view is open
 			if m.showRating {
 				m.showRating = false
@@ -379,15 +687,35 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return m, nil
 
+		case key.Matches(msg, m.keys.Stats):
+			if !m.authenticated {
+				m.previousView = m.currentView
+				m.currentView = ViewAuth
+				return m, m.authModel.Init()
+			}
+			if m.currentView != ViewStats {
+				m.previousView = m.currentView
+				m.currentView = ViewStats
+				return m, m.statsModel.Init()
+			}
+			return m, nil
+
+		case key.Matches(msg, m.keys.Settings):
+			if m.currentView != ViewSettings {
+				m.previousView = m.currentView
+				m.currentView = ViewSettings
+				return m, m.settingsModel.Init()
+			}
+			return m, nil
+
 		case key.Matches(msg, m.keys.Login):
 			if m.authenticated {
 				// Already logged in, logout instead
-				m.client.ClearToken()
 				m.authenticated = false
 				m.user = nil
+				m.unreadChatCount = 0
 				// Stop UDP listener on logout
-				m.udpListener.Stop()
-				return m, nil
+				return m, tea.Batch(m.udpListener.Stop(), m.logout)
 			}
 			if m.currentView != ViewAuth {
 				m.previousView = m.currentView
@@ -412,8 +740,10 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				}
 				wsURL := strings.Replace(m.client.GetBaseURL(), "http://", "ws://", 1)
 				wsURL = strings.Replace(wsURL, "https://", "wss://", 1)
+				historyCmd := m.chatModel.LoadHistory()
 				return m, tea.Batch(
 					m.chatModel.Init(),
+					historyCmd,
 					m.wsClient.Connect(wsURL, m.client.GetToken(), m.chatModel.RoomID()),
 				)
 			}
@@ -424,6 +754,15 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m.updateCurrentView(msg)
 		}
 
+	case views.ShowProfileMsg:
+		m.profileModel = views.NewProfile(msg.Username)
+		m.profileModel.SetTheme(m.theme)
+		m.profileModel.SetWidth(m.width - 4)
+		m.profileModel.SetHeight(m.height - 6)
+		m.previousView = m.currentView
+		m.currentView = ViewProfile
+		return m, m.profileModel.Init()
+
 	case views.CommandSelectedMsg:
 		// Handle command from palette
 		return m.handleCommand(msg.CommandID)
@@ -447,8 +786,65 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.authenticated = true
 		// Update chat user info
 		m.chatModel.SetUser(msg.User.ID, msg.User.Username)
-		// Start UDP listener for real-time notifications
-		return m, m.udpListener.Start("9091")
+		// UDP notifications start once preferences say whether to connect
+		cmds := []tea.Cmd{m.afterLogin(msg.Startup), m.fetchUnread}
+		if !m.unreadPolling {
+			m.unreadPolling = true
+			cmds = append(cmds, pollUnread())
+		}
+		return m, tea.Batch(cmds...)
+
+	case unreadPollMsg:
+		// The poll ends on logout and restarts with the next login
+		if !m.authenticated {
+			m.unreadPolling = false
+			return m, nil
+		}
+		return m, tea.Batch(m.fetchUnread, pollUnread())
+
+	case chatReadTickMsg:
+		m.readPending = false
+		// Leaving chat already sent the receipt
+		if m.currentView != ViewChat {
+			return m, nil
+		}
+		return m, m.markChatRead()
+
+	case UnreadCountsMsg:
+		// Keep the last known count when the server can't be reached
+		if msg.Err == nil && m.authenticated {
+			m.unreadChatCount = msg.Total
+		}
+		return m, nil
+
+	case views.OnboardingDoneMsg:
+		m.showOnboarding = false
+		saveCmd := finishOnboarding(msg)
+		if msg.Skipped {
+			// Undo any theme previewed before skipping
+			m.applyTheme(models.DefaultUserPreferences().Theme)
+			return m, saveCmd
+		}
+		m.onboardingChoices = &msg
+		if msg.Next == views.OnboardingGuest {
+			return m, saveCmd
+		}
+		m.authModel = views.NewAuth()
+		m.authModel.SetTheme(m.theme)
+		m.authModel.SetWidth(m.width - 4)
+		m.authModel.SetHeight(m.height - 6)
+		if msg.Next == views.OnboardingRegister {
+			m.authModel.SetMode(views.ModeSignup)
+		}
+		m.previousView = ViewDashboard
+		m.currentView = ViewAuth
+		return m, tea.Batch(saveCmd, m.authModel.Init())
+
+	case onboardingSavedMsg:
+		if msg.Err != nil {
+			m.toast.Show(fmt.Sprintf("Could not save first-run settings: %v", msg.Err), 5*time.Second)
+		}
+		return m, nil
 
 	case ErrorMsg:
 		m.lastError = msg.Error
@@ -468,12 +864,196 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.showRating = true
 		return m, m.ratingModal.Init()
 
+	case views.ShowRoomsMsg:
+		return m.handleCommand("goto_rooms")
+
 	case views.ShowCommentsMsg:
 		// Show comments view
 		m.commentsView = views.NewCommentsView(msg.MangaID, msg.MangaTitle)
+		m.commentsView.SetShowSpoilers(m.showSpoilers)
+		if m.user != nil {
+			m.commentsView.SetUser(m.user.ID, m.user.Role)
+		}
+		if msg.Chapter > 0 {
+			m.commentsView.SetChapter(msg.Chapter)
+		}
 		m.showComments = true
 		return m, m.commentsView.Init()
 
+	case views.ShowReviewsMsg:
+		// Show full reviews; spoilers follow the user's preference
+		m.reviewsView = views.NewReviewsView(msg.MangaID, msg.MangaTitle, m.showSpoilers)
+		m.reviewsView.SetTheme(m.theme)
+		m.reviewsView, _ = m.reviewsView.Update(tea.WindowSizeMsg{Width: m.width, Height: m.height})
+		m.showReviews = true
+		return m, m.reviewsView.Init()
+
+	case views.ShowReaderMsg:
+		// Open chapter reader for the selected manga
+		if !m.authenticated {
+			m.previousView = m.currentView
+			m.currentView = ViewAuth
+			return m, m.authModel.Init()
+		}
+		m.readerModel = views.NewReader(msg.MangaID, msg.MangaTitle, msg.CurrentChapter, msg.TotalChapters, msg.Status)
+		m.readerModel.SetWidth(m.width - 4)
+		m.readerModel.SetHeight(m.height - 6)
+		m.previousView = m.currentView
+		m.currentView = ViewReader
+		return m, m.readerModel.Init()
+
+	case views.PasswordChangedMsg:
+		// Handled here so the result is reported even after leaving settings
+		m.settingsModel, _ = m.settingsModel.Update(msg)
+		if msg.Error != nil {
+			m.toast.Show(fmt.Sprintf("Password change failed: %v", msg.Error), 5*time.Second)
+			return m, nil
+		}
+		m.toast.Show("Password changed", 3*time.Second)
+		return m, nil
+
+	case views.ThemeChangedMsg:
+		m.applyTheme(msg.Name)
+		return m, nil
+
+	case views.KeyBindingsChangedMsg:
+		m.keys = KeyMapFrom(msg.Overrides)
+		m.settingsModel.SetKeyBindings(shortcutList(m.keys))
+		return m, nil
+
+	case PreferencesLoadedMsg:
+		prefs := msg.Preferences
+		if msg.Err == nil {
+			// Keep the current settings otherwise; preferences are not critical
+			m.applyTheme(prefs.Theme)
+			m.showSpoilers = prefs.ShowSpoilers
+			m.settingsModel.SetShowSpoilers(prefs.ShowSpoilers)
+			m.settingsModel.SetStartup(prefs.DefaultView, prefs.AutoConnectNotifications)
+			m.settingsModel.SetEmailDigest(prefs.EmailNotifications)
+			m.keys = KeyMapFrom(prefs.Keybindings)
+			m.settingsModel.SetKeyBindings(shortcutList(m.keys))
+		}
+		var notifyCmd tea.Cmd
+		if prefs.AutoConnectNotifications && m.user != nil {
+			notifyCmd = m.startNotifications(m.user.ID)
+		}
+		if !msg.Startup {
+			return m, notifyCmd
+		}
+		var homeCmd tea.Cmd
+		m, homeCmd = m.openHomeView(prefs.DefaultView)
+		return m, tea.Batch(notifyCmd, homeCmd)
+
+	case views.SpoilersChangedMsg:
+		m.showSpoilers = msg.Show
+		if m.showReviews {
+			m.reviewsView.SetShowSpoilers(msg.Show)
+		}
+		if m.showComments {
+			m.commentsView.SetShowSpoilers(msg.Show)
+		}
+		return m, nil
+
+	case views.SpoilersSavedMsg:
+		if msg.Error != nil {
+			m.toast.Show(fmt.Sprintf("Spoiler setting not saved: %v", msg.Error), 5*time.Second)
+		}
+		return m, nil
+
+	case views.AccountDeletedMsg:
+		m.settingsModel, _ = m.settingsModel.Update(msg)
+		if msg.Error != nil {
+			m.toast.Show(fmt.Sprintf("Account not deleted: %v", msg.Error), 5*time.Second)
+			return m, nil
+		}
+		// The client already dropped its tokens; the server has no session left
+		m.authenticated = false
+		m.user = nil
+		m.unreadChatCount = 0
+		m.previousView = ViewDashboard
+		m.currentView = ViewDashboard
+		m.toast.Show("Account deleted", 5*time.Second)
+		return m, tea.Batch(m.udpListener.Stop(), m.dashboardModel.Init())
+
+	case views.ThemeSavedMsg:
+		if msg.Error != nil {
+			m.toast.Show(fmt.Sprintf("Theme not saved: %v", msg.Error), 5*time.Second)
+		}
+		return m, nil
+
+	case views.PreferenceSavedMsg:
+		if msg.Error != nil {
+			m.toast.Show(fmt.Sprintf("%s not saved: %v", msg.Label, msg.Error), 5*time.Second)
+		}
+		return m, nil
+
+	case views.LibraryImportedMsg:
+		// Handled here so the result is shown even after leaving settings
+		m.settingsModel, _ = m.settingsModel.Update(msg)
+		if msg.Error != nil {
+			m.toast.Show(fmt.Sprintf("Import failed: %v", msg.Error), 5*time.Second)
+			return m, nil
+		}
+		m.toast.Show("Imported "+views.ImportSummary(msg.Result.Stats), 5*time.Second)
+		return m, nil
+
+	case DataExportedMsg:
+		if msg.Error != nil {
+			m.toast.Show(fmt.Sprintf("Export failed: %v", msg.Error), 5*time.Second)
+			return m, nil
+		}
+		m.toast.Show("Exported data to "+msg.Path, 4*time.Second)
+		return m, nil
+
+	case views.RandomMangaMsg:
+		m.browseModel, _ = m.browseModel.Update(msg)
+		if msg.Error != nil {
+			m.toast.Show(fmt.Sprintf("No random pick: %v", msg.Error), 3*time.Second)
+			return m, nil
+		}
+		m.selectedMangaID = msg.Manga.ID
+		m.detailModel = views.NewDetail(msg.Manga.ID)
+		m.previousView = m.currentView
+		m.currentView = ViewDetail
+		return m, m.detailModel.Init()
+
+	case views.LibraryBulkStatusMsg:
+		// Summarize before the library reloads, while the titles are known
+		m.toast.Show(m.libraryModel.BulkStatusSummary(msg), 5*time.Second)
+		var cmd tea.Cmd
+		m.libraryModel, cmd = m.libraryModel.Update(msg)
+		return m, cmd
+
+	case views.EditResultMsg:
+		// Either view may have made the edit; it settles its own and the
+		// other picks up the confirmed change
+		var libraryCmd, detailCmd, dashboardCmd tea.Cmd
+		m.libraryModel, libraryCmd = m.libraryModel.Update(msg)
+		m.detailModel, detailCmd = m.detailModel.Update(msg)
+		if msg.Error == nil && msg.Action != views.EditRate {
+			// Continue Reading lists library entries
+			dashboardCmd = m.dashboardModel.Reload()
+		}
+		m.toast.Show(editResultToast(msg))
+		return m, tea.Batch(libraryCmd, detailCmd, dashboardCmd)
+
+	case views.LibraryDataLoadedMsg:
+		// Also the answer to a reload another view's edit started
+		var cmd tea.Cmd
+		m.libraryModel, cmd = m.libraryModel.Update(msg)
+		return m, cmd
+
+	case views.DashboardDataLoadedMsg:
+		var cmd tea.Cmd
+		m.dashboardModel, cmd = m.dashboardModel.Update(msg)
+		return m, cmd
+
+	case views.ShowLoginMsg:
+		m.toast.Show(msg.Reason, 3*time.Second)
+		m.previousView = m.currentView
+		m.currentView = ViewAuth
+		return m, m.authModel.Init()
+
 	case views.RatingSubmittedMsg:
 		// Rating was submitted successfully
 		m.showRating = false
@@ -486,6 +1066,23 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.toast.Show(fmt.Sprintf("Failed to submit rating: %v", msg.Error), 5*time.Second)
 		return m, nil
 
+	case views.ReviewsLoadedMsg, views.ReviewsErrorMsg:
+		if !m.showReviews {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.reviewsView, cmd = m.reviewsView.Update(msg)
+		return m, cmd
+
+	case views.CommentsLoadedMsg, views.CommentPostedMsg, views.CommentLikedMsg, views.CommentDeletedMsg, views.CommentsErrorMsg:
+		// Comment results belong to the overlay, not the view underneath
+		if !m.showComments {
+			return m, nil
+		}
+		var cmd tea.Cmd
+		m.commentsView, cmd = m.commentsView.Update(msg)
+		return m, cmd
+
 	case network.JoinRoomMsg:
 		// User requested to join a chat room
 		if !m.authenticated {
@@ -500,20 +1097,22 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Connect WebSocket
 		wsURL := strings.Replace(m.client.GetBaseURL(), "http://", "ws://", 1)
 		wsURL = strings.Replace(wsURL, "https://", "wss://", 1)
+		historyCmd := m.chatModel.LoadHistory()
 		return m, tea.Batch(
 			m.chatModel.Init(),
+			historyCmd,
 			m.wsClient.Connect(wsURL, m.client.GetToken(), msg.RoomID),
 		)
 
 	case network.WSConnectedMsg:
 		// WebSocket connected successfully
 		m.chatModel.SetStatus(views.StatusConnected)
-		// Mark unread as read when viewing chat
-		if m.currentView == ViewChat {
-			m.unreadChatCount = 0
+		// Start listening for messages; after a reconnect, ask for what was missed
+		listen := m.wsClient.ListenForMessages()
+		if lastID := m.chatModel.LastMessageID(); lastID != "" && m.chatModel.RoomID() == msg.RoomID {
+			return m, tea.Batch(listen, m.wsClient.Resume(msg.RoomID, lastID))
 		}
-		// Start listening for messages
-		return m, m.wsClient.ListenForMessages()
+		return m, listen
 
 	case network.WSDisconnectedMsg:
 		// WebSocket disconnected
@@ -528,6 +1127,17 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.chatModel.SetStatus(views.StatusReconnecting)
 		return m, m.wsClient.Reconnect()
 
+	case network.WSReconnectFailedMsg:
+		// Reconnect gave up; the user can retry by reopening chat
+		m.lastError = fmt.Errorf("chat connection lost after %d attempts", msg.Attempts)
+		m.chatModel.SetStatus(views.StatusDisconnected)
+		return m, nil
+
+	case network.WSClosedMsg:
+		// Closed on purpose (left chat, logged out); nothing to reconnect
+		m.chatModel.SetStatus(views.StatusDisconnected)
+		return m, nil
+
 	case network.WSErrorMsg:
 		m.lastError = msg.Err
 		m.chatModel.SetStatus(views.StatusDisconnected)
@@ -538,28 +1148,44 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case network.ChatMessageMsg:
 		// Incoming chat message from WebSocket
-		chatMsg := views.ChatMessageReceivedMsg{
-			ID:        msg.ID,
-			RoomID:    msg.RoomID,
-			UserID:    msg.UserID,
-			Username:  msg.Username,
-			Content:   msg.Content,
-			Type:      msg.Type,
-			Timestamp: msg.Timestamp,
+		chatMsg := toChatReceived(msg)
+		for _, replayed := range msg.Messages {
+			chatMsg.Replayed = append(chatMsg.Replayed, toChatReceived(replayed))
 		}
 		// Update chat model
-		m.chatModel, _ = m.chatModel.Update(chatMsg)
-		// If not on chat view, increment unread count
-		if m.currentView != ViewChat {
-			m.unreadChatCount++
+		var chatCmd tea.Cmd
+		m.chatModel, chatCmd = m.chatModel.Update(chatMsg)
+		// New and replayed messages on screen get a read receipt; the unread
+		// count itself comes from the server (typing/presence/error frames don't count)
+		var readCmd tea.Cmd
+		switch msg.Type {
+		case "typing", "presence", "error", "resync":
+		default:
+			if m.currentView == ViewChat {
+				readCmd = m.scheduleChatRead()
+			}
 		}
 		// Continue listening for messages
-		return m, m.wsClient.ListenForMessages()
+		return m, tea.Batch(chatCmd, readCmd, m.wsClient.ListenForMessages())
+
+	case views.ChatHistoryLoadedMsg:
+		// Route to the chat model even if the user has left the chat view
+		var chatCmd tea.Cmd
+		m.chatModel, chatCmd = m.chatModel.Update(msg)
+		// The latest page is what the user sees on opening the room
+		if msg.BeforeID == "" && msg.Err == nil && m.currentView == ViewChat {
+			return m, tea.Batch(chatCmd, m.scheduleChatRead())
+		}
+		return m, chatCmd
 
 	case views.SendChatMsg:
 		// User wants to send a chat message
 		return m, m.wsClient.SendMessage(msg.RoomID, msg.Content)
 
+	case views.ChatTypingMsg:
+		// User is typing - notify the room
+		return m, m.wsClient.SendTyping(msg.RoomID)
+
 	// =====================================
 	// UDP NOTIFICATION MESSAGES
 	// =====================================
@@ -578,6 +1204,11 @@ func (m Model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.lastError = msg.Err
 		return m, nil
 
+	case network.UDPRejectedMsg:
+		// Forged, unsigned or replayed packet - report it and keep listening
+		m.lastError = msg.Err
+		return m, m.udpListener.WaitForPacket()
+
 	case network.UDPNotificationMsg:
 		// Incoming UDP notification - show as toast
 		notification := network.FormatNotification(msg)
@@ -628,7 +1259,8 @@ func (m Model) updateCurrentView(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.detailModel = views.NewDetail(selected.ID)
 				m.previousView = m.currentView
 				m.currentView = ViewDetail
-				return m, m.detailModel.Init()
+				// cmd records the search in the history
+				return m, tea.Batch(cmd, m.detailModel.Init())
 			}
 		}
 	case ViewLibrary:
@@ -657,8 +1289,22 @@ func (m Model) updateCurrentView(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 	case ViewDetail:
 		m.detailModel, cmd = m.detailModel.Update(msg)
+	case ViewReader:
+		m.readerModel, cmd = m.readerModel.Update(msg)
 	case ViewActivity:
 		m.activityModel, cmd = m.activityModel.Update(msg)
+		// Check for manga selection
+		if selected := m.activityModel.GetSelectedActivity(); selected != nil && selected.MangaID != "" {
+			if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "m" {
+				m.selectedMangaID = selected.MangaID
+				m.detailModel = views.NewDetail(selected.MangaID)
+				m.previousView = m.currentView
+				m.currentView = ViewDetail
+				return m, m.detailModel.Init()
+			}
+		}
+	case ViewProfile:
+		m.profileModel, cmd = m.profileModel.Update(msg)
 	case ViewAuth:
 		m.authModel, cmd = m.authModel.Update(msg)
 		// Check for successful login
@@ -673,15 +1319,31 @@ func (m Model) updateCurrentView(msg tea.Msg) (tea.Model, tea.Cmd) {
 				} else {
 					m.currentView = ViewDashboard
 				}
-				return m, m.dashboardModel.Init()
+				return m, tea.Batch(m.dashboardModel.Init(), m.afterLogin(false))
 			}
 		}
 	case ViewHelp:
 		m.helpModel, cmd = m.helpModel.Update(msg)
+	case ViewSettings:
+		m.settingsModel, cmd = m.settingsModel.Update(msg)
+	case ViewStats:
+		m.statsModel, cmd = m.statsModel.Update(msg)
+	case ViewLists:
+		m.listsModel, cmd = m.listsModel.Update(msg)
+		// Check for manga selection in the items pane
+		if mangaID := m.listsModel.GetSelectedMangaID(); mangaID != "" {
+			if keyMsg, ok := msg.(tea.KeyMsg); ok && keyMsg.String() == "enter" {
+				m.selectedMangaID = mangaID
+				m.detailModel = views.NewDetail(mangaID)
+				m.previousView = m.currentView
+				m.currentView = ViewDetail
+				return m, m.detailModel.Init()
+			}
+		}
+	case ViewRooms:
+		m.roomsModel, cmd = m.roomsModel.Update(msg)
 	case ViewChat:
 		m.chatModel, cmd = m.chatModel.Update(msg)
-		// Clear unread count when viewing chat
-		m.unreadChatCount = 0
 	}
 
 	return m, cmd
@@ -715,18 +1377,70 @@ func (m Model) handleCommand(commandID string) (tea.Model, tea.Cmd) {
 		m.previousView = m.currentView
 		m.currentView = ViewActivity
 		return m, m.activityModel.Init()
+	case "goto_stats":
+		if !m.authenticated {
+			m.previousView = m.currentView
+			m.currentView = ViewAuth
+			return m, m.authModel.Init()
+		}
+		m.previousView = m.currentView
+		m.currentView = ViewStats
+		return m, m.statsModel.Init()
+	case "goto_settings":
+		m.previousView = m.currentView
+		m.currentView = ViewSettings
+		return m, m.settingsModel.Init()
+	case "goto_lists":
+		if !m.authenticated {
+			m.previousView = m.currentView
+			m.currentView = ViewAuth
+			return m, m.authModel.Init()
+		}
+		m.previousView = m.currentView
+		m.currentView = ViewLists
+		return m, m.listsModel.Init()
+	case "goto_rooms":
+		m.previousView = m.currentView
+		m.currentView = ViewRooms
+		return m, m.roomsModel.Init()
+	case "goto_reader":
+		// Reads the manga last opened in detail view
+		if cmd := m.detailModel.OpenReader(); cmd != nil {
+			return m, cmd
+		}
+		m.toast.Show("Open a manga from your library first", 3*time.Second)
+		return m, nil
+	case "import_library":
+		if !m.authenticated {
+			m.previousView = m.currentView
+			m.currentView = ViewAuth
+			return m, m.authModel.Init()
+		}
+		if m.currentView != ViewSettings {
+			m.previousView = m.currentView
+			m.currentView = ViewSettings
+		}
+		return m, m.settingsModel.FocusImport()
 	case "login":
 		if m.authenticated {
-			m.client.ClearToken()
 			m.authenticated = false
 			m.user = nil
+			m.unreadChatCount = 0
 			// Stop UDP listener on logout
-			m.udpListener.Stop()
+			return m, tea.Batch(m.udpListener.Stop(), m.logout)
 		} else {
 			m.previousView = m.currentView
 			m.currentView = ViewAuth
 			return m, m.authModel.Init()
 		}
+	case "export_data":
+		if !m.authenticated {
+			m.previousView = m.currentView
+			m.currentView = ViewAuth
+			return m, m.authModel.Init()
+		}
+		m.toast.Show("Exporting data...", 2*time.Second)
+		return m, m.exportData("csv")
 	case "help":
 		m.previousView = m.currentView
 		m.currentView = ViewHelp
@@ -745,12 +1459,15 @@ func (m Model) handleCommand(commandID string) (tea.Model, tea.Cmd) {
 		}
 		wsURL := strings.Replace(m.client.GetBaseURL(), "http://", "ws://", 1)
 		wsURL = strings.Replace(wsURL, "https://", "wss://", 1)
+		historyCmd := m.chatModel.LoadHistory()
 		return m, tea.Batch(
 			m.chatModel.Init(),
+			historyCmd,
 			m.wsClient.Connect(wsURL, m.client.GetToken(), m.chatModel.RoomID()),
 		)
 	case "refresh":
-		// Refresh current view
+		// Refresh current view from the server, not the response cache
+		m.client.InvalidateAll()
 		switch m.currentView {
 		case ViewDashboard:
 			return m, m.dashboardModel.Init()
@@ -776,6 +1493,11 @@ func (m Model) View() string {
 		return "Loading..."
 	}
 
+	// First-run onboarding covers the app until it is finished or dismissed
+	if m.showOnboarding {
+		return m.onboarding.View()
+	}
+
 	// Build main layout
 	content := m.renderCurrentView()
 	footer := m.renderFooter()
@@ -817,6 +1539,11 @@ func (m Model) View() string {
 		return m.commentsView.View()
 	}
 
+	// Overlay reviews list if visible
+	if m.showReviews {
+		return m.reviewsView.View()
+	}
+
 	// Overlay command palette if visible
 	if m.paletteModel.IsVisible() {
 		// Dim the background
@@ -852,14 +1579,26 @@ func (m Model) renderCurrentView() string {
 		content = m.libraryModel.View()
 	case ViewDetail:
 		content = m.detailModel.View()
+	case ViewReader:
+		content = m.readerModel.View()
 	case ViewBrowse:
 		content = m.browseModel.View()
 	case ViewActivity:
 		content = m.activityModel.View()
+	case ViewProfile:
+		content = m.profileModel.View()
 	case ViewAuth:
 		content = m.authModel.View()
 	case ViewHelp:
 		content = m.helpModel.View()
+	case ViewSettings:
+		content = m.settingsModel.View()
+	case ViewStats:
+		content = m.statsModel.View()
+	case ViewLists:
+		content = m.listsModel.View()
+	case ViewRooms:
+		content = m.roomsModel.View()
 	case ViewChat:
 		content = m.chatModel.View()
 	default:
@@ -961,11 +1700,63 @@ func (m Model) isInputFocused() bool {
 		return m.authModel.IsInputFocused()
 	case ViewChat:
 		return m.chatModel.IsInputFocused()
+	case ViewSettings:
+		return m.settingsModel.IsInputFocused()
+	case ViewLists:
+		return m.listsModel.IsInputFocused()
+	case ViewStats:
+		return m.statsModel.IsInputFocused()
+	case ViewBrowse:
+		return m.browseModel.IsInputFocused()
 	default:
 		return false
 	}
 }
 
+// toChatReceived converts a WebSocket message for the chat view
+func toChatReceived(msg network.ChatMessageMsg) views.ChatMessageReceivedMsg {
+	return views.ChatMessageReceivedMsg{
+		ID:        msg.ID,
+		RoomID:    msg.RoomID,
+		UserID:    msg.UserID,
+		Username:  msg.Username,
+		Content:   msg.Content,
+		Type:      msg.Type,
+		Timestamp: msg.Timestamp,
+		Members:   msg.Members,
+	}
+}
+
+// editResultToast words the server's answer to an optimistic edit; the change
+// already shows, so a rejection says it was undone
+func editResultToast(msg views.EditResultMsg) (string, time.Duration) {
+	if msg.Error != nil {
+		action := map[string]string{
+			views.EditAdd:      "add to library",
+			views.EditFavorite: "update favorite",
+			views.EditStatus:   "change status",
+			views.EditChapter:  "update progress",
+			views.EditRate:     "rate",
+		}[msg.Action]
+		return fmt.Sprintf("Couldn't %s, undone: %v", action, msg.Error), 5 * time.Second
+	}
+	switch msg.Action {
+	case views.EditAdd:
+		return "Added to library", 2 * time.Second
+	case views.EditFavorite:
+		if msg.Favorite {
+			return "Added to favorites", 2 * time.Second
+		}
+		return "Removed from favorites", 2 * time.Second
+	case views.EditStatus:
+		return "Moved to " + views.StatusLabels[msg.Status], 2 * time.Second
+	case views.EditChapter:
+		return fmt.Sprintf("Progress: chapter %d", msg.Chapter), 2 * time.Second
+	default:
+		return fmt.Sprintf("Rated %d/10 (press another number to change)", msg.Rating), 3 * time.Second
+	}
+}
+
 // =====================================
 // TOAST NOTIFICATION MODEL
 // =====================================
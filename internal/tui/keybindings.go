@@ -0,0 +1,102 @@
+// Package tui - Custom Key Bindings
+// Phím tắt toàn cục user đổi được trong Settings, lưu trong preferences
+// Chức năng:
+//   - KeyMapFrom: default key map + override của user
+//   - Override không hợp lệ (phím dành riêng, trùng action khác) bị bỏ qua
+//   - shortcutList: danh sách cho keybinding editor trong Settings
+package tui
+
+import (
+	"slices"
+
+	"github.com/charmbracelet/bubbles/key"
+
+	"mangahub/internal/tui/views"
+)
+
+// rebindable lists the global shortcuts users can change, in Settings order.
+// The action names are the keys of the keybindings preference.
+var rebindable = []struct {
+	action  string
+	label   string
+	binding func(*KeyMap) *key.Binding
+}{
+	{"dashboard", "Home", func(k *KeyMap) *key.Binding { return &k.Dashboard }},
+	{"search", "Search", func(k *KeyMap) *key.Binding { return &k.Search }},
+	{"browse", "Browse", func(k *KeyMap) *key.Binding { return &k.Browse }},
+	{"library", "Library", func(k *KeyMap) *key.Binding { return &k.Library }},
+	{"activity", "Activity", func(k *KeyMap) *key.Binding { return &k.Activity }},
+	{"stats", "Stats", func(k *KeyMap) *key.Binding { return &k.Stats }},
+	{"chat", "Chat", func(k *KeyMap) *key.Binding { return &k.Chat }},
+	{"settings", "Settings", func(k *KeyMap) *key.Binding { return &k.Settings }},
+	{"login", "Login / Logout", func(k *KeyMap) *key.Binding { return &k.Login }},
+	{"quit", "Quit", func(k *KeyMap) *key.Binding { return &k.Quit }},
+}
+
+// KeyMapFrom returns the default key map with overrides (action -> key)
+// applied. An override replaces every default key of its action. Overrides
+// for unknown actions, reserved keys or keys another action ends up using
+// are dropped, so a bad saved preference cannot lock the user out.
+func KeyMapFrom(overrides map[string]string) KeyMap {
+	defaults := DefaultKeyMap()
+	active := map[string]string{}
+	for _, r := range rebindable {
+		if k, ok := overrides[r.action]; ok && !views.ReservedKey(k) {
+			active[r.action] = k
+		}
+	}
+
+	// Dropping an override brings its defaults back, which may clash with
+	// another override, so repeat until nothing clashes
+	for changed := true; changed; {
+		changed = false
+		for _, r := range rebindable {
+			if k, ok := active[r.action]; ok && keyOwner(&defaults, active, k, r.action) != "" {
+				delete(active, r.action)
+				changed = true
+			}
+		}
+	}
+
+	keys := defaults
+	for _, r := range rebindable {
+		if k, ok := active[r.action]; ok {
+			b := r.binding(&keys)
+			*b = key.NewBinding(key.WithKeys(k), key.WithHelp(k, b.Help().Desc))
+		}
+	}
+	return keys
+}
+
+// keyOwner returns the rebindable action other than except that uses k,
+// given the active overrides on top of defaults, or ""
+func keyOwner(defaults *KeyMap, active map[string]string, k, except string) string {
+	for _, r := range rebindable {
+		if r.action == except {
+			continue
+		}
+		bound := r.binding(defaults).Keys()
+		if o, ok := active[r.action]; ok {
+			bound = []string{o}
+		}
+		if slices.Contains(bound, k) {
+			return r.action
+		}
+	}
+	return ""
+}
+
+// shortcutList describes the rebindable shortcuts of keys for the editor
+func shortcutList(keys KeyMap) []views.Shortcut {
+	defaults := DefaultKeyMap()
+	list := make([]views.Shortcut, 0, len(rebindable))
+	for _, r := range rebindable {
+		list = append(list, views.Shortcut{
+			Action:   r.action,
+			Label:    r.label,
+			Keys:     r.binding(&keys).Keys(),
+			Defaults: r.binding(&defaults).Keys(),
+		})
+	}
+	return list
+}
@@ -1,14 +1,29 @@
 // Package network - UDP Listener for Bubble Tea
 // Non-blocking UDP listener for real-time notifications
 // Handles chapter release alerts and system notifications
+// Registers with the UDP server as the logged-in user so the server can
+// apply the user's notification settings and per-manga mutes
+// Verifies signed notifications (NotificationSecret) and drops forged,
+// unsigned or replayed packets without stopping the listener
 package network
 
 import (
 	"encoding/json"
+	"fmt"
 	"net"
+	"strings"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
+
+	"mangahub/internal/udp"
+)
+
+// Notification signing settings (configs: udp.secret, udp.replay_window)
+// Set from cmd/tui/main.go; an empty secret accepts unsigned notifications
+var (
+	NotificationSecret       = ""
+	NotificationReplayWindow = udp.DefaultReplayWindow
 )
 
 // =====================================
@@ -26,9 +41,9 @@ type UDPNotificationMsg struct {
 	Timestamp time.Time `json:"timestamp"`  // When notification was sent
 }
 
-// UDPConnectedMsg signals UDP listener started
+// UDPConnectedMsg signals UDP listener started and registered
 type UDPConnectedMsg struct {
-	Port string
+	ServerAddr string
 }
 
 // UDPErrorMsg signals a UDP error
@@ -36,6 +51,12 @@ type UDPErrorMsg struct {
 	Err error
 }
 
+// UDPRejectedMsg signals a datagram dropped by signature verification
+// The listener is still running; keep waiting for packets
+type UDPRejectedMsg struct {
+	Err error
+}
+
 // UDPDisconnectedMsg signals UDP listener stopped
 type UDPDisconnectedMsg struct {
 	Reason string
@@ -47,16 +68,18 @@ type UDPDisconnectedMsg struct {
 
 // UDPListener manages UDP connection for Bubble Tea
 type UDPListener struct {
-	conn   *net.UDPConn
-	port   string
-	done   chan struct{}
-	active bool
+	conn       *net.UDPConn
+	serverAddr *net.UDPAddr
+	verifier   *udp.Verifier
+	done       chan struct{}
+	active     bool
 }
 
 // NewUDPListener creates a new UDP listener
 func NewUDPListener() *UDPListener {
 	return &UDPListener{
-		done: make(chan struct{}),
+		verifier: udp.NewVerifier(NotificationSecret, NotificationReplayWindow),
+		done:     make(chan struct{}),
 	}
 }
 
@@ -64,36 +87,52 @@ func NewUDPListener() *UDPListener {
 // BUBBLE TEA COMMANDS
 // =====================================
 
-// Start begins listening for UDP notifications - returns tea.Cmd
-func (l *UDPListener) Start(port string) tea.Cmd {
+// Start opens a local socket and registers it with the UDP server as userID - returns tea.Cmd
+// The registration stays in place while notification settings change; the
+// server decides per broadcast whether this user receives it.
+func (l *UDPListener) Start(serverAddr, userID string) tea.Cmd {
 	return func() tea.Msg {
-		l.port = port
-
-		addr, err := net.ResolveUDPAddr("udp", ":"+port)
+		addr, err := net.ResolveUDPAddr("udp", serverAddr)
 		if err != nil {
 			return UDPErrorMsg{Err: err}
 		}
 
-		conn, err := net.ListenUDP("udp", addr)
+		conn, err := net.ListenUDP("udp", nil)
 		if err != nil {
 			return UDPErrorMsg{Err: err}
 		}
 
+		register := "REGISTER"
+		if userID != "" {
+			register += " " + userID
+		}
+		if _, err := conn.WriteToUDP([]byte(register), addr); err != nil {
+			conn.Close()
+			return UDPErrorMsg{Err: err}
+		}
+
 		l.conn = conn
+		l.serverAddr = addr
+		l.done = make(chan struct{})
 		l.active = true
 
-		return UDPConnectedMsg{Port: port}
+		return UDPConnectedMsg{ServerAddr: serverAddr}
 	}
 }
 
-// Stop stops the UDP listener
+// Stop unregisters from the server and stops the UDP listener
 func (l *UDPListener) Stop() tea.Cmd {
 	return func() tea.Msg {
 		if l.conn != nil {
+			if l.serverAddr != nil {
+				_, _ = l.conn.WriteToUDP([]byte("UNREGISTER"), l.serverAddr)
+			}
 			l.conn.Close()
 		}
+		if l.active {
+			close(l.done)
+		}
 		l.active = false
-		close(l.done)
 		return UDPDisconnectedMsg{Reason: "user stopped"}
 	}
 }
@@ -107,37 +146,67 @@ func (l *UDPListener) WaitForPacket() tea.Cmd {
 		}
 
 		buffer := make([]byte, 2048)
+		for {
+			// Set read timeout to allow graceful shutdown
+			l.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
+
+			n, _, err := l.conn.ReadFromUDP(buffer)
+			if err != nil {
+				select {
+				case <-l.done:
+					return UDPDisconnectedMsg{Reason: "user stopped"}
+				default:
+				}
+				// Check if it's a timeout (expected, just retry)
+				if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+					continue
+				}
+				return UDPErrorMsg{Err: err}
+			}
 
-		// Set read timeout to allow graceful shutdown
-		l.conn.SetReadDeadline(time.Now().Add(5 * time.Second))
-
-		n, _, err := l.conn.ReadFromUDP(buffer)
-		if err != nil {
-			// Check if it's a timeout (expected, just retry)
-			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-				// Not an error, just no data yet - resubscribe
-				return nil
+			// Registration acknowledgements are not notifications
+			text := strings.TrimSpace(string(buffer[:n]))
+			if text == "REGISTERED" || text == "UNREGISTERED" {
+				continue
 			}
-			return UDPErrorMsg{Err: err}
-		}
 
-		// Parse the notification
-		var msg UDPNotificationMsg
-		if err := json.Unmarshal(buffer[:n], &msg); err != nil {
-			// Try parsing as simple text
-			msg = UDPNotificationMsg{
-				Type:      "system",
-				Content:   string(buffer[:n]),
-				Timestamp: time.Now(),
+			msg, err := l.parseNotification(buffer[:n])
+			if err != nil {
+				return UDPRejectedMsg{Err: err}
 			}
+			return msg
 		}
+	}
+}
 
-		if msg.Timestamp.IsZero() {
-			msg.Timestamp = time.Now()
+// parseNotification decodes and verifies a server datagram.
+// Without a secret, non-JSON datagrams are shown as plain text.
+func (l *UDPListener) parseNotification(data []byte) (UDPNotificationMsg, error) {
+	var wire udp.Notification
+	if err := json.Unmarshal(data, &wire); err != nil || wire.Type == "" {
+		if l.verifier != nil {
+			return UDPNotificationMsg{}, fmt.Errorf("dropped notification: %w", udp.ErrUnsigned)
 		}
+		return UDPNotificationMsg{
+			Type:      "system",
+			Content:   string(data),
+			Timestamp: time.Now(),
+		}, nil
+	}
+	if err := l.verifier.Verify(wire); err != nil {
+		return UDPNotificationMsg{}, fmt.Errorf("dropped notification: %w", err)
+	}
 
-		return msg
+	msg := UDPNotificationMsg{
+		Type:      wire.Type,
+		Content:   wire.Message,
+		MangaID:   wire.MangaID,
+		Timestamp: time.Unix(wire.Timestamp, 0),
+	}
+	if wire.Timestamp == 0 {
+		msg.Timestamp = time.Now()
 	}
+	return msg, nil
 }
 
 // IsActive returns whether the listener is active
@@ -149,43 +218,20 @@ func (l *UDPListener) IsActive() bool {
 // HELPER FUNCTIONS
 // =====================================
 
-// RegisterWithServer sends a REGISTER message to the UDP notification server
-func (l *UDPListener) RegisterWithServer(serverAddr, userID string) tea.Cmd {
-	return func() tea.Msg {
-		addr, err := net.ResolveUDPAddr("udp", serverAddr)
-		if err != nil {
-			return UDPErrorMsg{Err: err}
-		}
-
-		conn, err := net.DialUDP("udp", nil, addr)
-		if err != nil {
-			return UDPErrorMsg{Err: err}
-		}
-		defer conn.Close()
-
-		// Send REGISTER message
-		registerMsg := map[string]string{
-			"type":    "REGISTER",
-			"user_id": userID,
-		}
-		data, _ := json.Marshal(registerMsg)
-		_, err = conn.Write(data)
-		if err != nil {
-			return UDPErrorMsg{Err: err}
-		}
-
-		return nil
-	}
-}
-
 // FormatNotification formats a notification for display
+// Only verified notifications reach it (see WaitForPacket)
 func FormatNotification(msg UDPNotificationMsg) string {
 	switch msg.Type {
 	case "chapter_release":
 		if msg.MangaName != "" {
 			return "📖 " + msg.MangaName + " - Chapter " + string(rune('0'+msg.Chapter)) + " released!"
 		}
+		if msg.Content != "" {
+			return "📖 " + msg.Content
+		}
 		return "📖 New chapter released!"
+	case "status_change":
+		return "✅ " + msg.Content
 	case "announcement":
 		return "📢 " + msg.Content
 	default:
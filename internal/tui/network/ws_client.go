@@ -1,9 +1,12 @@
 // Package network - WebSocket Client Manager for Bubble Tea
 // Non-blocking WebSocket integration using tea.Cmd pattern
 // Handles real-time chat communication with the backend Hub
+// Close tears the session down (leaving chat, logout); Reconnect gives up after maxReconnect
+// After a reconnect, Resume asks the hub to replay what was missed
 package network
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net/http"
@@ -25,8 +28,34 @@ type ChatMessageMsg struct {
 	UserID    string    `json:"user_id"`
 	Username  string    `json:"username"`
 	Content   string    `json:"content"`
-	Type      string    `json:"type"` // text, join, leave, system
+	Type      string    `json:"type"` // text, join, leave, system, typing, presence, replay, resync
 	Timestamp time.Time `json:"timestamp"`
+	Members   []string  `json:"members,omitempty"` // presence only
+
+	Messages []ChatMessageMsg `json:"messages,omitempty"` // replay only, oldest first
+}
+
+// UnmarshalJSON accepts the hub's unix-seconds timestamps as well as RFC 3339
+func (m *ChatMessageMsg) UnmarshalJSON(data []byte) error {
+	type alias ChatMessageMsg
+	var wire struct {
+		alias
+		Timestamp json.RawMessage `json:"timestamp"`
+	}
+	if err := json.Unmarshal(data, &wire); err != nil {
+		return err
+	}
+	*m = ChatMessageMsg(wire.alias)
+
+	var unix int64
+	if err := json.Unmarshal(wire.Timestamp, &unix); err == nil {
+		m.Timestamp = time.Unix(unix, 0)
+		return nil
+	}
+	if err := json.Unmarshal(wire.Timestamp, &m.Timestamp); err != nil || m.Timestamp.IsZero() {
+		m.Timestamp = time.Now()
+	}
+	return nil
 }
 
 // WSConnectedMsg signals successful WebSocket connection
@@ -34,11 +63,16 @@ type WSConnectedMsg struct {
 	RoomID string
 }
 
-// WSDisconnectedMsg signals WebSocket disconnection
+// WSDisconnectedMsg signals that the connection dropped; the app may Reconnect
 type WSDisconnectedMsg struct {
 	Reason string
 }
 
+// WSClosedMsg signals that the client was closed on purpose (Close or
+// Disconnect); pending commands of the closed session end with it and nothing
+// should reconnect
+type WSClosedMsg struct{}
+
 // WSErrorMsg signals a WebSocket error
 type WSErrorMsg struct {
 	Err error
@@ -50,6 +84,12 @@ type WSReconnectingMsg struct {
 	MaxWait time.Duration
 }
 
+// WSReconnectFailedMsg signals that Reconnect gave up after Attempts tries;
+// the session is closed and a new Connect starts over
+type WSReconnectFailedMsg struct {
+	Attempts int
+}
+
 // SendMessageCmd is returned when user wants to send a message
 type SendMessageCmd struct {
 	RoomID  string
@@ -68,18 +108,28 @@ type JoinRoomMsg struct {
 // WEBSOCKET CLIENT
 // =====================================
 
-// WSClient manages WebSocket connection for Bubble Tea
+// WSClient manages WebSocket connection for Bubble Tea.
+//
+// A session runs from Connect until Close and survives reconnects; cancelling
+// its context stops the read/write loops, pending reconnect waits and
+// ListenForMessages. Each dialled connection gets a child context that is
+// cancelled when that connection is lost.
 type WSClient struct {
-	conn     *websocket.Conn
-	send     chan []byte
-	receive  chan []byte
-	done     chan struct{}
-	mu       sync.RWMutex
-	url      string
-	token    string
-	roomID   string
+	conn      *websocket.Conn
+	send      chan []byte
+	receive   chan []byte
+	mu        sync.RWMutex
+	url       string
+	token     string
+	roomID    string
 	connected bool
-	
+
+	// Session and current connection lifetimes
+	ctx        context.Context
+	cancel     context.CancelFunc
+	connCtx    context.Context
+	connCancel context.CancelFunc
+
 	// Reconnection
 	reconnectAttempt int
 	maxReconnect     int
@@ -92,7 +142,6 @@ func NewWSClient() *WSClient {
 	return &WSClient{
 		send:         make(chan []byte, 256),
 		receive:      make(chan []byte, 256),
-		done:         make(chan struct{}),
 		maxReconnect: 5,
 		baseBackoff:  2 * time.Second,
 		maxBackoff:   30 * time.Second,
@@ -113,79 +162,93 @@ func (c *WSClient) CurrentRoom() string {
 	return c.roomID
 }
 
+// Close ends the session: the socket is closed, the read/write loops stop and
+// no reconnect is attempted. Safe to call when not connected.
+func (c *WSClient) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closeLocked()
+}
+
+// closeLocked is Close with c.mu held
+func (c *WSClient) closeLocked() {
+	if c.cancel != nil {
+		c.cancel()
+		c.cancel = nil
+	}
+	if c.conn != nil {
+		c.conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
+		c.conn.Close()
+		c.conn = nil
+	}
+	c.connected = false
+	c.reconnectAttempt = 0
+	c.token = ""
+	c.roomID = ""
+
+	// Drop anything queued for the old session
+	for {
+		select {
+		case <-c.send:
+		case <-c.receive:
+		default:
+			return
+		}
+	}
+}
+
 // =====================================
 // BUBBLE TEA COMMANDS
 // =====================================
 
-// Connect establishes WebSocket connection - returns tea.Cmd
+// Connect starts a new session, closing any previous one - returns tea.Cmd
 func (c *WSClient) Connect(baseURL, token, roomID string) tea.Cmd {
 	return func() tea.Msg {
 		c.mu.Lock()
+		c.closeLocked()
 		c.url = baseURL
 		c.token = token
 		c.roomID = roomID
+		c.ctx, c.cancel = context.WithCancel(context.Background())
+		ctx := c.ctx
 		c.mu.Unlock()
 
-		// Build WebSocket URL with auth
-		wsURL := fmt.Sprintf("%s/ws/chat?room_id=%s", baseURL, roomID)
-		
-		// Set up headers with JWT token
-		header := http.Header{}
-		if token != "" {
-			header.Set("Authorization", "Bearer "+token)
-		}
-
-		// Dial WebSocket
-		conn, _, err := websocket.DefaultDialer.Dial(wsURL, header)
+		conn, err := c.dial(ctx, baseURL, token, roomID)
 		if err != nil {
+			if ctx.Err() != nil {
+				return WSClosedMsg{}
+			}
 			return WSErrorMsg{Err: fmt.Errorf("failed to connect: %w", err)}
 		}
-
-		c.mu.Lock()
-		c.conn = conn
-		c.connected = true
-		c.reconnectAttempt = 0
-		c.mu.Unlock()
-
-		// Start read/write loops
-		go c.readLoop()
-		go c.writeLoop()
-
+		if !c.start(ctx, conn) {
+			return WSClosedMsg{}
+		}
 		return WSConnectedMsg{RoomID: roomID}
 	}
 }
 
-// Disconnect closes the WebSocket connection
+// Disconnect closes the session - returns tea.Cmd
 func (c *WSClient) Disconnect() tea.Cmd {
 	return func() tea.Msg {
-		c.mu.Lock()
-		defer c.mu.Unlock()
-
-		if c.conn != nil {
-			// Send close message
-			c.conn.WriteMessage(websocket.CloseMessage, 
-				websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""))
-			c.conn.Close()
-			c.conn = nil
-		}
-		c.connected = false
-		close(c.done)
-
-		return WSDisconnectedMsg{Reason: "user disconnect"}
+		c.Close()
+		return WSClosedMsg{}
 	}
 }
 
 // ListenForMessages is a Bubble Tea subscription that listens for incoming messages
 // It blocks waiting for a message, then returns it and re-subscribes
 func (c *WSClient) ListenForMessages() tea.Cmd {
+	c.mu.RLock()
+	ctx, connCtx := c.ctx, c.connCtx
+	c.mu.RUnlock()
+
 	return func() tea.Msg {
+		if ctx == nil || connCtx == nil {
+			return WSClosedMsg{}
+		}
 		select {
-		case data, ok := <-c.receive:
-			if !ok {
-				// Channel closed, connection lost
-				return WSDisconnectedMsg{Reason: "connection closed"}
-			}
-
+		case data := <-c.receive:
 			// Parse the message
 			var msg ChatMessageMsg
 			if err := json.Unmarshal(data, &msg); err != nil {
@@ -198,8 +261,14 @@ func (c *WSClient) ListenForMessages() tea.Cmd {
 			}
 			return msg
 
-		case <-c.done:
-			return WSDisconnectedMsg{Reason: "client shutdown"}
+		case <-ctx.Done():
+			return WSClosedMsg{}
+
+		case <-connCtx.Done():
+			if ctx.Err() != nil {
+				return WSClosedMsg{}
+			}
+			return WSDisconnectedMsg{Reason: "connection lost"}
 		}
 	}
 }
@@ -236,16 +305,80 @@ func (c *WSClient) SendMessage(roomID, content string) tea.Cmd {
 	}
 }
 
-// Reconnect attempts to reconnect with exponential backoff
+// SendTyping tells the room the user is typing.
+// Best effort: a full buffer silently drops the event.
+func (c *WSClient) SendTyping(roomID string) tea.Cmd {
+	return func() tea.Msg {
+		c.mu.RLock()
+		connected := c.connected
+		c.mu.RUnlock()
+
+		if !connected {
+			return nil
+		}
+
+		data, err := json.Marshal(map[string]interface{}{
+			"room_id": roomID,
+			"type":    "typing",
+		})
+		if err != nil {
+			return nil
+		}
+
+		select {
+		case c.send <- data:
+		default:
+		}
+		return nil
+	}
+}
+
+// Resume asks the hub for the room's messages newer than lastID, the newest
+// message already shown. The hub answers with a "replay" message, or with
+// "resync" when the gap is too long to replay and history should be reloaded.
+func (c *WSClient) Resume(roomID, lastID string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := json.Marshal(map[string]interface{}{
+			"room_id": roomID,
+			"type":    "resume",
+			"last_id": lastID,
+		})
+		if err != nil {
+			return WSErrorMsg{Err: err}
+		}
+
+		select {
+		case c.send <- data:
+			return nil
+		default:
+			return WSErrorMsg{Err: fmt.Errorf("send buffer full")}
+		}
+	}
+}
+
+// Reconnect attempts to reconnect with exponential backoff. After
+// maxReconnect failed attempts it closes the session and returns
+// WSReconnectFailedMsg; a closed session returns WSClosedMsg at once.
 func (c *WSClient) Reconnect() tea.Cmd {
 	return func() tea.Msg {
 		c.mu.Lock()
+		ctx := c.ctx
+		if ctx == nil || ctx.Err() != nil {
+			c.mu.Unlock()
+			return WSClosedMsg{}
+		}
+		if c.connected {
+			// Another Reconnect already succeeded
+			c.mu.Unlock()
+			return nil
+		}
+
 		c.reconnectAttempt++
 		attempt := c.reconnectAttempt
-		
 		if attempt > c.maxReconnect {
+			c.closeLocked()
 			c.mu.Unlock()
-			return WSErrorMsg{Err: fmt.Errorf("max reconnection attempts reached")}
+			return WSReconnectFailedMsg{Attempts: attempt - 1}
 		}
 
 		// Calculate backoff with exponential increase
@@ -259,31 +392,25 @@ func (c *WSClient) Reconnect() tea.Cmd {
 		roomID := c.roomID
 		c.mu.Unlock()
 
-		// Wait before reconnecting
-		time.Sleep(backoff)
-
-		// Attempt reconnection
-		wsURL := fmt.Sprintf("%s/ws/chat?room_id=%s", url, roomID)
-		header := http.Header{}
-		if token != "" {
-			header.Set("Authorization", "Bearer "+token)
+		// Wait before reconnecting, unless the session is closed meanwhile
+		timer := time.NewTimer(backoff)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return WSClosedMsg{}
 		}
 
-		conn, _, err := websocket.DefaultDialer.Dial(wsURL, header)
+		conn, err := c.dial(ctx, url, token, roomID)
 		if err != nil {
+			if ctx.Err() != nil {
+				return WSClosedMsg{}
+			}
 			return WSReconnectingMsg{Attempt: attempt, MaxWait: backoff * 2}
 		}
-
-		c.mu.Lock()
-		c.conn = conn
-		c.connected = true
-		c.reconnectAttempt = 0
-		c.done = make(chan struct{}) // Reset done channel
-		c.mu.Unlock()
-
-		go c.readLoop()
-		go c.writeLoop()
-
+		if !c.start(ctx, conn) {
+			return WSClosedMsg{}
+		}
 		return WSConnectedMsg{RoomID: roomID}
 	}
 }
@@ -292,36 +419,72 @@ func (c *WSClient) Reconnect() tea.Cmd {
 // INTERNAL GOROUTINES
 // =====================================
 
-// readLoop runs in a goroutine, reading messages from WebSocket
-func (c *WSClient) readLoop() {
+// dial opens the WebSocket for a room; cancelling ctx aborts the handshake
+func (c *WSClient) dial(ctx context.Context, baseURL, token, roomID string) (*websocket.Conn, error) {
+	// Build WebSocket URL with auth
+	wsURL := fmt.Sprintf("%s/ws/chat?room_id=%s", baseURL, roomID)
+
+	// Set up headers with JWT token
+	header := http.Header{}
+	if token != "" {
+		header.Set("Authorization", "Bearer "+token)
+	}
+
+	conn, _, err := websocket.DefaultDialer.DialContext(ctx, wsURL, header)
+	return conn, err
+}
+
+// start installs conn as the session's connection and runs its loops. It
+// returns false, closing conn, when the session was closed during the dial.
+func (c *WSClient) start(ctx context.Context, conn *websocket.Conn) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if ctx.Err() != nil || ctx != c.ctx {
+		conn.Close()
+		return false
+	}
+	connCtx, connCancel := context.WithCancel(ctx)
+	c.conn = conn
+	c.connCtx, c.connCancel = connCtx, connCancel
+	c.connected = true
+	c.reconnectAttempt = 0
+
+	go c.readLoop(connCtx, connCancel, conn)
+	go c.writeLoop(connCtx, conn)
+	return true
+}
+
+// readLoop runs in a goroutine, reading messages from WebSocket until the
+// connection fails or its context is cancelled
+func (c *WSClient) readLoop(ctx context.Context, cancel context.CancelFunc, conn *websocket.Conn) {
 	defer func() {
 		c.mu.Lock()
-		c.connected = false
+		if c.conn == conn {
+			c.conn = nil
+			c.connected = false
+		}
 		c.mu.Unlock()
+		conn.Close()
+		// Tells writeLoop and ListenForMessages that this connection is gone
+		cancel()
 	}()
 
-	for {
-		c.mu.RLock()
-		conn := c.conn
-		c.mu.RUnlock()
-
-		if conn == nil {
-			return
-		}
+	// Closing the socket unblocks ReadMessage when the session is closed
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
 
+	for {
 		_, message, err := conn.ReadMessage()
 		if err != nil {
-			// Connection error - signal disconnect
-			select {
-			case c.receive <- []byte(`{"type":"error","content":"connection lost"}`):
-			default:
-			}
 			return
 		}
 
 		select {
 		case c.receive <- message:
-		case <-c.done:
+		case <-ctx.Done():
 			return
 		default:
 			// Buffer full, drop message (log in production)
@@ -330,45 +493,25 @@ func (c *WSClient) readLoop() {
 }
 
 // writeLoop runs in a goroutine, writing messages to WebSocket
-func (c *WSClient) writeLoop() {
+func (c *WSClient) writeLoop(ctx context.Context, conn *websocket.Conn) {
 	ticker := time.NewTicker(54 * time.Second) // Ping interval
 	defer ticker.Stop()
 
 	for {
 		select {
-		case message, ok := <-c.send:
-			if !ok {
-				return
-			}
-
-			c.mu.RLock()
-			conn := c.conn
-			c.mu.RUnlock()
-
-			if conn == nil {
-				return
-			}
-
+		case message := <-c.send:
 			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 			if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
 				return
 			}
 
 		case <-ticker.C:
-			c.mu.RLock()
-			conn := c.conn
-			c.mu.RUnlock()
-
-			if conn == nil {
-				return
-			}
-
 			conn.SetWriteDeadline(time.Now().Add(10 * time.Second))
 			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
 				return
 			}
 
-		case <-c.done:
+		case <-ctx.Done():
 			return
 		}
 	}
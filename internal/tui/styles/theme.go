@@ -1,5 +1,6 @@
-// Package styles - MangaHub Dracula Theme
-// Hệ thống thiết kế TUI với màu Dracula
+// Package styles - MangaHub Themes
+// Hệ thống thiết kế TUI, mặc định màu Dracula
+// Palettes: dracula, dark, light, nord (LoadTheme/ApplyTheme đổi theme lúc runtime)
 // Triết lý: "Bloomberg Terminal for Manga" - thông tin dày đặc nhưng sạch sẽ
 //
 // Color Palette (Dracula-inspired):
@@ -13,11 +14,15 @@
 package styles
 
 import (
+	"fmt"
+	"math"
+	"strings"
+
 	"github.com/charmbracelet/lipgloss"
 )
 
 // =====================================
-// COLOR PALETTE - Dracula Theme Colors
+// COLOR PALETTE - Active Theme Colors (Dracula until ApplyTheme)
 // =====================================
 
 var (
@@ -39,12 +44,137 @@ var (
 	ColorBlack   = lipgloss.Color("#21222c") // Darker background
 )
 
+// =====================================
+// PALETTES - Named Color Schemes
+// =====================================
+
+// Palette is a named set of theme colors
+type Palette struct {
+	Name       string
+	Background lipgloss.Color
+	Foreground lipgloss.Color
+	Primary    lipgloss.Color
+	Secondary  lipgloss.Color
+	Success    lipgloss.Color
+	Warning    lipgloss.Color
+	Error      lipgloss.Color
+	Cyan       lipgloss.Color
+	Dim        lipgloss.Color
+	Black      lipgloss.Color
+}
+
+var (
+	// DraculaPalette is the default theme
+	DraculaPalette = Palette{
+		Name:       "dracula",
+		Background: "#282a36",
+		Foreground: "#f8f8f2",
+		Primary:    "#bd93f9",
+		Secondary:  "#ff79c6",
+		Success:    "#50fa7b",
+		Warning:    "#ffb86c",
+		Error:      "#ff5555",
+		Cyan:       "#8be9fd",
+		Dim:        "#6272a4",
+		Black:      "#21222c",
+	}
+
+	// DarkPalette is a neutral dark theme
+	DarkPalette = Palette{
+		Name:       "dark",
+		Background: "#1e1e1e",
+		Foreground: "#d4d4d4",
+		Primary:    "#569cd6",
+		Secondary:  "#c586c0",
+		Success:    "#6a9955",
+		Warning:    "#ce9178",
+		Error:      "#f44747",
+		Cyan:       "#4ec9b0",
+		Dim:        "#808080",
+		Black:      "#252526",
+	}
+
+	// LightPalette is for light terminal backgrounds
+	LightPalette = Palette{
+		Name:       "light",
+		Background: "#fafafa",
+		Foreground: "#383a42",
+		Primary:    "#4078f2",
+		Secondary:  "#a626a4",
+		Success:    "#50a14f",
+		Warning:    "#c18401",
+		Error:      "#e45649",
+		Cyan:       "#0184bc",
+		Dim:        "#a0a1a7",
+		Black:      "#e5e5e6",
+	}
+
+	// NordPalette is the Nord arctic theme
+	NordPalette = Palette{
+		Name:       "nord",
+		Background: "#2e3440",
+		Foreground: "#eceff4",
+		Primary:    "#88c0d0",
+		Secondary:  "#b48ead",
+		Success:    "#a3be8c",
+		Warning:    "#ebcb8b",
+		Error:      "#bf616a",
+		Cyan:       "#8fbcbb",
+		Dim:        "#616e88",
+		Black:      "#3b4252",
+	}
+)
+
+// palettes lists the themes in the order Settings cycles through them
+var palettes = []Palette{DraculaPalette, DarkPalette, LightPalette, NordPalette}
+
+// ThemeNames returns the available theme names
+func ThemeNames() []string {
+	names := make([]string, len(palettes))
+	for i, p := range palettes {
+		names[i] = p.Name
+	}
+	return names
+}
+
+// LoadTheme returns the named theme; unknown names fall back to Dracula
+func LoadTheme(name string) *Theme {
+	name = strings.ToLower(strings.TrimSpace(name))
+	for _, p := range palettes {
+		if p.Name == name {
+			return NewThemeFromPalette(p)
+		}
+	}
+	return NewThemeFromPalette(DraculaPalette)
+}
+
+// ApplyTheme makes t the active theme: DefaultTheme, the Color* variables
+// and the Render* helpers all switch to it
+func ApplyTheme(t *Theme) {
+	DefaultTheme = t
+	ColorBackground = t.Palette.Background
+	ColorForeground = t.Palette.Foreground
+	ColorPrimary = t.Palette.Primary
+	ColorSecondary = t.Palette.Secondary
+	ColorSuccess = t.Palette.Success
+	ColorWarning = t.Palette.Warning
+	ColorError = t.Palette.Error
+	ColorCyan = t.Palette.Cyan
+	ColorDim = t.Palette.Dim
+	ColorComment = t.Palette.Dim
+	ColorBlack = t.Palette.Black
+}
+
 // =====================================
 // THEME STRUCT - Centralized Styling
 // =====================================
 
 // Theme contains all application styles
 type Theme struct {
+	// Palette the styles were built from
+	Name    string
+	Palette Palette
+
 	// Base styles
 	AppBox           lipgloss.Style
 	Container        lipgloss.Style
@@ -82,6 +212,9 @@ type Theme struct {
 	ProgressEmpty lipgloss.Style
 	ProgressText  lipgloss.Style
 
+	// Reading heatmap cells, from no activity (dim) to the busiest days
+	Heatmap [HeatmapLevels]lipgloss.Style
+
 	// Rating
 	RatingStar    lipgloss.Style
 	RatingStarDim lipgloss.Style
@@ -116,65 +249,73 @@ type Theme struct {
 	Badge     lipgloss.Style
 }
 
-// DefaultTheme is the global theme instance
+// HeatmapLevels is the number of heatmap shades, level 0 (no reading) included
+const HeatmapLevels = 5
+
+// DefaultTheme is the active theme; views read it when they are created
 var DefaultTheme = NewTheme()
 
 // NewTheme creates a new Theme with Dracula colors
 func NewTheme() *Theme {
-	t := &Theme{}
+	return NewThemeFromPalette(DraculaPalette)
+}
+
+// NewThemeFromPalette builds every style from a palette
+func NewThemeFromPalette(p Palette) *Theme {
+	t := &Theme{Name: p.Name, Palette: p}
 
 	// ===== BASE STYLES =====
 
 	// AppBox: Main application container
 	t.AppBox = lipgloss.NewStyle().
-		Background(ColorBackground).
-		Foreground(ColorForeground).
+		Background(p.Background).
+		Foreground(p.Foreground).
 		Padding(1)
 
 	// Container: Generic bordered container
 	t.Container = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(ColorDim).
+		BorderForeground(p.Dim).
 		Padding(0, 1)
 
 	// FocusedContainer: Container with focus highlight
 	t.FocusedContainer = t.Container.
-		BorderForeground(ColorPrimary)
+		BorderForeground(p.Primary)
 
 	// ===== HEADER & NAVIGATION =====
 
 	// Header: Top bar style
 	t.Header = lipgloss.NewStyle().
 		Bold(true).
-		Background(ColorPrimary).
-		Foreground(ColorBackground).
+		Background(p.Primary).
+		Foreground(p.Background).
 		Padding(0, 2).
 		MarginBottom(1)
 
 	// HeaderTitle: App title in header
 	t.HeaderTitle = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(ColorForeground)
+		Foreground(p.Foreground)
 
 	// Tab: Inactive tab style
 	t.Tab = lipgloss.NewStyle().
-		Foreground(ColorDim).
+		Foreground(p.Dim).
 		Padding(0, 2)
 
 	// ActiveTab: Currently selected tab
 	t.ActiveTab = lipgloss.NewStyle().
-		Foreground(ColorPrimary).
+		Foreground(p.Primary).
 		Bold(true).
 		Padding(0, 2).
 		Border(lipgloss.Border{Bottom: "─"}).
-		BorderForeground(ColorPrimary)
+		BorderForeground(p.Primary)
 
 	// InactiveTab: Unselected tab
 	t.InactiveTab = t.Tab
 
 	// StatusOnline: Online indicator
 	t.StatusOnline = lipgloss.NewStyle().
-		Foreground(ColorSuccess).
+		Foreground(p.Success).
 		Bold(true)
 
 	// ===== CONTENT STYLES =====
@@ -182,101 +323,101 @@ func NewTheme() *Theme {
 	// Title: Main headings
 	t.Title = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(ColorSecondary)
+		Foreground(p.Secondary)
 
 	// Subtitle: Secondary text
 	t.Subtitle = lipgloss.NewStyle().
-		Foreground(ColorCyan)
+		Foreground(p.Cyan)
 
 	// Description: Body text
 	t.Description = lipgloss.NewStyle().
-		Foreground(ColorForeground)
+		Foreground(p.Foreground)
 
 	// DimText: Muted/inactive text
 	t.DimText = lipgloss.NewStyle().
-		Foreground(ColorDim)
+		Foreground(p.Dim)
 
 	// ErrorText: Error messages
 	t.ErrorText = lipgloss.NewStyle().
-		Foreground(ColorError).
+		Foreground(p.Error).
 		Bold(true)
 
 	// SuccessText: Success messages
 	t.SuccessText = lipgloss.NewStyle().
-		Foreground(ColorSuccess).
+		Foreground(p.Success).
 		Bold(true)
 
 	// ===== INTERACTIVE ELEMENTS =====
 
 	// Button: Clickable button
 	t.Button = lipgloss.NewStyle().
-		Foreground(ColorForeground).
-		Background(ColorPrimary).
+		Foreground(p.Foreground).
+		Background(p.Primary).
 		Padding(0, 2).
 		Bold(true)
 
 	// ButtonActive: Focused button
 	t.ButtonActive = t.Button.
-		Background(ColorSecondary)
+		Background(p.Secondary)
 
 	// ButtonInactive: Disabled button
 	t.ButtonInactive = lipgloss.NewStyle().
-		Foreground(ColorDim).
-		Background(ColorBlack).
+		Foreground(p.Dim).
+		Background(p.Black).
 		Padding(0, 2)
 
 	// Link: Clickable link
 	t.Link = lipgloss.NewStyle().
-		Foreground(ColorCyan).
+		Foreground(p.Cyan).
 		Underline(true)
 
 	// ===== LIST & ITEMS =====
 
 	// ListItem: Normal list item
 	t.ListItem = lipgloss.NewStyle().
-		Foreground(ColorForeground).
+		Foreground(p.Foreground).
 		PaddingLeft(2)
 
 	// ListItemSelected: Highlighted/selected item
 	t.ListItemSelected = lipgloss.NewStyle().
-		Foreground(ColorPrimary).
+		Foreground(p.Primary).
 		Bold(true).
 		PaddingLeft(2).
-		Background(ColorBlack)
+		Background(p.Black)
 
 	// ListItemDim: Inactive list item
 	t.ListItemDim = lipgloss.NewStyle().
-		Foreground(ColorDim).
+		Foreground(p.Dim).
 		PaddingLeft(2)
 
 	// ===== PROGRESS BAR =====
 
 	// ProgressFull: Filled portion of progress bar
 	t.ProgressFull = lipgloss.NewStyle().
-		Foreground(ColorSuccess)
+		Foreground(p.Success)
 
 	// ProgressEmpty: Empty portion of progress bar
 	t.ProgressEmpty = lipgloss.NewStyle().
-		Foreground(ColorDim)
+		Foreground(p.Dim)
 
 	// ProgressText: Percentage text
 	t.ProgressText = lipgloss.NewStyle().
-		Foreground(ColorForeground).
+		Foreground(p.Foreground).
 		Bold(true)
 
 	// ===== RATING =====
 
 	// RatingStar: Filled star
 	t.RatingStar = lipgloss.NewStyle().
-		Foreground(ColorWarning)
+		Foreground(p.Warning)
 
 	// RatingStarDim: Empty star
 	t.RatingStarDim = lipgloss.NewStyle().
-		Foreground(ColorDim)
+		Foreground(p.Dim)
 
 	// RatingNumber: Numeric rating
 	t.RatingNumber = lipgloss.NewStyle().
-		Foreground(ColorWarning).
+		Foreground(p.Warning).
 		Bold(true)
 
 	// ===== CARDS & PANELS =====
@@ -284,87 +425,98 @@ func NewTheme() *Theme {
 	// Card: Content card
 	t.Card = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(ColorDim).
+		BorderForeground(p.Dim).
 		Padding(1, 2)
 
 	// CardFocused: Focused card
 	t.CardFocused = t.Card.
-		BorderForeground(ColorPrimary)
+		BorderForeground(p.Primary)
 
 	// Panel: Section panel
 	t.Panel = lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
-		BorderForeground(ColorDim).
+		BorderForeground(p.Dim).
 		Padding(0, 1)
 
 	// PanelHeader: Panel title
 	t.PanelHeader = lipgloss.NewStyle().
 		Bold(true).
-		Foreground(ColorPrimary).
+		Foreground(p.Primary).
 		MarginBottom(1)
 
 	// ===== ACTIVITY FEED =====
 
 	// ActivityTime: Timestamp
 	t.ActivityTime = lipgloss.NewStyle().
-		Foreground(ColorDim).
+		Foreground(p.Dim).
 		Width(8)
 
 	// ActivityUser: Username
 	t.ActivityUser = lipgloss.NewStyle().
-		Foreground(ColorCyan).
+		Foreground(p.Cyan).
 		Bold(true)
 
 	// ActivityAction: Action description
 	t.ActivityAction = lipgloss.NewStyle().
-		Foreground(ColorForeground)
+		Foreground(p.Foreground)
 
 	// ===== FOOTER =====
 
 	// Footer: Bottom bar
 	t.Footer = lipgloss.NewStyle().
-		Foreground(ColorDim).
+		Foreground(p.Dim).
 		MarginTop(1).
 		Padding(0, 1)
 
 	// FooterKey: Keyboard shortcut highlight
 	t.FooterKey = lipgloss.NewStyle().
-		Foreground(ColorPrimary).
+		Foreground(p.Primary).
 		Bold(true)
 
 	// FooterText: Footer description
 	t.FooterText = lipgloss.NewStyle().
-		Foreground(ColorDim)
+		Foreground(p.Dim)
+
+	// ===== HEATMAP =====
+	// Level 0 is a faint dim cell so empty days still show; the rest climb
+	// from the background toward the success color
+
+	t.Heatmap[0] = lipgloss.NewStyle().
+		Foreground(blendColor(p.Background, p.Dim, 0.5))
+	for level := 1; level < HeatmapLevels; level++ {
+		t.Heatmap[level] = lipgloss.NewStyle().
+			Foreground(blendColor(p.Background, p.Success, 0.2+0.8*float64(level)/float64(HeatmapLevels-1)))
+	}
 
 	// ===== SPINNER =====
 
 	t.Spinner = lipgloss.NewStyle().
-		Foreground(ColorPrimary)
+		Foreground(p.Primary)
 
 	// ===== DIRECT COLOR STYLES (convenience) =====
 
 	t.Primary = lipgloss.NewStyle().
-		Foreground(ColorPrimary)
+		Foreground(p.Primary)
 
 	t.Secondary = lipgloss.NewStyle().
-		Foreground(ColorSecondary)
+		Foreground(p.Secondary)
 
 	t.Success = lipgloss.NewStyle().
-		Foreground(ColorSuccess)
+		Foreground(p.Success)
 
 	t.Warning = lipgloss.NewStyle().
-		Foreground(ColorWarning)
+		Foreground(p.Warning)
 
 	t.Error = lipgloss.NewStyle().
-		Foreground(ColorError)
+		Foreground(p.Error)
 
 	t.Key = lipgloss.NewStyle().
-		Foreground(ColorPrimary).
+		Foreground(p.Primary).
 		Bold(true)
 
 	t.Badge = lipgloss.NewStyle().
-		Foreground(ColorBackground).
-		Background(ColorPrimary).
+		Foreground(p.Background).
+		Background(p.Primary).
 		Padding(0, 1)
 
 	return t
@@ -493,6 +645,22 @@ func repeatChar(char string, count int) string {
 	return result
 }
 
+// blendColor mixes two #rrggbb colors, t=0 giving from and t=1 giving to
+func blendColor(from, to lipgloss.Color, t float64) lipgloss.Color {
+	var a, b [3]int
+	if _, err := fmt.Sscanf(string(from), "#%02x%02x%02x", &a[0], &a[1], &a[2]); err != nil {
+		return to
+	}
+	if _, err := fmt.Sscanf(string(to), "#%02x%02x%02x", &b[0], &b[1], &b[2]); err != nil {
+		return to
+	}
+	var mixed [3]int
+	for i := range mixed {
+		mixed[i] = a[i] + int(math.Round(float64(b[i]-a[i])*t))
+	}
+	return lipgloss.Color(fmt.Sprintf("#%02x%02x%02x", mixed[0], mixed[1], mixed[2]))
+}
+
 func formatPercent(p float64) string {
 	return lipgloss.NewStyle().Render(
 		string(rune('0'+int(p*100)/10)) +
@@ -18,7 +18,7 @@
 //	│  │    10 min ago                          ♥ 12 💬 5│  │
 //	│  └─────────────────────────────────────────────────┘  │
 //	│                                                       │
-//	│  [↑↓] Navigate  [Enter] View  [l] Like  [r] Refresh   │
+//	│  [↑↓] Navigate  [Enter] Profile  [f] Filter  [r] Refresh │
 //	└────────────────────────────────────────────────────────┘
 package views
 
@@ -49,6 +49,8 @@ const (
 	ActivityRated     ActivityType = "rated"
 	ActivityComment   ActivityType = "comment"
 	ActivityProgress  ActivityType = "progress"
+	// ActivityStatus is a manga changing status (e.g. ongoing → completed); it has no user
+	ActivityStatus ActivityType = "status_change"
 )
 
 // Activity represents a single activity item
@@ -82,6 +84,13 @@ type ActivityModel struct {
 	// Data
 	activities    []Activity
 	selectedIndex int
+	scroll        listScroll
+
+	// Paging and type filter ("" for all)
+	filter      string
+	hasMore     bool
+	nextBefore  string
+	loadingMore bool
 
 	// Loading
 	loading   bool
@@ -102,9 +111,14 @@ type ActivityModel struct {
 // MESSAGES
 // =====================================
 
-// ActivityLoadedMsg signals activities were loaded
+// ActivityLoadedMsg signals activities were loaded.
+// More is set for a follow-up page that extends the current list.
 type ActivityLoadedMsg struct {
 	Activities []Activity
+	Filter     string
+	HasMore    bool
+	NextBefore string
+	More       bool
 }
 
 // ActivityErrorMsg signals an error
@@ -147,22 +161,64 @@ func (m ActivityModel) Init() tea.Cmd {
 	)
 }
 
-// loadActivities fetches recent activities
+// activityPageSize is how many activities one page of the feed loads
+const activityPageSize = 20
+
+// activityFilters is the order the filter key cycles through
+var activityFilters = []string{"", "comment", "rating", "progress", "list_add", "status_change"}
+
+// loadActivities fetches the first page of the feed for the current filter
 func (m ActivityModel) loadActivities() tea.Msg {
 	ctx := context.Background()
+	filter := m.filter
 
 	// Get real activity feed from API
-	activityEntries, err := m.client.GetActivities(ctx, 20)
+	page, err := m.client.GetActivityPage(ctx, filter, "", activityPageSize)
 	if err != nil {
+		if filter != "" {
+			return ActivityErrorMsg{Error: err}
+		}
 		// Generate mock activities if API fails
 		return ActivityLoadedMsg{
 			Activities: m.generateMockActivities(),
 		}
 	}
 
-	// Convert API ActivityEntry to view Activity struct
+	activities := toActivities(page.Activities)
+
+	// Fallback to mock if no activities
+	if len(activities) == 0 && filter == "" {
+		return ActivityLoadedMsg{Activities: m.generateMockActivities()}
+	}
+
+	return ActivityLoadedMsg{
+		Activities: activities,
+		Filter:     filter,
+		HasMore:    page.HasMore,
+		NextBefore: page.NextBefore,
+	}
+}
+
+// loadMore fetches the page after the last loaded activity
+func (m ActivityModel) loadMore() tea.Msg {
+	filter, before := m.filter, m.nextBefore
+	page, err := m.client.GetActivityPage(context.Background(), filter, before, activityPageSize)
+	if err != nil {
+		return ActivityErrorMsg{Error: err}
+	}
+	return ActivityLoadedMsg{
+		Activities: toActivities(page.Activities),
+		Filter:     filter,
+		HasMore:    page.HasMore,
+		NextBefore: page.NextBefore,
+		More:       true,
+	}
+}
+
+// toActivities converts API ActivityEntry values to view Activity structs
+func toActivities(entries []api.ActivityEntry) []Activity {
 	var activities []Activity
-	for _, entry := range activityEntries {
+	for _, entry := range entries {
 		// Determine activity type from API's activity_type
 		var actType ActivityType
 		switch entry.ActivityType {
@@ -174,6 +230,8 @@ func (m ActivityModel) loadActivities() tea.Msg {
 			actType = ActivityProgress
 		case "list_add":
 			actType = ActivityStarted
+		case "status_change":
+			actType = ActivityStatus
 		default:
 			actType = ActivityProgress
 		}
@@ -208,13 +266,7 @@ func (m ActivityModel) loadActivities() tea.Msg {
 			Timestamp: entry.CreatedAt,
 		})
 	}
-
-	// Fallback to mock if no activities
-	if len(activities) == 0 {
-		return ActivityLoadedMsg{Activities: m.generateMockActivities()}
-	}
-
-	return ActivityLoadedMsg{Activities: activities}
+	return activities
 }
 
 // generateMockActivities creates sample activities for demo
@@ -308,29 +360,72 @@ func (m ActivityModel) Update(msg tea.Msg) (ActivityModel, tea.Cmd) {
 				}
 			}
 		case "down", "j":
-			if len(m.activities) > 0 {
-				m.selectedIndex = (m.selectedIndex + 1) % len(m.activities)
+			if len(m.activities) == 0 {
+				break
+			}
+			// At the bottom: load the next page instead of wrapping
+			if m.selectedIndex == len(m.activities)-1 && m.hasMore {
+				if !m.loadingMore {
+					m.loadingMore = true
+					cmds = append(cmds, m.loadMore)
+				}
+				break
 			}
+			m.selectedIndex = (m.selectedIndex + 1) % len(m.activities)
 		case "r":
 			// Refresh
 			m.loading = true
 			cmds = append(cmds, m.loadActivities)
+		case "f":
+			// Cycle the type filter and reload from the top
+			for i, f := range activityFilters {
+				if f == m.filter {
+					m.filter = activityFilters[(i+1)%len(activityFilters)]
+					break
+				}
+			}
+			m.loading = true
+			m.selectedIndex = 0
+			cmds = append(cmds, m.loadActivities)
 		case "l":
 			// Toggle live
 			m.isLive = !m.isLive
 		case "enter":
+			// Open the selected user's profile
+			if selected := m.GetSelectedActivity(); selected != nil && selected.Username != "" {
+				username := selected.Username
+				cmds = append(cmds, func() tea.Msg { return ShowProfileMsg{Username: username} })
+			}
+		case "m":
 			// View manga details
 			// Will be handled by parent
 		}
 
 	case ActivityLoadedMsg:
-		m.activities = msg.Activities
+		// Ignore pages for a filter the user has already switched away from
+		if msg.Filter != m.filter {
+			return m, nil
+		}
+		if msg.More {
+			m.activities = append(m.activities, msg.Activities...)
+			if len(msg.Activities) > 0 {
+				m.selectedIndex++
+			}
+		} else {
+			m.activities = msg.Activities
+			m.selectedIndex = 0
+		}
+		m.hasMore = msg.HasMore
+		m.nextBefore = msg.NextBefore
 		m.loading = false
+		m.loadingMore = false
+		m.lastError = nil
 		m.lastFetch = time.Now()
 
 	case ActivityErrorMsg:
 		m.lastError = msg.Error
 		m.loading = false
+		m.loadingMore = false
 
 	case ActivityTickMsg:
 		if m.isLive {
@@ -343,9 +438,15 @@ func (m ActivityModel) Update(msg tea.Msg) (ActivityModel, tea.Cmd) {
 		cmds = append(cmds, cmd)
 	}
 
+	m.scroll.follow(m.selectedIndex, len(m.activities), m.listRows())
 	return m, tea.Batch(cmds...)
 }
 
+// listRows is how many activity items (about five lines each) fit the feed
+func (m ActivityModel) listRows() int {
+	return (m.height - 10) / 5
+}
+
 // View renders the activity view
 func (m ActivityModel) View() string {
 	var sections []string
@@ -372,6 +473,9 @@ func (m ActivityModel) View() string {
 
 func (m ActivityModel) renderHeader() string {
 	title := m.theme.PanelHeader.Render("🌐 ACTIVITY FEED")
+	if m.filter != "" {
+		title += " " + m.theme.Secondary.Render("["+activityFilterLabel(m.filter)+"]")
+	}
 
 	// Live indicator
 	var liveIndicator string
@@ -398,7 +502,14 @@ func (m ActivityModel) renderFeed() string {
 		return m.theme.DimText.Render("Loading activities... " + m.spinner.View())
 	}
 
+	if m.lastError != nil && len(m.activities) == 0 {
+		return m.theme.ErrorText.Render("⚠ " + m.lastError.Error())
+	}
+
 	if len(m.activities) == 0 {
+		if m.filter != "" {
+			return m.theme.DimText.Render("No " + activityFilterLabel(m.filter) + " activity yet. Press [f] for another filter.")
+		}
 		return m.theme.DimText.Render("No recent activity. Be the first to share!")
 	}
 
@@ -410,23 +521,29 @@ func (m ActivityModel) renderFeed() string {
 		Padding(0, 1)
 
 	var items []string
-	maxVisible := minInt((m.height-10)/5, len(m.activities))
-	if maxVisible < 1 {
-		maxVisible = 1
-	}
-
-	for i := 0; i < maxVisible; i++ {
+	start, end := m.scroll.window(m.selectedIndex, len(m.activities), m.listRows())
+	for i := start; i < end; i++ {
 		activity := m.activities[i]
 		item := m.renderActivityItem(activity, i == m.selectedIndex)
 		items = append(items, item)
 
 		// Add separator (except for last)
-		if i < maxVisible-1 {
+		if i < end-1 {
 			sep := m.theme.DimText.Render(strings.Repeat("─", m.width-16))
 			items = append(items, sep)
 		}
 	}
 
+	switch {
+	case m.loadingMore:
+		items = append(items, m.theme.DimText.Render("Loading more... "+m.spinner.View()))
+	case m.hasMore && m.selectedIndex == len(m.activities)-1:
+		items = append(items, m.theme.DimText.Render("↓ more"))
+	}
+	if status := scrollStatus(start, end, len(m.activities)); status != "" {
+		items = append(items, m.theme.DimText.Render(status))
+	}
+
 	list := lipgloss.JoinVertical(lipgloss.Left, items...)
 	return listStyle.Render(list)
 }
@@ -436,10 +553,13 @@ func (m ActivityModel) renderActivityItem(activity Activity, selected bool) stri
 
 	// ===== LINE 1: Action =====
 	icon := m.getActivityIcon(activity.Type)
-	username := m.theme.Primary.Bold(true).Render("@" + activity.Username)
 	action := m.getActivityAction(activity)
 
-	line1 := icon + " " + username + " " + action
+	line1 := icon + " " + action
+	if activity.Username != "" {
+		username := m.theme.Primary.Bold(true).Render("@" + activity.Username)
+		line1 = icon + " " + username + " " + action
+	}
 	if selected {
 		line1 = m.theme.Secondary.Render("> ") + line1
 	} else {
@@ -487,6 +607,8 @@ func (m ActivityModel) getActivityIcon(actType ActivityType) string {
 		return "💬"
 	case ActivityProgress:
 		return "📈"
+	case ActivityStatus:
+		return "✅"
 	default:
 		return "📌"
 	}
@@ -509,6 +631,8 @@ func (m ActivityModel) getActivityAction(activity Activity) string {
 	case ActivityProgress:
 		chapter := m.theme.Primary.Render(fmt.Sprintf("Ch. %d", activity.Chapter))
 		return "reached " + chapter + " in " + manga
+	case ActivityStatus:
+		return manga + " changed status"
 	default:
 		return "interacted with " + manga
 	}
@@ -517,7 +641,9 @@ func (m ActivityModel) getActivityAction(activity Activity) string {
 func (m ActivityModel) renderHelp() string {
 	helpItems := []string{
 		m.theme.Key.Render("[↑↓]") + " " + m.theme.DimText.Render("Navigate"),
-		m.theme.Key.Render("[Enter]") + " " + m.theme.DimText.Render("View Manga"),
+		m.theme.Key.Render("[Enter]") + " " + m.theme.DimText.Render("Profile"),
+		m.theme.Key.Render("[m]") + " " + m.theme.DimText.Render("View Manga"),
+		m.theme.Key.Render("[f]") + " " + m.theme.DimText.Render("Filter"),
 		m.theme.Key.Render("[l]") + " " + m.theme.DimText.Render("Toggle Live"),
 		m.theme.Key.Render("[r]") + " " + m.theme.DimText.Render("Refresh"),
 	}
@@ -555,6 +681,24 @@ func formatTimeAgo(t time.Time) string {
 	}
 }
 
+// activityFilterLabel names a type filter for the header
+func activityFilterLabel(filter string) string {
+	switch filter {
+	case "comment":
+		return "comments"
+	case "rating":
+		return "ratings"
+	case "progress":
+		return "progress"
+	case "list_add":
+		return "list adds"
+	case "status_change":
+		return "status changes"
+	default:
+		return "all"
+	}
+}
+
 func minInt(a, b int) int {
 	if a < b {
 		return a
@@ -574,6 +718,12 @@ func (m ActivityModel) GetSelectedActivity() *Activity {
 	return nil
 }
 
+// SetTheme switches the view to a new theme
+func (m *ActivityModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
+}
+
 // SetWidth sets the view width
 func (m *ActivityModel) SetWidth(w int) {
 	m.width = w
@@ -582,6 +732,7 @@ func (m *ActivityModel) SetWidth(w int) {
 // SetHeight sets the view height
 func (m *ActivityModel) SetHeight(h int) {
 	m.height = h
+	m.scroll.follow(m.selectedIndex, len(m.activities), m.listRows())
 }
 
 // Refresh triggers a refresh of the activity feed
@@ -313,6 +313,13 @@ func (m AuthModel) Update(msg tea.Msg) (AuthModel, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
+// SetMode opens the form in login or signup mode
+func (m *AuthModel) SetMode(mode AuthMode) {
+	m.mode = mode
+	m.focusedField = 0
+	m.updateFocus()
+}
+
 func (m *AuthModel) updateFocus() {
 	m.usernameInput.Blur()
 	m.emailInput.Blur()
@@ -445,6 +452,12 @@ func (m AuthModel) View() string {
 	)
 }
 
+// SetTheme switches the view to a new theme
+func (m *AuthModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
+}
+
 // SetWidth sets the view width
 func (m *AuthModel) SetWidth(w int) {
 	m.width = w
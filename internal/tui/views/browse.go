@@ -14,7 +14,7 @@
 //	│  │    🧙    │  │    👻    │  │    🚀    │            │
 //	│  └──────────┘  └──────────┘  └──────────┘             │
 //	│                                                       │
-//	│  TRENDING IN ACTION                                   │
+//	│  ACTION + HORROR · 42 manga · sort: ⭐ rating ↓        │
 //	│  ┌─────────────────────────────────────────────────┐  │
 //	│  │ > One Piece          #1   ⭐ 9.2                │  │
 //	│  │   Jujutsu Kaisen     #2   ⭐ 8.9                │  │
@@ -25,9 +25,12 @@ package views
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 
@@ -40,27 +43,44 @@ import (
 // CATEGORY DEFINITIONS
 // =====================================
 
-// Category represents a manga genre category
+// Category is a genre card in the grid
 type Category struct {
 	Name  string
+	Slug  string
+	Count int // manga tagged with the genre
 	Icon  string
 	Color lipgloss.Color
 }
 
-// Categories available for browsing
+// Categories is the seeded genre taxonomy. Search and the dashboard cycle
+// through it as filters; Browse loads the live list with counts and borrows
+// the icon and color from here.
 var Categories = []Category{
-	{Name: "Action", Icon: "⚔️", Color: styles.ColorError},
-	{Name: "Romance", Icon: "💕", Color: styles.ColorSecondary},
-	{Name: "Comedy", Icon: "😄", Color: styles.ColorWarning},
-	{Name: "Fantasy", Icon: "🧙", Color: styles.ColorPrimary},
-	{Name: "Horror", Icon: "👻", Color: styles.ColorDim},
-	{Name: "Sci-Fi", Icon: "🚀", Color: styles.ColorSuccess},
-	{Name: "Slice of Life", Icon: "🏠", Color: lipgloss.Color("#8be9fd")},
-	{Name: "Sports", Icon: "⚽", Color: lipgloss.Color("#ffb86c")},
-	{Name: "Mystery", Icon: "🔍", Color: lipgloss.Color("#f1fa8c")},
-	{Name: "Adventure", Icon: "🗺️", Color: lipgloss.Color("#50fa7b")},
-	{Name: "Drama", Icon: "🎭", Color: lipgloss.Color("#ff79c6")},
-	{Name: "Supernatural", Icon: "✨", Color: lipgloss.Color("#bd93f9")},
+	{Name: "Action", Slug: "action", Icon: "⚔️", Color: styles.ColorError},
+	{Name: "Romance", Slug: "romance", Icon: "💕", Color: styles.ColorSecondary},
+	{Name: "Comedy", Slug: "comedy", Icon: "😄", Color: styles.ColorWarning},
+	{Name: "Fantasy", Slug: "fantasy", Icon: "🧙", Color: styles.ColorPrimary},
+	{Name: "Horror", Slug: "horror", Icon: "👻", Color: styles.ColorDim},
+	{Name: "Sci-Fi", Slug: "sci-fi", Icon: "🚀", Color: styles.ColorSuccess},
+	{Name: "Slice of Life", Slug: "slice-of-life", Icon: "🏠", Color: lipgloss.Color("#8be9fd")},
+	{Name: "Sports", Slug: "sports", Icon: "⚽", Color: lipgloss.Color("#ffb86c")},
+	{Name: "Mystery", Slug: "mystery", Icon: "🔍", Color: lipgloss.Color("#f1fa8c")},
+	{Name: "Adventure", Slug: "adventure", Icon: "🗺️", Color: lipgloss.Color("#50fa7b")},
+	{Name: "Drama", Slug: "drama", Icon: "🎭", Color: lipgloss.Color("#ff79c6")},
+	{Name: "Supernatural", Slug: "supernatural", Icon: "✨", Color: lipgloss.Color("#bd93f9")},
+	{Name: "Isekai", Slug: "isekai", Icon: "🌀", Color: lipgloss.Color("#8be9fd")},
+	{Name: "Mecha", Slug: "mecha", Icon: "🤖", Color: styles.ColorDim},
+	{Name: "Thriller", Slug: "thriller", Icon: "🔪", Color: styles.ColorError},
+}
+
+// newCategory turns a genre from the API into a grid card; genres added
+// after seeding get a plain book icon
+func newCategory(g models.GenreWithCount) Category {
+	cat := Category{Name: g.Name, Slug: g.Slug, Count: g.MangaCount, Icon: "📖", Color: styles.ColorPrimary}
+	if i := slices.IndexFunc(Categories, func(c Category) bool { return c.Slug == g.Slug }); i >= 0 {
+		cat.Icon, cat.Color = Categories[i].Icon, Categories[i].Color
+	}
+	return cat
 }
 
 // =====================================
@@ -76,17 +96,39 @@ type BrowseModel struct {
 	// Theme
 	theme *styles.Theme
 
-	// Selection
+	// Genres from the server; the grid shows those passing the filter
+	categories    []Category
+	genresLoading bool
+	genresError   error
+
+	// filter narrows the grid by name; hideEmpty drops genres without manga
+	filter    textinput.Model
+	hideEmpty bool
+
+	// marked holds genre slugs picked with space; results match any of them
+	marked map[string]bool
+
+	// Selection: selectedCategory indexes the visible grid
 	selectedCategory int
 	selectedManga    int
+	resultsScroll    listScroll
 
 	// Grid configuration
 	columns int
 
-	// Results for selected category
+	// Results for resultsKey (the slugs they were loaded for)
 	categoryResults []models.Manga
+	resultsKey      string
+	resultsTotal    int
 	loading         bool
 
+	// Sort: index into models.MangaSortFields and asc/desc
+	sortIndex int
+	sortOrder string
+
+	// lastRandomID is the manga the random pick opened last, skipped next time
+	lastRandomID string
+
 	// Components
 	spinner spinner.Model
 
@@ -101,10 +143,17 @@ type BrowseModel struct {
 // MESSAGES
 // =====================================
 
+// BrowseGenresLoadedMsg carries the genres with their manga counts
+type BrowseGenresLoadedMsg struct {
+	Genres []models.GenreWithCount
+	Error  error
+}
+
 // BrowseCategoryLoadedMsg signals category manga loaded
 type BrowseCategoryLoadedMsg struct {
-	Category string
-	Results  []models.Manga
+	Key     string // comma-joined genre slugs the results are for
+	Results []models.Manga
+	Total   int
 }
 
 // BrowseErrorMsg signals an error
@@ -112,6 +161,12 @@ type BrowseErrorMsg struct {
 	Error error
 }
 
+// RandomMangaMsg carries a random pick; the app opens it in the detail view
+type RandomMangaMsg struct {
+	Manga *models.Manga
+	Error error
+}
+
 // =====================================
 // CONSTRUCTOR
 // =====================================
@@ -122,13 +177,27 @@ func NewBrowse() BrowseModel {
 	s.Spinner = spinner.Dot
 	s.Style = styles.DefaultTheme.Spinner
 
+	ti := textinput.New()
+	ti.Placeholder = "Filter genres..."
+	ti.CharLimit = 50
+	ti.Width = 30
+	ti.Prompt = "Genre: "
+	ti.PromptStyle = styles.DefaultTheme.Primary
+	ti.TextStyle = styles.DefaultTheme.Description
+	ti.PlaceholderStyle = styles.DefaultTheme.DimText
+
 	return BrowseModel{
 		theme:            styles.DefaultTheme,
 		spinner:          s,
 		client:           api.GetClient(),
+		filter:           ti,
+		marked:           make(map[string]bool),
+		genresLoading:    true,
 		columns:          4,
 		selectedCategory: 0,
 		categoryResults:  []models.Manga{},
+		sortIndex:        0,
+		sortOrder:        models.DefaultMangaSortOrder(models.MangaSortFields[0]),
 	}
 }
 
@@ -136,27 +205,47 @@ func NewBrowse() BrowseModel {
 // BUBBLE TEA INTERFACE
 // =====================================
 
-// Init initializes the browse view
+// Init initializes the browse view; the first genre's manga load once the
+// genres arrive
 func (m BrowseModel) Init() tea.Cmd {
 	return tea.Batch(
 		m.spinner.Tick,
-		m.loadCategoryManga(Categories[0].Name),
+		m.loadGenres,
 	)
 }
 
-// loadCategoryManga loads manga for a category
-func (m BrowseModel) loadCategoryManga(category string) tea.Cmd {
+// loadGenres fetches the genre grid with manga counts
+func (m BrowseModel) loadGenres() tea.Msg {
+	genres, err := m.client.ListGenres(context.Background())
+	return BrowseGenresLoadedMsg{Genres: genres, Error: err}
+}
+
+// loadCategoryManga loads the manga of the marked genres, or else the
+// highlighted one, through the server's genre filter
+func (m BrowseModel) loadCategoryManga() tea.Cmd {
+	slugs := m.selectedSlugs()
+	if len(slugs) == 0 {
+		return nil
+	}
+	key := strings.Join(slugs, ",")
+	sortBy, order := m.sortField(), m.sortOrder
+	client := m.client
 	return func() tea.Msg {
-		ctx := context.Background()
-		// Search by genre - the API will match genres in the genres JSON array
-		results, _, err := m.client.SearchMangaByGenre(ctx, category, 1, 20)
+		results, total, err := client.SearchMangaByGenre(context.Background(), slugs, sortBy, order, 1, 20)
 		if err != nil {
 			return BrowseErrorMsg{Error: err}
 		}
-		return BrowseCategoryLoadedMsg{
-			Category: category,
-			Results:  results,
-		}
+		return BrowseCategoryLoadedMsg{Key: key, Results: results, Total: total}
+	}
+}
+
+// randomManga picks a random manga, never the one picked just before
+// (unless it is the only one in the category)
+func (m BrowseModel) randomManga(genre string) tea.Cmd {
+	exclude := m.lastRandomID
+	return func() tea.Msg {
+		manga, err := m.client.RandomManga(context.Background(), genre, "", exclude)
+		return RandomMangaMsg{Manga: manga, Error: err}
 	}
 }
 
@@ -175,8 +264,13 @@ func (m BrowseModel) Update(msg tea.Msg) (BrowseModel, tea.Cmd) {
 		}
 
 	case tea.KeyMsg:
+		if m.filter.Focused() {
+			return m.updateFilter(msg)
+		}
+
 		// Calculate grid navigation
-		rows := (len(Categories) + m.columns - 1) / m.columns
+		visible := m.visibleCategories()
+		rows := (len(visible) + m.columns - 1) / m.columns
 		currentRow := m.selectedCategory / m.columns
 		currentCol := m.selectedCategory % m.columns
 
@@ -194,7 +288,7 @@ func (m BrowseModel) Update(msg tea.Msg) (BrowseModel, tea.Cmd) {
 		case "right", "l":
 			if m.selectedManga >= 0 {
 				// Already in results
-			} else if currentCol < m.columns-1 && m.selectedCategory < len(Categories)-1 {
+			} else if currentCol < m.columns-1 && m.selectedCategory < len(visible)-1 {
 				m.selectedCategory++
 			}
 		case "up", "k":
@@ -218,7 +312,7 @@ func (m BrowseModel) Update(msg tea.Msg) (BrowseModel, tea.Cmd) {
 				}
 			} else if currentRow < rows-1 {
 				newIdx := m.selectedCategory + m.columns
-				if newIdx < len(Categories) {
+				if newIdx < len(visible) {
 					m.selectedCategory = newIdx
 				}
 			}
@@ -228,10 +322,42 @@ func (m BrowseModel) Update(msg tea.Msg) (BrowseModel, tea.Cmd) {
 				// Will be handled by parent
 			} else {
 				// Load category and enter results mode
-				m.loading = true
-				m.selectedManga = 0
-				cmds = append(cmds, m.loadCategoryManga(Categories[m.selectedCategory].Name))
+				if cmd := m.loadCategoryManga(); cmd != nil {
+					m.loading = true
+					m.selectedManga = 0
+					cmds = append(cmds, cmd)
+				}
+			}
+		case " ":
+			// Mark the highlighted genre; results match any marked genre
+			if cat := m.GetSelectedCategory(); cat != nil && m.selectedManga < 0 {
+				if m.marked[cat.Slug] {
+					delete(m.marked, cat.Slug)
+				} else {
+					m.marked[cat.Slug] = true
+				}
+				if cmd := m.loadCategoryManga(); cmd != nil {
+					m.loading = true
+					cmds = append(cmds, cmd)
+				}
+			}
+		case "n":
+			// Clear the marked genres
+			if len(m.marked) > 0 {
+				m.marked = make(map[string]bool)
+				if cmd := m.loadCategoryManga(); cmd != nil {
+					m.loading = true
+					cmds = append(cmds, cmd)
+				}
 			}
+		case "f":
+			// Filter the grid by name (/ is the global search)
+			m.selectedManga = -1
+			return m, m.filter.Focus()
+		case "H":
+			// Hide or show genres without manga
+			m.hideEmpty = !m.hideEmpty
+			m = m.clampCategory()
 		case "esc":
 			if m.selectedManga >= 0 {
 				m.selectedManga = -1 // Back to categories
@@ -241,10 +367,61 @@ func (m BrowseModel) Update(msg tea.Msg) (BrowseModel, tea.Cmd) {
 			if len(m.categoryResults) > 0 && m.selectedManga < 0 {
 				m.selectedManga = 0
 			}
+		case "o":
+			// Cycle the sort field; each field starts in its natural order
+			m.sortIndex = (m.sortIndex + 1) % len(models.MangaSortFields)
+			m.sortOrder = models.DefaultMangaSortOrder(m.sortField())
+			if cmd := m.loadCategoryManga(); cmd != nil {
+				m.loading = true
+				cmds = append(cmds, cmd)
+			}
+		case "z":
+			// Random manga from the highlighted category
+			if cat := m.GetSelectedCategory(); cat != nil {
+				cmds = append(cmds, m.randomManga(cat.Slug))
+			}
+		case "Z":
+			// Random manga from any category
+			cmds = append(cmds, m.randomManga(""))
+		case "O":
+			// Flip the sort order
+			if m.sortOrder == models.SortAsc {
+				m.sortOrder = models.SortDesc
+			} else {
+				m.sortOrder = models.SortAsc
+			}
+			if cmd := m.loadCategoryManga(); cmd != nil {
+				m.loading = true
+				cmds = append(cmds, cmd)
+			}
+		}
+
+	case BrowseGenresLoadedMsg:
+		m.genresLoading = false
+		m.genresError = msg.Error
+		if msg.Error != nil {
+			break
+		}
+		m.categories = make([]Category, len(msg.Genres))
+		for i, g := range msg.Genres {
+			m.categories[i] = newCategory(g)
+		}
+		m = m.clampCategory()
+		if m.resultsKey == "" {
+			if cmd := m.loadCategoryManga(); cmd != nil {
+				m.loading = true
+				cmds = append(cmds, cmd)
+			}
 		}
 
 	case BrowseCategoryLoadedMsg:
+		// Drop results for genres the user has already moved away from
+		if msg.Key != strings.Join(m.selectedSlugs(), ",") {
+			break
+		}
 		m.categoryResults = msg.Results
+		m.resultsKey = msg.Key
+		m.resultsTotal = msg.Total
 		m.loading = false
 		if len(m.categoryResults) > 0 {
 			m.selectedManga = 0
@@ -254,15 +431,29 @@ func (m BrowseModel) Update(msg tea.Msg) (BrowseModel, tea.Cmd) {
 		m.lastError = msg.Error
 		m.loading = false
 
+	case RandomMangaMsg:
+		if msg.Manga != nil {
+			m.lastRandomID = msg.Manga.ID
+		}
+
 	case spinner.TickMsg:
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
 		cmds = append(cmds, cmd)
 	}
 
+	m.resultsScroll.follow(m.selectedManga, len(m.categoryResults), m.resultRows())
 	return m, tea.Batch(cmds...)
 }
 
+// resultRows is how many result rows fit under the category grid
+// (cards are five lines tall) with the headers, borders, scroll and cover lines
+func (m BrowseModel) resultRows() int {
+	columns := max(m.columns, 1)
+	gridRows := (len(m.visibleCategories()) + columns - 1) / columns
+	return m.height - 12 - gridRows*5
+}
+
 // View renders the browse view
 func (m BrowseModel) View() string {
 	var sections []string
@@ -296,19 +487,35 @@ func (m BrowseModel) renderCategoryGrid() string {
 		cardWidth = 14
 	}
 
-	for i, cat := range Categories {
+	if m.genresLoading {
+		return m.theme.DimText.Render("Loading genres... " + m.spinner.View())
+	}
+	if m.genresError != nil {
+		return m.theme.Error.Render(fmt.Sprintf("Couldn't load genres: %v", m.genresError))
+	}
+
+	filterLine := m.filter.View()
+	if !m.filter.Focused() {
+		filterLine = m.theme.DimText.Render("(f: filter  space: mark  n: clear marks  H: hide empty)")
+	}
+
+	visible := m.visibleCategories()
+	if len(visible) == 0 {
+		return filterLine + "\n" + m.theme.DimText.Render("No genre matches the filter")
+	}
+	for i, cat := range visible {
 		card := m.renderCategoryCard(cat, i == m.selectedCategory, cardWidth)
 		currentRow = append(currentRow, card)
 
 		// Start new row
-		if len(currentRow) >= m.columns || i == len(Categories)-1 {
+		if len(currentRow) >= m.columns || i == len(visible)-1 {
 			row := lipgloss.JoinHorizontal(lipgloss.Top, currentRow...)
 			rows = append(rows, row)
 			currentRow = []string{}
 		}
 	}
 
-	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+	return filterLine + "\n" + lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
 
 func (m BrowseModel) renderCategoryCard(cat Category, selected bool, width int) string {
@@ -335,30 +542,36 @@ func (m BrowseModel) renderCategoryCard(cat Category, selected bool, width int)
 
 	// Card content
 	icon := lipgloss.NewStyle().Foreground(cat.Color).Render(cat.Icon)
-	name := lipgloss.NewStyle().Foreground(lipgloss.Color("#f8f8f2")).Bold(true).Render(cat.Name)
+	label := cat.Name
+	if m.marked[cat.Slug] {
+		label = "✓ " + label
+	}
+	name := lipgloss.NewStyle().Foreground(lipgloss.Color("#f8f8f2")).Bold(true).Render(label)
+	count := m.theme.DimText.Render(fmt.Sprintf("%d manga", cat.Count))
 
-	content := icon + "\n" + name
+	content := icon + "\n" + name + "\n" + count
 	return style.Render(content)
 }
 
 func (m BrowseModel) renderCategoryResults() string {
-	if m.selectedCategory >= len(Categories) {
+	cat := m.GetSelectedCategory()
+	if cat == nil {
 		return ""
 	}
-
-	cat := Categories[m.selectedCategory]
+	title := strings.ToUpper(m.selectionTitle())
 
 	// Header
 	var headerText string
 	if m.loading {
-		headerText = fmt.Sprintf("LOADING %s... %s", strings.ToUpper(cat.Name), m.spinner.View())
+		headerText = fmt.Sprintf("LOADING %s... %s", title, m.spinner.View())
 	} else if len(m.categoryResults) > 0 {
-		headerText = fmt.Sprintf("TRENDING IN %s", strings.ToUpper(cat.Name))
+		headerText = fmt.Sprintf("%s · %d manga · sort: %s", title, m.resultsTotal, m.sortLabel())
 	} else {
-		headerText = fmt.Sprintf("NO MANGA FOUND IN %s", strings.ToUpper(cat.Name))
+		headerText = fmt.Sprintf("NO MANGA FOUND IN %s", title)
 	}
 
-	header := m.theme.PanelHeader.Render(headerText)
+	header := m.theme.PanelHeader.Render(headerText) + "  " +
+		m.theme.DimText.Render("(o: sort  O: order  z: random)")
 
 	if len(m.categoryResults) == 0 {
 		return header
@@ -372,16 +585,24 @@ func (m BrowseModel) renderCategoryResults() string {
 		Padding(0, 1)
 
 	var rows []string
-	maxVisible := min(5, len(m.categoryResults))
-
-	for i := 0; i < maxVisible; i++ {
+	start, end := m.resultsScroll.window(m.selectedManga, len(m.categoryResults), m.resultRows())
+	for i := start; i < end; i++ {
 		manga := m.categoryResults[i]
 		row := m.renderResultRow(manga, i, i == m.selectedManga)
 		rows = append(rows, row)
 	}
+	if status := scrollStatus(start, end, len(m.categoryResults)); status != "" {
+		rows = append(rows, m.theme.DimText.Render("  "+status))
+	}
 
 	list := lipgloss.JoinVertical(lipgloss.Left, rows...)
-	return header + "\n" + listStyle.Render(list)
+	result := header + "\n" + listStyle.Render(list)
+
+	// Cover image URL of the highlighted manga
+	if selected := m.GetSelectedManga(); selected != nil && selected.CoverURL != "" {
+		result += "\n" + m.theme.DimText.Render("Cover: "+selected.CoverURL)
+	}
+	return result
 }
 
 func (m BrowseModel) renderResultRow(manga models.Manga, rank int, selected bool) string {
@@ -416,7 +637,48 @@ func (m BrowseModel) renderResultRow(manga models.Manga, rank int, selected bool
 	}
 	authorText := m.theme.DimText.Render(fmt.Sprintf("%-15s", author))
 
-	return selector + rankBadge + "  " + titleText + "  " + authorText
+	row := selector + rankBadge + "  " + titleText + "  " + authorText + "  " +
+		m.theme.DimText.Render(m.sortValue(manga))
+	if manga.RecentlyCompleted(time.Now()) {
+		row += "  " + m.theme.Success.Render("✅ Recently completed")
+	}
+	return row
+}
+
+// sortField is the active models.MangaSortFields entry
+func (m BrowseModel) sortField() string {
+	return models.MangaSortFields[m.sortIndex]
+}
+
+// sortLabel renders the sort indicator, e.g. "⭐ rating ↓"
+func (m BrowseModel) sortLabel() string {
+	icons := map[string]string{
+		models.MangaSortRating:   "⭐",
+		models.MangaSortYear:     "📅",
+		models.MangaSortChapters: "📖",
+		models.MangaSortTitle:    "🔤",
+	}
+	arrow := "↓"
+	if m.sortOrder == models.SortAsc {
+		arrow = "↑"
+	}
+	return fmt.Sprintf("%s %s %s", icons[m.sortField()], m.sortField(), arrow)
+}
+
+// sortValue is the value a result row is ordered by, shown beside it
+func (m BrowseModel) sortValue(manga models.Manga) string {
+	switch m.sortField() {
+	case models.MangaSortRating:
+		return fmt.Sprintf("⭐ %.1f", manga.AverageRating)
+	case models.MangaSortYear:
+		if manga.Year == 0 {
+			return "📅 —"
+		}
+		return fmt.Sprintf("📅 %d", manga.Year)
+	case models.MangaSortChapters:
+		return fmt.Sprintf("📖 %d ch", manga.TotalChapters)
+	}
+	return ""
 }
 
 // =====================================
@@ -431,14 +693,99 @@ func (m BrowseModel) GetSelectedManga() *models.Manga {
 	return nil
 }
 
-// GetSelectedCategory returns the selected category
+// GetSelectedCategory returns the highlighted category, nil while the grid is empty
 func (m BrowseModel) GetSelectedCategory() *Category {
-	if m.selectedCategory < len(Categories) {
-		return &Categories[m.selectedCategory]
+	visible := m.visibleCategories()
+	if m.selectedCategory < len(visible) {
+		return &visible[m.selectedCategory]
 	}
 	return nil
 }
 
+// IsInputFocused reports whether the genre filter is being typed in
+func (m BrowseModel) IsInputFocused() bool {
+	return m.filter.Focused()
+}
+
+// updateFilter handles typing in the genre filter; enter keeps the filter,
+// esc clears it
+func (m BrowseModel) updateFilter(msg tea.KeyMsg) (BrowseModel, tea.Cmd) {
+	switch msg.String() {
+	case "enter":
+		m.filter.Blur()
+		return m, nil
+	case "esc":
+		m.filter.Blur()
+		m.filter.SetValue("")
+		return m.clampCategory(), nil
+	}
+	var cmd tea.Cmd
+	m.filter, cmd = m.filter.Update(msg)
+	m.selectedCategory = 0
+	return m, cmd
+}
+
+// visibleCategories are the genres passing the name filter and hide-empty
+// toggle; the full list is small enough to filter here
+func (m BrowseModel) visibleCategories() []Category {
+	query := strings.ToLower(strings.TrimSpace(m.filter.Value()))
+	var visible []Category
+	for _, cat := range m.categories {
+		if m.hideEmpty && cat.Count == 0 {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(cat.Name), query) {
+			continue
+		}
+		visible = append(visible, cat)
+	}
+	return visible
+}
+
+// clampCategory keeps the highlight inside the visible grid
+func (m BrowseModel) clampCategory() BrowseModel {
+	if n := len(m.visibleCategories()); m.selectedCategory >= n {
+		m.selectedCategory = max(n-1, 0)
+	}
+	return m
+}
+
+// selectedSlugs are the genres results load for: the marked ones in grid
+// order, or else the highlighted one
+func (m BrowseModel) selectedSlugs() []string {
+	var slugs []string
+	for _, cat := range m.categories {
+		if m.marked[cat.Slug] {
+			slugs = append(slugs, cat.Slug)
+		}
+	}
+	if len(slugs) == 0 {
+		if cat := m.GetSelectedCategory(); cat != nil {
+			slugs = append(slugs, cat.Slug)
+		}
+	}
+	return slugs
+}
+
+// selectionTitle names the genres the results are for, e.g. "Action + Horror"
+func (m BrowseModel) selectionTitle() string {
+	names := make(map[string]string, len(m.categories))
+	for _, cat := range m.categories {
+		names[cat.Slug] = cat.Name
+	}
+	var parts []string
+	for _, slug := range m.selectedSlugs() {
+		parts = append(parts, names[slug])
+	}
+	return strings.Join(parts, " + ")
+}
+
+// SetTheme switches the view to a new theme
+func (m *BrowseModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
+}
+
 // SetWidth sets the view width
 func (m *BrowseModel) SetWidth(w int) {
 	m.width = w
@@ -451,6 +798,7 @@ func (m *BrowseModel) SetWidth(w int) {
 // SetHeight sets the view height
 func (m *BrowseModel) SetHeight(h int) {
 	m.height = h
+	m.resultsScroll.follow(m.selectedManga, len(m.categoryResults), m.resultRows())
 }
 
 func max(a, b int) int {
@@ -1,10 +1,12 @@
 // Package views - Chat View Component
 // Real-time chat interface with message history and input
-// Integrates with WebSocket for live messaging
+// Integrates with WebSocket for live messaging, typing indicators and presence
 package views
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"strings"
 	"time"
 
@@ -12,6 +14,8 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"mangahub/internal/tui/api"
 )
 
 // =====================================
@@ -106,6 +110,10 @@ var (
 	userCountStyle = lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#00D4FF")).
 			Bold(true)
+
+	typingIndicatorStyle = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("#888888")).
+				Italic(true)
 )
 
 // =====================================
@@ -122,6 +130,15 @@ const (
 	StatusReconnecting
 )
 
+const (
+	// typingSendInterval throttles outgoing typing events (hub debounces at 3s)
+	typingSendInterval = 2 * time.Second
+	// typingIndicatorTTL is how long "X is typing…" stays visible without a new event
+	typingIndicatorTTL = 4 * time.Second
+	// chatHistoryPageSize is how many messages each history request loads
+	chatHistoryPageSize = 50
+)
+
 // ChatModel is the Bubble Tea model for chat view
 type ChatModel struct {
 	messages  []ChatMessage
@@ -134,11 +151,21 @@ type ChatModel struct {
 	userID    string
 	username  string
 	userCount int
+	members   []string
 	status    ConnectionStatus
 	width     int
 	height    int
 	focused   bool
 	ready     bool
+
+	// Typing indicators: username → last typing event
+	typingUsers    map[string]time.Time
+	lastTypingSent time.Time
+
+	// Message history paging
+	historyLoading bool
+	historyHasMore bool
+	historyErr     error
 }
 
 // NewChatModel creates a new chat model
@@ -163,6 +190,8 @@ func NewChatModel() ChatModel {
 		status:    StatusDisconnected,
 		focused:   true,
 		userCount: 0,
+
+		typingUsers: make(map[string]time.Time),
 	}
 }
 
@@ -187,7 +216,18 @@ func (m ChatModel) Update(msg tea.Msg) (ChatModel, tea.Cmd) {
 		m.updateDimensions()
 
 	case tea.KeyMsg:
+		// Scrolling past the top pages back through history
+		if msg.String() == "pgup" || (!m.focused && (msg.String() == "up" || msg.String() == "k")) {
+			if cmd := m.loadOlderHistory(); cmd != nil {
+				cmds = append(cmds, cmd)
+			}
+		}
+
 		switch msg.String() {
+		case "ctrl+o":
+			// Open the rooms directory to switch rooms
+			return m, func() tea.Msg { return ShowRoomsMsg{} }
+
 		case "enter":
 			if m.status == StatusConnected && strings.TrimSpace(m.textarea.Value()) != "" {
 				// Return command to send message
@@ -212,9 +252,43 @@ func (m ChatModel) Update(msg tea.Msg) (ChatModel, tea.Cmd) {
 				m.focused = true
 				return m, textarea.Blink
 			}
+
+		default:
+			// Let the room know we're typing, throttled client-side
+			if m.focused && m.status == StatusConnected && time.Since(m.lastTypingSent) >= typingSendInterval {
+				m.lastTypingSent = time.Now()
+				roomID := m.roomID
+				cmds = append(cmds, func() tea.Msg {
+					return ChatTypingMsg{RoomID: roomID}
+				})
+			}
 		}
 
 	case ChatMessageReceivedMsg:
+		switch msg.Type {
+		case "typing":
+			if msg.UserID != m.userID {
+				m.typingUsers[msg.Username] = time.Now()
+				return m, tea.Tick(typingIndicatorTTL, func(time.Time) tea.Msg {
+					return chatTypingExpiredMsg{}
+				})
+			}
+			return m, nil
+		case "presence":
+			m.members = msg.Members
+			m.userCount = len(msg.Members)
+			return m, nil
+		case "replay":
+			m.mergeReplay(msg.Replayed)
+			return m, nil
+		case "resync":
+			// Missed too much to replay; reload the latest page instead
+			return m, m.LoadHistory()
+		}
+
+		// A sent message ends that user's typing
+		delete(m.typingUsers, msg.Username)
+
 		// Add message to history
 		m.messages = append(m.messages, ChatMessage{
 			ID:        msg.ID,
@@ -236,17 +310,76 @@ func (m ChatModel) Update(msg tea.Msg) (ChatModel, tea.Cmd) {
 		m.mangaID = msg.MangaID
 		m.mangaName = msg.MangaName
 		m.userCount = msg.UserCount
+		m.members = nil
+		m.typingUsers = make(map[string]time.Time)
 		m.status = StatusConnected
 		// Clear old messages
 		m.messages = make([]ChatMessage, 0)
 		m.updateViewportContent()
 
+	case ChatHistoryLoadedMsg:
+		// Ignore pages for a room we already left
+		if msg.RoomID != m.roomID {
+			return m, nil
+		}
+		m.historyLoading = false
+		m.historyErr = msg.Err
+		if msg.Err != nil {
+			m.updateViewportContent()
+			return m, nil
+		}
+
+		m.historyHasMore = msg.Page.HasMore
+		history := make([]ChatMessage, 0, len(msg.Page.Messages))
+		for _, hm := range msg.Page.Messages {
+			history = append(history, ChatMessage{
+				ID:        hm.ID,
+				RoomID:    hm.RoomID,
+				UserID:    hm.UserID,
+				Username:  hm.Username,
+				Content:   hm.Content,
+				Type:      "text",
+				Timestamp: hm.CreatedAt,
+				IsOwn:     hm.UserID == m.userID,
+			})
+		}
+
+		if msg.BeforeID == "" {
+			// Latest page: keep live messages that arrived after the newest stored one
+			var newest time.Time
+			if len(history) > 0 {
+				newest = history[len(history)-1].Timestamp
+			}
+			for _, live := range m.messages {
+				if live.Timestamp.After(newest) {
+					history = append(history, live)
+				}
+			}
+			m.messages = history
+			m.updateViewportContent()
+			m.viewport.GotoBottom()
+		} else {
+			// Older page: prepend and keep the previous top message in view
+			m.messages = append(history, m.messages...)
+			m.updateViewportContent()
+			m.viewport.SetYOffset(len(history))
+		}
+		return m, nil
+
 	case ChatConnectionStatusMsg:
 		m.status = msg.Status
 		m.userCount = msg.UserCount
 
 	case ChatUserCountMsg:
 		m.userCount = msg.Count
+
+	case chatTypingExpiredMsg:
+		// Drop stale typing entries; the view re-renders on this message
+		for name, at := range m.typingUsers {
+			if time.Since(at) >= typingIndicatorTTL {
+				delete(m.typingUsers, name)
+			}
+		}
 	}
 
 	// Update textarea if focused
@@ -323,9 +456,35 @@ func (m ChatModel) renderHeader() string {
 		statusIndicator,
 	)
 
+	if typing := m.typingText(); typing != "" {
+		header += "  " + typingIndicatorStyle.Render(typing)
+	}
+
 	return chatHeaderStyle.Width(m.width).Render(header)
 }
 
+// typingText describes who is currently typing, e.g. "bob is typing…"
+func (m ChatModel) typingText() string {
+	var names []string
+	for name, at := range m.typingUsers {
+		if time.Since(at) < typingIndicatorTTL {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	switch len(names) {
+	case 0:
+		return ""
+	case 1:
+		return names[0] + " is typing…"
+	case 2:
+		return names[0] + " and " + names[1] + " are typing…"
+	default:
+		return fmt.Sprintf("%d people are typing…", len(names))
+	}
+}
+
 func (m ChatModel) renderMessages() string {
 	// Create viewport border
 	viewportStyle := lipgloss.NewStyle().
@@ -357,9 +516,9 @@ func (m ChatModel) renderInput() string {
 	if m.status != StatusConnected {
 		hint = inputHintStyle.Render("  ⚠ Connection required to send messages")
 	} else if m.focused {
-		hint = inputHintStyle.Render("  Enter: Send • Esc: Unfocus • Tab: Focus input")
+		hint = inputHintStyle.Render("  Enter: Send • Esc: Unfocus • Tab: Focus input • Ctrl+O: Rooms")
 	} else {
-		hint = inputHintStyle.Render("  Tab: Focus input • Esc: Back")
+		hint = inputHintStyle.Render("  Tab: Focus input • Ctrl+O: Rooms • Esc: Back")
 	}
 
 	return input + "\n" + hint
@@ -387,8 +546,21 @@ func (m *ChatModel) updateViewportContent() {
 
 	var lines []string
 
-	if len(m.messages) == 0 {
-		// Empty state
+	switch {
+	case m.historyLoading && len(m.messages) > 0:
+		lines = append(lines, systemMessageStyle.Width(m.viewport.Width).Render("Loading older messages..."))
+	case m.historyHasMore:
+		lines = append(lines, systemMessageStyle.Width(m.viewport.Width).Render("PgUp: older messages"))
+	}
+
+	if len(m.messages) == 0 && m.historyLoading {
+		lines = append(lines, systemMessageStyle.Width(m.viewport.Width).Render("\n\nLoading messages..."))
+	} else if len(m.messages) == 0 {
+		// Empty state, also shown when history could not be loaded
+		if m.historyErr != nil {
+			lines = append(lines, systemMessageStyle.Width(m.viewport.Width).
+				Render("\nCouldn't load message history: "+m.historyErr.Error()))
+		}
 		emptyMsg := lipgloss.NewStyle().
 			Foreground(lipgloss.Color("#666666")).
 			Italic(true).
@@ -419,6 +591,10 @@ func (m ChatModel) formatMessage(msg ChatMessage) string {
 	case "system":
 		return systemMessageStyle.Width(m.viewport.Width).Render(msg.Content)
 
+	case "error":
+		// Only this client sees it: the server rejected our last message
+		return leaveMessageStyle.Render("  ⚠ " + msg.Content)
+
 	default: // "text" or empty
 		var usernameRender string
 		if msg.IsOwn {
@@ -452,13 +628,110 @@ func (m *ChatModel) SetUser(userID, username string) {
 }
 
 // SetRoom sets the current room info
+// Switching to a different room drops the previous room's messages
 func (m *ChatModel) SetRoom(roomID, roomName, mangaID, mangaName string) {
+	if roomID != m.roomID {
+		m.messages = make([]ChatMessage, 0)
+		m.historyHasMore = false
+		m.historyErr = nil
+	}
 	m.roomID = roomID
 	m.roomName = roomName
 	m.mangaID = mangaID
 	m.mangaName = mangaName
 }
 
+// LoadHistory fetches the latest page of message history for the current room
+func (m *ChatModel) LoadHistory() tea.Cmd {
+	if m.roomID == "" {
+		return nil
+	}
+	m.historyLoading = true
+	m.historyErr = nil
+	m.updateViewportContent()
+	return fetchChatHistory(m.roomID, "")
+}
+
+// loadOlderHistory fetches the page before the oldest loaded message
+// Only fires when the viewport is scrolled to the top and more history exists
+func (m *ChatModel) loadOlderHistory() tea.Cmd {
+	if m.historyLoading || !m.historyHasMore || !m.viewport.AtTop() {
+		return nil
+	}
+	var beforeID string
+	for _, msg := range m.messages {
+		if msg.ID != "" {
+			beforeID = msg.ID
+			break
+		}
+	}
+	if beforeID == "" {
+		return nil
+	}
+	m.historyLoading = true
+	m.updateViewportContent()
+	return fetchChatHistory(m.roomID, beforeID)
+}
+
+// fetchChatHistory loads one page of history older than beforeID
+func fetchChatHistory(roomID, beforeID string) tea.Cmd {
+	return func() tea.Msg {
+		page, err := api.GetClient().GetRoomMessages(context.Background(), roomID, beforeID, chatHistoryPageSize)
+		return ChatHistoryLoadedMsg{RoomID: roomID, BeforeID: beforeID, Page: page, Err: err}
+	}
+}
+
+// LastMessageID returns the ID of the newest stored chat message shown,
+// which a reconnect resumes from; empty when there is none
+func (m ChatModel) LastMessageID() string {
+	for i := len(m.messages) - 1; i >= 0; i-- {
+		msg := m.messages[i]
+		if msg.ID != "" && (msg.Type == "message" || msg.Type == "text") {
+			return msg.ID
+		}
+	}
+	return ""
+}
+
+// mergeReplay adds messages replayed after a reconnect. Any already shown
+// (received live around the reconnect, or loaded with history) are skipped.
+func (m *ChatModel) mergeReplay(replayed []ChatMessageReceivedMsg) {
+	shown := make(map[string]bool, len(m.messages))
+	for _, msg := range m.messages {
+		if msg.ID != "" {
+			shown[msg.ID] = true
+		}
+	}
+
+	added := 0
+	for _, r := range replayed {
+		if r.ID == "" || shown[r.ID] {
+			continue
+		}
+		shown[r.ID] = true
+		m.messages = append(m.messages, ChatMessage{
+			ID:        r.ID,
+			RoomID:    r.RoomID,
+			UserID:    r.UserID,
+			Username:  r.Username,
+			Content:   r.Content,
+			Type:      r.Type,
+			Timestamp: r.Timestamp,
+			IsOwn:     r.UserID == m.userID,
+		})
+		added++
+	}
+	if added == 0 {
+		return
+	}
+	// Replayed messages predate the join notice and anything received since
+	sort.SliceStable(m.messages, func(i, j int) bool {
+		return m.messages[i].Timestamp.Before(m.messages[j].Timestamp)
+	})
+	m.updateViewportContent()
+	m.viewport.GotoBottom()
+}
+
 // SetStatus sets the connection status
 func (m *ChatModel) SetStatus(status ConnectionStatus) {
 	m.status = status
@@ -511,8 +784,30 @@ type ChatMessageReceivedMsg struct {
 	Content   string
 	Type      string
 	Timestamp time.Time
+	Members   []string // presence only
+
+	Replayed []ChatMessageReceivedMsg // replay only: messages missed while disconnected
 }
 
+// ChatTypingMsg is returned when the user is typing in the input
+type ChatTypingMsg struct {
+	RoomID string
+}
+
+// ChatHistoryLoadedMsg carries a page of message history for a room
+type ChatHistoryLoadedMsg struct {
+	RoomID   string
+	BeforeID string // empty for the latest page
+	Page     *api.ChatHistoryResponse
+	Err      error
+}
+
+// chatTypingExpiredMsg prunes stale typing indicators
+type chatTypingExpiredMsg struct{}
+
+// ShowRoomsMsg asks the app to open the rooms directory
+type ShowRoomsMsg struct{}
+
 // ChatRoomJoinedMsg is sent when successfully joined a room
 type ChatRoomJoinedMsg struct {
 	RoomID    string
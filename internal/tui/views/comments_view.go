@@ -1,10 +1,15 @@
 // Package views - Comments View Component
-// Display and post comments for manga
+// Display and post comments for manga, with indented reply threads
+// Lọc theo chapter (f); mở từ Reader thì comment mới được gắn chapter hiện tại
+// Moderators (admin/moderator role) có thể xóa comment của bất kỳ ai
+// Tải thêm trang (cursor) khi cuộn gần cuối; like/xóa cập nhật tại chỗ để không mất vị trí đọc
+// Comment spoiler bị server che trừ khi bật Show Spoilers; s tải lại kèm nội dung spoiler, Ctrl+O đánh dấu spoiler khi viết
 package views
 
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"strings"
 	"time"
 
@@ -19,11 +24,23 @@ import (
 	"mangahub/pkg/models"
 )
 
+// commentsPageSize is how many threads one page loads
+const commentsPageSize = 20
+
+// commentsPrefetchRows is how close to the last row the selection gets before the next page loads
+const commentsPrefetchRows = 3
+
 // CommentsView holds the comments view state
 type CommentsView struct {
 	mangaID       string
 	mangaTitle    string
+	chapter       int    // chapter opened from the reader (0 = none); new comments are tagged with it
+	filter        string // ?chapter= value: "" all, "general", or a chapter number
 	comments      []models.CommentWithReplies
+	rows          []commentRow // comments flattened in display order
+	total         int          // comments matching the filter on the server
+	nextCursor    string       // cursor for the next page, "" once every page is loaded
+	loadingMore   bool
 	viewport      viewport.Model
 	textarea      textarea.Model
 	active        bool
@@ -31,7 +48,14 @@ type CommentsView struct {
 	posting       bool
 	spinner       spinner.Model
 	selectedIndex int
-	composing     bool // Whether user is composing a comment
+	composing     bool        // Whether user is composing a comment
+	spoiler       bool        // the comment being composed is flagged as a spoiler
+	showSpoilers  bool        // user preference: spoiler comments are shown
+	revealed      bool        // spoilers revealed with s in this view
+	replyTo       *commentRow // Comment being replied to (nil = new top-level comment)
+	userID        string
+	moderator     bool // admin/moderator: may delete any comment
+	confirmDelete bool // waiting for y/n on deleting the selected comment
 	lastError     error
 	client        *api.Client
 	width         int
@@ -39,14 +63,45 @@ type CommentsView struct {
 	theme         *styles.Theme
 }
 
-// CommentsLoadedMsg signals comments were loaded
+// commentRow is one comment in the flattened thread, with its nesting depth
+type commentRow struct {
+	comment models.CommentWithUser
+	depth   int
+}
+
+// flattenComments walks threads depth-first so replies follow their parent
+func flattenComments(comments []models.CommentWithReplies, depth int) []commentRow {
+	var rows []commentRow
+	for _, c := range comments {
+		rows = append(rows, commentRow{comment: c.CommentWithUser, depth: depth})
+		rows = append(rows, flattenComments(c.Replies, depth+1)...)
+	}
+	return rows
+}
+
+// CommentsLoadedMsg signals a page of comments was loaded.
+// Cursor is the one the page was requested with ("" for the first page).
 type CommentsLoadedMsg struct {
+	Filter   string
+	Cursor   string
 	Comments []models.CommentWithReplies
+	Total    int
+	Next     string
 }
 
 // CommentPostedMsg signals comment was posted
 type CommentPostedMsg struct{}
 
+// CommentLikedMsg signals a comment was liked
+type CommentLikedMsg struct {
+	ID string
+}
+
+// CommentDeletedMsg signals a comment was deleted
+type CommentDeletedMsg struct {
+	ID string
+}
+
 // CommentsErrorMsg signals an error
 type CommentsErrorMsg struct {
 	Error error
@@ -84,6 +139,70 @@ func NewCommentsView(mangaID, mangaTitle string) CommentsView {
 	}
 }
 
+// SetUser sets the logged-in user so own comments (or all, for moderators) can be deleted
+func (m *CommentsView) SetUser(userID, role string) {
+	m.userID = userID
+	m.moderator = models.IsModerator(role)
+}
+
+// SetChapter scopes the view to a chapter's discussion and tags new comments with it
+func (m *CommentsView) SetChapter(chapter int) {
+	m.chapter = chapter
+	m.filter = strconv.Itoa(chapter)
+}
+
+// SetShowSpoilers applies the user's spoiler preference
+func (m *CommentsView) SetShowSpoilers(show bool) {
+	m.showSpoilers = show
+	m.viewport.SetContent(m.renderCommentsList())
+}
+
+// spoilersShown reports whether spoiler comments are displayed and fetched unmasked
+func (m CommentsView) spoilersShown() bool {
+	return m.showSpoilers || m.revealed
+}
+
+// masked reports whether a comment's text is hidden behind the spoiler notice.
+// Own comments are never masked, matching the server.
+func (m CommentsView) masked(c models.CommentWithUser) bool {
+	if c.SpoilerMasked {
+		return true
+	}
+	return c.IsSpoiler && !c.IsDeleted && c.UserID != m.userID && !m.spoilersShown()
+}
+
+// nextFilter cycles all → general → current chapter (when known) → all
+func (m CommentsView) nextFilter() string {
+	switch m.filter {
+	case "":
+		return models.CommentChapterGeneral
+	case models.CommentChapterGeneral:
+		if m.chapter > 0 {
+			return strconv.Itoa(m.chapter)
+		}
+	}
+	return ""
+}
+
+// filterLabel describes the active chapter filter
+func (m CommentsView) filterLabel() string {
+	switch m.filter {
+	case "":
+		return "All chapters"
+	case models.CommentChapterGeneral:
+		return "General"
+	}
+	return "Chapter " + m.filter
+}
+
+// canDelete reports whether the current user may delete the comment
+func (m CommentsView) canDelete(c models.CommentWithUser) bool {
+	if c.IsDeleted || m.userID == "" {
+		return false
+	}
+	return m.moderator || c.UserID == m.userID
+}
+
 // Init initializes the view
 func (m CommentsView) Init() tea.Cmd {
 	return tea.Batch(
@@ -92,18 +211,40 @@ func (m CommentsView) Init() tea.Cmd {
 	)
 }
 
-// loadComments loads comments from API
+// loadComments loads the newest page from API, replacing what is shown
 func (m CommentsView) loadComments() tea.Cmd {
+	return m.loadPage("")
+}
+
+// loadPage loads the page starting after cursor ("" for the newest page)
+func (m CommentsView) loadPage(cursor string) tea.Cmd {
+	client, mangaID, filter, spoilers := m.client, m.mangaID, m.filter, m.spoilersShown()
 	return func() tea.Msg {
 		ctx := context.Background()
-		result, err := m.client.GetComments(ctx, m.mangaID, 1, 50)
+		result, err := client.GetComments(ctx, mangaID, filter, cursor, commentsPageSize, spoilers)
 		if err != nil {
 			return CommentsErrorMsg{Error: err}
 		}
-		return CommentsLoadedMsg{Comments: result.Comments}
+		return CommentsLoadedMsg{
+			Filter:   filter,
+			Cursor:   cursor,
+			Comments: result.Comments,
+			Total:    result.TotalCount,
+			Next:     result.NextCursor,
+		}
 	}
 }
 
+// maybeLoadMore fetches the next page once the selection nears the last loaded row.
+// Older pages continue from a cursor, so comments posted meanwhile don't shift them.
+func (m *CommentsView) maybeLoadMore() tea.Cmd {
+	if m.loadingMore || m.nextCursor == "" || m.selectedIndex < len(m.rows)-commentsPrefetchRows {
+		return nil
+	}
+	m.loadingMore = true
+	return tea.Batch(m.spinner.Tick, m.loadPage(m.nextCursor))
+}
+
 // postComment posts a new comment
 func (m CommentsView) postComment() tea.Cmd {
 	return func() tea.Msg {
@@ -113,7 +254,18 @@ func (m CommentsView) postComment() tea.Cmd {
 			return CommentsErrorMsg{Error: fmt.Errorf("comment cannot be empty")}
 		}
 
-		err := m.client.PostComment(ctx, m.mangaID, content, nil, nil)
+		// Replies inherit their thread's chapter on the server
+		var parentID *string
+		var chapterNum *int
+		if m.replyTo != nil {
+			id := m.replyTo.comment.ID
+			parentID = &id
+		} else if m.chapter > 0 {
+			ch := m.chapter
+			chapterNum = &ch
+		}
+
+		err := m.client.PostComment(ctx, m.mangaID, content, chapterNum, parentID, m.spoiler)
 		if err != nil {
 			return CommentsErrorMsg{Error: err}
 		}
@@ -132,9 +284,15 @@ func (m CommentsView) Update(msg tea.Msg) (CommentsView, tea.Cmd) {
 			switch msg.String() {
 			case "esc":
 				m.composing = false
+				m.spoiler = false
+				m.replyTo = nil
 				m.textarea.Blur()
 				m.textarea.Reset()
 				return m, nil
+			case "ctrl+o":
+				// Flag the comment as a spoiler
+				m.spoiler = !m.spoiler
+				return m, nil
 			case "ctrl+s":
 				// Submit comment
 				m.posting = true
@@ -147,6 +305,13 @@ func (m CommentsView) Update(msg tea.Msg) (CommentsView, tea.Cmd) {
 				m.textarea, cmd = m.textarea.Update(msg)
 				cmds = append(cmds, cmd)
 			}
+		} else if m.confirmDelete {
+			// Waiting for delete confirmation
+			m.confirmDelete = false
+			if msg.String() == "y" && m.selectedIndex >= 0 && m.selectedIndex < len(m.rows) {
+				return m, m.deleteComment(m.rows[m.selectedIndex].comment.ID)
+			}
+			return m, nil
 		} else {
 			// Navigation mode
 			switch msg.String() {
@@ -158,25 +323,72 @@ func (m CommentsView) Update(msg tea.Msg) (CommentsView, tea.Cmd) {
 				if m.selectedIndex < 0 {
 					m.selectedIndex = 0
 				}
+				m.viewport.SetContent(m.renderCommentsList())
 			case "down", "j":
 				m.selectedIndex++
-				if m.selectedIndex >= len(m.comments) {
-					m.selectedIndex = len(m.comments) - 1
+				if m.selectedIndex >= len(m.rows) {
+					m.selectedIndex = len(m.rows) - 1
 				}
+				m.viewport.SetContent(m.renderCommentsList())
+				cmds = append(cmds, m.maybeLoadMore())
 			case "c":
-				// Start composing
+				// Start composing a top-level comment
 				m.composing = true
+				m.replyTo = nil
 				m.textarea.Focus()
 				return m, textarea.Blink
+			case "r":
+				// Reply to the highlighted comment
+				if m.selectedIndex >= 0 && m.selectedIndex < len(m.rows) {
+					row := m.rows[m.selectedIndex]
+					m.composing = true
+					m.replyTo = &row
+					m.textarea.Focus()
+					return m, textarea.Blink
+				}
 			case "l":
 				// Like selected comment
-				if m.selectedIndex >= 0 && m.selectedIndex < len(m.comments) {
-					commentID := m.comments[m.selectedIndex].ID
+				if m.selectedIndex >= 0 && m.selectedIndex < len(m.rows) {
+					commentID := m.rows[m.selectedIndex].comment.ID
 					return m, m.likeComment(commentID)
 				}
-			case "r":
+			case "d":
+				// Delete selected comment (own, or any for moderators)
+				if m.selectedIndex >= 0 && m.selectedIndex < len(m.rows) && m.canDelete(m.rows[m.selectedIndex].comment) {
+					m.confirmDelete = true
+				}
+			case "s":
+				// Reveal spoilers (fetching their text again) or hide them
+				if m.revealed {
+					m.revealed = false
+					m.viewport.SetContent(m.renderCommentsList())
+					return m, nil
+				}
+				m.revealed = true
+				if m.showSpoilers {
+					m.viewport.SetContent(m.renderCommentsList())
+					return m, nil
+				}
+				m.loading = true
+				m.loadingMore = false
+				return m, tea.Batch(
+					m.spinner.Tick,
+					m.loadComments(),
+				)
+			case "f":
+				// Cycle the chapter filter
+				m.filter = m.nextFilter()
+				m.selectedIndex = 0
+				m.loading = true
+				m.loadingMore = false
+				return m, tea.Batch(
+					m.spinner.Tick,
+					m.loadComments(),
+				)
+			case "R":
 				// Refresh comments
 				m.loading = true
+				m.loadingMore = false
 				return m, tea.Batch(
 					m.spinner.Tick,
 					m.loadComments(),
@@ -192,13 +404,42 @@ func (m CommentsView) Update(msg tea.Msg) (CommentsView, tea.Cmd) {
 		m.textarea.SetWidth(msg.Width - 12)
 
 	case CommentsLoadedMsg:
-		m.comments = msg.Comments
-		m.loading = false
+		if msg.Filter != m.filter {
+			return m, nil // filter changed while loading
+		}
+		if msg.Cursor == "" {
+			m.comments = msg.Comments
+			m.rows = flattenComments(msg.Comments, 0)
+			m.loading = false
+		} else {
+			if !m.loadingMore || msg.Cursor != m.nextCursor {
+				return m, nil // a refresh replaced the list meanwhile
+			}
+			m.comments = append(m.comments, msg.Comments...)
+			m.rows = append(m.rows, flattenComments(msg.Comments, 0)...)
+			m.loadingMore = false
+		}
+		m.total = msg.Total
+		m.nextCursor = msg.Next
+		if m.selectedIndex >= len(m.rows) {
+			m.selectedIndex = max(0, len(m.rows)-1)
+		}
+		m.viewport.SetContent(m.renderCommentsList())
+
+	case CommentLikedMsg:
+		for i := range m.rows {
+			if c := &m.rows[i].comment; c.ID == msg.ID && !c.LikedByMe {
+				c.LikedByMe = true
+				c.LikesCount++
+			}
+		}
 		m.viewport.SetContent(m.renderCommentsList())
 
 	case CommentPostedMsg:
 		m.posting = false
 		m.composing = false
+		m.spoiler = false
+		m.replyTo = nil
 		m.textarea.Reset()
 		m.textarea.Blur()
 		// Reload comments
@@ -208,13 +449,26 @@ func (m CommentsView) Update(msg tea.Msg) (CommentsView, tea.Cmd) {
 			m.loadComments(),
 		)
 
+	case CommentDeletedMsg:
+		// Blank it in place like the server does; replies stay under it
+		for i := range m.rows {
+			if c := &m.rows[i].comment; c.ID == msg.ID {
+				c.IsDeleted = true
+				c.Content = models.DeletedCommentText
+				c.Username = models.DeletedCommentText
+			}
+		}
+		m.total = max(0, m.total-1)
+		m.viewport.SetContent(m.renderCommentsList())
+
 	case CommentsErrorMsg:
 		m.lastError = msg.Error
 		m.loading = false
+		m.loadingMore = false
 		m.posting = false
 
 	case spinner.TickMsg:
-		if m.loading || m.posting {
+		if m.loading || m.loadingMore || m.posting {
 			var cmd tea.Cmd
 			m.spinner, cmd = m.spinner.Update(msg)
 			cmds = append(cmds, cmd)
@@ -231,7 +485,7 @@ func (m CommentsView) Update(msg tea.Msg) (CommentsView, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
-// likeComment likes a comment
+// likeComment likes a comment; the count updates in place so loaded pages stay put
 func (m CommentsView) likeComment(commentID string) tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
@@ -239,8 +493,17 @@ func (m CommentsView) likeComment(commentID string) tea.Cmd {
 		if err != nil {
 			return CommentsErrorMsg{Error: err}
 		}
-		// Reload comments to show updated like count
-		return m.loadComments()()
+		return CommentLikedMsg{ID: commentID}
+	}
+}
+
+// deleteComment deletes a comment
+func (m CommentsView) deleteComment(commentID string) tea.Cmd {
+	return func() tea.Msg {
+		if err := m.client.DeleteComment(context.Background(), m.mangaID, commentID); err != nil {
+			return CommentsErrorMsg{Error: err}
+		}
+		return CommentDeletedMsg{ID: commentID}
 	}
 }
 
@@ -254,6 +517,9 @@ func (m CommentsView) View() string {
 
 	// Title
 	title := m.theme.Title.Render(fmt.Sprintf("💬 Comments: %s", m.mangaTitle))
+	if m.moderator {
+		title += " " + m.theme.Warning.Render("🛡 moderator")
+	}
 	sections = append(sections, title)
 
 	// Loading state
@@ -272,25 +538,45 @@ func (m CommentsView) View() string {
 
 	// Comments count
 	countStyle := m.theme.DimText
-	countText := countStyle.Render(fmt.Sprintf("%d comments", len(m.comments)))
-	sections = append(sections, countText)
+	countText := fmt.Sprintf("%d comments · %s", m.total, m.filterLabel())
+	if m.loadingMore {
+		countText += " · " + m.spinner.View() + " loading more"
+	}
+	sections = append(sections, countStyle.Render(countText))
 
 	// Viewport with comments
 	sections = append(sections, m.viewport.View())
 
 	// Compose area
 	if m.composing {
-		composeLabel := m.theme.Primary.Bold(true).Render("▶ New Comment:")
+		label := "▶ New Comment:"
+		if m.chapter > 0 {
+			label = fmt.Sprintf("▶ New Comment on Chapter %d:", m.chapter)
+		}
+		if m.replyTo != nil {
+			label = "↳ Reply to " + m.replyTo.comment.Username + ":"
+		}
+		composeLabel := m.theme.Primary.Bold(true).Render(label)
+		if m.spoiler {
+			composeLabel += " " + m.theme.Warning.Render("⚠ spoiler")
+		}
 		if m.posting {
 			composeLabel += " " + m.spinner.View()
 		}
 		sections = append(sections, composeLabel)
 		sections = append(sections, m.textarea.View())
-		helpText := m.theme.DimText.Render("Ctrl+S: post | ESC: cancel")
+		helpText := m.theme.DimText.Render("Ctrl+S: post | Ctrl+O: mark spoiler | ESC: cancel")
 		sections = append(sections, helpText)
+	} else if m.confirmDelete {
+		prompt := m.theme.Warning.Render("Delete this comment? (y/n)")
+		sections = append(sections, prompt)
 	} else {
 		// Help text
-		helpText := m.theme.DimText.Render("↑/↓: navigate | c: new comment | l: like | r: refresh | q: back")
+		help := "↑/↓: navigate | c: new comment | r: reply | l: like | s: spoilers | f: filter | R: refresh | q: back"
+		if m.selectedIndex >= 0 && m.selectedIndex < len(m.rows) && m.canDelete(m.rows[m.selectedIndex].comment) {
+			help = "↑/↓: navigate | c: new comment | r: reply | l: like | d: delete | s: spoilers | f: filter | R: refresh | q: back"
+		}
+		helpText := m.theme.DimText.Render(help)
 		sections = append(sections, helpText)
 	}
 
@@ -303,49 +589,72 @@ func (m CommentsView) View() string {
 	return containerStyle.Render(content)
 }
 
-// renderCommentsList renders the list of comments
+// renderCommentsList renders the threads, replies indented under their parent
 func (m CommentsView) renderCommentsList() string {
-	if len(m.comments) == 0 {
+	if len(m.rows) == 0 {
 		return m.theme.DimText.Render("No comments yet. Be the first to comment!")
 	}
 
 	var rows []string
-	for i, comment := range m.comments {
-		row := m.renderComment(comment, i == m.selectedIndex)
-		rows = append(rows, row)
-		if i < len(m.comments)-1 {
+	for i, row := range m.rows {
+		// Separate threads, not replies within a thread
+		if i > 0 && row.depth == 0 {
 			rows = append(rows, m.theme.DimText.Render(strings.Repeat("─", 70)))
 		}
+		rows = append(rows, m.renderComment(row, i == m.selectedIndex))
 	}
 
 	return lipgloss.JoinVertical(lipgloss.Left, rows...)
 }
 
-// renderComment renders a single comment
-func (m CommentsView) renderComment(comment models.CommentWithReplies, selected bool) string {
+// renderComment renders a single comment, indented by its reply depth
+func (m CommentsView) renderComment(row commentRow, selected bool) string {
+	comment := row.comment
+
 	// Selector
 	selector := "  "
 	if selected {
 		selector = m.theme.Primary.Render("▶ ")
 	}
 
+	indent := strings.Repeat("    ", row.depth)
+	if row.depth > 0 {
+		indent = strings.Repeat("    ", row.depth-1) + "  ↳ "
+	}
+
 	// User and timestamp
 	userStyle := m.theme.Primary.Bold(true)
 	timeStyle := m.theme.DimText
 	timeStr := formatTimestamp(comment.CreatedAt)
 
-	header := selector + userStyle.Render(comment.CommentWithUser.Username) + " " + timeStyle.Render(timeStr)
+	header := selector + indent + userStyle.Render(comment.Username) + " " + timeStyle.Render(timeStr)
+	// Tag chapter threads when several chapters are listed together
+	if m.filter == "" && row.depth == 0 && comment.ChapterNumber != nil {
+		header += " " + m.theme.Warning.Render(fmt.Sprintf("Ch. %d", *comment.ChapterNumber))
+	}
+	if comment.IsSpoiler && !comment.IsDeleted {
+		header += " " + m.theme.Warning.Render("⚠ spoiler")
+	}
 
-	// Content
+	// Content (deleted parents stay visible so replies keep their context)
 	contentStyle := m.theme.Description
 	if selected {
 		contentStyle = m.theme.Primary
 	}
-	content := contentStyle.Render(comment.Content)
+	if comment.IsDeleted {
+		contentStyle = m.theme.DimText.Italic(true)
+	}
+	text := comment.Content
+	if m.masked(comment) {
+		contentStyle = m.theme.DimText.Italic(true)
+		text = "[spoiler - press s to reveal]"
+	}
+	pad := "  " + strings.Repeat("    ", row.depth)
+	content := pad + contentStyle.Render(text)
 
 	// Likes
 	likesStyle := m.theme.DimText
-	likes := likesStyle.Render(fmt.Sprintf("❤️  %d", comment.LikesCount))
+	likes := pad + likesStyle.Render(fmt.Sprintf("❤️  %d", comment.LikesCount))
 
 	return lipgloss.JoinVertical(lipgloss.Left, header, content, likes, "")
 }
@@ -379,3 +688,10 @@ func (m CommentsView) IsActive() bool {
 func (m *CommentsView) Close() {
 	m.active = false
 }
+
+// SetTheme switches the view to a new theme
+func (m *CommentsView) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
+	m.viewport.Style = m.viewport.Style.BorderForeground(t.Palette.Primary)
+}
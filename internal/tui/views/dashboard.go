@@ -8,11 +8,15 @@
 //	┌── 📌 Recent Activity (fixed height) ───────────────────┐
 //	│ [12:05] User1 rated One Piece 5★                       │
 //	└────────────────────────────────────────────────────────┘
+//
+// The trending panel also shows top rated and hidden gems ([m]),
+// filtered by genre ([f]) and manga type ([y]).
 package views
 
 import (
 	"context"
 	"fmt"
+	"strings"
 	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
@@ -21,6 +25,7 @@ import (
 
 	"mangahub/internal/tui/api"
 	"mangahub/internal/tui/styles"
+	"mangahub/pkg/models"
 )
 
 // =====================================
@@ -50,6 +55,12 @@ type DashboardModel struct {
 	selectedPane  int // 0=reading, 1=trending, 2=activity
 	selectedIndex int
 
+	// Trending filters
+	trendingWindow int // index into trendingWindows
+	trendingGenre  int // index into Categories, -1 = all genres
+	rankingList    int // index into rankingLists
+	rankingType    int // index into mangaTypes, -1 = all types (top rated, hidden gems)
+
 	// Components
 	spinner spinner.Model
 
@@ -60,6 +71,15 @@ type DashboardModel struct {
 	client *api.Client
 }
 
+// trendingWindows are the windows the trending panel cycles through
+var trendingWindows = []string{"week", "day", "month"}
+
+// rankingLists are the lists the trending panel cycles through
+var rankingLists = []string{"trending", "top rated", "hidden gems"}
+
+// mangaTypes are the type filters of the top rated and hidden gems lists
+var mangaTypes = []string{"manga", "manhwa", "manhua", "novel"}
+
 // ReadingEntry represents a manga in "Continue Reading"
 type ReadingEntry struct {
 	MangaID        string
@@ -117,6 +137,8 @@ func NewDashboard() DashboardModel {
 		loadingReading:  true,
 		loadingTrending: true,
 		loadingActivity: true,
+		trendingGenre:   -1,
+		rankingType:     -1,
 	}
 }
 
@@ -132,6 +154,11 @@ func (m DashboardModel) Init() tea.Cmd {
 	)
 }
 
+// Reload fetches the dashboard data again, e.g. after progress changed elsewhere
+func (m DashboardModel) Reload() tea.Cmd {
+	return m.loadDashboardData
+}
+
 // loadDashboardData fetches all dashboard data
 func (m DashboardModel) loadDashboardData() tea.Msg {
 	ctx := context.Background()
@@ -140,35 +167,24 @@ func (m DashboardModel) loadDashboardData() tea.Msg {
 	var trending []TrendingEntry
 	var activity []ActivityEntry
 
-	// Load library (reading) if authenticated
+	// Continue reading if authenticated; the server picks and orders the entries
 	if m.client.IsAuthenticated() {
-		library, err := m.client.GetLibrary(ctx)
+		entries, err := m.client.GetContinueReading(ctx, models.DefaultContinueLimit)
 		if err == nil {
-			for _, entry := range library {
-				if entry.Status == "reading" {
-					reading = append(reading, ReadingEntry{
-						MangaID:        entry.MangaID,
-						Title:          entry.Manga.Title,
-						CurrentChapter: entry.CurrentChapter,
-						TotalChapters:  entry.Manga.TotalChapters, // Get from manga, not entry
-						LastReadAt:     entry.LastReadAt,
-					})
-				}
+			for _, entry := range entries {
+				reading = append(reading, ReadingEntry{
+					MangaID:        entry.MangaID,
+					Title:          entry.Title,
+					CurrentChapter: entry.CurrentChapter,
+					TotalChapters:  entry.TotalChapters,
+					LastReadAt:     entry.LastReadAt,
+				})
 			}
 		}
 	}
 
-	// Load trending
-	trendingData, err := m.client.GetTrending(ctx, 5, 7)
-	if err == nil {
-		for _, t := range trendingData {
-			trending = append(trending, TrendingEntry{
-				Rank:   t.Rank,
-				Title:  t.Title,
-				Rating: t.AverageRating,
-			})
-		}
-	}
+	// Load trending (or the selected ranking)
+	trending = m.fetchRanking(ctx)
 
 	// Load real activities from API
 	activities, err := m.client.GetActivities(ctx, 10)
@@ -223,6 +239,58 @@ func formatActivityAction(activityType, mangaTitle string, rating *float64, chap
 	}
 }
 
+// dashboardTrendingLoadedMsg carries trending data after a filter change
+type dashboardTrendingLoadedMsg struct {
+	Trending []TrendingEntry
+}
+
+// trendingGenreName returns the selected genre, or "" for all genres
+func (m DashboardModel) trendingGenreName() string {
+	if m.trendingGenre < 0 || m.trendingGenre >= len(Categories) {
+		return ""
+	}
+	return Categories[m.trendingGenre].Name
+}
+
+// rankingTypeName returns the selected manga type, or "" for all types
+func (m DashboardModel) rankingTypeName() string {
+	if m.rankingType < 0 || m.rankingType >= len(mangaTypes) {
+		return ""
+	}
+	return mangaTypes[m.rankingType]
+}
+
+// fetchRanking loads the list shown in the trending panel with its filters
+func (m DashboardModel) fetchRanking(ctx context.Context) []TrendingEntry {
+	var data []api.TrendingEntry
+	var err error
+	switch rankingLists[m.rankingList] {
+	case "top rated":
+		data, err = m.client.GetTopRated(ctx, 5, m.trendingGenreName(), m.rankingTypeName())
+	case "hidden gems":
+		data, err = m.client.GetHiddenGems(ctx, 5, m.trendingGenreName(), m.rankingTypeName())
+	default:
+		data, err = m.client.GetTrending(ctx, 5, trendingWindows[m.trendingWindow], m.trendingGenreName())
+	}
+	if err != nil {
+		return nil
+	}
+	var entries []TrendingEntry
+	for _, t := range data {
+		entries = append(entries, TrendingEntry{
+			Rank:   t.Rank,
+			Title:  t.Title,
+			Rating: t.AverageRating,
+		})
+	}
+	return entries
+}
+
+// loadTrending refetches only the trending panel
+func (m DashboardModel) loadTrending() tea.Msg {
+	return dashboardTrendingLoadedMsg{Trending: m.fetchRanking(context.Background())}
+}
+
 // Update handles messages
 func (m DashboardModel) Update(msg tea.Msg) (DashboardModel, tea.Cmd) {
 	var cmds []tea.Cmd
@@ -252,8 +320,45 @@ func (m DashboardModel) Update(msg tea.Msg) (DashboardModel, tea.Cmd) {
 			m.loadingTrending = true
 			m.loadingActivity = true
 			return m, m.loadDashboardData
+		case "m":
+			// Cycle trending / top rated / hidden gems
+			m.rankingList = (m.rankingList + 1) % len(rankingLists)
+			m.loadingTrending = true
+			return m, m.loadTrending
+		case "y":
+			// Cycle the manga type filter of top rated and hidden gems
+			if m.rankingList == 0 {
+				break
+			}
+			m.rankingType++
+			if m.rankingType >= len(mangaTypes) {
+				m.rankingType = -1
+			}
+			m.loadingTrending = true
+			return m, m.loadTrending
+		case "w":
+			// Cycle trending window
+			if m.rankingList != 0 {
+				break
+			}
+			m.trendingWindow = (m.trendingWindow + 1) % len(trendingWindows)
+			m.loadingTrending = true
+			return m, m.loadTrending
+		case "f":
+			// Cycle trending genre filter, ending back at all genres
+			m.trendingGenre++
+			if m.trendingGenre >= len(Categories) {
+				m.trendingGenre = -1
+			}
+			m.loadingTrending = true
+			return m, m.loadTrending
 		}
 
+	case dashboardTrendingLoadedMsg:
+		m.trending = msg.Trending
+		m.loadingTrending = false
+		m = m.clampSelection()
+
 	case DashboardDataLoadedMsg:
 		m.reading = msg.Reading
 		m.trending = msg.Trending
@@ -388,7 +493,16 @@ func (m DashboardModel) renderTrendingPanel(width int) string {
 	}
 
 	// Panel header
-	header := m.theme.PanelHeader.Render(styles.FireIcon() + " TRENDING NOW")
+	title := " " + strings.ToUpper(rankingLists[m.rankingList])
+	if m.rankingList == 0 {
+		title += " · " + strings.ToUpper(trendingWindows[m.trendingWindow])
+	} else if mangaType := m.rankingTypeName(); mangaType != "" {
+		title += " · " + strings.ToUpper(mangaType)
+	}
+	if genre := m.trendingGenreName(); genre != "" {
+		title += " · " + strings.ToUpper(genre)
+	}
+	header := m.theme.PanelHeader.Render(styles.FireIcon() + title)
 
 	// Panel border style
 	borderStyle := m.theme.Panel
@@ -401,7 +515,7 @@ func (m DashboardModel) renderTrendingPanel(width int) string {
 	if m.loadingTrending {
 		content = m.spinner.View() + " Loading..."
 	} else if len(m.trending) == 0 {
-		content = m.theme.DimText.Render("No trending data")
+		content = m.theme.DimText.Render("No " + rankingLists[m.rankingList] + " manga")
 	} else {
 		for i, entry := range m.trending {
 			// Selection highlight
@@ -424,6 +538,13 @@ func (m DashboardModel) renderTrendingPanel(width int) string {
 			content += line + "\n"
 		}
 	}
+	if m.selectedPane == 1 {
+		hint := "[m] list  [w] window  [f] genre"
+		if m.rankingList != 0 {
+			hint = "[m] list  [y] type  [f] genre"
+		}
+		content += "\n" + m.theme.DimText.Render(hint)
+	}
 
 	// Combine and wrap in border
 	panelContent := header + "\n" + content
@@ -495,6 +616,12 @@ func (m DashboardModel) GetSelectedMangaID() string {
 	return ""
 }
 
+// SetTheme switches the view to a new theme
+func (m *DashboardModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
+}
+
 // SetWidth sets the dashboard width
 func (m *DashboardModel) SetWidth(w int) {
 	m.width = w
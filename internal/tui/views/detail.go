@@ -10,10 +10,13 @@
 //	│  [  Art  ]   Monkey D. Luffy dreams of finding the    │
 //	│  [ ASCII ]   One Piece treasure...                    │
 //	│                                                       │
-//	│  YOUR PROGRESS:                                       │
-//	│  [████████████░░] 89% (Ch 1093)                       │
+//	│  YOUR LIBRARY                                         │
+//	│  📖 Reading  ★ Favorite                               │
+//	│  [████████████░░] Chapter 1093 of 1100                │
+//	│  [S] status  [-/+] chapter  [f] favorite              │
 //	│                                                       │
-//	│  [r] Read Next   [c] Comments   [R] Rate              │
+//	│  [r] Read Next   [o] Reader   [C] Comments   [R] Rate │
+//	│  [1-9/0] Quick rate 1-10                              │
 //	└───────────────────────────────────────────────────────┘
 package views
 
@@ -21,6 +24,7 @@ import (
 	"context"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -50,6 +54,13 @@ type DetailModel struct {
 	manga   *models.Manga
 	ratings *models.RatingSummary
 	library *api.LibraryEntry
+	muted   bool // update notifications muted for this manga
+	similar []models.SimilarManga
+
+	// Optimistic edits: library is libraryEdits' shown value; score is the
+	// user's own score set here (0 until they rate in this visit)
+	libraryEdits optimistic[*api.LibraryEntry]
+	score        optimistic[int]
 
 	// Loading
 	loading        bool
@@ -78,6 +89,13 @@ type DetailDataLoadedMsg struct {
 	Manga   *models.Manga
 	Ratings *models.RatingSummary
 	Library *api.LibraryEntry
+	Muted   bool
+	Similar []models.SimilarManga
+}
+
+// MangaMuteToggledMsg signals the manga's update notifications were (un)muted
+type MangaMuteToggledMsg struct {
+	Muted bool
 }
 
 // DetailErrorMsg signals an error
@@ -89,6 +107,13 @@ type DetailErrorMsg struct {
 type ShowCommentsMsg struct {
 	MangaID    string
 	MangaTitle string
+	Chapter    int // > 0 opens the chapter's discussion and tags new comments with it
+}
+
+// ShowReviewsMsg signals to show the reviews list
+type ShowReviewsMsg struct {
+	MangaID    string
+	MangaTitle string
 }
 
 // ShowRatingMsg signals to show rating modal
@@ -97,6 +122,22 @@ type ShowRatingMsg struct {
 	MangaTitle string
 }
 
+// ShowLoginMsg asks the app to send a guest to login, saying why
+type ShowLoginMsg struct {
+	Reason string
+}
+
+// similarLimit is how many recommendations the detail view shows
+const similarLimit = 5
+
+// Mute and favorite action labels
+const (
+	actionMute       = "🔕 Mute updates"
+	actionUnmute     = "🔔 Unmute updates"
+	actionFavorite   = "★ Favorite"
+	actionUnfavorite = "☆ Unfavorite"
+)
+
 // =====================================
 // CONSTRUCTOR
 // =====================================
@@ -113,7 +154,7 @@ func NewDetail(mangaID string) DetailModel {
 		client:  api.GetClient(),
 		mangaID: mangaID,
 		loading: true,
-		actions: []string{"Read Next", "💬 Chat", "Comments", "Rate", "Add to Library"},
+		actions: []string{"Read Next", "💬 Chat", "Comments", "Reviews", "Rate", "Add to Library"},
 	}
 }
 
@@ -139,11 +180,13 @@ func (m DetailModel) loadMangaDetail() tea.Msg {
 		return DetailErrorMsg{Error: err}
 	}
 
-	// Load ratings
+	// Load ratings and recommendations (optional sections)
 	ratings, _ := m.client.GetRatings(ctx, m.mangaID)
+	similar, _ := m.client.GetSimilarManga(ctx, m.mangaID, similarLimit)
 
-	// Check if in library
+	// Check if in library and whether updates are muted
 	var library *api.LibraryEntry
+	var muted bool
 	if m.client.IsAuthenticated() {
 		entries, err := m.client.GetLibrary(ctx)
 		if err == nil {
@@ -154,12 +197,15 @@ func (m DetailModel) loadMangaDetail() tea.Msg {
 				}
 			}
 		}
+		muted, _ = m.client.GetMangaMute(ctx, m.mangaID)
 	}
 
 	return DetailDataLoadedMsg{
 		Manga:   manga,
 		Ratings: ratings,
 		Library: library,
+		Muted:   muted,
+		Similar: similar,
 	}
 }
 
@@ -195,16 +241,7 @@ func (m DetailModel) Update(msg tea.Msg) (DetailModel, tea.Cmd) {
 		case "c":
 			// Join Chat for this manga
 			if m.manga != nil {
-				mangaName := m.manga.Title
-				roomID := "manga_" + m.mangaID
-				return m, func() tea.Msg {
-					return network.JoinRoomMsg{
-						RoomID:    roomID,
-						RoomName:  mangaName + " Discussion",
-						MangaID:   m.mangaID,
-						MangaName: mangaName,
-					}
-				}
+				return m, m.openChat()
 			}
 		case "C":
 			// Comments (capital C)
@@ -215,6 +252,13 @@ func (m DetailModel) Update(msg tea.Msg) (DetailModel, tea.Cmd) {
 					MangaTitle: m.manga.Title,
 				}
 			}
+		case "v":
+			// Read full reviews
+			if m.manga != nil {
+				return m, func() tea.Msg {
+					return ShowReviewsMsg{MangaID: m.mangaID, MangaTitle: m.manga.Title}
+				}
+			}
 		case "R":
 			// Rate (capital R)
 			// TODO: Open rating modal - will be handled by parent app
@@ -224,10 +268,60 @@ func (m DetailModel) Update(msg tea.Msg) (DetailModel, tea.Cmd) {
 					MangaTitle: m.manga.Title,
 				}
 			}
-		case "a":
+		case "1", "2", "3", "4", "5", "6", "7", "8", "9", "0":
+			// Quick rate: 1-9 as pressed, 0 is 10. Pressing another number
+			// changes the score; the review, if any, is kept
+			if m.manga == nil {
+				break
+			}
+			if !m.client.IsAuthenticated() {
+				// The app answers guests with the login prompt
+				return m, func() tea.Msg {
+					return ShowRatingMsg{MangaID: m.mangaID, MangaTitle: m.manga.Title}
+				}
+			}
+			score := int(msg.String()[0] - '0')
+			if score == 0 {
+				score = 10
+			}
+			return m.quickRate(score)
+		case "o":
+			// Open chapter reader
+			if m.manga != nil && m.library != nil {
+				return m, m.openReader
+			}
+		case "a", "A":
 			// Add to library
 			if m.manga != nil && m.library == nil {
-				return m, m.addToLibrary
+				return m.addToLibrary()
+			}
+		case "S":
+			// Next library status (capital S)
+			if m.library != nil {
+				return m.changeStatus(nextLibraryStatus(m.library.Status))
+			}
+		case "+", "=":
+			if m.library != nil {
+				return m.changeChapter(m.library.CurrentChapter + 1)
+			}
+		case "-":
+			if m.library != nil {
+				return m.changeChapter(m.library.CurrentChapter - 1)
+			}
+		case "U":
+			// Catch up to the latest chapter (capital U)
+			if m.manga != nil && m.library != nil {
+				return m, m.catchUp
+			}
+		case "M":
+			// Mute/unmute update notifications (capital M)
+			if m.manga != nil && m.client.IsAuthenticated() {
+				return m, m.toggleMute()
+			}
+		case "f":
+			// Favorite/unfavorite (library entries only)
+			if m.library != nil {
+				return m.toggleFavorite()
 			}
 		case "enter":
 			// Execute the currently selected action
@@ -238,7 +332,7 @@ func (m DetailModel) Update(msg tea.Msg) (DetailModel, tea.Cmd) {
 			switch action {
 			case "Add to Library":
 				if m.manga != nil && m.library == nil {
-					return m, m.addToLibrary
+					return m.addToLibrary()
 				}
 			case "Read Next":
 				if m.manga != nil && m.library != nil {
@@ -248,50 +342,62 @@ func (m DetailModel) Update(msg tea.Msg) (DetailModel, tea.Cmd) {
 					}
 				} else if m.manga != nil && m.library == nil {
 					// If not in library, add first
-					return m, m.addToLibrary
+					return m.addToLibrary()
 				}
 			case "💬 Chat":
 				if m.manga != nil {
-					mangaName := m.manga.Title
-					roomID := "manga_" + m.mangaID
-					return m, func() tea.Msg {
-						return network.JoinRoomMsg{
-							RoomID:    roomID,
-							RoomName:  mangaName + " Discussion",
-							MangaID:   m.mangaID,
-							MangaName: mangaName,
-						}
-					}
+					return m, m.openChat()
 				}
 			case "Comments":
 				return m, func() tea.Msg {
 					return ShowCommentsMsg{MangaID: m.mangaID, MangaTitle: m.manga.Title}
 				}
+			case "Reviews":
+				return m, func() tea.Msg {
+					return ShowReviewsMsg{MangaID: m.mangaID, MangaTitle: m.manga.Title}
+				}
 			case "Rate":
 				return m, func() tea.Msg {
 					return ShowRatingMsg{MangaID: m.mangaID, MangaTitle: m.manga.Title}
 				}
+			case "Reader":
+				if m.manga != nil && m.library != nil {
+					return m, m.openReader
+				}
 			case "Update Progress":
 				if m.library != nil {
 					return m, m.updateReadingProgress(m.library.CurrentChapter + 1)
 				}
+			case "Catch Up":
+				if m.manga != nil && m.library != nil {
+					return m, m.catchUp
+				}
+			case actionMute, actionUnmute:
+				return m, m.toggleMute()
+			case actionFavorite, actionUnfavorite:
+				if m.library != nil {
+					return m.toggleFavorite()
+				}
 			}
 		}
 
 	case DetailDataLoadedMsg:
 		m.manga = msg.Manga
 		m.ratings = msg.Ratings
-		m.library = msg.Library
+		m.libraryEdits = m.libraryEdits.Load(msg.Library)
+		m.library = m.libraryEdits.Value()
+		m.muted = msg.Muted
+		m.similar = msg.Similar
 		m.loading = false
-		// Update actions based on library status
-		if m.library != nil {
-			m.actions = []string{"Read Next", "💬 Chat", "Update Progress", "Comments", "Rate"}
-		} else {
-			m.actions = []string{"Add to Library", "💬 Chat", "Comments", "Rate"}
-		}
-		// Ensure selectedAction is within bounds after actions change
-		if m.selectedAction >= len(m.actions) {
-			m.selectedAction = 0
+		m.updateActions()
+
+	case MangaMuteToggledMsg:
+		m.muted = msg.Muted
+		m.updateActions()
+
+	case EditResultMsg:
+		if msg.MangaID == m.mangaID {
+			return m.settleEdit(msg)
 		}
 
 	case DetailErrorMsg:
@@ -307,15 +413,220 @@ func (m DetailModel) Update(msg tea.Msg) (DetailModel, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
-// addToLibrary adds the manga to user's library
-func (m DetailModel) addToLibrary() tea.Msg {
-	ctx := context.Background()
-	err := m.client.AddToLibrary(ctx, m.mangaID)
-	if err != nil {
-		return DetailErrorMsg{Error: err}
+// updateActions rebuilds the action row from library and mute status
+func (m *DetailModel) updateActions() {
+	if m.library != nil {
+		m.actions = []string{"Read Next", "Reader", "💬 Chat", "Update Progress", "Catch Up", "Comments", "Reviews", "Rate"}
+		if m.library.IsFavorite {
+			m.actions = append(m.actions, actionUnfavorite)
+		} else {
+			m.actions = append(m.actions, actionFavorite)
+		}
+	} else {
+		m.actions = []string{"Add to Library", "💬 Chat", "Comments", "Reviews", "Rate"}
+	}
+	if m.client.IsAuthenticated() {
+		if m.muted {
+			m.actions = append(m.actions, actionUnmute)
+		} else {
+			m.actions = append(m.actions, actionMute)
+		}
+	}
+	// Ensure selectedAction is within bounds after actions change
+	if m.selectedAction >= len(m.actions) {
+		m.selectedAction = 0
+	}
+}
+
+// toggleMute mutes or unmutes update notifications for the manga
+func (m DetailModel) toggleMute() tea.Cmd {
+	muted := !m.muted
+	return func() tea.Msg {
+		if err := m.client.SetMangaMute(context.Background(), m.mangaID, muted); err != nil {
+			return DetailErrorMsg{Error: err}
+		}
+		return MangaMuteToggledMsg{Muted: muted}
 	}
-	// Reload to update library status
-	return m.loadMangaDetail()
+}
+
+// syncLibrary shows the confirmed library entry with the pending edits on top
+func (m *DetailModel) syncLibrary() {
+	m.library = m.libraryEdits.Value()
+	m.updateActions()
+}
+
+// settleEdit folds in or rolls back this view's own edits, and picks up a
+// favorite the library view got confirmed
+func (m DetailModel) settleEdit(msg EditResultMsg) (DetailModel, tea.Cmd) {
+	switch {
+	case m.score.Owns(msg.EditID):
+		if msg.Error != nil {
+			m.score = m.score.Rollback(msg.EditID)
+			return m, nil
+		}
+		m.score = m.score.Confirm(msg.EditID)
+		// Refresh the community summary the score went into
+		return m, m.loadMangaDetail
+	case m.libraryEdits.Owns(msg.EditID):
+		if msg.Error != nil {
+			m.libraryEdits = m.libraryEdits.Rollback(msg.EditID)
+			m.syncLibrary()
+			return m, nil
+		}
+		m.libraryEdits = m.libraryEdits.Confirm(msg.EditID)
+		m.syncLibrary()
+		if msg.Action == EditAdd {
+			// Swap the placeholder entry for the server's row
+			return m, m.loadMangaDetail
+		}
+	case msg.Error == nil && msg.Action == EditFavorite:
+		m.libraryEdits = m.libraryEdits.Amend(editEntry(setFavorite(msg.Favorite)))
+		m.syncLibrary()
+	}
+	return m, nil
+}
+
+// toggleFavorite marks or unmarks the manga as favorite, shown before the
+// server answers; the app passes the result to the library view as well
+func (m DetailModel) toggleFavorite() (DetailModel, tea.Cmd) {
+	isFavorite := !m.library.IsFavorite
+	var id int
+	m.libraryEdits, id = m.libraryEdits.Begin(editEntry(setFavorite(isFavorite)))
+	m.syncLibrary()
+	mangaID := m.mangaID
+	client := m.client
+	return m, func() tea.Msg {
+		err := client.ToggleFavorite(context.Background(), mangaID, isFavorite)
+		return EditResultMsg{EditID: id, MangaID: mangaID, Action: EditFavorite, Favorite: isFavorite, Error: err}
+	}
+}
+
+// quickRate submits a score without opening the rating modal; the score
+// shows at once and is taken back if the server rejects it
+func (m DetailModel) quickRate(score int) (DetailModel, tea.Cmd) {
+	var id int
+	m.score, id = m.score.Begin(func(int) int { return score })
+	mangaID := m.mangaID
+	client := m.client
+	return m, func() tea.Msg {
+		err := client.SubmitScore(context.Background(), mangaID, score)
+		return EditResultMsg{EditID: id, MangaID: mangaID, Action: EditRate, Rating: score, Error: err}
+	}
+}
+
+// openChat joins the manga's chat room, which the server creates the first
+// time anyone opens it. If that fails (e.g. logged out) the room is joined by
+// its conventional ID, and the app sends guests to login.
+func (m DetailModel) openChat() tea.Cmd {
+	mangaID := m.mangaID
+	mangaName := m.manga.Title
+	client := m.client
+	return func() tea.Msg {
+		join := network.JoinRoomMsg{
+			RoomID:    "manga_" + mangaID,
+			RoomName:  mangaName + " Discussion",
+			MangaID:   mangaID,
+			MangaName: mangaName,
+		}
+		if client.IsAuthenticated() {
+			ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+			if room, err := client.OpenMangaRoom(ctx, mangaID); err == nil {
+				join.RoomID = room.ID
+				join.RoomName = room.Name
+			}
+		}
+		return join
+	}
+}
+
+// addToLibrary adds the manga to user's library. A placeholder entry shows
+// until the server answers; it lives only in this view, never in the API
+// cache, so a rejected add leaves nothing behind. Guests are sent to login.
+func (m DetailModel) addToLibrary() (DetailModel, tea.Cmd) {
+	if !m.client.IsAuthenticated() {
+		return m, func() tea.Msg { return ShowLoginMsg{Reason: "Log in to add manga to your library"} }
+	}
+	placeholder := &api.LibraryEntry{
+		MangaID: m.mangaID,
+		Manga:   *m.manga,
+		Status:  "plan_to_read",
+		AddedAt: time.Now(),
+	}
+	var id int
+	m.libraryEdits, id = m.libraryEdits.Begin(func(entry *api.LibraryEntry) *api.LibraryEntry {
+		if entry != nil {
+			return entry
+		}
+		return placeholder
+	})
+	m.syncLibrary()
+	mangaID := m.mangaID
+	client := m.client
+	return m, func() tea.Msg {
+		err := client.AddToLibrary(context.Background(), mangaID)
+		return EditResultMsg{EditID: id, MangaID: mangaID, Action: EditAdd, Error: err}
+	}
+}
+
+// changeStatus moves the manga to another shelf, shown before the server answers
+func (m DetailModel) changeStatus(status string) (DetailModel, tea.Cmd) {
+	var id int
+	m.libraryEdits, id = m.libraryEdits.Begin(editEntry(setStatus(status)))
+	m.syncLibrary()
+	mangaID := m.mangaID
+	client := m.client
+	return m, func() tea.Msg {
+		err := client.UpdateLibraryStatus(context.Background(), mangaID, status)
+		return EditResultMsg{EditID: id, MangaID: mangaID, Action: EditStatus, Status: status, Error: err}
+	}
+}
+
+// changeChapter sets the current chapter, keeping the status, shown before
+// the server answers. It stays between 0 and the latest chapter, when known.
+func (m DetailModel) changeChapter(chapter int) (DetailModel, tea.Cmd) {
+	if chapter < 0 || (m.manga.TotalChapters > 0 && chapter > m.manga.TotalChapters) {
+		return m, nil
+	}
+	var id int
+	m.libraryEdits, id = m.libraryEdits.Begin(editEntry(setChapter(chapter)))
+	m.syncLibrary()
+	mangaID := m.mangaID
+	status := m.library.Status
+	client := m.client
+	return m, func() tea.Msg {
+		err := client.UpdateLibraryProgress(context.Background(), mangaID, status, chapter)
+		return EditResultMsg{EditID: id, MangaID: mangaID, Action: EditChapter, Chapter: chapter, Error: err}
+	}
+}
+
+// nextLibraryStatus is the shelf after status, in the library's tab order
+func nextLibraryStatus(status string) string {
+	for i, s := range tabStatuses {
+		if s == status {
+			return tabStatuses[(i+1)%len(tabStatuses)]
+		}
+	}
+	return tabStatuses[0]
+}
+
+// openReader opens the chapter reader at the current progress
+func (m DetailModel) openReader() tea.Msg {
+	return ShowReaderMsg{
+		MangaID:        m.mangaID,
+		MangaTitle:     m.manga.Title,
+		CurrentChapter: m.library.CurrentChapter,
+		TotalChapters:  m.manga.TotalChapters,
+		Status:         m.library.Status,
+	}
+}
+
+// OpenReader opens the reader for this manga; nil until it is loaded and in the library
+func (m DetailModel) OpenReader() tea.Cmd {
+	if m.manga == nil || m.library == nil {
+		return nil
+	}
+	return m.openReader
 }
 
 // updateReadingProgress updates the reading progress
@@ -331,6 +642,15 @@ func (m DetailModel) updateReadingProgress(chapter int) tea.Cmd {
 	}
 }
 
+// catchUp marks every chapter up to the latest as read
+func (m DetailModel) catchUp() tea.Msg {
+	if _, err := m.client.CatchUpProgress(context.Background(), m.mangaID); err != nil {
+		return DetailErrorMsg{Error: err}
+	}
+	// Reload to update library status
+	return m.loadMangaDetail()
+}
+
 // View renders the detail view
 func (m DetailModel) View() string {
 	if m.loading {
@@ -369,11 +689,8 @@ func (m DetailModel) renderCard() string {
 	body := m.renderBody()
 	sections = append(sections, body)
 
-	// ===== PROGRESS (if in library) =====
-	if m.library != nil {
-		progress := m.renderProgress()
-		sections = append(sections, progress)
-	}
+	// ===== YOUR LIBRARY =====
+	sections = append(sections, m.renderLibraryPanel())
 
 	// ===== RATING SUMMARY =====
 	if m.ratings != nil {
@@ -387,6 +704,11 @@ func (m DetailModel) renderCard() string {
 		sections = append(sections, chapters)
 	}
 
+	// ===== SIMILAR MANGA =====
+	if len(m.similar) > 0 {
+		sections = append(sections, m.renderSimilar())
+	}
+
 	// ===== ACTIONS =====
 	actions := m.renderActions()
 	sections = append(sections, actions)
@@ -472,7 +794,13 @@ func (m DetailModel) renderBody() string {
 		Width(synopsisWidth).
 		Render(synopsis)
 
-	return lipgloss.JoinHorizontal(lipgloss.Top, artBox, "  ", synopsisBox) + "\n"
+	body := lipgloss.JoinHorizontal(lipgloss.Top, artBox, "  ", synopsisBox) + "\n"
+
+	// Cover image URL (terminal image rendering may use it later)
+	if m.manga.CoverURL != "" {
+		body += m.theme.DimText.Render("Cover: "+m.manga.CoverURL) + "\n"
+	}
+	return body
 }
 
 // renderASCIIArt creates a placeholder manga cover in ASCII
@@ -517,9 +845,38 @@ func (m DetailModel) renderSynopsis() string {
 	return header + "\n" + strings.Join(lines, "\n")
 }
 
-// renderProgress renders the reading progress section
+// renderLibraryPanel shows the user's status, chapter and favorite flag with
+// the keys that change them, or how to add the manga
+func (m DetailModel) renderLibraryPanel() string {
+	header := m.theme.PanelHeader.Render("YOUR LIBRARY")
+	if m.library == nil {
+		text := "Not in your library."
+		if !m.client.IsAuthenticated() {
+			text = "Log in to track this manga."
+		}
+		return header + "\n" + m.theme.Description.Render(text) + "  " +
+			styles.RenderKeyHint("A", "add to library") + "\n"
+	}
+
+	status := m.library.Status
+	if label, ok := StatusLabels[status]; ok {
+		status = StatusIcons[status] + " " + label
+	}
+	line := m.theme.Title.Render(status)
+	if m.library.IsFavorite {
+		line += "  " + m.theme.Warning.Render("★ Favorite")
+	}
+
+	controls := strings.Join([]string{
+		styles.RenderKeyHint("S", "status"),
+		styles.RenderKeyHint("-/+", "chapter"),
+		styles.RenderKeyHint("f", "favorite"),
+	}, "  ")
+	return header + "\n" + line + "\n" + m.renderProgress() + controls + "\n"
+}
+
+// renderProgress renders the progress bar of the library panel
 func (m DetailModel) renderProgress() string {
-	header := m.theme.PanelHeader.Render("YOUR PROGRESS")
 
 	current := m.library.CurrentChapter
 	total := m.manga.TotalChapters // Get from manga, not library
@@ -536,7 +893,7 @@ func (m DetailModel) renderProgress() string {
 
 	progressBar := styles.RenderProgressBar(progressPct, 20)
 
-	return header + "\n" + progressBar + "  " + m.theme.Description.Render(progressText) + "\n"
+	return progressBar + "  " + m.theme.Description.Render(progressText) + "\n"
 }
 
 // renderRatingSummary renders the rating statistics
@@ -546,7 +903,68 @@ func (m DetailModel) renderRatingSummary() string {
 	avgRating := styles.RenderRating(m.ratings.AverageRating, true)
 	countText := m.theme.DimText.Render(fmt.Sprintf("(%d ratings)", m.ratings.RatingCount))
 
-	return header + "\n" + avgRating + " " + countText + "\n"
+	summary := header + "\n" + avgRating + " " + countText + "\n"
+	if score := m.score.Value(); score > 0 {
+		yours := fmt.Sprintf("Your score: %d/10", score)
+		if m.score.Pending() {
+			yours += " (saving…)"
+		}
+		summary += m.theme.Description.Render(yours) + "\n"
+	}
+	if m.ratings.RatingCount == 0 {
+		return summary
+	}
+	return summary + m.renderRatingDistribution()
+}
+
+// renderSimilar renders the "if you liked this" recommendations
+//
+//	Claymore            ★ 8.2  2 shared genres
+func (m DetailModel) renderSimilar() string {
+	header := m.theme.PanelHeader.Render("IF YOU LIKED THIS, TRY")
+
+	var lines []string
+	for _, s := range m.similar {
+		title := truncate(s.Title, 28)
+		meta := fmt.Sprintf("★ %.1f", s.AverageRating)
+		if s.SharedGenres > 0 {
+			meta += fmt.Sprintf("  %d shared genre", s.SharedGenres)
+			if s.SharedGenres > 1 {
+				meta += "s"
+			}
+		}
+		lines = append(lines, m.theme.Description.Render(fmt.Sprintf("%-30s", title))+m.theme.DimText.Render(meta))
+	}
+	return header + "\n" + strings.Join(lines, "\n")
+}
+
+// renderRatingDistribution renders the 1-10 score histogram, highest score first
+//
+//	10 ████████░░░░ 40%
+//	 9 ███░░░░░░░░░ 15%
+func (m DetailModel) renderRatingDistribution() string {
+	dist := m.ratings.RatingDistribution
+	total := 0
+	mostCommon := 0
+	for i, count := range dist {
+		total += count
+		if count > dist[mostCommon] {
+			mostCommon = i
+		}
+	}
+	if total == 0 {
+		return ""
+	}
+
+	var rows []string
+	for score := 10; score >= 1; score-- {
+		label := m.theme.DimText.Render(fmt.Sprintf("%2d ", score))
+		bar := styles.RenderProgressBar(float64(dist[score-1])/float64(total), 20)
+		rows = append(rows, label+bar)
+	}
+	rows = append(rows, m.theme.DimText.Render(fmt.Sprintf("Most people rated this %d", mostCommon+1)))
+
+	return strings.Join(rows, "\n") + "\n"
 }
 
 // renderChapters renders the chapter list
@@ -664,6 +1082,14 @@ func (m *DetailModel) SetMangaID(id string) {
 	m.manga = nil
 	m.ratings = nil
 	m.library = nil
+	m.libraryEdits = optimistic[*api.LibraryEntry]{}
+	m.score = optimistic[int]{}
+}
+
+// SetTheme switches the view to a new theme
+func (m *DetailModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
 }
 
 // SetWidth sets the view width
@@ -127,6 +127,19 @@ func (m HelpModel) View() string {
 			{"r", "Refresh", "Reload current view data"},
 			{"Enter", "Submit/Confirm", "Submit form or select item"},
 			{"Esc", "Cancel/Back", "Cancel action or go back"},
+			{"M (in detail)", "Mute updates", "Toggle new-chapter notifications for the manga"},
+			{"v (in detail)", "Read reviews", "Spoiler reviews stay collapsed unless Show Spoilers is on"},
+			{"U (detail/library)", "Catch up", "Jump to the latest chapter and mark completed"},
+			{"m (in dashboard)", "Cycle ranking", "Trending, top rated (Bayesian) or hidden gems"},
+			{"y (in dashboard)", "Filter by type", "Manga, manhwa, manhua or novel (top rated / hidden gems)"},
+			{"S / - / + (in detail)", "Library status", "Next status, previous/next chapter; A adds to library"},
+			{"Space (in library)", "Select", "Mark entries; 1-5 then set all their statuses at once"},
+			{"o (in browse)", "Cycle sort", "Sort by rating, year, chapters or title"},
+			{"O (in browse)", "Flip sort order", "Toggle ascending/descending"},
+			{"z / Z (in browse)", "Random manga", "Open a random manga from the category / from anywhere"},
+			{"space / n (in browse)", "Mark genres", "Combine genres (any of them) / clear the marks"},
+			{"f (in browse)", "Filter genres", "Narrow the genre grid by name"},
+			{"H (in browse)", "Hide empty genres", "Toggle genres that have no manga"},
 			{"q", "Quit", "Exit MangaHub"},
 			{"Ctrl+C", "Force quit", "Emergency exit"},
 		}),
@@ -144,6 +157,18 @@ func (m HelpModel) View() string {
 		}),
 	)
 
+	// Search View section
+	sections = append(sections,
+		m.renderSection("🔍 Search (S or / key)", []KeyBinding{
+			{"Ctrl+G", "Genre", "Cycle the genre filter"},
+			{"Ctrl+O", "Sort", "Cycle relevance, rating, year, chapters, title"},
+			{"Ctrl+R", "Reverse", "Flip the sort direction"},
+			{"Ctrl+S", "Save", "Save the query and filters under a name"},
+			{"↑/↓ Enter", "Recent/Saved", "With an empty input, run a recent or saved search"},
+			{"Ctrl+D", "Delete", "Delete a saved search, or clear recent searches"},
+		}),
+	)
+
 	// Auth View section
 	sections = append(sections,
 		m.renderSection("🔐 Authentication (L key or Login view)", []KeyBinding{
@@ -165,6 +190,27 @@ func (m HelpModel) View() string {
 		}),
 	)
 
+	// Reader View section
+	sections = append(sections,
+		m.renderSection("📖 Reader (o key in detail)", []KeyBinding{
+			{"n", "Next chapter", "Mark chapter read and record history"},
+			{"p", "Previous chapter", "Rewind progress by one chapter"},
+			{"u", "Undo read", "Remove the last recorded chapter from history and stats"},
+			{"C", "Discuss chapter", "Comments scoped to the current chapter"},
+			{"Esc", "Back", "Return to manga detail"},
+		}),
+	)
+
+	// Activity View section
+	sections = append(sections,
+		m.renderSection("🌐 Activity (a key)", []KeyBinding{
+			{"Enter", "View profile", "Open the selected user's public profile"},
+			{"m", "View manga", "Open the manga of the selected activity"},
+			{"f", "Filter", "Cycle comments/ratings/progress/list adds"},
+			{"r", "Refresh", "Reload the feed"},
+		}),
+	)
+
 	// Stats View section
 	sections = append(sections,
 		m.renderSection("📊 Statistics (t key)", []KeyBinding{
@@ -172,6 +218,9 @@ func (m HelpModel) View() string {
 			{"View", "Rank badge", "Bronze/Silver/Gold/Emerald/Diamond"},
 			{"View", "Genre distribution", "Your favorite genres"},
 			{"View", "Rank progress", "Progress to next rank"},
+			{"View", "Reading heatmap", "Chapters per day, latest weeks that fit"},
+			{"g", "Yearly goal", "Set chapters to read this year"},
+			{"m", "Monthly goal", "Set chapters to read this month"},
 			{"r", "Refresh", "Reload statistics"},
 		}),
 	)
@@ -209,6 +258,11 @@ func (m HelpModel) View() string {
 		Render(content)
 }
 
+// SetTheme switches the view to a new theme
+func (m *HelpModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+}
+
 // SetWidth sets the view width
 func (m *HelpModel) SetWidth(w int) {
 	m.width = w
@@ -0,0 +1,136 @@
+// Package views - Keybinding Editor
+// Đổi phím tắt toàn cục trong Settings
+// Chức năng:
+//   - Enter trên một binding: bắt phím nhấn tiếp theo
+//   - Từ chối phím dành riêng và phím đã thuộc action khác
+//   - Backspace/Delete: trả binding về mặc định
+//   - Lưu override qua UpdatePreferences (field keybindings)
+package views
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"mangahub/pkg/models"
+)
+
+// Shortcut is a rebindable global shortcut as the editor shows it.
+// Keys are in tea.KeyMsg.String() form, which is what key.Matches compares.
+type Shortcut struct {
+	Action   string // keybindings preference key, e.g. "search"
+	Label    string
+	Keys     []string // current keys, the first one shown
+	Defaults []string
+}
+
+// isDefault reports whether the binding uses its default keys
+func (b Shortcut) isDefault() bool {
+	return slices.Equal(b.Keys, b.Defaults)
+}
+
+// KeyBindingsChangedMsg asks the app to rebuild its key map from overrides
+// (action -> key); it is sent before the preference is saved
+type KeyBindingsChangedMsg struct {
+	Overrides map[string]string
+}
+
+// reservedKeys are handled before any rebindable shortcut (ctrl+c, the
+// palette, help, back) or move through lists and forms, including this editor
+var reservedKeys = []string{
+	"ctrl+c", "ctrl+p", "?", "esc", "enter",
+	"up", "down", "left", "right", "j", "k",
+	"tab", "shift+tab", "backspace", "delete",
+}
+
+// ReservedKey reports whether k can never be bound to a shortcut
+func ReservedKey(k string) bool {
+	return slices.Contains(reservedKeys, k)
+}
+
+// keyOverrides returns the bindings that differ from their defaults as
+// preference overrides: each rebound action mapped to its one key
+func keyOverrides(bindings []Shortcut) map[string]string {
+	overrides := map[string]string{}
+	for _, b := range bindings {
+		if !b.isDefault() && len(b.Keys) > 0 {
+			overrides[b.Action] = b.Keys[0]
+		}
+	}
+	return overrides
+}
+
+// rebind gives binding i the keys ks unless another binding uses one of
+// them. The slice is copied, so a rejected change leaves the model untouched.
+func rebind(bindings []Shortcut, i int, ks []string) ([]Shortcut, error) {
+	for _, k := range ks {
+		for j, other := range bindings {
+			if j != i && slices.Contains(other.Keys, k) {
+				return nil, fmt.Errorf("%s is already bound to %s", k, other.Label)
+			}
+		}
+	}
+	next := slices.Clone(bindings)
+	next[i].Keys = ks
+	return next, nil
+}
+
+// updateKeyCapture binds the captured key to the selected action
+func (m SettingsModel) updateKeyCapture(msg tea.KeyMsg) (SettingsModel, tea.Cmd) {
+	i := m.capturing
+	m.capturing = -1
+	// String() is the form key.Matches compares, e.g. "ctrl+f", "F", "f5"
+	k := msg.String()
+	if ReservedKey(k) {
+		m.bindingStatus = "⚠ " + k + " is reserved"
+		return m, nil
+	}
+	bindings, err := rebind(m.bindings, i, []string{k})
+	if err != nil {
+		m.bindingStatus = "⚠ " + err.Error()
+		return m, nil
+	}
+	return m.applyBindings(bindings, fmt.Sprintf("%s bound to %s", m.bindings[i].Label, k))
+}
+
+// resetBinding puts binding i back on its default keys
+func (m SettingsModel) resetBinding(i int) (SettingsModel, tea.Cmd) {
+	if m.bindings[i].isDefault() {
+		return m, nil
+	}
+	bindings, err := rebind(m.bindings, i, m.bindings[i].Defaults)
+	if err != nil {
+		m.bindingStatus = "⚠ cannot reset: " + err.Error()
+		return m, nil
+	}
+	return m.applyBindings(bindings, m.bindings[i].Label+" reset to "+strings.Join(m.bindings[i].Defaults, "/"))
+}
+
+// applyBindings switches the app to bindings right away and saves them in the background
+func (m SettingsModel) applyBindings(bindings []Shortcut, status string) (SettingsModel, tea.Cmd) {
+	m.bindings = bindings
+	m.bindingStatus = "✓ " + status
+	overrides := keyOverrides(bindings)
+	return m, tea.Batch(
+		func() tea.Msg { return KeyBindingsChangedMsg{Overrides: overrides} },
+		m.savePreference("Keybindings", models.UpdatePreferencesRequest{Keybindings: overrides}),
+	)
+}
+
+// IsCapturingKey reports whether the editor waits for a key to bind
+func (m SettingsModel) IsCapturingKey() bool {
+	return m.capturing >= 0
+}
+
+// CancelKeyCapture stops waiting for a key (Esc, which the app handles itself)
+func (m *SettingsModel) CancelKeyCapture() {
+	m.capturing = -1
+	m.bindingStatus = ""
+}
+
+// SetKeyBindings shows the app's current bindings in the editor
+func (m *SettingsModel) SetKeyBindings(bindings []Shortcut) {
+	m.bindings = bindings
+}
@@ -2,17 +2,21 @@
 // Tabbed shelf layout for user's manga library
 // Layout:
 //
-//	Reading  |  Plan  |  Completed  |  Dropped
+//	Reading  |  Plan  |  Completed  |  Dropped      ★ Favorites (3)
 //	─────────────────────────────────────────────
 //	[x] One Piece           Ch: 1093/1100   ★★★★★
 //	[ ] Jujutsu Kaisen      Ch: 260/???     ★★★★☆
 //	─────────────────────────────────────────────
-//	[Enter] Details  [d] Delete  [u] Update  [Tab] Next
+//	[Enter] Details  [d] Delete  [u] Update  [U] Catch up  [f] Favorite  [F] Favorites only
+//
+// Space marks entries; 1-5 then set the status of every marked entry at once.
 package views
 
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
@@ -20,6 +24,7 @@ import (
 
 	"mangahub/internal/tui/api"
 	"mangahub/internal/tui/styles"
+	"mangahub/pkg/models"
 )
 
 // =====================================
@@ -38,7 +43,7 @@ const (
 )
 
 var tabNames = []string{"Reading", "Plan", "Completed", "On-Hold", "Dropped"}
-var tabStatuses = []string{"reading", "planning", "completed", "on_hold", "dropped"}
+var tabStatuses = []string{"reading", "plan_to_read", "completed", "on_hold", "dropped"}
 
 // =====================================
 // LIBRARY MODEL
@@ -53,8 +58,9 @@ type LibraryModel struct {
 	// Theme
 	theme *styles.Theme
 
-	// Data
+	// Data: entries is edits' shown value, kept in step by applyEdits
 	entries []api.LibraryEntry
+	edits   optimistic[[]api.LibraryEntry]
 
 	// Filtered views per tab
 	filteredEntries []api.LibraryEntry
@@ -62,13 +68,19 @@ type LibraryModel struct {
 	// Current tab
 	activeTab LibraryTab
 
+	// favoritesOnly narrows every tab to favorites
+	favoritesOnly bool
+
 	// Selection
 	selectedIndex int
 	cursor        int
 
-	// Scroll offset
-	scrollOffset int
-	visibleRows  int
+	// marked holds the manga IDs picked with space for a bulk status change;
+	// keyed by ID so the marks survive a refresh and tab switches
+	marked map[string]bool
+
+	// Scroll position of the entry list
+	scroll listScroll
 
 	// Loading
 	loading bool
@@ -97,6 +109,13 @@ type LibraryErrorMsg struct {
 	Error error
 }
 
+// LibraryBulkStatusMsg reports a bulk status change of the marked entries
+type LibraryBulkStatusMsg struct {
+	Status string
+	Result *models.BulkStatusResponse
+	Error  error
+}
+
 // =====================================
 // CONSTRUCTOR
 // =====================================
@@ -108,12 +127,12 @@ func NewLibrary() LibraryModel {
 	s.Style = styles.DefaultTheme.Spinner
 
 	return LibraryModel{
-		theme:       styles.DefaultTheme,
-		spinner:     s,
-		client:      api.GetClient(),
-		loading:     true,
-		activeTab:   TabReading,
-		visibleRows: 10,
+		theme:     styles.DefaultTheme,
+		spinner:   s,
+		client:    api.GetClient(),
+		loading:   true,
+		activeTab: TabReading,
+		marked:    make(map[string]bool),
 	}
 }
 
@@ -138,6 +157,25 @@ func (m LibraryModel) loadLibrary() tea.Msg {
 		return LibraryErrorMsg{Error: err}
 	}
 
+	// Library rows embed manga without genres; fill them in with one batch
+	// request (cached manga cost nothing). Best effort: rows render without.
+	ids := make([]string, len(entries))
+	for i, entry := range entries {
+		ids[i] = entry.MangaID
+	}
+	if manga, err := m.client.GetMangaBatch(ctx, ids); err == nil {
+		entries = append([]api.LibraryEntry(nil), entries...) // don't mutate the cached slice
+		byID := make(map[string]models.Manga, len(manga))
+		for _, mg := range manga {
+			byID[mg.ID] = mg
+		}
+		for i := range entries {
+			if mg, ok := byID[entries[i].MangaID]; ok {
+				entries[i].Manga = mg
+			}
+		}
+	}
+
 	return LibraryDataLoadedMsg{Entries: entries}
 }
 
@@ -149,11 +187,7 @@ func (m LibraryModel) Update(msg tea.Msg) (LibraryModel, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		// Calculate visible rows based on height
-		m.visibleRows = (m.height - 10) / 2 // Account for headers/footers
-		if m.visibleRows < 3 {
-			m.visibleRows = 3
-		}
+		m = m.updateScroll()
 
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -170,7 +204,7 @@ func (m LibraryModel) Update(msg tea.Msg) (LibraryModel, tea.Cmd) {
 		case "tab":
 			m.activeTab = (m.activeTab + 1) % LibraryTab(len(tabNames))
 			m.selectedIndex = 0
-			m.scrollOffset = 0
+			m.scroll.reset()
 			m = m.filterEntries()
 
 		case "shift+tab":
@@ -180,12 +214,12 @@ func (m LibraryModel) Update(msg tea.Msg) (LibraryModel, tea.Cmd) {
 				m.activeTab--
 			}
 			m.selectedIndex = 0
-			m.scrollOffset = 0
+			m.scroll.reset()
 			m = m.filterEntries()
 
 		case "g", "home":
 			m.selectedIndex = 0
-			m.scrollOffset = 0
+			m.scroll.reset()
 
 		case "G", "end":
 			m.selectedIndex = len(m.filteredEntries) - 1
@@ -209,54 +243,93 @@ func (m LibraryModel) Update(msg tea.Msg) (LibraryModel, tea.Cmd) {
 				return m, m.updateProgress(entry.MangaID)
 			}
 
-		case "f":
-			// Toggle favorite
+		case "U":
+			// Catch up to the latest chapter
 			if m.selectedIndex < len(m.filteredEntries) {
 				entry := m.filteredEntries[m.selectedIndex]
-				return m, m.toggleFavorite(entry.MangaID, entry.Manga.Title)
+				return m, m.catchUp(entry.MangaID)
 			}
 
-		case "1":
-			// Mark as Reading
+		case "f":
+			// Toggle favorite
 			if m.selectedIndex < len(m.filteredEntries) {
 				entry := m.filteredEntries[m.selectedIndex]
-				return m, m.changeStatus(entry.MangaID, "reading")
+				return m.toggleFavorite(entry.MangaID, !entry.IsFavorite)
 			}
 
-		case "2":
-			// Mark as Planning
-			if m.selectedIndex < len(m.filteredEntries) {
-				entry := m.filteredEntries[m.selectedIndex]
-				return m, m.changeStatus(entry.MangaID, "planning")
-			}
+		case "F":
+			// Show only favorites
+			m.favoritesOnly = !m.favoritesOnly
+			m.selectedIndex = 0
+			m.scroll.reset()
+			m = m.filterEntries()
 
-		case "3":
-			// Mark as Completed
+		case " ":
+			// Toggle the bulk selection
 			if m.selectedIndex < len(m.filteredEntries) {
-				entry := m.filteredEntries[m.selectedIndex]
-				return m, m.changeStatus(entry.MangaID, "completed")
+				id := m.filteredEntries[m.selectedIndex].MangaID
+				if m.marked[id] {
+					delete(m.marked, id)
+				} else {
+					m.marked[id] = true
+				}
 			}
 
-		case "4":
-			// Mark as On Hold
-			if m.selectedIndex < len(m.filteredEntries) {
-				entry := m.filteredEntries[m.selectedIndex]
-				return m, m.changeStatus(entry.MangaID, "on_hold")
+		case "A":
+			// Select the whole shelf
+			for _, entry := range m.filteredEntries {
+				m.marked[entry.MangaID] = true
 			}
 
-		case "5":
-			// Mark as Dropped
+		case "n":
+			// Deselect all
+			m.marked = make(map[string]bool)
+
+		case "1", "2", "3", "4", "5":
+			// Set status: 1 Reading, 2 Plan, 3 Completed, 4 On-Hold, 5 Dropped,
+			// for every marked entry or else the one under the cursor
+			status := tabStatuses[msg.String()[0]-'1']
+			if len(m.marked) > 0 {
+				m.loading = true
+				return m, m.bulkChangeStatus(status)
+			}
 			if m.selectedIndex < len(m.filteredEntries) {
 				entry := m.filteredEntries[m.selectedIndex]
-				return m, m.changeStatus(entry.MangaID, "dropped")
+				return m.changeStatus(entry.MangaID, status)
 			}
 		}
 
 	case LibraryDataLoadedMsg:
-		m.entries = msg.Entries
+		m.edits = m.edits.Load(msg.Entries)
+		m = m.applyEdits()
 		m.loading = false
+		// Keep the marks of entries still in the library
+		inLibrary := make(map[string]bool, len(m.entries))
+		for _, entry := range m.entries {
+			inLibrary[entry.MangaID] = true
+		}
+		for id := range m.marked {
+			if !inLibrary[id] {
+				delete(m.marked, id)
+			}
+		}
 		m = m.filterEntries()
 
+	case EditResultMsg:
+		return m.settleEdit(msg)
+
+	case LibraryBulkStatusMsg:
+		if msg.Error != nil {
+			m.lastError = msg.Error
+			m.loading = false
+			return m, nil
+		}
+		// Failed entries stay marked so they can be retried
+		for _, id := range msg.Result.Updated {
+			delete(m.marked, id)
+		}
+		return m, m.loadLibrary
+
 	case LibraryErrorMsg:
 		m.lastError = msg.Error
 		m.loading = false
@@ -270,19 +343,70 @@ func (m LibraryModel) Update(msg tea.Msg) (LibraryModel, tea.Cmd) {
 	return m, tea.Batch(cmds...)
 }
 
-// filterEntries filters entries by current tab
+// filterEntries filters entries by current tab (and favorites, when on)
 func (m LibraryModel) filterEntries() LibraryModel {
 	m.filteredEntries = nil
 	targetStatus := tabStatuses[m.activeTab]
 
 	for _, entry := range m.entries {
-		if entry.Status == targetStatus {
+		if entry.Status == targetStatus && m.shows(entry) {
 			m.filteredEntries = append(m.filteredEntries, entry)
 		}
 	}
 
 	m = m.clampSelection()
-	return m
+	return m.updateScroll()
+}
+
+// shows reports whether the favorites filter lets entry through
+func (m LibraryModel) shows(entry api.LibraryEntry) bool {
+	return !m.favoritesOnly || entry.IsFavorite
+}
+
+// favoritesCount counts favorites across every shelf
+func (m LibraryModel) favoritesCount() int {
+	count := 0
+	for _, entry := range m.entries {
+		if entry.IsFavorite {
+			count++
+		}
+	}
+	return count
+}
+
+// applyEdits shows the confirmed library with the pending edits on top
+func (m LibraryModel) applyEdits() LibraryModel {
+	m.entries = m.edits.Value()
+	return m.filterEntries()
+}
+
+// settleEdit folds in or rolls back this view's own edits, and picks up
+// changes the detail view got confirmed
+func (m LibraryModel) settleEdit(msg EditResultMsg) (LibraryModel, tea.Cmd) {
+	if m.edits.Owns(msg.EditID) {
+		if msg.Error != nil {
+			m.edits = m.edits.Rollback(msg.EditID)
+			return m.applyEdits(), nil
+		}
+		m.edits = m.edits.Confirm(msg.EditID)
+		m = m.applyEdits()
+		if msg.Action == EditStatus {
+			// Pick up the server's timestamps for the next conflict check
+			return m, m.loadLibrary
+		}
+		return m, nil
+	}
+	if msg.Error != nil {
+		return m, nil
+	}
+	switch msg.Action {
+	case EditFavorite:
+		m.edits = m.edits.Amend(editEntries(msg.MangaID, setFavorite(msg.Favorite)))
+		return m.applyEdits(), nil
+	case EditAdd, EditStatus, EditChapter:
+		return m, m.loadLibrary
+	}
+	return m, nil
 }
 
 // clampSelection ensures selection is within bounds
@@ -300,17 +424,17 @@ func (m LibraryModel) clampSelection() LibraryModel {
 	return m
 }
 
-// updateScroll updates scroll offset based on selection
+// updateScroll keeps the selection in view
 func (m LibraryModel) updateScroll() LibraryModel {
-	if m.selectedIndex < m.scrollOffset {
-		m.scrollOffset = m.selectedIndex
-	}
-	if m.selectedIndex >= m.scrollOffset+m.visibleRows {
-		m.scrollOffset = m.selectedIndex - m.visibleRows + 1
-	}
+	m.scroll.follow(m.selectedIndex, len(m.filteredEntries), m.listRows())
 	return m
 }
 
+// listRows is how many entry rows fit below the tabs, header and footer
+func (m LibraryModel) listRows() int {
+	return max((m.height-10)/2, 1)
+}
+
 // View renders the library view
 func (m LibraryModel) View() string {
 	// Render tabs
@@ -337,7 +461,7 @@ func (m LibraryModel) renderTabs() string {
 		// Count entries for this tab
 		count := 0
 		for _, entry := range m.entries {
-			if entry.Status == tabStatuses[i] {
+			if entry.Status == tabStatuses[i] && m.shows(entry) {
 				count++
 			}
 		}
@@ -356,6 +480,15 @@ func (m LibraryModel) renderTabs() string {
 		tabs = append(tabs, style.Render(label))
 	}
 
+	// Favorites count; highlighted while the favorites filter is on
+	favorites := fmt.Sprintf(" ★ Favorites (%d) ", m.favoritesCount())
+	if m.favoritesOnly {
+		favorites = m.theme.ActiveTab.Render(favorites)
+	} else {
+		favorites = m.theme.DimText.Render(favorites)
+	}
+	tabs = append(tabs, "   ", favorites)
+
 	// Join tabs with separator
 	tabBar := lipgloss.JoinHorizontal(lipgloss.Bottom, tabs...)
 
@@ -368,14 +501,18 @@ func (m LibraryModel) renderTabs() string {
 // renderContent renders the manga list
 func (m LibraryModel) renderContent() string {
 	if m.loading {
-		return m.theme.Container.Width(m.width - 4).Height(m.visibleRows + 2).Render(
+		return m.theme.Container.Width(m.width - 4).Height(m.listRows() + 2).Render(
 			m.spinner.View() + " Loading library...")
 	}
 
 	if len(m.filteredEntries) == 0 {
 		emptyMsg := fmt.Sprintf("No manga in '%s' shelf.\n\nAdd manga from Search or Browse.",
 			tabNames[m.activeTab])
-		return m.theme.Container.Width(m.width - 4).Height(m.visibleRows + 2).Render(
+		if m.favoritesOnly {
+			emptyMsg = fmt.Sprintf("No favorites in '%s' shelf.\n\nPress f on a manga to favorite it, F to show all.",
+				tabNames[m.activeTab])
+		}
+		return m.theme.Container.Width(m.width - 4).Height(m.listRows() + 2).Render(
 			m.theme.DimText.Render(emptyMsg))
 	}
 
@@ -384,7 +521,7 @@ func (m LibraryModel) renderContent() string {
 
 	// Header row
 	headerStyle := m.theme.DimText.Bold(true)
-	header := fmt.Sprintf("  %-30s %-15s %-12s",
+	header := fmt.Sprintf("      %-30s %-15s %-12s",
 		headerStyle.Render("TITLE"),
 		headerStyle.Render("PROGRESS"),
 		headerStyle.Render("RATING"))
@@ -394,21 +531,15 @@ func (m LibraryModel) renderContent() string {
 	rows = append(rows, m.theme.DimText.Render(repeatString("─", m.width-8)))
 
 	// Entry rows
-	endIndex := m.scrollOffset + m.visibleRows
-	if endIndex > len(m.filteredEntries) {
-		endIndex = len(m.filteredEntries)
-	}
-
-	for i := m.scrollOffset; i < endIndex; i++ {
+	start, end := m.scroll.window(m.selectedIndex, len(m.filteredEntries), m.listRows())
+	for i := start; i < end; i++ {
 		entry := m.filteredEntries[i]
 		rows = append(rows, m.renderEntryRow(i, entry))
 	}
 
 	// Scroll indicator
-	if len(m.filteredEntries) > m.visibleRows {
-		scrollInfo := fmt.Sprintf("  Showing %d-%d of %d",
-			m.scrollOffset+1, endIndex, len(m.filteredEntries))
-		rows = append(rows, m.theme.DimText.Render(scrollInfo))
+	if status := scrollStatus(start, end, len(m.filteredEntries)); status != "" {
+		rows = append(rows, m.theme.DimText.Render("  "+status))
 	}
 
 	content := lipgloss.JoinVertical(lipgloss.Left, rows...)
@@ -424,6 +555,11 @@ func (m LibraryModel) renderEntryRow(index int, entry api.LibraryEntry) string {
 		prefix = "▶ "
 		style = m.theme.ListItemSelected
 	}
+	if m.marked[entry.MangaID] {
+		prefix += "[x] "
+	} else {
+		prefix += "[ ] "
+	}
 
 	// Title (truncated)
 	title := truncateLib(entry.Manga.Title, 28)
@@ -451,32 +587,56 @@ func (m LibraryModel) renderEntryRow(index int, entry api.LibraryEntry) string {
 		rating = m.theme.DimText.Render("Unrated")
 	}
 
+	// Primary genre (filled in from the batch manga lookup)
+	genre := ""
+	if len(entry.Manga.Genres) > 0 {
+		genre = "  " + m.theme.DimText.Render(entry.Manga.Genres[0].Name)
+	}
+
 	// Build row
-	row := fmt.Sprintf("%s%-28s %-8s %s  %s",
-		prefix, title, progress, progressBar, rating)
+	row := fmt.Sprintf("%s%-28s %-8s %s  %s%s",
+		prefix, title, progress, progressBar, rating, genre)
 
 	return style.Render(row)
 }
 
 // renderFooter renders the action hints footer
 func (m LibraryModel) renderFooter() string {
+	if len(m.marked) > 0 {
+		return m.theme.Footer.Render(joinHints(m.theme, []string{
+			m.theme.Primary.Bold(true).Render(fmt.Sprintf("%d selected", len(m.marked))),
+			styles.RenderKeyHint("1-5", "Set status"),
+			styles.RenderKeyHint("Space", "Toggle"),
+			styles.RenderKeyHint("A", "Select shelf"),
+			styles.RenderKeyHint("n", "Clear"),
+		}))
+	}
+
 	hints := []string{
 		styles.RenderKeyHint("Enter", "Details"),
+		styles.RenderKeyHint("Space", "Select"),
 		styles.RenderKeyHint("u", "Update"),
+		styles.RenderKeyHint("U", "Catch up"),
 		styles.RenderKeyHint("d", "Delete"),
+		styles.RenderKeyHint("f", "Favorite"),
+		styles.RenderKeyHint("F", "Favorites only"),
 		styles.RenderKeyHint("Tab", "Next Tab"),
 		styles.RenderKeyHint("r", "Refresh"),
 	}
 
+	return m.theme.Footer.Render(joinHints(m.theme, hints))
+}
+
+// joinHints joins footer key hints with separators
+func joinHints(t *styles.Theme, hints []string) string {
 	hintsStr := ""
 	for i, hint := range hints {
 		if i > 0 {
-			hintsStr += m.theme.DimText.Render("  │  ")
+			hintsStr += t.DimText.Render("  │  ")
 		}
 		hintsStr += hint
 	}
-
-	return m.theme.Footer.Render(hintsStr)
+	return hintsStr
 }
 
 // =====================================
@@ -509,6 +669,12 @@ func (m LibraryModel) GetSelectedEntry() *api.LibraryEntry {
 	return nil
 }
 
+// SetTheme switches the view to a new theme
+func (m *LibraryModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
+}
+
 // SetWidth sets the library width
 func (m *LibraryModel) SetWidth(w int) {
 	m.width = w
@@ -517,27 +683,73 @@ func (m *LibraryModel) SetWidth(w int) {
 // SetHeight sets the library height
 func (m *LibraryModel) SetHeight(h int) {
 	m.height = h
-	m.visibleRows = (h - 10) / 2
-	if m.visibleRows < 3 {
-		m.visibleRows = 3
-	}
+	*m = m.updateScroll()
 }
 
 // =====================================
 // LIBRARY ACTIONS
 // =====================================
 
-// changeStatus changes the reading status of a manga
-func (m LibraryModel) changeStatus(mangaID string, newStatus string) tea.Cmd {
+// changeStatus moves a manga to another shelf at once; the server's answer
+// confirms the move or puts it back
+func (m LibraryModel) changeStatus(mangaID string, newStatus string) (LibraryModel, tea.Cmd) {
+	var id int
+	m.edits, id = m.edits.Begin(editEntries(mangaID, setStatus(newStatus)))
+	client := m.client
+	return m.applyEdits(), func() tea.Msg {
+		err := client.UpdateLibraryStatus(context.Background(), mangaID, newStatus)
+		return EditResultMsg{EditID: id, MangaID: mangaID, Action: EditStatus, Status: newStatus, Error: err}
+	}
+}
+
+// bulkChangeStatus moves every marked entry to newStatus with one request
+func (m LibraryModel) bulkChangeStatus(newStatus string) tea.Cmd {
+	ids := make([]string, 0, len(m.marked))
+	for _, entry := range m.entries { // library order, so failures read in order
+		if m.marked[entry.MangaID] {
+			ids = append(ids, entry.MangaID)
+		}
+	}
 	return func() tea.Msg {
-		ctx := context.Background()
-		err := m.client.UpdateLibraryStatus(ctx, mangaID, newStatus)
-		if err != nil {
-			return LibraryErrorMsg{Error: err}
+		result, err := m.client.BulkUpdateStatus(context.Background(), ids, newStatus)
+		return LibraryBulkStatusMsg{Status: newStatus, Result: result, Error: err}
+	}
+}
+
+// BulkStatusSummary describes a bulk status change for a toast, naming the
+// manga that were not updated
+func (m LibraryModel) BulkStatusSummary(msg LibraryBulkStatusMsg) string {
+	if msg.Error != nil {
+		return fmt.Sprintf("Bulk status change failed: %v", msg.Error)
+	}
+	shelf := msg.Status
+	for i, status := range tabStatuses {
+		if status == msg.Status {
+			shelf = tabNames[i]
 		}
-		// Reload library
-		return m.loadLibrary()
 	}
+	summary := fmt.Sprintf("Moved %d manga to %s", len(msg.Result.Updated), shelf)
+	if len(msg.Result.Failed) == 0 {
+		return summary
+	}
+
+	titles := make(map[string]string, len(m.entries))
+	for _, entry := range m.entries {
+		titles[entry.MangaID] = entry.Manga.Title
+	}
+	var failed []string
+	for i, f := range msg.Result.Failed {
+		if i == 3 {
+			failed = append(failed, fmt.Sprintf("%d more", len(msg.Result.Failed)-i))
+			break
+		}
+		name := titles[f.MangaID]
+		if name == "" {
+			name = f.MangaID
+		}
+		failed = append(failed, fmt.Sprintf("%s (%s)", name, f.Error))
+	}
+	return summary + "; not updated: " + strings.Join(failed, ", ")
 }
 
 // updateProgress updates the reading progress
@@ -551,16 +763,18 @@ func (m LibraryModel) updateProgress(mangaID string) tea.Cmd {
 		var currentChapter int
 		var currentStatus string
 		var isFavorite bool
+		var seen time.Time
 		for _, entry := range m.filteredEntries {
 			if entry.MangaID == mangaID {
 				currentChapter = entry.CurrentChapter + 1
 				currentStatus = entry.Status
 				isFavorite = entry.IsFavorite
+				seen = entry.UpdatedAt
 				break
 			}
 		}
 
-		err := m.client.UpdateProgress(ctx, mangaID, currentChapter, currentStatus, isFavorite)
+		_, err := m.client.UpdateProgress(ctx, mangaID, currentChapter, currentStatus, isFavorite, seen)
 		if err != nil {
 			return LibraryErrorMsg{Error: err}
 		}
@@ -569,26 +783,24 @@ func (m LibraryModel) updateProgress(mangaID string) tea.Cmd {
 	}
 }
 
-// toggleFavorite toggles the favorite status
-func (m LibraryModel) toggleFavorite(mangaID string, title string) tea.Cmd {
+// catchUp marks every chapter up to the latest as read
+func (m LibraryModel) catchUp(mangaID string) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
-
-		// Find current favorite status
-		var isFavorite bool
-		for _, entry := range m.filteredEntries {
-			if entry.MangaID == mangaID {
-				// Assuming there's a favorite field - toggle it
-				isFavorite = !isFavorite // Toggle
-				break
-			}
-		}
-
-		err := m.client.ToggleFavorite(ctx, mangaID, isFavorite)
-		if err != nil {
+		if _, err := m.client.CatchUpProgress(context.Background(), mangaID); err != nil {
 			return LibraryErrorMsg{Error: err}
 		}
 		// Reload library
 		return m.loadLibrary()
 	}
 }
+
+// toggleFavorite marks or unmarks a manga as favorite, shown before the server answers
+func (m LibraryModel) toggleFavorite(mangaID string, isFavorite bool) (LibraryModel, tea.Cmd) {
+	var id int
+	m.edits, id = m.edits.Begin(editEntries(mangaID, setFavorite(isFavorite)))
+	client := m.client
+	return m.applyEdits(), func() tea.Msg {
+		err := client.ToggleFavorite(context.Background(), mangaID, isFavorite)
+		return EditResultMsg{EditID: id, MangaID: mangaID, Action: EditFavorite, Favorite: isFavorite, Error: err}
+	}
+}
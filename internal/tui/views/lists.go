@@ -0,0 +1,484 @@
+// Package views - Custom Lists View
+// Quản lý custom lists: tạo, xoá, public/private, sắp xếp manga trong list
+// Layout:
+//
+//	┌────────────────────────────────────────────────────────┐
+//	│  📋 MY LISTS                                           │
+//	│                                                        │
+//	│  > 🏆 Top 10 (3) 🌐      1. Berserk                   │
+//	│    ❤️ Favorites (12)    > 2. Vagabond                  │
+//	│    📋 Plan to Read (4)     3. Monster                  │
+//	│                                                        │
+//	│  [n] New  [p] Public  [d] Delete  [Tab] Items          │
+//	└────────────────────────────────────────────────────────┘
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"mangahub/internal/tui/api"
+	"mangahub/internal/tui/styles"
+	"mangahub/pkg/models"
+)
+
+// Panes of the lists view
+const (
+	listsPaneLists = iota
+	listsPaneItems
+)
+
+// =====================================
+// LISTS MODEL
+// =====================================
+
+// ListsModel holds the custom lists view state
+type ListsModel struct {
+	width  int
+	height int
+	theme  *styles.Theme
+
+	// Data
+	lists []models.CustomList
+	items []models.CustomListWithManga
+
+	// Selection
+	pane         int
+	selectedList int
+	selectedItem int
+
+	// New list name
+	nameInput textinput.Model
+
+	// UI state
+	loading       bool
+	confirmDelete bool
+	message       string
+	lastError     error
+	spinner       spinner.Model
+
+	client *api.Client
+}
+
+// =====================================
+// MESSAGES
+// =====================================
+
+// listsLoadedMsg carries the user's lists
+type listsLoadedMsg struct {
+	Lists []models.CustomList
+	Error error
+}
+
+// listItemsLoadedMsg carries the manga of one list
+type listItemsLoadedMsg struct {
+	ListID string
+	Items  []models.CustomListWithManga
+	Error  error
+}
+
+// listActionDoneMsg reports a list mutation; lists and items are reloaded afterwards
+type listActionDoneMsg struct {
+	Message string
+	Error   error
+}
+
+// =====================================
+// CONSTRUCTOR
+// =====================================
+
+// NewLists creates a new lists model
+func NewLists() ListsModel {
+	ti := textinput.New()
+	ti.Placeholder = "New list name"
+	ti.CharLimit = 100
+	ti.Width = 40
+	ti.PromptStyle = styles.DefaultTheme.Primary
+	ti.TextStyle = styles.DefaultTheme.Description
+	ti.PlaceholderStyle = styles.DefaultTheme.DimText
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = styles.DefaultTheme.Spinner
+
+	return ListsModel{
+		theme:     styles.DefaultTheme,
+		nameInput: ti,
+		spinner:   s,
+		client:    api.GetClient(),
+	}
+}
+
+// =====================================
+// BUBBLE TEA INTERFACE
+// =====================================
+
+// Init loads the user's lists
+func (m ListsModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, m.loadLists)
+}
+
+// Update handles messages
+func (m ListsModel) Update(msg tea.Msg) (ListsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		if m.nameInput.Focused() {
+			return m.updateNameInput(msg)
+		}
+
+		// Any key other than a second "d" cancels a pending delete
+		if msg.String() != "d" {
+			m.confirmDelete = false
+		}
+
+		if m.pane == listsPaneItems {
+			return m.updateItemsPane(msg)
+		}
+		return m.updateListsPane(msg)
+
+	case listsLoadedMsg:
+		m.loading = false
+		m.lastError = msg.Error
+		if msg.Error != nil {
+			return m, nil
+		}
+		m.lists = msg.Lists
+		if m.selectedList >= len(m.lists) {
+			m.selectedList = max(0, len(m.lists)-1)
+		}
+		if list := m.currentList(); list != nil {
+			return m, m.loadItems(list.ID)
+		}
+		m.items = nil
+		m.pane = listsPaneLists
+
+	case listItemsLoadedMsg:
+		// Ignore results for a list that is no longer selected
+		if list := m.currentList(); list == nil || list.ID != msg.ListID {
+			return m, nil
+		}
+		m.lastError = msg.Error
+		if msg.Error == nil {
+			m.items = msg.Items
+			if m.selectedItem >= len(m.items) {
+				m.selectedItem = max(0, len(m.items)-1)
+			}
+		}
+
+	case listActionDoneMsg:
+		m.lastError = msg.Error
+		if msg.Error == nil {
+			m.message = msg.Message
+		}
+		return m, m.loadLists
+
+	case spinner.TickMsg:
+		if m.loading {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+	}
+
+	return m, nil
+}
+
+// updateNameInput handles typing a new list name
+func (m ListsModel) updateNameInput(msg tea.KeyMsg) (ListsModel, tea.Cmd) {
+	if msg.String() == "enter" {
+		name := strings.TrimSpace(m.nameInput.Value())
+		m.nameInput.Blur()
+		m.nameInput.SetValue("")
+		if name == "" {
+			return m, nil
+		}
+		return m, m.runAction("Created "+name, func(ctx context.Context) error {
+			return m.client.CreateList(ctx, models.CreateListRequest{Name: name})
+		})
+	}
+	var cmd tea.Cmd
+	m.nameInput, cmd = m.nameInput.Update(msg)
+	return m, cmd
+}
+
+// updateListsPane handles keys while the list column is focused
+func (m ListsModel) updateListsPane(msg tea.KeyMsg) (ListsModel, tea.Cmd) {
+	switch msg.String() {
+	case "up", "k":
+		if m.selectedList > 0 {
+			m.selectedList--
+			m.selectedItem = 0
+			return m, m.loadItems(m.lists[m.selectedList].ID)
+		}
+	case "down", "j":
+		if m.selectedList < len(m.lists)-1 {
+			m.selectedList++
+			m.selectedItem = 0
+			return m, m.loadItems(m.lists[m.selectedList].ID)
+		}
+	case "tab", "right", "enter":
+		if len(m.items) > 0 {
+			m.pane = listsPaneItems
+		}
+	case "n":
+		m.message = ""
+		return m, m.nameInput.Focus()
+	case "p":
+		if list := m.currentList(); list != nil {
+			public := !list.IsPublic
+			label := "private"
+			if public {
+				label = "public"
+			}
+			return m, m.runAction(list.Name+" is now "+label, func(ctx context.Context) error {
+				return m.client.UpdateList(ctx, list.ID, models.UpdateListRequest{IsPublic: &public})
+			})
+		}
+	case "d":
+		list := m.currentList()
+		if list == nil {
+			return m, nil
+		}
+		if !m.confirmDelete {
+			m.confirmDelete = true
+			m.message = fmt.Sprintf("Press d again to delete %q", list.Name)
+			return m, nil
+		}
+		m.confirmDelete = false
+		return m, m.runAction("Deleted "+list.Name, func(ctx context.Context) error {
+			return m.client.DeleteList(ctx, list.ID)
+		})
+	case "r":
+		m.loading = true
+		return m, tea.Batch(m.spinner.Tick, m.loadLists)
+	}
+	return m, nil
+}
+
+// updateItemsPane handles keys while the manga column is focused
+func (m ListsModel) updateItemsPane(msg tea.KeyMsg) (ListsModel, tea.Cmd) {
+	list := m.currentList()
+	if list == nil {
+		m.pane = listsPaneLists
+		return m, nil
+	}
+
+	switch msg.String() {
+	case "up", "k":
+		if m.selectedItem > 0 {
+			m.selectedItem--
+		}
+	case "down", "j":
+		if m.selectedItem < len(m.items)-1 {
+			m.selectedItem++
+		}
+	case "tab", "left":
+		m.pane = listsPaneLists
+	case "K", "J":
+		// Move the selected manga up or down and save the new order
+		to := m.selectedItem - 1
+		if msg.String() == "J" {
+			to = m.selectedItem + 1
+		}
+		if to < 0 || to >= len(m.items) {
+			return m, nil
+		}
+		m.items[m.selectedItem], m.items[to] = m.items[to], m.items[m.selectedItem]
+		m.selectedItem = to
+		ids := make([]string, len(m.items))
+		for i, item := range m.items {
+			ids[i] = item.ID
+		}
+		return m, m.runAction("", func(ctx context.Context) error {
+			return m.client.ReorderList(ctx, list.ID, ids)
+		})
+	case "d":
+		if m.selectedItem >= len(m.items) {
+			return m, nil
+		}
+		item := m.items[m.selectedItem]
+		return m, m.runAction("Removed "+item.Manga.Title, func(ctx context.Context) error {
+			return m.client.RemoveFromList(ctx, list.ID, item.MangaID)
+		})
+	}
+	return m, nil
+}
+
+// =====================================
+// COMMANDS
+// =====================================
+
+func (m ListsModel) loadLists() tea.Msg {
+	lists, err := m.client.GetLists(context.Background())
+	return listsLoadedMsg{Lists: lists, Error: err}
+}
+
+func (m ListsModel) loadItems(listID string) tea.Cmd {
+	return func() tea.Msg {
+		list, err := m.client.GetList(context.Background(), listID)
+		if err != nil {
+			return listItemsLoadedMsg{ListID: listID, Error: err}
+		}
+		return listItemsLoadedMsg{ListID: listID, Items: list.Items}
+	}
+}
+
+// runAction performs a list mutation in the background
+func (m ListsModel) runAction(message string, fn func(ctx context.Context) error) tea.Cmd {
+	return func() tea.Msg {
+		return listActionDoneMsg{Message: message, Error: fn(context.Background())}
+	}
+}
+
+// =====================================
+// ACCESSORS
+// =====================================
+
+// currentList returns the selected list, or nil when there are none
+func (m ListsModel) currentList() *models.CustomList {
+	if m.selectedList < 0 || m.selectedList >= len(m.lists) {
+		return nil
+	}
+	return &m.lists[m.selectedList]
+}
+
+// GetSelectedMangaID returns the highlighted manga when the items pane is focused
+func (m ListsModel) GetSelectedMangaID() string {
+	if m.pane != listsPaneItems || m.selectedItem >= len(m.items) {
+		return ""
+	}
+	return m.items[m.selectedItem].MangaID
+}
+
+// IsInputFocused reports whether the new list name input is focused
+func (m ListsModel) IsInputFocused() bool {
+	return m.nameInput.Focused()
+}
+
+// =====================================
+// VIEW
+// =====================================
+
+// View renders the lists view
+func (m ListsModel) View() string {
+	var sections []string
+
+	sections = append(sections, m.theme.PanelHeader.Render("📋 MY LISTS"))
+
+	if m.loading && len(m.lists) == 0 {
+		sections = append(sections, m.spinner.View()+" Loading lists...")
+	} else {
+		colWidth := max(20, (m.width-8)/2)
+		sections = append(sections, lipgloss.JoinHorizontal(lipgloss.Top,
+			lipgloss.NewStyle().Width(colWidth).Render(m.renderLists()),
+			lipgloss.NewStyle().Width(colWidth).Render(m.renderItems()),
+		))
+	}
+
+	if m.nameInput.Focused() {
+		inputStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(styles.ColorPrimary).
+			Padding(0, 1).
+			Width(m.width - 10)
+		sections = append(sections, inputStyle.Render(m.nameInput.View()))
+	}
+
+	if m.lastError != nil {
+		sections = append(sections, m.theme.ErrorText.Render("⚠ "+m.lastError.Error()))
+	} else if m.message != "" {
+		sections = append(sections, m.theme.Description.Render(m.message))
+	}
+
+	sections = append(sections, m.renderHelp())
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return m.theme.Container.Width(m.width - 4).Render(content)
+}
+
+// =====================================
+// RENDERERS
+// =====================================
+
+func (m ListsModel) renderLists() string {
+	if len(m.lists) == 0 {
+		return m.theme.DimText.Render("No lists yet. Press [n] to create one.")
+	}
+
+	var rows []string
+	for i, list := range m.lists {
+		label := fmt.Sprintf("%s (%d)", list.Name, list.ItemCount)
+		if list.Icon != "" {
+			label = list.Icon + " " + label
+		}
+		if list.IsPublic {
+			label += " 🌐"
+		}
+
+		switch {
+		case i == m.selectedList && m.pane == listsPaneLists:
+			rows = append(rows, m.theme.Primary.Render("> "+label))
+		case i == m.selectedList:
+			rows = append(rows, m.theme.Description.Render("• "+label))
+		default:
+			rows = append(rows, "  "+label)
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m ListsModel) renderItems() string {
+	if m.currentList() == nil {
+		return ""
+	}
+	if len(m.items) == 0 {
+		return m.theme.DimText.Render("This list is empty.")
+	}
+
+	var rows []string
+	for i, item := range m.items {
+		label := fmt.Sprintf("%d. %s", i+1, truncate(item.Manga.Title, 30))
+		if i == m.selectedItem && m.pane == listsPaneItems {
+			rows = append(rows, m.theme.Primary.Render("> "+label))
+		} else {
+			rows = append(rows, "  "+label)
+		}
+	}
+	return strings.Join(rows, "\n")
+}
+
+func (m ListsModel) renderHelp() string {
+	if m.nameInput.Focused() {
+		return styles.RenderKeyHint("Enter", "create") + "  " + styles.RenderKeyHint("Esc", "back")
+	}
+	if m.pane == listsPaneItems {
+		return strings.Join([]string{
+			styles.RenderKeyHint("Enter", "open"),
+			styles.RenderKeyHint("J/K", "move"),
+			styles.RenderKeyHint("d", "remove"),
+			styles.RenderKeyHint("Tab", "lists"),
+		}, "  ")
+	}
+	return strings.Join([]string{
+		styles.RenderKeyHint("n", "new"),
+		styles.RenderKeyHint("p", "public/private"),
+		styles.RenderKeyHint("d", "delete"),
+		styles.RenderKeyHint("Tab", "items"),
+	}, "  ")
+}
+
+// SetTheme switches the view to a new theme
+func (m *ListsModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
+}
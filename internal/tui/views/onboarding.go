@@ -0,0 +1,206 @@
+// Package views - Onboarding Component
+// Màn hình chào mừng lần chạy đầu tiên: chọn theme, view mặc định, rồi login/register/guest
+// Esc bỏ qua bất cứ lúc nào; app vẫn load phía sau trong lúc hiển thị
+package views
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"mangahub/internal/tui/styles"
+	"mangahub/pkg/models"
+)
+
+// What to do after onboarding (OnboardingDoneMsg.Next)
+const (
+	OnboardingLogin    = "login"
+	OnboardingRegister = "register"
+	OnboardingGuest    = "guest"
+)
+
+// onboardingAccountChoices are the account step's options, in display order
+var onboardingAccountChoices = []string{OnboardingLogin, OnboardingRegister, OnboardingGuest}
+
+// Onboarding steps
+const (
+	onboardingTheme = iota
+	onboardingHomeView
+	onboardingAccount
+	onboardingSteps
+)
+
+// OnboardingModel holds the first-run screen state
+type OnboardingModel struct {
+	step     int
+	theme    string
+	homeView string
+	account  string
+	active   bool
+	width    int
+	height   int
+	styles   *styles.Theme
+}
+
+// OnboardingDoneMsg reports the choices once onboarding is finished or dismissed.
+// A dismissed onboarding keeps the defaults and continues as guest.
+type OnboardingDoneMsg struct {
+	Theme       string
+	DefaultView string
+	Next        string // OnboardingLogin, OnboardingRegister or OnboardingGuest
+	Skipped     bool
+}
+
+// NewOnboarding creates the first-run screen with the default choices
+func NewOnboarding() OnboardingModel {
+	return OnboardingModel{
+		theme:    styles.ThemeNames()[0],
+		homeView: models.HomeViewDashboard,
+		account:  OnboardingLogin,
+		active:   true,
+		styles:   styles.DefaultTheme,
+	}
+}
+
+// cycleOption returns the option next to current in options, dir +1 or -1
+func cycleOption(options []string, current string, dir int) string {
+	for i, option := range options {
+		if option == current {
+			return options[(i+dir+len(options))%len(options)]
+		}
+	}
+	return options[0]
+}
+
+// Update handles messages
+func (m OnboardingModel) Update(msg tea.Msg) (OnboardingModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		dir := 0
+		switch msg.String() {
+		case "esc", "q":
+			m.active = false
+			return m, m.done(true)
+		case "left", "h", "up", "k":
+			dir = -1
+		case "right", "l", "down", "j", "tab":
+			dir = 1
+		case "backspace", "shift+tab":
+			if m.step > 0 {
+				m.step--
+			}
+			return m, nil
+		case "enter":
+			if m.step < onboardingSteps-1 {
+				m.step++
+				return m, nil
+			}
+			m.active = false
+			return m, m.done(false)
+		}
+		if dir == 0 {
+			return m, nil
+		}
+
+		switch m.step {
+		case onboardingTheme:
+			// Preview the theme right away
+			m.theme = cycleOption(styles.ThemeNames(), m.theme, dir)
+			name := m.theme
+			return m, func() tea.Msg { return ThemeChangedMsg{Name: name} }
+		case onboardingHomeView:
+			m.homeView = cycleOption(models.HomeViews, m.homeView, dir)
+		case onboardingAccount:
+			m.account = cycleOption(onboardingAccountChoices, m.account, dir)
+		}
+	}
+	return m, nil
+}
+
+// done reports the choices; skipping keeps the defaults
+func (m OnboardingModel) done(skipped bool) tea.Cmd {
+	result := OnboardingDoneMsg{Theme: m.theme, DefaultView: m.homeView, Next: m.account, Skipped: skipped}
+	if skipped {
+		result = OnboardingDoneMsg{Next: OnboardingGuest, Skipped: true}
+	}
+	return func() tea.Msg { return result }
+}
+
+// View renders the onboarding screen
+func (m OnboardingModel) View() string {
+	if !m.active {
+		return ""
+	}
+	t := m.styles
+
+	var sections []string
+	sections = append(sections, t.Title.Render("Welcome to MangaHub"))
+	sections = append(sections, t.DimText.Render("Track your manga, chat with readers and get notified of new chapters."))
+	sections = append(sections, t.DimText.Render(fmt.Sprintf("Step %d of %d", m.step+1, onboardingSteps)), "")
+
+	switch m.step {
+	case onboardingTheme:
+		sections = append(sections, t.Primary.Bold(true).Render("Pick a theme"))
+		sections = append(sections, m.renderChoices(styles.ThemeNames(), m.theme, nil))
+	case onboardingHomeView:
+		sections = append(sections, t.Primary.Bold(true).Render("Open on startup"))
+		sections = append(sections, m.renderChoices(models.HomeViews, m.homeView, nil))
+	case onboardingAccount:
+		sections = append(sections, t.Primary.Bold(true).Render("Your account"))
+		sections = append(sections, m.renderChoices(onboardingAccountChoices, m.account, map[string]string{
+			OnboardingLogin:    "Log in",
+			OnboardingRegister: "Create an account",
+			OnboardingGuest:    "Browse as guest",
+		}))
+		sections = append(sections, t.DimText.Render("Your theme and startup view are saved to your account once you log in."))
+	}
+
+	help := "←/→: choose | Enter: next | Backspace: back | Esc: skip"
+	if m.step == onboardingSteps-1 {
+		help = "←/→: choose | Enter: finish | Backspace: back | Esc: skip"
+	}
+	sections = append(sections, "", t.DimText.Render(help))
+
+	box := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(t.Palette.Primary).
+		Padding(1, 3).
+		Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
+	if m.width > 0 && m.height > 0 {
+		return lipgloss.Place(m.width, m.height, lipgloss.Center, lipgloss.Center, box)
+	}
+	return box
+}
+
+// renderChoices lists options on one line, the selected one highlighted
+func (m OnboardingModel) renderChoices(options []string, selected string, labels map[string]string) string {
+	parts := make([]string, 0, len(options))
+	for _, option := range options {
+		label := option
+		if l, ok := labels[option]; ok {
+			label = l
+		}
+		if option == selected {
+			parts = append(parts, m.styles.Primary.Bold(true).Render("▶ "+label))
+		} else {
+			parts = append(parts, m.styles.DimText.Render("  "+label))
+		}
+	}
+	return strings.Join(parts, "  ")
+}
+
+// IsActive returns whether onboarding is still shown
+func (m OnboardingModel) IsActive() bool {
+	return m.active
+}
+
+// SetTheme switches the screen to a new theme (previewed while choosing)
+func (m *OnboardingModel) SetTheme(t *styles.Theme) {
+	m.styles = t
+}
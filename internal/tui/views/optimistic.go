@@ -0,0 +1,165 @@
+// Package views - Optimistic Edits
+// Library/rating changes show at once and are settled when the server answers
+// Thay đổi bị server từ chối được hoàn tác về đúng trạng thái trước đó
+package views
+
+import (
+	"mangahub/internal/tui/api"
+)
+
+// Optimistic edit actions
+const (
+	EditAdd      = "add"
+	EditFavorite = "favorite"
+	EditStatus   = "status"
+	EditChapter  = "chapter"
+	EditRate     = "rate"
+)
+
+// EditResultMsg is the server's answer to an optimistic edit. The view that
+// made the edit settles it by EditID; the other view picks up a confirmed
+// change in place.
+type EditResultMsg struct {
+	EditID   int
+	MangaID  string
+	Action   string // EditAdd, EditFavorite, EditStatus, EditChapter or EditRate
+	Favorite bool   // EditFavorite
+	Status   string // EditStatus
+	Chapter  int    // EditChapter
+	Rating   int    // EditRate
+	Error    error
+}
+
+// lastEditID numbers edits across every view, so a result can only ever
+// settle the edit that sent it. Only Update methods touch it.
+var lastEditID int
+
+// pendingEdit is a local change the server has not answered yet
+type pendingEdit[T any] struct {
+	id    int
+	apply func(T) T
+}
+
+// optimistic holds the last value the server confirmed plus the edits still
+// in flight. The shown value is the confirmed one with the pending edits
+// replayed in order, so dropping one restores exactly what the others leave
+// behind, however the answers interleave. Methods return copies: apply
+// functions must not mutate their argument either.
+type optimistic[T any] struct {
+	confirmed T
+	pending   []pendingEdit[T]
+}
+
+// Value is what the view shows
+func (o optimistic[T]) Value() T {
+	v := o.confirmed
+	for _, edit := range o.pending {
+		v = edit.apply(v)
+	}
+	return v
+}
+
+// Pending reports whether any edit is waiting on the server
+func (o optimistic[T]) Pending() bool {
+	return len(o.pending) > 0
+}
+
+// Owns reports whether id is one of this value's pending edits
+func (o optimistic[T]) Owns(id int) bool {
+	return o.index(id) >= 0
+}
+
+// Begin applies an edit locally and returns its ID for the result message
+func (o optimistic[T]) Begin(apply func(T) T) (optimistic[T], int) {
+	lastEditID++
+	o.pending = append(o.pending[:len(o.pending):len(o.pending)], pendingEdit[T]{id: lastEditID, apply: apply})
+	return o, lastEditID
+}
+
+// Confirm folds an accepted edit into the confirmed value
+func (o optimistic[T]) Confirm(id int) optimistic[T] {
+	if i := o.index(id); i >= 0 {
+		o.confirmed = o.pending[i].apply(o.confirmed)
+		o.pending = o.without(i)
+	}
+	return o
+}
+
+// Rollback drops a rejected edit
+func (o optimistic[T]) Rollback(id int) optimistic[T] {
+	if i := o.index(id); i >= 0 {
+		o.pending = o.without(i)
+	}
+	return o
+}
+
+// Load replaces the confirmed value with fresh server data; edits still in
+// flight stay applied on top
+func (o optimistic[T]) Load(v T) optimistic[T] {
+	o.confirmed = v
+	return o
+}
+
+// Amend applies a change confirmed elsewhere (another view's edit)
+func (o optimistic[T]) Amend(apply func(T) T) optimistic[T] {
+	o.confirmed = apply(o.confirmed)
+	return o
+}
+
+func (o optimistic[T]) index(id int) int {
+	for i, edit := range o.pending {
+		if edit.id == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// without copies pending minus entry i; the old slice may be shared with an
+// earlier copy of the model
+func (o optimistic[T]) without(i int) []pendingEdit[T] {
+	rest := make([]pendingEdit[T], 0, len(o.pending)-1)
+	rest = append(rest, o.pending[:i]...)
+	return append(rest, o.pending[i+1:]...)
+}
+
+// editEntry returns an edit of a detail view's library entry; a nil entry
+// (not in the library) stays nil
+func editEntry(change func(*api.LibraryEntry)) func(*api.LibraryEntry) *api.LibraryEntry {
+	return func(entry *api.LibraryEntry) *api.LibraryEntry {
+		if entry == nil {
+			return nil
+		}
+		copied := *entry
+		change(&copied)
+		return &copied
+	}
+}
+
+// editEntries returns an edit of one manga's row in the library list
+func editEntries(mangaID string, change func(*api.LibraryEntry)) func([]api.LibraryEntry) []api.LibraryEntry {
+	return func(entries []api.LibraryEntry) []api.LibraryEntry {
+		copied := make([]api.LibraryEntry, len(entries)) // the slice may be shared with the API cache
+		copy(copied, entries)
+		for i := range copied {
+			if copied[i].MangaID == mangaID {
+				change(&copied[i])
+			}
+		}
+		return copied
+	}
+}
+
+// setFavorite, setStatus and setChapter are the entry changes behind
+// EditFavorite, EditStatus and EditChapter
+func setFavorite(isFavorite bool) func(*api.LibraryEntry) {
+	return func(entry *api.LibraryEntry) { entry.IsFavorite = isFavorite }
+}
+
+func setStatus(status string) func(*api.LibraryEntry) {
+	return func(entry *api.LibraryEntry) { entry.Status = status }
+}
+
+func setChapter(chapter int) func(*api.LibraryEntry) {
+	return func(entry *api.LibraryEntry) { entry.CurrentChapter = chapter }
+}
@@ -1,12 +1,14 @@
 // Package views - Command Palette
 // Quick command launcher accessible via Ctrl+P
 // Provides fuzzy search over all available commands and views
+// Xếp hạng theo điểm fuzzy trên label/alias; query rỗng thì lệnh dùng gần đây lên đầu
 package views
 
 import (
+	"sort"
 	"strings"
+	"unicode"
 
-	"github.com/charmbracelet/bubbles/list"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
@@ -25,18 +27,11 @@ type PaletteCommand struct {
 	Desc     string
 	Keys     []string
 	Category string
+	Aliases  []string // extra words matched by the fuzzy filter
 }
 
-// FilterValue implements list.Item interface
-func (c PaletteCommand) FilterValue() string {
-	return c.Label + " " + c.Desc + " " + strings.Join(c.Keys, " ")
-}
-
-// Title implements list.DefaultItem interface
-func (c PaletteCommand) Title() string { return c.Label }
-
-// Description implements list.DefaultItem interface
-func (c PaletteCommand) Description() string { return c.Desc }
+// maxRecentCommands is how many executed commands are remembered
+const maxRecentCommands = 5
 
 // PaletteModel holds the command palette state
 type PaletteModel struct {
@@ -44,8 +39,11 @@ type PaletteModel struct {
 	height      int
 	theme       *styles.Theme
 	searchInput textinput.Model
-	list        list.Model
 	commands    []PaletteCommand
+	matches     []PaletteCommand // commands visible for the current query, best first
+	cursor      int              // index into matches
+	query       string           // query matches were computed for
+	recent      []string         // executed command IDs, most recent first
 	selected    *PaletteCommand
 	visible     bool
 }
@@ -64,20 +62,25 @@ type PaletteCloseMsg struct{}
 
 var allCommands = []PaletteCommand{
 	// Navigation
-	{ID: "goto_dashboard", Label: "Go to Dashboard", Desc: "View home dashboard", Keys: []string{"h"}, Category: "Navigation"},
-	{ID: "goto_search", Label: "Go to Search", Desc: "Search for manga", Keys: []string{"s", "/"}, Category: "Navigation"},
-	{ID: "goto_browse", Label: "Go to Browse", Desc: "Browse by category", Keys: []string{"b"}, Category: "Navigation"},
-	{ID: "goto_library", Label: "Go to Library", Desc: "View your library", Keys: []string{"l"}, Category: "Navigation"},
-	{ID: "goto_activity", Label: "Go to Activity", Desc: "View activity feed", Keys: []string{"a"}, Category: "Navigation"},
-	{ID: "goto_stats", Label: "Go to Statistics", Desc: "View reading stats & rank", Keys: []string{"t"}, Category: "Navigation"},
-	{ID: "goto_settings", Label: "Go to Settings", Desc: "App settings & preferences", Keys: []string{"x"}, Category: "Navigation"},
-	{ID: "goto_chat", Label: "Go to Chat", Desc: "Open real-time chat", Keys: []string{"c"}, Category: "Navigation"},
+	{ID: "goto_dashboard", Label: "Go to Dashboard", Desc: "View home dashboard", Keys: []string{"h"}, Category: "Navigation", Aliases: []string{"home"}},
+	{ID: "goto_search", Label: "Go to Search", Desc: "Search for manga", Keys: []string{"s", "/"}, Category: "Navigation", Aliases: []string{"find"}},
+	{ID: "goto_browse", Label: "Go to Browse", Desc: "Browse by category", Keys: []string{"b"}, Category: "Navigation", Aliases: []string{"genres", "discover"}},
+	{ID: "goto_library", Label: "Go to Library", Desc: "View your library", Keys: []string{"l"}, Category: "Navigation", Aliases: []string{"my manga", "collection"}},
+	{ID: "goto_activity", Label: "Go to Activity", Desc: "View activity feed", Keys: []string{"a"}, Category: "Navigation", Aliases: []string{"feed"}},
+	{ID: "goto_stats", Label: "Go to Statistics", Desc: "View reading stats & rank", Keys: []string{"t"}, Category: "Navigation", Aliases: []string{"stats", "rank", "leaderboard", "goals"}},
+	{ID: "goto_lists", Label: "Go to Lists", Desc: "Manage your custom lists", Keys: []string{}, Category: "Navigation", Aliases: []string{"custom lists", "collections"}},
+	{ID: "goto_settings", Label: "Go to Settings", Desc: "App settings & preferences", Keys: []string{"x"}, Category: "Navigation", Aliases: []string{"preferences", "config", "theme", "spoilers"}},
+	{ID: "goto_reader", Label: "Open Reader", Desc: "Read the manga you have open", Keys: []string{"o"}, Category: "Navigation", Aliases: []string{"read", "continue reading"}},
+	{ID: "goto_chat", Label: "Go to Chat", Desc: "Open real-time chat", Keys: []string{"c"}, Category: "Navigation", Aliases: []string{"messages"}},
+	{ID: "goto_rooms", Label: "Go to Chat Rooms", Desc: "Browse chat rooms and who is in them", Keys: []string{}, Category: "Navigation", Aliases: []string{"rooms", "room directory", "channels"}},
 
 	// Actions
-	{ID: "login", Label: "Login / Logout", Desc: "Toggle authentication", Keys: []string{"L"}, Category: "Account"},
-	{ID: "refresh", Label: "Refresh Data", Desc: "Reload current view", Keys: []string{"r"}, Category: "Actions"},
-	{ID: "help", Label: "Show Help", Desc: "View all keybindings", Keys: []string{"?"}, Category: "Help"},
-	{ID: "quit", Label: "Quit Application", Desc: "Exit MangaHub", Keys: []string{"q"}, Category: "System"},
+	{ID: "login", Label: "Login / Logout", Desc: "Toggle authentication", Keys: []string{"L"}, Category: "Account", Aliases: []string{"sign in", "sign out"}},
+	{ID: "import_library", Label: "Import Library", Desc: "Import a MyAnimeList XML/JSON export", Keys: []string{}, Category: "Account", Aliases: []string{"mal", "myanimelist"}},
+	{ID: "export_data", Label: "Export Data (CSV)", Desc: "Save library, history & lists as a zip of CSVs", Keys: []string{}, Category: "Account", Aliases: []string{"download", "backup"}},
+	{ID: "refresh", Label: "Refresh Data", Desc: "Reload current view", Keys: []string{"r"}, Category: "Actions", Aliases: []string{"reload"}},
+	{ID: "help", Label: "Show Help", Desc: "View all keybindings", Keys: []string{"?"}, Category: "Help", Aliases: []string{"keys", "shortcuts"}},
+	{ID: "quit", Label: "Quit Application", Desc: "Exit MangaHub", Keys: []string{"q"}, Category: "System", Aliases: []string{"exit"}},
 
 	// List navigation
 	{ID: "move_up", Label: "Move Up", Desc: "Navigate to previous item", Keys: []string{"↑", "k"}, Category: "List Navigation"},
@@ -95,37 +98,19 @@ func NewPalette() PaletteModel {
 	// Create search input
 	ti := textinput.New()
 	ti.Placeholder = "Type to search commands..."
+	ti.Prompt = "> "
 	ti.Focus()
 	ti.CharLimit = 50
 	ti.Width = 60
 
-	// Create list
-	items := make([]list.Item, len(allCommands))
-	for i, cmd := range allCommands {
-		items[i] = cmd
-	}
-
-	delegate := list.NewDefaultDelegate()
-	delegate.SetHeight(1)
-	delegate.ShowDescription = true
-
-	l := list.New(items, delegate, 70, 20)
-	l.Title = "Command Palette"
-	l.SetShowStatusBar(false)
-	l.SetFilteringEnabled(true)
-	l.SetShowHelp(false)
-	l.Styles.Title = lipgloss.NewStyle().
-		Bold(true).
-		Foreground(styles.ColorPrimary).
-		Padding(0, 1)
-
-	return PaletteModel{
+	m := PaletteModel{
 		theme:       styles.DefaultTheme,
 		searchInput: ti,
-		list:        l,
 		commands:    allCommands,
 		visible:     false,
 	}
+	m.refilter()
+	return m
 }
 
 // =====================================
@@ -137,13 +122,10 @@ func (m PaletteModel) Init() tea.Cmd {
 }
 
 func (m PaletteModel) Update(msg tea.Msg) (PaletteModel, tea.Cmd) {
-	var cmds []tea.Cmd
-
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		m.list.SetSize(msg.Width-20, msg.Height-10)
 
 	case tea.KeyMsg:
 		switch msg.String() {
@@ -152,27 +134,171 @@ func (m PaletteModel) Update(msg tea.Msg) (PaletteModel, tea.Cmd) {
 			return m, func() tea.Msg { return PaletteCloseMsg{} }
 
 		case "enter":
-			if i, ok := m.list.SelectedItem().(PaletteCommand); ok {
-				m.selected = &i
+			if m.cursor < len(m.matches) {
+				cmd := m.matches[m.cursor]
+				m.selected = &cmd
 				m.visible = false
+				m.remember(cmd.ID)
 				return m, func() tea.Msg {
-					return CommandSelectedMsg{CommandID: i.ID}
+					return CommandSelectedMsg{CommandID: cmd.ID}
 				}
 			}
+			return m, nil
+
+		case "up", "ctrl+k":
+			// Wrap to the bottom of the visible set
+			if len(m.matches) > 0 {
+				m.cursor = (m.cursor - 1 + len(m.matches)) % len(m.matches)
+			}
+			return m, nil
+
+		case "down", "ctrl+j", "tab":
+			if len(m.matches) > 0 {
+				m.cursor = (m.cursor + 1) % len(m.matches)
+			}
+			return m, nil
 
 		case "ctrl+c":
 			// Let parent handle quit
 			return m, nil
+		}
 
-		default:
-			// Update list for filtering
-			var cmd tea.Cmd
-			m.list, cmd = m.list.Update(msg)
-			cmds = append(cmds, cmd)
+		// Anything else edits the query
+		var cmd tea.Cmd
+		m.searchInput, cmd = m.searchInput.Update(msg)
+		if m.searchInput.Value() != m.query {
+			m.refilter()
 		}
+		return m, cmd
 	}
 
-	return m, tea.Batch(cmds...)
+	return m, nil
+}
+
+// refilter recomputes the visible commands for the current query.
+// Runs once per query change; the cursor returns to the best match.
+func (m *PaletteModel) refilter() {
+	m.query = m.searchInput.Value()
+	m.cursor = 0
+
+	q := strings.ToLower(strings.TrimSpace(m.query))
+	if q == "" {
+		m.matches = m.recentFirst()
+		return
+	}
+
+	type ranked struct {
+		cmd   PaletteCommand
+		score int
+		order int
+	}
+	var hits []ranked
+	for i, cmd := range m.commands {
+		best := fuzzyScore(q, cmd.Label)
+		for _, alias := range cmd.Aliases {
+			if s := fuzzyScore(q, alias); s > best {
+				best = s
+			}
+		}
+		if best >= 0 {
+			hits = append(hits, ranked{cmd: cmd, score: best, order: i})
+		}
+	}
+	sort.Slice(hits, func(a, b int) bool {
+		if hits[a].score != hits[b].score {
+			return hits[a].score > hits[b].score
+		}
+		return hits[a].order < hits[b].order
+	})
+
+	m.matches = make([]PaletteCommand, len(hits))
+	for i, h := range hits {
+		m.matches[i] = h.cmd
+	}
+}
+
+// recentFirst lists recently executed commands, then the rest in declaration order
+func (m PaletteModel) recentFirst() []PaletteCommand {
+	byID := make(map[string]PaletteCommand, len(m.commands))
+	for _, cmd := range m.commands {
+		byID[cmd.ID] = cmd
+	}
+
+	out := make([]PaletteCommand, 0, len(m.commands))
+	seen := make(map[string]bool, len(m.recent))
+	for _, id := range m.recent {
+		if cmd, ok := byID[id]; ok {
+			out = append(out, cmd)
+			seen[id] = true
+		}
+	}
+	for _, cmd := range m.commands {
+		if !seen[cmd.ID] {
+			out = append(out, cmd)
+		}
+	}
+	return out
+}
+
+// remember moves id to the front of the recent commands
+func (m *PaletteModel) remember(id string) {
+	recent := []string{id}
+	for _, r := range m.recent {
+		if r != id && len(recent) < maxRecentCommands {
+			recent = append(recent, r)
+		}
+	}
+	m.recent = recent
+}
+
+// isRecent reports whether id was executed recently
+func (m PaletteModel) isRecent(id string) bool {
+	for _, r := range m.recent {
+		if r == id {
+			return true
+		}
+	}
+	return false
+}
+
+// fuzzyScore matches query as a subsequence of target (both compared lowercase).
+// Returns -1 when it does not match. Prefix, word-start and consecutive matches
+// score higher; skipped characters cost a little.
+func fuzzyScore(query, target string) int {
+	t := []rune(strings.ToLower(target))
+	score := 0
+	ti := 0
+	prev := -2
+	for _, qc := range query {
+		found := false
+		for ; ti < len(t); ti++ {
+			if t[ti] != qc {
+				continue
+			}
+			switch {
+			case ti == 0:
+				score += 10 // start of the whole string
+			case !unicode.IsLetter(t[ti-1]) && !unicode.IsDigit(t[ti-1]):
+				score += 8 // start of a word
+			case ti == prev+1:
+				score += 5 // continues the previous match
+			default:
+				score++
+			}
+			if prev >= 0 {
+				score -= ti - prev - 1 // gap since the previous match
+			}
+			prev = ti
+			ti++
+			found = true
+			break
+		}
+		if !found {
+			return -1
+		}
+	}
+	// Prefer shorter targets when matches tie
+	return score*100 - len(t)
 }
 
 func (m PaletteModel) View() string {
@@ -180,15 +306,22 @@ func (m PaletteModel) View() string {
 		return ""
 	}
 
-	// Build palette view
-	content := m.list.View()
+	title := lipgloss.NewStyle().
+		Bold(true).
+		Foreground(m.theme.Palette.Primary).
+		Padding(0, 1).
+		Render("Command Palette")
+
+	sections := []string{title, m.searchInput.View(), ""}
+	sections = append(sections, m.renderMatches())
+	sections = append(sections, "", m.theme.DimText.Render("↑/↓: move | Enter: run | Esc: close"))
 
 	// Wrap in a box
 	box := m.theme.Card.
 		Width(m.width-20).
 		Height(m.height-10).
 		Padding(1, 2).
-		Render(content)
+		Render(lipgloss.JoinVertical(lipgloss.Left, sections...))
 
 	// Center it
 	return lipgloss.Place(
@@ -200,14 +333,55 @@ func (m PaletteModel) View() string {
 	)
 }
 
+// renderMatches renders the window of matches around the cursor
+func (m PaletteModel) renderMatches() string {
+	if len(m.matches) == 0 {
+		return m.theme.DimText.Render("No matching commands")
+	}
+
+	// Title, input, help and box padding take about 8 rows
+	rows := m.height - 10 - 8
+	if rows < 3 {
+		rows = 3
+	}
+	start := 0
+	if m.cursor >= rows {
+		start = m.cursor - rows + 1
+	}
+	end := min(start+rows, len(m.matches))
+
+	showRecent := strings.TrimSpace(m.query) == ""
+	var lines []string
+	for i := start; i < end; i++ {
+		cmd := m.matches[i]
+		label := cmd.Label
+		if len(cmd.Keys) > 0 {
+			label += " [" + strings.Join(cmd.Keys, ", ") + "]"
+		}
+		desc := cmd.Desc
+		if showRecent && m.isRecent(cmd.ID) {
+			desc = "recent · " + desc
+		}
+
+		if i == m.cursor {
+			lines = append(lines, m.theme.Primary.Render("▶ "+label)+"  "+m.theme.Description.Render(desc))
+		} else {
+			lines = append(lines, "  "+label+"  "+m.theme.DimText.Render(desc))
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
 // =====================================
 // PUBLIC METHODS
 // =====================================
 
-// Show makes the palette visible
+// Show makes the palette visible with an empty query
 func (m *PaletteModel) Show() {
 	m.visible = true
-	m.list.ResetFilter()
+	m.searchInput.Reset()
+	m.searchInput.Focus()
+	m.refilter()
 }
 
 // Hide hides the palette
@@ -220,14 +394,18 @@ func (m PaletteModel) IsVisible() bool {
 	return m.visible
 }
 
+// SetTheme switches the view to a new theme
+func (m *PaletteModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+}
+
 // SetWidth sets the view width
 func (m *PaletteModel) SetWidth(w int) {
 	m.width = w
-	m.list.SetSize(w-20, m.height-10)
+	m.searchInput.Width = max(20, w-30)
 }
 
 // SetHeight sets the view height
 func (m *PaletteModel) SetHeight(h int) {
 	m.height = h
-	m.list.SetSize(m.width-20, h-10)
 }
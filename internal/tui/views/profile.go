@@ -0,0 +1,285 @@
+// Package views - Profile View
+// Hồ sơ công khai của một user, mở từ Activity feed
+// Layout:
+//
+//	┌────────────────────────────────────────────────────────┐
+//	│  👤 Guts  @guts                                        │
+//	│  Joined Mar 2025  ·  🥈 Silver  ·  📚 412 chapters     │
+//	│                                                        │
+//	│  LIBRARY                                               │
+//	│  23 manga  ·  5 reading  ·  12 completed  ·  ♥ 4       │
+//	│                                                        │
+//	│  RECENT ACTIVITY                                       │
+//	│  📈 reached Ch. 120 in Berserk           2 hours ago   │
+//	│  ⭐ rated Vagabond 9.5/10                1 day ago     │
+//	│                                                        │
+//	│  [r] Refresh  [Esc] Back                               │
+//	└────────────────────────────────────────────────────────┘
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"mangahub/internal/tui/api"
+	"mangahub/internal/tui/styles"
+	"mangahub/pkg/models"
+)
+
+// =====================================
+// PROFILE MODEL
+// =====================================
+
+// ProfileModel holds the public profile view state
+type ProfileModel struct {
+	width  int
+	height int
+	theme  *styles.Theme
+
+	// Data
+	username string
+	profile  *models.PublicProfile
+
+	// UI state
+	loading   bool
+	lastError error
+	spinner   spinner.Model
+
+	client *api.Client
+}
+
+// =====================================
+// MESSAGES
+// =====================================
+
+// ShowProfileMsg asks the app to open a user's profile
+type ShowProfileMsg struct {
+	Username string
+}
+
+// profileLoadedMsg carries a loaded profile
+type profileLoadedMsg struct {
+	Username string
+	Profile  *models.PublicProfile
+	Error    error
+}
+
+// =====================================
+// CONSTRUCTOR
+// =====================================
+
+// NewProfile creates a profile model for a username
+func NewProfile(username string) ProfileModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = styles.DefaultTheme.Spinner
+
+	return ProfileModel{
+		theme:    styles.DefaultTheme,
+		username: username,
+		spinner:  s,
+		client:   api.GetClient(),
+		loading:  true,
+	}
+}
+
+// =====================================
+// BUBBLE TEA INTERFACE
+// =====================================
+
+// Init loads the profile
+func (m ProfileModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, m.loadProfile)
+}
+
+// Update handles messages
+func (m ProfileModel) Update(msg tea.Msg) (ProfileModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		if msg.String() == "r" && !m.loading {
+			m.loading = true
+			return m, tea.Batch(m.spinner.Tick, m.loadProfile)
+		}
+
+	case profileLoadedMsg:
+		// Ignore a slow response for a profile we already left
+		if msg.Username != m.username {
+			return m, nil
+		}
+		m.loading = false
+		m.lastError = msg.Error
+		if msg.Error == nil {
+			m.profile = msg.Profile
+		}
+
+	case spinner.TickMsg:
+		if m.loading {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+	}
+
+	return m, nil
+}
+
+// =====================================
+// COMMANDS
+// =====================================
+
+func (m ProfileModel) loadProfile() tea.Msg {
+	profile, err := m.client.GetProfile(context.Background(), m.username)
+	return profileLoadedMsg{Username: m.username, Profile: profile, Error: err}
+}
+
+// =====================================
+// ACCESSORS
+// =====================================
+
+// SetTheme switches the view to a new theme
+func (m *ProfileModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
+}
+
+// SetWidth sets the view width
+func (m *ProfileModel) SetWidth(w int) {
+	m.width = w
+}
+
+// SetHeight sets the view height
+func (m *ProfileModel) SetHeight(h int) {
+	m.height = h
+}
+
+// =====================================
+// VIEW
+// =====================================
+
+// View renders the profile view
+func (m ProfileModel) View() string {
+	var sections []string
+
+	switch {
+	case m.loading && m.profile == nil:
+		sections = append(sections,
+			m.theme.PanelHeader.Render("👤 @"+m.username),
+			m.spinner.View()+" Loading profile...")
+	case m.profile != nil:
+		sections = append(sections, m.renderHeader(), "", m.renderLibrary(), "", m.renderActivity())
+	default:
+		sections = append(sections, m.theme.PanelHeader.Render("👤 @"+m.username))
+	}
+
+	if m.lastError != nil {
+		sections = append(sections, m.theme.ErrorText.Render("⚠ "+m.lastError.Error()))
+	}
+
+	sections = append(sections, "", m.renderHelp())
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return m.theme.Container.Width(m.width - 4).Render(content)
+}
+
+// =====================================
+// RENDERERS
+// =====================================
+
+func (m ProfileModel) renderHeader() string {
+	p := m.profile
+	title := m.theme.PanelHeader.Render("👤 "+p.DisplayName) + "  " + m.theme.DimText.Render("@"+p.Username)
+
+	details := []string{
+		m.theme.Description.Render("Joined " + p.JoinedAt.Format("Jan 2006")),
+		m.theme.Warning.Render(p.Rank.Icon + " " + p.Rank.Name),
+	}
+	if p.ChaptersRead != nil {
+		details = append(details, m.theme.Primary.Render(fmt.Sprintf("📚 %d chapters", *p.ChaptersRead)))
+	}
+
+	return title + "\n" + strings.Join(details, m.theme.DimText.Render("  ·  "))
+}
+
+func (m ProfileModel) renderLibrary() string {
+	header := m.theme.Subtitle.Render("LIBRARY")
+	lib := m.profile.Library
+	if lib == nil {
+		return header + "\n" + m.theme.DimText.Render("🔒 This library is private")
+	}
+
+	counts := []string{
+		m.theme.Primary.Render(fmt.Sprintf("%d manga", lib.Total)),
+		m.theme.Description.Render(fmt.Sprintf("%d reading", lib.Reading)),
+		m.theme.Description.Render(fmt.Sprintf("%d completed", lib.Completed)),
+		m.theme.Description.Render(fmt.Sprintf("%d planned", lib.PlanToRead)),
+		m.theme.DimText.Render(fmt.Sprintf("%d on hold", lib.OnHold)),
+		m.theme.DimText.Render(fmt.Sprintf("%d dropped", lib.Dropped)),
+		m.theme.Secondary.Render(fmt.Sprintf("♥ %d", lib.Favorites)),
+	}
+	return header + "\n" + strings.Join(counts, m.theme.DimText.Render("  ·  "))
+}
+
+func (m ProfileModel) renderActivity() string {
+	header := m.theme.Subtitle.Render("RECENT ACTIVITY")
+	if !m.profile.ActivityPublic {
+		return header + "\n" + m.theme.DimText.Render("🔒 This activity is private")
+	}
+	if len(m.profile.RecentActivity) == 0 {
+		return header + "\n" + m.theme.DimText.Render("No activity yet")
+	}
+
+	maxVisible := len(m.profile.RecentActivity)
+	if m.height > 16 {
+		maxVisible = minInt(maxVisible, m.height-16)
+	}
+
+	lines := []string{header}
+	for _, a := range m.profile.RecentActivity[:maxVisible] {
+		line := m.describeActivity(a)
+		timeText := m.theme.DimText.Render(formatTimeAgo(a.CreatedAt))
+		padding := m.width - 14 - lipgloss.Width(line) - lipgloss.Width(timeText)
+		if padding < 2 {
+			padding = 2
+		}
+		lines = append(lines, line+strings.Repeat(" ", padding)+timeText)
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describeActivity renders one activity without the username
+func (m ProfileModel) describeActivity(a models.Activity) string {
+	manga := m.theme.Title.Render(a.MangaTitle)
+	switch a.ActivityType {
+	case "comment":
+		return "💬 commented on " + manga
+	case "rating":
+		rating := ""
+		if a.Rating != nil {
+			rating = " " + m.theme.Warning.Render(fmt.Sprintf("%.1f/10", *a.Rating))
+		}
+		return "⭐ rated " + manga + rating
+	case "list_add":
+		return "📖 added " + manga + " to a list"
+	default:
+		if a.ChapterNumber != nil {
+			return "📈 reached " + m.theme.Primary.Render(fmt.Sprintf("Ch. %d", *a.ChapterNumber)) + " in " + manga
+		}
+		return "📈 read " + manga
+	}
+}
+
+func (m ProfileModel) renderHelp() string {
+	return strings.Join([]string{
+		styles.RenderKeyHint("r", "refresh"),
+		styles.RenderKeyHint("Esc", "back"),
+	}, "  ")
+}
@@ -26,6 +26,7 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
@@ -56,6 +57,9 @@ var StatusIcons = map[string]string{
 	"completed": "✅",
 	"on-hold":   "⏸️",
 	"dropped":   "❌",
+	// Library statuses as the server names them
+	"plan_to_read": "📋",
+	"on_hold":      "⏸️",
 }
 
 // StatusLabels maps status to display labels
@@ -65,6 +69,9 @@ var StatusLabels = map[string]string{
 	"completed": "Completed",
 	"on-hold":   "On Hold",
 	"dropped":   "Dropped",
+	// Library statuses as the server names them
+	"plan_to_read": "Plan to Read",
+	"on_hold":      "On Hold",
 }
 
 // =====================================
@@ -276,10 +283,14 @@ func (m ProgressModel) saveProgress() tea.Cmd {
 		status := ReadingStatuses[m.currentStatus]
 
 		// Update progress with chapter, status, and favorite flag
-		err = m.client.UpdateProgress(ctx, m.mangaID, chapter, status, false)
+		progress, err := m.client.UpdateProgress(ctx, m.mangaID, chapter, status, false, time.Time{})
 		if err != nil {
 			return ProgressErrorMsg{Error: err}
 		}
+		if progress != nil {
+			// Another device may have been further ahead
+			chapter = progress.CurrentChapter
+		}
 
 		return ProgressSavedMsg{
 			Chapter: chapter,
@@ -456,6 +467,12 @@ func (m ProgressModel) GetStatus() string {
 	return ReadingStatuses[m.currentStatus]
 }
 
+// SetTheme switches the view to a new theme
+func (m *ProgressModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
+}
+
 // SetWidth sets the view width
 func (m *ProgressModel) SetWidth(w int) {
 	m.width = w
@@ -30,6 +30,7 @@ type RatingModal struct {
 	height      int
 	theme       *styles.Theme
 	focusReview bool // false = rating, true = review
+	spoiler     bool // review contains spoilers (collapsed for other readers)
 }
 
 // RatingSubmittedMsg signals rating was submitted
@@ -129,6 +130,8 @@ func (m RatingModal) Update(msg tea.Msg) (RatingModal, tea.Cmd) {
 				m.rating = maxFloat(0.0, m.rating-1.0)
 			case "up", "k":
 				m.rating = minFloat(10.0, m.rating+1.0)
+			case "s":
+				m.spoiler = !m.spoiler
 			case "tab":
 				m.focusReview = true
 				m.review.Focus()
@@ -155,7 +158,7 @@ func (m RatingModal) Update(msg tea.Msg) (RatingModal, tea.Cmd) {
 func (m RatingModal) submitRating() tea.Cmd {
 	return func() tea.Msg {
 		ctx := context.Background()
-		err := m.client.SubmitRating(ctx, m.mangaID, int(m.rating), m.review.Value())
+		err := m.client.SubmitRating(ctx, m.mangaID, int(m.rating), m.review.Value(), m.spoiler)
 		if err != nil {
 			return RatingErrorMsg{Error: err}
 		}
@@ -217,6 +220,10 @@ func (m RatingModal) View() string {
 		reviewLabel = m.theme.Primary.Bold(true).Render("\n▶ Review (optional):")
 	}
 
+	if m.spoiler {
+		reviewLabel += " " + m.theme.Warning.Render("⚠ spoiler")
+	}
+
 	reviewSection := reviewLabel + "\n" + m.review.View()
 
 	// Help text
@@ -225,7 +232,7 @@ func (m RatingModal) View() string {
 	if m.focusReview {
 		helpText = helpStyle.Render("ESC: back to rating | Ctrl+S: submit | Tab: switch focus")
 	} else {
-		helpText = helpStyle.Render("←/→: adjust by 0.5 | ↑/↓: adjust by 1.0 | Tab: review | Enter: submit | ESC: cancel")
+		helpText = helpStyle.Render("←/→: adjust by 0.5 | ↑/↓: adjust by 1.0 | s: spoiler | Tab: review | Enter: submit | ESC: cancel")
 	}
 
 	// Combine sections
@@ -306,3 +313,9 @@ func maxFloat(a, b float64) float64 {
 	}
 	return b
 }
+
+// SetTheme switches the view to a new theme
+func (m *RatingModal) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
+}
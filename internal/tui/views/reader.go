@@ -0,0 +1,468 @@
+// Package views - Chapter Reader View
+// Đánh dấu từng chapter đã đọc và ghi lại lịch sử đọc
+// Layout:
+//
+//	┌───────────────────────────────────────────────────────┐
+//	│  📖 ONE PIECE                     Chapter 1093 / 1100 │
+//	│  [████████████████████░]  99%                         │
+//	│                                                       │
+//	│    ✓ Chapter 1091 · The Straw Hat     2023-08-20      │
+//	│    ✓ Chapter 1092 · The Gear 5 Threat 2023-08-27      │
+//	│    ▶ Chapter 1093 · Luffy vs Kizaru   2023-09-03      │
+//	│    📖 Chapter 1094                                    │
+//	│                                                       │
+//	│  [n] Next  [p] Previous  [u] Undo  [C] Discuss  [esc] │
+//	└───────────────────────────────────────────────────────┘
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"mangahub/internal/tui/api"
+	"mangahub/internal/tui/styles"
+	"mangahub/pkg/models"
+)
+
+// Reading time estimation, set from the reader config in cmd/tui
+var (
+	// ReaderMaxChapterTime caps one chapter's recorded time so idle time is ignored
+	ReaderMaxChapterTime = 30 * time.Minute
+	// ReaderDefaultChapterTime is recorded when there is no previous advance to measure from
+	ReaderDefaultChapterTime = 5 * time.Minute
+)
+
+// =====================================
+// READER MODEL
+// =====================================
+
+// ReaderModel holds the chapter reader state
+type ReaderModel struct {
+	// Window dimensions
+	width  int
+	height int
+
+	// Theme
+	theme *styles.Theme
+
+	// Data
+	mangaID        string
+	mangaTitle     string
+	currentChapter int
+	totalChapters  int
+	status         string
+
+	// Chapter titles and release dates by number, loaded in the background
+	chapters map[int]models.Chapter
+
+	// Wall-clock time of the last chapter advance in this session
+	lastAdvance time.Time
+
+	// History entries recorded in this session, newest last, for undo
+	recorded []models.ChapterHistory
+
+	// UI state
+	saving    bool
+	message   string
+	lastError error
+
+	// API client
+	client *api.Client
+}
+
+// =====================================
+// MESSAGES
+// =====================================
+
+// ShowReaderMsg signals to open the chapter reader for a manga
+type ShowReaderMsg struct {
+	MangaID        string
+	MangaTitle     string
+	CurrentChapter int
+	TotalChapters  int
+	Status         string
+}
+
+// ReaderProgressSavedMsg signals the chapter change was persisted.
+// Recorded is the history entry an advance created; Undone the one an undo removed.
+type ReaderProgressSavedMsg struct {
+	Chapter  int
+	Status   string
+	Recorded *models.ChapterHistory
+	Undone   *models.ChapterHistory
+}
+
+// readerChaptersMsg delivers chapter metadata for the reader's manga
+type readerChaptersMsg struct {
+	MangaID string
+	List    *models.ChapterList
+}
+
+// ReaderErrorMsg signals a failed chapter update
+type ReaderErrorMsg struct {
+	Error error
+}
+
+// =====================================
+// CONSTRUCTOR
+// =====================================
+
+// NewReader creates a new reader model for a manga
+func NewReader(mangaID, mangaTitle string, currentChapter, totalChapters int, status string) ReaderModel {
+	return ReaderModel{
+		theme:          styles.DefaultTheme,
+		client:         api.GetClient(),
+		mangaID:        mangaID,
+		mangaTitle:     mangaTitle,
+		currentChapter: currentChapter,
+		totalChapters:  totalChapters,
+		status:         status,
+	}
+}
+
+// =====================================
+// BUBBLE TEA INTERFACE
+// =====================================
+
+// Init initializes the reader view
+func (m ReaderModel) Init() tea.Cmd {
+	return m.loadChapters()
+}
+
+// loadChapters fetches chapter titles; on failure the list stays numbered only
+func (m ReaderModel) loadChapters() tea.Cmd {
+	client, mangaID := m.client, m.mangaID
+	return func() tea.Msg {
+		list, err := client.GetChapters(context.Background(), mangaID)
+		if err != nil {
+			return nil
+		}
+		return readerChaptersMsg{MangaID: mangaID, List: list}
+	}
+}
+
+// Update handles messages
+func (m ReaderModel) Update(msg tea.Msg) (ReaderModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		if m.saving {
+			return m, nil
+		}
+		switch msg.String() {
+		case "n":
+			// Advance one chapter, clamped to total_chapters
+			next := m.currentChapter + 1
+			if m.totalChapters > 0 && next > m.totalChapters {
+				next = m.totalChapters
+			}
+			if next == m.currentChapter {
+				m.message = "Already at the latest chapter"
+				return m, nil
+			}
+			now := time.Now()
+			minutes := estimateChapterMinutes(m.lastAdvance, now)
+			m.lastAdvance = now
+			m.saving = true
+			m.message = ""
+			return m, m.advanceChapter(next, minutes)
+
+		case "C":
+			// Discuss the current chapter
+			if m.currentChapter <= 0 {
+				return m, nil
+			}
+			chapter := m.currentChapter
+			return m, func() tea.Msg {
+				return ShowCommentsMsg{MangaID: m.mangaID, MangaTitle: m.mangaTitle, Chapter: chapter}
+			}
+
+		case "p":
+			// Rewind one chapter (no history entry is recorded)
+			if m.currentChapter <= 0 {
+				return m, nil
+			}
+			m.saving = true
+			m.message = ""
+			return m, m.rewindChapter(m.currentChapter - 1)
+
+		case "u":
+			// Undo the last recorded chapter read
+			var last *models.ChapterHistory
+			if n := len(m.recorded); n > 0 {
+				last = &m.recorded[n-1]
+			}
+			m.saving = true
+			m.message = ""
+			return m, m.undoChapterRead(last)
+		}
+
+	case ReaderProgressSavedMsg:
+		m.saving = false
+		m.lastError = nil
+		m.currentChapter = msg.Chapter
+		m.status = msg.Status
+		if msg.Recorded != nil {
+			m.recorded = append(m.recorded, *msg.Recorded)
+		}
+		if msg.Undone != nil {
+			if n := len(m.recorded); n > 0 && m.recorded[n-1].ID == msg.Undone.ID {
+				m.recorded = m.recorded[:n-1]
+			}
+			m.message = fmt.Sprintf("↩ Undid chapter %d", msg.Undone.ChapterNumber)
+		} else if msg.Status == "completed" {
+			m.message = "🎉 Completed! You've caught up with every chapter."
+		}
+
+	case readerChaptersMsg:
+		if msg.MangaID != m.mangaID || msg.List == nil {
+			return m, nil
+		}
+		m.chapters = make(map[int]models.Chapter, len(msg.List.Chapters))
+		for _, ch := range msg.List.Chapters {
+			m.chapters[ch.Number] = ch
+		}
+
+	case ReaderErrorMsg:
+		m.saving = false
+		m.lastError = msg.Error
+	}
+
+	return m, nil
+}
+
+// statusFor returns the library status for a given chapter
+func (m ReaderModel) statusFor(chapter int) string {
+	if m.totalChapters > 0 && chapter >= m.totalChapters {
+		return "completed"
+	}
+	return "reading"
+}
+
+// estimateChapterMinutes measures reading time since the previous advance.
+// The first chapter of a session has nothing to measure from and gets the default.
+func estimateChapterMinutes(last, now time.Time) int {
+	elapsed := ReaderDefaultChapterTime
+	if !last.IsZero() {
+		elapsed = now.Sub(last)
+		if elapsed > ReaderMaxChapterTime {
+			elapsed = ReaderMaxChapterTime
+		}
+	}
+	// Any chapter read counts for at least a minute
+	return max(1, int(elapsed.Round(time.Minute)/time.Minute))
+}
+
+// advanceChapter records the chapter read and moves progress forward
+func (m ReaderModel) advanceChapter(chapter, minutes int) tea.Cmd {
+	status := m.statusFor(chapter)
+	return func() tea.Msg {
+		ctx := context.Background()
+		entry, err := m.client.RecordChapterRead(ctx, m.mangaID, chapter, 0, minutes)
+		if err != nil {
+			return ReaderErrorMsg{Error: err}
+		}
+		if err := m.client.UpdateLibraryProgress(ctx, m.mangaID, status, chapter); err != nil {
+			return ReaderErrorMsg{Error: err}
+		}
+		return ReaderProgressSavedMsg{Chapter: chapter, Status: status, Recorded: entry}
+	}
+}
+
+// readerUndoLookback is how many recent history entries are searched for
+// this manga's last read when nothing was recorded in this session
+const readerUndoLookback = 20
+
+// undoChapterRead removes a recorded chapter read and moves progress back
+// before it. With no entry from this session, the manga's newest history
+// entry is undone, so a mis-tap can still be fixed after reopening the reader.
+func (m ReaderModel) undoChapterRead(entry *models.ChapterHistory) tea.Cmd {
+	current := m.currentChapter
+	return func() tea.Msg {
+		ctx := context.Background()
+		if entry == nil {
+			history, err := m.client.GetChapterHistory(ctx, readerUndoLookback)
+			if err != nil {
+				return ReaderErrorMsg{Error: err}
+			}
+			for i := range history {
+				if history[i].MangaID == m.mangaID {
+					entry = &history[i]
+					break
+				}
+			}
+			if entry == nil {
+				return ReaderErrorMsg{Error: fmt.Errorf("no recent chapter read to undo")}
+			}
+		}
+
+		if _, err := m.client.DeleteChapterRead(ctx, entry.ID); err != nil {
+			return ReaderErrorMsg{Error: err}
+		}
+
+		// Progress goes back before the undone chapter, unless already rewound past it
+		chapter := min(current, entry.ChapterNumber-1)
+		status := m.statusFor(chapter)
+		if chapter != current {
+			if err := m.client.UpdateLibraryProgress(ctx, m.mangaID, status, chapter); err != nil {
+				return ReaderErrorMsg{Error: err}
+			}
+		}
+		return ReaderProgressSavedMsg{Chapter: chapter, Status: status, Undone: entry}
+	}
+}
+
+// rewindChapter moves progress back without touching history
+func (m ReaderModel) rewindChapter(chapter int) tea.Cmd {
+	status := m.statusFor(chapter)
+	return func() tea.Msg {
+		ctx := context.Background()
+		if err := m.client.UpdateLibraryProgress(ctx, m.mangaID, status, chapter); err != nil {
+			return ReaderErrorMsg{Error: err}
+		}
+		return ReaderProgressSavedMsg{Chapter: chapter, Status: status}
+	}
+}
+
+// View renders the reader view
+func (m ReaderModel) View() string {
+	var sections []string
+
+	sections = append(sections, m.renderHeader())
+	sections = append(sections, m.renderChapterList())
+
+	if m.lastError != nil {
+		sections = append(sections, m.theme.ErrorText.Render("⚠ "+m.lastError.Error()))
+	} else if m.saving {
+		sections = append(sections, m.theme.DimText.Render("Saving..."))
+	} else if m.message != "" {
+		sections = append(sections, m.theme.SuccessText.Render(m.message))
+	}
+
+	sections = append(sections, m.renderHints())
+
+	return m.theme.CardFocused.Width(m.width - 4).Render(strings.Join(sections, "\n\n"))
+}
+
+// =====================================
+// RENDERERS
+// =====================================
+
+// renderHeader renders title and overall progress
+func (m ReaderModel) renderHeader() string {
+	title := m.theme.Title.Render("📖 " + m.mangaTitle)
+
+	var chapterText string
+	var pct float64
+	if m.totalChapters > 0 {
+		chapterText = fmt.Sprintf("Chapter %d / %d", m.currentChapter, m.totalChapters)
+		pct = float64(m.currentChapter) / float64(m.totalChapters)
+	} else {
+		chapterText = fmt.Sprintf("Chapter %d", m.currentChapter)
+	}
+
+	progress := styles.RenderProgressBar(pct, 20) + "  " +
+		m.theme.Description.Render(chapterText) + "  " +
+		m.theme.DimText.Render(m.status)
+
+	return title + "\n" + progress
+}
+
+// renderChapterList renders a window of chapters around the current one
+func (m ReaderModel) renderChapterList() string {
+	header := m.theme.PanelHeader.Render("CHAPTERS")
+
+	// Number of rows that fit (header, progress, hints take ~12 lines)
+	visible := m.height - 12
+	if visible < 5 {
+		visible = 5
+	}
+
+	last := m.totalChapters
+	if last == 0 {
+		// Unknown total: show a few chapters past the current one
+		last = m.currentChapter + 3
+	}
+
+	start := max(1, m.currentChapter-visible/2)
+	end := min(last, start+visible-1)
+	if end-start < visible-1 {
+		start = max(1, end-visible+1)
+	}
+
+	var rows []string
+	for i := start; i <= end; i++ {
+		icon := "📖"
+		var style lipgloss.Style
+		switch {
+		case i < m.currentChapter:
+			icon = "✓"
+			style = m.theme.DimText
+		case i == m.currentChapter:
+			icon = "▶"
+			style = m.theme.Primary.Bold(true)
+		default:
+			style = m.theme.Description
+		}
+		rows = append(rows, "  "+icon+" "+m.renderChapterRow(i, style))
+	}
+
+	if len(rows) == 0 {
+		rows = append(rows, m.theme.DimText.Render("  No chapters available"))
+	}
+
+	return header + "\n" + strings.Join(rows, "\n")
+}
+
+// renderChapterRow renders "Chapter N · Title" with a dim release date,
+// or just "Chapter N" when the chapter has no metadata
+func (m ReaderModel) renderChapterRow(number int, style lipgloss.Style) string {
+	label := fmt.Sprintf("Chapter %d", number)
+	ch, ok := m.chapters[number]
+	if !ok {
+		return style.Render(label)
+	}
+	if ch.Title != "" {
+		label += " · " + ch.Title
+	}
+	row := style.Render(label)
+	if ch.ReleasedAt != nil {
+		row += "  " + m.theme.DimText.Render(ch.ReleasedAt.Local().Format("2006-01-02"))
+	}
+	return row
+}
+
+// renderHints renders the key hints
+func (m ReaderModel) renderHints() string {
+	hints := []string{
+		styles.RenderKeyHint("n", "next chapter"),
+		styles.RenderKeyHint("p", "previous chapter"),
+		styles.RenderKeyHint("u", "undo read"),
+		styles.RenderKeyHint("C", "discuss chapter"),
+		styles.RenderKeyHint("esc", "back"),
+	}
+	return strings.Join(hints, "   ")
+}
+
+// SetTheme switches the view to a new theme
+func (m *ReaderModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+}
+
+// SetWidth sets the view width
+func (m *ReaderModel) SetWidth(w int) {
+	m.width = w
+}
+
+// SetHeight sets the view height
+func (m *ReaderModel) SetHeight(h int) {
+	m.height = h
+}
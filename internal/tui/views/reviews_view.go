@@ -0,0 +1,320 @@
+// Package views - Reviews View Component
+// Danh sách review đầy đủ của một manga, mở từ Detail view
+// Sắp xếp theo recent/helpful (s), tải thêm trang (n)
+// Review có spoiler bị server che (trừ khi bật preference Show Spoilers); Enter tải lại nội dung để mở
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"mangahub/internal/tui/api"
+	"mangahub/internal/tui/styles"
+	"mangahub/pkg/models"
+)
+
+// ReviewsView holds the reviews view state
+type ReviewsView struct {
+	mangaID       string
+	mangaTitle    string
+	sort          string // models.ReviewSortRecent or models.ReviewSortHelpful
+	page          int    // last page loaded
+	reviews       []models.RatingWithUser
+	total         int
+	hasMore       bool
+	showSpoilers  bool            // user preference: expand spoiler reviews
+	revealed      map[string]bool // spoiler reviews opened in this session
+	withSpoilers  bool            // pages are fetched with spoiler text (a review was revealed)
+	selectedIndex int
+	viewport      viewport.Model
+	active        bool
+	loading       bool
+	spinner       spinner.Model
+	lastError     error
+	client        *api.Client
+	width         int
+	height        int
+	theme         *styles.Theme
+}
+
+// ReviewsLoadedMsg signals a page of reviews was loaded
+type ReviewsLoadedMsg struct {
+	Result *models.ReviewsResponse
+	Append bool // next page: add to the list instead of replacing it
+}
+
+// ReviewsErrorMsg signals an error
+type ReviewsErrorMsg struct {
+	Error error
+}
+
+// NewReviewsView creates a new reviews view; showSpoilers expands spoiler reviews
+func NewReviewsView(mangaID, mangaTitle string, showSpoilers bool) ReviewsView {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = styles.DefaultTheme.Spinner
+
+	vp := viewport.New(80, 20)
+	vp.Style = lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.ColorPrimary).
+		Padding(0, 1)
+
+	return ReviewsView{
+		mangaID:      mangaID,
+		mangaTitle:   mangaTitle,
+		sort:         models.ReviewSortRecent,
+		page:         1,
+		showSpoilers: showSpoilers,
+		revealed:     make(map[string]bool),
+		viewport:     vp,
+		spinner:      s,
+		client:       api.GetClient(),
+		theme:        styles.DefaultTheme,
+		active:       true,
+		loading:      true,
+	}
+}
+
+// Init initializes the view
+func (m ReviewsView) Init() tea.Cmd {
+	return tea.Batch(
+		m.spinner.Tick,
+		m.loadReviews(1, false),
+	)
+}
+
+// loadReviews loads one page of reviews in the current sort order
+func (m ReviewsView) loadReviews(page int, appendPage bool) tea.Cmd {
+	sort, spoilers := m.sort, m.showSpoilers || m.withSpoilers
+	return func() tea.Msg {
+		result, err := m.client.GetReviews(context.Background(), m.mangaID, sort, page, spoilers)
+		if err != nil {
+			return ReviewsErrorMsg{Error: err}
+		}
+		return ReviewsLoadedMsg{Result: result, Append: appendPage}
+	}
+}
+
+// Update handles messages
+func (m ReviewsView) Update(msg tea.Msg) (ReviewsView, tea.Cmd) {
+	var cmds []tea.Cmd
+
+	switch msg := msg.(type) {
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc", "q":
+			m.active = false
+			return m, nil
+		case "up", "k":
+			if m.selectedIndex > 0 {
+				m.selectedIndex--
+			}
+			m.viewport.SetContent(m.renderReviewsList())
+		case "down", "j":
+			if m.selectedIndex < len(m.reviews)-1 {
+				m.selectedIndex++
+			}
+			m.viewport.SetContent(m.renderReviewsList())
+		case "enter", " ":
+			// Reveal or collapse the selected spoiler review
+			if m.selectedIndex < len(m.reviews) && m.reviews[m.selectedIndex].IsSpoiler {
+				review := m.reviews[m.selectedIndex]
+				m.revealed[review.ID] = !m.revealed[review.ID]
+				m.viewport.SetContent(m.renderReviewsList())
+				// The server withheld the text; fetch the loaded pages again with it
+				if review.SpoilerMasked && m.revealed[review.ID] && !m.loading {
+					m.withSpoilers = true
+					m.loading = true
+					return m, tea.Batch(m.spinner.Tick, m.loadReviews(1, false))
+				}
+			}
+			return m, nil
+		case "s":
+			// Toggle sort order and start again from the first page
+			if m.sort == models.ReviewSortRecent {
+				m.sort = models.ReviewSortHelpful
+			} else {
+				m.sort = models.ReviewSortRecent
+			}
+			m.selectedIndex = 0
+			m.loading = true
+			return m, tea.Batch(m.spinner.Tick, m.loadReviews(1, false))
+		case "n":
+			// Load the next page below the current list
+			if m.hasMore && !m.loading {
+				m.loading = true
+				return m, tea.Batch(m.spinner.Tick, m.loadReviews(m.page+1, true))
+			}
+			return m, nil
+		case "R":
+			m.loading = true
+			return m, tea.Batch(m.spinner.Tick, m.loadReviews(1, false))
+		}
+
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.viewport.Width = msg.Width - 8
+		m.viewport.Height = msg.Height - 12
+		m.viewport.SetContent(m.renderReviewsList())
+
+	case ReviewsLoadedMsg:
+		if msg.Append {
+			m.reviews = append(m.reviews, msg.Result.Reviews...)
+		} else {
+			m.reviews = msg.Result.Reviews
+			m.viewport.GotoTop()
+		}
+		m.page = msg.Result.Page
+		m.total = msg.Result.Total
+		m.hasMore = msg.Result.HasMore
+		if m.selectedIndex >= len(m.reviews) {
+			m.selectedIndex = max(0, len(m.reviews)-1)
+		}
+		m.loading = false
+		m.viewport.SetContent(m.renderReviewsList())
+
+	case ReviewsErrorMsg:
+		m.lastError = msg.Error
+		m.loading = false
+
+	case spinner.TickMsg:
+		if m.loading {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			cmds = append(cmds, cmd)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	cmds = append(cmds, cmd)
+
+	return m, tea.Batch(cmds...)
+}
+
+// View renders the view
+func (m ReviewsView) View() string {
+	if !m.active {
+		return ""
+	}
+
+	var sections []string
+	sections = append(sections, m.theme.Title.Render(fmt.Sprintf("📝 Reviews: %s", m.mangaTitle)))
+
+	if m.loading && len(m.reviews) == 0 {
+		sections = append(sections, m.spinner.View()+" Loading reviews...")
+		return lipgloss.JoinVertical(lipgloss.Left, sections...)
+	}
+
+	if m.lastError != nil {
+		sections = append(sections, m.theme.ErrorText.Render(fmt.Sprintf("Error: %v", m.lastError)))
+	}
+
+	sortLabel := "Most recent"
+	if m.sort == models.ReviewSortHelpful {
+		sortLabel = "Most helpful"
+	}
+	status := fmt.Sprintf("%d of %d reviews · %s", len(m.reviews), m.total, sortLabel)
+	if m.loading {
+		status += " " + m.spinner.View()
+	}
+	sections = append(sections, m.theme.DimText.Render(status))
+	sections = append(sections, m.viewport.View())
+
+	help := "↑/↓: navigate | Enter: show/hide spoiler | s: sort | R: refresh | q: back"
+	if m.hasMore {
+		help = "↑/↓: navigate | Enter: show/hide spoiler | s: sort | n: more | R: refresh | q: back"
+	}
+	sections = append(sections, m.theme.DimText.Render(help))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return lipgloss.NewStyle().
+		Width(m.width-4).
+		Padding(1, 2).
+		Render(content)
+}
+
+// renderReviewsList renders every loaded review
+func (m ReviewsView) renderReviewsList() string {
+	if len(m.reviews) == 0 {
+		return m.theme.DimText.Render("No written reviews yet.")
+	}
+
+	var rows []string
+	for i, review := range m.reviews {
+		if i > 0 {
+			rows = append(rows, m.theme.DimText.Render(strings.Repeat("─", 70)))
+		}
+		rows = append(rows, m.renderReview(review, i == m.selectedIndex))
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, rows...)
+}
+
+// renderReview renders one review; spoilers stay collapsed unless shown
+func (m ReviewsView) renderReview(review models.RatingWithUser, selected bool) string {
+	selector := "  "
+	if selected {
+		selector = m.theme.Primary.Render("▶ ")
+	}
+
+	author := review.DisplayName
+	if author == "" {
+		author = review.Username
+	}
+	header := selector + m.theme.Primary.Bold(true).Render(author) + " " +
+		m.theme.Warning.Render(fmt.Sprintf("★ %d/10", review.Rating)) + " " +
+		m.theme.DimText.Render(formatTimestamp(review.CreatedAt))
+	if review.IsEdited {
+		header += " " + m.theme.DimText.Italic(true).Render("(edited)")
+	}
+	if review.IsSpoiler {
+		header += " " + m.theme.Warning.Render("⚠ spoiler")
+	}
+
+	width := m.viewport.Width - 6
+	if width < 20 {
+		width = 60
+	}
+
+	var body string
+	if review.SpoilerMasked || (review.IsSpoiler && !m.showSpoilers && !m.revealed[review.ID]) {
+		body = m.theme.DimText.Italic(true).Render("[spoiler - press Enter to reveal]")
+	} else {
+		style := m.theme.Description
+		if selected {
+			style = m.theme.Primary
+		}
+		body = style.Render(wordWrap(review.ReviewText, width))
+	}
+	body = lipgloss.NewStyle().PaddingLeft(2).Render(body)
+
+	footer := "  " + m.theme.DimText.Render(fmt.Sprintf("👍 %d found this helpful", review.HelpfulCount))
+
+	return lipgloss.JoinVertical(lipgloss.Left, header, body, footer, "")
+}
+
+// IsActive returns whether the view is active
+func (m ReviewsView) IsActive() bool {
+	return m.active
+}
+
+// SetShowSpoilers expands or collapses spoiler reviews (preference changed)
+func (m *ReviewsView) SetShowSpoilers(show bool) {
+	m.showSpoilers = show
+	m.viewport.SetContent(m.renderReviewsList())
+}
+
+// SetTheme switches the view to a new theme
+func (m *ReviewsView) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
+	m.viewport.Style = m.viewport.Style.BorderForeground(t.Palette.Primary)
+}
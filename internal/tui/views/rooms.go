@@ -0,0 +1,263 @@
+// Package views - Chat Rooms Directory
+// Danh sách chat rooms (GET /rooms) kèm số người đang online, phòng đông nhất lên đầu
+// Lọc theo loại room (all/general/manga), ẩn room không có ai, phân trang
+// Layout:
+//
+//	┌────────────────────────────────────────────────────────┐
+//	│  💬 CHAT ROOMS · manga · page 1/3                      │
+//	│                                                        │
+//	│  > Berserk Discussion                     👥 12        │
+//	│    general                                👥 4         │
+//	│    Monster Discussion                     👥 0         │
+//	│                                                        │
+//	│  [Enter] Join  [f] Type  [e] Hide empty  [←→] Page     │
+//	└────────────────────────────────────────────────────────┘
+package views
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"mangahub/internal/tui/api"
+	"mangahub/internal/tui/network"
+	"mangahub/internal/tui/styles"
+)
+
+// roomTypes are the directory filters [f] cycles through; "" is every room
+var roomTypes = []string{"", "general", "manga"}
+
+// roomsPageSize is how many rooms one directory page shows
+const roomsPageSize = 15
+
+// =====================================
+// ROOMS MODEL
+// =====================================
+
+// RoomsModel holds the rooms directory state
+type RoomsModel struct {
+	width  int
+	height int
+	theme  *styles.Theme
+
+	// Current page of the directory
+	rooms    []api.ChatRoom
+	total    int
+	page     int
+	hasMore  bool
+	selected int
+
+	// Filters: index into roomTypes, and whether empty rooms are hidden
+	typeIndex int
+	hideEmpty bool
+
+	loading   bool
+	lastError error
+	spinner   spinner.Model
+
+	client *api.Client
+}
+
+// roomsLoadedMsg carries a page of the directory
+type roomsLoadedMsg struct {
+	Page  int
+	Rooms *api.RoomDirectoryResponse
+	Error error
+}
+
+// NewRooms creates a new rooms directory model
+func NewRooms() RoomsModel {
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = styles.DefaultTheme.Spinner
+
+	return RoomsModel{
+		theme:   styles.DefaultTheme,
+		page:    1,
+		loading: true, // until Init's first load lands
+		spinner: s,
+		client:  api.GetClient(),
+	}
+}
+
+// =====================================
+// BUBBLE TEA INTERFACE
+// =====================================
+
+// Init loads the current page; member counts change, so it reloads every time
+func (m RoomsModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, m.loadRooms(m.page))
+}
+
+// Update handles messages
+func (m RoomsModel) Update(msg tea.Msg) (RoomsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			if m.selected > 0 {
+				m.selected--
+			}
+		case "down", "j":
+			if m.selected < len(m.rooms)-1 {
+				m.selected++
+			}
+		case "left", "pgup":
+			if m.page > 1 {
+				cmd := m.loadRooms(m.page - 1)
+				return m, cmd
+			}
+		case "right", "pgdown":
+			if m.hasMore {
+				cmd := m.loadRooms(m.page + 1)
+				return m, cmd
+			}
+		case "f":
+			m.typeIndex = (m.typeIndex + 1) % len(roomTypes)
+			cmd := m.loadRooms(1)
+			return m, cmd
+		case "e":
+			m.hideEmpty = !m.hideEmpty
+			cmd := m.loadRooms(1)
+			return m, cmd
+		case "r":
+			cmd := m.loadRooms(m.page)
+			return m, cmd
+		case "enter":
+			if room := m.selectedRoom(); room != nil {
+				return m, joinRoom(*room)
+			}
+		}
+
+	case roomsLoadedMsg:
+		m.loading = false
+		m.lastError = msg.Error
+		if msg.Error != nil {
+			return m, nil
+		}
+		m.rooms = msg.Rooms.Rooms
+		m.total = msg.Rooms.Total
+		m.hasMore = msg.Rooms.HasMore
+		m.page = msg.Page
+		m.selected = min(m.selected, max(0, len(m.rooms)-1))
+
+	case spinner.TickMsg:
+		if m.loading {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+	}
+
+	return m, nil
+}
+
+// loadRooms fetches one page with the current filters.
+// Callers take the command before returning m so the loading flag sticks.
+func (m *RoomsModel) loadRooms(page int) tea.Cmd {
+	m.loading = true
+	client := m.client
+	roomType := roomTypes[m.typeIndex]
+	hideEmpty := m.hideEmpty
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		rooms, err := client.ListRooms(ctx, roomType, hideEmpty, page, roomsPageSize)
+		return roomsLoadedMsg{Page: page, Rooms: rooms, Error: err}
+	}
+}
+
+// joinRoom asks the app to open the room in the chat view
+func joinRoom(room api.ChatRoom) tea.Cmd {
+	return func() tea.Msg {
+		join := network.JoinRoomMsg{RoomID: room.ID, RoomName: room.Name}
+		if room.MangaID != nil {
+			join.MangaID = *room.MangaID
+			join.MangaName = strings.TrimSuffix(room.Name, " Discussion")
+		}
+		return join
+	}
+}
+
+// selectedRoom returns the highlighted room, nil when the page is empty
+func (m RoomsModel) selectedRoom() *api.ChatRoom {
+	if m.selected < len(m.rooms) {
+		return &m.rooms[m.selected]
+	}
+	return nil
+}
+
+// SetTheme switches the view to a new theme
+func (m *RoomsModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
+}
+
+// =====================================
+// VIEW
+// =====================================
+
+// View renders the rooms directory
+func (m RoomsModel) View() string {
+	var sections []string
+
+	filter := "all rooms"
+	if roomTypes[m.typeIndex] != "" {
+		filter = roomTypes[m.typeIndex]
+	}
+	if m.hideEmpty {
+		filter += ", active only"
+	}
+	pages := max(1, (m.total+roomsPageSize-1)/roomsPageSize)
+	sections = append(sections, m.theme.PanelHeader.Render(
+		fmt.Sprintf("💬 CHAT ROOMS · %s · page %d/%d", filter, m.page, pages)))
+
+	switch {
+	case m.loading && len(m.rooms) == 0:
+		sections = append(sections, m.spinner.View()+" Loading rooms...")
+	case m.lastError != nil:
+		sections = append(sections, m.theme.ErrorText.Render("⚠ "+m.lastError.Error()))
+	case len(m.rooms) == 0:
+		sections = append(sections, m.theme.DimText.Render("No rooms here yet. Open a manga and press [c] to start one."))
+	default:
+		sections = append(sections, m.renderRooms())
+	}
+
+	sections = append(sections, "", strings.Join([]string{
+		styles.RenderKeyHint("Enter", "join"),
+		styles.RenderKeyHint("f", "type"),
+		styles.RenderKeyHint("e", "hide empty"),
+		styles.RenderKeyHint("←→", "page"),
+		styles.RenderKeyHint("r", "refresh"),
+	}, "  "))
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return m.theme.Container.Width(m.width - 4).Render(content)
+}
+
+func (m RoomsModel) renderRooms() string {
+	nameWidth := max(20, m.width-30)
+	var rows []string
+	for i, room := range m.rooms {
+		label := fmt.Sprintf("%-*s", nameWidth, truncate(room.Name, nameWidth))
+		count := fmt.Sprintf("👥 %d", room.MemberCount)
+		if !room.IsActive {
+			count += " (closed)"
+		}
+		if i == m.selected {
+			rows = append(rows, m.theme.Primary.Render("> "+label)+"  "+m.theme.Description.Render(count))
+		} else {
+			rows = append(rows, "  "+label+"  "+m.theme.DimText.Render(count))
+		}
+	}
+	return strings.Join(rows, "\n")
+}
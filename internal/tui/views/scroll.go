@@ -0,0 +1,52 @@
+// Package views - List Scrolling
+// Scroll state dùng chung cho các list (Library, Browse, Activity, Search)
+// Cursor luôn nằm trong vùng hiển thị, kể cả khi resize hay list ngắn lại
+package views
+
+import "fmt"
+
+// listScroll remembers the first visible row of a list. Views pass the row
+// count that fits their current height on every call, so a resize takes
+// effect on the next render without extra bookkeeping.
+type listScroll struct {
+	offset int
+}
+
+// window returns the rows [start, end) to draw for a list of total rows with
+// room for rows of them. The window moves from the last offset only as far as
+// needed to show cursor, and never leaves blank rows under a list that fits
+// better after a resize. Fewer than one row still shows the cursor.
+func (s listScroll) window(cursor, total, rows int) (start, end int) {
+	if total <= 0 {
+		return 0, 0
+	}
+	rows = max(rows, 1)
+	cursor = min(max(cursor, 0), total-1)
+
+	start = min(s.offset, max(total-rows, 0))
+	if cursor < start {
+		start = cursor
+	}
+	if cursor >= start+rows {
+		start = cursor - rows + 1
+	}
+	return start, min(start+rows, total)
+}
+
+// follow keeps cursor in view; call it when the cursor, the list or the height changes
+func (s *listScroll) follow(cursor, total, rows int) {
+	s.offset, _ = s.window(cursor, total, rows)
+}
+
+// reset scrolls back to the top
+func (s *listScroll) reset() {
+	s.offset = 0
+}
+
+// scrollStatus describes the window, or is empty when the whole list fits
+func scrollStatus(start, end, total int) string {
+	if start == 0 && end >= total {
+		return ""
+	}
+	return fmt.Sprintf("Showing %d-%d of %d", start+1, end, total)
+}
@@ -1,12 +1,20 @@
 // Package views - Manga Search View
 // Interactive search with instant results
+// Search-as-you-type: debounce 250ms, huỷ request cũ khi gõ tiếp,
+// query đã có trong cache hiện ngay không cần chờ.
+// Trong lúc chờ, dropdown gợi ý title (GET /manga/suggest) hiện ngay dưới ô input.
+// Filters: Ctrl+G đổi genre, Ctrl+O đổi sort, Ctrl+R đảo chiều sort.
+// Ô input trống: recent searches hiện dưới input, saved searches ở sidebar;
+// ↑↓ chọn, Enter chạy lại, Ctrl+D xoá. Ctrl+S lưu search hiện tại với một cái tên.
 // Layout:
 //
 //	┌────────────────────────────────────────────────────────┐
 //	│  🔍 SEARCH                                             │
 //	│  ┌─────────────────────────────────────────────────┐   │
-//	│  │ one piece_                                      │   │
+//	│  │ one pi_                           ⠋ searching…  │   │
 //	│  └─────────────────────────────────────────────────┘   │
+//	│    ▸ One Piece                          [Tab] complete │
+//	│      One Piece: Strong World                           │
 //	│                                                        │
 //	│  RESULTS (42 found)                                    │
 //	│  ┌─────────────────────────────────────────────────┐   │
@@ -15,13 +23,14 @@
 //	│  │   One Piece: Side...  Oda/Boichi      ⭐ 8.1   │   │
 //	│  └─────────────────────────────────────────────────┘   │
 //	│                                                        │
-//	│  [↑↓] Navigate  [Enter] View  [Esc] Clear              │
+//	│  [↑↓] Navigate  [Enter] View  [^S] Save  [^G] Genre    │
 //	└────────────────────────────────────────────────────────┘
 package views
 
 import (
 	"context"
 	"fmt"
+	"slices"
 	"strings"
 	"time"
 
@@ -55,14 +64,39 @@ type SearchModel struct {
 	// Results
 	results       []models.Manga
 	selectedIndex int
+	scroll        listScroll
 	totalResults  int
 
-	// Loading state
-	loading   bool
-	lastQuery string
-
-	// Debounce
-	debounceTimer time.Time
+	// Loading state; last is the query and filters the results belong to
+	loading bool
+	last    models.SearchFilters
+
+	// Filters: index into Categories (-1 = any genre) and into
+	// models.MangaSortFields (-1 = relevance), with the sort's direction
+	genreIndex int
+	sortIndex  int
+	sortOrder  string
+
+	// History: recent searches under the empty input, saved ones in the sidebar.
+	// pickIndex walks recent then saved while the input is empty.
+	recent    []models.RecentSearch
+	saved     []models.SavedSearch
+	pickIndex int
+
+	// Naming the current search before saving it (Ctrl+S)
+	naming    bool
+	nameInput textinput.Model
+	notice    string
+
+	// Debounce: each edit bumps searchSeq so only the latest tick searches,
+	// and starting a search cancels the one still in flight
+	searchSeq    int
+	cancelSearch context.CancelFunc
+
+	// Title suggestions for suggestQuery, shown until the full search lands
+	suggestions   []models.MangaSuggestion
+	suggestQuery  string
+	cancelSuggest context.CancelFunc
 
 	// Error
 	lastError error
@@ -77,21 +111,53 @@ type SearchModel struct {
 
 // SearchResultsMsg carries search results
 type SearchResultsMsg struct {
-	Query   string
+	Search  models.SearchFilters
 	Results []models.Manga
 	Total   int
 }
 
 // SearchErrorMsg signals search error
 type SearchErrorMsg struct {
-	Error error
+	Search models.SearchFilters
+	Error  error
+}
+
+// SearchSuggestionsMsg carries title suggestions for a typed prefix
+type SearchSuggestionsMsg struct {
+	Query       string
+	Suggestions []models.MangaSuggestion
 }
 
 // SearchDebounceMsg triggers debounced search
 type SearchDebounceMsg struct {
-	Query string
+	Search models.SearchFilters
+	Seq    int
+}
+
+// SearchHistoryLoadedMsg carries saved searches and recent history
+type SearchHistoryLoadedMsg struct {
+	Searches *models.SearchesResponse
+	Error    error
 }
 
+// SearchHistoryChangedMsg reports a save, delete or clear; the history is reloaded after it
+type SearchHistoryChangedMsg struct {
+	Message string
+	Error   error
+}
+
+// searchPickedMsg runs a search picked from the history. It is sent rather
+// than run in place so the Enter that picked it cannot also open a result.
+type searchPickedMsg struct{}
+
+// Search-as-you-type tuning
+const (
+	searchDebounce  = 250 * time.Millisecond
+	searchMinLength = 2 // same as the server's models.MinSuggestPrefix
+	searchPageSize  = 20
+	suggestLimit    = 5
+)
+
 // =====================================
 // CONSTRUCTOR
 // =====================================
@@ -108,17 +174,26 @@ func NewSearch() SearchModel {
 	ti.TextStyle = styles.DefaultTheme.Description
 	ti.PlaceholderStyle = styles.DefaultTheme.DimText
 
+	// Name prompt for saving a search
+	ni := textinput.New()
+	ni.Placeholder = "Name this search (Enter to save, empty to cancel)"
+	ni.CharLimit = 100
+	ni.Width = 50
+
 	// Create spinner
 	s := spinner.New()
 	s.Spinner = spinner.Dot
 	s.Style = styles.DefaultTheme.Spinner
 
 	return SearchModel{
-		theme:   styles.DefaultTheme,
-		input:   ti,
-		spinner: s,
-		client:  api.GetClient(),
-		results: []models.Manga{},
+		theme:      styles.DefaultTheme,
+		input:      ti,
+		nameInput:  ni,
+		spinner:    s,
+		client:     api.GetClient(),
+		results:    []models.Manga{},
+		genreIndex: -1,
+		sortIndex:  -1,
 	}
 }
 
@@ -128,7 +203,7 @@ func NewSearch() SearchModel {
 
 // Init initializes the search view
 func (m SearchModel) Init() tea.Cmd {
-	return textinput.Blink
+	return tea.Batch(textinput.Blink, m.loadSearches())
 }
 
 // Update handles messages
@@ -142,29 +217,79 @@ func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
 		m.input.Width = msg.Width - 16
 
 	case tea.KeyMsg:
+		if m.naming {
+			return m.updateNameInput(msg)
+		}
+		m.notice = ""
+
+		// The input always has focus, so j/k are typed rather than used to navigate
 		switch msg.String() {
-		case "up", "k":
-			if len(m.results) > 0 {
+		case "up":
+			if m.picking() {
+				m.pickIndex = (m.pickIndex + m.pickCount() - 1) % m.pickCount()
+			} else if len(m.results) > 0 {
 				m.selectedIndex--
 				if m.selectedIndex < 0 {
 					m.selectedIndex = len(m.results) - 1
 				}
 			}
-		case "down", "j":
-			if len(m.results) > 0 {
+		case "down":
+			if m.picking() {
+				m.pickIndex = (m.pickIndex + 1) % m.pickCount()
+			} else if len(m.results) > 0 {
 				m.selectedIndex = (m.selectedIndex + 1) % len(m.results)
 			}
 		case "enter":
-			// Return the selected manga ID
-			// Will be handled by parent
-			if len(m.results) > 0 && m.selectedIndex < len(m.results) {
-				// Navigation will be handled by parent
+			// The parent opens the selected result; the search that found it
+			// goes into the history
+			if m.picking() {
+				m.applySearch(m.pickedSearch())
+				cmds = append(cmds, func() tea.Msg { return searchPickedMsg{} })
+			} else if len(m.results) > 0 && m.selectedIndex < len(m.results) {
+				cmds = append(cmds, m.recordSearch(m.last))
+			}
+		case "ctrl+g":
+			m.genreIndex++
+			if m.genreIndex >= len(Categories) {
+				m.genreIndex = -1
+			}
+			cmds = append(cmds, m.queryChanged())
+		case "ctrl+o":
+			m.sortIndex++
+			if m.sortIndex >= len(models.MangaSortFields) {
+				m.sortIndex = -1
+			}
+			m.sortOrder = models.DefaultMangaSortOrder(m.sortField())
+			if m.sortIndex < 0 {
+				m.sortOrder = ""
+			}
+			cmds = append(cmds, m.queryChanged())
+		case "ctrl+r":
+			if m.sortIndex >= 0 {
+				if m.sortOrder == models.SortAsc {
+					m.sortOrder = models.SortDesc
+				} else {
+					m.sortOrder = models.SortAsc
+				}
+				cmds = append(cmds, m.queryChanged())
+			}
+		case "ctrl+s":
+			cmds = append(cmds, m.startNaming())
+		case "ctrl+d":
+			if m.picking() {
+				cmds = append(cmds, m.deletePicked())
+			}
+		case "tab":
+			// Complete the query with the top suggestion
+			if m.showSuggestions() {
+				m.input.SetValue(m.suggestions[0].Title)
+				m.input.CursorEnd()
+				cmds = append(cmds, m.queryChanged())
 			}
 		case "esc":
 			// Clear input
 			m.input.SetValue("")
-			m.results = []models.Manga{}
-			m.totalResults = 0
+			cmds = append(cmds, m.queryChanged())
 		default:
 			// Update text input
 			var cmd tea.Cmd
@@ -172,59 +297,329 @@ func (m SearchModel) Update(msg tea.Msg) (SearchModel, tea.Cmd) {
 			cmds = append(cmds, cmd)
 
 			// Trigger debounced search
-			query := m.input.Value()
-			if query != m.lastQuery && len(query) >= 2 {
-				m.lastQuery = query
-				m.debounceTimer = time.Now()
-				cmds = append(cmds, m.debounceSearch(query))
-			}
+			cmds = append(cmds, m.queryChanged())
 		}
 
 	case SearchDebounceMsg:
-		// Only search if query hasn't changed
-		if msg.Query == m.input.Value() {
-			m.loading = true
-			cmds = append(cmds, m.executeSearch(msg.Query))
+		// A newer edit has its own tick pending
+		if msg.Seq != m.searchSeq {
+			break
+		}
+		m.stopSearch()
+		ctx, cancel := context.WithCancel(context.Background())
+		m.cancelSearch = cancel
+		cmds = append(cmds, m.executeSearch(ctx, msg.Search))
+
+	case SearchSuggestionsMsg:
+		// Too late once the full results for this query are in
+		if msg.Query == m.last.Query && m.loading {
+			m.suggestions = msg.Suggestions
+			m.suggestQuery = msg.Query
 		}
 
 	case SearchResultsMsg:
-		if msg.Query == m.input.Value() {
-			m.results = msg.Results
-			m.totalResults = msg.Total
-			m.loading = false
-			m.selectedIndex = 0
+		if msg.Search == m.last {
+			m.stopSearch()
+			m.setResults(msg.Results, msg.Total)
 		}
 
 	case SearchErrorMsg:
-		m.lastError = msg.Error
-		m.loading = false
+		if msg.Search == m.last {
+			m.stopSearch()
+			m.lastError = msg.Error
+		}
+
+	case searchPickedMsg:
+		cmds = append(cmds, m.queryChanged(), m.recordSearch(m.currentSearch()))
+
+	case SearchHistoryLoadedMsg:
+		if msg.Error == nil && msg.Searches != nil {
+			m.recent = msg.Searches.Recent
+			m.saved = msg.Searches.Saved
+			m.pickIndex = min(m.pickIndex, max(0, m.pickCount()-1))
+		}
+
+	case SearchHistoryChangedMsg:
+		if msg.Error != nil {
+			m.notice = "⚠ " + msg.Error.Error()
+			break
+		}
+		if msg.Message != "" {
+			m.notice = msg.Message
+		}
+		cmds = append(cmds, m.loadSearches())
 
 	case spinner.TickMsg:
-		var cmd tea.Cmd
-		m.spinner, cmd = m.spinner.Update(msg)
-		cmds = append(cmds, cmd)
+		// Keep ticking only while a search is pending
+		if m.loading {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			cmds = append(cmds, cmd)
+		}
 	}
 
+	m.scroll.follow(m.selectedIndex, len(m.results), m.listRows())
 	return m, tea.Batch(cmds...)
 }
 
-// debounceSearch creates a debounced search command
-func (m SearchModel) debounceSearch(query string) tea.Cmd {
-	return tea.Tick(300*time.Millisecond, func(t time.Time) tea.Msg {
-		return SearchDebounceMsg{Query: query}
+// listRows is how many result rows fit under the input and above the help
+func (m SearchModel) listRows() int {
+	return m.height - 17
+}
+
+// queryChanged reacts to an edited query or filter. Cached queries show at once;
+// anything else waits for searchDebounce without typing before searching.
+// A genre filter alone is enough to search; a bare query needs searchMinLength.
+func (m *SearchModel) queryChanged() tea.Cmd {
+	search := m.currentSearch()
+	if search == m.last {
+		return nil
+	}
+	m.last = search
+	m.searchSeq++
+	m.stopSearch()
+	m.lastError = nil
+
+	if len(search.Query) < searchMinLength && search.Genre == "" {
+		m.setResults([]models.Manga{}, 0)
+		return nil
+	}
+	if search.Genre == "" && search.Sort == "" {
+		if results, total, ok := m.client.CachedSearch(search.Query, 1, searchPageSize); ok {
+			m.setResults(results, total)
+			return nil
+		}
+	}
+
+	seq := m.searchSeq
+	debounce := tea.Tick(searchDebounce, func(time.Time) tea.Msg {
+		return SearchDebounceMsg{Search: search, Seq: seq}
+	})
+	var suggest tea.Cmd
+	// Suggestions are cheap, so they are fetched on every edit without waiting
+	if len(search.Query) >= searchMinLength {
+		suggest = m.fetchSuggestions(search.Query)
+	}
+	if m.loading {
+		return tea.Batch(debounce, suggest)
+	}
+	m.loading = true
+	return tea.Batch(debounce, suggest, m.spinner.Tick)
+}
+
+// currentSearch is the typed query with the active filters
+func (m SearchModel) currentSearch() models.SearchFilters {
+	search := models.SearchFilters{
+		Query: strings.TrimSpace(m.input.Value()),
+		Sort:  m.sortField(),
+		Order: m.sortOrder,
+	}
+	if m.genreIndex >= 0 {
+		search.Genre = genreSlug(Categories[m.genreIndex].Name)
+	}
+	return search
+}
+
+// applySearch puts a recent or saved search back into the input and filters.
+// Genres outside Categories and unknown sorts fall back to no filter.
+func (m *SearchModel) applySearch(search models.SearchFilters) {
+	m.input.SetValue(search.Query)
+	m.input.CursorEnd()
+	m.genreIndex = slices.IndexFunc(Categories, func(c Category) bool {
+		return genreSlug(c.Name) == search.Genre
+	})
+	m.sortIndex = slices.Index(models.MangaSortFields, search.Sort)
+	m.sortOrder = ""
+	if m.sortIndex >= 0 {
+		m.sortOrder = search.Order
+		if m.sortOrder == "" {
+			m.sortOrder = models.DefaultMangaSortOrder(search.Sort)
+		}
+	}
+}
+
+// sortField is the active models.MangaSortFields entry, empty for relevance
+func (m SearchModel) sortField() string {
+	if m.sortIndex < 0 {
+		return ""
+	}
+	return models.MangaSortFields[m.sortIndex]
+}
+
+// genreSlug turns a category name into its genre slug: "Slice of Life" -> "slice-of-life"
+func genreSlug(name string) string {
+	return strings.Join(strings.Fields(strings.ToLower(name)), "-")
+}
+
+// =====================================
+// HISTORY & SAVED SEARCHES
+// =====================================
+
+// picking reports whether ↑↓ and Enter work on the history: the input is
+// empty, no filter is set and there is something to pick
+func (m SearchModel) picking() bool {
+	return m.currentSearch().IsEmpty() && m.pickCount() > 0
+}
+
+// pickCount is the number of recent plus saved searches
+func (m SearchModel) pickCount() int {
+	return len(m.recent) + len(m.saved)
+}
+
+// pickedSearch returns the highlighted recent or saved search
+func (m SearchModel) pickedSearch() models.SearchFilters {
+	if m.pickIndex < len(m.recent) {
+		return m.recent[m.pickIndex].SearchFilters
+	}
+	return m.saved[m.pickIndex-len(m.recent)].SearchFilters
+}
+
+// loadSearches fetches saved searches and recent history
+func (m SearchModel) loadSearches() tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		searches, err := client.GetSearches(ctx)
+		return SearchHistoryLoadedMsg{Searches: searches, Error: err}
+	}
+}
+
+// recordSearch adds a search to the history; failures are not worth interrupting for
+func (m SearchModel) recordSearch(search models.SearchFilters) tea.Cmd {
+	client := m.client
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := client.RecordSearch(ctx, search); err != nil {
+			return nil
+		}
+		return SearchHistoryChangedMsg{}
+	}
+}
+
+// historyAction runs a save, delete or clear and reports it
+func (m SearchModel) historyAction(message string, action func(ctx context.Context) error) tea.Cmd {
+	return func() tea.Msg {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		if err := action(ctx); err != nil {
+			return SearchHistoryChangedMsg{Error: err}
+		}
+		return SearchHistoryChangedMsg{Message: message}
+	}
+}
+
+// startNaming opens the name prompt for the current search.
+// Saved searches live on the server, so guests are asked to log in.
+func (m *SearchModel) startNaming() tea.Cmd {
+	switch {
+	case !m.client.IsAuthenticated():
+		m.notice = "Log in to save searches"
+		return nil
+	case m.currentSearch().IsEmpty():
+		m.notice = "Type a query or pick a filter first"
+		return nil
+	}
+	m.naming = true
+	m.input.Blur()
+	return m.nameInput.Focus()
+}
+
+// updateNameInput handles typing the saved search's name; an empty name cancels
+func (m SearchModel) updateNameInput(msg tea.KeyMsg) (SearchModel, tea.Cmd) {
+	if msg.String() != "enter" {
+		var cmd tea.Cmd
+		m.nameInput, cmd = m.nameInput.Update(msg)
+		return m, cmd
+	}
+
+	name := strings.TrimSpace(m.nameInput.Value())
+	m.naming = false
+	m.nameInput.Blur()
+	m.nameInput.SetValue("")
+	focus := m.input.Focus()
+	if name == "" {
+		return m, focus
+	}
+
+	req := models.SaveSearchRequest{Name: name, SearchFilters: m.currentSearch()}
+	client := m.client
+	return m, tea.Batch(focus, m.historyAction("Saved search "+name, func(ctx context.Context) error {
+		_, err := client.SaveSearch(ctx, req)
+		return err
+	}))
+}
+
+// deletePicked deletes the highlighted saved search, or clears the history
+// when a recent search is highlighted
+func (m SearchModel) deletePicked() tea.Cmd {
+	client := m.client
+	if m.pickIndex < len(m.recent) {
+		return m.historyAction("Search history cleared", client.ClearSearchHistory)
+	}
+	saved := m.saved[m.pickIndex-len(m.recent)]
+	return m.historyAction("Deleted "+saved.Name, func(ctx context.Context) error {
+		return client.DeleteSavedSearch(ctx, saved.ID)
 	})
 }
 
+// fetchSuggestions asks for title suggestions, cancelling the previous request
+func (m *SearchModel) fetchSuggestions(query string) tea.Cmd {
+	if m.cancelSuggest != nil {
+		m.cancelSuggest()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	m.cancelSuggest = cancel
+
+	client := m.client
+	return func() tea.Msg {
+		suggestions, err := client.SuggestManga(ctx, query, suggestLimit)
+		// Failures are ignored: the full search reports its own errors
+		if err != nil || ctx.Err() != nil {
+			return nil
+		}
+		return SearchSuggestionsMsg{Query: query, Suggestions: suggestions}
+	}
+}
+
+// showSuggestions reports whether the dropdown is visible: there are
+// suggestions for the current query and its full search is still pending
+func (m SearchModel) showSuggestions() bool {
+	return m.loading && len(m.suggestions) > 0 && m.suggestQuery == m.last.Query
+}
+
+// stopSearch cancels the in-flight request, if any
+func (m *SearchModel) stopSearch() {
+	if m.cancelSearch != nil {
+		m.cancelSearch()
+		m.cancelSearch = nil
+	}
+	m.loading = false
+}
+
+// setResults shows results for the current query, replacing the suggestions
+func (m *SearchModel) setResults(results []models.Manga, total int) {
+	m.results = results
+	m.totalResults = total
+	m.selectedIndex = 0
+	m.loading = false
+	m.suggestions = nil
+}
+
 // executeSearch performs the actual search
-func (m SearchModel) executeSearch(query string) tea.Cmd {
+func (m SearchModel) executeSearch(ctx context.Context, search models.SearchFilters) tea.Cmd {
 	return func() tea.Msg {
-		ctx := context.Background()
-		results, total, err := m.client.SearchManga(ctx, query, 1, 20)
+		results, total, err := m.client.SearchMangaFiltered(ctx, search, 1, searchPageSize)
+		// Superseded by a newer keystroke: drop the result quietly
+		if ctx.Err() != nil {
+			return nil
+		}
 		if err != nil {
-			return SearchErrorMsg{Error: err}
+			return SearchErrorMsg{Search: search, Error: err}
 		}
 		return SearchResultsMsg{
-			Query:   query,
+			Search:  search,
 			Results: results,
 			Total:   total,
 		}
@@ -243,9 +638,16 @@ func (m SearchModel) View() string {
 	inputBox := m.renderInputBox()
 	sections = append(sections, inputBox)
 
-	// ===== RESULTS =====
-	results := m.renderResults()
-	sections = append(sections, results)
+	// ===== RESULTS, OR RECENT + SAVED WHILE THE INPUT IS EMPTY =====
+	if m.currentSearch().IsEmpty() && m.pickCount() > 0 {
+		sections = append(sections, m.renderHistory())
+	} else {
+		sections = append(sections, m.renderResults())
+	}
+
+	if m.notice != "" {
+		sections = append(sections, m.theme.DimText.Render(m.notice))
+	}
 
 	// ===== HELP =====
 	help := m.renderHelp()
@@ -266,17 +668,120 @@ func (m SearchModel) renderInputBox() string {
 		Padding(0, 1).
 		Width(m.width - 10)
 
-	return inputStyle.Render(m.input.View()) + "\n"
+	content := m.input.View()
+	if m.naming {
+		content = m.nameInput.View()
+	} else if m.loading {
+		content += "  " + m.spinner.View() + m.theme.DimText.Render(" searching…")
+	}
+	return inputStyle.Render(content) + "\n" + m.renderFilters() + "\n" + m.renderSuggestions()
+}
+
+// renderFilters renders the genre and sort chips under the input
+func (m SearchModel) renderFilters() string {
+	genre := "any genre"
+	if m.genreIndex >= 0 {
+		genre = Categories[m.genreIndex].Name
+	}
+	sort := "relevance"
+	if m.sortIndex >= 0 {
+		arrow := "↓"
+		if m.sortOrder == models.SortAsc {
+			arrow = "↑"
+		}
+		sort = m.sortField() + " " + arrow
+	}
+	return "  " + m.theme.Key.Render("[^G]") + " " + m.theme.Description.Render(genre) +
+		"   " + m.theme.Key.Render("[^O]") + " " + m.theme.Description.Render(sort)
+}
+
+// renderHistory renders recent searches with the saved searches in a sidebar
+func (m SearchModel) renderHistory() string {
+	var recentRows []string
+	recentRows = append(recentRows, m.theme.PanelHeader.Render("RECENT"))
+	for i, r := range m.recent {
+		recentRows = append(recentRows, m.renderPickRow(describeSearch(r.SearchFilters), i == m.pickIndex))
+	}
+	if len(m.recent) == 0 {
+		recentRows = append(recentRows, m.theme.DimText.Render("  No recent searches"))
+	}
+
+	var savedRows []string
+	savedRows = append(savedRows, m.theme.PanelHeader.Render("SAVED"))
+	for i, s := range m.saved {
+		row := m.renderPickRow(s.Name, len(m.recent)+i == m.pickIndex)
+		savedRows = append(savedRows, row, "    "+m.theme.DimText.Render(describeSearch(s.SearchFilters)))
+	}
+	if len(m.saved) == 0 {
+		savedRows = append(savedRows, m.theme.DimText.Render("  [^S] saves a search"))
+	}
+
+	sidebarWidth := max(24, (m.width-10)/3)
+	recent := lipgloss.NewStyle().Width(m.width - 14 - sidebarWidth).
+		Render(lipgloss.JoinVertical(lipgloss.Left, recentRows...))
+	sidebar := lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(styles.ColorDim).
+		Padding(0, 1).
+		Width(sidebarWidth).
+		Render(lipgloss.JoinVertical(lipgloss.Left, savedRows...))
+	return lipgloss.JoinHorizontal(lipgloss.Top, recent, sidebar)
+}
+
+// renderPickRow renders a history or saved search row
+func (m SearchModel) renderPickRow(label string, selected bool) string {
+	if selected {
+		return m.theme.Primary.Render("> ") + m.theme.Title.Bold(true).Render(label)
+	}
+	return "  " + m.theme.Description.Render(label)
+}
+
+// describeSearch renders a search on one line, e.g. `"one piece" · action · rating ↓`
+func describeSearch(search models.SearchFilters) string {
+	var parts []string
+	if search.Query != "" {
+		parts = append(parts, fmt.Sprintf("%q", search.Query))
+	}
+	if search.Genre != "" {
+		parts = append(parts, search.Genre)
+	}
+	if search.Sort != "" {
+		arrow := "↓"
+		if search.Order == models.SortAsc {
+			arrow = "↑"
+		}
+		parts = append(parts, search.Sort+" "+arrow)
+	}
+	return strings.Join(parts, " · ")
+}
+
+// renderSuggestions renders the dropdown under the input, empty when hidden
+func (m SearchModel) renderSuggestions() string {
+	if !m.showSuggestions() {
+		return ""
+	}
+	var rows []string
+	for i, s := range m.suggestions {
+		if i == 0 {
+			rows = append(rows, "  "+m.theme.Primary.Render("▸ "+s.Title)+"  "+m.theme.DimText.Render("[Tab] complete"))
+			continue
+		}
+		rows = append(rows, "    "+m.theme.Description.Render(s.Title))
+	}
+	return strings.Join(rows, "\n") + "\n"
 }
 
 func (m SearchModel) renderResults() string {
 	// Results header
+	// Previous results stay visible while the next query is searching
 	var headerText string
-	if m.loading {
-		headerText = fmt.Sprintf("SEARCHING... %s", m.spinner.View())
-	} else if len(m.results) > 0 {
+	if len(m.results) > 0 {
 		headerText = fmt.Sprintf("RESULTS (%d found)", m.totalResults)
-	} else if m.input.Value() != "" {
+	} else if m.loading {
+		headerText = "SEARCHING..."
+	} else if m.lastError != nil {
+		headerText = "SEARCH FAILED"
+	} else if !m.currentSearch().IsEmpty() {
 		headerText = "NO RESULTS"
 	} else {
 		headerText = "TYPE TO SEARCH"
@@ -286,7 +791,10 @@ func (m SearchModel) renderResults() string {
 
 	// No results state
 	if len(m.results) == 0 {
-		if m.input.Value() == "" {
+		if m.lastError != nil && !m.loading {
+			return header + "\n" + m.theme.ErrorText.Render("⚠ "+m.lastError.Error())
+		}
+		if len(m.last.Query) < searchMinLength && m.last.Genre == "" {
 			hint := m.theme.DimText.Render("Enter at least 2 characters to search...")
 			return header + "\n" + hint
 		} else if !m.loading {
@@ -300,22 +808,19 @@ func (m SearchModel) renderResults() string {
 	listStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
 		BorderForeground(styles.ColorDim).
-		Width(m.width - 10).
-		MaxHeight(m.height - 14)
+		Width(m.width - 10)
 
 	var rows []string
-	maxVisible := min(10, len(m.results))
-
-	for i := 0; i < maxVisible; i++ {
+	start, end := m.scroll.window(m.selectedIndex, len(m.results), m.listRows())
+	for i := start; i < end; i++ {
 		manga := m.results[i]
 		row := m.renderResultRow(manga, i == m.selectedIndex)
 		rows = append(rows, row)
 	}
 
-	// Show truncation indicator
-	if len(m.results) > maxVisible {
-		more := m.theme.DimText.Render(fmt.Sprintf("  ... and %d more", len(m.results)-maxVisible))
-		rows = append(rows, more)
+	// Scroll indicator
+	if status := scrollStatus(start, end, len(m.results)); status != "" {
+		rows = append(rows, m.theme.DimText.Render("  "+status))
 	}
 
 	list := lipgloss.JoinVertical(lipgloss.Left, rows...)
@@ -372,7 +877,12 @@ func (m SearchModel) renderHelp() string {
 	helpItems := []string{
 		m.theme.Key.Render("[↑↓]") + " " + m.theme.DimText.Render("Navigate"),
 		m.theme.Key.Render("[Enter]") + " " + m.theme.DimText.Render("View Details"),
-		m.theme.Key.Render("[Esc]") + " " + m.theme.DimText.Render("Clear"),
+		m.theme.Key.Render("[Tab]") + " " + m.theme.DimText.Render("Complete"),
+		m.theme.Key.Render("[^S]") + " " + m.theme.DimText.Render("Save"),
+		m.theme.Key.Render("[^R]") + " " + m.theme.DimText.Render("Reverse"),
+	}
+	if m.picking() {
+		helpItems = append(helpItems, m.theme.Key.Render("[^D]")+" "+m.theme.DimText.Render("Delete"))
 	}
 	return "\n" + lipgloss.JoinHorizontal(lipgloss.Center, helpItems...)
 }
@@ -389,14 +899,23 @@ func (m SearchModel) GetSelectedManga() *models.Manga {
 	return nil
 }
 
-// Focus focuses the search input
+// Focus focuses the search input and refreshes the history, which
+// depends on who is logged in
 func (m *SearchModel) Focus() tea.Cmd {
-	return m.input.Focus()
+	return tea.Batch(m.input.Focus(), m.loadSearches())
 }
 
 // Blur removes focus from search input
 func (m *SearchModel) Blur() {
 	m.input.Blur()
+	m.nameInput.Blur()
+	m.naming = false
+}
+
+// SetTheme switches the view to a new theme
+func (m *SearchModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
 }
 
 // SetWidth sets the view width
@@ -408,6 +927,7 @@ func (m *SearchModel) SetWidth(w int) {
 // SetHeight sets the view height
 func (m *SearchModel) SetHeight(h int) {
 	m.height = h
+	m.scroll.follow(m.selectedIndex, len(m.results), m.listRows())
 }
 
 // ClearResults clears the search results
@@ -420,5 +940,5 @@ func (m *SearchModel) ClearResults() {
 
 // IsInputFocused reports whether the search input is focused.
 func (m SearchModel) IsInputFocused() bool {
-	return m.input.Focused()
+	return m.input.Focused() || m.nameInput.Focused()
 }
@@ -0,0 +1,879 @@
+// Package views - Settings View
+// App settings và các thao tác với dữ liệu tài khoản
+// Layout:
+//
+//	┌────────────────────────────────────────────────────────┐
+//	│  ⚙ SETTINGS                                            │
+//	│                                                        │
+//	│  DATA                                                  │
+//	│  > Import Library     MyAnimeList XML/JSON export      │
+//	│    Export Data (CSV)  Library, history & lists as zip  │
+//	│  ACCOUNT                                               │
+//	│    Change Password    Logs out your other sessions     │
+//	│    Email Digest       Off - no emails (Enter: toggle)  │
+//	│    Delete Account     Erase the account and its data   │
+//	│  APPEARANCE                                            │
+//	│    Theme              dracula (Enter: next theme)      │
+//	│  STARTUP                                               │
+//	│    Home View          dashboard (Enter: next view)     │
+//	│  KEYBINDINGS                                           │
+//	│    Search             s / /                            │
+//	│    Library            ctrl+l (custom, ⌫ reset)         │
+//	│                                                        │
+//	│  ┌─────────────────────────────────────────────────┐   │
+//	│  │ ~/Downloads/animelist.xml.gz_                   │   │
+//	│  └─────────────────────────────────────────────────┘   │
+//	│                                                        │
+//	│  LAST IMPORT                                           │
+//	│  120 rows: 100 added, 5 updated, 12 queued, 3 failed   │
+//	│                                                        │
+//	│  [↑↓] Navigate  [Enter] Select                         │
+//	└────────────────────────────────────────────────────────┘
+package views
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"mangahub/internal/tui/api"
+	"mangahub/internal/tui/styles"
+	"mangahub/pkg/models"
+)
+
+// Settings action IDs
+const (
+	SettingImportLibrary  = "import_library"
+	SettingExportData     = "export_data"
+	SettingChangePassword = "change_password"
+	SettingTheme          = "theme"
+	SettingShowSpoilers   = "show_spoilers"
+	SettingHomeView       = "home_view"
+	SettingAutoConnect    = "auto_connect"
+	SettingEmailDigest    = "email_digest"
+	SettingDeleteAccount  = "delete_account"
+)
+
+// deleteConfirmWord must be typed after the password to delete the account
+const deleteConfirmWord = "DELETE"
+
+// settingsItem is one selectable action
+type settingsItem struct {
+	id    string
+	group string
+	label string
+	desc  string
+}
+
+var settingsItems = []settingsItem{
+	{id: SettingImportLibrary, group: "DATA", label: "Import Library", desc: "MyAnimeList XML/JSON export"},
+	{id: SettingExportData, group: "DATA", label: "Export Data (CSV)", desc: "Library, history & lists as zip"},
+	{id: SettingChangePassword, group: "ACCOUNT", label: "Change Password", desc: "Logs out your other sessions"},
+	{id: SettingEmailDigest, group: "ACCOUNT", label: "Email Digest", desc: "Daily email of new chapters"},
+	{id: SettingDeleteAccount, group: "ACCOUNT", label: "Delete Account", desc: "Erase the account and all its data"},
+	{id: SettingTheme, group: "APPEARANCE", label: "Theme", desc: "Dracula, Dark, Light or Nord"},
+	{id: SettingShowSpoilers, group: "APPEARANCE", label: "Show Spoilers", desc: "Show spoiler reviews and comments"},
+	{id: SettingHomeView, group: "STARTUP", label: "Home View", desc: "View opened after login"},
+	{id: SettingAutoConnect, group: "STARTUP", label: "Live Notifications", desc: "Connect on login"},
+}
+
+// =====================================
+// SETTINGS MODEL
+// =====================================
+
+// SettingsModel holds the settings view state
+type SettingsModel struct {
+	width  int
+	height int
+	theme  *styles.Theme
+
+	selected int
+
+	showSpoilers bool // show spoiler reviews and comments (user preference)
+
+	// Startup preferences, applied on the next login
+	homeView    string
+	autoConnect bool
+
+	emailDigest bool // daily new-chapter email (opt-in)
+
+	// Keybinding editor: rows after settingsItems; capturing is the binding
+	// waiting for its next key, -1 when none
+	bindings      []Shortcut
+	capturing     int
+	bindingStatus string
+
+	// Library import
+	pathInput  textinput.Model
+	spinner    spinner.Model
+	importing  bool
+	lastImport *LibraryImportedMsg
+
+	// Password change: current, new, confirm
+	passwordInputs [3]textinput.Model
+	passwordFocus  int
+	changingPass   bool
+	passwordStatus string
+	passwordFailed bool
+
+	// Account deletion: the password, then deleteConfirmWord
+	deleteInputs [2]textinput.Model
+	deleteFocus  int
+	deleting     bool
+	deleteStatus string // last failure
+
+	client *api.Client
+}
+
+// =====================================
+// MESSAGES
+// =====================================
+
+// LibraryImportedMsg reports the result of a library import
+type LibraryImportedMsg struct {
+	Path   string
+	Result *models.BulkImportLibraryResponse
+	Error  error
+}
+
+// ThemeChangedMsg asks the app to switch to the named theme
+type ThemeChangedMsg struct {
+	Name string
+}
+
+// ThemeSavedMsg reports whether the theme preference was saved
+type ThemeSavedMsg struct {
+	Name  string
+	Error error
+}
+
+// SpoilersChangedMsg asks the app to expand or collapse spoiler reviews
+type SpoilersChangedMsg struct {
+	Show bool
+}
+
+// SpoilersSavedMsg reports whether the spoiler preference was saved
+type SpoilersSavedMsg struct {
+	Show  bool
+	Error error
+}
+
+// PreferenceSavedMsg reports whether a toggled preference (home view,
+// notifications auto-connect, email digest) was saved
+type PreferenceSavedMsg struct {
+	Label string
+	Error error
+}
+
+// PasswordChangedMsg reports the result of a password change
+type PasswordChangedMsg struct {
+	Error error
+}
+
+// AccountDeletedMsg reports the result of an account deletion; on success the
+// client is already logged out
+type AccountDeletedMsg struct {
+	Error error
+}
+
+// =====================================
+// CONSTRUCTOR
+// =====================================
+
+// NewSettings creates a new settings model
+func NewSettings() SettingsModel {
+	ti := textinput.New()
+	ti.Placeholder = "Path to export file (e.g. ~/Downloads/animelist.xml.gz)"
+	ti.CharLimit = 512
+	ti.Width = 50
+	ti.PromptStyle = styles.DefaultTheme.Primary
+	ti.TextStyle = styles.DefaultTheme.Description
+	ti.PlaceholderStyle = styles.DefaultTheme.DimText
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = styles.DefaultTheme.Spinner
+
+	var passwordInputs [3]textinput.Model
+	for i, placeholder := range []string{"Current password", "New password", "Confirm new password"} {
+		pi := textinput.New()
+		pi.Placeholder = placeholder
+		pi.EchoMode = textinput.EchoPassword
+		pi.EchoCharacter = '•'
+		pi.CharLimit = 100
+		pi.Width = 40
+		pi.PromptStyle = styles.DefaultTheme.Primary
+		pi.TextStyle = styles.DefaultTheme.Description
+		pi.PlaceholderStyle = styles.DefaultTheme.DimText
+		passwordInputs[i] = pi
+	}
+
+	var deleteInputs [2]textinput.Model
+	for i, placeholder := range []string{"Password", "Type " + deleteConfirmWord + " to confirm"} {
+		di := textinput.New()
+		di.Placeholder = placeholder
+		di.CharLimit = 100
+		di.Width = 40
+		di.PromptStyle = styles.DefaultTheme.Primary
+		di.TextStyle = styles.DefaultTheme.Description
+		di.PlaceholderStyle = styles.DefaultTheme.DimText
+		deleteInputs[i] = di
+	}
+	deleteInputs[0].EchoMode = textinput.EchoPassword
+	deleteInputs[0].EchoCharacter = '•'
+
+	return SettingsModel{
+		theme:          styles.DefaultTheme,
+		pathInput:      ti,
+		passwordInputs: passwordInputs,
+		deleteInputs:   deleteInputs,
+		spinner:        s,
+		homeView:       models.HomeViewDashboard,
+		autoConnect:    true,
+		capturing:      -1,
+		client:         api.GetClient(),
+	}
+}
+
+// =====================================
+// BUBBLE TEA INTERFACE
+// =====================================
+
+// Init initializes the settings view
+func (m SettingsModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles messages
+func (m SettingsModel) Update(msg tea.Msg) (SettingsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+		m.pathInput.Width = msg.Width - 16
+
+	case tea.KeyMsg:
+		if m.pathInput.Focused() {
+			if msg.String() == "enter" {
+				path := strings.TrimSpace(m.pathInput.Value())
+				if path == "" || m.importing {
+					return m, nil
+				}
+				m.pathInput.Blur()
+				m.importing = true
+				return m, tea.Batch(m.spinner.Tick, m.importLibrary(path))
+			}
+			var cmd tea.Cmd
+			m.pathInput, cmd = m.pathInput.Update(msg)
+			return m, cmd
+		}
+		if m.passwordFormFocused() {
+			return m.updatePasswordForm(msg)
+		}
+		if m.deleteFormFocused() {
+			return m.updateDeleteForm(msg)
+		}
+		if m.IsCapturingKey() {
+			return m.updateKeyCapture(msg)
+		}
+
+		switch msg.String() {
+		case "up", "k":
+			if m.selected > 0 {
+				m.selected--
+			}
+		case "down", "j":
+			if m.selected < len(settingsItems)+len(m.bindings)-1 {
+				m.selected++
+			}
+		case "backspace", "delete":
+			if i, ok := m.selectedBinding(); ok {
+				return m.resetBinding(i)
+			}
+		case "enter":
+			if i, ok := m.selectedBinding(); ok {
+				m.capturing = i
+				m.bindingStatus = ""
+				return m, nil
+			}
+			switch settingsItems[m.selected].id {
+			case SettingImportLibrary:
+				return m, m.FocusImport()
+			case SettingExportData:
+				// The app owns exports so they can finish in any view
+				return m, func() tea.Msg { return CommandSelectedMsg{CommandID: SettingExportData} }
+			case SettingChangePassword:
+				return m, m.focusPasswordForm()
+			case SettingDeleteAccount:
+				return m, m.focusDeleteForm()
+			case SettingTheme:
+				// Switch right away; saving happens in the background
+				next := nextThemeName(m.theme.Name)
+				return m, tea.Batch(
+					func() tea.Msg { return ThemeChangedMsg{Name: next} },
+					m.saveTheme(next),
+				)
+			case SettingShowSpoilers:
+				show := !m.showSpoilers
+				m.showSpoilers = show
+				return m, tea.Batch(
+					func() tea.Msg { return SpoilersChangedMsg{Show: show} },
+					m.saveShowSpoilers(show),
+				)
+			case SettingHomeView:
+				m.homeView = nextHomeView(m.homeView)
+				view := m.homeView
+				return m, m.savePreference("Home view", models.UpdatePreferencesRequest{DefaultView: &view})
+			case SettingAutoConnect:
+				m.autoConnect = !m.autoConnect
+				on := m.autoConnect
+				return m, m.savePreference("Live notifications", models.UpdatePreferencesRequest{AutoConnectNotifications: &on})
+			case SettingEmailDigest:
+				m.emailDigest = !m.emailDigest
+				on := m.emailDigest
+				return m, m.savePreference("Email digest", models.UpdatePreferencesRequest{EmailNotifications: &on})
+			}
+		}
+
+	case PasswordChangedMsg:
+		m.changingPass = false
+		m.passwordFailed = msg.Error != nil
+		if msg.Error != nil {
+			m.passwordStatus = "⚠ " + msg.Error.Error()
+		} else {
+			m.passwordStatus = "✓ Password changed. Other sessions were logged out."
+			m.resetPasswordForm()
+		}
+
+	case AccountDeletedMsg:
+		m.deleting = false
+		if msg.Error != nil {
+			m.deleteStatus = "⚠ " + msg.Error.Error()
+		} else {
+			m.resetDeleteForm()
+		}
+
+	case LibraryImportedMsg:
+		m.importing = false
+		m.lastImport = &msg
+
+	case spinner.TickMsg:
+		if m.importing || m.changingPass || m.deleting {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+	}
+
+	return m, nil
+}
+
+// saveTheme stores the theme as the user's preference (skipped when logged out)
+func (m SettingsModel) saveTheme(name string) tea.Cmd {
+	if !m.client.IsAuthenticated() {
+		return nil
+	}
+	return func() tea.Msg {
+		_, err := m.client.UpdatePreferences(context.Background(), models.UpdatePreferencesRequest{Theme: &name})
+		return ThemeSavedMsg{Name: name, Error: err}
+	}
+}
+
+// saveShowSpoilers stores the spoiler preference (skipped when logged out)
+func (m SettingsModel) saveShowSpoilers(show bool) tea.Cmd {
+	if !m.client.IsAuthenticated() {
+		return nil
+	}
+	return func() tea.Msg {
+		_, err := m.client.UpdatePreferences(context.Background(), models.UpdatePreferencesRequest{ShowSpoilers: &show})
+		return SpoilersSavedMsg{Show: show, Error: err}
+	}
+}
+
+// savePreference stores a preference change (skipped when logged out)
+func (m SettingsModel) savePreference(label string, req models.UpdatePreferencesRequest) tea.Cmd {
+	if !m.client.IsAuthenticated() {
+		return nil
+	}
+	return func() tea.Msg {
+		_, err := m.client.UpdatePreferences(context.Background(), req)
+		return PreferenceSavedMsg{Label: label, Error: err}
+	}
+}
+
+// nextHomeView returns the home view after current in the cycle
+func nextHomeView(current string) string {
+	for i, name := range models.HomeViews {
+		if name == current {
+			return models.HomeViews[(i+1)%len(models.HomeViews)]
+		}
+	}
+	return models.HomeViews[0]
+}
+
+// nextThemeName returns the theme after current in the cycle
+func nextThemeName(current string) string {
+	names := styles.ThemeNames()
+	for i, name := range names {
+		if name == current {
+			return names[(i+1)%len(names)]
+		}
+	}
+	return names[0]
+}
+
+// selectedBinding returns the index of the selected keybinding row, if one is selected
+func (m SettingsModel) selectedBinding() (int, bool) {
+	i := m.selected - len(settingsItems)
+	return i, i >= 0 && i < len(m.bindings)
+}
+
+// FocusImport selects the import action and focuses the path input
+func (m *SettingsModel) FocusImport() tea.Cmd {
+	m.selected = 0
+	return m.pathInput.Focus()
+}
+
+// IsInputFocused reports whether the path input, a password field or an
+// account deletion field is focused, or a key is being captured
+func (m SettingsModel) IsInputFocused() bool {
+	return m.pathInput.Focused() || m.passwordFormFocused() || m.deleteFormFocused() || m.IsCapturingKey()
+}
+
+// passwordFormFocused reports whether any password field is focused
+func (m SettingsModel) passwordFormFocused() bool {
+	for _, in := range m.passwordInputs {
+		if in.Focused() {
+			return true
+		}
+	}
+	return false
+}
+
+// focusPasswordForm clears the form and focuses the current password field
+func (m *SettingsModel) focusPasswordForm() tea.Cmd {
+	m.resetPasswordForm()
+	m.passwordStatus = ""
+	return m.passwordInputs[0].Focus()
+}
+
+// resetPasswordForm empties and blurs every password field
+func (m *SettingsModel) resetPasswordForm() {
+	for i := range m.passwordInputs {
+		m.passwordInputs[i].Reset()
+		m.passwordInputs[i].Blur()
+	}
+	m.passwordFocus = 0
+}
+
+// updatePasswordForm moves between the password fields and submits on the last one
+func (m SettingsModel) updatePasswordForm(msg tea.KeyMsg) (SettingsModel, tea.Cmd) {
+	switch msg.String() {
+	case "tab", "down":
+		if m.passwordFocus < len(m.passwordInputs)-1 {
+			m.passwordInputs[m.passwordFocus].Blur()
+			m.passwordFocus++
+			return m, m.passwordInputs[m.passwordFocus].Focus()
+		}
+		return m, nil
+	case "shift+tab", "up":
+		if m.passwordFocus > 0 {
+			m.passwordInputs[m.passwordFocus].Blur()
+			m.passwordFocus--
+			return m, m.passwordInputs[m.passwordFocus].Focus()
+		}
+		return m, nil
+	case "enter":
+		if m.passwordFocus < len(m.passwordInputs)-1 {
+			m.passwordInputs[m.passwordFocus].Blur()
+			m.passwordFocus++
+			return m, m.passwordInputs[m.passwordFocus].Focus()
+		}
+		return m.submitPassword()
+	}
+
+	var cmd tea.Cmd
+	m.passwordInputs[m.passwordFocus], cmd = m.passwordInputs[m.passwordFocus].Update(msg)
+	return m, cmd
+}
+
+// submitPassword checks the form locally before sending it to the server
+func (m SettingsModel) submitPassword() (SettingsModel, tea.Cmd) {
+	if m.changingPass {
+		return m, nil
+	}
+	current := m.passwordInputs[0].Value()
+	newPassword := m.passwordInputs[1].Value()
+
+	m.passwordFailed = true
+	switch {
+	case current == "" || newPassword == "":
+		m.passwordStatus = "⚠ Fill in the current and new password"
+		return m, nil
+	case newPassword != m.passwordInputs[2].Value():
+		m.passwordStatus = "⚠ New passwords do not match"
+		return m, nil
+	}
+
+	m.passwordStatus = ""
+	m.changingPass = true
+	m.passwordInputs[m.passwordFocus].Blur()
+	client := m.client
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		return PasswordChangedMsg{Error: client.ChangePassword(context.Background(), current, newPassword)}
+	})
+}
+
+// deleteFormFocused reports whether an account deletion field is focused
+func (m SettingsModel) deleteFormFocused() bool {
+	return m.deleteInputs[0].Focused() || m.deleteInputs[1].Focused()
+}
+
+// focusDeleteForm clears the form and asks for the password first
+func (m *SettingsModel) focusDeleteForm() tea.Cmd {
+	m.resetDeleteForm()
+	return m.deleteInputs[0].Focus()
+}
+
+// resetDeleteForm empties and blurs both deletion fields
+func (m *SettingsModel) resetDeleteForm() {
+	for i := range m.deleteInputs {
+		m.deleteInputs[i].Reset()
+		m.deleteInputs[i].Blur()
+	}
+	m.deleteFocus = 0
+	m.deleteStatus = ""
+}
+
+// updateDeleteForm asks for the password, then for deleteConfirmWord; only
+// the second Enter sends the request
+func (m SettingsModel) updateDeleteForm(msg tea.KeyMsg) (SettingsModel, tea.Cmd) {
+	if msg.String() != "enter" {
+		var cmd tea.Cmd
+		m.deleteInputs[m.deleteFocus], cmd = m.deleteInputs[m.deleteFocus].Update(msg)
+		return m, cmd
+	}
+
+	if m.deleteFocus == 0 {
+		if m.deleteInputs[0].Value() == "" {
+			m.deleteStatus = "⚠ Enter your password"
+			return m, nil
+		}
+		m.deleteStatus = ""
+		m.deleteInputs[0].Blur()
+		m.deleteFocus = 1
+		return m, m.deleteInputs[1].Focus()
+	}
+
+	if m.deleting {
+		return m, nil
+	}
+	if strings.TrimSpace(m.deleteInputs[1].Value()) != deleteConfirmWord {
+		m.deleteStatus = "⚠ Type " + deleteConfirmWord + " to confirm, or Esc to keep your account"
+		return m, nil
+	}
+
+	m.deleteStatus = ""
+	m.deleting = true
+	m.deleteInputs[1].Blur()
+	password := m.deleteInputs[0].Value()
+	client := m.client
+	return m, tea.Batch(m.spinner.Tick, func() tea.Msg {
+		return AccountDeletedMsg{Error: client.DeleteAccount(context.Background(), password)}
+	})
+}
+
+// importLibrary parses the export file and sends it in chunks the API accepts
+func (m SettingsModel) importLibrary(path string) tea.Cmd {
+	return func() tea.Msg {
+		if strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+
+		entries, err := api.ParseLibraryFile(path)
+		if err != nil {
+			return LibraryImportedMsg{Path: path, Error: err}
+		}
+		if len(entries) == 0 {
+			return LibraryImportedMsg{Path: path, Error: errors.New("no manga found in file")}
+		}
+
+		total := &models.BulkImportLibraryResponse{}
+		for start := 0; start < len(entries); start += models.MaxBulkImportEntries {
+			end := min(start+models.MaxBulkImportEntries, len(entries))
+			result, err := m.client.ImportLibrary(context.Background(), entries[start:end])
+			if err != nil {
+				return LibraryImportedMsg{Path: path, Result: total, Error: err}
+			}
+			for _, row := range result.Rows {
+				row.Index += start
+				total.Rows = append(total.Rows, row)
+			}
+			total.Stats.Total += result.Stats.Total
+			total.Stats.Inserted += result.Stats.Inserted
+			total.Stats.Updated += result.Stats.Updated
+			total.Stats.Queued += result.Stats.Queued
+			total.Stats.Failed += result.Stats.Failed
+		}
+		return LibraryImportedMsg{Path: path, Result: total}
+	}
+}
+
+// ImportSummary formats import stats for toasts and the settings view
+func ImportSummary(stats models.BulkImportStats) string {
+	return fmt.Sprintf("%d rows: %d added, %d updated, %d queued for fetch, %d failed",
+		stats.Total, stats.Inserted, stats.Updated, stats.Queued, stats.Failed)
+}
+
+// =====================================
+// VIEW
+// =====================================
+
+// View renders the settings view
+func (m SettingsModel) View() string {
+	var sections []string
+
+	sections = append(sections, m.theme.PanelHeader.Render("⚙ SETTINGS"))
+	sections = append(sections, m.renderItems())
+
+	if m.pathInput.Focused() || m.pathInput.Value() != "" {
+		inputStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(styles.ColorPrimary).
+			Padding(0, 1).
+			Width(m.width - 10)
+		sections = append(sections, inputStyle.Render(m.pathInput.View()))
+	}
+
+	if status := m.renderPasswordForm(); status != "" {
+		sections = append(sections, status)
+	}
+
+	if status := m.renderDeleteForm(); status != "" {
+		sections = append(sections, status)
+	}
+
+	if status := m.renderImportStatus(); status != "" {
+		sections = append(sections, status)
+	}
+
+	sections = append(sections, m.renderHelp())
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return m.theme.Container.Width(m.width - 4).Render(content)
+}
+
+// =====================================
+// RENDERERS
+// =====================================
+
+func (m SettingsModel) renderItems() string {
+	var b strings.Builder
+	group := ""
+	for i, item := range settingsItems {
+		if item.group != group {
+			group = item.group
+			b.WriteString(m.theme.DimText.Render(group) + "\n")
+		}
+		label := fmt.Sprintf("%-20s", item.label)
+		desc := item.desc
+		if item.id == SettingTheme {
+			desc = m.theme.Name + " (Enter: next theme)"
+		}
+		if item.id == SettingShowSpoilers {
+			desc = "Off - spoiler reviews collapsed (Enter: toggle)"
+			if m.showSpoilers {
+				desc = "On - spoiler reviews expanded (Enter: toggle)"
+			}
+		}
+		if item.id == SettingEmailDigest {
+			desc = "Off - no emails (Enter: toggle)"
+			if m.emailDigest {
+				desc = "On - daily new chapters, verified email only (Enter: toggle)"
+			}
+		}
+		if item.id == SettingHomeView {
+			desc = m.homeView + " (Enter: next view)"
+		}
+		if item.id == SettingAutoConnect {
+			desc = "Off - no live notifications on login (Enter: toggle)"
+			if m.autoConnect {
+				desc = "On - connect on login (Enter: toggle)"
+			}
+		}
+		if i == m.selected {
+			b.WriteString(m.theme.Primary.Render("> "+label) + " " + m.theme.Description.Render(desc))
+		} else {
+			b.WriteString("  " + label + " " + m.theme.DimText.Render(desc))
+		}
+		b.WriteString("\n")
+	}
+	b.WriteString(m.renderBindings())
+	return b.String()
+}
+
+// renderBindings lists the rebindable shortcuts below the settings items
+func (m SettingsModel) renderBindings() string {
+	if len(m.bindings) == 0 {
+		return ""
+	}
+	var b strings.Builder
+	b.WriteString(m.theme.DimText.Render("KEYBINDINGS") + "\n")
+	for i, kb := range m.bindings {
+		label := fmt.Sprintf("%-20s", kb.Label)
+		desc := strings.Join(kb.Keys, " / ")
+		if !kb.isDefault() {
+			desc += " (custom, ⌫ reset)"
+		}
+		if i == m.capturing {
+			desc = "Press a key... (Esc: cancel)"
+		}
+		if len(settingsItems)+i == m.selected {
+			b.WriteString(m.theme.Primary.Render("> "+label) + " " + m.theme.Description.Render(desc))
+		} else {
+			b.WriteString("  " + label + " " + m.theme.DimText.Render(desc))
+		}
+		b.WriteString("\n")
+	}
+	if m.bindingStatus != "" {
+		style := m.theme.SuccessText
+		if strings.HasPrefix(m.bindingStatus, "⚠") {
+			style = m.theme.ErrorText
+		}
+		b.WriteString(style.Render(m.bindingStatus) + "\n")
+	}
+	return b.String()
+}
+
+func (m SettingsModel) renderPasswordForm() string {
+	var lines []string
+	if m.passwordFormFocused() {
+		lines = append(lines, m.theme.PanelHeader.Render("CHANGE PASSWORD"))
+		for _, in := range m.passwordInputs {
+			lines = append(lines, in.View())
+		}
+	}
+	if m.changingPass {
+		lines = append(lines, m.theme.DimText.Render("Changing password... "+m.spinner.View()))
+	} else if m.passwordStatus != "" {
+		if m.passwordFailed {
+			lines = append(lines, m.theme.ErrorText.Render(m.passwordStatus))
+		} else {
+			lines = append(lines, m.theme.SuccessText.Render(m.passwordStatus))
+		}
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (m SettingsModel) renderDeleteForm() string {
+	var lines []string
+	if m.deleteFormFocused() || m.deleting {
+		lines = append(lines,
+			m.theme.PanelHeader.Render("DELETE ACCOUNT"),
+			m.theme.ErrorText.Render("This permanently deletes your account, library, ratings, comments,"),
+			m.theme.ErrorText.Render("history and messages. It cannot be undone."),
+		)
+		for _, in := range m.deleteInputs {
+			lines = append(lines, in.View())
+		}
+	}
+	if m.deleting {
+		lines = append(lines, m.theme.DimText.Render("Deleting account... "+m.spinner.View()))
+	} else if m.deleteStatus != "" {
+		lines = append(lines, m.theme.ErrorText.Render(m.deleteStatus))
+	}
+	if len(lines) == 0 {
+		return ""
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (m SettingsModel) renderImportStatus() string {
+	if m.importing {
+		return m.theme.PanelHeader.Render("IMPORTING... "+m.spinner.View()) + "\n"
+	}
+	if m.lastImport == nil {
+		return ""
+	}
+
+	var lines []string
+	lines = append(lines, m.theme.PanelHeader.Render("LAST IMPORT"))
+	if m.lastImport.Result != nil && m.lastImport.Result.Stats.Total > 0 {
+		lines = append(lines, m.theme.Description.Render(ImportSummary(m.lastImport.Result.Stats)))
+		if m.lastImport.Result.Stats.Queued > 0 {
+			lines = append(lines, m.theme.DimText.Render("Queued manga are added to your library once they are fetched."))
+		}
+	}
+	if m.lastImport.Error != nil {
+		lines = append(lines, m.theme.ErrorText.Render("⚠ "+m.lastImport.Error.Error()))
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+func (m SettingsModel) renderHelp() string {
+	if m.pathInput.Focused() {
+		return styles.RenderKeyHint("Enter", "import") + "  " + styles.RenderKeyHint("Esc", "back")
+	}
+	if m.passwordFormFocused() {
+		return styles.RenderKeyHint("Tab", "next field") + "  " + styles.RenderKeyHint("Enter", "submit") + "  " + styles.RenderKeyHint("Esc", "back")
+	}
+	if m.deleteFormFocused() {
+		action := "continue"
+		if m.deleteFocus == 1 {
+			action = "delete forever"
+		}
+		return styles.RenderKeyHint("Enter", action) + "  " + styles.RenderKeyHint("Esc", "keep account")
+	}
+	if m.IsCapturingKey() {
+		return styles.RenderKeyHint("any key", "bind") + "  " + styles.RenderKeyHint("Esc", "cancel")
+	}
+	if _, ok := m.selectedBinding(); ok {
+		return styles.RenderKeyHint("↑↓", "navigate") + "  " + styles.RenderKeyHint("Enter", "rebind") + "  " + styles.RenderKeyHint("⌫", "reset to default")
+	}
+	return styles.RenderKeyHint("↑↓", "navigate") + "  " + styles.RenderKeyHint("Enter", "select")
+}
+
+// SetShowSpoilers reflects the loaded spoiler preference
+func (m *SettingsModel) SetShowSpoilers(show bool) {
+	m.showSpoilers = show
+}
+
+// SetStartup reflects the loaded startup preferences; an unknown home view
+// shows as the dashboard, which is what the app opens for it
+func (m *SettingsModel) SetStartup(homeView string, autoConnect bool) {
+	if !slices.Contains(models.HomeViews, homeView) {
+		homeView = models.HomeViewDashboard
+	}
+	m.homeView = homeView
+	m.autoConnect = autoConnect
+}
+
+// SetEmailDigest reflects the loaded email digest preference
+func (m *SettingsModel) SetEmailDigest(on bool) {
+	m.emailDigest = on
+}
+
+// SetTheme switches the view to a new theme
+func (m *SettingsModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
+	for _, in := range []*textinput.Model{&m.pathInput, &m.passwordInputs[0], &m.passwordInputs[1], &m.passwordInputs[2], &m.deleteInputs[0], &m.deleteInputs[1]} {
+		in.PromptStyle = t.Primary
+		in.TextStyle = t.Description
+		in.PlaceholderStyle = t.DimText
+	}
+}
@@ -0,0 +1,557 @@
+// Package views - Statistics View
+// Thống kê đọc, rank theo tổng chapter, reading goals và badges
+// Layout:
+//
+//	┌────────────────────────────────────────────────────────┐
+//	│  📊 STATISTICS                                         │
+//	│                                                        │
+//	│  📚 412 chapters  ⏱ 96h  📖 23 manga  🔥 5 days        │
+//	│                                                        │
+//	│  🥈 Silver   [██████░░░░░░] 78%  88 to 🥇 Gold          │
+//	│  🎯 2026     [███░░░░░░░░░] 24%  120/500 · 240 days    │
+//	│  🎯 October  [████████░░░░] 66%  20/30 · 15 days       │
+//	│                                                        │
+//	│  🏅 3/12 badges                                        │
+//	│  📖 First Steps  🔥 Week Streak  🔒 Bookworm 88/100 ... │
+//	│                                                        │
+//	│  🗓 86 chapters on 31 days, last 24 weeks               │
+//	│      Oct     Nov      Dec     Jan      Feb     Mar      │
+//	│  Mon ■ ■ ■ ■ ■ ■ ■ ■ ■ ■ ■ ■ ■ ■ ■ ■ ■ ■ ■ ■ ■ ■ ■ ■    │
+//	│  ...                                Less ■ ■ ■ ■ ■ More │
+//	│                                                        │
+//	│  [g] Yearly goal  [m] Monthly goal  [r] Refresh        │
+//	└────────────────────────────────────────────────────────┘
+package views
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"mangahub/internal/tui/api"
+	"mangahub/internal/tui/styles"
+	"mangahub/pkg/models"
+)
+
+// =====================================
+// STATS MODEL
+// =====================================
+
+// StatsModel holds the statistics view state
+type StatsModel struct {
+	width  int
+	height int
+	theme  *styles.Theme
+
+	// Data
+	overview *models.StatsOverview
+	goals    []models.GoalProgress
+	heatmap  []models.HeatmapDay // oldest first, ending on the server's today
+	badges   []models.Achievement
+
+	// Goal target input, for goalPeriod
+	goalInput  textinput.Model
+	goalPeriod string
+
+	// UI state
+	loading   bool
+	message   string
+	lastError error
+	spinner   spinner.Model
+
+	client *api.Client
+}
+
+// =====================================
+// MESSAGES
+// =====================================
+
+// statsLoadedMsg carries the overview, current goals, reading heatmap and badges
+type statsLoadedMsg struct {
+	Overview *models.StatsOverview
+	Goals    []models.GoalProgress
+	Heatmap  []models.HeatmapDay
+	Badges   []models.Achievement
+	Error    error
+}
+
+// goalSavedMsg reports a goal update; stats are reloaded afterwards
+type goalSavedMsg struct {
+	Goal  *models.GoalProgress
+	Error error
+}
+
+// =====================================
+// CONSTRUCTOR
+// =====================================
+
+// NewStats creates a new statistics model
+func NewStats() StatsModel {
+	ti := textinput.New()
+	ti.Placeholder = "Chapters, e.g. 500"
+	ti.CharLimit = 6
+	ti.Width = 20
+	ti.PromptStyle = styles.DefaultTheme.Primary
+	ti.TextStyle = styles.DefaultTheme.Description
+	ti.PlaceholderStyle = styles.DefaultTheme.DimText
+
+	s := spinner.New()
+	s.Spinner = spinner.Dot
+	s.Style = styles.DefaultTheme.Spinner
+
+	return StatsModel{
+		theme:     styles.DefaultTheme,
+		goalInput: ti,
+		spinner:   s,
+		client:    api.GetClient(),
+		loading:   true,
+	}
+}
+
+// =====================================
+// BUBBLE TEA INTERFACE
+// =====================================
+
+// Init loads the overview and goals
+func (m StatsModel) Init() tea.Cmd {
+	return tea.Batch(m.spinner.Tick, m.loadStats)
+}
+
+// Update handles messages
+func (m StatsModel) Update(msg tea.Msg) (StatsModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.width = msg.Width
+		m.height = msg.Height
+
+	case tea.KeyMsg:
+		if m.goalInput.Focused() {
+			return m.updateGoalInput(msg)
+		}
+
+		switch msg.String() {
+		case "g", "m":
+			m.goalPeriod = models.GoalPeriodYearly
+			if msg.String() == "m" {
+				m.goalPeriod = models.GoalPeriodMonthly
+			}
+			m.message = ""
+			m.goalInput.SetValue("")
+			if goal := m.goal(m.goalPeriod); goal != nil {
+				m.goalInput.SetValue(strconv.Itoa(goal.TargetChapters))
+			}
+			return m, m.goalInput.Focus()
+		case "r":
+			m.loading = true
+			return m, tea.Batch(m.spinner.Tick, m.loadStats)
+		}
+
+	case statsLoadedMsg:
+		m.loading = false
+		m.lastError = msg.Error
+		if msg.Error == nil {
+			m.overview = msg.Overview
+			m.goals = msg.Goals
+			m.heatmap = msg.Heatmap
+			m.badges = msg.Badges
+		}
+
+	case goalSavedMsg:
+		m.lastError = msg.Error
+		if msg.Error != nil {
+			return m, nil
+		}
+		m.message = fmt.Sprintf("Goal set: %d chapters %s", msg.Goal.TargetChapters, goalLabel(msg.Goal.ReadingGoal))
+		return m, m.loadStats
+
+	case spinner.TickMsg:
+		if m.loading {
+			var cmd tea.Cmd
+			m.spinner, cmd = m.spinner.Update(msg)
+			return m, cmd
+		}
+	}
+
+	return m, nil
+}
+
+// updateGoalInput handles typing a goal target
+func (m StatsModel) updateGoalInput(msg tea.KeyMsg) (StatsModel, tea.Cmd) {
+	if msg.String() != "enter" {
+		var cmd tea.Cmd
+		m.goalInput, cmd = m.goalInput.Update(msg)
+		return m, cmd
+	}
+
+	value := strings.TrimSpace(m.goalInput.Value())
+	m.goalInput.Blur()
+	if value == "" {
+		return m, nil
+	}
+	target, err := strconv.Atoi(value)
+	if err != nil || target < 1 {
+		m.lastError = fmt.Errorf("goal must be a positive number of chapters")
+		return m, nil
+	}
+
+	period := m.goalPeriod
+	return m, func() tea.Msg {
+		goal, err := m.client.SetGoal(context.Background(), period, target)
+		return goalSavedMsg{Goal: goal, Error: err}
+	}
+}
+
+// =====================================
+// COMMANDS
+// =====================================
+
+func (m StatsModel) loadStats() tea.Msg {
+	ctx := context.Background()
+	overview, err := m.client.GetStatsOverview(ctx)
+	if err != nil {
+		return statsLoadedMsg{Error: err}
+	}
+	goals, err := m.client.GetGoals(ctx)
+	if err != nil {
+		return statsLoadedMsg{Error: err}
+	}
+	// The widest grid fits in the longest range; narrower ones show its tail
+	heatmap, err := m.client.GetReadingHeatmap(ctx, models.MaxHeatmapDays)
+	if err != nil {
+		return statsLoadedMsg{Error: err}
+	}
+	// Badges are extra: a server without them still shows the rest
+	badges, _ := m.client.GetAchievements(ctx)
+	return statsLoadedMsg{Overview: overview, Goals: goals, Heatmap: heatmap, Badges: badges}
+}
+
+// =====================================
+// ACCESSORS
+// =====================================
+
+// goal returns the current goal for a period, nil if none is set
+func (m StatsModel) goal(period string) *models.GoalProgress {
+	for i := range m.goals {
+		if m.goals[i].Period == period {
+			return &m.goals[i]
+		}
+	}
+	return nil
+}
+
+// IsInputFocused reports whether the goal target input is focused
+func (m StatsModel) IsInputFocused() bool {
+	return m.goalInput.Focused()
+}
+
+// SetTheme switches the view to a new theme
+func (m *StatsModel) SetTheme(t *styles.Theme) {
+	m.theme = t
+	m.spinner.Style = t.Spinner
+}
+
+// SetWidth sets the view width
+func (m *StatsModel) SetWidth(w int) {
+	m.width = w
+}
+
+// SetHeight sets the view height
+func (m *StatsModel) SetHeight(h int) {
+	m.height = h
+}
+
+// =====================================
+// VIEW
+// =====================================
+
+// View renders the statistics view
+func (m StatsModel) View() string {
+	var sections []string
+
+	sections = append(sections, m.theme.PanelHeader.Render("📊 STATISTICS"))
+
+	if m.loading && m.overview == nil {
+		sections = append(sections, m.spinner.View()+" Loading statistics...")
+	} else if m.overview != nil {
+		sections = append(sections,
+			m.renderOverview(),
+			"",
+			m.renderRank(),
+			m.renderGoal(models.GoalPeriodYearly),
+			m.renderGoal(models.GoalPeriodMonthly),
+		)
+		if badges := m.renderBadges(); badges != "" {
+			sections = append(sections, "", badges)
+		}
+		if heatmap := m.renderHeatmap(); heatmap != "" {
+			sections = append(sections, "", heatmap)
+		}
+	}
+
+	if m.goalInput.Focused() {
+		label := "Chapters this year"
+		if m.goalPeriod == models.GoalPeriodMonthly {
+			label = "Chapters this month"
+		}
+		inputStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(styles.ColorPrimary).
+			Padding(0, 1).
+			Width(m.width - 10)
+		sections = append(sections, inputStyle.Render(m.theme.DimText.Render(label+": ")+m.goalInput.View()))
+	}
+
+	if m.lastError != nil {
+		sections = append(sections, m.theme.ErrorText.Render("⚠ "+m.lastError.Error()))
+	} else if m.message != "" {
+		sections = append(sections, m.theme.Description.Render(m.message))
+	}
+
+	sections = append(sections, "", m.renderHelp())
+
+	content := lipgloss.JoinVertical(lipgloss.Left, sections...)
+	return m.theme.Container.Width(m.width - 4).Render(content)
+}
+
+// =====================================
+// RENDERERS
+// =====================================
+
+// statsBarWidth is the width of the rank and goal bars
+const statsBarWidth = 24
+
+func (m StatsModel) renderOverview() string {
+	o := m.overview
+	parts := []string{
+		m.theme.Primary.Render(fmt.Sprintf("📚 %d chapters", o.TotalChapters)),
+		m.theme.Description.Render(fmt.Sprintf("⏱ %dh", o.TotalMinutes/60)),
+		m.theme.Description.Render(fmt.Sprintf("📖 %d manga", o.MangaRead)),
+		m.theme.Warning.Render(fmt.Sprintf("🔥 %d days (best %d)", o.CurrentStreak, o.LongestStreak)),
+	}
+	// Grace days left this month, and the last missed day grace covered
+	if g := o.Grace; g.PerMonth > 0 {
+		grace := fmt.Sprintf("🧊 %d/%d grace left", g.Remaining, g.PerMonth)
+		if n := len(g.Dates); n > 0 {
+			grace += " (covered " + g.Dates[n-1] + ")"
+		}
+		parts = append(parts, m.theme.Description.Render(grace))
+	}
+	parts = append(parts, m.theme.DimText.Render(fmt.Sprintf("%d this week", o.ChaptersThisWeek)))
+	return strings.Join(parts, "  ")
+}
+
+func (m StatsModel) renderRank() string {
+	chapters := m.overview.TotalChapters
+	rank, next := models.RankFor(chapters)
+	label := lipgloss.NewStyle().Width(12).Render(rank.Icon + " " + rank.Name)
+
+	if next == nil {
+		return label + styles.RenderProgressBar(1, statsBarWidth) + "  " + m.theme.DimText.Render("MAX RANK")
+	}
+	span := next.MinChapters - rank.MinChapters
+	pct := float64(chapters-rank.MinChapters) / float64(span)
+	return label + styles.RenderProgressBar(pct, statsBarWidth) + "  " +
+		m.theme.DimText.Render(fmt.Sprintf("%d to %s %s", next.MinChapters-chapters, next.Icon, next.Name))
+}
+
+func (m StatsModel) renderGoal(period string) string {
+	goal := m.goal(period)
+	if goal == nil {
+		hint := "[g] set a yearly goal"
+		if period == models.GoalPeriodMonthly {
+			hint = "[m] set a monthly goal"
+		}
+		return m.theme.DimText.Render("🎯 " + hint)
+	}
+
+	label := lipgloss.NewStyle().Width(12).Render("🎯 " + goalLabel(goal.ReadingGoal))
+	detail := fmt.Sprintf("%d/%d · %d days left", goal.ChaptersRead, goal.TargetChapters, goal.DaysLeft)
+	if goal.Completed {
+		detail = fmt.Sprintf("%d/%d ✓ reached", goal.ChaptersRead, goal.TargetChapters)
+	}
+	return label + styles.RenderProgressBar(goal.Percentage/100, statsBarWidth) + "  " + m.theme.DimText.Render(detail)
+}
+
+// Heatmap grid layout. A year of weeks fits in MaxHeatmapDays whatever the
+// weekday, so every shown day is covered by the loaded range.
+const (
+	heatmapMaxWeeks   = 52
+	heatmapMinWeeks   = 8 // below this the weekday labels and cell gaps are dropped
+	heatmapLabelWidth = 4 // "Mon "
+)
+
+// renderBadges shows every badge: earned ones lit, locked ones dim with progress
+func (m StatsModel) renderBadges() string {
+	if len(m.badges) == 0 {
+		return ""
+	}
+
+	var earned int
+	cells := make([]string, 0, len(m.badges))
+	for _, b := range m.badges {
+		if b.Earned {
+			earned++
+			cells = append(cells, m.theme.Primary.Render(b.Icon+" "+b.Name))
+		} else {
+			cells = append(cells, m.theme.DimText.Render(fmt.Sprintf("🔒 %s %d/%d", b.Name, b.Progress, b.Threshold)))
+		}
+	}
+
+	// Flow the badges into lines that fit the container
+	available := max(m.width-8, 20)
+	lines := []string{m.theme.Description.Render(fmt.Sprintf("🏅 %d/%d badges", earned, len(m.badges)))}
+	var line string
+	for _, cell := range cells {
+		switch {
+		case line == "":
+			line = cell
+		case lipgloss.Width(line)+2+lipgloss.Width(cell) > available:
+			lines = append(lines, line)
+			line = cell
+		default:
+			line += "  " + cell
+		}
+	}
+	return strings.Join(append(lines, line), "\n")
+}
+
+// renderHeatmap draws the latest weeks of reading as a grid: one column per
+// calendar week (Sunday first), one row per weekday, today in the last column.
+// Days without reading get the dim level; only days after today stay blank.
+func (m StatsModel) renderHeatmap() string {
+	if len(m.heatmap) == 0 {
+		return ""
+	}
+	// The server's today, not the local one: the data is bucketed in UTC
+	today, err := time.Parse("2006-01-02", m.heatmap[len(m.heatmap)-1].Date)
+	if err != nil {
+		return ""
+	}
+
+	// Fit the widest layout the terminal allows; the container takes 6 columns
+	available := m.width - 6
+	cellWidth, labelWidth := 2, heatmapLabelWidth
+	weeks := (available - labelWidth) / cellWidth
+	if weeks < heatmapMinWeeks {
+		cellWidth, labelWidth = 1, 0
+		weeks = available
+	}
+	weeks = min(weeks, heatmapMaxWeeks)
+	if weeks < 1 {
+		return ""
+	}
+	start := today.AddDate(0, 0, -int(today.Weekday())-7*(weeks-1))
+
+	chapters := make(map[string]int, len(m.heatmap))
+	busiest, total, active := 0, 0, 0
+	for _, day := range m.heatmap {
+		if day.Date < start.Format("2006-01-02") {
+			continue
+		}
+		chapters[day.Date] = day.Chapters
+		busiest = max(busiest, day.Chapters)
+		total += day.Chapters
+		if day.Chapters > 0 {
+			active++
+		}
+	}
+
+	lines := []string{
+		m.theme.Description.Render(fmt.Sprintf("🗓 %d chapters on %d days, last %d weeks", total, active, weeks)),
+		strings.Repeat(" ", labelWidth) + m.theme.DimText.Render(heatmapMonths(start, weeks, cellWidth)),
+	}
+	for weekday := 0; weekday < 7; weekday++ {
+		var row strings.Builder
+		if labelWidth > 0 {
+			label := ""
+			if weekday%2 == 1 { // Mon, Wed, Fri like the GitHub grid
+				label = time.Weekday(weekday).String()[:3]
+			}
+			row.WriteString(m.theme.DimText.Render(fmt.Sprintf("%-*s", labelWidth, label)))
+		}
+		for week := 0; week < weeks; week++ {
+			day := start.AddDate(0, 0, 7*week+weekday)
+			if day.After(today) {
+				break
+			}
+			level := heatmapLevel(chapters[day.Format("2006-01-02")], busiest)
+			row.WriteString(m.theme.Heatmap[level].Render("■"))
+			if cellWidth > 1 {
+				row.WriteString(" ")
+			}
+		}
+		lines = append(lines, row.String())
+	}
+
+	shades := make([]string, len(m.theme.Heatmap))
+	for level, style := range m.theme.Heatmap {
+		shades[level] = style.Render("■")
+	}
+	legend := m.theme.DimText.Render("Less ") +
+		strings.Join(shades, strings.Repeat(" ", cellWidth-1)) +
+		m.theme.DimText.Render(" More")
+	lines = append(lines, strings.Repeat(" ", labelWidth)+legend)
+
+	return strings.Join(lines, "\n")
+}
+
+// heatmapMonths labels the weeks where a month starts. Labels are placed
+// right to left so a partial first week never hides the month after it.
+func heatmapMonths(start time.Time, weeks, cellWidth int) string {
+	line := []byte(strings.Repeat(" ", weeks*cellWidth))
+	limit := len(line) + 1 // start of the label to the right, keeping a gap before it
+	for week := weeks - 1; week >= 0; week-- {
+		weekStart := start.AddDate(0, 0, 7*week)
+		if week > 0 && weekStart.Month() == weekStart.AddDate(0, 0, -7).Month() {
+			continue
+		}
+		pos := week * cellWidth
+		label := weekStart.Format("Jan")
+		if pos+len(label) >= limit {
+			continue
+		}
+		copy(line[pos:], label)
+		limit = pos
+	}
+	return string(line)
+}
+
+// heatmapLevel buckets a day's chapters by its share of the busiest day shown:
+// 0 for no reading, then 1 up to the top level
+func heatmapLevel(chapters, busiest int) int {
+	if chapters <= 0 || busiest <= 0 {
+		return 0
+	}
+	top := styles.HeatmapLevels - 1
+	return min((chapters*top+busiest-1)/busiest, top)
+}
+
+func (m StatsModel) renderHelp() string {
+	if m.goalInput.Focused() {
+		return styles.RenderKeyHint("Enter", "save") + "  " + styles.RenderKeyHint("Esc", "back")
+	}
+	return strings.Join([]string{
+		styles.RenderKeyHint("g", "yearly goal"),
+		styles.RenderKeyHint("m", "monthly goal"),
+		styles.RenderKeyHint("r", "refresh"),
+	}, "  ")
+}
+
+// goalLabel names a goal's window: "2026" or "October"
+func goalLabel(goal models.ReadingGoal) string {
+	start, err := time.Parse("2006-01-02", goal.StartDate)
+	if err != nil {
+		return goal.Period
+	}
+	if goal.Period == models.GoalPeriodMonthly {
+		return start.Format("January")
+	}
+	return start.Format("2006")
+}
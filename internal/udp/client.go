@@ -12,8 +12,10 @@ import (
 // Client represents a UDP notification client
 type Client struct {
 	ServerAddr     string
+	UserID         string // optional; lets the server apply the user's notification settings
 	conn           *net.UDPConn
 	OnNotification func(Notification)
+	Verifier       *Verifier // optional; drops unsigned, forged or replayed notifications
 	stop           chan struct{}
 }
 
@@ -39,7 +41,11 @@ func (c *Client) Connect() error {
 	c.conn = conn
 
 	// Send registration message
-	_, err = c.conn.Write([]byte("REGISTER"))
+	register := "REGISTER"
+	if c.UserID != "" {
+		register += " " + c.UserID
+	}
+	_, err = c.conn.Write([]byte(register))
 	if err != nil {
 		return fmt.Errorf("send register: %w", err)
 	}
@@ -88,6 +94,10 @@ func (c *Client) listen() {
 				logger.Warnf("failed to unmarshal notification: %v", err)
 				continue
 			}
+			if err := c.Verifier.Verify(notification); err != nil {
+				logger.Warnf("dropped notification: %v", err)
+				continue
+			}
 
 			if c.OnNotification != nil {
 				c.OnNotification(notification)
@@ -106,3 +116,27 @@ func (c *Client) Close() error {
 	}
 	return nil
 }
+
+// SendBroadcast asks the UDP server at serverAddr to broadcast a notification
+// to all of its subscribers (BROADCAST command), signed with secret
+func SendBroadcast(serverAddr, secret string, notification Notification) error {
+	addr, err := net.ResolveUDPAddr("udp", serverAddr)
+	if err != nil {
+		return fmt.Errorf("resolve udp addr: %w", err)
+	}
+	conn, err := net.DialUDP("udp", nil, addr)
+	if err != nil {
+		return fmt.Errorf("dial udp: %w", err)
+	}
+	defer conn.Close()
+
+	Sign(&notification, secret)
+	payload, err := json.Marshal(notification)
+	if err != nil {
+		return fmt.Errorf("marshal notification: %w", err)
+	}
+	if _, err := conn.Write(append([]byte("BROADCAST "), payload...)); err != nil {
+		return fmt.Errorf("send broadcast: %w", err)
+	}
+	return nil
+}
@@ -1,6 +1,9 @@
 package udp
 
-import "time"
+import (
+	"fmt"
+	"time"
+)
 
 // Notification represents a UDP notification message
 type Notification struct {
@@ -8,6 +11,7 @@ type Notification struct {
 	MangaID   string `json:"manga_id"`   // manga identifier
 	Message   string `json:"message"`    // notification message
 	Timestamp int64  `json:"timestamp"`  // unix timestamp
+	Signature string `json:"signature,omitempty"` // hex HMAC-SHA256, see Sign
 }
 
 // NewChapterNotification creates a chapter release notification
@@ -20,6 +24,17 @@ func NewChapterNotification(mangaID, message string) Notification {
 	}
 }
 
+// NewStatusChangeNotification announces that a manga's status changed,
+// e.g. from ongoing to completed
+func NewStatusChangeNotification(mangaID, title, from, to string) Notification {
+	return Notification{
+		Type:      "status_change",
+		MangaID:   mangaID,
+		Message:   fmt.Sprintf("%s is now %s (was %s)", title, to, from),
+		Timestamp: time.Now().Unix(),
+	}
+}
+
 // NewSystemNotification creates a system notification
 func NewSystemNotification(message string) Notification {
 	return Notification{
@@ -1,32 +1,52 @@
 // Package udp - UDP Notification Server Implementation
 // Quản lý UDP datagram communication cho push notifications
 // Chức năng:
-//   - Nhận REGISTER/UNREGISTER messages từ clients
+//   - Nhận REGISTER [userID]/UNREGISTER messages từ clients
 //   - Maintain subscriber list
 //   - Broadcast chapter notifications đến tất cả subscribers
+//   - DeliveryFilter bỏ qua user đã tắt notification hoặc mute manga
+//   - Verify BROADCAST requests và sign notifications gửi đi (udp.secret)
 //   - Connectionless protocol - không maintain state
 //   - JSON datagram format
 //   - Non-blocking sends
 package udp
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"net"
 	"strings"
 	"sync"
+	"time"
 
 	"mangahub/pkg/logger"
 )
 
+// DeliveryFilter returns the users among userIDs who must not receive n.
+// Subscribers that registered without a user ID are never filtered.
+type DeliveryFilter func(ctx context.Context, n Notification, userIDs []string) (map[string]bool, error)
+
+// filterTimeout bounds how long a broadcast waits on the filter before delivering to everyone
+const filterTimeout = 2 * time.Second
+
+// subscriber is one registered client
+type subscriber struct {
+	addr   *net.UDPAddr
+	userID string // empty for anonymous registrations
+}
+
 // NotificationServer manages UDP notification broadcasting
 type NotificationServer struct {
 	Addr       string
 	conn       *net.UDPConn
 	clientsMu  sync.RWMutex
-	clients    map[string]*net.UDPAddr // clientID -> address
+	clients    map[string]subscriber // clientID -> subscriber
+	filter     DeliveryFilter
+	secret     string
+	verifier   *Verifier
 	Broadcast  chan Notification
-	register   chan *net.UDPAddr
+	register   chan subscriber
 	unregister chan string
 	stop       chan struct{}
 }
@@ -35,14 +55,27 @@ type NotificationServer struct {
 func NewNotificationServer(host string, port int) *NotificationServer {
 	return &NotificationServer{
 		Addr:       fmt.Sprintf("%s:%d", host, port),
-		clients:    make(map[string]*net.UDPAddr),
+		clients:    make(map[string]subscriber),
 		Broadcast:  make(chan Notification, 100),
-		register:   make(chan *net.UDPAddr),
+		register:   make(chan subscriber),
 		unregister: make(chan string),
 		stop:       make(chan struct{}),
 	}
 }
 
+// SetFilter enables per-user delivery filtering; call before Start
+func (s *NotificationServer) SetFilter(filter DeliveryFilter) {
+	s.filter = filter
+}
+
+// SetSecret enables notification signing; call before Start.
+// BROADCAST requests must then carry a valid, fresh signature and every
+// notification sent to subscribers is signed with the same secret.
+func (s *NotificationServer) SetSecret(secret string, replayWindow time.Duration) {
+	s.secret = secret
+	s.verifier = NewVerifier(secret, replayWindow)
+}
+
 // Start starts the UDP notification server
 func (s *NotificationServer) Start() error {
 	addr, err := net.ResolveUDPAddr("udp", s.Addr)
@@ -69,13 +102,13 @@ func (s *NotificationServer) Start() error {
 func (s *NotificationServer) runHub() {
 	for {
 		select {
-		case addr := <-s.register:
-			clientID := addr.String()
+		case sub := <-s.register:
+			clientID := sub.addr.String()
 			s.clientsMu.Lock()
-			s.clients[clientID] = addr
+			s.clients[clientID] = sub
 			s.clientsMu.Unlock()
 			// Protocol trace logging
-			logger.UDP("REGISTER", clientID, fmt.Sprintf("total_subscribers=%d", len(s.clients)))
+			logger.UDP("REGISTER", clientID, fmt.Sprintf("user=%s total_subscribers=%d", sub.userID, len(s.clients)))
 
 		case clientID := <-s.unregister:
 			s.clientsMu.Lock()
@@ -114,9 +147,10 @@ func (s *NotificationServer) listenForRegistrations() {
 			message := string(buffer[:n])
 			logger.Debugf("UDP message from %s: %s", addr.String(), message)
 
-			// Simple protocol: "REGISTER" to register, "UNREGISTER" to unregister
-			if message == "REGISTER" {
-				s.register <- addr
+			// Simple protocol: "REGISTER [userID]" to register, "UNREGISTER" to unregister
+			if message == "REGISTER" || strings.HasPrefix(message, "REGISTER ") {
+				userID := strings.TrimSpace(strings.TrimPrefix(message, "REGISTER"))
+				s.register <- subscriber{addr: addr, userID: userID}
 				// Send confirmation
 				s.sendTo(addr, []byte("REGISTERED"))
 			} else if message == "UNREGISTER" {
@@ -126,11 +160,13 @@ func (s *NotificationServer) listenForRegistrations() {
 				// Handle external broadcast request
 				payload := strings.TrimPrefix(message, "BROADCAST ")
 				var notification Notification
-				if err := json.Unmarshal([]byte(payload), &notification); err == nil {
+				if err := json.Unmarshal([]byte(payload), &notification); err != nil {
+					logger.Warnf("Invalid broadcast payload from %s: %v", addr.String(), err)
+				} else if err := s.verifier.Verify(notification); err != nil {
+					logger.Warnf("Rejected broadcast from %s: %v", addr.String(), err)
+				} else {
 					s.Broadcast <- notification
 					logger.Infof("Received external broadcast request from %s", addr.String())
-				} else {
-					logger.Warnf("Invalid broadcast payload from %s: %v", addr.String(), err)
 				}
 			} else {
 				logger.Warnf("unknown UDP command from %s: %s", addr.String(), message)
@@ -141,6 +177,7 @@ func (s *NotificationServer) listenForRegistrations() {
 
 // broadcastNotification sends notification to all registered clients
 func (s *NotificationServer) broadcastNotification(notification Notification) {
+	Sign(&notification, s.secret)
 	data, err := json.Marshal(notification)
 	if err != nil {
 		logger.Errorf("failed to marshal notification: %v", err)
@@ -148,23 +185,61 @@ func (s *NotificationServer) broadcastNotification(notification Notification) {
 	}
 
 	s.clientsMu.RLock()
-	defer s.clientsMu.RUnlock()
+	recipients := make(map[string]subscriber, len(s.clients))
+	for clientID, sub := range s.clients {
+		recipients[clientID] = sub
+	}
+	s.clientsMu.RUnlock()
 
-	if len(s.clients) == 0 {
+	if len(recipients) == 0 {
 		logger.Debug("no UDP clients to broadcast to")
 		return
 	}
 
+	blocked := s.blockedUsers(notification, recipients)
+
 	// Protocol trace logging
-	logger.UDP("BROADCAST", fmt.Sprintf("%d_clients", len(s.clients)), notification.Type+": "+notification.Message)
+	logger.UDP("BROADCAST", fmt.Sprintf("%d_clients", len(recipients)), notification.Type+": "+notification.Message)
 
-	for clientID, addr := range s.clients {
-		if err := s.sendTo(addr, data); err != nil {
+	for clientID, sub := range recipients {
+		if sub.userID != "" && blocked[sub.userID] {
+			continue
+		}
+		if err := s.sendTo(sub.addr, data); err != nil {
 			logger.Errorf("failed to send to %s: %v", clientID, err)
 		}
 	}
 }
 
+// blockedUsers asks the filter which identified subscribers to skip.
+// A failing filter delivers to everyone rather than dropping the notification.
+func (s *NotificationServer) blockedUsers(notification Notification, recipients map[string]subscriber) map[string]bool {
+	if s.filter == nil {
+		return nil
+	}
+
+	seen := make(map[string]bool)
+	var userIDs []string
+	for _, sub := range recipients {
+		if sub.userID != "" && !seen[sub.userID] {
+			seen[sub.userID] = true
+			userIDs = append(userIDs, sub.userID)
+		}
+	}
+	if len(userIDs) == 0 {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), filterTimeout)
+	defer cancel()
+	blocked, err := s.filter(ctx, notification, userIDs)
+	if err != nil {
+		logger.Warnf("udp delivery filter failed, delivering to all: %v", err)
+		return nil
+	}
+	return blocked
+}
+
 // sendTo sends data to a specific UDP address
 func (s *NotificationServer) sendTo(addr *net.UDPAddr, data []byte) error {
 	_, err := s.conn.WriteToUDP(data, addr)
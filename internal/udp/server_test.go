@@ -0,0 +1,106 @@
+// Package udp - Notification Server Tests
+// Unit tests cho DeliveryFilter (mute/tắt notification theo user)
+package udp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+// listen opens a loopback UDP socket
+func listen(t *testing.T) *net.UDPConn {
+	conn, err := net.ListenUDP("udp", &net.UDPAddr{IP: net.IPv4(127, 0, 0, 1)})
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// received reports whether conn gets a datagram within a short wait
+func received(conn *net.UDPConn) bool {
+	conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	buf := make([]byte, 2048)
+	_, _, err := conn.ReadFromUDP(buf)
+	return err == nil
+}
+
+func newTestServer(t *testing.T) (*NotificationServer, map[string]*net.UDPConn) {
+	s := NewNotificationServer("127.0.0.1", 0)
+	s.conn = listen(t)
+
+	clients := map[string]*net.UDPConn{
+		"alice": listen(t),
+		"bob":   listen(t),
+		"":      listen(t), // anonymous registration
+	}
+	for userID, conn := range clients {
+		addr := conn.LocalAddr().(*net.UDPAddr)
+		s.clients[addr.String()] = subscriber{addr: addr, userID: userID}
+	}
+	return s, clients
+}
+
+func TestBroadcastSkipsBlockedUsers(t *testing.T) {
+	s, clients := newTestServer(t)
+	s.SetFilter(func(ctx context.Context, n Notification, userIDs []string) (map[string]bool, error) {
+		if n.MangaID == "berserk" {
+			return map[string]bool{"alice": true}, nil
+		}
+		return nil, nil
+	})
+
+	s.broadcastNotification(NewChapterNotification("berserk", "Chapter 375 is out!"))
+	if received(clients["alice"]) {
+		t.Error("alice muted berserk but received the notification")
+	}
+	if !received(clients["bob"]) || !received(clients[""]) {
+		t.Error("bob and anonymous subscribers should receive the notification")
+	}
+
+	// Delivery is decided per broadcast, so other manga still reach alice
+	s.broadcastNotification(NewChapterNotification("vagabond", "Chapter 328 is out!"))
+	if !received(clients["alice"]) {
+		t.Error("alice should receive notifications for manga she did not mute")
+	}
+}
+
+func TestBroadcastDeliversWhenFilterFails(t *testing.T) {
+	s, clients := newTestServer(t)
+	s.SetFilter(func(ctx context.Context, n Notification, userIDs []string) (map[string]bool, error) {
+		return nil, errors.New("database is locked")
+	})
+
+	s.broadcastNotification(NewChapterNotification("berserk", "Chapter 375 is out!"))
+	for userID, conn := range clients {
+		if !received(conn) {
+			t.Errorf("subscriber %q missed the notification after a filter error", userID)
+		}
+	}
+}
+
+func TestBroadcastSignsNotifications(t *testing.T) {
+	s, clients := newTestServer(t)
+	s.SetSecret("s3cret", time.Minute)
+
+	s.broadcastNotification(NewChapterNotification("berserk", "Chapter 375 is out!"))
+
+	conn := clients["bob"]
+	conn.SetReadDeadline(time.Now().Add(200 * time.Millisecond))
+	buf := make([]byte, 2048)
+	n, _, err := conn.ReadFromUDP(buf)
+	if err != nil {
+		t.Fatalf("read: %v", err)
+	}
+	var got Notification
+	if err := json.Unmarshal(buf[:n], &got); err != nil {
+		t.Fatalf("decode: %v", err)
+	}
+	if err := NewVerifier("s3cret", time.Minute).Verify(got); err != nil {
+		t.Errorf("subscriber could not verify broadcast: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package udp
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerify(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	signed := func(mutate func(n *Notification)) Notification {
+		n := Notification{Type: "chapter_release", MangaID: "berserk", Message: "Chapter 375 is out!", Timestamp: now.Unix()}
+		if mutate != nil {
+			mutate(&n)
+		}
+		Sign(&n, "s3cret")
+		return n
+	}
+
+	tests := []struct {
+		name string
+		n    Notification
+		want error
+	}{
+		{"valid", signed(nil), nil},
+		{"unsigned", Notification{Type: "system", Message: "hi", Timestamp: now.Unix()}, ErrUnsigned},
+		{"wrong secret", func() Notification {
+			n := Notification{Type: "system", Message: "hi", Timestamp: now.Unix()}
+			Sign(&n, "other")
+			return n
+		}(), ErrBadSignature},
+		{"tampered message", func() Notification {
+			n := signed(nil)
+			n.Message = "Chapter 376 is out!"
+			return n
+		}(), ErrBadSignature},
+		{"stale", signed(func(n *Notification) { n.Timestamp = now.Add(-time.Minute).Unix() }), ErrStale},
+		{"from the future", signed(func(n *Notification) { n.Timestamp = now.Add(time.Minute).Unix() }), ErrStale},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier("s3cret", 30*time.Second)
+			v.now = func() time.Time { return now }
+			if err := v.Verify(tt.n); !errors.Is(err, tt.want) {
+				t.Errorf("Verify() = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifyRejectsReplay(t *testing.T) {
+	now := time.Unix(1_700_000_000, 0)
+	v := NewVerifier("s3cret", 30*time.Second)
+	v.now = func() time.Time { return now }
+
+	n := NewSystemNotification("maintenance at 02:00")
+	n.Timestamp = now.Unix()
+	Sign(&n, "s3cret")
+
+	if err := v.Verify(n); err != nil {
+		t.Fatalf("first delivery: %v", err)
+	}
+	if err := v.Verify(n); !errors.Is(err, ErrReplayed) {
+		t.Fatalf("second delivery = %v, want ErrReplayed", err)
+	}
+
+	// Once the window has passed the replay is rejected as stale and forgotten
+	v.now = func() time.Time { return now.Add(time.Minute) }
+	if err := v.Verify(n); !errors.Is(err, ErrStale) {
+		t.Fatalf("late replay = %v, want ErrStale", err)
+	}
+	if len(v.seen) != 0 {
+		t.Errorf("expired signatures kept: %d", len(v.seen))
+	}
+}
+
+func TestNilVerifierAcceptsUnsigned(t *testing.T) {
+	var v *Verifier = NewVerifier("", 0)
+	if err := v.Verify(NewSystemNotification("hello")); err != nil {
+		t.Errorf("verification disabled but got %v", err)
+	}
+}
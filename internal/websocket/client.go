@@ -9,10 +9,9 @@ import (
 )
 
 const (
-	writeWait      = 10 * time.Second
-	pongWait       = 60 * time.Second
-	pingPeriod     = (pongWait * 9) / 10
-	maxMessageSize = 512
+	writeWait  = 10 * time.Second
+	pongWait   = 60 * time.Second
+	pingPeriod = (pongWait * 9) / 10
 )
 
 type Client struct {
@@ -22,15 +21,22 @@ type Client struct {
 	userID   string
 	username string
 	roomID   string
+
+	// Only touched by readPump
+	limiter    *rateLimiter
+	violations int
 }
 
 func (c *Client) readPump() {
 	defer func() {
-		c.hub.unregister <- c
+		c.hub.leave(c)
 		c.conn.Close()
 	}()
 
-	c.conn.SetReadLimit(maxMessageSize)
+	limits := c.hub.limits
+	if limits.MaxFrameSize > 0 {
+		c.conn.SetReadLimit(limits.MaxFrameSize)
+	}
 	c.conn.SetReadDeadline(time.Now().Add(pongWait))
 	c.conn.SetPongHandler(func(string) error {
 		c.conn.SetReadDeadline(time.Now().Add(pongWait))
@@ -41,6 +47,7 @@ func (c *Client) readPump() {
 		var msg struct {
 			Content string `json:"content"`
 			Type    string `json:"type"`
+			LastID  string `json:"last_id"` // resume only
 		}
 		if err := c.conn.ReadJSON(&msg); err != nil {
 			if websocket.IsUnexpectedCloseError(err, websocket.CloseGoingAway, websocket.CloseAbnormalClosure) {
@@ -49,18 +56,67 @@ func (c *Client) readPump() {
 			break
 		}
 
-		if msg.Content != "" {
-			msgType := msg.Type
-			if msgType == "" {
-				msgType = "message"
+		// Clients may only send chat messages, typing events and resume
+		// requests; join/leave/presence are generated by the hub
+		switch {
+		case msg.Type == TypeResume:
+			// Each resume reads the database, so it counts against the rate limit
+			if !c.limiter.allow(time.Now()) {
+				if !c.reject("sending too fast, slow down") {
+					return
+				}
+				continue
 			}
-			roomMsg := NewRoomMessage(c.userID, c.username, msg.Content, msgType)
+			c.hub.resume(c, msg.LastID)
+		case msg.Type == TypeTyping:
+			roomMsg := NewRoomMessage(c.userID, c.username, "", TypeTyping)
 			roomMsg.RoomID = c.roomID
-			c.hub.broadcast <- roomMsg
+			c.hub.submit(roomMsg)
+		case msg.Content != "":
+			content, reason := limits.checkContent(msg.Content)
+			if reason == "" && content != "" && !c.limiter.allow(time.Now()) {
+				reason = "sending too fast, slow down"
+			}
+			if reason == "" && content != "" {
+				var blocked bool
+				if content, blocked = c.hub.filter.Apply(content); blocked {
+					reason = "message contains language that is not allowed"
+				}
+			}
+			if reason != "" {
+				if !c.reject(reason) {
+					return
+				}
+				continue
+			}
+			if content == "" {
+				continue
+			}
+			c.violations = 0
+			roomMsg := NewRoomMessage(c.userID, c.username, content, TypeMessage)
+			roomMsg.RoomID = c.roomID
+			c.hub.submit(roomMsg)
 		}
 	}
 }
 
+// reject tells the client its message was dropped. It returns false once the
+// client has hit MaxViolations in a row and has been sent a close frame.
+func (c *Client) reject(reason string) bool {
+	c.violations++
+	if limit := c.hub.limits.MaxViolations; limit > 0 && c.violations >= limit {
+		logger.Warnf("Disconnecting %s from room %s: %s", c.username, c.roomID, reason)
+		closeMsg := websocket.FormatCloseMessage(websocket.ClosePolicyViolation, reason)
+		_ = c.conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(writeWait))
+		return false
+	}
+
+	errMsg := NewRoomMessage("", "", reason, TypeError)
+	errMsg.RoomID = c.roomID
+	c.hub.notify(c, errMsg)
+	return true
+}
+
 func (c *Client) writePump() {
 	ticker := time.NewTicker(pingPeriod)
 	defer func() {
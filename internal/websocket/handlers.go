@@ -2,10 +2,13 @@ package websocket
 
 import (
 	"net/http"
+	"strconv"
 
 	"github.com/gin-gonic/gin"
 	"github.com/gorilla/websocket"
 	"mangahub/internal/auth"
+	"mangahub/internal/chat"
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/logger"
 )
 
@@ -28,13 +31,13 @@ func NewHandler(hub *Hub) *Handler {
 func (h *Handler) ServeWS(c *gin.Context) {
 	user := auth.GetCurrentUser(c)
 	if user == nil {
-		c.JSON(http.StatusUnauthorized, gin.H{"error": "unauthorized"})
+		apperrors.Respond(c, apperrors.Unauthorized("authentication required", nil), "")
 		return
 	}
 
 	roomID := c.Query("room_id")
 	if roomID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "room_id required"})
+		apperrors.Respond(c, apperrors.Validation("room_id", "room_id required"), "")
 		return
 	}
 
@@ -51,25 +54,222 @@ func (h *Handler) ServeWS(c *gin.Context) {
 		userID:   user.ID,
 		username: user.Username,
 		roomID:   roomID,
+		limiter:  newRateLimiter(h.hub.limits.RateMessages, h.hub.limits.RateWindow),
 	}
 
-	h.hub.register <- client
+	select {
+	case h.hub.register <- client:
+	case <-h.hub.done:
+		// Hub is shutting down
+		conn.WriteMessage(websocket.CloseMessage,
+			websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down"))
+		conn.Close()
+		return
+	}
 
 	go client.writePump()
 	go client.readPump()
 }
 
+// Page size limits for the member list and the rooms directory
+const (
+	defaultPageLimit = 50
+	maxPageLimit     = 100
+)
+
+// GetRoomInfo handles GET /rooms/:room_id?page=1&limit=50
+// clients is one page of the members' usernames, sorted; count is all of them
 func (h *Handler) GetRoomInfo(c *gin.Context) {
 	roomID := c.Param("room_id")
 	if roomID == "" {
-		c.JSON(http.StatusBadRequest, gin.H{"error": "room_id required"})
+		apperrors.Respond(c, apperrors.Validation("room_id", "room_id required"), "")
+		return
+	}
+	page, limit, err := pageParams(c)
+	if err != nil {
+		apperrors.Respond(c, err, "")
 		return
 	}
 
-	clients := h.hub.GetRoomClients(roomID)
+	clients, total := h.hub.GetRoomMembers(roomID, (page-1)*limit, limit)
 	c.JSON(http.StatusOK, gin.H{
-		"room_id": roomID,
-		"clients": clients,
-		"count":   len(clients),
+		"room_id":  roomID,
+		"clients":  clients,
+		"count":    total,
+		"page":     page,
+		"limit":    limit,
+		"has_more": (page-1)*limit+len(clients) < total,
+	})
+}
+
+// ListRooms handles GET /rooms?type=general|manga&page=1&limit=50
+// Optional: hide_empty=true leaves out rooms nobody is in,
+// include_inactive=true adds rooms that were closed
+func (h *Handler) ListRooms(c *gin.Context) {
+	roomType := c.Query("type")
+	if roomType != "" && roomType != "general" && roomType != "manga" {
+		apperrors.Respond(c, apperrors.Validation("type", "type must be general or manga"), "")
+		return
+	}
+	page, limit, err := pageParams(c)
+	if err != nil {
+		apperrors.Respond(c, err, "")
+		return
+	}
+
+	rooms, err := h.hub.ListRooms(c.Request.Context(), RoomFilter{
+		Type:            roomType,
+		IncludeInactive: c.Query("include_inactive") == "true",
+		HideEmpty:       c.Query("hide_empty") == "true",
+		Page:            page,
+		Limit:           limit,
 	})
+	if err != nil {
+		logger.Errorf("Failed to list rooms: %v", err)
+		apperrors.Respond(c, err, "failed to list rooms")
+		return
+	}
+
+	c.JSON(http.StatusOK, rooms)
+}
+
+// OpenMangaRoom handles POST /rooms/manga/:manga_id
+// Returns the manga's room, creating it on first use; never creates a second one
+func (h *Handler) OpenMangaRoom(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		apperrors.Respond(c, apperrors.Unauthorized("authentication required", nil), "")
+		return
+	}
+
+	room, err := h.hub.OpenMangaRoom(c.Request.Context(), c.Param("manga_id"), user.ID)
+	if err != nil {
+		apperrors.Respond(c, err, "failed to open room")
+		return
+	}
+
+	c.JSON(http.StatusOK, room)
+}
+
+// pageParams reads ?page= (default 1) and ?limit= (default 50, capped at 100)
+func pageParams(c *gin.Context) (page, limit int, err error) {
+	page, limit = 1, defaultPageLimit
+	if raw := c.Query("page"); raw != "" {
+		n, convErr := strconv.Atoi(raw)
+		if convErr != nil || n < 1 {
+			return 0, 0, apperrors.Validation("page", "page must be a positive integer")
+		}
+		page = n
+	}
+	if raw := c.Query("limit"); raw != "" {
+		n, convErr := strconv.Atoi(raw)
+		if convErr != nil || n < 1 {
+			return 0, 0, apperrors.Validation("limit", "limit must be a positive integer")
+		}
+		limit = min(n, maxPageLimit)
+	}
+	return page, limit, nil
+}
+
+// Message history page size limits for GetRoomMessages
+const (
+	defaultHistoryLimit = 50
+	maxHistoryLimit     = 100
+)
+
+// GetRoomMessages handles GET /rooms/:room_id/messages?before=<id>&limit=50
+// Returns history oldest first; has_more tells the client an older page exists.
+// ?after=<id> reads forward instead: messages newer than id, has_more when
+// newer ones remain
+func (h *Handler) GetRoomMessages(c *gin.Context) {
+	roomID := c.Param("room_id")
+	if roomID == "" {
+		apperrors.Respond(c, apperrors.Validation("room_id", "room_id required"), "")
+		return
+	}
+
+	limit := defaultHistoryLimit
+	if raw := c.Query("limit"); raw != "" {
+		n, err := strconv.Atoi(raw)
+		if err != nil || n < 1 {
+			apperrors.Respond(c, apperrors.Validation("limit", "limit must be a positive integer"), "")
+			return
+		}
+		if n > maxHistoryLimit {
+			n = maxHistoryLimit
+		}
+		limit = n
+	}
+
+	before, after := c.Query("before"), c.Query("after")
+	if before != "" && after != "" {
+		apperrors.Respond(c, apperrors.Validation("after", "use either before or after, not both"), "")
+		return
+	}
+
+	var page *chat.MessagePage
+	var err error
+	if after != "" {
+		page, err = h.hub.GetRoomMessagesAfter(c.Request.Context(), roomID, after, limit)
+	} else {
+		page, err = h.hub.GetRoomMessages(c.Request.Context(), roomID, before, limit)
+	}
+	if err != nil {
+		logger.Errorf("Failed to load history for room %s: %v", roomID, err)
+		apperrors.Respond(c, err, "failed to load messages")
+		return
+	}
+
+	c.JSON(http.StatusOK, page)
+}
+
+// MarkRoomRead handles POST /rooms/:room_id/read
+// Body (optional): {"message_id": "<id>"}, the newest message the user has seen;
+// without it the room is read up to its newest message. Clients should send
+// this once things settle, not for every message they render.
+func (h *Handler) MarkRoomRead(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		apperrors.Respond(c, apperrors.Unauthorized("authentication required", nil), "")
+		return
+	}
+
+	var body struct {
+		MessageID string `json:"message_id"`
+	}
+	if c.Request.ContentLength != 0 {
+		if err := c.ShouldBindJSON(&body); err != nil {
+			apperrors.Respond(c, apperrors.BadRequest("invalid JSON body", err), "")
+			return
+		}
+	}
+
+	roomID := c.Param("room_id")
+	if err := h.hub.MarkRoomRead(c.Request.Context(), roomID, user.ID, body.MessageID); err != nil {
+		apperrors.Respond(c, err, "failed to mark room read")
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"room_id": roomID, "message_id": body.MessageID})
+}
+
+// GetUnread handles GET /rooms/unread
+// Per-room unread counts for the current user, for the rooms they have read before
+func (h *Handler) GetUnread(c *gin.Context) {
+	user := auth.GetCurrentUser(c)
+	if user == nil {
+		apperrors.Respond(c, apperrors.Unauthorized("authentication required", nil), "")
+		return
+	}
+
+	rooms, err := h.hub.UnreadCounts(c.Request.Context(), user.ID)
+	if err != nil {
+		logger.Errorf("Failed to count unread messages for %s: %v", user.ID, err)
+		apperrors.Respond(c, err, "failed to count unread messages")
+		return
+	}
+	total := 0
+	for _, room := range rooms {
+		total += room.Unread
+	}
+	c.JSON(http.StatusOK, gin.H{"rooms": rooms, "total": total})
 }
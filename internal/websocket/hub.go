@@ -4,23 +4,42 @@
 //   - Quản lý nhiều chat rooms (theo manga_id)
 //   - Client registration/unregistration cho mỗi room
 //   - Real-time message broadcasting trong room
-//   - Join/leave notifications
+//   - Join/leave notifications, presence list (members trong room)
+//   - Typing indicators (debounce 3s mỗi user)
+//   - Resume sau reconnect: replay tin nhắn bị lỡ (tối đa maxReplayMessages)
+//   - Rate limit và giới hạn độ dài tin nhắn mỗi connection (limits.go)
+//   - Wordlist filter (pkg/moderation): mask hoặc từ chối tin nhắn
 //   - Bidirectional communication
 //   - Concurrent-safe với mutex
 //   - Message persistence to database (Phase 2)
+//   - Graceful stop: close frame cho tất cả clients
+//   - Optional relay (Redis pub/sub) cho nhiều api-server instance
 package websocket
 
 import (
 	"context"
+	"fmt"
+	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/gorilla/websocket"
 
 	"mangahub/internal/chat"
+	"mangahub/pkg/apperrors"
 	"mangahub/pkg/logger"
+	"mangahub/pkg/models"
+	"mangahub/pkg/moderation"
 )
 
+// typingDebounce limits how often one user's typing events reach the room
+const typingDebounce = 3 * time.Second
+
+// maxReplayMessages caps a resume replay; a longer gap gets a resync instead
+const maxReplayMessages = 100
+
 // Hub manages WebSocket connections and message routing
 // Integrates with chat.Repository for message persistence
 type Hub struct {
@@ -29,11 +48,31 @@ type Hub struct {
 	register   chan *Client
 	unregister chan *Client
 	broadcast  chan RoomMessage
+	direct     chan directMessage
 	stop       chan struct{}
+	done       chan struct{} // closed once Run has returned
+	stopOnce   sync.Once
+	started    atomic.Bool // set when Run begins, so Stop knows whether to wait
+
+	// Per-connection message limits; set before Run
+	limits Limits
+
+	// Wordlist filter for chat content; nil filters nothing. Set before Run
+	filter *moderation.Filter
+
+	// Last forwarded typing event per room+user; only touched by Run
+	typing map[string]time.Time
 
 	// Chat repository for message persistence (Phase 2)
 	// Optional: if nil, messages are not persisted
 	chatRepo chat.Repository
+
+	// Cross-instance relay; optional, see relay.go
+	relay      Relay
+	instanceID string
+	outbound   chan RoomMessage
+	remote     chan RoomMessage
+	relaySeen  map[string]time.Time // only touched by Run
 }
 
 // NewHub creates a new hub without persistence
@@ -44,7 +83,15 @@ func NewHub() *Hub {
 		register:   make(chan *Client),
 		unregister: make(chan *Client),
 		broadcast:  make(chan RoomMessage, 256),
+		direct:     make(chan directMessage, 64),
+		limits:     DefaultLimits(),
 		stop:       make(chan struct{}),
+		done:       make(chan struct{}),
+		typing:     make(map[string]time.Time),
+		instanceID: uuid.New().String(),
+		outbound:   make(chan RoomMessage, 256),
+		remote:     make(chan RoomMessage, 256),
+		relaySeen:  make(map[string]time.Time),
 	}
 }
 
@@ -55,7 +102,34 @@ func (h *Hub) SetChatRepository(repo chat.Repository) {
 	logger.Info("Chat message persistence enabled")
 }
 
+// SetLimits replaces the default per-connection message limits.
+// Call before Run; connections already open keep their rate limiter.
+func (h *Hub) SetLimits(l Limits) {
+	h.limits = l
+}
+
+// SetWordFilter masks or rejects chat messages matching the moderation
+// wordlist. Call before Run.
+func (h *Hub) SetWordFilter(f *moderation.Filter) {
+	h.filter = f
+}
+
+// directMessage is a message for one client only, such as an error frame
+type directMessage struct {
+	client *Client
+	msg    RoomMessage
+}
+
 func (h *Hub) Run() {
+	h.started.Store(true)
+	defer close(h.done)
+
+	if h.relay != nil {
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go h.runRelay(ctx)
+	}
+
 	for {
 		select {
 		case client := <-h.register:
@@ -64,13 +138,115 @@ func (h *Hub) Run() {
 			h.unregisterClient(client)
 		case msg := <-h.broadcast:
 			h.broadcastMessage(msg)
+		case d := <-h.direct:
+			h.sendDirect(d.client, d.msg)
+		case msg := <-h.remote:
+			h.deliverRemote(msg)
 		case <-h.stop:
 			logger.Info("WebSocket hub stopping...")
+			h.closeAllClients()
 			return
 		}
 	}
 }
 
+// closeAllClients sends a close frame to every client and releases their pumps
+func (h *Hub) closeAllClients() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	closeMsg := websocket.FormatCloseMessage(websocket.CloseGoingAway, "server shutting down")
+	for roomID, room := range h.rooms {
+		for c := range room {
+			// WriteControl is safe to call concurrently with writePump
+			_ = c.conn.WriteControl(websocket.CloseMessage, closeMsg, time.Now().Add(writeWait))
+			close(c.send)
+			logger.WebSocket("LEAVE", roomID, c.userID, c.username+" disconnected (shutdown)")
+		}
+		delete(h.rooms, roomID)
+	}
+}
+
+// leave queues a client for unregistration unless the hub has stopped
+func (h *Hub) leave(c *Client) {
+	select {
+	case h.unregister <- c:
+	case <-h.done:
+	}
+}
+
+// submit queues a message for broadcast unless the hub has stopped
+func (h *Hub) submit(msg RoomMessage) {
+	select {
+	case h.broadcast <- msg:
+	case <-h.done:
+	}
+}
+
+// notify queues a message for one client unless the hub has stopped
+func (h *Hub) notify(c *Client, msg RoomMessage) {
+	select {
+	case h.direct <- directMessage{client: c, msg: msg}:
+	case <-h.done:
+	}
+}
+
+// sendDirect delivers a message to one client if it is still registered.
+// Its send channel is closed on unregister, so only Run may write to it.
+func (h *Hub) sendDirect(c *Client, msg RoomMessage) {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	if !h.rooms[c.roomID][c] {
+		return
+	}
+	select {
+	case c.send <- msg:
+	default:
+		// Direct frames are advisory (errors, replays the client can reload);
+		// drop instead of disconnecting
+	}
+}
+
+// resume sends c the room's messages newer than lastID in one replay frame.
+// It runs on the client's read goroutine, after registration: anything
+// broadcast since then reached c live and is already queued ahead of the
+// replay, which may repeat it, so clients de-duplicate by ID. A gap longer
+// than maxReplayMessages, or a lastID the room does not know, gets a resync
+// frame instead and the client reloads recent history.
+func (h *Hub) resume(c *Client, lastID string) {
+	if h.chatRepo == nil || lastID == "" {
+		return
+	}
+
+	page, err := h.chatRepo.GetMessagesAfter(context.Background(), c.roomID, lastID, maxReplayMessages)
+	if err != nil {
+		logger.Errorf("Failed to load messages to replay for %s in room %s: %v", c.username, c.roomID, err)
+	}
+	if err != nil || page == nil || page.HasMore {
+		resync := NewRoomMessage("", "", "", TypeResync)
+		resync.RoomID = c.roomID
+		h.notify(c, resync)
+		return
+	}
+	if len(page.Messages) == 0 {
+		return
+	}
+
+	replay := NewRoomMessage("", "", "", TypeReplay)
+	replay.RoomID = c.roomID
+	replay.Messages = make([]RoomMessage, 0, len(page.Messages))
+	for _, m := range page.Messages {
+		msg := NewRoomMessage(m.UserID, m.Username, m.Content, TypeMessage)
+		msg.ID = m.ID
+		msg.RoomID = m.RoomID
+		msg.Timestamp = m.CreatedAt.Unix()
+		replay.Messages = append(replay.Messages, msg)
+	}
+	logger.WebSocket("REPLAY", c.roomID, c.userID, fmt.Sprintf("%d messages after %s", len(replay.Messages), lastID))
+	h.notify(c, replay)
+}
+
 func (h *Hub) registerClient(c *Client) {
 	h.mu.Lock()
 	if _, exists := h.rooms[c.roomID]; !exists {
@@ -82,40 +258,133 @@ func (h *Hub) registerClient(c *Client) {
 	// Protocol trace logging
 	logger.WebSocket("JOIN", c.roomID, c.userID, c.username+" connected")
 
-	joinNotice := NewRoomMessage(c.userID, c.username, c.username+" joined the chat", "join")
+	joinNotice := NewRoomMessage(c.userID, c.username, c.username+" joined the chat", TypeJoin)
 	h.broadcastToRoom(c.roomID, joinNotice)
+	h.broadcastPresence(c.roomID)
 }
 
+// unregisterClient removes a client and always tells the room it left,
+// so member lists never keep stale users
 func (h *Hub) unregisterClient(c *Client) {
 	h.mu.Lock()
-	if room, exists := h.rooms[c.roomID]; exists {
-		if _, ok := room[c]; ok {
-			delete(room, c)
-			close(c.send)
+	room, exists := h.rooms[c.roomID]
+	if !exists {
+		h.mu.Unlock()
+		return
+	}
+	if _, ok := room[c]; !ok {
+		h.mu.Unlock()
+		return
+	}
+	delete(room, c)
+	close(c.send)
+	empty := len(room) == 0
+	if empty {
+		delete(h.rooms, c.roomID)
+	}
+	h.mu.Unlock()
+
+	// Protocol trace logging
+	logger.WebSocket("LEAVE", c.roomID, c.userID, c.username+" disconnected")
 
-			// Protocol trace logging
-			logger.WebSocket("LEAVE", c.roomID, c.userID, c.username+" disconnected")
+	if !h.hasUser(c.roomID, c.userID) {
+		delete(h.typing, typingKey(c.roomID, c.userID))
+	}
+	if empty {
+		logger.Infof("Room %s is now empty", c.roomID)
+		return
+	}
 
-			leaveNotice := NewRoomMessage(c.userID, c.username, c.username+" left the chat", "leave")
-			h.mu.Unlock()
-			h.broadcastToRoom(c.roomID, leaveNotice)
-			h.mu.Lock()
+	leaveNotice := NewRoomMessage(c.userID, c.username, c.username+" left the chat", TypeLeave)
+	h.broadcastToRoom(c.roomID, leaveNotice)
+	h.broadcastPresence(c.roomID)
+}
 
-			if len(room) == 0 {
-				delete(h.rooms, c.roomID)
-				logger.Infof("Room %s is now empty", c.roomID)
-			}
+// broadcastPresence sends the current member list to everyone in the room
+func (h *Hub) broadcastPresence(roomID string) {
+	presence := NewRoomMessage("", "", "", TypePresence)
+	presence.RoomID = roomID
+	presence.Members = h.roomMembers(roomID)
+	h.broadcastToRoom(roomID, presence)
+}
+
+// broadcastTyping forwards a typing event to the other members of the room,
+// at most once per typingDebounce per user. Reports whether it was forwarded.
+func (h *Hub) broadcastTyping(msg RoomMessage) bool {
+	key := typingKey(msg.RoomID, msg.UserID)
+	if last, ok := h.typing[key]; ok && time.Since(last) < typingDebounce {
+		return false
+	}
+	h.typing[key] = time.Now()
+
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.rooms[msg.RoomID] {
+		if client.userID == msg.UserID {
+			continue
+		}
+		select {
+		case client.send <- msg:
+		default:
+			// Typing events are ephemeral; drop instead of disconnecting
 		}
 	}
-	h.mu.Unlock()
+	return true
+}
+
+// roomMembers returns the distinct usernames in a room, sorted
+func (h *Hub) roomMembers(roomID string) []string {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	seen := make(map[string]bool)
+	members := []string{}
+	for client := range h.rooms[roomID] {
+		if !seen[client.username] {
+			seen[client.username] = true
+			members = append(members, client.username)
+		}
+	}
+	sort.Strings(members)
+	return members
+}
+
+// hasUser reports whether a user still has a connection in the room
+func (h *Hub) hasUser(roomID, userID string) bool {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	for client := range h.rooms[roomID] {
+		if client.userID == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func typingKey(roomID, userID string) string {
+	return roomID + "|" + userID
 }
 
 func (h *Hub) broadcastMessage(msg RoomMessage) {
+	if msg.ID == "" {
+		msg.ID = uuid.New().String()
+	}
+
+	if msg.Type == TypeTyping {
+		if h.broadcastTyping(msg) {
+			h.publish(msg)
+		}
+		return
+	}
+	h.publish(msg)
+
 	// Persist message to database if repository is configured
 	// Chỉ lưu message type "message", không lưu join/leave notifications
-	if h.chatRepo != nil && msg.Type == "message" {
+	if h.chatRepo != nil && msg.Type == TypeMessage {
 		chatMsg := &chat.Message{
-			ID:        uuid.New().String(),
+			ID:        msg.ID,
 			RoomID:    msg.RoomID,
 			UserID:    msg.UserID,
 			Username:  msg.Username,
@@ -126,6 +395,9 @@ func (h *Hub) broadcastMessage(msg RoomMessage) {
 			CreatedAt: time.Now(),
 			UpdatedAt: time.Now(),
 		}
+		if err := h.chatRepo.EnsureRoom(context.Background(), msg.RoomID, msg.UserID); err != nil {
+			logger.Errorf("Failed to create chat room %s: %v", msg.RoomID, err)
+		}
 		if err := h.chatRepo.SaveMessage(context.Background(), chatMsg); err != nil {
 			logger.Errorf("Failed to persist chat message: %v", err)
 			// Continue broadcasting even if persistence fails
@@ -142,11 +414,9 @@ func (h *Hub) broadcastMessage(msg RoomMessage) {
 			select {
 			case client.send <- msg:
 			default:
+				// unregisterClient closes the send channel
 				logger.Warnf("Client %s send buffer full, closing connection", client.username)
-				close(client.send)
-				go func(c *Client) {
-					h.unregister <- c
-				}(client)
+				go h.leave(client)
 			}
 		}
 	}
@@ -161,11 +431,9 @@ func (h *Hub) broadcastToRoom(roomID string, msg RoomMessage) {
 			select {
 			case client.send <- msg:
 			default:
+				// unregisterClient closes the send channel
 				logger.Warnf("Client %s send buffer full, closing connection", client.username)
-				close(client.send)
-				go func(c *Client) {
-					h.unregister <- c
-				}(client)
+				go h.leave(client)
 			}
 		}
 	}
@@ -184,6 +452,86 @@ func (h *Hub) GetRoomClients(roomID string) []string {
 	return clients
 }
 
+// GetRoomMembers returns one page of a room's distinct usernames, sorted,
+// and how many there are in total
+func (h *Hub) GetRoomMembers(roomID string, offset, limit int) ([]string, int) {
+	members := h.roomMembers(roomID)
+	total := len(members)
+	if offset >= total {
+		return []string{}, total
+	}
+	return members[offset:min(offset+limit, total)], total
+}
+
+// RoomFilter selects rooms for the directory
+type RoomFilter struct {
+	Type            string // general, manga, or "" for both
+	IncludeInactive bool   // include rooms marked is_active = 0
+	HideEmpty       bool   // leave out rooms nobody is connected to
+	Page            int    // 1-based
+	Limit           int
+}
+
+// ListRooms returns one page of the rooms directory with live member counts,
+// busiest rooms first. Counts cover this instance's connections only.
+// Without persistence there is no directory and the page is empty.
+func (h *Hub) ListRooms(ctx context.Context, f RoomFilter) (*chat.RoomPage, error) {
+	page := &chat.RoomPage{Rooms: []chat.RoomSummary{}, Page: f.Page, Limit: f.Limit}
+	if h.chatRepo == nil {
+		return page, nil
+	}
+
+	rooms, err := h.chatRepo.ListRooms(ctx, f.Type, f.IncludeInactive)
+	if err != nil {
+		return nil, err
+	}
+
+	summaries := make([]chat.RoomSummary, 0, len(rooms))
+	for _, room := range rooms {
+		count := len(h.roomMembers(room.ID))
+		if f.HideEmpty && count == 0 {
+			continue
+		}
+		summaries = append(summaries, chat.RoomSummary{Room: room, MemberCount: count})
+	}
+	// Stable so rooms with the same count keep their oldest-first order
+	sort.SliceStable(summaries, func(i, j int) bool {
+		return summaries[i].MemberCount > summaries[j].MemberCount
+	})
+
+	page.Total = len(summaries)
+	offset := (f.Page - 1) * f.Limit
+	if offset < page.Total {
+		page.Rooms = summaries[offset:min(offset+f.Limit, page.Total)]
+	}
+	page.HasMore = offset+len(page.Rooms) < page.Total
+	return page, nil
+}
+
+// OpenMangaRoom returns the manga's chat room, creating it the first time
+// someone opens it. Without persistence the room only lives in the hub.
+func (h *Hub) OpenMangaRoom(ctx context.Context, mangaID, userID string) (*chat.Room, error) {
+	if h.chatRepo == nil {
+		return &chat.Room{
+			ID:       "manga_" + mangaID,
+			Name:     "manga_" + mangaID,
+			RoomType: "manga",
+			MangaID:  &mangaID,
+			OwnerID:  userID,
+			IsActive: true,
+		}, nil
+	}
+
+	room, err := h.chatRepo.GetOrCreateMangaRoom(ctx, mangaID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if room == nil {
+		return nil, apperrors.NotFound("manga not found", models.ErrMangaNotFound)
+	}
+	return room, nil
+}
+
 // GetRoomHistory retrieves message history for a room
 // Được gọi khi user join room để load tin nhắn cũ
 func (h *Hub) GetRoomHistory(ctx context.Context, roomID string, limit, offset int) (*chat.MessageListResponse, error) {
@@ -211,6 +559,67 @@ func (h *Hub) GetRoomHistory(ctx context.Context, roomID string, limit, offset i
 	}, nil
 }
 
+// GetRoomMessages returns one page of history older than beforeID
+// Empty beforeID returns the latest page; without persistence the page is empty
+func (h *Hub) GetRoomMessages(ctx context.Context, roomID, beforeID string, limit int) (*chat.MessagePage, error) {
+	if h.chatRepo == nil {
+		return &chat.MessagePage{Messages: []chat.Message{}}, nil
+	}
+	return h.chatRepo.GetMessages(ctx, roomID, beforeID, limit)
+}
+
+// GetRoomMessagesAfter returns up to limit messages newer than afterID, oldest
+// first; has_more means newer ones remain. Without persistence the page is empty.
+func (h *Hub) GetRoomMessagesAfter(ctx context.Context, roomID, afterID string, limit int) (*chat.MessagePage, error) {
+	if h.chatRepo == nil {
+		return &chat.MessagePage{Messages: []chat.Message{}}, nil
+	}
+	page, err := h.chatRepo.GetMessagesAfter(ctx, roomID, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	if page == nil {
+		return nil, apperrors.NotFound("message not found in this room", nil)
+	}
+	return page, nil
+}
+
+// MarkRoomRead moves the user's read position in a room up to messageID (the
+// newest message when empty). Without persistence there is nothing to track.
+func (h *Hub) MarkRoomRead(ctx context.Context, roomID, userID, messageID string) error {
+	if h.chatRepo == nil {
+		return nil
+	}
+	ok, err := h.chatRepo.MarkRead(ctx, roomID, userID, messageID)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		if messageID != "" {
+			return apperrors.NotFound("message not found in this room", nil)
+		}
+		return apperrors.NotFound("room not found", nil)
+	}
+	return nil
+}
+
+// UnreadCounts returns the user's unread message count per room they have read
+func (h *Hub) UnreadCounts(ctx context.Context, userID string) ([]chat.RoomUnread, error) {
+	if h.chatRepo == nil {
+		return []chat.RoomUnread{}, nil
+	}
+	return h.chatRepo.UnreadCounts(ctx, userID)
+}
+
+// Stop closes every client connection with a close frame and waits for Run
+// to return. Safe to call more than once, and before Run has started (a
+// later Run then returns at once).
 func (h *Hub) Stop() {
-	close(h.stop)
+	h.stopOnce.Do(func() {
+		close(h.stop)
+	})
+	if !h.started.Load() {
+		return
+	}
+	<-h.done
 }
@@ -0,0 +1,325 @@
+// Package websocket - Hub Tests
+// Unit tests cho graceful shutdown, presence, typing indicators, message limits và resume
+package websocket
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"go.uber.org/goleak"
+	"mangahub/internal/auth"
+	"mangahub/internal/chat"
+	"mangahub/pkg/models"
+)
+
+// newTestServer serves ServeWS; the ?user= query param picks the authenticated user
+func newTestServer(hub *Hub) *httptest.Server {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		name := c.DefaultQuery("user", "reader")
+		c.Set(auth.ContextUserKey, &models.UserProfile{ID: "id-" + name, Username: name})
+		c.Next()
+	})
+	router.GET("/ws/chat", NewHandler(hub).ServeWS)
+	return httptest.NewServer(router)
+}
+
+func TestHubStopClosesClientsWithoutLeaks(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	hub := NewHub()
+	go hub.Run()
+
+	srv := newTestServer(hub)
+	defer srv.Close()
+
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws/chat?room_id=room-1"
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	defer conn.Close()
+
+	// Wait until the hub has registered the client
+	deadline := time.Now().Add(2 * time.Second)
+	for len(hub.GetRoomClients("room-1")) == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("client was never registered")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	hub.Stop()
+	// A second Stop must not panic or block
+	hub.Stop()
+
+	// Drain join/system messages until the close frame arrives
+	conn.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		_, _, err := conn.ReadMessage()
+		if err == nil {
+			continue
+		}
+		if !websocket.IsCloseError(err, websocket.CloseGoingAway) {
+			t.Fatalf("expected going-away close frame, got %v", err)
+		}
+		break
+	}
+}
+
+func TestHubStopBeforeRun(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	hub := NewHub()
+	stopped := make(chan struct{})
+	go func() {
+		hub.Stop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Stop blocked on a hub that never ran")
+	}
+
+	// A Run started afterwards sees the stop and returns
+	ran := make(chan struct{})
+	go func() {
+		hub.Run()
+		close(ran)
+	}()
+	select {
+	case <-ran:
+	case <-time.After(2 * time.Second):
+		t.Fatal("Run kept going after Stop")
+	}
+}
+
+func dialRoom(t *testing.T, srv *httptest.Server, user string) *websocket.Conn {
+	url := "ws" + strings.TrimPrefix(srv.URL, "http") + "/ws/chat?room_id=room-1&user=" + user
+	conn, _, err := websocket.DefaultDialer.Dial(url, nil)
+	if err != nil {
+		t.Fatalf("dial failed: %v", err)
+	}
+	return conn
+}
+
+// readUntil reads messages until match returns true or the timeout expires
+func readUntil(t *testing.T, conn *websocket.Conn, timeout time.Duration, match func(RoomMessage) bool) RoomMessage {
+	t.Helper()
+	conn.SetReadDeadline(time.Now().Add(timeout))
+	for {
+		var msg RoomMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			t.Fatalf("did not receive expected message: %v", err)
+		}
+		if match(msg) {
+			return msg
+		}
+	}
+}
+
+func TestHubPresenceAndTyping(t *testing.T) {
+	hub := NewHub()
+	go hub.Run()
+	defer hub.Stop()
+
+	srv := newTestServer(hub)
+	defer srv.Close()
+
+	alice := dialRoom(t, srv, "alice")
+	defer alice.Close()
+	readUntil(t, alice, 2*time.Second, func(m RoomMessage) bool { return m.Type == TypePresence })
+
+	bob := dialRoom(t, srv, "bob")
+	presence := readUntil(t, alice, 2*time.Second, func(m RoomMessage) bool {
+		return m.Type == TypePresence && len(m.Members) == 2
+	})
+	if presence.Members[0] != "alice" || presence.Members[1] != "bob" {
+		t.Errorf("unexpected members: %v", presence.Members)
+	}
+
+	// Bursts of typing events are debounced to one per user.
+	// The hub preserves order, so every forwarded typing event arrives before the message.
+	for i := 0; i < 3; i++ {
+		if err := bob.WriteJSON(map[string]string{"type": TypeTyping}); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	if err := bob.WriteJSON(map[string]string{"content": "hello"}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+	typingEvents := 0
+	readUntil(t, alice, 2*time.Second, func(m RoomMessage) bool {
+		if m.Type == TypeTyping {
+			if m.Username != "bob" {
+				t.Errorf("expected bob to be typing, got %q", m.Username)
+			}
+			typingEvents++
+		}
+		return m.Type == TypeMessage
+	})
+	if typingEvents != 1 {
+		t.Errorf("expected 1 typing event after debounce, got %d", typingEvents)
+	}
+
+	// Disconnect always produces a leave and an updated member list
+	bob.Close()
+	readUntil(t, alice, 2*time.Second, func(m RoomMessage) bool { return m.Type == TypeLeave && m.Username == "bob" })
+	presence = readUntil(t, alice, 2*time.Second, func(m RoomMessage) bool { return m.Type == TypePresence })
+	if len(presence.Members) != 1 || presence.Members[0] != "alice" {
+		t.Errorf("expected only alice after bob left, got %v", presence.Members)
+	}
+}
+
+func TestClientMessageLimits(t *testing.T) {
+	hub := NewHub()
+	hub.SetLimits(Limits{MaxFrameSize: 4096, MaxContentLength: 10, RateMessages: 2, RateWindow: time.Hour, MaxViolations: 2})
+	go hub.Run()
+	defer hub.Stop()
+
+	srv := newTestServer(hub)
+	defer srv.Close()
+
+	alice := dialRoom(t, srv, "alice")
+	defer alice.Close()
+	bob := dialRoom(t, srv, "bob")
+	defer bob.Close()
+	readUntil(t, alice, 2*time.Second, func(m RoomMessage) bool {
+		return m.Type == TypePresence && len(m.Members) == 2
+	})
+
+	send := func(content string) {
+		if err := bob.WriteJSON(map[string]string{"content": content}); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+	}
+	isChat := func(m RoomMessage) bool { return m.Type == TypeMessage || m.Type == TypeError }
+
+	// Control characters are stripped; an oversized message is rejected whole
+	send("a\x07b")
+	send("0123456789x")
+	if m := readUntil(t, bob, 2*time.Second, isChat); m.Content != "ab" {
+		t.Errorf("first message = %+v, want sanitized \"ab\"", m)
+	}
+	if m := readUntil(t, bob, 2*time.Second, isChat); m.Type != TypeError || !strings.Contains(m.Content, "too long") {
+		t.Errorf("expected a too-long error frame, got %+v", m)
+	}
+
+	// An accepted message resets the violation count; the bucket then runs dry
+	send("c")
+	send("d")
+	if m := readUntil(t, bob, 2*time.Second, isChat); m.Content != "c" {
+		t.Errorf("expected \"c\" to be accepted, got %+v", m)
+	}
+	if m := readUntil(t, bob, 2*time.Second, isChat); m.Type != TypeError || !strings.Contains(m.Content, "too fast") {
+		t.Errorf("expected a rate limit error frame, got %+v", m)
+	}
+
+	// A second violation in a row disconnects with a policy violation
+	send("e")
+	bob.SetReadDeadline(time.Now().Add(2 * time.Second))
+	for {
+		var m RoomMessage
+		err := bob.ReadJSON(&m)
+		if err == nil {
+			continue
+		}
+		if !websocket.IsCloseError(err, websocket.ClosePolicyViolation) {
+			t.Fatalf("expected policy violation close, got %v", err)
+		}
+		break
+	}
+
+	// Alice only ever saw the accepted messages
+	var got []string
+	readUntil(t, alice, 2*time.Second, func(m RoomMessage) bool {
+		if m.Type == TypeMessage {
+			got = append(got, m.Content)
+		}
+		if m.Type == TypeError {
+			t.Errorf("alice received bob's error frame: %+v", m)
+		}
+		return m.Type == TypeLeave
+	})
+	if strings.Join(got, ",") != "ab,c" {
+		t.Errorf("alice received %v, want [ab c]", got)
+	}
+}
+
+// replayRepo serves stored history to resume; the test never sends a chat
+// message, so the embedded (nil) repository is never called
+type replayRepo struct {
+	chat.Repository
+	messages []chat.Message
+}
+
+func (r *replayRepo) GetMessagesAfter(ctx context.Context, roomID, afterID string, limit int) (*chat.MessagePage, error) {
+	for i, m := range r.messages {
+		if m.ID == afterID {
+			newer := r.messages[i+1:]
+			page := &chat.MessagePage{Messages: newer, HasMore: len(newer) > limit}
+			if page.HasMore {
+				page.Messages = newer[:limit]
+			}
+			return page, nil
+		}
+	}
+	return nil, nil
+}
+
+func TestResumeReplaysMissedMessages(t *testing.T) {
+	repo := &replayRepo{}
+	base := time.Now().Add(-time.Hour)
+	for i := 0; i < maxReplayMessages+2; i++ {
+		repo.messages = append(repo.messages, chat.Message{
+			ID: fmt.Sprintf("m%03d", i), RoomID: "room-1", UserID: "id-bob", Username: "bob",
+			Content: fmt.Sprintf("msg %d", i), CreatedAt: base.Add(time.Duration(i) * time.Second),
+		})
+	}
+
+	hub := NewHub()
+	hub.SetChatRepository(repo)
+	go hub.Run()
+	defer hub.Stop()
+
+	srv := newTestServer(hub)
+	defer srv.Close()
+
+	alice := dialRoom(t, srv, "alice")
+	defer alice.Close()
+	resume := func(lastID string) RoomMessage {
+		t.Helper()
+		if err := alice.WriteJSON(map[string]string{"type": TypeResume, "last_id": lastID}); err != nil {
+			t.Fatalf("write failed: %v", err)
+		}
+		return readUntil(t, alice, 2*time.Second, func(m RoomMessage) bool {
+			return m.Type == TypeReplay || m.Type == TypeResync
+		})
+	}
+
+	// A short gap is replayed in one frame, oldest first
+	last := repo.messages[len(repo.messages)-3].ID
+	replay := resume(last)
+	if replay.Type != TypeReplay || len(replay.Messages) != 2 {
+		t.Fatalf("resume after %s = %+v, want a replay of 2 messages", last, replay)
+	}
+	if m := replay.Messages[0]; m.ID != repo.messages[len(repo.messages)-2].ID || m.Type != TypeMessage || m.Content == "" {
+		t.Errorf("first replayed message = %+v", m)
+	}
+
+	// Too long a gap, or an ID the room does not know, asks for a history reload
+	if m := resume(repo.messages[0].ID); m.Type != TypeResync || len(m.Messages) != 0 {
+		t.Errorf("resume over %d messages = %+v, want resync", maxReplayMessages+1, m)
+	}
+	if m := resume("unknown"); m.Type != TypeResync {
+		t.Errorf("resume from an unknown ID = %+v, want resync", m)
+	}
+}
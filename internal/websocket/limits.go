@@ -0,0 +1,104 @@
+// Package websocket - Chat Message Limits
+// Giới hạn tin nhắn client gửi lên hub
+// Chức năng:
+//   - Token bucket mỗi connection: cho phép burst ngắn, chặn spam kéo dài
+//   - Giới hạn độ dài content (tính theo ký tự, không phải byte)
+//   - Loại bỏ ký tự điều khiển và UTF-8 không hợp lệ
+//   - Ngắt kết nối client vi phạm liên tục
+package websocket
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// Limits bounds what one connection may send. Zero fields disable that limit.
+type Limits struct {
+	// MaxFrameSize is the largest frame read from a client, in bytes;
+	// larger frames close the connection
+	MaxFrameSize int64
+	// MaxContentLength is the longest accepted message, in characters
+	MaxContentLength int
+	// RateMessages messages are allowed per RateWindow, in bursts of up to RateMessages
+	RateMessages int
+	RateWindow   time.Duration
+	// MaxViolations consecutive rejected messages disconnect the client
+	MaxViolations int
+}
+
+// DefaultLimits are used when the hub is not given any
+func DefaultLimits() Limits {
+	return Limits{
+		MaxFrameSize:     512000,
+		MaxContentLength: 1000,
+		RateMessages:     10,
+		RateWindow:       10 * time.Second,
+		MaxViolations:    5,
+	}
+}
+
+// rateLimiter is a token bucket holding up to capacity tokens,
+// refilled continuously at capacity per window. Not safe for concurrent use.
+type rateLimiter struct {
+	capacity float64
+	perSec   float64
+	tokens   float64
+	last     time.Time
+}
+
+// newRateLimiter returns nil (no limit) unless both messages and window are positive
+func newRateLimiter(messages int, window time.Duration) *rateLimiter {
+	if messages <= 0 || window <= 0 {
+		return nil
+	}
+	return &rateLimiter{
+		capacity: float64(messages),
+		perSec:   float64(messages) / window.Seconds(),
+		tokens:   float64(messages),
+	}
+}
+
+// allow takes a token if one is available at now
+func (r *rateLimiter) allow(now time.Time) bool {
+	if r == nil {
+		return true
+	}
+	if !r.last.IsZero() {
+		r.tokens = min(r.capacity, r.tokens+now.Sub(r.last).Seconds()*r.perSec)
+	}
+	r.last = now
+	if r.tokens < 1 {
+		return false
+	}
+	r.tokens--
+	return true
+}
+
+// sanitizeContent drops invalid UTF-8 and control characters other than
+// newline and tab, then trims surrounding whitespace
+func sanitizeContent(s string) string {
+	s = strings.ToValidUTF8(s, "")
+	s = strings.Map(func(r rune) rune {
+		if r != '\n' && r != '\t' && unicode.IsControl(r) {
+			return -1
+		}
+		return r
+	}, s)
+	return strings.TrimSpace(s)
+}
+
+// checkContent returns the cleaned content and why it is rejected, if it is.
+// Oversized messages are rejected whole, never truncated.
+func (l Limits) checkContent(raw string) (content, reason string) {
+	content = sanitizeContent(raw)
+	if content == "" {
+		return "", ""
+	}
+	if l.MaxContentLength > 0 && utf8.RuneCountInString(content) > l.MaxContentLength {
+		return "", fmt.Sprintf("message too long (max %d characters)", l.MaxContentLength)
+	}
+	return content, ""
+}
@@ -2,6 +2,19 @@ package websocket
 
 import "time"
 
+// Message types exchanged over the chat socket
+const (
+	TypeMessage  = "message"
+	TypeJoin     = "join"
+	TypeLeave    = "leave"
+	TypeTyping   = "typing"   // client → server → other room members
+	TypePresence = "presence" // server → room, carries Members
+	TypeError    = "error"    // server → one client, Content says what was rejected
+	TypeResume   = "resume"   // client → server after a reconnect, carries LastID
+	TypeReplay   = "replay"   // server → one client, Messages missed since LastID
+	TypeResync   = "resync"   // server → one client, gap too long: reload recent history
+)
+
 type ChatMessage struct {
 	UserID    string `json:"user_id"`
 	Username  string `json:"username"`
@@ -11,13 +24,17 @@ type ChatMessage struct {
 }
 
 type RoomMessage struct {
-	UserID    string `json:"user_id"`
-	Username  string `json:"username"`
-	Message   string `json:"message"` // For internal use
-	Content   string `json:"content"` // For JSON serialization (same as Message)
-	Timestamp int64  `json:"timestamp"`
-	Type      string `json:"type"` // message, join, leave
-	RoomID    string `json:"room_id,omitempty"`
+	ID        string   `json:"id,omitempty"` // set on broadcast; de-duplicates relayed copies
+	UserID    string   `json:"user_id"`
+	Username  string   `json:"username"`
+	Message   string   `json:"message"` // For internal use
+	Content   string   `json:"content"` // For JSON serialization (same as Message)
+	Timestamp int64    `json:"timestamp"`
+	Type      string   `json:"type"` // message, join, leave, typing, presence
+	RoomID    string   `json:"room_id,omitempty"`
+	Members   []string `json:"members,omitempty"` // presence only
+
+	Messages []RoomMessage `json:"messages,omitempty"` // replay only, oldest first
 }
 
 func NewRoomMessage(userID, username, message, msgType string) RoomMessage {
@@ -0,0 +1,156 @@
+// Package websocket - Cross-Instance Relay
+// Pub/sub giữa nhiều api-server để user ở instance khác nhau vẫn chat được
+// Cách làm:
+//   - Message broadcast local cũng được publish lên channel chat:<room>
+//   - Mỗi hub subscribe chat:* và broadcast lại message của instance khác
+//   - Bỏ qua message do chính instance gửi (client local đã nhận rồi)
+//   - De-duplicate theo message id nếu broker giao lại
+package websocket
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"mangahub/pkg/logger"
+)
+
+// Relay is a pub/sub broker shared by every hub instance (Redis in production)
+type Relay interface {
+	// Publish sends payload to every subscriber of channel
+	Publish(ctx context.Context, channel string, payload []byte) error
+
+	// Subscribe delivers payloads from channels matching pattern until ctx is done
+	Subscribe(ctx context.Context, pattern string) (<-chan []byte, error)
+}
+
+const (
+	relayChannelPrefix = "chat:"
+	relayRetryDelay    = 2 * time.Second
+	relayPublishWait   = 2 * time.Second
+
+	// relayDedupeWindow is how long a relayed message id is remembered
+	relayDedupeWindow = time.Minute
+)
+
+// relayEnvelope is what goes over the broker
+type relayEnvelope struct {
+	Origin  string      `json:"origin"` // instance id of the publishing hub
+	Message RoomMessage `json:"message"`
+}
+
+// SetRelay enables cross-instance broadcasting. Call before Run.
+func (h *Hub) SetRelay(relay Relay) {
+	h.relay = relay
+	logger.Infof("Chat relay enabled (instance %s)", h.instanceID)
+}
+
+// relayed reports whether a message should be shared with other instances.
+// Join, leave and presence describe this instance's own connections.
+func relayed(msg RoomMessage) bool {
+	return msg.Type == TypeMessage || msg.Type == TypeTyping
+}
+
+// publish queues a locally originated message for the other instances
+func (h *Hub) publish(msg RoomMessage) {
+	if h.relay == nil || !relayed(msg) {
+		return
+	}
+	select {
+	case h.outbound <- msg:
+	default:
+		logger.Warnf("Chat relay queue full, message %s not shared with other instances", msg.ID)
+	}
+}
+
+// runRelay publishes queued messages and forwards remote ones into Run,
+// until ctx is cancelled
+func (h *Hub) runRelay(ctx context.Context) {
+	go h.publishLoop(ctx)
+
+	for {
+		payloads, err := h.relay.Subscribe(ctx, relayChannelPrefix+"*")
+		if err != nil {
+			logger.Warnf("Chat relay subscribe failed, retrying: %v", err)
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(relayRetryDelay):
+				continue
+			}
+		}
+
+		for payload := range payloads {
+			var env relayEnvelope
+			if err := json.Unmarshal(payload, &env); err != nil {
+				logger.Warnf("Dropping malformed chat relay payload: %v", err)
+				continue
+			}
+			// Local clients already got our own messages from broadcastMessage
+			if env.Origin == h.instanceID {
+				continue
+			}
+			select {
+			case h.remote <- env.Message:
+			case <-ctx.Done():
+				return
+			}
+		}
+		if ctx.Err() != nil {
+			return
+		}
+	}
+}
+
+// publishLoop sends queued messages in order on a single goroutine
+func (h *Hub) publishLoop(ctx context.Context) {
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case msg := <-h.outbound:
+			payload, err := json.Marshal(relayEnvelope{Origin: h.instanceID, Message: msg})
+			if err != nil {
+				logger.Errorf("Failed to encode chat relay message: %v", err)
+				continue
+			}
+			pubCtx, cancel := context.WithTimeout(ctx, relayPublishWait)
+			if err := h.relay.Publish(pubCtx, relayChannelPrefix+msg.RoomID, payload); err != nil {
+				logger.Warnf("Chat relay publish failed for room %s: %v", msg.RoomID, err)
+			}
+			cancel()
+		}
+	}
+}
+
+// deliverRemote broadcasts a message from another instance to local clients.
+// It is not persisted or republished; the origin instance did both.
+// Only called from Run.
+func (h *Hub) deliverRemote(msg RoomMessage) {
+	now := time.Now()
+	if msg.ID != "" {
+		if seenAt, dup := h.relaySeen[msg.ID]; dup && now.Sub(seenAt) < relayDedupeWindow {
+			return
+		}
+		h.relaySeen[msg.ID] = now
+		h.pruneRelaySeen(now)
+	}
+
+	if msg.Type == TypeTyping {
+		h.broadcastTyping(msg)
+		return
+	}
+	h.broadcastToRoom(msg.RoomID, msg)
+}
+
+// pruneRelaySeen forgets ids older than the dedupe window once the set grows
+func (h *Hub) pruneRelaySeen(now time.Time) {
+	if len(h.relaySeen) < 1024 {
+		return
+	}
+	for id, seenAt := range h.relaySeen {
+		if now.Sub(seenAt) >= relayDedupeWindow {
+			delete(h.relaySeen, id)
+		}
+	}
+}
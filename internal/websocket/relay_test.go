@@ -0,0 +1,115 @@
+// Package websocket - Relay Tests
+// Unit tests cho chat giữa hai hub qua pub/sub, không echo và không trùng
+package websocket
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+// memRelay is an in-process broker that delivers every publish twice,
+// like a broker redelivering after a reconnect
+type memRelay struct {
+	mu   sync.Mutex
+	subs []chan []byte
+}
+
+func (r *memRelay) Publish(ctx context.Context, channel string, payload []byte) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, sub := range r.subs {
+		sub <- payload
+		sub <- payload
+	}
+	return nil
+}
+
+func (r *memRelay) Subscribe(ctx context.Context, pattern string) (<-chan []byte, error) {
+	sub := make(chan []byte, 64)
+	r.mu.Lock()
+	r.subs = append(r.subs, sub)
+	r.mu.Unlock()
+
+	out := make(chan []byte)
+	go func() {
+		defer close(out)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case p := <-sub:
+				select {
+				case out <- p:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
+
+func (r *memRelay) subscribers() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return len(r.subs)
+}
+
+// countMessages counts chat messages received until the connection goes quiet
+func countMessages(conn *websocket.Conn, content string) int {
+	n := 0
+	for {
+		conn.SetReadDeadline(time.Now().Add(300 * time.Millisecond))
+		var msg RoomMessage
+		if err := conn.ReadJSON(&msg); err != nil {
+			return n
+		}
+		if msg.Type == TypeMessage && msg.Content == content {
+			n++
+		}
+	}
+}
+
+func TestRelayAcrossHubs(t *testing.T) {
+	relay := &memRelay{}
+	hubs := []*Hub{NewHub(), NewHub()}
+	for _, hub := range hubs {
+		hub.SetRelay(relay)
+		go hub.Run()
+		defer hub.Stop()
+	}
+	for deadline := time.Now().Add(2 * time.Second); relay.subscribers() < 2; {
+		if time.Now().After(deadline) {
+			t.Fatal("hubs never subscribed to the relay")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	srvA, srvB := newTestServer(hubs[0]), newTestServer(hubs[1])
+	defer srvA.Close()
+	defer srvB.Close()
+
+	alice := dialRoom(t, srvA, "alice")
+	defer alice.Close()
+	bob := dialRoom(t, srvB, "bob")
+	defer bob.Close()
+	readUntil(t, alice, 2*time.Second, func(m RoomMessage) bool { return m.Type == TypePresence })
+	readUntil(t, bob, 2*time.Second, func(m RoomMessage) bool { return m.Type == TypePresence })
+
+	if err := bob.WriteJSON(map[string]string{"content": "hello from B"}); err != nil {
+		t.Fatalf("write failed: %v", err)
+	}
+
+	// Alice on the other instance gets it once despite the double delivery,
+	// and bob's own instance does not echo the relayed copy back to him
+	if n := countMessages(alice, "hello from B"); n != 1 {
+		t.Errorf("alice received the message %d times, want 1", n)
+	}
+	if n := countMessages(bob, "hello from B"); n != 1 {
+		t.Errorf("bob received his message %d times, want 1", n)
+	}
+}
@@ -0,0 +1,96 @@
+// Package apperrors - Typed Application Errors
+// Constructor cho models.AppError theo loại lỗi, dùng chung cho mọi service
+// Chức năng:
+//   - NotFound, Validation, Unauthorized, Forbidden, Conflict, Internal
+//   - EmailNotVerified: 403 riêng để client nhắc người dùng xác thực email
+//   - Validation lỗi luôn ghi field bị sai vào details.field
+//   - Respond: handler trả mọi lỗi theo cùng một shape models.APIResponse
+package apperrors
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/go-playground/validator/v10"
+
+	"mangahub/pkg/models"
+)
+
+// NotFound is a 404 NOT_FOUND error; err is usually a models sentinel such as models.ErrMangaNotFound
+func NotFound(message string, err error) *models.AppError {
+	return models.NewAppError(models.ErrCodeNotFound, message, http.StatusNotFound, err)
+}
+
+// Validation is a 400 VALIDATION_ERROR for one request field, named in details.field
+func Validation(field, message string) *models.AppError {
+	appErr := models.NewAppError(models.ErrCodeValidation, message, http.StatusBadRequest, models.ErrInvalidInput)
+	appErr.Details["field"] = field
+	return appErr
+}
+
+// Invalid is a 400 VALIDATION_ERROR from a failed utils.ValidateStruct. The
+// first failing field goes in details.field and every failing field with its
+// rule in details.fields.
+func Invalid(message string, err error) *models.AppError {
+	appErr := models.NewAppError(models.ErrCodeValidation, message, http.StatusBadRequest, err)
+	var fieldErrs validator.ValidationErrors
+	if errors.As(err, &fieldErrs) && len(fieldErrs) > 0 {
+		fields := make(map[string]string, len(fieldErrs))
+		for _, fe := range fieldErrs {
+			fields[fe.Field()] = fe.Tag()
+		}
+		appErr.Details["field"] = fieldErrs[0].Field()
+		appErr.Details["fields"] = fields
+	}
+	return appErr
+}
+
+// BadRequest is a 400 BAD_REQUEST for a body that could not be read at all,
+// e.g. malformed JSON; the decoder's message goes in details.error
+func BadRequest(message string, err error) *models.AppError {
+	appErr := models.NewAppError(models.ErrCodeBadRequest, message, http.StatusBadRequest, err)
+	if err != nil {
+		appErr.Details["error"] = err.Error()
+	}
+	return appErr
+}
+
+// Unauthorized is a 401 UNAUTHORIZED error
+func Unauthorized(message string, err error) *models.AppError {
+	return models.NewAppError(models.ErrCodeUnauthorized, message, http.StatusUnauthorized, err)
+}
+
+// Forbidden is a 403 FORBIDDEN error
+func Forbidden(message string, err error) *models.AppError {
+	return models.NewAppError(models.ErrCodeForbidden, message, http.StatusForbidden, err)
+}
+
+// EmailNotVerified is a 403 EMAIL_NOT_VERIFIED error for actions that need a
+// verified email address
+func EmailNotVerified(message string) *models.AppError {
+	return models.NewAppError(models.ErrCodeEmailNotVerified, message, http.StatusForbidden, models.ErrEmailNotVerified)
+}
+
+// Conflict is a 409 CONFLICT error
+func Conflict(message string, err error) *models.AppError {
+	return models.NewAppError(models.ErrCodeConflict, message, http.StatusConflict, err)
+}
+
+// Internal is a 500 INTERNAL_ERROR; err is kept for logs, never sent to clients
+func Internal(message string, err error) *models.AppError {
+	return models.NewAppError(models.ErrCodeInternal, message, http.StatusInternalServerError, err)
+}
+
+// Respond writes err as an error response. AppErrors keep their status, code,
+// message and details; anything else becomes a 500 with the fallback message.
+func Respond(c *gin.Context, err error, fallback string) {
+	var appErr *models.AppError
+	if errors.As(err, &appErr) {
+		c.JSON(appErr.StatusCode,
+			models.NewErrorResponse(appErr.Code, appErr.Message, appErr.Details))
+		return
+	}
+	c.JSON(http.StatusInternalServerError,
+		models.NewErrorResponse(models.ErrCodeInternal, fallback, nil))
+}
@@ -0,0 +1,64 @@
+// Package apperrors - Typed Application Error Tests
+// Unit tests cho field của validation error và shape của Respond
+package apperrors
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+
+	"mangahub/pkg/models"
+	"mangahub/pkg/utils"
+)
+
+func TestInvalidNamesFailingFields(t *testing.T) {
+	err := utils.ValidateStruct(models.RegisterRequest{Username: "ab", Email: "a@example.com", Password: "longenough"})
+	appErr := Invalid("invalid registration data", err)
+
+	if appErr.StatusCode != http.StatusBadRequest || appErr.Code != models.ErrCodeValidation {
+		t.Fatalf("got %d %s, want 400 %s", appErr.StatusCode, appErr.Code, models.ErrCodeValidation)
+	}
+	// JSON names, not Go field names
+	if appErr.Details["field"] != "username" {
+		t.Errorf("field = %v, want username", appErr.Details["field"])
+	}
+	if fields, _ := appErr.Details["fields"].(map[string]string); fields["username"] != "min" {
+		t.Errorf("fields = %v", appErr.Details["fields"])
+	}
+}
+
+func TestRespondKeepsAPIResponseShape(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cases := []struct {
+		err        error
+		wantStatus int
+		wantCode   string
+		wantMsg    string
+	}{
+		{Validation("rating", "rating must be 1-10"), 400, models.ErrCodeValidation, "rating must be 1-10"},
+		{NotFound("manga not found", models.ErrMangaNotFound), 404, models.ErrCodeNotFound, "manga not found"},
+		{Conflict("manga already in list", models.ErrAlreadyInList), 409, models.ErrCodeConflict, "manga already in list"},
+		// Wrapped AppErrors are still found; plain errors never leak their text
+		{errors.Join(errors.New("context"), Unauthorized("invalid token", nil)), 401, models.ErrCodeUnauthorized, "invalid token"},
+		{errors.New("disk I/O error"), 500, models.ErrCodeInternal, "fallback"},
+	}
+	for _, tc := range cases {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		Respond(c, tc.err, "fallback")
+
+		var resp models.APIResponse
+		if err := json.Unmarshal(w.Body.Bytes(), &resp); err != nil {
+			t.Fatalf("decode %s: %v", w.Body.String(), err)
+		}
+		if w.Code != tc.wantStatus || resp.Success || resp.Error == nil ||
+			resp.Error.Code != tc.wantCode || resp.Error.Message != tc.wantMsg {
+			t.Errorf("Respond(%v) = %d %s", tc.err, w.Code, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,144 @@
+// Package cache - Circuit Breaker
+// Ngắt Redis tạm thời khi lỗi liên tiếp, để cache hỏng không làm chậm mọi request
+// Trạng thái:
+//   - closed: gọi Redis bình thường, đếm lỗi liên tiếp
+//   - open: sau N lỗi liên tiếp, mọi lệnh trả ErrCacheDisabled ngay, không chờ timeout
+//   - probe: goroutine Ping mỗi cooldown; Ping thành công thì đóng lại (tự phục hồi)
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"mangahub/pkg/logger"
+)
+
+// ErrCacheDisabled is returned without contacting Redis while the breaker is open.
+// Callers treat the cache as best-effort, so it reads like a cache miss.
+var ErrCacheDisabled = errors.New("cache disabled: redis unavailable")
+
+// Breaker defaults, used when the config leaves them at zero
+const (
+	DefaultBreakerThreshold = 5
+	DefaultBreakerCooldown  = 30 * time.Second
+
+	// probeTimeout bounds each recovery Ping
+	probeTimeout = 2 * time.Second
+)
+
+// breaker trips after threshold consecutive failures and stays open until a
+// background Ping succeeds. It is safe for concurrent use.
+type breaker struct {
+	threshold int
+	cooldown  time.Duration
+	ping      func(ctx context.Context) error
+
+	mu       sync.Mutex
+	failures int  // consecutive failures while closed
+	open     bool // short-circuiting; a probe goroutine is running
+	stop     chan struct{}
+	stopped  bool
+}
+
+func newBreaker(threshold int, cooldown time.Duration, ping func(ctx context.Context) error) *breaker {
+	if threshold <= 0 {
+		threshold = DefaultBreakerThreshold
+	}
+	if cooldown <= 0 {
+		cooldown = DefaultBreakerCooldown
+	}
+	return &breaker{
+		threshold: threshold,
+		cooldown:  cooldown,
+		ping:      ping,
+		stop:      make(chan struct{}),
+	}
+}
+
+// do runs op unless the breaker is open, and records its outcome
+func (b *breaker) do(op func() error) error {
+	if !b.allow() {
+		return ErrCacheDisabled
+	}
+	err := op()
+	b.record(err)
+	return err
+}
+
+// allow reports whether calls may reach Redis
+func (b *breaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return !b.open
+}
+
+// isOpen reports whether the breaker is short-circuiting calls
+func (b *breaker) isOpen() bool {
+	return !b.allow()
+}
+
+// record counts a call's outcome; the threshold-th consecutive failure opens the breaker.
+// A cancelled caller says nothing about Redis, so it is not counted either way.
+func (b *breaker) record(err error) {
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if err == nil {
+		b.failures = 0
+		return
+	}
+	if b.open || b.stopped {
+		return
+	}
+	b.failures++
+	if b.failures < b.threshold {
+		return
+	}
+
+	b.open = true
+	logger.Warnf("Redis failed %d times in a row, disabling cache (probing every %s): %v",
+		b.failures, b.cooldown, err)
+	go b.probe()
+}
+
+// probe pings Redis every cooldown until it answers, then closes the breaker
+func (b *breaker) probe() {
+	ticker := time.NewTicker(b.cooldown)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-b.stop:
+			return
+		case <-ticker.C:
+		}
+
+		ctx, cancel := context.WithTimeout(context.Background(), probeTimeout)
+		err := b.ping(ctx)
+		cancel()
+		if err != nil {
+			continue
+		}
+
+		b.mu.Lock()
+		b.open = false
+		b.failures = 0
+		b.mu.Unlock()
+		logger.Info("Redis is reachable again, cache re-enabled")
+		return
+	}
+}
+
+// close stops a running probe; the breaker stays in its current state
+func (b *breaker) close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.stopped {
+		b.stopped = true
+		close(b.stop)
+	}
+}
@@ -0,0 +1,90 @@
+// Package cache - Circuit Breaker Tests
+// Unit tests cho breaker: mở sau N lỗi, tự đóng khi Ping thành công
+package cache
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestBreakerOpensAndRecovers(t *testing.T) {
+	var redisUp atomic.Bool
+	ping := func(ctx context.Context) error {
+		if redisUp.Load() {
+			return nil
+		}
+		return errors.New("connection refused")
+	}
+	b := newBreaker(3, 10*time.Millisecond, ping)
+	defer b.close()
+
+	down := errors.New("connection refused")
+	calls := 0
+	op := func() error { calls++; return down }
+
+	// A success in between resets the count
+	b.do(op)
+	b.do(op)
+	b.do(func() error { return nil })
+	b.do(op)
+	b.do(op)
+	if b.isOpen() {
+		t.Fatal("breaker opened before 3 consecutive failures")
+	}
+	b.do(op)
+	if !b.isOpen() {
+		t.Fatal("breaker should open after 3 consecutive failures")
+	}
+
+	// Open: calls are short-circuited without reaching Redis
+	before := calls
+	if err := b.do(op); !errors.Is(err, ErrCacheDisabled) {
+		t.Fatalf("expected ErrCacheDisabled, got %v", err)
+	}
+	if calls != before {
+		t.Error("open breaker should not run the operation")
+	}
+
+	// Stays open while Ping fails, closes once it succeeds
+	time.Sleep(30 * time.Millisecond)
+	if !b.isOpen() {
+		t.Fatal("breaker closed while Redis is still down")
+	}
+	redisUp.Store(true)
+	deadline := time.Now().Add(time.Second)
+	for b.isOpen() && time.Now().Before(deadline) {
+		time.Sleep(5 * time.Millisecond)
+	}
+	if b.isOpen() {
+		t.Fatal("breaker did not recover after Ping succeeded")
+	}
+	if err := b.do(func() error { return nil }); err != nil {
+		t.Errorf("closed breaker should run operations, got %v", err)
+	}
+}
+
+func TestBreakerConcurrentUse(t *testing.T) {
+	b := newBreaker(5, time.Hour, func(ctx context.Context) error { return errors.New("down") })
+	defer b.close()
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := 0; i < 100; i++ {
+				b.do(func() error { return errors.New("down") })
+				b.isOpen()
+			}
+		}()
+	}
+	wg.Wait()
+
+	if !b.isOpen() {
+		t.Error("breaker should be open after concurrent failures")
+	}
+}
@@ -0,0 +1,151 @@
+// Package cache - In-Memory Cache
+// Cache implementation trong process, dùng khi không có Redis
+// Chỉ dùng cho một api-server: các instance không thấy cache của nhau
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// memoryItem is a stored value and its expiry (zero means no expiry)
+type memoryItem struct {
+	value     string
+	expiresAt time.Time
+}
+
+func (i memoryItem) expired(now time.Time) bool {
+	return !i.expiresAt.IsZero() && now.After(i.expiresAt)
+}
+
+// MemoryCache implements Cache in process memory.
+// Expired entries are dropped lazily when read and by Set once the map grows.
+type MemoryCache struct {
+	mu    sync.Mutex
+	items map[string]memoryItem
+	now   func() time.Time
+}
+
+// memorySweepSize is how many entries the map holds before Set sweeps expired ones
+const memorySweepSize = 1024
+
+// NewMemoryCache creates an empty in-memory cache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{items: make(map[string]memoryItem), now: time.Now}
+}
+
+// Get retrieves a value by key; missing and expired keys return ""
+func (m *MemoryCache) Get(ctx context.Context, key string) (string, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[key]
+	if !ok {
+		return "", nil
+	}
+	if item.expired(m.now()) {
+		delete(m.items, key)
+		return "", nil
+	}
+	return item.value, nil
+}
+
+// Set stores a value with optional TTL
+func (m *MemoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return m.SetWithTTL(ctx, key, value, ttl)
+}
+
+// SetWithTTL sets a value with specific TTL; values are encoded like RedisCache does
+func (m *MemoryCache) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	var strVal string
+	switch v := value.(type) {
+	case string:
+		strVal = v
+	case []byte:
+		strVal = string(v)
+	default:
+		bytes, err := json.Marshal(value)
+		if err != nil {
+			return fmt.Errorf("failed to marshal value: %w", err)
+		}
+		strVal = string(bytes)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := m.now()
+	if len(m.items) >= memorySweepSize {
+		for k, item := range m.items {
+			if item.expired(now) {
+				delete(m.items, k)
+			}
+		}
+	}
+
+	item := memoryItem{value: strVal}
+	if ttl > 0 {
+		item.expiresAt = now.Add(ttl)
+	}
+	m.items[key] = item
+	return nil
+}
+
+// Delete removes a key
+func (m *MemoryCache) Delete(ctx context.Context, key string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.items, key)
+	return nil
+}
+
+// Exists checks if a key exists
+func (m *MemoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	val, err := m.Get(ctx, key)
+	return val != "", err
+}
+
+// GetTTL returns remaining TTL for a key, following Redis: -1 without expiry, -2 when missing
+func (m *MemoryCache) GetTTL(ctx context.Context, key string) (time.Duration, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[key]
+	now := m.now()
+	switch {
+	case !ok || item.expired(now):
+		return -2, nil
+	case item.expiresAt.IsZero():
+		return -1, nil
+	}
+	return item.expiresAt.Sub(now), nil
+}
+
+// FlushByPrefix removes all keys matching prefix
+func (m *MemoryCache) FlushByPrefix(ctx context.Context, prefix string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k := range m.items {
+		if strings.HasPrefix(k, prefix) {
+			delete(m.items, k)
+		}
+	}
+	return nil
+}
+
+// Close drops every entry
+func (m *MemoryCache) Close() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items = make(map[string]memoryItem)
+	return nil
+}
+
+// Ping always succeeds
+func (m *MemoryCache) Ping(ctx context.Context) error {
+	return nil
+}
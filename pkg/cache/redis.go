@@ -5,12 +5,15 @@
 //   - Session storage
 //   - Rate limiting counters
 //   - Real-time data caching
+//   - Pub/sub relay cho chat giữa nhiều api-server
+//   - Circuit breaker khi Redis chết giữa chừng (breaker.go)
 package cache
 
 import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math/rand"
 	"time"
 
 	"github.com/redis/go-redis/v9"
@@ -47,10 +50,13 @@ type Cache interface {
 	Ping(ctx context.Context) error
 }
 
-// RedisCache implements Cache interface using Redis
+// RedisCache implements Cache interface using Redis.
+// Commands go through a circuit breaker: after repeated failures they return
+// ErrCacheDisabled immediately until a background Ping finds Redis again.
 type RedisCache struct {
-	config *config.RedisConfig
-	client *redis.Client
+	config  *config.RedisConfig
+	client  *redis.Client
+	breaker *breaker
 }
 
 // NewRedisCache creates a new Redis cache client
@@ -69,15 +75,27 @@ func NewRedisCache(cfg *config.RedisConfig) (*RedisCache, error) {
 		return nil, fmt.Errorf("redis ping failed: %w", err)
 	}
 
-	return &RedisCache{config: cfg, client: client}, nil
+	r := &RedisCache{config: cfg, client: client}
+	r.breaker = newBreaker(cfg.BreakerThreshold, cfg.BreakerCooldown, r.Ping)
+	return r, nil
+}
+
+// Disabled reports whether the circuit breaker is currently skipping Redis
+func (r *RedisCache) Disabled() bool {
+	return r.breaker.isOpen()
 }
 
 // Get retrieves a value by key
 func (r *RedisCache) Get(ctx context.Context, key string) (string, error) {
-	val, err := r.client.Get(ctx, key).Result()
-	if err == redis.Nil {
-		return "", nil
-	}
+	var val string
+	err := r.breaker.do(func() error {
+		var err error
+		val, err = r.client.Get(ctx, key).Result()
+		if err == redis.Nil {
+			return nil // a miss is a healthy answer
+		}
+		return err
+	})
 	if err != nil {
 		return "", err
 	}
@@ -105,17 +123,26 @@ func (r *RedisCache) SetWithTTL(ctx context.Context, key string, value interface
 		strVal = string(bytes)
 	}
 
-	return r.client.Set(ctx, key, strVal, ttl).Err()
+	return r.breaker.do(func() error {
+		return r.client.Set(ctx, key, strVal, ttl).Err()
+	})
 }
 
 // Delete removes a key
 func (r *RedisCache) Delete(ctx context.Context, key string) error {
-	return r.client.Del(ctx, key).Err()
+	return r.breaker.do(func() error {
+		return r.client.Del(ctx, key).Err()
+	})
 }
 
 // Exists checks if a key exists
 func (r *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
-	count, err := r.client.Exists(ctx, key).Result()
+	var count int64
+	err := r.breaker.do(func() error {
+		var err error
+		count, err = r.client.Exists(ctx, key).Result()
+		return err
+	})
 	if err != nil {
 		return false, err
 	}
@@ -124,30 +151,82 @@ func (r *RedisCache) Exists(ctx context.Context, key string) (bool, error) {
 
 // GetTTL returns remaining TTL for a key
 func (r *RedisCache) GetTTL(ctx context.Context, key string) (time.Duration, error) {
-	return r.client.TTL(ctx, key).Result()
+	var ttl time.Duration
+	err := r.breaker.do(func() error {
+		var err error
+		ttl, err = r.client.TTL(ctx, key).Result()
+		return err
+	})
+	return ttl, err
 }
 
 // FlushByPrefix removes all keys matching prefix
 func (r *RedisCache) FlushByPrefix(ctx context.Context, prefix string) error {
-	iter := r.client.Scan(ctx, 0, fmt.Sprintf("%s*", prefix), 0).Iterator()
-	for iter.Next(ctx) {
-		if err := r.client.Del(ctx, iter.Val()).Err(); err != nil {
-			return err
+	return r.breaker.do(func() error {
+		iter := r.client.Scan(ctx, 0, fmt.Sprintf("%s*", prefix), 0).Iterator()
+		for iter.Next(ctx) {
+			if err := r.client.Del(ctx, iter.Val()).Err(); err != nil {
+				return err
+			}
 		}
-	}
-	return iter.Err()
+		return iter.Err()
+	})
 }
 
 // Close closes the cache connection
 func (r *RedisCache) Close() error {
+	r.breaker.close()
 	return r.client.Close()
 }
 
-// Ping checks if cache is healthy
+// Ping checks if cache is healthy.
+// It always contacts Redis, even while the breaker is open.
 func (r *RedisCache) Ping(ctx context.Context) error {
 	return r.client.Ping(ctx).Err()
 }
 
+// slidingWindowScript atomically trims, counts and records a hit in a sorted set.
+// Returns {allowed (1/0), retry-after in ms}.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local now = tonumber(ARGV[1])
+local window = tonumber(ARGV[2])
+local limit = tonumber(ARGV[3])
+redis.call('ZREMRANGEBYSCORE', key, 0, now - window)
+if redis.call('ZCARD', key) < limit then
+	redis.call('ZADD', key, now, ARGV[4])
+	redis.call('PEXPIRE', key, window)
+	return {1, 0}
+end
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+return {0, tonumber(oldest[2]) + window - now}
+`)
+
+// AllowSlidingWindow records a hit for key and reports whether it is within
+// limit hits per window. When rejected, retryAfter is the time until the
+// oldest hit leaves the window.
+func (r *RedisCache) AllowSlidingWindow(ctx context.Context, key string, limit int, window time.Duration) (bool, time.Duration, error) {
+	now := time.Now()
+	member := fmt.Sprintf("%d-%d", now.UnixNano(), rand.Int63())
+
+	var res []int64
+	err := r.breaker.do(func() error {
+		var err error
+		res, err = slidingWindowScript.Run(ctx, r.client, []string{key},
+			now.UnixMilli(), window.Milliseconds(), limit, member,
+		).Int64Slice()
+		return err
+	})
+	if err != nil {
+		return false, 0, err
+	}
+	if len(res) != 2 {
+		return false, 0, fmt.Errorf("unexpected sliding window result: %v", res)
+	}
+
+	return res[0] == 1, time.Duration(res[1]) * time.Millisecond, nil
+}
+
 // Cache key prefixes
 const (
 	PrefixManga       = "manga:"
@@ -157,6 +236,8 @@ const (
 	PrefixSearch      = "search:"
 	PrefixExternal    = "external:"
 	PrefixLeaderboard = "leaderboard:"
+	PrefixCover       = "cover:"
+	PrefixStats       = "stats:"
 )
 
 // BuildKey creates a cache key with prefix
@@ -164,10 +245,63 @@ func BuildKey(prefix, id string) string {
 	return fmt.Sprintf("%s%s", prefix, id)
 }
 
+// CoverKey builds the cache key for a resolved cover URL, e.g. "cover:mangadex:<id>"
+func CoverKey(source, externalID string) string {
+	return BuildKey(PrefixCover, source+":"+externalID)
+}
+
+// StatsKey builds the cache key for a user's reading stats, e.g. "stats:<userID>"
+func StatsKey(userID string) string {
+	return BuildKey(PrefixStats, userID)
+}
+
 // Default TTLs
 const (
 	TTLShort  = 5 * time.Minute
 	TTLMedium = 30 * time.Minute
 	TTLLong   = 2 * time.Hour
 	TTLDay    = 24 * time.Hour
+	TTLWeek   = 7 * 24 * time.Hour
 )
+
+// Publish sends payload to every subscriber of channel
+func (r *RedisCache) Publish(ctx context.Context, channel string, payload []byte) error {
+	return r.breaker.do(func() error {
+		return r.client.Publish(ctx, channel, payload).Err()
+	})
+}
+
+// Subscribe listens on every channel matching pattern (e.g. "chat:*") and
+// delivers raw payloads until ctx is cancelled, then closes the channel.
+// go-redis reconnects the subscription by itself after network errors.
+func (r *RedisCache) Subscribe(ctx context.Context, pattern string) (<-chan []byte, error) {
+	sub := r.client.PSubscribe(ctx, pattern)
+	// Wait for the confirmation so messages published after this returns are not missed
+	if _, err := sub.Receive(ctx); err != nil {
+		sub.Close()
+		return nil, fmt.Errorf("redis psubscribe %s: %w", pattern, err)
+	}
+
+	out := make(chan []byte, 256)
+	go func() {
+		defer close(out)
+		defer sub.Close()
+		messages := sub.Channel()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case msg, ok := <-messages:
+				if !ok {
+					return
+				}
+				select {
+				case out <- []byte(msg.Payload):
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	return out, nil
+}
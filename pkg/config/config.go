@@ -10,6 +10,8 @@ package config
 
 import (
 	"fmt"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/spf13/viper"
@@ -17,27 +19,48 @@ import (
 
 // Config holds all application configuration
 type Config struct {
-	Server    ServerConfig
-	Database  DatabaseConfig
-	JWT       JWTConfig
-	TCP       TCPConfig
-	UDP       UDPConfig
-	GRPC      GRPCConfig
-	WebSocket WebSocketConfig
-	Logging   LoggingConfig
-	Redis     RedisConfig
-	MangaDex  MangaDexConfig
-	Jikan     JikanConfig
-	AniList   AniListConfig
+	Server      ServerConfig
+	Database    DatabaseConfig
+	JWT         JWTConfig
+	Auth        AuthConfig
+	Email       EmailConfig
+	TCP         TCPConfig
+	UDP         UDPConfig
+	GRPC        GRPCConfig
+	Bridge      BridgeConfig
+	WebSocket   WebSocketConfig
+	Logging     LoggingConfig
+	Redis       RedisConfig
+	MangaDex    MangaDexConfig
+	Jikan       JikanConfig
+	AniList     AniListConfig
+	Reader      ReaderConfig
+	Stats       StatsConfig
+	Leaderboard LeaderboardConfig
+	Moderation  ModerationConfig
 }
 
 type ServerConfig struct {
-	Host         string        `mapstructure:"host"`
-	Port         int           `mapstructure:"port"`
-	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
-	WriteTimeout time.Duration `mapstructure:"write_timeout"`
-	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
-	Mode         string        `mapstructure:"mode"` // debug, release
+	Host           string          `mapstructure:"host"`
+	Port           int             `mapstructure:"port"`
+	ReadTimeout    time.Duration   `mapstructure:"read_timeout"`
+	WriteTimeout   time.Duration   `mapstructure:"write_timeout"`
+	IdleTimeout    time.Duration   `mapstructure:"idle_timeout"`
+	RequestTimeout time.Duration   `mapstructure:"request_timeout"` // cancels the request context and its DB queries; 0 disables
+	Mode           string          `mapstructure:"mode"`            // debug, release
+	RateLimit      RateLimitConfig `mapstructure:"rate_limit"`
+	// TrustedProxies are the reverse proxy IPs/CIDRs allowed to set
+	// X-Forwarded-For; empty trusts none and uses the connection address
+	TrustedProxies []string `mapstructure:"trusted_proxies"`
+}
+
+// RateLimitConfig controls the Redis sliding-window request limiter
+type RateLimitConfig struct {
+	Enabled       bool          `mapstructure:"enabled"`
+	Requests      int           `mapstructure:"requests"` // per client IP per window
+	Window        time.Duration `mapstructure:"window"`
+	LoginRequests int           `mapstructure:"login_requests"` // failed logins per username per login window
+	LoginWindow   time.Duration `mapstructure:"login_window"`
 }
 
 type DatabaseConfig struct {
@@ -45,12 +68,55 @@ type DatabaseConfig struct {
 	MaxOpenConns    int           `mapstructure:"max_open_conns"`
 	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
+	BusyTimeout     time.Duration `mapstructure:"busy_timeout"` // wait on a locked database before failing a write
+	Backup          BackupConfig  `mapstructure:"backup"`
+}
+
+// BackupConfig controls the API server's periodic database backups
+type BackupConfig struct {
+	Dir      string        `mapstructure:"dir"`
+	Interval time.Duration `mapstructure:"interval"` // 0 disables auto backup
+	Keep     int           `mapstructure:"keep"`     // newest backups kept, 0 keeps all
 }
 
 type JWTConfig struct {
-	Secret     string        `mapstructure:"secret"`
-	Expiration time.Duration `mapstructure:"expiration"`
-	Issuer     string        `mapstructure:"issuer"`
+	Secret            string        `mapstructure:"secret"`
+	Expiration        time.Duration `mapstructure:"expiration"`
+	RefreshExpiration time.Duration `mapstructure:"refresh_expiration"`
+	Issuer            string        `mapstructure:"issuer"`
+	// Algorithm signs access tokens: HS256, HS384 or HS512
+	Algorithm string `mapstructure:"algorithm"`
+	// KeyID names Secret in the kid header of the tokens it signs
+	KeyID string `mapstructure:"key_id"`
+	// PreviousKeys are rotated-out secrets, still accepted for the tokens they signed
+	PreviousKeys []JWTKey `mapstructure:"previous_keys"`
+}
+
+// JWTKey is a retired JWT secret and the key ID its tokens carry
+type JWTKey struct {
+	ID     string `mapstructure:"id"`
+	Secret string `mapstructure:"secret"`
+}
+
+// AuthConfig controls email verification of new accounts
+type AuthConfig struct {
+	VerificationTTL time.Duration `mapstructure:"verification_ttl"`
+	// ExposeVerificationToken returns the token in the register response;
+	// for development only, where no mail is sent
+	ExposeVerificationToken bool `mapstructure:"expose_verification_token"`
+}
+
+// EmailConfig controls outgoing email (new-chapter digests)
+type EmailConfig struct {
+	// Sender is "log" (write emails to the log, nothing is sent) or "smtp"
+	Sender   string `mapstructure:"sender"`
+	SMTPHost string `mapstructure:"smtp_host"`
+	SMTPPort int    `mapstructure:"smtp_port"`
+	Username string `mapstructure:"username"`
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"`
+	// DigestInterval is how often the API server sends digests; 0 disables them
+	DigestInterval time.Duration `mapstructure:"digest_interval"`
 }
 
 type TCPConfig struct {
@@ -64,11 +130,22 @@ type UDPConfig struct {
 	Host       string `mapstructure:"host"`
 	Port       int    `mapstructure:"port"`
 	BufferSize int    `mapstructure:"buffer_size"`
+	// Secret signs notification datagrams (HMAC-SHA256); empty disables signing
+	Secret       string        `mapstructure:"secret"`
+	ReplayWindow time.Duration `mapstructure:"replay_window"`
 }
 
 type GRPCConfig struct {
 	Host string `mapstructure:"host"`
 	Port int    `mapstructure:"port"`
+	// APIKey must be sent as x-api-key metadata on catalog mutations; empty disables them
+	APIKey string `mapstructure:"api_key"`
+}
+
+// BridgeConfig controls how the API server reconnects to the TCP and gRPC servers
+type BridgeConfig struct {
+	InitialBackoff time.Duration `mapstructure:"initial_backoff"`
+	MaxBackoff     time.Duration `mapstructure:"max_backoff"`
 }
 
 type WebSocketConfig struct {
@@ -79,6 +156,14 @@ type WebSocketConfig struct {
 	HandshakeTimeout time.Duration `mapstructure:"handshake_timeout"`
 	PingPeriod       time.Duration `mapstructure:"ping_period"`
 	MaxMessageSize   int64         `mapstructure:"max_message_size"`
+	// Chat limits per connection; see websocket.Limits
+	MaxContentLength  int           `mapstructure:"max_content_length"`
+	RateLimitMessages int           `mapstructure:"rate_limit_messages"`
+	RateLimitWindow   time.Duration `mapstructure:"rate_limit_window"`
+	MaxViolations     int           `mapstructure:"max_violations"`
+	// RedisPubSub relays chat between api-server instances through Redis.
+	// Leave off for single-instance deployments.
+	RedisPubSub bool `mapstructure:"redis_pubsub"`
 }
 
 type LoggingConfig struct {
@@ -94,6 +179,16 @@ type RedisConfig struct {
 	Password string `mapstructure:"password"`
 	DB       int    `mapstructure:"db"`
 	PoolSize int    `mapstructure:"pool_size"`
+
+	// Circuit breaker: after BreakerThreshold consecutive failures the cache is
+	// skipped, and Redis is pinged every BreakerCooldown until it answers again
+	BreakerThreshold int           `mapstructure:"breaker_threshold"`
+	BreakerCooldown  time.Duration `mapstructure:"breaker_cooldown"`
+}
+
+// Enabled reports whether Redis is configured (an empty host disables it)
+func (c RedisConfig) Enabled() bool {
+	return c.Host != ""
 }
 
 // MangaDexConfig holds MangaDex API configuration
@@ -102,6 +197,7 @@ type MangaDexConfig struct {
 	RateLimit     int           `mapstructure:"rate_limit"`
 	Timeout       time.Duration `mapstructure:"timeout"`
 	RetryAttempts int           `mapstructure:"retry_attempts"`
+	Languages     []string      `mapstructure:"languages"` // searches return only manga translated into one of these; empty: all
 }
 
 // JikanConfig holds Jikan API configuration
@@ -120,6 +216,42 @@ type AniListConfig struct {
 	RetryAttempts int           `mapstructure:"retry_attempts"`
 }
 
+// ReaderConfig tunes the TUI chapter reader
+type ReaderConfig struct {
+	// MaxChapterTime caps the time recorded for one chapter so idle time is ignored
+	MaxChapterTime time.Duration `mapstructure:"max_chapter_time"`
+	// DefaultChapterTime is recorded for the first chapter of a session
+	DefaultChapterTime time.Duration `mapstructure:"default_chapter_time"`
+}
+
+// StatsConfig tunes reading statistics
+type StatsConfig struct {
+	// StreakGraceDays is how many single missed days per month keep a streak alive
+	StreakGraceDays int `mapstructure:"streak_grace_days"`
+}
+
+// LeaderboardConfig tunes the top-rated ranking and the background trending worker
+type LeaderboardConfig struct {
+	// MinVotes is both the prior's weight and the rating count a manga needs
+	// to be ranked; manga with fewer ratings are listed as hidden gems
+	MinVotes int `mapstructure:"min_votes"`
+	// PriorMean is the rating few-vote manga are pulled toward; 0 uses the
+	// average of all ratings
+	PriorMean float64 `mapstructure:"prior_mean"`
+	// TrendingInterval is how often trending scores are recomputed; 0 computes them per request
+	TrendingInterval time.Duration `mapstructure:"trending_interval"`
+	// LeaderElection lets only one instance sharing the database run the worker
+	LeaderElection bool `mapstructure:"leader_election"`
+}
+
+// ModerationConfig is the wordlist applied to comments and chat messages.
+// Words match whole words only, ignoring case, look-alike characters and
+// spelled-out letters; see moderation.Filter.
+type ModerationConfig struct {
+	Mask  []string `mapstructure:"mask"`  // replaced with asterisks
+	Block []string `mapstructure:"block"` // the content is rejected
+}
+
 // Load reads configuration from file
 func Load(configPath string) (*Config, error) {
 	viper.SetConfigName("development")
@@ -133,13 +265,14 @@ func Load(configPath string) (*Config, error) {
 	// Read config file
 	if err := viper.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			fmt.Println("Config file not found, using defaults")
+			fmt.Fprintln(os.Stderr, "Config file not found, using defaults")
 		} else {
 			return nil, fmt.Errorf("failed to read config: %w", err)
 		}
 	}
 
-	// Allow environment variable override
+	// Allow environment variable override (JWT_SECRET -> jwt.secret)
+	viper.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 	viper.AutomaticEnv()
 
 	var config Config
@@ -157,18 +290,45 @@ func setDefaults() {
 	viper.SetDefault("server.read_timeout", "15s")
 	viper.SetDefault("server.write_timeout", "15s")
 	viper.SetDefault("server.idle_timeout", "60s")
+	viper.SetDefault("server.request_timeout", "10s")
 	viper.SetDefault("server.mode", "debug")
+	viper.SetDefault("server.rate_limit.enabled", true)
+	viper.SetDefault("server.rate_limit.requests", 120)
+	viper.SetDefault("server.rate_limit.window", "1m")
+	viper.SetDefault("server.rate_limit.login_requests", 5)
+	viper.SetDefault("server.rate_limit.login_window", "5m")
+	viper.SetDefault("server.trusted_proxies", []string{})
 
 	// Database defaults
 	viper.SetDefault("database.path", "./data/mangahub.db")
 	viper.SetDefault("database.max_open_conns", 25)
 	viper.SetDefault("database.max_idle_conns", 5)
 	viper.SetDefault("database.conn_max_lifetime", "5m")
+	viper.SetDefault("database.busy_timeout", "5s")
+	viper.SetDefault("database.backup.dir", "./data/backups")
+	viper.SetDefault("database.backup.interval", "0s")
+	viper.SetDefault("database.backup.keep", 7)
 
 	// JWT defaults
 	viper.SetDefault("jwt.secret", "your-secret-key-change-in-production")
 	viper.SetDefault("jwt.expiration", "24h")
+	viper.SetDefault("jwt.refresh_expiration", "720h")
 	viper.SetDefault("jwt.issuer", "mangahub")
+	viper.SetDefault("jwt.algorithm", "HS256")
+	viper.SetDefault("jwt.key_id", "primary")
+
+	// Auth defaults
+	viper.SetDefault("auth.verification_ttl", "24h")
+	viper.SetDefault("auth.expose_verification_token", false)
+
+	// Email defaults: log only, so no environment emails by accident
+	viper.SetDefault("email.sender", "log")
+	viper.SetDefault("email.smtp_host", "")
+	viper.SetDefault("email.smtp_port", 587)
+	viper.SetDefault("email.username", "")
+	viper.SetDefault("email.password", "")
+	viper.SetDefault("email.from", "MangaHub <noreply@mangahub.local>")
+	viper.SetDefault("email.digest_interval", "24h")
 
 	// TCP defaults
 	viper.SetDefault("tcp.host", "localhost")
@@ -180,10 +340,17 @@ func setDefaults() {
 	viper.SetDefault("udp.host", "localhost")
 	viper.SetDefault("udp.port", 9091)
 	viper.SetDefault("udp.buffer_size", 2048)
+	viper.SetDefault("udp.secret", "")
+	viper.SetDefault("udp.replay_window", "30s")
 
 	// gRPC defaults
 	viper.SetDefault("grpc.host", "localhost")
 	viper.SetDefault("grpc.port", 9092)
+	viper.SetDefault("grpc.api_key", "")
+
+	// Protocol bridge reconnect defaults
+	viper.SetDefault("bridge.initial_backoff", "1s")
+	viper.SetDefault("bridge.max_backoff", "1m")
 
 	// WebSocket defaults
 	viper.SetDefault("websocket.host", "localhost")
@@ -193,6 +360,11 @@ func setDefaults() {
 	viper.SetDefault("websocket.handshake_timeout", "10s")
 	viper.SetDefault("websocket.ping_period", "54s")
 	viper.SetDefault("websocket.max_message_size", 512000)
+	viper.SetDefault("websocket.max_content_length", 1000)
+	viper.SetDefault("websocket.rate_limit_messages", 10)
+	viper.SetDefault("websocket.rate_limit_window", "10s")
+	viper.SetDefault("websocket.max_violations", 5)
+	viper.SetDefault("websocket.redis_pubsub", false)
 
 	// Logging defaults
 	viper.SetDefault("logging.level", "info")
@@ -200,11 +372,13 @@ func setDefaults() {
 	viper.SetDefault("logging.output", "stdout")
 
 	// Redis defaults
-	viper.SetDefault("redis.host", "localhost")
+	viper.SetDefault("redis.host", "") // empty: run without Redis
 	viper.SetDefault("redis.port", 6379)
 	viper.SetDefault("redis.password", "")
 	viper.SetDefault("redis.db", 0)
 	viper.SetDefault("redis.pool_size", 10)
+	viper.SetDefault("redis.breaker_threshold", 5)
+	viper.SetDefault("redis.breaker_cooldown", "30s")
 
 	// MangaDex API defaults
 	viper.SetDefault("mangadex.base_url", "https://api.mangadex.org")
@@ -223,4 +397,26 @@ func setDefaults() {
 	viper.SetDefault("anilist.rate_limit", 30)
 	viper.SetDefault("anilist.timeout", "30s")
 	viper.SetDefault("anilist.retry_attempts", 3)
+
+	// Reader defaults
+	viper.SetDefault("reader.max_chapter_time", "30m")
+	viper.SetDefault("reader.default_chapter_time", "5m")
+
+	// Statistics defaults
+	viper.SetDefault("stats.streak_grace_days", 1)
+	viper.SetDefault("leaderboard.min_votes", 5)
+	viper.SetDefault("leaderboard.prior_mean", 0)
+	viper.SetDefault("leaderboard.trending_interval", "5m")
+	viper.SetDefault("leaderboard.leader_election", false)
+
+	// Moderation defaults: empty wordlists filter nothing
+	viper.SetDefault("moderation.mask", []string{})
+	viper.SetDefault("moderation.block", []string{})
+
+	// TUI client cache defaults (read by internal/tui/api)
+	viper.SetDefault("tui.cache.default_ttl", "5m")
+	viper.SetDefault("tui.cache.dashboard_ttl", "30s")
+	viper.SetDefault("tui.cache.trending_ttl", "10m")
+	viper.SetDefault("tui.cache.library_ttl", "1m")
+	viper.SetDefault("tui.cache.not_found_ttl", "5s")
 }
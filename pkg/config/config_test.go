@@ -0,0 +1,146 @@
+package config
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// defaultConfig loads the built-in defaults (no config file in this directory)
+// with the database pointed at a temp dir
+func defaultConfig(t *testing.T) *Config {
+	t.Helper()
+	cfg, err := Load("")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	cfg.Database.Path = filepath.Join(t.TempDir(), "data", "mangahub.db")
+	return cfg
+}
+
+func TestValidateDefaults(t *testing.T) {
+	cfg := defaultConfig(t)
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("defaults should validate, got %v", err)
+	}
+}
+
+func TestValidateJWTSecret(t *testing.T) {
+	cfg := defaultConfig(t)
+	cfg.Server.Mode = ModeRelease
+	cfg.JWT.Secret = "${JWT_SECRET}"
+	err := cfg.Validate()
+	if err == nil || !strings.Contains(err.Error(), "jwt.secret") {
+		t.Fatalf("release mode without a secret should fail on jwt.secret, got %v", err)
+	}
+
+	cfg.Server.Mode = ModeDebug
+	cfg.JWT.Secret = ""
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("debug mode without a secret should validate, got %v", err)
+	}
+	if len(cfg.JWT.Secret) != 64 {
+		t.Errorf("expected a generated secret, got %q", cfg.JWT.Secret)
+	}
+}
+
+func TestValidateUDPSecret(t *testing.T) {
+	for _, secret := range []string{"${UDP_SECRET}", "dev-udp-secret-change-in-production"} {
+		cfg := defaultConfig(t)
+		cfg.Server.Mode = ModeRelease
+		cfg.JWT.Secret = "a-real-release-secret"
+		cfg.UDP.Secret = secret
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "udp.secret") {
+			t.Errorf("release mode should reject udp.secret %q, got %v", secret, err)
+		}
+	}
+
+	// Outside release mode an unexpanded placeholder means unsigned
+	cfg := defaultConfig(t)
+	cfg.UDP.Secret = "${UDP_SECRET}"
+	if err := cfg.Validate(); err != nil || cfg.UDP.Secret != "" {
+		t.Errorf("expected the placeholder to be dropped, got %q (%v)", cfg.UDP.Secret, err)
+	}
+}
+
+func TestValidateGRPCKey(t *testing.T) {
+	for _, key := range []string{"${GRPC_API_KEY}", "dev-grpc-key-change-in-production"} {
+		cfg := defaultConfig(t)
+		cfg.Server.Mode = ModeRelease
+		cfg.JWT.Secret = "a-real-release-secret"
+		cfg.GRPC.APIKey = key
+		if err := cfg.Validate(); err == nil || !strings.Contains(err.Error(), "grpc.api_key") {
+			t.Errorf("release mode should reject grpc.api_key %q, got %v", key, err)
+		}
+
+		cfg.Server.Mode = ModeDebug
+		if err := cfg.Validate(); err != nil {
+			t.Fatalf("debug mode should validate with grpc.api_key %q, got %v", key, err)
+		}
+		if cfg.GRPC.APIKey != "" {
+			t.Errorf("expected mutations disabled for %q, got key %q", key, cfg.GRPC.APIKey)
+		}
+	}
+}
+
+func TestValidateJWTKeys(t *testing.T) {
+	cfg := defaultConfig(t)
+	cfg.JWT.PreviousKeys = []JWTKey{{ID: "2025", Secret: "old"}}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("a rotated-out key should validate, got %v", err)
+	}
+
+	cfg.JWT.Algorithm = "RS256"
+	cfg.JWT.PreviousKeys = append(cfg.JWT.PreviousKeys, JWTKey{ID: cfg.JWT.KeyID, Secret: "older"})
+	err := cfg.Validate()
+	for _, field := range []string{"jwt.algorithm", "jwt.previous_keys[1]"} {
+		if err == nil || !strings.Contains(err.Error(), field) {
+			t.Errorf("error should mention %s: %v", field, err)
+		}
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	cfg := defaultConfig(t)
+	cfg.Server.Port = 70000
+	cfg.TCP.Port = 0
+	cfg.Server.ReadTimeout = 0
+	cfg.Server.TrustedProxies = []string{"10.0.0.0/8", "proxy.local"}
+
+	err := cfg.Validate()
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, field := range []string{"server.port", "tcp.port", "server.read_timeout", "server.trusted_proxies"} {
+		if !strings.Contains(err.Error(), field) {
+			t.Errorf("error should mention %s: %v", field, err)
+		}
+	}
+}
+
+func TestReloadRejectsInvalidConfig(t *testing.T) {
+	current := defaultConfig(t)
+	t.Setenv("DATABASE_PATH", current.Database.Path)
+	if err := current.Validate(); err != nil {
+		t.Fatal(err)
+	}
+
+	t.Setenv("SERVER_RATE_LIMIT_REQUESTS", "300")
+	next, err := Reload(current)
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if next.JWT.Secret != current.JWT.Secret {
+		t.Error("reload should keep the generated JWT secret")
+	}
+	if got := strings.Join(Changed(current, next), ","); got != "server.rate_limit.requests" {
+		t.Errorf("Changed = %s", got)
+	}
+
+	for _, port := range []string{"0", "not-a-port"} {
+		t.Setenv("SERVER_PORT", port)
+		if _, err := Reload(current); err == nil {
+			t.Errorf("server.port=%s should be rejected", port)
+		}
+	}
+}
@@ -0,0 +1,59 @@
+// Package config - Configuration Reload
+// Đọc lại config khi server đang chạy (SIGHUP)
+// Chức năng:
+//   - Reload: đọc và validate lại toàn bộ file; lỗi thì giữ config cũ
+//   - Changed: liệt kê các key thay đổi để server áp dụng hoặc báo cần restart
+package config
+
+import (
+	"reflect"
+	"strings"
+)
+
+// Reload reads the config file again and validates it. The result is either
+// a complete, valid config or an error, in which case the caller keeps
+// running on current; nothing is applied half way.
+func Reload(current *Config) (*Config, error) {
+	next, err := Load("")
+	if err != nil {
+		return nil, err
+	}
+	// Keep the secret generated at startup instead of generating another one,
+	// which would show up as a change on every reload
+	if !next.IsRelease() && SecretUnset(next.JWT.Secret) {
+		next.JWT.Secret = current.JWT.Secret
+	}
+	if err := next.Validate(); err != nil {
+		return nil, err
+	}
+	return next, nil
+}
+
+// Changed lists the config keys, e.g. "server.rate_limit.requests", whose
+// values differ between old and new. Lists such as jwt.previous_keys are
+// compared as a whole.
+func Changed(old, new *Config) []string {
+	var keys []string
+	diffFields(reflect.ValueOf(*old), reflect.ValueOf(*new), "", &keys)
+	return keys
+}
+
+func diffFields(old, new reflect.Value, prefix string, keys *[]string) {
+	for i := 0; i < old.NumField(); i++ {
+		field := old.Type().Field(i)
+		name := field.Tag.Get("mapstructure")
+		if name == "" {
+			name = strings.ToLower(field.Name)
+		}
+		key := prefix + name
+
+		a, b := old.Field(i), new.Field(i)
+		if a.Kind() == reflect.Struct {
+			diffFields(a, b, key+".", keys)
+			continue
+		}
+		if !reflect.DeepEqual(a.Interface(), b.Interface()) {
+			*keys = append(*keys, key)
+		}
+	}
+}
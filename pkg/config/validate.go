@@ -0,0 +1,333 @@
+// Package config - Configuration Validation
+// Kiểm tra config ngay khi khởi động, trước khi mở database hay listen port
+// Chức năng:
+//   - Ports trong khoảng 1-65535, timeouts và rate limits dương
+//   - server.trusted_proxies là IP hoặc CIDR
+//   - mangadex.languages là mã ngôn ngữ hợp lệ (en, pt-br)
+//   - JWT secret bắt buộc trong release mode; dev mode tự sinh secret và cảnh báo
+//   - udp.secret, grpc.api_key: placeholder "${...}" hoặc giá trị mẫu bị từ chối
+//     trong release mode; dev mode tắt signing / mutation kèm cảnh báo
+//   - Database path ghi được
+//   - moderation.mask/block chỉ chứa từ đơn
+//   - Gom tất cả lỗi vào một error để sửa một lần
+package config
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"mangahub/pkg/moderation"
+)
+
+// Server modes accepted by server.mode (gin's modes)
+const (
+	ModeDebug   = "debug"
+	ModeRelease = "release"
+	ModeTest    = "test"
+)
+
+// jwtAlgorithms are the accepted jwt.algorithm values; keys are shared secrets, so HMAC only
+var jwtAlgorithms = []string{"HS256", "HS384", "HS512"}
+
+// placeholderSecrets are the shipped example secrets and keys; in release mode they count as unset
+var placeholderSecrets = []string{
+	"your-secret-key-change-in-production",
+	"dev-secret-change-in-production-please",
+	"dev-udp-secret-change-in-production",
+	"your-udp-secret-change-in-production",
+	"dev-grpc-key-change-in-production",
+	"your-grpc-api-key-change-in-production",
+}
+
+// languageCode matches MangaDex language codes: ISO 639 with an optional
+// region or script, e.g. en, pt-br, ja-ro
+var languageCode = regexp.MustCompile(`^[a-z]{2,3}(-[a-z]{2,3})?$`)
+
+// ValidLanguageCode reports whether lang is a MangaDex language code
+func ValidLanguageCode(lang string) bool {
+	return languageCode.MatchString(lang)
+}
+
+// IsRelease reports whether the server runs in release mode
+func (c *Config) IsRelease() bool {
+	return c.Server.Mode == ModeRelease
+}
+
+// Validate checks the configuration and returns every problem found, one per
+// line, as a single error. In debug and test mode a missing JWT secret is
+// replaced by a random one (tokens then stop working on restart) and a
+// warning is printed; in release mode it is an error.
+func (c *Config) Validate() error {
+	v := &validator{}
+
+	// Server
+	v.check(slices.Contains([]string{ModeDebug, ModeRelease, ModeTest}, c.Server.Mode),
+		"server.mode must be debug, release or test, got %q", c.Server.Mode)
+	v.port("server.port", c.Server.Port)
+	v.positive("server.read_timeout", c.Server.ReadTimeout)
+	v.positive("server.write_timeout", c.Server.WriteTimeout)
+	v.positive("server.idle_timeout", c.Server.IdleTimeout)
+	v.check(c.Server.RequestTimeout >= 0, "server.request_timeout must not be negative (0 disables it)")
+	if rl := c.Server.RateLimit; rl.Enabled {
+		v.check(rl.Requests > 0, "server.rate_limit.requests must be positive, got %d", rl.Requests)
+		v.positive("server.rate_limit.window", rl.Window)
+		v.check(rl.LoginRequests > 0, "server.rate_limit.login_requests must be positive, got %d", rl.LoginRequests)
+		v.positive("server.rate_limit.login_window", rl.LoginWindow)
+	}
+	for _, proxy := range c.Server.TrustedProxies {
+		_, _, cidrErr := net.ParseCIDR(proxy)
+		v.check(cidrErr == nil || net.ParseIP(proxy) != nil,
+			"server.trusted_proxies: %q is not an IP address or CIDR range", proxy)
+	}
+
+	// Database
+	if c.Database.Path == "" {
+		v.fail("database.path is required")
+	} else if err := checkWritable(c.Database.Path); err != nil {
+		v.fail("database.path %s is not writable: %v", c.Database.Path, err)
+	}
+	v.check(c.Database.MaxOpenConns > 0, "database.max_open_conns must be positive, got %d", c.Database.MaxOpenConns)
+	v.check(c.Database.MaxIdleConns >= 0, "database.max_idle_conns must not be negative")
+	v.check(c.Database.MaxIdleConns <= c.Database.MaxOpenConns,
+		"database.max_idle_conns (%d) must not exceed database.max_open_conns (%d)", c.Database.MaxIdleConns, c.Database.MaxOpenConns)
+	v.positive("database.busy_timeout", c.Database.BusyTimeout)
+	v.check(c.Database.Backup.Interval >= 0, "database.backup.interval must not be negative (0 disables backups)")
+	v.check(c.Database.Backup.Keep >= 0, "database.backup.keep must not be negative (0 keeps all)")
+
+	// JWT
+	c.validateJWTSecret(v)
+	v.positive("jwt.expiration", c.JWT.Expiration)
+	v.positive("jwt.refresh_expiration", c.JWT.RefreshExpiration)
+	v.check(slices.Contains(jwtAlgorithms, c.JWT.Algorithm),
+		"jwt.algorithm must be one of %s, got %q", strings.Join(jwtAlgorithms, ", "), c.JWT.Algorithm)
+	v.check(c.JWT.KeyID != "", "jwt.key_id must not be empty")
+	keyIDs := map[string]bool{c.JWT.KeyID: true}
+	for i, k := range c.JWT.PreviousKeys {
+		v.check(k.ID != "" && k.Secret != "", "jwt.previous_keys[%d] needs both id and secret", i)
+		v.check(!keyIDs[k.ID], "jwt.previous_keys[%d]: key id %q is already in use", i, k.ID)
+		keyIDs[k.ID] = true
+	}
+
+	// Email
+	v.check(c.Email.Sender == "log" || c.Email.Sender == "smtp",
+		"email.sender must be log or smtp, got %q", c.Email.Sender)
+	if c.Email.Sender == "smtp" {
+		v.check(c.Email.SMTPHost != "", "email.smtp_host is required when email.sender is smtp")
+		v.port("email.smtp_port", c.Email.SMTPPort)
+	}
+	v.check(c.Email.DigestInterval >= 0, "email.digest_interval must not be negative (0 disables digests)")
+	v.check(c.Leaderboard.TrendingInterval >= 0, "leaderboard.trending_interval must not be negative (0 computes trending per request)")
+
+	// TCP, UDP, gRPC, bridge
+	v.port("tcp.port", c.TCP.Port)
+	v.check(c.TCP.MaxConnections > 0, "tcp.max_connections must be positive, got %d", c.TCP.MaxConnections)
+	v.check(c.TCP.BufferSize > 0, "tcp.buffer_size must be positive, got %d", c.TCP.BufferSize)
+	v.port("udp.port", c.UDP.Port)
+	v.check(c.UDP.BufferSize > 0, "udp.buffer_size must be positive, got %d", c.UDP.BufferSize)
+	c.validateUDPSecret(v)
+	if c.UDP.Secret != "" {
+		v.positive("udp.replay_window", c.UDP.ReplayWindow)
+	}
+	v.port("grpc.port", c.GRPC.Port)
+	c.validateGRPCKey(v)
+	v.positive("bridge.initial_backoff", c.Bridge.InitialBackoff)
+	v.positive("bridge.max_backoff", c.Bridge.MaxBackoff)
+	v.check(c.Bridge.InitialBackoff <= c.Bridge.MaxBackoff,
+		"bridge.initial_backoff (%s) must not exceed bridge.max_backoff (%s)", c.Bridge.InitialBackoff, c.Bridge.MaxBackoff)
+
+	// WebSocket
+	ws := c.WebSocket
+	v.port("websocket.port", ws.Port)
+	v.positive("websocket.handshake_timeout", ws.HandshakeTimeout)
+	v.positive("websocket.ping_period", ws.PingPeriod)
+	v.check(ws.MaxMessageSize > 0, "websocket.max_message_size must be positive, got %d", ws.MaxMessageSize)
+	v.check(ws.MaxContentLength > 0, "websocket.max_content_length must be positive, got %d", ws.MaxContentLength)
+	v.check(ws.RateLimitMessages > 0, "websocket.rate_limit_messages must be positive, got %d", ws.RateLimitMessages)
+	v.positive("websocket.rate_limit_window", ws.RateLimitWindow)
+	v.check(ws.MaxViolations > 0, "websocket.max_violations must be positive, got %d", ws.MaxViolations)
+	v.check(!ws.RedisPubSub || c.Redis.Enabled(), "websocket.redis_pubsub needs redis.host")
+
+	// Redis is optional; its port only matters when it is configured
+	if c.Redis.Enabled() {
+		v.port("redis.port", c.Redis.Port)
+		v.check(c.Redis.PoolSize > 0, "redis.pool_size must be positive, got %d", c.Redis.PoolSize)
+		v.check(c.Redis.BreakerThreshold >= 0, "redis.breaker_threshold must not be negative (0 uses the default)")
+		v.check(c.Redis.BreakerCooldown >= 0, "redis.breaker_cooldown must not be negative (0 uses the default)")
+	}
+
+	// External APIs
+	for _, api := range []struct {
+		name      string
+		rateLimit int
+		timeout   time.Duration
+	}{
+		{"mangadex", c.MangaDex.RateLimit, c.MangaDex.Timeout},
+		{"jikan", c.Jikan.RateLimit, c.Jikan.Timeout},
+		{"anilist", c.AniList.RateLimit, c.AniList.Timeout},
+	} {
+		v.check(api.rateLimit > 0, "%s.rate_limit must be positive, got %d", api.name, api.rateLimit)
+		v.positive(api.name+".timeout", api.timeout)
+	}
+
+	for _, lang := range c.MangaDex.Languages {
+		v.check(ValidLanguageCode(lang), "mangadex.languages: %q is not a language code like en or pt-br", lang)
+	}
+
+	// Moderation wordlists must build a filter
+	if _, err := moderation.New(c.Moderation.Mask, c.Moderation.Block); err != nil {
+		v.fail("%v", err)
+	}
+
+	// Logging
+	v.check(slices.Contains([]string{"debug", "info", "warn", "warning", "error"}, strings.ToLower(c.Logging.Level)),
+		"logging.level must be debug, info, warn or error, got %q", c.Logging.Level)
+
+	if len(v.problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("invalid configuration:\n  - %s", strings.Join(v.problems, "\n  - "))
+}
+
+// validateJWTSecret rejects a missing secret in release mode and generates one otherwise.
+// Unexpanded "${JWT_SECRET}" placeholders and the shipped example secrets count as missing.
+func (c *Config) validateJWTSecret(v *validator) {
+	secret := c.JWT.Secret
+	missing := SecretUnset(secret)
+	if c.IsRelease() {
+		switch {
+		case missing:
+			v.fail("jwt.secret is required in release mode (set it in the config file or the JWT_SECRET environment variable)")
+		case slices.Contains(placeholderSecrets, secret):
+			v.fail("jwt.secret is still the example value; set a real secret for release mode")
+		}
+		return
+	}
+	if !missing {
+		return
+	}
+
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		v.fail("jwt.secret is empty and a random one could not be generated: %v", err)
+		return
+	}
+	c.JWT.Secret = hex.EncodeToString(buf)
+	fmt.Fprintln(os.Stderr, "WARNING: jwt.secret is not set; using a random secret, so tokens stop working when the server restarts")
+}
+
+// SecretUnset reports whether secret is empty or an unexpanded "${VAR}"
+// placeholder, i.e. its environment variable was not set
+func SecretUnset(secret string) bool {
+	return secret == "" || strings.HasPrefix(secret, "${")
+}
+
+// validateUDPSecret applies the jwt.secret checks to udp.secret. Release mode
+// rejects a placeholder or example value, since anyone reading the repo could
+// forge signatures with it. Otherwise an unexpanded placeholder disables
+// signing with a warning. An empty secret always means unsigned.
+func (c *Config) validateUDPSecret(v *validator) {
+	secret := c.UDP.Secret
+	if secret == "" {
+		return
+	}
+	if c.IsRelease() {
+		switch {
+		case SecretUnset(secret):
+			v.fail("udp.secret is an unexpanded placeholder; set the UDP_SECRET environment variable or leave it empty to disable signing")
+		case slices.Contains(placeholderSecrets, secret):
+			v.fail("udp.secret is still the example value; set a real secret for release mode")
+		}
+		return
+	}
+	if SecretUnset(secret) {
+		c.UDP.Secret = ""
+		fmt.Fprintln(os.Stderr, "WARNING: udp.secret is not set; notifications are unsigned")
+	}
+}
+
+// validateGRPCKey guards grpc.api_key, which authorizes catalog mutations.
+// Release mode rejects a placeholder or example key. Otherwise such a key
+// disables mutations with a warning. An empty key always disables them.
+func (c *Config) validateGRPCKey(v *validator) {
+	key := c.GRPC.APIKey
+	if key == "" {
+		return
+	}
+	example := slices.Contains(placeholderSecrets, key)
+	if c.IsRelease() {
+		switch {
+		case SecretUnset(key):
+			v.fail("grpc.api_key is an unexpanded placeholder; set the GRPC_API_KEY environment variable or leave it empty to disable mutations")
+		case example:
+			v.fail("grpc.api_key is still the example value; set a real key for release mode")
+		}
+		return
+	}
+	if SecretUnset(key) || example {
+		c.GRPC.APIKey = ""
+		fmt.Fprintln(os.Stderr, "WARNING: grpc.api_key is not set or is the example key; catalog mutation RPCs are disabled")
+	}
+}
+
+// checkWritable reports whether a file can be created next to path. The
+// directory may not exist yet (NewDB creates it), so the nearest existing
+// parent is tested instead; nothing is left behind.
+func checkWritable(path string) error {
+	dir := filepath.Dir(path)
+	for {
+		info, err := os.Stat(dir)
+		if err == nil {
+			if !info.IsDir() {
+				return fmt.Errorf("%s is not a directory", dir)
+			}
+			break
+		}
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return err
+		}
+		dir = parent
+	}
+
+	f, err := os.CreateTemp(dir, ".mangahub-write-check-*")
+	if err != nil {
+		return err
+	}
+	f.Close()
+	return os.Remove(f.Name())
+}
+
+// validator collects problems so Validate can report them all at once
+type validator struct {
+	problems []string
+}
+
+func (v *validator) fail(format string, args ...interface{}) {
+	v.problems = append(v.problems, fmt.Sprintf(format, args...))
+}
+
+func (v *validator) check(ok bool, format string, args ...interface{}) {
+	if !ok {
+		v.fail(format, args...)
+	}
+}
+
+func (v *validator) port(name string, port int) {
+	v.check(port >= 1 && port <= 65535, "%s must be between 1 and 65535, got %d", name, port)
+}
+
+func (v *validator) positive(name string, d time.Duration) {
+	v.check(d > 0, "%s must be positive, got %s", name, d)
+}
@@ -0,0 +1,187 @@
+// Package database - Backup and Restore
+// Sao lưu / khôi phục SQLite database
+// Chức năng:
+//   - Backup bằng VACUUM INTO: bản copy nhất quán kể cả khi API server đang mở DB (WAL)
+//   - Restore từ file backup, từ chối nếu DB hiện tại có schema mới hơn backup
+//   - AutoBackup chạy định kỳ theo config (database.backup) và xoá backup cũ
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// backupPrefix and backupSuffix name the files written by AutoBackup
+const (
+	backupPrefix = "mangahub-"
+	backupSuffix = ".db"
+)
+
+// ErrNewerSchema is returned by Restore when the live database is ahead of the backup
+var ErrNewerSchema = errors.New("database schema is newer than the backup")
+
+// Backup writes a consistent copy of the database to path.
+// VACUUM INTO reads inside one transaction, so committed WAL pages are included
+// and writers on other connections are not blocked. path must not exist yet.
+func (db *DB) Backup(ctx context.Context, path string) error {
+	if _, err := os.Stat(path); err == nil {
+		return fmt.Errorf("backup target %s already exists", path)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("create backup directory: %w", err)
+	}
+
+	// Best effort: fold the WAL into the main file first; VACUUM INTO is consistent either way
+	_, _ = db.ExecContext(ctx, "PRAGMA wal_checkpoint(PASSIVE)")
+
+	if _, err := db.ExecContext(ctx, "VACUUM INTO ?", path); err != nil {
+		return fmt.Errorf("vacuum into %s: %w", path, err)
+	}
+	return nil
+}
+
+// FileSchemaVersion reports the schema version stored in the database file at path.
+// A file without schema_migrations (or no file at all) reports 0.
+func FileSchemaVersion(path string) (int, error) {
+	if _, err := os.Stat(path); err != nil {
+		if os.IsNotExist(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("stat %s: %w", path, err)
+	}
+
+	sqlDB, err := sql.Open("sqlite", path+"?_pragma=query_only(1)")
+	if err != nil {
+		return 0, fmt.Errorf("open %s: %w", path, err)
+	}
+	defer sqlDB.Close()
+
+	var exists int
+	if err := sqlDB.QueryRow(
+		"SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'",
+	).Scan(&exists); err != nil {
+		return 0, fmt.Errorf("read %s: %w", path, err)
+	}
+	if exists == 0 {
+		return 0, nil
+	}
+	return (&DB{sqlDB}).SchemaVersion()
+}
+
+// Restore replaces the database at dbPath with the backup at backupPath.
+// It refuses with ErrNewerSchema when dbPath has a newer schema than the backup,
+// unless force is set. Nothing else may hold dbPath open while it runs.
+func Restore(backupPath, dbPath string, force bool) error {
+	if _, err := os.Stat(backupPath); err != nil {
+		return fmt.Errorf("backup %s: %w", backupPath, err)
+	}
+	backupVersion, err := FileSchemaVersion(backupPath)
+	if err != nil {
+		return err
+	}
+	currentVersion, err := FileSchemaVersion(dbPath)
+	if err != nil {
+		return err
+	}
+	if currentVersion > backupVersion && !force {
+		return fmt.Errorf("%w (database v%d, backup v%d)", ErrNewerSchema, currentVersion, backupVersion)
+	}
+
+	// Copy through SQLite into a temp file beside the target, so a corrupt
+	// backup fails here and the final swap is a same-directory rename
+	tmpPath := dbPath + ".restore"
+	_ = os.Remove(tmpPath)
+	src, err := sql.Open("sqlite", backupPath)
+	if err != nil {
+		return fmt.Errorf("open backup: %w", err)
+	}
+	_, err = src.Exec("VACUUM INTO ?", tmpPath)
+	src.Close()
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("copy backup: %w", err)
+	}
+
+	// Stale WAL/SHM files would be replayed over the restored pages
+	for _, suffix := range []string{"-wal", "-shm"} {
+		if err := os.Remove(dbPath + suffix); err != nil && !os.IsNotExist(err) {
+			_ = os.Remove(tmpPath)
+			return fmt.Errorf("remove %s: %w", dbPath+suffix, err)
+		}
+	}
+	if err := os.Rename(tmpPath, dbPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("replace database: %w", err)
+	}
+	return nil
+}
+
+// BackupConfig controls periodic backups; Interval <= 0 disables them
+type BackupConfig struct {
+	Dir      string
+	Interval time.Duration
+	Keep     int // newest backups to keep, <= 0 keeps all
+}
+
+// AutoBackup writes a timestamped backup into cfg.Dir every cfg.Interval until
+// ctx is cancelled, pruning old ones. Errors go to logf and do not stop the loop.
+func (db *DB) AutoBackup(ctx context.Context, cfg BackupConfig, logf func(format string, args ...interface{})) {
+	if cfg.Interval <= 0 || cfg.Dir == "" {
+		return
+	}
+	ticker := time.NewTicker(cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			path := filepath.Join(cfg.Dir, backupPrefix+now.UTC().Format("20060102-150405")+backupSuffix)
+			if err := db.Backup(ctx, path); err != nil {
+				logf("auto backup failed: %v", err)
+				continue
+			}
+			if err := pruneBackups(cfg.Dir, cfg.Keep); err != nil {
+				logf("prune backups: %v", err)
+			}
+		}
+	}
+}
+
+// pruneBackups deletes all but the newest keep auto backups in dir
+func pruneBackups(dir string, keep int) error {
+	if keep <= 0 {
+		return nil
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasPrefix(e.Name(), backupPrefix) && strings.HasSuffix(e.Name(), backupSuffix) {
+			names = append(names, e.Name())
+		}
+	}
+	if len(names) <= keep {
+		return nil
+	}
+
+	// Timestamped names sort chronologically
+	sort.Strings(names)
+	for _, name := range names[:len(names)-keep] {
+		if err := os.Remove(filepath.Join(dir, name)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
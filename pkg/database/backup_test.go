@@ -0,0 +1,120 @@
+// Package database - Backup Tests
+// Unit tests cho backup khi DB đang mở (WAL), restore và guard schema version
+package database
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// openLiveDB opens a migrated WAL database like the API server does
+func openLiveDB(t *testing.T, path string) *DB {
+	db, err := NewDB(Config{Path: path, MaxOpenConns: 2, MaxIdleConns: 2})
+	if err != nil {
+		t.Fatalf("NewDB: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func countManga(t *testing.T, path string) int {
+	db := openLiveDB(t, path)
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM manga WHERE id = 'backup-test'").Scan(&n); err != nil {
+		t.Fatalf("count: %v", err)
+	}
+	db.Close()
+	return n
+}
+
+func TestBackupAndRestore(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "mangahub.db")
+	backupPath := filepath.Join(dir, "backups", "snapshot.db")
+
+	live := openLiveDB(t, dbPath)
+	if _, err := live.Exec("INSERT INTO manga (id, title) VALUES ('backup-test', 'Berserk')"); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+
+	// Backup while the live connection is still open and the row may sit in the WAL
+	if err := live.Backup(context.Background(), backupPath); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	if err := live.Backup(context.Background(), backupPath); err == nil {
+		t.Error("Backup overwrote an existing file")
+	}
+	if _, err := live.Exec("DELETE FROM manga WHERE id = 'backup-test'"); err != nil {
+		t.Fatalf("delete: %v", err)
+	}
+	live.Close()
+
+	version, err := FileSchemaVersion(backupPath)
+	if err != nil || version != migrations[len(migrations)-1].Version {
+		t.Fatalf("backup schema version = %d, %v", version, err)
+	}
+
+	if err := Restore(backupPath, dbPath, false); err != nil {
+		t.Fatalf("Restore: %v", err)
+	}
+	if n := countManga(t, dbPath); n != 1 {
+		t.Errorf("restored database has %d seeded rows, want 1", n)
+	}
+}
+
+func TestRestoreRefusesOlderBackup(t *testing.T) {
+	dir := t.TempDir()
+	dbPath := filepath.Join(dir, "mangahub.db")
+	backupPath := filepath.Join(dir, "old.db")
+
+	live := openLiveDB(t, dbPath)
+	if err := live.Backup(context.Background(), backupPath); err != nil {
+		t.Fatalf("Backup: %v", err)
+	}
+	live.Close()
+
+	// Pretend the backup predates the latest migration
+	old := openLiveDB(t, backupPath)
+	if _, err := old.Exec("DELETE FROM schema_migrations WHERE version = (SELECT MAX(version) FROM schema_migrations)"); err != nil {
+		t.Fatalf("downgrade: %v", err)
+	}
+	old.Close()
+
+	if err := Restore(backupPath, dbPath, false); !errors.Is(err, ErrNewerSchema) {
+		t.Fatalf("Restore = %v, want ErrNewerSchema", err)
+	}
+	if err := Restore(filepath.Join(dir, "missing.db"), dbPath, false); err == nil {
+		t.Error("Restore accepted a missing backup")
+	}
+	if err := Restore(backupPath, dbPath, true); err != nil {
+		t.Fatalf("forced Restore: %v", err)
+	}
+}
+
+func TestPruneBackupsKeepsNewest(t *testing.T) {
+	dir := t.TempDir()
+	names := []string{
+		"mangahub-20260101-000000.db",
+		"mangahub-20260102-000000.db",
+		"mangahub-20260103-000000.db",
+		"before-upgrade.db", // manual backups are never pruned
+	}
+	for _, name := range names {
+		if err := os.WriteFile(filepath.Join(dir, name), nil, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := pruneBackups(dir, 2); err != nil {
+		t.Fatalf("pruneBackups: %v", err)
+	}
+	for i, name := range names {
+		_, err := os.Stat(filepath.Join(dir, name))
+		if gone := os.IsNotExist(err); gone != (i == 0) {
+			t.Errorf("%s removed = %v", name, gone)
+		}
+	}
+}
@@ -0,0 +1,106 @@
+// Package database - SQLite Busy Handling
+// Xử lý lock contention khi nhiều writer (import, progress, chat) ghi cùng lúc
+// Chức năng:
+//   - busy_timeout trên mọi connection: statement chờ lock thay vì lỗi ngay
+//   - WithTx: chạy lại transaction bị SQLITE_BUSY/SQLITE_LOCKED với backoff
+//   - Hết busy timeout thì trả lỗi, không treo mãi
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultBusyTimeout is how long a statement waits on a locked database when
+// the config does not set database.busy_timeout
+const DefaultBusyTimeout = 5 * time.Second
+
+// SQLite primary result codes for lock contention
+const (
+	sqliteBusy   = 5
+	sqliteLocked = 6
+)
+
+// Backoff between transaction attempts: doubles from busyBackoffMin up to busyBackoffMax
+const (
+	busyBackoffMin = 10 * time.Millisecond
+	busyBackoffMax = 250 * time.Millisecond
+)
+
+// busyRetryWindow bounds how long WithTx keeps retrying; NewDB sets it to
+// the configured busy timeout
+var busyRetryWindow = DefaultBusyTimeout
+
+// busyDSN builds the connection string; pragmas apply to every pooled connection
+func busyDSN(path string, busyTimeout time.Duration) string {
+	return fmt.Sprintf("%s?_pragma=foreign_keys(1)&_pragma=journal_mode(WAL)&_pragma=busy_timeout(%d)",
+		path, busyTimeout.Milliseconds())
+}
+
+// IsBusy reports whether err is SQLite lock contention (SQLITE_BUSY or
+// SQLITE_LOCKED, extended codes included) that a retry may get past
+func IsBusy(err error) bool {
+	var coded interface{ Code() int }
+	if !errors.As(err, &coded) {
+		return false
+	}
+	switch coded.Code() & 0xff {
+	case sqliteBusy, sqliteLocked:
+		return true
+	}
+	return false
+}
+
+// WithTx runs fn in a transaction and commits it. busy_timeout cannot help a
+// WAL transaction whose read snapshot went stale before its first write, so
+// one that fails with lock contention is rolled back and run again with
+// backoff until the busy timeout has passed; then the busy error is returned.
+// fn may therefore run more than once and should only touch the database
+// through tx. Any other error is returned at once.
+func WithTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	deadline := time.Now().Add(busyRetryWindow)
+	wait := busyBackoffMin
+	for {
+		err := runTx(ctx, db, fn)
+		if err == nil || !IsBusy(err) || time.Now().Add(wait).After(deadline) {
+			return err
+		}
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(wait):
+		}
+		wait = min(wait*2, busyBackoffMax)
+	}
+}
+
+// runTx is one attempt of WithTx. It pins a connection because SQLite keeps
+// the transaction open when COMMIT fails (busy, or a deferred foreign key),
+// while database/sql already counts it as finished; the connection must not
+// go back to the pool still inside it.
+func runTx(ctx context.Context, db *sql.DB, fn func(tx *sql.Tx) error) error {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer conn.Close()
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if err := fn(tx); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		// Fails harmlessly if the driver already ended the transaction
+		conn.ExecContext(context.WithoutCancel(ctx), "ROLLBACK")
+		return fmt.Errorf("commit tx: %w", err)
+	}
+	return nil
+}
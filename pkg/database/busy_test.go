@@ -0,0 +1,75 @@
+// Package database - Busy Handling Tests
+// Unit tests cho WithTx: lock ngắn được retry, lock kéo dài thì lỗi sau timeout
+package database
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// holdWriteLock takes the write lock on path from another connection until release is called
+func holdWriteLock(t *testing.T, path string) (release func()) {
+	t.Helper()
+	other, err := sql.Open("sqlite", busyDSN(path, 0))
+	if err != nil {
+		t.Fatalf("open second connection: %v", err)
+	}
+	t.Cleanup(func() { other.Close() })
+
+	conn, err := other.Conn(context.Background())
+	if err != nil {
+		t.Fatalf("conn: %v", err)
+	}
+	if _, err := conn.ExecContext(context.Background(), "BEGIN IMMEDIATE"); err != nil {
+		t.Fatalf("take write lock: %v", err)
+	}
+	return func() {
+		conn.ExecContext(context.Background(), "COMMIT")
+		conn.Close()
+	}
+}
+
+func TestWithTxRetriesBusyWrites(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "busy.db")
+	db, err := sql.Open("sqlite", busyDSN(path, 20*time.Millisecond))
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	defer db.Close()
+	if _, err := db.Exec("CREATE TABLE kv (k TEXT PRIMARY KEY, v TEXT)"); err != nil {
+		t.Fatalf("create table: %v", err)
+	}
+
+	defer func(window time.Duration) { busyRetryWindow = window }(busyRetryWindow)
+	busyRetryWindow = 2 * time.Second
+
+	write := func() error {
+		return WithTx(context.Background(), db, func(tx *sql.Tx) error {
+			_, err := tx.Exec("INSERT OR REPLACE INTO kv (k, v) VALUES ('a', 'b')")
+			return err
+		})
+	}
+
+	// A lock released after a few busy timeouts is retried past
+	release := holdWriteLock(t, path)
+	time.AfterFunc(100*time.Millisecond, release)
+	if err := write(); err != nil {
+		t.Fatalf("write during brief contention: %v", err)
+	}
+
+	// A lock that is never released fails once the retry window is over
+	busyRetryWindow = 200 * time.Millisecond
+	release = holdWriteLock(t, path)
+	defer release()
+	start := time.Now()
+	err = write()
+	if !IsBusy(err) {
+		t.Fatalf("write under a held lock: got %v, want a busy error", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("stuck write took %s to fail", elapsed)
+	}
+}
@@ -0,0 +1,47 @@
+// Package database - Worker Leases
+// Chọn một instance duy nhất chạy background job khi nhiều server dùng chung DB
+// Chức năng:
+//   - AcquireLease: lấy hoặc gia hạn lease theo tên
+//   - ReleaseLease: trả lease khi dừng để instance khác nhận ngay
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// AcquireLease takes the named lease for holder, or renews it if holder
+// already has it, until ttl from now. It reports false while another
+// holder's lease has not expired.
+func AcquireLease(ctx context.Context, db *sql.DB, name, holder string, ttl time.Duration) (bool, error) {
+	res, err := db.ExecContext(ctx, `
+		INSERT INTO worker_leases (name, holder, expires_at)
+		VALUES (?, ?, datetime('now', ?))
+		ON CONFLICT(name) DO UPDATE SET
+			holder = excluded.holder,
+			expires_at = excluded.expires_at
+		WHERE worker_leases.holder = excluded.holder
+		   OR worker_leases.expires_at <= datetime('now')`,
+		name, holder, fmt.Sprintf("+%d seconds", int(ttl.Seconds())),
+	)
+	if err != nil {
+		return false, fmt.Errorf("acquire lease %s: %w", name, err)
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("acquire lease %s: %w", name, err)
+	}
+	return n > 0, nil
+}
+
+// ReleaseLease drops the named lease if holder still has it
+func ReleaseLease(ctx context.Context, db *sql.DB, name, holder string) error {
+	if _, err := db.ExecContext(ctx,
+		`DELETE FROM worker_leases WHERE name = ? AND holder = ?`, name, holder,
+	); err != nil {
+		return fmt.Errorf("release lease %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,56 @@
+// Package database - Worker Lease Tests
+// Unit tests cho lease: một holder tại một thời điểm, hết hạn thì chuyển giao
+package database
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestLeaseHasOneHolderAtATime(t *testing.T) {
+	db := openEmptyDB(t)
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+	ctx := context.Background()
+
+	acquire := func(holder string) bool {
+		t.Helper()
+		ok, err := AcquireLease(ctx, db.DB, "job", holder, time.Minute)
+		if err != nil {
+			t.Fatalf("AcquireLease(%s) failed: %v", holder, err)
+		}
+		return ok
+	}
+
+	if !acquire("a") {
+		t.Fatal("expected a to take the free lease")
+	}
+	if acquire("b") {
+		t.Fatal("expected b to be refused while a holds the lease")
+	}
+	if !acquire("a") {
+		t.Fatal("expected a to renew its own lease")
+	}
+
+	// An expired lease goes to whoever asks next
+	db.Exec(`UPDATE worker_leases SET expires_at = datetime('now', '-1 seconds')`)
+	if !acquire("b") {
+		t.Fatal("expected b to take over the expired lease")
+	}
+
+	// Releasing someone else's lease is a no-op; releasing your own frees it
+	if err := ReleaseLease(ctx, db.DB, "job", "a"); err != nil {
+		t.Fatalf("ReleaseLease failed: %v", err)
+	}
+	if acquire("a") {
+		t.Fatal("expected b to keep the lease after a released")
+	}
+	if err := ReleaseLease(ctx, db.DB, "job", "b"); err != nil {
+		t.Fatalf("ReleaseLease failed: %v", err)
+	}
+	if !acquire("a") {
+		t.Fatal("expected a to take the released lease")
+	}
+}
@@ -0,0 +1,971 @@
+// Package database - Versioned Schema Migrations
+// Danh sách migration được đánh số, mỗi migration chạy đúng một lần
+// Chức năng:
+//   - schema_migrations ghi lại các version đã apply
+//   - Mỗi migration chạy trong một transaction cùng với việc ghi version
+//   - Version 1 là schema gốc (idempotent) để DB cũ nâng cấp được
+//
+// Thêm thay đổi schema mới bằng cách append migration với version kế tiếp.
+// Không sửa migration đã release: DB đã apply sẽ không chạy lại nó.
+package database
+
+// migration is one numbered schema change
+type migration struct {
+	Version int
+	Name    string
+	Up      string
+}
+
+// migrations are applied in order by Migrate
+var migrations = []migration{
+	{
+		Version: 1,
+		Name:    "baseline schema",
+		// IF NOT EXISTS so databases created before schema_migrations upgrade cleanly
+		Up: `
+	-- ===== Core Tables =====
+	CREATE TABLE IF NOT EXISTS users (
+		id TEXT PRIMARY KEY,
+		username TEXT UNIQUE NOT NULL,
+		email TEXT UNIQUE NOT NULL,
+		password_hash TEXT NOT NULL,
+		display_name TEXT NOT NULL,
+		role TEXT DEFAULT 'user' CHECK (role IN ('user', 'admin', 'moderator')),
+		is_active BOOLEAN DEFAULT 1,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		last_login_at DATETIME
+	);
+
+	CREATE TABLE IF NOT EXISTS refresh_tokens (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		token_hash TEXT UNIQUE NOT NULL,
+		expires_at DATETIME NOT NULL,
+		revoked_at DATETIME,
+		replaced_by TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS manga (
+		id TEXT PRIMARY KEY,
+		title TEXT NOT NULL,
+		author TEXT,
+		artist TEXT,
+		description TEXT,
+		cover_url TEXT,
+		status TEXT DEFAULT 'ongoing' CHECK (status IN ('ongoing', 'completed', 'hiatus', 'cancelled')),
+		type TEXT DEFAULT 'manga' CHECK (type IN ('manga', 'manhwa', 'manhua', 'novel')),
+		total_chapters INTEGER DEFAULT 0,
+		average_rating REAL DEFAULT 0.0 CHECK (average_rating BETWEEN 0 AND 10),
+		rating_count INTEGER DEFAULT 0,
+		year INTEGER,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS genres (
+		id TEXT PRIMARY KEY,
+		name TEXT UNIQUE NOT NULL,
+		slug TEXT UNIQUE NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE TABLE IF NOT EXISTS manga_genres (
+		id TEXT PRIMARY KEY,
+		manga_id TEXT NOT NULL,
+		genre_id TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+		FOREIGN KEY (genre_id) REFERENCES genres(id) ON DELETE CASCADE,
+		UNIQUE(manga_id, genre_id)
+	);
+
+	-- ===== Full-text Search =====
+	CREATE VIRTUAL TABLE IF NOT EXISTS manga_fts USING fts5(
+		id UNINDEXED,
+		title,
+		author,
+		description,
+		content='manga'
+	);
+
+	CREATE TRIGGER IF NOT EXISTS manga_fts_insert AFTER INSERT ON manga BEGIN
+		INSERT INTO manga_fts(id, title, author, description)
+		VALUES (new.id, new.title, new.author, new.description);
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS manga_fts_update AFTER UPDATE ON manga BEGIN
+		UPDATE manga_fts SET title = new.title, author = new.author, description = new.description
+		WHERE id = new.id;
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS manga_fts_delete AFTER DELETE ON manga BEGIN
+		DELETE FROM manga_fts WHERE id = old.id;
+	END;
+
+	-- ===== External IDs =====
+	CREATE TABLE IF NOT EXISTS manga_external_ids (
+		manga_id TEXT PRIMARY KEY,
+		mangadex_id TEXT,
+		anilist_id INTEGER,
+		mal_id INTEGER,
+		kitsu_id TEXT,
+		primary_source TEXT DEFAULT 'mangadex',
+		last_synced_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+	);
+
+	-- ===== User Reading Progress =====
+	CREATE TABLE IF NOT EXISTS reading_progress (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		manga_id TEXT NOT NULL,
+		current_chapter INTEGER DEFAULT 0,
+		status TEXT DEFAULT 'plan_to_read' CHECK (status IN ('plan_to_read', 'reading', 'completed', 'on_hold', 'dropped')),
+		is_favorite BOOLEAN DEFAULT 0,
+		started_at DATETIME,
+		completed_at DATETIME,
+		last_read_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+		UNIQUE(user_id, manga_id)
+	);
+
+	-- ===== Library Import Queue =====
+	-- Bulk-imported entries whose manga is not in the DB yet; drained by data-cli
+	CREATE TABLE IF NOT EXISTS library_import_queue (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		source TEXT NOT NULL CHECK (source IN ('mal', 'mangadex', 'anilist', 'kitsu')),
+		external_id TEXT NOT NULL,
+		title TEXT DEFAULT '',
+		status TEXT DEFAULT 'plan_to_read',
+		current_chapter INTEGER DEFAULT 0,
+		is_favorite BOOLEAN DEFAULT 0,
+		state TEXT DEFAULT 'pending' CHECK (state IN ('pending', 'done', 'failed')),
+		last_error TEXT DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		UNIQUE(user_id, source, external_id)
+	);
+
+	-- ===== Chapter Reading History =====
+	CREATE TABLE IF NOT EXISTS chapter_history (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		manga_id TEXT NOT NULL,
+		chapter_number INTEGER NOT NULL,
+		pages_read INTEGER DEFAULT 0,
+		time_minutes INTEGER DEFAULT 0,
+		read_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+	);
+
+	-- ===== Ratings =====
+	CREATE TABLE IF NOT EXISTS manga_ratings (
+		id TEXT PRIMARY KEY,
+		manga_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		rating INTEGER NOT NULL CHECK (rating BETWEEN 1 AND 10),
+		review_text TEXT,
+		is_spoiler BOOLEAN DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		UNIQUE(manga_id, user_id)
+	);
+
+	CREATE TRIGGER IF NOT EXISTS update_manga_rating_insert AFTER INSERT ON manga_ratings BEGIN
+		UPDATE manga 
+		SET average_rating = (SELECT AVG(rating) FROM manga_ratings WHERE manga_id = new.manga_id),
+			rating_count = (SELECT COUNT(*) FROM manga_ratings WHERE manga_id = new.manga_id)
+		WHERE id = new.manga_id;
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS update_manga_rating_update AFTER UPDATE ON manga_ratings BEGIN
+		UPDATE manga 
+		SET average_rating = (SELECT AVG(rating) FROM manga_ratings WHERE manga_id = new.manga_id)
+		WHERE id = new.manga_id;
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS update_manga_rating_delete AFTER DELETE ON manga_ratings BEGIN
+		UPDATE manga 
+		SET average_rating = (SELECT COALESCE(AVG(rating), 0) FROM manga_ratings WHERE manga_id = old.manga_id),
+			rating_count = (SELECT COUNT(*) FROM manga_ratings WHERE manga_id = old.manga_id)
+		WHERE id = old.manga_id;
+	END;
+
+	-- ===== Comments =====
+	CREATE TABLE IF NOT EXISTS comments (
+		id TEXT PRIMARY KEY,
+		manga_id TEXT NOT NULL,
+		chapter_number INTEGER,
+		user_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		parent_id TEXT,
+		likes_count INTEGER DEFAULT 0,
+		is_spoiler BOOLEAN DEFAULT 0,
+		is_edited BOOLEAN DEFAULT 0,
+		is_deleted BOOLEAN DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (parent_id) REFERENCES comments(id) ON DELETE SET NULL
+	);
+
+	CREATE TABLE IF NOT EXISTS comment_likes (
+		id TEXT PRIMARY KEY,
+		comment_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (comment_id) REFERENCES comments(id) ON DELETE CASCADE,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		UNIQUE(comment_id, user_id)
+	);
+
+	CREATE TRIGGER IF NOT EXISTS increment_comment_likes AFTER INSERT ON comment_likes BEGIN
+		UPDATE comments SET likes_count = likes_count + 1 WHERE id = new.comment_id;
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS decrement_comment_likes AFTER DELETE ON comment_likes BEGIN
+		UPDATE comments SET likes_count = likes_count - 1 WHERE id = old.comment_id;
+	END;
+
+	-- ===== Chat =====
+	CREATE TABLE IF NOT EXISTS chat_rooms (
+		id TEXT PRIMARY KEY,
+		name TEXT NOT NULL,
+		room_type TEXT DEFAULT 'manga' CHECK (room_type IN ('general', 'manga')),
+		manga_id TEXT,
+		owner_id TEXT NOT NULL,
+		description TEXT,
+		is_active BOOLEAN DEFAULT 1,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE SET NULL,
+		FOREIGN KEY (owner_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS chat_room_members (
+		id TEXT PRIMARY KEY,
+		room_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		role TEXT DEFAULT 'member' CHECK (role IN ('owner', 'moderator', 'member')),
+		joined_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		last_read_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (room_id) REFERENCES chat_rooms(id) ON DELETE CASCADE,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		UNIQUE(room_id, user_id)
+	);
+
+	CREATE TABLE IF NOT EXISTS chat_messages (
+		id TEXT PRIMARY KEY,
+		room_id TEXT NOT NULL,
+		user_id TEXT NOT NULL,
+		content TEXT NOT NULL,
+		reply_to_id TEXT,
+		is_edited BOOLEAN DEFAULT 0,
+		is_deleted BOOLEAN DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (room_id) REFERENCES chat_rooms(id) ON DELETE CASCADE,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	-- ===== Custom Lists =====
+	CREATE TABLE IF NOT EXISTS custom_lists (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		description TEXT,
+		is_public BOOLEAN DEFAULT 0,
+		sort_order INTEGER DEFAULT 0,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE TABLE IF NOT EXISTS custom_list_items (
+		id TEXT PRIMARY KEY,
+		list_id TEXT NOT NULL,
+		manga_id TEXT NOT NULL,
+		notes TEXT,
+		sort_order INTEGER DEFAULT 0,
+		added_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (list_id) REFERENCES custom_lists(id) ON DELETE CASCADE,
+		FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE,
+		UNIQUE(list_id, manga_id)
+	);
+
+	-- ===== Activity Feed =====
+	CREATE TABLE IF NOT EXISTS activity_feed (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		username TEXT NOT NULL,
+		activity_type TEXT NOT NULL CHECK (activity_type IN ('comment', 'rating', 'progress', 'list_add')),
+		manga_id TEXT NOT NULL,
+		manga_title TEXT NOT NULL,
+		chapter_number INTEGER,
+		rating REAL,
+		comment_text TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+	);
+
+	CREATE TRIGGER IF NOT EXISTS activity_on_comment AFTER INSERT ON comments BEGIN
+		INSERT INTO activity_feed (id, user_id, username, activity_type, manga_id, manga_title, chapter_number, comment_text, created_at)
+		SELECT
+			'act-' || new.id,
+			new.user_id,
+			u.username,
+			'comment',
+			new.manga_id,
+			m.title,
+			new.chapter_number,
+			new.content,
+			new.created_at
+		FROM users u, manga m
+		WHERE u.id = new.user_id AND m.id = new.manga_id;
+	END;
+
+	CREATE TRIGGER IF NOT EXISTS activity_on_rating AFTER INSERT ON manga_ratings BEGIN
+		INSERT INTO activity_feed (id, user_id, username, activity_type, manga_id, manga_title, rating, created_at)
+		SELECT
+			'act-' || new.id,
+			new.user_id,
+			u.username,
+			'rating',
+			new.manga_id,
+			m.title,
+			new.rating,
+			new.created_at
+		FROM users u, manga m
+		WHERE u.id = new.user_id AND m.id = new.manga_id;
+	END;
+
+	-- ===== Indexes =====
+	CREATE INDEX IF NOT EXISTS idx_users_username ON users(username);
+	CREATE INDEX IF NOT EXISTS idx_users_email ON users(email);
+	CREATE INDEX IF NOT EXISTS idx_refresh_tokens_user ON refresh_tokens(user_id);
+	CREATE INDEX IF NOT EXISTS idx_manga_title ON manga(title);
+	CREATE INDEX IF NOT EXISTS idx_manga_status ON manga(status);
+	CREATE INDEX IF NOT EXISTS idx_manga_type ON manga(type);
+	CREATE INDEX IF NOT EXISTS idx_manga_rating ON manga(average_rating DESC);
+	CREATE INDEX IF NOT EXISTS idx_manga_genres_manga ON manga_genres(manga_id);
+	CREATE INDEX IF NOT EXISTS idx_manga_genres_genre ON manga_genres(genre_id);
+	CREATE INDEX IF NOT EXISTS idx_external_mangadex ON manga_external_ids(mangadex_id);
+	CREATE INDEX IF NOT EXISTS idx_external_mal ON manga_external_ids(mal_id);
+	CREATE INDEX IF NOT EXISTS idx_external_anilist ON manga_external_ids(anilist_id);
+	CREATE INDEX IF NOT EXISTS idx_progress_user ON reading_progress(user_id);
+	CREATE INDEX IF NOT EXISTS idx_progress_manga ON reading_progress(manga_id);
+	CREATE INDEX IF NOT EXISTS idx_progress_status ON reading_progress(status);
+	CREATE INDEX IF NOT EXISTS idx_progress_favorite ON reading_progress(is_favorite) WHERE is_favorite = 1;
+	CREATE INDEX IF NOT EXISTS idx_progress_last_read ON reading_progress(last_read_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_progress_user_updated ON reading_progress(user_id, julianday(updated_at));
+	CREATE INDEX IF NOT EXISTS idx_import_queue_state ON library_import_queue(state, created_at);
+	CREATE INDEX IF NOT EXISTS idx_chapter_history_user ON chapter_history(user_id, read_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_chapter_history_manga ON chapter_history(manga_id);
+	CREATE INDEX IF NOT EXISTS idx_ratings_manga ON manga_ratings(manga_id);
+	CREATE INDEX IF NOT EXISTS idx_ratings_user ON manga_ratings(user_id);
+	CREATE INDEX IF NOT EXISTS idx_ratings_created ON manga_ratings(created_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_comments_manga ON comments(manga_id);
+	CREATE INDEX IF NOT EXISTS idx_comments_chapter ON comments(manga_id, chapter_number);
+	CREATE INDEX IF NOT EXISTS idx_comments_user ON comments(user_id);
+	CREATE INDEX IF NOT EXISTS idx_comments_parent ON comments(parent_id);
+	CREATE INDEX IF NOT EXISTS idx_comments_created ON comments(created_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_comment_likes_comment ON comment_likes(comment_id);
+	CREATE INDEX IF NOT EXISTS idx_comment_likes_user ON comment_likes(user_id);
+	CREATE INDEX IF NOT EXISTS idx_chat_rooms_type ON chat_rooms(room_type);
+	CREATE INDEX IF NOT EXISTS idx_chat_rooms_manga ON chat_rooms(manga_id);
+	CREATE INDEX IF NOT EXISTS idx_room_members_room ON chat_room_members(room_id);
+	CREATE INDEX IF NOT EXISTS idx_room_members_user ON chat_room_members(user_id);
+	CREATE INDEX IF NOT EXISTS idx_chat_messages_room ON chat_messages(room_id);
+	CREATE INDEX IF NOT EXISTS idx_chat_messages_created ON chat_messages(created_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_custom_lists_user ON custom_lists(user_id);
+	CREATE INDEX IF NOT EXISTS idx_custom_list_items_list ON custom_list_items(list_id);
+	CREATE INDEX IF NOT EXISTS idx_custom_list_items_manga ON custom_list_items(manga_id);
+	CREATE INDEX IF NOT EXISTS idx_activity_created ON activity_feed(created_at DESC);
+	CREATE INDEX IF NOT EXISTS idx_activity_user ON activity_feed(user_id);
+	CREATE INDEX IF NOT EXISTS idx_activity_manga ON activity_feed(manga_id);
+	CREATE INDEX IF NOT EXISTS idx_activity_type ON activity_feed(activity_type);
+`,
+	},
+	{
+		Version: 2,
+		Name:    "daily stats and user preferences",
+		Up: `
+	-- ===== Daily Reading Stats =====
+	-- One row per user per day, rolled up from chapter_history
+	CREATE TABLE daily_stats (
+		user_id TEXT NOT NULL,
+		stat_date TEXT NOT NULL, -- YYYY-MM-DD (UTC)
+		chapters_read INTEGER DEFAULT 0,
+		pages_read INTEGER DEFAULT 0,
+		time_minutes INTEGER DEFAULT 0,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (user_id, stat_date),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	INSERT INTO daily_stats (user_id, stat_date, chapters_read, pages_read, time_minutes)
+	SELECT user_id, date(read_at), COUNT(*), SUM(pages_read), SUM(time_minutes)
+	FROM chapter_history
+	GROUP BY user_id, date(read_at);
+
+	-- ===== User Preferences =====
+	CREATE TABLE user_preferences (
+		user_id TEXT PRIMARY KEY,
+		theme TEXT DEFAULT 'dark',
+		language TEXT DEFAULT 'en',
+		default_status TEXT DEFAULT 'plan_to_read',
+		notifications_enabled BOOLEAN DEFAULT 1,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+`,
+	},
+	{
+		Version: 3,
+		Name:    "custom list icon, default flag and item count",
+		Up: `
+	ALTER TABLE custom_lists ADD COLUMN icon TEXT DEFAULT '';
+	ALTER TABLE custom_lists ADD COLUMN is_default BOOLEAN DEFAULT 0;
+	ALTER TABLE custom_lists ADD COLUMN item_count INTEGER DEFAULT 0;
+	ALTER TABLE custom_list_items ADD COLUMN created_at DATETIME;
+
+	UPDATE custom_list_items SET created_at = added_at;
+	UPDATE custom_lists
+	SET item_count = (SELECT COUNT(*) FROM custom_list_items WHERE list_id = custom_lists.id);
+
+	CREATE TRIGGER custom_list_items_count_insert AFTER INSERT ON custom_list_items BEGIN
+		UPDATE custom_lists SET item_count = item_count + 1 WHERE id = new.list_id;
+	END;
+
+	CREATE TRIGGER custom_list_items_count_delete AFTER DELETE ON custom_list_items BEGIN
+		UPDATE custom_lists SET item_count = item_count - 1 WHERE id = old.list_id;
+	END;
+`,
+	},
+	{
+		Version: 4,
+		Name:    "audit log",
+		Up: `
+	-- ===== Audit Log =====
+	-- Privileged or destructive actions, kept after the target is gone
+	CREATE TABLE audit_log (
+		id TEXT PRIMARY KEY,
+		actor_id TEXT NOT NULL,
+		action TEXT NOT NULL, -- e.g. comment.delete, comment.moderate
+		target_type TEXT NOT NULL,
+		target_id TEXT NOT NULL,
+		details TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP
+	);
+
+	CREATE INDEX idx_audit_log_target ON audit_log(target_type, target_id);
+	CREATE INDEX idx_audit_log_actor ON audit_log(actor_id, created_at DESC);
+`,
+	},
+	{
+		Version: 5,
+		Name:    "reading goals",
+		Up: `
+	-- ===== Reading Goals =====
+	-- One row per user per period window; kept separate from user_preferences
+	CREATE TABLE reading_goals (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		period TEXT NOT NULL CHECK(period IN ('yearly', 'monthly')),
+		target_chapters INTEGER NOT NULL CHECK(target_chapters > 0),
+		start_date TEXT NOT NULL, -- YYYY-MM-DD (UTC)
+		end_date TEXT NOT NULL,   -- YYYY-MM-DD (UTC), inclusive
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		UNIQUE(user_id, period, start_date),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX idx_reading_goals_user ON reading_goals(user_id, period, end_date DESC);
+`,
+	},
+	{
+		Version: 6,
+		Name:    "notification mutes",
+		Up: `
+	-- ===== Notification Mutes =====
+	-- A row silences chapter_release pushes for one manga; deleting it resumes them
+	CREATE TABLE notification_mutes (
+		user_id TEXT NOT NULL,
+		manga_id TEXT NOT NULL,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (user_id, manga_id),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX idx_notification_mutes_manga ON notification_mutes(manga_id);
+`,
+	},
+	{
+		Version: 7,
+		Name:    "review edits and spoiler preference",
+		Up: `
+	-- ===== Reviews =====
+	-- is_edited: the review text changed after it was first written
+	ALTER TABLE manga_ratings ADD COLUMN is_edited BOOLEAN DEFAULT 0;
+	ALTER TABLE manga_ratings ADD COLUMN helpful_count INTEGER DEFAULT 0;
+
+	CREATE INDEX idx_ratings_manga_helpful ON manga_ratings(manga_id, helpful_count DESC, created_at DESC);
+
+	-- ===== Preferences =====
+	-- Spoiler reviews stay collapsed unless the user opts in
+	ALTER TABLE user_preferences ADD COLUMN show_spoilers BOOLEAN DEFAULT 0;
+`,
+	},
+	{
+		Version: 8,
+		Name:    "profile privacy",
+		Up: `
+	-- What GET /users/:username shows to other people; public unless the user opts out
+	ALTER TABLE user_preferences ADD COLUMN activity_public BOOLEAN DEFAULT 1;
+	ALTER TABLE user_preferences ADD COLUMN library_public BOOLEAN DEFAULT 1;
+`,
+	},
+	{
+		Version: 9,
+		Name:    "daily manga count",
+		Up: `
+	-- Distinct manga read per day, kept in step when history is recorded or undone
+	ALTER TABLE daily_stats ADD COLUMN manga_count INTEGER DEFAULT 0;
+
+	UPDATE daily_stats SET manga_count = (
+		SELECT COUNT(DISTINCT ch.manga_id)
+		FROM chapter_history ch
+		WHERE ch.user_id = daily_stats.user_id AND date(ch.read_at) = daily_stats.stat_date
+	);
+`,
+	},
+	{
+		Version: 10,
+		Name:    "chapters",
+		Up: `
+	-- ===== Chapters =====
+	-- Chapter metadata filled by imports; numbers match reading_progress.current_chapter
+	CREATE TABLE chapters (
+		manga_id TEXT NOT NULL,
+		number INTEGER NOT NULL CHECK(number > 0),
+		title TEXT DEFAULT '',
+		released_at DATETIME,
+		external_id TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (manga_id, number),
+		FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+	);
+`,
+	},
+	{
+		Version: 11,
+		Name:    "streak_state",
+		Up: `
+	-- ===== Streak State =====
+	-- Grace days consumed by each user's streak, as last computed from daily_stats
+	CREATE TABLE streak_state (
+		user_id TEXT PRIMARY KEY,
+		current_streak INTEGER NOT NULL DEFAULT 0,
+		grace_period TEXT NOT NULL DEFAULT '',
+		grace_used INTEGER NOT NULL DEFAULT 0,
+		grace_dates TEXT NOT NULL DEFAULT '',
+		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+`,
+	},
+	{
+		Version: 12,
+		Name:    "manga sort indexes",
+		Up: `
+	-- GET /manga?sort=year|chapters; rating and title use idx_manga_rating and idx_manga_title
+	CREATE INDEX IF NOT EXISTS idx_manga_year ON manga(year DESC);
+	CREATE INDEX IF NOT EXISTS idx_manga_chapters ON manga(total_chapters DESC);
+`,
+	},
+	{
+		Version: 13,
+		Name:    "email verification",
+		Up: `
+	-- New accounts start unverified; accounts created before verification existed keep full access
+	ALTER TABLE users ADD COLUMN email_verified BOOLEAN DEFAULT 0;
+	UPDATE users SET email_verified = 1;
+
+	-- ===== Email Verification Tokens =====
+	-- Only the SHA-256 of a token is stored; issuing a new token revokes older unused ones
+	CREATE TABLE email_verification_tokens (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		token_hash TEXT UNIQUE NOT NULL,
+		expires_at DATETIME NOT NULL,
+		used_at DATETIME,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX idx_email_verification_user ON email_verification_tokens(user_id);
+`,
+	},
+	{
+		Version: 14,
+		Name:    "manga status history",
+		Up: `
+	-- ===== Manga Status History =====
+	-- One row per actual status change; inserts (imports) are not transitions
+	CREATE TABLE manga_status_history (
+		id INTEGER PRIMARY KEY AUTOINCREMENT,
+		manga_id TEXT NOT NULL,
+		old_status TEXT NOT NULL,
+		new_status TEXT NOT NULL,
+		changed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX idx_manga_status_history_manga ON manga_status_history(manga_id, changed_at DESC);
+
+	-- When the manga last became completed; cleared when it leaves completed
+	ALTER TABLE manga ADD COLUMN completed_at DATETIME;
+
+	-- ===== Activity Feed =====
+	-- status_change entries belong to no user, so user_id becomes nullable.
+	-- SQLite cannot alter NOT NULL or CHECK constraints, so the table is rebuilt;
+	-- rowids are copied because the feed tail cursor (GetAfter) is the rowid.
+	DROP TRIGGER IF EXISTS activity_on_comment;
+	DROP TRIGGER IF EXISTS activity_on_rating;
+
+	CREATE TABLE activity_feed_new (
+		id TEXT PRIMARY KEY,
+		user_id TEXT,
+		username TEXT NOT NULL DEFAULT '',
+		activity_type TEXT NOT NULL CHECK (activity_type IN ('comment', 'rating', 'progress', 'list_add', 'status_change')),
+		manga_id TEXT NOT NULL,
+		manga_title TEXT NOT NULL,
+		chapter_number INTEGER,
+		rating REAL,
+		comment_text TEXT,
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+	);
+
+	INSERT INTO activity_feed_new (rowid, id, user_id, username, activity_type, manga_id, manga_title, chapter_number, rating, comment_text, created_at)
+	SELECT rowid, id, user_id, username, activity_type, manga_id, manga_title, chapter_number, rating, comment_text, created_at
+	FROM activity_feed;
+
+	DROP TABLE activity_feed;
+	ALTER TABLE activity_feed_new RENAME TO activity_feed;
+
+	CREATE INDEX idx_activity_created ON activity_feed(created_at DESC);
+	CREATE INDEX idx_activity_user ON activity_feed(user_id);
+	CREATE INDEX idx_activity_manga ON activity_feed(manga_id);
+	CREATE INDEX idx_activity_type ON activity_feed(activity_type);
+
+	CREATE TRIGGER activity_on_comment AFTER INSERT ON comments BEGIN
+		INSERT INTO activity_feed (id, user_id, username, activity_type, manga_id, manga_title, chapter_number, comment_text, created_at)
+		SELECT
+			'act-' || new.id,
+			new.user_id,
+			u.username,
+			'comment',
+			new.manga_id,
+			m.title,
+			new.chapter_number,
+			new.content,
+			new.created_at
+		FROM users u, manga m
+		WHERE u.id = new.user_id AND m.id = new.manga_id;
+	END;
+
+	CREATE TRIGGER activity_on_rating AFTER INSERT ON manga_ratings BEGIN
+		INSERT INTO activity_feed (id, user_id, username, activity_type, manga_id, manga_title, rating, created_at)
+		SELECT
+			'act-' || new.id,
+			new.user_id,
+			u.username,
+			'rating',
+			new.manga_id,
+			m.title,
+			new.rating,
+			new.created_at
+		FROM users u, manga m
+		WHERE u.id = new.user_id AND m.id = new.manga_id;
+	END;
+
+	-- Every update path (resync, gRPC UpdateManga, data-cli) goes through here;
+	-- writing the same status again is not a change
+	CREATE TRIGGER manga_status_change AFTER UPDATE OF status ON manga
+	WHEN old.status IS NOT new.status BEGIN
+		INSERT INTO manga_status_history (manga_id, old_status, new_status)
+		VALUES (new.id, COALESCE(old.status, ''), COALESCE(new.status, ''));
+
+		UPDATE manga SET completed_at = CASE WHEN new.status = 'completed' THEN CURRENT_TIMESTAMP END
+		WHERE id = new.id;
+
+		INSERT INTO activity_feed (id, user_id, username, activity_type, manga_id, manga_title, comment_text, created_at)
+		VALUES ('act-status-' || lower(hex(randomblob(8))), NULL, '', 'status_change', new.id, new.title,
+		        COALESCE(old.status, '') || ' → ' || COALESCE(new.status, ''), CURRENT_TIMESTAMP);
+	END;
+`,
+	},
+	{
+		Version: 15,
+		Name:    "import idempotency",
+		Up: `
+	-- ===== Processed Import Records =====
+	-- Scoped by idempotency key and external source; a changed fingerprint
+	-- means the source record changed and is imported again
+	CREATE TABLE import_processed (
+		idempotency_key TEXT NOT NULL,
+		source TEXT NOT NULL,
+		external_id TEXT NOT NULL,
+		fingerprint TEXT NOT NULL,
+		manga_id TEXT NOT NULL,
+		processed_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		PRIMARY KEY (idempotency_key, source, external_id)
+	);
+`,
+	},
+	{
+		Version: 16,
+		Name:    "tui startup preferences",
+		Up: `
+	-- View the TUI opens after login, and whether it joins UDP notifications then
+	ALTER TABLE user_preferences ADD COLUMN default_view TEXT DEFAULT 'dashboard';
+	ALTER TABLE user_preferences ADD COLUMN auto_connect_notifications BOOLEAN DEFAULT 1;
+`,
+	},
+	{
+		Version: 17,
+		Name:    "email digests",
+		Up: `
+	-- Daily digest of new chapters by email; users opt in
+	ALTER TABLE user_preferences ADD COLUMN email_notifications BOOLEAN DEFAULT 0;
+
+	-- ===== Email Digests =====
+	-- When each user's last digest was sent; the next one covers chapters added since
+	CREATE TABLE email_digests (
+		user_id TEXT PRIMARY KEY,
+		last_sent_at DATETIME NOT NULL,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX idx_chapters_created ON chapters(created_at);
+`,
+	},
+	{
+		Version: 18,
+		Name:    "search history and saved searches",
+		Up: `
+	-- ===== Search History =====
+	-- One row per distinct query + filters; searching again bumps searched_at.
+	-- The repository trims each user to the newest models.MaxRecentSearches rows.
+	CREATE TABLE search_history (
+		user_id TEXT NOT NULL,
+		query TEXT NOT NULL DEFAULT '',
+		genre TEXT NOT NULL DEFAULT '',
+		sort TEXT NOT NULL DEFAULT '',
+		sort_order TEXT NOT NULL DEFAULT '',
+		searched_at DATETIME NOT NULL,
+		PRIMARY KEY (user_id, query, genre, sort, sort_order),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX idx_search_history_user ON search_history(user_id, searched_at DESC);
+
+	-- ===== Saved Searches =====
+	CREATE TABLE saved_searches (
+		id TEXT PRIMARY KEY,
+		user_id TEXT NOT NULL,
+		name TEXT NOT NULL,
+		query TEXT NOT NULL DEFAULT '',
+		genre TEXT NOT NULL DEFAULT '',
+		sort TEXT NOT NULL DEFAULT '',
+		sort_order TEXT NOT NULL DEFAULT '',
+		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+		UNIQUE(user_id, name)
+	);
+`,
+	},
+	{
+		Version: 19,
+		Name:    "comment keyset pagination",
+		Up: `
+	-- Comment pages are keyset-paginated on (created_at, id) within a manga.
+	-- idx_comments_created orders the whole table, so a busy manga's deep pages
+	-- still filtered every other manga's rows; these walk one manga's (or one
+	-- chapter's) comments in page order.
+	CREATE INDEX idx_comments_manga_created ON comments(manga_id, created_at DESC, id DESC);
+	DROP INDEX IF EXISTS idx_comments_chapter;
+	CREATE INDEX idx_comments_chapter ON comments(manga_id, chapter_number, created_at DESC, id DESC);
+`,
+	},
+	{
+		Version: 20,
+		Name:    "continue reading index",
+		Up: `
+	-- GET /users/continue and the library list read one user's progress by
+	-- recency; the old index ordered every user's rows together
+	DROP INDEX IF EXISTS idx_progress_last_read;
+	CREATE INDEX idx_progress_last_read ON reading_progress(user_id, last_read_at DESC);
+`,
+	},
+	{
+		Version: 21,
+		Name:    "manga field provenance",
+		Up: `
+	-- ===== Manga Field Provenance =====
+	-- Which source last wrote each imported field; manga_external_ids keeps the
+	-- IDs and last_synced_at. source 'manual' marks an admin edit, which imports
+	-- and resyncs leave alone.
+	CREATE TABLE manga_field_provenance (
+		manga_id TEXT NOT NULL,
+		field TEXT NOT NULL,
+		source TEXT NOT NULL,
+		external_id TEXT,
+		updated_at DATETIME NOT NULL,
+		PRIMARY KEY (manga_id, field),
+		FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+	);
+`,
+	},
+	{
+		Version: 22,
+		Name:    "manga_fts external content triggers",
+		Up: `
+	-- manga_fts reads its content from manga by rowid. Updating or deleting the
+	-- FTS row after manga has changed removed tokens that were never indexed,
+	-- and the second edit of a manga failed with "database disk image is
+	-- malformed". The old values go through the 'delete' command instead, and
+	-- rows keep manga's rowid, which search joins on.
+	DROP TRIGGER IF EXISTS manga_fts_insert;
+	DROP TRIGGER IF EXISTS manga_fts_update;
+	DROP TRIGGER IF EXISTS manga_fts_delete;
+
+	CREATE TRIGGER manga_fts_insert AFTER INSERT ON manga BEGIN
+		INSERT INTO manga_fts(rowid, id, title, author, description)
+		VALUES (new.rowid, new.id, new.title, new.author, new.description);
+	END;
+
+	CREATE TRIGGER manga_fts_update AFTER UPDATE OF id, title, author, description ON manga BEGIN
+		INSERT INTO manga_fts(manga_fts, rowid, id, title, author, description)
+		VALUES ('delete', old.rowid, old.id, old.title, old.author, old.description);
+		INSERT INTO manga_fts(rowid, id, title, author, description)
+		VALUES (new.rowid, new.id, new.title, new.author, new.description);
+	END;
+
+	CREATE TRIGGER manga_fts_delete AFTER DELETE ON manga BEGIN
+		INSERT INTO manga_fts(manga_fts, rowid, id, title, author, description)
+		VALUES ('delete', old.rowid, old.id, old.title, old.author, old.description);
+	END;
+
+	-- Reindex from manga so the deletes above match what is indexed
+	INSERT INTO manga_fts(manga_fts) VALUES ('rebuild');
+`,
+	},
+	{
+		Version: 23,
+		Name:    "spoiler comments out of the activity feed",
+		Up: `
+	-- Comment listings mask spoilers for viewers who hide them; the feed copied
+	-- every comment's text to everyone. Spoilers now go in without it.
+	DROP TRIGGER IF EXISTS activity_on_comment;
+
+	CREATE TRIGGER activity_on_comment AFTER INSERT ON comments BEGIN
+		INSERT INTO activity_feed (id, user_id, username, activity_type, manga_id, manga_title, chapter_number, comment_text, created_at)
+		SELECT
+			'act-' || new.id,
+			new.user_id,
+			u.username,
+			'comment',
+			new.manga_id,
+			m.title,
+			new.chapter_number,
+			CASE WHEN new.is_spoiler THEN NULL ELSE new.content END,
+			new.created_at
+		FROM users u, manga m
+		WHERE u.id = new.user_id AND m.id = new.manga_id;
+	END;
+
+	-- A comment flagged as a spoiler after posting drops its text from the feed
+	CREATE TRIGGER activity_comment_spoiler AFTER UPDATE OF is_spoiler ON comments
+	WHEN new.is_spoiler BEGIN
+		UPDATE activity_feed SET comment_text = NULL WHERE id = 'act-' || new.id;
+	END;
+
+	UPDATE activity_feed SET comment_text = NULL
+	WHERE activity_type = 'comment'
+	  AND id IN (SELECT 'act-' || id FROM comments WHERE is_spoiler);
+`,
+	},
+	{
+		Version: 24,
+		Name:    "precomputed trending scores and worker leases",
+		Up: `
+	-- Written by the leaderboard worker, one row per window and manga;
+	-- the trending endpoint reads it while computed_at is recent
+	CREATE TABLE IF NOT EXISTS trending_scores (
+		trend_window TEXT NOT NULL,
+		manga_id TEXT NOT NULL,
+		score REAL NOT NULL,
+		total_ratings INTEGER NOT NULL DEFAULT 0,
+		total_readers INTEGER NOT NULL DEFAULT 0,
+		computed_at DATETIME NOT NULL,
+		PRIMARY KEY (trend_window, manga_id),
+		FOREIGN KEY (manga_id) REFERENCES manga(id) ON DELETE CASCADE
+	);
+
+	CREATE INDEX IF NOT EXISTS idx_trending_scores_rank ON trending_scores(trend_window, score DESC);
+
+	-- Singleton background jobs: the holder renews before expires_at,
+	-- another instance takes over once it lapses
+	CREATE TABLE IF NOT EXISTS worker_leases (
+		name TEXT PRIMARY KEY,
+		holder TEXT NOT NULL,
+		expires_at DATETIME NOT NULL
+	);
+`,
+	},
+	{
+		Version: 25,
+		Name:    "achievements",
+		Up: `
+	-- Badges a user has earned; the badges themselves (metric, threshold)
+	-- are defined in code, so new badges need no schema change
+	CREATE TABLE IF NOT EXISTS achievements (
+		user_id TEXT NOT NULL,
+		badge_id TEXT NOT NULL,
+		earned_at DATETIME NOT NULL,
+		PRIMARY KEY (user_id, badge_id),
+		FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE
+	);
+`,
+	},
+	{
+		Version: 26,
+		Name:    "tui keybindings preference",
+		Up: `
+	-- JSON object of action -> key overrides; NULL keeps every default
+	ALTER TABLE user_preferences ADD COLUMN keybindings TEXT;
+`,
+	},
+}
@@ -0,0 +1,214 @@
+// Package database - Migration Tests
+// Unit tests cho versioned migrations (DB mới, chạy lại, nâng cấp DB cũ)
+package database
+
+import (
+	"database/sql"
+	"path/filepath"
+	"testing"
+)
+
+// openEmptyDB opens a fresh database file without running migrations
+func openEmptyDB(t *testing.T) *DB {
+	sqlDB, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "mangahub.db"))
+	if err != nil {
+		t.Fatalf("failed to open db: %v", err)
+	}
+	sqlDB.SetMaxOpenConns(1)
+	t.Cleanup(func() { sqlDB.Close() })
+	return &DB{sqlDB}
+}
+
+// columnsOf returns the column names of a table
+func columnsOf(t *testing.T, db *DB, table string) map[string]bool {
+	rows, err := db.Query("SELECT name FROM pragma_table_info(?)", table)
+	if err != nil {
+		t.Fatalf("table_info %s: %v", table, err)
+	}
+	defer rows.Close()
+
+	cols := map[string]bool{}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("scan column: %v", err)
+		}
+		cols[name] = true
+	}
+	return cols
+}
+
+func TestMigrateCreatesEverySchemaObjectTheCodeReads(t *testing.T) {
+	db := openEmptyDB(t)
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	// Tables and the columns queried outside the original CREATE statements
+	want := map[string][]string{
+		"users":                     {"email_verified"},
+		"refresh_tokens":            nil,
+		"manga":                     {"average_rating", "rating_count", "completed_at"},
+		"genres":                    nil,
+		"manga_genres":              nil,
+		"manga_fts":                 nil,
+		"manga_external_ids":        nil,
+		"reading_progress":          nil,
+		"library_import_queue":      nil,
+		"chapter_history":           {"pages_read", "time_minutes"},
+		"daily_stats":               {"stat_date", "chapters_read", "time_minutes", "manga_count"},
+		"user_preferences":          {"theme", "language", "show_spoilers", "activity_public", "library_public", "keybindings"},
+		"manga_ratings":             {"is_spoiler", "is_edited", "helpful_count"},
+		"comments":                  nil,
+		"comment_likes":             nil,
+		"chat_rooms":                nil,
+		"chat_room_members":         nil,
+		"chat_messages":             nil,
+		"custom_lists":              {"icon", "is_default", "item_count"},
+		"custom_list_items":         {"created_at", "added_at"},
+		"activity_feed":             nil,
+		"notification_mutes":        nil,
+		"chapters":                  {"title", "released_at", "external_id"},
+		"streak_state":              {"current_streak", "grace_period", "grace_used", "grace_dates"},
+		"email_verification_tokens": {"token_hash", "expires_at", "used_at"},
+		"manga_status_history":      {"old_status", "new_status", "changed_at"},
+		"manga_field_provenance":    {"source", "external_id"},
+		"trending_scores":           {"trend_window", "score", "computed_at"},
+		"worker_leases":             {"holder", "expires_at"},
+		"achievements":              {"badge_id", "earned_at"},
+	}
+	for table, cols := range want {
+		have := columnsOf(t, db, table)
+		if len(have) == 0 {
+			t.Errorf("table %s is missing", table)
+			continue
+		}
+		for _, col := range cols {
+			if !have[col] {
+				t.Errorf("column %s.%s is missing", table, col)
+			}
+		}
+	}
+
+	version, err := db.SchemaVersion()
+	if err != nil {
+		t.Fatalf("SchemaVersion failed: %v", err)
+	}
+	if version != migrations[len(migrations)-1].Version {
+		t.Errorf("expected schema version %d, got %d", migrations[len(migrations)-1].Version, version)
+	}
+
+	// Running again must be a no-op
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("second Migrate failed: %v", err)
+	}
+	var applied int
+	db.QueryRow("SELECT COUNT(*) FROM schema_migrations").Scan(&applied)
+	if applied != len(migrations) {
+		t.Errorf("expected %d recorded migrations, got %d", len(migrations), applied)
+	}
+}
+
+func TestMangaFTSFollowsRepeatedEdits(t *testing.T) {
+	db := openEmptyDB(t)
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	steps := []string{
+		`INSERT INTO manga (id, title, author, description) VALUES ('m1', 'Berserk', 'Miura', 'Guts')`,
+		`INSERT INTO manga (id, title) VALUES ('m2', 'Vagabond')`,
+		`UPDATE manga SET description = 'Guts, a mercenary' WHERE id = 'm1'`,
+		`UPDATE manga SET title = 'Berserk Deluxe' WHERE id = 'm1'`,
+		`DELETE FROM manga WHERE id = 'm2'`,
+	}
+	for _, step := range steps {
+		if _, err := db.Exec(step); err != nil {
+			t.Fatalf("%s: %v", step, err)
+		}
+	}
+	if _, err := db.Exec(`INSERT INTO manga_fts(manga_fts) VALUES ('integrity-check')`); err != nil {
+		t.Fatalf("manga_fts integrity check failed: %v", err)
+	}
+
+	match := func(query string) int {
+		var n int
+		if err := db.QueryRow(`SELECT COUNT(*) FROM manga_fts WHERE manga_fts MATCH ?`, query).Scan(&n); err != nil {
+			t.Fatalf("MATCH %s: %v", query, err)
+		}
+		return n
+	}
+	if match("deluxe") != 1 || match("mercenary") != 1 || match("vagabond") != 0 {
+		t.Errorf("index does not follow edits: deluxe=%d mercenary=%d vagabond=%d",
+			match("deluxe"), match("mercenary"), match("vagabond"))
+	}
+}
+
+func TestSpoilerCommentsStayOutOfFeed(t *testing.T) {
+	db := openEmptyDB(t)
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	steps := []string{
+		`INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`,
+		`INSERT INTO manga (id, title) VALUES ('m1', 'Berserk')`,
+		`INSERT INTO comments (id, manga_id, user_id, content) VALUES ('c1', 'm1', 'u1', 'Great arc')`,
+		`INSERT INTO comments (id, manga_id, user_id, content, is_spoiler) VALUES ('c2', 'm1', 'u1', 'Guts survives', 1)`,
+		`INSERT INTO comments (id, manga_id, user_id, content) VALUES ('c3', 'm1', 'u1', 'Casca too')`,
+		`UPDATE comments SET is_spoiler = 1 WHERE id = 'c3'`,
+	}
+	for _, step := range steps {
+		if _, err := db.Exec(step); err != nil {
+			t.Fatalf("%s: %v", step, err)
+		}
+	}
+
+	feedText := func(commentID string) string {
+		var text sql.NullString
+		if err := db.QueryRow(`SELECT comment_text FROM activity_feed WHERE id = ?`, "act-"+commentID).Scan(&text); err != nil {
+			t.Fatalf("feed entry for %s: %v", commentID, err)
+		}
+		return text.String
+	}
+	if got := feedText("c1"); got != "Great arc" {
+		t.Errorf("plain comment feed text = %q", got)
+	}
+	for _, id := range []string{"c2", "c3"} {
+		if got := feedText(id); got != "" {
+			t.Errorf("spoiler comment %s leaked %q into the feed", id, got)
+		}
+	}
+}
+
+func TestMigrateUpgradesUnversionedDatabase(t *testing.T) {
+	db := openEmptyDB(t)
+
+	// A database created before schema_migrations existed
+	if _, err := db.Exec(migrations[0].Up); err != nil {
+		t.Fatalf("baseline failed: %v", err)
+	}
+	db.Exec(`INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'reader', 'r@example.com', 'x', 'Reader')`)
+	db.Exec(`INSERT INTO manga (id, title) VALUES ('m1', 'Berserk')`)
+	db.Exec(`INSERT INTO custom_lists (id, user_id, name) VALUES ('l1', 'u1', 'Favorites')`)
+	db.Exec(`INSERT INTO custom_list_items (id, list_id, manga_id) VALUES ('i1', 'l1', 'm1')`)
+
+	if err := db.Migrate(); err != nil {
+		t.Fatalf("Migrate failed: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow("SELECT item_count FROM custom_lists WHERE id = 'l1'").Scan(&count); err != nil {
+		t.Fatalf("query item_count: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected backfilled item_count 1, got %d", count)
+	}
+
+	// Triggers keep the count current afterwards
+	db.Exec(`DELETE FROM custom_list_items WHERE id = 'i1'`)
+	db.QueryRow("SELECT item_count FROM custom_lists WHERE id = 'l1'").Scan(&count)
+	if count != 0 {
+		t.Errorf("expected item_count 0 after delete, got %d", count)
+	}
+}
@@ -74,8 +74,8 @@ func (db *DB) seedAdminUser() error {
 	}
 
 	_, err = db.Exec(`
-		INSERT INTO users (id, username, email, password_hash, display_name, role, is_active, created_at, updated_at)
-		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		INSERT INTO users (id, username, email, password_hash, display_name, role, is_active, email_verified, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, 1, ?, ?)`,
 		user.ID, user.Username, user.Email, user.PasswordHash, user.DisplayName,
 		user.Role, user.IsActive, user.CreatedAt, user.UpdatedAt,
 	)
@@ -113,8 +113,8 @@ func (db *DB) seedTestUsers() error {
 		}
 
 		_, err = db.Exec(`
-			INSERT INTO users (id, username, email, password_hash, display_name, role, is_active, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+			INSERT INTO users (id, username, email, password_hash, display_name, role, is_active, email_verified, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?, 1, ?, ?)`,
 			user.ID, user.Username, user.Email, user.PasswordHash, user.DisplayName,
 			user.Role, user.IsActive, user.CreatedAt, user.UpdatedAt,
 		)
@@ -1,11 +1,13 @@
 // Package external - Jikan API Client (MyAnimeList Unofficial)
 // Integration với Jikan API để lấy MAL data
 // Chức năng:
-//   - Search manga
+//   - Search manga (một page hoặc nhiều page đến đủ số lượng)
 //   - Get manga details
 //   - Get recommendations
 //   - Get reviews
+//   - Top manga theo type/genre, nhiều page, bỏ entry trùng
 //   - Rate limiting (3 req/s)
+//   - Retry 429/502/503 với exponential backoff (xem retry.go)
 //
 // API Docs: https://docs.api.jikan.moe/
 package external
@@ -17,27 +19,36 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"mangahub/pkg/config"
 	"mangahub/pkg/models"
 )
 
+// defaultJikanRateLimit is Jikan's documented limit (requests per second)
+const defaultJikanRateLimit = 3
+
 // JikanClient provides methods to interact with Jikan API
 type JikanClient struct {
-	baseURL    string
-	httpClient *http.Client
-	rateLimit  int
+	baseURL     string
+	httpClient  *http.Client
+	rateLimiter *RateLimiter
 }
 
 // NewJikanClient creates a new Jikan API client
 func NewJikanClient(cfg *config.JikanConfig) *JikanClient {
+	rate := cfg.RateLimit
+	if rate <= 0 {
+		rate = defaultJikanRateLimit
+	}
 	return &JikanClient{
 		baseURL: cfg.BaseURL,
 		httpClient: &http.Client{
-			Timeout: cfg.Timeout,
+			Timeout:   cfg.Timeout,
+			Transport: newRetryTransport(http.DefaultTransport, cfg.RetryAttempts),
 		},
-		rateLimit: cfg.RateLimit,
+		rateLimiter: NewRateLimiter(rate),
 	}
 }
 
@@ -118,6 +129,19 @@ type JikanImages struct {
 	} `json:"webp"`
 }
 
+// CoverURL picks the largest available cover image, preferring JPG
+func (i JikanImages) CoverURL() string {
+	for _, u := range []string{
+		i.JPG.LargeImageURL, i.JPG.ImageURL,
+		i.WebP.LargeImageURL, i.WebP.ImageURL,
+	} {
+		if u != "" {
+			return u
+		}
+	}
+	return ""
+}
+
 type JikanPublished struct {
 	From   string `json:"from"`
 	To     string `json:"to"`
@@ -125,7 +149,11 @@ type JikanPublished struct {
 }
 
 // SearchManga searches for manga on MAL via Jikan
+// A page below 1 means the first page; limit is clamped to 1..jikanTopPageSize.
 func (c *JikanClient) SearchManga(ctx context.Context, query string, page, limit int) (*JikanSearchResponse, error) {
+	page = max(page, 1)
+	limit = min(max(limit, 1), jikanTopPageSize)
+
 	params := url.Values{}
 	params.Set("q", query)
 	params.Set("page", fmt.Sprintf("%d", page))
@@ -134,6 +162,11 @@ func (c *JikanClient) SearchManga(ctx context.Context, query string, page, limit
 
 	reqURL := fmt.Sprintf("%s/manga?%s", c.baseURL, params.Encode())
 
+	// Wait for rate limiter
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter cancelled: %w", err)
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -174,10 +207,57 @@ func (c *JikanClient) SearchMangaFiltered(ctx context.Context, query string, pag
 	return items, nil
 }
 
+// jikanSearchMaxPages bounds how far SearchMangaAll walks the results
+const jikanSearchMaxPages = 20
+
+// SearchMangaAll pages through the search results until count distinct manga
+// were found, Jikan reports no next page or jikanSearchMaxPages pages were read.
+// Every page goes through the rate limiter and the 429 retry transport.
+// If a page fails, the manga fetched so far are returned together with the error.
+func (c *JikanClient) SearchMangaAll(ctx context.Context, query string, count int) ([]models.ExternalMangaData, error) {
+	if count <= 0 {
+		return nil, nil
+	}
+
+	// The page size must stay the same across pages or the offsets shift
+	limit := min(count, jikanTopPageSize)
+
+	results := make([]models.ExternalMangaData, 0, count)
+	seen := make(map[int]bool)
+	for page := 1; page <= jikanSearchMaxPages && len(results) < count; page++ {
+		resp, err := c.SearchManga(ctx, query, page, limit)
+		if err != nil {
+			return results, fmt.Errorf("search page %d: %w", page, err)
+		}
+
+		for _, m := range resp.Data {
+			if seen[m.MalID] {
+				continue
+			}
+			seen[m.MalID] = true
+			results = append(results, m.ToExternalMangaData())
+			if len(results) == count {
+				break
+			}
+		}
+
+		if !resp.Pagination.HasNextPage {
+			break
+		}
+	}
+
+	return results, nil
+}
+
 // GetManga retrieves manga details by MAL ID
 func (c *JikanClient) GetManga(ctx context.Context, malID int) (*JikanMangaData, error) {
 	reqURL := fmt.Sprintf("%s/manga/%d/full", c.baseURL, malID)
 
+	// Wait for rate limiter
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter cancelled: %w", err)
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -206,17 +286,26 @@ func (c *JikanClient) GetManga(ctx context.Context, malID int) (*JikanMangaData,
 	return &result.Data, nil
 }
 
-// GetTopManga retrieves top manga list
-func (c *JikanClient) GetTopManga(ctx context.Context, page, limit int, filter string) (*JikanSearchResponse, error) {
+// GetTopManga retrieves one page of the top manga list
+// mangaType is one of JikanMangaTypes ("" for all)
+func (c *JikanClient) GetTopManga(ctx context.Context, page, limit int, mangaType, filter string) (*JikanSearchResponse, error) {
 	params := url.Values{}
 	params.Set("page", fmt.Sprintf("%d", page))
 	params.Set("limit", fmt.Sprintf("%d", limit))
+	if mangaType != "" {
+		params.Set("type", mangaType)
+	}
 	if filter != "" {
 		params.Set("filter", filter) // publishing, upcoming, bypopularity, favorite
 	}
 
 	reqURL := fmt.Sprintf("%s/top/manga?%s", c.baseURL, params.Encode())
 
+	// Wait for rate limiter
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter cancelled: %w", err)
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -242,10 +331,91 @@ func (c *JikanClient) GetTopManga(ctx context.Context, page, limit int, filter s
 	return &result, nil
 }
 
+// JikanMangaTypes lists the MAL types the top manga list can be filtered by
+var JikanMangaTypes = []string{"manga", "manhwa", "manhua", "novel", "lightnovel", "oneshot", "doujin"}
+
+const (
+	// jikanTopPageSize is the most entries Jikan returns per top manga page
+	jikanTopPageSize = 25
+
+	// jikanTopMaxPages bounds how far FetchTopManga walks the list
+	// (a rare genre would otherwise page through all of MAL)
+	jikanTopMaxPages = 40
+)
+
+// TopMangaQuery selects entries from the top manga list
+type TopMangaQuery struct {
+	Type   string // one of JikanMangaTypes, "" for all
+	Genre  string // genre, theme or demographic name, "" for all
+	Filter string // publishing, upcoming, bypopularity, favorite
+	Count  int    // entries wanted
+}
+
+// FetchTopManga pages through the top manga list until Count entries match
+// the query, the list ends or jikanTopMaxPages pages were read.
+// Jikan has no genre filter for the top list, so genres are matched here.
+// Entries repeated across pages are dropped. If a page fails, the entries
+// fetched so far are returned together with the error.
+func (c *JikanClient) FetchTopManga(ctx context.Context, q TopMangaQuery) ([]JikanMangaData, error) {
+	if q.Type != "" && !isJikanMangaType(q.Type) {
+		return nil, fmt.Errorf("unknown manga type %q (want one of %s)", q.Type, strings.Join(JikanMangaTypes, ", "))
+	}
+	if q.Count <= 0 {
+		return nil, nil
+	}
+
+	// The page size must stay the same across pages or the offsets shift
+	limit := jikanTopPageSize
+	if q.Genre == "" && q.Count < limit {
+		limit = q.Count
+	}
+
+	results := make([]JikanMangaData, 0, q.Count)
+	seen := make(map[int]bool)
+	for page := 1; page <= jikanTopMaxPages && len(results) < q.Count; page++ {
+		resp, err := c.GetTopManga(ctx, page, limit, q.Type, q.Filter)
+		if err != nil {
+			return results, fmt.Errorf("top manga page %d: %w", page, err)
+		}
+
+		for _, m := range resp.Data {
+			if seen[m.MalID] || !m.HasGenre(q.Genre) {
+				continue
+			}
+			seen[m.MalID] = true
+			results = append(results, m)
+			if len(results) == q.Count {
+				break
+			}
+		}
+
+		if !resp.Pagination.HasNextPage {
+			break
+		}
+	}
+
+	return results, nil
+}
+
+// isJikanMangaType reports whether t is one of JikanMangaTypes
+func isJikanMangaType(t string) bool {
+	for _, known := range JikanMangaTypes {
+		if t == known {
+			return true
+		}
+	}
+	return false
+}
+
 // GetRecommendations retrieves manga recommendations based on MAL ID
 func (c *JikanClient) GetRecommendations(ctx context.Context, malID int) ([]JikanRecommendation, error) {
 	reqURL := fmt.Sprintf("%s/manga/%d/recommendations", c.baseURL, malID)
 
+	// Wait for rate limiter
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter cancelled: %w", err)
+	}
+
 	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
@@ -284,6 +454,24 @@ type JikanRecommendation struct {
 	Votes int `json:"votes"`
 }
 
+// HasGenre reports whether the manga is tagged with a genre, theme or
+// demographic, ignoring case and "-" for spaces ("slice-of-life").
+// An empty name matches every manga.
+func (m *JikanMangaData) HasGenre(name string) bool {
+	if name == "" {
+		return true
+	}
+	name = strings.ReplaceAll(name, "-", " ")
+	for _, tags := range [][]JikanGenre{m.Genres, m.Themes, m.Demographics} {
+		for _, g := range tags {
+			if strings.EqualFold(g.Name, name) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
 // ToExternalMangaData converts Jikan response to our internal model
 func (m *JikanMangaData) ToExternalMangaData() models.ExternalMangaData {
 	// Extract genre names
@@ -312,7 +500,7 @@ func (m *JikanMangaData) ToExternalMangaData() models.ExternalMangaData {
 		ExternalID:   fmt.Sprintf("%d", m.MalID),
 		Title:        m.Title,
 		Description:  m.Synopsis,
-		CoverURL:     m.Images.JPG.LargeImageURL,
+		CoverURL:     m.Images.CoverURL(),
 		Status:       m.Status,
 		Genres:       genres,
 		Rating:       m.Score,
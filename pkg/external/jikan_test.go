@@ -0,0 +1,142 @@
+// Package external - Jikan Client Tests
+// Unit tests cho top manga và search pagination (dedupe, genre filter, partial failure)
+package external
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"mangahub/pkg/config"
+)
+
+// topPage renders a top manga page; each entry is "id:Genre"
+func topPage(hasNext bool, entries ...string) string {
+	var data []string
+	for _, e := range entries {
+		var id int
+		var genre string
+		fmt.Sscanf(strings.Replace(e, ":", " ", 1), "%d %s", &id, &genre)
+		data = append(data, fmt.Sprintf(`{"mal_id":%d,"title":"M%d","genres":[{"name":%q}]}`, id, id, genre))
+	}
+	return fmt.Sprintf(`{"data":[%s],"pagination":{"has_next_page":%t}}`, strings.Join(data, ","), hasNext)
+}
+
+func newTopServer(t *testing.T, pages map[string]string) *JikanClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("type") != "manhwa" {
+			t.Errorf("type = %q, want manhwa", r.URL.Query().Get("type"))
+		}
+		body, ok := pages[r.URL.Query().Get("page")]
+		if !ok {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return NewJikanClient(&config.JikanConfig{BaseURL: srv.URL, RateLimit: 100, Timeout: 5 * time.Second})
+}
+
+func TestFetchTopMangaDedupesAndFiltersGenre(t *testing.T) {
+	client := newTopServer(t, map[string]string{
+		"1": topPage(true, "1:Action", "2:Romance", "3:Action"),
+		"2": topPage(true, "3:Action", "4:Action", "5:Action"),
+	})
+
+	got, err := client.FetchTopManga(context.Background(), TopMangaQuery{Type: "manhwa", Genre: "action", Count: 3})
+	if err != nil {
+		t.Fatalf("FetchTopManga: %v", err)
+	}
+	var ids []int
+	for _, m := range got {
+		ids = append(ids, m.MalID)
+	}
+	if fmt.Sprint(ids) != "[1 3 4]" {
+		t.Errorf("ids = %v, want [1 3 4]", ids)
+	}
+}
+
+func TestFetchTopMangaKeepsPagesBeforeFailure(t *testing.T) {
+	client := newTopServer(t, map[string]string{
+		"1": topPage(true, "1:Action", "2:Action"),
+	})
+
+	got, err := client.FetchTopManga(context.Background(), TopMangaQuery{Type: "manhwa", Count: 50})
+	if err == nil || !strings.Contains(err.Error(), "page 2") {
+		t.Errorf("err = %v, want a page 2 error", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("got %d manga, want the 2 from page 1", len(got))
+	}
+}
+
+func TestFetchTopMangaRejectsUnknownType(t *testing.T) {
+	client := NewJikanClient(&config.JikanConfig{BaseURL: "http://127.0.0.1:0"})
+	if _, err := client.FetchTopManga(context.Background(), TopMangaQuery{Type: "webtoon", Count: 5}); err == nil {
+		t.Error("expected an error for an unknown type")
+	}
+}
+
+func newSearchServer(t *testing.T, pages map[string]string, limits *[]string) *JikanClient {
+	t.Helper()
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*limits = append(*limits, r.URL.Query().Get("limit"))
+		body, ok := pages[r.URL.Query().Get("page")]
+		if !ok {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		fmt.Fprint(w, body)
+	}))
+	t.Cleanup(srv.Close)
+	return NewJikanClient(&config.JikanConfig{BaseURL: srv.URL, RateLimit: 100, Timeout: 5 * time.Second})
+}
+
+func TestSearchMangaAllPagesUntilCount(t *testing.T) {
+	var limits []string
+	client := newSearchServer(t, map[string]string{
+		"1": topPage(true, "1:Action", "2:Action"),
+		"2": topPage(true, "2:Action", "3:Action"),
+		"3": topPage(false, "4:Action", "5:Action"),
+	}, &limits)
+
+	got, err := client.SearchMangaAll(context.Background(), "isekai", 3)
+	if err != nil {
+		t.Fatalf("SearchMangaAll: %v", err)
+	}
+	var ids []string
+	for _, m := range got {
+		ids = append(ids, m.ExternalID)
+	}
+	if fmt.Sprint(ids) != "[1 2 3]" {
+		t.Errorf("ids = %v, want [1 2 3]", ids)
+	}
+	if fmt.Sprint(limits) != "[3 3]" {
+		t.Errorf("limits = %v, want the same page size on both pages", limits)
+	}
+}
+
+func TestSearchMangaAllStopsAtLastPageAndKeepsPartial(t *testing.T) {
+	var limits []string
+	client := newSearchServer(t, map[string]string{"1": topPage(false, "1:Action")}, &limits)
+	got, err := client.SearchMangaAll(context.Background(), "rare", 50)
+	if err != nil || len(got) != 1 || len(limits) != 1 {
+		t.Errorf("got %d results, %d requests, err %v; want 1, 1, nil", len(got), len(limits), err)
+	}
+
+	limits = nil
+	client = newSearchServer(t, map[string]string{"1": topPage(true, "1:Action", "2:Action")}, &limits)
+	got, err = client.SearchMangaAll(context.Background(), "naruto", 50)
+	if err == nil || !strings.Contains(err.Error(), "page 2") {
+		t.Errorf("err = %v, want a page 2 error", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("got %d manga, want the 2 from page 1", len(got))
+	}
+}
@@ -1,11 +1,13 @@
 // Package external - MangaDex API Client
 // Integration với MangaDex API để fetch manga data
 // Chức năng:
-//   - Search manga
+//   - Search manga (lọc theo ngôn ngữ bản dịch nếu có cấu hình languages)
 //   - Get manga details
-//   - Get chapter list
+//   - Get chapter list (FetchChapters gom mọi trang thành metadata theo số chapter)
 //   - Get chapter pages/images
 //   - Rate limiting (5 req/s as per MangaDex API limits)
+//   - Retry 429/502/503 với exponential backoff (xem retry.go)
+//   - Resolve cover art URLs (cached theo manga ID nếu có cache)
 //
 // API Docs: https://api.mangadex.org/docs/
 package external
@@ -17,9 +19,13 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"slices"
+	"sort"
+	"strconv"
 	"sync"
 	"time"
 
+	"mangahub/pkg/cache"
 	"mangahub/pkg/config"
 	"mangahub/pkg/models"
 )
@@ -80,6 +86,13 @@ type MangaDexClient struct {
 	baseURL     string
 	httpClient  *http.Client
 	rateLimiter *RateLimiter
+
+	// Translated languages a manga must have chapters in to be returned by
+	// searches (e.g. "en", "pt-br"); empty returns every manga
+	languages []string
+
+	// Optional cache of resolved cover URLs keyed by manga ID
+	coverCache cache.Cache
 }
 
 // NewMangaDexClient creates a new MangaDex API client
@@ -87,12 +100,29 @@ func NewMangaDexClient(cfg *config.MangaDexConfig) *MangaDexClient {
 	return &MangaDexClient{
 		baseURL: cfg.BaseURL,
 		httpClient: &http.Client{
-			Timeout: cfg.Timeout,
+			Timeout:   cfg.Timeout,
+			Transport: newRetryTransport(http.DefaultTransport, cfg.RetryAttempts),
 		},
 		rateLimiter: NewRateLimiter(cfg.RateLimit),
+		languages:   cfg.Languages,
 	}
 }
 
+// WithLanguages returns a client that searches for manga translated into
+// languages instead of the configured ones; nil or empty lifts the filter.
+// The copy shares the HTTP client, rate limiter and cover cache.
+func (c *MangaDexClient) WithLanguages(languages []string) *MangaDexClient {
+	copied := *c
+	copied.languages = languages
+	return &copied
+}
+
+// SetCoverCache enables caching of resolved cover URLs
+// Covers already in the cache are not looked up again on re-import
+func (c *MangaDexClient) SetCoverCache(coverCache cache.Cache) {
+	c.coverCache = coverCache
+}
+
 // MangaDexSearchResponse represents the search API response
 type MangaDexSearchResponse struct {
 	Result   string          `json:"result"`
@@ -116,6 +146,9 @@ type MangaDexManga struct {
 	Type          string                 `json:"type"`
 	Attributes    MangaDexAttributes     `json:"attributes"`
 	Relationships []MangaDexRelationship `json:"relationships"`
+
+	// CoverURL is filled by cover resolution; not part of the API response
+	CoverURL string `json:"-"`
 }
 
 // MangaDexAttributes contains manga attributes
@@ -128,6 +161,7 @@ type MangaDexAttributes struct {
 	OriginalLanguage       string              `json:"originalLanguage"`
 	LastVolume             string              `json:"lastVolume"`
 	LastChapter            string              `json:"lastChapter"`
+	AvailableLanguages     []string            `json:"availableTranslatedLanguages"`
 	PublicationDemographic string              `json:"publicationDemographic"`
 	Status                 string              `json:"status"`
 	Year                   int                 `json:"year"`
@@ -190,9 +224,13 @@ func (c *MangaDexClient) SearchManga(ctx context.Context, query string, limit, o
 	params.Set("title", query)
 	params.Set("limit", fmt.Sprintf("%d", limit))
 	params.Set("offset", fmt.Sprintf("%d", offset))
-	params.Set("includes[]", "cover_art")
-	params.Set("includes[]", "author")
+	params.Add("includes[]", "cover_art")
+	params.Add("includes[]", "author")
 	params.Set("order[relevance]", "desc")
+	// Filtered by MangaDex, so limit still counts only manga that match
+	for _, lang := range c.languages {
+		params.Add("availableTranslatedLanguage[]", lang)
+	}
 
 	reqURL := fmt.Sprintf("%s/manga?%s", c.baseURL, params.Encode())
 
@@ -218,6 +256,7 @@ func (c *MangaDexClient) SearchManga(ctx context.Context, query string, limit, o
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
+	c.resolveCovers(ctx, result.Data)
 	return &result, nil
 }
 
@@ -231,7 +270,7 @@ func (c *MangaDexClient) SearchMangaFiltered(ctx context.Context, query string,
 
 	var items []models.ExternalMangaData
 	for _, m := range res.Data {
-		items = append(items, m.ToExternalMangaData())
+		items = append(items, m.toExternalMangaData(c.languages))
 	}
 	return items, nil
 }
@@ -244,9 +283,9 @@ func (c *MangaDexClient) GetManga(ctx context.Context, mangaID string) (*MangaDe
 	}
 
 	params := url.Values{}
-	params.Set("includes[]", "cover_art")
-	params.Set("includes[]", "author")
-	params.Set("includes[]", "artist")
+	params.Add("includes[]", "cover_art")
+	params.Add("includes[]", "author")
+	params.Add("includes[]", "artist")
 
 	reqURL := fmt.Sprintf("%s/manga/%s?%s", c.baseURL, mangaID, params.Encode())
 
@@ -275,7 +314,9 @@ func (c *MangaDexClient) GetManga(ctx context.Context, mangaID string) (*MangaDe
 		return nil, fmt.Errorf("failed to decode response: %w", err)
 	}
 
-	return &result.Data, nil
+	mangas := []MangaDexManga{result.Data}
+	c.resolveCovers(ctx, mangas)
+	return &mangas[0], nil
 }
 
 // GetChapterList retrieves chapters for a manga
@@ -321,8 +362,66 @@ func (c *MangaDexClient) GetChapterList(ctx context.Context, mangaID string, lim
 	return &result, nil
 }
 
+// Chapter feed paging: MangaDex allows up to 500 per page, 100 keeps responses small
+const (
+	mangadexChapterPageSize = 100
+	mangadexChapterMaxPages = 50
+)
+
+// FetchChapters returns a manga's chapters in one language (e.g. "en"), lowest
+// number first, one per chapter number. MangaDex lists a chapter once per
+// scanlation group; the first upload with a title wins. Chapters whose number is
+// not a whole number (extras such as "10.5") are skipped, since progress is
+// tracked in whole chapters.
+func (c *MangaDexClient) FetchChapters(ctx context.Context, mangaID, lang string) ([]models.ExternalChapter, error) {
+	var chapters []models.ExternalChapter
+	index := make(map[int]int) // chapter number -> position in chapters
+
+	for page := 0; page < mangadexChapterMaxPages; page++ {
+		resp, err := c.GetChapterList(ctx, mangaID, mangadexChapterPageSize, page*mangadexChapterPageSize, lang)
+		if err != nil {
+			return nil, fmt.Errorf("chapter page %d: %w", page+1, err)
+		}
+
+		for _, ch := range resp.Data {
+			number, err := strconv.Atoi(ch.Attributes.Chapter)
+			if err != nil || number <= 0 {
+				continue
+			}
+			if i, ok := index[number]; ok {
+				if chapters[i].Title == "" && ch.Attributes.Title != "" {
+					chapters[i].Title = ch.Attributes.Title
+				}
+				continue
+			}
+
+			entry := models.ExternalChapter{Number: number, Title: ch.Attributes.Title, ExternalID: ch.ID}
+			if t, err := time.Parse(time.RFC3339, ch.Attributes.PublishAt); err == nil {
+				entry.ReleasedAt = &t
+			}
+			index[number] = len(chapters)
+			chapters = append(chapters, entry)
+		}
+
+		if len(resp.Data) == 0 || resp.Offset+len(resp.Data) >= resp.Total {
+			break
+		}
+	}
+
+	sort.Slice(chapters, func(i, j int) bool { return chapters[i].Number < chapters[j].Number })
+	return chapters, nil
+}
+
 // ToExternalMangaData converts MangaDex response to internal model
 func (m *MangaDexManga) ToExternalMangaData() models.ExternalMangaData {
+	return m.toExternalMangaData(nil)
+}
+
+// toExternalMangaData converts the manga for a search filtered to languages:
+// Languages keeps the requested ones the manga is translated into, in request
+// order, and the description is taken in the first of those that has one.
+// Without languages every available translation is listed.
+func (m *MangaDexManga) toExternalMangaData(languages []string) models.ExternalMangaData {
 	// Get English title, fallback to first available
 	title := ""
 	if en, ok := m.Attributes.Title["en"]; ok {
@@ -334,11 +433,26 @@ func (m *MangaDexManga) ToExternalMangaData() models.ExternalMangaData {
 		}
 	}
 
-	// Get English description
+	// Get English description, or one in a requested language
 	description := ""
 	if en, ok := m.Attributes.Description["en"]; ok {
 		description = en
 	}
+	available := m.Attributes.AvailableLanguages
+	if len(languages) > 0 {
+		available = nil
+		for _, lang := range languages {
+			if slices.Contains(m.Attributes.AvailableLanguages, lang) {
+				available = append(available, lang)
+			}
+		}
+		for _, lang := range available {
+			if text := m.Attributes.Description[lang]; text != "" {
+				description = text
+				break
+			}
+		}
+	}
 
 	// Extract genres from tags
 	var genres []string
@@ -349,13 +463,9 @@ func (m *MangaDexManga) ToExternalMangaData() models.ExternalMangaData {
 	}
 
 	// Find cover art
-	coverURL := ""
-	for _, rel := range m.Relationships {
-		if rel.Type == "cover_art" {
-			if fileName, ok := rel.Attributes["fileName"].(string); ok {
-				coverURL = fmt.Sprintf("https://uploads.mangadex.org/covers/%s/%s", m.ID, fileName)
-			}
-		}
+	coverURL := m.CoverURL
+	if coverURL == "" {
+		coverURL = m.embeddedCoverURL()
 	}
 
 	// Extract authors
@@ -378,10 +488,151 @@ func (m *MangaDexManga) ToExternalMangaData() models.ExternalMangaData {
 		Genres:      genres,
 		Year:        m.Attributes.Year,
 		Authors:     authors,
+		Languages:   available,
 		FetchedAt:   time.Now(),
 	}
 }
 
+// embeddedCoverURL builds the cover URL from an expanded cover_art relationship
+func (m *MangaDexManga) embeddedCoverURL() string {
+	for _, rel := range m.Relationships {
+		if rel.Type == "cover_art" {
+			if fileName, ok := rel.Attributes["fileName"].(string); ok && fileName != "" {
+				return GetCoverURL(m.ID, fileName, "")
+			}
+		}
+	}
+	return ""
+}
+
+// coverArtID returns the ID of the manga's cover_art relationship
+func (m *MangaDexManga) coverArtID() string {
+	for _, rel := range m.Relationships {
+		if rel.Type == "cover_art" {
+			return rel.ID
+		}
+	}
+	return ""
+}
+
+// MangaDexCoverResponse represents the cover list response
+type MangaDexCoverResponse struct {
+	Result string `json:"result"`
+	Data   []struct {
+		ID         string `json:"id"`
+		Attributes struct {
+			FileName string `json:"fileName"`
+		} `json:"attributes"`
+	} `json:"data"`
+}
+
+// resolveCovers fills CoverURL for each manga.
+// Thứ tự: cover_art đã expand trong response → cache → một request /cover
+// cho tất cả cover còn thiếu (qua rate limiter, chỉ tính một lần).
+// Lookup failures are not fatal; the manga simply has no cover.
+func (c *MangaDexClient) resolveCovers(ctx context.Context, mangas []MangaDexManga) {
+	pending := make(map[string][]int) // cover ID -> indexes into mangas
+	for i := range mangas {
+		m := &mangas[i]
+		if coverURL := m.embeddedCoverURL(); coverURL != "" {
+			m.CoverURL = coverURL
+			c.cacheCover(ctx, m.ID, coverURL)
+			continue
+		}
+		if coverURL := c.cachedCover(ctx, m.ID); coverURL != "" {
+			m.CoverURL = coverURL
+			continue
+		}
+		if coverID := m.coverArtID(); coverID != "" {
+			pending[coverID] = append(pending[coverID], i)
+		}
+	}
+	if len(pending) == 0 {
+		return
+	}
+
+	fileNames, err := c.getCoverFileNames(ctx, pending)
+	if err != nil {
+		return
+	}
+	for coverID, indexes := range pending {
+		fileName, ok := fileNames[coverID]
+		if !ok {
+			continue
+		}
+		for _, i := range indexes {
+			mangas[i].CoverURL = GetCoverURL(mangas[i].ID, fileName, "")
+			c.cacheCover(ctx, mangas[i].ID, mangas[i].CoverURL)
+		}
+	}
+}
+
+// getCoverFileNames looks up cover file names in a single /cover request
+func (c *MangaDexClient) getCoverFileNames(ctx context.Context, covers map[string][]int) (map[string]string, error) {
+	// Wait for rate limiter
+	if err := c.rateLimiter.Wait(ctx); err != nil {
+		return nil, fmt.Errorf("rate limiter cancelled: %w", err)
+	}
+
+	params := url.Values{}
+	for coverID := range covers {
+		params.Add("ids[]", coverID)
+	}
+	params.Set("limit", fmt.Sprintf("%d", len(covers)))
+
+	reqURL := fmt.Sprintf("%s/cover?%s", c.baseURL, params.Encode())
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, reqURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to execute request: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(resp.Body)
+		return nil, fmt.Errorf("API error (status %d): %s", resp.StatusCode, string(body))
+	}
+
+	var result MangaDexCoverResponse
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+
+	fileNames := make(map[string]string, len(result.Data))
+	for _, cover := range result.Data {
+		if cover.Attributes.FileName != "" {
+			fileNames[cover.ID] = cover.Attributes.FileName
+		}
+	}
+	return fileNames, nil
+}
+
+// cachedCover returns a cached cover URL, or "" on a miss or without a cache
+func (c *MangaDexClient) cachedCover(ctx context.Context, mangaID string) string {
+	if c.coverCache == nil {
+		return ""
+	}
+	coverURL, err := c.coverCache.Get(ctx, cache.CoverKey(models.SourceMangaDex, mangaID))
+	if err != nil {
+		return ""
+	}
+	return coverURL
+}
+
+// cacheCover stores a resolved cover URL; cache errors are ignored
+func (c *MangaDexClient) cacheCover(ctx context.Context, mangaID, coverURL string) {
+	if c.coverCache == nil {
+		return
+	}
+	_ = c.coverCache.Set(ctx, cache.CoverKey(models.SourceMangaDex, mangaID), coverURL, cache.TTLWeek)
+}
+
 // GetCoverURL builds the cover image URL
 func GetCoverURL(mangaID, coverFileName string, size string) string {
 	// size: 256, 512, or empty for original
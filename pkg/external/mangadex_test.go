@@ -0,0 +1,179 @@
+// Package external - MangaDex Client Tests
+// Unit tests cho cover art resolution (relationship lookup + cache) và lọc ngôn ngữ
+package external
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"mangahub/pkg/cache"
+	"mangahub/pkg/config"
+)
+
+// memoryCache is an in-memory cache.Cache for tests
+type memoryCache struct {
+	mu    sync.Mutex
+	items map[string]string
+}
+
+func newMemoryCache() *memoryCache {
+	return &memoryCache{items: make(map[string]string)}
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string) (string, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.items[key], nil
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	return c.SetWithTTL(ctx, key, value, ttl)
+}
+
+func (c *memoryCache) SetWithTTL(ctx context.Context, key string, value interface{}, ttl time.Duration) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.items[key] = fmt.Sprint(value)
+	return nil
+}
+
+func (c *memoryCache) Delete(ctx context.Context, key string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	delete(c.items, key)
+	return nil
+}
+
+func (c *memoryCache) Exists(ctx context.Context, key string) (bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	_, ok := c.items[key]
+	return ok, nil
+}
+
+func (c *memoryCache) GetTTL(ctx context.Context, key string) (time.Duration, error) { return 0, nil }
+func (c *memoryCache) FlushByPrefix(ctx context.Context, prefix string) error        { return nil }
+func (c *memoryCache) Close() error                                                  { return nil }
+func (c *memoryCache) Ping(ctx context.Context) error                                { return nil }
+
+// Search results: m1 has an expanded cover, m2 and m3 only reference theirs
+const searchBody = `{"result":"ok","data":[
+	{"id":"m1","attributes":{"title":{"en":"One"}},"relationships":[{"id":"c1","type":"cover_art","attributes":{"fileName":"one.jpg"}}]},
+	{"id":"m2","attributes":{"title":{"en":"Two"}},"relationships":[{"id":"c2","type":"cover_art"}]},
+	{"id":"m3","attributes":{"title":{"en":"Three"}},"relationships":[{"id":"c3","type":"cover_art"}]}
+]}`
+
+const coverBody = `{"result":"ok","data":[
+	{"id":"c2","attributes":{"fileName":"two.jpg"}},
+	{"id":"c3","attributes":{"fileName":"three.jpg"}}
+]}`
+
+func TestSearchResolvesCoverArt(t *testing.T) {
+	var mu sync.Mutex
+	calls := map[string]int{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		calls[r.URL.Path]++
+		mu.Unlock()
+
+		switch r.URL.Path {
+		case "/manga":
+			if got := r.URL.Query()["includes[]"]; len(got) != 2 {
+				t.Errorf("expected cover_art and author includes, got %v", got)
+			}
+			fmt.Fprint(w, searchBody)
+		case "/cover":
+			if got := r.URL.Query()["ids[]"]; len(got) != 2 {
+				t.Errorf("expected one lookup for both missing covers, got ids %v", got)
+			}
+			fmt.Fprint(w, coverBody)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	client := NewMangaDexClient(&config.MangaDexConfig{BaseURL: srv.URL, RateLimit: 5, Timeout: 5 * time.Second})
+	covers := newMemoryCache()
+	client.SetCoverCache(covers)
+	ctx := context.Background()
+
+	items, err := client.SearchMangaFiltered(ctx, "test", 10, 0)
+	if err != nil {
+		t.Fatalf("SearchMangaFiltered failed: %v", err)
+	}
+	want := map[string]string{
+		"m1": "https://uploads.mangadex.org/covers/m1/one.jpg",
+		"m2": "https://uploads.mangadex.org/covers/m2/two.jpg",
+		"m3": "https://uploads.mangadex.org/covers/m3/three.jpg",
+	}
+	for _, item := range items {
+		if item.CoverURL != want[item.ExternalID] {
+			t.Errorf("%s: cover = %q, want %q", item.ExternalID, item.CoverURL, want[item.ExternalID])
+		}
+	}
+	if calls["/cover"] != 1 {
+		t.Errorf("expected 1 cover lookup, got %d", calls["/cover"])
+	}
+
+	// A re-import resolves every cover from the cache
+	if _, err := client.SearchMangaFiltered(ctx, "test", 10, 0); err != nil {
+		t.Fatalf("second search failed: %v", err)
+	}
+	if calls["/cover"] != 1 {
+		t.Errorf("cached covers should not be looked up again, got %d lookups", calls["/cover"])
+	}
+	if got, _ := covers.Get(ctx, cache.CoverKey("mangadex", "m2")); got != want["m2"] {
+		t.Errorf("cache entry for m2 = %q", got)
+	}
+}
+
+func TestSearchFiltersByTranslatedLanguage(t *testing.T) {
+	var got [][]string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = append(got, r.URL.Query()["availableTranslatedLanguage[]"])
+		fmt.Fprint(w, `{"result":"ok","data":[{"id":"m1","attributes":{
+			"title":{"en":"One"},
+			"description":{"en":"English","pt-br":"Português"},
+			"availableTranslatedLanguages":["en","pt-br","fr"]}}]}`)
+	}))
+	defer srv.Close()
+
+	cfg := &config.MangaDexConfig{BaseURL: srv.URL, RateLimit: 5, Timeout: 5 * time.Second}
+	ctx := context.Background()
+
+	// No languages configured: no filter, every translation listed
+	items, err := NewMangaDexClient(cfg).SearchMangaFiltered(ctx, "one", 10, 0)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if len(got[0]) != 0 {
+		t.Errorf("expected no language filter by default, got %v", got[0])
+	}
+	if !slices.Equal(items[0].Languages, []string{"en", "pt-br", "fr"}) || items[0].Description != "English" {
+		t.Errorf("unfiltered item = %v, %q", items[0].Languages, items[0].Description)
+	}
+
+	// The configured languages go to MangaDex; a per-call override replaces them
+	cfg.Languages = []string{"en"}
+	client := NewMangaDexClient(cfg)
+	if _, err := client.SearchMangaFiltered(ctx, "one", 10, 0); err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	items, err = client.WithLanguages([]string{"pt-br", "de"}).SearchMangaFiltered(ctx, "one", 10, 0)
+	if err != nil {
+		t.Fatalf("search failed: %v", err)
+	}
+	if !slices.Equal(got[1], []string{"en"}) || !slices.Equal(got[2], []string{"pt-br", "de"}) {
+		t.Errorf("language params = %v", got[1:])
+	}
+	if !slices.Equal(items[0].Languages, []string{"pt-br"}) || items[0].Description != "Português" {
+		t.Errorf("filtered item = %v, %q", items[0].Languages, items[0].Description)
+	}
+}
@@ -0,0 +1,126 @@
+// Package external - Retry Transport
+// Retry middleware dùng chung cho các external API clients
+// Chức năng:
+//   - Retry idempotent requests (GET/HEAD) khi gặp 429/502/503
+//   - Exponential backoff với full jitter
+//   - Tôn trọng Retry-After header (seconds hoặc HTTP-date)
+//   - Không chờ vượt quá context deadline
+package external
+
+import (
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay = 500 * time.Millisecond
+	defaultRetryMaxDelay  = 30 * time.Second
+)
+
+// retryTransport wraps an http.RoundTripper and retries transient failures
+type retryTransport struct {
+	base       http.RoundTripper
+	maxRetries int
+	baseDelay  time.Duration
+	maxDelay   time.Duration
+}
+
+// newRetryTransport creates a retry transport around base
+// maxRetries is the number of retries after the first attempt (0 disables retrying)
+func newRetryTransport(base http.RoundTripper, maxRetries int) *retryTransport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if maxRetries < 0 {
+		maxRetries = 0
+	}
+	return &retryTransport{
+		base:       base,
+		maxRetries: maxRetries,
+		baseDelay:  defaultRetryBaseDelay,
+		maxDelay:   defaultRetryMaxDelay,
+	}
+}
+
+// RoundTrip implements http.RoundTripper
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if req.Method != http.MethodGet && req.Method != http.MethodHead {
+		return t.base.RoundTrip(req)
+	}
+
+	ctx := req.Context()
+	for attempt := 0; ; attempt++ {
+		resp, err := t.base.RoundTrip(req)
+		if err != nil || attempt >= t.maxRetries || !isRetryableStatus(resp.StatusCode) {
+			return resp, err
+		}
+
+		wait := t.backoff(attempt, resp.Header.Get("Retry-After"))
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			// Waiting would overrun the caller's deadline; hand back the last response
+			return resp, nil
+		}
+
+		// Drain so the connection can be reused
+		io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+		resp.Body.Close()
+
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+}
+
+// backoff returns the delay before the next attempt
+// Retry-After wins when present, otherwise exponential backoff with full jitter
+func (t *retryTransport) backoff(attempt int, retryAfter string) time.Duration {
+	if d, ok := parseRetryAfter(retryAfter, time.Now()); ok {
+		if d > t.maxDelay {
+			return t.maxDelay
+		}
+		return d
+	}
+
+	ceiling := t.baseDelay << attempt
+	if ceiling <= 0 || ceiling > t.maxDelay {
+		ceiling = t.maxDelay
+	}
+	return time.Duration(rand.Int63n(int64(ceiling) + 1))
+}
+
+// isRetryableStatus reports whether a status code is worth retrying
+func isRetryableStatus(code int) bool {
+	switch code {
+	case http.StatusTooManyRequests, http.StatusBadGateway, http.StatusServiceUnavailable:
+		return true
+	}
+	return false
+}
+
+// parseRetryAfter parses a Retry-After header value (delay-seconds or HTTP-date)
+func parseRetryAfter(value string, now time.Time) (time.Duration, bool) {
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0, false
+		}
+		return time.Duration(secs) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		d := at.Sub(now)
+		if d < 0 {
+			d = 0
+		}
+		return d, true
+	}
+	return 0, false
+}
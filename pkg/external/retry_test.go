@@ -0,0 +1,93 @@
+// Package external - Retry Transport Tests
+// Unit tests cho retry/backoff của external clients
+package external
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"mangahub/pkg/config"
+)
+
+func TestJikanRetriesRateLimitedRequests(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) <= 2 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"mal_id":1,"title":"Berserk"}}`))
+	}))
+	defer srv.Close()
+
+	client := NewJikanClient(&config.JikanConfig{BaseURL: srv.URL, RateLimit: 3, RetryAttempts: 3, Timeout: 5 * time.Second})
+
+	manga, err := client.GetManga(context.Background(), 1)
+	if err != nil {
+		t.Fatalf("GetManga: %v", err)
+	}
+	if manga.Title != "Berserk" {
+		t.Errorf("title = %q, want Berserk", manga.Title)
+	}
+	if got := atomic.LoadInt32(&calls); got != 3 {
+		t.Errorf("server saw %d requests, want 3", got)
+	}
+}
+
+func TestRetryStopsAtContextDeadline(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.Header().Set("Retry-After", "10")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newRetryTransport(http.DefaultTransport, 5)}
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	req, _ := http.NewRequestWithContext(ctx, http.MethodGet, srv.URL, nil)
+	start := time.Now()
+	resp, err := client.Do(req)
+	if err != nil {
+		t.Fatalf("Do: %v", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusServiceUnavailable {
+		t.Errorf("status = %d, want 503", resp.StatusCode)
+	}
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("server saw %d requests, want 1", got)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("retry waited %v past the deadline", elapsed)
+	}
+}
+
+func TestRetryDoesNotRepeatPOST(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: newRetryTransport(http.DefaultTransport, 3)}
+	resp, err := client.Post(srv.URL, "application/json", nil)
+	if err != nil {
+		t.Fatalf("Post: %v", err)
+	}
+	resp.Body.Close()
+
+	if got := atomic.LoadInt32(&calls); got != 1 {
+		t.Errorf("server saw %d requests, want 1", got)
+	}
+}
@@ -0,0 +1,143 @@
+// Package importer - Chapter Import
+// Lưu chapter metadata (title, ngày phát hành) khi import manga
+// Chức năng:
+//   - Lấy chapter từ ExternalMangaData.Chapters, hoặc từ ChapterFetcher của source
+//   - Upsert theo (manga_id, number): re-import không tạo chapter trùng
+//   - Batch import fetch chapter trước khi mở transaction của chunk
+//   - Không ghi đè title/ngày đã có bằng giá trị rỗng
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"mangahub/pkg/database"
+	"mangahub/pkg/external"
+	"mangahub/pkg/models"
+)
+
+// ChapterFetcher fetches a manga's chapters from an external source by its ID on that source
+type ChapterFetcher func(ctx context.Context, externalID string) ([]models.ExternalChapter, error)
+
+// MangaDexChapterFetcher adapts a MangaDex client to a ChapterFetcher for one language
+func MangaDexChapterFetcher(client *external.MangaDexClient, lang string) ChapterFetcher {
+	return func(ctx context.Context, externalID string) ([]models.ExternalChapter, error) {
+		return client.FetchChapters(ctx, externalID, lang)
+	}
+}
+
+// SetChapterFetcher registers the chapter source for manga imported from source.
+// Sources without a fetcher (Jikan has no chapter API) import no chapters, and
+// GET /manga/:id/chapters falls back to numbered chapters.
+func (i *Importer) SetChapterFetcher(source string, fetch ChapterFetcher) {
+	if i.chapterFetchers == nil {
+		i.chapterFetchers = make(map[string]ChapterFetcher)
+	}
+	i.chapterFetchers[source] = fetch
+}
+
+// importChapters stores ext's chapters for mangaID, fetching them first when
+// the source data carries none. It returns how many chapters were written.
+func (i *Importer) importChapters(ctx context.Context, mangaID string, ext models.ExternalMangaData) (int, error) {
+	chapters := ext.Chapters
+	if chapters == nil {
+		fetch, ok := i.chapterFetchers[ext.Source]
+		if !ok {
+			return 0, nil
+		}
+		var err error
+		if chapters, err = fetch(ctx, ext.ExternalID); err != nil {
+			return 0, fmt.Errorf("fetch chapters: %w", err)
+		}
+	}
+	if len(chapters) == 0 {
+		return 0, nil
+	}
+	return i.upsertChapters(ctx, mangaID, chapters)
+}
+
+// upsertChapters writes chapters in one transaction (the batch chunk's, if
+// one is open), keyed by (manga_id, number). Empty titles, dates and IDs never
+// replace stored ones.
+func (i *Importer) upsertChapters(ctx context.Context, mangaID string, chapters []models.ExternalChapter) (int, error) {
+	if i.tx != nil {
+		written, err := writeChapters(ctx, i.tx, mangaID, chapters)
+		if err != nil {
+			return 0, fmt.Errorf("write chapters: %w", err)
+		}
+		return written, nil
+	}
+
+	written := 0
+	err := database.WithTx(ctx, i.db, func(tx *sql.Tx) error {
+		var err error
+		written, err = writeChapters(ctx, tx, mangaID, chapters)
+		return err
+	})
+	if err != nil {
+		return 0, fmt.Errorf("write chapters: %w", err)
+	}
+	return written, nil
+}
+
+// writeChapters upserts each numbered chapter through db
+func writeChapters(ctx context.Context, db dbtx, mangaID string, chapters []models.ExternalChapter) (int, error) {
+	now := time.Now()
+	written := 0
+	for _, ch := range chapters {
+		if ch.Number <= 0 {
+			continue
+		}
+		var releasedAt interface{}
+		if ch.ReleasedAt != nil {
+			releasedAt = *ch.ReleasedAt
+		}
+		_, err := db.ExecContext(ctx, `
+			INSERT INTO chapters (manga_id, number, title, released_at, external_id, created_at, updated_at)
+			VALUES (?, ?, ?, ?, ?, ?, ?)
+			ON CONFLICT(manga_id, number) DO UPDATE SET
+				title = COALESCE(NULLIF(excluded.title, ''), chapters.title),
+				released_at = COALESCE(excluded.released_at, chapters.released_at),
+				external_id = COALESCE(excluded.external_id, chapters.external_id),
+				updated_at = excluded.updated_at`,
+			mangaID, ch.Number, ch.Title, releasedAt, sqlNullString(true, ch.ExternalID), now, now,
+		)
+		if err != nil {
+			return 0, fmt.Errorf("upsert chapter %d: %w", ch.Number, err)
+		}
+		written++
+	}
+	return written, nil
+}
+
+// prefetchChapters fetches the chapter lists of a batch chunk before its
+// transaction opens, so no network call runs while the write lock is held.
+// Records a re-run will skip are not fetched. A failed fetch is only a
+// warning, as in importChapters, and leaves the record without chapters.
+func (i *Importer) prefetchChapters(ctx context.Context, chunk []models.ExternalMangaData) {
+	if i.dryRun {
+		return
+	}
+	for k := range chunk {
+		ext := &chunk[k]
+		fetch, ok := i.chapterFetchers[ext.Source]
+		if ext.Chapters != nil || !ok {
+			continue
+		}
+		if i.idempotent(*ext) {
+			if id, err := i.alreadyProcessed(ctx, *ext); err == nil && id != "" {
+				continue
+			}
+		}
+		chapters, err := fetch(ctx, ext.ExternalID)
+		if err != nil {
+			fmt.Printf("Warning: failed to import chapters for '%s': fetch chapters: %v\n", ext.Title, err)
+		}
+		if chapters == nil {
+			chapters = []models.ExternalChapter{} // non-nil: importChapters must not fetch again
+		}
+		ext.Chapters = chapters
+	}
+}
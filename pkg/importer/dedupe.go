@@ -0,0 +1,168 @@
+// Package importer - Fuzzy Title Dedupe
+// Gộp manga trùng khi import từ nhiều nguồn ("Re:Zero" vs "ReZero")
+// Cách làm:
+//   - Normalize title: lowercase, bỏ dấu câu và khoảng trắng
+//   - So sánh trigram (Jaccard similarity) với ngưỡng cấu hình được
+//   - Title khác số (tập 2, season 3, ...) không bao giờ bị gộp
+//   - Dry run chỉ báo cáo các merge dự kiến, không ghi DB
+package importer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// DefaultDedupeThreshold is the trigram similarity above which two titles are merged
+const DefaultDedupeThreshold = 0.8
+
+// MergeCandidate is an imported title matched to an existing manga by similarity
+type MergeCandidate struct {
+	Title         string  `json:"title"`
+	Source        string  `json:"source"`
+	ExternalID    string  `json:"external_id"`
+	ExistingID    string  `json:"existing_id"`
+	ExistingTitle string  `json:"existing_title"`
+	Similarity    float64 `json:"similarity"`
+}
+
+// indexedTitle is an existing manga title prepared for comparison
+type indexedTitle struct {
+	id       string
+	title    string
+	numbers  string
+	trigrams map[string]struct{}
+}
+
+// SetDedupe enables fuzzy title matching before inserting new manga.
+// A threshold outside (0, 1] falls back to DefaultDedupeThreshold.
+func (i *Importer) SetDedupe(enabled bool, threshold float64) {
+	if threshold <= 0 || threshold > 1 {
+		threshold = DefaultDedupeThreshold
+	}
+	i.dedupe = enabled
+	i.dedupeThreshold = threshold
+}
+
+// GetMerges returns the fuzzy merges made (or, in dry run, that would be made)
+func (i *Importer) GetMerges() []MergeCandidate {
+	return i.merges
+}
+
+// findSimilarManga returns the existing manga whose title is most similar to
+// title, if it reaches the dedupe threshold. Returns nil when nothing matches.
+func (i *Importer) findSimilarManga(ctx context.Context, title string) (*indexedTitle, float64, error) {
+	if err := i.loadTitleIndex(ctx); err != nil {
+		return nil, 0, err
+	}
+
+	if normalizeTitle(title) == "" {
+		return nil, 0, nil
+	}
+
+	candidate := newIndexedTitle("", title)
+	var best *indexedTitle
+	var bestScore float64
+	for n := range i.titleIndex {
+		existing := &i.titleIndex[n]
+		// Different numbers usually mean a sequel or another volume
+		if existing.numbers != candidate.numbers {
+			continue
+		}
+		if score := jaccard(candidate.trigrams, existing.trigrams); score > bestScore {
+			best, bestScore = existing, score
+		}
+	}
+	if best == nil || bestScore < i.dedupeThreshold {
+		return nil, 0, nil
+	}
+	return best, bestScore, nil
+}
+
+// loadTitleIndex reads every manga title once per importer
+func (i *Importer) loadTitleIndex(ctx context.Context) error {
+	if i.titleIndex != nil {
+		return nil
+	}
+
+	rows, err := i.conn().QueryContext(ctx, "SELECT id, title FROM manga")
+	if err != nil {
+		return fmt.Errorf("load manga titles: %w", err)
+	}
+	defer rows.Close()
+
+	index := []indexedTitle{}
+	for rows.Next() {
+		var id, title string
+		if err := rows.Scan(&id, &title); err != nil {
+			return fmt.Errorf("scan manga title: %w", err)
+		}
+		index = append(index, newIndexedTitle(id, title))
+	}
+	if err := rows.Err(); err != nil {
+		return fmt.Errorf("load manga titles: %w", err)
+	}
+	i.titleIndex = index
+	return nil
+}
+
+// indexTitle adds a newly inserted manga so later rows in the batch can match it
+func (i *Importer) indexTitle(id, title string) {
+	if i.titleIndex != nil {
+		i.titleIndex = append(i.titleIndex, newIndexedTitle(id, title))
+	}
+}
+
+func newIndexedTitle(id, title string) indexedTitle {
+	normalized := normalizeTitle(title)
+	return indexedTitle{
+		id:       id,
+		title:    title,
+		numbers:  titleNumbers(normalized),
+		trigrams: trigrams(normalized),
+	}
+}
+
+// normalizeTitle lowercases a title and drops everything but letters and digits
+func normalizeTitle(title string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(title) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// titleNumbers returns the digit runs of a normalized title, space separated
+func titleNumbers(normalized string) string {
+	return strings.Join(strings.FieldsFunc(normalized, func(r rune) bool {
+		return !unicode.IsDigit(r)
+	}), " ")
+}
+
+// trigrams splits a normalized title into rune trigrams, padded so short
+// titles and word starts still carry weight
+func trigrams(normalized string) map[string]struct{} {
+	runes := []rune("  " + normalized + " ")
+	set := make(map[string]struct{}, len(runes))
+	for n := 0; n+3 <= len(runes); n++ {
+		set[string(runes[n:n+3])] = struct{}{}
+	}
+	return set
+}
+
+// jaccard is |a ∩ b| / |a ∪ b|
+func jaccard(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	shared := 0
+	for t := range a {
+		if _, ok := b[t]; ok {
+			shared++
+		}
+	}
+	return float64(shared) / float64(len(a)+len(b)-shared)
+}
@@ -0,0 +1,107 @@
+// Package importer - Fuzzy Title Dedupe Tests
+// Unit tests cho merge title gần giống và chống merge nhầm
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+func countManga(t *testing.T, db *sql.DB) int {
+	t.Helper()
+	var n int
+	if err := db.QueryRow("SELECT COUNT(*) FROM manga").Scan(&n); err != nil {
+		t.Fatalf("count manga failed: %v", err)
+	}
+	return n
+}
+
+func TestTitleSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		merge bool
+	}{
+		{"Re:Zero", "ReZero", true},
+		{"Kaguya-sama: Love is War", "Kaguya sama - Love Is War", true},
+		{"One Piece", "One Punch-Man", false},
+		{"Berserk", "Berserk 2", false},
+		{"Tokyo Ghoul", "Tokyo Ghoul:re", false},
+	}
+	for _, tt := range tests {
+		a, b := newIndexedTitle("a", tt.a), newIndexedTitle("b", tt.b)
+		score := jaccard(a.trigrams, b.trigrams)
+		merge := a.numbers == b.numbers && score >= DefaultDedupeThreshold
+		if merge != tt.merge {
+			t.Errorf("%q vs %q: similarity %.2f, merge=%v, want %v", tt.a, tt.b, score, merge, tt.merge)
+		}
+	}
+}
+
+func TestImportDedupe(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	imp := NewImporter(db, nil)
+	if _, err := imp.ImportOne(ctx, models.ExternalMangaData{Title: "Re:Zero", Status: "ongoing", Source: models.SourceMangaDex, ExternalID: "md-1"}); err != nil {
+		t.Fatalf("ImportOne failed: %v", err)
+	}
+
+	// Dry run reports the merge without writing
+	imp.SetDedupe(true, 0)
+	imp.SetDryRun(true)
+	rezero := models.ExternalMangaData{Title: "ReZero", Status: "ongoing", Source: models.SourceJikan, ExternalID: "42"}
+	if _, err := imp.ImportOne(ctx, rezero); err != nil {
+		t.Fatalf("dry run ImportOne failed: %v", err)
+	}
+	if merges := imp.GetMerges(); len(merges) != 1 || merges[0].ExistingTitle != "Re:Zero" {
+		t.Fatalf("expected one would-be merge into Re:Zero, got %+v", merges)
+	}
+	if n := countManga(t, db); n != 1 {
+		t.Fatalf("dry run must not write, got %d manga", n)
+	}
+
+	// A real import merges and adds the missing external id
+	imp.SetDryRun(false)
+	imp.ResetStats()
+	manga, err := imp.ImportOne(ctx, rezero)
+	if err != nil {
+		t.Fatalf("ImportOne failed: %v", err)
+	}
+	var malID int
+	db.QueryRow("SELECT mal_id FROM manga_external_ids WHERE manga_id = ?", manga.ID).Scan(&malID)
+	if n := countManga(t, db); n != 1 || malID != 42 {
+		t.Errorf("expected one manga with mal_id 42, got %d manga, mal_id %d", n, malID)
+	}
+
+	// Below the threshold titles stay distinct
+	if _, err := imp.ImportOne(ctx, models.ExternalMangaData{Title: "Re:Monster", Status: "ongoing", Source: models.SourceJikan, ExternalID: "43"}); err != nil {
+		t.Fatalf("ImportOne failed: %v", err)
+	}
+	if n := countManga(t, db); n != 2 {
+		t.Errorf("expected Re:Monster as a new manga, got %d manga", n)
+	}
+
+	// The threshold decides typo tolerance: 0.86 similar merges by default, not at 0.9
+	if _, err := imp.ImportOne(ctx, models.ExternalMangaData{Title: "Kaguya-sama: Love is War", Status: "ongoing", Source: models.SourceJikan, ExternalID: "44"}); err != nil {
+		t.Fatalf("ImportOne failed: %v", err)
+	}
+	typo := models.ExternalMangaData{Title: "Kaguya-sama: Love is Wars", Status: "ongoing", Source: models.SourceMangaDex, ExternalID: "md-2"}
+	for _, tt := range []struct {
+		threshold float64
+		merges    int
+	}{{0, 1}, {0.9, 0}} {
+		preview := NewImporter(db, nil)
+		preview.SetDedupe(true, tt.threshold)
+		preview.SetDryRun(true)
+		if _, err := preview.ImportOne(ctx, typo); err != nil {
+			t.Fatalf("dry run ImportOne failed: %v", err)
+		}
+		if got := len(preview.GetMerges()); got != tt.merges {
+			t.Errorf("threshold %.2f: expected %d merges, got %d", tt.threshold, tt.merges, got)
+		}
+	}
+}
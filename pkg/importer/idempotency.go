@@ -0,0 +1,79 @@
+// Package importer - Import Idempotency
+// Chạy lại cùng một batch (sau lỗi mạng) không xử lý lại các item đã import
+// Cách làm:
+//   - Mỗi lần import có idempotency key (vd. "importj:naruto")
+//   - Ghi lại (key, source, external ID) cùng fingerprint của record
+//   - Record đã xử lý và không đổi thì bỏ qua, tính vào Skipped
+//   - Record đổi (thêm chapter, đổi status) thì vẫn update bình thường
+package importer
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"mangahub/pkg/models"
+)
+
+// SetIdempotencyKey scopes the processed-record log used to skip items a
+// previous run with the same key already imported. "" disables skipping.
+func (i *Importer) SetIdempotencyKey(key string) {
+	i.idempotencyKey = key
+}
+
+// recordFingerprint hashes the fields an import writes, so any change in the
+// source record gives a different fingerprint
+func recordFingerprint(ext models.ExternalMangaData) string {
+	data, _ := json.Marshal(struct {
+		Title       string
+		Authors     []string
+		Description string
+		CoverURL    string
+		Status      string
+		Year        int
+		Chapters    int
+		Genres      []string
+	}{
+		ext.Title, ext.Authors, ext.Description, ext.CoverURL,
+		normalizeStatus(ext.Status), ext.Year, ext.ChapterCount, ext.Genres,
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// idempotent reports whether idempotency applies to ext
+func (i *Importer) idempotent(ext models.ExternalMangaData) bool {
+	return i.idempotencyKey != "" && !i.dryRun && ext.Source != "" && ext.ExternalID != ""
+}
+
+// alreadyProcessed returns the manga ID recorded for ext under the current
+// key, or "" if ext was not imported yet or has changed since
+func (i *Importer) alreadyProcessed(ctx context.Context, ext models.ExternalMangaData) (string, error) {
+	var mangaID, fingerprint string
+	err := i.conn().QueryRowContext(ctx, `
+		SELECT manga_id, fingerprint FROM import_processed
+		WHERE idempotency_key = ? AND source = ? AND external_id = ?`,
+		i.idempotencyKey, ext.Source, ext.ExternalID,
+	).Scan(&mangaID, &fingerprint)
+	if err == sql.ErrNoRows || (err == nil && fingerprint != recordFingerprint(ext)) {
+		return "", nil
+	}
+	return mangaID, err
+}
+
+// markProcessed records that ext was imported as mangaID under the current key
+func (i *Importer) markProcessed(ctx context.Context, ext models.ExternalMangaData, mangaID string) error {
+	_, err := i.conn().ExecContext(ctx, `
+		INSERT INTO import_processed (idempotency_key, source, external_id, fingerprint, manga_id, processed_at)
+		VALUES (?, ?, ?, ?, ?, ?)
+		ON CONFLICT(idempotency_key, source, external_id) DO UPDATE SET
+			fingerprint = excluded.fingerprint,
+			manga_id = excluded.manga_id,
+			processed_at = excluded.processed_at`,
+		i.idempotencyKey, ext.Source, ext.ExternalID, recordFingerprint(ext), mangaID, time.Now(),
+	)
+	return err
+}
@@ -3,9 +3,13 @@
 // Features:
 //   - Convert MangaDex/Jikan data to local Manga model
 //   - Upsert to avoid duplicates (update if exists)
+//   - Match theo external ID, title, rồi fuzzy title khi bật dedupe (dedupe.go)
 //   - Track external IDs for cross-referencing
-//   - Batch import support
-//   - Preview before import
+//   - Import chapter metadata (chapters.go)
+//   - Batch import theo chunk: mỗi chunk một transaction, chunk lỗi không ảnh hưởng chunk đã commit
+//   - Preview before import; kiểm tra chất lượng dữ liệu không ghi DB (validate.go)
+//   - Cache cover URLs theo external ID (Redis) để re-import không mất cover
+//   - Idempotency key: chạy lại batch bỏ qua item đã import (idempotency.go)
 package importer
 
 import (
@@ -16,6 +20,7 @@ import (
 	"time"
 
 	"mangahub/pkg/cache"
+	"mangahub/pkg/database"
 	"mangahub/pkg/models"
 
 	"github.com/google/uuid"
@@ -28,27 +33,69 @@ type Importer struct {
 	useCache    bool
 	dryRun      bool
 	importStats ImportStats
+
+	// Fuzzy title dedupe (see SetDedupe)
+	dedupe          bool
+	dedupeThreshold float64
+	titleIndex      []indexedTitle
+	merges          []MergeCandidate
+
+	// Chapter sources by external source (see SetChapterFetcher)
+	chapterFetchers map[string]ChapterFetcher
+
+	// Processed-record scope for re-runs (see SetIdempotencyKey)
+	idempotencyKey string
+
+	// Items per batch transaction (see SetChunkSize), and the transaction of
+	// the chunk being imported; nil outside ImportBatch
+	chunkSize int
+	tx        *sql.Tx
 }
 
+// DefaultChunkSize is how many items ImportBatch commits per transaction
+const DefaultChunkSize = 25
+
 // ImportStats tracks import statistics
 type ImportStats struct {
 	Total       int `json:"total"`
 	Inserted    int `json:"inserted"`
 	Updated     int `json:"updated"`
-	Skipped     int `json:"skipped"`
+	Skipped     int `json:"skipped"` // dry run, or already imported under the idempotency key
 	Failed      int `json:"failed"`
+	Merged      int `json:"merged"` // fuzzy title matches, also counted as updated
 	CacheHits   int `json:"cache_hits"`
 	CacheMisses int `json:"cache_misses"`
+	Chapters    int `json:"chapters"` // chapter rows written
 }
 
 // NewImporter creates a new importer instance
 func NewImporter(db *sql.DB, cacheClient *cache.RedisCache) *Importer {
 	return &Importer{
-		db:       db,
-		cache:    cacheClient,
-		useCache: cacheClient != nil,
-		dryRun:   false,
+		db:              db,
+		cache:           cacheClient,
+		useCache:        cacheClient != nil,
+		dryRun:          false,
+		dedupeThreshold: DefaultDedupeThreshold,
+		chunkSize:       DefaultChunkSize,
+	}
+}
+
+// SetChunkSize sets how many items ImportBatch writes per transaction;
+// n < 1 restores DefaultChunkSize
+func (i *Importer) SetChunkSize(n int) {
+	if n < 1 {
+		n = DefaultChunkSize
+	}
+	i.chunkSize = n
+}
+
+// conn is where import statements go: the open chunk transaction during
+// ImportBatch, else the database
+func (i *Importer) conn() dbtx {
+	if i.tx != nil {
+		return i.tx
 	}
+	return i.db
 }
 
 // SetDryRun enables/disables dry run mode (preview only)
@@ -56,6 +103,11 @@ func (i *Importer) SetDryRun(dryRun bool) {
 	i.dryRun = dryRun
 }
 
+// IsDryRun reports whether imports are only previewed
+func (i *Importer) IsDryRun() bool {
+	return i.dryRun
+}
+
 // GetStats returns import statistics
 func (i *Importer) GetStats() ImportStats {
 	return i.importStats
@@ -64,6 +116,7 @@ func (i *Importer) GetStats() ImportStats {
 // ResetStats resets import statistics
 func (i *Importer) ResetStats() {
 	i.importStats = ImportStats{}
+	i.merges = nil
 }
 
 // ConvertToManga converts ExternalMangaData to Manga model
@@ -139,25 +192,59 @@ func truncateDescription(desc string, maxLen int) string {
 func (i *Importer) ImportOne(ctx context.Context, ext models.ExternalMangaData) (*models.Manga, error) {
 	i.importStats.Total++
 
+	// A re-run with the same idempotency key skips unchanged records
+	if i.idempotent(ext) {
+		existingID, err := i.alreadyProcessed(ctx, ext)
+		if err != nil {
+			i.importStats.Failed++
+			return nil, fmt.Errorf("failed to check processed imports: %w", err)
+		}
+		if existingID != "" {
+			i.importStats.Skipped++
+			manga := ConvertToManga(ext)
+			manga.ID = existingID
+			return &manga, nil
+		}
+	}
+
+	// Fill or remember the cover URL
+	ext.CoverURL = i.resolveCoverURL(ctx, ext)
+
 	// Convert to Manga model
 	manga := ConvertToManga(ext)
 
-	if i.dryRun {
+	// Dry run without dedupe never touches the database
+	if i.dryRun && !i.dedupe {
 		i.importStats.Skipped++
 		return &manga, nil
 	}
 
-	// Check if manga with same title already exists
-	existingID, err := i.findExistingManga(ctx, manga.Title)
-	if err != nil && err != sql.ErrNoRows {
+	// Check if the manga already exists
+	existingID, err := i.matchExisting(ctx, ext, manga.Title)
+	if err != nil {
 		i.importStats.Failed++
 		return nil, fmt.Errorf("failed to check existing manga: %w", err)
 	}
 
+	if i.dryRun {
+		// Later rows of the batch may still merge into this would-be insert
+		if existingID == "" {
+			i.indexTitle(manga.ID, manga.Title)
+		}
+		i.importStats.Skipped++
+		return &manga, nil
+	}
+
+	var manual map[string]bool
 	if existingID != "" {
-		// Update existing manga
+		// Update existing manga; fields edited by hand keep their values
 		manga.ID = existingID
-		if err := i.updateManga(ctx, manga); err != nil {
+		manual, err = manualFields(ctx, i.conn(), existingID)
+		if err != nil {
+			i.importStats.Failed++
+			return nil, fmt.Errorf("failed to check manual fields: %w", err)
+		}
+		if err := i.updateManga(ctx, withoutManual(manga, manual)); err != nil {
 			i.importStats.Failed++
 			return nil, fmt.Errorf("failed to update manga: %w", err)
 		}
@@ -168,6 +255,7 @@ func (i *Importer) ImportOne(ctx context.Context, ext models.ExternalMangaData)
 			i.importStats.Failed++
 			return nil, fmt.Errorf("failed to insert manga: %w", err)
 		}
+		i.indexTitle(manga.ID, manga.Title)
 		i.importStats.Inserted++
 	}
 
@@ -177,32 +265,218 @@ func (i *Importer) ImportOne(ctx context.Context, ext models.ExternalMangaData)
 		fmt.Printf("Warning: failed to save external mapping: %v\n", err)
 	}
 
+	fields := importedFields(manga, manual, existingID == "")
+	if err := recordProvenance(ctx, i.conn(), manga.ID, ext.Source, ext.ExternalID, manga.UpdatedAt, fields...); err != nil {
+		// Non-fatal, just log
+		fmt.Printf("Warning: failed to record field provenance: %v\n", err)
+	}
+
+	// Chapter metadata is optional: the API synthesizes numbered chapters without it
+	if n, err := i.importChapters(ctx, manga.ID, ext); err != nil {
+		fmt.Printf("Warning: failed to import chapters for '%s': %v\n", manga.Title, err)
+	} else {
+		i.importStats.Chapters += n
+	}
+
+	if i.idempotent(ext) {
+		if err := i.markProcessed(ctx, ext, manga.ID); err != nil {
+			// Non-fatal: the next run imports this item again
+			fmt.Printf("Warning: failed to record processed import: %v\n", err)
+		}
+	}
+
 	return &manga, nil
 }
 
+// ProgressFunc reports batch progress: done of total items finished, and the
+// title about to be imported ("" once the batch is complete)
+type ProgressFunc func(done, total int, current string)
+
 // ImportBatch imports multiple manga entries
 func (i *Importer) ImportBatch(ctx context.Context, items []models.ExternalMangaData) ([]models.Manga, error) {
+	return i.ImportBatchWithProgress(ctx, items, nil)
+}
+
+// ImportBatchWithProgress imports items in chunks of SetChunkSize items,
+// each in its own transaction, calling progress (if not nil) before each item
+// and once at the end.
+//
+// A chunk whose transaction fails is rolled back on its own: chunks committed
+// before it stay, its items count as failed in GetStats, and the batch moves
+// on. ctx is checked between items: on cancellation the items already done
+// in the current chunk are committed, and the manga imported so far are
+// returned with ctx.Err().
+func (i *Importer) ImportBatchWithProgress(ctx context.Context, items []models.ExternalMangaData, progress ProgressFunc) ([]models.Manga, error) {
 	results := make([]models.Manga, 0, len(items))
 
-	for _, ext := range items {
-		manga, err := i.ImportOne(ctx, ext)
+	size := i.chunkSize
+	if size < 1 {
+		size = DefaultChunkSize
+	}
+	for start := 0; start < len(items); start += size {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		chunk := items[start:min(start+size, len(items))]
+		imported, err := i.importChunk(ctx, chunk, start, len(items), progress)
 		if err != nil {
-			// Log error but continue with other items
-			fmt.Printf("Import error for '%s': %v\n", ext.Title, err)
-			continue
+			// Log error but continue with the next chunk
+			fmt.Printf("Import error: items %d-%d rolled back: %v\n", start+1, start+len(chunk), err)
 		}
-		if manga != nil {
-			results = append(results, *manga)
+		results = append(results, imported...)
+		if err := ctx.Err(); err != nil {
+			return results, err
 		}
 	}
 
+	if progress != nil {
+		progress(len(items), len(items), "")
+	}
 	return results, nil
 }
 
+// importChunk imports chunk in one transaction. Statements run on a context
+// that cancellation doesn't reach, so stopping between items still commits
+// the items done; a failed transaction restores the stats, title index and
+// merge list to before the chunk and counts the items it reached as failed.
+func (i *Importer) importChunk(ctx context.Context, chunk []models.ExternalMangaData, offset, total int, progress ProgressFunc) ([]models.Manga, error) {
+	chunk = append([]models.ExternalMangaData(nil), chunk...) // prefetching fills in Chapters
+	i.prefetchChapters(ctx, chunk)
+
+	stats, titles, merges := i.importStats, i.titleIndex, i.merges
+	var imported []models.Manga
+	reached := 0
+	txCtx := context.WithoutCancel(ctx)
+	err := database.WithTx(txCtx, i.db, func(tx *sql.Tx) error {
+		// A busy retry runs the chunk again from the start
+		i.importStats, i.titleIndex, i.merges = stats, titles, merges
+		imported, reached = nil, 0
+		i.tx = tx
+		defer func() { i.tx = nil }()
+
+		for n, ext := range chunk {
+			if progress != nil {
+				progress(offset+n, total, ext.Title)
+			}
+			if ctx.Err() != nil {
+				return nil // commit what is done
+			}
+			reached++
+
+			manga, err := i.ImportOne(txCtx, ext)
+			if err != nil {
+				// Log error but continue with other items
+				fmt.Printf("Import error for '%s': %v\n", ext.Title, err)
+				continue
+			}
+			if manga != nil {
+				imported = append(imported, *manga)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		i.importStats, i.titleIndex, i.merges = stats, titles, merges
+		i.importStats.Total += reached
+		i.importStats.Failed += reached
+		return nil, err
+	}
+	return imported, nil
+}
+
+// resolveCoverURL returns the cover URL for ext, falling back to the cached
+// URL from an earlier import when the source returned none this time.
+// Newly seen URLs are cached; cache errors only cost a refetch.
+func (i *Importer) resolveCoverURL(ctx context.Context, ext models.ExternalMangaData) string {
+	if !i.useCache || ext.ExternalID == "" {
+		return ext.CoverURL
+	}
+
+	key := cache.CoverKey(ext.Source, ext.ExternalID)
+	if ext.CoverURL != "" {
+		if i.dryRun {
+			return ext.CoverURL
+		}
+		if err := i.cache.Set(ctx, key, ext.CoverURL, cache.TTLWeek); err != nil {
+			fmt.Printf("Warning: failed to cache cover URL: %v\n", err)
+		}
+		return ext.CoverURL
+	}
+
+	cached, err := i.cache.Get(ctx, key)
+	if err != nil {
+		return ""
+	}
+	if cached != "" {
+		i.importStats.CacheHits++
+	} else {
+		i.importStats.CacheMisses++
+	}
+	return cached
+}
+
+// matchExisting finds the manga an import refers to: by external ID, then by
+// exact title, then (with dedupe on) by title similarity. Returns "" if new.
+func (i *Importer) matchExisting(ctx context.Context, ext models.ExternalMangaData, title string) (string, error) {
+	id, err := i.findByExternalID(ctx, ext)
+	if err != nil || id != "" {
+		return id, err
+	}
+
+	id, err = i.findExistingManga(ctx, title)
+	if err != sql.ErrNoRows {
+		return id, err
+	}
+	if !i.dedupe {
+		return "", nil
+	}
+
+	match, score, err := i.findSimilarManga(ctx, title)
+	if err != nil || match == nil {
+		return "", err
+	}
+	i.importStats.Merged++
+	i.merges = append(i.merges, MergeCandidate{
+		Title:         title,
+		Source:        ext.Source,
+		ExternalID:    ext.ExternalID,
+		ExistingID:    match.id,
+		ExistingTitle: match.title,
+		Similarity:    score,
+	})
+	return match.id, nil
+}
+
+// findByExternalID looks up a manga already imported from the same source
+func (i *Importer) findByExternalID(ctx context.Context, ext models.ExternalMangaData) (string, error) {
+	var query string
+	var arg interface{}
+	switch ext.Source {
+	case models.SourceMangaDex:
+		query, arg = "SELECT manga_id FROM manga_external_ids WHERE mangadex_id = ? LIMIT 1", ext.ExternalID
+	case models.SourceJikan:
+		var malID int
+		fmt.Sscanf(ext.ExternalID, "%d", &malID)
+		query, arg = "SELECT manga_id FROM manga_external_ids WHERE mal_id = ? LIMIT 1", malID
+	default:
+		return "", nil
+	}
+	if ext.ExternalID == "" {
+		return "", nil
+	}
+
+	var id string
+	err := i.conn().QueryRowContext(ctx, query, arg).Scan(&id)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	return id, err
+}
+
 // findExistingManga checks if a manga with the same title exists
 func (i *Importer) findExistingManga(ctx context.Context, title string) (string, error) {
 	var id string
-	err := i.db.QueryRowContext(ctx,
+	err := i.conn().QueryRowContext(ctx,
 		"SELECT id FROM manga WHERE LOWER(title) = LOWER(?) LIMIT 1",
 		title,
 	).Scan(&id)
@@ -213,7 +487,7 @@ func (i *Importer) findExistingManga(ctx context.Context, title string) (string,
 // Note: Genres must be inserted separately via manga_genres junction table
 // Note: Ratings must be inserted separately via manga_ratings table
 func (i *Importer) insertManga(ctx context.Context, m models.Manga) error {
-	_, err := i.db.ExecContext(ctx, `
+	_, err := i.conn().ExecContext(ctx, `
 		INSERT INTO manga (id, title, author, artist, description, cover_url, status, type, total_chapters, year, created_at, updated_at)
 		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
 		m.ID, m.Title, m.Author, m.Artist, m.Description, m.CoverURL, m.Status, m.Type, m.TotalChapters, m.Year, m.CreatedAt, m.UpdatedAt,
@@ -222,15 +496,16 @@ func (i *Importer) insertManga(ctx context.Context, m models.Manga) error {
 }
 
 // updateManga updates an existing manga in the database
+// Empty values (and a zero year or chapter count) leave the stored value alone
 // Note: Genres should be updated separately via manga_genres junction table
 // Note: Ratings should be updated separately via manga_ratings table
 func (i *Importer) updateManga(ctx context.Context, m models.Manga) error {
-	_, err := i.db.ExecContext(ctx, `
+	_, err := i.conn().ExecContext(ctx, `
 		UPDATE manga SET 
 			author = COALESCE(NULLIF(?, ''), author),
 			description = COALESCE(NULLIF(?, ''), description),
 			cover_url = COALESCE(NULLIF(?, ''), cover_url),
-			status = ?,
+			status = COALESCE(NULLIF(?, ''), status),
 			total_chapters = CASE WHEN ? > total_chapters THEN ? ELSE total_chapters END,
 			year = COALESCE(NULLIF(?, 0), year),
 			updated_at = ?
@@ -242,45 +517,29 @@ func (i *Importer) updateManga(ctx context.Context, m models.Manga) error {
 	return err
 }
 
-// saveExternalMapping saves the external ID mapping for cross-referencing
+// saveExternalMapping saves the external ID mapping for cross-referencing.
+// manga_external_ids has one row per manga; IDs from other sources are kept.
 func (i *Importer) saveExternalMapping(ctx context.Context, mangaID string, ext models.ExternalMangaData) error {
-	// Check if mapping exists
-	var existingID string
-	err := i.db.QueryRowContext(ctx,
-		"SELECT id FROM manga_external_ids WHERE manga_id = ? AND primary_source = ?",
-		mangaID, ext.Source,
-	).Scan(&existingID)
-
 	now := time.Now()
 
-	if err == sql.ErrNoRows {
-		// Insert new mapping
-		id := uuid.New().String()
-		var malID int
-		if ext.Source == models.SourceJikan {
-			// Parse MAL ID from external ID
-			fmt.Sscanf(ext.ExternalID, "%d", &malID)
-		}
-
-		_, err = i.db.ExecContext(ctx, `
-			INSERT INTO manga_external_ids (id, manga_id, mangadex_id, mal_id, primary_source, last_synced_at, created_at, updated_at)
-			VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
-			id, mangaID,
-			sqlNullString(ext.Source == models.SourceMangaDex, ext.ExternalID),
-			sqlNullInt(malID),
-			ext.Source, now, now, now,
-		)
-		return err
-	}
-
-	if err != nil {
-		return err
+	var malID int
+	if ext.Source == models.SourceJikan {
+		// Parse MAL ID from external ID
+		fmt.Sscanf(ext.ExternalID, "%d", &malID)
 	}
 
-	// Update existing mapping
-	_, err = i.db.ExecContext(ctx,
-		"UPDATE manga_external_ids SET last_synced_at = ?, updated_at = ? WHERE id = ?",
-		now, now, existingID,
+	_, err := i.conn().ExecContext(ctx, `
+		INSERT INTO manga_external_ids (manga_id, mangadex_id, mal_id, primary_source, last_synced_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(manga_id) DO UPDATE SET
+			mangadex_id = COALESCE(excluded.mangadex_id, mangadex_id),
+			mal_id = COALESCE(excluded.mal_id, mal_id),
+			last_synced_at = excluded.last_synced_at,
+			updated_at = excluded.updated_at`,
+		mangaID,
+		sqlNullString(ext.Source == models.SourceMangaDex, ext.ExternalID),
+		sqlNullInt(malID),
+		ext.Source, now, now, now,
 	)
 	return err
 }
@@ -0,0 +1,188 @@
+// Package importer - Batch Import Tests
+// Unit tests cho progress callback, huỷ import giữa chừng và chunk lỗi
+package importer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+func batchItems(n int) []models.ExternalMangaData {
+	items := make([]models.ExternalMangaData, n)
+	for i := range items {
+		items[i] = models.ExternalMangaData{
+			Source:     models.SourceJikan,
+			ExternalID: fmt.Sprintf("%d", i+1),
+			Title:      fmt.Sprintf("Manga %d", i+1),
+			Status:     "ongoing",
+		}
+	}
+	return items
+}
+
+func TestImportBatchReportsProgress(t *testing.T) {
+	imp := NewImporter(testutil.OpenDB(t), nil)
+
+	var calls [][2]int
+	var titles []string
+	_, err := imp.ImportBatchWithProgress(context.Background(), batchItems(3), func(done, total int, current string) {
+		calls = append(calls, [2]int{done, total})
+		titles = append(titles, current)
+	})
+	if err != nil {
+		t.Fatalf("ImportBatchWithProgress: %v", err)
+	}
+
+	want := [][2]int{{0, 3}, {1, 3}, {2, 3}, {3, 3}}
+	if fmt.Sprint(calls) != fmt.Sprint(want) {
+		t.Errorf("progress calls = %v, want %v", calls, want)
+	}
+	if titles[0] != "Manga 1" || titles[3] != "" {
+		t.Errorf("current titles = %q", titles)
+	}
+}
+
+func TestImportBatchStopsOnCancel(t *testing.T) {
+	db := testutil.OpenDB(t)
+	imp := NewImporter(db, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results, err := imp.ImportBatchWithProgress(ctx, batchItems(5), func(done, total int, current string) {
+		if done == 2 {
+			cancel() // user pressed Esc while the third item was next
+		}
+	})
+
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want context.Canceled", err)
+	}
+	if len(results) != 2 || countManga(t, db) != 2 {
+		t.Errorf("imported %d results, %d rows; want 2 of each", len(results), countManga(t, db))
+	}
+	if stats := imp.GetStats(); stats.Inserted != 2 {
+		t.Errorf("partial stats = %+v, want 2 inserted", stats)
+	}
+}
+
+func TestImportBatchIsolatesFailedChunk(t *testing.T) {
+	db := testutil.OpenDB(t)
+	// A deferred foreign key only fails at COMMIT: the chunk holding
+	// "Manga 3" is rejected after every item in it was written
+	for _, stmt := range []string{
+		`PRAGMA foreign_keys = ON`,
+		`CREATE TABLE poison (genre_id TEXT REFERENCES genres(id) DEFERRABLE INITIALLY DEFERRED)`,
+		`CREATE TRIGGER poison_manga AFTER INSERT ON manga WHEN NEW.title = 'Manga 3'
+		 BEGIN INSERT INTO poison VALUES ('no-such-genre'); END`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("%s: %v", stmt, err)
+		}
+	}
+
+	imp := NewImporter(db, nil)
+	imp.SetChunkSize(2)
+	results, err := imp.ImportBatch(context.Background(), batchItems(5))
+	if err != nil {
+		t.Fatalf("ImportBatch: %v", err)
+	}
+
+	// Chunks [1 2] and [5] commit; [3 4] rolls back without touching them
+	if len(results) != 3 || countManga(t, db) != 3 {
+		t.Errorf("imported %d results, %d rows; want 3 of each", len(results), countManga(t, db))
+	}
+	stats := imp.GetStats()
+	if stats.Total != 5 || stats.Inserted != 3 || stats.Failed != 2 {
+		t.Errorf("stats = %+v, want 5 total: 3 inserted, 2 failed", stats)
+	}
+}
+
+func TestReimportUpsertsChapters(t *testing.T) {
+	db := testutil.OpenDB(t)
+	imp := NewImporter(db, nil)
+	ctx := context.Background()
+
+	fetched := []models.ExternalChapter{
+		{Number: 1, Title: "The Black Swordsman", ExternalID: "c1"},
+		{Number: 2, ExternalID: "c2"},
+	}
+	imp.SetChapterFetcher(models.SourceMangaDex, func(ctx context.Context, externalID string) ([]models.ExternalChapter, error) {
+		return fetched, nil
+	})
+	ext := models.ExternalMangaData{Source: models.SourceMangaDex, ExternalID: "md-1", Title: "Berserk", Status: "ongoing"}
+
+	manga, err := imp.ImportOne(ctx, ext)
+	if err != nil {
+		t.Fatalf("first import: %v", err)
+	}
+
+	// Re-import: chapter 2 gains a title, chapter 1 comes back untitled, chapter 3 is new
+	fetched = []models.ExternalChapter{
+		{Number: 1, ExternalID: "c1"},
+		{Number: 2, Title: "The Brand", ExternalID: "c2"},
+		{Number: 3, Title: "The Guardians of Desire", ExternalID: "c3"},
+	}
+	if _, err := imp.ImportOne(ctx, ext); err != nil {
+		t.Fatalf("re-import: %v", err)
+	}
+
+	rows, err := db.Query(`SELECT number, title FROM chapters WHERE manga_id = ? ORDER BY number`, manga.ID)
+	if err != nil {
+		t.Fatalf("query chapters: %v", err)
+	}
+	defer rows.Close()
+	var got []string
+	for rows.Next() {
+		var n int
+		var title string
+		if err := rows.Scan(&n, &title); err != nil {
+			t.Fatalf("scan: %v", err)
+		}
+		got = append(got, fmt.Sprintf("%d:%s", n, title))
+	}
+	want := "[1:The Black Swordsman 2:The Brand 3:The Guardians of Desire]"
+	if fmt.Sprint(got) != want {
+		t.Errorf("chapters = %v, want %s", got, want)
+	}
+}
+
+func TestRerunWithIdempotencyKeySkipsUnchanged(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+	items := batchItems(2)
+
+	first := NewImporter(db, nil)
+	first.SetIdempotencyKey("importj:manga")
+	if _, err := first.ImportBatch(ctx, items); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+
+	// Re-run after a "network blip": item 2 gained chapters meanwhile, and a
+	// MangaDex record shares item 1's external ID
+	items[1].ChapterCount = 42
+	items = append(items, models.ExternalMangaData{
+		Source: models.SourceMangaDex, ExternalID: items[0].ExternalID, Title: "Another Manga", Status: "ongoing",
+	})
+	rerun := NewImporter(db, nil)
+	rerun.SetIdempotencyKey("importj:manga")
+	if _, err := rerun.ImportBatch(ctx, items); err != nil {
+		t.Fatalf("re-run: %v", err)
+	}
+
+	stats := rerun.GetStats()
+	if stats.Skipped != 1 || stats.Updated != 1 || stats.Inserted != 1 {
+		t.Errorf("re-run stats = %+v, want 1 skipped, 1 updated, 1 inserted", stats)
+	}
+	var chapters int
+	if err := db.QueryRow(`SELECT total_chapters FROM manga WHERE title = 'Manga 2'`).Scan(&chapters); err != nil {
+		t.Fatalf("query chapters: %v", err)
+	}
+	if chapters != 42 {
+		t.Errorf("total_chapters = %d, want the changed record's 42", chapters)
+	}
+}
@@ -0,0 +1,103 @@
+// Package importer - Library Import Queue
+// Xử lý các entry trong library_import_queue (manga chưa có trong DB khi user bulk import)
+// Flow:
+//  1. PendingLibraryImports lấy các entry đang chờ
+//  2. Caller fetch manga từ external API và gọi ImportOne
+//  3. CompleteLibraryImport thêm manga vào library của user
+//     hoặc FailLibraryImport ghi lại lỗi
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"mangahub/pkg/database"
+	"mangahub/pkg/models"
+
+	"github.com/google/uuid"
+)
+
+// PendingLibraryImports returns queued library entries, oldest first
+func (i *Importer) PendingLibraryImports(ctx context.Context, limit int) ([]models.QueuedLibraryImport, error) {
+	rows, err := i.db.QueryContext(ctx, `
+		SELECT id, user_id, source, external_id, COALESCE(title, ''), COALESCE(status, 'plan_to_read'),
+		       current_chapter, is_favorite, state, COALESCE(last_error, ''), created_at, updated_at
+		FROM library_import_queue
+		WHERE state = ?
+		ORDER BY created_at ASC
+		LIMIT ?`, models.ImportQueuePending, limit)
+	if err != nil {
+		return nil, fmt.Errorf("list import queue: %w", err)
+	}
+	defer rows.Close()
+
+	var queue []models.QueuedLibraryImport
+	for rows.Next() {
+		var q models.QueuedLibraryImport
+		if err := rows.Scan(
+			&q.ID, &q.UserID, &q.Source, &q.ExternalID, &q.Title, &q.Status,
+			&q.CurrentChapter, &q.IsFavorite, &q.State, &q.LastError, &q.CreatedAt, &q.UpdatedAt,
+		); err != nil {
+			return nil, fmt.Errorf("scan import queue: %w", err)
+		}
+		queue = append(queue, q)
+	}
+	return queue, rows.Err()
+}
+
+// CompleteLibraryImport adds the fetched manga to the user's library
+// and marks the queue entry done
+func (i *Importer) CompleteLibraryImport(ctx context.Context, q models.QueuedLibraryImport, mangaID string) error {
+	if i.dryRun {
+		return nil
+	}
+
+	now := time.Now()
+	return database.WithTx(ctx, i.db, func(tx *sql.Tx) error {
+		return completeLibraryImport(ctx, tx, q, mangaID, now)
+	})
+}
+
+// completeLibraryImport writes the library entry and the queue state in tx
+func completeLibraryImport(ctx context.Context, tx *sql.Tx, q models.QueuedLibraryImport, mangaID string, now time.Time) error {
+	_, err := tx.ExecContext(ctx, `
+		INSERT INTO reading_progress
+		(id, user_id, manga_id, current_chapter, status, is_favorite, last_read_at, created_at, updated_at)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(user_id, manga_id) DO UPDATE SET
+			current_chapter = excluded.current_chapter,
+			status = excluded.status,
+			is_favorite = excluded.is_favorite,
+			updated_at = excluded.updated_at`,
+		uuid.New().String(), q.UserID, mangaID, q.CurrentChapter, q.Status, q.IsFavorite, now, now, now,
+	)
+	if err != nil {
+		return fmt.Errorf("insert progress: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE library_import_queue SET state = ?, last_error = '', updated_at = ? WHERE id = ?",
+		models.ImportQueueDone, now, q.ID,
+	)
+	if err != nil {
+		return fmt.Errorf("update import queue: %w", err)
+	}
+	return nil
+}
+
+// FailLibraryImport records why a queued entry could not be fetched
+func (i *Importer) FailLibraryImport(ctx context.Context, id string, cause error) error {
+	if i.dryRun {
+		return nil
+	}
+	_, err := i.db.ExecContext(ctx,
+		"UPDATE library_import_queue SET state = ?, last_error = ?, updated_at = ? WHERE id = ?",
+		models.ImportQueueFailed, cause.Error(), time.Now(), id,
+	)
+	if err != nil {
+		return fmt.Errorf("update import queue: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,261 @@
+// Package importer - Merge Duplicate Manga
+// Gộp một manga trùng (source) vào manga giữ lại (target), trong một transaction
+// Chức năng:
+//   - Reparent mọi bảng tham chiếu manga (progress, ratings, comments, genres, lists, ...)
+//   - Trùng unique key (vd. user rate cả hai): giữ row mới nhất, hòa thì giữ row của target
+//   - manga_external_ids: target giữ ID của mình, thiếu thì lấy từ source
+//   - Tính lại average_rating/rating_count, xóa source, ghi audit_log
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"mangahub/pkg/database"
+	"mangahub/pkg/logger"
+)
+
+// ErrMergeSameManga is returned when source and target are the same manga
+var ErrMergeSameManga = errors.New("cannot merge a manga into itself")
+
+// MergeResult says exactly which rows a merge moved and which it dropped
+type MergeResult struct {
+	SourceID      string         `json:"source_id"`
+	SourceTitle   string         `json:"source_title"`
+	TargetID      string         `json:"target_id"`
+	TargetTitle   string         `json:"target_title"`
+	Reparented    map[string]int `json:"reparented"` // table → rows moved onto the target
+	Dropped       map[string]int `json:"dropped"`    // table → older duplicates deleted
+	AverageRating float64        `json:"average_rating"`
+	RatingCount   int            `json:"rating_count"`
+}
+
+// Moved is the total number of rows reparented
+func (r *MergeResult) Moved() int {
+	n := 0
+	for _, count := range r.Reparented {
+		n += count
+	}
+	return n
+}
+
+// mergeTable is one table that references manga(id)
+type mergeTable struct {
+	name string
+	// key holds the other columns of a unique constraint that includes manga_id;
+	// empty when the table has none and rows can simply be moved
+	key []string
+	// recency picks the row kept on a collision: the larger value wins
+	recency string
+}
+
+// mergeTables lists every table with a manga_id. Deleting the source cascades,
+// so a table missing here silently loses the source's rows.
+// manga_external_ids is handled separately (one row per manga).
+// manga_field_provenance is left to the cascade: the target keeps its own
+// fields, so the source's provenance describes nothing that survives.
+var mergeTables = []mergeTable{
+	{name: "reading_progress", key: []string{"user_id"}, recency: "updated_at"},
+	{name: "manga_ratings", key: []string{"user_id"}, recency: "updated_at"},
+	{name: "comments"},
+	{name: "manga_genres", key: []string{"genre_id"}, recency: "created_at"},
+	{name: "custom_list_items", key: []string{"list_id"}, recency: "added_at"},
+	{name: "notification_mutes", key: []string{"user_id"}, recency: "created_at"},
+	{name: "chapters", key: []string{"number"}, recency: "updated_at"},
+	{name: "chapter_history"},
+	{name: "activity_feed"},
+	{name: "manga_status_history"},
+	{name: "chat_rooms"},
+	{name: "import_processed"}, // re-imports of the source's records now update the target
+}
+
+// Merger merges duplicate manga
+type Merger struct {
+	db  *sql.DB
+	now func() time.Time
+}
+
+// NewMerger creates a merger
+func NewMerger(db *sql.DB) *Merger {
+	return &Merger{db: db, now: time.Now}
+}
+
+// Merge moves everything attached to sourceID onto targetID and deletes the
+// source, all in one transaction. actorID is recorded in the audit log.
+// The target's own fields (title, status, ...) are kept as they are.
+func (m *Merger) Merge(ctx context.Context, actorID, sourceID, targetID string) (*MergeResult, error) {
+	if sourceID == targetID {
+		return nil, ErrMergeSameManga
+	}
+
+	var result *MergeResult
+	err := database.WithTx(ctx, m.db, func(tx *sql.Tx) error {
+		var err error
+		result, err = m.merge(ctx, tx, actorID, sourceID, targetID)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Infof("Merged manga %s (%s) into %s (%s) by %s: reparented %v, dropped %v",
+		sourceID, result.SourceTitle, targetID, result.TargetTitle, actorID, result.Reparented, result.Dropped)
+	return result, nil
+}
+
+// merge does the work of Merge inside tx
+func (m *Merger) merge(ctx context.Context, tx *sql.Tx, actorID, sourceID, targetID string) (*MergeResult, error) {
+	result := &MergeResult{
+		SourceID:   sourceID,
+		TargetID:   targetID,
+		Reparented: make(map[string]int),
+		Dropped:    make(map[string]int),
+	}
+	for id, title := range map[string]*string{sourceID: &result.SourceTitle, targetID: &result.TargetTitle} {
+		err := tx.QueryRowContext(ctx, "SELECT title FROM manga WHERE id = ?", id).Scan(title)
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("%w: %s", ErrMangaNotFound, id)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("get manga %s: %w", id, err)
+		}
+	}
+
+	for _, t := range mergeTables {
+		if len(t.key) > 0 {
+			dropped, err := dropCollisions(ctx, tx, t, sourceID, targetID)
+			if err != nil {
+				return nil, err
+			}
+			if dropped > 0 {
+				result.Dropped[t.name] = dropped
+			}
+		}
+		res, err := tx.ExecContext(ctx, "UPDATE "+t.name+" SET manga_id = ? WHERE manga_id = ?", targetID, sourceID)
+		if err != nil {
+			return nil, fmt.Errorf("reparent %s: %w", t.name, err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			result.Reparented[t.name] = int(n)
+		}
+	}
+
+	// The feed shows the title it was written with; moved entries take the target's
+	if _, err := tx.ExecContext(ctx,
+		"UPDATE activity_feed SET manga_title = ? WHERE manga_id = ? AND manga_title = ?",
+		result.TargetTitle, targetID, result.SourceTitle,
+	); err != nil {
+		return nil, fmt.Errorf("retitle activity: %w", err)
+	}
+
+	if err := mergeExternalIDs(ctx, tx, sourceID, targetID, result); err != nil {
+		return nil, err
+	}
+
+	// The rating triggers only see one row at a time; recompute from scratch
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE manga SET
+			average_rating = (SELECT COALESCE(AVG(rating), 0) FROM manga_ratings WHERE manga_id = manga.id),
+			rating_count = (SELECT COUNT(*) FROM manga_ratings WHERE manga_id = manga.id),
+			updated_at = ?
+		WHERE id = ?`, m.now(), targetID,
+	); err != nil {
+		return nil, fmt.Errorf("recompute rating: %w", err)
+	}
+	if err := tx.QueryRowContext(ctx,
+		"SELECT average_rating, rating_count FROM manga WHERE id = ?", targetID,
+	).Scan(&result.AverageRating, &result.RatingCount); err != nil {
+		return nil, fmt.Errorf("read rating: %w", err)
+	}
+
+	if _, err := tx.ExecContext(ctx, "DELETE FROM manga WHERE id = ?", sourceID); err != nil {
+		return nil, fmt.Errorf("delete source manga: %w", err)
+	}
+
+	details, _ := json.Marshal(map[string]interface{}{
+		"source_id":    sourceID,
+		"source_title": result.SourceTitle,
+		"reparented":   result.Reparented,
+		"dropped":      result.Dropped,
+	})
+	if _, err := tx.ExecContext(ctx, `
+		INSERT INTO audit_log (id, actor_id, action, target_type, target_id, details, created_at)
+		VALUES (?, ?, 'manga.merge', 'manga', ?, ?, ?)`,
+		uuid.New().String(), actorID, targetID, string(details), m.now(),
+	); err != nil {
+		return nil, fmt.Errorf("insert audit log: %w", err)
+	}
+	return result, nil
+}
+
+// dropCollisions deletes, for each key present on both manga, the older of the
+// two rows so the reparenting UPDATE cannot break the unique constraint.
+// On equal recency the target's row is kept. Recency columns hold both Go
+// times with a zone offset and UTC datetime('now') text, so they compare
+// through julianday().
+func dropCollisions(ctx context.Context, tx *sql.Tx, t mergeTable, sourceID, targetID string) (int, error) {
+	match := ""
+	for _, col := range t.key {
+		match += fmt.Sprintf(" AND o.%s = %s.%s", col, t.name, col)
+	}
+	query := fmt.Sprintf(`
+		DELETE FROM %[1]s
+		WHERE manga_id IN (?, ?) AND EXISTS (
+			SELECT 1 FROM %[1]s o
+			WHERE o.manga_id IN (?, ?) AND o.manga_id != %[1]s.manga_id%[2]s
+			  AND (COALESCE(julianday(o.%[3]s), 0) > COALESCE(julianday(%[1]s.%[3]s), 0)
+			       OR (COALESCE(julianday(o.%[3]s), 0) = COALESCE(julianday(%[1]s.%[3]s), 0) AND o.manga_id = ?))
+		)`, t.name, match, t.recency)
+	res, err := tx.ExecContext(ctx, query, sourceID, targetID, sourceID, targetID, targetID)
+	if err != nil {
+		return 0, fmt.Errorf("resolve %s collisions: %w", t.name, err)
+	}
+	n, _ := res.RowsAffected()
+	return int(n), nil
+}
+
+// mergeExternalIDs keeps the target's external IDs and fills the ones it lacks
+// from the source, so both upstream records resync into the target
+func mergeExternalIDs(ctx context.Context, tx *sql.Tx, sourceID, targetID string, result *MergeResult) error {
+	var targetLinked bool
+	if err := tx.QueryRowContext(ctx,
+		"SELECT EXISTS(SELECT 1 FROM manga_external_ids WHERE manga_id = ?)", targetID,
+	).Scan(&targetLinked); err != nil {
+		return fmt.Errorf("get target external ids: %w", err)
+	}
+
+	if !targetLinked {
+		res, err := tx.ExecContext(ctx,
+			"UPDATE manga_external_ids SET manga_id = ? WHERE manga_id = ?", targetID, sourceID)
+		if err != nil {
+			return fmt.Errorf("reparent manga_external_ids: %w", err)
+		}
+		if n, _ := res.RowsAffected(); n > 0 {
+			result.Reparented["manga_external_ids"] = int(n)
+		}
+		return nil
+	}
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE manga_external_ids SET
+			mangadex_id = COALESCE(manga_external_ids.mangadex_id, s.mangadex_id),
+			anilist_id = COALESCE(manga_external_ids.anilist_id, s.anilist_id),
+			mal_id = COALESCE(manga_external_ids.mal_id, s.mal_id),
+			kitsu_id = COALESCE(manga_external_ids.kitsu_id, s.kitsu_id),
+			updated_at = CURRENT_TIMESTAMP
+		FROM (SELECT * FROM manga_external_ids WHERE manga_id = ?) AS s
+		WHERE manga_external_ids.manga_id = ?`, sourceID, targetID)
+	if err != nil {
+		return fmt.Errorf("merge manga_external_ids: %w", err)
+	}
+	if n, _ := res.RowsAffected(); n > 0 {
+		// The source row is folded into the target's and goes with the source manga
+		result.Reparented["manga_external_ids"] = int(n)
+	}
+	return nil
+}
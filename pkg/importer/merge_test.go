@@ -0,0 +1,92 @@
+// Package importer - Merge Tests
+// Unit tests cho merge manga trùng: reparent, giữ rating mới nhất, tính lại rating
+package importer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mangahub/internal/testutil"
+)
+
+func TestMergeReparentsAndKeepsNewest(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	for _, stmt := range []string{
+		`INSERT INTO users (id, username, email, password_hash, display_name) VALUES ('u1', 'alice', 'a@x', 'h', 'Alice'), ('u2', 'bob', 'b@x', 'h', 'Bob'), ('u3', 'carol', 'c@x', 'h', 'Carol')`,
+		`INSERT INTO manga (id, title) VALUES ('dup', 'Berserk (MAL)'), ('keep', 'Berserk')`,
+		`INSERT INTO genres (id, name, slug) VALUES ('g1', 'Action', 'action'), ('g2', 'Horror', 'horror')`,
+		`INSERT INTO manga_genres (id, manga_id, genre_id) VALUES ('mg1', 'dup', 'g1'), ('mg2', 'dup', 'g2'), ('mg3', 'keep', 'g1')`,
+		`INSERT INTO manga_external_ids (manga_id, mal_id) VALUES ('dup', 2)`,
+		`INSERT INTO manga_external_ids (manga_id, mangadex_id) VALUES ('keep', 'md-1')`,
+		// alice rated both: the newer rating (on the duplicate) wins
+		`INSERT INTO manga_ratings (id, manga_id, user_id, rating, updated_at) VALUES
+			('r1', 'dup', 'u1', 10, '2026-02-01 00:00:00'),
+			('r2', 'keep', 'u1', 4, '2026-01-01 00:00:00'),
+			('r3', 'dup', 'u2', 8, '2026-01-01 00:00:00')`,
+		// bob tracks both: the newer progress (on the target) wins
+		`INSERT INTO reading_progress (id, user_id, manga_id, current_chapter, updated_at) VALUES
+			('p1', 'u2', 'dup', 10, '2026-01-01 00:00:00'),
+			('p2', 'u2', 'keep', 50, '2026-03-01 00:00:00')`,
+		// carol's rows mix formats: REST writes Go times with a zone offset,
+		// gRPC writes UTC datetime('now'). The duplicate's 10:00 UTC is newer
+		// than the target's 15:00+07:00 (08:00 UTC)
+		`INSERT INTO reading_progress (id, user_id, manga_id, current_chapter, updated_at) VALUES
+			('p3', 'u3', 'dup', 30, '2026-04-01 10:00:00'),
+			('p4', 'u3', 'keep', 20, '2026-04-01 15:00:00.123456789+07:00')`,
+		`INSERT INTO comments (id, manga_id, user_id, content) VALUES ('c1', 'dup', 'u1', 'hi')`,
+	} {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("seed: %v\n%s", err, stmt)
+		}
+	}
+
+	result, err := NewMerger(db).Merge(ctx, "admin", "dup", "keep")
+	if err != nil {
+		t.Fatalf("Merge: %v", err)
+	}
+
+	if result.Reparented["manga_ratings"] != 2 || result.Dropped["manga_ratings"] != 1 {
+		t.Errorf("ratings: reparented %d, dropped %d", result.Reparented["manga_ratings"], result.Dropped["manga_ratings"])
+	}
+	if result.Dropped["reading_progress"] != 2 || result.Reparented["reading_progress"] != 1 {
+		t.Errorf("progress: %+v", result)
+	}
+	if result.RatingCount != 2 || result.AverageRating != 9 {
+		t.Errorf("rating = %.2f over %d, want 9 over 2", result.AverageRating, result.RatingCount)
+	}
+
+	var rating, chapter, genres, comments, mangaCount int
+	db.QueryRow("SELECT rating FROM manga_ratings WHERE manga_id = 'keep' AND user_id = 'u1'").Scan(&rating)
+	db.QueryRow("SELECT current_chapter FROM reading_progress WHERE manga_id = 'keep' AND user_id = 'u2'").Scan(&chapter)
+	db.QueryRow("SELECT COUNT(*) FROM manga_genres WHERE manga_id = 'keep'").Scan(&genres)
+	db.QueryRow("SELECT COUNT(*) FROM comments WHERE manga_id = 'keep'").Scan(&comments)
+	db.QueryRow("SELECT COUNT(*) FROM manga WHERE id = 'dup'").Scan(&mangaCount)
+	if rating != 10 || chapter != 50 || genres != 2 || comments != 1 || mangaCount != 0 {
+		t.Errorf("rating %d, chapter %d, genres %d, comments %d, source rows %d", rating, chapter, genres, comments, mangaCount)
+	}
+	var carolChapter int
+	db.QueryRow("SELECT current_chapter FROM reading_progress WHERE manga_id = 'keep' AND user_id = 'u3'").Scan(&carolChapter)
+	if carolChapter != 30 {
+		t.Errorf("carol's chapter = %d, want 30 from the newer row across formats", carolChapter)
+	}
+
+	var mangadexID string
+	var malID int
+	db.QueryRow("SELECT mangadex_id, mal_id FROM manga_external_ids WHERE manga_id = 'keep'").Scan(&mangadexID, &malID)
+	if mangadexID != "md-1" || malID != 2 {
+		t.Errorf("external ids = %q, %d; want both sources linked", mangadexID, malID)
+	}
+
+	var audits int
+	db.QueryRow("SELECT COUNT(*) FROM audit_log WHERE action = 'manga.merge' AND target_id = 'keep'").Scan(&audits)
+	if audits != 1 {
+		t.Errorf("audit rows = %d, want 1", audits)
+	}
+
+	if _, err := NewMerger(db).Merge(ctx, "admin", "dup", "keep"); !errors.Is(err, ErrMangaNotFound) {
+		t.Errorf("merging a deleted source: %v, want ErrMangaNotFound", err)
+	}
+}
@@ -0,0 +1,138 @@
+// Package importer - Field Provenance
+// Ghi lại source nào đã cung cấp giá trị hiện tại của từng field (manga_field_provenance)
+// Chức năng:
+//   - Import và resync ghi source + external ID cho field mà chúng thực sự ghi
+//   - Admin sửa tay (gRPC UpdateManga) đánh dấu field là "manual"
+//   - Field "manual" không bị import hay resync ghi đè
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"mangahub/pkg/models"
+)
+
+// SourceManual marks a field last set by hand; imports and resyncs skip it
+const SourceManual = "manual"
+
+// FieldProvenance says where a manga field's current value came from
+type FieldProvenance struct {
+	Field      string    `json:"field"`
+	Source     string    `json:"source"` // models.SourceMangaDex, models.SourceJikan or SourceManual
+	ExternalID string    `json:"external_id,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// dbtx is the part of *sql.DB and *sql.Tx imports write through
+type dbtx interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// recordProvenance stores source as the origin of each field
+func recordProvenance(ctx context.Context, db dbtx, mangaID, source, externalID string, at time.Time, fields ...string) error {
+	for _, field := range fields {
+		if _, err := db.ExecContext(ctx, `
+			INSERT INTO manga_field_provenance (manga_id, field, source, external_id, updated_at)
+			VALUES (?, ?, ?, ?, ?)
+			ON CONFLICT(manga_id, field) DO UPDATE SET
+				source = excluded.source,
+				external_id = excluded.external_id,
+				updated_at = excluded.updated_at`,
+			mangaID, field, source, sqlNullString(true, externalID), at,
+		); err != nil {
+			return fmt.Errorf("record provenance of %s: %w", field, err)
+		}
+	}
+	return nil
+}
+
+// MarkManual records fields as edited by hand, so later imports and resyncs
+// keep their values
+func MarkManual(ctx context.Context, db *sql.DB, mangaID string, fields ...string) error {
+	return recordProvenance(ctx, db, mangaID, SourceManual, "", time.Now(), fields...)
+}
+
+// withoutManual blanks the fields marked manual so updateManga keeps them
+func withoutManual(m models.Manga, manual map[string]bool) models.Manga {
+	if manual["author"] {
+		m.Author = ""
+	}
+	if manual["description"] {
+		m.Description = ""
+	}
+	if manual["cover_url"] {
+		m.CoverURL = ""
+	}
+	if manual["status"] {
+		m.Status = ""
+	}
+	if manual["total_chapters"] {
+		m.TotalChapters = 0
+	}
+	if manual["year"] {
+		m.Year = 0
+	}
+	return m
+}
+
+// importedFields lists the tracked fields an import wrote: the title only on
+// insert, description and cover when the source had them and they are not manual
+func importedFields(m models.Manga, manual map[string]bool, inserted bool) []string {
+	var fields []string
+	if inserted {
+		fields = append(fields, "title")
+	}
+	if m.Description != "" && !manual["description"] {
+		fields = append(fields, "description")
+	}
+	if m.CoverURL != "" && !manual["cover_url"] {
+		fields = append(fields, "cover_url")
+	}
+	return fields
+}
+
+// manualFields returns the manga's fields marked manual
+func manualFields(ctx context.Context, db dbtx, mangaID string) (map[string]bool, error) {
+	rows, err := db.QueryContext(ctx,
+		"SELECT field FROM manga_field_provenance WHERE manga_id = ? AND source = ?", mangaID, SourceManual)
+	if err != nil {
+		return nil, fmt.Errorf("get manual fields: %w", err)
+	}
+	defer rows.Close()
+
+	manual := make(map[string]bool)
+	for rows.Next() {
+		var field string
+		if err := rows.Scan(&field); err != nil {
+			return nil, fmt.Errorf("scan manual field: %w", err)
+		}
+		manual[field] = true
+	}
+	return manual, rows.Err()
+}
+
+// Provenance lists where each tracked field of a manga came from, by field name
+func Provenance(ctx context.Context, db *sql.DB, mangaID string) ([]FieldProvenance, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT field, source, COALESCE(external_id, ''), updated_at
+		FROM manga_field_provenance WHERE manga_id = ? ORDER BY field`, mangaID)
+	if err != nil {
+		return nil, fmt.Errorf("get provenance: %w", err)
+	}
+	defer rows.Close()
+
+	fields := []FieldProvenance{}
+	for rows.Next() {
+		var p FieldProvenance
+		if err := rows.Scan(&p.Field, &p.Source, &p.ExternalID, &p.UpdatedAt); err != nil {
+			return nil, fmt.Errorf("scan provenance: %w", err)
+		}
+		fields = append(fields, p)
+	}
+	return fields, rows.Err()
+}
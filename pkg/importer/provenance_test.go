@@ -0,0 +1,81 @@
+// Package importer - Provenance Tests
+// Unit tests cho field provenance: import ghi source, field "manual" không bị ghi đè
+package importer
+
+import (
+	"context"
+	"testing"
+
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+func TestManualFieldsSurviveImportAndResync(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	jikan := models.ExternalMangaData{
+		Source: models.SourceJikan, ExternalID: "2", Title: "Berserk", Status: "publishing",
+		Description: "Guts, a former mercenary.", CoverURL: "https://example.com/mal.jpg",
+	}
+	manga, err := NewImporter(db, nil).ImportOne(ctx, jikan)
+	if err != nil {
+		t.Fatalf("ImportOne: %v", err)
+	}
+
+	sources := func() map[string]string {
+		t.Helper()
+		fields, err := Provenance(ctx, db, manga.ID)
+		if err != nil {
+			t.Fatalf("Provenance: %v", err)
+		}
+		bySource := make(map[string]string)
+		for _, f := range fields {
+			bySource[f.Field] = f.Source
+		}
+		return bySource
+	}
+	if got := sources(); got["title"] != models.SourceJikan || got["description"] != models.SourceJikan || got["cover_url"] != models.SourceJikan {
+		t.Fatalf("provenance after insert = %v, want jikan for title, description and cover", got)
+	}
+
+	// An admin rewrites the synopsis
+	if _, err := db.Exec(`UPDATE manga SET description = 'Curated synopsis' WHERE id = ?`, manga.ID); err != nil {
+		t.Fatalf("edit description: %v", err)
+	}
+	if err := MarkManual(ctx, db, manga.ID, "description"); err != nil {
+		t.Fatalf("MarkManual: %v", err)
+	}
+
+	// Re-importing replaces the cover but not the hand-edited synopsis
+	again := jikan
+	again.Description, again.CoverURL = "Source synopsis", "https://example.com/mal-2.jpg"
+	if _, err := NewImporter(db, nil).ImportOne(ctx, again); err != nil {
+		t.Fatalf("re-import: %v", err)
+	}
+
+	// So does a resync that changes the title
+	r := NewResyncer(db)
+	r.SetFetcher(models.SourceJikan, func(ctx context.Context, id string) (models.ExternalMangaData, error) {
+		return models.ExternalMangaData{Title: "Berserk (Deluxe)", Status: "publishing", Description: "Resynced synopsis"}, nil
+	})
+	result, err := r.Resync(ctx, manga.ID)
+	if err != nil {
+		t.Fatalf("Resync: %v", err)
+	}
+	if len(result.SkippedFields) != 1 || result.SkippedFields[0] != "description" {
+		t.Errorf("skipped fields = %v, want [description]", result.SkippedFields)
+	}
+
+	var title, description, cover string
+	db.QueryRow(`SELECT title, description, cover_url FROM manga WHERE id = ?`, manga.ID).Scan(&title, &description, &cover)
+	if description != "Curated synopsis" {
+		t.Errorf("description = %q, manual edit was overwritten", description)
+	}
+	if title != "Berserk (Deluxe)" || cover != "https://example.com/mal-2.jpg" {
+		t.Errorf("title = %q, cover = %q; non-manual fields should still update", title, cover)
+	}
+	if got := sources(); got["description"] != SourceManual || got["title"] != models.SourceJikan {
+		t.Errorf("provenance after resync = %v", got)
+	}
+}
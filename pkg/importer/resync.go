@@ -0,0 +1,300 @@
+// Package importer - Resync Imported Manga
+// Refetch manga đã import từ external sources để cập nhật data cũ
+// Chức năng:
+//   - Lookup external IDs qua manga_external_ids
+//   - Thử primary source trước, fallback sang source còn lại khi lỗi
+//   - Chỉ ghi đè field khi external value khác rỗng
+//   - Field admin sửa tay ("manual" trong manga_field_provenance) không bị ghi đè
+//   - Ghi provenance (source + external ID) cho field đã cập nhật
+//   - Cập nhật last_synced_at
+//   - Đổi status được ghi vào manga_status_history bởi trigger manga_status_change
+package importer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"mangahub/pkg/database"
+	"mangahub/pkg/external"
+	"mangahub/pkg/models"
+)
+
+var (
+	// ErrMangaNotFound is returned when the manga to resync does not exist
+	ErrMangaNotFound = errors.New("manga not found")
+	// ErrNoExternalIDs is returned when the manga was never linked to an external source
+	ErrNoExternalIDs = errors.New("manga has no external ids")
+	// ErrAllSourcesFailed is returned when every linked source failed to fetch
+	ErrAllSourcesFailed = errors.New("all external sources failed")
+)
+
+// Fetcher fetches one manga from an external source by its ID on that source
+type Fetcher func(ctx context.Context, externalID string) (models.ExternalMangaData, error)
+
+// MangaDexFetcher adapts a MangaDex client to a Fetcher
+func MangaDexFetcher(client *external.MangaDexClient) Fetcher {
+	return func(ctx context.Context, externalID string) (models.ExternalMangaData, error) {
+		m, err := client.GetManga(ctx, externalID)
+		if err != nil {
+			return models.ExternalMangaData{}, err
+		}
+		return m.ToExternalMangaData(), nil
+	}
+}
+
+// JikanFetcher adapts a Jikan client to a Fetcher (external ID is the MAL ID)
+func JikanFetcher(client *external.JikanClient) Fetcher {
+	return func(ctx context.Context, externalID string) (models.ExternalMangaData, error) {
+		malID, err := strconv.Atoi(externalID)
+		if err != nil {
+			return models.ExternalMangaData{}, fmt.Errorf("invalid MAL id %q", externalID)
+		}
+		m, err := client.GetManga(ctx, malID)
+		if err != nil {
+			return models.ExternalMangaData{}, err
+		}
+		return m.ToExternalMangaData(), nil
+	}
+}
+
+// ResyncResult describes what a resync changed
+type ResyncResult struct {
+	MangaID          string    `json:"manga_id"`
+	Title            string    `json:"title"`
+	Source           string    `json:"source"`
+	ExternalID       string    `json:"external_id"`
+	UpdatedFields    []string  `json:"updated_fields"`
+	SkippedFields    []string  `json:"skipped_fields,omitempty"` // edited by hand, left as they are
+	PreviousChapters int       `json:"previous_chapters"`
+	TotalChapters    int       `json:"total_chapters"`
+	PreviousStatus   string    `json:"previous_status"`
+	Status           string    `json:"status"`
+	Failures         []string  `json:"failures,omitempty"` // sources tried before Source
+	LastSyncedAt     time.Time `json:"last_synced_at"`
+}
+
+// NewChapters reports whether the resync raised the chapter count
+func (r *ResyncResult) NewChapters() bool {
+	return r.TotalChapters > r.PreviousChapters
+}
+
+// StatusChanged reports whether the resync changed the manga's status
+func (r *ResyncResult) StatusChanged() bool {
+	return r.Status != r.PreviousStatus
+}
+
+// Resyncer refreshes imported manga from their external sources
+type Resyncer struct {
+	db       *sql.DB
+	fetchers map[string]Fetcher
+	now      func() time.Time
+}
+
+// NewResyncer creates a resyncer; register sources with SetFetcher
+func NewResyncer(db *sql.DB) *Resyncer {
+	return &Resyncer{
+		db:       db,
+		fetchers: make(map[string]Fetcher),
+		now:      time.Now,
+	}
+}
+
+// SetFetcher registers the fetcher for a source (models.SourceMangaDex, models.SourceJikan)
+func (r *Resyncer) SetFetcher(source string, f Fetcher) {
+	r.fetchers[source] = f
+}
+
+// sourceRef is one external ID a manga can be refetched by
+type sourceRef struct {
+	source     string
+	externalID string
+}
+
+// Resync refetches a manga and updates title, status, total_chapters and description.
+// The primary source is tried first; on failure the other linked sources are tried.
+// Empty external values never overwrite what is stored, and the chapter count only grows.
+func (r *Resyncer) Resync(ctx context.Context, mangaID string) (*ResyncResult, error) {
+	var current models.Manga
+	var description sql.NullString
+	err := r.db.QueryRowContext(ctx,
+		"SELECT id, title, COALESCE(status, ''), total_chapters, description FROM manga WHERE id = ?",
+		mangaID,
+	).Scan(&current.ID, &current.Title, &current.Status, &current.TotalChapters, &description)
+	if err == sql.ErrNoRows {
+		return nil, ErrMangaNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get manga: %w", err)
+	}
+	current.Description = description.String
+
+	refs, err := r.sourceRefs(ctx, mangaID)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &ResyncResult{
+		MangaID:          mangaID,
+		PreviousChapters: current.TotalChapters,
+		PreviousStatus:   current.Status,
+	}
+	var ext models.ExternalMangaData
+	fetched := false
+	for _, ref := range refs {
+		fetch, ok := r.fetchers[ref.source]
+		if !ok {
+			continue
+		}
+		data, err := fetch(ctx, ref.externalID)
+		if err != nil {
+			result.Failures = append(result.Failures, fmt.Sprintf("%s %s: %v", ref.source, ref.externalID, err))
+			continue
+		}
+		ext, fetched = data, true
+		result.Source, result.ExternalID = ref.source, ref.externalID
+		break
+	}
+	if !fetched {
+		if len(result.Failures) == 0 {
+			return nil, fmt.Errorf("%w: no fetcher registered for linked sources", ErrAllSourcesFailed)
+		}
+		return nil, fmt.Errorf("%w: %v", ErrAllSourcesFailed, result.Failures)
+	}
+
+	manual, err := manualFields(ctx, r.db, mangaID)
+	if err != nil {
+		return nil, err
+	}
+	updated := mergeResync(current, ext)
+	updated, result.SkippedFields = keepManual(current, updated, manual)
+	result.UpdatedFields = changedFields(current, updated)
+	result.TotalChapters = updated.TotalChapters
+	result.Title = updated.Title
+	result.Status = updated.Status
+	result.LastSyncedAt = r.now()
+
+	err = database.WithTx(ctx, r.db, func(tx *sql.Tx) error {
+		if len(result.UpdatedFields) > 0 {
+			if _, err := tx.ExecContext(ctx, `
+				UPDATE manga SET title = ?, status = ?, total_chapters = ?, description = ?, updated_at = ?
+				WHERE id = ?`,
+				updated.Title, updated.Status, updated.TotalChapters, updated.Description, result.LastSyncedAt, mangaID,
+			); err != nil {
+				return fmt.Errorf("update manga: %w", err)
+			}
+		}
+		if err := recordProvenance(ctx, tx, mangaID, result.Source, result.ExternalID,
+			result.LastSyncedAt, result.UpdatedFields...); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx,
+			"UPDATE manga_external_ids SET last_synced_at = ?, updated_at = ? WHERE manga_id = ?",
+			result.LastSyncedAt, result.LastSyncedAt, mangaID,
+		); err != nil {
+			return fmt.Errorf("update last_synced_at: %w", err)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("resync: %w", err)
+	}
+
+	return result, nil
+}
+
+// sourceRefs lists the manga's external IDs, primary source first
+func (r *Resyncer) sourceRefs(ctx context.Context, mangaID string) ([]sourceRef, error) {
+	var mangadexID, primary sql.NullString
+	var malID sql.NullInt64
+	err := r.db.QueryRowContext(ctx,
+		"SELECT mangadex_id, mal_id, primary_source FROM manga_external_ids WHERE manga_id = ?",
+		mangaID,
+	).Scan(&mangadexID, &malID, &primary)
+	if err == sql.ErrNoRows {
+		return nil, ErrNoExternalIDs
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get external ids: %w", err)
+	}
+
+	var refs []sourceRef
+	if mangadexID.String != "" {
+		refs = append(refs, sourceRef{models.SourceMangaDex, mangadexID.String})
+	}
+	if malID.Int64 > 0 {
+		ref := sourceRef{models.SourceJikan, strconv.FormatInt(malID.Int64, 10)}
+		if primary.String == models.SourceJikan {
+			refs = append([]sourceRef{ref}, refs...)
+		} else {
+			refs = append(refs, ref)
+		}
+	}
+	if len(refs) == 0 {
+		return nil, ErrNoExternalIDs
+	}
+	return refs, nil
+}
+
+// mergeResync applies non-empty external values on top of the stored manga
+func mergeResync(current models.Manga, ext models.ExternalMangaData) models.Manga {
+	merged := current
+	if ext.Title != "" {
+		merged.Title = ext.Title
+	}
+	if ext.Description != "" {
+		merged.Description = truncateDescription(ext.Description, 2000)
+	}
+	switch status := normalizeStatus(ext.Status); status {
+	case "ongoing", "completed", "hiatus", "cancelled":
+		merged.Status = status
+	}
+	if ext.ChapterCount > merged.TotalChapters {
+		merged.TotalChapters = ext.ChapterCount
+	}
+	return merged
+}
+
+// keepManual restores the manual fields a resync would have changed and
+// lists them
+func keepManual(current, updated models.Manga, manual map[string]bool) (models.Manga, []string) {
+	var skipped []string
+	for _, field := range changedFields(current, updated) {
+		if !manual[field] {
+			continue
+		}
+		switch field {
+		case "title":
+			updated.Title = current.Title
+		case "status":
+			updated.Status = current.Status
+		case "total_chapters":
+			updated.TotalChapters = current.TotalChapters
+		case "description":
+			updated.Description = current.Description
+		}
+		skipped = append(skipped, field)
+	}
+	return updated, skipped
+}
+
+// changedFields lists the resynced fields that differ between two versions
+func changedFields(before, after models.Manga) []string {
+	fields := []string{}
+	if before.Title != after.Title {
+		fields = append(fields, "title")
+	}
+	if before.Status != after.Status {
+		fields = append(fields, "status")
+	}
+	if before.TotalChapters != after.TotalChapters {
+		fields = append(fields, "total_chapters")
+	}
+	if before.Description != after.Description {
+		fields = append(fields, "description")
+	}
+	return fields
+}
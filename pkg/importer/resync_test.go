@@ -0,0 +1,75 @@
+// Package importer - Resync Tests
+// Unit tests cho fallback source và không ghi đè field bằng giá trị rỗng
+package importer
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"mangahub/internal/testutil"
+	"mangahub/pkg/models"
+)
+
+func TestResyncFallsBackAndKeepsCuratedFields(t *testing.T) {
+	db := testutil.OpenDB(t)
+	ctx := context.Background()
+
+	if _, err := db.Exec(`INSERT INTO manga (id, title, description, status, total_chapters)
+		VALUES ('m1', 'Berserk', 'Curated synopsis', 'ongoing', 360)`); err != nil {
+		t.Fatalf("seed manga: %v", err)
+	}
+	if _, err := db.Exec(`INSERT INTO manga_external_ids (manga_id, mangadex_id, mal_id, primary_source)
+		VALUES ('m1', 'md-1', 2, 'mangadex')`); err != nil {
+		t.Fatalf("seed external ids: %v", err)
+	}
+
+	r := NewResyncer(db)
+	r.SetFetcher(models.SourceMangaDex, func(ctx context.Context, id string) (models.ExternalMangaData, error) {
+		return models.ExternalMangaData{}, errors.New("503 service unavailable")
+	})
+	r.SetFetcher(models.SourceJikan, func(ctx context.Context, id string) (models.ExternalMangaData, error) {
+		if id != "2" {
+			t.Errorf("jikan fetched %q, want MAL id 2", id)
+		}
+		return models.ExternalMangaData{Source: models.SourceJikan, ExternalID: id, Title: "Berserk", Status: "Publishing", ChapterCount: 374}, nil
+	})
+
+	result, err := r.Resync(ctx, "m1")
+	if err != nil {
+		t.Fatalf("Resync: %v", err)
+	}
+	if result.Source != models.SourceJikan || len(result.Failures) != 1 {
+		t.Errorf("source = %s, failures = %v; want jikan after one failure", result.Source, result.Failures)
+	}
+	if !result.NewChapters() || result.TotalChapters != 374 {
+		t.Errorf("chapters %d -> %d, want 360 -> 374", result.PreviousChapters, result.TotalChapters)
+	}
+
+	var description string
+	var chapters int
+	var synced *string
+	db.QueryRow("SELECT description, total_chapters FROM manga WHERE id = 'm1'").Scan(&description, &chapters)
+	db.QueryRow("SELECT last_synced_at FROM manga_external_ids WHERE manga_id = 'm1'").Scan(&synced)
+	if description != "Curated synopsis" {
+		t.Errorf("description = %q, empty external value must not overwrite it", description)
+	}
+	if chapters != 374 {
+		t.Errorf("total_chapters = %d, want 374", chapters)
+	}
+	if synced == nil {
+		t.Error("last_synced_at was not set")
+	}
+}
+
+func TestResyncWithoutExternalIDs(t *testing.T) {
+	db := testutil.OpenDB(t)
+	if _, err := db.Exec(`INSERT INTO manga (id, title) VALUES ('m1', 'Local Only')`); err != nil {
+		t.Fatalf("seed manga: %v", err)
+	}
+
+	_, err := NewResyncer(db).Resync(context.Background(), "m1")
+	if !errors.Is(err, ErrNoExternalIDs) {
+		t.Fatalf("err = %v, want ErrNoExternalIDs", err)
+	}
+}
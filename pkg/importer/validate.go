@@ -0,0 +1,196 @@
+// Package importer - Import Validation
+// Kiểm tra chất lượng dữ liệu trước khi import, không ghi database
+// Chức năng:
+//   - Chạy cùng mapping logic (ConvertToManga) như ImportOne
+//   - Error (blocking): thiếu title, năm vô lý, số chapter/rating sai
+//   - Warning: completed mà 0 chapter, thiếu cover/tác giả/mô tả, trùng trong batch
+package importer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"mangahub/pkg/models"
+)
+
+// Severity says whether a validation issue should stop a record from being imported
+type Severity string
+
+// Validation severities
+const (
+	SeverityWarning Severity = "warning" // importable, but worth a look
+	SeverityError   Severity = "error"   // blocking: the record would import as garbage
+)
+
+// Year bounds for a plausible publication year; 0 means unknown
+const (
+	minPlausibleYear = 1900
+	maxYearsAhead    = 1 // announced series may list next year
+)
+
+// ValidationIssue is one problem found in one item of a batch
+type ValidationIssue struct {
+	Index      int      `json:"index"` // position in the validated batch
+	Title      string   `json:"title"`
+	Source     string   `json:"source"`
+	ExternalID string   `json:"external_id"`
+	Field      string   `json:"field"`
+	Severity   Severity `json:"severity"`
+	Message    string   `json:"message"`
+}
+
+// Blocking reports whether the issue should keep the record out of the catalog
+func (v ValidationIssue) Blocking() bool {
+	return v.Severity == SeverityError
+}
+
+// ValidationSummary counts the issues of a validated batch
+type ValidationSummary struct {
+	Items    int `json:"items"`
+	Valid    int `json:"valid"`    // items without any issue
+	Warned   int `json:"warned"`   // items with warnings only
+	Blocked  int `json:"blocked"`  // items with at least one error
+	Warnings int `json:"warnings"` // warning issues
+	Errors   int `json:"errors"`   // error issues
+}
+
+// Validate runs the import mapping over items without writing anything and
+// reports per-item problems, in item order. Duplicates are checked within the
+// batch only; matching against the catalog is left to a --dry-run import.
+// On cancellation it returns the issues found so far.
+func (i *Importer) Validate(ctx context.Context, items []models.ExternalMangaData) []ValidationIssue {
+	var issues []ValidationIssue
+	seenIDs := make(map[string]int)
+	seenTitles := make(map[string]int)
+
+	for n, ext := range items {
+		if ctx.Err() != nil {
+			break
+		}
+		issue := func(field string, severity Severity, format string, args ...interface{}) {
+			issues = append(issues, ValidationIssue{
+				Index:      n,
+				Title:      ext.Title,
+				Source:     ext.Source,
+				ExternalID: ext.ExternalID,
+				Field:      field,
+				Severity:   severity,
+				Message:    fmt.Sprintf(format, args...),
+			})
+		}
+
+		manga := ConvertToManga(ext)
+
+		// Blocking problems
+		title := strings.TrimSpace(manga.Title)
+		if title == "" {
+			issue("title", SeverityError, "missing title")
+		}
+		maxYear := time.Now().Year() + maxYearsAhead
+		if manga.Year != 0 && (manga.Year < minPlausibleYear || manga.Year > maxYear) {
+			issue("year", SeverityError, "year %d is outside %d-%d", manga.Year, minPlausibleYear, maxYear)
+		}
+		if manga.TotalChapters < 0 {
+			issue("chapters", SeverityError, "negative chapter count %d", manga.TotalChapters)
+		}
+		if ext.Rating < 0 || ext.Rating > 10 {
+			issue("rating", SeverityError, "rating %.2f is outside 0-10", ext.Rating)
+		}
+
+		// Importable, but probably wrong or incomplete
+		if manga.Status == "completed" && manga.TotalChapters == 0 {
+			issue("chapters", SeverityWarning, "completed series with zero chapters")
+		}
+		switch manga.Status {
+		case "ongoing", "completed", "hiatus", "cancelled":
+		case "unknown":
+			issue("status", SeverityWarning, "missing status")
+		default:
+			issue("status", SeverityWarning, "unrecognized status %q", manga.Status)
+		}
+		if manga.Year == 0 {
+			issue("year", SeverityWarning, "missing year")
+		}
+		if ext.ExternalID == "" {
+			issue("external_id", SeverityWarning, "missing external ID; re-imports can only match by title")
+		}
+		if manga.Author == "" {
+			issue("author", SeverityWarning, "missing author")
+		}
+		if ext.CoverURL == "" {
+			issue("cover_url", SeverityWarning, "missing cover")
+		}
+		if strings.TrimSpace(ext.Description) == "" {
+			issue("description", SeverityWarning, "missing description")
+		} else if manga.Description != ext.Description {
+			issue("description", SeverityWarning, "description will be truncated from %d characters", len(ext.Description))
+		}
+
+		// Later copies in the same batch would update the first one
+		if ext.ExternalID != "" {
+			key := ext.Source + ":" + ext.ExternalID
+			if first, ok := seenIDs[key]; ok {
+				issue("external_id", SeverityWarning, "same %s ID as item %d", ext.Source, first+1)
+			} else {
+				seenIDs[key] = n
+			}
+		}
+		if title != "" {
+			key := strings.ToLower(title)
+			if first, ok := seenTitles[key]; ok {
+				issue("title", SeverityWarning, "same title as item %d", first+1)
+			} else {
+				seenTitles[key] = n
+			}
+		}
+	}
+	return issues
+}
+
+// SummarizeIssues counts issues per severity and per item for a batch of size items
+func SummarizeIssues(items int, issues []ValidationIssue) ValidationSummary {
+	summary := ValidationSummary{Items: items}
+	for n, itemIssues := range IssuesByItem(issues) {
+		if n >= items {
+			continue
+		}
+		switch ItemSeverity(itemIssues) {
+		case SeverityError:
+			summary.Blocked++
+		case SeverityWarning:
+			summary.Warned++
+		}
+	}
+	for _, issue := range issues {
+		if issue.Blocking() {
+			summary.Errors++
+		} else {
+			summary.Warnings++
+		}
+	}
+	summary.Valid = items - summary.Blocked - summary.Warned
+	return summary
+}
+
+// IssuesByItem groups issues by the index of the item they belong to
+func IssuesByItem(issues []ValidationIssue) map[int][]ValidationIssue {
+	byItem := make(map[int][]ValidationIssue)
+	for _, issue := range issues {
+		byItem[issue.Index] = append(byItem[issue.Index], issue)
+	}
+	return byItem
+}
+
+// ItemSeverity returns the worst severity among one item's issues, "" if it has none
+func ItemSeverity(issues []ValidationIssue) Severity {
+	var worst Severity
+	for _, issue := range issues {
+		if issue.Blocking() {
+			return SeverityError
+		}
+		worst = SeverityWarning
+	}
+	return worst
+}
@@ -0,0 +1,41 @@
+// Package importer - Validation Tests
+// Unit tests cho Validate: phân biệt error (blocking) và warning
+package importer
+
+import (
+	"context"
+	"testing"
+
+	"mangahub/pkg/models"
+)
+
+func TestValidateSeverity(t *testing.T) {
+	good := models.ExternalMangaData{
+		Source: models.SourceJikan, ExternalID: "2", Title: "Berserk", Status: "publishing",
+		Year: 1989, ChapterCount: 380, Authors: []string{"Miura, Kentarou"},
+		CoverURL: "https://example.com/berserk.jpg", Description: "Guts.", Rating: 9.4,
+	}
+	noTitle := good
+	noTitle.ExternalID, noTitle.Title = "3", " "
+	badYear := good
+	badYear.ExternalID, badYear.Title, badYear.Year = "4", "Future Manga", 3020
+	emptyCompleted := good
+	emptyCompleted.ExternalID, emptyCompleted.Title, emptyCompleted.Status, emptyCompleted.ChapterCount = "5", "Done", "finished", 0
+	duplicate := good // same ID and title as the first item
+
+	items := []models.ExternalMangaData{good, noTitle, badYear, emptyCompleted, duplicate}
+	issues := NewImporter(nil, nil).Validate(context.Background(), items)
+	byItem := IssuesByItem(issues)
+
+	want := []Severity{"", SeverityError, SeverityError, SeverityWarning, SeverityWarning}
+	for n, severity := range want {
+		if got := ItemSeverity(byItem[n]); got != severity {
+			t.Errorf("item %d: severity %q, want %q (%+v)", n, got, severity, byItem[n])
+		}
+	}
+
+	summary := SummarizeIssues(len(items), issues)
+	if summary.Valid != 1 || summary.Blocked != 2 || summary.Warned != 2 {
+		t.Errorf("summary = %+v", summary)
+	}
+}
@@ -27,21 +27,7 @@ type Config struct {
 func Init(config Config) {
 	log = logrus.New()
 
-	// Set log level
-	level, err := logrus.ParseLevel(config.Level)
-	if err != nil {
-		level = logrus.InfoLevel
-	}
-	log.SetLevel(level)
-
-	// Set formatter
-	if config.Format == "json" {
-		log.SetFormatter(&logrus.JSONFormatter{})
-	} else {
-		log.SetFormatter(&logrus.TextFormatter{
-			FullTimestamp: true,
-		})
-	}
+	applyLevelAndFormat(log, config.Level, config.Format)
 
 	// Set output
 	if config.Output == "stdout" || config.Output == "" {
@@ -55,6 +41,29 @@ func Init(config Config) {
 	}
 }
 
+// Reconfigure changes the level and format of the running logger, e.g. on a
+// config reload. The output stays as Init opened it.
+func Reconfigure(level, format string) {
+	applyLevelAndFormat(Get(), level, format)
+}
+
+// applyLevelAndFormat sets the level (info when unknown) and the formatter
+func applyLevelAndFormat(l *logrus.Logger, level, format string) {
+	lvl, err := logrus.ParseLevel(level)
+	if err != nil {
+		lvl = logrus.InfoLevel
+	}
+	l.SetLevel(lvl)
+
+	if format == "json" {
+		l.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		l.SetFormatter(&logrus.TextFormatter{
+			FullTimestamp: true,
+		})
+	}
+}
+
 // Get returns the logger instance
 func Get() *logrus.Logger {
 	if log == nil {
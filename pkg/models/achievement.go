@@ -0,0 +1,52 @@
+// Package models - Achievement Models
+// Badge cho các mốc đọc (chapter đầu tiên, streak 100 ngày, 10 bộ hoàn thành...)
+// Chức năng:
+//   - Badges: danh sách badge, mỗi badge là một metric và ngưỡng
+//   - Thêm badge mới chỉ cần thêm vào danh sách, không đổi schema
+package models
+
+import "time"
+
+// Achievement metrics: the numbers badges are earned on
+const (
+	MetricChaptersRead    = "chapters_read"    // chapters in the reading history
+	MetricLongestStreak   = "longest_streak"   // longest run of reading days
+	MetricCompletedSeries = "completed_series" // library entries marked completed
+	MetricReviews         = "reviews"          // ratings with written review text
+	MetricGenresRead      = "genres_read"      // distinct genres among manga read
+)
+
+// Badge is earned once a metric reaches Threshold. Earned badges are stored
+// by ID, so an ID must never change or be reused for another milestone.
+type Badge struct {
+	ID          string `json:"id"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Icon        string `json:"icon"`
+	Metric      string `json:"metric"`
+	Threshold   int    `json:"threshold"`
+}
+
+// Badges lists every badge in display order
+var Badges = []Badge{
+	{ID: "first_chapter", Name: "First Steps", Description: "Read your first chapter", Icon: "📖", Metric: MetricChaptersRead, Threshold: 1},
+	{ID: "chapters_100", Name: "Bookworm", Description: "Read 100 chapters", Icon: "📚", Metric: MetricChaptersRead, Threshold: 100},
+	{ID: "chapters_1000", Name: "Library Dweller", Description: "Read 1000 chapters", Icon: "🏛", Metric: MetricChaptersRead, Threshold: 1000},
+	{ID: "streak_7", Name: "Week Streak", Description: "Read 7 days in a row", Icon: "🔥", Metric: MetricLongestStreak, Threshold: 7},
+	{ID: "streak_30", Name: "Month Streak", Description: "Read 30 days in a row", Icon: "🌙", Metric: MetricLongestStreak, Threshold: 30},
+	{ID: "streak_100", Name: "Unstoppable", Description: "Read 100 days in a row", Icon: "⚡", Metric: MetricLongestStreak, Threshold: 100},
+	{ID: "first_completed", Name: "Finisher", Description: "Complete a series", Icon: "✅", Metric: MetricCompletedSeries, Threshold: 1},
+	{ID: "completed_10", Name: "Completionist", Description: "Complete 10 series", Icon: "🏆", Metric: MetricCompletedSeries, Threshold: 10},
+	{ID: "first_review", Name: "Critic", Description: "Write your first review", Icon: "✍", Metric: MetricReviews, Threshold: 1},
+	{ID: "reviews_10", Name: "Reviewer", Description: "Write 10 reviews", Icon: "📝", Metric: MetricReviews, Threshold: 10},
+	{ID: "genres_5", Name: "Explorer", Description: "Read manga in 5 genres", Icon: "🧭", Metric: MetricGenresRead, Threshold: 5},
+	{ID: "genres_10", Name: "Omnivore", Description: "Read manga in 10 genres", Icon: "🌈", Metric: MetricGenresRead, Threshold: 10},
+}
+
+// Achievement is a badge with the user's standing on it
+type Achievement struct {
+	Badge
+	Earned   bool       `json:"earned"`
+	EarnedAt *time.Time `json:"earned_at,omitempty"`
+	Progress int        `json:"progress"` // current value of the metric, capped at Threshold
+}
@@ -1,13 +1,16 @@
 // Package models - Comment System for Chapter Discussions
 // Hệ thống bình luận cho manga chapters
 // Chức năng:
-//   - Comments on manga/chapters with spoiler support
+//   - Comments on manga/chapters with spoiler support (masked server-side)
+//   - Lọc theo chapter hoặc general (không gắn chapter)
 //   - Threaded replies via parent_id
 //   - Like/unlike comments
 //   - Edit and soft-delete support
 package models
 
 import (
+	"fmt"
+	"strconv"
 	"time"
 )
 
@@ -19,6 +22,7 @@ type Comment struct {
 	UserID        string    `json:"user_id" db:"user_id"`
 	Content       string    `json:"content" db:"content"`
 	IsSpoiler     bool      `json:"is_spoiler" db:"is_spoiler"`
+	SpoilerMasked bool      `json:"spoiler_masked,omitempty"`           // spoiler content withheld from this viewer
 	ParentID      *string   `json:"parent_id,omitempty" db:"parent_id"` // For threaded replies
 	LikesCount    int       `json:"likes_count" db:"likes_count"`
 	IsEdited      bool      `json:"is_edited" db:"is_edited"`
@@ -44,10 +48,31 @@ type CommentWithUser struct {
 	LikedByMe   bool   `json:"liked_by_me"` // Whether current user liked this comment
 }
 
-// CommentWithReplies includes nested replies
+// CommentWithReplies includes nested replies.
+// Threaded responses nest replies recursively up to the requested depth.
 type CommentWithReplies struct {
 	CommentWithUser
-	Replies []CommentWithUser `json:"replies,omitempty"`
+	Replies []CommentWithReplies `json:"replies,omitempty"`
+}
+
+// DeletedCommentText replaces the content and author of deleted comments
+const DeletedCommentText = "[deleted]"
+
+// SpoilersShow is the ?spoilers= value that asks comment and review listings
+// for spoiler content the viewer's preferences would otherwise withhold
+const SpoilersShow = "show"
+
+// MaskCommentSpoilers blanks spoiler comments, replies included, that viewerID
+// did not write, so their text never reaches a client that won't show it
+func MaskCommentSpoilers(comments []CommentWithReplies, viewerID string) {
+	for i := range comments {
+		c := &comments[i].Comment
+		if c.IsSpoiler && !c.IsDeleted && c.UserID != viewerID {
+			c.Content = ""
+			c.SpoilerMasked = true
+		}
+		MaskCommentSpoilers(comments[i].Replies, viewerID)
+	}
 }
 
 // ===== Request/Response Types for Comment API =====
@@ -60,19 +85,47 @@ type CreateCommentRequest struct {
 	ParentID      string `json:"parent_id,omitempty"` // For replies
 }
 
+// CommentChapterGeneral is the ?chapter= value that selects comments not tied to a chapter
+const CommentChapterGeneral = "general"
+
+// CommentFilter scopes a comment listing by chapter
+// The zero value lists every comment of the manga
+type CommentFilter struct {
+	Chapter *int // only comments on this chapter
+	General bool // only comments not tied to a chapter
+}
+
+// ParseCommentFilter parses the ?chapter= query value: "", "general" or a chapter number
+func ParseCommentFilter(value string) (CommentFilter, error) {
+	switch value {
+	case "":
+		return CommentFilter{}, nil
+	case CommentChapterGeneral:
+		return CommentFilter{General: true}, nil
+	}
+	ch, err := strconv.Atoi(value)
+	if err != nil || ch < 0 {
+		return CommentFilter{}, fmt.Errorf("chapter must be a chapter number or %q", CommentChapterGeneral)
+	}
+	return CommentFilter{Chapter: &ch}, nil
+}
+
 // UpdateCommentRequest is the payload for editing a comment
 type UpdateCommentRequest struct {
 	Content   string `json:"content" validate:"required,min=1,max=2000"`
 	IsSpoiler bool   `json:"is_spoiler"`
 }
 
-// CommentListResponse is paginated list of comments
+// CommentListResponse is paginated list of comments.
+// Pages hold top-level comments, newest first; pass NextCursor as ?cursor= for the
+// next page so comments posted meanwhile don't shift it the way page offsets do.
 type CommentListResponse struct {
 	Comments   []CommentWithReplies `json:"comments"`
 	TotalCount int                  `json:"total_count"`
 	Page       int                  `json:"page"`
 	PageSize   int                  `json:"page_size"`
 	HasMore    bool                 `json:"has_more"`
+	NextCursor string               `json:"next_cursor,omitempty"`
 }
 
 // Activity represents a user action for the activity feed
@@ -81,7 +134,7 @@ type Activity struct {
 	ID            string    `json:"id" db:"id"`
 	UserID        string    `json:"user_id" db:"user_id"`
 	Username      string    `json:"username" db:"username"`
-	ActivityType  string    `json:"activity_type" db:"activity_type"` // comment, rating, progress, list_add
+	ActivityType  string    `json:"activity_type" db:"activity_type"` // comment, rating, progress, list_add, status_change
 	MangaID       string    `json:"manga_id" db:"manga_id"`
 	MangaTitle    string    `json:"manga_title" db:"manga_title"`
 	ChapterNumber *int      `json:"chapter_number,omitempty" db:"chapter_number"`
@@ -101,4 +154,34 @@ const (
 	ActivityRating   = "rating"   // User rated a manga
 	ActivityProgress = "progress" // User updated reading progress
 	ActivityListAdd  = "list_add" // User added manga to custom list
+	// ActivityStatusChange is written by the manga_status_change trigger; it has
+	// no user and comment_text holds "old → new"
+	ActivityStatusChange = "status_change"
 )
+
+// IsActivityType reports whether t is a known activity type
+func IsActivityType(t string) bool {
+	switch t {
+	case ActivityComment, ActivityRating, ActivityProgress, ActivityListAdd, ActivityStatusChange:
+		return true
+	}
+	return false
+}
+
+// ActivityQuery filters and pages the activity feed
+type ActivityQuery struct {
+	Type   string // one of the activity types, "" for all
+	Before string // next_before cursor from the previous page
+	Limit  int
+	Offset int
+}
+
+// ActivityPage is one page of the activity feed, newest first
+type ActivityPage struct {
+	Activities []Activity `json:"activities"`
+	Total      int        `json:"total"` // matching the type filter, ignoring the cursor
+	Limit      int        `json:"limit"`
+	Offset     int        `json:"offset"`
+	HasMore    bool       `json:"has_more"`
+	NextBefore string     `json:"next_before,omitempty"` // pass as ?before= for the next page
+}
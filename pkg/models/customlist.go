@@ -1,3 +1,9 @@
+// Package models - Custom Lists
+// Danh sách manga do user tự tạo (Favorites, Top 10, ...)
+// Chức năng:
+//   - List riêng tư hoặc public (ai cũng xem được qua GET /lists/:id)
+//   - Mỗi manga chỉ xuất hiện một lần trong một list
+//   - Item có thứ tự (sort_order) và ghi chú
 package models
 
 import (
@@ -10,8 +16,11 @@ type CustomList struct {
 	UserID      string    `json:"user_id" db:"user_id"`
 	Name        string    `json:"name" db:"name"`
 	Description string    `json:"description" db:"description"`
+	Icon        string    `json:"icon,omitempty" db:"icon"`
 	IsPublic    bool      `json:"is_public" db:"is_public"`
+	IsDefault   bool      `json:"is_default" db:"is_default"`
 	SortOrder   int       `json:"sort_order" db:"sort_order"`
+	ItemCount   int       `json:"item_count" db:"item_count"` // kept current by triggers
 	CreatedAt   time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
 }
@@ -42,6 +51,7 @@ type CustomListWithItems struct {
 type CreateListRequest struct {
 	Name        string `json:"name" validate:"required,min=1,max=100"`
 	Description string `json:"description,omitempty" validate:"max=500"`
+	Icon        string `json:"icon,omitempty" validate:"max=16"`
 	IsPublic    bool   `json:"is_public"`
 }
 
@@ -49,19 +59,19 @@ type CreateListRequest struct {
 type UpdateListRequest struct {
 	Name        *string `json:"name,omitempty" validate:"omitempty,min=1,max=100"`
 	Description *string `json:"description,omitempty" validate:"omitempty,max=500"`
+	Icon        *string `json:"icon,omitempty" validate:"omitempty,max=16"`
 	IsPublic    *bool   `json:"is_public,omitempty"`
 }
 
 // AddToListRequest is used to add manga to a custom list
 type AddToListRequest struct {
-	MangaID   string `json:"manga_id" validate:"required"`
-	Notes     string `json:"notes,omitempty"`
-	SortOrder int    `json:"sort_order,omitempty"`
+	MangaID string `json:"manga_id" validate:"required"`
+	Notes   string `json:"notes,omitempty" validate:"max=500"`
 }
 
 // ReorderListRequest is used to reorder items in a list
 type ReorderListRequest struct {
-	ItemIDs []string `json:"item_ids" validate:"required"`
+	ItemIDs []string `json:"item_ids" validate:"required,min=1"`
 }
 
 // CustomListsResponse is a list of user's custom lists
@@ -23,6 +23,7 @@ const (
 	ErrCodeInternal           = "INTERNAL_ERROR"
 	ErrCodeBadRequest         = "BAD_REQUEST"
 	ErrCodeServiceUnavailable = "SERVICE_UNAVAILABLE"
+	ErrCodeEmailNotVerified   = "EMAIL_NOT_VERIFIED"
 )
 
 // Common errors
@@ -30,13 +31,21 @@ var (
 	ErrUserNotFound       = errors.New("user not found")
 	ErrMangaNotFound      = errors.New("manga not found")
 	ErrProgressNotFound   = errors.New("reading progress not found")
+	ErrProgressConflict   = errors.New("reading progress changed since last read")
 	ErrInvalidCredentials = errors.New("invalid username or password")
 	ErrUsernameExists     = errors.New("username already exists")
 	ErrEmailExists        = errors.New("email already exists")
 	ErrInvalidToken       = errors.New("invalid or expired token")
+	ErrTokenReused        = errors.New("refresh token reuse detected")
+	ErrEmailNotVerified   = errors.New("email address not verified")
 	ErrUnauthorized       = errors.New("unauthorized access")
 	ErrForbidden          = errors.New("forbidden access")
 	ErrInvalidInput       = errors.New("invalid input")
+	ErrListNotFound       = errors.New("list not found")
+	ErrListItemNotFound   = errors.New("manga not in list")
+	ErrAlreadyInList      = errors.New("manga already in list")
+	ErrSearchNotFound     = errors.New("saved search not found")
+	ErrSearchNameExists   = errors.New("saved search name already exists")
 )
 
 // AppError is a custom application error
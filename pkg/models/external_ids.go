@@ -51,8 +51,18 @@ type ExternalMangaData struct {
 	LastChapter  int                    `json:"last_chapter"`
 	Year         int                    `json:"year"`
 	Authors      []string               `json:"authors"`
-	RawData      map[string]interface{} `json:"raw_data,omitempty"` // Original API response
+	Languages    []string               `json:"languages,omitempty"` // translated languages with chapters (MangaDex)
+	RawData      map[string]interface{} `json:"raw_data,omitempty"`  // Original API response
 	FetchedAt    time.Time              `json:"fetched_at"`
+	Chapters     []ExternalChapter      `json:"chapters,omitempty"` // nil when the source was not asked for chapters
+}
+
+// ExternalChapter is chapter metadata from an external source
+type ExternalChapter struct {
+	Number     int        `json:"number"`
+	Title      string     `json:"title"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+	ExternalID string     `json:"external_id"`
 }
 
 // External source constants
@@ -38,6 +38,34 @@ type GenreWithCount struct {
 	MangaCount int `json:"manga_count"`
 }
 
+// Genre list orders accepted by GET /genres?sort=
+const (
+	GenreSortName  = "name"  // alphabetical (default)
+	GenreSortCount = "count" // most manga first
+)
+
+// DefaultGenreLimit is the page size of GET /genres when none is given; it
+// covers the whole seeded taxonomy in one page
+const DefaultGenreLimit = 50
+
+// GenreListRequest filters and pages GET /genres
+type GenreListRequest struct {
+	Query     string `form:"q" validate:"max=50"` // case-insensitive substring of the name
+	HideEmpty bool   `form:"hide_empty"`          // skip genres no manga is tagged with
+	Sort      string `form:"sort" validate:"omitempty,oneof=name count"`
+	Limit     int    `form:"limit" validate:"min=0,max=100"`
+	Offset    int    `form:"offset" validate:"min=0"`
+}
+
+// GenreListResponse is a page of genres with their manga counts
+type GenreListResponse struct {
+	Data    []GenreWithCount `json:"data"`
+	Total   int              `json:"total"`
+	Limit   int              `json:"limit"`
+	Offset  int              `json:"offset"`
+	HasMore bool             `json:"has_more"`
+}
+
 // Common genre slugs (for seeding/reference)
 const (
 	GenreAction        = "action"
@@ -0,0 +1,43 @@
+// Package models - Reading Goal Models
+// Mục tiêu đọc theo năm/tháng ("đọc 500 chapter năm nay")
+// Chức năng:
+//   - Goal theo period (yearly/monthly) với khoảng ngày UTC
+//   - Tiến độ tính từ chapter_history trong khoảng ngày của goal
+package models
+
+import (
+	"time"
+)
+
+// Reading goal periods
+const (
+	GoalPeriodYearly  = "yearly"
+	GoalPeriodMonthly = "monthly"
+)
+
+// ReadingGoal is a chapter target over one period
+type ReadingGoal struct {
+	ID             string    `json:"id" db:"id"`
+	UserID         string    `json:"user_id" db:"user_id"`
+	Period         string    `json:"period" db:"period"`
+	TargetChapters int       `json:"target_chapters" db:"target_chapters"`
+	StartDate      string    `json:"start_date" db:"start_date"` // YYYY-MM-DD (UTC), inclusive
+	EndDate        string    `json:"end_date" db:"end_date"`     // YYYY-MM-DD (UTC), inclusive
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+// GoalProgress is a goal with the chapters read so far in its window
+type GoalProgress struct {
+	ReadingGoal
+	ChaptersRead int     `json:"chapters_read"`
+	Percentage   float64 `json:"percentage"` // 0-100, capped
+	DaysLeft     int     `json:"days_left"`  // including today
+	Completed    bool    `json:"completed"`
+}
+
+// SetGoalRequest sets the target for the current period
+type SetGoalRequest struct {
+	Period         string `json:"period" validate:"required,oneof=yearly monthly"`
+	TargetChapters int    `json:"target_chapters" validate:"min=1,max=100000"`
+}
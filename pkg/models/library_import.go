@@ -0,0 +1,84 @@
+// Package models - Bulk Library Import
+// Models cho việc import cả thư viện từ nền tảng khác (MyAnimeList, MangaDex, ...)
+// Chức năng:
+//   - Mỗi entry tham chiếu manga bằng external ID + source
+//   - Kết quả trả về thống kê và trạng thái từng dòng
+//   - Manga chưa có trong DB được đưa vào hàng đợi fetch thay vì bỏ qua
+package models
+
+import "time"
+
+// ImportSourceMAL identifies MyAnimeList IDs in a library import.
+// The other sources reuse SourceMangaDex, SourceAniList and SourceKitsu.
+const ImportSourceMAL = "mal"
+
+// MaxBulkImportEntries caps the entries accepted by one bulk import
+const MaxBulkImportEntries = 5000
+
+// Per-row outcomes of a bulk library import
+const (
+	ImportRowInserted = "inserted" // new library entry
+	ImportRowUpdated  = "updated"  // existing library entry overwritten
+	ImportRowQueued   = "queued"   // manga not in DB yet, waiting for an external fetch
+	ImportRowFailed   = "failed"   // invalid row
+)
+
+// Import queue states
+const (
+	ImportQueuePending = "pending"
+	ImportQueueDone    = "done"
+	ImportQueueFailed  = "failed"
+)
+
+// BulkLibraryEntry is one row of a bulk library import.
+// Title is optional and only used for the fuzzy fallback match.
+type BulkLibraryEntry struct {
+	ExternalID     string `json:"external_id" validate:"required,max=64"`
+	Source         string `json:"source" validate:"required,oneof=mal mangadex anilist kitsu"`
+	Title          string `json:"title,omitempty" validate:"max=500"`
+	Status         string `json:"status" validate:"omitempty,oneof=plan_to_read reading completed on_hold dropped"`
+	CurrentChapter int    `json:"current_chapter" validate:"min=0"`
+	IsFavorite     bool   `json:"is_favorite"`
+}
+
+// BulkImportStats counts row outcomes, like importer.ImportStats
+type BulkImportStats struct {
+	Total    int `json:"total"`
+	Inserted int `json:"inserted"`
+	Updated  int `json:"updated"`
+	Queued   int `json:"queued"`
+	Failed   int `json:"failed"`
+}
+
+// BulkImportRowResult reports what happened to one entry
+type BulkImportRowResult struct {
+	Index      int    `json:"index"`
+	ExternalID string `json:"external_id"`
+	Source     string `json:"source"`
+	Result     string `json:"result"`               // inserted, updated, queued, failed
+	MangaID    string `json:"manga_id,omitempty"`   // set when the manga was resolved
+	MatchedBy  string `json:"matched_by,omitempty"` // external_id or title
+	Error      string `json:"error,omitempty"`
+}
+
+// BulkImportLibraryResponse is returned by POST /users/library/bulk
+type BulkImportLibraryResponse struct {
+	Stats BulkImportStats       `json:"stats"`
+	Rows  []BulkImportRowResult `json:"rows"`
+}
+
+// QueuedLibraryImport is a library entry waiting for its manga to be fetched
+type QueuedLibraryImport struct {
+	ID             string    `json:"id" db:"id"`
+	UserID         string    `json:"user_id" db:"user_id"`
+	Source         string    `json:"source" db:"source"`
+	ExternalID     string    `json:"external_id" db:"external_id"`
+	Title          string    `json:"title" db:"title"`
+	Status         string    `json:"status" db:"status"`
+	CurrentChapter int       `json:"current_chapter" db:"current_chapter"`
+	IsFavorite     bool      `json:"is_favorite" db:"is_favorite"`
+	State          string    `json:"state" db:"state"` // pending, done, failed
+	LastError      string    `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
@@ -0,0 +1,28 @@
+// Package models - Bulk Library Status
+// Models cho việc đổi status nhiều manga trong library cùng lúc
+// Chức năng:
+//   - Một request đổi status cho nhiều manga_id, chạy trong một transaction
+//   - Manga không có trong library được báo lại trong failed, không làm hỏng cả batch
+package models
+
+// MaxBulkStatusMangaIDs caps the manga changed by one bulk status update
+const MaxBulkStatusMangaIDs = 500
+
+// BulkStatusRequest is the body of PUT /users/library/bulk-status
+type BulkStatusRequest struct {
+	MangaIDs []string `json:"manga_ids" validate:"required,min=1,dive,required"`
+	Status   string   `json:"status" validate:"required,oneof=plan_to_read reading completed on_hold dropped"`
+}
+
+// BulkStatusFailure names a manga whose status was not changed, and why
+type BulkStatusFailure struct {
+	MangaID string `json:"manga_id"`
+	Error   string `json:"error"`
+}
+
+// BulkStatusResponse is returned by PUT /users/library/bulk-status
+type BulkStatusResponse struct {
+	Status  string              `json:"status"`
+	Updated []string            `json:"updated"` // manga IDs now in Status
+	Failed  []BulkStatusFailure `json:"failed"`
+}
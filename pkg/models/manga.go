@@ -21,6 +21,19 @@ type Manga struct {
 	Genres        []Genre   `json:"genres,omitempty" db:"-"` // populated via join with manga_genres
 	CreatedAt     time.Time `json:"created_at" db:"created_at"`
 	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
+	// CompletedAt is when the status last changed to completed; nil for manga
+	// imported as completed or not completed now
+	CompletedAt *time.Time `json:"completed_at,omitempty" db:"completed_at"`
+}
+
+// RecentlyCompletedWindow is how long a manga counts as recently completed
+const RecentlyCompletedWindow = 30 * 24 * time.Hour
+
+// RecentlyCompleted reports whether the manga changed to completed within
+// RecentlyCompletedWindow of now
+func (m *Manga) RecentlyCompleted(now time.Time) bool {
+	return m.Status == "completed" && m.CompletedAt != nil &&
+		now.Sub(*m.CompletedAt) < RecentlyCompletedWindow
 }
 
 // MangaSearchRequest represents search parameters
@@ -31,8 +44,35 @@ type MangaSearchRequest struct {
 	Type   string   `json:"type" form:"type"`
 	Limit  int      `json:"limit" form:"limit" validate:"min=1,max=100"`
 	Offset int      `json:"offset" form:"offset" validate:"min=0"`
-	SortBy string   `json:"sort_by" form:"sort_by"` // title, rating, year
-	Order  string   `json:"order" form:"order"`     // asc, desc
+	SortBy string   `json:"sort_by" form:"sort"` // rating (default), year, chapters, title
+	Order  string   `json:"order" form:"order"`  // asc, desc; default desc, asc for title
+	Mode   string   `json:"mode" form:"mode"`    // "" (substring) or "fts" (ranked full-text)
+}
+
+// Sort fields accepted by GET /manga?sort=
+const (
+	MangaSortRating   = "rating"
+	MangaSortYear     = "year"
+	MangaSortChapters = "chapters"
+	MangaSortTitle    = "title"
+)
+
+// Sort orders accepted by GET /manga?order=
+const (
+	SortAsc  = "asc"
+	SortDesc = "desc"
+)
+
+// MangaSortFields lists the sort fields in the order clients cycle through them
+var MangaSortFields = []string{MangaSortRating, MangaSortYear, MangaSortChapters, MangaSortTitle}
+
+// DefaultMangaSortOrder is the order a sort field uses when none is given:
+// titles A-Z, everything else highest first
+func DefaultMangaSortOrder(sortBy string) string {
+	if sortBy == MangaSortTitle {
+		return SortAsc
+	}
+	return SortDesc
 }
 
 // MangaListResponse represents paginated manga results
@@ -44,6 +84,64 @@ type MangaListResponse struct {
 	HasMore bool    `json:"has_more"`
 }
 
+// MaxMangaBatch is the most ids accepted by POST /manga/batch
+const MaxMangaBatch = 100
+
+// RandomMangaRequest filters GET /manga/random
+type RandomMangaRequest struct {
+	Genre   string `form:"genre"` // genre name or slug
+	Type    string `form:"type" validate:"omitempty,oneof=manga manhwa manhua novel"`
+	Exclude string `form:"exclude"` // the manga shown last; skipped unless it is the only match
+}
+
+// MangaBatchRequest asks for several manga by id
+type MangaBatchRequest struct {
+	IDs []string `json:"ids" validate:"required,min=1,max=100,dive,required"`
+}
+
+// MangaBatchResponse holds the found manga in request order; unknown ids are listed in Missing
+type MangaBatchResponse struct {
+	Data    []Manga  `json:"data"`
+	Missing []string `json:"missing"`
+}
+
+// SimilarManga is a recommendation scored against a source manga
+type SimilarManga struct {
+	Manga
+	Score        float64 `json:"score"`         // 0-1, genre overlap weighted over rating closeness
+	SharedGenres int     `json:"shared_genres"` // genres in common with the source manga
+}
+
+// Limits for GET /manga/suggest
+const (
+	MinSuggestPrefix    = 2 // shorter prefixes match too much of the table to be useful
+	DefaultSuggestLimit = 10
+	MaxSuggestLimit     = 20
+)
+
+// MangaSuggestion is a lightweight title match for search autocomplete
+type MangaSuggestion struct {
+	ID    string `json:"id"`
+	Title string `json:"title"`
+}
+
+// Chapter is one chapter of a manga. Synthetic chapters stand in for
+// numbers up to total_chapters that have no stored metadata.
+type Chapter struct {
+	Number     int        `json:"number"`
+	Title      string     `json:"title,omitempty"`
+	ReleasedAt *time.Time `json:"released_at,omitempty"`
+	ExternalID string     `json:"external_id,omitempty"`
+	Synthetic  bool       `json:"synthetic,omitempty"`
+}
+
+// ChapterList is the response of GET /manga/:id/chapters, ordered by number
+type ChapterList struct {
+	MangaID       string    `json:"manga_id"`
+	TotalChapters int       `json:"total_chapters"`
+	Chapters      []Chapter `json:"chapters"`
+}
+
 // ValidateMangaSearch validates manga search request
 func ValidateMangaSearch(req *MangaSearchRequest) error {
 	if req.Limit <= 0 {
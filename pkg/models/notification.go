@@ -0,0 +1,34 @@
+// Package models - Email Notification Models
+// Daily digest các chapter mới cho manga trong library
+// Chức năng:
+//   - Recipient: user bật email notifications và đã verify email
+//   - Chapter mới theo manga, đã lọc manga bị mute
+//   - Kết quả một lần chạy digest
+package models
+
+import "time"
+
+// DigestRecipient is a user who opted in to email digests
+type DigestRecipient struct {
+	UserID     string
+	Username   string
+	Email      string
+	LastSentAt *time.Time // nil before the first digest
+}
+
+// DigestChapter is a chapter added since the recipient's last digest
+type DigestChapter struct {
+	MangaID    string
+	MangaTitle string
+	Number     int
+	Title      string
+	AddedAt    time.Time
+}
+
+// DigestRunResult counts what one digest run did
+type DigestRunResult struct {
+	Recipients int `json:"recipients"`
+	Sent       int `json:"sent"`
+	Empty      int `json:"empty"` // nothing new for the user, no email
+	Failed     int `json:"failed"`
+}
@@ -0,0 +1,160 @@
+// Package models - User Preferences & Data Export Models
+// Xuất dữ liệu người dùng (library, lịch sử đọc, custom lists)
+// Chức năng:
+//   - App preferences (theme, language, default status, notifications, spoilers)
+//   - TUI startup: home view và tự kết nối UDP notifications khi login
+//   - Email digest chapter mới (opt-in)
+//   - TUI keybindings do user đổi (action -> phím)
+//   - Per-manga notification mute
+//   - Export request/response (JSON hoặc zip chứa các file CSV)
+//   - Flat export rows cho từng loại dữ liệu
+package models
+
+import (
+	"time"
+)
+
+// TUI themes (user_preferences.theme)
+const (
+	ThemeDracula = "dracula"
+	ThemeDark    = "dark"
+	ThemeLight   = "light"
+	ThemeNord    = "nord"
+)
+
+// TUI home views (user_preferences.default_view)
+const (
+	HomeViewDashboard = "dashboard"
+	HomeViewSearch    = "search"
+	HomeViewBrowse    = "browse"
+	HomeViewLibrary   = "library"
+	HomeViewActivity  = "activity"
+	HomeViewLists     = "lists"
+	HomeViewStats     = "stats"
+	HomeViewChat      = "chat"
+)
+
+// HomeViews are the views the TUI can open on startup, in settings order
+var HomeViews = []string{
+	HomeViewDashboard, HomeViewSearch, HomeViewBrowse, HomeViewLibrary,
+	HomeViewActivity, HomeViewLists, HomeViewStats, HomeViewChat,
+}
+
+// UserPreferences are a user's app settings
+type UserPreferences struct {
+	Theme                    string    `json:"theme"`
+	Language                 string    `json:"language"`
+	DefaultStatus            string    `json:"default_status"`
+	NotificationsEnabled     bool      `json:"notifications_enabled"`
+	ShowSpoilers             bool      `json:"show_spoilers"`              // expand spoiler reviews by default
+	ActivityPublic           bool      `json:"activity_public"`            // profile shows recent activity and chapters read
+	LibraryPublic            bool      `json:"library_public"`             // profile shows library counts
+	DefaultView              string    `json:"default_view"`               // TUI view opened after login (HomeView*)
+	AutoConnectNotifications bool      `json:"auto_connect_notifications"` // TUI joins UDP notifications on login
+	EmailNotifications       bool      `json:"email_notifications"`        // daily email digest of new chapters (opt-in)
+	UpdatedAt                time.Time `json:"updated_at"`
+	// Keybindings maps TUI actions to the key that replaces their default,
+	// in key.Matches form ("s", "ctrl+f"); actions not listed keep theirs
+	Keybindings map[string]string `json:"keybindings,omitempty"`
+}
+
+// DefaultUserPreferences are used until a user saves their own
+func DefaultUserPreferences() UserPreferences {
+	return UserPreferences{
+		Theme:                    ThemeDracula,
+		Language:                 "en",
+		DefaultStatus:            "plan_to_read",
+		NotificationsEnabled:     true,
+		ActivityPublic:           true,
+		LibraryPublic:            true,
+		DefaultView:              HomeViewDashboard,
+		AutoConnectNotifications: true,
+	}
+}
+
+// UpdatePreferencesRequest changes preferences; omitted fields keep their value
+type UpdatePreferencesRequest struct {
+	Theme                    *string `json:"theme,omitempty" validate:"omitempty,oneof=dracula dark light nord"`
+	Language                 *string `json:"language,omitempty" validate:"omitempty,min=2,max=8"`
+	DefaultStatus            *string `json:"default_status,omitempty" validate:"omitempty,oneof=plan_to_read reading completed on_hold dropped"`
+	NotificationsEnabled     *bool   `json:"notifications_enabled,omitempty"`
+	ShowSpoilers             *bool   `json:"show_spoilers,omitempty"`
+	ActivityPublic           *bool   `json:"activity_public,omitempty"`
+	LibraryPublic            *bool   `json:"library_public,omitempty"`
+	DefaultView              *string `json:"default_view,omitempty" validate:"omitempty,oneof=dashboard search browse library activity lists stats chat"`
+	AutoConnectNotifications *bool   `json:"auto_connect_notifications,omitempty"`
+	EmailNotifications       *bool   `json:"email_notifications,omitempty"`
+	// Keybindings replaces every saved binding when not null; {} resets all to default
+	Keybindings map[string]string `json:"keybindings"`
+}
+
+// MaxKeybindings bounds how many key overrides a user can save
+const MaxKeybindings = 50
+
+// MangaMute is whether a user muted update notifications for one manga
+type MangaMute struct {
+	MangaID string `json:"manga_id"`
+	Muted   bool   `json:"muted"`
+}
+
+// Export formats
+const (
+	ExportFormatJSON = "json"
+	ExportFormatCSV  = "csv"
+)
+
+// ExportDataRequest selects the export format
+type ExportDataRequest struct {
+	Format string `form:"format" json:"format" validate:"omitempty,oneof=json csv"`
+}
+
+// ExportDataResponse is a ready-to-download export file
+type ExportDataResponse struct {
+	Filename    string `json:"filename"`
+	ContentType string `json:"content_type"`
+	Data        []byte `json:"data"`
+}
+
+// ExportLibraryEntry is one manga in the user's library
+type ExportLibraryEntry struct {
+	MangaID        string     `json:"manga_id"`
+	Title          string     `json:"title"`
+	Status         string     `json:"status"`
+	CurrentChapter int        `json:"current_chapter"`
+	TotalChapters  int        `json:"total_chapters"`
+	IsFavorite     bool       `json:"is_favorite"`
+	Rating         *int       `json:"rating,omitempty"`
+	Review         string     `json:"review,omitempty"`
+	StartedAt      *time.Time `json:"started_at,omitempty"`
+	CompletedAt    *time.Time `json:"completed_at,omitempty"`
+	LastReadAt     time.Time  `json:"last_read_at"`
+}
+
+// ExportHistoryEntry is one chapter read
+type ExportHistoryEntry struct {
+	MangaID       string    `json:"manga_id"`
+	Title         string    `json:"title"`
+	ChapterNumber int       `json:"chapter_number"`
+	PagesRead     int       `json:"pages_read"`
+	TimeMinutes   int       `json:"time_minutes"`
+	ReadAt        time.Time `json:"read_at"`
+}
+
+// ExportListEntry is one manga in a custom list.
+// Empty lists appear once with no manga.
+type ExportListEntry struct {
+	ListName    string     `json:"list_name"`
+	Description string     `json:"description,omitempty"`
+	MangaID     string     `json:"manga_id,omitempty"`
+	Title       string     `json:"title,omitempty"`
+	Notes       string     `json:"notes,omitempty"`
+	AddedAt     *time.Time `json:"added_at,omitempty"`
+}
+
+// UserDataExport bundles everything exported for a user
+type UserDataExport struct {
+	ExportedAt time.Time            `json:"exported_at"`
+	Library    []ExportLibraryEntry `json:"library"`
+	History    []ExportHistoryEntry `json:"history"`
+	Lists      []ExportListEntry    `json:"lists"`
+}
@@ -0,0 +1,63 @@
+// Package models - Public Profile Models
+// Hồ sơ công khai của user (GET /users/:username)
+// Chức năng:
+//   - Reader rank theo tổng chapter đã đọc (Bronze → Diamond)
+//   - Public profile: activity và library chỉ hiện khi user để public
+package models
+
+import "time"
+
+// ReaderRank is a reader tier earned by total chapters read
+type ReaderRank struct {
+	Name        string `json:"name"`
+	Icon        string `json:"icon"`
+	MinChapters int    `json:"min_chapters"`
+}
+
+// ReaderRanks lists the tiers from lowest to highest
+var ReaderRanks = []ReaderRank{
+	{Name: "Bronze", Icon: "🥉", MinChapters: 0},
+	{Name: "Silver", Icon: "🥈", MinChapters: 100},
+	{Name: "Gold", Icon: "🥇", MinChapters: 500},
+	{Name: "Emerald", Icon: "💎", MinChapters: 1000},
+	{Name: "Diamond", Icon: "👑", MinChapters: 2500},
+}
+
+// RankFor returns the rank for a chapter count and the next one (nil at max rank)
+func RankFor(chapters int) (ReaderRank, *ReaderRank) {
+	for i := len(ReaderRanks) - 1; i >= 0; i-- {
+		if chapters >= ReaderRanks[i].MinChapters {
+			if i == len(ReaderRanks)-1 {
+				return ReaderRanks[i], nil
+			}
+			return ReaderRanks[i], &ReaderRanks[i+1]
+		}
+	}
+	return ReaderRanks[0], &ReaderRanks[1]
+}
+
+// LibraryCounts summarizes a library by status
+type LibraryCounts struct {
+	Total      int `json:"total"`
+	Reading    int `json:"reading"`
+	Completed  int `json:"completed"`
+	PlanToRead int `json:"plan_to_read"`
+	OnHold     int `json:"on_hold"`
+	Dropped    int `json:"dropped"`
+	Favorites  int `json:"favorites"`
+}
+
+// PublicProfile is what anyone can see about a user.
+// Email, ID and login times are never included; ChaptersRead and
+// RecentActivity need ActivityPublic, Library needs LibraryPublic.
+type PublicProfile struct {
+	Username       string         `json:"username"`
+	DisplayName    string         `json:"display_name"`
+	JoinedAt       time.Time      `json:"joined_at"`
+	Rank           ReaderRank     `json:"rank"`
+	ActivityPublic bool           `json:"activity_public"`
+	LibraryPublic  bool           `json:"library_public"`
+	ChaptersRead   *int           `json:"chapters_read,omitempty"`
+	RecentActivity []Activity     `json:"recent_activity,omitempty"`
+	Library        *LibraryCounts `json:"library,omitempty"`
+}
@@ -25,12 +25,46 @@ type ProgressWithManga struct {
 	Manga Manga `json:"manga"`
 }
 
-// UpdateProgressRequest represents a progress update request
+// ContinueReadingEntry is one manga on the "Continue Reading" shelf, with
+// enough of the manga to render a progress bar
+type ContinueReadingEntry struct {
+	MangaID        string    `json:"manga_id"`
+	Title          string    `json:"title"`
+	CoverURL       string    `json:"cover_url"`
+	MangaStatus    string    `json:"manga_status"` // ongoing, completed, ...
+	CurrentChapter int       `json:"current_chapter"`
+	TotalChapters  int       `json:"total_chapters"` // 0 when unknown
+	LastReadAt     time.Time `json:"last_read_at"`
+	UpdatedAt      time.Time `json:"updated_at"` // send back as expected_updated_at
+}
+
+// Continue reading limits for GET /users/continue
+const (
+	DefaultContinueLimit = 5
+	MaxContinueLimit     = 50
+)
+
+// UpdateProgressRequest represents a progress update request.
+// ExpectedUpdatedAt is the updated_at the client last saw; when set, the
+// update is rejected with a conflict if the stored row changed since.
 type UpdateProgressRequest struct {
-	MangaID        string `json:"manga_id" validate:"required"`
-	CurrentChapter int    `json:"current_chapter" validate:"min=0"`
-	Status         string `json:"status" validate:"omitempty,oneof=plan_to_read reading completed on_hold dropped"`
-	IsFavorite     bool   `json:"is_favorite"`
+	MangaID           string     `json:"manga_id" validate:"required"`
+	CurrentChapter    int        `json:"current_chapter" validate:"min=0"`
+	Status            string     `json:"status" validate:"omitempty,oneof=plan_to_read reading completed on_hold dropped"`
+	IsFavorite        bool       `json:"is_favorite"`
+	ExpectedUpdatedAt *time.Time `json:"expected_updated_at,omitempty"`
+}
+
+// CatchUpRequest marks a manga as read up to its latest chapter
+type CatchUpRequest struct {
+	MangaID string `json:"manga_id" validate:"required"`
+}
+
+// CatchUpResponse is the result of PUT /users/progress/catchup
+type CatchUpResponse struct {
+	Progress         *ReadingProgress `json:"progress"`
+	ChaptersRecorded int              `json:"chapters_recorded"` // history entries added for chapters not recorded before
+	AlreadyCaughtUp  bool             `json:"already_caught_up"` // nothing changed
 }
 
 // LibraryStats represents user library statistics
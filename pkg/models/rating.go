@@ -4,6 +4,7 @@
 //   - Single rating scale (1-10)
 //   - Optional review text with spoiler tags
 //   - Helpful count tracking
+//   - Paginated review list (recent/helpful), edited flag
 //   - Auto-calculation of manga.average_rating via triggers
 package models
 
@@ -13,14 +14,17 @@ import (
 
 // MangaRating represents a user's rating for a manga
 type MangaRating struct {
-	ID         string    `json:"id" db:"id"`
-	MangaID    string    `json:"manga_id" db:"manga_id"`
-	UserID     string    `json:"user_id" db:"user_id"`
-	Rating     int       `json:"rating" db:"rating" validate:"required,min=1,max=10"` // 1-10 scale
-	ReviewText string    `json:"review_text,omitempty" db:"review_text"`
-	IsSpoiler  bool      `json:"is_spoiler" db:"is_spoiler"`
-	CreatedAt  time.Time `json:"created_at" db:"created_at"`
-	UpdatedAt  time.Time `json:"updated_at" db:"updated_at"`
+	ID            string    `json:"id" db:"id"`
+	MangaID       string    `json:"manga_id" db:"manga_id"`
+	UserID        string    `json:"user_id" db:"user_id"`
+	Rating        int       `json:"rating" db:"rating" validate:"required,min=1,max=10"` // 1-10 scale
+	ReviewText    string    `json:"review_text,omitempty" db:"review_text"`
+	IsSpoiler     bool      `json:"is_spoiler" db:"is_spoiler"`
+	SpoilerMasked bool      `json:"spoiler_masked,omitempty"` // spoiler review withheld from this viewer
+	IsEdited      bool      `json:"is_edited" db:"is_edited"` // review text changed after it was first written
+	HelpfulCount  int       `json:"helpful_count" db:"helpful_count"`
+	CreatedAt     time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time `json:"updated_at" db:"updated_at"`
 }
 
 // RatingWithUser includes user info for display
@@ -45,6 +49,9 @@ type CreateRatingRequest struct {
 	Rating     int    `json:"rating" validate:"required,min=1,max=10"`
 	ReviewText string `json:"review_text,omitempty" validate:"omitempty,max=5000"`
 	IsSpoiler  bool   `json:"is_spoiler"`
+	// KeepReview changes only the score of an existing rating, leaving its
+	// review and spoiler flag alone (quick rating from the TUI)
+	KeepReview bool `json:"keep_review,omitempty"`
 }
 
 // UpdateRatingRequest is the payload for updating a rating
@@ -54,6 +61,33 @@ type UpdateRatingRequest struct {
 	IsSpoiler  bool   `json:"is_spoiler"`
 }
 
+// MaskReviewSpoilers blanks the text of spoiler reviews viewerID did not write
+func MaskReviewSpoilers(reviews []RatingWithUser, viewerID string) {
+	for i := range reviews {
+		r := &reviews[i].MangaRating
+		if r.IsSpoiler && r.ReviewText != "" && r.UserID != viewerID {
+			r.ReviewText = ""
+			r.SpoilerMasked = true
+		}
+	}
+}
+
+// Review sort orders (GET /manga/:id/reviews?sort=)
+const (
+	ReviewSortRecent  = "recent"
+	ReviewSortHelpful = "helpful"
+)
+
+// ReviewsResponse is a page of written reviews for a manga
+type ReviewsResponse struct {
+	Reviews []RatingWithUser `json:"reviews"`
+	Sort    string           `json:"sort"`
+	Total   int              `json:"total"`
+	Page    int              `json:"page"`
+	Limit   int              `json:"limit"`
+	HasMore bool             `json:"has_more"`
+}
+
 // MangaRatingsResponse is returned when fetching ratings for a manga
 type MangaRatingsResponse struct {
 	Summary RatingSummary    `json:"summary"`
@@ -0,0 +1,54 @@
+// Package models - Search History & Saved Searches
+// Lịch sử tìm kiếm gần đây và các tìm kiếm đã lưu của user
+// Chức năng:
+//   - Recent searches: tối đa MaxRecentSearches, không trùng lặp (tìm lại thì đưa lên đầu)
+//   - Saved searches: query + filters (genre, sort, order) có tên để chạy lại
+package models
+
+import (
+	"time"
+)
+
+// MaxRecentSearches caps how many recent searches are kept per user
+const MaxRecentSearches = 20
+
+// SearchFilters is a query plus the filters GET /manga accepts.
+// Genre is a genre slug; Sort and Order use the MangaSort* and Sort* values.
+type SearchFilters struct {
+	Query string `json:"query" validate:"max=200"`
+	Genre string `json:"genre,omitempty" validate:"max=50"`
+	Sort  string `json:"sort,omitempty" validate:"omitempty,oneof=rating year chapters title"`
+	Order string `json:"order,omitempty" validate:"omitempty,oneof=asc desc"`
+}
+
+// IsEmpty reports whether there is neither a query nor a filter
+func (f SearchFilters) IsEmpty() bool {
+	return f.Query == "" && f.Genre == "" && f.Sort == "" && f.Order == ""
+}
+
+// SavedSearch is a named search the user can run again
+type SavedSearch struct {
+	ID     string `json:"id" db:"id"`
+	UserID string `json:"user_id" db:"user_id"`
+	Name   string `json:"name" db:"name"`
+	SearchFilters
+	CreatedAt time.Time `json:"created_at" db:"created_at"`
+}
+
+// RecentSearch is an entry in the user's search history
+type RecentSearch struct {
+	SearchFilters
+	SearchedAt time.Time `json:"searched_at" db:"searched_at"`
+}
+
+// SaveSearchRequest is used to save a named search
+type SaveSearchRequest struct {
+	Name string `json:"name" validate:"required,min=1,max=100"`
+	SearchFilters
+}
+
+// SearchesResponse holds the user's saved searches and recent history
+type SearchesResponse struct {
+	Saved  []SavedSearch  `json:"saved"`
+	Recent []RecentSearch `json:"recent"`
+}
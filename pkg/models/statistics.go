@@ -0,0 +1,104 @@
+// Package models - Reading Statistics Models
+// Lịch sử đọc chapter và thống kê đọc truyện
+// Chức năng:
+//   - Per-chapter reading history (pages, minutes)
+//   - Source data cho reading streaks và heatmap
+//   - Phân bố thể loại theo lịch sử đọc
+//   - Overview, thống kê theo tháng, kỷ lục và heatmap theo ngày (UTC)
+package models
+
+import (
+	"time"
+)
+
+// ChapterHistory records a single chapter read by a user
+type ChapterHistory struct {
+	ID            string    `json:"id" db:"id"`
+	UserID        string    `json:"user_id" db:"user_id"`
+	MangaID       string    `json:"manga_id" db:"manga_id"`
+	ChapterNumber int       `json:"chapter_number" db:"chapter_number"`
+	PagesRead     int       `json:"pages_read" db:"pages_read"`
+	TimeMinutes   int       `json:"time_minutes" db:"time_minutes"`
+	ReadAt        time.Time `json:"read_at" db:"read_at"`
+}
+
+// RecordChapterRequest is the payload for recording a chapter read
+type RecordChapterRequest struct {
+	MangaID       string `json:"manga_id" validate:"required"`
+	ChapterNumber int    `json:"chapter_number" validate:"min=1"`
+	PagesRead     int    `json:"pages_read" validate:"min=0"`
+	TimeMinutes   int    `json:"time_minutes" validate:"min=0"`
+}
+
+// Heatmap range limits in days
+const (
+	DefaultHeatmapDays = 90
+	MaxHeatmapDays     = 366
+)
+
+// ReadingStats summarises a user's chapter history
+type ReadingStats struct {
+	TotalChapters        int            `json:"total_chapters"`
+	TotalMinutes         int            `json:"total_minutes"`
+	AvgMinutesPerChapter float64        `json:"avg_minutes_per_chapter"`
+	MangaRead            int            `json:"manga_read"`
+	CurrentStreak        int            `json:"current_streak"` // reading days up to today or yesterday; grace days bridge single gaps
+	LongestStreak        int            `json:"longest_streak"` // longest run without gaps; grace does not apply
+	Grace                StreakGrace    `json:"grace"`
+	Genres               []GenreStat    `json:"genres,omitempty"`
+	Monthly              []MonthlyStat  `json:"monthly,omitempty"` // last 12 months, oldest first
+	Records              ReadingRecords `json:"records"`
+	UpdatedAt            time.Time      `json:"updated_at"` // when these stats were computed; may predate the response when cached
+}
+
+// StatsOverview is the small summary shown on dashboards
+type StatsOverview struct {
+	TotalChapters    int         `json:"total_chapters"`
+	TotalMinutes     int         `json:"total_minutes"`
+	MangaRead        int         `json:"manga_read"`
+	CurrentStreak    int         `json:"current_streak"`
+	LongestStreak    int         `json:"longest_streak"`
+	ChaptersThisWeek int         `json:"chapters_this_week"` // last 7 days including today
+	Grace            StreakGrace `json:"grace"`
+}
+
+// StreakGrace reports the grace days that keep the current streak alive
+// across single missed days. The allowance resets each calendar month (UTC).
+type StreakGrace struct {
+	PerMonth  int      `json:"per_month"`
+	Used      int      `json:"used"` // this month
+	Remaining int      `json:"remaining"`
+	Dates     []string `json:"dates,omitempty"` // missed days (YYYY-MM-DD) bridged in the current streak
+}
+
+// MonthlyStat is the reading done in one calendar month
+type MonthlyStat struct {
+	Month    string `json:"month"` // YYYY-MM
+	Chapters int    `json:"chapters"`
+	Minutes  int    `json:"minutes"`
+}
+
+// ReadingRecords holds a user's personal bests
+type ReadingRecords struct {
+	BestDay          string `json:"best_day,omitempty"` // YYYY-MM-DD
+	BestDayChapters  int    `json:"best_day_chapters"`
+	TopMangaID       string `json:"top_manga_id,omitempty"`
+	TopMangaTitle    string `json:"top_manga_title,omitempty"`
+	TopMangaChapters int    `json:"top_manga_chapters"`
+}
+
+// HeatmapDay is one day of reading activity
+type HeatmapDay struct {
+	Date     string `json:"date"` // YYYY-MM-DD (UTC)
+	Chapters int    `json:"chapters"`
+	Minutes  int    `json:"minutes"`
+	Manga    int    `json:"manga"` // distinct manga read that day
+}
+
+// GenreStat is one genre's share of the manga a user has read
+type GenreStat struct {
+	Genre      string  `json:"genre"`
+	MangaCount int     `json:"manga_count"`
+	Chapters   int     `json:"chapters"`
+	Percentage float64 `json:"percentage"` // share of MangaCount across all genres, 0-100
+}
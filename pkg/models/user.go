@@ -20,12 +20,26 @@ type User struct {
 
 // UserProfile is the public-facing user profile
 type UserProfile struct {
-	ID          string     `json:"id"`
-	Username    string     `json:"username"`
-	DisplayName string     `json:"display_name"`
-	AvatarURL   string     `json:"avatar_url"`
-	CreatedAt   time.Time  `json:"created_at"`
-	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+	ID            string     `json:"id"`
+	Username      string     `json:"username"`
+	DisplayName   string     `json:"display_name"`
+	AvatarURL     string     `json:"avatar_url"`
+	Role          string     `json:"role,omitempty"`
+	CreatedAt     time.Time  `json:"created_at"`
+	LastLoginAt   *time.Time `json:"last_login_at,omitempty"`
+	EmailVerified bool       `json:"email_verified"` // unverified users can log in but not comment or rate
+}
+
+// User roles (users.role)
+const (
+	UserRoleUser      = "user"
+	UserRoleModerator = "moderator"
+	UserRoleAdmin     = "admin"
+)
+
+// IsModerator reports whether the role may moderate other users' content
+func IsModerator(role string) bool {
+	return role == UserRoleAdmin || role == UserRoleModerator
 }
 
 // RegisterRequest represents a user registration request
@@ -41,9 +55,41 @@ type LoginRequest struct {
 	Password string `json:"password" validate:"required"`
 }
 
+// ChangePasswordRequest represents a password change request
+type ChangePasswordRequest struct {
+	CurrentPassword string `json:"current_password" validate:"required"`
+	NewPassword     string `json:"new_password" validate:"required,min=8,max=100"`
+}
+
+// DeleteAccountRequest confirms an account deletion with the current password
+type DeleteAccountRequest struct {
+	Password string `json:"password" validate:"required"`
+}
+
 // LoginResponse represents a successful login response
 type LoginResponse struct {
-	Token     string      `json:"token"`
-	ExpiresAt time.Time   `json:"expires_at"`
-	User      UserProfile `json:"user"`
+	Token            string      `json:"token"`
+	ExpiresAt        time.Time   `json:"expires_at"`
+	RefreshToken     string      `json:"refresh_token"`
+	RefreshExpiresAt time.Time   `json:"refresh_expires_at"`
+	User             UserProfile `json:"user"`
+}
+
+// RegisterResponse is the new account's profile. VerificationToken is only
+// filled when the server is configured to expose it (development).
+type RegisterResponse struct {
+	UserProfile
+	VerificationToken     string     `json:"verification_token,omitempty"`
+	VerificationExpiresAt *time.Time `json:"verification_expires_at,omitempty"`
+}
+
+// EmailVerification is a freshly issued email verification token
+type EmailVerification struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// RefreshTokenRequest carries a refresh token for rotation or revocation
+type RefreshTokenRequest struct {
+	RefreshToken string `json:"refresh_token" validate:"required"`
 }
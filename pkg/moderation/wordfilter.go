@@ -0,0 +1,251 @@
+// Package moderation - Word Filter
+// Lọc từ ngữ không phù hợp trong nội dung người dùng (comment, chat)
+// Chức năng:
+//   - Mỗi từ trong wordlist có action riêng: mask (thay bằng *) hoặc block (từ chối)
+//   - So khớp theo nguyên từ nên "class" không bị bắt vì chứa "ass"
+//   - Chuẩn hoá lách luật đơn giản: hoa/thường, thay ký tự (sh1t, $hit), tách chữ (f u c k)
+//   - Chỉ thay ký tự khi từ có ít nhất một chữ cái thật, nên số ("chapter 455") không bị bắt
+//   - Một lượt quét + map lookup mỗi từ, đủ nhanh để chạy inline
+package moderation
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// Action is what happens to content containing a listed word
+type Action string
+
+const (
+	ActionMask  Action = "mask"  // replace the word's letters with *
+	ActionBlock Action = "block" // reject the whole content
+)
+
+// maxSpelledRun bounds how many spaced-out letters ("f u c k") are joined
+// into candidate words, keeping the check linear in practice
+const maxSpelledRun = 24
+
+// substitutions undo common look-alike replacements. They only apply to
+// words with at least one real letter, so plain numbers stay numbers.
+var substitutions = map[rune]rune{
+	'0': 'o', '1': 'i', '3': 'e', '4': 'a', '5': 's', '7': 't',
+	'@': 'a', '$': 's', '!': 'i', '|': 'i', '+': 't',
+}
+
+// Filter matches content against a wordlist. A nil Filter allows everything.
+// It is read-only after New and safe for concurrent use.
+type Filter struct {
+	words map[string]Action // normalized word -> action
+}
+
+// New builds a filter from the words to mask and the words to block.
+// A word in both lists is blocked.
+func New(mask, block []string) (*Filter, error) {
+	f := &Filter{words: make(map[string]Action, len(mask)+len(block))}
+	for _, list := range []struct {
+		words  []string
+		action Action
+	}{{mask, ActionMask}, {block, ActionBlock}} {
+		for _, w := range list.words {
+			norm, ok := normalizeWord(w)
+			if !ok {
+				return nil, fmt.Errorf("moderation: %q is not a single word", w)
+			}
+			f.words[norm] = list.action
+		}
+	}
+	return f, nil
+}
+
+// normalizeWord normalizes a listed word the same way content is, reporting
+// false for blanks and anything that would not match as one word
+func normalizeWord(w string) (string, bool) {
+	w = strings.TrimSpace(w)
+	if w == "" {
+		return "", false
+	}
+	runes := []rune(w)
+	for _, r := range runes {
+		if !isWordRune(r) {
+			return "", false
+		}
+	}
+	return normalize(runes), true
+}
+
+func isWordRune(r rune) bool {
+	if unicode.IsLetter(r) || unicode.IsDigit(r) || unicode.IsMark(r) {
+		return true
+	}
+	_, ok := substitutions[r]
+	return ok
+}
+
+// isSymbolSub reports whether r is a substitute that is also ordinary
+// punctuation, so it may be trimmed from the ends of a token ("damn!")
+func isSymbolSub(r rune) bool {
+	_, ok := substitutions[r]
+	return ok && !unicode.IsDigit(r)
+}
+
+// normalize lowercases a word, undoing substitutions only when it contains
+// a real letter: "sh1t" reads as "shit" but "455" stays "455"
+func normalize(word []rune) string {
+	hasLetter := false
+	for _, r := range word {
+		if unicode.IsLetter(r) {
+			hasLetter = true
+			break
+		}
+	}
+	var b strings.Builder
+	for _, r := range word {
+		if s, ok := substitutions[r]; ok && hasLetter {
+			r = s
+		}
+		b.WriteRune(unicode.ToLower(r))
+	}
+	return b.String()
+}
+
+// span is a token: runes [start, end) of the content
+type span struct{ start, end int }
+
+// Apply returns text with masked words replaced by asterisks and whether it
+// contains a blocked word. Only whole words match.
+func (f *Filter) Apply(text string) (string, bool) {
+	if f == nil || len(f.words) == 0 || text == "" {
+		return text, false
+	}
+
+	runes := []rune(text)
+	masked := make([]bool, len(runes))
+	anyMasked := false
+
+	// match looks up sp, marking it for masking if it is a mask word
+	match := func(sp span) (listed, blocked bool) {
+		action, ok := f.words[normalizedSpan(runes, sp)]
+		if action == ActionMask {
+			for i := sp.start; i < sp.end; i++ {
+				masked[i] = true
+			}
+			anyMasked = true
+		}
+		return ok, action == ActionBlock
+	}
+
+	tokens := tokenize(runes)
+	for i, tok := range tokens {
+		listed, blocked := match(tok)
+		if blocked {
+			return text, true
+		}
+		// "damn!" or "(sh1t)": retry without leading/trailing symbol substitutes
+		trimmed := trimSymbols(runes, tok)
+		if trimmed.start == trimmed.end {
+			continue
+		}
+		if !listed && trimmed != tok {
+			if _, blocked := match(trimmed); blocked {
+				return text, true
+			}
+		}
+		tokens[i] = trimmed
+	}
+
+	// Spaced-out words: single-letter tokens separated only by spaces or
+	// punctuation on one line are joined, checking every sub-run
+	for i := 0; i < len(tokens); {
+		j := i
+		for j < len(tokens) && j-i < maxSpelledRun && tokens[j].end-tokens[j].start == 1 &&
+			(j == i || spelledGap(runes[tokens[j-1].end:tokens[j].start])) {
+			j++
+		}
+		if j-i >= 2 && f.checkSpelled(runes, tokens[i:j], masked, &anyMasked) {
+			return text, true
+		}
+		i = max(j, i+1)
+	}
+
+	if !anyMasked {
+		return text, false
+	}
+	for i, m := range masked {
+		if m && isWordRune(runes[i]) {
+			runes[i] = '*'
+		}
+	}
+	return string(runes), false
+}
+
+// checkSpelled matches every run of at least two consecutive single-letter
+// tokens, masking the letters of masked words; it reports a blocked word
+func (f *Filter) checkSpelled(runes []rune, letters []span, masked []bool, anyMasked *bool) bool {
+	spelled := make([]rune, len(letters))
+	for i, l := range letters {
+		spelled[i] = runes[l.start]
+	}
+	for i := range letters {
+		for j := i + 1; j < len(letters); j++ {
+			switch f.words[normalize(spelled[i:j+1])] {
+			case ActionBlock:
+				return true
+			case ActionMask:
+				for _, l := range letters[i : j+1] {
+					masked[l.start] = true
+				}
+				*anyMasked = true
+			}
+		}
+	}
+	return false
+}
+
+// spelledGap reports whether the runes between two spelled-out letters are
+// a short separator such as " ", "." or " - "
+func spelledGap(gap []rune) bool {
+	if len(gap) == 0 || len(gap) > 3 {
+		return false
+	}
+	for _, r := range gap {
+		if r == '\n' || !(unicode.IsSpace(r) || unicode.IsPunct(r)) {
+			return false
+		}
+	}
+	return true
+}
+
+// tokenize splits runes into maximal runs of word runes
+func tokenize(runes []rune) []span {
+	var tokens []span
+	start := -1
+	for i, r := range runes {
+		switch {
+		case isWordRune(r) && start < 0:
+			start = i
+		case !isWordRune(r) && start >= 0:
+			tokens = append(tokens, span{start, i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		tokens = append(tokens, span{start, len(runes)})
+	}
+	return tokens
+}
+
+// trimSymbols drops symbol substitutes from both ends of sp
+func trimSymbols(runes []rune, sp span) span {
+	for sp.start < sp.end && isSymbolSub(runes[sp.start]) {
+		sp.start++
+	}
+	for sp.end > sp.start && isSymbolSub(runes[sp.end-1]) {
+		sp.end--
+	}
+	return sp
+}
+
+func normalizedSpan(runes []rune, sp span) string {
+	return normalize(runes[sp.start:sp.end])
+}
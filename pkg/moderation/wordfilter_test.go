@@ -0,0 +1,53 @@
+// Package moderation - Word Filter Tests
+package moderation
+
+import "testing"
+
+func TestFilterApply(t *testing.T) {
+	f, err := New([]string{"damn", "ass"}, []string{"shit"})
+	if err != nil {
+		t.Fatalf("New failed: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		in      string
+		want    string
+		blocked bool
+	}{
+		{"clean", "great chapter", "great chapter", false},
+		{"innocent substrings", "Classic assassin, passable class", "Classic assassin, passable class", false},
+		{"mask keeps punctuation", "Damn! that ending", "****! that ending", false},
+		{"mask substitution", "d4mn it", "**** it", false},
+		{"mask spaced letters", "what a d a m n ending", "what a * * * * ending", false},
+		{"block", "this is shit", "this is shit", true},
+		{"block symbol substitution", "$h1t", "$h1t", true},
+		{"block trailing symbols", "(sh!t!!)", "(sh!t!!)", true},
+		{"block dotted letters", "s.h.i.t", "s.h.i.t", true},
+		{"spelling split by newline", "s\nh\ni\nt", "s\nh\ni\nt", false},
+		{"numbers untouched", "chapter 455, page 5417", "chapter 455, page 5417", false},
+		{"spelled-out digits untouched", "rated 4 5 5", "rated 4 5 5", false},
+		{"digits inside a word", "5h17 and 455hole", "5h17 and 455hole", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, blocked := f.Apply(tt.in)
+			if got != tt.want || blocked != tt.blocked {
+				t.Errorf("Apply(%q) = %q, %v; want %q, %v", tt.in, got, blocked, tt.want, tt.blocked)
+			}
+		})
+	}
+}
+
+func TestFilterNewRejectsPhrases(t *testing.T) {
+	for _, w := range []string{"", "  ", "two words", "a-b"} {
+		if _, err := New([]string{w}, nil); err == nil {
+			t.Errorf("New accepted %q", w)
+		}
+	}
+
+	var nilFilter *Filter
+	if got, blocked := nilFilter.Apply("anything"); got != "anything" || blocked {
+		t.Errorf("nil filter changed content: %q, %v", got, blocked)
+	}
+}
@@ -1,6 +1,9 @@
 package utils
 
 import (
+	"reflect"
+	"strings"
+
 	"github.com/go-playground/validator/v10"
 )
 
@@ -8,6 +11,14 @@ var validate *validator.Validate
 
 func init() {
 	validate = validator.New()
+	// Report fields by their JSON name, the name clients send
+	validate.RegisterTagNameFunc(func(f reflect.StructField) string {
+		name, _, _ := strings.Cut(f.Tag.Get("json"), ",")
+		if name == "-" || name == "" {
+			return f.Name
+		}
+		return name
+	})
 }
 
 // ValidateStruct validates a struct
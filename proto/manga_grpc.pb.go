@@ -22,6 +22,10 @@ const (
 	MangaService_GetManga_FullMethodName       = "/mangahub.v1.MangaService/GetManga"
 	MangaService_SearchManga_FullMethodName    = "/mangahub.v1.MangaService/SearchManga"
 	MangaService_UpdateProgress_FullMethodName = "/mangahub.v1.MangaService/UpdateProgress"
+	MangaService_StreamActivity_FullMethodName = "/mangahub.v1.MangaService/StreamActivity"
+	MangaService_CreateManga_FullMethodName    = "/mangahub.v1.MangaService/CreateManga"
+	MangaService_UpdateManga_FullMethodName    = "/mangahub.v1.MangaService/UpdateManga"
+	MangaService_DeleteManga_FullMethodName    = "/mangahub.v1.MangaService/DeleteManga"
 )
 
 // MangaServiceClient is the client API for MangaService service.
@@ -33,6 +37,11 @@ type MangaServiceClient interface {
 	GetManga(ctx context.Context, in *GetMangaRequest, opts ...grpc.CallOption) (*MangaResponse, error)
 	SearchManga(ctx context.Context, in *SearchRequest, opts ...grpc.CallOption) (*SearchResponse, error)
 	UpdateProgress(ctx context.Context, in *ProgressRequest, opts ...grpc.CallOption) (*ProgressResponse, error)
+	StreamActivity(ctx context.Context, in *StreamActivityRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ActivityEvent], error)
+	// Catalog mutations; require the x-api-key metadata header
+	CreateManga(ctx context.Context, in *CreateMangaRequest, opts ...grpc.CallOption) (*MangaResponse, error)
+	UpdateManga(ctx context.Context, in *UpdateMangaRequest, opts ...grpc.CallOption) (*MangaResponse, error)
+	DeleteManga(ctx context.Context, in *DeleteMangaRequest, opts ...grpc.CallOption) (*DeleteMangaResponse, error)
 }
 
 type mangaServiceClient struct {
@@ -73,6 +82,55 @@ func (c *mangaServiceClient) UpdateProgress(ctx context.Context, in *ProgressReq
 	return out, nil
 }
 
+func (c *mangaServiceClient) StreamActivity(ctx context.Context, in *StreamActivityRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ActivityEvent], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &MangaService_ServiceDesc.Streams[0], MangaService_StreamActivity_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamActivityRequest, ActivityEvent]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MangaService_StreamActivityClient = grpc.ServerStreamingClient[ActivityEvent]
+
+func (c *mangaServiceClient) CreateManga(ctx context.Context, in *CreateMangaRequest, opts ...grpc.CallOption) (*MangaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MangaResponse)
+	err := c.cc.Invoke(ctx, MangaService_CreateManga_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) UpdateManga(ctx context.Context, in *UpdateMangaRequest, opts ...grpc.CallOption) (*MangaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(MangaResponse)
+	err := c.cc.Invoke(ctx, MangaService_UpdateManga_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *mangaServiceClient) DeleteManga(ctx context.Context, in *DeleteMangaRequest, opts ...grpc.CallOption) (*DeleteMangaResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteMangaResponse)
+	err := c.cc.Invoke(ctx, MangaService_DeleteManga_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // MangaServiceServer is the server API for MangaService service.
 // All implementations must embed UnimplementedMangaServiceServer
 // for forward compatibility.
@@ -82,6 +140,11 @@ type MangaServiceServer interface {
 	GetManga(context.Context, *GetMangaRequest) (*MangaResponse, error)
 	SearchManga(context.Context, *SearchRequest) (*SearchResponse, error)
 	UpdateProgress(context.Context, *ProgressRequest) (*ProgressResponse, error)
+	StreamActivity(*StreamActivityRequest, grpc.ServerStreamingServer[ActivityEvent]) error
+	// Catalog mutations; require the x-api-key metadata header
+	CreateManga(context.Context, *CreateMangaRequest) (*MangaResponse, error)
+	UpdateManga(context.Context, *UpdateMangaRequest) (*MangaResponse, error)
+	DeleteManga(context.Context, *DeleteMangaRequest) (*DeleteMangaResponse, error)
 	mustEmbedUnimplementedMangaServiceServer()
 }
 
@@ -101,6 +164,18 @@ func (UnimplementedMangaServiceServer) SearchManga(context.Context, *SearchReque
 func (UnimplementedMangaServiceServer) UpdateProgress(context.Context, *ProgressRequest) (*ProgressResponse, error) {
 	return nil, status.Error(codes.Unimplemented, "method UpdateProgress not implemented")
 }
+func (UnimplementedMangaServiceServer) StreamActivity(*StreamActivityRequest, grpc.ServerStreamingServer[ActivityEvent]) error {
+	return status.Error(codes.Unimplemented, "method StreamActivity not implemented")
+}
+func (UnimplementedMangaServiceServer) CreateManga(context.Context, *CreateMangaRequest) (*MangaResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method CreateManga not implemented")
+}
+func (UnimplementedMangaServiceServer) UpdateManga(context.Context, *UpdateMangaRequest) (*MangaResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method UpdateManga not implemented")
+}
+func (UnimplementedMangaServiceServer) DeleteManga(context.Context, *DeleteMangaRequest) (*DeleteMangaResponse, error) {
+	return nil, status.Error(codes.Unimplemented, "method DeleteManga not implemented")
+}
 func (UnimplementedMangaServiceServer) mustEmbedUnimplementedMangaServiceServer() {}
 func (UnimplementedMangaServiceServer) testEmbeddedByValue()                      {}
 
@@ -176,6 +251,71 @@ func _MangaService_UpdateProgress_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _MangaService_StreamActivity_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamActivityRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(MangaServiceServer).StreamActivity(m, &grpc.GenericServerStream[StreamActivityRequest, ActivityEvent]{ServerStream: stream})
+}
+
+func _MangaService_CreateManga_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateMangaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).CreateManga(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_CreateManga_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).CreateManga(ctx, req.(*CreateMangaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_UpdateManga_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateMangaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).UpdateManga(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_UpdateManga_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).UpdateManga(ctx, req.(*UpdateMangaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _MangaService_DeleteManga_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteMangaRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(MangaServiceServer).DeleteManga(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: MangaService_DeleteManga_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(MangaServiceServer).DeleteManga(ctx, req.(*DeleteMangaRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type MangaService_StreamActivityServer = grpc.ServerStreamingServer[ActivityEvent]
+
 // MangaService_ServiceDesc is the grpc.ServiceDesc for MangaService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -195,7 +335,25 @@ var MangaService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "UpdateProgress",
 			Handler:    _MangaService_UpdateProgress_Handler,
 		},
+		{
+			MethodName: "CreateManga",
+			Handler:    _MangaService_CreateManga_Handler,
+		},
+		{
+			MethodName: "UpdateManga",
+			Handler:    _MangaService_UpdateManga_Handler,
+		},
+		{
+			MethodName: "DeleteManga",
+			Handler:    _MangaService_DeleteManga_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamActivity",
+			Handler:       _MangaService_StreamActivity_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/manga.proto",
 }